@@ -19,17 +19,22 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"path"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/kibamail/kibaship/pkg/freeze"
 	"github.com/kibamail/kibaship/pkg/validation"
 )
 
@@ -72,7 +77,7 @@ const (
 )
 
 // BuildType defines the build type for GitRepository applications
-// +kubebuilder:validation:Enum=Railpack;Dockerfile
+// +kubebuilder:validation:Enum=Railpack;Dockerfile;Nixpacks;Buildpacks
 type BuildType string
 
 const (
@@ -80,6 +85,28 @@ const (
 	BuildTypeRailpack BuildType = "Railpack"
 	// BuildTypeDockerfile uses a Dockerfile to build the application
 	BuildTypeDockerfile BuildType = "Dockerfile"
+	// BuildTypeNixpacks uses Nixpacks to detect the stack and build the application
+	BuildTypeNixpacks BuildType = "Nixpacks"
+	// BuildTypeBuildpacks uses Cloud Native Buildpacks to build the application
+	BuildTypeBuildpacks BuildType = "Buildpacks"
+)
+
+// DefaultBuildpacksBuilderImage is the Cloud Native Buildpacks builder used when none is configured
+const DefaultBuildpacksBuilderImage = "paketobuildpacks/builder-jammy-base:latest"
+
+// MaxBuildEnvBytes bounds the names and values of the build environment variables of an
+// application, well under the size limit of the secret they are passed to the build through
+const MaxBuildEnvBytes = 256 * 1024
+
+// MaxBuildEnvVariables bounds the number of build environment variables of an application
+const MaxBuildEnvVariables = 100
+
+const (
+	// MaxCloneDepth bounds the number of commits of a shallow clone
+	MaxCloneDepth = 10000
+
+	// MaxSparseCheckoutPaths bounds the directories of a sparse checkout
+	MaxSparseCheckoutPaths = 20
 )
 
 // RegistryType defines the container registry type
@@ -177,6 +204,25 @@ type DockerfileBuildConfig struct {
 	// +kubebuilder:default="."
 	// +optional
 	BuildContext string `json:"buildContext,omitempty"`
+
+	// BuildArgs are passed to the build as --build-arg values for ARG instructions
+	// +optional
+	BuildArgs map[string]string `json:"buildArgs,omitempty"`
+
+	// BuildSecrets references a secret in the project namespace whose keys are exposed to the
+	// build as BuildKit secrets, usable with RUN --mount=type=secret,id=<key>. The values are
+	// never written to image layers.
+	// +optional
+	BuildSecrets *corev1.LocalObjectReference `json:"buildSecrets,omitempty"`
+}
+
+// BuildpacksBuildConfig defines the configuration for Cloud Native Buildpacks builds
+type BuildpacksBuildConfig struct {
+	// BuilderImage is the CNB builder image providing the buildpacks and the lifecycle
+	// (defaults to paketobuildpacks/builder-jammy-base:latest)
+	// +kubebuilder:validation:Pattern=`^[^\s]+$`
+	// +optional
+	BuilderImage string `json:"builderImage,omitempty"`
 }
 
 // GitRepositoryConfig defines the configuration for GitRepository applications
@@ -214,7 +260,31 @@ type GitRepositoryConfig struct {
 	// +optional
 	RootDirectory string `json:"rootDirectory,omitempty"`
 
-	// BuildType defines how the application should be built (Railpack or Dockerfile)
+	// GitSubmodules clones the submodules of the repository recursively. Private submodules must
+	// be hosted on the same provider, they are cloned with the access token of the repository.
+	// +optional
+	GitSubmodules bool `json:"gitSubmodules,omitempty"`
+
+	// GitLFS fetches the Git LFS objects of the checked out commit. Without it, files tracked by
+	// Git LFS are built as pointer files.
+	// +optional
+	GitLFS bool `json:"gitLFS,omitempty"`
+
+	// CloneDepth clones only the latest commits of the branch, 0 clones its full history.
+	// The commit of a deployment is fetched on its own when it is older than the clone.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10000
+	// +optional
+	CloneDepth int32 `json:"cloneDepth,omitempty"`
+
+	// SparseCheckoutPaths checks out only these directories of the repository, along with the
+	// files at its root. The root directory, Dockerfile and build context of the application
+	// must be inside them.
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	SparseCheckoutPaths []string `json:"sparseCheckoutPaths,omitempty"`
+
+	// BuildType defines how the application should be built (Railpack, Dockerfile, Nixpacks or Buildpacks)
 	// +kubebuilder:default="Railpack"
 	// +optional
 	BuildType BuildType `json:"buildType,omitempty"`
@@ -224,6 +294,11 @@ type GitRepositoryConfig struct {
 	// +optional
 	DockerfileBuild *DockerfileBuildConfig `json:"dockerfileBuild,omitempty"`
 
+	// BuildpacksBuild contains configuration for Cloud Native Buildpacks builds
+	// Only used when BuildType is Buildpacks
+	// +optional
+	BuildpacksBuild *BuildpacksBuildConfig `json:"buildpacksBuild,omitempty"`
+
 	// BuildCommand is the command to build the application (optional, for Railpack builds)
 	// +optional
 	BuildCommand string `json:"buildCommand,omitempty"`
@@ -236,6 +311,12 @@ type GitRepositoryConfig struct {
 	// +optional
 	Env *corev1.LocalObjectReference `json:"env,omitempty"`
 
+	// BuildEnvSecretRef references the operator managed secret holding the environment variables
+	// only the build sees, such as NPM_TOKEN or NODE_OPTIONS. They are passed to the builder as
+	// secrets and never set in the running container. Nixpacks builds do not support them.
+	// +optional
+	BuildEnvSecretRef *corev1.LocalObjectReference `json:"buildEnvSecretRef,omitempty"`
+
 	// SpaOutputDirectory is the output directory for SPA builds (optional, for Railpack builds)
 	// +optional
 	SpaOutputDirectory string `json:"spaOutputDirectory,omitempty"`
@@ -243,6 +324,47 @@ type GitRepositoryConfig struct {
 	// HealthCheck defines the health check configuration for this application (optional)
 	// +optional
 	HealthCheck *HealthCheckConfig `json:"healthCheck,omitempty"`
+
+	// Steps are custom commands, such as tests, run in order before the image is built (optional)
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	Steps []PipelineStep `json:"steps,omitempty"`
+
+	// BuildScheduling places the build pods of this application on specific nodes (optional).
+	// It replaces the operator wide build node pool settings when set.
+	// +optional
+	BuildScheduling *BuildSchedulingConfig `json:"buildScheduling,omitempty"`
+}
+
+// BuildSchedulingConfig selects the nodes Tekton build pods run on
+type BuildSchedulingConfig struct {
+	// NodeSelector restricts build pods to nodes carrying these labels
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations let build pods run on nodes tainted for builds
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// PipelineStep is a custom command run in the cloned repository before the image is built.
+// A failing step fails the deployment. Files the step writes to the directory in the
+// KIBASHIP_ARTIFACTS_DIR environment variable are published as deployment artifacts
+// when the operator has artifact collection enabled.
+type PipelineStep struct {
+	// Name identifies the step in the pipeline and must be unique within the application
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=40
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Image is the container image the step runs in
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+
+	// Script is run in the root directory of the application, with sh unless it starts with a shebang
+	// +kubebuilder:validation:Required
+	Script string `json:"script"`
 }
 
 // DockerImageConfig defines the configuration for DockerImage applications
@@ -411,6 +533,651 @@ type ValkeyClusterConfig struct {
 	Env *corev1.LocalObjectReference `json:"env,omitempty"`
 }
 
+// ExternalSecretStoreKind is the kind of External Secrets Operator store referenced by an application
+// +kubebuilder:validation:Enum=SecretStore;ClusterSecretStore
+type ExternalSecretStoreKind string
+
+const (
+	// ExternalSecretStoreKindSecretStore is a namespaced store in the application namespace
+	ExternalSecretStoreKindSecretStore ExternalSecretStoreKind = "SecretStore"
+	// ExternalSecretStoreKindClusterSecretStore is a cluster wide store
+	ExternalSecretStoreKindClusterSecretStore ExternalSecretStoreKind = "ClusterSecretStore"
+)
+
+// ExternalSecretStoreRef references an External Secrets Operator SecretStore or ClusterSecretStore
+type ExternalSecretStoreRef struct {
+	// Name of the store
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Kind of the store
+	// +kubebuilder:default=ClusterSecretStore
+	// +optional
+	Kind ExternalSecretStoreKind `json:"kind,omitempty"`
+}
+
+// ExternalEnvVar maps an environment variable to a value in an external secret store
+type ExternalEnvVar struct {
+	// Name is the environment variable name
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+
+	// Key is the remote key in the store, e.g. a Vault path like secret/data/my-app
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// Property selects a field of the remote secret, e.g. a key of a Vault KV secret
+	// +optional
+	Property string `json:"property,omitempty"`
+}
+
+// ExternalEnvConfig sources environment variables from an external secret store through the
+// External Secrets Operator instead of literal values
+type ExternalEnvConfig struct {
+	// SecretStoreRef references the store the values are read from
+	// +kubebuilder:validation:Required
+	SecretStoreRef ExternalSecretStoreRef `json:"secretStoreRef"`
+
+	// RefreshInterval is how often values are re-read from the store
+	// +kubebuilder:default="1h"
+	// +optional
+	RefreshInterval string `json:"refreshInterval,omitempty"`
+
+	// Variables lists the environment variables read from the store
+	// +kubebuilder:validation:MinItems=1
+	Variables []ExternalEnvVar `json:"variables"`
+
+	// RedeployOnChange restarts the running pods when an upstream value rotates.
+	// Without it new values are picked up on the next deployment.
+	// +optional
+	RedeployOnChange bool `json:"redeployOnChange,omitempty"`
+}
+
+// ReplicaWindow runs a different number of replicas during a recurring period
+type ReplicaWindow struct {
+	// Name identifies the window, e.g. business-hours
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Schedule is a five field cron expression for when the window starts, e.g. "0 9 * * MON-FRI"
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window lasts after each start, e.g. "9h"
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+
+	// Timezone is the IANA timezone the schedule is evaluated in (defaults to UTC)
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+
+	// Replicas is the number of replicas to run while the window is active
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Replicas int32 `json:"replicas"`
+}
+
+// ReplicaSchedule scales an application on a timetable, running DefaultReplicas outside its windows
+type ReplicaSchedule struct {
+	// DefaultReplicas is the number of replicas to run when no window is active (defaults to 1)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	DefaultReplicas *int32 `json:"defaultReplicas,omitempty"`
+
+	// Windows override the replica count during recurring periods.
+	// When windows overlap the one with the most replicas wins.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Windows []ReplicaWindow `json:"windows,omitempty"`
+}
+
+// ActiveWindow returns the window covering now, the replicas to run and when the window ends.
+// The window is nil when the default replicas apply. Invalid windows are skipped, they are
+// rejected by the webhook.
+func (s *ReplicaSchedule) ActiveWindow(now time.Time) (*ReplicaWindow, int32, time.Time) {
+	var active *ReplicaWindow
+	var activeUntil time.Time
+	for i := range s.Windows {
+		window := &s.Windows[i]
+		ok, end, err := freeze.Active(window.Schedule, window.Duration.Duration, window.Timezone, now)
+		if err != nil || !ok {
+			continue
+		}
+		if active == nil || window.Replicas > active.Replicas {
+			active, activeUntil = window, end
+		}
+	}
+	if active == nil {
+		replicas := int32(1)
+		if s.DefaultReplicas != nil {
+			replicas = *s.DefaultReplicas
+		}
+		return nil, replicas, time.Time{}
+	}
+	return active, active.Replicas, activeUntil
+}
+
+// ValidateReplicaWindow checks the schedule, duration and timezone of a replica window
+func ValidateReplicaWindow(window ReplicaWindow) error {
+	if _, err := freeze.ParseSchedule(window.Schedule); err != nil {
+		return fmt.Errorf("replica window %s: %w", window.Name, err)
+	}
+	if err := freeze.ValidateDuration(window.Duration.Duration); err != nil {
+		return fmt.Errorf("replica window %s: %w", window.Name, err)
+	}
+	if _, err := freeze.LoadLocation(window.Timezone); err != nil {
+		return fmt.Errorf("replica window %s: %w", window.Name, err)
+	}
+	return nil
+}
+
+// RuntimeSecurityConfig sets the security context of the application containers. Unset fields
+// keep the user and filesystem of the image.
+type RuntimeSecurityConfig struct {
+	// RunAsUser runs the application processes as this user ID instead of the image user
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=2147483647
+	// +optional
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+
+	// FSGroup owns the mounted volumes, so an application running as another user can write them
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=2147483647
+	// +optional
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+
+	// ReadOnlyRootFilesystem mounts the container image read-only. /tmp stays writable.
+	// +optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// Capabilities adds or drops Linux capabilities of the application containers
+	// +optional
+	Capabilities *RuntimeCapabilities `json:"capabilities,omitempty"`
+}
+
+// RuntimeCapabilities adds or drops Linux capabilities, named without the CAP_ prefix
+type RuntimeCapabilities struct {
+	// Add grants capabilities of the container runtime default set, such as NET_BIND_SERVICE
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:items:Pattern=`^[A-Z_]+$`
+	// +optional
+	Add []string `json:"add,omitempty"`
+
+	// Drop removes capabilities, ALL drops every capability not added back
+	// +kubebuilder:validation:MaxItems=50
+	// +kubebuilder:validation:items:Pattern=`^[A-Z_]+$`
+	// +optional
+	Drop []string `json:"drop,omitempty"`
+}
+
+// addableCapabilities are the capabilities applications may add, the ones the Pod Security
+// Standards baseline level allows. Privileged ones such as SYS_ADMIN or NET_ADMIN would let an
+// application escape its container.
+var addableCapabilities = map[string]bool{
+	"AUDIT_WRITE":      true,
+	"CHOWN":            true,
+	"DAC_OVERRIDE":     true,
+	"FOWNER":           true,
+	"FSETID":           true,
+	"KILL":             true,
+	"MKNOD":            true,
+	"NET_BIND_SERVICE": true,
+	"SETFCAP":          true,
+	"SETGID":           true,
+	"SETPCAP":          true,
+	"SETUID":           true,
+	"SYS_CHROOT":       true,
+}
+
+// ValidateRuntimeSecurity rejects privileged capabilities and capabilities both added and dropped
+func ValidateRuntimeSecurity(c RuntimeSecurityConfig) error {
+	if c.Capabilities == nil {
+		return nil
+	}
+	dropped := map[string]bool{}
+	for _, capability := range c.Capabilities.Drop {
+		dropped[capability] = true
+	}
+	for _, capability := range c.Capabilities.Add {
+		if !addableCapabilities[capability] {
+			return fmt.Errorf("capability %s cannot be added, only capabilities of the runtime default set can", capability)
+		}
+		if dropped[capability] {
+			return fmt.Errorf("capability %s is both added and dropped", capability)
+		}
+	}
+	return nil
+}
+
+// DefaultSessionAffinityCookieName is the cookie pinning clients to a replica when none is set
+const DefaultSessionAffinityCookieName = "kibaship-affinity"
+
+// MaxSessionAffinityTTLSeconds is the longest a session affinity cookie may pin a client, a year
+const MaxSessionAffinityTTLSeconds = 365 * 24 * 60 * 60
+
+var sessionAffinityCookiePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// SessionAffinity pins each client of an application to one replica with a cookie set by the
+// ingress. Ingress-nginx applies it to every path of the domains of the application, Traefik
+// reads it from the application Service and Gateway API routes through session persistence.
+type SessionAffinity struct {
+	// CookieName is the name of the cookie pinning the client (defaults to kibaship-affinity)
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_-]{1,64}$`
+	// +optional
+	CookieName string `json:"cookieName,omitempty"`
+
+	// TTLSeconds is how long the cookie pins the client, 0 keeps it until the browser is closed
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=31536000
+	// +optional
+	TTLSeconds int32 `json:"ttlSeconds,omitempty"`
+}
+
+// Cookie returns the name of the cookie pinning clients, DefaultSessionAffinityCookieName when not set
+func (a *SessionAffinity) Cookie() string {
+	if a.CookieName == "" {
+		return DefaultSessionAffinityCookieName
+	}
+	return a.CookieName
+}
+
+// ValidateSessionAffinity checks the cookie name and lifetime of a session affinity
+func ValidateSessionAffinity(a SessionAffinity) error {
+	if a.CookieName != "" && !sessionAffinityCookiePattern.MatchString(a.CookieName) {
+		return fmt.Errorf("session affinity cookie name %q may only contain letters, digits, - and _ and be at most 64 characters", a.CookieName)
+	}
+	if a.TTLSeconds < 0 || a.TTLSeconds > MaxSessionAffinityTTLSeconds {
+		return fmt.Errorf("session affinity ttlSeconds must be between 0 and %d", MaxSessionAffinityTTLSeconds)
+	}
+	return nil
+}
+
+// AutoscalingTriggerType is the metric an autoscaling trigger scales an application on
+// +kubebuilder:validation:Enum=cpu;requestsPerSecond;valkeyListLength;mysqlQuery
+type AutoscalingTriggerType string
+
+const (
+	// AutoscalingTriggerCPU scales on the average CPU utilization of the replicas, in percent of
+	// their CPU requests
+	AutoscalingTriggerCPU AutoscalingTriggerType = "cpu"
+	// AutoscalingTriggerRequestsPerSecond scales on the requests per second the ingress routes to
+	// the application, read from the Prometheus of the traffic metrics
+	AutoscalingTriggerRequestsPerSecond AutoscalingTriggerType = "requestsPerSecond"
+	// AutoscalingTriggerValkeyListLength scales on the length of a list of a Valkey application,
+	// such as a job queue
+	AutoscalingTriggerValkeyListLength AutoscalingTriggerType = "valkeyListLength"
+	// AutoscalingTriggerMySQLQuery scales on the number a query returns from a MySQL application
+	AutoscalingTriggerMySQLQuery AutoscalingTriggerType = "mysqlQuery"
+)
+
+// MaxAutoscalingReplicas is the largest replica count autoscaling may run
+const MaxAutoscalingReplicas = 100
+
+// AutoscalingTrigger is a metric an application scales on, the replicas are the metric divided by
+// the target
+type AutoscalingTrigger struct {
+	// Type is the metric of the trigger
+	// +kubebuilder:validation:Required
+	Type AutoscalingTriggerType `json:"type"`
+
+	// Target is the value of the metric one replica handles: the CPU utilization percent, the
+	// requests per second, the list length or the query result
+	// +kubebuilder:validation:Minimum=1
+	Target int64 `json:"target"`
+
+	// ApplicationRef references the Valkey application of valkeyListLength triggers and the MySQL
+	// application of mysqlQuery triggers, in the namespace of the application
+	// +optional
+	ApplicationRef *corev1.LocalObjectReference `json:"applicationRef,omitempty"`
+
+	// ListName is the Valkey list measured by valkeyListLength triggers
+	// +kubebuilder:validation:MaxLength=256
+	// +optional
+	ListName string `json:"listName,omitempty"`
+
+	// Query returns a single number for mysqlQuery triggers, e.g.
+	// SELECT COUNT(*) FROM jobs WHERE state = 'pending'
+	// +kubebuilder:validation:MaxLength=1024
+	// +optional
+	Query string `json:"query,omitempty"`
+}
+
+// Autoscaling scales an application between MinReplicas and MaxReplicas on its triggers through
+// KEDA, which must be installed in the cluster. With several triggers the one asking for the most
+// replicas wins.
+type Autoscaling struct {
+	// MinReplicas is the fewest replicas to run (defaults to 1). 0 scales the application to zero
+	// while no trigger is active, which cpu triggers alone cannot wake it from.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the most replicas to run
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Triggers are the metrics the application scales on
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=5
+	Triggers []AutoscalingTrigger `json:"triggers"`
+}
+
+// Min returns the fewest replicas to run, 1 when MinReplicas is not set
+func (a *Autoscaling) Min() int32 {
+	if a.MinReplicas == nil {
+		return 1
+	}
+	return *a.MinReplicas
+}
+
+// ValidateAutoscaling checks the replica bounds of an autoscaling configuration and that each
+// trigger sets the fields its type reads
+func ValidateAutoscaling(a Autoscaling) error {
+	if a.MaxReplicas < 1 || a.MaxReplicas > MaxAutoscalingReplicas {
+		return fmt.Errorf("autoscaling maxReplicas must be between 1 and %d", MaxAutoscalingReplicas)
+	}
+	if a.Min() < 0 || a.Min() > a.MaxReplicas {
+		return fmt.Errorf("autoscaling minReplicas must be between 0 and maxReplicas %d", a.MaxReplicas)
+	}
+	if len(a.Triggers) == 0 || len(a.Triggers) > 5 {
+		return fmt.Errorf("autoscaling needs between 1 and 5 triggers, got %d", len(a.Triggers))
+	}
+
+	onlyCPU := true
+	for i, trigger := range a.Triggers {
+		if trigger.Target < 1 {
+			return fmt.Errorf("autoscaling trigger %d: target must be at least 1", i)
+		}
+		needsApplication, needsList, needsQuery := false, false, false
+		switch trigger.Type {
+		case AutoscalingTriggerCPU:
+			if trigger.Target > 100 {
+				return fmt.Errorf("autoscaling trigger %d: cpu target is a utilization percent between 1 and 100", i)
+			}
+		case AutoscalingTriggerRequestsPerSecond:
+		case AutoscalingTriggerValkeyListLength:
+			needsApplication, needsList = true, true
+		case AutoscalingTriggerMySQLQuery:
+			needsApplication, needsQuery = true, true
+		default:
+			return fmt.Errorf("autoscaling trigger %d: unknown type %q", i, trigger.Type)
+		}
+		if trigger.Type != AutoscalingTriggerCPU {
+			onlyCPU = false
+		}
+
+		hasApplication := trigger.ApplicationRef != nil && trigger.ApplicationRef.Name != ""
+		if hasApplication != needsApplication {
+			return fmt.Errorf("autoscaling trigger %d: applicationRef is required by valkeyListLength and mysqlQuery triggers only", i)
+		}
+		if (trigger.ListName != "") != needsList {
+			return fmt.Errorf("autoscaling trigger %d: listName is required by valkeyListLength triggers only", i)
+		}
+		if (strings.TrimSpace(trigger.Query) != "") != needsQuery {
+			return fmt.Errorf("autoscaling trigger %d: query is required by mysqlQuery triggers only", i)
+		}
+	}
+	if onlyCPU && a.Min() == 0 {
+		return fmt.Errorf("autoscaling minReplicas must be at least 1 with only cpu triggers")
+	}
+	return nil
+}
+
+// LogAlertMatch selects how the pattern of a log alert rule matches log lines
+// +kubebuilder:validation:Enum=Substring;Regex
+type LogAlertMatch string
+
+const (
+	// LogAlertMatchSubstring matches lines containing the pattern
+	LogAlertMatchSubstring LogAlertMatch = "Substring"
+
+	// LogAlertMatchRegex matches lines matching the pattern as an RE2 regular expression
+	LogAlertMatchRegex LogAlertMatch = "Regex"
+)
+
+// LogAlertRule fires when the runtime logs of an application match a pattern at least
+// ThresholdPerMinute times in a minute. Rules are evaluated against the persisted logs and
+// require the operator logging pipeline.
+type LogAlertRule struct {
+	// Name identifies the rule, e.g. database-errors
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Pattern is the substring or regular expression log lines are matched against
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=1024
+	Pattern string `json:"pattern"`
+
+	// Match selects how Pattern is matched (defaults to Substring)
+	// +kubebuilder:default=Substring
+	// +optional
+	Match LogAlertMatch `json:"match,omitempty"`
+
+	// ThresholdPerMinute is the number of matching lines in a minute at which the rule fires
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	ThresholdPerMinute int32 `json:"thresholdPerMinute,omitempty"`
+}
+
+// Threshold returns the matches per minute at which the rule fires, 1 when not set
+func (r *LogAlertRule) Threshold() int64 {
+	if r.ThresholdPerMinute <= 0 {
+		return 1
+	}
+	return int64(r.ThresholdPerMinute)
+}
+
+// ValidateLogAlertRule checks the pattern of a log alert rule
+func ValidateLogAlertRule(rule LogAlertRule) error {
+	if rule.Pattern == "" {
+		return fmt.Errorf("log alert %s: pattern is required", rule.Name)
+	}
+	if rule.Match == LogAlertMatchRegex {
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("log alert %s: invalid regular expression: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// DatabaseAccessConfig declares the additional databases and users of a MySQL or Postgres
+// application. The operator applies it with a Job running against the database.
+type DatabaseAccessConfig struct {
+	// Databases are created as databases on MySQL and as schemas of the application database
+	// on Postgres. Databases removed from the list are kept with their data.
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	Databases []DatabaseSchema `json:"databases,omitempty"`
+
+	// Users are created as users on MySQL and as login roles on Postgres, with a generated
+	// password stored in a Secret. Users removed from the list are dropped.
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	Users []DatabaseUser `json:"users,omitempty"`
+}
+
+// DatabaseSchema is an additional database of a database application
+type DatabaseSchema struct {
+	// Name of the database
+	// +kubebuilder:validation:Pattern=`^[a-z][a-z0-9_]{0,62}$`
+	Name string `json:"name"`
+}
+
+// DatabaseUser is an additional user of a database application
+type DatabaseUser struct {
+	// Username of the user
+	// +kubebuilder:validation:Pattern=`^[a-z][a-z0-9_]{0,31}$`
+	Username string `json:"username"`
+
+	// Databases the user is granted access to, declared in databases or the database of the
+	// application itself (the public schema on Postgres)
+	// +kubebuilder:validation:MaxItems=50
+	// +kubebuilder:validation:items:Pattern=`^[a-z][a-z0-9_]{0,62}$`
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+
+	// ReadOnly limits the user to reading data
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// validateDatabaseAccess checks that spec.databaseAccess is set on a MySQL or Postgres application
+// and only grants users databases that exist
+func (r *Application) validateDatabaseAccess() error {
+	access := r.Spec.DatabaseAccess
+	known := map[string]bool{}
+	switch r.Spec.Type {
+	case ApplicationTypeMySQL:
+		if r.Spec.MySQL != nil && r.Spec.MySQL.Database != "" {
+			known[r.Spec.MySQL.Database] = true
+		}
+	case ApplicationTypeMySQLCluster:
+		if r.Spec.MySQLCluster != nil && r.Spec.MySQLCluster.Database != "" {
+			known[r.Spec.MySQLCluster.Database] = true
+		}
+	case ApplicationTypePostgres, ApplicationTypePostgresCluster:
+		known["public"] = true
+	default:
+		return fmt.Errorf("databaseAccess is not supported for %s applications", r.Spec.Type)
+	}
+	for _, database := range access.Databases {
+		known[database.Name] = true
+	}
+
+	for _, user := range access.Users {
+		for _, database := range user.Databases {
+			if !known[database] {
+				return fmt.Errorf("database user %s is granted database %s which is not declared in databaseAccess.databases", user.Username, database)
+			}
+		}
+	}
+	return nil
+}
+
+// DatabaseAccessPhase is the state of the last database access Job
+type DatabaseAccessPhase string
+
+const (
+	DatabaseAccessPhasePending   DatabaseAccessPhase = "Pending"
+	DatabaseAccessPhaseSucceeded DatabaseAccessPhase = "Succeeded"
+	DatabaseAccessPhaseFailed    DatabaseAccessPhase = "Failed"
+)
+
+// DatabaseAccessStatus reports the databases and users applied to a database application
+type DatabaseAccessStatus struct {
+	// Phase of the Job applying the current spec.databaseAccess
+	Phase DatabaseAccessPhase `json:"phase"`
+
+	// Message explains a failed Job
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// JobName is the Job applying the current spec.databaseAccess
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+
+	// Databases are the databases created by the last successful Job
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+
+	// Users are the users created by the last successful Job
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// LastAppliedTime is when a Job last succeeded
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// DefaultServiceBindingEnvPrefix prefixes the variables of service bindings without a prefix
+const DefaultServiceBindingEnvPrefix = "DATABASE"
+
+// ServiceBinding connects an application to a shared database application of its environment.
+// The binding gets a database user of its own, its connection details are injected into the
+// application containers as <PREFIX>_URL, <PREFIX>_HOST, <PREFIX>_PORT, <PREFIX>_DATABASE,
+// <PREFIX>_USERNAME and <PREFIX>_PASSWORD.
+type ServiceBinding struct {
+	// Application references the shared MySQL or Postgres application
+	// +kubebuilder:validation:Required
+	Application corev1.LocalObjectReference `json:"application"`
+
+	// EnvPrefix prefixes the injected variables (defaults to DATABASE)
+	// +kubebuilder:validation:Pattern=`^[A-Z][A-Z0-9_]{0,39}$`
+	// +optional
+	EnvPrefix string `json:"envPrefix,omitempty"`
+
+	// ReadOnly limits the database user of the binding to reading data
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// Prefix returns the prefix of the variables of the binding
+func (b ServiceBinding) Prefix() string {
+	if b.EnvPrefix == "" {
+		return DefaultServiceBindingEnvPrefix
+	}
+	return b.EnvPrefix
+}
+
+// HookFailurePolicy decides what a failed promotion hook does to the promotion
+// +kubebuilder:validation:Enum=Abort;Continue
+type HookFailurePolicy string
+
+const (
+	// HookFailurePolicyAbort keeps a deployment whose pre-promote hook failed from being
+	// promoted, and reverts a promotion whose post-promote hook failed to the previous deployment
+	HookFailurePolicyAbort HookFailurePolicy = "Abort"
+	// HookFailurePolicyContinue promotes the deployment whatever the outcome of the hook
+	HookFailurePolicyContinue HookFailurePolicy = "Continue"
+)
+
+// PromotionHook is a command run as a Job with the image and environment of the deployment
+// being promoted
+type PromotionHook struct {
+	// Image runs the command, the image of the deployment when empty
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Command of the hook, e.g. ["npm", "run", "migrate"]
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+
+	// FailurePolicy decides what a failed hook does to the promotion
+	// +kubebuilder:default=Abort
+	// +optional
+	FailurePolicy HookFailurePolicy `json:"failurePolicy,omitempty"`
+
+	// TimeoutSeconds fails the hook when it runs longer, 600 when unset
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=86400
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// PromotionHooks are the commands run around the promotion of a deployment
+type PromotionHooks struct {
+	// PrePromote runs before the deployment receives traffic, e.g. to migrate the database
+	// +optional
+	PrePromote *PromotionHook `json:"prePromote,omitempty"`
+
+	// PostPromote runs once the deployment receives traffic, e.g. to warm caches
+	// +optional
+	PostPromote *PromotionHook `json:"postPromote,omitempty"`
+}
+
 // ApplicationSpec defines the desired state of Application.
 type ApplicationSpec struct {
 	// EnvironmentRef references the Environment this application belongs to
@@ -421,18 +1188,93 @@ type ApplicationSpec struct {
 	// +kubebuilder:validation:Required
 	Type ApplicationType `json:"type"`
 
-	// Port specifies the container port the application listens on
+	// Port specifies the container port the application listens on. Without a port, deployments
+	// listen on the port their build detected, or 3000.
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=65535
-	// +kubebuilder:default=3000
 	// +optional
 	Port int32 `json:"port,omitempty"`
 
+	// BaseDomain overrides the project and operator base domain for this application's
+	// generated domains (<subdomain>.apps.<baseDomain>)
+	// +kubebuilder:validation:MaxLength=200
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?\.)+[a-z]{2,}$`
+	// +optional
+	BaseDomain string `json:"baseDomain,omitempty"`
+
+	// DependsOn references the applications of the same environment this application needs,
+	// e.g. the database of a web application. Builds of this application wait until every
+	// dependency is ready.
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	DependsOn []corev1.LocalObjectReference `json:"dependsOn,omitempty"`
+
 	// CurrentDeploymentRef references the currently promoted deployment for this application
 	// This field is automatically updated when a deployment with promote=true succeeds
 	// +optional
 	CurrentDeploymentRef *corev1.LocalObjectReference `json:"currentDeploymentRef,omitempty"`
 
+	// ExternalEnv sources environment variables from an external secret store.
+	// External values override variables with the same name in the env secret.
+	// +optional
+	ExternalEnv *ExternalEnvConfig `json:"externalEnv,omitempty"`
+
+	// PlatformEnv injects variables describing the deployment context into the application
+	// containers: KIBASHIP_PROJECT, KIBASHIP_ENVIRONMENT, KIBASHIP_APPLICATION,
+	// KIBASHIP_DEPLOYMENT_UUID, KIBASHIP_COMMIT_SHA, KIBASHIP_PUBLIC_URL and PORT.
+	// Set to false to opt out. Defaults to true.
+	// +optional
+	PlatformEnv *bool `json:"platformEnv,omitempty"`
+
+	// ReplicaSchedule scales the application's deployments on a timetable.
+	// Without a schedule deployments run a single replica.
+	// +optional
+	ReplicaSchedule *ReplicaSchedule `json:"replicaSchedule,omitempty"`
+
+	// Autoscaling scales the deployments of GitRepository, DockerImage and ImageFromRegistry
+	// applications on CPU, request rate or queue metrics through KEDA. It replaces the replica
+	// schedule.
+	// +optional
+	Autoscaling *Autoscaling `json:"autoscaling,omitempty"`
+
+	// LogAlerts fire webhooks when the runtime logs of the application match a pattern more
+	// often than a threshold. Requires the operator logging pipeline.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=20
+	LogAlerts []LogAlertRule `json:"logAlerts,omitempty"`
+
+	// SecurityContext sets the user, filesystem and capabilities of the application containers of
+	// GitRepository, DockerImage and ImageFromRegistry applications
+	// +optional
+	SecurityContext *RuntimeSecurityConfig `json:"securityContext,omitempty"`
+
+	// DatabaseAccess declares additional databases and users of MySQL and Postgres applications
+	// +optional
+	DatabaseAccess *DatabaseAccessConfig `json:"databaseAccess,omitempty"`
+
+	// SessionAffinity pins each client to one replica of GitRepository, DockerImage and
+	// ImageFromRegistry applications with a cookie, for applications keeping sessions in memory
+	// +optional
+	SessionAffinity *SessionAffinity `json:"sessionAffinity,omitempty"`
+
+	// Shared lets the other applications of the environment bind to this MySQL or Postgres
+	// application through serviceBindings
+	// +optional
+	Shared bool `json:"shared,omitempty"`
+
+	// ServiceBindings connect this application to shared database applications of its environment
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	ServiceBindings []ServiceBinding `json:"serviceBindings,omitempty"`
+
+	// Hooks run commands around the automatic promotion of the deployments of GitRepository,
+	// DockerImage and ImageFromRegistry applications. Promotions requested through the API, such
+	// as rollbacks, do not run them.
+	// +optional
+	Hooks *PromotionHooks `json:"hooks,omitempty"`
+
 	// GitRepository contains configuration for GitRepository applications
 	// +optional
 	GitRepository *GitRepositoryConfig `json:"gitRepository,omitempty"`
@@ -487,10 +1329,140 @@ type ApplicationStatus struct {
 	// ObservedGeneration reflects the generation of the most recently observed Application
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Idle reports the scale-to-zero state when the application's environment has an idle policy
+	// +optional
+	Idle *IdleStatus `json:"idle,omitempty"`
+
+	// ReplicaSchedule reports the replica schedule window applied to the application's deployments
+	// +optional
+	ReplicaSchedule *ReplicaScheduleStatus `json:"replicaSchedule,omitempty"`
+
+	// Autoscaling reports the KEDA ScaledObject of the application and its current scale
+	// +optional
+	Autoscaling *AutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// DatabaseAccess reports the databases and users applied to a database application
+	// +optional
+	DatabaseAccess *DatabaseAccessStatus `json:"databaseAccess,omitempty"`
+
+	// LogAlerts reports the state of the log alert rules of the application
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	LogAlerts []LogAlertStatus `json:"logAlerts,omitempty"`
+}
+
+// LogAlertState is the state of a log alert rule
+type LogAlertState string
+
+const (
+	// LogAlertStateOK means the logs matched the rule less often than its threshold
+	LogAlertStateOK LogAlertState = "OK"
+
+	// LogAlertStateFiring means the logs matched the rule at least as often as its threshold
+	LogAlertStateFiring LogAlertState = "Firing"
+)
+
+// LogAlertStatus reports the state of a log alert rule
+type LogAlertStatus struct {
+	// Name is the name of the rule
+	Name string `json:"name"`
+
+	// State is whether the rule is firing
+	State LogAlertState `json:"state"`
+
+	// Matches is the number of matching lines in the minute before the last state change
+	// +optional
+	Matches int64 `json:"matches,omitempty"`
+
+	// LastTransitionTime is when State last changed
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// LastError is the error of the last failed evaluation, empty after a successful one
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// ReplicaScheduleStatus reports the replica schedule window currently applied
+type ReplicaScheduleStatus struct {
+	// ActiveWindow is the name of the active window, empty when the default replicas apply
+	// +optional
+	ActiveWindow string `json:"activeWindow,omitempty"`
+
+	// ActiveUntil is when the active window ends
+	// +optional
+	ActiveUntil *metav1.Time `json:"activeUntil,omitempty"`
+
+	// Replicas is the replica count applied to the application's deployments
+	Replicas int32 `json:"replicas"`
+
+	// LastScaleTime is when the scheduler last changed the replica count
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+}
+
+// AutoscalingStatus reports the KEDA ScaledObject scaling an application
+type AutoscalingStatus struct {
+	// ScaledObject is the name of the KEDA ScaledObject, empty until it is created
+	// +optional
+	ScaledObject string `json:"scaledObject,omitempty"`
+
+	// Ready reports whether KEDA accepted the ScaledObject and reads its triggers
+	Ready bool `json:"ready"`
+
+	// Active reports whether a trigger is above zero, inactive applications scale to minReplicas
+	// +optional
+	Active bool `json:"active,omitempty"`
+
+	// CurrentReplicas is the number of replicas of the current deployment
+	CurrentReplicas int32 `json:"currentReplicas"`
+
+	// DesiredReplicas is the number of replicas the autoscaler asks for
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// LastScaleTime is when the autoscaler last changed the replica count
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+
+	// Message explains why the application is not autoscaled yet
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// IdleState is the scale-to-zero state of an application
+type IdleState string
+
+const (
+	// IdleStateAwake means the application is running and receives traffic through the activator
+	IdleStateAwake IdleState = "Awake"
+
+	// IdleStateSleeping means the application was scaled to zero after receiving no traffic
+	IdleStateSleeping IdleState = "Sleeping"
+
+	// IdleStateWaking means a request woke the application and it is starting
+	IdleStateWaking IdleState = "Waking"
+)
+
+// IdleStatus reports the scale-to-zero state of an application
+type IdleStatus struct {
+	// State is the current sleep/wake state
+	// +kubebuilder:validation:Enum=Awake;Sleeping;Waking
+	State IdleState `json:"state"`
+
+	// LastRequestTime is when the activator last proxied a request to the application
+	// +optional
+	LastRequestTime *metav1.Time `json:"lastRequestTime,omitempty"`
+
+	// LastTransitionTime is when State last changed
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
 // +kubebuilder:printcolumn:name="Environment",type="string",JSONPath=".spec.environmentRef.name"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
@@ -532,7 +1504,13 @@ func (r *Application) ValidateCreate(ctx context.Context, obj runtime.Object) (a
 
 	applicationlog.Info("validate create", "name", app.Name)
 
-	return nil, app.validateApplication(ctx)
+	if err := app.validateApplication(ctx); err != nil {
+		return nil, err
+	}
+	if err := CurrentBaseDomainPolicy().Check(app.Spec.BaseDomain); err != nil {
+		return nil, err
+	}
+	return app.gitCloneWarnings(), app.validateImagePolicy()
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
@@ -546,7 +1524,21 @@ func (r *Application) ValidateUpdate(ctx context.Context, oldObj, newObj runtime
 
 	applicationlog.Info("validate update", "name", app.Name)
 
-	return nil, app.validateApplication(ctx)
+	if err := app.validateApplication(ctx); err != nil {
+		return nil, err
+	}
+	// Applications admitted before a policy change keep their base domain until it is changed
+	if old, ok := oldObj.(*Application); !ok || old.Spec.BaseDomain != app.Spec.BaseDomain {
+		if err := CurrentBaseDomainPolicy().Check(app.Spec.BaseDomain); err != nil {
+			return nil, err
+		}
+	}
+	warnings := app.gitCloneWarnings()
+	// Applications admitted before a policy change keep their image until it is changed
+	if old, ok := oldObj.(*Application); ok && old.RunImage() == app.RunImage() {
+		return warnings, nil
+	}
+	return warnings, app.validateImagePolicy()
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
@@ -639,6 +1631,81 @@ func (r *Application) validateApplication(ctx context.Context) error {
 		}
 	}
 
+	// Validate replica schedule windows
+	if r.Spec.ReplicaSchedule != nil {
+		for _, window := range r.Spec.ReplicaSchedule.Windows {
+			if err := ValidateReplicaWindow(window); err != nil {
+				errors = append(errors, err.Error())
+			}
+		}
+	}
+
+	// Autoscaling sets the replicas of generated deployments, a replica schedule would fight it
+	if r.Spec.Autoscaling != nil {
+		switch r.Spec.Type {
+		case ApplicationTypeGitRepository, ApplicationTypeDockerImage, ApplicationTypeImageFromRegistry:
+			if err := ValidateAutoscaling(*r.Spec.Autoscaling); err != nil {
+				errors = append(errors, err.Error())
+			}
+		default:
+			errors = append(errors, fmt.Sprintf("autoscaling is not supported for %s applications", r.Spec.Type))
+		}
+		if r.Spec.ReplicaSchedule != nil {
+			errors = append(errors, "autoscaling and replicaSchedule cannot be combined")
+		}
+	}
+
+	// Validate log alert patterns
+	for _, rule := range r.Spec.LogAlerts {
+		if err := ValidateLogAlertRule(rule); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	// Only databases with per-user credentials can be shared
+	if r.Spec.Shared {
+		switch r.Spec.Type {
+		case ApplicationTypeMySQL, ApplicationTypeMySQLCluster, ApplicationTypePostgres, ApplicationTypePostgresCluster:
+		default:
+			errors = append(errors, fmt.Sprintf("shared is not supported for %s applications", r.Spec.Type))
+		}
+	}
+
+	// Users are only granted databases the application declares
+	if r.Spec.DatabaseAccess != nil {
+		if err := r.validateDatabaseAccess(); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	if err := ValidateServiceBindings(r.Name, r.Spec.ServiceBindings); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	// Validate the runtime security context, only generated application pods apply it
+	if r.Spec.SecurityContext != nil {
+		switch r.Spec.Type {
+		case ApplicationTypeGitRepository, ApplicationTypeDockerImage, ApplicationTypeImageFromRegistry:
+			if err := ValidateRuntimeSecurity(*r.Spec.SecurityContext); err != nil {
+				errors = append(errors, err.Error())
+			}
+		default:
+			errors = append(errors, fmt.Sprintf("securityContext is not supported for %s applications", r.Spec.Type))
+		}
+	}
+
+	// Session affinity is set by the ingress, only applications serving traffic have one
+	if r.Spec.SessionAffinity != nil {
+		switch r.Spec.Type {
+		case ApplicationTypeGitRepository, ApplicationTypeDockerImage, ApplicationTypeImageFromRegistry:
+			if err := ValidateSessionAffinity(*r.Spec.SessionAffinity); err != nil {
+				errors = append(errors, err.Error())
+			}
+		default:
+			errors = append(errors, fmt.Sprintf("sessionAffinity is not supported for %s applications", r.Spec.Type))
+		}
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("validation failed: %v", errors)
 	}
@@ -685,8 +1752,252 @@ func (r *Application) validateGitRepository() error {
 				return err
 			}
 		}
+
+		if err := ValidateBuildArgs(gitRepo.DockerfileBuild.BuildArgs); err != nil {
+			return err
+		}
+
+		if secret := gitRepo.DockerfileBuild.BuildSecrets; secret != nil {
+			if errs := k8svalidation.IsDNS1123Subdomain(secret.Name); len(errs) > 0 {
+				return fmt.Errorf("BuildSecrets secret name %q is invalid: %s", secret.Name, strings.Join(errs, ", "))
+			}
+		}
+	}
+
+	if secret := gitRepo.BuildEnvSecretRef; secret != nil {
+		if buildType == BuildTypeNixpacks {
+			return fmt.Errorf("BuildEnv is not supported by Nixpacks builds, which store their environment in the image")
+		}
+		if errs := k8svalidation.IsDNS1123Subdomain(secret.Name); len(errs) > 0 {
+			return fmt.Errorf("BuildEnvSecretRef secret name %q is invalid: %s", secret.Name, strings.Join(errs, ", "))
+		}
+	}
+
+	// Validate the builder image of Buildpacks builds
+	if buildType == BuildTypeBuildpacks && gitRepo.BuildpacksBuild != nil {
+		if image := gitRepo.BuildpacksBuild.BuilderImage; image != "" && strings.ContainsAny(image, " \t\n") {
+			return fmt.Errorf("BuilderImage must be an image reference without whitespace: %q", image)
+		}
+	}
+
+	if err := ValidatePipelineSteps(gitRepo.Steps); err != nil {
+		return err
+	}
+
+	if gitRepo.CloneDepth < 0 || gitRepo.CloneDepth > MaxCloneDepth {
+		return fmt.Errorf("CloneDepth must be between 0 and %d, got: %d", MaxCloneDepth, gitRepo.CloneDepth)
+	}
+	buildPaths := []string{gitRepo.RootDirectory}
+	if buildType == BuildTypeDockerfile {
+		buildPaths = append(buildPaths, path.Dir(gitRepo.DockerfileBuild.DockerfilePath), gitRepo.DockerfileBuild.BuildContext)
+	}
+	if err := ValidateSparseCheckoutPaths(gitRepo.SparseCheckoutPaths, buildPaths...); err != nil {
+		return err
+	}
+
+	return ValidateBuildScheduling(gitRepo.BuildScheduling)
+}
+
+// gitCloneWarnings warns about git clone options that may not work for every repository
+func (r *Application) gitCloneWarnings() admission.Warnings {
+	gitRepo := r.Spec.GitRepository
+	if r.Spec.Type != ApplicationTypeGitRepository || gitRepo == nil {
+		return nil
+	}
+	if gitRepo.GitSubmodules && !gitRepo.PublicAccess {
+		return admission.Warnings{fmt.Sprintf("private submodules are cloned with the access token of %s, "+
+			"submodules hosted on another provider or requiring another token fail the build", gitRepo.Provider)}
+	}
+	return nil
+}
+
+// ValidateBuildScheduling checks that the node selector uses valid labels and the tolerations
+// use a known operator and taint effect
+func ValidateBuildScheduling(scheduling *BuildSchedulingConfig) error {
+	if scheduling == nil {
+		return nil
+	}
+
+	for key, value := range scheduling.NodeSelector {
+		if errs := k8svalidation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("node selector label %q is invalid: %s", key, strings.Join(errs, ", "))
+		}
+		if errs := k8svalidation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("node selector value %q of label %s is invalid: %s", value, key, strings.Join(errs, ", "))
+		}
+	}
+
+	for _, toleration := range scheduling.Tolerations {
+		if toleration.Key != "" {
+			if errs := k8svalidation.IsQualifiedName(toleration.Key); len(errs) > 0 {
+				return fmt.Errorf("toleration key %q is invalid: %s", toleration.Key, strings.Join(errs, ", "))
+			}
+		}
+
+		switch toleration.Operator {
+		case "", corev1.TolerationOpEqual:
+			if toleration.Key == "" {
+				return fmt.Errorf("toleration with operator Equal requires a key")
+			}
+			if errs := k8svalidation.IsValidLabelValue(toleration.Value); len(errs) > 0 {
+				return fmt.Errorf("toleration value %q is invalid: %s", toleration.Value, strings.Join(errs, ", "))
+			}
+		case corev1.TolerationOpExists:
+			if toleration.Value != "" {
+				return fmt.Errorf("toleration %q with operator Exists must not have a value", toleration.Key)
+			}
+		default:
+			return fmt.Errorf("toleration operator %q must be Equal or Exists", toleration.Operator)
+		}
+
+		switch toleration.Effect {
+		case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			return fmt.Errorf("toleration effect %q must be NoSchedule, PreferNoSchedule or NoExecute", toleration.Effect)
+		}
+		if toleration.TolerationSeconds != nil && toleration.Effect != corev1.TaintEffectNoExecute {
+			return fmt.Errorf("toleration %q sets tolerationSeconds, which requires the NoExecute effect", toleration.Key)
+		}
+	}
+
+	return nil
+}
+
+// ValidateBuildArgs checks that build arg names are valid ARG names
+func ValidateBuildArgs(args map[string]string) error {
+	namePattern := regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	for name := range args {
+		if !namePattern.MatchString(name) {
+			return fmt.Errorf("build arg name %q must start with a letter or underscore and contain only letters, digits and underscores", name)
+		}
 	}
+	return nil
+}
 
+// ValidateBuildEnv checks that build environment variable names are valid, their values fit in
+// the secret they are passed to the build through and their references are well formed
+func ValidateBuildEnv(env map[string]string) error {
+	if len(env) > MaxBuildEnvVariables {
+		return fmt.Errorf("build env holds %d variables, at most %d are allowed", len(env), MaxBuildEnvVariables)
+	}
+	namePattern := regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	size := 0
+	for name, value := range env {
+		if !namePattern.MatchString(name) {
+			return fmt.Errorf("build env name %q must start with a letter or underscore and contain only letters, digits and underscores", name)
+		}
+		size += len(name) + len(value)
+	}
+	if size > MaxBuildEnvBytes {
+		return fmt.Errorf("build env holds %d bytes, at most %d are allowed", size, MaxBuildEnvBytes)
+	}
+	if err := ValidateEnvTemplates(env); err != nil {
+		return fmt.Errorf("build %w", err)
+	}
+	return nil
+}
+
+// ValidateSparseCheckoutPaths checks that the sparse checkout directories are relative paths
+// inside the repository and that they contain the directories the build reads
+func ValidateSparseCheckoutPaths(paths []string, buildPaths ...string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	if len(paths) > MaxSparseCheckoutPaths {
+		return fmt.Errorf("at most %d sparse checkout paths are allowed", MaxSparseCheckoutPaths)
+	}
+
+	directories := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if strings.HasPrefix(p, "/") || strings.Contains(p, "..") {
+			return fmt.Errorf("sparse checkout path must be a relative path inside the repository: %q", p)
+		}
+		if strings.ContainsAny(p, "*?[!\\ \t\n") {
+			return fmt.Errorf("sparse checkout path must be a directory without wildcards or whitespace: %q", p)
+		}
+		directory := cleanRepositoryPath(p)
+		if directory == "" {
+			return fmt.Errorf("sparse checkout path must not be the root of the repository: %q", p)
+		}
+		if slices.Contains(directories, directory) {
+			return fmt.Errorf("sparse checkout path %q is listed more than once", p)
+		}
+		directories = append(directories, directory)
+	}
+
+	for _, buildPath := range buildPaths {
+		buildPath = cleanRepositoryPath(buildPath)
+		if buildPath == "" {
+			continue
+		}
+		covered := false
+		for _, directory := range directories {
+			if buildPath == directory || strings.HasPrefix(buildPath, directory+"/") {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return fmt.Errorf("%s is used by the build but not checked out by the sparse checkout paths", buildPath)
+		}
+	}
+	return nil
+}
+
+// cleanRepositoryPath returns a path relative to the root of a repository without leading ./
+// and trailing slashes, empty for the root itself
+func cleanRepositoryPath(p string) string {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if p == "." {
+		return ""
+	}
+	return p
+}
+
+// ValidateServiceBindings checks that an application binds to other applications, at most once
+// each, and that the variables of its bindings do not collide
+func ValidateServiceBindings(applicationName string, bindings []ServiceBinding) error {
+	applications := make(map[string]bool, len(bindings))
+	prefixes := make(map[string]bool, len(bindings))
+	for _, binding := range bindings {
+		name := binding.Application.Name
+		if name == "" {
+			return fmt.Errorf("service binding requires an application")
+		}
+		if name == applicationName {
+			return fmt.Errorf("an application cannot bind to itself")
+		}
+		if applications[name] {
+			return fmt.Errorf("application %s is bound more than once", name)
+		}
+		applications[name] = true
+		if prefixes[binding.Prefix()] {
+			return fmt.Errorf("service binding env prefix %s is used more than once", binding.Prefix())
+		}
+		prefixes[binding.Prefix()] = true
+	}
+	return nil
+}
+
+// ValidatePipelineSteps checks that custom pipeline steps have unique names, an image and a script
+func ValidatePipelineSteps(steps []PipelineStep) error {
+	namePattern := regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	seen := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		if !namePattern.MatchString(step.Name) || len(step.Name) > 40 {
+			return fmt.Errorf("pipeline step name %q must be a lowercase DNS label of at most 40 characters", step.Name)
+		}
+		if seen[step.Name] {
+			return fmt.Errorf("pipeline step name %q is used more than once", step.Name)
+		}
+		seen[step.Name] = true
+		if strings.TrimSpace(step.Image) == "" {
+			return fmt.Errorf("pipeline step %q requires an image", step.Name)
+		}
+		if strings.TrimSpace(step.Script) == "" {
+			return fmt.Errorf("pipeline step %q requires a script", step.Name)
+		}
+	}
 	return nil
 }
 
@@ -858,6 +2169,12 @@ func (r *Application) GetProjectUUID() string {
 	return r.Labels[validation.LabelProjectUUID]
 }
 
+// PlatformEnvEnabled reports whether the deployment context variables are injected into the
+// application containers
+func (r *Application) PlatformEnvEnabled() bool {
+	return r.Spec.PlatformEnv == nil || *r.Spec.PlatformEnv
+}
+
 // SetupWebhookWithManager will setup the manager to manage the webhooks
 func (r *Application) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
@@ -18,16 +18,26 @@ package v1alpha1
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	apivalidation "k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/kibamail/kibaship/pkg/uptime"
 	"github.com/kibamail/kibaship/pkg/validation"
 )
 
@@ -42,6 +52,19 @@ const (
 	ApplicationDomainTypeCustom ApplicationDomainType = "custom"
 )
 
+// BackendProtocol is the protocol an application speaks behind its domains
+// +kubebuilder:validation:Enum=http1;grpc;websocket
+type BackendProtocol string
+
+const (
+	// BackendProtocolHTTP1 is plain HTTP/1.1, the default
+	BackendProtocolHTTP1 BackendProtocol = "http1"
+	// BackendProtocolGRPC is gRPC over cleartext HTTP/2 (h2c)
+	BackendProtocolGRPC BackendProtocol = "grpc"
+	// BackendProtocolWebSocket is HTTP/1.1 upgraded to long lived WebSocket connections
+	BackendProtocolWebSocket BackendProtocol = "websocket"
+)
+
 // ApplicationDomainPhase defines the phase of an ApplicationDomain
 // +kubebuilder:validation:Enum=Pending;Ready;Failed
 type ApplicationDomainPhase string
@@ -55,6 +78,419 @@ const (
 	ApplicationDomainPhaseFailed ApplicationDomainPhase = "Failed"
 )
 
+// UptimeState is the availability of a domain as seen by its uptime check
+// +kubebuilder:validation:Enum=Unknown;Up;Down
+type UptimeState string
+
+const (
+	// UptimeStateUnknown indicates the domain has not been probed yet
+	UptimeStateUnknown UptimeState = "Unknown"
+	// UptimeStateUp indicates the last probe succeeded
+	UptimeStateUp UptimeState = "Up"
+	// UptimeStateDown indicates FailureThreshold consecutive probes failed
+	UptimeStateDown UptimeState = "Down"
+)
+
+// UptimeCheck configures periodic HTTP probes of a domain by the operator
+type UptimeCheck struct {
+	// Interval between probes, at least 30s
+	// +kubebuilder:default="1m"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// Path is the HTTP path probed
+	// +kubebuilder:default="/"
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// ExpectedStatus is the HTTP status a healthy domain returns. Any status below 400 is
+	// accepted when unset.
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=599
+	// +optional
+	ExpectedStatus int32 `json:"expectedStatus,omitempty"`
+
+	// Timeout of a single probe
+	// +kubebuilder:default="10s"
+	// +optional
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed probes after which the domain is down
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +kubebuilder:default=3
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+}
+
+// ProbeInterval returns the interval between probes, uptime.DefaultInterval when unset
+func (c *UptimeCheck) ProbeInterval() time.Duration {
+	if c.Interval.Duration <= 0 {
+		return uptime.DefaultInterval
+	}
+	return c.Interval.Duration
+}
+
+// ProbeTimeout returns the timeout of a probe, uptime.DefaultTimeout when unset
+func (c *UptimeCheck) ProbeTimeout() time.Duration {
+	if c.Timeout.Duration <= 0 {
+		return uptime.DefaultTimeout
+	}
+	return c.Timeout.Duration
+}
+
+// ProbePath returns the probed path, / when unset
+func (c *UptimeCheck) ProbePath() string {
+	if c.Path == "" {
+		return "/"
+	}
+	return c.Path
+}
+
+// Threshold returns the failure threshold, uptime.DefaultFailureThreshold when unset
+func (c *UptimeCheck) Threshold() int32 {
+	if c.FailureThreshold <= 0 {
+		return uptime.DefaultFailureThreshold
+	}
+	return c.FailureThreshold
+}
+
+// ValidateUptimeCheck checks the interval, timeout and path of an uptime check
+func ValidateUptimeCheck(check *UptimeCheck) error {
+	if check.Interval.Duration != 0 && check.Interval.Duration < uptime.MinInterval {
+		return fmt.Errorf("uptime check interval must be at least %s", uptime.MinInterval)
+	}
+	if check.Timeout.Duration < 0 || check.ProbeTimeout() >= check.ProbeInterval() {
+		return fmt.Errorf("uptime check timeout must be positive and shorter than the interval")
+	}
+	if check.Path != "" && !strings.HasPrefix(check.Path, "/") {
+		return fmt.Errorf("uptime check path must start with /")
+	}
+	return nil
+}
+
+// DomainRoute routes a path prefix of a domain to an application
+type DomainRoute struct {
+	// Path is the path prefix routed to the application, e.g. "/api". Requests keep the full path.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^/[A-Za-z0-9._~/-]*$`
+	Path string `json:"path"`
+
+	// ApplicationRef references the application serving the path, in the namespace of the domain
+	// +kubebuilder:validation:Required
+	ApplicationRef corev1.LocalObjectReference `json:"applicationRef"`
+
+	// Port is the application port the path is routed to
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=3000
+	Port int32 `json:"port,omitempty"`
+}
+
+// NormalizeRoutePath returns the canonical form of a route path prefix, without a trailing slash
+func NormalizeRoutePath(path string) string {
+	if trimmed := strings.TrimRight(path, "/"); trimmed != "" {
+		return trimmed
+	}
+	return "/"
+}
+
+// ValidateDomainRoutes checks the paths of the routes of a domain are well formed and unique
+func ValidateDomainRoutes(routes []DomainRoute) error {
+	seen := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, "/") {
+			return fmt.Errorf("route path %q must start with /", route.Path)
+		}
+		if strings.Contains(route.Path, "//") {
+			return fmt.Errorf("route path %q must not contain empty segments", route.Path)
+		}
+		if route.ApplicationRef.Name == "" {
+			return fmt.Errorf("route %s must reference an application", route.Path)
+		}
+		path := NormalizeRoutePath(route.Path)
+		if path == "/" {
+			return fmt.Errorf("route path / is served by the application of the domain")
+		}
+		if seen[path] {
+			return fmt.Errorf("route path %s is routed more than once", path)
+		}
+		seen[path] = true
+	}
+	return nil
+}
+
+// SecurityHeaders configures security headers added to every response of a domain
+type SecurityHeaders struct {
+	// HSTSMaxAgeSeconds enables Strict-Transport-Security with this max-age, 0 leaves it unset
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	HSTSMaxAgeSeconds int64 `json:"hstsMaxAgeSeconds,omitempty"`
+
+	// HSTSIncludeSubdomains adds includeSubDomains to Strict-Transport-Security
+	// +optional
+	HSTSIncludeSubdomains bool `json:"hstsIncludeSubdomains,omitempty"`
+
+	// HSTSPreload adds preload to Strict-Transport-Security
+	// +optional
+	HSTSPreload bool `json:"hstsPreload,omitempty"`
+
+	// ContentSecurityPolicy is the value of the Content-Security-Policy header
+	// +kubebuilder:validation:Pattern=`^[^"$\\]*$`
+	// +kubebuilder:validation:MaxLength=4096
+	// +optional
+	ContentSecurityPolicy string `json:"contentSecurityPolicy,omitempty"`
+
+	// FrameOptions is the value of the X-Frame-Options header
+	// +kubebuilder:validation:Enum=DENY;SAMEORIGIN
+	// +optional
+	FrameOptions string `json:"frameOptions,omitempty"`
+
+	// ContentTypeNosniff sets X-Content-Type-Options to nosniff
+	// +optional
+	ContentTypeNosniff bool `json:"contentTypeNosniff,omitempty"`
+
+	// ReferrerPolicy is the value of the Referrer-Policy header
+	// +kubebuilder:validation:Enum=no-referrer;no-referrer-when-downgrade;origin;origin-when-cross-origin;same-origin;strict-origin;strict-origin-when-cross-origin;unsafe-url
+	// +optional
+	ReferrerPolicy string `json:"referrerPolicy,omitempty"`
+}
+
+// ResponseHeaders returns the headers set on every response, keyed by header name
+func (h *SecurityHeaders) ResponseHeaders() map[string]string {
+	headers := map[string]string{}
+	if h.HSTSMaxAgeSeconds > 0 {
+		value := fmt.Sprintf("max-age=%d", h.HSTSMaxAgeSeconds)
+		if h.HSTSIncludeSubdomains {
+			value += "; includeSubDomains"
+		}
+		if h.HSTSPreload {
+			value += "; preload"
+		}
+		headers["Strict-Transport-Security"] = value
+	}
+	if h.ContentSecurityPolicy != "" {
+		headers["Content-Security-Policy"] = h.ContentSecurityPolicy
+	}
+	if h.FrameOptions != "" {
+		headers["X-Frame-Options"] = h.FrameOptions
+	}
+	if h.ContentTypeNosniff {
+		headers["X-Content-Type-Options"] = "nosniff"
+	}
+	if h.ReferrerPolicy != "" {
+		headers["Referrer-Policy"] = h.ReferrerPolicy
+	}
+	return headers
+}
+
+// CORSPolicy configures the cross-origin requests browsers allow to a domain
+type CORSPolicy struct {
+	// AllowOrigins are the origins allowed to call the domain, e.g. "https://app.example.com", or "*" for any origin
+	// +kubebuilder:validation:MinItems=1
+	AllowOrigins []string `json:"allowOrigins"`
+
+	// AllowMethods are the methods allowed in cross-origin requests, GET, HEAD and POST when empty
+	// +optional
+	AllowMethods []string `json:"allowMethods,omitempty"`
+
+	// AllowHeaders are the request headers allowed in cross-origin requests
+	// +optional
+	AllowHeaders []string `json:"allowHeaders,omitempty"`
+
+	// ExposeHeaders are the response headers exposed to cross-origin callers
+	// +optional
+	ExposeHeaders []string `json:"exposeHeaders,omitempty"`
+
+	// AllowCredentials allows cookies and authorization headers in cross-origin requests
+	// +optional
+	AllowCredentials bool `json:"allowCredentials,omitempty"`
+
+	// MaxAgeSeconds is how long browsers may cache preflight responses
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxAgeSeconds int32 `json:"maxAgeSeconds,omitempty"`
+}
+
+// Methods returns the allowed methods, GET, HEAD and POST when none are configured
+func (c *CORSPolicy) Methods() []string {
+	if len(c.AllowMethods) == 0 {
+		return []string{"GET", "HEAD", "POST"}
+	}
+	return c.AllowMethods
+}
+
+// referrerPolicies are the values of the Referrer-Policy header
+var referrerPolicies = map[string]bool{
+	"no-referrer": true, "no-referrer-when-downgrade": true, "origin": true, "origin-when-cross-origin": true,
+	"same-origin": true, "strict-origin": true, "strict-origin-when-cross-origin": true, "unsafe-url": true,
+}
+
+// corsMethods are the methods a CORS policy may allow
+var corsMethods = map[string]bool{
+	"GET": true, "HEAD": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "OPTIONS": true,
+}
+
+// ValidateSecurityHeaders checks security header values can be rendered into ingress configuration
+func ValidateSecurityHeaders(headers *SecurityHeaders) error {
+	if headers.HSTSMaxAgeSeconds < 0 {
+		return fmt.Errorf("hstsMaxAgeSeconds must not be negative")
+	}
+	if strings.ContainsAny(headers.ContentSecurityPolicy, "\"$\\\r\n") {
+		return fmt.Errorf("contentSecurityPolicy must not contain quotes, $, backslashes or line breaks")
+	}
+	if headers.FrameOptions != "" && headers.FrameOptions != "DENY" && headers.FrameOptions != "SAMEORIGIN" {
+		return fmt.Errorf("frameOptions must be DENY or SAMEORIGIN")
+	}
+	if headers.ReferrerPolicy != "" && !referrerPolicies[headers.ReferrerPolicy] {
+		return fmt.Errorf("referrerPolicy %q is not a valid Referrer-Policy", headers.ReferrerPolicy)
+	}
+	return nil
+}
+
+// ValidateCORSPolicy checks the origins, methods and headers of a CORS policy
+func ValidateCORSPolicy(cors *CORSPolicy) error {
+	if len(cors.AllowOrigins) == 0 {
+		return fmt.Errorf("cors must allow at least one origin")
+	}
+	for _, origin := range cors.AllowOrigins {
+		if origin == "*" {
+			if cors.AllowCredentials {
+				return fmt.Errorf("cors cannot allow credentials for any origin (*)")
+			}
+			continue
+		}
+		if !corsOriginPattern.MatchString(origin) {
+			return fmt.Errorf("cors origin %q must be * or a scheme and host such as https://app.example.com", origin)
+		}
+	}
+	for _, method := range cors.AllowMethods {
+		if !corsMethods[method] {
+			return fmt.Errorf("cors method %q is not supported", method)
+		}
+	}
+	for _, header := range append(append([]string{}, cors.AllowHeaders...), cors.ExposeHeaders...) {
+		if !corsHeaderPattern.MatchString(header) {
+			return fmt.Errorf("cors header %q must be a header name", header)
+		}
+	}
+	if cors.MaxAgeSeconds < 0 {
+		return fmt.Errorf("cors maxAgeSeconds must not be negative")
+	}
+	return nil
+}
+
+var (
+	corsOriginPattern = regexp.MustCompile(`^https?://[a-z0-9]([a-z0-9.-]*[a-z0-9])?(:[0-9]{1,5})?$`)
+	corsHeaderPattern = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+)
+
+// MaxIngressAnnotations is the number of annotations an ApplicationDomain may add to its Ingress
+const MaxIngressAnnotations = 50
+
+// IngressAnnotationPrefixes are the prefixes of the annotations an ApplicationDomain may add to
+// its Ingress
+var IngressAnnotationPrefixes = []string{
+	"nginx.ingress.kubernetes.io/",
+	"traefik.ingress.kubernetes.io/",
+}
+
+// ValidateIngressAnnotations checks the annotations an ApplicationDomain adds to its Ingress use
+// an allowed prefix. Snippet annotations are rejected, they inject raw ingress-nginx configuration
+// that could read the secrets of other namespaces.
+func ValidateIngressAnnotations(annotations map[string]string) error {
+	if len(annotations) > MaxIngressAnnotations {
+		return fmt.Errorf("at most %d ingress annotations are allowed, got %d", MaxIngressAnnotations, len(annotations))
+	}
+
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		allowed := false
+		for _, prefix := range IngressAnnotationPrefixes {
+			if strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("ingress annotation %q must start with one of %s", key, strings.Join(IngressAnnotationPrefixes, ", "))
+		}
+		if strings.Contains(key, "snippet") {
+			return fmt.Errorf("ingress annotation %q is not allowed, snippets inject raw ingress configuration", key)
+		}
+		if errs := apivalidation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("ingress annotation %q is not a valid annotation name: %s", key, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+var (
+	// MinRequestBodySize and MaxRequestBodySize bound the request body size limit of a domain
+	MinRequestBodySize = resource.MustParse("1Ki")
+	MaxRequestBodySize = resource.MustParse("5Gi")
+)
+
+// MaxProxyTimeout is the longest the ingress of a domain may wait on its application
+const MaxProxyTimeout = time.Hour
+
+// ValidateProxySettings checks the request body size limit and proxy timeouts of a domain are
+// within bounds, timeouts are whole seconds as ingress-nginx only accepts seconds
+func ValidateProxySettings(maxBodySize *resource.Quantity, readTimeout, sendTimeout *metav1.Duration) error {
+	if maxBodySize != nil && (maxBodySize.Cmp(MinRequestBodySize) < 0 || maxBodySize.Cmp(MaxRequestBodySize) > 0) {
+		return fmt.Errorf("maxBodySize must be between %s and %s, got %s",
+			MinRequestBodySize.String(), MaxRequestBodySize.String(), maxBodySize.String())
+	}
+
+	timeouts := []struct {
+		name    string
+		timeout *metav1.Duration
+	}{
+		{name: "proxyReadTimeout", timeout: readTimeout},
+		{name: "proxySendTimeout", timeout: sendTimeout},
+	}
+	for _, t := range timeouts {
+		if t.timeout == nil {
+			continue
+		}
+		if t.timeout.Duration < time.Second || t.timeout.Duration > MaxProxyTimeout {
+			return fmt.Errorf("%s must be between 1s and %.0fh, got %s", t.name, MaxProxyTimeout.Hours(), t.timeout.Duration)
+		}
+		if t.timeout.Duration%time.Second != 0 {
+			return fmt.Errorf("%s must be a whole number of seconds, got %s", t.name, t.timeout.Duration)
+		}
+	}
+	return nil
+}
+
+// ValidateDomainCertificate checks a PEM certificate chain and private key form a pair that is
+// valid for domain at now, and returns the leaf certificate
+func ValidateDomainCertificate(certPEM, keyPEM []byte, domain string, now time.Time) (*x509.Certificate, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("certificate and private key are not a valid pair: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %v", err)
+	}
+	if err := leaf.VerifyHostname(domain); err != nil {
+		return nil, fmt.Errorf("certificate is not valid for %s", domain)
+	}
+	if now.Before(leaf.NotBefore) {
+		return nil, fmt.Errorf("certificate is not valid before %s", leaf.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if !now.Before(leaf.NotAfter) {
+		return nil, fmt.Errorf("certificate expired on %s", leaf.NotAfter.UTC().Format(time.RFC3339))
+	}
+	return leaf, nil
+}
+
 // ApplicationDomainSpec defines the desired state of ApplicationDomain
 type ApplicationDomainSpec struct {
 	// ApplicationRef references the parent application
@@ -87,6 +523,117 @@ type ApplicationDomainSpec struct {
 	// +kubebuilder:default=true
 	// Note: omit 'omitempty' so that false is preserved over the default.
 	TLSEnabled bool `json:"tlsEnabled"`
+
+	// UptimeCheck enables periodic availability probes of the domain by the operator.
+	// Downtime is reported on the status and through webhooks.
+	// +optional
+	UptimeCheck *UptimeCheck `json:"uptimeCheck,omitempty"`
+
+	// Routes send path prefixes of the domain to other applications, e.g. /api to a backend
+	// while the referenced application keeps serving every other path. The longest matching
+	// prefix wins.
+	// +optional
+	Routes []DomainRoute `json:"routes,omitempty"`
+
+	// SecurityHeaders adds security headers such as HSTS, CSP and X-Frame-Options to every response
+	// +optional
+	SecurityHeaders *SecurityHeaders `json:"securityHeaders,omitempty"`
+
+	// CORS allows browsers to call the domain from other origins
+	// +optional
+	CORS *CORSPolicy `json:"cors,omitempty"`
+
+	// IgnoreTLSPolicy opts the domain out of the cluster TLS policy: HTTP is served without
+	// redirect, and neither the policy HSTS header nor its minimum TLS version apply
+	// +optional
+	IgnoreTLSPolicy bool `json:"ignoreTLSPolicy,omitempty"`
+
+	// CertificateSecretRef references a kubernetes.io/tls Secret in the namespace of the domain
+	// holding the certificate served for it instead of one issued through ACME. Only custom
+	// domains may bring their own certificate.
+	// +optional
+	CertificateSecretRef *corev1.LocalObjectReference `json:"certificateSecretRef,omitempty"`
+
+	// IngressAnnotations are added to the Ingress of the domain for controller specific behavior,
+	// such as nginx.ingress.kubernetes.io/proxy-body-size. Only the prefixes of the ingress-nginx
+	// and Traefik annotations are allowed, annotations the platform sets take precedence and
+	// Gateway API routes ignore them.
+	// +kubebuilder:validation:MaxProperties=50
+	// +optional
+	IngressAnnotations map[string]string `json:"ingressAnnotations,omitempty"`
+
+	// BackendProtocol is the protocol the application speaks behind the domain, grpc for gRPC
+	// over cleartext HTTP/2 or websocket for long lived connections (defaults to http1). Traefik
+	// and Gateway API read it from the application Service, so the domains of an application
+	// must agree on it and domains without one follow the others.
+	// +optional
+	BackendProtocol BackendProtocol `json:"backendProtocol,omitempty"`
+
+	// MaxBodySize limits the size of request bodies sent to the domain, between 1Ki and 5Gi.
+	// Unset keeps the ingress controller default, 1m for ingress-nginx. Traefik does not limit
+	// request bodies and Gateway API has no standard limit, they ignore it.
+	// +optional
+	MaxBodySize *resource.Quantity `json:"maxBodySize,omitempty"`
+
+	// ProxyReadTimeout is how long the ingress waits between two reads of the response of the
+	// application, in whole seconds up to 1h. Gateway API routes use the longest of the read and
+	// send timeouts as their request timeout, Traefik configures timeouts per entrypoint and
+	// ignores both.
+	// +optional
+	ProxyReadTimeout *metav1.Duration `json:"proxyReadTimeout,omitempty"`
+
+	// ProxySendTimeout is how long the ingress waits between two writes of the request to the
+	// application, in whole seconds up to 1h
+	// +optional
+	ProxySendTimeout *metav1.Duration `json:"proxySendTimeout,omitempty"`
+}
+
+// HasResponsePolicy reports whether responses of the domain carry security or CORS headers
+func (s *ApplicationDomainSpec) HasResponsePolicy() bool {
+	return s.SecurityHeaders != nil || s.CORS != nil
+}
+
+// HasProxySettings reports whether the domain tunes its request body size limit or proxy timeouts
+func (s *ApplicationDomainSpec) HasProxySettings() bool {
+	return s.MaxBodySize != nil || s.ProxyReadTimeout != nil || s.ProxySendTimeout != nil
+}
+
+// UptimeStatus reports the results of the domain's uptime check
+type UptimeStatus struct {
+	// State is the availability of the domain
+	State UptimeState `json:"state,omitempty"`
+
+	// LastCheckTime is when the domain was last probed
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// LastTransitionTime is when State last changed
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// LastStatusCode is the HTTP status of the last probe, 0 when the request failed
+	LastStatusCode int32 `json:"lastStatusCode,omitempty"`
+
+	// LastResponseTimeMillis is how long the last probe took
+	LastResponseTimeMillis int64 `json:"lastResponseTimeMillis,omitempty"`
+
+	// LastError is the error of the last failed probe
+	LastError string `json:"lastError,omitempty"`
+
+	// ConsecutiveFailures is the number of failed probes since the last success
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+
+	// TotalChecks is the number of probes since the check was enabled
+	TotalChecks int64 `json:"totalChecks,omitempty"`
+
+	// SuccessfulChecks is the number of successful probes since the check was enabled
+	SuccessfulChecks int64 `json:"successfulChecks,omitempty"`
+}
+
+// UptimePercentage returns the share of successful probes, 0 before the first probe
+func (s *UptimeStatus) UptimePercentage() float64 {
+	if s.TotalChecks == 0 {
+		return 0
+	}
+	return float64(s.SuccessfulChecks) * 100 / float64(s.TotalChecks)
 }
 
 // NamespacedRef is a simple reference to a namespaced object by name/namespace
@@ -96,6 +643,42 @@ type NamespacedRef struct {
 	Namespace string `json:"namespace"`
 }
 
+// DomainHistorySource is the part of a domain an entry of its history is about
+// +kubebuilder:validation:Enum=Certificate;Ingress
+type DomainHistorySource string
+
+const (
+	// DomainHistorySourceCertificate records certificate issuance attempts and failures
+	DomainHistorySourceCertificate DomainHistorySource = "Certificate"
+
+	// DomainHistorySourceIngress records the routing of the domain to its application
+	DomainHistorySourceIngress DomainHistorySource = "Ingress"
+)
+
+// MaxDomainHistory bounds the history of a domain, older entries are dropped first
+const MaxDomainHistory = 20
+
+// DomainHistoryEntry is a certificate or ingress event of a domain, also emitted as a Kubernetes
+// Event on the ApplicationDomain
+type DomainHistoryEntry struct {
+	// Time is when the event was observed
+	Time metav1.Time `json:"time"`
+
+	// Source is the part of the domain the event is about
+	Source DomainHistorySource `json:"source"`
+
+	// Type is Normal or Warning, like the type of Kubernetes Events
+	// +kubebuilder:validation:Enum=Normal;Warning
+	Type string `json:"type"`
+
+	// Reason is a CamelCase summary of the event, such as CertificateFailed
+	Reason string `json:"reason"`
+
+	// Message details the event
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // ApplicationDomainStatus defines the observed state of ApplicationDomain
 type ApplicationDomainStatus struct {
 	// Phase indicates the current phase of the domain
@@ -108,6 +691,10 @@ type ApplicationDomainStatus struct {
 	// CertificateRef references the cert-manager Certificate created for this domain
 	CertificateRef *NamespacedRef `json:"certificateRef,omitempty"`
 
+	// CertificateNotAfter is when the uploaded certificate of the domain expires
+	// +optional
+	CertificateNotAfter *metav1.Time `json:"certificateNotAfter,omitempty"`
+
 	// IngressReady indicates if the ingress is configured and ready
 	IngressReady bool `json:"ingressReady,omitempty"`
 
@@ -122,10 +709,20 @@ type ApplicationDomainStatus struct {
 
 	// Conditions represent the latest available observations of the domain state
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Uptime reports the results of the uptime check, nil when no check is configured
+	// +optional
+	Uptime *UptimeStatus `json:"uptime,omitempty"`
+
+	// History lists the latest certificate and ingress events of the domain, oldest first
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	History []DomainHistoryEntry `json:"history,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:webhook:path=/validate-platform-operator-kibaship-com-v1alpha1-applicationdomain,mutating=false,failurePolicy=fail,sideEffects=None,groups=platform.operator.kibaship.com,resources=applicationdomains,verbs=create;update,versions=v1alpha1,name=vapplicationdomain.kb.io,admissionReviewVersions=v1
 // +kubebuilder:printcolumn:name="Domain",type=string,JSONPath=".spec.domain"
 // +kubebuilder:printcolumn:name="Port",type=integer,JSONPath=".spec.port"
@@ -154,6 +751,12 @@ func init() {
 	SchemeBuilder.Register(&ApplicationDomain{}, &ApplicationDomainList{})
 }
 
+// AllowsDomainConflict reports whether an admin allowed the domain to claim a hostname another
+// ApplicationDomain already claims
+func (r *ApplicationDomain) AllowsDomainConflict() bool {
+	return r.GetAnnotations()[validation.AnnotationAllowDomainConflict] == "true"
+}
+
 var _ webhook.CustomValidator = &ApplicationDomain{}
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
@@ -204,8 +807,7 @@ func (r *ApplicationDomain) validateApplicationDomain(ctx context.Context) error
 	var errors []string
 
 	// Use the centralized labeling validation
-	// Note: In webhook context, we don't have access to the client for uniqueness checks
-	// Uniqueness will be validated in the controller reconcile loop
+	// Hostname uniqueness needs a client, it is checked by applicationDomainValidator
 	labels := r.GetLabels()
 	if labels == nil {
 		errors = append(errors, "application domain must have labels")
@@ -246,6 +848,45 @@ func (r *ApplicationDomain) validateApplicationDomain(ctx context.Context) error
 		}
 	}
 
+	if r.Spec.UptimeCheck != nil {
+		if err := ValidateUptimeCheck(r.Spec.UptimeCheck); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	if err := ValidateDomainRoutes(r.Spec.Routes); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	if r.Spec.SecurityHeaders != nil {
+		if err := ValidateSecurityHeaders(r.Spec.SecurityHeaders); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	if r.Spec.CORS != nil {
+		if err := ValidateCORSPolicy(r.Spec.CORS); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	if err := ValidateIngressAnnotations(r.Spec.IngressAnnotations); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	if err := ValidateProxySettings(r.Spec.MaxBodySize, r.Spec.ProxyReadTimeout, r.Spec.ProxySendTimeout); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	if ref := r.Spec.CertificateSecretRef; ref != nil {
+		if r.Spec.Type != ApplicationDomainTypeCustom {
+			errors = append(errors, "only custom domains can bring their own certificate")
+		}
+		if ref.Name == "" {
+			errors = append(errors, "certificateSecretRef must name a Secret")
+		}
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("validation failed: %v", errors)
 	}
@@ -253,10 +894,140 @@ func (r *ApplicationDomain) validateApplicationDomain(ctx context.Context) error
 	return nil
 }
 
-// SetupWebhookWithManager will setup the manager to manage the webhooks
+// applicationDomainValidator adds the cluster-wide hostname uniqueness check, which needs a
+// client, to the validation of ApplicationDomain
+type applicationDomainValidator struct {
+	*ApplicationDomain
+	client client.Reader
+	claims client.Reader
+}
+
+var _ webhook.CustomValidator = &applicationDomainValidator{}
+
+// ValidateCreate implements webhook.CustomValidator
+func (v *applicationDomainValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	warnings, err := v.ApplicationDomain.ValidateCreate(ctx, obj)
+	if err != nil {
+		return warnings, err
+	}
+	domain := obj.(*ApplicationDomain)
+	if err := v.validateBackendProtocol(ctx, domain); err != nil {
+		return warnings, err
+	}
+	if domain.AllowsDomainConflict() {
+		if err := AuthorizeDomainConflict(ctx); err != nil {
+			return warnings, err
+		}
+	}
+	return v.validateHostname(ctx, domain)
+}
+
+// ValidateUpdate implements webhook.CustomValidator. The hostname is checked again when it
+// changes or the conflict override is removed, so domains admitted before keep updating.
+func (v *applicationDomainValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	warnings, err := v.ApplicationDomain.ValidateUpdate(ctx, oldObj, newObj)
+	if err != nil {
+		return warnings, err
+	}
+
+	oldDomain, ok := oldObj.(*ApplicationDomain)
+	if !ok {
+		return nil, fmt.Errorf("expected an ApplicationDomain object, but got %T", oldObj)
+	}
+	domain := newObj.(*ApplicationDomain)
+	if oldDomain.Spec.BackendProtocol != domain.Spec.BackendProtocol {
+		if err := v.validateBackendProtocol(ctx, domain); err != nil {
+			return nil, err
+		}
+	}
+	if oldDomain.Spec.Domain == domain.Spec.Domain && oldDomain.AllowsDomainConflict() == domain.AllowsDomainConflict() {
+		return nil, nil
+	}
+	// The override only covers the hostname an admin allowed it for
+	if domain.AllowsDomainConflict() {
+		if err := AuthorizeDomainConflict(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return v.validateHostname(ctx, domain)
+}
+
+// AuthorizeDomainConflict rejects requests setting the conflict override, or changing the
+// hostname of a domain carrying it, unless they come from a cluster or domain admin
+func AuthorizeDomainConflict(ctx context.Context) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("%s can only be set by an admin: %w", validation.AnnotationAllowDomainConflict, err)
+	}
+	for _, group := range req.UserInfo.Groups {
+		if group == validation.GroupDomainAdmins || group == "system:masters" {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s can only be set by members of the %s group, not by %s",
+		validation.AnnotationAllowDomainConflict, validation.GroupDomainAdmins, req.UserInfo.Username)
+}
+
+// validateBackendProtocol rejects a backend protocol other than the one of the other domains of
+// the application, the providers reading it from the application Service can only serve one
+func (v *applicationDomainValidator) validateBackendProtocol(ctx context.Context, domain *ApplicationDomain) error {
+	if domain.Spec.BackendProtocol == "" {
+		return nil
+	}
+	other, err := FindBackendProtocol(ctx, v.client, domain.Namespace, domain.Spec.ApplicationRef.Name, domain.Name)
+	if err != nil {
+		return err
+	}
+	if other != "" && other != domain.Spec.BackendProtocol {
+		return fmt.Errorf("backendProtocol %s differs from %s set on the other domains of application %s",
+			domain.Spec.BackendProtocol, other, domain.Spec.ApplicationRef.Name)
+	}
+	return nil
+}
+
+// FindBackendProtocol returns the backend protocol set on the domains of an application, except
+// the domain named exclude, or an empty string when none sets one
+func FindBackendProtocol(ctx context.Context, c client.Reader, namespace, applicationName, exclude string) (BackendProtocol, error) {
+	var domains ApplicationDomainList
+	if err := c.List(ctx, &domains, client.InNamespace(namespace)); err != nil {
+		return "", fmt.Errorf("failed to list the domains of application %s: %w", applicationName, err)
+	}
+	sort.Slice(domains.Items, func(i, j int) bool { return domains.Items[i].Name < domains.Items[j].Name })
+	for _, other := range domains.Items {
+		if other.Name == exclude || other.Spec.ApplicationRef.Name != applicationName || other.DeletionTimestamp != nil {
+			continue
+		}
+		if other.Spec.BackendProtocol != "" {
+			return other.Spec.BackendProtocol, nil
+		}
+	}
+	return "", nil
+}
+
+// validateHostname rejects a domain whose hostname another ApplicationDomain already claims,
+// unless an admin allowed the conflict
+func (v *applicationDomainValidator) validateHostname(ctx context.Context, domain *ApplicationDomain) (admission.Warnings, error) {
+	conflict, err := FindDomainConflict(ctx, v.claims, domain)
+	if err != nil {
+		return nil, err
+	}
+	if conflict == nil {
+		return nil, nil
+	}
+
+	if domain.AllowsDomainConflict() {
+		return admission.Warnings{fmt.Sprintf("domain %s is also claimed by ApplicationDomain %s/%s",
+			domain.Spec.Domain, conflict.Namespace, conflict.Name)}, nil
+	}
+	return nil, fmt.Errorf("domain %s is already claimed by ApplicationDomain %s/%s",
+		domain.Spec.Domain, conflict.Namespace, conflict.Name)
+}
+
+// SetupWebhookWithManager will setup the manager to manage the webhooks. Hostname claims are read
+// from the API server, a cache could miss a claim made moments before.
 func (r *ApplicationDomain) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
-		WithValidator(r).
+		WithValidator(&applicationDomainValidator{ApplicationDomain: r, client: mgr.GetClient(), claims: mgr.GetAPIReader()}).
 		Complete()
 }
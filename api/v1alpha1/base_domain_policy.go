@@ -0,0 +1,42 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sync/atomic"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// baseDomainPolicy is the operator base domain policy Projects and Applications are validated against
+var baseDomainPolicy atomic.Pointer[config.BaseDomainPolicy]
+
+// SetBaseDomainPolicy replaces the base domain policy enforced by the Project and Application
+// webhooks and the controllers provisioning custom base domains. It is called at startup and
+// whenever the operator ConfigMap changes.
+func SetBaseDomainPolicy(cfg config.BaseDomainPolicy) {
+	baseDomainPolicy.Store(&cfg)
+}
+
+// CurrentBaseDomainPolicy returns the base domain policy, which allows no custom base domain
+// until the operator configuration is loaded
+func CurrentBaseDomainPolicy() config.BaseDomainPolicy {
+	if current := baseDomainPolicy.Load(); current != nil {
+		return *current
+	}
+	return config.BaseDomainPolicy{}
+}
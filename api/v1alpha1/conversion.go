@@ -0,0 +1,32 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// v1alpha1 is the storage version and the conversion hub of the platform resources served in
+// v1beta1 too, see the conversions of the v1beta1 package
+
+// Hub marks this type as a conversion hub.
+func (*Project) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*Application) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*Deployment) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*ApplicationDomain) Hub() {}
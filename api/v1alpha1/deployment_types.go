@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"regexp"
 
 	corev1 "k8s.io/api/core/v1"
@@ -76,6 +77,39 @@ type ImageFromRegistryDeploymentConfig struct {
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
+// PromotionSource records the deployment of another environment a promoted deployment was created from
+type PromotionSource struct {
+	// DeploymentUUID is the UUID of the source deployment
+	// +kubebuilder:validation:Required
+	DeploymentUUID string `json:"deploymentUUID"`
+
+	// EnvironmentUUID is the UUID of the environment of the source deployment
+	// +kubebuilder:validation:Required
+	EnvironmentUUID string `json:"environmentUUID"`
+
+	// Image is the image built for the source deployment, pinned to its digest when known.
+	// The promoted deployment runs this image instead of building the commit again.
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+}
+
+// ExternalBuildSource records an image built outside the platform, such as by a Tekton
+// EventListener or a GitHub Actions workflow, that a deployment rolls out
+type ExternalBuildSource struct {
+	// Image is the built image pinned to its digest
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[^@\s]+@sha256:[a-f0-9]{64}$`
+	Image string `json:"image"`
+
+	// Provider names the system that built the image, e.g. github-actions
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// RunURL links to the build run
+	// +optional
+	RunURL string `json:"runURL,omitempty"`
+}
+
 // DeploymentSpec defines the desired state of Deployment.
 type DeploymentSpec struct {
 	// ApplicationRef references the Application this deployment belongs to
@@ -88,6 +122,10 @@ type DeploymentSpec struct {
 	// +optional
 	Promote bool `json:"promote,omitempty"`
 
+	// OverrideFreeze promotes this deployment even while an environment freeze window is active
+	// +optional
+	OverrideFreeze bool `json:"overrideFreeze,omitempty"`
+
 	// GitRepository contains configuration for GitRepository deployments
 	// Required when ApplicationRef points to a GitRepository application
 	// +optional
@@ -97,6 +135,16 @@ type DeploymentSpec struct {
 	// Required when ApplicationRef points to an ImageFromRegistry application
 	// +optional
 	ImageFromRegistry *ImageFromRegistryDeploymentConfig `json:"imageFromRegistry,omitempty"`
+
+	// PromotedFrom is set on deployments promoted from another environment. Their pipeline is
+	// skipped and the image of the source deployment is rolled out.
+	// +optional
+	PromotedFrom *PromotionSource `json:"promotedFrom,omitempty"`
+
+	// ExternalBuild is set on deployments of images built outside the platform. Their pipeline
+	// is skipped and the external image is rolled out.
+	// +optional
+	ExternalBuild *ExternalBuildSource `json:"externalBuild,omitempty"`
 }
 
 // DeploymentStatus defines the observed state of Deployment.
@@ -112,10 +160,84 @@ type DeploymentStatus struct {
 	// ObservedGeneration reflects the generation of the most recently observed Deployment
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ImageDigest is the digest of the image pushed by the build pipeline
+	// +optional
+	ImageDigest string `json:"imageDigest,omitempty"`
+
+	// DetectedPort is the port the built image exposes, as detected by the build pipeline from an
+	// EXPOSE instruction or the build plan. Applications without a port listen on it.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	DetectedPort int32 `json:"detectedPort,omitempty"`
+
+	// QueuePosition is the position of the build in the build queue of the project, starting at 1.
+	// It is set while the build waits for the project build concurrency limit.
+	// +optional
+	QueuePosition int32 `json:"queuePosition,omitempty"`
+
+	// EstimatedStart is when the queued build is expected to start, derived from the durations of
+	// recent builds of the project
+	// +optional
+	EstimatedStart *metav1.Time `json:"estimatedStart,omitempty"`
+
+	// Hooks reports the promotion hooks run for the deployment
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Hooks []PromotionHookStatus `json:"hooks,omitempty"`
+}
+
+// PromotionHookType names a promotion hook of an application
+type PromotionHookType string
+
+const (
+	PromotionHookPrePromote  PromotionHookType = "PrePromote"
+	PromotionHookPostPromote PromotionHookType = "PostPromote"
+)
+
+// PromotionHookPhase is the state of the Job of a promotion hook
+type PromotionHookPhase string
+
+const (
+	PromotionHookPhaseRunning   PromotionHookPhase = "Running"
+	PromotionHookPhaseSucceeded PromotionHookPhase = "Succeeded"
+	PromotionHookPhaseFailed    PromotionHookPhase = "Failed"
+)
+
+// PromotionHookStatus reports a promotion hook run for a deployment
+type PromotionHookStatus struct {
+	// Type of the hook
+	Type PromotionHookType `json:"type"`
+
+	// Phase of the Job of the hook
+	Phase PromotionHookPhase `json:"phase"`
+
+	// JobName is the Job running the hook, its pod holds the hook logs
+	JobName string `json:"jobName"`
+
+	// Message explains a failed hook
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is when the Job of the hook was created
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the hook succeeded or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// PreviousDeployment is the deployment the application served before the promotion, a
+	// failed post-promote hook with the Abort policy reverts to it
+	// +optional
+	PreviousDeployment string `json:"previousDeployment,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Application",type="string",JSONPath=".spec.applicationRef.name"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
@@ -170,6 +292,12 @@ func (r *Deployment) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.
 
 	deploymentlog.Info("validate update", "name", dep.Name)
 
+	if old, ok := oldObj.(*Deployment); ok {
+		if err := dep.validateImmutableFields(old); err != nil {
+			return nil, err
+		}
+	}
+
 	return nil, dep.validateDeployment(ctx)
 }
 
@@ -258,6 +386,21 @@ func (r *Deployment) validateDeployment(ctx context.Context) error {
 	return nil
 }
 
+// validateImmutableFields rejects changes to what a deployment runs. The built image is pinned to
+// the deployment, so retargeting an existing deployment would silently change the running workload.
+func (r *Deployment) validateImmutableFields(old *Deployment) error {
+	if !reflect.DeepEqual(r.Spec.PromotedFrom, old.Spec.PromotedFrom) {
+		return fmt.Errorf("spec.promotedFrom is immutable")
+	}
+	if !reflect.DeepEqual(r.Spec.ExternalBuild, old.Spec.ExternalBuild) {
+		return fmt.Errorf("spec.externalBuild is immutable")
+	}
+	if !reflect.DeepEqual(r.Spec.GitRepository, old.Spec.GitRepository) {
+		return fmt.Errorf("spec.gitRepository is immutable, create a new deployment to deploy another commit")
+	}
+	return nil
+}
+
 // validateImageFromRegistryDeployment validates ImageFromRegistry deployment configuration
 func (r *Deployment) validateImageFromRegistryDeployment() error {
 	config := r.Spec.ImageFromRegistry
@@ -330,6 +473,18 @@ func (r *Deployment) GetEnvironmentUUID() string {
 	return r.Labels[validation.LabelEnvironmentUUID]
 }
 
+// PrebuiltImage returns the image a deployment rolls out instead of building its commit, the
+// image of the source deployment or of the external build, empty when the deployment is built
+func (r *Deployment) PrebuiltImage() string {
+	if r.Spec.PromotedFrom != nil {
+		return r.Spec.PromotedFrom.Image
+	}
+	if r.Spec.ExternalBuild != nil {
+		return r.Spec.ExternalBuild.Image
+	}
+	return ""
+}
+
 // SetupWebhookWithManager will setup the manager to manage the webhooks
 func (r *Deployment) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
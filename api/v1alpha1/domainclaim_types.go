@@ -0,0 +1,182 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LabelDomainClaimUID labels a DomainClaim with the UID of the ApplicationDomain holding it
+const LabelDomainClaimUID = "platform.kibaship.com/domain-uid"
+
+// DomainClaimSpec records the ApplicationDomain a hostname belongs to
+type DomainClaimSpec struct {
+	// DomainRef references the ApplicationDomain holding the hostname
+	// +kubebuilder:validation:Required
+	DomainRef DomainClaimReference `json:"domainRef"`
+}
+
+// DomainClaimReference references an ApplicationDomain across namespaces
+type DomainClaimReference struct {
+	// Namespace of the ApplicationDomain
+	Namespace string `json:"namespace"`
+
+	// Name of the ApplicationDomain
+	Name string `json:"name"`
+
+	// UID of the ApplicationDomain, a domain recreated under the same name claims anew
+	UID types.UID `json:"uid"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Namespace",type=string,JSONPath=".spec.domainRef.namespace"
+// +kubebuilder:printcolumn:name="Domain",type=string,JSONPath=".spec.domainRef.name"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// DomainClaim reserves a hostname for one ApplicationDomain. It is named after the hostname, so
+// creating it is atomic across the cluster: of two domains claiming a hostname at the same time
+// only one creates the claim. The operator manages DomainClaims, they are not meant to be edited.
+type DomainClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DomainClaimSpec `json:"spec,omitempty"`
+}
+
+// DomainClaimList contains a list of DomainClaim
+// +kubebuilder:object:root=true
+type DomainClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DomainClaim `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DomainClaim{}, &DomainClaimList{})
+}
+
+// heldBy reports whether the claim belongs to domain
+func (c *DomainClaim) heldBy(domain *ApplicationDomain) bool {
+	return c.Spec.DomainRef.UID == domain.UID
+}
+
+// claimHolder returns the ApplicationDomain holding claim, nil when it no longer exists and the
+// claim is stale
+func claimHolder(ctx context.Context, c client.Reader, claim *DomainClaim) (*ApplicationDomain, error) {
+	ref := claim.Spec.DomainRef
+	holder := &ApplicationDomain{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, holder); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get ApplicationDomain %s/%s claiming %s: %w", ref.Namespace, ref.Name, claim.Name, err)
+	}
+	if holder.UID != ref.UID || holder.Spec.Domain != claim.Name {
+		return nil, nil
+	}
+	return holder, nil
+}
+
+// FindDomainConflict returns the other ApplicationDomain holding the claim of the hostname of
+// domain, nil when there is none. c should read from the API server rather than a cache.
+func FindDomainConflict(ctx context.Context, c client.Reader, domain *ApplicationDomain) (*ApplicationDomain, error) {
+	claim := &DomainClaim{}
+	if err := c.Get(ctx, client.ObjectKey{Name: domain.Spec.Domain}, claim); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the claim of %s: %w", domain.Spec.Domain, err)
+	}
+	if claim.heldBy(domain) {
+		return nil, nil
+	}
+	return claimHolder(ctx, c, claim)
+}
+
+// ClaimDomain claims the hostname of domain. It returns the other ApplicationDomain holding the
+// hostname when the claim is taken, nil once domain holds it. Claims of deleted domains are
+// taken over.
+func ClaimDomain(ctx context.Context, c client.Client, domain *ApplicationDomain) (*ApplicationDomain, error) {
+	claim := &DomainClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: domain.Spec.Domain,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "kibaship",
+				LabelDomainClaimUID:            string(domain.UID),
+			},
+		},
+		Spec: DomainClaimSpec{DomainRef: DomainClaimReference{
+			Namespace: domain.Namespace,
+			Name:      domain.Name,
+			UID:       domain.UID,
+		}},
+	}
+	err := c.Create(ctx, claim)
+	if err == nil {
+		return nil, nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("failed to claim %s: %w", domain.Spec.Domain, err)
+	}
+
+	existing := &DomainClaim{}
+	if err := c.Get(ctx, client.ObjectKey{Name: domain.Spec.Domain}, existing); err != nil {
+		return nil, fmt.Errorf("failed to get the claim of %s: %w", domain.Spec.Domain, err)
+	}
+	if existing.heldBy(domain) {
+		return nil, nil
+	}
+	holder, err := claimHolder(ctx, c, existing)
+	if err != nil || holder != nil {
+		return holder, err
+	}
+
+	// The holder is gone, the stale claim is replaced unless another domain replaced it first
+	if err := c.Delete(ctx, existing, client.Preconditions{UID: &existing.UID, ResourceVersion: &existing.ResourceVersion}); err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		return nil, fmt.Errorf("failed to delete the stale claim of %s: %w", domain.Spec.Domain, err)
+	}
+	if err := c.Create(ctx, claim); err != nil {
+		return nil, fmt.Errorf("failed to claim %s: %w", domain.Spec.Domain, err)
+	}
+	return nil, nil
+}
+
+// ReleaseDomainClaims deletes the claims domain holds, except the one of hostname keep. Claims
+// are released when a domain is deleted or its hostname changes.
+func ReleaseDomainClaims(ctx context.Context, c client.Client, domain *ApplicationDomain, keep string) error {
+	var claims DomainClaimList
+	if err := c.List(ctx, &claims, client.MatchingLabels{LabelDomainClaimUID: string(domain.UID)}); err != nil {
+		return fmt.Errorf("failed to list the claims of ApplicationDomain %s/%s: %w", domain.Namespace, domain.Name, err)
+	}
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		if claim.Name == keep || !claim.heldBy(domain) {
+			continue
+		}
+		if err := c.Delete(ctx, claim, client.Preconditions{UID: &claim.UID}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to release the claim of %s: %w", claim.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kinds of the references of templated env values, written ${kind:name}
+const (
+	// EnvReferenceEnv is another variable of the same application, ${env:DATABASE_HOST}
+	EnvReferenceEnv = "env"
+	// EnvReferenceApp is a value of another application of the environment, addressed by its slug,
+	// ${app:backend.url}
+	EnvReferenceApp = "app"
+	// EnvReferenceDomain is a domain of the application itself, ${domain:primary}
+	EnvReferenceDomain = "domain"
+)
+
+// Fields of the applications referenced by ${app:<slug>.<field>}
+const (
+	// EnvAppFieldURL is the public URL of the default domain of the application
+	EnvAppFieldURL = "url"
+	// EnvAppFieldHost is the in-cluster host of the application
+	EnvAppFieldHost = "host"
+	// EnvAppFieldPort is the port the application listens on
+	EnvAppFieldPort = "port"
+)
+
+// EnvDomainPrimary is the default domain of the application, referenced by ${domain:primary}
+const EnvDomainPrimary = "primary"
+
+var (
+	// envTemplateStartPattern matches the start of a reference. Other ${ are kept as written, so
+	// shell style values such as ${HOME} or ${PORT:-3000} pass through.
+	envTemplateStartPattern = regexp.MustCompile(`^\$\{[a-z]+:`)
+	envTemplateNamePattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	envTemplateSlugPattern  = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+)
+
+// EnvReference is a ${kind:name} reference of a templated env value. Field is only set for
+// application references, ${app:backend.url} has the name backend and the field url.
+type EnvReference struct {
+	Kind  string
+	Name  string
+	Field string
+}
+
+// String returns the reference as written in env values
+func (r EnvReference) String() string {
+	if r.Field != "" {
+		return fmt.Sprintf("${%s:%s.%s}", r.Kind, r.Name, r.Field)
+	}
+	return fmt.Sprintf("${%s:%s}", r.Kind, r.Name)
+}
+
+// HasEnvTemplate reports whether an env value may hold a reference, or an escaped $${
+func HasEnvTemplate(value string) bool {
+	return strings.Contains(value, "${")
+}
+
+// ParseEnvTemplate returns the references of an env value, written ${kind:name} with a lower
+// case kind. $${ is a literal ${.
+func ParseEnvTemplate(value string) ([]EnvReference, error) {
+	var references []EnvReference
+	_, err := expandEnvTemplate(value, func(reference EnvReference) (string, error) {
+		references = append(references, reference)
+		return "", nil
+	})
+	return references, err
+}
+
+// ValidateEnvTemplates checks the references of the templated values of env: they must be well
+// formed, reference variables of env and must not reference each other in a cycle
+func ValidateEnvTemplates(env map[string]string) error {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		references, err := ParseEnvTemplate(env[name])
+		if err != nil {
+			return fmt.Errorf("env %s: %w", name, err)
+		}
+		for _, reference := range references {
+			if reference.Kind != EnvReferenceEnv {
+				continue
+			}
+			if _, ok := env[reference.Name]; !ok {
+				return fmt.Errorf("env %s: %s references a variable that is not set", name, reference)
+			}
+		}
+	}
+
+	_, err := ResolveEnvTemplates(env, func(EnvReference) (string, error) { return "", nil })
+	return err
+}
+
+// ResolveEnvTemplates resolves the templated values of env, returning only the variables whose
+// value held a reference. References to other variables of env are resolved from env, others
+// by resolve. Variables referencing each other in a cycle are an error.
+func ResolveEnvTemplates(env map[string]string, resolve func(EnvReference) (string, error)) (map[string]string, error) {
+	resolver := &envTemplateResolver{
+		env:      env,
+		resolve:  resolve,
+		resolved: map[string]string{},
+		visiting: map[string]bool{},
+	}
+
+	names := make([]string, 0, len(env))
+	for name, value := range env {
+		if HasEnvTemplate(value) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	templated := make(map[string]string, len(names))
+	for _, name := range names {
+		value, err := resolver.value(name, nil)
+		if err != nil {
+			return nil, err
+		}
+		templated[name] = value
+	}
+	return templated, nil
+}
+
+type envTemplateResolver struct {
+	env      map[string]string
+	resolve  func(EnvReference) (string, error)
+	resolved map[string]string
+	visiting map[string]bool
+}
+
+// value resolves a variable, path is the chain of variables referencing it
+func (r *envTemplateResolver) value(name string, path []string) (string, error) {
+	if value, ok := r.resolved[name]; ok {
+		return value, nil
+	}
+	path = append(path, name)
+	if r.visiting[name] {
+		return "", fmt.Errorf("env %s references itself through %s", path[0], strings.Join(path, " -> "))
+	}
+	raw, ok := r.env[name]
+	if !ok {
+		return "", fmt.Errorf("env %s references %s, which is not set", path[0], name)
+	}
+
+	r.visiting[name] = true
+	value, err := expandEnvTemplate(raw, func(reference EnvReference) (string, error) {
+		if reference.Kind == EnvReferenceEnv {
+			return r.value(reference.Name, path)
+		}
+		resolved, err := r.resolve(reference)
+		if err != nil {
+			return "", fmt.Errorf("env %s: %w", name, err)
+		}
+		return resolved, nil
+	})
+	delete(r.visiting, name)
+	if err != nil {
+		return "", err
+	}
+	r.resolved[name] = value
+	return value, nil
+}
+
+// expandEnvTemplate replaces the references of value with what fn returns for them
+func expandEnvTemplate(value string, fn func(EnvReference) (string, error)) (string, error) {
+	var out strings.Builder
+	for i := 0; i < len(value); i++ {
+		if strings.HasPrefix(value[i:], "$${") {
+			out.WriteString("${")
+			i += 2
+			continue
+		}
+		if !envTemplateStartPattern.MatchString(value[i:]) {
+			out.WriteByte(value[i])
+			continue
+		}
+
+		end := strings.IndexByte(value[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("reference at offset %d is missing its closing }, write $${ for a literal ${", i)
+		}
+		reference, err := parseEnvReference(value[i+2 : i+end])
+		if err != nil {
+			return "", err
+		}
+		resolved, err := fn(reference)
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(resolved)
+		i += end
+	}
+	return out.String(), nil
+}
+
+// parseEnvReference parses the kind:name between ${ and }
+func parseEnvReference(text string) (EnvReference, error) {
+	kind, name, _ := strings.Cut(text, ":")
+	switch kind {
+	case EnvReferenceEnv:
+		if !envTemplateNamePattern.MatchString(name) {
+			return EnvReference{}, fmt.Errorf("reference ${%s} must name an env variable", text)
+		}
+		return EnvReference{Kind: kind, Name: name}, nil
+	case EnvReferenceApp:
+		slug, field, _ := strings.Cut(name, ".")
+		if !envTemplateSlugPattern.MatchString(slug) {
+			return EnvReference{}, fmt.Errorf("reference ${%s} must name an application slug", text)
+		}
+		switch field {
+		case EnvAppFieldURL, EnvAppFieldHost, EnvAppFieldPort:
+			return EnvReference{Kind: kind, Name: slug, Field: field}, nil
+		}
+		return EnvReference{}, fmt.Errorf("reference ${%s} must end with .%s, .%s or .%s",
+			text, EnvAppFieldURL, EnvAppFieldHost, EnvAppFieldPort)
+	case EnvReferenceDomain:
+		if name != EnvDomainPrimary {
+			return EnvReference{}, fmt.Errorf("reference ${%s} is not supported, use ${%s:%s}", text, EnvReferenceDomain, EnvDomainPrimary)
+		}
+		return EnvReference{Kind: kind, Name: name}, nil
+	}
+	return EnvReference{}, fmt.Errorf("reference ${%s} has unknown kind %q, use %s, %s or %s",
+		text, kind, EnvReferenceEnv, EnvReferenceApp, EnvReferenceDomain)
+}
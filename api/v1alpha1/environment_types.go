@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,9 +31,68 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/kibamail/kibaship/pkg/freeze"
 	"github.com/kibamail/kibaship/pkg/validation"
 )
 
+// FreezeWindow is a recurring period during which deployment promotions are blocked
+type FreezeWindow struct {
+	// Name identifies the window, e.g. weekends
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Schedule is a five field cron expression for when the window starts, e.g. "0 18 * * FRI"
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window lasts after each start, e.g. "62h"
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+
+	// Timezone is the IANA timezone the schedule is evaluated in (defaults to UTC)
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+
+	// Reason is shown when a promotion is blocked by this window
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+const (
+	// DefaultIdleAfter is how long applications may go without requests when the idle policy sets no timeout
+	DefaultIdleAfter = 30 * time.Minute
+
+	// MinIdleAfter is the shortest supported idle timeout
+	MinIdleAfter = time.Minute
+)
+
+// productionEnvironmentNames are environment names treated as production, where idle policies are not allowed
+var productionEnvironmentNames = map[string]bool{
+	"production": true,
+	"prod":       true,
+}
+
+// IdlePolicy scales the applications of an environment to zero after a period without ingress traffic.
+// Requests to a sleeping application are held by the activator until the application is running again.
+type IdlePolicy struct {
+	// Enabled turns scale-to-zero on for the environment's applications
+	Enabled bool `json:"enabled"`
+
+	// IdleAfter is how long an application may go without requests before it is scaled to zero (defaults to 30m)
+	// +optional
+	IdleAfter *metav1.Duration `json:"idleAfter,omitempty"`
+}
+
+// Timeout returns the idle timeout of the policy
+func (p *IdlePolicy) Timeout() time.Duration {
+	if p.IdleAfter == nil || p.IdleAfter.Duration == 0 {
+		return DefaultIdleAfter
+	}
+	return p.IdleAfter.Duration
+}
+
 // EnvironmentSpec defines the desired state of Environment
 type EnvironmentSpec struct {
 	// ProjectRef references the Project this environment belongs to
@@ -41,6 +102,35 @@ type EnvironmentSpec struct {
 	// Description of the environment (optional)
 	// +optional
 	Description string `json:"description,omitempty"`
+
+	// FreezeWindows block deployment promotions during recurring periods.
+	// Deployments with overrideFreeze set are promoted regardless.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	FreezeWindows []FreezeWindow `json:"freezeWindows,omitempty"`
+
+	// IdlePolicy scales idle applications to zero. Intended for preview and staging environments,
+	// the first request after a sleep waits for the application to start. It cannot be enabled on
+	// production environments (named production or prod).
+	// +optional
+	IdlePolicy *IdlePolicy `json:"idlePolicy,omitempty"`
+
+	// RequireApproval stops successful deployments from going live automatically. They are
+	// released when promoted explicitly through the API.
+	// +optional
+	RequireApproval bool `json:"requireApproval,omitempty"`
+}
+
+// IsProduction reports whether the environment serves production traffic
+func (r *Environment) IsProduction() bool {
+	return productionEnvironmentNames[strings.ToLower(r.Annotations[validation.AnnotationResourceName])]
+}
+
+// IdleEnabled reports whether the environment scales idle applications to zero.
+// Production environments never do.
+func (r *Environment) IdleEnabled() bool {
+	return r.Spec.IdlePolicy != nil && r.Spec.IdlePolicy.Enabled && !r.IsProduction()
 }
 
 // EnvironmentStatus defines the observed state of Environment
@@ -182,6 +272,23 @@ func (r *Environment) validateEnvironment(ctx context.Context) error {
 		errors = append(errors, fmt.Sprintf("environment name '%s' must follow format 'environment-<uuid>'", r.Name))
 	}
 
+	// Validate freeze windows
+	for _, window := range r.Spec.FreezeWindows {
+		if err := ValidateFreezeWindow(window); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	// Validate idle policy
+	if policy := r.Spec.IdlePolicy; policy != nil {
+		if policy.IdleAfter != nil && policy.IdleAfter.Duration < MinIdleAfter {
+			errors = append(errors, fmt.Sprintf("idlePolicy.idleAfter must be at least %s", MinIdleAfter))
+		}
+		if policy.Enabled && r.IsProduction() {
+			errors = append(errors, "idlePolicy cannot be enabled on production environments")
+		}
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("validation failed: %v", errors)
 	}
@@ -221,6 +328,48 @@ func (r *Environment) GetUUID() string {
 	return r.Labels[validation.LabelResourceUUID]
 }
 
+// ValidateFreezeWindow validates the schedule, duration and timezone of a freeze window
+func ValidateFreezeWindow(window FreezeWindow) error {
+	if _, err := freeze.ParseSchedule(window.Schedule); err != nil {
+		return fmt.Errorf("freeze window %s: %w", window.Name, err)
+	}
+	if err := freeze.ValidateDuration(window.Duration.Duration); err != nil {
+		return fmt.Errorf("freeze window %s: %w", window.Name, err)
+	}
+	if _, err := freeze.LoadLocation(window.Timezone); err != nil {
+		return fmt.Errorf("freeze window %s: %w", window.Name, err)
+	}
+	return nil
+}
+
+// ActiveFreezeWindow returns the freeze window covering now and when it ends, or nil when
+// promotions are allowed. Invalid windows are skipped, they are rejected by the webhook.
+func (r *Environment) ActiveFreezeWindow(now time.Time) (*FreezeWindow, time.Time) {
+	var active *FreezeWindow
+	var activeUntil time.Time
+	for i := range r.Spec.FreezeWindows {
+		window := &r.Spec.FreezeWindows[i]
+		ok, end, err := freeze.Active(window.Schedule, window.Duration.Duration, window.Timezone, now)
+		if err != nil || !ok {
+			continue
+		}
+		// Report the window that blocks promotions the longest
+		if active == nil || end.After(activeUntil) {
+			active, activeUntil = window, end
+		}
+	}
+	return active, activeUntil
+}
+
+// BlockedMessage describes a promotion blocked by the window until the given time
+func (w *FreezeWindow) BlockedMessage(until time.Time) string {
+	message := fmt.Sprintf("promotion blocked by freeze window %q until %s", w.Name, until.UTC().Format(time.RFC3339))
+	if w.Reason != "" {
+		message += ": " + w.Reason
+	}
+	return message + "; set overrideFreeze to promote anyway"
+}
+
 // SetupWebhookWithManager will setup the manager to manage the webhooks
 func (r *Environment) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// imagePolicy is the operator image policy Applications are validated against
+var imagePolicy atomic.Pointer[config.ImagePolicyConfig]
+
+// SetImagePolicy replaces the image policy enforced by the Application webhook and Dockerfile
+// builds. It is called at startup and whenever the operator ConfigMap changes.
+func SetImagePolicy(cfg config.ImagePolicyConfig) {
+	imagePolicy.Store(&cfg)
+}
+
+// CurrentImagePolicy returns the image policy, empty until the operator configuration is loaded
+func CurrentImagePolicy() config.ImagePolicyConfig {
+	if current := imagePolicy.Load(); current != nil {
+		return *current
+	}
+	return config.ImagePolicyConfig{}
+}
+
+// RunImage returns the image an application runs as is, empty for applications whose image
+// is built by the platform or managed by a database operator
+func (r *Application) RunImage() string {
+	switch r.Spec.Type {
+	case ApplicationTypeDockerImage:
+		if r.Spec.DockerImage != nil {
+			return r.Spec.DockerImage.Image
+		}
+	case ApplicationTypeImageFromRegistry:
+		if r.Spec.ImageFromRegistry != nil {
+			return fmt.Sprintf("%s/%s", r.Spec.ImageFromRegistry.RegistryHost(), r.Spec.ImageFromRegistry.Repository)
+		}
+	}
+	return ""
+}
+
+// RegistryHost returns the host of the registry the image is pulled from
+func (c *ImageFromRegistryConfig) RegistryHost() string {
+	if c.Registry == RegistryTypeGHCR {
+		return "ghcr.io"
+	}
+	return "docker.io"
+}
+
+// Image returns the image a deployment of the application runs: the tag of the deployment, else
+// the default tag of the application, else latest. deployment may be nil.
+func (c *ImageFromRegistryConfig) Image(deployment *ImageFromRegistryDeploymentConfig) string {
+	tag := ""
+	if deployment != nil {
+		tag = deployment.Tag
+	}
+	if tag == "" {
+		tag = c.DefaultTag
+	}
+	if tag == "" {
+		tag = "latest"
+	}
+	return fmt.Sprintf("%s/%s:%s", c.RegistryHost(), c.Repository, tag)
+}
+
+// validateImagePolicy rejects applications running an image the operator image policy forbids
+func (r *Application) validateImagePolicy() error {
+	image := r.RunImage()
+	if image == "" {
+		return nil
+	}
+	return CurrentImagePolicy().Check(image)
+}
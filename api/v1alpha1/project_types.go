@@ -19,9 +19,14 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"net"
+	"sort"
+	"strings"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -118,6 +123,210 @@ type VolumeConfig struct {
 	MaxStorageSize string `json:"maxStorageSize,omitempty"`
 }
 
+// PriorityConfig selects the PriorityClasses scheduling the pods of a project.
+// Empty names use the kibaship-build and kibaship-runtime classes provisioned by the operator.
+type PriorityConfig struct {
+	// BuildPriorityClassName is the PriorityClass of the pipeline pods building the project applications
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	// +optional
+	BuildPriorityClassName string `json:"buildPriorityClassName,omitempty"`
+
+	// RuntimePriorityClassName is the PriorityClass of the pods running the project applications
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	// +optional
+	RuntimePriorityClassName string `json:"runtimePriorityClassName,omitempty"`
+}
+
+// reservedPriorityClassPrefixes are PriorityClasses reserved for platform and Kubernetes system pods
+var reservedPriorityClassPrefixes = []string{"system-", "kibaship-system"}
+
+// ValidatePriorityClassName rejects the PriorityClasses project pods must not use
+func ValidatePriorityClassName(name string) error {
+	for _, prefix := range reservedPriorityClassPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return fmt.Errorf("priority class %s is reserved for system pods", name)
+		}
+	}
+	return nil
+}
+
+// ErrorPagesConfig replaces the error responses of the ingress layer for the routes of a project.
+// Either Image or the NotFound and ServerError pages are set, an empty config keeps the ingress
+// controller responses. Backend 5xx responses are replaced on the nginx and Traefik ingress providers,
+// Gateway API HTTPRoutes cannot intercept them. With a project base domain, hosts under
+// *.apps.<baseDomain> matching no application are answered with the not found page.
+type ErrorPagesConfig struct {
+	// Image serves the error pages over HTTP on port 8080. It receives the upstream status code
+	// in the X-Code header from ingress-nginx or as the /<status> path from Traefik, must respond
+	// with that status code and with 404 to requests carrying neither.
+	// +kubebuilder:validation:MaxLength=512
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// NotFound is the HTML document served for 404 responses
+	// +kubebuilder:validation:MaxLength=262144
+	// +optional
+	NotFound string `json:"notFound,omitempty"`
+
+	// ServerError is the HTML document served for 500, 502, 503 and 504 responses
+	// +kubebuilder:validation:MaxLength=262144
+	// +optional
+	ServerError string `json:"serverError,omitempty"`
+}
+
+// Enabled reports whether the project serves custom error pages
+func (c ErrorPagesConfig) Enabled() bool {
+	return c.Image != "" || c.NotFound != "" || c.ServerError != ""
+}
+
+// ValidateErrorPages rejects error pages configuring both an image and HTML pages
+func ValidateErrorPages(c ErrorPagesConfig) error {
+	if c.Image != "" && (c.NotFound != "" || c.ServerError != "") {
+		return fmt.Errorf("error pages are served either by an image or from notFound and serverError, not both")
+	}
+	if strings.ContainsAny(c.Image, " \t\n") {
+		return fmt.Errorf("error pages image %q must not contain whitespace", c.Image)
+	}
+	return nil
+}
+
+// EgressConfig restricts the outbound traffic of the project applications. Restricted
+// applications reach the cluster, cluster DNS and the listed CIDRs and domains only. Rendered
+// as CiliumNetworkPolicies, Cilium must be the cluster CNI.
+type EgressConfig struct {
+	// Restricted denies outbound traffic to destinations outside the cluster not listed below
+	// +optional
+	Restricted bool `json:"restricted,omitempty"`
+
+	// AllowedCIDRs are the external IP ranges restricted applications may call
+	// +kubebuilder:validation:MaxItems=100
+	// +optional
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+
+	// AllowedDomains are the domains restricted applications may call. A leading *. matches
+	// every subdomain, resolved addresses are learned through the Cilium DNS proxy.
+	// +kubebuilder:validation:MaxItems=100
+	// +kubebuilder:validation:items:MaxLength=253
+	// +kubebuilder:validation:items:Pattern=`^(\*\.)?([a-z0-9]([-a-z0-9]*[a-z0-9])?\.)+[a-z]{2,}$`
+	// +optional
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
+}
+
+// ValidateEgress rejects allowlists on unrestricted egress and malformed CIDRs
+func ValidateEgress(c EgressConfig) error {
+	if !c.Restricted && (len(c.AllowedCIDRs) > 0 || len(c.AllowedDomains) > 0) {
+		return fmt.Errorf("egress allowlists only apply to restricted egress")
+	}
+	for _, cidr := range c.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("egress CIDR %q is invalid: %w", cidr, err)
+		}
+	}
+	return nil
+}
+
+// NamespaceConfig customizes the metadata of the project namespace, such as the
+// pod-security.kubernetes.io enforcement levels, istio-injection or a cost-center label
+type NamespaceConfig struct {
+	// Labels are added to the project namespace
+	// +kubebuilder:validation:MaxProperties=50
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to the project namespace
+	// +kubebuilder:validation:MaxProperties=50
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ProtectedNamespaceKeyPrefixes are the label and annotation key prefixes of project namespaces
+// reserved to the operator and to Kubernetes. Prefixes starting with a dot protect every
+// subdomain, pod-security.kubernetes.io and other Kubernetes subdomains remain open.
+var ProtectedNamespaceKeyPrefixes = []string{
+	"kibaship.com/",
+	".kibaship.com/",
+	"kubernetes.io/",
+	"k8s.io/",
+	"app.kubernetes.io/managed-by",
+}
+
+// IsProtectedNamespaceKey reports whether a namespace label or annotation key is reserved
+func IsProtectedNamespaceKey(key string) bool {
+	for _, prefix := range ProtectedNamespaceKeyPrefixes {
+		if strings.HasPrefix(key, prefix) || (strings.HasPrefix(prefix, ".") && strings.Contains(key, prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateNamespace rejects malformed and reserved namespace label and annotation keys, and
+// label values Kubernetes would refuse
+func ValidateNamespace(c NamespaceConfig) error {
+	for _, kind := range []struct {
+		name   string
+		values map[string]string
+	}{{"label", c.Labels}, {"annotation", c.Annotations}} {
+		keys := make([]string, 0, len(kind.values))
+		for key := range kind.values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if errs := utilvalidation.IsQualifiedName(key); len(errs) > 0 {
+				return fmt.Errorf("namespace %s key %q is invalid: %s", kind.name, key, strings.Join(errs, ", "))
+			}
+			if IsProtectedNamespaceKey(key) {
+				return fmt.Errorf("namespace %s key %q uses a reserved prefix", kind.name, key)
+			}
+			if kind.name != "label" {
+				continue
+			}
+			if errs := utilvalidation.IsValidLabelValue(kind.values[key]); len(errs) > 0 {
+				return fmt.Errorf("namespace label %q value is invalid: %s", key, strings.Join(errs, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// SecurityLevel is a Pod Security Standards level enforced on the project namespace
+// +kubebuilder:validation:Enum=baseline;restricted
+type SecurityLevel string
+
+const (
+	// SecurityLevelBaseline forbids privileged pods, host namespaces, host paths and added
+	// capabilities
+	SecurityLevelBaseline SecurityLevel = "baseline"
+	// SecurityLevelRestricted also requires pods to run as non-root with the RuntimeDefault
+	// seccomp profile, no privilege escalation and every capability dropped
+	SecurityLevelRestricted SecurityLevel = "restricted"
+)
+
+// PodSecurityLabelPrefix is the prefix of the namespace labels enforcing Pod Security Standards
+const PodSecurityLabelPrefix = "pod-security.kubernetes.io/"
+
+// ValidateSecurityLevel rejects unknown levels and pod security labels in the namespace config
+// of a project enforcing a level, the operator owns them then
+func ValidateSecurityLevel(level SecurityLevel, namespace NamespaceConfig) error {
+	switch level {
+	case "":
+		return nil
+	case SecurityLevelBaseline, SecurityLevelRestricted:
+	default:
+		return fmt.Errorf("security level must be %s or %s, got %q", SecurityLevelBaseline, SecurityLevelRestricted, level)
+	}
+	for key := range namespace.Labels {
+		if strings.HasPrefix(key, PodSecurityLabelPrefix) {
+			return fmt.Errorf("namespace label %q conflicts with security level %s", key, level)
+		}
+	}
+	return nil
+}
+
 // ProjectSpec defines the desired state of Project.
 type ProjectSpec struct {
 	// Application type configurations defining resource limits and policies
@@ -126,6 +335,42 @@ type ProjectSpec struct {
 
 	// Volume configuration for the project
 	Volumes VolumeConfig `json:"volumes,omitempty"`
+
+	// BaseDomain overrides the operator-wide domain for applications in this project.
+	// Generated application URLs become <subdomain>.apps.<baseDomain> and a dedicated
+	// wildcard certificate is provisioned for *.apps.<baseDomain>.
+	// +kubebuilder:validation:MaxLength=200
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?\.)+[a-z]{2,}$`
+	// +optional
+	BaseDomain string `json:"baseDomain,omitempty"`
+
+	// Priority selects the PriorityClasses of the project build and application pods
+	// +optional
+	Priority PriorityConfig `json:"priority,omitempty"`
+
+	// ErrorPages replaces the error responses of the ingress layer for the project routes
+	// +optional
+	ErrorPages ErrorPagesConfig `json:"errorPages,omitempty"`
+
+	// Egress restricts what the project applications may call outside the cluster
+	// +optional
+	Egress EgressConfig `json:"egress,omitempty"`
+
+	// Namespace adds labels and annotations to the project namespace, kept in sync by the operator
+	// +optional
+	Namespace NamespaceConfig `json:"namespace,omitempty"`
+
+	// SecurityLevel enforces a Pod Security Standards level on the project namespace and makes
+	// the application pods comply with it. It applies to the build pods running in the namespace
+	// too, restricted suits projects deploying prebuilt images. Empty enforces no level.
+	// +optional
+	SecurityLevel SecurityLevel `json:"securityLevel,omitempty"`
+
+	// RegistryQuota is a soft limit on the registry storage used by the project images. New
+	// builds fail once the measured usage exceeds it, running applications keep their images.
+	// +kubebuilder:validation:Pattern=^[0-9]+(\.[0-9]+)?(Mi|Gi|Ti)$
+	// +optional
+	RegistryQuota string `json:"registryQuota,omitempty"`
 }
 
 // ApplicationTypesConfig defines configurations for all supported application types
@@ -175,10 +420,155 @@ type ProjectStatus struct {
 
 	// LastReconcileTime is the timestamp of the last successful reconciliation
 	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// Cost is the estimated cost of the resources requested by the project's workloads
+	// +optional
+	Cost *ProjectCost `json:"cost,omitempty"`
+
+	// RegistryUsage is the registry storage used by the images of the project's applications
+	// +optional
+	RegistryUsage *ProjectRegistryUsage `json:"registryUsage,omitempty"`
+}
+
+// ProjectCost is a cost estimate based on the CPU, memory and storage requested by a project.
+// Amounts are decimal strings in Currency.
+type ProjectCost struct {
+	// Currency is the ISO 4217 code the amounts are expressed in
+	Currency string `json:"currency"`
+
+	// CPU is the total CPU requested by the project's running pods
+	CPU string `json:"cpu,omitempty"`
+
+	// Memory is the total memory requested by the project's running pods
+	Memory string `json:"memory,omitempty"`
+
+	// Storage is the total storage requested by the project's persistent volume claims
+	Storage string `json:"storage,omitempty"`
+
+	// HourlyCost is the estimated cost of one hour at the current requests
+	HourlyCost string `json:"hourlyCost"`
+
+	// Applications breaks the estimate down per application
+	// +optional
+	Applications []ApplicationCost `json:"applications,omitempty"`
+
+	// History is the estimated cost accrued per day (UTC), oldest first
+	// +optional
+	History []DailyCost `json:"history,omitempty"`
+
+	// LastCalculatedTime is when the estimate was last updated
+	LastCalculatedTime *metav1.Time `json:"lastCalculatedTime,omitempty"`
+}
+
+// ApplicationCost is the cost estimate of a single application
+type ApplicationCost struct {
+	// ApplicationUUID identifies the application
+	ApplicationUUID string `json:"applicationUuid"`
+
+	// CPU is the CPU requested by the application's running pods
+	CPU string `json:"cpu,omitempty"`
+
+	// Memory is the memory requested by the application's running pods
+	Memory string `json:"memory,omitempty"`
+
+	// Storage is the storage requested by the application's persistent volume claims
+	Storage string `json:"storage,omitempty"`
+
+	// HourlyCost is the estimated cost of one hour at the current requests
+	HourlyCost string `json:"hourlyCost"`
+}
+
+// DailyCost is the estimated cost accrued on one day
+type DailyCost struct {
+	// Date is the UTC day in YYYY-MM-DD format
+	Date string `json:"date"`
+
+	// Cost is the estimated cost accrued on that day
+	Cost string `json:"cost"`
+}
+
+// ProjectRegistryUsage is the registry storage used by the images of a project, measured by
+// inspecting the manifests of its repositories
+type ProjectRegistryUsage struct {
+	// Bytes is the size of the distinct layers and image configs of the project repositories.
+	// Blobs shared by several tags or repositories are counted once.
+	Bytes int64 `json:"bytes"`
+
+	// Repositories breaks the usage down per application repository
+	// +optional
+	Repositories []RepositoryUsage `json:"repositories,omitempty"`
+
+	// LastInspectedTime is when the registry was last inspected successfully
+	LastInspectedTime *metav1.Time `json:"lastInspectedTime,omitempty"`
+
+	// Error is why the last inspection failed, the usage of the previous one is kept
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// RepositoryUsage is the registry storage used by the images of a single application
+type RepositoryUsage struct {
+	// Repository is the registry repository, <namespace>/<application uuid>
+	Repository string `json:"repository"`
+
+	// ApplicationUUID identifies the application
+	ApplicationUUID string `json:"applicationUuid"`
+
+	// Tags is the number of tags in the repository
+	Tags int32 `json:"tags"`
+
+	// Bytes is the size of the distinct layers and image configs of the repository
+	Bytes int64 `json:"bytes"`
+}
+
+// RegistryQuotaBytes returns the registry quota of the project in bytes, 0 without a quota
+func (r *Project) RegistryQuotaBytes() int64 {
+	if r.Spec.RegistryQuota == "" {
+		return 0
+	}
+	quota, err := resource.ParseQuantity(r.Spec.RegistryQuota)
+	if err != nil {
+		return 0
+	}
+	return quota.Value()
+}
+
+// RegistryQuotaExceeded reports whether the last measured registry usage of the project is above
+// its registry quota
+func (r *Project) RegistryQuotaExceeded() bool {
+	quota := r.RegistryQuotaBytes()
+	return quota > 0 && r.Status.RegistryUsage != nil && r.Status.RegistryUsage.Bytes > quota
+}
+
+// RegistryQuotaMessage describes a build blocked by the registry quota of the project
+func (r *Project) RegistryQuotaMessage() string {
+	var used int64
+	if r.Status.RegistryUsage != nil {
+		used = r.Status.RegistryUsage.Bytes
+	}
+	return fmt.Sprintf("the project images use %s of registry storage, above its %s quota; "+
+		"delete unused applications or raise the project registryQuota to build again",
+		formatBinaryBytes(used), r.Spec.RegistryQuota)
+}
+
+// formatBinaryBytes renders a byte count with one decimal in the largest binary unit below it
+func formatBinaryBytes(bytes int64) string {
+	units := []string{"Ki", "Mi", "Gi", "Ti", "Pi"}
+	if bytes < 1024 {
+		return fmt.Sprintf("%d", bytes)
+	}
+	value := float64(bytes) / 1024
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f%s", value, units[unit])
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:webhook:path=/validate-platform-operator-kibaship-com-v1alpha1-project,mutating=false,failurePolicy=fail,sideEffects=None,groups=platform.operator.kibaship.com,resources=projects,verbs=create;update,versions=v1alpha1,name=vproject.kb.io,admissionReviewVersions=v1
 
@@ -217,7 +607,10 @@ func (r *Project) ValidateCreate(ctx context.Context, obj runtime.Object) (admis
 
 	projectlog.Info("validate create", "name", project.Name)
 
-	return nil, project.validateProject(ctx)
+	if err := project.validateProject(ctx); err != nil {
+		return nil, err
+	}
+	return nil, CurrentBaseDomainPolicy().Check(project.Spec.BaseDomain)
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
@@ -231,7 +624,14 @@ func (r *Project) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Obj
 
 	projectlog.Info("validate update", "name", project.Name)
 
-	return nil, project.validateProject(ctx)
+	if err := project.validateProject(ctx); err != nil {
+		return nil, err
+	}
+	// Projects admitted before a policy change keep their base domain until it is changed
+	if old, ok := oldObj.(*Project); ok && old.Spec.BaseDomain == project.Spec.BaseDomain {
+		return nil, nil
+	}
+	return nil, CurrentBaseDomainPolicy().Check(project.Spec.BaseDomain)
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
@@ -285,6 +685,32 @@ func (r *Project) validateProject(ctx context.Context) error {
 		}
 	}
 
+	if err := ValidatePriorityClassName(r.Spec.Priority.BuildPriorityClassName); err != nil {
+		return err
+	}
+	if err := ValidatePriorityClassName(r.Spec.Priority.RuntimePriorityClassName); err != nil {
+		return err
+	}
+	if err := ValidateErrorPages(r.Spec.ErrorPages); err != nil {
+		return err
+	}
+	if err := ValidateEgress(r.Spec.Egress); err != nil {
+		return err
+	}
+	if err := ValidateNamespace(r.Spec.Namespace); err != nil {
+		return err
+	}
+	if err := ValidateSecurityLevel(r.Spec.SecurityLevel, r.Spec.Namespace); err != nil {
+		return err
+	}
+
+	// Validate the operator shard if present, projects without it belong to the default shard
+	if shard, exists := labels[validation.LabelShard]; exists {
+		if !validation.ValidateShard(shard) {
+			return fmt.Errorf("project shard must be a lowercase DNS label: %s", shard)
+		}
+	}
+
 	return nil
 }
 
@@ -53,12 +53,27 @@ func (in *Application) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationCost) DeepCopyInto(out *ApplicationCost) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationCost.
+func (in *ApplicationCost) DeepCopy() *ApplicationCost {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationCost)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationDomain) DeepCopyInto(out *ApplicationDomain) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -116,6 +131,53 @@ func (in *ApplicationDomainList) DeepCopyObject() runtime.Object {
 func (in *ApplicationDomainSpec) DeepCopyInto(out *ApplicationDomainSpec) {
 	*out = *in
 	out.ApplicationRef = in.ApplicationRef
+	if in.UptimeCheck != nil {
+		in, out := &in.UptimeCheck, &out.UptimeCheck
+		*out = new(UptimeCheck)
+		**out = **in
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]DomainRoute, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityHeaders != nil {
+		in, out := &in.SecurityHeaders, &out.SecurityHeaders
+		*out = new(SecurityHeaders)
+		**out = **in
+	}
+	if in.CORS != nil {
+		in, out := &in.CORS, &out.CORS
+		*out = new(CORSPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateSecretRef != nil {
+		in, out := &in.CertificateSecretRef, &out.CertificateSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.IngressAnnotations != nil {
+		in, out := &in.IngressAnnotations, &out.IngressAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MaxBodySize != nil {
+		in, out := &in.MaxBodySize, &out.MaxBodySize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.ProxyReadTimeout != nil {
+		in, out := &in.ProxyReadTimeout, &out.ProxyReadTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ProxySendTimeout != nil {
+		in, out := &in.ProxySendTimeout, &out.ProxySendTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationDomainSpec.
@@ -136,6 +198,10 @@ func (in *ApplicationDomainStatus) DeepCopyInto(out *ApplicationDomainStatus) {
 		*out = new(NamespacedRef)
 		**out = **in
 	}
+	if in.CertificateNotAfter != nil {
+		in, out := &in.CertificateNotAfter, &out.CertificateNotAfter
+		*out = (*in).DeepCopy()
+	}
 	if in.LastReconcileTime != nil {
 		in, out := &in.LastReconcileTime, &out.LastReconcileTime
 		*out = (*in).DeepCopy()
@@ -147,6 +213,18 @@ func (in *ApplicationDomainStatus) DeepCopyInto(out *ApplicationDomainStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Uptime != nil {
+		in, out := &in.Uptime, &out.Uptime
+		*out = new(UptimeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]DomainHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationDomainStatus.
@@ -195,11 +273,66 @@ func (in *ApplicationList) DeepCopyObject() runtime.Object {
 func (in *ApplicationSpec) DeepCopyInto(out *ApplicationSpec) {
 	*out = *in
 	out.EnvironmentRef = in.EnvironmentRef
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.CurrentDeploymentRef != nil {
 		in, out := &in.CurrentDeploymentRef, &out.CurrentDeploymentRef
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.ExternalEnv != nil {
+		in, out := &in.ExternalEnv, &out.ExternalEnv
+		*out = new(ExternalEnvConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PlatformEnv != nil {
+		in, out := &in.PlatformEnv, &out.PlatformEnv
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReplicaSchedule != nil {
+		in, out := &in.ReplicaSchedule, &out.ReplicaSchedule
+		*out = new(ReplicaSchedule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(Autoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogAlerts != nil {
+		in, out := &in.LogAlerts, &out.LogAlerts
+		*out = make([]LogAlertRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(RuntimeSecurityConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseAccess != nil {
+		in, out := &in.DatabaseAccess, &out.DatabaseAccess
+		*out = new(DatabaseAccessConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SessionAffinity != nil {
+		in, out := &in.SessionAffinity, &out.SessionAffinity
+		*out = new(SessionAffinity)
+		**out = **in
+	}
+	if in.ServiceBindings != nil {
+		in, out := &in.ServiceBindings, &out.ServiceBindings
+		*out = make([]ServiceBinding, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(PromotionHooks)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.GitRepository != nil {
 		in, out := &in.GitRepository, &out.GitRepository
 		*out = new(GitRepositoryConfig)
@@ -267,6 +400,33 @@ func (in *ApplicationStatus) DeepCopyInto(out *ApplicationStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Idle != nil {
+		in, out := &in.Idle, &out.Idle
+		*out = new(IdleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicaSchedule != nil {
+		in, out := &in.ReplicaSchedule, &out.ReplicaSchedule
+		*out = new(ReplicaScheduleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseAccess != nil {
+		in, out := &in.DatabaseAccess, &out.DatabaseAccess
+		*out = new(DatabaseAccessStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogAlerts != nil {
+		in, out := &in.LogAlerts, &out.LogAlerts
+		*out = make([]LogAlertStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationStatus.
@@ -320,6 +480,151 @@ func (in *ApplicationTypesConfig) DeepCopy() *ApplicationTypesConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Autoscaling) DeepCopyInto(out *Autoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]AutoscalingTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Autoscaling.
+func (in *Autoscaling) DeepCopy() *Autoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(Autoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingStatus) DeepCopyInto(out *AutoscalingStatus) {
+	*out = *in
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingStatus.
+func (in *AutoscalingStatus) DeepCopy() *AutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingTrigger) DeepCopyInto(out *AutoscalingTrigger) {
+	*out = *in
+	if in.ApplicationRef != nil {
+		in, out := &in.ApplicationRef, &out.ApplicationRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingTrigger.
+func (in *AutoscalingTrigger) DeepCopy() *AutoscalingTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildSchedulingConfig) DeepCopyInto(out *BuildSchedulingConfig) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildSchedulingConfig.
+func (in *BuildSchedulingConfig) DeepCopy() *BuildSchedulingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildSchedulingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildpacksBuildConfig) DeepCopyInto(out *BuildpacksBuildConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildpacksBuildConfig.
+func (in *BuildpacksBuildConfig) DeepCopy() *BuildpacksBuildConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildpacksBuildConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CORSPolicy) DeepCopyInto(out *CORSPolicy) {
+	*out = *in
+	if in.AllowOrigins != nil {
+		in, out := &in.AllowOrigins, &out.AllowOrigins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowMethods != nil {
+		in, out := &in.AllowMethods, &out.AllowMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowHeaders != nil {
+		in, out := &in.AllowHeaders, &out.AllowHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExposeHeaders != nil {
+		in, out := &in.ExposeHeaders, &out.ExposeHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CORSPolicy.
+func (in *CORSPolicy) DeepCopy() *CORSPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CORSPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterApplicationTypeConfig) DeepCopyInto(out *ClusterApplicationTypeConfig) {
 	*out = *in
@@ -369,6 +674,112 @@ func (in *ClusterResourceLimits) DeepCopy() *ClusterResourceLimits {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DailyCost) DeepCopyInto(out *DailyCost) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DailyCost.
+func (in *DailyCost) DeepCopy() *DailyCost {
+	if in == nil {
+		return nil
+	}
+	out := new(DailyCost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseAccessConfig) DeepCopyInto(out *DatabaseAccessConfig) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]DatabaseSchema, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]DatabaseUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseAccessConfig.
+func (in *DatabaseAccessConfig) DeepCopy() *DatabaseAccessConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseAccessConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseAccessStatus) DeepCopyInto(out *DatabaseAccessStatus) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseAccessStatus.
+func (in *DatabaseAccessStatus) DeepCopy() *DatabaseAccessStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseAccessStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseSchema) DeepCopyInto(out *DatabaseSchema) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSchema.
+func (in *DatabaseSchema) DeepCopy() *DatabaseSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUser) DeepCopyInto(out *DatabaseUser) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUser.
+func (in *DatabaseUser) DeepCopy() *DatabaseUser {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Deployment) DeepCopyInto(out *Deployment) {
 	*out = *in
@@ -442,6 +853,16 @@ func (in *DeploymentSpec) DeepCopyInto(out *DeploymentSpec) {
 		*out = new(ImageFromRegistryDeploymentConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PromotedFrom != nil {
+		in, out := &in.PromotedFrom, &out.PromotedFrom
+		*out = new(PromotionSource)
+		**out = **in
+	}
+	if in.ExternalBuild != nil {
+		in, out := &in.ExternalBuild, &out.ExternalBuild
+		*out = new(ExternalBuildSource)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentSpec.
@@ -464,6 +885,17 @@ func (in *DeploymentStatus) DeepCopyInto(out *DeploymentStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EstimatedStart != nil {
+		in, out := &in.EstimatedStart, &out.EstimatedStart
+		*out = (*in).DeepCopy()
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]PromotionHookStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentStatus.
@@ -509,6 +941,18 @@ func (in *DockerImageConfig) DeepCopy() *DockerImageConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DockerfileBuildConfig) DeepCopyInto(out *DockerfileBuildConfig) {
 	*out = *in
+	if in.BuildArgs != nil {
+		in, out := &in.BuildArgs, &out.BuildArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.BuildSecrets != nil {
+		in, out := &in.BuildSecrets, &out.BuildSecrets
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerfileBuildConfig.
@@ -522,26 +966,25 @@ func (in *DockerfileBuildConfig) DeepCopy() *DockerfileBuildConfig {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Environment) DeepCopyInto(out *Environment) {
+func (in *DomainClaim) DeepCopyInto(out *DomainClaim) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Environment.
-func (in *Environment) DeepCopy() *Environment {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainClaim.
+func (in *DomainClaim) DeepCopy() *DomainClaim {
 	if in == nil {
 		return nil
 	}
-	out := new(Environment)
+	out := new(DomainClaim)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Environment) DeepCopyObject() runtime.Object {
+func (in *DomainClaim) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -549,31 +992,31 @@ func (in *Environment) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EnvironmentList) DeepCopyInto(out *EnvironmentList) {
+func (in *DomainClaimList) DeepCopyInto(out *DomainClaimList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Environment, len(*in))
+		*out = make([]DomainClaim, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentList.
-func (in *EnvironmentList) DeepCopy() *EnvironmentList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainClaimList.
+func (in *DomainClaimList) DeepCopy() *DomainClaimList {
 	if in == nil {
 		return nil
 	}
-	out := new(EnvironmentList)
+	out := new(DomainClaimList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EnvironmentList) DeepCopyObject() runtime.Object {
+func (in *DomainClaimList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -581,400 +1024,1200 @@ func (in *EnvironmentList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EnvironmentSpec) DeepCopyInto(out *EnvironmentSpec) {
+func (in *DomainClaimReference) DeepCopyInto(out *DomainClaimReference) {
 	*out = *in
-	out.ProjectRef = in.ProjectRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentSpec.
-func (in *EnvironmentSpec) DeepCopy() *EnvironmentSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainClaimReference.
+func (in *DomainClaimReference) DeepCopy() *DomainClaimReference {
 	if in == nil {
 		return nil
 	}
-	out := new(EnvironmentSpec)
+	out := new(DomainClaimReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EnvironmentStatus) DeepCopyInto(out *EnvironmentStatus) {
+func (in *DomainClaimSpec) DeepCopyInto(out *DomainClaimSpec) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	out.DomainRef = in.DomainRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainClaimSpec.
+func (in *DomainClaimSpec) DeepCopy() *DomainClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainHistoryEntry) DeepCopyInto(out *DomainHistoryEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainHistoryEntry.
+func (in *DomainHistoryEntry) DeepCopy() *DomainHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainRoute) DeepCopyInto(out *DomainRoute) {
+	*out = *in
+	out.ApplicationRef = in.ApplicationRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainRoute.
+func (in *DomainRoute) DeepCopy() *DomainRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EgressConfig) DeepCopyInto(out *EgressConfig) {
+	*out = *in
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedDomains != nil {
+		in, out := &in.AllowedDomains, &out.AllowedDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressConfig.
+func (in *EgressConfig) DeepCopy() *EgressConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EgressConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvReference) DeepCopyInto(out *EnvReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvReference.
+func (in *EnvReference) DeepCopy() *EnvReference {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Environment) DeepCopyInto(out *Environment) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Environment.
+func (in *Environment) DeepCopy() *Environment {
+	if in == nil {
+		return nil
+	}
+	out := new(Environment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Environment) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentList) DeepCopyInto(out *EnvironmentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Environment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentList.
+func (in *EnvironmentList) DeepCopy() *EnvironmentList {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnvironmentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentSpec) DeepCopyInto(out *EnvironmentSpec) {
+	*out = *in
+	out.ProjectRef = in.ProjectRef
+	if in.FreezeWindows != nil {
+		in, out := &in.FreezeWindows, &out.FreezeWindows
+		*out = make([]FreezeWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.IdlePolicy != nil {
+		in, out := &in.IdlePolicy, &out.IdlePolicy
+		*out = new(IdlePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentSpec.
+func (in *EnvironmentSpec) DeepCopy() *EnvironmentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentStatus) DeepCopyInto(out *EnvironmentStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastReconcileTime != nil {
+		in, out := &in.LastReconcileTime, &out.LastReconcileTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentStatus.
+func (in *EnvironmentStatus) DeepCopy() *EnvironmentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorPagesConfig) DeepCopyInto(out *ErrorPagesConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorPagesConfig.
+func (in *ErrorPagesConfig) DeepCopy() *ErrorPagesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorPagesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalBuildSource) DeepCopyInto(out *ExternalBuildSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalBuildSource.
+func (in *ExternalBuildSource) DeepCopy() *ExternalBuildSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalBuildSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalEnvConfig) DeepCopyInto(out *ExternalEnvConfig) {
+	*out = *in
+	out.SecretStoreRef = in.SecretStoreRef
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make([]ExternalEnvVar, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalEnvConfig.
+func (in *ExternalEnvConfig) DeepCopy() *ExternalEnvConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalEnvConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalEnvVar) DeepCopyInto(out *ExternalEnvVar) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalEnvVar.
+func (in *ExternalEnvVar) DeepCopy() *ExternalEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretStoreRef) DeepCopyInto(out *ExternalSecretStoreRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretStoreRef.
+func (in *ExternalSecretStoreRef) DeepCopy() *ExternalSecretStoreRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretStoreRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FreezeWindow) DeepCopyInto(out *FreezeWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreezeWindow.
+func (in *FreezeWindow) DeepCopy() *FreezeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(FreezeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitRepositoryConfig) DeepCopyInto(out *GitRepositoryConfig) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.SparseCheckoutPaths != nil {
+		in, out := &in.SparseCheckoutPaths, &out.SparseCheckoutPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DockerfileBuild != nil {
+		in, out := &in.DockerfileBuild, &out.DockerfileBuild
+		*out = new(DockerfileBuildConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BuildpacksBuild != nil {
+		in, out := &in.BuildpacksBuild, &out.BuildpacksBuild
+		*out = new(BuildpacksBuildConfig)
+		**out = **in
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.BuildEnvSecretRef != nil {
+		in, out := &in.BuildEnvSecretRef, &out.BuildEnvSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheckConfig)
+		**out = **in
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]PipelineStep, len(*in))
+		copy(*out, *in)
+	}
+	if in.BuildScheduling != nil {
+		in, out := &in.BuildScheduling, &out.BuildScheduling
+		*out = new(BuildSchedulingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepositoryConfig.
+func (in *GitRepositoryConfig) DeepCopy() *GitRepositoryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GitRepositoryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitRepositoryDeploymentConfig) DeepCopyInto(out *GitRepositoryDeploymentConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepositoryDeploymentConfig.
+func (in *GitRepositoryDeploymentConfig) DeepCopy() *GitRepositoryDeploymentConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GitRepositoryDeploymentConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheckConfig) DeepCopyInto(out *HealthCheckConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckConfig.
+func (in *HealthCheckConfig) DeepCopy() *HealthCheckConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheckConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdlePolicy) DeepCopyInto(out *IdlePolicy) {
+	*out = *in
+	if in.IdleAfter != nil {
+		in, out := &in.IdleAfter, &out.IdleAfter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdlePolicy.
+func (in *IdlePolicy) DeepCopy() *IdlePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(IdlePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdleStatus) DeepCopyInto(out *IdleStatus) {
+	*out = *in
+	if in.LastRequestTime != nil {
+		in, out := &in.LastRequestTime, &out.LastRequestTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdleStatus.
+func (in *IdleStatus) DeepCopy() *IdleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IdleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageFromRegistryConfig) DeepCopyInto(out *ImageFromRegistryConfig) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheckConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageFromRegistryConfig.
+func (in *ImageFromRegistryConfig) DeepCopy() *ImageFromRegistryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageFromRegistryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageFromRegistryDeploymentConfig) DeepCopyInto(out *ImageFromRegistryDeploymentConfig) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageFromRegistryDeploymentConfig.
+func (in *ImageFromRegistryDeploymentConfig) DeepCopy() *ImageFromRegistryDeploymentConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageFromRegistryDeploymentConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogAlertRule) DeepCopyInto(out *LogAlertRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogAlertRule.
+func (in *LogAlertRule) DeepCopy() *LogAlertRule {
+	if in == nil {
+		return nil
+	}
+	out := new(LogAlertRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogAlertStatus) DeepCopyInto(out *LogAlertStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogAlertStatus.
+func (in *LogAlertStatus) DeepCopy() *LogAlertStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LogAlertStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MySQLClusterConfig) DeepCopyInto(out *MySQLClusterConfig) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MySQLClusterConfig.
+func (in *MySQLClusterConfig) DeepCopy() *MySQLClusterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MySQLClusterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MySQLConfig) DeepCopyInto(out *MySQLConfig) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MySQLConfig.
+func (in *MySQLConfig) DeepCopy() *MySQLConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MySQLConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceConfig) DeepCopyInto(out *NamespaceConfig) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceConfig.
+func (in *NamespaceConfig) DeepCopy() *NamespaceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedRef) DeepCopyInto(out *NamespacedRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedRef.
+func (in *NamespacedRef) DeepCopy() *NamespacedRef {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineStep) DeepCopyInto(out *PipelineStep) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStep.
+func (in *PipelineStep) DeepCopy() *PipelineStep {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresClusterConfig) DeepCopyInto(out *PostgresClusterConfig) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresClusterConfig.
+func (in *PostgresClusterConfig) DeepCopy() *PostgresClusterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresClusterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresConfig) DeepCopyInto(out *PostgresConfig) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfig.
+func (in *PostgresConfig) DeepCopy() *PostgresConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PriorityConfig) DeepCopyInto(out *PriorityConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriorityConfig.
+func (in *PriorityConfig) DeepCopy() *PriorityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PriorityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Project) DeepCopyInto(out *Project) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Project.
+func (in *Project) DeepCopy() *Project {
+	if in == nil {
+		return nil
+	}
+	out := new(Project)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Project) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectCost) DeepCopyInto(out *ProjectCost) {
+	*out = *in
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]ApplicationCost, len(*in))
+		copy(*out, *in)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]DailyCost, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastCalculatedTime != nil {
+		in, out := &in.LastCalculatedTime, &out.LastCalculatedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectCost.
+func (in *ProjectCost) DeepCopy() *ProjectCost {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectCost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectList) DeepCopyInto(out *ProjectList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Project, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.LastReconcileTime != nil {
-		in, out := &in.LastReconcileTime, &out.LastReconcileTime
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectList.
+func (in *ProjectList) DeepCopy() *ProjectList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectRegistryUsage) DeepCopyInto(out *ProjectRegistryUsage) {
+	*out = *in
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]RepositoryUsage, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastInspectedTime != nil {
+		in, out := &in.LastInspectedTime, &out.LastInspectedTime
 		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentStatus.
-func (in *EnvironmentStatus) DeepCopy() *EnvironmentStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectRegistryUsage.
+func (in *ProjectRegistryUsage) DeepCopy() *ProjectRegistryUsage {
 	if in == nil {
 		return nil
 	}
-	out := new(EnvironmentStatus)
+	out := new(ProjectRegistryUsage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitRepositoryConfig) DeepCopyInto(out *GitRepositoryConfig) {
+func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
 	*out = *in
-	if in.SecretRef != nil {
-		in, out := &in.SecretRef, &out.SecretRef
-		*out = new(v1.LocalObjectReference)
-		**out = **in
+	out.ApplicationTypes = in.ApplicationTypes
+	out.Volumes = in.Volumes
+	out.Priority = in.Priority
+	out.ErrorPages = in.ErrorPages
+	in.Egress.DeepCopyInto(&out.Egress)
+	in.Namespace.DeepCopyInto(&out.Namespace)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSpec.
+func (in *ProjectSpec) DeepCopy() *ProjectSpec {
+	if in == nil {
+		return nil
 	}
-	if in.DockerfileBuild != nil {
-		in, out := &in.DockerfileBuild, &out.DockerfileBuild
-		*out = new(DockerfileBuildConfig)
-		**out = **in
+	out := new(ProjectSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectStatus) DeepCopyInto(out *ProjectStatus) {
+	*out = *in
+	if in.LastReconcileTime != nil {
+		in, out := &in.LastReconcileTime, &out.LastReconcileTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = new(v1.LocalObjectReference)
-		**out = **in
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(ProjectCost)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.HealthCheck != nil {
-		in, out := &in.HealthCheck, &out.HealthCheck
-		*out = new(HealthCheckConfig)
-		**out = **in
+	if in.RegistryUsage != nil {
+		in, out := &in.RegistryUsage, &out.RegistryUsage
+		*out = new(ProjectRegistryUsage)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepositoryConfig.
-func (in *GitRepositoryConfig) DeepCopy() *GitRepositoryConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectStatus.
+func (in *ProjectStatus) DeepCopy() *ProjectStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GitRepositoryConfig)
+	out := new(ProjectStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitRepositoryDeploymentConfig) DeepCopyInto(out *GitRepositoryDeploymentConfig) {
+func (in *PromotionHook) DeepCopyInto(out *PromotionHook) {
 	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepositoryDeploymentConfig.
-func (in *GitRepositoryDeploymentConfig) DeepCopy() *GitRepositoryDeploymentConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionHook.
+func (in *PromotionHook) DeepCopy() *PromotionHook {
 	if in == nil {
 		return nil
 	}
-	out := new(GitRepositoryDeploymentConfig)
+	out := new(PromotionHook)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealthCheckConfig) DeepCopyInto(out *HealthCheckConfig) {
+func (in *PromotionHookStatus) DeepCopyInto(out *PromotionHookStatus) {
 	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckConfig.
-func (in *HealthCheckConfig) DeepCopy() *HealthCheckConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionHookStatus.
+func (in *PromotionHookStatus) DeepCopy() *PromotionHookStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HealthCheckConfig)
+	out := new(PromotionHookStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageFromRegistryConfig) DeepCopyInto(out *ImageFromRegistryConfig) {
+func (in *PromotionHooks) DeepCopyInto(out *PromotionHooks) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
+	if in.PrePromote != nil {
+		in, out := &in.PrePromote, &out.PrePromote
+		*out = new(PromotionHook)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = new(v1.LocalObjectReference)
-		**out = **in
-	}
-	if in.HealthCheck != nil {
-		in, out := &in.HealthCheck, &out.HealthCheck
-		*out = new(HealthCheckConfig)
-		**out = **in
+	if in.PostPromote != nil {
+		in, out := &in.PostPromote, &out.PostPromote
+		*out = new(PromotionHook)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageFromRegistryConfig.
-func (in *ImageFromRegistryConfig) DeepCopy() *ImageFromRegistryConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionHooks.
+func (in *PromotionHooks) DeepCopy() *PromotionHooks {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageFromRegistryConfig)
+	out := new(PromotionHooks)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageFromRegistryDeploymentConfig) DeepCopyInto(out *ImageFromRegistryDeploymentConfig) {
+func (in *PromotionSource) DeepCopyInto(out *PromotionSource) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageFromRegistryDeploymentConfig.
-func (in *ImageFromRegistryDeploymentConfig) DeepCopy() *ImageFromRegistryDeploymentConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionSource.
+func (in *PromotionSource) DeepCopy() *PromotionSource {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageFromRegistryDeploymentConfig)
+	out := new(PromotionSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MySQLClusterConfig) DeepCopyInto(out *MySQLClusterConfig) {
+func (in *ReplicaSchedule) DeepCopyInto(out *ReplicaSchedule) {
 	*out = *in
-	if in.SecretRef != nil {
-		in, out := &in.SecretRef, &out.SecretRef
-		*out = new(v1.LocalObjectReference)
+	if in.DefaultReplicas != nil {
+		in, out := &in.DefaultReplicas, &out.DefaultReplicas
+		*out = new(int32)
 		**out = **in
 	}
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = new(v1.LocalObjectReference)
-		**out = **in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]ReplicaWindow, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MySQLClusterConfig.
-func (in *MySQLClusterConfig) DeepCopy() *MySQLClusterConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaSchedule.
+func (in *ReplicaSchedule) DeepCopy() *ReplicaSchedule {
 	if in == nil {
 		return nil
 	}
-	out := new(MySQLClusterConfig)
+	out := new(ReplicaSchedule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MySQLConfig) DeepCopyInto(out *MySQLConfig) {
+func (in *ReplicaScheduleStatus) DeepCopyInto(out *ReplicaScheduleStatus) {
 	*out = *in
-	if in.SecretRef != nil {
-		in, out := &in.SecretRef, &out.SecretRef
-		*out = new(v1.LocalObjectReference)
-		**out = **in
+	if in.ActiveUntil != nil {
+		in, out := &in.ActiveUntil, &out.ActiveUntil
+		*out = (*in).DeepCopy()
 	}
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = new(v1.LocalObjectReference)
-		**out = **in
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MySQLConfig.
-func (in *MySQLConfig) DeepCopy() *MySQLConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaScheduleStatus.
+func (in *ReplicaScheduleStatus) DeepCopy() *ReplicaScheduleStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MySQLConfig)
+	out := new(ReplicaScheduleStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespacedRef) DeepCopyInto(out *NamespacedRef) {
+func (in *ReplicaWindow) DeepCopyInto(out *ReplicaWindow) {
 	*out = *in
+	out.Duration = in.Duration
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedRef.
-func (in *NamespacedRef) DeepCopy() *NamespacedRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaWindow.
+func (in *ReplicaWindow) DeepCopy() *ReplicaWindow {
 	if in == nil {
 		return nil
 	}
-	out := new(NamespacedRef)
+	out := new(ReplicaWindow)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostgresClusterConfig) DeepCopyInto(out *PostgresClusterConfig) {
+func (in *RepositoryUsage) DeepCopyInto(out *RepositoryUsage) {
 	*out = *in
-	if in.SecretRef != nil {
-		in, out := &in.SecretRef, &out.SecretRef
-		*out = new(v1.LocalObjectReference)
-		**out = **in
-	}
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = new(v1.LocalObjectReference)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresClusterConfig.
-func (in *PostgresClusterConfig) DeepCopy() *PostgresClusterConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryUsage.
+func (in *RepositoryUsage) DeepCopy() *RepositoryUsage {
 	if in == nil {
 		return nil
 	}
-	out := new(PostgresClusterConfig)
+	out := new(RepositoryUsage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostgresConfig) DeepCopyInto(out *PostgresConfig) {
+func (in *ResourceBounds) DeepCopyInto(out *ResourceBounds) {
 	*out = *in
-	if in.SecretRef != nil {
-		in, out := &in.SecretRef, &out.SecretRef
-		*out = new(v1.LocalObjectReference)
-		**out = **in
-	}
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = new(v1.LocalObjectReference)
-		**out = **in
-	}
+	out.Min = in.Min
+	out.Max = in.Max
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfig.
-func (in *PostgresConfig) DeepCopy() *PostgresConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceBounds.
+func (in *ResourceBounds) DeepCopy() *ResourceBounds {
 	if in == nil {
 		return nil
 	}
-	out := new(PostgresConfig)
+	out := new(ResourceBounds)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Project) DeepCopyInto(out *Project) {
+func (in *ResourceLimits) DeepCopyInto(out *ResourceLimits) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Project.
-func (in *Project) DeepCopy() *Project {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceLimits.
+func (in *ResourceLimits) DeepCopy() *ResourceLimits {
 	if in == nil {
 		return nil
 	}
-	out := new(Project)
+	out := new(ResourceLimits)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Project) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeCapabilities) DeepCopyInto(out *RuntimeCapabilities) {
+	*out = *in
+	if in.Add != nil {
+		in, out := &in.Add, &out.Add
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	return nil
+	if in.Drop != nil {
+		in, out := &in.Drop, &out.Drop
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeCapabilities.
+func (in *RuntimeCapabilities) DeepCopy() *RuntimeCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeCapabilities)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectList) DeepCopyInto(out *ProjectList) {
+func (in *RuntimeSecurityConfig) DeepCopyInto(out *RuntimeSecurityConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Project, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.RunAsUser != nil {
+		in, out := &in.RunAsUser, &out.RunAsUser
+		*out = new(int64)
+		**out = **in
+	}
+	if in.FSGroup != nil {
+		in, out := &in.FSGroup, &out.FSGroup
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(RuntimeCapabilities)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectList.
-func (in *ProjectList) DeepCopy() *ProjectList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeSecurityConfig.
+func (in *RuntimeSecurityConfig) DeepCopy() *RuntimeSecurityConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectList)
+	out := new(RuntimeSecurityConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProjectList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityHeaders) DeepCopyInto(out *SecurityHeaders) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityHeaders.
+func (in *SecurityHeaders) DeepCopy() *SecurityHeaders {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(SecurityHeaders)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
+func (in *ServiceBinding) DeepCopyInto(out *ServiceBinding) {
 	*out = *in
-	out.ApplicationTypes = in.ApplicationTypes
-	out.Volumes = in.Volumes
+	out.Application = in.Application
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSpec.
-func (in *ProjectSpec) DeepCopy() *ProjectSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceBinding.
+func (in *ServiceBinding) DeepCopy() *ServiceBinding {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectSpec)
+	out := new(ServiceBinding)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectStatus) DeepCopyInto(out *ProjectStatus) {
+func (in *SessionAffinity) DeepCopyInto(out *SessionAffinity) {
 	*out = *in
-	if in.LastReconcileTime != nil {
-		in, out := &in.LastReconcileTime, &out.LastReconcileTime
-		*out = (*in).DeepCopy()
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectStatus.
-func (in *ProjectStatus) DeepCopy() *ProjectStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionAffinity.
+func (in *SessionAffinity) DeepCopy() *SessionAffinity {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectStatus)
+	out := new(SessionAffinity)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceBounds) DeepCopyInto(out *ResourceBounds) {
+func (in *UptimeCheck) DeepCopyInto(out *UptimeCheck) {
 	*out = *in
-	out.Min = in.Min
-	out.Max = in.Max
+	out.Interval = in.Interval
+	out.Timeout = in.Timeout
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceBounds.
-func (in *ResourceBounds) DeepCopy() *ResourceBounds {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UptimeCheck.
+func (in *UptimeCheck) DeepCopy() *UptimeCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceBounds)
+	out := new(UptimeCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceLimits) DeepCopyInto(out *ResourceLimits) {
+func (in *UptimeStatus) DeepCopyInto(out *UptimeStatus) {
 	*out = *in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceLimits.
-func (in *ResourceLimits) DeepCopy() *ResourceLimits {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UptimeStatus.
+func (in *UptimeStatus) DeepCopy() *UptimeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceLimits)
+	out := new(UptimeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
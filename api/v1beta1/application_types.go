@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// ApplicationSpec defines the desired state of Application.
+type ApplicationSpec struct {
+	// EnvironmentRef references the Environment this application belongs to
+	// +kubebuilder:validation:Required
+	EnvironmentRef corev1.LocalObjectReference `json:"environmentRef"`
+
+	// Type defines the type of application
+	// +kubebuilder:validation:Required
+	Type v1alpha1.ApplicationType `json:"type"`
+
+	// Port specifies the container port the application listens on. Without a port, deployments
+	// listen on the port their build detected, or 3000.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// BaseDomain overrides the project and operator base domain for this application's
+	// generated domains
+	// +kubebuilder:validation:MaxLength=200
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?\.)+[a-z]{2,}$`
+	// +optional
+	BaseDomain string `json:"baseDomain,omitempty"`
+
+	// DependsOn references the applications of the same environment this application needs
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	DependsOn []corev1.LocalObjectReference `json:"dependsOn,omitempty"`
+
+	// PromotedDeploymentRef references the deployment serving the application, updated when a
+	// deployment with promote=true succeeds. It is currentDeploymentRef in v1alpha1.
+	// +optional
+	PromotedDeploymentRef *corev1.LocalObjectReference `json:"promotedDeploymentRef,omitempty"`
+
+	// ExternalEnv sources environment variables from an external secret store
+	// +optional
+	ExternalEnv *v1alpha1.ExternalEnvConfig `json:"externalEnv,omitempty"`
+
+	// PlatformEnv injects variables describing the deployment context into the application
+	// containers. Defaults to true.
+	// +optional
+	PlatformEnv *bool `json:"platformEnv,omitempty"`
+
+	// ReplicaSchedule scales the application's deployments on a timetable
+	// +optional
+	ReplicaSchedule *v1alpha1.ReplicaSchedule `json:"replicaSchedule,omitempty"`
+
+	// Autoscaling scales the application's deployments on CPU, request rate or queue metrics
+	// +optional
+	Autoscaling *v1alpha1.Autoscaling `json:"autoscaling,omitempty"`
+
+	// LogAlerts fire webhooks when the runtime logs of the application match a pattern
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=20
+	LogAlerts []v1alpha1.LogAlertRule `json:"logAlerts,omitempty"`
+
+	// SecurityContext sets the user, filesystem and capabilities of the application containers
+	// +optional
+	SecurityContext *v1alpha1.RuntimeSecurityConfig `json:"securityContext,omitempty"`
+
+	// DatabaseAccess declares additional databases and users of MySQL and Postgres applications
+	// +optional
+	DatabaseAccess *v1alpha1.DatabaseAccessConfig `json:"databaseAccess,omitempty"`
+
+	// SessionAffinity pins each client to one replica of the application with a cookie
+	// +optional
+	SessionAffinity *v1alpha1.SessionAffinity `json:"sessionAffinity,omitempty"`
+
+	// Shared lets the other applications of the environment bind to this MySQL or Postgres
+	// application through serviceBindings
+	// +optional
+	Shared bool `json:"shared,omitempty"`
+
+	// ServiceBindings connect this application to shared database applications of its environment
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	ServiceBindings []v1alpha1.ServiceBinding `json:"serviceBindings,omitempty"`
+
+	// Hooks run commands around the automatic promotion of the deployments of GitRepository,
+	// DockerImage and ImageFromRegistry applications. Promotions requested through the API, such
+	// as rollbacks, do not run them.
+	// +optional
+	Hooks *v1alpha1.PromotionHooks `json:"hooks,omitempty"`
+
+	// GitRepository contains configuration for GitRepository applications
+	// +optional
+	GitRepository *v1alpha1.GitRepositoryConfig `json:"gitRepository,omitempty"`
+
+	// DockerImage contains configuration for DockerImage applications
+	// +optional
+	DockerImage *v1alpha1.DockerImageConfig `json:"dockerImage,omitempty"`
+
+	// RegistryImage contains configuration for ImageFromRegistry applications. It is
+	// imageFromRegistry in v1alpha1.
+	// +optional
+	RegistryImage *v1alpha1.ImageFromRegistryConfig `json:"registryImage,omitempty"`
+
+	// MySQL contains configuration for MySQL applications
+	// +optional
+	MySQL *v1alpha1.MySQLConfig `json:"mysql,omitempty"`
+
+	// MySQLCluster contains configuration for MySQLCluster applications
+	// +optional
+	MySQLCluster *v1alpha1.MySQLClusterConfig `json:"mysqlCluster,omitempty"`
+
+	// Postgres contains configuration for Postgres applications
+	// +optional
+	Postgres *v1alpha1.PostgresConfig `json:"postgres,omitempty"`
+
+	// PostgresCluster contains configuration for PostgresCluster applications
+	// +optional
+	PostgresCluster *v1alpha1.PostgresClusterConfig `json:"postgresCluster,omitempty"`
+
+	// Valkey contains configuration for Valkey applications
+	// +optional
+	Valkey *v1alpha1.ValkeyConfig `json:"valkey,omitempty"`
+
+	// ValkeyCluster contains configuration for ValkeyCluster applications
+	// +optional
+	ValkeyCluster *v1alpha1.ValkeyClusterConfig `json:"valkeyCluster,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Environment",type="string",JSONPath=".spec.environmentRef.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Application is the Schema for the applications API.
+type Application struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApplicationSpec            `json:"spec,omitempty"`
+	Status v1alpha1.ApplicationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ApplicationList contains a list of Application.
+type ApplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Application `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Application{}, &ApplicationList{})
+}
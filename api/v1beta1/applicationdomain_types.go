@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// ApplicationDomainSpec defines the desired state of ApplicationDomain
+type ApplicationDomainSpec struct {
+	// ApplicationRef references the parent application
+	// +kubebuilder:validation:Required
+	ApplicationRef corev1.LocalObjectReference `json:"applicationRef"`
+
+	// Host is the full host name of the domain. It is domain in v1alpha1.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`
+	Host string `json:"host"`
+
+	// Port is the application port for ingress routing
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=3000
+	Port int32 `json:"port"`
+
+	// Type indicates if this is a default generated domain or custom domain
+	// +kubebuilder:validation:Enum=default;custom
+	// +kubebuilder:default=default
+	Type v1alpha1.ApplicationDomainType `json:"type,omitempty"`
+
+	// Default indicates if this is the default domain for the application
+	// +kubebuilder:default=false
+	Default bool `json:"default,omitempty"`
+
+	// TLS enables TLS for the domain. It is tlsEnabled in v1alpha1.
+	// +kubebuilder:default=true
+	TLS bool `json:"tls"`
+
+	// UptimeCheck enables periodic availability probes of the domain by the operator
+	// +optional
+	UptimeCheck *v1alpha1.UptimeCheck `json:"uptimeCheck,omitempty"`
+
+	// Routes send path prefixes of the domain to other applications
+	// +optional
+	Routes []v1alpha1.DomainRoute `json:"routes,omitempty"`
+
+	// SecurityHeaders adds security headers to every response
+	// +optional
+	SecurityHeaders *v1alpha1.SecurityHeaders `json:"securityHeaders,omitempty"`
+
+	// CORS allows browsers to call the domain from other origins
+	// +optional
+	CORS *v1alpha1.CORSPolicy `json:"cors,omitempty"`
+
+	// IgnoreTLSPolicy opts the domain out of the cluster TLS policy
+	// +optional
+	IgnoreTLSPolicy bool `json:"ignoreTLSPolicy,omitempty"`
+
+	// CertificateSecretRef references a kubernetes.io/tls Secret holding the certificate served
+	// for the domain instead of one issued through ACME
+	// +optional
+	CertificateSecretRef *corev1.LocalObjectReference `json:"certificateSecretRef,omitempty"`
+
+	// IngressAnnotations are added to the Ingress of the domain for controller specific behavior
+	// +kubebuilder:validation:MaxProperties=50
+	// +optional
+	IngressAnnotations map[string]string `json:"ingressAnnotations,omitempty"`
+
+	// BackendProtocol is the protocol the application speaks behind the domain
+	// +optional
+	BackendProtocol v1alpha1.BackendProtocol `json:"backendProtocol,omitempty"`
+
+	// MaxBodySize limits the size of request bodies sent to the domain
+	// +optional
+	MaxBodySize *resource.Quantity `json:"maxBodySize,omitempty"`
+
+	// ProxyReadTimeout is how long the ingress waits between two reads of the response
+	// +optional
+	ProxyReadTimeout *metav1.Duration `json:"proxyReadTimeout,omitempty"`
+
+	// ProxySendTimeout is how long the ingress waits between two writes of the request
+	// +optional
+	ProxySendTimeout *metav1.Duration `json:"proxySendTimeout,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Host",type=string,JSONPath=".spec.host"
+// +kubebuilder:printcolumn:name="Port",type=integer,JSONPath=".spec.port"
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Default",type=boolean,JSONPath=".spec.default"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Certificate Ready",type=boolean,JSONPath=".status.certificateReady"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// ApplicationDomain is the Schema for the applicationdomains API
+type ApplicationDomain struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApplicationDomainSpec            `json:"spec,omitempty"`
+	Status v1alpha1.ApplicationDomainStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ApplicationDomainList contains a list of ApplicationDomain
+type ApplicationDomainList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApplicationDomain `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ApplicationDomain{}, &ApplicationDomainList{})
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// The conversions rename fields only, every field has a counterpart in v1alpha1 so objects
+// round-trip without loss
+
+var (
+	_ conversion.Convertible = &Project{}
+	_ conversion.Convertible = &Application{}
+	_ conversion.Convertible = &Deployment{}
+	_ conversion.Convertible = &ApplicationDomain{}
+)
+
+// ConvertTo converts this Project to the Hub version (v1alpha1).
+func (src *Project) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Project)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.ProjectSpec{
+		ApplicationTypes: src.Spec.AllowedApplicationTypes,
+		Volumes:          src.Spec.Volumes,
+		BaseDomain:       src.Spec.BaseDomain,
+		Priority:         src.Spec.Priority,
+		ErrorPages:       src.Spec.ErrorPages,
+		Egress:           src.Spec.Egress,
+		Namespace:        src.Spec.Namespace,
+		SecurityLevel:    src.Spec.SecurityLevel,
+		RegistryQuota:    src.Spec.RegistryQuota,
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *Project) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Project)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = ProjectSpec{
+		AllowedApplicationTypes: src.Spec.ApplicationTypes,
+		Volumes:                 src.Spec.Volumes,
+		BaseDomain:              src.Spec.BaseDomain,
+		Priority:                src.Spec.Priority,
+		ErrorPages:              src.Spec.ErrorPages,
+		Egress:                  src.Spec.Egress,
+		Namespace:               src.Spec.Namespace,
+		SecurityLevel:           src.Spec.SecurityLevel,
+		RegistryQuota:           src.Spec.RegistryQuota,
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertTo converts this Application to the Hub version (v1alpha1).
+func (src *Application) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Application)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.ApplicationSpec{
+		EnvironmentRef:       src.Spec.EnvironmentRef,
+		Type:                 src.Spec.Type,
+		Port:                 src.Spec.Port,
+		BaseDomain:           src.Spec.BaseDomain,
+		DependsOn:            src.Spec.DependsOn,
+		CurrentDeploymentRef: src.Spec.PromotedDeploymentRef,
+		ExternalEnv:          src.Spec.ExternalEnv,
+		PlatformEnv:          src.Spec.PlatformEnv,
+		ReplicaSchedule:      src.Spec.ReplicaSchedule,
+		Autoscaling:          src.Spec.Autoscaling,
+		LogAlerts:            src.Spec.LogAlerts,
+		SecurityContext:      src.Spec.SecurityContext,
+		DatabaseAccess:       src.Spec.DatabaseAccess,
+		SessionAffinity:      src.Spec.SessionAffinity,
+		Shared:               src.Spec.Shared,
+		ServiceBindings:      src.Spec.ServiceBindings,
+		Hooks:                src.Spec.Hooks,
+		GitRepository:        src.Spec.GitRepository,
+		DockerImage:          src.Spec.DockerImage,
+		ImageFromRegistry:    src.Spec.RegistryImage,
+		MySQL:                src.Spec.MySQL,
+		MySQLCluster:         src.Spec.MySQLCluster,
+		Postgres:             src.Spec.Postgres,
+		PostgresCluster:      src.Spec.PostgresCluster,
+		Valkey:               src.Spec.Valkey,
+		ValkeyCluster:        src.Spec.ValkeyCluster,
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *Application) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Application)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = ApplicationSpec{
+		EnvironmentRef:        src.Spec.EnvironmentRef,
+		Type:                  src.Spec.Type,
+		Port:                  src.Spec.Port,
+		BaseDomain:            src.Spec.BaseDomain,
+		DependsOn:             src.Spec.DependsOn,
+		PromotedDeploymentRef: src.Spec.CurrentDeploymentRef,
+		ExternalEnv:           src.Spec.ExternalEnv,
+		PlatformEnv:           src.Spec.PlatformEnv,
+		ReplicaSchedule:       src.Spec.ReplicaSchedule,
+		Autoscaling:           src.Spec.Autoscaling,
+		LogAlerts:             src.Spec.LogAlerts,
+		SecurityContext:       src.Spec.SecurityContext,
+		DatabaseAccess:        src.Spec.DatabaseAccess,
+		SessionAffinity:       src.Spec.SessionAffinity,
+		Shared:                src.Spec.Shared,
+		ServiceBindings:       src.Spec.ServiceBindings,
+		Hooks:                 src.Spec.Hooks,
+		GitRepository:         src.Spec.GitRepository,
+		DockerImage:           src.Spec.DockerImage,
+		RegistryImage:         src.Spec.ImageFromRegistry,
+		MySQL:                 src.Spec.MySQL,
+		MySQLCluster:          src.Spec.MySQLCluster,
+		Postgres:              src.Spec.Postgres,
+		PostgresCluster:       src.Spec.PostgresCluster,
+		Valkey:                src.Spec.Valkey,
+		ValkeyCluster:         src.Spec.ValkeyCluster,
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertTo converts this Deployment to the Hub version (v1alpha1).
+func (src *Deployment) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Deployment)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.DeploymentSpec{
+		ApplicationRef:    src.Spec.ApplicationRef,
+		Promote:           src.Spec.Promote,
+		OverrideFreeze:    src.Spec.OverrideFreeze,
+		GitRepository:     src.Spec.GitRepository,
+		ImageFromRegistry: src.Spec.RegistryImage,
+		PromotedFrom:      src.Spec.PromotedFrom,
+		ExternalBuild:     src.Spec.ExternalBuild,
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *Deployment) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Deployment)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = DeploymentSpec{
+		ApplicationRef: src.Spec.ApplicationRef,
+		Promote:        src.Spec.Promote,
+		OverrideFreeze: src.Spec.OverrideFreeze,
+		GitRepository:  src.Spec.GitRepository,
+		RegistryImage:  src.Spec.ImageFromRegistry,
+		PromotedFrom:   src.Spec.PromotedFrom,
+		ExternalBuild:  src.Spec.ExternalBuild,
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertTo converts this ApplicationDomain to the Hub version (v1alpha1).
+func (src *ApplicationDomain) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.ApplicationDomain)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.ApplicationDomainSpec{
+		ApplicationRef:       src.Spec.ApplicationRef,
+		Domain:               src.Spec.Host,
+		Port:                 src.Spec.Port,
+		Type:                 src.Spec.Type,
+		Default:              src.Spec.Default,
+		TLSEnabled:           src.Spec.TLS,
+		UptimeCheck:          src.Spec.UptimeCheck,
+		Routes:               src.Spec.Routes,
+		SecurityHeaders:      src.Spec.SecurityHeaders,
+		CORS:                 src.Spec.CORS,
+		IgnoreTLSPolicy:      src.Spec.IgnoreTLSPolicy,
+		CertificateSecretRef: src.Spec.CertificateSecretRef,
+		IngressAnnotations:   src.Spec.IngressAnnotations,
+		BackendProtocol:      src.Spec.BackendProtocol,
+		MaxBodySize:          src.Spec.MaxBodySize,
+		ProxyReadTimeout:     src.Spec.ProxyReadTimeout,
+		ProxySendTimeout:     src.Spec.ProxySendTimeout,
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *ApplicationDomain) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.ApplicationDomain)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = ApplicationDomainSpec{
+		ApplicationRef:       src.Spec.ApplicationRef,
+		Host:                 src.Spec.Domain,
+		Port:                 src.Spec.Port,
+		Type:                 src.Spec.Type,
+		Default:              src.Spec.Default,
+		TLS:                  src.Spec.TLSEnabled,
+		UptimeCheck:          src.Spec.UptimeCheck,
+		Routes:               src.Spec.Routes,
+		SecurityHeaders:      src.Spec.SecurityHeaders,
+		CORS:                 src.Spec.CORS,
+		IgnoreTLSPolicy:      src.Spec.IgnoreTLSPolicy,
+		CertificateSecretRef: src.Spec.CertificateSecretRef,
+		IngressAnnotations:   src.Spec.IngressAnnotations,
+		BackendProtocol:      src.Spec.BackendProtocol,
+		MaxBodySize:          src.Spec.MaxBodySize,
+		ProxyReadTimeout:     src.Spec.ProxyReadTimeout,
+		ProxySendTimeout:     src.Spec.ProxySendTimeout,
+	}
+	dst.Status = src.Status
+	return nil
+}
@@ -0,0 +1,107 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+func TestApplicationRoundTrip(t *testing.T) {
+	g := NewWithT(t)
+	hub := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "application-1", Namespace: "default", Labels: map[string]string{"a": "b"}},
+		Spec: v1alpha1.ApplicationSpec{
+			EnvironmentRef:       corev1.LocalObjectReference{Name: "environment-1"},
+			Type:                 v1alpha1.ApplicationTypeImageFromRegistry,
+			Port:                 8080,
+			CurrentDeploymentRef: &corev1.LocalObjectReference{Name: "deployment-1"},
+			ImageFromRegistry:    &v1alpha1.ImageFromRegistryConfig{Registry: "ghcr", Repository: "org/app"},
+			ServiceBindings: []v1alpha1.ServiceBinding{
+				{Application: corev1.LocalObjectReference{Name: "application-2"}, EnvPrefix: "ORDERS_DB"},
+			},
+			SessionAffinity: &v1alpha1.SessionAffinity{CookieName: "SERVERID", TTLSeconds: 3600},
+			Autoscaling: &v1alpha1.Autoscaling{
+				MaxReplicas: 5,
+				Triggers:    []v1alpha1.AutoscalingTrigger{{Type: v1alpha1.AutoscalingTriggerRequestsPerSecond, Target: 50}},
+			},
+		},
+		Status: v1alpha1.ApplicationStatus{Phase: "Ready"},
+	}
+
+	spoke := &Application{}
+	g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+	g.Expect(spoke.Spec.PromotedDeploymentRef.Name).To(Equal("deployment-1"))
+	g.Expect(spoke.Spec.RegistryImage.Repository).To(Equal("org/app"))
+	g.Expect(spoke.Status.Phase).To(Equal("Ready"))
+
+	converted := &v1alpha1.Application{}
+	g.Expect(spoke.ConvertTo(converted)).To(Succeed())
+	g.Expect(converted).To(Equal(hub))
+}
+
+func TestRenamedFieldsRoundTrip(t *testing.T) {
+	g := NewWithT(t)
+
+	project := &Project{Spec: ProjectSpec{
+		AllowedApplicationTypes: v1alpha1.ApplicationTypesConfig{MySQL: v1alpha1.ApplicationTypeConfig{Enabled: true}},
+		BaseDomain:              "apps.example.com",
+	}}
+	projectHub := &v1alpha1.Project{}
+	g.Expect(project.ConvertTo(projectHub)).To(Succeed())
+	g.Expect(projectHub.Spec.ApplicationTypes.MySQL.Enabled).To(BeTrue())
+	projectBack := &Project{}
+	g.Expect(projectBack.ConvertFrom(projectHub)).To(Succeed())
+	g.Expect(projectBack).To(Equal(project))
+
+	deployment := &Deployment{Spec: DeploymentSpec{
+		ApplicationRef: corev1.LocalObjectReference{Name: "application-1"},
+		RegistryImage:  &v1alpha1.ImageFromRegistryDeploymentConfig{Tag: "v1.2.3"},
+	}}
+	deploymentHub := &v1alpha1.Deployment{}
+	g.Expect(deployment.ConvertTo(deploymentHub)).To(Succeed())
+	g.Expect(deploymentHub.Spec.ImageFromRegistry.Tag).To(Equal("v1.2.3"))
+	deploymentBack := &Deployment{}
+	g.Expect(deploymentBack.ConvertFrom(deploymentHub)).To(Succeed())
+	g.Expect(deploymentBack).To(Equal(deployment))
+
+	maxBodySize := resource.MustParse("64Mi")
+	domain := &ApplicationDomain{Spec: ApplicationDomainSpec{
+		Host:               "app.example.com",
+		Port:               3000,
+		TLS:                true,
+		IngressAnnotations: map[string]string{"nginx.ingress.kubernetes.io/proxy-buffering": "off"},
+		BackendProtocol:    v1alpha1.BackendProtocolGRPC,
+		MaxBodySize:        &maxBodySize,
+		ProxyReadTimeout:   &metav1.Duration{Duration: time.Minute},
+	}}
+	domainHub := &v1alpha1.ApplicationDomain{}
+	g.Expect(domain.ConvertTo(domainHub)).To(Succeed())
+	g.Expect(domainHub.Spec.Domain).To(Equal("app.example.com"))
+	g.Expect(domainHub.Spec.TLSEnabled).To(BeTrue())
+	domainBack := &ApplicationDomain{}
+	g.Expect(domainBack.ConvertFrom(domainHub)).To(Succeed())
+	g.Expect(domainBack).To(Equal(domain))
+}
+
+// TestConvertible checks the webhook builder registers the conversion webhook for the kinds
+// served in both versions
+func TestConvertible(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(AddToScheme(scheme)).To(Succeed())
+
+	for _, obj := range []runtime.Object{&v1alpha1.Project{}, &v1alpha1.Application{}, &v1alpha1.Deployment{}, &v1alpha1.ApplicationDomain{}} {
+		convertible, err := conversion.IsConvertible(scheme, obj)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(convertible).To(BeTrue(), "%T", obj)
+	}
+}
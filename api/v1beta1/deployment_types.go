@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// DeploymentSpec defines the desired state of Deployment.
+type DeploymentSpec struct {
+	// ApplicationRef references the Application this deployment belongs to
+	// +kubebuilder:validation:Required
+	ApplicationRef corev1.LocalObjectReference `json:"applicationRef"`
+
+	// Promote makes this deployment the promoted deployment of its application once it succeeds
+	// +kubebuilder:default=false
+	// +optional
+	Promote bool `json:"promote,omitempty"`
+
+	// OverrideFreeze promotes this deployment even while an environment freeze window is active
+	// +optional
+	OverrideFreeze bool `json:"overrideFreeze,omitempty"`
+
+	// GitRepository contains configuration for GitRepository deployments
+	// +optional
+	GitRepository *v1alpha1.GitRepositoryDeploymentConfig `json:"gitRepository,omitempty"`
+
+	// RegistryImage contains configuration for ImageFromRegistry deployments. It is
+	// imageFromRegistry in v1alpha1.
+	// +optional
+	RegistryImage *v1alpha1.ImageFromRegistryDeploymentConfig `json:"registryImage,omitempty"`
+
+	// PromotedFrom is set on deployments promoted from another environment
+	// +optional
+	PromotedFrom *v1alpha1.PromotionSource `json:"promotedFrom,omitempty"`
+
+	// ExternalBuild is set on deployments of images built outside the platform
+	// +optional
+	ExternalBuild *v1alpha1.ExternalBuildSource `json:"externalBuild,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Application",type="string",JSONPath=".spec.applicationRef.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Deployment is the Schema for the deployments API.
+type Deployment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DeploymentSpec            `json:"spec,omitempty"`
+	Status v1alpha1.DeploymentStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DeploymentList contains a list of Deployment.
+type DeploymentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Deployment `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Deployment{}, &DeploymentList{})
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// ProjectSpec defines the desired state of Project.
+type ProjectSpec struct {
+	// AllowedApplicationTypes defines which types of applications can be deployed in the project,
+	// with their resource limits. It is applicationTypes in v1alpha1.
+	AllowedApplicationTypes v1alpha1.ApplicationTypesConfig `json:"allowedApplicationTypes,omitempty"`
+
+	// Volume configuration for the project
+	Volumes v1alpha1.VolumeConfig `json:"volumes,omitempty"`
+
+	// BaseDomain overrides the operator-wide domain for applications in this project
+	// +kubebuilder:validation:MaxLength=200
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?\.)+[a-z]{2,}$`
+	// +optional
+	BaseDomain string `json:"baseDomain,omitempty"`
+
+	// Priority selects the PriorityClasses of the project build and application pods
+	// +optional
+	Priority v1alpha1.PriorityConfig `json:"priority,omitempty"`
+
+	// ErrorPages replaces the error responses of the ingress layer for the project routes
+	// +optional
+	ErrorPages v1alpha1.ErrorPagesConfig `json:"errorPages,omitempty"`
+
+	// Egress restricts what the project applications may call outside the cluster
+	// +optional
+	Egress v1alpha1.EgressConfig `json:"egress,omitempty"`
+
+	// Namespace adds labels and annotations to the project namespace, kept in sync by the operator
+	// +optional
+	Namespace v1alpha1.NamespaceConfig `json:"namespace,omitempty"`
+
+	// SecurityLevel enforces a Pod Security Standards level on the project namespace
+	// +optional
+	SecurityLevel v1alpha1.SecurityLevel `json:"securityLevel,omitempty"`
+
+	// RegistryQuota is a soft limit on the registry storage used by the project images
+	// +kubebuilder:validation:Pattern=^[0-9]+(\.[0-9]+)?(Mi|Gi|Ti)$
+	// +optional
+	RegistryQuota string `json:"registryQuota,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// Project is the Schema for the projects API.
+type Project struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProjectSpec            `json:"spec,omitempty"`
+	Status v1alpha1.ProjectStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProjectList contains a list of Project.
+type ProjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Project `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Project{}, &ProjectList{})
+}
@@ -32,6 +32,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -43,13 +44,23 @@ import (
 	"github.com/kibamail/kibaship/api/v1alpha1"
 	_ "github.com/kibamail/kibaship/docs"
 	"github.com/kibamail/kibaship/pkg/auth"
+	operatorconfig "github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/envcrypt"
 	"github.com/kibamail/kibaship/pkg/handlers"
 	"github.com/kibamail/kibaship/pkg/services"
+	"github.com/kibamail/kibaship/pkg/validation"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -77,6 +88,12 @@ func main() {
 		log.Fatalf("Failed to create or retrieve API key: %v", err)
 	}
 
+	// Every replica watches the shared secret so a rotated key is accepted by all of them
+	apiKeyWatcher, err := secretManager.WatchAPIKey(context.Background(), apiKey)
+	if err != nil {
+		log.Fatalf("Failed to watch API key: %v", err)
+	}
+
 	log.Println("API key ready for authentication")
 
 	// Initialize Kubernetes client and scheme
@@ -84,6 +101,7 @@ func main() {
 	scheme := runtime.NewScheme()
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = v1alpha1.AddToScheme(scheme)
+	_ = tektonv1.AddToScheme(scheme)
 
 	config, err := rest.InClusterConfig()
 	if err != nil {
@@ -95,14 +113,63 @@ func main() {
 		log.Fatalf("Failed to create Kubernetes client: %v", err)
 	}
 
+	// Build logs are streamed from the build pods, which the controller-runtime client cannot do
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		log.Fatalf("Failed to create Kubernetes clientset: %v", err)
+	}
+
 	log.Println("Kubernetes client initialized successfully")
 
+	// Load the env var encryption provider configured in the operator ConfigMap
+	encryptor, err := loadEncryptor(context.Background(), k8sClient)
+	if err != nil {
+		log.Fatalf("Failed to initialize env var encryption: %v", err)
+	}
+	if encryptor != nil {
+		log.Printf("Env var encryption enabled with provider %s", encryptor.ProviderName())
+	}
+
+	// Load the log store the operator ships application logs to
+	logging, err := loadLoggingConfig(context.Background(), k8sClient)
+	if err != nil {
+		log.Fatalf("Failed to load logging configuration: %v", err)
+	}
+	if logging.Enabled() {
+		log.Printf("Historical logs enabled, querying Loki at %s", logging.Endpoint())
+	}
+
+	// Load the artifact service pipelines publish test reports to
+	artifacts, err := loadArtifactsConfig(context.Background(), k8sClient)
+	if err != nil {
+		log.Fatalf("Failed to load artifacts configuration: %v", err)
+	}
+	if artifacts.Enabled() {
+		log.Printf("Deployment artifacts enabled, reading from %s", artifacts.Endpoint())
+	}
+
+	// Load the Prometheus application traffic metrics are read from
+	metrics, err := loadMetricsConfig(context.Background(), k8sClient)
+	if err != nil {
+		log.Fatalf("Failed to load metrics configuration: %v", err)
+	}
+	if metrics.Enabled() {
+		log.Printf("Traffic metrics enabled, querying Prometheus at %s for %s ingress metrics", metrics.Endpoint(), metrics.IngressProvider)
+	}
+
+	// Start the informer cache the project summaries and admin searches read from
+	cacheCtx, stopCache := context.WithCancel(context.Background())
+	resourceCache, err := startResourceCache(cacheCtx, config, scheme)
+	if err != nil {
+		log.Fatalf("Failed to start resource cache: %v", err)
+	}
+
 	// Create services
 	projectService := services.NewProjectService(k8sClient, scheme)
 	environmentService := services.NewEnvironmentService(k8sClient, scheme, projectService)
 
 	// Create authenticator
-	authenticator := auth.NewAPIKeyAuthenticator(apiKey)
+	authenticator := auth.NewAPIKeyAuthenticatorWithSource(apiKeyWatcher)
 
 	// Create Gin router
 	router := gin.New()
@@ -127,7 +194,20 @@ func main() {
 		projectHandler := handlers.NewProjectHandler(projectService)
 		environmentHandler := handlers.NewEnvironmentHandler(environmentService)
 		applicationService := services.NewApplicationService(k8sClient, scheme, projectService, environmentService)
+		if encryptor != nil {
+			applicationService.SetEncryptor(encryptor)
+		}
+		if logging.Enabled() {
+			applicationService.SetLogStore(services.NewLokiClient(logging.Endpoint()))
+		}
+		if metrics.Enabled() {
+			applicationService.SetMetricsStore(services.NewPrometheusClient(metrics.Endpoint()), metrics.IngressProvider)
+		}
 		deploymentService := services.NewDeploymentService(k8sClient, scheme, applicationService)
+		if artifacts.Enabled() {
+			deploymentService.SetArtifactStore(services.NewArtifactStore(artifacts.Endpoint()))
+		}
+		deploymentService.SetPodLogs(clientset.CoreV1())
 		applicationDomainService := services.NewApplicationDomainService(k8sClient, scheme, applicationService)
 
 		// Set circular dependencies for auto-loading
@@ -138,12 +218,23 @@ func main() {
 		applicationHandler := handlers.NewApplicationHandler(applicationService)
 		deploymentHandler := handlers.NewDeploymentHandler(deploymentService)
 		applicationDomainHandler := handlers.NewApplicationDomainHandler(applicationDomainService)
+		manifestHandler := handlers.NewManifestHandler(services.NewManifestService(k8sClient, scheme))
+		projectSummaryHandler := handlers.NewProjectSummaryHandler(services.NewProjectSummaryService(resourceCache, projectService))
+		adminHandler := handlers.NewAdminHandler(services.NewAdminService(resourceCache))
+		platformHandler := handlers.NewPlatformHandler(services.NewPlatformService(k8sClient))
+		applyHandler := handlers.NewApplyHandler(services.NewApplyService(k8sClient, projectService, environmentService, applicationService, applicationDomainService))
 
 		// Project endpoints
 		v1.POST("/projects", projectHandler.CreateProject)
 		v1.GET("/projects/:uuid", projectHandler.GetProject)
 		v1.PATCH("/projects/:uuid", projectHandler.UpdateProject)
 		v1.DELETE("/projects/:uuid", projectHandler.DeleteProject)
+		v1.GET("/projects/:uuid/cost", projectHandler.GetProjectCost)
+		v1.GET("/projects/:uuid/usage", projectHandler.GetProjectUsage)
+		v1.GET("/projects/:uuid/registry-robots", projectHandler.ListRegistryRobots)
+		v1.POST("/projects/:uuid/registry-robots", projectHandler.CreateRegistryRobot)
+		v1.DELETE("/projects/:uuid/registry-robots/:name", projectHandler.DeleteRegistryRobot)
+		v1.GET("/projects/:uuid/summary", projectSummaryHandler.GetProjectSummary)
 
 		// Environment endpoints
 		v1.POST("/projects/:uuid/environments", environmentHandler.CreateEnvironment)
@@ -151,6 +242,8 @@ func main() {
 		v1.GET("/environments/:uuid", environmentHandler.GetEnvironment)
 		v1.PATCH("/environments/:uuid", environmentHandler.UpdateEnvironment)
 		v1.DELETE("/environments/:uuid", environmentHandler.DeleteEnvironment)
+		v1.GET("/environments/:uuid/freeze-windows", environmentHandler.GetFreezeWindows)
+		v1.PUT("/environments/:uuid/freeze-windows", environmentHandler.UpdateFreezeWindows)
 
 		// Application endpoints
 		v1.POST("/environments/:uuid/applications", applicationHandler.CreateApplication)
@@ -159,18 +252,96 @@ func main() {
 		v1.GET("/applications/:uuid", applicationHandler.GetApplication)
 		v1.PATCH("/applications/:uuid", applicationHandler.UpdateApplication)
 		v1.PATCH("/applications/:uuid/env", applicationHandler.UpdateApplicationEnv)
+		v1.GET("/applications/:uuid/env/versions", applicationHandler.GetApplicationEnvVersions)
+		v1.POST("/applications/:uuid/env/versions/:version/rollback", applicationHandler.RollbackApplicationEnv)
+		v1.GET("/applications/:uuid/replica-schedule", applicationHandler.GetReplicaSchedule)
+		v1.PUT("/applications/:uuid/replica-schedule", applicationHandler.UpdateReplicaSchedule)
+		v1.DELETE("/applications/:uuid/replica-schedule", applicationHandler.DeleteReplicaSchedule)
+		v1.GET("/applications/:uuid/autoscaling", applicationHandler.GetAutoscaling)
+		v1.PUT("/applications/:uuid/autoscaling", applicationHandler.UpdateAutoscaling)
+		v1.DELETE("/applications/:uuid/autoscaling", applicationHandler.DeleteAutoscaling)
+		v1.GET("/applications/:uuid/logs/history", applicationHandler.GetApplicationLogHistory)
+		v1.GET("/applications/:uuid/traffic", applicationHandler.GetApplicationTraffic)
+		v1.GET("/applications/:uuid/log-alerts", applicationHandler.GetLogAlertRules)
+		v1.PUT("/applications/:uuid/log-alerts", applicationHandler.UpdateLogAlertRules)
+		v1.GET("/applications/:uuid/hooks", applicationHandler.GetPromotionHooks)
+		v1.PUT("/applications/:uuid/hooks", applicationHandler.UpdatePromotionHooks)
+		v1.GET("/applications/:uuid/build-env", applicationHandler.GetBuildEnv)
+		v1.PUT("/applications/:uuid/build-env", applicationHandler.UpdateBuildEnv)
+		v1.GET("/applications/:uuid/session-affinity", applicationHandler.GetSessionAffinity)
+		v1.PUT("/applications/:uuid/session-affinity", applicationHandler.UpdateSessionAffinity)
+		v1.DELETE("/applications/:uuid/session-affinity", applicationHandler.DeleteSessionAffinity)
+		v1.GET("/applications/:uuid/service-bindings", applicationHandler.GetServiceBindings)
+		v1.PUT("/applications/:uuid/service-bindings", applicationHandler.UpdateServiceBindings)
+		v1.GET("/applications/:uuid/connection", applicationHandler.GetApplicationConnection)
+		v1.GET("/applications/:uuid/databases", applicationHandler.GetDatabaseAccess)
+		v1.POST("/applications/:uuid/databases", applicationHandler.CreateDatabase)
+		v1.POST("/applications/:uuid/users", applicationHandler.CreateDatabaseUser)
+		v1.DELETE("/applications/:uuid/users/:username", applicationHandler.DeleteDatabaseUser)
 		v1.DELETE("/applications/:uuid", applicationHandler.DeleteApplication)
 
 		// Deployment endpoints
 		v1.POST("/applications/:uuid/deployments", deploymentHandler.CreateDeployment)
+		v1.POST("/applications/:uuid/deployments/external", deploymentHandler.CreateExternalDeployment)
 		v1.GET("/applications/:uuid/deployments", deploymentHandler.GetDeploymentsByApplication)
+		v1.GET("/applications/:uuid/releases", deploymentHandler.GetReleasesByApplication)
 		v1.GET("/deployments/:uuid", deploymentHandler.GetDeployment)
 		v1.POST("/deployments/:uuid/promote", deploymentHandler.PromoteDeployment)
+		v1.POST("/deployments/:uuid/promote-to/:environmentUuid", deploymentHandler.PromoteDeploymentToEnvironment)
+		v1.POST("/deployments/:uuid/sync-env", deploymentHandler.SyncDeploymentEnv)
+		v1.GET("/deployments/:uuid/pipeline", deploymentHandler.GetDeploymentPipeline)
+		v1.GET("/deployments/:uuid/hooks", deploymentHandler.GetDeploymentHooks)
+		v1.GET("/deployments/:uuid/build-logs/ws", deploymentHandler.StreamBuildLogs)
+		v1.GET("/deployments/:uuid/artifacts", deploymentHandler.ListDeploymentArtifacts)
+		v1.GET("/deployments/:uuid/artifacts/*path", deploymentHandler.DownloadDeploymentArtifact)
 
 		// Application Domain endpoints
 		v1.POST("/applications/:uuid/domains", applicationDomainHandler.CreateApplicationDomain)
+		v1.POST("/applications/:uuid/domains/bulk", applicationDomainHandler.BulkCreateApplicationDomains)
 		v1.GET("/domains/:uuid", applicationDomainHandler.GetApplicationDomain)
 		v1.DELETE("/domains/:uuid", applicationDomainHandler.DeleteApplicationDomain)
+		v1.PUT("/domains/:uuid/uptime-check", applicationDomainHandler.UpdateUptimeCheck)
+		v1.DELETE("/domains/:uuid/uptime-check", applicationDomainHandler.DeleteUptimeCheck)
+		v1.PUT("/domains/:uuid/routes", applicationDomainHandler.UpdateDomainRoutes)
+		v1.DELETE("/domains/:uuid/routes", applicationDomainHandler.DeleteDomainRoutes)
+		v1.PUT("/domains/:uuid/headers", applicationDomainHandler.UpdateDomainHeaders)
+		v1.DELETE("/domains/:uuid/headers", applicationDomainHandler.DeleteDomainHeaders)
+		v1.PUT("/domains/:uuid/ingress-annotations", applicationDomainHandler.UpdateDomainIngressAnnotations)
+		v1.DELETE("/domains/:uuid/ingress-annotations", applicationDomainHandler.DeleteDomainIngressAnnotations)
+		v1.PUT("/domains/:uuid/backend-protocol", applicationDomainHandler.UpdateDomainBackendProtocol)
+		v1.DELETE("/domains/:uuid/backend-protocol", applicationDomainHandler.DeleteDomainBackendProtocol)
+		v1.PUT("/domains/:uuid/proxy-settings", applicationDomainHandler.UpdateDomainProxySettings)
+		v1.DELETE("/domains/:uuid/proxy-settings", applicationDomainHandler.DeleteDomainProxySettings)
+		v1.POST("/domains/:uuid/certificate", applicationDomainHandler.UploadDomainCertificate)
+		v1.DELETE("/domains/:uuid/certificate", applicationDomainHandler.DeleteDomainCertificate)
+
+		// Manifest endpoints
+		v1.GET("/projects/:uuid/manifest", manifestHandler.GetProjectManifest)
+		v1.GET("/environments/:uuid/manifest", manifestHandler.GetEnvironmentManifest)
+		v1.GET("/applications/:uuid/manifest", manifestHandler.GetApplicationManifest)
+		v1.GET("/deployments/:uuid/manifest", manifestHandler.GetDeploymentManifest)
+		v1.GET("/domains/:uuid/manifest", manifestHandler.GetApplicationDomainManifest)
+
+		// Declarative apply endpoint
+		v1.POST("/apply", applyHandler.Apply)
+
+		// Admin endpoints search the resources of all projects
+		v1.GET("/admin/applications", adminHandler.ListApplications)
+		v1.GET("/admin/deployments", adminHandler.ListDeployments)
+
+		// Platform endpoints report the state of the installation
+		v1.GET("/platform/bootstrap", platformHandler.GetBootstrapStatus)
+
+		// Status badges are embedded in READMEs and served without authentication
+		router.GET("/v1/domains/:uuid/badge.svg", applicationDomainHandler.GetApplicationDomainBadge)
+
+		// Git providers authenticate webhook deliveries with a signature instead of the API key
+		if secret := os.Getenv("GIT_WEBHOOK_SECRET"); secret != "" {
+			previewService := services.NewPreviewEnvironmentService(k8sClient, environmentService, applicationService, deploymentService)
+			gitWebhookHandler := handlers.NewGitWebhookHandler(previewService, secret)
+			router.POST("/v1/webhooks/github", gitWebhookHandler.ReceiveGitHubWebhook)
+			log.Println("Git webhooks enabled, pull requests get preview environments")
+		}
 	}
 
 	// Get port from environment or use default
@@ -199,6 +370,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopCache()
 
 	// Give outstanding requests a deadline for completion
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -258,3 +430,103 @@ func readyzHandler(c *gin.Context) {
 func serveSwaggerYAML(c *gin.Context) {
 	c.File("docs/swagger.yaml")
 }
+
+// loadEncryptor reads the encryption settings from the operator ConfigMap. A missing ConfigMap
+// leaves encryption disabled so the API server can run without the operator installed.
+func loadEncryptor(ctx context.Context, c client.Client) (*envcrypt.Encryptor, error) {
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: operatorconfig.OperatorNamespace, Name: operatorconfig.OperatorConfigMapName}
+	if err := c.Get(ctx, key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	encryption, err := operatorconfig.ParseEncryptionConfig(cm.Data)
+	if err != nil {
+		return nil, err
+	}
+	return envcrypt.Load(ctx, c, encryption)
+}
+
+// loadLoggingConfig reads the persistent logging settings from the operator ConfigMap. A missing
+// ConfigMap leaves historical logs disabled.
+func loadLoggingConfig(ctx context.Context, c client.Client) (operatorconfig.LoggingConfig, error) {
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: operatorconfig.OperatorNamespace, Name: operatorconfig.OperatorConfigMapName}
+	if err := c.Get(ctx, key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return operatorconfig.LoggingConfig{}, nil
+		}
+		return operatorconfig.LoggingConfig{}, err
+	}
+
+	return operatorconfig.ParseLoggingConfig(cm.Data)
+}
+
+// loadArtifactsConfig reads the pipeline artifact settings from the operator ConfigMap. A missing
+// ConfigMap leaves artifact collection disabled.
+func loadArtifactsConfig(ctx context.Context, c client.Client) (operatorconfig.ArtifactsConfig, error) {
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: operatorconfig.OperatorNamespace, Name: operatorconfig.OperatorConfigMapName}
+	if err := c.Get(ctx, key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return operatorconfig.ArtifactsConfig{}, nil
+		}
+		return operatorconfig.ArtifactsConfig{}, err
+	}
+
+	return operatorconfig.ParseArtifactsConfig(cm.Data)
+}
+
+// loadMetricsConfig reads the traffic metrics settings from the operator ConfigMap. A missing
+// ConfigMap leaves traffic metrics disabled.
+func loadMetricsConfig(ctx context.Context, c client.Client) (operatorconfig.MetricsConfig, error) {
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: operatorconfig.OperatorNamespace, Name: operatorconfig.OperatorConfigMapName}
+	if err := c.Get(ctx, key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return operatorconfig.MetricsConfig{}, nil
+		}
+		return operatorconfig.MetricsConfig{}, err
+	}
+
+	return operatorconfig.ParseMetricsConfig(cm.Data)
+}
+
+// startResourceCache starts an informer cache of the resources aggregated across a project and
+// searched by the admin endpoints, and waits for it to sync. Only the pods of applications are cached.
+func startResourceCache(ctx context.Context, cfg *rest.Config, scheme *runtime.Scheme) (cache.Cache, error) {
+	inProject, err := labels.NewRequirement(validation.LabelProjectUUID, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceCache, err := cache.New(cfg, cache.Options{
+		Scheme: scheme,
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Pod{}: {Label: labels.NewSelector().Add(*inProject)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Informers start on first use, register them up front so the first request finds them synced
+	for _, obj := range []client.Object{&v1alpha1.Application{}, &v1alpha1.Deployment{}, &v1alpha1.ApplicationDomain{}, &corev1.Pod{}} {
+		if _, err := resourceCache.GetInformer(ctx, obj); err != nil {
+			return nil, err
+		}
+	}
+
+	go func() {
+		if err := resourceCache.Start(ctx); err != nil {
+			log.Fatalf("Resource cache stopped: %v", err)
+		}
+	}()
+	if !resourceCache.WaitForCacheSync(ctx) {
+		return nil, fmt.Errorf("resource cache did not sync")
+	}
+	return resourceCache, nil
+}
@@ -0,0 +1,160 @@
+package apply
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/credentials"
+	"github.com/kibamail/kibaship/cmd/cli/internal/styles"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+// NewCommand creates and returns the apply command
+func NewCommand() *cobra.Command {
+	var (
+		file   string
+		apiURL string
+		apiKey string
+		dryRun bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply a kibaship.yaml manifest",
+		Long: "Create, update and delete the environments, applications, domains and environment " +
+			"variables of a project until they match a kibaship.yaml manifest.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file == "" {
+				PrintHelp()
+				return fmt.Errorf("a manifest is required, pass it with -f")
+			}
+			profile, _ := cmd.Flags().GetString("profile")
+			conn, err := credentials.Resolve(profile, apiURL, apiKey)
+			if err != nil {
+				return err
+			}
+
+			manifest, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read manifest: %w", err)
+			}
+
+			response, err := apply(conn.APIURL, conn.Token, manifest, dryRun)
+			if err != nil {
+				return err
+			}
+			printPlan(response)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Path of the kibaship.yaml manifest")
+	cmd.Flags().StringVar(&apiURL, "api-url", "", "URL of the kibaship API server (defaults to $KIBASHIP_API_URL, then the profile)")
+	cmd.Flags().StringVar(&apiKey, "api-key", "", "API key of the kibaship API server (defaults to $KIBASHIP_API_KEY, then the profile)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the change plan without applying it")
+
+	// Override help command behavior
+	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		PrintHelp()
+	})
+
+	return cmd
+}
+
+// apply sends the manifest to the API server and returns the change plan
+func apply(apiURL, apiKey string, manifest []byte, dryRun bool) (*models.ApplyResponse, error) {
+	url := strings.TrimSuffix(apiURL, "/") + "/v1/apply"
+	if dryRun {
+		url += "?dryRun=true"
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(manifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/yaml")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the API server: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp.StatusCode, body)
+	}
+
+	var response models.ApplyResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &response, nil
+}
+
+// apiError describes a failed apply, printing the changes made before it failed
+func apiError(status int, body []byte) error {
+	var failure struct {
+		Message string                   `json:"message"`
+		Changes []models.ApplyChange     `json:"changes"`
+		Errors  []models.ValidationError `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &failure); err != nil {
+		return fmt.Errorf("apply failed with status %d: %s", status, strings.TrimSpace(string(body)))
+	}
+
+	if len(failure.Errors) > 0 {
+		messages := make([]string, 0, len(failure.Errors))
+		for _, e := range failure.Errors {
+			messages = append(messages, fmt.Sprintf("  %s: %s", e.Field, e.Message))
+		}
+		return fmt.Errorf("invalid manifest:\n%s", strings.Join(messages, "\n"))
+	}
+
+	if len(failure.Changes) > 0 {
+		printPlan(&models.ApplyResponse{Changes: failure.Changes})
+	}
+	return fmt.Errorf("apply failed with status %d: %s", status, failure.Message)
+}
+
+// printPlan prints the changes of a plan, one per line
+func printPlan(response *models.ApplyResponse) {
+	if len(response.Changes) == 0 {
+		fmt.Println(styles.DescriptionStyle.Render("No changes, the project matches the manifest."))
+		return
+	}
+
+	symbols := map[models.ApplyAction]string{
+		models.ApplyActionCreate: "+",
+		models.ApplyActionUpdate: "~",
+		models.ApplyActionDelete: "-",
+	}
+	for _, change := range response.Changes {
+		line := fmt.Sprintf("%s %s %s", symbols[change.Action], change.Kind, change.Path)
+		if len(change.Fields) > 0 {
+			line += styles.DescriptionStyle.Render(" (" + strings.Join(change.Fields, ", ") + ")")
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Println()
+	if response.DryRun {
+		fmt.Println(styles.HelpStyle.Render(fmt.Sprintf("%d changes planned, run without --dry-run to apply them.", len(response.Changes))))
+		return
+	}
+	fmt.Println(styles.TitleStyle.Render(fmt.Sprintf("Applied %d changes.", len(response.Changes))))
+}
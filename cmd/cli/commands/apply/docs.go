@@ -0,0 +1,39 @@
+package apply
+
+import (
+	"fmt"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/styles"
+)
+
+// PrintHelp displays the help documentation for the apply command
+func PrintHelp() {
+	styles.PrintBanner()
+	fmt.Println(styles.TitleStyle.Render("🚀 Kibaship Apply"))
+	fmt.Println()
+	fmt.Println(styles.DescriptionStyle.Render("Converge a project to a kibaship.yaml manifest. Environments, applications and"))
+	fmt.Println(styles.DescriptionStyle.Render("custom domains the manifest leaves out are deleted."))
+	fmt.Println()
+	fmt.Println(styles.HelpStyle.Render("Usage:"))
+	fmt.Printf("  %s\n", styles.CommandStyle.Render("kibaship apply -f kibaship.yaml"))
+	fmt.Println()
+	fmt.Println(styles.HelpStyle.Render("Flags:"))
+
+	flags := []struct {
+		name        string
+		description string
+	}{
+		{"-f, --file", "Path of the kibaship.yaml manifest"},
+		{"--dry-run", "Print the change plan without applying it"},
+		{"--profile", "Profile of ~/.kibaship/config to use (defaults to the current profile)"},
+		{"--api-url", "URL of the kibaship API server (defaults to $KIBASHIP_API_URL, then the profile)"},
+		{"--api-key", "API key of the kibaship API server (defaults to $KIBASHIP_API_KEY, then the profile)"},
+		{"-h, --help", "Show help for any command"},
+	}
+
+	for _, flag := range flags {
+		fmt.Printf("  %s  %s\n",
+			styles.CommandStyle.Render(flag.name),
+			styles.DescriptionStyle.Render(flag.description))
+	}
+}
@@ -21,5 +21,7 @@ func NewCommand() *cobra.Command {
 		PrintHelp()
 	})
 
+	cmd.AddCommand(NewPreflightCommand())
+
 	return cmd
 }
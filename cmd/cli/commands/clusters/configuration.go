@@ -0,0 +1,153 @@
+package clusters
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Configuration is the cluster configuration file, see cmd/cli/configuration.yaml
+type Configuration struct {
+	State   StateConfiguration   `yaml:"state"`
+	Cluster ClusterConfiguration `yaml:"cluster"`
+}
+
+// StateConfiguration configures where the Terraform state is stored
+type StateConfiguration struct {
+	S3 *S3StateConfiguration `yaml:"s3"`
+}
+
+// S3StateConfiguration stores the Terraform state in an S3 bucket
+type S3StateConfiguration struct {
+	Bucket       string `yaml:"bucket"`
+	Region       string `yaml:"region"`
+	AccessKey    string `yaml:"access-key"`
+	AccessSecret string `yaml:"access-secret"`
+}
+
+// ClusterConfiguration configures the cluster and the provider it is created on
+type ClusterConfiguration struct {
+	Name         string                `yaml:"name"`
+	Email        string                `yaml:"email"`
+	PaasFeatures string                `yaml:"paas-features"`
+	Provider     ProviderConfiguration `yaml:"provider"`
+}
+
+// ProviderConfiguration holds the credentials of the provider, exactly one is set
+type ProviderConfiguration struct {
+	AWS          *AWSConfiguration          `yaml:"aws"`
+	DigitalOcean *DigitalOceanConfiguration `yaml:"digital-ocean"`
+	Hetzner      *HetznerConfiguration      `yaml:"hetzner"`
+	HetznerRobot *HetznerRobotConfiguration `yaml:"hetzner-robot"`
+	Linode       *LinodeConfiguration       `yaml:"linode"`
+	GCloud       *GCloudConfiguration       `yaml:"gcloud"`
+}
+
+// AWSConfiguration holds the AWS credentials
+type AWSConfiguration struct {
+	AccessKeyID     string `yaml:"access-key-id"`
+	SecretAccessKey string `yaml:"secret-access-key"`
+	Region          string `yaml:"region"`
+}
+
+// DigitalOceanConfiguration holds the DigitalOcean token and droplets
+type DigitalOceanConfiguration struct {
+	Token     string `yaml:"token"`
+	Nodes     string `yaml:"nodes"`
+	NodesSize string `yaml:"nodes-size"`
+	Region    string `yaml:"region"`
+}
+
+// HetznerConfiguration holds the Hetzner Cloud token
+type HetznerConfiguration struct {
+	Token string `yaml:"token"`
+}
+
+// HetznerRobotConfiguration holds the Hetzner Robot web service credentials and the Hetzner
+// Cloud token used next to the dedicated servers
+type HetznerRobotConfiguration struct {
+	Username   string `yaml:"username"`
+	Password   string `yaml:"password"`
+	CloudToken string `yaml:"cloud-token"`
+}
+
+// LinodeConfiguration holds the Linode token
+type LinodeConfiguration struct {
+	Token string `yaml:"token"`
+}
+
+// GCloudConfiguration holds the Google Cloud service account
+type GCloudConfiguration struct {
+	ServiceAccountKey string `yaml:"service-account-key"`
+	ProjectID         string `yaml:"project-id"`
+	Region            string `yaml:"region"`
+}
+
+// LoadConfiguration reads and validates the cluster configuration file at path
+func LoadConfiguration(path string) (*Configuration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the cluster configuration: %w", err)
+	}
+
+	cfg := &Configuration{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid cluster configuration %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid cluster configuration %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate checks the required settings are present
+func (c *Configuration) Validate() error {
+	if c.Cluster.Name == "" {
+		return fmt.Errorf("cluster.name is required")
+	}
+	if c.State.S3 == nil || c.State.S3.Bucket == "" || c.State.S3.Region == "" {
+		return fmt.Errorf("state.s3.bucket and state.s3.region are required")
+	}
+	if providers := c.Cluster.Provider.configured(); len(providers) != 1 {
+		return fmt.Errorf("exactly one of cluster.provider.aws, digital-ocean, hetzner, hetzner-robot, linode or gcloud must be set, got %d", len(providers))
+	}
+	if do := c.Cluster.Provider.DigitalOcean; do != nil && do.Nodes != "" {
+		if nodes, err := strconv.Atoi(do.Nodes); err != nil || nodes < 1 {
+			return fmt.Errorf("cluster.provider.digital-ocean.nodes must be a positive number")
+		}
+	}
+	return nil
+}
+
+// Name returns the name of the configured provider
+func (p ProviderConfiguration) Name() string {
+	if providers := p.configured(); len(providers) > 0 {
+		return providers[0]
+	}
+	return ""
+}
+
+func (p ProviderConfiguration) configured() []string {
+	var providers []string
+	if p.AWS != nil {
+		providers = append(providers, "aws")
+	}
+	if p.DigitalOcean != nil {
+		providers = append(providers, "digital-ocean")
+	}
+	if p.Hetzner != nil {
+		providers = append(providers, "hetzner")
+	}
+	if p.HetznerRobot != nil {
+		providers = append(providers, "hetzner-robot")
+	}
+	if p.Linode != nil {
+		providers = append(providers, "linode")
+	}
+	if p.GCloud != nil {
+		providers = append(providers, "gcloud")
+	}
+	return providers
+}
@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || freebsd)
+
+package clusters
+
+// freeDiskSpace cannot read the free disk space of this platform
+func freeDiskSpace(string) (uint64, error) {
+	return 0, errDiskSpaceUnsupported
+}
@@ -11,12 +11,17 @@ func PrintHelp() {
 	styles.PrintBanner()
 	fmt.Println(styles.TitleStyle.Render("🚀 Kibaship Clusters"))
 	fmt.Println()
-	fmt.Println(styles.DescriptionStyle.Render("Cluster management commands have been removed from this CLI."))
+	fmt.Println(styles.DescriptionStyle.Render("Cluster creation has been removed from this CLI, preflight still validates a cluster configuration."))
 	fmt.Println()
 	fmt.Println(styles.HelpStyle.Render("Available Commands:"))
-	fmt.Println(styles.DescriptionStyle.Render("  No cluster commands are currently available."))
+	fmt.Printf("  %s  %s\n",
+		styles.CommandStyle.Render("preflight"),
+		styles.DescriptionStyle.Render("Check the credentials, quota, DNS, tools, disk space and network a cluster configuration needs"))
 	fmt.Println()
 	fmt.Println(styles.HelpStyle.Render("Flags:"))
+	fmt.Printf("  %s  %s\n",
+		styles.CommandStyle.Render("-c, --configuration"),
+		styles.DescriptionStyle.Render("Path of the cluster configuration file of preflight (default configuration.yaml)"))
 	fmt.Printf("  %s  %s\n",
 		styles.CommandStyle.Render("-h, --help"),
 		styles.DescriptionStyle.Render("Show help for any command"))
@@ -0,0 +1,248 @@
+package clusters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/checks"
+	"github.com/kibamail/kibaship/cmd/cli/internal/styles"
+)
+
+const (
+	// minFreeDiskSpace is the space needed for the Terraform providers, state and generated configs
+	minFreeDiskSpace = 2 << 30
+	// networkTimeout bounds each reachability probe and provider API request
+	networkTimeout = 10 * time.Second
+)
+
+// requiredTools are the binaries the cluster creation runs, with their minimum versions
+var requiredTools = []struct {
+	name       string
+	args       []string
+	minVersion string
+}{
+	{"terraform", []string{"version"}, "1.5.0"},
+	{"talosctl", []string{"version", "--client"}, "1.8.0"},
+	{"kubectl", []string{"version", "--client"}, "1.30.0"},
+}
+
+var errDiskSpaceUnsupported = errors.New("free disk space cannot be read on this platform")
+
+// ProviderEndpoints are the base URLs of the provider APIs, overridden in tests
+type ProviderEndpoints struct {
+	AWSSTS       string
+	DigitalOcean string
+	HetznerCloud string
+	HetznerRobot string
+	Linode       string
+}
+
+// DefaultProviderEndpoints are the public provider APIs
+var DefaultProviderEndpoints = ProviderEndpoints{
+	AWSSTS:       "https://sts.amazonaws.com",
+	DigitalOcean: "https://api.digitalocean.com",
+	HetznerCloud: "https://api.hetzner.cloud",
+	HetznerRobot: "https://robot-ws.your-server.de",
+	Linode:       "https://api.linode.com",
+}
+
+// Preflight validates that a cluster can be created from a configuration before anything is
+// provisioned. The functions reaching outside the process can be replaced in tests.
+type Preflight struct {
+	Config     *Configuration
+	Endpoints  ProviderEndpoints
+	HTTPClient *http.Client
+	WorkDir    string
+
+	LookupNS      func(ctx context.Context, name string) ([]*net.NS, error)
+	Dial          func(ctx context.Context, network, address string) (net.Conn, error)
+	RunCommand    func(ctx context.Context, name string, args ...string) ([]byte, error)
+	FreeDiskSpace func(path string) (uint64, error)
+	Now           func() time.Time
+}
+
+// NewPreflight creates a preflight of cfg against the public provider APIs and the local machine
+func NewPreflight(cfg *Configuration, workDir string) *Preflight {
+	dialer := &net.Dialer{Timeout: networkTimeout}
+	return &Preflight{
+		Config:     cfg,
+		Endpoints:  DefaultProviderEndpoints,
+		HTTPClient: &http.Client{Timeout: networkTimeout},
+		WorkDir:    workDir,
+		LookupNS:   net.DefaultResolver.LookupNS,
+		Dial:       dialer.DialContext,
+		RunCommand: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			return exec.CommandContext(ctx, name, args...).CombinedOutput()
+		},
+		FreeDiskSpace: freeDiskSpace,
+		Now:           time.Now,
+	}
+}
+
+// Run runs every check and returns their results in order
+func (p *Preflight) Run(ctx context.Context) []checks.Result {
+	var results []checks.Result
+	results = append(results, p.checkCredentials(ctx)...)
+	results = append(results, p.checkQuota(ctx))
+	results = append(results, p.checkDomain(ctx))
+	for _, tool := range requiredTools {
+		results = append(results, p.checkTool(ctx, tool.name, tool.args, tool.minVersion))
+	}
+	results = append(results, p.checkDiskSpace())
+	results = append(results, p.checkNetwork(ctx)...)
+	return results
+}
+
+// checkDomain verifies the zone of the cluster domain is delegated to nameservers, the DNS
+// records of the cluster are created in it
+func (p *Preflight) checkDomain(ctx context.Context) checks.Result {
+	name := "DNS domain " + p.Config.Cluster.Name
+	labels := strings.Split(strings.TrimSuffix(p.Config.Cluster.Name, "."), ".")
+	for i := 0; i < len(labels)-1; i++ {
+		zone := strings.Join(labels[i:], ".")
+		nameservers, err := p.LookupNS(ctx, zone)
+		if err != nil || len(nameservers) == 0 {
+			continue
+		}
+		hosts := make([]string, 0, len(nameservers))
+		for _, ns := range nameservers {
+			hosts = append(hosts, strings.TrimSuffix(ns.Host, "."))
+		}
+		return checks.Result{Name: name, Status: checks.Pass, Detail: fmt.Sprintf("zone %s is served by %s", zone, strings.Join(hosts, ", "))}
+	}
+	return checks.Result{Name: name, Status: checks.Fail, Detail: "no nameservers found for the domain or its parent domains, delegate the zone before creating the cluster"}
+}
+
+// checkTool verifies a binary is installed in at least minVersion
+func (p *Preflight) checkTool(ctx context.Context, tool string, args []string, minVersion string) checks.Result {
+	name := tool + " version"
+	output, err := p.RunCommand(ctx, tool, args...)
+	if err != nil && len(output) == 0 {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: fmt.Sprintf("%s is not installed or not in PATH, %s or later is required", tool, minVersion)}
+	}
+	installed := checks.FindVersion(string(output))
+	if installed == "" {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: fmt.Sprintf("could not read the version of %s, %s or later is required", tool, minVersion)}
+	}
+	if checks.CompareVersions(installed, minVersion) < 0 {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: fmt.Sprintf("%s is installed, %s or later is required", installed, minVersion)}
+	}
+	return checks.Result{Name: name, Status: checks.Pass, Detail: installed}
+}
+
+// checkDiskSpace verifies the working directory has room for the Terraform providers and state
+func (p *Preflight) checkDiskSpace() checks.Result {
+	name := "Local disk space"
+	free, err := p.FreeDiskSpace(p.WorkDir)
+	if errors.Is(err, errDiskSpaceUnsupported) {
+		return checks.Result{Name: name, Status: checks.Skip, Detail: err.Error()}
+	}
+	if err != nil {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: err.Error()}
+	}
+	detail := fmt.Sprintf("%s free in %s, %s required", formatBytes(free), p.WorkDir, formatBytes(minFreeDiskSpace))
+	if free < minFreeDiskSpace {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: detail}
+	}
+	return checks.Result{Name: name, Status: checks.Pass, Detail: detail}
+}
+
+// checkNetwork verifies the provider APIs and the state bucket can be reached
+func (p *Preflight) checkNetwork(ctx context.Context) []checks.Result {
+	var endpoints []string
+	switch p.Config.Cluster.Provider.Name() {
+	case "aws":
+		endpoints = append(endpoints, p.Endpoints.AWSSTS)
+	case "digital-ocean":
+		endpoints = append(endpoints, p.Endpoints.DigitalOcean)
+	case "hetzner":
+		endpoints = append(endpoints, p.Endpoints.HetznerCloud)
+	case "hetzner-robot":
+		endpoints = append(endpoints, p.Endpoints.HetznerRobot, p.Endpoints.HetznerCloud)
+	case "linode":
+		endpoints = append(endpoints, p.Endpoints.Linode)
+	case "gcloud":
+		endpoints = append(endpoints, "https://compute.googleapis.com", "https://oauth2.googleapis.com")
+	}
+	endpoints = append(endpoints, fmt.Sprintf("https://s3.%s.amazonaws.com", p.Config.State.S3.Region))
+
+	results := make([]checks.Result, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		results = append(results, p.checkReachable(ctx, endpoint))
+	}
+	return results
+}
+
+func (p *Preflight) checkReachable(ctx context.Context, endpoint string) checks.Result {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return checks.Result{Name: "Reach " + endpoint, Status: checks.Fail, Detail: err.Error()}
+	}
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), "443")
+	}
+	name := "Reach " + u.Hostname()
+
+	ctx, cancel := context.WithTimeout(ctx, networkTimeout)
+	defer cancel()
+	conn, err := p.Dial(ctx, "tcp", address)
+	if err != nil {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: err.Error()}
+	}
+	_ = conn.Close()
+	return checks.Result{Name: name, Status: checks.Pass, Detail: address}
+}
+
+func formatBytes(bytes uint64) string {
+	return fmt.Sprintf("%.1f GiB", float64(bytes)/(1<<30))
+}
+
+// NewPreflightCommand creates the clusters preflight command
+func NewPreflightCommand() *cobra.Command {
+	var configPath string
+
+	cmd := &cobra.Command{
+		Use:   "preflight",
+		Short: "Check a cluster can be created from a configuration",
+		Long: "Validate the provider credentials, quota, DNS domain, terraform/talosctl/kubectl versions, " +
+			"local disk space and network reachability of the provider APIs before creating a cluster.",
+		// Failed checks are reported in the table, the usage would only bury them
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := LoadConfiguration(configPath)
+			if err != nil {
+				return err
+			}
+			workDir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(styles.TitleStyle.Render(fmt.Sprintf("Preflight checks of %s on %s", cfg.Cluster.Name, cfg.Cluster.Provider.Name())))
+			fmt.Println()
+			results := NewPreflight(cfg, workDir).Run(cmd.Context())
+			failed := checks.Print(os.Stdout, results)
+			fmt.Println()
+			if failed > 0 {
+				return fmt.Errorf("%d of %d preflight checks failed", failed, len(results))
+			}
+			fmt.Println(styles.TitleStyle.Render(fmt.Sprintf("All %d preflight checks passed.", len(results))))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&configPath, "configuration", "c", "configuration.yaml", "Path of the cluster configuration file")
+
+	return cmd
+}
@@ -0,0 +1,218 @@
+package clusters
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/checks"
+)
+
+// defaultDigitalOceanNodes is the number of droplets created when nodes is not configured
+const defaultDigitalOceanNodes = 3
+
+// checkCredentials verifies the provider and state credentials are accepted by their APIs
+func (p *Preflight) checkCredentials(ctx context.Context) []checks.Result {
+	provider := p.Config.Cluster.Provider
+	var results []checks.Result
+
+	switch provider.Name() {
+	case "aws":
+		results = append(results, p.checkAWSCredentials(ctx, "AWS credentials", provider.AWS.AccessKeyID, provider.AWS.SecretAccessKey))
+	case "digital-ocean":
+		results = append(results, p.checkAPICredentials(ctx, "DigitalOcean credentials", p.Endpoints.DigitalOcean+"/v2/account", bearer(provider.DigitalOcean.Token)))
+	case "hetzner":
+		results = append(results, p.checkAPICredentials(ctx, "Hetzner Cloud credentials", p.Endpoints.HetznerCloud+"/v1/servers?per_page=1", bearer(provider.Hetzner.Token)))
+	case "hetzner-robot":
+		robot := provider.HetznerRobot
+		results = append(results,
+			p.checkAPICredentials(ctx, "Hetzner Robot credentials", p.Endpoints.HetznerRobot+"/server", func(req *http.Request) {
+				req.SetBasicAuth(robot.Username, robot.Password)
+			}),
+			p.checkAPICredentials(ctx, "Hetzner Cloud credentials", p.Endpoints.HetznerCloud+"/v1/servers?per_page=1", bearer(robot.CloudToken)),
+		)
+	case "linode":
+		results = append(results, p.checkAPICredentials(ctx, "Linode credentials", p.Endpoints.Linode+"/v4/profile", bearer(provider.Linode.Token)))
+	case "gcloud":
+		results = append(results, checkGCloudServiceAccount(provider.GCloud))
+	}
+
+	state := p.Config.State.S3
+	results = append(results, p.checkAWSCredentials(ctx, "State bucket credentials", state.AccessKey, state.AccessSecret))
+	return results
+}
+
+// checkQuota verifies the provider account has room for the cluster nodes. Only DigitalOcean
+// reports its limits through the API the CLI uses.
+func (p *Preflight) checkQuota(ctx context.Context) checks.Result {
+	name := "Provider quota"
+	do := p.Config.Cluster.Provider.DigitalOcean
+	if do == nil {
+		return checks.Result{Name: name, Status: checks.Skip, Detail: fmt.Sprintf("%s quotas are checked by the provider when the cluster is created", p.Config.Cluster.Provider.Name())}
+	}
+
+	nodes := defaultDigitalOceanNodes
+	if do.Nodes != "" {
+		nodes, _ = strconv.Atoi(do.Nodes)
+	}
+
+	var account struct {
+		Account struct {
+			DropletLimit int `json:"droplet_limit"`
+		} `json:"account"`
+	}
+	if err := p.getJSON(ctx, p.Endpoints.DigitalOcean+"/v2/account", bearer(do.Token), &account); err != nil {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: err.Error()}
+	}
+	var droplets struct {
+		Meta struct {
+			Total int `json:"total"`
+		} `json:"meta"`
+	}
+	if err := p.getJSON(ctx, p.Endpoints.DigitalOcean+"/v2/droplets?per_page=1", bearer(do.Token), &droplets); err != nil {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: err.Error()}
+	}
+
+	detail := fmt.Sprintf("%d droplets in use, %d needed, limit %d", droplets.Meta.Total, nodes, account.Account.DropletLimit)
+	if droplets.Meta.Total+nodes > account.Account.DropletLimit {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: detail}
+	}
+	return checks.Result{Name: name, Status: checks.Pass, Detail: detail}
+}
+
+func (p *Preflight) checkAPICredentials(ctx context.Context, name, endpoint string, authorize func(*http.Request)) checks.Result {
+	status, err := p.get(ctx, endpoint, authorize)
+	switch {
+	case err != nil:
+		return checks.Result{Name: name, Status: checks.Fail, Detail: err.Error()}
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return checks.Result{Name: name, Status: checks.Fail, Detail: fmt.Sprintf("rejected by the API (HTTP %d)", status)}
+	// The Hetzner Robot answers 404 to an account without servers
+	case status < 300 || status == http.StatusNotFound:
+		return checks.Result{Name: name, Status: checks.Pass, Detail: "accepted by the API"}
+	default:
+		return checks.Result{Name: name, Status: checks.Fail, Detail: fmt.Sprintf("unexpected response from the API (HTTP %d)", status)}
+	}
+}
+
+// checkAWSCredentials calls STS GetCallerIdentity, which any valid access key may call
+func (p *Preflight) checkAWSCredentials(ctx context.Context, name, accessKeyID, secretAccessKey string) checks.Result {
+	if accessKeyID == "" || secretAccessKey == "" {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: "the access key and secret are required"}
+	}
+
+	endpoint := p.Endpoints.AWSSTS + "/?" + url.Values{
+		"Action":  {"GetCallerIdentity"},
+		"Version": {"2011-06-15"},
+	}.Encode()
+	return p.checkAPICredentials(ctx, name, endpoint, func(req *http.Request) {
+		signAWSRequest(req, accessKeyID, secretAccessKey, "us-east-1", "sts", p.Now())
+	})
+}
+
+// checkGCloudServiceAccount validates the service account key file. The key is not exchanged
+// for a token, so a revoked key is only detected when the cluster is created.
+func checkGCloudServiceAccount(cfg *GCloudConfiguration) checks.Result {
+	name := "Google Cloud credentials"
+	data, err := os.ReadFile(cfg.ServiceAccountKey)
+	if err != nil {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: fmt.Sprintf("failed to read the service account key: %v", err)}
+	}
+
+	var key struct {
+		Type        string `json:"type"`
+		ProjectID   string `json:"project_id"`
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+	}
+	if err := json.Unmarshal(data, &key); err != nil || key.Type != "service_account" || key.ClientEmail == "" || key.PrivateKey == "" {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: "the service account key is not a JSON key of a service account"}
+	}
+	if cfg.ProjectID != "" && key.ProjectID != cfg.ProjectID {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: fmt.Sprintf("the service account belongs to project %s, not %s", key.ProjectID, cfg.ProjectID)}
+	}
+	return checks.Result{Name: name, Status: checks.Pass, Detail: "valid key of " + key.ClientEmail}
+}
+
+func bearer(token string) func(*http.Request) {
+	return func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+func (p *Preflight) get(ctx context.Context, endpoint string, authorize func(*http.Request)) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	authorize(req)
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return resp.StatusCode, nil
+}
+
+func (p *Preflight) getJSON(ctx context.Context, endpoint string, authorize func(*http.Request), out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	authorize(req)
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response from the API (HTTP %d)", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// signAWSRequest signs a request without a body with AWS Signature Version 4
+func signAWSRequest(req *http.Request, accessKeyID, secretAccessKey, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	emptyPayload := sha256.Sum256(nil)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		req.URL.Query().Encode(),
+		"host:" + req.URL.Host,
+		"x-amz-date:" + amzDate,
+		"",
+		"host;x-amz-date",
+		hex.EncodeToString(emptyPayload[:]),
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	hashedRequest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(hashedRequest[:])}, "\n")
+
+	key := []byte("AWS4" + secretAccessKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=host;x-amz-date, Signature=%s", accessKeyID, scope, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,183 @@
+package clusters
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/checks"
+)
+
+const digitalOceanConfiguration = `
+state:
+  s3:
+    bucket: "state"
+    region: "eu-central-1"
+    access-key: "AKIDEXAMPLE"
+    access-secret: "secret"
+cluster:
+  name: "app.example.com"
+  email: "admin@example.com"
+  provider:
+    digital-ocean:
+      token: "do-token"
+      nodes: "3"
+      nodes-size: "s-4vcpu-8gb"
+      region: "nyc3"
+`
+
+func writeConfiguration(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "configuration.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfiguration(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg, err := LoadConfiguration(writeConfiguration(t, digitalOceanConfiguration))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Cluster.Provider.Name()).To(Equal("digital-ocean"))
+	g.Expect(cfg.Cluster.Provider.DigitalOcean.Token).To(Equal("do-token"))
+	g.Expect(cfg.State.S3.AccessKey).To(Equal("AKIDEXAMPLE"))
+
+	// The example configuration lists every provider
+	_, err = LoadConfiguration("../../configuration.yaml")
+	g.Expect(err).To(MatchError(ContainSubstring("exactly one of cluster.provider")))
+
+	_, err = LoadConfiguration(writeConfiguration(t, strings.Replace(digitalOceanConfiguration, `nodes: "3"`, `nodes: "three"`, 1)))
+	g.Expect(err).To(MatchError(ContainSubstring("nodes must be a positive number")))
+
+	_, err = LoadConfiguration(writeConfiguration(t, strings.Replace(digitalOceanConfiguration, `name: "app.example.com"`, `name: ""`, 1)))
+	g.Expect(err).To(MatchError(ContainSubstring("cluster.name is required")))
+}
+
+func TestPreflight(t *testing.T) {
+	g := NewWithT(t)
+
+	var stsAuthorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Query().Get("Action") == "GetCallerIdentity":
+			stsAuthorization = r.Header.Get("Authorization")
+			w.WriteHeader(http.StatusOK)
+		case r.Header.Get("Authorization") != "Bearer do-token":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v2/account":
+			_, _ = w.Write([]byte(`{"account":{"droplet_limit":10,"status":"active"}}`))
+		case r.URL.Path == "/v2/droplets":
+			_, _ = w.Write([]byte(`{"droplets":[],"meta":{"total":8}}`))
+		}
+	}))
+	defer server.Close()
+
+	cfg, err := LoadConfiguration(writeConfiguration(t, digitalOceanConfiguration))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	preflight := NewPreflight(cfg, "/work")
+	preflight.Endpoints = ProviderEndpoints{AWSSTS: server.URL, DigitalOcean: server.URL}
+	preflight.HTTPClient = server.Client()
+	preflight.Now = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+	preflight.LookupNS = func(_ context.Context, name string) ([]*net.NS, error) {
+		if name == "example.com" {
+			return []*net.NS{{Host: "ns1.example.net."}, {Host: "ns2.example.net."}}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	var dialed []string
+	preflight.Dial = func(_ context.Context, _, address string) (net.Conn, error) {
+		dialed = append(dialed, address)
+		if strings.HasPrefix(address, "s3.") {
+			return nil, errors.New("connection refused")
+		}
+		client, server := net.Pipe()
+		_ = server.Close()
+		return client, nil
+	}
+	preflight.RunCommand = func(_ context.Context, name string, _ ...string) ([]byte, error) {
+		switch name {
+		case "terraform":
+			return []byte("Terraform v1.9.5\non linux_amd64\n"), nil
+		case "talosctl":
+			return []byte("Client:\n\tTag:         v1.7.6\n"), nil
+		}
+		return nil, errors.New("executable file not found in $PATH")
+	}
+	preflight.FreeDiskSpace = func(string) (uint64, error) { return 10 << 30, nil }
+
+	results := preflight.Run(context.Background())
+	byName := map[string]checks.Result{}
+	for _, result := range results {
+		byName[result.Name] = result
+	}
+
+	g.Expect(byName["DigitalOcean credentials"].Status).To(Equal(checks.Pass))
+	g.Expect(byName["State bucket credentials"].Status).To(Equal(checks.Pass))
+	g.Expect(stsAuthorization).To(HavePrefix("AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20250102/us-east-1/sts/aws4_request, SignedHeaders=host;x-amz-date, Signature="))
+
+	// 8 droplets in use and 3 more needed exceed the limit of 10
+	g.Expect(byName["Provider quota"].Status).To(Equal(checks.Fail))
+	g.Expect(byName["Provider quota"].Detail).To(Equal("8 droplets in use, 3 needed, limit 10"))
+
+	g.Expect(byName["DNS domain app.example.com"].Status).To(Equal(checks.Pass))
+	g.Expect(byName["DNS domain app.example.com"].Detail).To(Equal("zone example.com is served by ns1.example.net, ns2.example.net"))
+
+	g.Expect(byName["terraform version"].Status).To(Equal(checks.Pass))
+	g.Expect(byName["talosctl version"].Status).To(Equal(checks.Fail))
+	g.Expect(byName["talosctl version"].Detail).To(Equal("v1.7.6 is installed, 1.8.0 or later is required"))
+	g.Expect(byName["kubectl version"].Status).To(Equal(checks.Fail))
+	g.Expect(byName["kubectl version"].Detail).To(ContainSubstring("not installed"))
+
+	g.Expect(byName["Local disk space"].Status).To(Equal(checks.Pass))
+	g.Expect(byName["Reach s3.eu-central-1.amazonaws.com"].Status).To(Equal(checks.Fail))
+	g.Expect(dialed).To(ContainElement("s3.eu-central-1.amazonaws.com:443"))
+
+	var out bytes.Buffer
+	g.Expect(checks.Print(&out, results)).To(Equal(4))
+	g.Expect(out.String()).To(ContainSubstring("Provider quota"))
+}
+
+func TestPreflightRejectedCredentials(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	cfg, err := LoadConfiguration(writeConfiguration(t, digitalOceanConfiguration))
+	g.Expect(err).NotTo(HaveOccurred())
+	preflight := NewPreflight(cfg, "/work")
+	preflight.Endpoints = ProviderEndpoints{AWSSTS: server.URL, DigitalOcean: server.URL}
+	preflight.HTTPClient = server.Client()
+
+	results := preflight.checkCredentials(context.Background())
+	g.Expect(results).To(HaveLen(2))
+	for _, result := range results {
+		g.Expect(result.Status).To(Equal(checks.Fail))
+		g.Expect(result.Detail).To(Equal("rejected by the API (HTTP 401)"))
+	}
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	g := NewWithT(t)
+
+	preflight := &Preflight{WorkDir: "/work"}
+	preflight.FreeDiskSpace = func(string) (uint64, error) { return 1 << 30, nil }
+	g.Expect(preflight.checkDiskSpace().Status).To(Equal(checks.Fail))
+
+	preflight.FreeDiskSpace = func(string) (uint64, error) { return 0, errDiskSpaceUnsupported }
+	g.Expect(preflight.checkDiskSpace().Status).To(Equal(checks.Skip))
+}
@@ -0,0 +1,65 @@
+package dashboard
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/api"
+	"github.com/kibamail/kibaship/cmd/cli/internal/completion"
+	"github.com/kibamail/kibaship/cmd/cli/internal/credentials"
+)
+
+// NewCommand creates and returns the dashboard command
+func NewCommand() *cobra.Command {
+	var (
+		project     string
+		application string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "dashboard",
+		Short: "Watch and deploy the applications of a project in the terminal",
+		Long: "Show the applications of a project with the live status of their deployments, and " +
+			"deploy, promote and read the logs of them without leaving the terminal.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile, _ := cmd.Flags().GetString("profile")
+			conn, err := credentials.Resolve(profile, "", "")
+			if err != nil {
+				return err
+			}
+			if project == "" {
+				project = conn.Project
+			}
+			if project == "" {
+				PrintHelp()
+				return fmt.Errorf("a project is required, pass it with --project or save one with kibaship login --project")
+			}
+
+			client := api.NewClient(conn)
+			found, err := client.GetProject(project)
+			if err != nil {
+				return err
+			}
+			completion.RememberProject(conn.Profile, found.Slug, found.Name)
+
+			m := newModel(client, found.UUID)
+			m.selected = application
+			_, err = tea.NewProgram(m, tea.WithAltScreen()).Run()
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&project, "project", "", "UUID or slug of the project (defaults to the project of the profile)")
+	cmd.Flags().StringVar(&application, "application", "", "Slug of the application selected first")
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects)
+	_ = cmd.RegisterFlagCompletionFunc("application", completion.Applications)
+
+	// Override help command behavior
+	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		PrintHelp()
+	})
+
+	return cmd
+}
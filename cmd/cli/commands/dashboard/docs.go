@@ -0,0 +1,58 @@
+package dashboard
+
+import (
+	"fmt"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/styles"
+)
+
+// PrintHelp displays the help documentation for the dashboard command
+func PrintHelp() {
+	styles.PrintBanner()
+	fmt.Println(styles.TitleStyle.Render("🚀 Kibaship Dashboard"))
+	fmt.Println()
+	fmt.Println(styles.DescriptionStyle.Render("Watch the applications of a project and the status of their deployments, refreshed"))
+	fmt.Println(styles.DescriptionStyle.Render("every few seconds, and deploy, promote or read their logs from the terminal."))
+	fmt.Println()
+	fmt.Println(styles.HelpStyle.Render("Usage:"))
+	fmt.Printf("  %s\n", styles.CommandStyle.Render("kibaship dashboard --project billing"))
+	fmt.Println()
+	fmt.Println(styles.HelpStyle.Render("Keys:"))
+
+	keys := []struct {
+		name        string
+		description string
+	}{
+		{"↑/↓, k/j", "Select an application"},
+		{"d", "Redeploy the source of the latest deployment"},
+		{"p", "Promote the latest deployment"},
+		{"l", "Show the logs of the last hour, esc goes back"},
+		{"r", "Refresh now"},
+		{"q", "Quit"},
+	}
+
+	for _, key := range keys {
+		fmt.Printf("  %s  %s\n",
+			styles.CommandStyle.Render(key.name),
+			styles.DescriptionStyle.Render(key.description))
+	}
+
+	fmt.Println()
+	fmt.Println(styles.HelpStyle.Render("Flags:"))
+
+	flags := []struct {
+		name        string
+		description string
+	}{
+		{"--project", "UUID or slug of the project (defaults to the project of the profile)"},
+		{"--application", "Slug of the application selected first"},
+		{"--profile", "Profile of ~/.kibaship/config to use (defaults to the current profile)"},
+		{"-h, --help", "Show help for any command"},
+	}
+
+	for _, flag := range flags {
+		fmt.Printf("  %s  %s\n",
+			styles.CommandStyle.Render(flag.name),
+			styles.DescriptionStyle.Render(flag.description))
+	}
+}
@@ -0,0 +1,356 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/api"
+	"github.com/kibamail/kibaship/cmd/cli/internal/styles"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+const (
+	// refreshInterval is how often the dashboard reloads the deployment status
+	refreshInterval = 5 * time.Second
+
+	// logLines is the number of log lines shown for an application
+	logLines = 200
+)
+
+// view is the screen the dashboard shows
+type view int
+
+const (
+	viewApplications view = iota
+	viewLogs
+)
+
+// client is the part of the API client the dashboard uses
+type client interface {
+	GetProject(uuid string) (*models.ProjectResponse, error)
+	GetApplicationsByProject(projectUUID string) ([]models.ApplicationResponse, error)
+	CreateDeployment(req *models.DeploymentCreateRequest) (*models.DeploymentResponse, error)
+	PromoteDeployment(deploymentUUID string) error
+	GetApplicationLogHistory(applicationUUID string, since time.Duration, limit int) (*models.ApplicationLogHistoryResponse, error)
+}
+
+var _ client = (*api.Client)(nil)
+
+type (
+	projectLoadedMsg struct {
+		project      *models.ProjectResponse
+		applications []models.ApplicationResponse
+		err          error
+	}
+	logsLoadedMsg struct {
+		entries []models.ApplicationLogEntry
+		err     error
+	}
+	actionDoneMsg struct {
+		status string
+		err    error
+	}
+	tickMsg time.Time
+)
+
+var (
+	selectedStyle = lipgloss.NewStyle().Foreground(styles.PrimaryColor).Bold(true)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444"))
+	phaseStyles   = map[models.DeploymentPhase]lipgloss.Style{
+		models.DeploymentPhaseSucceeded: lipgloss.NewStyle().Foreground(styles.PrimaryColor),
+		models.DeploymentPhaseRunning:   lipgloss.NewStyle().Foreground(styles.AccentColor),
+		models.DeploymentPhaseWaiting:   lipgloss.NewStyle().Foreground(styles.AccentColor),
+		models.DeploymentPhaseFailed:    errorStyle,
+	}
+)
+
+// model is the state of the dashboard
+type model struct {
+	client      client
+	projectUUID string
+	// selected is the slug of the application the cursor moves to once loaded
+	selected string
+
+	project      *models.ProjectResponse
+	applications []models.ApplicationResponse
+	cursor       int
+	view         view
+	logs         []models.ApplicationLogEntry
+
+	status string
+	err    error
+	height int
+}
+
+func newModel(c client, projectUUID string) model {
+	return model{client: c, projectUUID: projectUUID}
+}
+
+func (m model) Init() tea.Cmd {
+	return tea.Batch(m.load(), tick())
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+	case tickMsg:
+		return m, tea.Batch(m.load(), tick())
+	case projectLoadedMsg:
+		m.err = msg.err
+		if msg.err == nil {
+			m.project, m.applications = msg.project, msg.applications
+			for i, app := range m.applications {
+				if m.selected != "" && app.Slug == m.selected {
+					m.cursor, m.selected = i, ""
+				}
+			}
+			m.cursor = min(m.cursor, max(len(m.applications)-1, 0))
+		}
+	case logsLoadedMsg:
+		m.err = msg.err
+		m.logs = msg.entries
+	case actionDoneMsg:
+		m.status, m.err = msg.status, msg.err
+		return m, m.load()
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.view = viewApplications
+		return m, nil
+	case "r":
+		if m.view == viewLogs {
+			return m, m.loadLogs()
+		}
+		return m, m.load()
+	}
+
+	if m.view != viewApplications || len(m.applications) == 0 {
+		return m, nil
+	}
+	app := m.applications[m.cursor]
+	switch msg.String() {
+	case "up", "k":
+		m.cursor = max(m.cursor-1, 0)
+	case "down", "j":
+		m.cursor = min(m.cursor+1, len(m.applications)-1)
+	case "l":
+		m.view, m.logs = viewLogs, nil
+		return m, m.loadLogs()
+	case "d":
+		req, err := redeployRequest(&app)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.status, m.err = fmt.Sprintf("Deploying %s...", app.Name), nil
+		return m, m.deploy(req, app.Name)
+	case "p":
+		if app.LatestDeployment == nil {
+			m.err = fmt.Errorf("%s has no deployment to promote", app.Name)
+			return m, nil
+		}
+		m.status, m.err = fmt.Sprintf("Promoting %s...", app.LatestDeployment.Slug), nil
+		return m, m.promote(app.LatestDeployment, app.Name)
+	}
+	return m, nil
+}
+
+func (m model) View() string {
+	var b strings.Builder
+	title := "Kibaship dashboard"
+	if m.project != nil {
+		title += " · " + m.project.Name
+	}
+	b.WriteString(styles.TitleStyle.Render(title))
+	b.WriteString("\n\n")
+
+	if m.view == viewLogs {
+		m.renderLogs(&b)
+	} else {
+		m.renderApplications(&b)
+	}
+
+	b.WriteString("\n")
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(m.err.Error()))
+	} else if m.status != "" {
+		b.WriteString(styles.DescriptionStyle.Render(m.status))
+	}
+	b.WriteString("\n")
+	if m.view == viewLogs {
+		b.WriteString(styles.DescriptionStyle.Render("r refresh · esc back · q quit"))
+	} else {
+		b.WriteString(styles.DescriptionStyle.Render("↑/↓ select · d deploy · p promote · l logs · r refresh · q quit"))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// renderApplications lists the applications with the phase of their latest deployment
+func (m model) renderApplications(b *strings.Builder) {
+	if m.project == nil && m.err == nil {
+		b.WriteString(styles.DescriptionStyle.Render("Loading..."))
+		b.WriteString("\n")
+		return
+	}
+	if len(m.applications) == 0 {
+		b.WriteString(styles.DescriptionStyle.Render("No applications in this project."))
+		b.WriteString("\n")
+		return
+	}
+
+	fmt.Fprintf(b, "  %-24s %-18s %-12s %s\n", "APPLICATION", "TYPE", "STATUS", "LATEST DEPLOYMENT")
+	for i, app := range m.applications {
+		deployment := styles.DescriptionStyle.Render("none")
+		if latest := app.LatestDeployment; latest != nil {
+			phase := string(latest.Phase)
+			if style, ok := phaseStyles[latest.Phase]; ok {
+				phase = style.Render(phase)
+			}
+			deployment = fmt.Sprintf("%s %s %s", latest.Slug, phase,
+				styles.DescriptionStyle.Render(since(latest.CreatedAt)+" ago"))
+		}
+		row := fmt.Sprintf("%-24s %-18s %-12s ", truncate(app.Name, 24), app.Type, app.Status)
+		if i == m.cursor {
+			b.WriteString(selectedStyle.Render("> " + row))
+		} else {
+			b.WriteString("  " + row)
+		}
+		b.WriteString(deployment)
+		b.WriteString("\n")
+	}
+}
+
+// renderLogs shows the newest log lines of the selected application that fit the terminal
+func (m model) renderLogs(b *strings.Builder) {
+	if len(m.applications) > 0 {
+		b.WriteString(styles.CommandStyle.Render("Logs of " + m.applications[m.cursor].Name))
+		b.WriteString("\n")
+	}
+	if len(m.logs) == 0 {
+		b.WriteString(styles.DescriptionStyle.Render("No log lines in the last hour."))
+		b.WriteString("\n")
+		return
+	}
+
+	entries := m.logs
+	if m.height > 8 && len(entries) > m.height-8 {
+		entries = entries[:m.height-8]
+	}
+	// The API returns the newest lines first, print them in order
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		b.WriteString(styles.DescriptionStyle.Render(entry.Timestamp.Local().Format("15:04:05")))
+		b.WriteString(" ")
+		b.WriteString(entry.Line)
+		b.WriteString("\n")
+	}
+}
+
+func (m model) load() tea.Cmd {
+	c, projectUUID := m.client, m.projectUUID
+	return func() tea.Msg {
+		project, err := c.GetProject(projectUUID)
+		if err != nil {
+			return projectLoadedMsg{err: err}
+		}
+		applications, err := c.GetApplicationsByProject(project.UUID)
+		return projectLoadedMsg{project: project, applications: applications, err: err}
+	}
+}
+
+func (m model) loadLogs() tea.Cmd {
+	if len(m.applications) == 0 {
+		return nil
+	}
+	c, appUUID := m.client, m.applications[m.cursor].UUID
+	return func() tea.Msg {
+		history, err := c.GetApplicationLogHistory(appUUID, time.Hour, logLines)
+		if err != nil {
+			return logsLoadedMsg{err: err}
+		}
+		return logsLoadedMsg{entries: history.Entries}
+	}
+}
+
+func (m model) deploy(req *models.DeploymentCreateRequest, name string) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		deployment, err := c.CreateDeployment(req)
+		if err != nil {
+			return actionDoneMsg{err: err}
+		}
+		return actionDoneMsg{status: fmt.Sprintf("Started deployment %s of %s", deployment.Slug, name)}
+	}
+}
+
+func (m model) promote(deployment *models.DeploymentResponse, name string) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		if err := c.PromoteDeployment(deployment.UUID); err != nil {
+			return actionDoneMsg{err: err}
+		}
+		return actionDoneMsg{status: fmt.Sprintf("Promoted deployment %s of %s", deployment.Slug, name)}
+	}
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(refreshInterval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// redeployRequest builds a deployment of the source the latest deployment of an application
+// used, the commit for git applications and the tag for registry images
+func redeployRequest(app *models.ApplicationResponse) (*models.DeploymentCreateRequest, error) {
+	req := &models.DeploymentCreateRequest{ApplicationUUID: app.UUID}
+	switch app.Type {
+	case models.ApplicationTypeDockerImage:
+		return req, nil
+	case models.ApplicationTypeGitRepository, models.ApplicationTypeImageFromRegistry:
+		latest := app.LatestDeployment
+		if latest == nil {
+			return nil, fmt.Errorf("%s has no deployment to redeploy, deploy a first version with the API", app.Name)
+		}
+		req.GitRepository = latest.GitRepository
+		req.ImageFromRegistry = latest.ImageFromRegistry
+		return req, nil
+	default:
+		return nil, fmt.Errorf("%s applications are not deployed", app.Type)
+	}
+}
+
+// since formats the time elapsed since t, rounded for display
+func since(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// truncate shortens s to n runes
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
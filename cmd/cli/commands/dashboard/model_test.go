@@ -0,0 +1,89 @@
+package dashboard
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	. "github.com/onsi/gomega"
+
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+type fakeClient struct {
+	applications []models.ApplicationResponse
+	deployed     []*models.DeploymentCreateRequest
+	promoted     []string
+}
+
+func (c *fakeClient) GetProject(uuid string) (*models.ProjectResponse, error) {
+	return &models.ProjectResponse{UUID: "project-1", Name: "billing"}, nil
+}
+
+func (c *fakeClient) GetApplicationsByProject(projectUUID string) ([]models.ApplicationResponse, error) {
+	return c.applications, nil
+}
+
+func (c *fakeClient) CreateDeployment(req *models.DeploymentCreateRequest) (*models.DeploymentResponse, error) {
+	c.deployed = append(c.deployed, req)
+	return &models.DeploymentResponse{UUID: "deployment-2", Slug: "dep2"}, nil
+}
+
+func (c *fakeClient) PromoteDeployment(deploymentUUID string) error {
+	c.promoted = append(c.promoted, deploymentUUID)
+	return nil
+}
+
+func (c *fakeClient) GetApplicationLogHistory(applicationUUID string, since time.Duration, limit int) (*models.ApplicationLogHistoryResponse, error) {
+	return &models.ApplicationLogHistoryResponse{Entries: []models.ApplicationLogEntry{
+		{Timestamp: time.Now(), Line: "GET /healthz 200"},
+	}}, nil
+}
+
+// press sends a key to the model and runs the command it returns
+func press(m tea.Model, key string) tea.Model {
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	if cmd != nil {
+		m, _ = m.Update(cmd())
+	}
+	return m
+}
+
+func TestDashboard(t *testing.T) {
+	g := NewWithT(t)
+
+	c := &fakeClient{applications: []models.ApplicationResponse{
+		{UUID: "app-1", Name: "postgres", Type: models.ApplicationTypePostgres, Status: "Running"},
+		{UUID: "app-2", Name: "api", Type: models.ApplicationTypeGitRepository, Status: "Running",
+			LatestDeployment: &models.DeploymentResponse{
+				UUID:          "deployment-1",
+				Slug:          "dep1",
+				Phase:         models.DeploymentPhaseSucceeded,
+				GitRepository: &models.GitRepositoryDeploymentConfig{CommitSHA: "abc123", Branch: "main"},
+				CreatedAt:     time.Now(),
+			}},
+	}}
+
+	var m tea.Model = newModel(c, "billing")
+	m, _ = m.Update(m.(model).load()())
+	g.Expect(m.View()).To(ContainSubstring("Kibaship dashboard · billing"))
+	g.Expect(m.View()).To(ContainSubstring("dep1"))
+
+	// Databases are not deployed
+	m = press(m, "d")
+	g.Expect(m.(model).err).To(MatchError(ContainSubstring("Postgres applications are not deployed")))
+
+	m = press(m, "j")
+	m = press(m, "d")
+	g.Expect(c.deployed).To(HaveLen(1))
+	g.Expect(c.deployed[0].ApplicationUUID).To(Equal("app-2"))
+	g.Expect(c.deployed[0].GitRepository.CommitSHA).To(Equal("abc123"))
+
+	m = press(m, "p")
+	g.Expect(c.promoted).To(Equal([]string{"deployment-1"}))
+	g.Expect(m.View()).To(ContainSubstring("Promoted deployment dep1 of api"))
+
+	m = press(m, "l")
+	g.Expect(m.View()).To(ContainSubstring("Logs of api"))
+	g.Expect(m.View()).To(ContainSubstring("GET /healthz 200"))
+}
@@ -0,0 +1,265 @@
+package install
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/version"
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+const (
+	// fieldManager owns the fields applied by the install
+	fieldManager = "kibaship-cli"
+
+	// pollInterval is how often the readiness of CRDs and deployments is checked
+	pollInterval = 5 * time.Second
+)
+
+// Prerequisite is a component the platform needs, installed from its release manifest when
+// its API is missing from the cluster
+type Prerequisite struct {
+	Name string
+	// GroupVersion is the API whose presence means the component is installed
+	GroupVersion string
+	ManifestURL  string
+	// Deployments are waited for, as namespace/name, before the platform is applied
+	Deployments []string
+}
+
+// Prerequisites returns the components installed before the platform. The Gateway API CRDs
+// are only needed when applications are routed through the Gateway API.
+func Prerequisites(ingressProvider config.IngressProvider) []Prerequisite {
+	prerequisites := []Prerequisite{
+		{
+			Name:         "cert-manager",
+			GroupVersion: "cert-manager.io/v1",
+			ManifestURL: fmt.Sprintf("https://github.com/cert-manager/cert-manager/releases/download/v%s/cert-manager.yaml",
+				version.GetComponentVersion("cert-manager")),
+			Deployments: []string{"cert-manager/cert-manager", "cert-manager/cert-manager-cainjector", "cert-manager/cert-manager-webhook"},
+		},
+		{
+			Name:         "Tekton Pipelines",
+			GroupVersion: "tekton.dev/v1",
+			ManifestURL: fmt.Sprintf("https://storage.googleapis.com/tekton-releases/pipeline/previous/v%s/release.yaml",
+				version.GetComponentVersion("tekton-pipelines")),
+			Deployments: []string{"tekton-pipelines/tekton-pipelines-controller", "tekton-pipelines/tekton-pipelines-webhook"},
+		},
+	}
+	if ingressProvider.UsesGatewayAPI() {
+		prerequisites = append(prerequisites, Prerequisite{
+			Name:         "Gateway API",
+			GroupVersion: "gateway.networking.k8s.io/v1",
+			ManifestURL: fmt.Sprintf("https://github.com/kubernetes-sigs/gateway-api/releases/download/v%s/experimental-install.yaml",
+				version.GetComponentVersion("gateway-api")),
+		})
+	}
+	return prerequisites
+}
+
+// platformDeployments are waited for once the platform manifest is applied
+var platformDeployments = []string{
+	config.OperatorNamespace + "/kibaship-controller-manager",
+	config.OperatorNamespace + "/apiserver",
+}
+
+// Installed reports whether the API of the prerequisite is served by the cluster
+func (p Prerequisite) Installed(clientset kubernetes.Interface) bool {
+	_, err := clientset.Discovery().ServerResourcesForGroupVersion(p.GroupVersion)
+	return err == nil
+}
+
+// ReadManifest reads a manifest from an http(s) URL or a local file
+func ReadManifest(ctx context.Context, source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "https://") && !strings.HasPrefix(source, "http://") {
+		return os.ReadFile(source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", source, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: HTTP %d", source, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// DecodeManifest splits a multi-document manifest into objects, ordered so the namespaces and
+// CRDs exist before the objects using them
+func DecodeManifest(data []byte) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	var objects []*unstructured.Unstructured
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("invalid manifest: %w", err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetKind() == "" || obj.GetAPIVersion() == "" {
+			return nil, fmt.Errorf("invalid manifest: object %q has no apiVersion or kind", obj.GetName())
+		}
+		if obj.IsList() {
+			if err := obj.EachListItem(func(item runtime.Object) error {
+				objects = append(objects, item.(*unstructured.Unstructured))
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		objects = append(objects, obj)
+	}
+
+	sort.SliceStable(objects, func(i, j int) bool {
+		return applyPriority(objects[i]) < applyPriority(objects[j])
+	})
+	return objects, nil
+}
+
+func applyPriority(obj *unstructured.Unstructured) int {
+	switch obj.GetKind() {
+	case "Namespace":
+		return 0
+	case "CustomResourceDefinition":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Apply server-side applies the objects. Objects whose kind is not served yet, because their
+// CRD was applied moments before, are retried until timeout.
+func Apply(ctx context.Context, c client.Client, objects []*unstructured.Unstructured, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for _, obj := range objects {
+		obj.SetManagedFields(nil)
+		obj.SetResourceVersion("")
+		for {
+			err := c.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership)
+			if err == nil {
+				break
+			}
+			if !retryable(err) || time.Now().After(deadline) {
+				return fmt.Errorf("failed to apply %s %s: %w", obj.GetKind(), client.ObjectKeyFromObject(obj), err)
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(pollInterval):
+			}
+		}
+	}
+	return nil
+}
+
+// retryable reports errors of objects applied before their CRD is established or their
+// admission webhook is ready
+func retryable(err error) bool {
+	if client.IgnoreNotFound(err) == nil || apierrors.IsServiceUnavailable(err) || apierrors.IsInternalError(err) {
+		return true
+	}
+	return meta.IsNoMatchError(err) || strings.Contains(err.Error(), "failed calling webhook")
+}
+
+// EnsureOperatorConfigMap creates the operator ConfigMap unless one exists, an existing
+// ConfigMap holds the settings of an earlier install and is left alone
+func EnsureOperatorConfigMap(ctx context.Context, clientset kubernetes.Interface, cm *corev1.ConfigMap) (bool, error) {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: cm.Namespace}}
+	if _, err := clientset.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return false, fmt.Errorf("failed to create namespace %s: %w", cm.Namespace, err)
+	}
+
+	_, err := clientset.CoreV1().ConfigMaps(cm.Namespace).Create(ctx, cm, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create ConfigMap %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+	return true, nil
+}
+
+// WaitForAPI waits until the cluster serves the API group version
+func WaitForAPI(ctx context.Context, clientset kubernetes.Interface, groupVersion string, timeout time.Duration) error {
+	return poll(ctx, timeout, func() (bool, error) {
+		_, err := clientset.Discovery().ServerResourcesForGroupVersion(groupVersion)
+		return err == nil, nil
+	}, "API "+groupVersion)
+}
+
+// WaitForDeployments waits until every deployment, as namespace/name, is Available
+func WaitForDeployments(ctx context.Context, clientset kubernetes.Interface, deployments []string, timeout time.Duration) error {
+	for _, ref := range deployments {
+		namespace, name, _ := strings.Cut(ref, "/")
+		err := poll(ctx, timeout, func() (bool, error) {
+			deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			for _, condition := range deployment.Status.Conditions {
+				if condition.Type == appsv1.DeploymentAvailable && condition.Status == corev1.ConditionTrue {
+					return true, nil
+				}
+			}
+			return false, nil
+		}, "deployment "+ref)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func poll(ctx context.Context, timeout time.Duration, done func() (bool, error), what string) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, err := done()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for %s", timeout, what)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/checks"
+)
+
+const (
+	// minKubernetesVersion is the oldest Kubernetes release the platform is tested on
+	minKubernetesVersion = "1.30.0"
+
+	// defaultStorageClassAnnotation marks the default StorageClass of a cluster
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+)
+
+// cloudProviderIDPrefixes are the node provider IDs of clouds whose controller manager provisions
+// load balancers for LoadBalancer Services
+var cloudProviderIDPrefixes = []string{"aws://", "gce://", "azure://", "digitalocean://", "hcloud://", "linode://", "oci://", "vultr://"}
+
+// loadBalancerAPIs are the API groups of in-cluster load balancer IPAM implementations
+var loadBalancerAPIs = map[string]string{
+	"metallb.io/v1beta1": "MetalLB",
+	"cilium.io/v2alpha1": "Cilium LB-IPAM",
+}
+
+// CheckCompatibility checks the cluster can run the platform
+func CheckCompatibility(ctx context.Context, clientset kubernetes.Interface) ([]checks.Result, *storagev1.StorageClass) {
+	storageResult, defaultClass := checkDefaultStorageClass(ctx, clientset)
+	return []checks.Result{
+		checkKubernetesVersion(clientset),
+		storageResult,
+		checkLoadBalancer(ctx, clientset),
+	}, defaultClass
+}
+
+func checkKubernetesVersion(clientset kubernetes.Interface) checks.Result {
+	name := "Kubernetes version"
+	info, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: fmt.Sprintf("failed to read the server version: %v", err)}
+	}
+	if checks.CompareVersions(info.GitVersion, minKubernetesVersion) < 0 {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: fmt.Sprintf("%s is running, %s or later is required", info.GitVersion, minKubernetesVersion)}
+	}
+	return checks.Result{Name: name, Status: checks.Pass, Detail: info.GitVersion}
+}
+
+// checkDefaultStorageClass finds the default StorageClass the platform volumes are provisioned with
+func checkDefaultStorageClass(ctx context.Context, clientset kubernetes.Interface) (checks.Result, *storagev1.StorageClass) {
+	name := "Default storage class"
+	classes, err := clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: fmt.Sprintf("failed to list storage classes: %v", err)}, nil
+	}
+	for i := range classes.Items {
+		class := &classes.Items[i]
+		if class.Annotations[defaultStorageClassAnnotation] == "true" {
+			return checks.Result{Name: name, Status: checks.Pass, Detail: fmt.Sprintf("%s (%s)", class.Name, class.Provisioner)}, class
+		}
+	}
+	return checks.Result{Name: name, Status: checks.Fail, Detail: "no storage class is marked as the default, annotate one with " + defaultStorageClassAnnotation + "=true"}, nil
+}
+
+// checkLoadBalancer looks for something assigning addresses to LoadBalancer Services, the
+// ingress of the platform is exposed through one
+func checkLoadBalancer(ctx context.Context, clientset kubernetes.Interface) checks.Result {
+	name := "Load balancer"
+
+	for groupVersion, implementation := range loadBalancerAPIs {
+		if _, err := clientset.Discovery().ServerResourcesForGroupVersion(groupVersion); err == nil {
+			return checks.Result{Name: name, Status: checks.Pass, Detail: implementation + " is installed"}
+		}
+	}
+
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: fmt.Sprintf("failed to list nodes: %v", err)}
+	}
+	for _, node := range nodes.Items {
+		for _, prefix := range cloudProviderIDPrefixes {
+			if strings.HasPrefix(node.Spec.ProviderID, prefix) {
+				return checks.Result{Name: name, Status: checks.Pass, Detail: "provisioned by the " + strings.TrimSuffix(prefix, "://") + " cloud controller"}
+			}
+		}
+	}
+
+	services, err := clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return checks.Result{Name: name, Status: checks.Fail, Detail: fmt.Sprintf("failed to list services: %v", err)}
+	}
+	for _, service := range services.Items {
+		if len(service.Status.LoadBalancer.Ingress) > 0 {
+			return checks.Result{Name: name, Status: checks.Pass, Detail: fmt.Sprintf("service %s/%s has a load balancer address", service.Namespace, service.Name)}
+		}
+	}
+
+	return checks.Result{Name: name, Status: checks.Fail, Detail: "no cloud load balancer, MetalLB or Cilium LB-IPAM found, LoadBalancer services would stay pending"}
+}
@@ -0,0 +1,170 @@
+package install
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/checks"
+	"github.com/kibamail/kibaship/cmd/cli/internal/styles"
+	"github.com/kibamail/kibaship/cmd/cli/internal/version"
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+const (
+	// applyTimeout bounds the retries of objects applied before their CRD or webhook is ready
+	applyTimeout = 5 * time.Minute
+	// rolloutTimeout bounds the wait for the deployments of a component to become available
+	rolloutTimeout = 10 * time.Minute
+)
+
+// NewCommand creates and returns the install command
+func NewCommand() *cobra.Command {
+	var (
+		kubeconfig  string
+		kubeContext string
+		manifest    string
+		checkOnly   bool
+		force       bool
+		opts        Options
+	)
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install kibaship on an existing Kubernetes cluster",
+		Long: "Check an existing cluster can run kibaship, install cert-manager, Tekton Pipelines and the " +
+			"Gateway API CRDs when they are missing, then install the operator, API server, registry and " +
+			"BuildKit. No servers are provisioned.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Domain == "" || opts.ACMEEmail == "" {
+				PrintHelp()
+				return fmt.Errorf("the domain and ACME email are required, pass them with --domain and --acme-email")
+			}
+			ingressProvider, err := config.ParseIngressProvider(opts.IngressProvider)
+			if err != nil {
+				return err
+			}
+			if manifest == "" {
+				manifest = fmt.Sprintf("https://github.com/kibamail/kibaship/releases/download/%s/install.yaml", version.GetVersion())
+			}
+
+			rules := clientcmd.NewDefaultClientConfigLoadingRules()
+			rules.ExplicitPath = kubeconfig
+			restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: kubeContext}).ClientConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load the kubeconfig: %w", err)
+			}
+			clientset, err := kubernetes.NewForConfig(restConfig)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+
+			fmt.Println(styles.TitleStyle.Render("Compatibility checks of " + restConfig.Host))
+			fmt.Println()
+			results, defaultClass := CheckCompatibility(ctx, clientset)
+			failed := checks.Print(os.Stdout, results)
+			fmt.Println()
+			if failed > 0 && !force {
+				return fmt.Errorf("%d of %d compatibility checks failed, fix them or pass --force to install anyway", failed, len(results))
+			}
+
+			operatorConfig, err := opts.OperatorConfigMap(defaultClass)
+			if err != nil {
+				return err
+			}
+			if checkOnly {
+				return nil
+			}
+
+			c, err := client.New(restConfig, client.Options{})
+			if err != nil {
+				return err
+			}
+
+			for _, prerequisite := range Prerequisites(ingressProvider) {
+				if prerequisite.Installed(clientset) {
+					fmt.Println(styles.DescriptionStyle.Render(prerequisite.Name + " is already installed, leaving it alone."))
+					continue
+				}
+				fmt.Println(styles.TitleStyle.Render("Installing " + prerequisite.Name))
+				if err := applyManifest(cmd, c, prerequisite.ManifestURL); err != nil {
+					return err
+				}
+				if err := WaitForAPI(ctx, clientset, prerequisite.GroupVersion, rolloutTimeout); err != nil {
+					return err
+				}
+				if err := WaitForDeployments(ctx, clientset, prerequisite.Deployments, rolloutTimeout); err != nil {
+					return err
+				}
+			}
+
+			created, err := EnsureOperatorConfigMap(ctx, clientset, operatorConfig)
+			if err != nil {
+				return err
+			}
+			if created {
+				fmt.Println(styles.DescriptionStyle.Render(fmt.Sprintf("Created the operator configuration %s/%s.", operatorConfig.Namespace, operatorConfig.Name)))
+			} else {
+				fmt.Println(styles.DescriptionStyle.Render(fmt.Sprintf("Keeping the existing operator configuration %s/%s.", operatorConfig.Namespace, operatorConfig.Name)))
+			}
+
+			fmt.Println(styles.TitleStyle.Render("Installing kibaship from " + manifest))
+			if err := applyManifest(cmd, c, manifest); err != nil {
+				return err
+			}
+			if err := WaitForDeployments(ctx, clientset, platformDeployments, rolloutTimeout); err != nil {
+				return err
+			}
+
+			fmt.Println()
+			fmt.Println(styles.TitleStyle.Render("Kibaship is installed."))
+			fmt.Println(styles.DescriptionStyle.Render(fmt.Sprintf("The operator is now provisioning ingress and certificates, follow its progress in the %s/%s ConfigMap.",
+				config.OperatorNamespace, config.BootstrapStatusConfigMapName)))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "Path of the kubeconfig of the cluster (defaults to $KUBECONFIG, then ~/.kube/config)")
+	cmd.Flags().StringVar(&kubeContext, "context", "", "Context of the kubeconfig to use (defaults to the current context)")
+	cmd.Flags().StringVar(&manifest, "manifest", "", "URL or path of the kibaship install.yaml (defaults to the release of this CLI)")
+	cmd.Flags().StringVar(&opts.Domain, "domain", "", "Domain the applications are served under")
+	cmd.Flags().StringVar(&opts.ACMEEmail, "acme-email", "", "Email of the Let's Encrypt account")
+	cmd.Flags().StringVar(&opts.ACMEEnv, "acme-env", "", "Let's Encrypt environment, production or staging (defaults to production)")
+	cmd.Flags().StringVar(&opts.IngressProvider, "ingress-provider", "", "How traffic is routed: gateway, nginx or traefik (defaults to gateway)")
+	cmd.Flags().StringVar(&opts.GatewayClassName, "gateway-class", "", "GatewayClass of the kibaship Gateway, required with the gateway provider")
+	cmd.Flags().StringVar(&opts.WebhookURL, "webhook-url", "", "URL the platform events are delivered to")
+	cmd.Flags().BoolVar(&checkOnly, "check-only", false, "Only run the compatibility checks")
+	cmd.Flags().BoolVar(&force, "force", false, "Install even when compatibility checks fail")
+
+	// Override help command behavior
+	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		PrintHelp()
+	})
+
+	return cmd
+}
+
+// applyManifest reads, decodes and applies a manifest
+func applyManifest(cmd *cobra.Command, c client.Client, source string) error {
+	data, err := ReadManifest(cmd.Context(), source)
+	if err != nil {
+		return err
+	}
+	objects, err := DecodeManifest(data)
+	if err != nil {
+		return err
+	}
+	if err := Apply(cmd.Context(), c, objects, applyTimeout); err != nil {
+		return err
+	}
+	fmt.Println(styles.DescriptionStyle.Render(fmt.Sprintf("Applied %d objects.", len(objects))))
+	return nil
+}
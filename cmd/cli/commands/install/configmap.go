@@ -0,0 +1,70 @@
+package install
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// Options are the settings of the operator ConfigMap written by the install
+type Options struct {
+	Domain           string
+	ACMEEmail        string
+	ACMEEnv          string
+	IngressProvider  string
+	GatewayClassName string
+	WebhookURL       string
+}
+
+// OperatorConfigMap renders the operator ConfigMap. Without Longhorn the platform storage
+// classes are provisioned by the driver of the default storage class of the cluster.
+// The ConfigMap is validated the way the operator reads it.
+func (o Options) OperatorConfigMap(defaultClass *storagev1.StorageClass) (*corev1.ConfigMap, error) {
+	data := map[string]string{
+		config.ConfigKeyDomain:    o.Domain,
+		config.ConfigKeyACMEEmail: o.ACMEEmail,
+	}
+	setIfNotEmpty(data, config.ConfigKeyACMEEnv, o.ACMEEnv)
+	setIfNotEmpty(data, config.ConfigKeyIngressProvider, o.IngressProvider)
+	setIfNotEmpty(data, config.ConfigKeyGatewayClassName, o.GatewayClassName)
+	setIfNotEmpty(data, config.ConfigKeyWebhookURL, o.WebhookURL)
+
+	if defaultClass != nil && defaultClass.Provisioner != config.LonghornProvisioner {
+		classes := []config.StorageClassConfig{
+			{Name: config.StorageClassReplica1, Provisioner: defaultClass.Provisioner, Parameters: defaultClass.Parameters},
+			{Name: config.StorageClassReplica2, Provisioner: defaultClass.Provisioner, Parameters: defaultClass.Parameters},
+		}
+		encoded, err := yaml.Marshal(classes)
+		if err != nil {
+			return nil, err
+		}
+		data[config.ConfigKeyStorageClasses] = string(encoded)
+	}
+
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.OperatorConfigMapName,
+			Namespace: config.OperatorNamespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "kibaship",
+			},
+		},
+		Data: data,
+	}
+	if _, err := config.ParseOperatorConfiguration(cm); err != nil {
+		return nil, fmt.Errorf("invalid installation settings: %w", err)
+	}
+	return cm, nil
+}
+
+func setIfNotEmpty(data map[string]string, key, value string) {
+	if value != "" {
+		data[key] = value
+	}
+}
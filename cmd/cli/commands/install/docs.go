@@ -0,0 +1,81 @@
+package install
+
+import (
+	"fmt"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/styles"
+)
+
+// PrintHelp displays the help documentation for the install command
+func PrintHelp() {
+	styles.PrintBanner()
+	fmt.Println(styles.TitleStyle.Render("🚀 Kibaship Install"))
+	fmt.Println()
+	fmt.Println(styles.DescriptionStyle.Render("Install kibaship on an existing Kubernetes cluster. The Kubernetes version, default"))
+	fmt.Println(styles.DescriptionStyle.Render("storage class and load balancer are checked first, cert-manager, Tekton Pipelines and"))
+	fmt.Println(styles.DescriptionStyle.Render("the Gateway API CRDs are installed when missing."))
+	fmt.Println()
+	fmt.Println(styles.HelpStyle.Render("Usage:"))
+	fmt.Printf("  %s\n", styles.CommandStyle.Render("kibaship install --domain apps.example.com --acme-email ops@example.com --gateway-class cilium"))
+	fmt.Println()
+	fmt.Println(styles.HelpStyle.Render("Flags:"))
+
+	flags := []struct {
+		name        string
+		description string
+	}{
+		{"--domain", "Domain the applications are served under"},
+		{"--acme-email", "Email of the Let's Encrypt account"},
+		{"--acme-env", "Let's Encrypt environment, production or staging (defaults to production)"},
+		{"--ingress-provider", "How traffic is routed: gateway, nginx or traefik (defaults to gateway)"},
+		{"--gateway-class", "GatewayClass of the kibaship Gateway, required with the gateway provider"},
+		{"--webhook-url", "URL the platform events are delivered to"},
+		{"--kubeconfig", "Path of the kubeconfig of the cluster (defaults to $KUBECONFIG, then ~/.kube/config)"},
+		{"--context", "Context of the kubeconfig to use (defaults to the current context)"},
+		{"--manifest", "URL or path of the kibaship install.yaml (defaults to the release of this CLI)"},
+		{"--check-only", "Only run the compatibility checks"},
+		{"--force", "Install even when compatibility checks fail"},
+		{"-h, --help", "Show help for any command"},
+	}
+
+	for _, flag := range flags {
+		fmt.Printf("  %s  %s\n",
+			styles.CommandStyle.Render(flag.name),
+			styles.DescriptionStyle.Render(flag.description))
+	}
+}
+
+// PrintUninstallHelp displays the help documentation for the uninstall command
+func PrintUninstallHelp() {
+	styles.PrintBanner()
+	fmt.Println(styles.TitleStyle.Render("🧹 Kibaship Uninstall"))
+	fmt.Println()
+	fmt.Println(styles.DescriptionStyle.Render("Remove kibaship from a cluster. Projects, applications and deployments are deleted first,"))
+	fmt.Println(styles.DescriptionStyle.Render("while the operator is still running to clean up after them, then the objects created by"))
+	fmt.Println(styles.DescriptionStyle.Render("the operator and finally the operator, its webhooks and CRDs. cert-manager, Tekton"))
+	fmt.Println(styles.DescriptionStyle.Render("Pipelines and the Gateway API CRDs are left installed."))
+	fmt.Println()
+	fmt.Println(styles.HelpStyle.Render("Usage:"))
+	fmt.Printf("  %s\n", styles.CommandStyle.Render("kibaship uninstall --yes"))
+	fmt.Println()
+	fmt.Println(styles.HelpStyle.Render("Flags:"))
+
+	flags := []struct {
+		name        string
+		description string
+	}{
+		{"--yes", "Confirm that every project and application should be deleted"},
+		{"--timeout", "How long to wait for each kind of resource to be deleted (defaults to 5m)"},
+		{"--remove-finalizers", "Remove the finalizers of resources still present after --timeout"},
+		{"--kubeconfig", "Path of the kubeconfig of the cluster (defaults to $KUBECONFIG, then ~/.kube/config)"},
+		{"--context", "Context of the kubeconfig to use (defaults to the current context)"},
+		{"--manifest", "URL or path of the kibaship install.yaml (defaults to the release of this CLI)"},
+		{"-h, --help", "Show help for any command"},
+	}
+
+	for _, flag := range flags {
+		fmt.Printf("  %s  %s\n",
+			styles.CommandStyle.Render(flag.name),
+			styles.DescriptionStyle.Render(flag.description))
+	}
+}
@@ -0,0 +1,214 @@
+package install
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/checks"
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func newFakeClientset(serverVersion string, objects ...corev1.Node) *fake.Clientset {
+	clientset := fake.NewClientset()
+	for i := range objects {
+		_, _ = clientset.CoreV1().Nodes().Create(context.Background(), &objects[i], metav1.CreateOptions{})
+	}
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: serverVersion}
+	return clientset
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	clientset := newFakeClientset("v1.31.2", corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       corev1.NodeSpec{ProviderID: "hcloud://12345"},
+	})
+	_, err := clientset.StorageV1().StorageClasses().Create(ctx, &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "hcloud-volumes", Annotations: map[string]string{defaultStorageClassAnnotation: "true"}},
+		Provisioner: "csi.hetzner.cloud",
+	}, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	results, defaultClass := CheckCompatibility(ctx, clientset)
+	g.Expect(checks.Failed(results)).To(Equal(0))
+	g.Expect(defaultClass.Name).To(Equal("hcloud-volumes"))
+	g.Expect(results[2].Detail).To(Equal("provisioned by the hcloud cloud controller"))
+}
+
+func TestCheckCompatibilityFailures(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	clientset := newFakeClientset("v1.29.4", corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       corev1.NodeSpec{ProviderID: "talos://node-1"},
+	})
+	results, defaultClass := CheckCompatibility(ctx, clientset)
+	g.Expect(checks.Failed(results)).To(Equal(3))
+	g.Expect(defaultClass).To(BeNil())
+	g.Expect(results[0].Detail).To(Equal("v1.29.4 is running, 1.30.0 or later is required"))
+
+	// MetalLB assigns the load balancer addresses of bare-metal clusters
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{GroupVersion: "metallb.io/v1beta1"}}
+	g.Expect(checkLoadBalancer(ctx, clientset).Status).To(Equal(checks.Pass))
+}
+
+func TestOperatorConfigMap(t *testing.T) {
+	g := NewWithT(t)
+
+	opts := Options{
+		Domain:           "apps.example.com",
+		ACMEEmail:        "ops@example.com",
+		GatewayClassName: "cilium",
+		WebhookURL:       "https://hooks.example.com/kibaship",
+	}
+
+	cm, err := opts.OperatorConfigMap(&storagev1.StorageClass{Provisioner: config.LonghornProvisioner})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm.Namespace).To(Equal(config.OperatorNamespace))
+	g.Expect(cm.Name).To(Equal(config.OperatorConfigMapName))
+	g.Expect(cm.Data).NotTo(HaveKey(config.ConfigKeyStorageClasses))
+
+	// The platform classes are backed by the driver of the default class
+	cm, err = opts.OperatorConfigMap(&storagev1.StorageClass{Provisioner: "ebs.csi.aws.com", Parameters: map[string]string{"type": "gp3"}})
+	g.Expect(err).NotTo(HaveOccurred())
+	parsed, err := config.ParseOperatorConfiguration(cm)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(parsed.StorageClasses).To(HaveLen(2))
+	g.Expect(parsed.StorageClasses[0].Provisioner).To(Equal("ebs.csi.aws.com"))
+	g.Expect(parsed.StorageClasses[1].Parameters).To(HaveKeyWithValue("type", "gp3"))
+
+	opts.GatewayClassName = ""
+	_, err = opts.OperatorConfigMap(nil)
+	g.Expect(err).To(MatchError(ContainSubstring(config.ConfigKeyGatewayClassName)))
+}
+
+func TestDecodeManifest(t *testing.T) {
+	g := NewWithT(t)
+
+	objects, err := DecodeManifest([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: apiserver
+  namespace: kibaship
+---
+# comment only
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: projects.platform.operator.kibaship.com
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: kibaship
+`))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objects).To(HaveLen(3))
+	g.Expect(objects[0].GetKind()).To(Equal("Namespace"))
+	g.Expect(objects[1].GetKind()).To(Equal("CustomResourceDefinition"))
+	g.Expect(objects[2].GetKind()).To(Equal("Deployment"))
+
+	_, err = DecodeManifest([]byte("metadata:\n  name: broken\n"))
+	g.Expect(err).To(MatchError(ContainSubstring("has no apiVersion or kind")))
+}
+
+func TestEnsureOperatorConfigMap(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	clientset := fake.NewClientset()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: config.OperatorConfigMapName, Namespace: config.OperatorNamespace},
+		Data:       map[string]string{config.ConfigKeyDomain: "apps.example.com"},
+	}
+	created, err := EnsureOperatorConfigMap(ctx, clientset, cm)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(created).To(BeTrue())
+
+	// A second install keeps the settings of the first
+	changed := cm.DeepCopy()
+	changed.Data[config.ConfigKeyDomain] = "other.example.com"
+	created, err = EnsureOperatorConfigMap(ctx, clientset, changed)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(created).To(BeFalse())
+
+	stored, err := clientset.CoreV1().ConfigMaps(config.OperatorNamespace).Get(ctx, config.OperatorConfigMapName, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(stored.Data[config.ConfigKeyDomain]).To(Equal("apps.example.com"))
+}
+
+func TestPrerequisites(t *testing.T) {
+	g := NewWithT(t)
+
+	gateway := Prerequisites(config.IngressProviderGateway)
+	g.Expect(gateway).To(HaveLen(3))
+	g.Expect(gateway[0].ManifestURL).To(Equal("https://github.com/cert-manager/cert-manager/releases/download/v1.18.2/cert-manager.yaml"))
+	g.Expect(gateway[2].Name).To(Equal("Gateway API"))
+
+	g.Expect(Prerequisites(config.IngressProviderNginx)).To(HaveLen(2))
+
+	clientset := fake.NewClientset()
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{GroupVersion: "cert-manager.io/v1"}}
+	g.Expect(gateway[0].Installed(clientset)).To(BeTrue())
+	g.Expect(gateway[1].Installed(clientset)).To(BeFalse())
+}
+
+func TestDeleteManifest(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	c := fakeclient.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kibaship"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "kibaship-config", Namespace: "kibaship"}},
+	).Build()
+
+	objects, err := DecodeManifest([]byte(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: kibaship
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kibaship-config
+  namespace: kibaship
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: already-gone
+  namespace: kibaship
+---
+apiVersion: platform.operator.kibaship.com/v1alpha1
+kind: Project
+metadata:
+  name: api-not-served
+`))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(DeleteManifest(ctx, c, objects, time.Second)).To(BeEmpty())
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "kibaship"}, &corev1.Namespace{}))).To(BeTrue())
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Namespace: "kibaship", Name: "kibaship-config"}, &corev1.ConfigMap{}))).To(BeTrue())
+}
@@ -0,0 +1,158 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/checks"
+	"github.com/kibamail/kibaship/cmd/cli/internal/styles"
+	"github.com/kibamail/kibaship/cmd/cli/internal/version"
+	"github.com/kibamail/kibaship/internal/bootstrap"
+)
+
+// NewUninstallCommand creates and returns the uninstall command
+func NewUninstallCommand() *cobra.Command {
+	var (
+		kubeconfig       string
+		kubeContext      string
+		manifest         string
+		timeout          time.Duration
+		removeFinalizers bool
+		yes              bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove kibaship and every platform resource from a Kubernetes cluster",
+		Long: "Delete the projects, applications and deployments of the platform while the operator is still " +
+			"running to clean up after them, then the objects created by the operator and finally the operator, " +
+			"its webhooks and CRDs. cert-manager, Tekton Pipelines and the Gateway API CRDs are left installed.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !yes {
+				PrintUninstallHelp()
+				return fmt.Errorf("uninstalling deletes every project and application of the cluster, pass --yes to confirm")
+			}
+			if manifest == "" {
+				manifest = fmt.Sprintf("https://github.com/kibamail/kibaship/releases/download/%s/install.yaml", version.GetVersion())
+			}
+
+			rules := clientcmd.NewDefaultClientConfigLoadingRules()
+			rules.ExplicitPath = kubeconfig
+			restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: kubeContext}).ClientConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load the kubeconfig: %w", err)
+			}
+			c, err := client.New(restConfig, client.Options{})
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+
+			// Read the manifest first, a wrong --manifest should fail before anything is deleted
+			data, err := ReadManifest(ctx, manifest)
+			if err != nil {
+				return err
+			}
+			objects, err := DecodeManifest(data)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(styles.TitleStyle.Render("Uninstalling kibaship from " + restConfig.Host))
+			leftovers := bootstrap.Teardown(ctx, c, bootstrap.TeardownOptions{
+				Timeout:          timeout,
+				RemoveFinalizers: removeFinalizers,
+				Progress: func(message string) {
+					fmt.Println(styles.DescriptionStyle.Render(message + "..."))
+				},
+			})
+
+			fmt.Println(styles.DescriptionStyle.Render("Deleting the operator, its webhooks and CRDs..."))
+			leftovers = append(leftovers, DeleteManifest(ctx, c, objects, timeout)...)
+
+			fmt.Println()
+			if len(leftovers) > 0 {
+				fmt.Println(styles.TitleStyle.Render("Objects that could not be deleted"))
+				fmt.Println()
+				results := make([]checks.Result, 0, len(leftovers))
+				for _, leftover := range leftovers {
+					results = append(results, checks.Result{Name: leftover.String(), Status: checks.Fail, Detail: leftover.Reason})
+				}
+				checks.Print(os.Stdout, results)
+				fmt.Println()
+				return fmt.Errorf("%d objects could not be deleted, remove them by hand or rerun with --remove-finalizers", len(leftovers))
+			}
+
+			fmt.Println(styles.TitleStyle.Render("Kibaship is uninstalled."))
+			fmt.Println(styles.DescriptionStyle.Render("cert-manager, Tekton Pipelines and the Gateway API CRDs were left installed."))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "Path of the kubeconfig of the cluster (defaults to $KUBECONFIG, then ~/.kube/config)")
+	cmd.Flags().StringVar(&kubeContext, "context", "", "Context of the kubeconfig to use (defaults to the current context)")
+	cmd.Flags().StringVar(&manifest, "manifest", "", "URL or path of the kibaship install.yaml (defaults to the release of this CLI)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute, "How long to wait for each kind of resource to be deleted")
+	cmd.Flags().BoolVar(&removeFinalizers, "remove-finalizers", false, "Remove the finalizers of resources still present after --timeout")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Confirm that every project and application should be deleted")
+
+	// Override help command behavior
+	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		PrintUninstallHelp()
+	})
+
+	return cmd
+}
+
+// DeleteManifest deletes the objects of a manifest in the reverse of the order they are applied,
+// so CRDs and namespaces go last, and waits for the namespaces to be finalized. The objects that
+// could not be deleted are returned.
+func DeleteManifest(ctx context.Context, c client.Client, objects []*unstructured.Unstructured, timeout time.Duration) []bootstrap.Leftover {
+	var (
+		leftovers  []bootstrap.Leftover
+		namespaces []*unstructured.Unstructured
+	)
+	for i := len(objects) - 1; i >= 0; i-- {
+		obj := objects[i]
+		err := c.Delete(ctx, obj, client.PropagationPolicy("Background"))
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			leftovers = append(leftovers, manifestLeftover(obj, fmt.Sprintf("failed to delete: %v", err)))
+			continue
+		}
+		if obj.GetKind() == "Namespace" {
+			namespaces = append(namespaces, obj)
+		}
+	}
+
+	for _, namespace := range namespaces {
+		err := poll(ctx, timeout, func() (bool, error) {
+			err := c.Get(ctx, client.ObjectKeyFromObject(namespace), namespace.DeepCopy())
+			if apierrors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}, "namespace "+namespace.GetName())
+		if err != nil {
+			leftovers = append(leftovers, manifestLeftover(namespace, err.Error()))
+		}
+	}
+	return leftovers
+}
+
+func manifestLeftover(obj *unstructured.Unstructured, reason string) bootstrap.Leftover {
+	return bootstrap.Leftover{Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName(), Reason: reason}
+}
@@ -0,0 +1,148 @@
+package login
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/completion"
+	"github.com/kibamail/kibaship/cmd/cli/internal/credentials"
+	"github.com/kibamail/kibaship/cmd/cli/internal/styles"
+)
+
+// NewCommand creates and returns the login command
+func NewCommand() *cobra.Command {
+	var (
+		apiURL  string
+		token   string
+		project string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Save the credentials of a kibaship API server",
+		Long: "Save the API URL, API key and default project of a kibaship API server in a profile of " +
+			"~/.kibaship/config. The API key is kept in the OS keychain when one is available.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := credentials.ConfigPath()
+			if err != nil {
+				return err
+			}
+			cfg, err := credentials.LoadConfig(path)
+			if err != nil {
+				return err
+			}
+			profileFlag, _ := cmd.Flags().GetString("profile")
+			name := profileFlag
+			if name == "" {
+				name = credentials.DefaultProfile
+			}
+
+			profile := cfg.Profiles[name]
+			if apiURL != "" {
+				profile.APIURL = strings.TrimSuffix(apiURL, "/")
+			}
+			if project != "" {
+				profile.Project = project
+			}
+			if profile.APIURL == "" {
+				PrintHelp()
+				return fmt.Errorf("the API URL is required, pass it with --api-url")
+			}
+			if token == "" {
+				if token, err = readToken(); err != nil {
+					return err
+				}
+			}
+			if token == "" {
+				return fmt.Errorf("an API key is required")
+			}
+
+			if cfg.Profiles == nil {
+				cfg.Profiles = map[string]credentials.Profile{}
+			}
+			cfg.Profiles[name] = profile
+			storedIn, err := cfg.StoreToken(credentials.SystemKeychain(), name, token)
+			if err != nil {
+				return err
+			}
+			cfg.CurrentProfile = name
+			if err := cfg.Save(path); err != nil {
+				return err
+			}
+
+			fmt.Println(styles.TitleStyle.Render(fmt.Sprintf("Logged in to %s as profile %s.", profile.APIURL, name)))
+			fmt.Println(styles.DescriptionStyle.Render(fmt.Sprintf("The API key is stored in the %s.", storedIn)))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&apiURL, "api-url", "", "URL of the kibaship API server")
+	cmd.Flags().StringVar(&token, "api-key", "", "API key, prompted for when omitted")
+	cmd.Flags().StringVar(&project, "project", "", "UUID of the project commands default to")
+	_ = cmd.RegisterFlagCompletionFunc("project", completion.Projects)
+
+	// Override help command behavior
+	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		PrintHelp()
+	})
+
+	return cmd
+}
+
+// NewLogoutCommand creates and returns the logout command
+func NewLogoutCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Remove the API key of a profile",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := credentials.ConfigPath()
+			if err != nil {
+				return err
+			}
+			cfg, err := credentials.LoadConfig(path)
+			if err != nil {
+				return err
+			}
+			profileFlag, _ := cmd.Flags().GetString("profile")
+			name := cfg.SelectedProfile(profileFlag)
+			if _, ok := cfg.Profiles[name]; !ok {
+				return fmt.Errorf("profile %s does not exist", name)
+			}
+
+			if err := cfg.ForgetToken(credentials.SystemKeychain(), name); err != nil {
+				return err
+			}
+			if err := cfg.Save(path); err != nil {
+				return err
+			}
+			fmt.Println(styles.TitleStyle.Render(fmt.Sprintf("Logged out of profile %s.", name)))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// readToken prompts for the API key without echoing it, or reads it from stdin when piped
+func readToken() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Print(styles.HelpStyle.Render("API key: "))
+		token, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf("failed to read the API key: %w", err)
+		}
+		return strings.TrimSpace(string(token)), nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read the API key from stdin: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,39 @@
+package login
+
+import (
+	"fmt"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/styles"
+)
+
+// PrintHelp displays the help documentation for the login command
+func PrintHelp() {
+	styles.PrintBanner()
+	fmt.Println(styles.TitleStyle.Render("🚀 Kibaship Login"))
+	fmt.Println()
+	fmt.Println(styles.DescriptionStyle.Render("Save the credentials of a kibaship API server in a profile of ~/.kibaship/config."))
+	fmt.Println(styles.DescriptionStyle.Render("The API key goes to the OS keychain when one is available, the config file otherwise."))
+	fmt.Println()
+	fmt.Println(styles.HelpStyle.Render("Usage:"))
+	fmt.Printf("  %s\n", styles.CommandStyle.Render("kibaship login --api-url https://api.kibaship.example"))
+	fmt.Printf("  %s\n", styles.CommandStyle.Render("kibaship login --profile staging --api-url https://api.staging.example"))
+	fmt.Println()
+	fmt.Println(styles.HelpStyle.Render("Flags:"))
+
+	flags := []struct {
+		name        string
+		description string
+	}{
+		{"--api-url", "URL of the kibaship API server"},
+		{"--api-key", "API key, prompted for when omitted"},
+		{"--project", "UUID of the project commands default to"},
+		{"--profile", "Name of the profile to save (defaults to default)"},
+		{"-h, --help", "Show help for any command"},
+	}
+
+	for _, flag := range flags {
+		fmt.Printf("  %s  %s\n",
+			styles.CommandStyle.Render(flag.name),
+			styles.DescriptionStyle.Render(flag.description))
+	}
+}
@@ -0,0 +1,112 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Command describes a command of the CLI for tools integrating with it
+type Command struct {
+	// Path is the full command line prefix, such as "kibaship profiles use"
+	Path        string   `json:"path"`
+	Use         string   `json:"use"`
+	Short       string   `json:"short,omitempty"`
+	Long        string   `json:"long,omitempty"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Flags       []Flag   `json:"flags,omitempty"`
+	Subcommands []string `json:"subcommands,omitempty"`
+}
+
+// Flag describes a flag of a command
+type Flag struct {
+	Name      string `json:"name"`
+	Shorthand string `json:"shorthand,omitempty"`
+	Type      string `json:"type"`
+	Default   string `json:"default,omitempty"`
+	Usage     string `json:"usage,omitempty"`
+	// Inherited flags are defined by a parent command, such as --profile
+	Inherited bool `json:"inherited,omitempty"`
+}
+
+// NewCommand creates and returns the hidden commands command, printing the command tree of root
+func NewCommand(root *cobra.Command) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:    "commands",
+		Short:  "Describe every command of the CLI",
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			commands := Describe(root)
+			if asJSON {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(commands)
+			}
+			for _, c := range commands {
+				fmt.Printf("%-32s %s\n", c.Path, c.Short)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the commands with their flags as JSON")
+	return cmd
+}
+
+// Describe lists the visible commands below root, root included, sorted by path
+func Describe(root *cobra.Command) []Command {
+	var commands []Command
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		if c.Hidden || c.Name() == "help" {
+			return
+		}
+		commands = append(commands, describe(c))
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Path < commands[j].Path })
+	return commands
+}
+
+func describe(c *cobra.Command) Command {
+	out := Command{
+		Path:    c.CommandPath(),
+		Use:     c.Use,
+		Short:   c.Short,
+		Long:    c.Long,
+		Aliases: c.Aliases,
+	}
+
+	addFlag := func(inherited bool) func(f *pflag.Flag) {
+		return func(f *pflag.Flag) {
+			if f.Hidden {
+				return
+			}
+			out.Flags = append(out.Flags, Flag{
+				Name:      f.Name,
+				Shorthand: f.Shorthand,
+				Type:      f.Value.Type(),
+				Default:   f.DefValue,
+				Usage:     f.Usage,
+				Inherited: inherited,
+			})
+		}
+	}
+	c.NonInheritedFlags().VisitAll(addFlag(false))
+	c.InheritedFlags().VisitAll(addFlag(true))
+
+	for _, sub := range c.Commands() {
+		if !sub.Hidden && sub.Name() != "help" {
+			out.Subcommands = append(out.Subcommands, sub.Name())
+		}
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package profiles
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/credentials"
+	"github.com/kibamail/kibaship/cmd/cli/internal/styles"
+)
+
+// NewCommand creates and returns the profiles command with all subcommands
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "List the profiles of ~/.kibaship/config",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, _, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			if len(cfg.Profiles) == 0 {
+				fmt.Println(styles.DescriptionStyle.Render("No profiles, create one with kibaship login."))
+				return nil
+			}
+
+			current := cfg.SelectedProfile("")
+			for _, name := range cfg.ProfileNames() {
+				profile := cfg.Profiles[name]
+				marker := " "
+				if name == current {
+					marker = "*"
+				}
+				line := fmt.Sprintf("%s %s  %s", marker, styles.CommandStyle.Render(name), profile.APIURL)
+				if profile.Project != "" {
+					line += styles.DescriptionStyle.Render(" (project " + profile.Project + ")")
+				}
+				fmt.Println(line)
+			}
+			return nil
+		},
+	}
+
+	useCmd := &cobra.Command{
+		Use:   "use <profile>",
+		Short: "Make a profile the one used without --profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, path, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			if _, ok := cfg.Profiles[args[0]]; !ok {
+				return fmt.Errorf("profile %s does not exist, create it with kibaship login --profile %s", args[0], args[0])
+			}
+			cfg.CurrentProfile = args[0]
+			if err := cfg.Save(path); err != nil {
+				return err
+			}
+			fmt.Println(styles.TitleStyle.Render(fmt.Sprintf("Using profile %s.", args[0])))
+			return nil
+		},
+	}
+	cmd.AddCommand(useCmd)
+
+	// Override help command behavior
+	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		PrintHelp()
+	})
+
+	return cmd
+}
+
+// loadConfig reads the CLI config and returns it with its path
+func loadConfig() (*credentials.Config, string, error) {
+	path, err := credentials.ConfigPath()
+	if err != nil {
+		return nil, "", err
+	}
+	cfg, err := credentials.LoadConfig(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return cfg, path, nil
+}
@@ -0,0 +1,38 @@
+package profiles
+
+import (
+	"fmt"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/styles"
+)
+
+// PrintHelp displays the help documentation for the profiles command
+func PrintHelp() {
+	styles.PrintBanner()
+	fmt.Println(styles.TitleStyle.Render("🚀 Kibaship Profiles"))
+	fmt.Println()
+	fmt.Println(styles.DescriptionStyle.Render("Profiles name the kibaship API servers saved with kibaship login. Commands use"))
+	fmt.Println(styles.DescriptionStyle.Render("--profile, then $KIBASHIP_PROFILE, then the current profile marked with *."))
+	fmt.Println()
+	fmt.Println(styles.HelpStyle.Render("Available Commands:"))
+
+	commands := []struct {
+		name        string
+		description string
+	}{
+		{"profiles", "List the profiles"},
+		{"profiles use <profile>", "Make a profile the current one"},
+	}
+
+	for _, cmd := range commands {
+		fmt.Printf("  %s  %s\n",
+			styles.CommandStyle.Render(cmd.name),
+			styles.DescriptionStyle.Render(cmd.description))
+	}
+
+	fmt.Println()
+	fmt.Println(styles.HelpStyle.Render("Flags:"))
+	fmt.Printf("  %s  %s\n",
+		styles.CommandStyle.Render("-h, --help"),
+		styles.DescriptionStyle.Render("Show help for any command"))
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/credentials"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+// Client talks to the kibaship API server
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient returns a client for the API server of a connection
+func NewClient(conn *credentials.Connection) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(conn.APIURL, "/"),
+		token:   conn.Token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// GetProject returns a project by UUID or slug
+func (c *Client) GetProject(uuid string) (*models.ProjectResponse, error) {
+	var project models.ProjectResponse
+	if err := c.do(http.MethodGet, "/v1/projects/"+url.PathEscape(uuid), nil, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// GetApplicationsByProject returns the applications of a project with their latest deployment
+func (c *Client) GetApplicationsByProject(projectUUID string) ([]models.ApplicationResponse, error) {
+	var applications []models.ApplicationResponse
+	if err := c.do(http.MethodGet, "/v1/projects/"+url.PathEscape(projectUUID)+"/applications", nil, &applications); err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// CreateDeployment starts a deployment of an application
+func (c *Client) CreateDeployment(req *models.DeploymentCreateRequest) (*models.DeploymentResponse, error) {
+	var deployment models.DeploymentResponse
+	path := "/v1/applications/" + url.PathEscape(req.ApplicationUUID) + "/deployments"
+	if err := c.do(http.MethodPost, path, req, &deployment); err != nil {
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+// PromoteDeployment makes a deployment the live release of its application
+func (c *Client) PromoteDeployment(deploymentUUID string) error {
+	return c.do(http.MethodPost, "/v1/deployments/"+url.PathEscape(deploymentUUID)+"/promote?promotedBy=cli", nil, nil)
+}
+
+// GetApplicationLogHistory returns the newest persisted log lines of an application
+func (c *Client) GetApplicationLogHistory(applicationUUID string, since time.Duration, limit int) (*models.ApplicationLogHistoryResponse, error) {
+	query := url.Values{}
+	query.Set("since", since.String())
+	query.Set("limit", fmt.Sprintf("%d", limit))
+	var history models.ApplicationLogHistoryResponse
+	path := "/v1/applications/" + url.PathEscape(applicationUUID) + "/logs/history?" + query.Encode()
+	if err := c.do(http.MethodGet, path, nil, &history); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// do sends a request and decodes the JSON response into out when given
+func (c *Client) do(method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the API server: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var failure struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &failure) == nil && failure.Message != "" {
+			return fmt.Errorf("%s %s failed with status %d: %s", method, path, resp.StatusCode, failure.Message)
+		}
+		return fmt.Errorf("%s %s failed with status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package checks
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/styles"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	// Pass is a check that succeeded
+	Pass Status = "PASS"
+	// Fail is a check that would make the operation fail
+	Fail Status = "FAIL"
+	// Skip is a check that cannot be run for the provider or platform
+	Skip Status = "SKIP"
+)
+
+// Result is the outcome of one check
+type Result struct {
+	Name   string
+	Status Status
+	Detail string
+}
+
+// Failed returns the number of failed results
+func Failed(results []Result) int {
+	failed := 0
+	for _, result := range results {
+		if result.Status == Fail {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Print writes the results as a table and returns the number of failed checks
+func Print(w io.Writer, results []Result) int {
+	width := 0
+	for _, result := range results {
+		width = max(width, len(result.Name))
+	}
+
+	for _, result := range results {
+		var status string
+		switch result.Status {
+		case Pass:
+			status = styles.TitleStyle.Render(string(result.Status))
+		case Fail:
+			status = styles.ErrorStyle.Render(string(result.Status))
+		default:
+			status = styles.DescriptionStyle.Render(string(result.Status))
+		}
+		_, _ = fmt.Fprintf(w, "  %s  %-*s  %s\n", status, width, result.Name, styles.DescriptionStyle.Render(result.Detail))
+	}
+	return Failed(results)
+}
+
+var versionPattern = regexp.MustCompile(`v?(\d+)\.(\d+)\.(\d+)`)
+
+// FindVersion returns the first semantic version in the output of a command, or an empty string
+func FindVersion(output string) string {
+	return versionPattern.FindString(output)
+}
+
+// CompareVersions compares two semantic versions, ignoring a v prefix and pre-release suffixes
+func CompareVersions(a, b string) int {
+	pa, pb := versionPattern.FindStringSubmatch(a), versionPattern.FindStringSubmatch(b)
+	if pa == nil || pb == nil {
+		return strings.Compare(a, b)
+	}
+	for i := 1; i <= 3; i++ {
+		na, _ := strconv.Atoi(pa[i])
+		nb, _ := strconv.Atoi(pb[i])
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
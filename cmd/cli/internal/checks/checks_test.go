@@ -0,0 +1,33 @@
+package checks
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCompareVersions(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(CompareVersions("v1.9.5", "1.5.0")).To(Equal(1))
+	g.Expect(CompareVersions("1.10.0", "1.9.0")).To(Equal(1))
+	g.Expect(CompareVersions("v1.30.0-rc.1", "1.30.0")).To(Equal(0))
+	g.Expect(CompareVersions("v1.31.2+k3s1", "1.30.0")).To(Equal(1))
+	g.Expect(CompareVersions("1.29.9", "1.30.0")).To(Equal(-1))
+	g.Expect(FindVersion("Client Version: v1.31.0\nKustomize Version: v5.4.2")).To(Equal("v1.31.0"))
+}
+
+func TestPrint(t *testing.T) {
+	g := NewWithT(t)
+
+	var out bytes.Buffer
+	failed := Print(&out, []Result{
+		{Name: "Kubernetes version", Status: Pass, Detail: "v1.31.0"},
+		{Name: "Load balancer", Status: Fail, Detail: "none found"},
+		{Name: "Quota", Status: Skip},
+	})
+	g.Expect(failed).To(Equal(1))
+	g.Expect(out.String()).To(ContainSubstring("Kubernetes version"))
+	g.Expect(out.String()).To(ContainSubstring("none found"))
+}
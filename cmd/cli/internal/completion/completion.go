@@ -0,0 +1,191 @@
+package completion
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/api"
+	"github.com/kibamail/kibaship/cmd/cli/internal/credentials"
+)
+
+// cacheTTL is how long completions fetched from the API are reused, shells ask for them on
+// every tab press
+const cacheTTL = 5 * time.Minute
+
+// Item is a completion candidate with its description
+type Item struct {
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// entry is a cached list of candidates
+type entry struct {
+	FetchedAt time.Time `json:"fetchedAt"`
+	Items     []Item    `json:"items"`
+}
+
+// cache holds the candidates of every profile, keyed by profile and list
+type cache struct {
+	// Projects are the projects the CLI was used with, the API has no project listing
+	Projects map[string][]Item `json:"projects,omitempty"`
+
+	// Lists are the candidates fetched from the API, such as the applications of a project
+	Lists map[string]entry `json:"lists,omitempty"`
+}
+
+// cachePath returns the path of the completion cache, next to the CLI config
+func cachePath() (string, error) {
+	path, err := credentials.ConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "cache", "completion.json"), nil
+}
+
+func loadCache(path string) *cache {
+	c := &cache{}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		// A corrupt cache is rebuilt
+		_ = json.Unmarshal(data, c)
+	}
+	if c.Projects == nil {
+		c.Projects = map[string][]Item{}
+	}
+	if c.Lists == nil {
+		c.Lists = map[string]entry{}
+	}
+	return c
+}
+
+func (c *cache) save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// RememberProject records a project used with a profile so its slug completes later
+func RememberProject(profile, slug, name string) {
+	path, err := cachePath()
+	if err != nil || slug == "" {
+		return
+	}
+	c := loadCache(path)
+	projects := c.Projects[profile]
+	for _, project := range projects {
+		if project.Value == slug {
+			return
+		}
+	}
+	c.Projects[profile] = append(projects, Item{Value: slug, Description: name})
+	_ = c.save(path)
+}
+
+// Projects completes project slugs: the projects of the profiles and the ones used before
+func Projects(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfgPath, err := credentials.ConfigPath()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg, err := credentials.LoadConfig(cfgPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	path, err := cachePath()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	profileFlag, _ := cmd.Flags().GetString("profile")
+	profile := cfg.SelectedProfile(profileFlag)
+	items := append([]Item{}, loadCache(path).Projects[profile]...)
+	if project := cfg.Profiles[profile].Project; project != "" {
+		items = append(items, Item{Value: project, Description: "project of profile " + profile})
+	}
+	return candidates(items, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// Applications completes the application slugs of the project given with --project, or the
+// project of the profile, fetched from the API and cached for a few minutes
+func Applications(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	profileFlag, _ := cmd.Flags().GetString("profile")
+	conn, err := credentials.Resolve(profileFlag, "", "")
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	project, _ := cmd.Flags().GetString("project")
+	if project == "" {
+		project = conn.Project
+	}
+	if project == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	items, err := cached(conn.Profile+"/"+project+"/applications", func() ([]Item, error) {
+		applications, err := api.NewClient(conn).GetApplicationsByProject(project)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]Item, 0, len(applications))
+		for _, app := range applications {
+			items = append(items, Item{Value: app.Slug, Description: app.Name})
+		}
+		return items, nil
+	})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return candidates(items, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// cached returns the cached candidates of a list, fetching them when missing or stale
+func cached(key string, fetch func() ([]Item, error)) ([]Item, error) {
+	path, err := cachePath()
+	if err != nil {
+		return nil, err
+	}
+	c := loadCache(path)
+	if e, ok := c.Lists[key]; ok && time.Since(e.FetchedAt) < cacheTTL {
+		return e.Items, nil
+	}
+
+	items, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.Lists[key] = entry{FetchedAt: time.Now(), Items: items}
+	// Completions work without the cache
+	_ = c.save(path)
+	return items, nil
+}
+
+// candidates returns the sorted, unique items starting with toComplete in cobra's
+// value<TAB>description format
+func candidates(items []Item, toComplete string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, item := range items {
+		if seen[item.Value] || !strings.HasPrefix(item.Value, toComplete) {
+			continue
+		}
+		seen[item.Value] = true
+		if item.Description != "" {
+			out = append(out, item.Value+"\t"+item.Description)
+		} else {
+			out = append(out, item.Value)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,59 @@
+package completion
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"github.com/kibamail/kibaship/cmd/cli/internal/credentials"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+func TestCompletion(t *testing.T) {
+	g := NewWithT(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		g.Expect(r.URL.Path).To(Equal("/v1/projects/billing/applications"))
+		g.Expect(r.Header.Get("Authorization")).To(Equal("Bearer secret"))
+		_ = json.NewEncoder(w).Encode([]models.ApplicationResponse{
+			{Slug: "api12345", Name: "api"},
+			{Slug: "web12345", Name: "web"},
+		})
+	}))
+	defer server.Close()
+
+	configPath := filepath.Join(t.TempDir(), "config")
+	t.Setenv("KIBASHIP_CONFIG", configPath)
+	t.Setenv("KIBASHIP_PROFILE", "")
+	t.Setenv("KIBASHIP_API_URL", "")
+	t.Setenv("KIBASHIP_API_KEY", "")
+	cfg := &credentials.Config{
+		CurrentProfile: "prod",
+		Profiles:       map[string]credentials.Profile{"prod": {APIURL: server.URL, Token: "secret", Project: "billing"}},
+	}
+	g.Expect(cfg.Save(configPath)).To(Succeed())
+
+	cmd := &cobra.Command{Use: "dashboard"}
+	cmd.Flags().String("profile", "", "")
+	cmd.Flags().String("project", "", "")
+
+	RememberProject("prod", "shop1234", "shop")
+	projects, directive := Projects(cmd, nil, "")
+	g.Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp))
+	g.Expect(projects).To(Equal([]string{"billing\tproject of profile prod", "shop1234\tshop"}))
+
+	applications, _ := Applications(cmd, nil, "w")
+	g.Expect(applications).To(Equal([]string{"web12345\tweb"}))
+
+	// Served from the cache on the next tab press
+	applications, _ = Applications(cmd, nil, "")
+	g.Expect(applications).To(HaveLen(2))
+	g.Expect(requests).To(Equal(1))
+}
@@ -0,0 +1,103 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultProfile is the profile used when none is selected
+const DefaultProfile = "default"
+
+// Profile describes how to reach a kibaship API server
+type Profile struct {
+	// APIURL is the URL of the API server
+	APIURL string `json:"apiUrl"`
+
+	// Token is the API key, only stored in the config file when no keychain is available
+	Token string `json:"token,omitempty"`
+
+	// Project is the UUID of the project commands acting on a single project default to
+	Project string `json:"project,omitempty"`
+}
+
+// Config is the content of ~/.kibaship/config
+type Config struct {
+	// CurrentProfile is the profile used without --profile
+	CurrentProfile string `json:"currentProfile,omitempty"`
+
+	// Profiles are the API servers the CLI knows, by name
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+}
+
+// ConfigPath returns the path of the CLI config, $KIBASHIP_CONFIG or ~/.kibaship/config
+func ConfigPath() (string, error) {
+	if path := os.Getenv("KIBASHIP_CONFIG"); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the home directory: %w", err)
+	}
+	return filepath.Join(home, ".kibaship", "config"), nil
+}
+
+// LoadConfig reads the CLI config, a missing file is an empty config
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the CLI config, readable by the current user only as it may hold tokens
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ProfileNames returns the names of the profiles, sorted
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SelectedProfile returns the name of the profile a command uses: the --profile flag, then
+// $KIBASHIP_PROFILE, then the current profile of the config
+func (c *Config) SelectedProfile(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if name := os.Getenv("KIBASHIP_PROFILE"); name != "" {
+		return name
+	}
+	if c.CurrentProfile != "" {
+		return c.CurrentProfile
+	}
+	return DefaultProfile
+}
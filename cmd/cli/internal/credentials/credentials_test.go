@@ -0,0 +1,106 @@
+package credentials
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+type fakeKeychain map[string]string
+
+func (k fakeKeychain) Name() string { return "fake keychain" }
+
+func (k fakeKeychain) Get(profile string) (string, error) {
+	token, ok := k[profile]
+	if !ok {
+		return "", ErrTokenNotFound
+	}
+	return token, nil
+}
+
+func (k fakeKeychain) Set(profile, token string) error {
+	k[profile] = token
+	return nil
+}
+
+func (k fakeKeychain) Delete(profile string) error {
+	delete(k, profile)
+	return nil
+}
+
+func TestConfigRoundTrip(t *testing.T) {
+	g := NewWithT(t)
+	path := filepath.Join(t.TempDir(), ".kibaship", "config")
+
+	cfg, err := LoadConfig(path)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Profiles).To(BeEmpty())
+
+	keychain := fakeKeychain{}
+	cfg.Profiles = map[string]Profile{"staging": {APIURL: "https://api.staging.example", Project: "p1"}}
+	storedIn, err := cfg.StoreToken(keychain, "staging", "secret")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(storedIn).To(Equal("fake keychain"))
+	cfg.CurrentProfile = "staging"
+	g.Expect(cfg.Save(path)).To(Succeed())
+
+	loaded, err := LoadConfig(path)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(loaded).To(Equal(cfg))
+	g.Expect(loaded.Profiles["staging"].Token).To(BeEmpty())
+	g.Expect(keychain["staging"]).To(Equal("secret"))
+
+	// Without keychain the token stays in the config file
+	_, err = loaded.StoreToken(nil, "ci", "ci-secret")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(loaded.Profiles["ci"].Token).To(Equal("ci-secret"))
+
+	g.Expect(loaded.ForgetToken(keychain, "staging")).To(Succeed())
+	g.Expect(keychain).NotTo(HaveKey("staging"))
+}
+
+func TestResolve(t *testing.T) {
+	g := NewWithT(t)
+	t.Setenv("KIBASHIP_PROFILE", "")
+	t.Setenv("KIBASHIP_API_URL", "")
+	t.Setenv("KIBASHIP_API_KEY", "")
+
+	keychain := fakeKeychain{"prod": "prod-secret"}
+	cfg := &Config{
+		CurrentProfile: "prod",
+		Profiles: map[string]Profile{
+			"prod":    {APIURL: "https://api.example", Project: "p1"},
+			"staging": {APIURL: "https://api.staging.example", Token: "staging-secret"},
+		},
+	}
+
+	conn, err := resolve(cfg, keychain, "", "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*conn).To(Equal(Connection{Profile: "prod", APIURL: "https://api.example", Token: "prod-secret", Project: "p1"}))
+
+	conn, err = resolve(cfg, keychain, "staging", "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conn.Token).To(Equal("staging-secret"))
+
+	t.Setenv("KIBASHIP_PROFILE", "staging")
+	conn, err = resolve(cfg, keychain, "", "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conn.Profile).To(Equal("staging"))
+
+	// Environment variables win over the profile, flags over both
+	t.Setenv("KIBASHIP_API_URL", "https://env.example")
+	t.Setenv("KIBASHIP_API_KEY", "env-secret")
+	conn, err = resolve(cfg, keychain, "prod", "https://flag.example", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conn.APIURL).To(Equal("https://flag.example"))
+	g.Expect(conn.Token).To(Equal("env-secret"))
+
+	_, err = resolve(cfg, keychain, "missing", "", "")
+	g.Expect(err).To(MatchError(ContainSubstring("profile missing does not exist")))
+
+	t.Setenv("KIBASHIP_API_URL", "")
+	t.Setenv("KIBASHIP_API_KEY", "")
+	_, err = resolve(&Config{}, nil, "", "", "")
+	g.Expect(err).To(MatchError(ContainSubstring("not logged in")))
+}
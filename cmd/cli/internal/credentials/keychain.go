@@ -0,0 +1,193 @@
+package credentials
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keychainService is the service the tokens are stored under in the OS keychain
+const keychainService = "kibaship"
+
+// ErrTokenNotFound is returned when the keychain holds no token for a profile
+var ErrTokenNotFound = errors.New("token not found in keychain")
+
+// Keychain stores the API tokens of the profiles outside the config file
+type Keychain interface {
+	// Name describes the keychain in messages
+	Name() string
+	Get(profile string) (string, error)
+	Set(profile, token string) error
+	Delete(profile string) error
+}
+
+// SystemKeychain returns the keychain of the operating system: the macOS login keychain through
+// security, or the Secret Service (GNOME Keyring, KWallet) through secret-tool on Linux desktops.
+// It returns nil when none is available, tokens are then kept in the config file.
+func SystemKeychain() Keychain {
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("security"); err == nil {
+			return &macOSKeychain{run: runCommand}
+		}
+	case "linux":
+		// Without a session bus, on servers and in CI, there is no Secret Service to talk to
+		if _, err := exec.LookPath("secret-tool"); err == nil && os.Getenv("DBUS_SESSION_BUS_ADDRESS") != "" {
+			return &secretServiceKeychain{run: runCommand}
+		}
+	}
+	return nil
+}
+
+// commandRunner runs a command with the given stdin and returns its stdout and exit code
+type commandRunner func(stdin, name string, args ...string) (string, int, error)
+
+// runCommand runs a command, a non-zero exit code is not an error
+func runCommand(stdin, name string, args ...string) (string, int, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return stdout.String(), exitErr.ExitCode(), nil
+		}
+		return "", 0, fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	return stdout.String(), 0, nil
+}
+
+// macOSKeychain stores tokens as generic passwords of the login keychain
+type macOSKeychain struct {
+	run commandRunner
+}
+
+func (k *macOSKeychain) Name() string { return "macOS keychain" }
+
+func (k *macOSKeychain) Get(profile string) (string, error) {
+	out, code, err := k.run("", "security", "find-generic-password", "-s", keychainService, "-a", profile, "-w")
+	if err != nil {
+		return "", err
+	}
+	// 44 is errSecItemNotFound
+	if code == 44 {
+		return "", ErrTokenNotFound
+	}
+	if code != 0 {
+		return "", fmt.Errorf("security exited with code %d reading the token of profile %s", code, profile)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (k *macOSKeychain) Set(profile, token string) error {
+	// Commands read from stdin in interactive mode keep the token out of the process list
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+		shellQuote(keychainService), shellQuote(profile), shellQuote(token))
+	_, code, err := k.run(command, "security", "-i")
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("security exited with code %d storing the token of profile %s", code, profile)
+	}
+	return nil
+}
+
+func (k *macOSKeychain) Delete(profile string) error {
+	_, code, err := k.run("", "security", "delete-generic-password", "-s", keychainService, "-a", profile)
+	if err != nil {
+		return err
+	}
+	if code != 0 && code != 44 {
+		return fmt.Errorf("security exited with code %d deleting the token of profile %s", code, profile)
+	}
+	return nil
+}
+
+// secretServiceKeychain stores tokens in the Secret Service of the desktop session
+type secretServiceKeychain struct {
+	run commandRunner
+}
+
+func (k *secretServiceKeychain) Name() string { return "Secret Service keyring" }
+
+func (k *secretServiceKeychain) Get(profile string) (string, error) {
+	out, code, err := k.run("", "secret-tool", "lookup", "service", keychainService, "profile", profile)
+	if err != nil {
+		return "", err
+	}
+	// secret-tool exits with 1 and prints nothing when the secret is missing
+	token := strings.TrimSpace(out)
+	if code != 0 || token == "" {
+		return "", ErrTokenNotFound
+	}
+	return token, nil
+}
+
+func (k *secretServiceKeychain) Set(profile, token string) error {
+	_, code, err := k.run(token, "secret-tool", "store", "--label", "Kibaship ("+profile+")",
+		"service", keychainService, "profile", profile)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("secret-tool exited with code %d storing the token of profile %s", code, profile)
+	}
+	return nil
+}
+
+func (k *secretServiceKeychain) Delete(profile string) error {
+	_, code, err := k.run("", "secret-tool", "clear", "service", keychainService, "profile", profile)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("secret-tool exited with code %d deleting the token of profile %s", code, profile)
+	}
+	return nil
+}
+
+// shellQuote quotes an argument of a security interactive mode command
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// StoreToken keeps the token of a profile in the keychain, or in the config when there is no
+// keychain. It returns where the token went.
+func (c *Config) StoreToken(keychain Keychain, name, token string) (string, error) {
+	if c.Profiles == nil {
+		c.Profiles = map[string]Profile{}
+	}
+	profile := c.Profiles[name]
+	if keychain == nil {
+		profile.Token = token
+		c.Profiles[name] = profile
+		return "config file", nil
+	}
+	if err := keychain.Set(name, token); err != nil {
+		return "", fmt.Errorf("failed to store the token in the %s: %w", keychain.Name(), err)
+	}
+	profile.Token = ""
+	c.Profiles[name] = profile
+	return keychain.Name(), nil
+}
+
+// ForgetToken removes the token of a profile from the keychain and the config
+func (c *Config) ForgetToken(keychain Keychain, name string) error {
+	if keychain != nil {
+		if err := keychain.Delete(name); err != nil {
+			return fmt.Errorf("failed to remove the token from the %s: %w", keychain.Name(), err)
+		}
+	}
+	if profile, ok := c.Profiles[name]; ok {
+		profile.Token = ""
+		c.Profiles[name] = profile
+	}
+	return nil
+}
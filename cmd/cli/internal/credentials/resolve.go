@@ -0,0 +1,64 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Connection is the API server a command talks to
+type Connection struct {
+	Profile string
+	APIURL  string
+	Token   string
+	Project string
+}
+
+// Resolve returns the API server a command talks to. Flags win, then $KIBASHIP_API_URL and
+// $KIBASHIP_API_KEY, which keep CI jobs working without a config file, then the selected profile
+// with its token read from the keychain.
+func Resolve(profileFlag, apiURL, token string) (*Connection, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return resolve(cfg, SystemKeychain(), profileFlag, apiURL, token)
+}
+
+func resolve(cfg *Config, keychain Keychain, profileFlag, apiURL, token string) (*Connection, error) {
+	name := cfg.SelectedProfile(profileFlag)
+	profile, found := cfg.Profiles[name]
+	if !found && profileFlag != "" {
+		return nil, fmt.Errorf("profile %s does not exist, create it with kibaship login --profile %s", name, name)
+	}
+
+	conn := &Connection{Profile: name, APIURL: apiURL, Token: token, Project: profile.Project}
+	if conn.APIURL == "" {
+		conn.APIURL = os.Getenv("KIBASHIP_API_URL")
+	}
+	if conn.Token == "" {
+		conn.Token = os.Getenv("KIBASHIP_API_KEY")
+	}
+	if conn.APIURL == "" {
+		conn.APIURL = profile.APIURL
+	}
+	if conn.Token == "" {
+		conn.Token = profile.Token
+	}
+	if conn.Token == "" && found && keychain != nil {
+		stored, err := keychain.Get(name)
+		if err != nil && !errors.Is(err, ErrTokenNotFound) {
+			return nil, fmt.Errorf("failed to read the token of profile %s from the %s: %w", name, keychain.Name(), err)
+		}
+		conn.Token = stored
+	}
+
+	if conn.APIURL == "" || conn.Token == "" {
+		return nil, fmt.Errorf("not logged in, run kibaship login or set KIBASHIP_API_URL and KIBASHIP_API_KEY")
+	}
+	return conn, nil
+}
@@ -12,6 +12,7 @@ var (
 	AccentColor  = lipgloss.Color("#F59E0B")
 	TextColor    = lipgloss.Color("#E5E7EB")
 	MutedColor   = lipgloss.Color("#9CA3AF")
+	ErrorColor   = lipgloss.Color("#EF4444")
 
 	// Styles
 	TitleStyle = lipgloss.NewStyle().
@@ -36,6 +37,10 @@ var (
 
 	DescriptionStyle = lipgloss.NewStyle().
 				Foreground(MutedColor)
+
+	ErrorStyle = lipgloss.NewStyle().
+			Foreground(ErrorColor).
+			Bold(true)
 )
 
 // ASCII art banner for Kibaship
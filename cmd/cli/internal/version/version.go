@@ -18,6 +18,8 @@ var ComponentVersions = map[string]string{
 	"mysql-operator":    "9.4.0-2.2.5",
 	"valkey-operator":   "1.0.0", // TODO: Set actual default version
 	"acme-dns":          "1.0.0", // TODO: Set actual default version
+	"tekton-pipelines":  "1.4.0",
+	"gateway-api":       "1.2.0",
 }
 
 // HetznerRobotComponentVersions defines the default versions for hetznerrobot provider components
@@ -6,7 +6,13 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/kibamail/kibaship/cmd/cli/commands/apply"
 	"github.com/kibamail/kibaship/cmd/cli/commands/clusters"
+	"github.com/kibamail/kibaship/cmd/cli/commands/dashboard"
+	"github.com/kibamail/kibaship/cmd/cli/commands/install"
+	"github.com/kibamail/kibaship/cmd/cli/commands/login"
+	"github.com/kibamail/kibaship/cmd/cli/commands/metadata"
+	"github.com/kibamail/kibaship/cmd/cli/commands/profiles"
 	"github.com/kibamail/kibaship/cmd/cli/internal/styles"
 )
 
@@ -26,7 +32,15 @@ func printHelp() {
 		name        string
 		description string
 	}{
+		{"apply", "Apply a kibaship.yaml manifest to a project"},
 		{"clusters", "Manage Kubernetes clusters"},
+		{"completion", "Generate the completion script of bash, zsh, fish or powershell"},
+		{"dashboard", "Watch and deploy the applications of a project"},
+		{"install", "Install kibaship on an existing Kubernetes cluster"},
+		{"login", "Save the credentials of a kibaship API server"},
+		{"logout", "Remove the API key of a profile"},
+		{"profiles", "List and switch profiles"},
+		{"uninstall", "Remove kibaship and every platform resource from a cluster"},
 		{"version", "Show version information"},
 	}
 
@@ -38,6 +52,9 @@ func printHelp() {
 
 	fmt.Println()
 	fmt.Println(styles.HelpStyle.Render("Flags:"))
+	fmt.Printf("  %s  %s\n",
+		styles.CommandStyle.Render("--profile"),
+		styles.DescriptionStyle.Render("Profile of ~/.kibaship/config to use (defaults to $KIBASHIP_PROFILE)"))
 	fmt.Printf("  %s  %s\n",
 		styles.CommandStyle.Render("-h, --help"),
 		styles.DescriptionStyle.Render("Show help for any command"))
@@ -72,14 +89,29 @@ func init() {
 		},
 	}
 
-	// Override root command help to use our styled help
+	// Override root command help to use our styled help, commands without their own keep cobra's
+	defaultHelp := rootCmd.HelpFunc()
 	rootCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		if cmd != rootCmd {
+			defaultHelp(cmd, args)
+			return
+		}
 		printHelp()
 	})
 
+	rootCmd.PersistentFlags().String("profile", "", "Profile of ~/.kibaship/config to use (defaults to $KIBASHIP_PROFILE)")
+
 	// Add commands to root
+	rootCmd.AddCommand(apply.NewCommand())
 	rootCmd.AddCommand(clusters.NewCommand())
+	rootCmd.AddCommand(dashboard.NewCommand())
+	rootCmd.AddCommand(install.NewCommand())
+	rootCmd.AddCommand(login.NewCommand())
+	rootCmd.AddCommand(login.NewLogoutCommand())
+	rootCmd.AddCommand(profiles.NewCommand())
+	rootCmd.AddCommand(install.NewUninstallCommand())
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(metadata.NewCommand(rootCmd))
 }
 
 func main() {
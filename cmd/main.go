@@ -19,8 +19,13 @@ package main
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -34,15 +39,24 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	platformv1beta1 "github.com/kibamail/kibaship/api/v1beta1"
 	"github.com/kibamail/kibaship/internal/bootstrap"
 	"github.com/kibamail/kibaship/internal/controller"
+	"github.com/kibamail/kibaship/internal/migrations"
 	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/envcrypt"
+	"github.com/kibamail/kibaship/pkg/logalert"
+	"github.com/kibamail/kibaship/pkg/pullrequest"
 	"github.com/kibamail/kibaship/pkg/webhooks"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	// +kubebuilder:scaffold:imports
@@ -57,6 +71,9 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(platformv1alpha1.AddToScheme(scheme))
+	// v1beta1 is converted to v1alpha1 by the conversion webhook, registered along the
+	// validating webhooks of the v1alpha1 types
+	utilruntime.Must(platformv1beta1.AddToScheme(scheme))
 	utilruntime.Must(tektonv1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
@@ -65,10 +82,45 @@ func init() {
 func main() {
 	var enableLeaderElection bool
 	var probeAddr string
+	var shard string
+	var faultInjection bool
+	var controllerConcurrency string
+	var usePriorityQueue bool
+	var teardown bool
+	var teardownTimeout time.Duration
+	var teardownRemoveFinalizers bool
+	tuning := controller.DefaultControllerTuning()
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&shard, "shard", "",
+		"The shard of projects reconciled by this controller manager, matched against the "+
+			"platform.kibaship.com/shard project label. The default shard reconciles projects without the label.")
+	flag.BoolVar(&faultInjection, "enable-fault-injection", false,
+		"Honor the fault.kibaship.com annotations that force PipelineRun failures, delay pod readiness and "+
+			"fail certificate issuance. For e2e test clusters only, never enable it in production.")
+	flag.StringVar(&controllerConcurrency, "controller-concurrency", "",
+		"Comma separated controller=workers pairs overriding the concurrent reconciles of controllers, "+
+			"such as deployment=20,deployment-progress=40,project-cost=2.")
+	flag.DurationVar(&tuning.BaseDelay, "requeue-base-delay", tuning.BaseDelay,
+		"The delay before the first retry of a failed reconcile, doubled on every further failure.")
+	flag.DurationVar(&tuning.MaxDelay, "requeue-max-delay", tuning.MaxDelay,
+		"The longest delay between the retries of a failed reconcile.")
+	flag.Float64Var(&tuning.QPS, "requeue-qps", tuning.QPS,
+		"The rate at which each controller requeues objects, 0 lifts the limit.")
+	flag.IntVar(&tuning.Burst, "requeue-burst", tuning.Burst,
+		"The requeues each controller may burst above --requeue-qps.")
+	flag.BoolVar(&usePriorityQueue, "priority-queue", true,
+		"Queue the objects listed at startup and on resyncs behind the objects that just changed, so new "+
+			"deployments are reconciled first on large clusters.")
+	flag.BoolVar(&teardown, "teardown", false,
+		"Delete every platform resource, in dependency order, and the objects created by the bootstrap, "+
+			"then exit. Run it as a Job while the operator is still running so the finalizers are processed.")
+	flag.DurationVar(&teardownTimeout, "teardown-timeout", 5*time.Minute,
+		"How long --teardown waits for each kind of resource to be deleted.")
+	flag.BoolVar(&teardownRemoveFinalizers, "teardown-remove-finalizers", false,
+		"Remove the finalizers of resources still present after --teardown-timeout.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -77,11 +129,47 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if teardown {
+		runTeardown(teardownTimeout, teardownRemoveFinalizers)
+		return
+	}
+
+	if err := controller.SetOperatorShard(shard); err != nil {
+		setupLog.Error(err, "invalid shard")
+		os.Exit(1)
+	}
+
+	if faultInjection {
+		controller.SetFaultInjection(true)
+		setupLog.Info("Fault injection is enabled, fault.kibaship.com annotations force failures")
+	}
+
+	concurrency, err := controller.ParseControllerConcurrency(controllerConcurrency)
+	if err != nil {
+		setupLog.Error(err, "invalid controller concurrency")
+		os.Exit(1)
+	}
+	tuning.Concurrency = concurrency
+	if err := controller.SetControllerTuning(tuning); err != nil {
+		setupLog.Error(err, "invalid requeue settings")
+		os.Exit(1)
+	}
+
+	// Every shard elects its own leader so shards reconcile side by side
+	leaderElectionID := "d3e53d55.operator.kibaship.com"
+	if shard != "" {
+		leaderElectionID = shard + "-" + leaderElectionID
+		setupLog.Info("Reconciling projects of shard", "shard", shard)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "d3e53d55.operator.kibaship.com",
+		LeaderElectionID:       leaderElectionID,
+		Controller: ctrlconfig.Controller{
+			UsePriorityQueue: &usePriorityQueue,
+		},
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -127,68 +215,194 @@ func main() {
 	setupLog.Info("Operator configuration loaded successfully",
 		"domain", opConfig.Domain,
 		"webhookURL", opConfig.WebhookURL,
+		"eventsBackend", opConfig.Events.Backend,
 		"acmeEmail", opConfig.ACMEEmail,
-		"gatewayClassName", opConfig.GatewayClassName)
+		"gatewayClassName", opConfig.GatewayClassName,
+		"ingressProvider", opConfig.IngressProvider,
+		"ingressClassName", opConfig.IngressClassName)
 
 	// Set the global operator configuration
-	if err := controller.SetOperatorConfig(opConfig.Domain, opConfig.GatewayClassName); err != nil {
+	if err := controller.SetOperatorConfigWithIngress(
+		opConfig.Domain,
+		opConfig.IngressProvider,
+		opConfig.GatewayClassName,
+		opConfig.IngressClassName,
+	); err != nil {
 		setupLog.Error(err, "failed to set operator configuration")
 		os.Exit(1)
 	}
+	controller.SetBuildScheduling(opConfig.Builds)
+	controller.SetBuildKitPool(opConfig.BuildKit)
+	controller.SetTLSPolicy(opConfig.TLS)
+	platformv1alpha1.SetImagePolicy(opConfig.Images)
+	platformv1alpha1.SetBaseDomainPolicy(opConfig.BaseDomainPolicy())
+	controller.SetImageMirror(opConfig.ImageMirror)
+	controller.SetDrainPeriod(opConfig.DrainPeriod)
+
+	// Bootstrap: ensure storage classes first, then provision dynamic ingress/cert-manager resources.
+	// The outcome of every step is recorded in the bootstrap status ConfigMap and failed steps are
+	// retried in the background once the manager starts.
+	bootstrap.SetImageMirror(opConfig.ImageMirror)
+	bootstrapStatus := bootstrap.NewStatusRecorder(uncachedClient, opConfig)
+	// The bootstrap steps and data migrations are cluster-wide, only the operator of the default
+	// shard runs them and records their status
+	if shard == "" {
+		setupLog.Info("Starting bootstrap process")
+		setupLog.Info("Bootstrap step 1: Ensuring storage classes")
+		if err := bootstrapStatus.Run(context.Background(), "storage-classes", func(ctx context.Context, cfg *config.OperatorConfiguration) error {
+			return bootstrap.EnsureStorageClasses(ctx, uncachedClient, cfg.StorageClasses)
+		}); err != nil {
+			setupLog.Error(err, "bootstrap storage classes failed (continuing)")
+		} else {
+			setupLog.Info("Bootstrap step 1: Storage classes completed successfully")
+		}
 
-	// Bootstrap: ensure storage classes first, then provision dynamic ingress/cert-manager resources
-	setupLog.Info("Starting bootstrap process")
-	setupLog.Info("Bootstrap step 1: Ensuring storage classes")
-	if err := bootstrap.EnsureStorageClasses(context.Background(), uncachedClient); err != nil {
-		setupLog.Error(err, "bootstrap storage classes failed (continuing)")
-	} else {
-		setupLog.Info("Bootstrap step 1: Storage classes completed successfully")
-	}
+		setupLog.Info("Bootstrap step 2: Ensuring priority classes")
+		if err := bootstrapStatus.Run(context.Background(), "priority-classes", func(ctx context.Context, _ *config.OperatorConfiguration) error {
+			return bootstrap.EnsurePriorityClasses(ctx, uncachedClient)
+		}); err != nil {
+			setupLog.Error(err, "bootstrap priority classes failed (continuing)")
+		} else {
+			setupLog.Info("Bootstrap step 2: Priority classes completed successfully")
+		}
 
-	acmeEmail := opConfig.ACMEEmail
-	baseDomain := opConfig.Domain
-	setupLog.Info("Bootstrap step 2: Provisioning ingress and certificates", "domain", baseDomain, "acmeEmail", acmeEmail, "acmeEnv", opConfig.ACMEEnv)
-	if err := bootstrap.ProvisionIngressAndCertificates(
-		context.Background(),
-		uncachedClient,
-		baseDomain,
-		acmeEmail,
-		opConfig.ACMEEnv,
-		opConfig.GatewayClassName,
-	); err != nil {
-		setupLog.Error(err, "bootstrap provisioning failed (continuing)")
-	} else {
-		setupLog.Info("Bootstrap step 2: Ingress and certificates completed successfully")
-	}
+		setupLog.Info("Bootstrap step 3: Ensuring load balancer IP pools", "provider", opConfig.LoadBalancer.Provider)
+		if err := bootstrapStatus.Run(context.Background(), "load-balancer", func(ctx context.Context, cfg *config.OperatorConfiguration) error {
+			return bootstrap.ProvisionLoadBalancer(ctx, uncachedClient, cfg.LoadBalancer)
+		}); err != nil {
+			setupLog.Error(err, "bootstrap load balancer IP pools failed (continuing)")
+		} else {
+			setupLog.Info("Bootstrap step 3: Load balancer IP pools completed successfully")
+		}
 
-	// Bootstrap: ensure registry credentials are provisioned
-	setupLog.Info("Bootstrap step 3: Ensuring registry credentials")
-	if err := bootstrap.EnsureRegistryCredentials(context.Background(), uncachedClient); err != nil {
-		setupLog.Error(err, "bootstrap registry credentials failed (continuing)")
-	} else {
-		setupLog.Info("Bootstrap step 3: Registry credentials completed successfully")
-	}
+		setupLog.Info("Bootstrap step 4: Provisioning ingress and certificates", "domain", opConfig.Domain, "acmeEmail", opConfig.ACMEEmail, "acmeEnv", opConfig.ACMEEnv)
+		if err := bootstrapStatus.Run(context.Background(), "ingress-certificates", func(ctx context.Context, cfg *config.OperatorConfiguration) error {
+			return bootstrap.ProvisionIngressAndCertificates(
+				ctx,
+				uncachedClient,
+				cfg.Domain,
+				cfg.ACMEEmail,
+				cfg.ACMEEnv,
+				cfg.ACMEDNS,
+				cfg.GatewayClassName,
+				cfg.IngressProvider,
+			)
+		}); err != nil {
+			setupLog.Error(err, "bootstrap provisioning failed (continuing)")
+		} else {
+			setupLog.Info("Bootstrap step 4: Ingress and certificates completed successfully")
+		}
+
+		// Bootstrap: ensure registry credentials are provisioned
+		setupLog.Info("Bootstrap step 5: Ensuring registry credentials")
+		if err := bootstrapStatus.Run(context.Background(), "registry-credentials", func(ctx context.Context, _ *config.OperatorConfiguration) error {
+			return bootstrap.EnsureRegistryCredentials(ctx, uncachedClient)
+		}); err != nil {
+			setupLog.Error(err, "bootstrap registry credentials failed (continuing)")
+		} else {
+			setupLog.Info("Bootstrap step 5: Registry credentials completed successfully")
+		}
+
+		// Bootstrap: ensure registry JWKS secret is provisioned
+		setupLog.Info("Bootstrap step 6: Ensuring registry JWKS secret")
+		if err := bootstrapStatus.Run(context.Background(), "registry-jwks", func(ctx context.Context, _ *config.OperatorConfiguration) error {
+			return bootstrap.EnsureRegistryJWKS(ctx, uncachedClient)
+		}); err != nil {
+			setupLog.Error(err, "bootstrap registry JWKS failed (continuing)")
+		} else {
+			setupLog.Info("Bootstrap step 6: Registry JWKS completed successfully")
+		}
+
+		// Bootstrap: copy registry CA certificate to buildkit namespace
+		setupLog.Info("Bootstrap step 7: Ensuring registry CA certificate in buildkit namespace")
+		if err := bootstrapStatus.Run(context.Background(), "registry-ca-buildkit", func(ctx context.Context, _ *config.OperatorConfiguration) error {
+			return bootstrap.EnsureRegistryCACertificateInBuildkit(ctx, uncachedClient)
+		}); err != nil {
+			setupLog.Error(err, "bootstrap registry CA certificate in buildkit failed (continuing)")
+		} else {
+			setupLog.Info("Bootstrap step 7: Registry CA certificate in buildkit completed successfully")
+		}
+
+		setupLog.Info("Bootstrap step 8: Provisioning logging pipeline", "provider", opConfig.Logging.Provider, "collector", opConfig.Logging.Collector)
+		if err := bootstrapStatus.Run(context.Background(), "logging", func(ctx context.Context, cfg *config.OperatorConfiguration) error {
+			return bootstrap.ProvisionLogging(ctx, uncachedClient, cfg.Logging)
+		}); err != nil {
+			setupLog.Error(err, "bootstrap logging pipeline failed (continuing)")
+		} else {
+			setupLog.Info("Bootstrap step 8: Logging pipeline completed successfully")
+		}
+
+		setupLog.Info("Bootstrap step 9: Provisioning artifact service", "provider", opConfig.Artifacts.Provider)
+		if err := bootstrapStatus.Run(context.Background(), "artifacts", func(ctx context.Context, cfg *config.OperatorConfiguration) error {
+			return bootstrap.ProvisionArtifacts(ctx, uncachedClient, cfg.Artifacts)
+		}); err != nil {
+			setupLog.Error(err, "bootstrap artifact service failed (continuing)")
+		} else {
+			setupLog.Info("Bootstrap step 9: Artifact service completed successfully")
+		}
+
+		setupLog.Info("Bootstrap step 10: Ensuring registry mirror", "mirror", opConfig.ImageMirror)
+		if err := bootstrapStatus.Run(context.Background(), "registry-mirror", func(ctx context.Context, cfg *config.OperatorConfiguration) error {
+			// An unreachable mirror is reported but the BuildKit mirrors are still configured
+			validateErr := bootstrap.ValidateImageMirror(ctx, cfg.ImageMirror)
+			if validateErr != nil {
+				setupLog.Error(validateErr, "bootstrap registry mirror validation failed (continuing)")
+				validateErr = fmt.Errorf("validate registry mirror: %w", validateErr)
+			}
+			return errors.Join(validateErr, bootstrap.EnsureBuildkitRegistryMirrors(ctx, uncachedClient, cfg.ImageMirror))
+		}); err != nil {
+			setupLog.Error(err, "bootstrap BuildKit registry mirrors failed (continuing)")
+		} else {
+			setupLog.Info("Bootstrap step 10: BuildKit registry mirrors completed successfully")
+		}
+
+		setupLog.Info("Bootstrap step 11: Provisioning monitoring stack", "provider", opConfig.Monitoring.Provider)
+		if err := bootstrapStatus.Run(context.Background(), "monitoring", func(ctx context.Context, cfg *config.OperatorConfiguration) error {
+			return bootstrap.ProvisionMonitoring(ctx, uncachedClient, cfg.Monitoring)
+		}); err != nil {
+			setupLog.Error(err, "bootstrap monitoring stack failed (continuing)")
+		} else {
+			setupLog.Info("Bootstrap step 11: Monitoring stack completed successfully")
+		}
 
-	// Bootstrap: ensure registry JWKS secret is provisioned
-	setupLog.Info("Bootstrap step 4: Ensuring registry JWKS secret")
-	if err := bootstrap.EnsureRegistryJWKS(context.Background(), uncachedClient); err != nil {
-		setupLog.Error(err, "bootstrap registry JWKS failed (continuing)")
+		setupLog.Info("Bootstrap process completed")
+		if err := mgr.Add(bootstrapStatus); err != nil {
+			setupLog.Error(err, "unable to add bootstrap retries to the manager")
+			os.Exit(1)
+		}
+
+		// Data migrations bring the resources of existing clusters up to date with the CRDs, a failed
+		// migration is retried on the next start
+		if err := migrations.Apply(context.Background(), uncachedClient); err != nil {
+			setupLog.Error(err, "data migrations failed (continuing)")
+		}
 	} else {
-		setupLog.Info("Bootstrap step 4: Registry JWKS completed successfully")
+		setupLog.Info("Skipping bootstrap and data migrations, they are run by the operator of the default shard")
 	}
 
-	// Bootstrap: copy registry CA certificate to buildkit namespace
-	setupLog.Info("Bootstrap step 5: Ensuring registry CA certificate in buildkit namespace")
-	if err := bootstrap.EnsureRegistryCACertificateInBuildkit(context.Background(), uncachedClient); err != nil {
-		setupLog.Error(err, "bootstrap registry CA certificate in buildkit failed (continuing)")
-	} else {
-		setupLog.Info("Bootstrap step 5: Registry CA certificate in buildkit completed successfully")
+	// Env var encryption at rest: load the KMS provider used to decrypt env Secrets
+	encryptor, err := envcrypt.Load(context.Background(), uncachedClient, opConfig.Encryption)
+	if err != nil {
+		setupLog.Error(err, "failed to initialize env var encryption")
+		os.Exit(1)
 	}
+	if encryptor != nil {
+		setupLog.Info("Env var encryption enabled", "provider", encryptor.ProviderName())
+	}
+	// Pods of deployments with encrypted env vars fetch the decrypted values from the webhook
+	// server when they start, so the plaintext is never stored in the cluster
+	mgr.GetWebhookServer().Register(controller.EnvDecryptPath, &controller.EnvDecryptHandler{
+		Client:    uncachedClient,
+		Encryptor: encryptor,
+	})
 
-	setupLog.Info("Bootstrap process completed")
+	// Pipeline steps publish their artifacts to the artifact service when collection is enabled
+	var artifactsURL string
+	if opConfig.Artifacts.Enabled() {
+		artifactsURL = opConfig.Artifacts.Endpoint()
+	}
 
 	// Webhook configuration: ensure signing Secret exists
-	webhookURL := opConfig.WebhookURL
 	kcs, err := kubernetes.NewForConfig(mgr.GetConfig())
 	if err != nil {
 		setupLog.Error(err, "failed to build clientset")
@@ -252,8 +466,26 @@ func main() {
 		}
 	}
 
-	// Build notifier (inject cache-backed reader for enrichment)
-	n := webhooks.NewHTTPNotifier(webhookURL, signingKey, mgr.GetClient())
+	// Build notifier of the configured event backend (inject cache-backed reader for enrichment)
+	notifierSettings := func(cfg *config.OperatorConfiguration) webhooks.NotifierSettings {
+		return webhooks.NotifierSettings{
+			WebhookURL:        cfg.WebhookURL,
+			SigningKey:        signingKey,
+			NATSURL:           cfg.Events.NATSURL,
+			NATSStream:        cfg.Events.NATSStream,
+			SubjectPrefix:     cfg.Events.SubjectPrefix,
+			EnrichDeployments: cfg.WebhookEnrichDeployments,
+			Reader:            mgr.GetClient(),
+		}
+	}
+	n, err := webhooks.NewNotifier(context.Background(), opConfig.Events.Backend, notifierSettings(opConfig))
+	if err != nil {
+		setupLog.Error(err, "unable to create notifier", "backend", opConfig.Events.Backend)
+		os.Exit(1)
+	}
+	if closer, ok := n.(io.Closer); ok {
+		defer func() { _ = closer.Close() }()
+	}
 
 	// Now set up controllers
 	if err := (&controller.ProjectReconciler{
@@ -262,6 +494,7 @@ func main() {
 		NamespaceManager: controller.NewNamespaceManager(mgr.GetClient()),
 		Validator:        controller.NewProjectValidator(mgr.GetClient()),
 		Notifier:         n,
+		Recorder:         mgr.GetEventRecorderFor("project-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Project")
 		os.Exit(1)
@@ -275,6 +508,7 @@ func main() {
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Notifier: n,
+		Recorder: mgr.GetEventRecorderFor("environment-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Environment")
 		os.Exit(1)
@@ -298,33 +532,126 @@ func main() {
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Notifier: n,
+		Recorder: mgr.GetEventRecorderFor("application-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Application")
 		os.Exit(1)
 	}
+	// Builds dispatched to a namespace pool run in the operator cluster unless a kubeconfig
+	// Secret points at a dedicated build cluster
+	var remoteBuilds *controller.RemoteBuildExecutor
+	var buildCluster cluster.Cluster
+	if opConfig.Builds.Remote() {
+		buildConfig := mgr.GetConfig()
+		if opConfig.Builds.RemoteKubeconfigSecret != "" {
+			var secret corev1.Secret
+			if err := uncachedClient.Get(context.Background(), client.ObjectKey{
+				Namespace: config.OperatorNamespace,
+				Name:      opConfig.Builds.RemoteKubeconfigSecret,
+			}, &secret); err != nil {
+				setupLog.Error(err, "failed to get build cluster kubeconfig Secret")
+				os.Exit(1)
+			}
+			buildConfig, err = clientcmd.RESTConfigFromKubeConfig(secret.Data["kubeconfig"])
+			if err != nil {
+				setupLog.Error(err, "invalid build cluster kubeconfig")
+				os.Exit(1)
+			}
+		}
+
+		namespaces := map[string]cache.Config{}
+		for _, namespace := range opConfig.Builds.RemoteNamespaces {
+			namespaces[namespace] = cache.Config{}
+		}
+		buildCluster, err = cluster.New(buildConfig, func(o *cluster.Options) {
+			o.Scheme = mgr.GetScheme()
+			o.Cache.DefaultNamespaces = namespaces
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to create build cluster client")
+			os.Exit(1)
+		}
+		if err := mgr.Add(buildCluster); err != nil {
+			setupLog.Error(err, "unable to add build cluster to manager")
+			os.Exit(1)
+		}
+		remoteBuilds = &controller.RemoteBuildExecutor{
+			Client:     buildCluster.GetClient(),
+			Namespaces: opConfig.Builds.RemoteNamespaces,
+			Registry:   opConfig.Builds.RemoteRegistry,
+		}
+		setupLog.Info("Dispatching builds to the build namespace pool",
+			"namespaces", opConfig.Builds.RemoteNamespaces,
+			"remoteCluster", opConfig.Builds.RemoteKubeconfigSecret != "")
+	}
+
 	if err := (&controller.DeploymentReconciler{
 		Client:           mgr.GetClient(),
 		Scheme:           mgr.GetScheme(),
 		NamespaceManager: controller.NewNamespaceManager(mgr.GetClient()),
 		Notifier:         n,
 		Recorder:         mgr.GetEventRecorderFor("deployment-controller"),
+		Encryptor:        encryptor,
+		ArtifactsURL:     artifactsURL,
+		RemoteBuilds:     remoteBuilds,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Deployment")
 		os.Exit(1)
 	}
+	if buildCluster != nil {
+		if err := (&controller.RemoteBuildStatusReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+			Builds: buildCluster,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "RemoteBuildStatus")
+			os.Exit(1)
+		}
+	}
 	if err := (&controller.ApplicationDomainReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Notifier: n,
+		Recorder: mgr.GetEventRecorderFor("applicationdomain-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ApplicationDomain")
 		os.Exit(1)
 	}
+	if err := (&controller.UptimeCheckReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Notifier: n,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ApplicationDomainUptime")
+		os.Exit(1)
+	}
+	// Watch application pods and mirror crash loops and image pull failures to Applications
+	if err := (&controller.ApplicationHealthReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Notifier: n,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ApplicationHealth")
+		os.Exit(1)
+	}
+	// Evaluate application log alert rules against the persisted logs
+	if opConfig.Logging.Enabled() {
+		if err := (&controller.LogAlertReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Notifier: n,
+			Logs:     logalert.NewClient(opConfig.Logging.Endpoint()),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ApplicationLogAlert")
+			os.Exit(1)
+		}
+	}
 	// Watch cert-manager Certificates and mirror status to ApplicationDomains
 	if err := (&controller.CertificateWatcherReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Notifier: n,
+		Recorder: mgr.GetEventRecorderFor("certificate-watcher"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "CertificateWatcher")
 		os.Exit(1)
@@ -349,10 +676,109 @@ func main() {
 	}
 
 	// New: DeploymentProgressController - manages phase transitions and K8s resource creation
+	if err := (&controller.DeploymentEnvReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Encryptor: encryptor,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DeploymentEnv")
+		os.Exit(1)
+	}
+	if err := (&controller.ExternalEnvReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ExternalEnv")
+		os.Exit(1)
+	}
+
+	if err := (&controller.ProjectCostReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ProjectCost")
+		os.Exit(1)
+	}
+
+	if err := (&controller.ProjectRegistryUsageReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ProjectRegistryUsage")
+		os.Exit(1)
+	}
+
+	if err := (&controller.IdleReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ApplicationIdle")
+		os.Exit(1)
+	}
+
+	if err := (&controller.ReplicaScheduleReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ReplicaSchedule")
+		os.Exit(1)
+	}
+
+	if err := (&controller.AutoscalingReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Autoscaling")
+		os.Exit(1)
+	}
+
+	if err := (&controller.DatabaseAccessReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DatabaseAccess")
+		os.Exit(1)
+	}
+
+	if err := (&controller.ServiceDiscoveryReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ServiceDiscovery")
+		os.Exit(1)
+	}
+
+	// The resources of every shard are exported together, by the operator of the default shard
+	// A single operator manages the BuildKit pool, it removes the pool when it is disabled
+	if shard == "" {
+		if err := (&controller.BuildKitPoolReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "BuildKitPool")
+			os.Exit(1)
+		}
+	}
+
+	if opConfig.GitOps.Enabled() && shard == "" {
+		if err := (&controller.GitOpsExportReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+			Config: opConfig.GitOps,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "GitOpsExport")
+			os.Exit(1)
+		}
+		setupLog.Info("Exporting platform resources to git", "repository", opConfig.GitOps.RepositoryURL, "branch", opConfig.GitOps.Branch)
+	}
+
 	if err := (&controller.DeploymentProgressController{
 		Client:           mgr.GetClient(),
 		Scheme:           mgr.GetScheme(),
 		NamespaceManager: controller.NewNamespaceManager(mgr.GetClient()),
+		Encryptor:        encryptor,
+		PullRequests:     pullrequest.NewCommenter(pullrequest.GitHubAPIURL),
+		Recorder:         mgr.GetEventRecorderFor("deployment-progress-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DeploymentProgress")
 		os.Exit(1)
@@ -376,6 +802,31 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Hot-reload the operator configuration when the ConfigMap changes
+	if err := controller.NewOperatorConfigReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		mgr.GetEventRecorderFor("operator-config-controller"),
+		opConfig,
+		func(ctx context.Context, previous, current *config.OperatorConfiguration) error {
+			webhooks.Reconfigure(n, notifierSettings(current))
+			// The cluster-wide bootstrap steps are re-run by the operator of the default shard
+			if shard != "" {
+				bootstrap.SetImageMirror(current.ImageMirror)
+				return nil
+			}
+			bootstrapStatus.SetConfiguration(current)
+			return bootstrap.ApplyConfigurationChange(ctx, uncachedClient, previous, current)
+		},
+	).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OperatorConfig")
+		os.Exit(1)
+	}
+
+	if unknown := controller.UnknownConcurrencyOverrides(); len(unknown) > 0 {
+		setupLog.Info("Ignoring the concurrency of unknown controllers", "controllers", strings.Join(unknown, ","))
+	}
+
 	setupLog.Info("All controllers initialized")
 
 	ctx := ctrl.SetupSignalHandler()
@@ -386,3 +837,29 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runTeardown deletes the platform resources and bootstrap artifacts, logging every object that
+// could not be deleted, and exits non-zero when any is left
+func runTeardown(timeout time.Duration, removeFinalizers bool) {
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "Failed to create client")
+		os.Exit(1)
+	}
+
+	leftovers := bootstrap.Teardown(ctrl.SetupSignalHandler(), c, bootstrap.TeardownOptions{
+		Timeout:          timeout,
+		RemoveFinalizers: removeFinalizers,
+		Progress: func(message string) {
+			setupLog.Info(message)
+		},
+	})
+	for _, leftover := range leftovers {
+		setupLog.Info("Could not delete", "object", leftover.String(), "reason", leftover.Reason)
+	}
+	if len(leftovers) > 0 {
+		setupLog.Error(fmt.Errorf("%d objects could not be deleted", len(leftovers)), "Teardown incomplete")
+		os.Exit(1)
+	}
+	setupLog.Info("Teardown completed, delete the operator manifest to remove the operator, its webhooks and CRDs")
+}
@@ -0,0 +1,69 @@
+package activator
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// EnvWakeTimeoutSeconds overrides how long a request is held while a sleeping application wakes
+	EnvWakeTimeoutSeconds = "ACTIVATOR_WAKE_TIMEOUT_SECONDS"
+
+	// MinWakeTimeoutSeconds and MaxWakeTimeoutSeconds bound the configurable wake timeout
+	MinWakeTimeoutSeconds = 10
+	MaxWakeTimeoutSeconds = 600
+)
+
+// Config holds the configuration for the activator
+// All values are hardcoded since the deployment environment is fully known,
+// except for the wake timeout which can be set through the environment
+type Config struct {
+	Server struct {
+		Listen string
+	}
+	Wake struct {
+		Timeout      time.Duration
+		PollInterval time.Duration
+	}
+	// RecordInterval throttles how often the last request time of an application is written
+	RecordInterval time.Duration
+	// ResolveTTL is how long the host to application mapping is cached
+	ResolveTTL time.Duration
+}
+
+// LoadConfig returns the configuration with hardcoded values
+// The deployment knows:
+// - We run on port 8080
+// - Requests wait up to 2 minutes for an application to wake (override with ACTIVATOR_WAKE_TIMEOUT_SECONDS)
+// - Last request times are recorded at most every 30 seconds per application
+// - Host lookups are cached for 30 seconds
+func LoadConfig() Config {
+	cfg := Config{}
+
+	// Server configuration
+	cfg.Server.Listen = ":8080"
+
+	// Wake configuration
+	cfg.Wake.Timeout = 2 * time.Minute
+	cfg.Wake.PollInterval = 500 * time.Millisecond
+
+	if raw := os.Getenv(EnvWakeTimeoutSeconds); raw != "" {
+		timeout, err := strconv.Atoi(raw)
+		switch {
+		case err != nil:
+			log.Printf("config: ignoring invalid %s=%q: %v", EnvWakeTimeoutSeconds, raw, err)
+		case timeout < MinWakeTimeoutSeconds || timeout > MaxWakeTimeoutSeconds:
+			log.Printf("config: ignoring %s=%d, must be between %d and %d",
+				EnvWakeTimeoutSeconds, timeout, MinWakeTimeoutSeconds, MaxWakeTimeoutSeconds)
+		default:
+			cfg.Wake.Timeout = time.Duration(timeout) * time.Second
+		}
+	}
+
+	cfg.RecordInterval = 30 * time.Second
+	cfg.ResolveTTL = 30 * time.Second
+
+	return cfg
+}
@@ -0,0 +1,174 @@
+package activator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/idle"
+	"github.com/kibamail/kibaship/pkg/utils"
+)
+
+// Handler proxies requests to applications, waking them first when they sleep
+type Handler struct {
+	client   client.Client
+	resolver *Resolver
+	config   Config
+
+	mu       sync.Mutex
+	recorded map[string]time.Time
+}
+
+// NewHandler creates a new activator handler
+func NewHandler(c client.Client, resolver *Resolver, config Config) *Handler {
+	return &Handler{
+		client:   c,
+		resolver: resolver,
+		config:   config,
+		recorded: make(map[string]time.Time),
+	}
+}
+
+// ServeHTTP holds the request until the application has a ready pod and proxies it there
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	target, err := h.resolver.Resolve(ctx, r.Host)
+	if err != nil {
+		log.Printf("resolve %s: %v", r.Host, err)
+		http.Error(w, "application not found", http.StatusNotFound)
+		return
+	}
+
+	h.record(target)
+
+	var service corev1.Service
+	if err := h.client.Get(ctx, client.ObjectKey{
+		Namespace: target.Namespace,
+		Name:      idle.PrivateServiceName(utils.GetServiceName(target.ApplicationUUID)),
+	}, &service); err != nil {
+		log.Printf("get private service for application %s: %v", target.ApplicationUUID, err)
+		http.Error(w, "application unavailable", http.StatusBadGateway)
+		return
+	}
+	if len(service.Spec.Ports) == 0 {
+		http.Error(w, "application unavailable", http.StatusBadGateway)
+		return
+	}
+
+	if err := h.awaitReady(ctx, target, service.Name); err != nil {
+		log.Printf("wake application %s: %v", target.ApplicationUUID, err)
+		http.Error(w, "application is starting, try again shortly", http.StatusServiceUnavailable)
+		return
+	}
+
+	backend := &url.URL{
+		Scheme: "http",
+		Host:   fmt.Sprintf("%s.%s.svc:%d", service.Name, service.Namespace, service.Spec.Ports[0].Port),
+	}
+	proxy := httputil.NewSingleHostReverseProxy(backend)
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		// Keep the original host so applications see the domain they are served on
+		req.Host = r.Host
+	}
+	proxy.ServeHTTP(w, r)
+}
+
+// awaitReady wakes a sleeping application and waits until its private Service has a ready endpoint
+func (h *Handler) awaitReady(ctx context.Context, target Target, serviceName string) error {
+	ready, err := h.hasReadyEndpoints(ctx, target.Namespace, serviceName)
+	if err != nil {
+		return err
+	}
+	if ready {
+		return nil
+	}
+
+	woken, err := idle.Wake(ctx, h.client, target.Namespace, target.ApplicationUUID)
+	if err != nil {
+		return err
+	}
+	if woken {
+		log.Printf("woke application %s", target.ApplicationUUID)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.config.Wake.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(h.config.Wake.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for a ready pod: %w", ctx.Err())
+		case <-ticker.C:
+			ready, err := h.hasReadyEndpoints(ctx, target.Namespace, serviceName)
+			if err != nil {
+				return err
+			}
+			if ready {
+				return nil
+			}
+		}
+	}
+}
+
+func (h *Handler) hasReadyEndpoints(ctx context.Context, namespace, serviceName string) (bool, error) {
+	var slices discoveryv1.EndpointSliceList
+	if err := h.client.List(ctx, &slices, client.InNamespace(namespace), client.MatchingLabels{
+		discoveryv1.LabelServiceName: serviceName,
+	}); err != nil {
+		return false, fmt.Errorf("failed to list endpoint slices: %w", err)
+	}
+	for _, slice := range slices.Items {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// record writes the last request time of an application, at most once per RecordInterval
+func (h *Handler) record(target Target) {
+	now := time.Now()
+
+	h.mu.Lock()
+	if last, ok := h.recorded[target.ApplicationUUID]; ok && now.Sub(last) < h.config.RecordInterval {
+		h.mu.Unlock()
+		return
+	}
+	h.recorded[target.ApplicationUUID] = now
+	h.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var service corev1.Service
+		if err := h.client.Get(ctx, client.ObjectKey{
+			Namespace: target.Namespace,
+			Name:      idle.PrivateServiceName(utils.GetServiceName(target.ApplicationUUID)),
+		}, &service); err != nil {
+			log.Printf("record request for application %s: %v", target.ApplicationUUID, err)
+			return
+		}
+		patch := client.MergeFrom(service.DeepCopy())
+		idle.SetLastRequest(&service, now)
+		if err := h.client.Patch(ctx, &service, patch); err != nil {
+			log.Printf("record request for application %s: %v", target.ApplicationUUID, err)
+		}
+	}()
+}
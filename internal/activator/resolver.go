@@ -0,0 +1,94 @@
+package activator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// Target identifies the application serving a host
+type Target struct {
+	Namespace       string
+	ApplicationUUID string
+}
+
+// Resolver maps request hosts to applications through their ApplicationDomains
+type Resolver struct {
+	client client.Reader
+	ttl    time.Duration
+
+	mu        sync.RWMutex
+	targets   map[string]Target
+	expiresAt time.Time
+}
+
+// NewResolver creates a new resolver caching the domain table for ttl
+func NewResolver(c client.Reader, ttl time.Duration) *Resolver {
+	return &Resolver{client: c, ttl: ttl}
+}
+
+// Resolve returns the application serving host
+func (r *Resolver) Resolve(ctx context.Context, host string) (Target, error) {
+	host = normalizeHost(host)
+
+	r.mu.RLock()
+	target, ok := r.targets[host]
+	fresh := time.Now().Before(r.expiresAt)
+	r.mu.RUnlock()
+	if ok && fresh {
+		return target, nil
+	}
+
+	// Unknown hosts refresh the table too, the domain may have been created since
+	if err := r.refresh(ctx); err != nil {
+		return Target{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	target, ok = r.targets[host]
+	if !ok {
+		return Target{}, fmt.Errorf("no application serves host %s", host)
+	}
+	return target, nil
+}
+
+func (r *Resolver) refresh(ctx context.Context) error {
+	var domains v1alpha1.ApplicationDomainList
+	if err := r.client.List(ctx, &domains); err != nil {
+		return fmt.Errorf("failed to list application domains: %w", err)
+	}
+
+	targets := make(map[string]Target, len(domains.Items))
+	for _, domain := range domains.Items {
+		appUUID := domain.Labels[validation.LabelApplicationUUID]
+		if appUUID == "" || domain.Spec.Domain == "" {
+			continue
+		}
+		targets[normalizeHost(domain.Spec.Domain)] = Target{
+			Namespace:       domain.Namespace,
+			ApplicationUUID: appUUID,
+		}
+	}
+
+	r.mu.Lock()
+	r.targets = targets
+	r.expiresAt = time.Now().Add(r.ttl)
+	r.mu.Unlock()
+	return nil
+}
+
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
@@ -0,0 +1,91 @@
+package activator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// Server wraps the HTTP server for the activator
+type Server struct {
+	handler *Handler
+	config  Config
+	server  *http.Server
+}
+
+// NewServer creates a new activator server
+func NewServer(cfg Config) (*Server, error) {
+	// Initialize Kubernetes client
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		corev1.AddToScheme,
+		appsv1.AddToScheme,
+		discoveryv1.AddToScheme,
+		v1alpha1.AddToScheme,
+	} {
+		if err := add(scheme); err != nil {
+			return nil, fmt.Errorf("failed to build scheme: %w", err)
+		}
+	}
+
+	restConfig, err := config.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubernetes config: %w", err)
+	}
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	// Initialize handler
+	handler := NewHandler(k8sClient, NewResolver(k8sClient, cfg.ResolveTTL), cfg)
+
+	// Create HTTP server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.Handle("/", handler)
+
+	server := &http.Server{
+		Addr:              cfg.Server.Listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+
+	return &Server{
+		handler: handler,
+		config:  cfg,
+		server:  server,
+	}, nil
+}
+
+// Start starts the HTTP server
+func (s *Server) Start() error {
+	log.Printf("starting activator on %s", s.config.Server.Listen)
+	log.Printf("wake timeout: %s", s.config.Wake.Timeout)
+
+	if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	return nil
+}
+
+// Shutdown gracefully shuts down the server
+func (s *Server) Shutdown(ctx context.Context) error {
+	log.Printf("shutting down activator...")
+	return s.server.Shutdown(ctx)
+}
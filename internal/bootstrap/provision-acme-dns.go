@@ -439,6 +439,7 @@ func ensureAcmeDNSDeployment(ctx context.Context, c client.Client) error {
 						},
 					},
 					Spec: corev1.PodSpec{
+						PriorityClassName: config.PriorityClassSystem,
 						SecurityContext: &corev1.PodSecurityContext{
 							RunAsUser:  &[]int64{0}[0],
 							RunAsGroup: &[]int64{0}[0],
@@ -447,7 +448,7 @@ func ensureAcmeDNSDeployment(ctx context.Context, c client.Client) error {
 						Containers: []corev1.Container{
 							{
 								Name:            AcmeDNSName,
-								Image:           "joohoi/acme-dns:" + AcmeDNSVersion,
+								Image:           mirrorImage("joohoi/acme-dns:" + AcmeDNSVersion),
 								ImagePullPolicy: corev1.PullIfNotPresent,
 								Ports: []corev1.ContainerPort{
 									{
@@ -601,6 +602,13 @@ func triggerAcmeDNSRolloutRestart(ctx context.Context, c client.Client, deployme
 	// Use current timestamp to ensure the annotation value changes
 	deployment.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = time.Now().Format(time.RFC3339)
 
+	// Follow registry mirror changes
+	for i := range deployment.Spec.Template.Spec.Containers {
+		if deployment.Spec.Template.Spec.Containers[i].Name == AcmeDNSName {
+			deployment.Spec.Template.Spec.Containers[i].Image = mirrorImage("joohoi/acme-dns:" + AcmeDNSVersion)
+		}
+	}
+
 	log.Info("Updating ACME-DNS Deployment to trigger rollout restart",
 		"deployment", deployment.Name,
 		"restartedAt", deployment.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"])
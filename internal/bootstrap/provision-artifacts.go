@@ -0,0 +1,212 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// Artifact service constants
+const (
+	// ArtifactsName is the name of the artifact service Deployment and Service
+	ArtifactsName = "artifacts"
+
+	// ArtifactsDataClaimName is the volume of the pvc artifact provider
+	ArtifactsDataClaimName = "artifacts-data"
+
+	// RcloneImage serves the artifact storage over WebDAV
+	RcloneImage = "rclone/rclone:1.69.1"
+
+	artifactsPort = 8080
+)
+
+// ProvisionArtifacts ensures the artifact service pipelines publish test reports and other
+// build artifacts to is installed. The service is rclone serving WebDAV on top of either a
+// volume (pvc provider) or an S3 compatible bucket (s3 provider), so pipelines and the API
+// server talk to the same in-cluster endpoint whatever the backend.
+// It is idempotent and safe to call on every manager start.
+//
+// Resources created in the kibaship-artifacts namespace:
+//  1. Data PersistentVolumeClaim (pvc provider only)
+//  2. Artifact service Deployment and Service
+func ProvisionArtifacts(ctx context.Context, c client.Client, artifacts config.ArtifactsConfig) error {
+	log := ctrl.Log.WithName("bootstrap").WithName("artifacts")
+
+	if !artifacts.Enabled() {
+		log.Info("No artifacts provider configured, skipping artifact service provisioning")
+		return nil
+	}
+
+	log.Info("Provisioning artifact service", "provider", artifacts.Provider, "endpoint", artifacts.Endpoint())
+
+	if err := ensureNamespace(ctx, c, config.ArtifactsNamespace); err != nil {
+		return fmt.Errorf("ensure artifacts namespace: %w", err)
+	}
+
+	if artifacts.Provider == config.ArtifactsProviderPVC {
+		if err := ensureArtifactsClaim(ctx, c, artifacts); err != nil {
+			return fmt.Errorf("ensure artifacts volume: %w", err)
+		}
+	}
+
+	labels := artifactsLabels()
+
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: ArtifactsName, Namespace: config.ArtifactsNamespace}}
+	if err := ensureArtifactsObject(ctx, c, svc, func() error {
+		svc.Labels = labels
+		svc.Spec.Selector = labels
+		svc.Spec.Ports = []corev1.ServicePort{{
+			Name:       "http",
+			Port:       artifactsPort,
+			TargetPort: intstr.FromString("http"),
+			Protocol:   corev1.ProtocolTCP,
+		}}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("ensure artifacts service: %w", err)
+	}
+
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: ArtifactsName, Namespace: config.ArtifactsNamespace}}
+	if err := ensureArtifactsObject(ctx, c, deploy, func() error {
+		replicas := int32(1)
+		deploy.Labels = labels
+		deploy.Spec.Replicas = &replicas
+		deploy.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+		// The data volume is ReadWriteOnce, the old pod must release it before the new one starts
+		deploy.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+		deploy.Spec.Template = artifactsPodTemplate(artifacts, labels)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("ensure artifacts deployment: %w", err)
+	}
+
+	log.Info("Artifact service provisioning completed successfully")
+	return nil
+}
+
+// ensureArtifactsClaim creates the data volume of the pvc provider. The claim spec is
+// immutable so it is only set on creation.
+func ensureArtifactsClaim(ctx context.Context, c client.Client, artifacts config.ArtifactsConfig) error {
+	storageSize, err := resource.ParseQuantity(artifacts.StorageSize)
+	if err != nil {
+		return fmt.Errorf("invalid storage size %s: %w", artifacts.StorageSize, err)
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: ArtifactsDataClaimName, Namespace: config.ArtifactsNamespace}}
+	return ensureArtifactsObject(ctx, c, pvc, func() error {
+		pvc.Labels = artifactsLabels()
+		if !pvc.CreationTimestamp.IsZero() {
+			return nil
+		}
+		pvc.Spec = corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: storageSize},
+			},
+		}
+		if artifacts.StorageClass != "" {
+			pvc.Spec.StorageClassName = &artifacts.StorageClass
+		}
+		return nil
+	})
+}
+
+// artifactsPodTemplate renders the rclone WebDAV server for the configured backend
+func artifactsPodTemplate(artifacts config.ArtifactsConfig, labels map[string]string) corev1.PodTemplateSpec {
+	container := corev1.Container{
+		Name:  ArtifactsName,
+		Image: mirrorImage(RcloneImage),
+		Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: artifactsPort, Protocol: corev1.ProtocolTCP}},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromString("http")},
+			},
+			InitialDelaySeconds: 5,
+			PeriodSeconds:       10,
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("50m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+		},
+	}
+
+	var volumes []corev1.Volume
+	switch artifacts.Provider {
+	case config.ArtifactsProviderS3:
+		container.Args = []string{"serve", "webdav", "store:" + artifacts.S3Bucket, "--addr", fmt.Sprintf(":%d", artifactsPort)}
+		credential := func(key string) *corev1.EnvVarSource {
+			return &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: artifacts.S3CredentialsSecret},
+				Key:                  key,
+			}}
+		}
+		container.Env = []corev1.EnvVar{
+			{Name: "RCLONE_CONFIG_STORE_TYPE", Value: "s3"},
+			{Name: "RCLONE_CONFIG_STORE_PROVIDER", Value: "Other"},
+			{Name: "RCLONE_CONFIG_STORE_ENDPOINT", Value: artifacts.S3Endpoint},
+			{Name: "RCLONE_CONFIG_STORE_REGION", Value: artifacts.S3Region},
+			{Name: "RCLONE_CONFIG_STORE_ACCESS_KEY_ID", ValueFrom: credential(config.ArtifactsS3AccessKeyIDKey)},
+			{Name: "RCLONE_CONFIG_STORE_SECRET_ACCESS_KEY", ValueFrom: credential(config.ArtifactsS3SecretAccessKeyKey)},
+		}
+	default:
+		container.Args = []string{"serve", "webdav", "/data", "--addr", fmt.Sprintf(":%d", artifactsPort)}
+		container.VolumeMounts = []corev1.VolumeMount{{Name: "data", MountPath: "/data"}}
+		volumes = []corev1.Volume{{
+			Name: "data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: ArtifactsDataClaimName},
+			},
+		}}
+	}
+
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: labels},
+		Spec: corev1.PodSpec{
+			PriorityClassName: config.PriorityClassSystem,
+			SecurityContext: &corev1.PodSecurityContext{
+				FSGroup:   ptrInt64(1000),
+				RunAsUser: ptrInt64(1000),
+			},
+			Containers: []corev1.Container{container},
+			Volumes:    volumes,
+		},
+	}
+}
+
+// ensureArtifactsObject creates or updates an artifact service resource
+func ensureArtifactsObject(ctx context.Context, c client.Client, obj client.Object, mutate func() error) error {
+	log := ctrl.Log.WithName("bootstrap").WithName("artifacts")
+	kind := fmt.Sprintf("%T", obj)
+
+	result, err := controllerutil.CreateOrUpdate(ctx, c, obj, mutate)
+	if err != nil {
+		log.Error(err, "Failed to ensure artifacts resource", "kind", kind, "name", obj.GetName())
+		return err
+	}
+	log.Info("Artifacts resource ensured", "kind", kind, "name", obj.GetName(), "result", result)
+	return nil
+}
+
+func artifactsLabels() map[string]string {
+	return map[string]string{
+		"app":                          ArtifactsName,
+		"app.kubernetes.io/name":       ArtifactsName,
+		"app.kubernetes.io/component":  "artifacts",
+		"app.kubernetes.io/managed-by": "kibaship",
+	}
+}
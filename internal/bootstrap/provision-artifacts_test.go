@@ -0,0 +1,81 @@
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func TestProvisionArtifactsDisabled(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	g.Expect(ProvisionArtifacts(ctx, fakeClient, config.ArtifactsConfig{})).To(Succeed())
+
+	err := fakeClient.Get(ctx, client.ObjectKey{Name: config.ArtifactsNamespace}, &corev1.Namespace{})
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestProvisionArtifactsPVC(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+
+	artifacts := config.ArtifactsConfig{
+		Provider:     config.ArtifactsProviderPVC,
+		StorageSize:  "50Gi",
+		StorageClass: "storage-replica-2",
+	}
+	g.Expect(ProvisionArtifacts(ctx, fakeClient, artifacts)).To(Succeed())
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.ArtifactsNamespace, Name: ArtifactsDataClaimName}, pvc)).To(Succeed())
+	g.Expect(*pvc.Spec.StorageClassName).To(Equal("storage-replica-2"))
+	g.Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("50Gi"))
+
+	deploy := &appsv1.Deployment{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.ArtifactsNamespace, Name: ArtifactsName}, deploy)).To(Succeed())
+	g.Expect(deploy.Spec.Strategy.Type).To(Equal(appsv1.RecreateDeploymentStrategyType))
+	container := deploy.Spec.Template.Spec.Containers[0]
+	g.Expect(container.Args).To(Equal([]string{"serve", "webdav", "/data", "--addr", ":8080"}))
+	g.Expect(deploy.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(ArtifactsDataClaimName))
+
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.ArtifactsNamespace, Name: ArtifactsName}, &corev1.Service{})).To(Succeed())
+}
+
+func TestProvisionArtifactsS3(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+
+	artifacts := config.ArtifactsConfig{
+		Provider:            config.ArtifactsProviderS3,
+		S3Endpoint:          "https://s3.eu-central-1.amazonaws.com",
+		S3Bucket:            "kibaship-artifacts",
+		S3Region:            "eu-central-1",
+		S3CredentialsSecret: "artifacts-s3-credentials",
+	}
+	g.Expect(ProvisionArtifacts(ctx, fakeClient, artifacts)).To(Succeed())
+
+	err := fakeClient.Get(ctx, client.ObjectKey{Namespace: config.ArtifactsNamespace, Name: ArtifactsDataClaimName}, &corev1.PersistentVolumeClaim{})
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+
+	deploy := &appsv1.Deployment{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.ArtifactsNamespace, Name: ArtifactsName}, deploy)).To(Succeed())
+	container := deploy.Spec.Template.Spec.Containers[0]
+	g.Expect(container.Args).To(ContainElement("store:kibaship-artifacts"))
+	g.Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "RCLONE_CONFIG_STORE_ENDPOINT", Value: "https://s3.eu-central-1.amazonaws.com"}))
+	g.Expect(deploy.Spec.Template.Spec.Volumes).To(BeEmpty())
+}
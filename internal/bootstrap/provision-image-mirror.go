@@ -0,0 +1,150 @@
+package bootstrap
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// Registry mirror constants
+const (
+	// BuildkitdConfigMapName holds the buildkitd.toml shared by every BuildKit daemon
+	BuildkitdConfigMapName = "buildkitd-config"
+
+	// buildkitdConfigHashAnnotation rolls the shared BuildKit daemons when their configuration changes
+	buildkitdConfigHashAnnotation = "kibaship.com/buildkitd-config-hash"
+)
+
+// MirroredRegistries are the registries BuildKit pulls base images of builds from through the
+// registry mirror. The mirror serves each of them below a path named after the registry.
+var MirroredRegistries = []string{"docker.io", "gcr.io", "ghcr.io", "quay.io", "registry.k8s.io"}
+
+// mirrorHTTPClient checks the reachability of the registry mirror
+var mirrorHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// imageMirror holds the registry mirror the provisioned components are pulled from
+var imageMirror atomic.Pointer[string]
+
+// SetImageMirror replaces the registry mirror the images of the provisioned components are
+// pulled from. It is called before the bootstrap runs and whenever the configuration changes.
+func SetImageMirror(mirror string) {
+	imageMirror.Store(&mirror)
+}
+
+// mirrorImage rewrites an image of a provisioned component to the registry mirror
+func mirrorImage(image string) string {
+	mirror := ""
+	if current := imageMirror.Load(); current != nil {
+		mirror = *current
+	}
+	return config.MirrorImage(mirror, image)
+}
+
+// ValidateImageMirror checks that the registry mirror answers the registry API, so air-gapped
+// installations fail early instead of with image pull errors. Without mirror it does nothing.
+func ValidateImageMirror(ctx context.Context, mirror string) error {
+	if mirror == "" {
+		return nil
+	}
+	host, _, _ := strings.Cut(mirror, "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+host+"/v2/", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create registry mirror request: %w", err)
+	}
+	resp, err := mirrorHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("registry mirror %s is not reachable: %w", host, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Registries requiring authentication answer 401, which still proves the API is served
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusUnauthorized {
+		return fmt.Errorf("registry mirror %s answered %d to the registry API check", host, resp.StatusCode)
+	}
+	return nil
+}
+
+// EnsureBuildkitRegistryMirrors points the BuildKit daemons at the registry mirror for the base
+// images of builds and rolls the shared buildkitd Deployment when the configuration changed.
+// Daemons of the BuildKit pool mount the same configuration and roll with their mirrored image.
+// It is skipped when BuildKit is not installed yet.
+func EnsureBuildkitRegistryMirrors(ctx context.Context, c client.Client, mirror string) error {
+	log := ctrl.Log.WithName("bootstrap").WithName("buildkit-mirrors")
+
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "buildkit", Name: BuildkitdConfigMapName}, cm); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("BuildKit configuration doesn't exist yet, skipping registry mirrors")
+			return nil
+		}
+		return fmt.Errorf("failed to get BuildKit configuration: %w", err)
+	}
+
+	data := buildkitdConfig(mirror)
+	if cm.Data["buildkitd.toml"] == data {
+		return nil
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data["buildkitd.toml"] = data
+	if err := c.Update(ctx, cm); err != nil {
+		return fmt.Errorf("failed to update BuildKit configuration: %w", err)
+	}
+	log.Info("BuildKit registry mirrors updated", "mirror", mirror)
+
+	deployment := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "buildkit", Name: "buildkitd"}, deployment); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get buildkitd Deployment: %w", err)
+	}
+	sum := sha256.Sum256([]byte(data))
+	patch := client.MergeFrom(deployment.DeepCopy())
+	if deployment.Spec.Template.Annotations == nil {
+		deployment.Spec.Template.Annotations = map[string]string{}
+	}
+	deployment.Spec.Template.Annotations[buildkitdConfigHashAnnotation] = hex.EncodeToString(sum[:])[:16]
+	if err := c.Patch(ctx, deployment, patch); err != nil {
+		return fmt.Errorf("failed to restart buildkitd Deployment: %w", err)
+	}
+	return nil
+}
+
+// buildkitdConfig renders buildkitd.toml, trusting the in-cluster registry and pulling from the
+// registry mirror when one is configured
+func buildkitdConfig(mirror string) string {
+	var b strings.Builder
+	b.WriteString(`# BuildKit daemon configuration
+debug = true
+
+# Registry-specific TLS configuration
+[registry."registry.registry.svc.cluster.local"]
+  ca = ["/usr/local/share/ca-certificates/registry-ca.crt"]
+  insecure = true
+`)
+	if mirror == "" {
+		return b.String()
+	}
+
+	b.WriteString("\n# Base images are pulled through the registry mirror\n")
+	for _, registry := range MirroredRegistries {
+		fmt.Fprintf(&b, "[registry.%q]\n  mirrors = [%q]\n", registry, mirror+"/"+registry)
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package bootstrap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateImageMirror(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	status := http.StatusUnauthorized
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	previous := mirrorHTTPClient
+	mirrorHTTPClient = server.Client()
+	t.Cleanup(func() { mirrorHTTPClient = previous })
+
+	mirror := strings.TrimPrefix(server.URL, "https://") + "/platform"
+	g.Expect(ValidateImageMirror(ctx, "")).To(Succeed())
+	g.Expect(ValidateImageMirror(ctx, mirror)).To(Succeed())
+
+	status = http.StatusServiceUnavailable
+	g.Expect(ValidateImageMirror(ctx, mirror)).To(MatchError(ContainSubstring("answered 503")))
+}
+
+func TestEnsureBuildkitRegistryMirrors(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	// BuildKit not installed yet
+	fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	g.Expect(EnsureBuildkitRegistryMirrors(ctx, fakeClient, "mirror.internal")).To(Succeed())
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: BuildkitdConfigMapName, Namespace: "buildkit"},
+		Data:       map[string]string{"buildkitd.toml": buildkitdConfig("")},
+	}
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "buildkitd", Namespace: "buildkit"}}
+	fakeClient = fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(cm, deployment).Build()
+
+	// Unchanged configuration leaves the daemons running
+	g.Expect(EnsureBuildkitRegistryMirrors(ctx, fakeClient, "")).To(Succeed())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), deployment)).To(Succeed())
+	g.Expect(deployment.Spec.Template.Annotations).NotTo(HaveKey(buildkitdConfigHashAnnotation))
+
+	g.Expect(EnsureBuildkitRegistryMirrors(ctx, fakeClient, "mirror.internal/platform")).To(Succeed())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cm), cm)).To(Succeed())
+	g.Expect(cm.Data["buildkitd.toml"]).To(ContainSubstring(`[registry."registry.registry.svc.cluster.local"]`))
+	g.Expect(cm.Data["buildkitd.toml"]).To(ContainSubstring("[registry.\"docker.io\"]\n  mirrors = [\"mirror.internal/platform/docker.io\"]"))
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), deployment)).To(Succeed())
+	g.Expect(deployment.Spec.Template.Annotations).To(HaveKey(buildkitdConfigHashAnnotation))
+}
+
+func TestProvisionedImagesFollowMirror(t *testing.T) {
+	g := NewWithT(t)
+
+	SetImageMirror("mirror.internal")
+	t.Cleanup(func() { SetImageMirror("") })
+
+	g.Expect(mirrorImage(RcloneImage)).To(Equal("mirror.internal/docker.io/rclone/rclone:1.69.1"))
+	g.Expect(mirrorImage(LokiImage)).To(Equal("mirror.internal/docker.io/grafana/loki:3.4.2"))
+}
@@ -0,0 +1,191 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// LoadBalancer IP pool constants
+const (
+	// LoadBalancerIPPoolName is the name of the IP pool for LoadBalancer Services
+	LoadBalancerIPPoolName = "kibaship-lb-pool"
+
+	// LoadBalancerL2PolicyName is the name of the L2 announcement policy / advertisement
+	LoadBalancerL2PolicyName = "kibaship-l2-announcement"
+
+	// MetalLBNamespace is the namespace MetalLB is installed into
+	MetalLBNamespace = "metallb-system"
+)
+
+// ProvisionLoadBalancer ensures LoadBalancer IP pools and L2 announcements exist so
+// LoadBalancer Services (such as the ingress Gateway) get an external IP on bare-metal clusters.
+// It is idempotent and safe to call on every manager start; existing pools are updated
+// to match the configured addresses.
+//
+// Prerequisites:
+//   - cilium: Cilium installed with l2announcements.enabled=true
+//   - metallb: MetalLB installed in the metallb-system namespace
+func ProvisionLoadBalancer(ctx context.Context, c client.Client, lb config.LoadBalancerConfig) error {
+	log := ctrl.Log.WithName("bootstrap").WithName("loadbalancer")
+
+	if !lb.Enabled() {
+		log.Info("No load balancer provider configured, skipping IP pool provisioning")
+		return nil
+	}
+
+	log.Info("Provisioning load balancer IP pool", "provider", lb.Provider, "addresses", lb.Addresses, "interfaces", lb.Interfaces)
+
+	switch lb.Provider {
+	case config.LoadBalancerProviderCilium:
+		if err := ensureLoadBalancerObject(ctx, c, ciliumIPPool(lb)); err != nil {
+			return fmt.Errorf("ensure CiliumLoadBalancerIPPool: %w", err)
+		}
+		if err := ensureLoadBalancerObject(ctx, c, ciliumL2AnnouncementPolicy(lb)); err != nil {
+			return fmt.Errorf("ensure CiliumL2AnnouncementPolicy: %w", err)
+		}
+	case config.LoadBalancerProviderMetalLB:
+		if err := ensureLoadBalancerObject(ctx, c, metalLBIPAddressPool(lb)); err != nil {
+			return fmt.Errorf("ensure MetalLB IPAddressPool: %w", err)
+		}
+		if err := ensureLoadBalancerObject(ctx, c, metalLBL2Advertisement(lb)); err != nil {
+			return fmt.Errorf("ensure MetalLB L2Advertisement: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported load balancer provider: %s", lb.Provider)
+	}
+
+	log.Info("Load balancer IP pool provisioning completed successfully", "provider", lb.Provider)
+	return nil
+}
+
+// ciliumIPPool builds the cluster-scoped CiliumLoadBalancerIPPool
+func ciliumIPPool(lb config.LoadBalancerConfig) *unstructured.Unstructured {
+	blocks := make([]any, 0, len(lb.Addresses))
+	for _, address := range lb.Addresses {
+		if start, stop, ok := strings.Cut(address, "-"); ok {
+			blocks = append(blocks, map[string]any{
+				"start": strings.TrimSpace(start),
+				"stop":  strings.TrimSpace(stop),
+			})
+			continue
+		}
+		blocks = append(blocks, map[string]any{"cidr": address})
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "cilium.io", Version: "v2alpha1", Kind: "CiliumLoadBalancerIPPool"})
+	obj.SetName(LoadBalancerIPPoolName)
+	obj.SetLabels(loadBalancerLabels())
+	obj.Object["spec"] = map[string]any{
+		"blocks": blocks,
+	}
+	return obj
+}
+
+// ciliumL2AnnouncementPolicy builds the cluster-scoped CiliumL2AnnouncementPolicy
+func ciliumL2AnnouncementPolicy(lb config.LoadBalancerConfig) *unstructured.Unstructured {
+	spec := map[string]any{
+		"loadBalancerIPs": true,
+		"externalIPs":     false,
+	}
+	if len(lb.Interfaces) > 0 {
+		spec["interfaces"] = toAnySlice(lb.Interfaces)
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "cilium.io", Version: "v2alpha1", Kind: "CiliumL2AnnouncementPolicy"})
+	obj.SetName(LoadBalancerL2PolicyName)
+	obj.SetLabels(loadBalancerLabels())
+	obj.Object["spec"] = spec
+	return obj
+}
+
+// metalLBIPAddressPool builds the MetalLB IPAddressPool in the metallb-system namespace
+func metalLBIPAddressPool(lb config.LoadBalancerConfig) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "metallb.io", Version: "v1beta1", Kind: "IPAddressPool"})
+	obj.SetNamespace(MetalLBNamespace)
+	obj.SetName(LoadBalancerIPPoolName)
+	obj.SetLabels(loadBalancerLabels())
+	obj.Object["spec"] = map[string]any{
+		"addresses": toAnySlice(lb.Addresses),
+	}
+	return obj
+}
+
+// metalLBL2Advertisement builds the MetalLB L2Advertisement for the kibaship pool
+func metalLBL2Advertisement(lb config.LoadBalancerConfig) *unstructured.Unstructured {
+	spec := map[string]any{
+		"ipAddressPools": []any{LoadBalancerIPPoolName},
+	}
+	if len(lb.Interfaces) > 0 {
+		spec["interfaces"] = toAnySlice(lb.Interfaces)
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "metallb.io", Version: "v1beta1", Kind: "L2Advertisement"})
+	obj.SetNamespace(MetalLBNamespace)
+	obj.SetName(LoadBalancerL2PolicyName)
+	obj.SetLabels(loadBalancerLabels())
+	obj.Object["spec"] = spec
+	return obj
+}
+
+// ensureLoadBalancerObject creates the object or updates its spec when it differs
+func ensureLoadBalancerObject(ctx context.Context, c client.Client, desired *unstructured.Unstructured) error {
+	log := ctrl.Log.WithName("bootstrap").WithName("loadbalancer")
+	kind := desired.GetKind()
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(desired.GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKey{Namespace: desired.GetNamespace(), Name: desired.GetName()}, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		log.Info("Creating load balancer resource", "kind", kind, "name", desired.GetName())
+		if err := c.Create(ctx, desired); err != nil {
+			log.Error(err, "Failed to create load balancer resource", "kind", kind, "name", desired.GetName())
+			return err
+		}
+		log.Info("Load balancer resource created successfully", "kind", kind, "name", desired.GetName())
+		return nil
+	}
+
+	if reflect.DeepEqual(existing.Object["spec"], desired.Object["spec"]) {
+		log.Info("Load balancer resource already up to date", "kind", kind, "name", desired.GetName())
+		return nil
+	}
+
+	existing.Object["spec"] = desired.Object["spec"]
+	if err := c.Update(ctx, existing); err != nil {
+		log.Error(err, "Failed to update load balancer resource", "kind", kind, "name", desired.GetName())
+		return err
+	}
+	log.Info("Load balancer resource updated successfully", "kind", kind, "name", desired.GetName())
+	return nil
+}
+
+func loadBalancerLabels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/managed-by": "kibaship",
+		"app.kubernetes.io/component":  "loadbalancer",
+	}
+}
+
+func toAnySlice(values []string) []any {
+	out := make([]any, 0, len(values))
+	for _, v := range values {
+		out = append(out, v)
+	}
+	return out
+}
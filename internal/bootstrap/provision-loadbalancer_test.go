@@ -0,0 +1,89 @@
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func TestProvisionLoadBalancerCilium(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+
+	lb := config.LoadBalancerConfig{
+		Provider:   config.LoadBalancerProviderCilium,
+		Addresses:  []string{"10.0.0.0/28", "192.168.1.10-192.168.1.20"},
+		Interfaces: []string{"^eth[0-9]+"},
+	}
+	g.Expect(ProvisionLoadBalancer(ctx, fakeClient, lb)).To(Succeed())
+
+	pool := &unstructured.Unstructured{}
+	pool.SetGroupVersionKind(schema.GroupVersionKind{Group: "cilium.io", Version: "v2alpha1", Kind: "CiliumLoadBalancerIPPool"})
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: LoadBalancerIPPoolName}, pool)).To(Succeed())
+
+	blocks, found, err := unstructured.NestedSlice(pool.Object, "spec", "blocks")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeTrue())
+	g.Expect(blocks).To(ConsistOf(
+		map[string]any{"cidr": "10.0.0.0/28"},
+		map[string]any{"start": "192.168.1.10", "stop": "192.168.1.20"},
+	))
+
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(schema.GroupVersionKind{Group: "cilium.io", Version: "v2alpha1", Kind: "CiliumL2AnnouncementPolicy"})
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: LoadBalancerL2PolicyName}, policy)).To(Succeed())
+
+	interfaces, _, _ := unstructured.NestedStringSlice(policy.Object, "spec", "interfaces")
+	g.Expect(interfaces).To(Equal([]string{"^eth[0-9]+"}))
+
+	// Changing the addresses updates the existing pool
+	lb.Addresses = []string{"10.0.1.0/28"}
+	g.Expect(ProvisionLoadBalancer(ctx, fakeClient, lb)).To(Succeed())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: LoadBalancerIPPoolName}, pool)).To(Succeed())
+	blocks, _, _ = unstructured.NestedSlice(pool.Object, "spec", "blocks")
+	g.Expect(blocks).To(ConsistOf(map[string]any{"cidr": "10.0.1.0/28"}))
+}
+
+func TestProvisionLoadBalancerMetalLB(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+
+	lb := config.LoadBalancerConfig{
+		Provider:  config.LoadBalancerProviderMetalLB,
+		Addresses: []string{"192.168.1.10-192.168.1.20"},
+	}
+	g.Expect(ProvisionLoadBalancer(ctx, fakeClient, lb)).To(Succeed())
+
+	pool := &unstructured.Unstructured{}
+	pool.SetGroupVersionKind(schema.GroupVersionKind{Group: "metallb.io", Version: "v1beta1", Kind: "IPAddressPool"})
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: MetalLBNamespace, Name: LoadBalancerIPPoolName}, pool)).To(Succeed())
+
+	addresses, _, _ := unstructured.NestedStringSlice(pool.Object, "spec", "addresses")
+	g.Expect(addresses).To(Equal([]string{"192.168.1.10-192.168.1.20"}))
+
+	advertisement := &unstructured.Unstructured{}
+	advertisement.SetGroupVersionKind(schema.GroupVersionKind{Group: "metallb.io", Version: "v1beta1", Kind: "L2Advertisement"})
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: MetalLBNamespace, Name: LoadBalancerL2PolicyName}, advertisement)).To(Succeed())
+
+	pools, _, _ := unstructured.NestedStringSlice(advertisement.Object, "spec", "ipAddressPools")
+	g.Expect(pools).To(Equal([]string{LoadBalancerIPPoolName}))
+}
+
+func TestProvisionLoadBalancerSkipsWhenDisabled(t *testing.T) {
+	g := NewWithT(t)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+	g.Expect(ProvisionLoadBalancer(context.Background(), fakeClient, config.LoadBalancerConfig{})).To(Succeed())
+}
@@ -0,0 +1,504 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// Logging pipeline constants
+const (
+	// LokiName is the name of the Loki StatefulSet, Service and ConfigMap
+	LokiName = "loki"
+
+	// LokiImage is the Loki image installed by the operator
+	LokiImage = "grafana/loki:3.4.2"
+
+	// PromtailImage is the promtail image used when the collector is promtail
+	PromtailImage = "grafana/promtail:3.4.2"
+
+	// VectorImage is the Vector image used when the collector is vector
+	VectorImage = "timberio/vector:0.45.0-debian"
+
+	// LogCollectorServiceAccountName is the service account of the log collector DaemonSet
+	LogCollectorServiceAccountName = "kibaship-log-collector"
+
+	lokiPort = 3100
+)
+
+// ProvisionLogging ensures the persistent logging pipeline is installed so application logs
+// outlive their pods. It installs a single binary Loki (unless an external Loki URL is
+// configured) and a collector DaemonSet that ships the logs of application pods to it.
+// It is idempotent and safe to call on every manager start; configuration changes such as the
+// retention period or the collector are applied to the existing resources.
+//
+// Resources created in the kibaship-logging namespace:
+//  1. Loki ConfigMap, Service and StatefulSet with a data volume
+//  2. Collector ServiceAccount, ClusterRole and ClusterRoleBinding
+//  3. Collector ConfigMap and DaemonSet (promtail or vector)
+func ProvisionLogging(ctx context.Context, c client.Client, logging config.LoggingConfig) error {
+	log := ctrl.Log.WithName("bootstrap").WithName("logging")
+
+	if !logging.Enabled() {
+		log.Info("No logging provider configured, skipping logging pipeline provisioning")
+		return nil
+	}
+
+	log.Info("Provisioning logging pipeline", "provider", logging.Provider, "collector", logging.Collector,
+		"retention", logging.Retention, "lokiURL", logging.Endpoint())
+
+	if err := ensureNamespace(ctx, c, config.LoggingNamespace); err != nil {
+		return fmt.Errorf("ensure logging namespace: %w", err)
+	}
+
+	if logging.LokiURL == "" {
+		if err := ensureLoki(ctx, c, logging); err != nil {
+			return fmt.Errorf("ensure Loki: %w", err)
+		}
+	} else {
+		log.Info("External Loki configured, skipping Loki installation", "lokiURL", logging.LokiURL)
+	}
+
+	if err := ensureLogCollector(ctx, c, logging); err != nil {
+		return fmt.Errorf("ensure log collector: %w", err)
+	}
+
+	log.Info("Logging pipeline provisioning completed successfully")
+	return nil
+}
+
+// ensureLoki installs the single binary Loki with filesystem storage
+func ensureLoki(ctx context.Context, c client.Client, logging config.LoggingConfig) error {
+	labels := loggingLabels(LokiName)
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: LokiName + "-config", Namespace: config.LoggingNamespace}}
+	if err := ensureLoggingObject(ctx, c, cm, func() error {
+		cm.Labels = labels
+		cm.Data = map[string]string{"loki.yaml": lokiConfig(logging.Retention)}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: LokiName, Namespace: config.LoggingNamespace}}
+	if err := ensureLoggingObject(ctx, c, svc, func() error {
+		svc.Labels = labels
+		svc.Spec.Selector = labels
+		svc.Spec.Ports = []corev1.ServicePort{{
+			Name:       "http",
+			Port:       lokiPort,
+			TargetPort: intstr.FromString("http"),
+			Protocol:   corev1.ProtocolTCP,
+		}}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	storageSize, err := resource.ParseQuantity(logging.StorageSize)
+	if err != nil {
+		return fmt.Errorf("invalid storage size %s: %w", logging.StorageSize, err)
+	}
+
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: LokiName, Namespace: config.LoggingNamespace}}
+	return ensureLoggingObject(ctx, c, sts, func() error {
+		replicas := int32(1)
+		sts.Labels = labels
+		sts.Spec.Replicas = &replicas
+		sts.Spec.ServiceName = LokiName
+		sts.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+
+		// Volume claim templates are immutable, they are only set when the StatefulSet is created
+		if sts.CreationTimestamp.IsZero() {
+			claim := corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: storageSize},
+					},
+				},
+			}
+			if logging.StorageClass != "" {
+				claim.Spec.StorageClassName = &logging.StorageClass
+			}
+			sts.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{claim}
+		}
+
+		sts.Spec.Template = corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: labels,
+				// Roll Loki when its configuration changes
+				Annotations: map[string]string{"kibaship.com/config-retention": logging.Retention.String()},
+			},
+			Spec: corev1.PodSpec{
+				PriorityClassName: config.PriorityClassSystem,
+				SecurityContext: &corev1.PodSecurityContext{
+					FSGroup:   ptrInt64(10001),
+					RunAsUser: ptrInt64(10001),
+				},
+				Containers: []corev1.Container{{
+					Name:  LokiName,
+					Image: mirrorImage(LokiImage),
+					Args:  []string{"-config.file=/etc/loki/loki.yaml", "-target=all"},
+					Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: lokiPort, Protocol: corev1.ProtocolTCP}},
+					ReadinessProbe: &corev1.Probe{
+						ProbeHandler: corev1.ProbeHandler{
+							HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromString("http")},
+						},
+						InitialDelaySeconds: 15,
+						PeriodSeconds:       10,
+					},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("100m"),
+							corev1.ResourceMemory: resource.MustParse("256Mi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "config", MountPath: "/etc/loki"},
+						{Name: "data", MountPath: "/var/loki"},
+					},
+				}},
+				Volumes: []corev1.Volume{{
+					Name: "config",
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: LokiName + "-config"},
+						},
+					},
+				}},
+			},
+		}
+		return nil
+	})
+}
+
+// ensureLogCollector installs the configured collector and removes the other one
+func ensureLogCollector(ctx context.Context, c client.Client, logging config.LoggingConfig) error {
+	log := ctrl.Log.WithName("bootstrap").WithName("logging")
+
+	name := string(logging.Collector)
+	labels := loggingLabels(name)
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: LogCollectorServiceAccountName, Namespace: config.LoggingNamespace}}
+	if err := ensureLoggingObject(ctx, c, sa, func() error {
+		sa.Labels = loggingLabels("log-collector")
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	role := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: LogCollectorServiceAccountName}}
+	if err := ensureLoggingObject(ctx, c, role, func() error {
+		role.Labels = loggingLabels("log-collector")
+		role.Rules = []rbacv1.PolicyRule{{
+			APIGroups: []string{""},
+			Resources: []string{"namespaces", "nodes", "pods"},
+			Verbs:     []string{"get", "list", "watch"},
+		}}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	binding := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: LogCollectorServiceAccountName}}
+	if err := ensureLoggingObject(ctx, c, binding, func() error {
+		binding.Labels = loggingLabels("log-collector")
+		binding.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: LogCollectorServiceAccountName}
+		binding.Subjects = []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      LogCollectorServiceAccountName,
+			Namespace: config.LoggingNamespace,
+		}}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	var configFile, configData, image string
+	var args []string
+	var env []corev1.EnvVar
+	switch logging.Collector {
+	case config.LoggingCollectorPromtail:
+		configFile, configData, image = "promtail.yaml", promtailConfig(logging.Endpoint()), mirrorImage(PromtailImage)
+		args = []string{"-config.file=/etc/" + name + "/" + configFile, "-config.expand-env=true"}
+		env = []corev1.EnvVar{{
+			Name:      "HOSTNAME",
+			ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}},
+		}}
+	case config.LoggingCollectorVector:
+		configFile, configData, image = "vector.yaml", vectorConfig(logging.Endpoint()), mirrorImage(VectorImage)
+		args = []string{"--config-dir", "/etc/" + name}
+		env = []corev1.EnvVar{{
+			Name:      "VECTOR_SELF_NODE_NAME",
+			ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}},
+		}}
+	default:
+		return fmt.Errorf("unsupported log collector: %s", logging.Collector)
+	}
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name + "-config", Namespace: config.LoggingNamespace}}
+	if err := ensureLoggingObject(ctx, c, cm, func() error {
+		cm.Labels = labels
+		cm.Data = map[string]string{configFile: configData}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: config.LoggingNamespace}}
+	if err := ensureLoggingObject(ctx, c, ds, func() error {
+		ds.Labels = labels
+		ds.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+		ds.Spec.Template = corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: labels,
+				// Roll the collector when the Loki it ships to changes
+				Annotations: map[string]string{"kibaship.com/loki-url": logging.Endpoint()},
+			},
+			Spec: corev1.PodSpec{
+				PriorityClassName:  config.PriorityClassSystem,
+				ServiceAccountName: LogCollectorServiceAccountName,
+				Tolerations:        []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+				Containers: []corev1.Container{{
+					Name:  name,
+					Image: image,
+					Args:  args,
+					Env:   env,
+					SecurityContext: &corev1.SecurityContext{
+						RunAsUser: ptrInt64(0),
+					},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("50m"),
+							corev1.ResourceMemory: resource.MustParse("64Mi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("256Mi"),
+						},
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "config", MountPath: "/etc/" + name},
+						{Name: "pods", MountPath: "/var/log/pods", ReadOnly: true},
+						{Name: "state", MountPath: "/var/lib/" + name},
+					},
+				}},
+				Volumes: []corev1.Volume{
+					{
+						Name: "config",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: name + "-config"},
+							},
+						},
+					},
+					{
+						Name:         "pods",
+						VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/log/pods"}},
+					},
+					{
+						Name:         "state",
+						VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/lib/kibaship-" + name}},
+					},
+				},
+			},
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// Remove the collector that is no longer configured so logs are not shipped twice
+	for _, other := range []config.LoggingCollector{config.LoggingCollectorPromtail, config.LoggingCollectorVector} {
+		if other == logging.Collector {
+			continue
+		}
+		stale := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: string(other), Namespace: config.LoggingNamespace}}
+		if err := c.Delete(ctx, stale); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("delete previous log collector %s: %w", other, err)
+		} else if err == nil {
+			log.Info("Deleted previous log collector", "collector", other)
+		}
+	}
+
+	return nil
+}
+
+// ensureLoggingObject creates the object or updates it to match the mutate function
+func ensureLoggingObject(ctx context.Context, c client.Client, obj client.Object, mutate func() error) error {
+	log := ctrl.Log.WithName("bootstrap").WithName("logging")
+	kind := fmt.Sprintf("%T", obj)
+
+	result, err := controllerutil.CreateOrUpdate(ctx, c, obj, mutate)
+	if err != nil {
+		log.Error(err, "Failed to ensure logging resource", "kind", kind, "name", obj.GetName())
+		return err
+	}
+	log.Info("Logging resource ensured", "kind", kind, "name", obj.GetName(), "result", result)
+	return nil
+}
+
+// lokiConfig renders a single binary Loki configuration with filesystem storage and retention
+func lokiConfig(retention time.Duration) string {
+	return fmt.Sprintf(`auth_enabled: false
+server:
+  http_listen_port: %d
+common:
+  path_prefix: /var/loki
+  replication_factor: 1
+  ring:
+    kvstore:
+      store: inmemory
+  storage:
+    filesystem:
+      chunks_directory: /var/loki/chunks
+      rules_directory: /var/loki/rules
+schema_config:
+  configs:
+    - from: "2024-01-01"
+      store: tsdb
+      object_store: filesystem
+      schema: v13
+      index:
+        prefix: index_
+        period: 24h
+limits_config:
+  retention_period: %s
+  reject_old_samples: true
+  reject_old_samples_max_age: %s
+  max_query_length: %s
+compactor:
+  working_directory: /var/loki/compactor
+  retention_enabled: true
+  delete_request_store: filesystem
+analytics:
+  reporting_enabled: false
+`, lokiPort, lokiDuration(retention), lokiDuration(retention), lokiDuration(retention))
+}
+
+// promtailConfig renders a promtail configuration that ships the logs of application pods
+func promtailConfig(lokiURL string) string {
+	podLabel := func(label string) string {
+		return "__meta_kubernetes_pod_label_" + sanitizePromLabel(label)
+	}
+	return fmt.Sprintf(`server:
+  http_listen_port: 9080
+  grpc_listen_port: 0
+positions:
+  filename: /var/lib/promtail/positions.yaml
+clients:
+  - url: %s/loki/api/v1/push
+scrape_configs:
+  - job_name: kibaship-applications
+    kubernetes_sd_configs:
+      - role: pod
+    pipeline_stages:
+      - cri: {}
+    relabel_configs:
+      - source_labels: [%s]
+        action: keep
+        regex: .+
+      - source_labels: [__meta_kubernetes_pod_node_name]
+        action: keep
+        regex: ${HOSTNAME}
+      - source_labels: [__meta_kubernetes_namespace]
+        target_label: %s
+      - source_labels: [__meta_kubernetes_pod_name]
+        target_label: %s
+      - source_labels: [__meta_kubernetes_pod_container_name]
+        target_label: %s
+      - source_labels: [%s]
+        target_label: %s
+      - source_labels: [%s]
+        target_label: %s
+      - source_labels: [%s]
+        target_label: %s
+      - source_labels: [__meta_kubernetes_pod_uid, __meta_kubernetes_pod_container_name]
+        separator: /
+        target_label: __path__
+        replacement: /var/log/pods/*$1/*.log
+`, lokiURL,
+		podLabel(validation.LabelApplicationUUID),
+		config.LogLabelNamespace, config.LogLabelPod, config.LogLabelContainer,
+		podLabel(validation.LabelApplicationUUID), config.LogLabelApplicationUUID,
+		podLabel(validation.LabelDeploymentUUID), config.LogLabelDeploymentUUID,
+		podLabel(validation.LabelProjectUUID), config.LogLabelProjectUUID)
+}
+
+// vectorConfig renders a Vector configuration that ships the logs of application pods
+func vectorConfig(lokiURL string) string {
+	podLabel := func(label string) string {
+		return fmt.Sprintf(`'{{ kubernetes.pod_labels."%s" }}'`, label)
+	}
+	return fmt.Sprintf(`data_dir: /var/lib/vector
+sources:
+  applications:
+    type: kubernetes_logs
+    extra_label_selector: %s
+sinks:
+  loki:
+    type: loki
+    inputs: [applications]
+    endpoint: %s
+    encoding:
+      codec: text
+    labels:
+      %s: '{{ kubernetes.pod_namespace }}'
+      %s: '{{ kubernetes.pod_name }}'
+      %s: '{{ kubernetes.container_name }}'
+      %s: %s
+      %s: %s
+      %s: %s
+`, validation.LabelApplicationUUID, lokiURL,
+		config.LogLabelNamespace, config.LogLabelPod, config.LogLabelContainer,
+		config.LogLabelApplicationUUID, podLabel(validation.LabelApplicationUUID),
+		config.LogLabelDeploymentUUID, podLabel(validation.LabelDeploymentUUID),
+		config.LogLabelProjectUUID, podLabel(validation.LabelProjectUUID))
+}
+
+// sanitizePromLabel converts a Kubernetes label key to the form used in Prometheus service discovery
+func sanitizePromLabel(label string) string {
+	out := []byte(label)
+	for i, ch := range out {
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9') {
+			out[i] = '_'
+		}
+	}
+	return string(out)
+}
+
+// lokiDuration formats a duration in whole hours, the unit Loki retention is expressed in
+func lokiDuration(d time.Duration) string {
+	return fmt.Sprintf("%dh", int64(d.Hours()))
+}
+
+func loggingLabels(component string) map[string]string {
+	return map[string]string{
+		"app":                          component,
+		"app.kubernetes.io/name":       component,
+		"app.kubernetes.io/component":  "logging",
+		"app.kubernetes.io/managed-by": "kibaship",
+	}
+}
+
+func ptrInt64(v int64) *int64 {
+	return &v
+}
@@ -0,0 +1,97 @@
+package bootstrap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func TestProvisionLoggingDisabled(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	g.Expect(ProvisionLogging(ctx, fakeClient, config.LoggingConfig{})).To(Succeed())
+
+	err := fakeClient.Get(ctx, client.ObjectKey{Name: config.LoggingNamespace}, &corev1.Namespace{})
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestProvisionLoggingLoki(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+
+	logging := config.LoggingConfig{
+		Provider:     config.LoggingProviderLoki,
+		Collector:    config.LoggingCollectorPromtail,
+		Retention:    168 * time.Hour,
+		StorageSize:  "20Gi",
+		StorageClass: "storage-replica-1",
+	}
+	g.Expect(ProvisionLogging(ctx, fakeClient, logging)).To(Succeed())
+
+	sts := &appsv1.StatefulSet{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.LoggingNamespace, Name: LokiName}, sts)).To(Succeed())
+	g.Expect(sts.Spec.VolumeClaimTemplates).To(HaveLen(1))
+	g.Expect(*sts.Spec.VolumeClaimTemplates[0].Spec.StorageClassName).To(Equal("storage-replica-1"))
+	g.Expect(sts.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests.Storage().String()).To(Equal("20Gi"))
+
+	cm := &corev1.ConfigMap{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.LoggingNamespace, Name: LokiName + "-config"}, cm)).To(Succeed())
+	g.Expect(cm.Data["loki.yaml"]).To(ContainSubstring("retention_period: 168h"))
+
+	promtail := &corev1.ConfigMap{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.LoggingNamespace, Name: "promtail-config"}, promtail)).To(Succeed())
+	g.Expect(promtail.Data["promtail.yaml"]).To(ContainSubstring("url: " + config.BundledLokiURL + "/loki/api/v1/push"))
+	g.Expect(promtail.Data["promtail.yaml"]).To(ContainSubstring("__meta_kubernetes_pod_label_platform_kibaship_com_application_uuid"))
+
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.LoggingNamespace, Name: "promtail"}, &appsv1.DaemonSet{})).To(Succeed())
+
+	// Changing the retention updates the Loki configuration
+	logging.Retention = 720 * time.Hour
+	g.Expect(ProvisionLogging(ctx, fakeClient, logging)).To(Succeed())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.LoggingNamespace, Name: LokiName + "-config"}, cm)).To(Succeed())
+	g.Expect(cm.Data["loki.yaml"]).To(ContainSubstring("retention_period: 720h"))
+
+	// Switching the collector replaces the promtail DaemonSet with vector
+	logging.Collector = config.LoggingCollectorVector
+	g.Expect(ProvisionLogging(ctx, fakeClient, logging)).To(Succeed())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.LoggingNamespace, Name: "vector"}, &appsv1.DaemonSet{})).To(Succeed())
+	err := fakeClient.Get(ctx, client.ObjectKey{Namespace: config.LoggingNamespace, Name: "promtail"}, &appsv1.DaemonSet{})
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestProvisionLoggingExternalLoki(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+
+	logging := config.LoggingConfig{
+		Provider:    config.LoggingProviderLoki,
+		Collector:   config.LoggingCollectorVector,
+		Retention:   168 * time.Hour,
+		StorageSize: "10Gi",
+		LokiURL:     "http://loki.monitoring.svc:3100",
+	}
+	g.Expect(ProvisionLogging(ctx, fakeClient, logging)).To(Succeed())
+
+	err := fakeClient.Get(ctx, client.ObjectKey{Namespace: config.LoggingNamespace, Name: LokiName}, &appsv1.StatefulSet{})
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+
+	vector := &corev1.ConfigMap{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.LoggingNamespace, Name: "vector-config"}, vector)).To(Succeed())
+	g.Expect(vector.Data["vector.yaml"]).To(ContainSubstring("endpoint: http://loki.monitoring.svc:3100"))
+}
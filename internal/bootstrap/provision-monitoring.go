@@ -0,0 +1,473 @@
+package bootstrap
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// Monitoring stack constants
+const (
+	// HelmImage runs the Helm install of the monitoring stack
+	HelmImage = "alpine/helm:3.17.3"
+
+	// MonitoringInstallerName is the service account, and the prefix of the Jobs, installing the
+	// monitoring stack
+	MonitoringInstallerName = "kibaship-monitoring-installer"
+
+	// MonitoringValuesName is the ConfigMap holding the Helm values of the monitoring stack
+	MonitoringValuesName = "kube-prometheus-stack-values"
+
+	// MonitoringDashboardsName is the ConfigMap holding the Kibaship Grafana dashboards
+	MonitoringDashboardsName = "kibaship-dashboards"
+
+	// GrafanaAdminSecretName holds the generated Grafana admin credentials
+	GrafanaAdminSecretName = "kibaship-grafana-admin"
+
+	monitoringChartRepository = "https://prometheus-community.github.io/helm-charts"
+
+	// grafanaDashboardLabel makes the Grafana sidecar load a ConfigMap as dashboards
+	grafanaDashboardLabel = "grafana_dashboard"
+
+	// monitoringInstallTTLSeconds keeps finished install Jobs around for a day for their logs
+	monitoringInstallTTLSeconds = 24 * 60 * 60
+)
+
+// ProvisionMonitoring ensures the monitoring stack is installed: the kube-prometheus-stack Helm
+// chart with the Kibaship Grafana dashboards (deployments by phase, build durations, certificate
+// expiries) and alert rules. The chart is installed by a Job running helm, named after the hash
+// of the chart version, values and helm image, so the Job only runs again when the configuration
+// or the registry mirror changes.
+// It is idempotent and safe to call on every manager start.
+//
+// Resources created in the kibaship-monitoring namespace:
+//  1. Grafana admin Secret with a generated password, kept once created
+//  2. Dashboards ConfigMap, loaded by the Grafana sidecar
+//  3. Helm values ConfigMap
+//  4. Installer ServiceAccount and ClusterRoleBinding, the chart installs CRDs and cluster roles
+//  5. Install Job
+func ProvisionMonitoring(ctx context.Context, c client.Client, monitoring config.MonitoringConfig) error {
+	log := ctrl.Log.WithName("bootstrap").WithName("monitoring")
+
+	if !monitoring.Enabled() {
+		log.Info("No monitoring provider configured, skipping monitoring stack provisioning")
+		return nil
+	}
+
+	log.Info("Provisioning monitoring stack", "provider", monitoring.Provider, "chartVersion", monitoring.ChartVersion,
+		"retention", monitoring.Retention)
+
+	if err := ensureNamespace(ctx, c, config.MonitoringNamespace); err != nil {
+		return fmt.Errorf("ensure monitoring namespace: %w", err)
+	}
+
+	if err := ensureGrafanaAdminSecret(ctx, c); err != nil {
+		return fmt.Errorf("ensure Grafana admin secret: %w", err)
+	}
+
+	dashboards := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: MonitoringDashboardsName, Namespace: config.MonitoringNamespace}}
+	if err := ensureMonitoringObject(ctx, c, dashboards, func() error {
+		dashboards.Labels = monitoringLabels(MonitoringDashboardsName)
+		dashboards.Labels[grafanaDashboardLabel] = "1"
+		dashboards.Data = map[string]string{"kibaship.json": kibashipDashboard}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("ensure dashboards: %w", err)
+	}
+
+	values := monitoringValues(monitoring)
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: MonitoringValuesName, Namespace: config.MonitoringNamespace}}
+	if err := ensureMonitoringObject(ctx, c, cm, func() error {
+		cm.Labels = monitoringLabels(MonitoringInstallerName)
+		cm.Data = map[string]string{"values.yaml": values}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("ensure Helm values: %w", err)
+	}
+
+	if err := ensureMonitoringInstaller(ctx, c); err != nil {
+		return fmt.Errorf("ensure installer: %w", err)
+	}
+
+	if err := ensureMonitoringInstallJob(ctx, c, monitoring.ChartVersion, values); err != nil {
+		return fmt.Errorf("ensure install job: %w", err)
+	}
+
+	log.Info("Monitoring stack provisioning completed successfully")
+	return nil
+}
+
+// ensureGrafanaAdminSecret creates the Grafana admin credentials. The password of an existing
+// Secret is kept, so restarting the operator does not lock administrators out.
+func ensureGrafanaAdminSecret(ctx context.Context, c client.Client) error {
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: config.MonitoringNamespace, Name: GrafanaAdminSecretName}, secret)
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	password, err := generateMonitoringPassword()
+	if err != nil {
+		return err
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GrafanaAdminSecretName,
+			Namespace: config.MonitoringNamespace,
+			Labels:    monitoringLabels("grafana"),
+		},
+		Type: corev1.SecretTypeOpaque,
+		StringData: map[string]string{
+			"admin-user":     "admin",
+			"admin-password": password,
+		},
+	}
+	if err := c.Create(ctx, secret); err != nil {
+		return err
+	}
+	ctrl.Log.WithName("bootstrap").WithName("monitoring").Info("Grafana admin secret created", "name", GrafanaAdminSecretName)
+	return nil
+}
+
+// ensureMonitoringInstaller creates the service account the install Jobs run as
+func ensureMonitoringInstaller(ctx context.Context, c client.Client) error {
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: MonitoringInstallerName, Namespace: config.MonitoringNamespace}}
+	if err := ensureMonitoringObject(ctx, c, sa, func() error {
+		sa.Labels = monitoringLabels(MonitoringInstallerName)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	binding := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: MonitoringInstallerName}}
+	return ensureMonitoringObject(ctx, c, binding, func() error {
+		binding.Labels = monitoringLabels(MonitoringInstallerName)
+		binding.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "cluster-admin"}
+		binding.Subjects = []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      MonitoringInstallerName,
+			Namespace: config.MonitoringNamespace,
+		}}
+		return nil
+	})
+}
+
+// ensureMonitoringInstallJob starts the Job installing the chart, unless the Job of the same chart
+// version, values and helm image already exists. A new registry mirror starts a new Job pulling
+// helm from it.
+func ensureMonitoringInstallJob(ctx context.Context, c client.Client, chartVersion, values string) error {
+	log := ctrl.Log.WithName("bootstrap").WithName("monitoring")
+
+	image := mirrorImage(HelmImage)
+	sum := sha256.Sum256([]byte(chartVersion + "\n" + image + "\n" + values))
+	name := MonitoringInstallerName + "-" + hex.EncodeToString(sum[:])[:10]
+
+	existing := &batchv1.Job{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: config.MonitoringNamespace, Name: name}, existing)
+	if err == nil {
+		log.Info("Monitoring stack install job already exists", "name", name)
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return err
+	}
+
+	backoffLimit := int32(3)
+	ttl := int32(monitoringInstallTTLSeconds)
+	labels := monitoringLabels(MonitoringInstallerName)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: config.MonitoringNamespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					PriorityClassName:  config.PriorityClassSystem,
+					ServiceAccountName: MonitoringInstallerName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:  "helm",
+						Image: image,
+						Args: []string{
+							"upgrade", "--install", config.MonitoringReleaseName, "kube-prometheus-stack",
+							"--repo", monitoringChartRepository,
+							"--version", chartVersion,
+							"--namespace", config.MonitoringNamespace,
+							"--values", "/etc/kube-prometheus-stack/values.yaml",
+							"--wait", "--timeout", "15m",
+						},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("50m"),
+								corev1.ResourceMemory: resource.MustParse("64Mi"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("256Mi"),
+							},
+						},
+						VolumeMounts: []corev1.VolumeMount{{Name: "values", MountPath: "/etc/kube-prometheus-stack"}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "values",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: MonitoringValuesName},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+	if err := c.Create(ctx, job); err != nil {
+		return err
+	}
+	log.Info("Monitoring stack install job created", "name", name, "chartVersion", chartVersion)
+	return nil
+}
+
+// ensureMonitoringObject creates the object or updates it to match the mutate function
+func ensureMonitoringObject(ctx context.Context, c client.Client, obj client.Object, mutate func() error) error {
+	log := ctrl.Log.WithName("bootstrap").WithName("monitoring")
+	kind := fmt.Sprintf("%T", obj)
+
+	result, err := controllerutil.CreateOrUpdate(ctx, c, obj, mutate)
+	if err != nil {
+		log.Error(err, "Failed to ensure monitoring resource", "kind", kind, "name", obj.GetName())
+		return err
+	}
+	log.Info("Monitoring resource ensured", "kind", kind, "name", obj.GetName(), "result", result)
+	return nil
+}
+
+// monitoringValues renders the kube-prometheus-stack values. Prometheus picks up the
+// ServiceMonitors of every namespace, scrapes Tekton and cert-manager, and kube-state-metrics
+// exports the phase of Kibaship deployments as kibaship_deployment_status_phase.
+func monitoringValues(monitoring config.MonitoringConfig) string {
+	storageClass := ""
+	if monitoring.StorageClass != "" {
+		storageClass = fmt.Sprintf("\n          storageClassName: %s", monitoring.StorageClass)
+	}
+	return fmt.Sprintf(`grafana:
+  admin:
+    existingSecret: %s
+    userKey: admin-user
+    passwordKey: admin-password
+  sidecar:
+    dashboards:
+      enabled: true
+      label: %s
+      labelValue: "1"
+prometheus:
+  prometheusSpec:
+    priorityClassName: %s
+    retention: %dh
+    serviceMonitorSelectorNilUsesHelmValues: false
+    podMonitorSelectorNilUsesHelmValues: false
+    ruleSelectorNilUsesHelmValues: false
+    storageSpec:
+      volumeClaimTemplate:
+        spec:%s
+          accessModes: ["ReadWriteOnce"]
+          resources:
+            requests:
+              storage: %s
+    additionalScrapeConfigs:
+      - job_name: tekton-pipelines
+        kubernetes_sd_configs:
+          - role: endpoints
+            namespaces:
+              names: [tekton-pipelines]
+        relabel_configs:
+          - source_labels: [__meta_kubernetes_service_name, __meta_kubernetes_endpoint_port_name]
+            action: keep
+            regex: tekton-pipelines-controller;http-metrics
+      - job_name: cert-manager
+        kubernetes_sd_configs:
+          - role: endpoints
+            namespaces:
+              names: [cert-manager]
+        relabel_configs:
+          - source_labels: [__meta_kubernetes_service_name, __meta_kubernetes_endpoint_port_name]
+            action: keep
+            regex: cert-manager;tcp-prometheus-servicemonitor
+kube-state-metrics:
+  rbac:
+    extraRules:
+      - apiGroups: [platform.operator.kibaship.com]
+        resources: [deployments]
+        verbs: [list, watch]
+  customResourceState:
+    enabled: true
+    config:
+      kind: CustomResourceStateMetrics
+      spec:
+        resources:
+          - groupVersionKind:
+              group: platform.operator.kibaship.com
+              version: v1alpha1
+              kind: Deployment
+            metricNamePrefix: kibaship_deployment
+            labelsFromPath:
+              name: [metadata, name]
+              namespace: [metadata, namespace]
+              application_uuid: [metadata, labels, %s]
+              project_uuid: [metadata, labels, %s]
+            metrics:
+              - name: status_phase
+                help: The phase of the deployment
+                each:
+                  type: StateSet
+                  stateSet:
+                    labelName: phase
+                    path: [status, phase]
+                    list: [Initializing, Preparing, Building, Deploying, Running, Succeeded, Failed, Waiting]
+additionalPrometheusRulesMap:
+  kibaship:
+%s`, GrafanaAdminSecretName, grafanaDashboardLabel, config.PriorityClassSystem,
+		int64(monitoring.Retention.Hours()), storageClass, monitoring.StorageSize,
+		validation.LabelApplicationUUID, validation.LabelProjectUUID, kibashipAlertRules)
+}
+
+// kibashipAlertRules are the alert rules of the Kibaship platform, indented under their
+// additionalPrometheusRulesMap entry. Annotations hold no templates, the chart may render the
+// rules through tpl; the namespace and name of the failing resource are alert labels.
+const kibashipAlertRules = `    groups:
+      - name: kibaship
+        rules:
+          - alert: KibashipDeploymentsFailing
+            expr: sum(kibaship_deployment_status_phase{phase="Failed"}) - sum(kibaship_deployment_status_phase{phase="Failed"} offset 15m) > 0
+            labels:
+              severity: warning
+            annotations:
+              summary: Deployments failed in the last 15 minutes
+          - alert: KibashipDeploymentStuck
+            expr: max by (namespace, name) (kibaship_deployment_status_phase{phase=~"Initializing|Preparing|Building|Deploying"}) == 1
+            for: 1h
+            labels:
+              severity: warning
+            annotations:
+              summary: A deployment has not progressed for over an hour
+          - alert: KibashipBuildsSlow
+            expr: histogram_quantile(0.95, sum by (le) (rate(tekton_pipelines_controller_pipelinerun_duration_seconds_bucket[1h]))) > 1800
+            for: 30m
+            labels:
+              severity: info
+            annotations:
+              summary: The 95th percentile build duration over the last hour is above 30 minutes
+          - alert: KibashipCertificateExpiringSoon
+            expr: certmanager_certificate_expiration_timestamp_seconds - time() < 7 * 24 * 3600
+            for: 1h
+            labels:
+              severity: warning
+            annotations:
+              summary: A certificate expires in less than 7 days
+          - alert: KibashipCertificateNotReady
+            expr: max by (namespace, name) (certmanager_certificate_ready_status{condition="False"}) == 1
+            for: 30m
+            labels:
+              severity: warning
+            annotations:
+              summary: A certificate has not been ready for 30 minutes
+`
+
+// kibashipDashboard is the Grafana dashboard of the Kibaship platform
+const kibashipDashboard = `{
+  "title": "Kibaship",
+  "uid": "kibaship-platform",
+  "tags": ["kibaship"],
+  "timezone": "browser",
+  "schemaVersion": 39,
+  "refresh": "1m",
+  "time": {"from": "now-24h", "to": "now"},
+  "panels": [
+    {
+      "id": 1,
+      "title": "Deployments by phase",
+      "type": "bargauge",
+      "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8},
+      "datasource": {"type": "prometheus", "uid": "prometheus"},
+      "targets": [
+        {"refId": "A", "expr": "sum by (phase) (kibaship_deployment_status_phase)", "legendFormat": "{{phase}}", "instant": true}
+      ]
+    },
+    {
+      "id": 2,
+      "title": "Failed deployments",
+      "type": "timeseries",
+      "gridPos": {"x": 12, "y": 0, "w": 12, "h": 8},
+      "datasource": {"type": "prometheus", "uid": "prometheus"},
+      "targets": [
+        {"refId": "A", "expr": "sum by (project_uuid) (kibaship_deployment_status_phase{phase=\"Failed\"})", "legendFormat": "{{project_uuid}}"}
+      ]
+    },
+    {
+      "id": 3,
+      "title": "Build durations",
+      "type": "timeseries",
+      "gridPos": {"x": 0, "y": 8, "w": 12, "h": 8},
+      "datasource": {"type": "prometheus", "uid": "prometheus"},
+      "fieldConfig": {"defaults": {"unit": "s"}},
+      "targets": [
+        {"refId": "A", "expr": "histogram_quantile(0.5, sum by (le) (rate(tekton_pipelines_controller_pipelinerun_duration_seconds_bucket[1h])))", "legendFormat": "p50"},
+        {"refId": "B", "expr": "histogram_quantile(0.95, sum by (le) (rate(tekton_pipelines_controller_pipelinerun_duration_seconds_bucket[1h])))", "legendFormat": "p95"}
+      ]
+    },
+    {
+      "id": 4,
+      "title": "Certificate expiries",
+      "type": "table",
+      "gridPos": {"x": 12, "y": 8, "w": 12, "h": 8},
+      "datasource": {"type": "prometheus", "uid": "prometheus"},
+      "fieldConfig": {"defaults": {"unit": "s"}},
+      "targets": [
+        {"refId": "A", "expr": "sort(certmanager_certificate_expiration_timestamp_seconds - time())", "format": "table", "instant": true}
+      ]
+    }
+  ]
+}
+`
+
+// generateMonitoringPassword returns a random alphanumeric password
+func generateMonitoringPassword() (string, error) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	password := make([]byte, 32)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		password[i] = alphabet[n.Int64()]
+	}
+	return string(password), nil
+}
+
+func monitoringLabels(component string) map[string]string {
+	return map[string]string{
+		"app":                          component,
+		"app.kubernetes.io/name":       component,
+		"app.kubernetes.io/component":  "monitoring",
+		"app.kubernetes.io/managed-by": "kibaship",
+	}
+}
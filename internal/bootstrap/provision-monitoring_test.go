@@ -0,0 +1,97 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func TestProvisionMonitoringDisabled(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	g.Expect(ProvisionMonitoring(ctx, fakeClient, config.MonitoringConfig{})).To(Succeed())
+
+	err := fakeClient.Get(ctx, client.ObjectKey{Name: config.MonitoringNamespace}, &corev1.Namespace{})
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestProvisionMonitoring(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+
+	monitoring := config.MonitoringConfig{
+		Provider:     config.MonitoringProviderKubePrometheusStack,
+		ChartVersion: "75.15.1",
+		Retention:    360 * time.Hour,
+		StorageSize:  "50Gi",
+		StorageClass: "storage-replica-2",
+	}
+	g.Expect(ProvisionMonitoring(ctx, fakeClient, monitoring)).To(Succeed())
+
+	secret := &corev1.Secret{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.MonitoringNamespace, Name: GrafanaAdminSecretName}, secret)).To(Succeed())
+	password := secret.StringData["admin-password"]
+	g.Expect(password).To(HaveLen(32))
+
+	dashboards := &corev1.ConfigMap{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.MonitoringNamespace, Name: MonitoringDashboardsName}, dashboards)).To(Succeed())
+	g.Expect(dashboards.Labels).To(HaveKeyWithValue("grafana_dashboard", "1"))
+	g.Expect(json.Valid([]byte(dashboards.Data["kibaship.json"]))).To(BeTrue())
+
+	values := &corev1.ConfigMap{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.MonitoringNamespace, Name: MonitoringValuesName}, values)).To(Succeed())
+	var parsed map[string]any
+	g.Expect(yaml.Unmarshal([]byte(values.Data["values.yaml"]), &parsed)).To(Succeed())
+	g.Expect(values.Data["values.yaml"]).To(ContainSubstring("retention: 360h"))
+	g.Expect(values.Data["values.yaml"]).To(ContainSubstring("storageClassName: storage-replica-2"))
+	g.Expect(values.Data["values.yaml"]).To(ContainSubstring("storage: 50Gi"))
+	g.Expect(values.Data["values.yaml"]).To(ContainSubstring("platform.kibaship.com/application-uuid"))
+	g.Expect(parsed).To(HaveKey("additionalPrometheusRulesMap"))
+
+	binding := &rbacv1.ClusterRoleBinding{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: MonitoringInstallerName}, binding)).To(Succeed())
+	g.Expect(binding.Subjects[0].Namespace).To(Equal(config.MonitoringNamespace))
+
+	jobs := &batchv1.JobList{}
+	g.Expect(fakeClient.List(ctx, jobs, client.InNamespace(config.MonitoringNamespace))).To(Succeed())
+	g.Expect(jobs.Items).To(HaveLen(1))
+	g.Expect(jobs.Items[0].Spec.Template.Spec.ServiceAccountName).To(Equal(MonitoringInstallerName))
+	g.Expect(jobs.Items[0].Spec.Template.Spec.Containers[0].Args).To(ContainElements("--version", "75.15.1"))
+
+	// Provisioning again keeps the password and does not reinstall the chart
+	g.Expect(ProvisionMonitoring(ctx, fakeClient, monitoring)).To(Succeed())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: config.MonitoringNamespace, Name: GrafanaAdminSecretName}, secret)).To(Succeed())
+	g.Expect(secret.StringData["admin-password"]).To(Equal(password))
+	g.Expect(fakeClient.List(ctx, jobs, client.InNamespace(config.MonitoringNamespace))).To(Succeed())
+	g.Expect(jobs.Items).To(HaveLen(1))
+
+	// A configuration change installs the chart again
+	monitoring.Retention = 720 * time.Hour
+	g.Expect(ProvisionMonitoring(ctx, fakeClient, monitoring)).To(Succeed())
+	g.Expect(fakeClient.List(ctx, jobs, client.InNamespace(config.MonitoringNamespace))).To(Succeed())
+	g.Expect(jobs.Items).To(HaveLen(2))
+
+	// So does a new registry mirror, helm is pulled from it
+	SetImageMirror("mirror.internal")
+	t.Cleanup(func() { SetImageMirror("") })
+	g.Expect(ProvisionMonitoring(ctx, fakeClient, monitoring)).To(Succeed())
+	g.Expect(fakeClient.List(ctx, jobs, client.InNamespace(config.MonitoringNamespace))).To(Succeed())
+	g.Expect(jobs.Items).To(HaveLen(3))
+}
@@ -0,0 +1,84 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// priorityClassSpec describes a PriorityClass provisioned by the operator
+type priorityClassSpec struct {
+	name             string
+	value            int32
+	preemptionPolicy corev1.PreemptionPolicy
+	description      string
+}
+
+// platformPriorityClasses are ordered from the lowest to the highest priority. All of them stay
+// below the system-cluster-critical and system-node-critical classes of Kubernetes.
+var platformPriorityClasses = []priorityClassSpec{
+	{
+		name:             config.PriorityClassBuild,
+		value:            1000,
+		preemptionPolicy: corev1.PreemptNever,
+		description:      "Kibaship pipeline and image build pods. Builds wait for capacity instead of preempting other pods.",
+	},
+	{
+		name:             config.PriorityClassRuntime,
+		value:            100000,
+		preemptionPolicy: corev1.PreemptLowerPriority,
+		description:      "Kibaship application pods. Applications may preempt builds.",
+	},
+	{
+		name:             config.PriorityClassSystem,
+		value:            1000000,
+		preemptionPolicy: corev1.PreemptLowerPriority,
+		description:      "Kibaship platform components.",
+	},
+}
+
+// EnsurePriorityClasses creates the PriorityClasses assigned to build, application and platform pods.
+// The value and preemption policy of a PriorityClass are immutable, existing classes are left as is.
+// It is idempotent and safe to call on every manager start.
+func EnsurePriorityClasses(ctx context.Context, c client.Client) error {
+	log := ctrl.Log.WithName("bootstrap").WithName("priority-classes")
+
+	for _, spec := range platformPriorityClasses {
+		existing := &schedulingv1.PriorityClass{}
+		err := c.Get(ctx, client.ObjectKey{Name: spec.name}, existing)
+		if err == nil {
+			log.Info("Priority class already exists", "name", spec.name, "value", existing.Value)
+			continue
+		}
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("get priority class %s: %w", spec.name, err)
+		}
+
+		preemptionPolicy := spec.preemptionPolicy
+		class := &schedulingv1.PriorityClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: spec.name,
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "kibaship",
+				},
+			},
+			Value:            spec.value,
+			PreemptionPolicy: &preemptionPolicy,
+			Description:      spec.description,
+		}
+		if err := c.Create(ctx, class); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("create priority class %s: %w", spec.name, err)
+		}
+		log.Info("Priority class created", "name", spec.name, "value", spec.value)
+	}
+
+	return nil
+}
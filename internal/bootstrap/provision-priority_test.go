@@ -0,0 +1,57 @@
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func TestEnsurePriorityClasses(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	g.Expect(EnsurePriorityClasses(ctx, fakeClient)).To(Succeed())
+
+	build := &schedulingv1.PriorityClass{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: config.PriorityClassBuild}, build)).To(Succeed())
+	g.Expect(*build.PreemptionPolicy).To(Equal(corev1.PreemptNever))
+
+	runtime := &schedulingv1.PriorityClass{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: config.PriorityClassRuntime}, runtime)).To(Succeed())
+	g.Expect(*runtime.PreemptionPolicy).To(Equal(corev1.PreemptLowerPriority))
+
+	system := &schedulingv1.PriorityClass{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: config.PriorityClassSystem}, system)).To(Succeed())
+
+	g.Expect(build.Value).To(BeNumerically("<", runtime.Value))
+	g.Expect(runtime.Value).To(BeNumerically("<", system.Value))
+	g.Expect(build.GlobalDefault).To(BeFalse())
+	g.Expect(runtime.GlobalDefault).To(BeFalse())
+}
+
+func TestEnsurePriorityClassesKeepsExisting(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	existing := &schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{Name: config.PriorityClassRuntime},
+		Value:      500000,
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(existing).Build()
+	g.Expect(EnsurePriorityClasses(ctx, fakeClient)).To(Succeed())
+	g.Expect(EnsurePriorityClasses(ctx, fakeClient)).To(Succeed())
+
+	runtime := &schedulingv1.PriorityClass{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: config.PriorityClassRuntime}, runtime)).To(Succeed())
+	g.Expect(runtime.Value).To(Equal(int32(500000)))
+}
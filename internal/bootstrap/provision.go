@@ -10,6 +10,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"math/big"
+	"reflect"
 	"time"
 
 	"github.com/kibamail/kibaship/pkg/config"
@@ -28,32 +29,36 @@ const (
 	issuerName = "certmanager-acme-issuer"
 )
 
-// EnsureStorageClasses creates Longhorn-backed StorageClasses used by the platform.
+// EnsureStorageClasses creates the StorageClasses configured in the operator ConfigMap.
+// When no classes are configured the Longhorn-backed defaults are used.
 // It is safe to call repeatedly; it only creates them if missing.
-func EnsureStorageClasses(ctx context.Context, c client.Client) error {
+func EnsureStorageClasses(ctx context.Context, c client.Client, classes []config.StorageClassConfig) error {
 	log := ctrl.Log.WithName("bootstrap").WithName("storage-classes")
 	log.Info("Starting storage classes provisioning")
 
-	log.Info("Ensuring storage class", "name", config.StorageClassReplica1, "replicas", "1")
-	if err := ensureStorageClass(ctx, c, config.StorageClassReplica1, "1"); err != nil {
-		log.Error(err, "Failed to ensure storage class", "name", config.StorageClassReplica1)
-		return err
+	if len(classes) == 0 {
+		classes = config.DefaultStorageClasses()
 	}
-	log.Info("Storage class ensured successfully", "name", config.StorageClassReplica1)
 
-	log.Info("Ensuring storage class", "name", config.StorageClassReplica2, "replicas", "2")
-	if err := ensureStorageClass(ctx, c, config.StorageClassReplica2, "2"); err != nil {
-		log.Error(err, "Failed to ensure storage class", "name", config.StorageClassReplica2)
-		return err
+	for _, class := range classes {
+		log.Info("Ensuring storage class", "name", class.Name, "provisioner", class.Provisioner, "replicas", class.Replicas, "default", class.Default)
+		if err := ensureStorageClass(ctx, c, class); err != nil {
+			log.Error(err, "Failed to ensure storage class", "name", class.Name)
+			return err
+		}
+		log.Info("Storage class ensured successfully", "name", class.Name)
 	}
-	log.Info("Storage class ensured successfully", "name", config.StorageClassReplica2)
 
 	log.Info("Storage classes provisioning completed successfully")
 	return nil
 }
 
-func ensureStorageClass(ctx context.Context, c client.Client, name, replicas string) error {
+// defaultStorageClassAnnotation marks a StorageClass as the cluster default
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+func ensureStorageClass(ctx context.Context, c client.Client, class config.StorageClassConfig) error {
 	log := ctrl.Log.WithName("bootstrap").WithName("storage-class")
+	name := class.Name
 	obj := &unstructured.Unstructured{}
 	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"})
 	obj.SetName(name)
@@ -64,21 +69,58 @@ func ensureStorageClass(ctx context.Context, c client.Client, name, replicas str
 			log.Error(err, "Failed to check storage class", "name", name)
 			return err
 		}
-		log.Info("Storage class not found, creating", "name", name, "replicas", replicas)
-		obj.Object["provisioner"] = "driver.longhorn.io"
+		log.Info("Storage class not found, creating", "name", name, "provisioner", class.Provisioner, "replicas", class.Replicas)
+
+		volumeBindingMode := class.VolumeBindingMode
+		if volumeBindingMode == "" {
+			volumeBindingMode = "WaitForFirstConsumer"
+		}
+
+		parameters := map[string]any{}
+		for k, v := range class.StorageParameters() {
+			parameters[k] = v
+		}
+
+		obj.Object["provisioner"] = class.Provisioner
 		obj.Object["allowVolumeExpansion"] = true
-		obj.Object["volumeBindingMode"] = "WaitForFirstConsumer"
-		obj.Object["parameters"] = map[string]any{
-			"numberOfReplicas": replicas,
+		obj.Object["volumeBindingMode"] = volumeBindingMode
+		obj.Object["parameters"] = parameters
+		if class.ReclaimPolicy != "" {
+			obj.Object["reclaimPolicy"] = class.ReclaimPolicy
+		}
+		if class.Default {
+			obj.SetAnnotations(map[string]string{defaultStorageClassAnnotation: "true"})
 		}
 		if err := c.Create(ctx, obj); err != nil {
 			log.Error(err, "Failed to create storage class", "name", name)
 			return err
 		}
 		log.Info("Storage class created successfully", "name", name)
+		return nil
+	}
+
+	log.Info("Storage class already exists", "name", name)
+
+	// Provisioner and parameters are immutable, only the default flag can be reconciled
+	annotations := obj.GetAnnotations()
+	isDefault := annotations[defaultStorageClassAnnotation] == "true"
+	if isDefault == class.Default {
+		return nil
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if class.Default {
+		annotations[defaultStorageClassAnnotation] = "true"
 	} else {
-		log.Info("Storage class already exists", "name", name)
+		delete(annotations, defaultStorageClassAnnotation)
 	}
+	obj.SetAnnotations(annotations)
+	if err := c.Update(ctx, obj); err != nil {
+		log.Error(err, "Failed to update default flag on storage class", "name", name)
+		return err
+	}
+	log.Info("Storage class default flag updated", "name", name, "default", class.Default)
 	return nil
 }
 
@@ -87,9 +129,13 @@ func ensureStorageClass(ctx context.Context, c client.Client, name, replicas str
 //
 // This function orchestrates the provisioning of:
 //   - ACME-DNS server for DNS-01 challenges
-//   - ClusterIssuer for ACME certificates
-//   - Ingress resources (Gateway, certificates, routes) via ProvisionIngress
-func ProvisionIngressAndCertificates(ctx context.Context, c client.Client, baseDomain, acmeEmail, acmeEnv, gatewayClassName string) error {
+//   - ClusterIssuer for ACME certificates, solving the platform zones through the configured
+//     DNS provider and every other domain through acme-dns
+//   - Ingress resources (Gateway, certificates, routes) via ProvisionIngress, when the
+//     Gateway API ingress provider is selected. Ingress based providers (nginx, traefik)
+//     are expected to be installed in the cluster and request certificates per Ingress.
+func ProvisionIngressAndCertificates(ctx context.Context, c client.Client, baseDomain, acmeEmail, acmeEnv string,
+	acmeDNS config.ACMEDNSConfig, gatewayClassName string, ingressProvider config.IngressProvider) error {
 	if baseDomain == "" {
 		return nil // nothing to do without a domain
 	}
@@ -101,13 +147,17 @@ func ProvisionIngressAndCertificates(ctx context.Context, c client.Client, baseD
 
 	// 2) ClusterIssuer (requires cert-manager CRDs to exist)
 	if acmeEmail != "" {
-		if err := ensureClusterIssuer(ctx, c, acmeEmail, acmeEnv); err != nil {
+		if err := ensureClusterIssuer(ctx, c, acmeEmail, acmeEnv, acmeDNS); err != nil {
 			return fmt.Errorf("ensure ClusterIssuer: %w", err)
 		}
 	}
 
 	// 3) Ingress provisioning (wildcard certificate, Gateway, ReferenceGrant)
 	// This is handled in provision-ingress.go
+	if ingressProvider != "" && !ingressProvider.UsesGatewayAPI() {
+		ctrl.Log.WithName("bootstrap").WithName("ingress").Info("Skipping Gateway provisioning for ingress provider", "provider", ingressProvider)
+		return nil
+	}
 	if err := ProvisionIngress(ctx, c, baseDomain, acmeEmail, gatewayClassName); err != nil {
 		return fmt.Errorf("provision ingress: %w", err)
 	}
@@ -126,7 +176,7 @@ func ensureNamespace(ctx context.Context, c client.Client, name string) error {
 	return nil
 }
 
-func ensureClusterIssuer(ctx context.Context, c client.Client, email, acmeEnv string) error {
+func ensureClusterIssuer(ctx context.Context, c client.Client, email, acmeEnv string, acmeDNS config.ACMEDNSConfig) error {
 	obj := &unstructured.Unstructured{}
 	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "ClusterIssuer"})
 	obj.SetName(issuerName)
@@ -137,36 +187,118 @@ func ensureClusterIssuer(ctx context.Context, c client.Client, email, acmeEnv st
 		}
 
 		// Determine ACME server URL based on environment
-		var acmeServerURL string
-		if acmeEnv == "staging" {
-			acmeServerURL = "https://acme-staging-v02.api.letsencrypt.org/directory"
-		} else {
-			acmeServerURL = "https://acme-v02.api.letsencrypt.org/directory"
-		}
+		acmeServerURL := acmeServerURLFor(acmeEnv)
 
 		obj.Object["spec"] = map[string]any{
 			"acme": map[string]any{
 				"email":               email,
 				"server":              acmeServerURL,
 				"privateKeySecretRef": map[string]any{"name": "acme-certificates-private-key"},
-				"solvers": []any{
-					map[string]any{
-						"dns01": map[string]any{
-							"acmeDNS": map[string]any{
-								"host": "http://acme-dns.kibaship.svc.cluster.local",
-								"accountSecretRef": map[string]any{
-									"name": "acme-dns-account",
-									"key":  "acmedns.json",
-								},
-							},
+				"solvers":             acmeSolvers(acmeDNS),
+			},
+		}
+		return c.Create(ctx, obj)
+	}
+
+	// Keep the account email, ACME server and solvers in sync with the operator configuration
+	acmeServerURL := acmeServerURLFor(acmeEnv)
+	solvers := acmeSolvers(acmeDNS)
+	currentEmail, _, _ := unstructured.NestedString(obj.Object, "spec", "acme", "email")
+	currentServer, _, _ := unstructured.NestedString(obj.Object, "spec", "acme", "server")
+	currentSolvers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "acme", "solvers")
+	if currentEmail == email && currentServer == acmeServerURL && reflect.DeepEqual(currentSolvers, solvers) {
+		return nil
+	}
+	if err := unstructured.SetNestedField(obj.Object, email, "spec", "acme", "email"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(obj.Object, acmeServerURL, "spec", "acme", "server"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedSlice(obj.Object, solvers, "spec", "acme", "solvers"); err != nil {
+		return err
+	}
+	return c.Update(ctx, obj)
+}
+
+// acmeSolvers returns the DNS01 solvers of the ClusterIssuer. cert-manager picks the solver whose
+// dnsZones selector matches a certificate name most specifically, so the platform zones go to the
+// configured DNS provider and every other domain falls back to the unselected acme-dns solver.
+func acmeSolvers(acmeDNS config.ACMEDNSConfig) []any {
+	acmeDNSSolver := map[string]any{
+		"dns01": map[string]any{
+			"acmeDNS": map[string]any{
+				"host": "http://acme-dns.kibaship.svc.cluster.local",
+				"accountSecretRef": map[string]any{
+					"name": "acme-dns-account",
+					"key":  "acmedns.json",
+				},
+			},
+		},
+	}
+	if !acmeDNS.Enabled() {
+		return []any{acmeDNSSolver}
+	}
+
+	secretRef := func(key string) map[string]any {
+		return map[string]any{"name": config.ACMEDNSCredentialsSecretName, "key": key}
+	}
+	zones := make([]any, 0, len(acmeDNS.Zones))
+	for _, zone := range acmeDNS.Zones {
+		zones = append(zones, zone)
+	}
+
+	var solvers []any
+	switch acmeDNS.Provider {
+	case config.ACMEDNSProviderCloudflare:
+		solvers = append(solvers, map[string]any{
+			"selector": map[string]any{"dnsZones": zones},
+			"dns01": map[string]any{
+				"cloudflare": map[string]any{"apiTokenSecretRef": secretRef("api-token")},
+			},
+		})
+	case config.ACMEDNSProviderRoute53:
+		route53 := map[string]any{
+			"region":                   acmeDNS.Route53Region,
+			"accessKeyIDSecretRef":     secretRef("access-key-id"),
+			"secretAccessKeySecretRef": secretRef("secret-access-key"),
+		}
+		if acmeDNS.Route53HostedZoneID != "" {
+			route53["hostedZoneID"] = acmeDNS.Route53HostedZoneID
+		}
+		solvers = append(solvers, map[string]any{
+			"selector": map[string]any{"dnsZones": zones},
+			"dns01":    map[string]any{"route53": route53},
+		})
+	case config.ACMEDNSProviderHetzner:
+		// The Hetzner webhook needs the zone name of each challenge, one solver per zone
+		for _, zone := range acmeDNS.Zones {
+			solvers = append(solvers, map[string]any{
+				"selector": map[string]any{"dnsZones": []any{zone}},
+				"dns01": map[string]any{
+					"webhook": map[string]any{
+						"groupName":  acmeDNS.HetznerGroupName,
+						"solverName": "hetzner",
+						"config": map[string]any{
+							"secretName": config.ACMEDNSCredentialsSecretName,
+							"zoneName":   zone,
+							"apiUrl":     config.DefaultHetznerDNSAPIURL,
 						},
 					},
 				},
-			},
+			})
 		}
-		return c.Create(ctx, obj)
 	}
-	return nil
+
+	return append(solvers, acmeDNSSolver)
+}
+
+// acmeServerURLFor returns the Let's Encrypt directory URL for the ACME environment
+func acmeServerURLFor(acmeEnv string) string {
+	if acmeEnv == "staging" {
+		return "https://acme-staging-v02.api.letsencrypt.org/directory"
+	}
+	return "https://acme-v02.api.letsencrypt.org/directory"
 }
 
 // EnsureRegistryCredentials provisions the registry-registry-auth secret in the registry namespace.
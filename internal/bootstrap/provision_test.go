@@ -10,6 +10,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kibamail/kibaship/pkg/config"
 )
 
 func TestEnsureClusterIssuerProduction(t *testing.T) {
@@ -22,7 +24,7 @@ func TestEnsureClusterIssuerProduction(t *testing.T) {
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
 
 	// Test production environment
-	err := ensureClusterIssuer(ctx, fakeClient, "test@example.com", "production")
+	err := ensureClusterIssuer(ctx, fakeClient, "test@example.com", "production", config.ACMEDNSConfig{})
 	g.Expect(err).NotTo(HaveOccurred())
 
 	// Verify ClusterIssuer was created with production URL
@@ -62,7 +64,7 @@ func TestEnsureClusterIssuerStaging(t *testing.T) {
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
 
 	// Test staging environment
-	err := ensureClusterIssuer(ctx, fakeClient, "test@example.com", "staging")
+	err := ensureClusterIssuer(ctx, fakeClient, "test@example.com", "staging", config.ACMEDNSConfig{})
 	g.Expect(err).NotTo(HaveOccurred())
 
 	// Verify ClusterIssuer was created with staging URL
@@ -102,11 +104,11 @@ func TestEnsureClusterIssuerIdempotent(t *testing.T) {
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
 
 	// Create ClusterIssuer first time
-	err := ensureClusterIssuer(ctx, fakeClient, "test@example.com", "production")
+	err := ensureClusterIssuer(ctx, fakeClient, "test@example.com", "production", config.ACMEDNSConfig{})
 	g.Expect(err).NotTo(HaveOccurred())
 
 	// Create ClusterIssuer second time (should be idempotent)
-	err = ensureClusterIssuer(ctx, fakeClient, "test@example.com", "production")
+	err = ensureClusterIssuer(ctx, fakeClient, "test@example.com", "production", config.ACMEDNSConfig{})
 	g.Expect(err).NotTo(HaveOccurred())
 
 	// Verify only one ClusterIssuer exists
@@ -115,3 +117,63 @@ func TestEnsureClusterIssuerIdempotent(t *testing.T) {
 	err = fakeClient.Get(ctx, client.ObjectKey{Name: "certmanager-acme-issuer"}, issuer)
 	g.Expect(err).NotTo(HaveOccurred())
 }
+
+func TestEnsureClusterIssuerDNSProviderSolvers(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+	getSolvers := func() []any {
+		issuer := &unstructured.Unstructured{}
+		issuer.SetGroupVersionKind(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "ClusterIssuer"})
+		g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: "certmanager-acme-issuer"}, issuer)).To(Succeed())
+		solvers, _, _ := unstructured.NestedSlice(issuer.Object, "spec", "acme", "solvers")
+		return solvers
+	}
+
+	g.Expect(ensureClusterIssuer(ctx, fakeClient, "ops@example.com", "production", config.ACMEDNSConfig{})).To(Succeed())
+	g.Expect(getSolvers()).To(HaveLen(1))
+
+	// A reload selects Cloudflare for the platform zone, acme-dns keeps solving every other domain
+	cloudflare := config.ACMEDNSConfig{Provider: config.ACMEDNSProviderCloudflare, Zones: []string{"example.com"}}
+	g.Expect(ensureClusterIssuer(ctx, fakeClient, "ops@example.com", "production", cloudflare)).To(Succeed())
+	solvers := getSolvers()
+	g.Expect(solvers).To(HaveLen(2))
+	zones, _, _ := unstructured.NestedStringSlice(solvers[0].(map[string]any), "selector", "dnsZones")
+	g.Expect(zones).To(Equal([]string{"example.com"}))
+	tokenSecret, _, _ := unstructured.NestedString(solvers[0].(map[string]any), "dns01", "cloudflare", "apiTokenSecretRef", "name")
+	g.Expect(tokenSecret).To(Equal(config.ACMEDNSCredentialsSecretName))
+	g.Expect(solvers[1]).NotTo(HaveKey("selector"))
+	g.Expect(solvers[1]).To(HaveKeyWithValue("dns01", HaveKey("acmeDNS")))
+
+	// Hetzner solves each zone with its own webhook solver
+	hetzner := config.ACMEDNSConfig{
+		Provider:         config.ACMEDNSProviderHetzner,
+		Zones:            []string{"example.com", "example.org"},
+		HetznerGroupName: "acme.example.com",
+	}
+	g.Expect(acmeSolvers(hetzner)).To(HaveLen(3))
+	zoneName, _, _ := unstructured.NestedString(acmeSolvers(hetzner)[1].(map[string]any), "dns01", "webhook", "config", "zoneName")
+	g.Expect(zoneName).To(Equal("example.org"))
+}
+
+func TestEnsureClusterIssuerUpdatesExistingIssuer(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+
+	g.Expect(ensureClusterIssuer(ctx, fakeClient, "ops@example.com", "staging", config.ACMEDNSConfig{})).To(Succeed())
+
+	// A configuration reload changes the email and the ACME environment
+	g.Expect(ensureClusterIssuer(ctx, fakeClient, "certs@example.com", "production", config.ACMEDNSConfig{})).To(Succeed())
+
+	issuer := &unstructured.Unstructured{}
+	issuer.SetGroupVersionKind(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "ClusterIssuer"})
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: "certmanager-acme-issuer"}, issuer)).To(Succeed())
+
+	email, _, _ := unstructured.NestedString(issuer.Object, "spec", "acme", "email")
+	server, _, _ := unstructured.NestedString(issuer.Object, "spec", "acme", "server")
+	g.Expect(email).To(Equal("certs@example.com"))
+	g.Expect(server).To(Equal("https://acme-v02.api.letsencrypt.org/directory"))
+}
@@ -0,0 +1,96 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// ApplyConfigurationChange re-runs the bootstrap steps affected by a change of the
+// operator configuration. Steps whose inputs did not change are skipped.
+// It is called by the operator configuration watcher after the new configuration
+// has been validated.
+func ApplyConfigurationChange(ctx context.Context, c client.Client, previous, current *config.OperatorConfiguration) error {
+	log := ctrl.Log.WithName("bootstrap").WithName("reload")
+
+	if previous == nil || current == nil {
+		return fmt.Errorf("previous and current configuration are required")
+	}
+
+	mirrorChanged := previous.ImageMirror != current.ImageMirror
+	if mirrorChanged {
+		log.Info("Registry mirror changed, re-provisioning mirrored components", "mirror", current.ImageMirror)
+		SetImageMirror(current.ImageMirror)
+		if err := ValidateImageMirror(ctx, current.ImageMirror); err != nil {
+			return fmt.Errorf("validate registry mirror: %w", err)
+		}
+		if err := EnsureBuildkitRegistryMirrors(ctx, c, current.ImageMirror); err != nil {
+			return fmt.Errorf("ensure BuildKit registry mirrors: %w", err)
+		}
+	}
+
+	if !reflect.DeepEqual(previous.StorageClasses, current.StorageClasses) {
+		log.Info("Storage classes changed, re-running storage class provisioning")
+		if err := EnsureStorageClasses(ctx, c, current.StorageClasses); err != nil {
+			return fmt.Errorf("ensure storage classes: %w", err)
+		}
+	}
+
+	if !reflect.DeepEqual(previous.LoadBalancer, current.LoadBalancer) {
+		log.Info("Load balancer configuration changed, re-running IP pool provisioning")
+		if err := ProvisionLoadBalancer(ctx, c, current.LoadBalancer); err != nil {
+			return fmt.Errorf("provision load balancer: %w", err)
+		}
+	}
+
+	if previous.Logging != current.Logging || mirrorChanged {
+		log.Info("Logging configuration changed, re-running logging pipeline provisioning")
+		if err := ProvisionLogging(ctx, c, current.Logging); err != nil {
+			return fmt.Errorf("provision logging: %w", err)
+		}
+	}
+
+	if previous.Artifacts != current.Artifacts || mirrorChanged {
+		log.Info("Artifacts configuration changed, re-running artifact service provisioning")
+		if err := ProvisionArtifacts(ctx, c, current.Artifacts); err != nil {
+			return fmt.Errorf("provision artifacts: %w", err)
+		}
+	}
+
+	if previous.Monitoring != current.Monitoring || mirrorChanged {
+		log.Info("Monitoring configuration changed, re-running monitoring stack provisioning")
+		if err := ProvisionMonitoring(ctx, c, current.Monitoring); err != nil {
+			return fmt.Errorf("provision monitoring: %w", err)
+		}
+	}
+
+	if previous.Domain != current.Domain ||
+		previous.ACMEEmail != current.ACMEEmail ||
+		previous.ACMEEnv != current.ACMEEnv ||
+		!reflect.DeepEqual(previous.ACMEDNS, current.ACMEDNS) ||
+		previous.GatewayClassName != current.GatewayClassName ||
+		previous.IngressProvider != current.IngressProvider ||
+		mirrorChanged {
+		log.Info("Ingress or certificate settings changed, re-running ingress provisioning",
+			"domain", current.Domain, "acmeEmail", current.ACMEEmail, "acmeEnv", current.ACMEEnv)
+		if err := ProvisionIngressAndCertificates(
+			ctx,
+			c,
+			current.Domain,
+			current.ACMEEmail,
+			current.ACMEEnv,
+			current.ACMEDNS,
+			current.GatewayClassName,
+			current.IngressProvider,
+		); err != nil {
+			return fmt.Errorf("provision ingress and certificates: %w", err)
+		}
+	}
+
+	return nil
+}
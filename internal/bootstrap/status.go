@@ -0,0 +1,220 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+const (
+	// initialRetryDelay is the delay before a failed bootstrap step is retried the first time
+	initialRetryDelay = time.Minute
+	// maxRetryDelay caps the doubling delay between the retries of a failed bootstrap step
+	maxRetryDelay = 30 * time.Minute
+	// retryCheckInterval is how often due retries are looked for
+	retryCheckInterval = 15 * time.Second
+)
+
+// StepFunc runs a bootstrap step against the operator configuration
+type StepFunc func(ctx context.Context, cfg *config.OperatorConfiguration) error
+
+type recordedStep struct {
+	status   config.BootstrapStepStatus
+	run      StepFunc
+	failures int
+}
+
+// StatusRecorder runs the bootstrap steps and records the outcome of each in the bootstrap
+// status ConfigMap, so a failed installation can be diagnosed without the operator logs.
+// Once added to the manager it retries the failed steps with an exponential backoff.
+type StatusRecorder struct {
+	client client.Client
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cfg   *config.OperatorConfiguration
+	steps []*recordedStep
+}
+
+// NewStatusRecorder creates a recorder running the bootstrap steps against cfg
+func NewStatusRecorder(c client.Client, cfg *config.OperatorConfiguration) *StatusRecorder {
+	return &StatusRecorder{
+		client: c,
+		now:    time.Now,
+		cfg:    cfg,
+	}
+}
+
+// Run runs a bootstrap step and records its outcome. Steps are numbered in the order they
+// are first run. The error of the step is returned for the caller to log.
+func (r *StatusRecorder) Run(ctx context.Context, name string, fn StepFunc) error {
+	r.mu.Lock()
+	step := &recordedStep{
+		status: config.BootstrapStepStatus{Step: len(r.steps) + 1, Name: name},
+		run:    fn,
+	}
+	r.steps = append(r.steps, step)
+	r.mu.Unlock()
+
+	return r.attempt(ctx, step)
+}
+
+// SetConfiguration replaces the configuration the steps run against. Failed steps are retried
+// on the next check, as the new configuration may fix them.
+func (r *StatusRecorder) SetConfiguration(cfg *config.OperatorConfiguration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cfg = cfg
+	now := r.now()
+	for _, step := range r.steps {
+		if step.status.State == config.BootstrapStepFailed {
+			step.status.NextRetryTime = &now
+		}
+	}
+}
+
+// Steps returns the recorded status of the bootstrap steps
+func (r *StatusRecorder) Steps() []config.BootstrapStepStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	steps := make([]config.BootstrapStepStatus, 0, len(r.steps))
+	for _, step := range r.steps {
+		steps = append(steps, step.status)
+	}
+	return steps
+}
+
+// Start retries the failed bootstrap steps once they are due until ctx is done
+func (r *StatusRecorder) Start(ctx context.Context) error {
+	ticker := time.NewTicker(retryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.RetryDue(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection only lets the leader retry the failed steps
+func (r *StatusRecorder) NeedLeaderElection() bool {
+	return true
+}
+
+// RetryDue retries the failed steps whose retry time has passed
+func (r *StatusRecorder) RetryDue(ctx context.Context) {
+	log := ctrl.Log.WithName("bootstrap").WithName("status")
+
+	r.mu.Lock()
+	now := r.now()
+	var due []*recordedStep
+	for _, step := range r.steps {
+		if step.status.State == config.BootstrapStepFailed && step.status.NextRetryTime != nil && !now.Before(*step.status.NextRetryTime) {
+			due = append(due, step)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, step := range due {
+		log.Info("Retrying failed bootstrap step", "step", step.status.Step, "name", step.status.Name)
+		if err := r.attempt(ctx, step); err != nil {
+			log.Error(err, "Bootstrap step retry failed", "step", step.status.Step, "name", step.status.Name)
+		} else {
+			log.Info("Bootstrap step retry succeeded", "step", step.status.Step, "name", step.status.Name)
+		}
+	}
+}
+
+func (r *StatusRecorder) attempt(ctx context.Context, step *recordedStep) error {
+	r.mu.Lock()
+	started := r.now()
+	cfg := r.cfg
+	step.status.State = config.BootstrapStepRunning
+	step.status.Attempts++
+	step.status.LastAttemptTime = &started
+	step.status.NextRetryTime = nil
+	r.mu.Unlock()
+
+	err := step.run(ctx, cfg)
+
+	r.mu.Lock()
+	if err != nil {
+		step.failures++
+		next := r.now().Add(retryDelay(step.failures))
+		step.status.State = config.BootstrapStepFailed
+		step.status.Error = err.Error()
+		step.status.NextRetryTime = &next
+	} else {
+		finished := r.now()
+		step.failures = 0
+		step.status.State = config.BootstrapStepSucceeded
+		step.status.Error = ""
+		step.status.LastSuccessTime = &finished
+	}
+	r.mu.Unlock()
+
+	if persistErr := r.persist(ctx); persistErr != nil {
+		ctrl.Log.WithName("bootstrap").WithName("status").Error(persistErr, "Failed to record bootstrap status", "configMap", config.BootstrapStatusConfigMapName)
+	}
+	return err
+}
+
+// retryDelay doubles the delay with every consecutive failure up to maxRetryDelay
+func retryDelay(failures int) time.Duration {
+	delay := initialRetryDelay
+	for i := 1; i < failures && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// persist writes the status of the steps to the bootstrap status ConfigMap
+func (r *StatusRecorder) persist(ctx context.Context) error {
+	encoded, err := json.Marshal(r.Steps())
+	if err != nil {
+		return err
+	}
+
+	status := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: config.OperatorNamespace, Name: config.BootstrapStatusConfigMapName}
+	exists := true
+	if err := r.client.Get(ctx, key, status); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		exists = false
+		status = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "kibaship",
+				},
+			},
+		}
+	}
+
+	if status.Data == nil {
+		status.Data = map[string]string{}
+	}
+	status.Data[config.BootstrapStatusKey] = string(encoded)
+
+	if exists {
+		return r.client.Update(ctx, status)
+	}
+	return r.client.Create(ctx, status)
+}
@@ -0,0 +1,90 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func TestStatusRecorderRecordsAndRetriesFailedSteps(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorder := NewStatusRecorder(fakeClient, &config.OperatorConfiguration{Domain: "old.example.com"})
+	recorder.now = func() time.Time { return now }
+
+	g.Expect(recorder.Run(ctx, "storage-classes", func(context.Context, *config.OperatorConfiguration) error {
+		return nil
+	})).To(Succeed())
+
+	var domains []string
+	ingressErr := errors.New("cert-manager is not installed")
+	g.Expect(recorder.Run(ctx, "ingress-certificates", func(_ context.Context, cfg *config.OperatorConfiguration) error {
+		domains = append(domains, cfg.Domain)
+		if cfg.Domain == "old.example.com" {
+			return ingressErr
+		}
+		return nil
+	})).To(MatchError(ingressErr))
+
+	status := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: config.OperatorNamespace, Name: config.BootstrapStatusConfigMapName}
+	g.Expect(fakeClient.Get(ctx, key, status)).To(Succeed())
+	steps, err := config.ParseBootstrapStatus(status.Data)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(steps).To(HaveLen(2))
+	g.Expect(steps[0].Step).To(Equal(1))
+	g.Expect(steps[0].State).To(Equal(config.BootstrapStepSucceeded))
+	g.Expect(steps[1].Step).To(Equal(2))
+	g.Expect(steps[1].Name).To(Equal("ingress-certificates"))
+	g.Expect(steps[1].State).To(Equal(config.BootstrapStepFailed))
+	g.Expect(steps[1].Error).To(Equal("cert-manager is not installed"))
+	g.Expect(steps[1].Attempts).To(Equal(1))
+	g.Expect(steps[1].NextRetryTime.Equal(now.Add(time.Minute))).To(BeTrue())
+
+	// Not due yet
+	recorder.RetryDue(ctx)
+	g.Expect(domains).To(HaveLen(1))
+
+	// Due, still failing: the delay doubles
+	now = now.Add(time.Minute)
+	recorder.RetryDue(ctx)
+	g.Expect(domains).To(HaveLen(2))
+	g.Expect(recorder.Steps()[1].Attempts).To(Equal(2))
+	g.Expect(recorder.Steps()[1].NextRetryTime.Equal(now.Add(2 * time.Minute))).To(BeTrue())
+
+	// A configuration change retries right away with the new configuration
+	recorder.SetConfiguration(&config.OperatorConfiguration{Domain: "new.example.com"})
+	recorder.RetryDue(ctx)
+	g.Expect(domains).To(Equal([]string{"old.example.com", "old.example.com", "new.example.com"}))
+
+	g.Expect(fakeClient.Get(ctx, key, status)).To(Succeed())
+	steps, err = config.ParseBootstrapStatus(status.Data)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(steps[1].State).To(Equal(config.BootstrapStepSucceeded))
+	g.Expect(steps[1].Error).To(BeEmpty())
+	g.Expect(steps[1].NextRetryTime).To(BeNil())
+	g.Expect(steps[1].LastSuccessTime).NotTo(BeNil())
+	g.Expect(steps[0].Attempts).To(Equal(1))
+}
+
+func TestRetryDelay(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(retryDelay(1)).To(Equal(time.Minute))
+	g.Expect(retryDelay(2)).To(Equal(2 * time.Minute))
+	g.Expect(retryDelay(5)).To(Equal(16 * time.Minute))
+	g.Expect(retryDelay(6)).To(Equal(30 * time.Minute))
+	g.Expect(retryDelay(100)).To(Equal(30 * time.Minute))
+}
@@ -0,0 +1,475 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	// defaultTeardownTimeout bounds the wait for each stage of the teardown
+	defaultTeardownTimeout = 5 * time.Minute
+	// teardownPollInterval is how often deleted resources are checked for
+	teardownPollInterval = 2 * time.Second
+
+	// monitoringUninstallJobName is the Job uninstalling the monitoring stack
+	monitoringUninstallJobName = "kibaship-monitoring-uninstaller"
+
+	// helmReleaseNameAnnotation and helmReleaseNamespaceAnnotation mark the objects of a Helm release
+	helmReleaseNameAnnotation      = "meta.helm.sh/release-name"
+	helmReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+)
+
+// platformResourceOrder lists the platform resources in the order they are deleted: dependents
+// first, so their finalizers run while their owners still exist. Deleting a Project deletes its
+// namespace.
+var platformResourceOrder = []schema.GroupVersionKind{
+	{Group: "platform.operator.kibaship.com", Version: "v1alpha1", Kind: "Deployment"},
+	{Group: "platform.operator.kibaship.com", Version: "v1alpha1", Kind: "ApplicationDomain"},
+	{Group: "platform.operator.kibaship.com", Version: "v1alpha1", Kind: "Application"},
+	{Group: "platform.operator.kibaship.com", Version: "v1alpha1", Kind: "Environment"},
+	{Group: "platform.operator.kibaship.com", Version: "v1alpha1", Kind: "Project"},
+}
+
+// bootstrapArtifact is an object created by the bootstrap steps outside of the install manifest
+type bootstrapArtifact struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+// bootstrapArtifacts lists the cluster scoped objects, and the objects in shared namespaces,
+// created by the bootstrap steps. Objects of APIs missing from the cluster are skipped.
+func bootstrapArtifacts() []bootstrapArtifact {
+	priorityClass := schema.GroupVersionKind{Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass"}
+	storageClass := schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"}
+	clusterRole := schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}
+	clusterRoleBinding := schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"}
+
+	return []bootstrapArtifact{
+		{gvk: schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"}, namespace: KibashipNamespace, name: IngressGatewayName},
+		{gvk: schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}, namespace: KibashipNamespace, name: IngressWildcardCertName},
+		{gvk: schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "ClusterIssuer"}, name: issuerName},
+		{gvk: schema.GroupVersionKind{Group: "cilium.io", Version: "v2alpha1", Kind: "CiliumLoadBalancerIPPool"}, name: LoadBalancerIPPoolName},
+		{gvk: schema.GroupVersionKind{Group: "cilium.io", Version: "v2alpha1", Kind: "CiliumL2AnnouncementPolicy"}, name: LoadBalancerL2PolicyName},
+		{gvk: schema.GroupVersionKind{Group: "metallb.io", Version: "v1beta1", Kind: "IPAddressPool"}, namespace: MetalLBNamespace, name: LoadBalancerIPPoolName},
+		{gvk: schema.GroupVersionKind{Group: "metallb.io", Version: "v1beta1", Kind: "L2Advertisement"}, namespace: MetalLBNamespace, name: LoadBalancerL2PolicyName},
+		{gvk: clusterRoleBinding, name: LogCollectorServiceAccountName},
+		{gvk: clusterRole, name: LogCollectorServiceAccountName},
+		{gvk: clusterRoleBinding, name: MonitoringInstallerName},
+		{gvk: priorityClass, name: config.PriorityClassBuild},
+		{gvk: priorityClass, name: config.PriorityClassRuntime},
+		{gvk: priorityClass, name: config.PriorityClassSystem},
+		{gvk: storageClass, name: config.StorageClassReplica1},
+		{gvk: storageClass, name: config.StorageClassReplica2},
+	}
+}
+
+// bootstrapNamespaces are the namespaces created by the bootstrap steps, deleted last
+var bootstrapNamespaces = []string{config.LoggingNamespace, config.MonitoringNamespace, config.ArtifactsNamespace}
+
+// TeardownOptions configures a teardown of the platform
+type TeardownOptions struct {
+	// Timeout bounds the wait for each stage, defaults to five minutes
+	Timeout time.Duration
+	// RemoveFinalizers strips the finalizers of platform resources still present after Timeout,
+	// for when the operator is no longer running to process them
+	RemoveFinalizers bool
+	// Progress, when set, is called with a line describing each stage
+	Progress func(message string)
+}
+
+// Leftover is an object the teardown could not delete
+type Leftover struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Reason    string
+}
+
+// String returns the kind and namespaced name of the object
+func (l Leftover) String() string {
+	if l.Namespace == "" {
+		return fmt.Sprintf("%s %s", l.Kind, l.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", l.Kind, l.Namespace, l.Name)
+}
+
+// Teardown deletes every platform resource and the objects created by the bootstrap steps. The
+// platform resources are deleted in dependency order, each kind waited for before the next so
+// the operator can run their finalizers, then the project namespaces are waited for before the
+// bootstrap artifacts are removed. The monitoring stack is uninstalled with helm before its
+// namespace is deleted. The operator namespace, CRDs and webhooks belong to the install manifest
+// and are left for its deletion.
+//
+// Teardown carries on past failures; the objects it could not delete are returned.
+func Teardown(ctx context.Context, c client.Client, opts TeardownOptions) []Leftover {
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultTeardownTimeout
+	}
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(string) {}
+	}
+
+	var leftovers []Leftover
+	for _, gvk := range platformResourceOrder {
+		progress(fmt.Sprintf("Deleting %s resources", gvk.Kind))
+		leftovers = append(leftovers, deletePlatformResources(ctx, c, gvk, opts)...)
+	}
+
+	progress("Waiting for the project namespaces to be finalized")
+	leftovers = append(leftovers, deleteProjectNamespaces(ctx, c, opts.Timeout)...)
+
+	progress("Uninstalling the monitoring stack")
+	leftovers = append(leftovers, uninstallMonitoring(ctx, c, opts.Timeout, progress)...)
+
+	progress("Deleting the bootstrap artifacts")
+	for _, artifact := range bootstrapArtifacts() {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(artifact.gvk)
+		obj.SetNamespace(artifact.namespace)
+		obj.SetName(artifact.name)
+		if err := c.Delete(ctx, obj); err != nil && !ignorableDeleteError(err) {
+			leftovers = append(leftovers, Leftover{Kind: artifact.gvk.Kind, Namespace: artifact.namespace, Name: artifact.name, Reason: err.Error()})
+		}
+	}
+
+	progress("Deleting the logging, monitoring and artifacts namespaces")
+	leftovers = append(leftovers, deleteNamespaces(ctx, c, bootstrapNamespaces, opts.Timeout)...)
+
+	return leftovers
+}
+
+// deletePlatformResources deletes every resource of a platform kind and waits for them to go
+func deletePlatformResources(ctx context.Context, c client.Client, gvk schema.GroupVersionKind, opts TeardownOptions) []Leftover {
+	items, err := listPlatformResources(ctx, c, gvk)
+	if err != nil {
+		return []Leftover{{Kind: gvk.Kind, Reason: fmt.Sprintf("failed to list: %v", err)}}
+	}
+
+	var leftovers []Leftover
+	failed := map[string]bool{}
+	for i := range items {
+		if err := c.Delete(ctx, &items[i]); err != nil && !apierrors.IsNotFound(err) {
+			failed[client.ObjectKeyFromObject(&items[i]).String()] = true
+			leftovers = append(leftovers, platformLeftover(gvk, &items[i], fmt.Sprintf("failed to delete: %v", err)))
+		}
+	}
+
+	remaining, err := waitForPlatformResources(ctx, c, gvk, opts.Timeout)
+	if err == nil && len(remaining) > 0 && opts.RemoveFinalizers {
+		for i := range remaining {
+			patch := client.MergeFrom(remaining[i].DeepCopy())
+			remaining[i].SetFinalizers(nil)
+			if patchErr := c.Patch(ctx, &remaining[i], patch); patchErr != nil && !apierrors.IsNotFound(patchErr) {
+				err = errors.Join(err, patchErr)
+			}
+		}
+		remaining, err = waitForPlatformResources(ctx, c, gvk, opts.Timeout)
+	}
+	if err != nil {
+		return append(leftovers, Leftover{Kind: gvk.Kind, Reason: fmt.Sprintf("failed to wait for deletion: %v", err)})
+	}
+
+	for i := range remaining {
+		if failed[client.ObjectKeyFromObject(&remaining[i]).String()] {
+			continue
+		}
+		leftovers = append(leftovers, platformLeftover(gvk, &remaining[i],
+			fmt.Sprintf("still present after %s, waiting on finalizers %v", opts.Timeout, remaining[i].GetFinalizers())))
+	}
+	return leftovers
+}
+
+func platformLeftover(gvk schema.GroupVersionKind, obj *unstructured.Unstructured, reason string) Leftover {
+	return Leftover{Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), Reason: reason}
+}
+
+// listPlatformResources lists the resources of a platform kind in every namespace. A kind whose
+// CRD is not installed has no resources.
+func listPlatformResources(ctx context.Context, c client.Client, gvk schema.GroupVersionKind) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := c.List(ctx, list); err != nil {
+		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// waitForPlatformResources waits for every resource of a platform kind to be gone, returning
+// the resources still present once timeout has passed
+func waitForPlatformResources(ctx context.Context, c client.Client, gvk schema.GroupVersionKind, timeout time.Duration) ([]unstructured.Unstructured, error) {
+	var remaining []unstructured.Unstructured
+	err := waitUntil(ctx, timeout, func() (bool, error) {
+		var err error
+		remaining, err = listPlatformResources(ctx, c, gvk)
+		return len(remaining) == 0, err
+	})
+	return remaining, err
+}
+
+// deleteProjectNamespaces deletes the namespaces of projects, normally already removed with
+// their Project, and waits for them to be finalized
+func deleteProjectNamespaces(ctx context.Context, c client.Client, timeout time.Duration) []Leftover {
+	requirement, err := labels.NewRequirement(validation.LabelResourceUUID, selection.Exists, nil)
+	if err != nil {
+		return []Leftover{{Kind: "Namespace", Reason: err.Error()}}
+	}
+	selector := labels.SelectorFromSet(labels.Set{"app.kubernetes.io/managed-by": "kibaship"}).Add(*requirement)
+
+	namespaces := &corev1.NamespaceList{}
+	if err := c.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return []Leftover{{Kind: "Namespace", Reason: fmt.Sprintf("failed to list the project namespaces: %v", err)}}
+	}
+	names := make([]string, 0, len(namespaces.Items))
+	for _, namespace := range namespaces.Items {
+		names = append(names, namespace.Name)
+	}
+	return deleteNamespaces(ctx, c, names, timeout)
+}
+
+// deleteNamespaces deletes the namespaces and waits for them to be finalized
+func deleteNamespaces(ctx context.Context, c client.Client, names []string, timeout time.Duration) []Leftover {
+	var leftovers []Leftover
+	pending := make([]string, 0, len(names))
+	for _, name := range names {
+		namespace := &corev1.Namespace{}
+		namespace.Name = name
+		if err := c.Delete(ctx, namespace); err != nil {
+			if !apierrors.IsNotFound(err) {
+				leftovers = append(leftovers, Leftover{Kind: "Namespace", Name: name, Reason: fmt.Sprintf("failed to delete: %v", err)})
+			}
+			continue
+		}
+		pending = append(pending, name)
+	}
+
+	err := waitUntil(ctx, timeout, func() (bool, error) {
+		remaining := pending[:0]
+		for _, name := range pending {
+			err := c.Get(ctx, client.ObjectKey{Name: name}, &corev1.Namespace{})
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return false, err
+			}
+			remaining = append(remaining, name)
+		}
+		pending = remaining
+		return len(pending) == 0, nil
+	})
+	reason := fmt.Sprintf("still terminating after %s", timeout)
+	if err != nil {
+		reason = fmt.Sprintf("failed to wait for deletion: %v", err)
+	}
+	for _, name := range pending {
+		leftovers = append(leftovers, Leftover{Kind: "Namespace", Name: name, Reason: reason})
+	}
+	return leftovers
+}
+
+// waitUntil polls done until it reports true, returning nil without error once timeout has
+// passed so the caller can report what is left
+func waitUntil(ctx context.Context, timeout time.Duration, done func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, err := done()
+		if err != nil || ok || !time.Now().Before(deadline) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(min(teardownPollInterval, time.Until(deadline))):
+		}
+	}
+}
+
+// uninstallMonitoring uninstalls the monitoring stack Helm release with a Job running helm, as
+// it was installed, so the cluster scoped objects of the chart are removed too. Objects of the
+// release left by a failed uninstall, and the CRDs helm never uninstalls, are deleted directly.
+func uninstallMonitoring(ctx context.Context, c client.Client, timeout time.Duration, progress func(string)) []Leftover {
+	err := c.Get(ctx, client.ObjectKey{Name: config.MonitoringNamespace}, &corev1.Namespace{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return []Leftover{{Kind: "Namespace", Name: config.MonitoringNamespace, Reason: fmt.Sprintf("failed to get: %v", err)}}
+	}
+
+	if err := runMonitoringUninstall(ctx, c, timeout); err != nil {
+		progress(fmt.Sprintf("helm uninstall of %s did not complete (%v), deleting its objects directly", config.MonitoringReleaseName, err))
+	}
+
+	var leftovers []Leftover
+	for _, kind := range []struct {
+		name string
+		list client.ObjectList
+	}{
+		{"ClusterRoleBinding", &rbacv1.ClusterRoleBindingList{}},
+		{"ClusterRole", &rbacv1.ClusterRoleList{}},
+		{"MutatingWebhookConfiguration", &admissionregistrationv1.MutatingWebhookConfigurationList{}},
+		{"ValidatingWebhookConfiguration", &admissionregistrationv1.ValidatingWebhookConfigurationList{}},
+	} {
+		leftovers = append(leftovers, deleteMonitoringReleaseObjects(ctx, c, kind.name, kind.list)...)
+	}
+
+	crdGVK := schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+	for _, name := range monitoringCRDs {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(crdGVK)
+		crd.SetName(name)
+		if err := c.Delete(ctx, crd); err != nil && !ignorableDeleteError(err) {
+			leftovers = append(leftovers, Leftover{Kind: crdGVK.Kind, Name: name, Reason: fmt.Sprintf("failed to delete: %v", err)})
+		}
+	}
+	return leftovers
+}
+
+// monitoringCRDs are the Prometheus operator CRDs installed by the chart, which helm keeps on
+// uninstall
+var monitoringCRDs = []string{
+	"alertmanagerconfigs.monitoring.coreos.com",
+	"alertmanagers.monitoring.coreos.com",
+	"podmonitors.monitoring.coreos.com",
+	"probes.monitoring.coreos.com",
+	"prometheusagents.monitoring.coreos.com",
+	"prometheuses.monitoring.coreos.com",
+	"prometheusrules.monitoring.coreos.com",
+	"scrapeconfigs.monitoring.coreos.com",
+	"servicemonitors.monitoring.coreos.com",
+	"thanosrulers.monitoring.coreos.com",
+}
+
+// runMonitoringUninstall stops the install Jobs, so they cannot reinstall the release, then runs
+// the uninstall Job and waits for it to finish
+func runMonitoringUninstall(ctx context.Context, c client.Client, timeout time.Duration) error {
+	if err := c.DeleteAllOf(ctx, &batchv1.Job{}, client.InNamespace(config.MonitoringNamespace),
+		client.MatchingLabels{"app": MonitoringInstallerName}, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+		return fmt.Errorf("failed to stop the install jobs: %w", err)
+	}
+	// The installer, deleted with the bootstrap artifacts, may be gone after an earlier teardown
+	if err := ensureMonitoringInstaller(ctx, c); err != nil {
+		return fmt.Errorf("failed to ensure the installer: %w", err)
+	}
+
+	job := monitoringUninstallJob()
+	if err := c.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create the uninstall job: %w", err)
+	}
+
+	err := waitUntil(ctx, timeout, func() (bool, error) {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(job), job); err != nil {
+			return false, err
+		}
+		return jobCondition(job, batchv1.JobComplete) || jobCondition(job, batchv1.JobFailed), nil
+	})
+	switch {
+	case err != nil:
+		return err
+	case jobCondition(job, batchv1.JobFailed):
+		return fmt.Errorf("job %s failed, see its logs", job.Name)
+	case !jobCondition(job, batchv1.JobComplete):
+		return fmt.Errorf("job %s still running after %s", job.Name, timeout)
+	}
+	return nil
+}
+
+// jobCondition reports whether the Job has the condition
+func jobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// monitoringUninstallJob returns the Job uninstalling the monitoring stack release
+func monitoringUninstallJob() *batchv1.Job {
+	backoffLimit := int32(3)
+	labels := monitoringLabels(monitoringUninstallJobName)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      monitoringUninstallJobName,
+			Namespace: config.MonitoringNamespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: MonitoringInstallerName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:  "helm",
+						Image: mirrorImage(HelmImage),
+						Args: []string{
+							"uninstall", config.MonitoringReleaseName,
+							"--namespace", config.MonitoringNamespace,
+							"--ignore-not-found", "--wait", "--timeout", "10m",
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// deleteMonitoringReleaseObjects deletes the objects of a cluster scoped kind that helm recorded
+// as part of the monitoring stack release
+func deleteMonitoringReleaseObjects(ctx context.Context, c client.Client, kind string, list client.ObjectList) []Leftover {
+	if err := c.List(ctx, list); err != nil {
+		return []Leftover{{Kind: kind, Reason: fmt.Sprintf("failed to list: %v", err)}}
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return []Leftover{{Kind: kind, Reason: err.Error()}}
+	}
+
+	var leftovers []Leftover
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+		annotations := obj.GetAnnotations()
+		if annotations[helmReleaseNameAnnotation] != config.MonitoringReleaseName ||
+			annotations[helmReleaseNamespaceAnnotation] != config.MonitoringNamespace {
+			continue
+		}
+		if err := c.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			leftovers = append(leftovers, Leftover{Kind: kind, Name: obj.GetName(), Reason: fmt.Sprintf("failed to delete: %v", err)})
+		}
+	}
+	return leftovers
+}
+
+// ignorableDeleteError reports whether a failed delete means the object is already gone: it,
+// or the API of its kind, does not exist
+func ignorableDeleteError(err error) bool {
+	return apierrors.IsNotFound(err) || meta.IsNoMatchError(err)
+}
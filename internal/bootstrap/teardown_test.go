@@ -0,0 +1,150 @@
+package bootstrap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func newTeardownClient(t *testing.T) client.Client {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := platformv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&platformv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{
+			Name:       "project-1",
+			Finalizers: []string{"platform.kibaship.com/project-finalizer"},
+		}},
+		&platformv1alpha1.Application{ObjectMeta: metav1.ObjectMeta{
+			Name:       "application-1",
+			Namespace:  "project-1",
+			Finalizers: []string{"platform.operator.kibaship.com/application-finalizer"},
+		}},
+		&platformv1alpha1.Environment{ObjectMeta: metav1.ObjectMeta{Name: "environment-1", Namespace: "project-1"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-1", Labels: map[string]string{
+			"app.kubernetes.io/managed-by": "kibaship",
+			validation.LabelResourceUUID:   "0b1c6f4e-1f5a-4c52-9d7e-2b2f1a0e4b11",
+		}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "unrelated"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: config.LoggingNamespace}},
+		&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: config.PriorityClassBuild}, Value: 1000},
+	).Build()
+}
+
+func TestTeardownReportsResourcesHeldByFinalizers(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := newTeardownClient(t)
+
+	leftovers := Teardown(ctx, c, TeardownOptions{Timeout: 10 * time.Millisecond})
+
+	var names []string
+	for _, leftover := range leftovers {
+		names = append(names, leftover.String())
+	}
+	g.Expect(names).To(ConsistOf("Application project-1/application-1", "Project project-1"))
+
+	// Everything not held by a finalizer is gone, the unrelated namespace is kept
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Namespace: "project-1", Name: "environment-1"}, &platformv1alpha1.Environment{}))).To(BeTrue())
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "project-1"}, &corev1.Namespace{}))).To(BeTrue())
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: config.LoggingNamespace}, &corev1.Namespace{}))).To(BeTrue())
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: config.PriorityClassBuild}, &schedulingv1.PriorityClass{}))).To(BeTrue())
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: "unrelated"}, &corev1.Namespace{})).To(Succeed())
+}
+
+func TestTeardownRemovesFinalizers(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := newTeardownClient(t)
+
+	var stages []string
+	leftovers := Teardown(ctx, c, TeardownOptions{
+		Timeout:          10 * time.Millisecond,
+		RemoveFinalizers: true,
+		Progress:         func(message string) { stages = append(stages, message) },
+	})
+
+	g.Expect(leftovers).To(BeEmpty())
+	g.Expect(stages[0]).To(Equal("Deleting Deployment resources"))
+	g.Expect(stages[4]).To(Equal("Deleting Project resources"))
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "project-1"}, &platformv1alpha1.Project{}))).To(BeTrue())
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Namespace: "project-1", Name: "application-1"}, &platformv1alpha1.Application{}))).To(BeTrue())
+}
+
+func TestTeardownUninstallsMonitoring(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := newTeardownClient(t)
+	release := map[string]string{
+		helmReleaseNameAnnotation:      config.MonitoringReleaseName,
+		helmReleaseNamespaceAnnotation: config.MonitoringNamespace,
+	}
+	for _, obj := range []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: config.MonitoringNamespace}},
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: MonitoringInstallerName + "-abc", Namespace: config.MonitoringNamespace,
+			Labels: monitoringLabels(MonitoringInstallerName)}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "kube-prometheus-stack-operator", Annotations: release}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "unrelated"}},
+	} {
+		g.Expect(c.Create(ctx, obj)).To(Succeed())
+	}
+
+	var stages []string
+	leftovers := Teardown(ctx, c, TeardownOptions{
+		Timeout:          10 * time.Millisecond,
+		RemoveFinalizers: true,
+		Progress:         func(message string) { stages = append(stages, message) },
+	})
+	g.Expect(leftovers).To(BeEmpty())
+
+	// helm uninstall runs as a Job, the install Jobs are stopped so they cannot reinstall the release
+	job := &batchv1.Job{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: config.MonitoringNamespace, Name: monitoringUninstallJobName}, job)).To(Succeed())
+	g.Expect(job.Spec.Template.Spec.ServiceAccountName).To(Equal(MonitoringInstallerName))
+	g.Expect(job.Spec.Template.Spec.Containers[0].Args).To(HaveExactElements(
+		"uninstall", config.MonitoringReleaseName, "--namespace", config.MonitoringNamespace,
+		"--ignore-not-found", "--wait", "--timeout", "10m"))
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Namespace: config.MonitoringNamespace, Name: MonitoringInstallerName + "-abc"}, &batchv1.Job{}))).To(BeTrue())
+
+	// The Job never finished, so the cluster scoped objects of the release were deleted directly
+	g.Expect(stages).To(ContainElement(ContainSubstring("helm uninstall of kube-prometheus-stack did not complete")))
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "kube-prometheus-stack-operator"}, &rbacv1.ClusterRole{}))).To(BeTrue())
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: "unrelated"}, &rbacv1.ClusterRole{})).To(Succeed())
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: config.MonitoringNamespace}, &corev1.Namespace{}))).To(BeTrue())
+}
+
+func TestRunMonitoringUninstall(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := newTeardownClient(t)
+
+	job := monitoringUninstallJob()
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	g.Expect(c.Create(ctx, job)).To(Succeed())
+	g.Expect(runMonitoringUninstall(ctx, c, 10*time.Millisecond)).To(MatchError(ContainSubstring("failed, see its logs")))
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	g.Expect(c.Status().Update(ctx, job)).To(Succeed())
+	g.Expect(runMonitoringUninstall(ctx, c, 10*time.Millisecond)).To(Succeed())
+}
@@ -26,6 +26,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -52,6 +53,8 @@ type ApplicationReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Notifier webhooks.Notifier
+	// Recorder emits the Events of applications, nil emits none
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications,verbs=get;list;watch;create;update;patch;delete
@@ -61,6 +64,7 @@ type ApplicationReconciler struct {
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applicationdomains,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=environments,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=external-secrets.io,resources=externalsecrets,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -88,6 +92,7 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	labelsUpdated, err := r.ensureUUIDLabels(ctx, &app)
 	if err != nil {
 		log.Error(err, "Failed to ensure UUID labels")
+		recordEvent(r.Recorder, &app, corev1.EventTypeWarning, eventReasonValidationFailed, "%v", err)
 		return ctrl.Result{}, err
 	}
 
@@ -121,6 +126,7 @@ func (r *ApplicationReconciler) handleDeletion(ctx context.Context, app *platfor
 	}
 
 	log.Info("Handling Application deletion")
+	recordEvent(r.Recorder, app, corev1.EventTypeNormal, eventReasonDeleting, "Deleting the deployments and domains of the application")
 
 	// Delete all Deployments associated with this Application
 	if err := r.deleteAssociatedDeployments(ctx, app); err != nil {
@@ -181,6 +187,7 @@ func (r *ApplicationReconciler) handleApplicationReconcile(ctx context.Context,
 	secretRefUpdated, err := r.ensureApplicationEnvSecret(ctx, app)
 	if err != nil {
 		log.Error(err, "Failed to ensure application env secret")
+		recordEvent(r.Recorder, app, corev1.EventTypeWarning, eventReasonReconcileFailed, "Failed to ensure env secret: %v", err)
 		return ctrl.Result{}, err
 	}
 	if secretRefUpdated {
@@ -193,9 +200,17 @@ func (r *ApplicationReconciler) handleApplicationReconcile(ctx context.Context,
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// Delegate external env vars to the External Secrets Operator
+	if err := r.ensureExternalEnvSecret(ctx, app); err != nil {
+		log.Error(err, "Failed to ensure external env secret")
+		recordEvent(r.Recorder, app, corev1.EventTypeWarning, eventReasonReconcileFailed, "Failed to ensure external env secret: %v", err)
+		return ctrl.Result{}, err
+	}
+
 	// Handle ApplicationDomain creation for GitRepository applications
 	if err := r.handleApplicationDomains(ctx, app); err != nil {
 		log.Error(err, "Failed to handle ApplicationDomains")
+		recordEvent(r.Recorder, app, corev1.EventTypeWarning, eventReasonReconcileFailed, "Failed to ensure default domain: %v", err)
 		return ctrl.Result{}, err
 	}
 
@@ -210,6 +225,9 @@ func (r *ApplicationReconciler) handleApplicationReconcile(ctx context.Context,
 
 	// Emit webhook on phase transition
 	r.emitApplicationPhaseChange(ctx, app, prevPhase, app.Status.Phase)
+	if prevPhase != app.Status.Phase && app.Status.Phase == applicationPhaseReady {
+		recordEvent(r.Recorder, app, corev1.EventTypeNormal, eventReasonReady, "Application is ready")
+	}
 
 	log.Info("Successfully reconciled Application")
 	return ctrl.Result{}, nil
@@ -486,10 +504,10 @@ func (r *ApplicationReconciler) handleApplicationDomains(ctx context.Context, ap
 func (r *ApplicationReconciler) createDefaultDomain(ctx context.Context, app *platformv1alpha1.Application) error {
 	log := logf.FromContext(ctx).WithValues("application", app.Name, "namespace", app.Namespace)
 
-	// Get operator configuration
-	config, err := GetOperatorConfig()
+	// Resolve the base domain from the application, its project or the operator configuration
+	baseDomain, err := ResolveBaseDomain(ctx, r.Client, app)
 	if err != nil {
-		return fmt.Errorf("failed to get operator configuration: %v", err)
+		return fmt.Errorf("failed to resolve base domain: %v", err)
 	}
 
 	// Use the application UUID from labels for subdomain generation (not the CR name)
@@ -506,7 +524,7 @@ func (r *ApplicationReconciler) createDefaultDomain(ctx context.Context, app *pl
 	}
 
 	// Generate full domain based on application type
-	fullDomain, port, err := GenerateFullDomainForApplicationType(subdomain, app.Spec.Type)
+	fullDomain, port, err := GenerateFullDomainForBaseDomain(subdomain, baseDomain, app.Spec.Type)
 	if err != nil {
 		return fmt.Errorf("failed to generate full domain: %v", err)
 	}
@@ -566,7 +584,8 @@ func (r *ApplicationReconciler) createDefaultDomain(ctx context.Context, app *pl
 		return fmt.Errorf("failed to create ApplicationDomain: %v", err)
 	}
 
-	log.Info("Successfully created default ApplicationDomain", "domain", fullDomain, "port", config.DefaultPort)
+	log.Info("Successfully created default ApplicationDomain", "domain", fullDomain, "port", port)
+	recordEvent(r.Recorder, app, corev1.EventTypeNormal, eventReasonDomainCreated, "Created default domain %s", fullDomain)
 	return nil
 }
 
@@ -622,6 +641,8 @@ func (r *ApplicationReconciler) emitApplicationPhaseChange(ctx context.Context,
 func (r *ApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&platformv1alpha1.Application{}).
+		WithOptions(controllerOptions("application", 1)).
 		Named("application").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
 		Complete(r)
 }
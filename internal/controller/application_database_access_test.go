@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestValidateDatabaseAccess(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-550e8400-e29b-41d4-a716-446655440031",
+			Namespace: "default",
+			Labels: map[string]string{
+				validation.LabelResourceUUID:    "550e8400-e29b-41d4-a716-446655440031",
+				validation.LabelResourceSlug:    "orders",
+				validation.LabelEnvironmentUUID: "550e8400-e29b-41d4-a716-446655440010",
+				validation.LabelProjectUUID:     "550e8400-e29b-41d4-a716-446655440000",
+			},
+		},
+		Spec: platformv1alpha1.ApplicationSpec{
+			EnvironmentRef: corev1.LocalObjectReference{Name: "environment-production"},
+			Type:           platformv1alpha1.ApplicationTypeMySQL,
+			MySQL:          &platformv1alpha1.MySQLConfig{Database: "orders"},
+			DatabaseAccess: &platformv1alpha1.DatabaseAccessConfig{
+				Databases: []platformv1alpha1.DatabaseSchema{{Name: "reports"}},
+				Users: []platformv1alpha1.DatabaseUser{
+					{Username: "analyst", Databases: []string{"orders", "reports"}, ReadOnly: true},
+				},
+			},
+		},
+	}
+
+	// Users may be granted declared databases and the database of the application
+	_, err := app.ValidateCreate(ctx, app)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	app.Spec.DatabaseAccess.Users[0].Databases = []string{"billing"}
+	_, err = app.ValidateCreate(ctx, app)
+	g.Expect(err).To(MatchError(ContainSubstring("database user analyst is granted database billing which is not declared")))
+
+	// The public schema is the database of Postgres applications
+	app.Spec.Type = platformv1alpha1.ApplicationTypePostgres
+	app.Spec.MySQL = nil
+	app.Spec.DatabaseAccess.Users[0].Databases = []string{"public", "reports"}
+	_, err = app.ValidateCreate(ctx, app)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	app.Spec.DatabaseAccess.Users[0].Databases = []string{"orders"}
+	_, err = app.ValidateCreate(ctx, app)
+	g.Expect(err).To(MatchError(ContainSubstring("granted database orders")))
+
+	// Other applications have no databases to grant
+	app.Spec.Type = platformv1alpha1.ApplicationTypeValkey
+	_, err = app.ValidateCreate(ctx, app)
+	g.Expect(err).To(MatchError(ContainSubstring("databaseAccess is not supported for Valkey applications")))
+}
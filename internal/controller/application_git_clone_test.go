@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestValidateGitCloneOptions(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-550e8400-e29b-41d4-a716-446655440030",
+			Namespace: "default",
+			Labels: map[string]string{
+				validation.LabelResourceUUID:    "550e8400-e29b-41d4-a716-446655440030",
+				validation.LabelResourceSlug:    "assets01",
+				validation.LabelEnvironmentUUID: "550e8400-e29b-41d4-a716-446655440010",
+				validation.LabelProjectUUID:     "550e8400-e29b-41d4-a716-446655440000",
+			},
+		},
+		Spec: platformv1alpha1.ApplicationSpec{
+			EnvironmentRef: corev1.LocalObjectReference{Name: "environment-production"},
+			Type:           platformv1alpha1.ApplicationTypeGitRepository,
+			GitRepository: &platformv1alpha1.GitRepositoryConfig{
+				Provider:     platformv1alpha1.GitProviderGitHub,
+				Repository:   "myorg/assets",
+				PublicAccess: true,
+				GitLFS:       true,
+			},
+		},
+	}
+
+	warnings, err := app.ValidateCreate(ctx, app)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(BeEmpty())
+
+	// Private submodules can only be reached with the token of the repository
+	app.Spec.GitRepository.PublicAccess = false
+	app.Spec.GitRepository.SecretRef = &corev1.LocalObjectReference{Name: "git-token"}
+	app.Spec.GitRepository.GitSubmodules = true
+	warnings, err = app.ValidateUpdate(ctx, app.DeepCopy(), app)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(ConsistOf(ContainSubstring("private submodules are cloned with the access token of github.com")))
+
+	// The sparse checkout must contain the root directory the build runs in
+	app.Spec.GitRepository.CloneDepth = 1
+	app.Spec.GitRepository.RootDirectory = "services/api"
+	app.Spec.GitRepository.SparseCheckoutPaths = []string{"services/web"}
+	_, err = app.ValidateCreate(ctx, app)
+	g.Expect(err).To(MatchError(ContainSubstring("services/api is used by the build but not checked out")))
+
+	app.Spec.GitRepository.SparseCheckoutPaths = []string{"services/api", "packages/shared"}
+	_, err = app.ValidateCreate(ctx, app)
+	g.Expect(err).NotTo(HaveOccurred())
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+	"github.com/kibamail/kibaship/pkg/webhooks"
+)
+
+const (
+	// ApplicationConditionDegraded reports whether the pods of the promoted deployment of an
+	// application fail to run
+	ApplicationConditionDegraded = "Degraded"
+
+	// ImagePullBackOffReason is the reason of containers whose image cannot be pulled
+	ImagePullBackOffReason = "ImagePullBackOff"
+
+	// errImagePullReason is the waiting reason of the first failed image pull, before the back-off
+	errImagePullReason = "ErrImagePull"
+
+	// maxTerminationMessageLength bounds the container termination message copied into conditions
+	maxTerminationMessageLength = 512
+)
+
+// ApplicationHealthReconciler watches the pods of the promoted deployment of applications and
+// mirrors crash loops and image pull failures into a Degraded condition on the Application,
+// sending webhooks when an application degrades and when it recovers
+type ApplicationHealthReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Notifier webhooks.Notifier
+
+	// Clock stamps condition transitions and webhooks, the real clock when nil
+	Clock clock.PassiveClock
+}
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=deployments,verbs=get;list;watch
+
+// Reconcile inspects the pods of the promoted deployment of an application and updates its
+// Degraded condition
+func (r *ApplicationHealthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var app platformv1alpha1.Application
+	if err := r.Get(ctx, req.NamespacedName, &app); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !app.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	reason, message, degraded, err := r.promotedPodFailure(ctx, &app)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	current := meta.FindStatusCondition(app.Status.Conditions, ApplicationConditionDegraded)
+	wasDegraded := current != nil && current.Status == metav1.ConditionTrue
+	condition := metav1.Condition{
+		Type:    ApplicationConditionDegraded,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	}
+	if !degraded {
+		if !wasDegraded {
+			return ctrl.Result{}, nil
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "PodsRecovered"
+		condition.Message = "The pods of the promoted deployment are running"
+	}
+	if current != nil && current.Status == condition.Status && current.Reason == condition.Reason && current.Message == condition.Message {
+		return ctrl.Result{}, nil
+	}
+
+	now := currentTime(r.Clock)
+	patch := client.MergeFrom(app.DeepCopy())
+	condition.LastTransitionTime = metav1.NewTime(now)
+	meta.SetStatusCondition(&app.Status.Conditions, condition)
+	if err := r.Status().Patch(ctx, &app, patch); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to update application health: %w", err)
+	}
+
+	if degraded != wasDegraded {
+		log.Info("Application health changed", "degraded", degraded, "reason", condition.Reason, "message", condition.Message)
+		r.emitHealthChange(ctx, &app, condition)
+	}
+	return ctrl.Result{}, nil
+}
+
+// promotedPodFailure returns the failure of the first failing pod of the promoted deployment of app
+func (r *ApplicationHealthReconciler) promotedPodFailure(ctx context.Context, app *platformv1alpha1.Application) (reason, message string, failing bool, err error) {
+	if app.Spec.CurrentDeploymentRef == nil {
+		return "", "", false, nil
+	}
+
+	var deployment platformv1alpha1.Deployment
+	if err := r.Get(ctx, types.NamespacedName{Name: app.Spec.CurrentDeploymentRef.Name, Namespace: app.Namespace}, &deployment); err != nil {
+		return "", "", false, client.IgnoreNotFound(err)
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(app.Namespace), client.MatchingLabels{
+		validation.LabelDeploymentUUID: deployment.GetUUID(),
+		"app.kubernetes.io/component":  "application",
+	}); err != nil {
+		return "", "", false, fmt.Errorf("failed to list pods of deployment %s: %w", deployment.Name, err)
+	}
+
+	// Report pods in name order so the message does not flip between pods failing alike
+	slices.SortFunc(pods.Items, func(a, b corev1.Pod) int { return strings.Compare(a.Name, b.Name) })
+	for i := range pods.Items {
+		if reason, message, failing := podFailure(&pods.Items[i]); failing {
+			return reason, message, true, nil
+		}
+	}
+	return "", "", false, nil
+}
+
+// podFailure reports whether a container of pod crash loops or cannot pull its image, with the
+// reason and the container error message
+func podFailure(pod *corev1.Pod) (reason, message string, failing bool) {
+	statuses := slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses)
+	for _, status := range statuses {
+		waiting := status.State.Waiting
+		if waiting == nil {
+			continue
+		}
+		switch waiting.Reason {
+		case CrashLoopBackOffReason:
+			message := fmt.Sprintf("Container %s of pod %s is crash looping", status.Name, pod.Name)
+			if terminated := status.LastTerminationState.Terminated; terminated != nil {
+				message += fmt.Sprintf(", last exit code %d (%s)", terminated.ExitCode, terminated.Reason)
+				if terminated.Message != "" {
+					message += ": " + truncateMessage(strings.TrimSpace(terminated.Message), maxTerminationMessageLength)
+				}
+			}
+			return CrashLoopBackOffReason, message, true
+		case ImagePullBackOffReason, errImagePullReason:
+			return ImagePullBackOffReason, fmt.Sprintf("Container %s of pod %s cannot pull image %s: %s",
+				status.Name, pod.Name, status.Image, waiting.Message), true
+		}
+	}
+	return "", "", false
+}
+
+// truncateMessage shortens message to at most limit bytes
+func truncateMessage(message string, limit int) string {
+	if len(message) <= limit {
+		return message
+	}
+	return strings.ToValidUTF8(message[:limit], "") + "..."
+}
+
+// emitHealthChange sends a webhook when an application degrades or recovers
+func (r *ApplicationHealthReconciler) emitHealthChange(ctx context.Context, app *platformv1alpha1.Application, condition metav1.Condition) {
+	if r.Notifier == nil {
+		return
+	}
+
+	evt := webhooks.ApplicationHealthEvent{
+		Type:        "application.degraded",
+		Reason:      condition.Reason,
+		Message:     condition.Message,
+		Application: *app,
+		Timestamp:   currentTime(r.Clock).UTC(),
+	}
+	if condition.Status == metav1.ConditionFalse {
+		evt.Type = "application.recovered"
+	}
+	_ = r.Notifier.NotifyApplicationHealthChange(ctx, evt)
+}
+
+// podToApplication maps an application pod to its Application
+func podToApplication(_ context.Context, obj client.Object) []reconcile.Request {
+	appUUID := obj.GetLabels()[validation.LabelApplicationUUID]
+	if appUUID == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Name:      utils.GetApplicationResourceName(appUUID),
+		Namespace: obj.GetNamespace(),
+	}}}
+}
+
+// podFailureChanged filters pod events down to those that may change the health of an application:
+// a pod starting or stopping to fail, and failing pods going away
+func podFailureChanged() predicate.Predicate {
+	failureOf := func(obj client.Object) string {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			return ""
+		}
+		reason, _, _ := podFailure(pod)
+		return reason
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return failureOf(e.Object) != ""
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return failureOf(e.ObjectOld) != failureOf(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return true
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ApplicationHealthReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	applicationPods := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		labels := obj.GetLabels()
+		return labels["app.kubernetes.io/component"] == "application" && labels[validation.LabelApplicationUUID] != ""
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&platformv1alpha1.Application{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(podToApplication),
+			builder.WithPredicates(applicationPods, podFailureChanged())).
+		WithOptions(controllerOptions("application-health", 1)).
+		Named("application-health").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
+		Complete(r)
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestPodFailure(t *testing.T) {
+	g := NewWithT(t)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1"},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "app",
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		}}},
+	}
+	_, _, failing := podFailure(pod)
+	g.Expect(failing).To(BeFalse())
+
+	pod.Status.ContainerStatuses[0] = corev1.ContainerStatus{
+		Name:  "app",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: CrashLoopBackOffReason}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: 1,
+			Reason:   "Error",
+			Message:  "panic: missing DATABASE_URL\n",
+		}},
+	}
+	reason, message, failing := podFailure(pod)
+	g.Expect(failing).To(BeTrue())
+	g.Expect(reason).To(Equal(CrashLoopBackOffReason))
+	g.Expect(message).To(Equal("Container app of pod web-1 is crash looping, last exit code 1 (Error): panic: missing DATABASE_URL"))
+
+	pod.Status.ContainerStatuses[0] = corev1.ContainerStatus{
+		Name:  "app",
+		Image: "registry.example.com/web:v2",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: errImagePullReason, Message: "manifest unknown"}},
+	}
+	reason, message, failing = podFailure(pod)
+	g.Expect(failing).To(BeTrue())
+	g.Expect(reason).To(Equal(ImagePullBackOffReason))
+	g.Expect(message).To(Equal("Container app of pod web-1 cannot pull image registry.example.com/web:v2: manifest unknown"))
+}
+
+func TestApplicationHealthReconciler(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-app-1",
+			Namespace: "default",
+			Labels:    map[string]string{validation.LabelResourceUUID: "app-1"},
+		},
+		Spec: platformv1alpha1.ApplicationSpec{
+			CurrentDeploymentRef: &corev1.LocalObjectReference{Name: "deployment-dep-1"},
+		},
+	}
+	deployment := &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      "deployment-dep-1",
+		Namespace: "default",
+		Labels:    map[string]string{validation.LabelResourceUUID: "dep-1"},
+	}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-1",
+			Namespace: "default",
+			Labels: map[string]string{
+				validation.LabelDeploymentUUID:  "dep-1",
+				validation.LabelApplicationUUID: "app-1",
+				"app.kubernetes.io/component":   "application",
+			},
+		},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "app",
+			Image: "registry.example.com/web:v2",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: ImagePullBackOffReason, Message: "manifest unknown"}},
+		}}},
+	}
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(app, deployment, pod).
+		WithStatusSubresource(&platformv1alpha1.Application{}, &corev1.Pod{}).
+		Build()
+	clk := clocktesting.NewFakePassiveClock(time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC))
+	notifier := &recordingNotifier{}
+	r := &ApplicationHealthReconciler{Client: cl, Scheme: scheme, Notifier: notifier, Clock: clk}
+
+	reconcileApp := func() *metav1.Condition {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)})
+		g.Expect(err).NotTo(HaveOccurred())
+		updated := &platformv1alpha1.Application{}
+		g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(app), updated)).To(Succeed())
+		return meta.FindStatusCondition(updated.Status.Conditions, ApplicationConditionDegraded)
+	}
+
+	// The promoted image cannot be pulled
+	condition := reconcileApp()
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(condition.Reason).To(Equal(ImagePullBackOffReason))
+	g.Expect(condition.LastTransitionTime.Time).To(BeTemporally("==", clk.Now()))
+
+	events := notifier.HealthEvents()
+	g.Expect(events).To(HaveLen(1))
+	g.Expect(events[0].Type).To(Equal("application.degraded"))
+	g.Expect(events[0].Message).To(ContainSubstring("manifest unknown"))
+
+	// Reconciling again without a change neither updates nor notifies
+	reconcileApp()
+	g.Expect(notifier.HealthEvents()).To(HaveLen(1))
+
+	// The pod recovers
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	g.Expect(cl.Status().Update(ctx, pod)).To(Succeed())
+	clk.SetTime(clk.Now().Add(time.Minute))
+
+	condition = reconcileApp()
+	g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(condition.Reason).To(Equal("PodsRecovered"))
+
+	events = notifier.HealthEvents()
+	g.Expect(events).To(HaveLen(2))
+	g.Expect(events[1].Type).To(Equal("application.recovered"))
+	g.Expect(events[1].Timestamp).To(BeTemporally("==", clk.Now()))
+}
+
+func TestApplicationHealthReconciler_HealthyApplicationWithoutCondition(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	app := &platformv1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "application-app-2", Namespace: "default"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).WithStatusSubresource(app).Build()
+	r := &ApplicationHealthReconciler{Client: cl, Scheme: scheme}
+
+	_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	updated := &platformv1alpha1.Application{}
+	g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(app), updated)).To(Succeed())
+	g.Expect(updated.Status.Conditions).To(BeEmpty())
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// certificateExpiryRequeueInterval is the longest wait between two checks of an uploaded certificate
+const certificateExpiryRequeueInterval = 24 * time.Hour
+
+var (
+	gatewayGVK        = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"}
+	referenceGrantGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "ReferenceGrant"}
+)
+
+// certificateListenerName returns the Gateway HTTPS listener name serving the uploaded certificate of a domain
+func certificateListenerName(domain string) string {
+	return fmt.Sprintf("tls-%s", strings.ReplaceAll(domain, ".", "-"))
+}
+
+// certificateReferenceGrantName returns the ReferenceGrant letting the Gateway read the certificate Secret of a domain
+func certificateReferenceGrantName(appDomain *platformv1alpha1.ApplicationDomain) string {
+	return fmt.Sprintf("%s-certificate", appDomain.Name)
+}
+
+// reconcileUploadedCertificate validates the certificate a custom domain brings in its
+// CertificateSecretRef and serves it instead of an ACME certificate. The ACME Certificate of the
+// domain is removed, and with the Gateway API a listener for the domain references the Secret.
+func (r *ApplicationDomainReconciler) reconcileUploadedCertificate(ctx context.Context, appDomain *platformv1alpha1.ApplicationDomain) (*x509.Certificate, error) {
+	var secret corev1.Secret
+	key := client.ObjectKey{Namespace: appDomain.Namespace, Name: appDomain.Spec.CertificateSecretRef.Name}
+	if err := r.Get(ctx, key, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get certificate Secret %s: %w", key.Name, err)
+	}
+
+	leaf, err := platformv1alpha1.ValidateDomainCertificate(secret.Data[corev1.TLSCertKey],
+		secret.Data[corev1.TLSPrivateKeyKey], appDomain.Spec.Domain, currentTime(r.Clock))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.deleteDomainACMECertificate(ctx, appDomain); err != nil {
+		return nil, err
+	}
+
+	if opConfig, cfgErr := GetOperatorConfig(); cfgErr == nil && opConfig.IngressProvider.UsesGatewayAPI() {
+		if err := r.ensureCertificateReferenceGrant(ctx, appDomain); err != nil {
+			return nil, err
+		}
+		if err := ensureCertificateGatewayListener(ctx, r.Client, appDomain); err != nil {
+			return nil, err
+		}
+	}
+
+	return leaf, nil
+}
+
+// removeUploadedCertificate stops serving the uploaded certificate of a domain, once it went back to
+// an ACME certificate or is being deleted
+func (r *ApplicationDomainReconciler) removeUploadedCertificate(ctx context.Context, appDomain *platformv1alpha1.ApplicationDomain) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(referenceGrantGVK)
+	obj.SetNamespace(appDomain.Namespace)
+	obj.SetName(certificateReferenceGrantName(appDomain))
+	if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to delete certificate ReferenceGrant: %w", err)
+	}
+
+	if err := deleteCertificateGatewayListener(ctx, r.Client, appDomain.Spec.Domain); err != nil {
+		return err
+	}
+	appDomain.Status.CertificateNotAfter = nil
+	return nil
+}
+
+// deleteDomainACMECertificate removes the cert-manager Certificate issued for a domain before it
+// brought its own certificate
+func (r *ApplicationDomainReconciler) deleteDomainACMECertificate(ctx context.Context, appDomain *platformv1alpha1.ApplicationDomain) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"})
+	obj.SetNamespace(certificatesNamespace)
+	obj.SetName(fmt.Sprintf("ad-%s", appDomain.Name))
+
+	if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to delete ACME Certificate: %w", err)
+	}
+	return nil
+}
+
+// ensureCertificateReferenceGrant allows the kibaship Gateway to read the certificate Secret of a
+// domain from the namespace of the domain
+func (r *ApplicationDomainReconciler) ensureCertificateReferenceGrant(ctx context.Context, appDomain *platformv1alpha1.ApplicationDomain) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(referenceGrantGVK)
+	obj.SetNamespace(appDomain.Namespace)
+	obj.SetName(certificateReferenceGrantName(appDomain))
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		obj.SetLabels(map[string]string{
+			"app.kubernetes.io/managed-by": "kibaship",
+			"platform.kibaship.com/type":   "referencegrant",
+		})
+		obj.Object["spec"] = map[string]any{
+			"from": []any{
+				map[string]any{
+					"group":     "gateway.networking.k8s.io",
+					"kind":      "Gateway",
+					"namespace": ingressGatewayNamespace,
+				},
+			},
+			"to": []any{
+				map[string]any{
+					"group": "",
+					"kind":  "Secret",
+					"name":  appDomain.Spec.CertificateSecretRef.Name,
+				},
+			},
+		}
+		return ctrl.SetControllerReference(appDomain, obj, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ensure certificate ReferenceGrant: %w", err)
+	}
+	return nil
+}
+
+// ensureCertificateGatewayListener adds or updates the HTTPS listener of the kibaship Gateway
+// terminating TLS for a domain with its uploaded certificate
+func ensureCertificateGatewayListener(ctx context.Context, c client.Client, appDomain *platformv1alpha1.ApplicationDomain) error {
+	listenerName := certificateListenerName(appDomain.Spec.Domain)
+	desired := map[string]any{
+		"name":     listenerName,
+		"protocol": "HTTPS",
+		"port":     int64(443),
+		"hostname": appDomain.Spec.Domain,
+		"tls": map[string]any{
+			"mode": "Terminate",
+			"certificateRefs": []any{
+				map[string]any{
+					"kind":      "Secret",
+					"name":      appDomain.Spec.CertificateSecretRef.Name,
+					"namespace": appDomain.Namespace,
+				},
+			},
+		},
+		"allowedRoutes": map[string]any{
+			"namespaces": map[string]any{"from": "All"},
+		},
+	}
+
+	return updateGatewayListeners(ctx, c, func(listeners []any) ([]any, bool) {
+		for i, listener := range listeners {
+			if m, ok := listener.(map[string]any); ok && m["name"] == listenerName {
+				if reflect.DeepEqual(m, desired) {
+					return listeners, false
+				}
+				listeners[i] = desired
+				return listeners, true
+			}
+		}
+		return append(listeners, desired), true
+	})
+}
+
+// deleteCertificateGatewayListener removes the listener serving the uploaded certificate of domain
+// from the kibaship Gateway
+func deleteCertificateGatewayListener(ctx context.Context, c client.Client, domain string) error {
+	listenerName := certificateListenerName(domain)
+	err := updateGatewayListeners(ctx, c, func(listeners []any) ([]any, bool) {
+		kept := make([]any, 0, len(listeners))
+		for _, listener := range listeners {
+			if m, ok := listener.(map[string]any); ok && m["name"] == listenerName {
+				continue
+			}
+			kept = append(kept, listener)
+		}
+		return kept, len(kept) != len(listeners)
+	})
+	// Without a Gateway there is no listener to remove
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil
+	}
+	return err
+}
+
+// updateGatewayListeners applies mutate to the listeners of the kibaship Gateway, and updates the
+// Gateway when mutate reports a change
+func updateGatewayListeners(ctx context.Context, c client.Client, mutate func(listeners []any) ([]any, bool)) error {
+	gateway := &unstructured.Unstructured{}
+	gateway.SetGroupVersionKind(gatewayGVK)
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ingressGatewayNamespace, Name: ingressGatewayName}, gateway); err != nil {
+		return fmt.Errorf("failed to get Gateway: %w", err)
+	}
+
+	listeners, _, err := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+	if err != nil {
+		return fmt.Errorf("failed to read Gateway listeners: %w", err)
+	}
+	listeners, changed := mutate(listeners)
+	if !changed {
+		return nil
+	}
+
+	if err := unstructured.SetNestedSlice(gateway.Object, listeners, "spec", "listeners"); err != nil {
+		return fmt.Errorf("failed to set Gateway listeners: %w", err)
+	}
+	if err := c.Update(ctx, gateway); err != nil {
+		return fmt.Errorf("failed to update Gateway: %w", err)
+	}
+	return nil
+}
+
+// certificateExpiryRequeue returns when to check the uploaded certificate of a domain again: when
+// it expires, and at least daily so a replaced Secret is picked up
+func certificateExpiryRequeue(notAfter, now time.Time) time.Duration {
+	if until := notAfter.Sub(now); until < certificateExpiryRequeueInterval {
+		return until
+	}
+	return certificateExpiryRequeueInterval
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// selfSignedCertificate returns a PEM certificate for domain valid between notBefore and notAfter and its key
+func selfSignedCertificate(t *testing.T, domain string, notBefore, notAfter time.Time) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestValidateDomainCertificate(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	certPEM, keyPEM := selfSignedCertificate(t, "shop.example.com", now.Add(-time.Hour), now.Add(90*24*time.Hour))
+	leaf, err := platformv1alpha1.ValidateDomainCertificate(certPEM, keyPEM, "shop.example.com", now)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(leaf.NotAfter).To(BeTemporally("==", now.Add(90*24*time.Hour)))
+
+	_, err = platformv1alpha1.ValidateDomainCertificate(certPEM, keyPEM, "other.example.com", now)
+	g.Expect(err).To(MatchError(ContainSubstring("not valid for other.example.com")))
+
+	_, err = platformv1alpha1.ValidateDomainCertificate(certPEM, keyPEM, "shop.example.com", now.Add(91*24*time.Hour))
+	g.Expect(err).To(MatchError(ContainSubstring("certificate expired on")))
+
+	_, otherKey := selfSignedCertificate(t, "shop.example.com", now.Add(-time.Hour), now.Add(time.Hour))
+	_, err = platformv1alpha1.ValidateDomainCertificate(certPEM, otherKey, "shop.example.com", now)
+	g.Expect(err).To(MatchError(ContainSubstring("not a valid pair")))
+
+	g.Expect(certificateExpiryRequeue(now.Add(time.Hour), now)).To(Equal(time.Hour))
+	g.Expect(certificateExpiryRequeue(now.Add(90*24*time.Hour), now)).To(Equal(certificateExpiryRequeueInterval))
+}
+
+func TestCertificateGatewayListener(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	gateway := &unstructured.Unstructured{}
+	gateway.SetGroupVersionKind(gatewayGVK)
+	gateway.SetNamespace(ingressGatewayNamespace)
+	gateway.SetName(ingressGatewayName)
+	gateway.Object["spec"] = map[string]any{
+		"listeners": []any{map[string]any{"name": "https", "protocol": "HTTPS", "port": int64(443)}},
+	}
+	cl := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(gateway).Build()
+
+	appDomain := &platformv1alpha1.ApplicationDomain{
+		ObjectMeta: metav1.ObjectMeta{Name: "domain-1", Namespace: "project-ns"},
+		Spec: platformv1alpha1.ApplicationDomainSpec{
+			Domain:               "shop.example.com",
+			Type:                 platformv1alpha1.ApplicationDomainTypeCustom,
+			CertificateSecretRef: &corev1.LocalObjectReference{Name: "domain-certificate-domain-1"},
+		},
+	}
+	listeners := func() []any {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(gatewayGVK)
+		g.Expect(cl.Get(ctx, client.ObjectKey{Namespace: ingressGatewayNamespace, Name: ingressGatewayName}, current)).To(Succeed())
+		listeners, _, _ := unstructured.NestedSlice(current.Object, "spec", "listeners")
+		return listeners
+	}
+
+	g.Expect(ensureCertificateGatewayListener(ctx, cl, appDomain)).To(Succeed())
+	g.Expect(ensureCertificateGatewayListener(ctx, cl, appDomain)).To(Succeed())
+	g.Expect(listeners()).To(HaveLen(2))
+	listener := listeners()[1].(map[string]any)
+	g.Expect(listener).To(HaveKeyWithValue("name", "tls-shop-example-com"))
+	g.Expect(listener).To(HaveKeyWithValue("hostname", "shop.example.com"))
+	refs, _, _ := unstructured.NestedSlice(listener, "tls", "certificateRefs")
+	g.Expect(refs).To(ConsistOf(HaveKeyWithValue("namespace", "project-ns")))
+
+	g.Expect(deleteCertificateGatewayListener(ctx, cl, "shop.example.com")).To(Succeed())
+	g.Expect(listeners()).To(ConsistOf(HaveKeyWithValue("name", "https")))
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func testApplicationDomain(namespace, name, uid, hostname string) *platformv1alpha1.ApplicationDomain {
+	return &platformv1alpha1.ApplicationDomain{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(uid)},
+		Spec:       platformv1alpha1.ApplicationDomainSpec{Domain: hostname},
+	}
+}
+
+func domainClaimClient(g *WithT, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func TestClaimDomain(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	claimed := testApplicationDomain("project-a", "domain-shop", "uid-a", "shop.example.com")
+	duplicate := testApplicationDomain("project-b", "domain-shop", "uid-b", "shop.example.com")
+	c := domainClaimClient(g, claimed, duplicate)
+
+	// The first domain claims the hostname, claiming again is a no-op
+	holder, err := platformv1alpha1.ClaimDomain(ctx, c, claimed)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(holder).To(BeNil())
+	holder, err = platformv1alpha1.ClaimDomain(ctx, c, claimed)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(holder).To(BeNil())
+
+	// The second one is told who holds it
+	holder, err = platformv1alpha1.ClaimDomain(ctx, c, duplicate)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(holder).NotTo(BeNil())
+	g.Expect(holder.Namespace).To(Equal("project-a"))
+
+	claim := &platformv1alpha1.DomainClaim{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: "shop.example.com"}, claim)).To(Succeed())
+	g.Expect(claim.Spec.DomainRef.UID).To(Equal(types.UID("uid-a")))
+
+	// Once the holder is gone its claim is taken over
+	g.Expect(c.Delete(ctx, claimed)).To(Succeed())
+	holder, err = platformv1alpha1.ClaimDomain(ctx, c, duplicate)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(holder).To(BeNil())
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: "shop.example.com"}, claim)).To(Succeed())
+	g.Expect(claim.Spec.DomainRef.UID).To(Equal(types.UID("uid-b")))
+}
+
+func TestFindDomainConflict(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	claimed := testApplicationDomain("project-a", "domain-shop", "uid-a", "shop.example.com")
+	c := domainClaimClient(g, claimed)
+	_, err := platformv1alpha1.ClaimDomain(ctx, c, claimed)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// A hostname claimed in another project conflicts
+	conflict, err := platformv1alpha1.FindDomainConflict(ctx, c, testApplicationDomain("project-b", "domain-shop", "uid-b", "shop.example.com"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conflict).NotTo(BeNil())
+	g.Expect(conflict.Namespace).To(Equal("project-a"))
+	g.Expect(conflict.Name).To(Equal("domain-shop"))
+
+	// A domain does not conflict with itself, nor with other hostnames
+	conflict, err = platformv1alpha1.FindDomainConflict(ctx, c, claimed)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conflict).To(BeNil())
+
+	conflict, err = platformv1alpha1.FindDomainConflict(ctx, c, testApplicationDomain("project-b", "domain-docs", "uid-c", "docs.example.com"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conflict).To(BeNil())
+
+	// Claims of domains that moved to another hostname are stale
+	claimed.Spec.Domain = "store.example.com"
+	g.Expect(c.Update(ctx, claimed)).To(Succeed())
+	conflict, err = platformv1alpha1.FindDomainConflict(ctx, c, testApplicationDomain("project-b", "domain-shop", "uid-b", "shop.example.com"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conflict).To(BeNil())
+}
+
+func TestReleaseDomainClaims(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	domain := testApplicationDomain("project-a", "domain-shop", "uid-a", "shop.example.com")
+	c := domainClaimClient(g, domain)
+	_, err := platformv1alpha1.ClaimDomain(ctx, c, domain)
+	g.Expect(err).NotTo(HaveOccurred())
+	domain.Spec.Domain = "store.example.com"
+	_, err = platformv1alpha1.ClaimDomain(ctx, c, domain)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The claim of the current hostname is kept
+	g.Expect(platformv1alpha1.ReleaseDomainClaims(ctx, c, domain, "store.example.com")).To(Succeed())
+	claims := &platformv1alpha1.DomainClaimList{}
+	g.Expect(c.List(ctx, claims)).To(Succeed())
+	g.Expect(claims.Items).To(HaveLen(1))
+	g.Expect(claims.Items[0].Name).To(Equal("store.example.com"))
+
+	g.Expect(platformv1alpha1.ReleaseDomainClaims(ctx, c, domain, "")).To(Succeed())
+	g.Expect(c.List(ctx, claims)).To(Succeed())
+	g.Expect(claims.Items).To(BeEmpty())
+}
+
+func TestValidateDomainUniquenessHonoursConflictOverride(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	claimed := testApplicationDomain("project-a", "domain-shop", "domain-a-uid", "shop.example.com")
+	duplicate := testApplicationDomain("project-b", "domain-shop", "domain-b-uid", "shop.example.com")
+	c := domainClaimClient(g, claimed, duplicate)
+	r := &ApplicationDomainReconciler{Client: c, Scheme: c.Scheme()}
+
+	g.Expect(r.validateDomainUniqueness(ctx, claimed)).To(Succeed())
+	g.Expect(r.validateDomainUniqueness(ctx, duplicate)).To(MatchError(ContainSubstring("project-a/domain-shop")))
+
+	duplicate.Annotations = map[string]string{validation.AnnotationAllowDomainConflict: "true"}
+	g.Expect(r.validateDomainUniqueness(ctx, duplicate)).To(Succeed())
+}
+
+func TestAuthorizeDomainConflict(t *testing.T) {
+	g := NewWithT(t)
+	request := func(username string, groups ...string) context.Context {
+		return admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: username, Groups: groups},
+			},
+		})
+	}
+
+	// Only admins may let a domain claim a hostname another domain holds
+	g.Expect(platformv1alpha1.AuthorizeDomainConflict(request("alice", validation.GroupDomainAdmins))).To(Succeed())
+	g.Expect(platformv1alpha1.AuthorizeDomainConflict(request("admin", "system:masters"))).To(Succeed())
+	g.Expect(platformv1alpha1.AuthorizeDomainConflict(request("tenant", "system:authenticated"))).
+		To(MatchError(ContainSubstring("not by tenant")))
+	g.Expect(platformv1alpha1.AuthorizeDomainConflict(context.Background())).To(HaveOccurred())
+}
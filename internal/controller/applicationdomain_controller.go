@@ -18,9 +18,11 @@ package controller
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,10 +30,15 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
 	"github.com/kibamail/kibaship/pkg/webhooks"
@@ -56,13 +63,23 @@ type ApplicationDomainReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Notifier webhooks.Notifier
+	// Clock stamps the status and webhook events, nil uses the wall clock
+	Clock clock.PassiveClock
+	// Recorder emits the ingress history of domains as Events, nil records the status only
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applicationdomains,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applicationdomains/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applicationdomains/finalizers,verbs=update
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=domainclaims,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // Access cert-manager.io Certificates to provision TLS for domains
-// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+// Read uploaded certificates and let the Gateway reference them
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=referencegrants,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -101,23 +118,64 @@ func (r *ApplicationDomainReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	// Validate the domain configuration
 	if err := r.validateDomain(ctx, &appDomain); err != nil {
 		logger.Error(err, "Domain validation failed")
+		recordEvent(r.Recorder, &appDomain, corev1.EventTypeWarning, eventReasonValidationFailed, "%v", err)
 		return r.updateStatus(ctx, &appDomain, platformv1alpha1.ApplicationDomainPhaseFailed,
 			fmt.Sprintf("Domain validation failed: %v", err))
 	}
 
 	// Handle certificate provisioning based on domain type
 	var certName, certNS string
+	var uploaded *x509.Certificate
 	var err error
 
-	if appDomain.Spec.Type == platformv1alpha1.ApplicationDomainTypeCustom {
+	if appDomain.Spec.Type == platformv1alpha1.ApplicationDomainTypeCustom && appDomain.Spec.CertificateSecretRef != nil {
+		// Custom domains bringing their own certificate: serve the uploaded Secret
+		uploaded, err = r.reconcileUploadedCertificate(ctx, &appDomain)
+		if err != nil {
+			logger.Error(err, "Uploaded certificate of custom ApplicationDomain rejected")
+			recordEvent(r.Recorder, &appDomain, corev1.EventTypeWarning, eventReasonCertificateFailed, "Uploaded certificate rejected: %v", err)
+			return r.updateStatus(ctx, &appDomain, platformv1alpha1.ApplicationDomainPhaseFailed,
+				fmt.Sprintf("Uploaded certificate rejected: %v", err))
+		}
+		logger.Info("Serving uploaded certificate for custom domain",
+			"secret", appDomain.Spec.CertificateSecretRef.Name, "notAfter", uploaded.NotAfter)
+	} else if appDomain.Spec.Type == platformv1alpha1.ApplicationDomainTypeCustom {
+		if appDomain.Status.CertificateNotAfter != nil {
+			if err := r.removeUploadedCertificate(ctx, &appDomain); err != nil {
+				logger.Error(err, "Failed to stop serving uploaded certificate")
+				return r.updateStatus(ctx, &appDomain, platformv1alpha1.ApplicationDomainPhaseFailed,
+					fmt.Sprintf("Certificate provisioning failed: %v", err))
+			}
+		}
+
 		// Custom domains: provision individual certificate via ACME/Let's Encrypt
 		certName, certNS, err = r.ensureCertificateForDomain(ctx, &appDomain)
 		if err != nil {
 			logger.Error(err, "Failed to provision Certificate for custom ApplicationDomain")
+			recordEvent(r.Recorder, &appDomain, corev1.EventTypeWarning, eventReasonCertificateFailed, "Certificate provisioning failed: %v", err)
 			return r.updateStatus(ctx, &appDomain, platformv1alpha1.ApplicationDomainPhaseFailed,
 				fmt.Sprintf("Certificate provisioning failed: %v", err))
 		}
 		logger.Info("Provisioned individual certificate for custom domain", "certificate", certName, "namespace", certNS)
+	} else if baseDomain := r.customBaseDomainFor(&appDomain); baseDomain != "" {
+		// Default domains under a project or application base domain: reference that base domain's wildcard certificate
+		certName, err = ensureBaseDomainCertificate(ctx, r.Client, baseDomain)
+		if err != nil {
+			logger.Error(err, "Failed to provision wildcard Certificate for custom base domain", "baseDomain", baseDomain)
+			recordEvent(r.Recorder, &appDomain, corev1.EventTypeWarning, eventReasonCertificateFailed, "Certificate provisioning failed: %v", err)
+			return r.updateStatus(ctx, &appDomain, platformv1alpha1.ApplicationDomainPhaseFailed,
+				fmt.Sprintf("Certificate provisioning failed: %v", err))
+		}
+		certNS = certificatesNamespace
+
+		if opConfig, cfgErr := GetOperatorConfig(); cfgErr == nil && opConfig.IngressProvider.UsesGatewayAPI() {
+			if err := ensureBaseDomainGatewayListener(ctx, r.Client, baseDomain); err != nil {
+				logger.Error(err, "Failed to add Gateway listener for custom base domain", "baseDomain", baseDomain)
+				return r.updateStatus(ctx, &appDomain, platformv1alpha1.ApplicationDomainPhaseFailed,
+					fmt.Sprintf("Gateway listener provisioning failed: %v", err))
+			}
+		}
+		logger.Info("Using wildcard certificate for custom base domain", "certificate", certName, "baseDomain", baseDomain)
 	} else {
 		// Default domains: reference the wildcard certificate
 		certName = ingressWildcardCertName
@@ -125,13 +183,45 @@ func (r *ApplicationDomainReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		logger.Info("Using wildcard certificate for default domain", "certificate", certName, "namespace", certNS)
 	}
 
-	appDomain.Status.CertificateRef = &platformv1alpha1.NamespacedRef{Name: certName, Namespace: certNS}
+	if uploaded != nil {
+		notAfter := metav1.NewTime(uploaded.NotAfter)
+		appDomain.Status.CertificateRef = nil
+		appDomain.Status.CertificateNotAfter = &notAfter
+	} else {
+		appDomain.Status.CertificateRef = &platformv1alpha1.NamespacedRef{Name: certName, Namespace: certNS}
+	}
+
+	if err := r.reconcileDomainRoutes(ctx, &appDomain); err != nil {
+		logger.Error(err, "Failed to route paths of ApplicationDomain")
+		return r.updateStatus(ctx, &appDomain, platformv1alpha1.ApplicationDomainPhaseFailed,
+			fmt.Sprintf("Path routing failed: %v", err))
+	}
+
+	if uploaded != nil {
+		result, err := r.updateStatus(ctx, &appDomain, platformv1alpha1.ApplicationDomainPhaseReady,
+			"Domain is configured with its uploaded certificate")
+		if err == nil {
+			// Check the certificate again when it expires, the domain then fails until it is replaced
+			result.RequeueAfter = certificateExpiryRequeue(uploaded.NotAfter, currentTime(r.Clock))
+		}
+		return result, err
+	}
 
 	// Update status to indicate domain is ready (certificate issuance will progress asynchronously)
 	return r.updateStatus(ctx, &appDomain, platformv1alpha1.ApplicationDomainPhaseReady,
 		"Domain is configured and certificate requested")
 }
 
+// customBaseDomainFor returns the custom base domain of a default-type ApplicationDomain,
+// or "" when it lives under the operator domain
+func (r *ApplicationDomainReconciler) customBaseDomainFor(appDomain *platformv1alpha1.ApplicationDomain) string {
+	opConfig, err := GetOperatorConfig()
+	if err != nil {
+		return ""
+	}
+	return customBaseDomain(appDomain.Spec.Domain, opConfig.Domain)
+}
+
 // handleDeletion handles the cleanup when an ApplicationDomain is being deleted
 func (r *ApplicationDomainReconciler) handleDeletion(ctx context.Context, appDomain *platformv1alpha1.ApplicationDomain) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
@@ -145,6 +235,20 @@ func (r *ApplicationDomainReconciler) handleDeletion(ctx context.Context, appDom
 
 	// TODO: In future phases, clean up ingress and certificate resources here
 
+	// The Gateway listener of an uploaded certificate is not owned by the domain
+	if appDomain.Spec.CertificateSecretRef != nil || appDomain.Status.CertificateNotAfter != nil {
+		if err := r.removeUploadedCertificate(ctx, appDomain); err != nil {
+			logger.Error(err, "Failed to remove uploaded certificate listener")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Free the hostname for other domains
+	if err := platformv1alpha1.ReleaseDomainClaims(ctx, r.Client, appDomain, ""); err != nil {
+		logger.Error(err, "Failed to release domain claims")
+		return ctrl.Result{}, err
+	}
+
 	// Remove the finalizer to allow deletion
 	controllerutil.RemoveFinalizer(appDomain, ApplicationDomainFinalizerName)
 	if err := r.Update(ctx, appDomain); err != nil {
@@ -175,6 +279,11 @@ func (r *ApplicationDomainReconciler) validateDomain(ctx context.Context, appDom
 		return fmt.Errorf("application reference validation failed: %v", err)
 	}
 
+	// Validate path routes to other applications
+	if err := r.validateDomainRoutes(ctx, appDomain); err != nil {
+		return fmt.Errorf("route validation failed: %v", err)
+	}
+
 	// Validate default domain constraints
 	if appDomain.Spec.Default {
 		if err := r.validateDefaultDomainUniqueness(ctx, appDomain); err != nil {
@@ -186,21 +295,23 @@ func (r *ApplicationDomainReconciler) validateDomain(ctx context.Context, appDom
 	return nil
 }
 
-// validateDomainUniqueness ensures the domain is unique across all ApplicationDomains
+// validateDomainUniqueness claims the hostname of the domain with a DomainClaim, unless an admin
+// allowed the conflict. Claims are created atomically, so of two domains created with the same
+// hostname at once only one passes. Claims of previous hostnames of the domain are released.
 func (r *ApplicationDomainReconciler) validateDomainUniqueness(ctx context.Context, appDomain *platformv1alpha1.ApplicationDomain) error {
-	var domains platformv1alpha1.ApplicationDomainList
-	if err := r.List(ctx, &domains); err != nil {
-		return fmt.Errorf("failed to list existing domains: %v", err)
+	if err := platformv1alpha1.ReleaseDomainClaims(ctx, r.Client, appDomain, appDomain.Spec.Domain); err != nil {
+		return err
 	}
 
-	for _, d := range domains.Items {
-		if d.UID != appDomain.UID && d.Spec.Domain == appDomain.Spec.Domain {
-			return fmt.Errorf("domain %s already exists in ApplicationDomain %s/%s",
-				appDomain.Spec.Domain, d.Namespace, d.Name)
-		}
+	holder, err := platformv1alpha1.ClaimDomain(ctx, r.Client, appDomain)
+	if err != nil {
+		return err
 	}
-
-	return nil
+	if holder == nil || appDomain.AllowsDomainConflict() {
+		return nil
+	}
+	return fmt.Errorf("domain %s already exists in ApplicationDomain %s/%s",
+		appDomain.Spec.Domain, holder.Namespace, holder.Name)
 }
 
 // validateApplicationReference validates that the referenced application exists
@@ -269,7 +380,7 @@ func (r *ApplicationDomainReconciler) updateStatus(ctx context.Context, appDomai
 
 	// Update application domain status
 
-	now := metav1.Now()
+	now := metav1.NewTime(currentTime(r.Clock))
 	prevPhase := appDomain.Status.Phase
 	appDomain.Status.Phase = phase
 	appDomain.Status.Message = message
@@ -313,6 +424,9 @@ func (r *ApplicationDomainReconciler) updateStatus(ctx context.Context, appDomai
 
 	meta.SetStatusCondition(&appDomain.Status.Conditions, condition)
 
+	eventType, reason := ingressEvent(phase)
+	recordDomainEvent(r.Recorder, appDomain, now, platformv1alpha1.DomainHistorySourceIngress, eventType, reason, message)
+
 	if err := r.Status().Update(ctx, appDomain); err != nil {
 		logger.Error(err, "Failed to update ApplicationDomain status")
 		return ctrl.Result{}, err
@@ -347,7 +461,7 @@ func (r *ApplicationDomainReconciler) emitApplicationDomainPhaseChange(ctx conte
 		PreviousPhase:     prev,
 		NewPhase:          next,
 		ApplicationDomain: *appDomain,
-		Timestamp:         time.Now().UTC(),
+		Timestamp:         currentTime(r.Clock).UTC(),
 	}
 	_ = r.Notifier.NotifyApplicationDomainStatusChange(ctx, evt)
 
@@ -411,6 +525,13 @@ func (r *ApplicationDomainReconciler) ensureCertificateForDomain(ctx context.Con
 // SetupWithManager sets up the controller with the Manager.
 func (r *ApplicationDomainReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&platformv1alpha1.ApplicationDomain{}).
+		For(&platformv1alpha1.ApplicationDomain{}, builder.WithPredicates(ignoreUptimeStatusUpdates())).
+		Owns(&corev1.Secret{}).
+		Watches(&platformv1alpha1.Application{}, handler.EnqueueRequestsFromMapFunc(r.applicationDomainRequests),
+			builder.WithPredicates(sessionAffinityChanged())).
+		Watches(&platformv1alpha1.ApplicationDomain{}, handler.EnqueueRequestsFromMapFunc(r.siblingDomainRequests),
+			builder.WithPredicates(backendProtocolChanged())).
+		WatchesRawSource(source.Channel(tlsPolicyChanged, handler.EnqueueRequestsFromMapFunc(r.tlsPolicyDomainRequests))).
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
 		Complete(r)
 }
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+const (
+	// DomainReasonCertificateIssuing is recorded while cert-manager attempts to issue the certificate
+	DomainReasonCertificateIssuing = "CertificateIssuing"
+	// DomainReasonCertificateIssued is recorded once the certificate is ready
+	DomainReasonCertificateIssued = "CertificateIssued"
+	// DomainReasonCertificateFailed is recorded when issuing the certificate fails
+	DomainReasonCertificateFailed = "CertificateFailed"
+	// DomainReasonIngressSynced is recorded once the domain routes to its application
+	DomainReasonIngressSynced = "IngressSynced"
+	// DomainReasonIngressSyncFailed is recorded when the domain cannot be routed
+	DomainReasonIngressSyncFailed = "IngressSyncFailed"
+	// DomainReasonIngressPending is recorded while the routing of the domain waits on another resource
+	DomainReasonIngressPending = "IngressPending"
+)
+
+// recordDomainEvent appends an event to the history of a domain, dropping the oldest entries past
+// MaxDomainHistory, and emits it as a Kubernetes Event when recorder is set. An event repeating the
+// latest one of its source is skipped, so resyncs do not flood the history. It reports whether the
+// history changed, the caller persists the status.
+func recordDomainEvent(recorder record.EventRecorder, domain *platformv1alpha1.ApplicationDomain, now metav1.Time,
+	source platformv1alpha1.DomainHistorySource, eventType, reason, message string) bool {
+	history := domain.Status.History
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Source != source {
+			continue
+		}
+		if history[i].Type == eventType && history[i].Reason == reason && history[i].Message == message {
+			return false
+		}
+		break
+	}
+
+	history = append(history, platformv1alpha1.DomainHistoryEntry{
+		Time:    now,
+		Source:  source,
+		Type:    eventType,
+		Reason:  reason,
+		Message: message,
+	})
+	if len(history) > platformv1alpha1.MaxDomainHistory {
+		history = history[len(history)-platformv1alpha1.MaxDomainHistory:]
+	}
+	domain.Status.History = history
+
+	if recorder != nil {
+		recorder.Event(domain, eventType, reason, message)
+	}
+	return true
+}
+
+// certificateEvent returns the history event of a cert-manager Certificate Ready condition
+func certificateEvent(readyStatus, reason, message string) (eventType, eventReason, eventMessage string) {
+	switch readyStatus {
+	case condTrue:
+		return corev1.EventTypeNormal, DomainReasonCertificateIssued, joinNonEmpty(reason, message)
+	case condFalse:
+		return corev1.EventTypeWarning, DomainReasonCertificateFailed, joinNonEmpty(reason, message)
+	default:
+		return corev1.EventTypeNormal, DomainReasonCertificateIssuing, joinNonEmpty(reason, message)
+	}
+}
+
+// ingressEvent returns the history event of the domain reconcile outcome
+func ingressEvent(phase platformv1alpha1.ApplicationDomainPhase) (eventType, reason string) {
+	switch phase {
+	case platformv1alpha1.ApplicationDomainPhaseReady:
+		return corev1.EventTypeNormal, DomainReasonIngressSynced
+	case platformv1alpha1.ApplicationDomainPhaseFailed:
+		return corev1.EventTypeWarning, DomainReasonIngressSyncFailed
+	default:
+		return corev1.EventTypeNormal, DomainReasonIngressPending
+	}
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+func TestRecordDomainEvent(t *testing.T) {
+	g := NewWithT(t)
+	recorder := record.NewFakeRecorder(100)
+	domain := &platformv1alpha1.ApplicationDomain{ObjectMeta: metav1.ObjectMeta{Name: "domain-1", Namespace: "project-ns"}}
+	now := metav1.NewTime(time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC))
+
+	eventType, reason, message := certificateEvent(condFalse, "Failed", "the ACME order was rejected")
+	g.Expect(recordDomainEvent(recorder, domain, now, platformv1alpha1.DomainHistorySourceCertificate, eventType, reason, message)).To(BeTrue())
+	g.Expect(<-recorder.Events).To(Equal("Warning CertificateFailed Failed: the ACME order was rejected"))
+
+	// An ingress event in between does not hide a repeat of the latest certificate event
+	eventType, reason = ingressEvent(platformv1alpha1.ApplicationDomainPhaseReady)
+	g.Expect(recordDomainEvent(recorder, domain, now, platformv1alpha1.DomainHistorySourceIngress, eventType, reason, "Domain is configured")).To(BeTrue())
+	eventType, reason, message = certificateEvent(condFalse, "Failed", "the ACME order was rejected")
+	g.Expect(recordDomainEvent(recorder, domain, now, platformv1alpha1.DomainHistorySourceCertificate, eventType, reason, message)).To(BeFalse())
+	g.Expect(domain.Status.History).To(HaveLen(2))
+
+	eventType, reason, message = certificateEvent(condTrue, "Ready", "Certificate is up to date and has not expired")
+	g.Expect(recordDomainEvent(recorder, domain, now, platformv1alpha1.DomainHistorySourceCertificate, eventType, reason, message)).To(BeTrue())
+	g.Expect(domain.Status.History[2]).To(Equal(platformv1alpha1.DomainHistoryEntry{
+		Time:    now,
+		Source:  platformv1alpha1.DomainHistorySourceCertificate,
+		Type:    corev1.EventTypeNormal,
+		Reason:  DomainReasonCertificateIssued,
+		Message: "Ready: Certificate is up to date and has not expired",
+	}))
+
+	// The history keeps the latest entries only
+	for i := 0; i < platformv1alpha1.MaxDomainHistory; i++ {
+		recordDomainEvent(nil, domain, now, platformv1alpha1.DomainHistorySourceIngress,
+			corev1.EventTypeWarning, DomainReasonIngressSyncFailed, fmt.Sprintf("Path routing failed: attempt %d", i))
+	}
+	g.Expect(domain.Status.History).To(HaveLen(platformv1alpha1.MaxDomainHistory))
+	g.Expect(domain.Status.History[0].Message).To(Equal("Path routing failed: attempt 0"))
+	g.Expect(domain.Status.History[platformv1alpha1.MaxDomainHistory-1].Message).To(Equal("Path routing failed: attempt 19"))
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+var _ = Describe("ApplicationDomain reconciler", func() {
+	var (
+		ctx           context.Context
+		clk           *clocktesting.FakePassiveClock
+		notifier      *recordingNotifier
+		reconciler    *ApplicationDomainReconciler
+		testNamespace *corev1.Namespace
+		appUUID       string
+		domainUUID    string
+	)
+
+	newDomain := func(applicationName string) *platformv1alpha1.ApplicationDomain {
+		domain := &platformv1alpha1.ApplicationDomain{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      utils.GetApplicationDomainResourceName(domainUUID),
+				Namespace: testNamespace.Name,
+				Labels: map[string]string{
+					validation.LabelResourceUUID:    domainUUID,
+					validation.LabelResourceSlug:    "web",
+					validation.LabelApplicationUUID: appUUID,
+				},
+			},
+			Spec: platformv1alpha1.ApplicationDomainSpec{
+				ApplicationRef: corev1.LocalObjectReference{Name: applicationName},
+				Domain:         fmt.Sprintf("web-%s.apps.kibaship.com", domainUUID),
+				Port:           3000,
+				Type:           platformv1alpha1.ApplicationDomainTypeDefault,
+				Default:        true,
+			},
+		}
+		Expect(k8sClient.Create(ctx, domain)).To(Succeed())
+		return domain
+	}
+
+	// reconcileDomain reconciles twice, the first reconcile only adds the finalizer
+	reconcileDomain := func(domain *platformv1alpha1.ApplicationDomain) *platformv1alpha1.ApplicationDomain {
+		for range 2 {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(domain)})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		reconciled := &platformv1alpha1.ApplicationDomain{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(domain), reconciled)).To(Succeed())
+		return reconciled
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		Expect(SetOperatorConfig("kibaship.com", "test-gateway-class")).To(Succeed())
+		clk = clocktesting.NewFakePassiveClock(time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC))
+		notifier = &recordingNotifier{}
+		reconciler = &ApplicationDomainReconciler{
+			Client:   k8sClient,
+			Scheme:   k8sClient.Scheme(),
+			Notifier: notifier,
+			Clock:    clk,
+		}
+
+		uniqueID := time.Now().UnixNano()
+		appUUID = fmt.Sprintf("app-uuid-%d", uniqueID)
+		domainUUID = fmt.Sprintf("%d", uniqueID)
+
+		testNamespace = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("test-domain-integration-%d", uniqueID)},
+		}
+		Expect(k8sClient.Create(ctx, testNamespace)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = k8sClient.Delete(ctx, testNamespace)
+	})
+
+	It("marks the domain ready and notifies the phase change", func() {
+		application := &platformv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      utils.GetApplicationResourceName(appUUID),
+				Namespace: testNamespace.Name,
+				Labels:    map[string]string{validation.LabelResourceUUID: appUUID},
+			},
+			Spec: platformv1alpha1.ApplicationSpec{
+				EnvironmentRef: corev1.LocalObjectReference{Name: "environment-production"},
+				Type:           platformv1alpha1.ApplicationTypeGitRepository,
+				GitRepository: &platformv1alpha1.GitRepositoryConfig{
+					Provider:     platformv1alpha1.GitProviderGitHub,
+					Repository:   "acme/web",
+					PublicAccess: true,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, application)).To(Succeed())
+
+		domain := reconcileDomain(newDomain(application.Name))
+
+		Expect(domain.Status.Phase).To(Equal(platformv1alpha1.ApplicationDomainPhaseReady))
+		Expect(domain.Status.LastReconcileTime).NotTo(BeNil())
+		Expect(domain.Status.LastReconcileTime.Time).To(BeTemporally("==", clk.Now()))
+		Expect(domain.Status.CertificateRef).NotTo(BeNil())
+		Expect(domain.Status.CertificateRef.Name).To(Equal(ingressWildcardCertName))
+
+		events := notifier.DomainEvents()
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].PreviousPhase).To(BeEmpty())
+		Expect(events[0].NewPhase).To(Equal(string(platformv1alpha1.ApplicationDomainPhaseReady)))
+		Expect(events[0].Timestamp).To(BeTemporally("==", clk.Now()))
+	})
+
+	It("fails the domain when its application does not exist", func() {
+		domain := reconcileDomain(newDomain(utils.GetApplicationResourceName(appUUID)))
+
+		Expect(domain.Status.Phase).To(Equal(platformv1alpha1.ApplicationDomainPhaseFailed))
+		Expect(domain.Status.Message).To(ContainSubstring("not found"))
+		Expect(domain.Status.CertificateReady).To(BeFalse())
+
+		events := notifier.DomainEvents()
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].NewPhase).To(Equal(string(platformv1alpha1.ApplicationDomainPhaseFailed)))
+
+		By("reconciling again without a change")
+		clk.SetTime(clk.Now().Add(time.Minute))
+		domain = reconcileDomain(domain)
+		Expect(domain.Status.LastReconcileTime.Time).To(BeTemporally("==", clk.Now()))
+		Expect(notifier.DomainEvents()).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,205 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// domainRouteName is the name of the routing resources serving a domain with path routes
+func domainRouteName(appDomain *platformv1alpha1.ApplicationDomain) string {
+	return fmt.Sprintf("httproute-%s", appDomain.Name)
+}
+
+// validateDomainRoutes checks the path routes of a domain are unique and only send traffic to
+// web applications of the same project as the application owning the domain
+func (r *ApplicationDomainReconciler) validateDomainRoutes(ctx context.Context, appDomain *platformv1alpha1.ApplicationDomain) error {
+	if len(appDomain.Spec.Routes) == 0 {
+		return nil
+	}
+
+	if err := platformv1alpha1.ValidateDomainRoutes(appDomain.Spec.Routes); err != nil {
+		return err
+	}
+
+	owner, err := r.getDomainRouteApplication(ctx, appDomain, appDomain.Spec.ApplicationRef.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, route := range appDomain.Spec.Routes {
+		app, err := r.getDomainRouteApplication(ctx, appDomain, route.ApplicationRef.Name)
+		if err != nil {
+			return fmt.Errorf("route %s: %w", route.Path, err)
+		}
+
+		switch app.Spec.Type {
+		case platformv1alpha1.ApplicationTypeGitRepository,
+			platformv1alpha1.ApplicationTypeDockerImage,
+			platformv1alpha1.ApplicationTypeImageFromRegistry:
+		default:
+			return fmt.Errorf("route %s: application %s of type %s does not serve HTTP", route.Path, app.Name, app.Spec.Type)
+		}
+
+		if app.GetProjectUUID() != owner.GetProjectUUID() {
+			return fmt.Errorf("route %s: application %s belongs to another project", route.Path, app.Name)
+		}
+	}
+
+	return nil
+}
+
+// getDomainRouteApplication fetches an application referenced by a domain
+func (r *ApplicationDomainReconciler) getDomainRouteApplication(ctx context.Context, appDomain *platformv1alpha1.ApplicationDomain, name string) (*platformv1alpha1.Application, error) {
+	var app platformv1alpha1.Application
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: appDomain.Namespace}, &app); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("referenced application %s not found in namespace %s", name, appDomain.Namespace)
+		}
+		return nil, fmt.Errorf("failed to get referenced application: %v", err)
+	}
+	return &app, nil
+}
+
+// applicationDomainRouteSpec describes the routing of a domain: its application serves every path
+// not routed to another application, and every response carries the headers of the domain
+func applicationDomainRouteSpec(
+	ctx context.Context,
+	c client.Client,
+	appDomain *platformv1alpha1.ApplicationDomain,
+	owner *platformv1alpha1.Application,
+	opConfig *OperatorConfig,
+) (RouteSpec, error) {
+	name := domainRouteName(appDomain)
+	if appDomain.Spec.Default {
+		// The default domain shares the application route created once the application is deployed
+		name = fmt.Sprintf("httproute-app-%s", owner.GetUUID())
+	}
+
+	route := RouteSpec{
+		Namespace:        appDomain.Namespace,
+		Name:             name,
+		Hostname:         appDomain.Spec.Domain,
+		BaseDomain:       customBaseDomain(appDomain.Spec.Domain, opConfig.Domain),
+		ServiceName:      utils.GetServiceName(owner.GetUUID()),
+		ServicePort:      appDomain.Spec.Port,
+		SecurityHeaders:  appDomain.Spec.SecurityHeaders,
+		CORS:             appDomain.Spec.CORS,
+		Annotations:      appDomain.Spec.IngressAnnotations,
+		SessionAffinity:  owner.Spec.SessionAffinity,
+		MaxBodySize:      appDomain.Spec.MaxBodySize,
+		ProxyReadTimeout: appDomain.Spec.ProxyReadTimeout,
+		ProxySendTimeout: appDomain.Spec.ProxySendTimeout,
+		TLSPolicy:        routeTLSPolicy(appDomain.Spec.IgnoreTLSPolicy),
+		ProjectUUID:      owner.Labels[validation.LabelProjectUUID],
+		Reconcile:        domainRoutesManaged(appDomain),
+	}
+	if ref := appDomain.Spec.CertificateSecretRef; ref != nil {
+		route.TLSSecretName = ref.Name
+	}
+	protocol, err := applicationBackendProtocol(ctx, c, owner, appDomain)
+	if err != nil {
+		return RouteSpec{}, err
+	}
+	route.BackendProtocol = protocol
+
+	for _, domainRoute := range appDomain.Spec.Routes {
+		var app platformv1alpha1.Application
+		key := types.NamespacedName{Name: domainRoute.ApplicationRef.Name, Namespace: appDomain.Namespace}
+		if err := c.Get(ctx, key, &app); err != nil {
+			return RouteSpec{}, fmt.Errorf("failed to get application of route %s: %w", domainRoute.Path, err)
+		}
+		port := domainRoute.Port
+		if port == 0 {
+			port = 3000 // Default port
+		}
+		route.Paths = append(route.Paths, RoutePath{
+			Path:        platformv1alpha1.NormalizeRoutePath(domainRoute.Path),
+			ServiceName: utils.GetServiceName(app.GetUUID()),
+			ServicePort: port,
+		})
+	}
+
+	return route, nil
+}
+
+// domainRoutesManaged reports whether a domain is routed by its own resources rather than the
+// application route: it has path routes, response headers, its own certificate, ingress annotations
+// or proxy settings
+func domainRoutesManaged(appDomain *platformv1alpha1.ApplicationDomain) bool {
+	return len(appDomain.Spec.Routes) > 0 || appDomain.Spec.HasResponsePolicy() || appDomain.Spec.CertificateSecretRef != nil ||
+		len(appDomain.Spec.IngressAnnotations) > 0 || appDomain.Spec.HasProxySettings()
+}
+
+// reconcileDomainRoutes keeps the routing resources of a domain in sync with its path routes and
+// response headers. Default domains update the application route once the application is deployed,
+// other domains are routed by their own resources while they have path routes, response headers,
+// their own certificate, ingress annotations or proxy settings.
+func (r *ApplicationDomainReconciler) reconcileDomainRoutes(ctx context.Context, appDomain *platformv1alpha1.ApplicationDomain) error {
+	logger := log.FromContext(ctx)
+	managed := domainRoutesManaged(appDomain)
+
+	opConfig, err := GetOperatorConfig()
+	if err != nil || opConfig.Domain == "" {
+		if !managed {
+			return nil // Nothing could have been routed without a configuration
+		}
+		if err == nil {
+			err = fmt.Errorf("no base domain configured")
+		}
+		return fmt.Errorf("failed to get operator config: %w", err)
+	}
+
+	provider := NewRouteProvider(r.Client, r.Scheme, opConfig)
+	if !managed && !appDomain.Spec.Default {
+		return provider.DeleteRoute(ctx, appDomain.Namespace, domainRouteName(appDomain))
+	}
+
+	owner, err := r.getDomainRouteApplication(ctx, appDomain, appDomain.Spec.ApplicationRef.Name)
+	if err != nil {
+		return err
+	}
+	if appDomain.Spec.Default && owner.Spec.CurrentDeploymentRef == nil {
+		logger.V(1).Info("Application not deployed yet, its route is created by the first deployment",
+			"domain", appDomain.Spec.Domain)
+		return nil
+	}
+
+	route, err := applicationDomainRouteSpec(ctx, r.Client, appDomain, owner, opConfig)
+	if err != nil {
+		return err
+	}
+	route.Reconcile = true
+
+	if err := provider.EnsureRoute(ctx, route, appDomain); err != nil {
+		return err
+	}
+
+	logger.Info("Ensured routes for domain", "domain", appDomain.Spec.Domain, "routes", len(route.Paths),
+		"responseHeaders", route.hasResponseHeaders(), "ingressProvider", opConfig.IngressProvider)
+	return nil
+}
@@ -0,0 +1,536 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/dbaccess"
+	"github.com/kibamail/kibaship/pkg/idle"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+var (
+	// kedaScaledObjectGVK is the KEDA kind scaling the deployment of an application on its triggers
+	kedaScaledObjectGVK = schema.GroupVersionKind{Group: "keda.sh", Version: "v1alpha1", Kind: "ScaledObject"}
+
+	// kedaTriggerAuthenticationGVK is the KEDA kind passing database credentials to a trigger
+	kedaTriggerAuthenticationGVK = schema.GroupVersionKind{Group: "keda.sh", Version: "v1alpha1", Kind: "TriggerAuthentication"}
+)
+
+const (
+	// autoscalingCheckInterval is how often the reported scale of an application is refreshed
+	autoscalingCheckInterval = 30 * time.Second
+
+	// kedaMissingCheckInterval is how long to wait before retrying when the KEDA CRDs are missing
+	kedaMissingCheckInterval = 5 * time.Minute
+
+	// kedaPausedAnnotation stops KEDA from scaling a ScaledObject, set while the idle policy keeps
+	// the application asleep
+	kedaPausedAnnotation = "autoscaling.keda.sh/paused"
+
+	// requestsPerSecondWindow is the range the request rate of requestsPerSecond triggers is
+	// averaged over
+	requestsPerSecondWindow = "2m"
+)
+
+// errInvalidAutoscalingTrigger marks triggers that cannot be rendered from the current state of
+// the cluster, they are reported on the application status instead of retried
+var errInvalidAutoscalingTrigger = errors.New("invalid autoscaling trigger")
+
+// kedaTrigger is an autoscaling trigger rendered for a KEDA ScaledObject
+type kedaTrigger struct {
+	Type       string
+	MetricType string
+	Metadata   map[string]any
+
+	// Secret holds the credentials of the trigger, SecretKeys maps the KEDA parameters to its keys
+	Secret     string
+	SecretKeys map[string]string
+}
+
+// AutoscalingReconciler scales applications with an autoscaling configuration through a KEDA
+// ScaledObject targeting their current deployment, and reports the scale on the application status
+type AutoscalingReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=keda.sh,resources=scaledobjects;triggerauthentications,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// Reconcile keeps the ScaledObject of an application in sync with its autoscaling configuration
+func (r *AutoscalingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var app platformv1alpha1.Application
+	if err := r.Get(ctx, req.NamespacedName, &app); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !app.DeletionTimestamp.IsZero() || app.GetUUID() == "" {
+		return ctrl.Result{}, nil
+	}
+
+	name := scaledObjectName(app.GetUUID())
+	if app.Spec.Autoscaling == nil {
+		// Deployments keep their current replicas when autoscaling is removed
+		if err := r.deleteScaledObject(ctx, &app, name); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, r.updateStatus(ctx, &app, nil)
+	}
+
+	next := &platformv1alpha1.AutoscalingStatus{}
+	if app.Spec.CurrentDeploymentRef == nil {
+		next.Message = "Waiting for the first deployment of the application"
+		return ctrl.Result{}, r.updateStatus(ctx, &app, next)
+	}
+
+	triggers, err := r.autoscalingTriggers(ctx, &app)
+	if errors.Is(err, errInvalidAutoscalingTrigger) {
+		next.Message = err.Error()
+		return ctrl.Result{RequeueAfter: autoscalingCheckInterval}, r.updateStatus(ctx, &app, next)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	deployments, err := idle.Deployments(ctx, r.Client, app.Namespace, app.GetUUID())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	var current *appsv1.Deployment
+	for i := range deployments {
+		if deployments[i].Name == app.Spec.CurrentDeploymentRef.Name {
+			current = &deployments[i]
+		}
+	}
+
+	scaledObject, err := r.ensureScaledObject(ctx, &app, name, triggers, current)
+	if meta.IsNoMatchError(err) {
+		next.Message = "KEDA is not installed in the cluster"
+		return ctrl.Result{RequeueAfter: kedaMissingCheckInterval}, r.updateStatus(ctx, &app, next)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	next.ScaledObject = name
+	next.Ready, next.Active, next.Message = scaledObjectConditions(scaledObject)
+	if current != nil {
+		next.CurrentReplicas = current.Status.Replicas
+		if current.Spec.Replicas != nil {
+			next.DesiredReplicas = *current.Spec.Replicas
+		}
+	}
+	if err := r.hpaScale(ctx, scaledObject, next); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if current := app.Status.Autoscaling; current == nil || current.DesiredReplicas != next.DesiredReplicas {
+		log.V(1).Info("Application scale changed", "application", app.Name, "desiredReplicas", next.DesiredReplicas)
+	}
+	if err := r.updateStatus(ctx, &app, next); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: autoscalingCheckInterval}, nil
+}
+
+// autoscalingTriggers renders the triggers of the autoscaling configuration of app for KEDA
+func (r *AutoscalingReconciler) autoscalingTriggers(ctx context.Context, app *platformv1alpha1.Application) ([]kedaTrigger, error) {
+	triggers := make([]kedaTrigger, 0, len(app.Spec.Autoscaling.Triggers))
+	for i, trigger := range app.Spec.Autoscaling.Triggers {
+		var rendered kedaTrigger
+		var err error
+		switch trigger.Type {
+		case platformv1alpha1.AutoscalingTriggerCPU:
+			rendered = cpuTrigger(trigger)
+		case platformv1alpha1.AutoscalingTriggerRequestsPerSecond:
+			var metrics config.MetricsConfig
+			if metrics, err = r.metricsConfig(ctx); err == nil {
+				rendered, err = requestsPerSecondTrigger(trigger, metrics, app)
+			}
+		case platformv1alpha1.AutoscalingTriggerValkeyListLength, platformv1alpha1.AutoscalingTriggerMySQLQuery:
+			var database platformv1alpha1.Application
+			if err = r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: trigger.ApplicationRef.Name}, &database); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return nil, fmt.Errorf("failed to get application of autoscaling trigger %d: %w", i, err)
+				}
+				err = fmt.Errorf("%w: application %s not found", errInvalidAutoscalingTrigger, trigger.ApplicationRef.Name)
+				break
+			}
+			rendered, err = databaseTrigger(trigger, &database)
+		default:
+			err = fmt.Errorf("%w: unknown type %q", errInvalidAutoscalingTrigger, trigger.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("trigger %d: %w", i, err)
+		}
+		triggers = append(triggers, rendered)
+	}
+	return triggers, nil
+}
+
+// metricsConfig reads the Prometheus of the traffic metrics from the operator ConfigMap, so
+// requestsPerSecond triggers follow configuration changes without a restart
+func (r *AutoscalingReconciler) metricsConfig(ctx context.Context) (config.MetricsConfig, error) {
+	var cm corev1.ConfigMap
+	err := r.Get(ctx, client.ObjectKey{Namespace: config.OperatorNamespace, Name: config.OperatorConfigMapName}, &cm)
+	if apierrors.IsNotFound(err) {
+		return config.ParseMetricsConfig(nil)
+	}
+	if err != nil {
+		return config.MetricsConfig{}, fmt.Errorf("failed to get operator ConfigMap: %w", err)
+	}
+
+	metrics, err := config.ParseMetricsConfig(cm.Data)
+	if err != nil {
+		return config.MetricsConfig{}, fmt.Errorf("%w: %v", errInvalidAutoscalingTrigger, err)
+	}
+	return metrics, nil
+}
+
+// cpuTrigger scales on the average CPU utilization of the replicas
+func cpuTrigger(trigger platformv1alpha1.AutoscalingTrigger) kedaTrigger {
+	return kedaTrigger{
+		Type:       "cpu",
+		MetricType: "Utilization",
+		Metadata:   map[string]any{"value": strconv.FormatInt(trigger.Target, 10)},
+	}
+}
+
+// requestsPerSecondTrigger scales on the request rate the ingress controller routes to the Service
+// of app, read from Prometheus
+func requestsPerSecondTrigger(trigger platformv1alpha1.AutoscalingTrigger, metrics config.MetricsConfig, app *platformv1alpha1.Application) (kedaTrigger, error) {
+	if !metrics.Enabled() {
+		return kedaTrigger{}, fmt.Errorf("%w: requestsPerSecond needs traffic metrics, set %s in the operator configuration",
+			errInvalidAutoscalingTrigger, config.ConfigKeyMetricsPrometheusURL)
+	}
+	metric, selector, ok := metrics.IngressProvider.RequestsMetric(app.Namespace, utils.GetServiceName(app.GetUUID()))
+	if !ok {
+		return kedaTrigger{}, fmt.Errorf("%w: the %s ingress provider exports no request metrics",
+			errInvalidAutoscalingTrigger, metrics.IngressProvider)
+	}
+
+	return kedaTrigger{
+		Type: "prometheus",
+		Metadata: map[string]any{
+			"serverAddress": metrics.Endpoint(),
+			"query":         fmt.Sprintf("sum(rate(%s{%s}[%s]))", metric, selector, requestsPerSecondWindow),
+			"threshold":     strconv.FormatInt(trigger.Target, 10),
+		},
+	}, nil
+}
+
+// databaseTrigger scales on the length of a list of a Valkey application or the result of a query
+// against a MySQL application, authenticated with the administrative credentials of the database
+func databaseTrigger(trigger platformv1alpha1.AutoscalingTrigger, database *platformv1alpha1.Application) (kedaTrigger, error) {
+	conn, ok := dbaccess.ConnectionFor(database)
+	target := strconv.FormatInt(trigger.Target, 10)
+	address := fmt.Sprintf("%s:%d", conn.Host(database.Namespace), conn.Port)
+
+	switch {
+	case ok && trigger.Type == platformv1alpha1.AutoscalingTriggerValkeyListLength && conn.Engine == dbaccess.EngineValkey:
+		rendered := kedaTrigger{
+			Type:       "redis",
+			Metadata:   map[string]any{"address": address, "listName": trigger.ListName, "listLength": target, "databaseIndex": conn.Database},
+			Secret:     conn.Secret,
+			SecretKeys: map[string]string{"password": conn.PasswordKey},
+		}
+		if database.Spec.Type == platformv1alpha1.ApplicationTypeValkeyCluster {
+			// Clusters shard lists over their nodes and have no numbered databases
+			rendered.Type = "redis-cluster"
+			rendered.Metadata = map[string]any{"addresses": address, "listName": trigger.ListName, "listLength": target}
+		}
+		return rendered, nil
+
+	case ok && trigger.Type == platformv1alpha1.AutoscalingTriggerMySQLQuery && conn.Engine == dbaccess.EngineMySQL:
+		dbName := conn.Database
+		if dbName == "" {
+			// Queries of applications without a default database name their tables in full
+			dbName = "mysql"
+		}
+		return kedaTrigger{
+			Type: "mysql",
+			Metadata: map[string]any{
+				"host":       conn.Host(database.Namespace),
+				"port":       strconv.Itoa(int(conn.Port)),
+				"dbName":     dbName,
+				"query":      trigger.Query,
+				"queryValue": target,
+			},
+			Secret:     conn.Secret,
+			SecretKeys: map[string]string{"username": conn.UsernameKey, "password": conn.PasswordKey},
+		}, nil
+	}
+
+	engine := "Valkey"
+	if trigger.Type == platformv1alpha1.AutoscalingTriggerMySQLQuery {
+		engine = "MySQL"
+	}
+	return kedaTrigger{}, fmt.Errorf("%w: %s triggers need a %s application, %s is a %s application",
+		errInvalidAutoscalingTrigger, trigger.Type, engine, database.Name, database.Spec.Type)
+}
+
+// ensureScaledObject creates or updates the ScaledObject of app and the TriggerAuthentications of
+// its triggers, deleting the ones of removed triggers. The ScaledObject is paused while the current
+// deployment sleeps, so the idle policy and KEDA do not fight over its replicas.
+func (r *AutoscalingReconciler) ensureScaledObject(
+	ctx context.Context,
+	app *platformv1alpha1.Application,
+	name string,
+	triggers []kedaTrigger,
+	current *appsv1.Deployment,
+) (*unstructured.Unstructured, error) {
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by":  "kibaship",
+		validation.LabelApplicationUUID: app.GetUUID(),
+	}
+
+	rendered := make([]any, 0, len(triggers))
+	authentications := map[string]bool{}
+	for i, trigger := range triggers {
+		spec := map[string]any{"type": trigger.Type, "metadata": trigger.Metadata}
+		if trigger.MetricType != "" {
+			spec["metricType"] = trigger.MetricType
+		}
+		if trigger.Secret != "" {
+			authName := fmt.Sprintf("%s-%d", name, i)
+			if err := r.ensureTriggerAuthentication(ctx, app, authName, labels, trigger); err != nil {
+				return nil, err
+			}
+			authentications[authName] = true
+			spec["authenticationRef"] = map[string]any{"name": authName}
+		}
+		rendered = append(rendered, spec)
+	}
+	if err := r.deleteTriggerAuthentications(ctx, app, authentications); err != nil {
+		return nil, err
+	}
+
+	autoscaling := app.Spec.Autoscaling
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(kedaScaledObjectGVK)
+	obj.SetNamespace(app.Namespace)
+	obj.SetName(name)
+
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		obj.SetLabels(labels)
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		delete(annotations, kedaPausedAnnotation)
+		if current != nil {
+			if _, sleeping := current.Annotations[idle.AnnotationIdleReplicas]; sleeping {
+				annotations[kedaPausedAnnotation] = TrueString
+			}
+		}
+		obj.SetAnnotations(annotations)
+		obj.Object["spec"] = map[string]any{
+			"scaleTargetRef":  map[string]any{"name": app.Spec.CurrentDeploymentRef.Name},
+			"minReplicaCount": int64(autoscaling.Min()),
+			"maxReplicaCount": int64(autoscaling.MaxReplicas),
+			"triggers":        rendered,
+		}
+		return ctrl.SetControllerReference(app, obj, r.Scheme)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to ensure ScaledObject: %w", err)
+	}
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Ensured ScaledObject", "name", name, "result", result)
+	return obj, nil
+}
+
+// ensureTriggerAuthentication creates or updates the TriggerAuthentication reading the credentials
+// of trigger from its Secret
+func (r *AutoscalingReconciler) ensureTriggerAuthentication(
+	ctx context.Context,
+	app *platformv1alpha1.Application,
+	name string,
+	labels map[string]string,
+	trigger kedaTrigger,
+) error {
+	parameters := make([]string, 0, len(trigger.SecretKeys))
+	for parameter := range trigger.SecretKeys {
+		parameters = append(parameters, parameter)
+	}
+	sort.Strings(parameters)
+
+	refs := make([]any, 0, len(parameters))
+	for _, parameter := range parameters {
+		refs = append(refs, map[string]any{"parameter": parameter, "name": trigger.Secret, "key": trigger.SecretKeys[parameter]})
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(kedaTriggerAuthenticationGVK)
+	obj.SetNamespace(app.Namespace)
+	obj.SetName(name)
+
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		obj.SetLabels(labels)
+		obj.Object["spec"] = map[string]any{"secretTargetRef": refs}
+		return ctrl.SetControllerReference(app, obj, r.Scheme)
+	}); err != nil {
+		return fmt.Errorf("failed to ensure TriggerAuthentication: %w", err)
+	}
+	return nil
+}
+
+// deleteTriggerAuthentications deletes the TriggerAuthentications of app not in keep
+func (r *AutoscalingReconciler) deleteTriggerAuthentications(ctx context.Context, app *platformv1alpha1.Application, keep map[string]bool) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(kedaTriggerAuthenticationGVK.GroupVersion().WithKind(kedaTriggerAuthenticationGVK.Kind + "List"))
+	if err := r.List(ctx, list, client.InNamespace(app.Namespace),
+		client.MatchingLabels{validation.LabelApplicationUUID: app.GetUUID(), "app.kubernetes.io/managed-by": "kibaship"}); err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		if keep[list.Items[i].GetName()] {
+			continue
+		}
+		if err := r.Delete(ctx, &list.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete TriggerAuthentication %s: %w", list.Items[i].GetName(), err)
+		}
+	}
+	return nil
+}
+
+// deleteScaledObject deletes the ScaledObject of app and its TriggerAuthentications
+func (r *AutoscalingReconciler) deleteScaledObject(ctx context.Context, app *platformv1alpha1.Application, name string) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(kedaScaledObjectGVK)
+	obj.SetNamespace(app.Namespace)
+	obj.SetName(name)
+
+	// The ScaledObject kind is missing when KEDA was never installed, nothing to delete then
+	if err := r.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to delete ScaledObject: %w", err)
+	}
+	if err := r.deleteTriggerAuthentications(ctx, app, nil); err != nil && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
+
+// scaledObjectConditions reads whether KEDA accepted the ScaledObject and whether a trigger is active
+func scaledObjectConditions(obj *unstructured.Unstructured) (ready, active bool, message string) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	message = "Waiting for KEDA to pick up the ScaledObject"
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		status, _ := condition["status"].(string)
+		switch condition["type"] {
+		case "Ready":
+			ready = status == string(corev1.ConditionTrue)
+			message, _ = condition["message"].(string)
+		case "Active":
+			active = status == string(corev1.ConditionTrue)
+		}
+	}
+	if ready {
+		message = ""
+	}
+	return ready, active, message
+}
+
+// hpaScale reports the replicas the HorizontalPodAutoscaler KEDA manages for the ScaledObject asks
+// for and when it last scaled
+func (r *AutoscalingReconciler) hpaScale(ctx context.Context, scaledObject *unstructured.Unstructured, status *platformv1alpha1.AutoscalingStatus) error {
+	name, _, _ := unstructured.NestedString(scaledObject.Object, "status", "hpaName")
+	if name == "" {
+		name = "keda-hpa-" + scaledObject.GetName()
+	}
+
+	var hpa autoscalingv2.HorizontalPodAutoscaler
+	if err := r.Get(ctx, client.ObjectKey{Namespace: scaledObject.GetNamespace(), Name: name}, &hpa); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get HorizontalPodAutoscaler of ScaledObject: %w", err)
+	}
+	status.DesiredReplicas = hpa.Status.DesiredReplicas
+	status.LastScaleTime = hpa.Status.LastScaleTime
+	return nil
+}
+
+// updateStatus records the scale of the application, clearing it when next is nil
+func (r *AutoscalingReconciler) updateStatus(ctx context.Context, app *platformv1alpha1.Application, next *platformv1alpha1.AutoscalingStatus) error {
+	if reflect.DeepEqual(app.Status.Autoscaling, next) {
+		return nil
+	}
+
+	patch := client.MergeFrom(app.DeepCopy())
+	app.Status.Autoscaling = next
+	if err := r.Status().Patch(ctx, app, patch); err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to update application autoscaling status: %w", err)
+	}
+	return nil
+}
+
+// scaledObjectName is the name of the ScaledObject of an application
+func scaledObjectName(applicationUUID string) string {
+	return fmt.Sprintf("scaledobject-%s", applicationUUID)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Status updates are ignored, the scale is refreshed on spec changes and every 30 seconds. The
+// ScaledObjects are not watched so the operator starts on clusters without KEDA.
+func (r *AutoscalingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&platformv1alpha1.Application{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controllerOptions("application-autoscaling", 1)).
+		Named("application-autoscaling").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
+		Complete(r)
+}
@@ -0,0 +1,228 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/idle"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestAutoscalingTriggers(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(cpuTrigger(platformv1alpha1.AutoscalingTrigger{Type: platformv1alpha1.AutoscalingTriggerCPU, Target: 70})).To(Equal(kedaTrigger{
+		Type:       "cpu",
+		MetricType: "Utilization",
+		Metadata:   map[string]any{"value": "70"},
+	}))
+
+	app := &platformv1alpha1.Application{ObjectMeta: metav1.ObjectMeta{
+		Name:      "application-web",
+		Namespace: "project-ns",
+		Labels:    map[string]string{validation.LabelResourceUUID: "app-uuid"},
+	}}
+	rps := platformv1alpha1.AutoscalingTrigger{Type: platformv1alpha1.AutoscalingTriggerRequestsPerSecond, Target: 50}
+	metrics := config.MetricsConfig{PrometheusURL: "http://prometheus:9090", IngressProvider: config.IngressProviderNginx}
+	trigger, err := requestsPerSecondTrigger(rps, metrics, app)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(trigger.Type).To(Equal("prometheus"))
+	g.Expect(trigger.Metadata).To(Equal(map[string]any{
+		"serverAddress": "http://prometheus:9090",
+		"query":         `sum(rate(nginx_ingress_controller_requests{namespace="project-ns", service="service-app-uuid"}[2m]))`,
+		"threshold":     "50",
+	}))
+
+	// Request rates need the metrics of an ingress controller exporting them
+	metrics.IngressProvider = config.IngressProviderGateway
+	_, err = requestsPerSecondTrigger(rps, metrics, app)
+	g.Expect(err).To(MatchError(errInvalidAutoscalingTrigger))
+	_, err = requestsPerSecondTrigger(rps, config.MetricsConfig{}, app)
+	g.Expect(err).To(MatchError(ContainSubstring("needs traffic metrics")))
+
+	valkey := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "application-queue", Namespace: "project-ns", Labels: map[string]string{validation.LabelResourceUUID: "queue-uuid"}},
+		Spec:       platformv1alpha1.ApplicationSpec{Type: platformv1alpha1.ApplicationTypeValkey},
+	}
+	list := platformv1alpha1.AutoscalingTrigger{Type: platformv1alpha1.AutoscalingTriggerValkeyListLength, Target: 10, ListName: "jobs"}
+	trigger, err = databaseTrigger(list, valkey)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(trigger).To(Equal(kedaTrigger{
+		Type: "redis",
+		Metadata: map[string]any{
+			"address":       "valkey-queue-uuid.project-ns.svc.cluster.local:6379",
+			"listName":      "jobs",
+			"listLength":    "10",
+			"databaseIndex": "0",
+		},
+		Secret:     "valkey-queue-uuid",
+		SecretKeys: map[string]string{"password": "password"},
+	}))
+
+	valkey.Spec.Type = platformv1alpha1.ApplicationTypeValkeyCluster
+	trigger, err = databaseTrigger(list, valkey)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(trigger.Type).To(Equal("redis-cluster"))
+	g.Expect(trigger.Metadata).NotTo(HaveKey("databaseIndex"))
+
+	mysql := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "application-db", Namespace: "project-ns"},
+		Spec: platformv1alpha1.ApplicationSpec{
+			Type:  platformv1alpha1.ApplicationTypeMySQL,
+			MySQL: &platformv1alpha1.MySQLConfig{Slug: "orders", Database: "orders"},
+		},
+	}
+	query := platformv1alpha1.AutoscalingTrigger{Type: platformv1alpha1.AutoscalingTriggerMySQLQuery, Target: 100, Query: "SELECT COUNT(*) FROM jobs"}
+	trigger, err = databaseTrigger(query, mysql)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(trigger.Type).To(Equal("mysql"))
+	g.Expect(trigger.Metadata).To(HaveKeyWithValue("dbName", "orders"))
+	g.Expect(trigger.Metadata).To(HaveKeyWithValue("queryValue", "100"))
+	g.Expect(trigger.SecretKeys).To(Equal(map[string]string{"username": "rootUser", "password": "rootPassword"}))
+
+	// Triggers reference databases of their engine
+	_, err = databaseTrigger(list, mysql)
+	g.Expect(err).To(MatchError(ContainSubstring("valkeyListLength triggers need a Valkey application")))
+}
+
+func TestAutoscalingReconcile(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(autoscalingv2.AddToScheme(scheme)).To(Succeed())
+	for _, gvk := range []schema.GroupVersionKind{kedaScaledObjectGVK, kedaTriggerAuthenticationGVK} {
+		scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+	}
+
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-worker",
+			Namespace: "project-ns",
+			Labels:    map[string]string{validation.LabelResourceUUID: "app-uuid"},
+		},
+		Spec: platformv1alpha1.ApplicationSpec{
+			Type:                 platformv1alpha1.ApplicationTypeDockerImage,
+			CurrentDeploymentRef: &corev1.LocalObjectReference{Name: "deployment-dep-uuid"},
+			Autoscaling: &platformv1alpha1.Autoscaling{
+				MinReplicas: ptr.To[int32](0),
+				MaxReplicas: 10,
+				Triggers: []platformv1alpha1.AutoscalingTrigger{
+					{Type: platformv1alpha1.AutoscalingTriggerCPU, Target: 80},
+					{
+						Type:           platformv1alpha1.AutoscalingTriggerValkeyListLength,
+						Target:         5,
+						ApplicationRef: &corev1.LocalObjectReference{Name: "application-queue"},
+						ListName:       "jobs",
+					},
+				},
+			},
+		},
+	}
+	valkey := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "application-queue", Namespace: "project-ns", Labels: map[string]string{validation.LabelResourceUUID: "queue-uuid"}},
+		Spec:       platformv1alpha1.ApplicationSpec{Type: platformv1alpha1.ApplicationTypeValkey},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "deployment-dep-uuid",
+			Namespace:   "project-ns",
+			Labels:      map[string]string{validation.LabelApplicationUUID: "app-uuid", "app.kubernetes.io/component": "application"},
+			Annotations: map[string]string{idle.AnnotationIdleReplicas: "2"},
+		},
+		Spec:   appsv1.DeploymentSpec{Replicas: ptr.To[int32](0)},
+		Status: appsv1.DeploymentStatus{Replicas: 0},
+	}
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "keda-hpa-scaledobject-app-uuid", Namespace: "project-ns"},
+		Status:     autoscalingv2.HorizontalPodAutoscalerStatus{DesiredReplicas: 3},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(app, valkey, deployment, hpa).
+		WithStatusSubresource(&platformv1alpha1.Application{}).
+		Build()
+	r := &AutoscalingReconciler{Client: cl, Scheme: scheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "project-ns", Name: "application-worker"}}
+
+	result, err := r.Reconcile(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(autoscalingCheckInterval))
+
+	scaledObject := &unstructured.Unstructured{}
+	scaledObject.SetGroupVersionKind(kedaScaledObjectGVK)
+	g.Expect(cl.Get(ctx, client.ObjectKey{Namespace: "project-ns", Name: "scaledobject-app-uuid"}, scaledObject)).To(Succeed())
+	g.Expect(scaledObject.GetOwnerReferences()).To(HaveLen(1))
+	// The idle policy keeps the sleeping deployment at zero replicas
+	g.Expect(scaledObject.GetAnnotations()).To(HaveKeyWithValue(kedaPausedAnnotation, TrueString))
+	spec := scaledObject.Object["spec"].(map[string]any)
+	g.Expect(spec["scaleTargetRef"]).To(Equal(map[string]any{"name": "deployment-dep-uuid"}))
+	g.Expect(spec["minReplicaCount"]).To(BeEquivalentTo(0))
+	g.Expect(spec["maxReplicaCount"]).To(BeEquivalentTo(10))
+	triggers := spec["triggers"].([]any)
+	g.Expect(triggers).To(HaveLen(2))
+	g.Expect(triggers[0]).NotTo(HaveKey("authenticationRef"))
+	g.Expect(triggers[1]).To(HaveKeyWithValue("authenticationRef", map[string]any{"name": "scaledobject-app-uuid-1"}))
+
+	auth := &unstructured.Unstructured{}
+	auth.SetGroupVersionKind(kedaTriggerAuthenticationGVK)
+	g.Expect(cl.Get(ctx, client.ObjectKey{Namespace: "project-ns", Name: "scaledobject-app-uuid-1"}, auth)).To(Succeed())
+	g.Expect(auth.Object["spec"]).To(Equal(map[string]any{"secretTargetRef": []any{
+		map[string]any{"parameter": "password", "name": "valkey-queue-uuid", "key": "password"},
+	}}))
+
+	var current platformv1alpha1.Application
+	g.Expect(cl.Get(ctx, req.NamespacedName, &current)).To(Succeed())
+	g.Expect(current.Status.Autoscaling).To(Equal(&platformv1alpha1.AutoscalingStatus{
+		ScaledObject:    "scaledobject-app-uuid",
+		DesiredReplicas: 3,
+		Message:         "Waiting for KEDA to pick up the ScaledObject",
+	}))
+
+	// Removed triggers lose their TriggerAuthentication
+	current.Spec.Autoscaling.MinReplicas = nil
+	current.Spec.Autoscaling.Triggers = current.Spec.Autoscaling.Triggers[:1]
+	g.Expect(cl.Update(ctx, &current)).To(Succeed())
+	_, err = r.Reconcile(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(auth), auth)).NotTo(Succeed())
+
+	// Triggers referencing missing databases are reported on the status
+	g.Expect(cl.Get(ctx, req.NamespacedName, &current)).To(Succeed())
+	current.Spec.Autoscaling.Triggers = append(current.Spec.Autoscaling.Triggers, platformv1alpha1.AutoscalingTrigger{
+		Type: platformv1alpha1.AutoscalingTriggerMySQLQuery, Target: 1, ApplicationRef: &corev1.LocalObjectReference{Name: "application-db"}, Query: "SELECT 1",
+	})
+	g.Expect(cl.Update(ctx, &current)).To(Succeed())
+	_, err = r.Reconcile(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cl.Get(ctx, req.NamespacedName, &current)).To(Succeed())
+	g.Expect(current.Status.Autoscaling.Message).To(Equal("trigger 1: invalid autoscaling trigger: application application-db not found"))
+
+	// Removing autoscaling deletes the ScaledObject
+	current.Spec.Autoscaling = nil
+	g.Expect(cl.Update(ctx, &current)).To(Succeed())
+	_, err = r.Reconcile(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(scaledObject), scaledObject)).NotTo(Succeed())
+	g.Expect(cl.Get(ctx, req.NamespacedName, &current)).To(Succeed())
+	g.Expect(current.Status.Autoscaling).To(BeNil())
+}
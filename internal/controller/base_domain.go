@@ -0,0 +1,168 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ResolveBaseDomain returns the base domain used for an application's generated domains.
+// The Application spec.baseDomain wins over the owning Project's spec.baseDomain, which
+// wins over the operator-wide domain.
+func ResolveBaseDomain(ctx context.Context, c client.Reader, app *platformv1alpha1.Application) (string, error) {
+	if app.Spec.BaseDomain != "" {
+		return app.Spec.BaseDomain, nil
+	}
+
+	if projectUUID := app.Labels[validation.LabelProjectUUID]; projectUUID != "" {
+		var projects platformv1alpha1.ProjectList
+		if err := c.List(ctx, &projects, client.MatchingLabels{validation.LabelResourceUUID: projectUUID}); err != nil {
+			return "", fmt.Errorf("failed to list projects: %w", err)
+		}
+		if len(projects.Items) > 0 && projects.Items[0].Spec.BaseDomain != "" {
+			return projects.Items[0].Spec.BaseDomain, nil
+		}
+	}
+
+	opConfig, err := GetOperatorConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to get operator configuration: %w", err)
+	}
+	return opConfig.Domain, nil
+}
+
+// customBaseDomain returns the base domain of a generated <subdomain>.apps.<baseDomain> domain
+// when it differs from the operator domain, or "" when the domain uses the operator domain.
+func customBaseDomain(domain, operatorDomain string) string {
+	_, base, found := strings.Cut(domain, ".apps.")
+	if !found || base == operatorDomain {
+		return ""
+	}
+	return base
+}
+
+// baseDomainCertificateName returns the wildcard Certificate (and Secret) name for a custom base domain
+func baseDomainCertificateName(baseDomain string) string {
+	return fmt.Sprintf("ingress-%s-certificate", strings.ReplaceAll(baseDomain, ".", "-"))
+}
+
+// baseDomainListenerName returns the Gateway HTTPS listener name serving a custom base domain
+func baseDomainListenerName(baseDomain string) string {
+	return fmt.Sprintf("https-%s", strings.ReplaceAll(baseDomain, ".", "-"))
+}
+
+// ensureBaseDomainCertificate ensures the *.apps.<baseDomain> wildcard Certificate exists in the
+// kibaship namespace. The ClusterIssuer solves DNS-01 challenges through acme-dns, so the customer
+// must delegate _acme-challenge.apps.<baseDomain> the same way as for custom domains. Only base
+// domains the operator base domain policy allows are provisioned.
+func ensureBaseDomainCertificate(ctx context.Context, c client.Client, baseDomain string) (string, error) {
+	log := ctrl.LoggerFrom(ctx)
+	certName := baseDomainCertificateName(baseDomain)
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"})
+	if err := c.Get(ctx, client.ObjectKey{Namespace: certificatesNamespace, Name: certName}, obj); err == nil {
+		return certName, nil
+	} else if !errors.IsNotFound(err) {
+		return "", err
+	}
+	// The webhooks check the policy too, resources admitted before it changed are not provisioned
+	if err := platformv1alpha1.CurrentBaseDomainPolicy().Check(baseDomain); err != nil {
+		return "", err
+	}
+
+	obj.SetNamespace(certificatesNamespace)
+	obj.SetName(certName)
+	obj.SetLabels(map[string]string{
+		"app.kubernetes.io/managed-by":      "kibaship",
+		"platform.kibaship.com/base-domain": baseDomain,
+	})
+	obj.Object["spec"] = map[string]any{
+		"secretName": certName,
+		"issuerRef":  map[string]any{"name": clusterIssuerName, "kind": "ClusterIssuer"},
+		"dnsNames":   []any{fmt.Sprintf("*.apps.%s", baseDomain)},
+	}
+	if err := c.Create(ctx, obj); err != nil {
+		return "", err
+	}
+
+	log.Info("Created wildcard certificate for custom base domain", "certificate", certName, "baseDomain", baseDomain)
+	return certName, nil
+}
+
+// ensureBaseDomainGatewayListener adds an HTTPS listener for *.apps.<baseDomain> to the kibaship
+// Gateway, terminating TLS with the base domain wildcard certificate. Like the certificate, it is
+// only added for base domains the operator base domain policy allows.
+func ensureBaseDomainGatewayListener(ctx context.Context, c client.Client, baseDomain string) error {
+	log := ctrl.LoggerFrom(ctx)
+	listenerName := baseDomainListenerName(baseDomain)
+
+	gateway := &unstructured.Unstructured{}
+	gateway.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"})
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ingressGatewayNamespace, Name: ingressGatewayName}, gateway); err != nil {
+		return fmt.Errorf("failed to get Gateway: %w", err)
+	}
+
+	listeners, _, err := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+	if err != nil {
+		return fmt.Errorf("failed to read Gateway listeners: %w", err)
+	}
+	for _, listener := range listeners {
+		if m, ok := listener.(map[string]any); ok && m["name"] == listenerName {
+			return nil
+		}
+	}
+	if err := platformv1alpha1.CurrentBaseDomainPolicy().Check(baseDomain); err != nil {
+		return err
+	}
+
+	listeners = append(listeners, map[string]any{
+		"name":     listenerName,
+		"protocol": "HTTPS",
+		"port":     int64(443),
+		"hostname": fmt.Sprintf("*.apps.%s", baseDomain),
+		"tls": map[string]any{
+			"mode": "Terminate",
+			"certificateRefs": []any{
+				map[string]any{"name": baseDomainCertificateName(baseDomain)},
+			},
+		},
+		"allowedRoutes": map[string]any{
+			"namespaces": map[string]any{"from": "All"},
+		},
+	})
+	if err := unstructured.SetNestedSlice(gateway.Object, listeners, "spec", "listeners"); err != nil {
+		return fmt.Errorf("failed to set Gateway listeners: %w", err)
+	}
+	if err := c.Update(ctx, gateway); err != nil {
+		return fmt.Errorf("failed to update Gateway: %w", err)
+	}
+
+	log.Info("Added Gateway listener for custom base domain", "listener", listenerName, "baseDomain", baseDomain)
+	return nil
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestBaseDomainPolicyWebhooks(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	t.Cleanup(func() { platformv1alpha1.SetBaseDomainPolicy(config.BaseDomainPolicy{}) })
+	platformv1alpha1.SetBaseDomainPolicy(config.BaseDomainPolicy{Domain: "kibaship.example.com", Allowed: []string{"customer.com"}})
+
+	project := &platformv1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "project-shop",
+			Labels: map[string]string{
+				validation.LabelResourceUUID: "550e8400-e29b-41d4-a716-446655440000",
+				validation.LabelResourceSlug: "shop",
+			},
+		},
+		Spec: platformv1alpha1.ProjectSpec{BaseDomain: "eu.customer.com"},
+	}
+	_, err := project.ValidateCreate(ctx, project)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Tenants cannot claim domains the platform operator did not allow
+	previous := project.DeepCopy()
+	project.Spec.BaseDomain = "bank.com"
+	_, err = project.ValidateUpdate(ctx, previous, project)
+	g.Expect(err).To(MatchError(ContainSubstring("base domain bank.com is not allowed")))
+
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-550e8400-e29b-41d4-a716-446655440032",
+			Namespace: "default",
+			Labels: map[string]string{
+				validation.LabelResourceUUID:    "550e8400-e29b-41d4-a716-446655440032",
+				validation.LabelResourceSlug:    "web",
+				validation.LabelEnvironmentUUID: "550e8400-e29b-41d4-a716-446655440010",
+				validation.LabelProjectUUID:     "550e8400-e29b-41d4-a716-446655440000",
+			},
+		},
+		Spec: platformv1alpha1.ApplicationSpec{
+			EnvironmentRef: corev1.LocalObjectReference{Name: "environment-production"},
+			Type:           platformv1alpha1.ApplicationTypeDockerImage,
+			DockerImage:    &platformv1alpha1.DockerImageConfig{Image: "nginx:1.27"},
+			BaseDomain:     "bank.com",
+		},
+	}
+	_, err = app.ValidateCreate(ctx, app)
+	g.Expect(err).To(MatchError(ContainSubstring("use one of customer.com")))
+
+	// Resources admitted before the policy changed keep their base domain
+	platformv1alpha1.SetBaseDomainPolicy(config.BaseDomainPolicy{Domain: "kibaship.example.com"})
+	app.Spec.BaseDomain = "customer.com"
+	_, err = app.ValidateUpdate(ctx, app.DeepCopy(), app)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestBaseDomainProvisioningRequiresPolicy(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	t.Cleanup(func() { platformv1alpha1.SetBaseDomainPolicy(config.BaseDomainPolicy{}) })
+	platformv1alpha1.SetBaseDomainPolicy(config.BaseDomainPolicy{Domain: "kibaship.example.com"})
+
+	// Nothing is provisioned for base domains the policy does not allow
+	c := fake.NewClientBuilder().Build()
+	_, err := ensureBaseDomainCertificate(ctx, c, "bank.com")
+	g.Expect(err).To(MatchError(ContainSubstring("base domain bank.com is not allowed")))
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/pipelines"
+	"github.com/kibamail/kibaship/pkg/utils"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// buildEnvEnabled reports whether the builds of a deployment receive the build environment
+// variables of its application. Pipelines generated before the build environment existed do not
+// declare its workspace.
+func buildEnvEnabled(deployment *platformv1alpha1.Deployment, app *platformv1alpha1.Application) bool {
+	gitConfig := app.Spec.GitRepository
+	return gitConfig != nil && gitConfig.BuildEnvSecretRef != nil &&
+		pipelines.SupportsBuildEnv(pipelines.SpecVersion(deployment), gitConfig.BuildType)
+}
+
+// ensureBuildEnvSecret copies the build environment variables of the application, with their
+// templated values resolved, from the secret it references into a secret owned by the deployment.
+// Like the env secret of the deployment it is not updated afterwards, rebuilds of the deployment
+// use the variables it was created with.
+func (r *DeploymentReconciler) ensureBuildEnvSecret(ctx context.Context, deployment *platformv1alpha1.Deployment, app *platformv1alpha1.Application) error {
+	if !buildEnvEnabled(deployment, app) {
+		return nil
+	}
+
+	secretName := utils.GetBuildEnvSecretName(deployment.GetUUID())
+	existing := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: deployment.Namespace}, existing)
+	if err == nil {
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to check for existing build env secret: %w", err)
+	}
+
+	source := &corev1.Secret{}
+	sourceName := app.Spec.GitRepository.BuildEnvSecretRef.Name
+	if err := r.Get(ctx, types.NamespacedName{Name: sourceName, Namespace: app.Namespace}, source); err != nil {
+		return fmt.Errorf("failed to get build env secret %s of the application: %w", sourceName, err)
+	}
+
+	data := make(map[string][]byte, len(source.Data))
+	for name, value := range source.Data {
+		data[name] = value
+	}
+	templated, err := resolveEnvTemplates(ctx, r.Client, app, data)
+	if err != nil {
+		return err
+	}
+	for name, value := range templated {
+		data[name] = []byte(value)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: deployment.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by":           "kibaship",
+				"platform.kibaship.com/deployment-uuid":  deployment.GetUUID(),
+				"platform.kibaship.com/application-uuid": app.GetUUID(),
+				"platform.kibaship.com/project-uuid":     deployment.GetProjectUUID(),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+	if err := controllerutil.SetControllerReference(deployment, secret, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference on build env secret: %w", err)
+	}
+	if err := r.Create(ctx, secret); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create build env secret: %w", err)
+	}
+
+	logf.FromContext(ctx).Info("Created build env secret", "secretName", secretName, "variables", len(data))
+	return nil
+}
+
+// getBuildEnvWorkspaceBinding returns the workspace binding of the build env secret of a
+// deployment, nil when its builds do not receive build environment variables
+func getBuildEnvWorkspaceBinding(deployment *platformv1alpha1.Deployment, app *platformv1alpha1.Application) *tektonv1.WorkspaceBinding {
+	if !buildEnvEnabled(deployment, app) {
+		return nil
+	}
+	return &tektonv1.WorkspaceBinding{
+		Name: pipelines.WorkspaceBuildEnv,
+		Secret: &corev1.SecretVolumeSource{
+			SecretName: utils.GetBuildEnvSecretName(deployment.GetUUID()),
+		},
+	}
+}
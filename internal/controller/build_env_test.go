@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/pipelines"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestEnsureBuildEnvSecret(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := remoteBuildsScheme(g)
+
+	deployment := &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      "deployment-dep-1",
+		Namespace: "project-ns",
+		UID:       "dep-1-uid",
+		Labels:    map[string]string{validation.LabelResourceUUID: "dep-1"},
+	}}
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "application-app-1", Namespace: "project-ns"},
+		Spec: platformv1alpha1.ApplicationSpec{
+			GitRepository: &platformv1alpha1.GitRepositoryConfig{
+				Provider:          platformv1alpha1.GitProviderGitHub,
+				Repository:        "org/repo",
+				BuildEnvSecretRef: &corev1.LocalObjectReference{Name: "application-app-1-build-env"},
+			},
+		},
+	}
+	buildEnv := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "application-app-1-build-env", Namespace: "project-ns"},
+		Data:       map[string][]byte{"NPM_TOKEN": []byte("npm_secret"), "NODE_OPTIONS": []byte("--max-old-space-size=4096")},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment, buildEnv).Build()
+	r := &DeploymentReconciler{Client: c, Scheme: scheme}
+
+	g.Expect(r.ensureBuildEnvSecret(ctx, deployment, app)).To(Succeed())
+	var secret corev1.Secret
+	g.Expect(c.Get(ctx, types.NamespacedName{Name: "deployment-dep-1-build-env", Namespace: "project-ns"}, &secret)).To(Succeed())
+	g.Expect(secret.Data).To(HaveKeyWithValue("NPM_TOKEN", []byte("npm_secret")))
+	g.Expect(secret.OwnerReferences).To(HaveLen(1))
+
+	binding := getBuildEnvWorkspaceBinding(deployment, app)
+	g.Expect(binding).NotTo(BeNil())
+	g.Expect(binding.Name).To(Equal(pipelines.WorkspaceBuildEnv))
+	g.Expect(binding.Secret.SecretName).To(Equal("deployment-dep-1-build-env"))
+
+	// Later changes do not reach the builds of existing deployments
+	buildEnv.Data = map[string][]byte{"NPM_TOKEN": []byte("rotated")}
+	g.Expect(c.Update(ctx, buildEnv)).To(Succeed())
+	g.Expect(r.ensureBuildEnvSecret(ctx, deployment, app)).To(Succeed())
+	g.Expect(c.Get(ctx, types.NamespacedName{Name: "deployment-dep-1-build-env", Namespace: "project-ns"}, &secret)).To(Succeed())
+	g.Expect(secret.Data).To(HaveKeyWithValue("NPM_TOKEN", []byte("npm_secret")))
+
+	// New deployments fail loudly when the referenced secret is gone
+	other := &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      "deployment-dep-2",
+		Namespace: "project-ns",
+		UID:       "dep-2-uid",
+		Labels:    map[string]string{validation.LabelResourceUUID: "dep-2"},
+	}}
+	g.Expect(c.Delete(ctx, buildEnv)).To(Succeed())
+	g.Expect(r.ensureBuildEnvSecret(ctx, other, app)).To(MatchError(ContainSubstring("application-app-1-build-env")))
+
+	// Pipelines of older spec versions do not declare the workspace
+	deployment.Annotations = map[string]string{pipelines.AnnotationSpecVersion: pipelines.SpecVersionV2}
+	g.Expect(getBuildEnvWorkspaceBinding(deployment, app)).To(BeNil())
+}
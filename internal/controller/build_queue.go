@@ -0,0 +1,253 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+const (
+	// DeploymentConditionBuildQueued reports whether the build of a deployment waits for the build
+	// concurrency limit of its project
+	DeploymentConditionBuildQueued = "BuildQueued"
+
+	// pipelineRunComponent labels the PipelineRuns of deployments
+	pipelineRunComponent = "ci-cd-pipeline-run"
+
+	// buildQueueRequeueInterval is how often a queued build checks for a free build slot
+	buildQueueRequeueInterval = 15 * time.Second
+
+	// defaultBuildDuration estimates the builds of projects without finished builds
+	defaultBuildDuration = 5 * time.Minute
+
+	// buildDurationSamples is how many recent builds the build duration estimate averages
+	buildDurationSamples = 10
+)
+
+// buildConcurrencyLimit returns how many builds a project runs at once, zero when unlimited
+func buildConcurrencyLimit() int {
+	cfg := buildScheduling.Load()
+	if cfg == nil {
+		return 0
+	}
+	return cfg.MaxConcurrentPerProject
+}
+
+// waitForBuildSlot holds the build of a deployment while its project runs as many builds as the
+// build concurrency limit allows. Queued builds start in the order their deployments were
+// created and report their queue position and estimated start on the deployment status. Once
+// released, the deployment is not queued again. The limit is soft: builds released by concurrent
+// reconciles may briefly exceed it.
+func (r *DeploymentReconciler) waitForBuildSlot(ctx context.Context, deployment *platformv1alpha1.Deployment) (bool, error) {
+	limit := buildConcurrencyLimit()
+	if limit == 0 || deployment.Spec.GitRepository == nil || deployment.PrebuiltImage() != "" {
+		return false, nil
+	}
+	queued := meta.FindStatusCondition(deployment.Status.Conditions, DeploymentConditionBuildQueued)
+	if queued != nil && queued.Status == metav1.ConditionFalse {
+		return false, nil
+	}
+	if queued == nil {
+		// Builds that started before the limit was configured are never queued
+		started, err := r.buildStarted(ctx, deployment)
+		if err != nil || started {
+			return false, err
+		}
+	}
+
+	projectUUID := deployment.GetProjectUUID()
+	// Builds dispatched to the build namespace pool count against the limit of their project alike
+	builds := client.Reader(r.Client)
+	if r.RemoteBuilds != nil {
+		builds = r.RemoteBuilds.Client
+	}
+	var pipelineRuns tektonv1.PipelineRunList
+	if err := builds.List(ctx, &pipelineRuns, client.MatchingLabels{
+		validation.LabelProjectUUID:   projectUUID,
+		"app.kubernetes.io/component": pipelineRunComponent,
+	}); err != nil {
+		return false, fmt.Errorf("failed to list project builds: %w", err)
+	}
+	running, average := summarizeBuilds(pipelineRuns.Items)
+
+	var deployments platformv1alpha1.DeploymentList
+	if err := r.List(ctx, &deployments, client.MatchingLabels{validation.LabelProjectUUID: projectUUID}); err != nil {
+		return false, fmt.Errorf("failed to list project deployments: %w", err)
+	}
+	queue := []*platformv1alpha1.Deployment{deployment}
+	for i := range deployments.Items {
+		other := &deployments.Items[i]
+		if other.UID != deployment.UID && other.DeletionTimestamp == nil &&
+			meta.IsStatusConditionTrue(other.Status.Conditions, DeploymentConditionBuildQueued) {
+			queue = append(queue, other)
+		}
+	}
+	slices.SortFunc(queue, func(a, b *platformv1alpha1.Deployment) int {
+		if c := a.CreationTimestamp.Compare(b.CreationTimestamp.Time); c != 0 {
+			return c
+		}
+		return compareStrings(a.Name, b.Name)
+	})
+	position := slices.Index(queue, deployment) + 1
+
+	status := deployment.Status.DeepCopy()
+	condition := metav1.Condition{
+		Type:    DeploymentConditionBuildQueued,
+		Status:  metav1.ConditionFalse,
+		Reason:  "BuildStarted",
+		Message: "A build slot of the project is free",
+	}
+	waiting := len(running)+position > limit
+	if waiting {
+		now := currentTime(r.Clock)
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ConcurrencyLimitReached"
+		condition.Message = fmt.Sprintf("Build %d in the project queue, %d of %d builds running", position, len(running), limit)
+		estimatedStart := metav1.NewTime(estimateBuildStart(now, running, average, limit, position).Truncate(time.Second))
+		status.QueuePosition = int32(position)
+		status.EstimatedStart = &estimatedStart
+	} else {
+		status.QueuePosition = 0
+		status.EstimatedStart = nil
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	if !equalBuildQueueStatus(&deployment.Status, status) {
+		deployment.Status = *status
+		if err := r.Status().Update(ctx, deployment); err != nil {
+			return false, fmt.Errorf("failed to update build queue status: %w", err)
+		}
+	}
+
+	if waiting {
+		logf.FromContext(ctx).Info("Build queued by the project build concurrency limit",
+			"position", position, "running", len(running), "limit", limit)
+	}
+	return waiting, nil
+}
+
+// buildStarted reports whether the PipelineRun of the current generation of a deployment exists
+func (r *DeploymentReconciler) buildStarted(ctx context.Context, deployment *platformv1alpha1.Deployment) (bool, error) {
+	pipelineRun, err := r.getPipelineRun(ctx, deployment)
+	if err != nil {
+		return false, fmt.Errorf("failed to get PipelineRun: %w", err)
+	}
+	return pipelineRun != nil, nil
+}
+
+// summarizeBuilds returns the start times of the running builds among pipelineRuns and the
+// average duration of the most recent finished ones
+func summarizeBuilds(pipelineRuns []tektonv1.PipelineRun) ([]time.Time, time.Duration) {
+	var running []time.Time
+	var finished []*tektonv1.PipelineRun
+	for i := range pipelineRuns {
+		pipelineRun := &pipelineRuns[i]
+		condition := pipelineRun.Status.GetCondition("Succeeded")
+		if condition == nil || condition.Status == corev1.ConditionUnknown {
+			started := pipelineRun.CreationTimestamp.Time
+			if pipelineRun.Status.StartTime != nil {
+				started = pipelineRun.Status.StartTime.Time
+			}
+			running = append(running, started)
+			continue
+		}
+		if pipelineRun.Status.StartTime != nil && pipelineRun.Status.CompletionTime != nil {
+			finished = append(finished, pipelineRun)
+		}
+	}
+
+	if len(finished) == 0 {
+		return running, defaultBuildDuration
+	}
+	slices.SortFunc(finished, func(a, b *tektonv1.PipelineRun) int {
+		return b.Status.CompletionTime.Compare(a.Status.CompletionTime.Time)
+	})
+	finished = finished[:min(len(finished), buildDurationSamples)]
+
+	var total time.Duration
+	for _, pipelineRun := range finished {
+		total += pipelineRun.Status.CompletionTime.Sub(pipelineRun.Status.StartTime.Time)
+	}
+	return running, total / time.Duration(len(finished))
+}
+
+// estimateBuildStart estimates when the build at position of the queue starts. Every running
+// build is expected to take the average build duration, and so is every build ahead in the
+// queue once it takes a free slot.
+func estimateBuildStart(now time.Time, running []time.Time, average time.Duration, limit, position int) time.Time {
+	ends := make([]time.Time, 0, len(running)+position)
+	for _, started := range running {
+		ends = append(ends, laterTime(started.Add(average), now))
+	}
+
+	start := now
+	for range position {
+		slices.SortFunc(ends, func(a, b time.Time) int { return a.Compare(b) })
+		if len(ends) >= limit {
+			// The build starts once all but limit-1 of the builds ahead of it finished
+			start = laterTime(start, ends[len(ends)-limit])
+			ends = ends[len(ends)-limit+1:]
+		}
+		ends = append(ends, start.Add(average))
+	}
+	return start
+}
+
+// equalBuildQueueStatus reports whether applying the build queue state of b leaves a unchanged
+func equalBuildQueueStatus(a, b *platformv1alpha1.DeploymentStatus) bool {
+	if a.QueuePosition != b.QueuePosition || !a.EstimatedStart.Equal(b.EstimatedStart) {
+		return false
+	}
+	ca := meta.FindStatusCondition(a.Conditions, DeploymentConditionBuildQueued)
+	cb := meta.FindStatusCondition(b.Conditions, DeploymentConditionBuildQueued)
+	if ca == nil || cb == nil {
+		return ca == cb
+	}
+	return ca.Status == cb.Status && ca.Reason == cb.Reason && ca.Message == cb.Message
+}
+
+// laterTime returns the later of a and b
+func laterTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// compareStrings orders strings lexically
+func compareStrings(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/validation"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+func TestEstimateBuildStart(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC)
+	average := 10 * time.Minute
+
+	// One slot, the running build finishes in 4 minutes
+	running := []time.Time{now.Add(-6 * time.Minute)}
+	g.Expect(estimateBuildStart(now, running, average, 1, 1)).To(Equal(now.Add(4 * time.Minute)))
+	g.Expect(estimateBuildStart(now, running, average, 1, 2)).To(Equal(now.Add(14 * time.Minute)))
+
+	// Two slots, builds finishing in 4 and 8 minutes
+	running = []time.Time{now.Add(-6 * time.Minute), now.Add(-2 * time.Minute)}
+	g.Expect(estimateBuildStart(now, running, average, 2, 1)).To(Equal(now.Add(4 * time.Minute)))
+	g.Expect(estimateBuildStart(now, running, average, 2, 2)).To(Equal(now.Add(8 * time.Minute)))
+	g.Expect(estimateBuildStart(now, running, average, 2, 3)).To(Equal(now.Add(14 * time.Minute)))
+
+	// A lowered limit waits for the builds over the limit to finish too
+	g.Expect(estimateBuildStart(now, running, average, 1, 1)).To(Equal(now.Add(8 * time.Minute)))
+
+	// Builds running longer than the average are expected to finish any moment
+	running = []time.Time{now.Add(-time.Hour)}
+	g.Expect(estimateBuildStart(now, running, average, 1, 1)).To(Equal(now))
+}
+
+func TestSummarizeBuilds(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC)
+
+	running, average := summarizeBuilds(nil)
+	g.Expect(running).To(BeEmpty())
+	g.Expect(average).To(Equal(defaultBuildDuration))
+
+	finished := func(minutes int) tektonv1.PipelineRun {
+		var pipelineRun tektonv1.PipelineRun
+		pipelineRun.Status.MarkSucceeded("Succeeded", "All Tasks have completed executing")
+		pipelineRun.Status.StartTime = &metav1.Time{Time: now.Add(-time.Hour)}
+		pipelineRun.Status.CompletionTime = &metav1.Time{Time: now.Add(-time.Hour + time.Duration(minutes)*time.Minute)}
+		return pipelineRun
+	}
+	var started tektonv1.PipelineRun
+	started.Status.StartTime = &metav1.Time{Time: now.Add(-time.Minute)}
+
+	running, average = summarizeBuilds([]tektonv1.PipelineRun{finished(4), started, finished(8)})
+	g.Expect(running).To(ConsistOf(now.Add(-time.Minute)))
+	g.Expect(average).To(Equal(6 * time.Minute))
+}
+
+func TestWaitForBuildSlot(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC)
+
+	previous := buildScheduling.Load()
+	t.Cleanup(func() { buildScheduling.Store(previous) })
+	SetBuildScheduling(config.BuildsConfig{MaxConcurrentPerProject: 1})
+
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(tektonv1.AddToScheme(scheme)).To(Succeed())
+
+	newDeployment := func(name string, created time.Time) *platformv1alpha1.Deployment {
+		return &platformv1alpha1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				UID:               types.UID(name + "-uid"),
+				Generation:        1,
+				CreationTimestamp: metav1.NewTime(created),
+				Labels: map[string]string{
+					validation.LabelResourceUUID: name,
+					validation.LabelProjectUUID:  "project-1",
+				},
+			},
+			Spec: platformv1alpha1.DeploymentSpec{
+				ApplicationRef: corev1.LocalObjectReference{Name: "application-web"},
+				GitRepository:  &platformv1alpha1.GitRepositoryDeploymentConfig{CommitSHA: "abc123"},
+			},
+		}
+	}
+	first := newDeployment("first", now.Add(-2*time.Minute))
+	second := newDeployment("second", now.Add(-time.Minute))
+
+	build := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name:      "pipeline-run-running-1",
+		Namespace: "default",
+		Labels: map[string]string{
+			validation.LabelProjectUUID:   "project-1",
+			"app.kubernetes.io/component": pipelineRunComponent,
+		},
+	}}
+	build.Status.StartTime = &metav1.Time{Time: now.Add(-3 * time.Minute)}
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(first, second, build).
+		WithStatusSubresource(&platformv1alpha1.Deployment{}, &tektonv1.PipelineRun{}).
+		Build()
+	r := &DeploymentReconciler{Client: cl, Scheme: scheme, Clock: clocktesting.NewFakePassiveClock(now)}
+
+	waitFor := func(deployment *platformv1alpha1.Deployment) (bool, *platformv1alpha1.Deployment) {
+		current := &platformv1alpha1.Deployment{}
+		g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(deployment), current)).To(Succeed())
+		waiting, err := r.waitForBuildSlot(ctx, current)
+		g.Expect(err).NotTo(HaveOccurred())
+		return waiting, current
+	}
+
+	// The running build holds the only slot, the deployments queue in creation order
+	waiting, deployment := waitFor(second)
+	g.Expect(waiting).To(BeTrue())
+	g.Expect(deployment.Status.QueuePosition).To(Equal(int32(1)))
+
+	waiting, deployment = waitFor(first)
+	g.Expect(waiting).To(BeTrue())
+	g.Expect(deployment.Status.QueuePosition).To(Equal(int32(1)))
+	g.Expect(deployment.Status.EstimatedStart.Time).To(BeTemporally("==", now.Add(2*time.Minute)))
+	g.Expect(meta.IsStatusConditionTrue(deployment.Status.Conditions, DeploymentConditionBuildQueued)).To(BeTrue())
+
+	waiting, deployment = waitFor(second)
+	g.Expect(waiting).To(BeTrue())
+	g.Expect(deployment.Status.QueuePosition).To(Equal(int32(2)))
+	g.Expect(deployment.Status.EstimatedStart.Time).To(BeTemporally("==", now.Add(7*time.Minute)))
+
+	// The running build finishes, the first deployment takes the slot
+	build.Status.MarkSucceeded("Succeeded", "All Tasks have completed executing")
+	build.Status.CompletionTime = &metav1.Time{Time: now}
+	g.Expect(cl.Status().Update(ctx, build)).To(Succeed())
+
+	waiting, deployment = waitFor(first)
+	g.Expect(waiting).To(BeFalse())
+	g.Expect(deployment.Status.QueuePosition).To(BeZero())
+	g.Expect(deployment.Status.EstimatedStart).To(BeNil())
+	condition := meta.FindStatusCondition(deployment.Status.Conditions, DeploymentConditionBuildQueued)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+
+	// A released deployment is never queued again
+	waiting, _ = waitFor(first)
+	g.Expect(waiting).To(BeFalse())
+
+	// Without a limit nothing queues
+	SetBuildScheduling(config.BuildsConfig{})
+	third := newDeployment("third", now)
+	g.Expect(cl.Create(ctx, third)).To(Succeed())
+	waiting, deployment = waitFor(third)
+	g.Expect(waiting).To(BeFalse())
+	g.Expect(deployment.Status.Conditions).To(BeEmpty())
+}
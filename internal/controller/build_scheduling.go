@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// buildScheduling holds the build node pool of the operator configuration
+var buildScheduling atomic.Pointer[config.BuildsConfig]
+
+// SetBuildScheduling replaces the build node pool applied to new PipelineRuns.
+// It is called at startup and whenever the operator ConfigMap changes.
+func SetBuildScheduling(cfg config.BuildsConfig) {
+	buildScheduling.Store(&cfg)
+}
+
+// ResolveBuildScheduling returns the node selector and tolerations of the build pods of app.
+// The buildScheduling of the application replaces the operator build node pool as a whole.
+func ResolveBuildScheduling(app *platformv1alpha1.Application) (map[string]string, []corev1.Toleration) {
+	if app != nil && app.Spec.GitRepository != nil && app.Spec.GitRepository.BuildScheduling != nil {
+		scheduling := app.Spec.GitRepository.BuildScheduling.DeepCopy()
+		return scheduling.NodeSelector, scheduling.Tolerations
+	}
+
+	cfg := buildScheduling.Load()
+	if cfg == nil {
+		return nil, nil
+	}
+	scheduling := (&platformv1alpha1.BuildSchedulingConfig{NodeSelector: cfg.NodeSelector, Tolerations: cfg.Tolerations}).DeepCopy()
+	return scheduling.NodeSelector, scheduling.Tolerations
+}
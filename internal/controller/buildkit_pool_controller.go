@@ -0,0 +1,625 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/pipelines"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+const (
+	// BuildKitNamespace is the namespace of the BuildKit daemons
+	BuildKitNamespace = "buildkit"
+
+	// LabelBuildKitBuilder names the daemon of the BuildKit pool a PipelineRun was routed to,
+	// and the daemon of BuildKit pool Deployments and Services
+	LabelBuildKitBuilder = "platform.kibaship.com/buildkit-builder"
+
+	// AnnotationBuildKitDraining marks daemons of the pool no new build is routed to, before
+	// they are removed or restarted
+	AnnotationBuildKitDraining = "platform.kibaship.com/draining"
+
+	// annotationBuildKitTemplateHash records the pod template a daemon of the pool was last
+	// rolled to, including the registry CA certificate it trusts
+	annotationBuildKitTemplateHash = "platform.kibaship.com/template-hash"
+
+	// annotationRegistryCAHash restarts the pods of daemons when the registry CA rotates
+	annotationRegistryCAHash = "platform.kibaship.com/registry-ca-hash"
+
+	// buildKitPoolName is the single request every watched resource maps to
+	buildKitPoolName = "buildkit-pool"
+
+	// buildKitBuilderComponent labels the Deployments and Services of the BuildKit pool
+	buildKitBuilderComponent = "buildkit-builder"
+
+	// buildKitBuilderPrefix prefixes the names of the daemons of the pool, suffixed by their index
+	buildKitBuilderPrefix = "buildkitd-"
+
+	// buildKitImage runs the daemons of the pool, the image of the shared buildkitd Deployment
+	buildKitImage = "moby/buildkit:v0.24.0-rootless"
+
+	// buildKitPort is the TCP port the daemons listen on
+	buildKitPort = 1234
+
+	// buildKitCASecret holds the registry CA certificate in the BuildKit namespace
+	buildKitCASecret = "registry-ca-cert"
+
+	// buildKitPoolRequeueInterval is how often the pool is resized and its daemons checked
+	buildKitPoolRequeueInterval = 30 * time.Second
+)
+
+// registryTLSSecret holds the registry certificate and the CA certificate that signed it
+var registryTLSSecret = types.NamespacedName{Namespace: "registry", Name: "registry-tls"}
+
+// buildKitPool holds the BuildKit pool of the operator configuration
+var buildKitPool atomic.Pointer[config.BuildKitConfig]
+
+// SetBuildKitPool replaces the BuildKit pool settings. It is called at startup and whenever
+// the operator ConfigMap changes.
+func SetBuildKitPool(cfg config.BuildKitConfig) {
+	buildKitPool.Store(&cfg)
+}
+
+// BuildKitPoolReconciler manages a pool of BuildKit daemons in the buildkit namespace, one
+// single-replica Deployment and Service per daemon. The pool grows with the running builds
+// between its configured bounds. Daemons leaving the pool, or restarting for a new registry CA
+// certificate or template, first drain: no new build is routed to them, and they are removed
+// or restarted once their builds finish, one daemon at a time.
+type BuildKitPoolReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=tekton.dev,resources=pipelineruns,verbs=get;list;watch
+
+// Reconcile resizes the BuildKit pool to the running builds and rolls stale daemons
+func (r *BuildKitPoolReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var namespace corev1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: BuildKitNamespace}, &namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(1).Info("BuildKit namespace does not exist, skipping the BuildKit pool")
+			return ctrl.Result{RequeueAfter: buildKitPoolRequeueInterval}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	caHash, err := r.syncRegistryCA(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	builders, err := listBuildKitBuilders(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	load, running, err := buildKitLoad(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	cfg := config.BuildKitConfig{}
+	if current := buildKitPool.Load(); current != nil {
+		cfg = *current
+	}
+	desired := desiredBuildKitBuilders(cfg, running)
+
+	existing := map[int]*appsv1.Deployment{}
+	for _, deployment := range builders {
+		existing[buildKitBuilderIndex(deployment.Name)] = deployment
+	}
+
+	// Grow the pool
+	for index := range desired {
+		if _, ok := existing[index]; ok {
+			continue
+		}
+		name := buildKitBuilderName(index)
+		if err := r.createBuilder(ctx, name, caHash); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("Added BuildKit daemon to the pool", "builder", name, "runningBuilds", running, "poolSize", desired)
+	}
+
+	// Shrink the pool, daemons leave once their builds finished
+	for index, deployment := range existing {
+		if index < desired {
+			continue
+		}
+		if load[deployment.Name] > 0 {
+			if err := r.setDraining(ctx, deployment, true); err != nil {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+		if err := r.Delete(ctx, deployment); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to remove BuildKit daemon %s: %w", deployment.Name, err)
+		}
+		log.Info("Removed idle BuildKit daemon from the pool", "builder", deployment.Name, "poolSize", desired)
+	}
+
+	if err := r.rollStaleBuilder(ctx, existing, desired, load, caHash); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: buildKitPoolRequeueInterval}, nil
+}
+
+// rollStaleBuilder restarts, one at a time, the daemons of the pool running an outdated
+// template. A stale daemon drains first and restarts once idle, and no daemon drains while
+// another one is unhealthy.
+func (r *BuildKitPoolReconciler) rollStaleBuilder(ctx context.Context, existing map[int]*appsv1.Deployment, desired int, load map[string]int, caHash string) error {
+	var stale []*appsv1.Deployment
+	for index := range desired {
+		deployment, ok := existing[index]
+		if !ok {
+			continue
+		}
+		if deployment.Annotations[annotationBuildKitTemplateHash] == buildKitTemplateHash(deployment.Name, caHash) {
+			if deployment.Annotations[AnnotationBuildKitDraining] == "true" {
+				// A daemon that grew back into the pool before it was removed
+				if err := r.setDraining(ctx, deployment, false); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		stale = append(stale, deployment)
+	}
+
+	for _, deployment := range stale {
+		if deployment.Annotations[AnnotationBuildKitDraining] != "true" {
+			continue
+		}
+		if load[deployment.Name] > 0 {
+			return nil
+		}
+		if err := r.updateBuilder(ctx, deployment, caHash); err != nil {
+			return err
+		}
+		logf.FromContext(ctx).Info("Restarted drained BuildKit daemon with the current template", "builder", deployment.Name)
+		return nil
+	}
+
+	if len(stale) == 0 {
+		return nil
+	}
+	for index := range desired {
+		if deployment, ok := existing[index]; ok && !buildKitBuilderHealthy(deployment) {
+			return nil
+		}
+	}
+	logf.FromContext(ctx).Info("Draining stale BuildKit daemon before restarting it", "builder", stale[0].Name)
+	return r.setDraining(ctx, stale[0], true)
+}
+
+// syncRegistryCA copies the registry CA certificate into the BuildKit namespace whenever it
+// rotates, and returns the hash of the certificate the daemons trust
+func (r *BuildKitPoolReconciler) syncRegistryCA(ctx context.Context) (string, error) {
+	var target corev1.Secret
+	err := r.Get(ctx, types.NamespacedName{Namespace: BuildKitNamespace, Name: buildKitCASecret}, &target)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get BuildKit registry CA: %w", err)
+	}
+	exists := err == nil
+
+	var source corev1.Secret
+	if err := r.Get(ctx, registryTLSSecret, &source); err != nil && !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get registry certificate: %w", err)
+	}
+	ca := source.Data["ca.crt"]
+	if len(ca) == 0 {
+		// Keep trusting the copied certificate until the registry certificate is issued again
+		return hashRegistryCA(target.Data["ca.crt"]), nil
+	}
+	if exists && bytes.Equal(target.Data["ca.crt"], ca) {
+		return hashRegistryCA(ca), nil
+	}
+
+	target.Name = buildKitCASecret
+	target.Namespace = BuildKitNamespace
+	if target.Labels == nil {
+		target.Labels = map[string]string{}
+	}
+	target.Labels["app.kubernetes.io/managed-by"] = "kibaship"
+	target.Type = corev1.SecretTypeOpaque
+	target.Data = map[string][]byte{"ca.crt": ca}
+	if exists {
+		err = r.Update(ctx, &target)
+	} else {
+		err = r.Create(ctx, &target)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to copy registry CA to the BuildKit namespace: %w", err)
+	}
+	logf.FromContext(ctx).Info("Registry CA certificate rotated, rolling the BuildKit pool")
+	return hashRegistryCA(ca), nil
+}
+
+// createBuilder creates the Deployment and Service of a daemon of the pool
+func (r *BuildKitPoolReconciler) createBuilder(ctx context.Context, name, caHash string) error {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   BuildKitNamespace,
+			Labels:      buildKitBuilderLabels(name),
+			Annotations: map[string]string{annotationBuildKitTemplateHash: buildKitTemplateHash(name, caHash)},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{LabelBuildKitBuilder: name}},
+			Template: buildKitPodTemplate(name, caHash),
+		},
+	}
+	if err := r.Create(ctx, deployment); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create BuildKit daemon %s: %w", name, err)
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: BuildKitNamespace,
+			Labels:    buildKitBuilderLabels(name),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{LabelBuildKitBuilder: name},
+			Ports: []corev1.ServicePort{{
+				Name:       "buildkitd",
+				Port:       buildKitPort,
+				TargetPort: intstr.FromInt32(buildKitPort),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+	// The Service goes away with the daemon
+	if err := controllerutil.SetControllerReference(deployment, service, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner of BuildKit service %s: %w", name, err)
+	}
+	if err := r.Create(ctx, service); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create BuildKit service %s: %w", name, err)
+	}
+	return nil
+}
+
+// updateBuilder rolls a drained daemon to the current template and routes builds to it again
+func (r *BuildKitPoolReconciler) updateBuilder(ctx context.Context, deployment *appsv1.Deployment, caHash string) error {
+	patch := client.MergeFrom(deployment.DeepCopy())
+	deployment.Spec.Template = buildKitPodTemplate(deployment.Name, caHash)
+	deployment.Annotations[annotationBuildKitTemplateHash] = buildKitTemplateHash(deployment.Name, caHash)
+	delete(deployment.Annotations, AnnotationBuildKitDraining)
+	if err := r.Patch(ctx, deployment, patch); err != nil {
+		return fmt.Errorf("failed to restart BuildKit daemon %s: %w", deployment.Name, err)
+	}
+	return nil
+}
+
+// setDraining starts or stops routing builds away from a daemon of the pool
+func (r *BuildKitPoolReconciler) setDraining(ctx context.Context, deployment *appsv1.Deployment, draining bool) error {
+	if (deployment.Annotations[AnnotationBuildKitDraining] == "true") == draining {
+		return nil
+	}
+	patch := client.MergeFrom(deployment.DeepCopy())
+	if draining {
+		if deployment.Annotations == nil {
+			deployment.Annotations = map[string]string{}
+		}
+		deployment.Annotations[AnnotationBuildKitDraining] = "true"
+	} else {
+		delete(deployment.Annotations, AnnotationBuildKitDraining)
+	}
+	if err := r.Patch(ctx, deployment, patch); err != nil {
+		return fmt.Errorf("failed to drain BuildKit daemon %s: %w", deployment.Name, err)
+	}
+	return nil
+}
+
+// buildKitPodTemplate returns the pod template of a daemon of the pool. The daemon runs
+// rootless like the shared buildkitd Deployment, on the build node pool, and its readiness and
+// liveness probes ask the daemon for its workers.
+func buildKitPodTemplate(name, caHash string) corev1.PodTemplateSpec {
+	nodeSelector, tolerations := ResolveBuildScheduling(nil)
+	probe := func() *corev1.Probe {
+		return &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{Exec: &corev1.ExecAction{
+				Command: []string{"buildctl", "debug", "workers"},
+			}},
+			InitialDelaySeconds: 5,
+			PeriodSeconds:       30,
+		}
+	}
+
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      buildKitBuilderLabels(name),
+			Annotations: map[string]string{annotationRegistryCAHash: caHash},
+		},
+		Spec: corev1.PodSpec{
+			NodeSelector: nodeSelector,
+			Tolerations:  tolerations,
+			Containers: []corev1.Container{{
+				Name:  "buildkitd",
+				Image: mirrorImage(buildKitImage),
+				Args: []string{
+					"--addr", "unix:///run/user/1000/buildkit/buildkitd.sock",
+					"--addr", fmt.Sprintf("tcp://0.0.0.0:%d", buildKitPort),
+					"--oci-worker-no-process-sandbox",
+				},
+				Ports:          []corev1.ContainerPort{{ContainerPort: buildKitPort}},
+				ReadinessProbe: probe(),
+				LivenessProbe:  probe(),
+				SecurityContext: &corev1.SecurityContext{
+					SeccompProfile:  &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined},
+					AppArmorProfile: &corev1.AppArmorProfile{Type: corev1.AppArmorProfileTypeUnconfined},
+					RunAsUser:       ptr.To[int64](1000),
+					RunAsGroup:      ptr.To[int64](1000),
+				},
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      "buildkitd-config",
+						MountPath: "/home/user/.config/buildkit/buildkitd.toml",
+						SubPath:   "buildkitd.toml",
+						ReadOnly:  true,
+					},
+					{
+						Name:      buildKitCASecret,
+						MountPath: "/usr/local/share/ca-certificates/registry-ca.crt",
+						SubPath:   "ca.crt",
+						ReadOnly:  true,
+					},
+				},
+			}},
+			Volumes: []corev1.Volume{
+				{
+					Name: "buildkitd-config",
+					VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "buildkitd-config"},
+					}},
+				},
+				{
+					Name: buildKitCASecret,
+					VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+						SecretName: buildKitCASecret,
+						Optional:   ptr.To(true),
+					}},
+				},
+			},
+		},
+	}
+}
+
+// buildKitTemplateHash identifies the pod template of a daemon
+func buildKitTemplateHash(name, caHash string) string {
+	template := buildKitPodTemplate(name, caHash)
+	data, _ := json.Marshal(template)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// hashRegistryCA identifies a registry CA certificate, empty without certificate
+func hashRegistryCA(ca []byte) string {
+	if len(ca) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(ca)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// buildKitBuilderLabels returns the labels of the objects of a daemon of the pool
+func buildKitBuilderLabels(name string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "buildkitd",
+		"app.kubernetes.io/component":  buildKitBuilderComponent,
+		"app.kubernetes.io/managed-by": "kibaship",
+		"app.kubernetes.io/part-of":    "kibaship",
+		LabelBuildKitBuilder:           name,
+	}
+}
+
+// buildKitBuilderName returns the name of the daemon of the pool at index
+func buildKitBuilderName(index int) string {
+	return buildKitBuilderPrefix + strconv.Itoa(index)
+}
+
+// buildKitBuilderIndex returns the index of a daemon of the pool, -1 for other names
+func buildKitBuilderIndex(name string) int {
+	index, err := strconv.Atoi(strings.TrimPrefix(name, buildKitBuilderPrefix))
+	if err != nil || !strings.HasPrefix(name, buildKitBuilderPrefix) {
+		return -1
+	}
+	return index
+}
+
+// buildKitBuilderHealthy reports whether a daemon of the pool runs its current template and
+// passes its readiness probe
+func buildKitBuilderHealthy(deployment *appsv1.Deployment) bool {
+	status := deployment.Status
+	return status.ObservedGeneration >= deployment.Generation &&
+		status.UpdatedReplicas >= 1 && status.AvailableReplicas >= 1 && status.UnavailableReplicas == 0
+}
+
+// desiredBuildKitBuilders returns the size of the pool for the running builds
+func desiredBuildKitBuilders(cfg config.BuildKitConfig, running int) int {
+	if !cfg.Enabled() {
+		return 0
+	}
+	needed := (running + cfg.BuildsPerBuilder - 1) / cfg.BuildsPerBuilder
+	return min(max(needed, cfg.MinBuilders), cfg.MaxBuilders)
+}
+
+// listBuildKitBuilders returns the daemons of the pool in index order
+func listBuildKitBuilders(ctx context.Context, c client.Reader) ([]*appsv1.Deployment, error) {
+	var deployments appsv1.DeploymentList
+	if err := c.List(ctx, &deployments, client.InNamespace(BuildKitNamespace), client.MatchingLabels{
+		"app.kubernetes.io/component": buildKitBuilderComponent,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list BuildKit daemons: %w", err)
+	}
+
+	var builders []*appsv1.Deployment
+	for i := range deployments.Items {
+		if buildKitBuilderIndex(deployments.Items[i].Name) >= 0 {
+			builders = append(builders, &deployments.Items[i])
+		}
+	}
+	slices.SortFunc(builders, func(a, b *appsv1.Deployment) int {
+		return buildKitBuilderIndex(a.Name) - buildKitBuilderIndex(b.Name)
+	})
+	return builders, nil
+}
+
+// buildKitLoad returns the running builds per daemon of the pool and the running builds overall
+func buildKitLoad(ctx context.Context, c client.Reader) (map[string]int, int, error) {
+	var pipelineRuns tektonv1.PipelineRunList
+	if err := c.List(ctx, &pipelineRuns, client.MatchingLabels{
+		"app.kubernetes.io/component": pipelineRunComponent,
+	}); err != nil {
+		return nil, 0, fmt.Errorf("failed to list builds: %w", err)
+	}
+
+	load := map[string]int{}
+	running := 0
+	for i := range pipelineRuns.Items {
+		if !pipelineRunRunning(&pipelineRuns.Items[i]) {
+			continue
+		}
+		running++
+		if builder := pipelineRuns.Items[i].Labels[LabelBuildKitBuilder]; builder != "" {
+			load[builder]++
+		}
+	}
+	return load, running, nil
+}
+
+// pipelineRunRunning reports whether a PipelineRun has not finished yet
+func pipelineRunRunning(pipelineRun *tektonv1.PipelineRun) bool {
+	condition := pipelineRun.Status.GetCondition("Succeeded")
+	return condition == nil || condition.Status == corev1.ConditionUnknown
+}
+
+// pickBuildKitBuilder returns the least busy healthy daemon of the BuildKit pool and its
+// address. Without a healthy daemon, builds go to the shared buildkitd Deployment.
+func pickBuildKitBuilder(ctx context.Context, c client.Reader) (string, string, error) {
+	builders, err := listBuildKitBuilders(ctx, c)
+	if err != nil || len(builders) == 0 {
+		return "", pipelines.DefaultBuildKitHost, err
+	}
+	load, _, err := buildKitLoad(ctx, c)
+	if err != nil {
+		return "", pipelines.DefaultBuildKitHost, err
+	}
+
+	var picked *appsv1.Deployment
+	for _, deployment := range builders {
+		if deployment.Annotations[AnnotationBuildKitDraining] == "true" || !buildKitBuilderHealthy(deployment) {
+			continue
+		}
+		if picked == nil || load[deployment.Name] < load[picked.Name] {
+			picked = deployment
+		}
+	}
+	if picked == nil {
+		logf.FromContext(ctx).Info("No healthy BuildKit daemon in the pool, using the shared daemon", "builders", len(builders))
+		return "", pipelines.DefaultBuildKitHost, nil
+	}
+	return picked.Name, fmt.Sprintf("tcp://%s.%s.svc:%d", picked.Name, BuildKitNamespace, buildKitPort), nil
+}
+
+// buildKitPoolChanged filters events down to those that may resize or roll the pool: builds
+// starting and finishing, daemons of the pool changing, the registry certificate rotating and
+// the BuildKit namespace appearing, which also starts the pool when the operator starts
+func buildKitPoolChanged() predicate.Predicate {
+	relevant := func(obj client.Object) bool {
+		switch obj := obj.(type) {
+		case *tektonv1.PipelineRun:
+			return obj.Labels["app.kubernetes.io/component"] == pipelineRunComponent
+		case *appsv1.Deployment:
+			return obj.Namespace == BuildKitNamespace && obj.Labels["app.kubernetes.io/component"] == buildKitBuilderComponent
+		case *corev1.Secret:
+			return client.ObjectKeyFromObject(obj) == registryTLSSecret
+		case *corev1.Namespace:
+			return obj.Name == BuildKitNamespace
+		}
+		return false
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool { return relevant(e.Object) },
+		DeleteFunc: func(e event.DeleteEvent) bool { return relevant(e.Object) },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !relevant(e.ObjectNew) {
+				return false
+			}
+			if previous, ok := e.ObjectOld.(*tektonv1.PipelineRun); ok {
+				return pipelineRunRunning(previous) != pipelineRunRunning(e.ObjectNew.(*tektonv1.PipelineRun))
+			}
+			return true
+		},
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// The pool is shared by all shards and managed by the operator of the default shard.
+func (r *BuildKitPoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	pool := handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []ctrl.Request {
+		return []ctrl.Request{{NamespacedName: types.NamespacedName{Namespace: BuildKitNamespace, Name: buildKitPoolName}}}
+	})
+	changed := builder.WithPredicates(buildKitPoolChanged())
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controllerOptions("buildkit-pool", 1)).
+		Named("buildkit-pool").
+		WithEventFilter(DefaultShardPredicate()).
+		Watches(&tektonv1.PipelineRun{}, pool, changed).
+		Watches(&appsv1.Deployment{}, pool, changed).
+		Watches(&corev1.Secret{}, pool, changed).
+		Watches(&corev1.Namespace{}, pool, changed).
+		Complete(r)
+}
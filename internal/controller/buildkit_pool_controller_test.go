@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/pipelines"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+func buildKitScheme(g *WithT) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(tektonv1.AddToScheme(scheme)).To(Succeed())
+	return scheme
+}
+
+func runningBuild(name, builder string) *tektonv1.PipelineRun {
+	labels := map[string]string{"app.kubernetes.io/component": pipelineRunComponent}
+	if builder != "" {
+		labels[LabelBuildKitBuilder] = builder
+	}
+	return &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "project-ns", Labels: labels}}
+}
+
+func healthyBuilder(name string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: BuildKitNamespace, Labels: buildKitBuilderLabels(name)},
+		Status:     appsv1.DeploymentStatus{UpdatedReplicas: 1, AvailableReplicas: 1},
+	}
+}
+
+func TestDesiredBuildKitBuilders(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := config.BuildKitConfig{MinBuilders: 1, MaxBuilders: 3, BuildsPerBuilder: 2}
+	for running, expected := range map[int]int{0: 1, 1: 1, 2: 1, 3: 2, 5: 3, 20: 3} {
+		g.Expect(desiredBuildKitBuilders(cfg, running)).To(Equal(expected), "running %d", running)
+	}
+	g.Expect(desiredBuildKitBuilders(config.BuildKitConfig{}, 10)).To(BeZero())
+	g.Expect(desiredBuildKitBuilders(config.BuildKitConfig{MaxBuilders: 2, BuildsPerBuilder: 1}, 0)).To(BeZero())
+}
+
+func TestPickBuildKitBuilder(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := buildKitScheme(g)
+
+	draining := healthyBuilder("buildkitd-2")
+	draining.Annotations = map[string]string{AnnotationBuildKitDraining: "true"}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		healthyBuilder("buildkitd-0"), healthyBuilder("buildkitd-1"), draining,
+		runningBuild("build-1", "buildkitd-0"), runningBuild("build-2", "buildkitd-0"), runningBuild("build-3", "buildkitd-1"),
+	).Build()
+
+	name, host, err := pickBuildKitBuilder(ctx, cl)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(name).To(Equal("buildkitd-1"))
+	g.Expect(host).To(Equal("tcp://buildkitd-1.buildkit.svc:1234"))
+
+	// Without healthy daemons builds go to the shared daemon
+	cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(draining).Build()
+	name, host, err = pickBuildKitBuilder(ctx, cl)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(name).To(BeEmpty())
+	g.Expect(host).To(Equal(pipelines.DefaultBuildKitHost))
+}
+
+func TestBuildKitPoolReconciler(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := buildKitScheme(g)
+	t.Cleanup(func() { SetBuildKitPool(config.BuildKitConfig{}) })
+	SetBuildKitPool(config.BuildKitConfig{MinBuilders: 1, MaxBuilders: 3, BuildsPerBuilder: 2})
+
+	objects := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: BuildKitNamespace}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: registryTLSSecret.Name, Namespace: registryTLSSecret.Namespace},
+			Data:       map[string][]byte{"ca.crt": []byte("ca-1")},
+		},
+	}
+	for i := range 5 {
+		objects = append(objects, runningBuild(fmt.Sprintf("build-%d", i), ""))
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	r := &BuildKitPoolReconciler{Client: cl, Scheme: scheme}
+
+	reconcilePool := func() []*appsv1.Deployment {
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		g.Expect(err).NotTo(HaveOccurred())
+		builders, err := listBuildKitBuilders(ctx, cl)
+		g.Expect(err).NotTo(HaveOccurred())
+		return builders
+	}
+
+	// Five running builds need three daemons of two builds each
+	builders := reconcilePool()
+	g.Expect(builders).To(HaveLen(3))
+	g.Expect(builders[2].Name).To(Equal("buildkitd-2"))
+	g.Expect(cl.Get(ctx, types.NamespacedName{Name: "buildkitd-2", Namespace: BuildKitNamespace}, &corev1.Service{})).To(Succeed())
+	ca := &corev1.Secret{}
+	g.Expect(cl.Get(ctx, types.NamespacedName{Name: buildKitCASecret, Namespace: BuildKitNamespace}, ca)).To(Succeed())
+	g.Expect(ca.Data["ca.crt"]).To(Equal([]byte("ca-1")))
+
+	for _, builder := range builders {
+		builder.Status = appsv1.DeploymentStatus{UpdatedReplicas: 1, AvailableReplicas: 1}
+		g.Expect(cl.Status().Update(ctx, builder)).To(Succeed())
+	}
+	previousHash := builders[0].Spec.Template.Annotations[annotationRegistryCAHash]
+
+	// The registry CA rotates, the first daemon drains then restarts
+	source := &corev1.Secret{}
+	g.Expect(cl.Get(ctx, registryTLSSecret, source)).To(Succeed())
+	source.Data["ca.crt"] = []byte("ca-2")
+	g.Expect(cl.Update(ctx, source)).To(Succeed())
+
+	builders = reconcilePool()
+	g.Expect(cl.Get(ctx, types.NamespacedName{Name: buildKitCASecret, Namespace: BuildKitNamespace}, ca)).To(Succeed())
+	g.Expect(ca.Data["ca.crt"]).To(Equal([]byte("ca-2")))
+	g.Expect(builders[0].Annotations[AnnotationBuildKitDraining]).To(Equal("true"))
+	g.Expect(builders[1].Annotations).NotTo(HaveKey(AnnotationBuildKitDraining))
+
+	builders = reconcilePool()
+	g.Expect(builders[0].Annotations).NotTo(HaveKey(AnnotationBuildKitDraining))
+	g.Expect(builders[0].Spec.Template.Annotations[annotationRegistryCAHash]).NotTo(Equal(previousHash))
+	g.Expect(builders[1].Spec.Template.Annotations[annotationRegistryCAHash]).To(Equal(previousHash))
+
+	// Builds finish, the pool shrinks back to its minimum
+	var runs tektonv1.PipelineRunList
+	g.Expect(cl.List(ctx, &runs)).To(Succeed())
+	for i := range runs.Items {
+		runs.Items[i].Status.MarkSucceeded("Succeeded", "All Tasks have completed executing")
+		g.Expect(cl.Update(ctx, &runs.Items[i])).To(Succeed())
+	}
+	builders = reconcilePool()
+	g.Expect(builders).To(HaveLen(1))
+	g.Expect(builders[0].Name).To(Equal("buildkitd-0"))
+}
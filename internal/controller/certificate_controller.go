@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -44,6 +45,8 @@ type CertificateWatcherReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Notifier webhooks.Notifier
+	// Recorder emits the certificate history of domains as Events, nil records the status only
+	Recorder record.EventRecorder
 }
 
 const (
@@ -56,6 +59,7 @@ const (
 // +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applicationdomains,verbs=get;list;watch
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applicationdomains/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *CertificateWatcherReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
@@ -89,8 +93,21 @@ func (r *CertificateWatcherReconciler) Reconcile(ctx context.Context, req ctrl.R
 	ad := adList.Items[0]
 	prevPhase := ad.Status.Phase
 
+	// Certificates may live outside project namespaces, only the shard of the domain updates it
+	owned, err := ownsNamespace(ctx, r.Client, ad.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !owned {
+		return ctrl.Result{}, nil
+	}
+
 	// Extract Certificate Ready condition
 	readyStatus, reason, message := extractCertReady(u)
+	// Tests fail certificate issuance through a fault annotation, whatever cert-manager reports
+	if value, ok := injectedFault(&ad, validation.AnnotationFaultCertificateFailure); ok {
+		readyStatus, reason, message = condFalse, FaultInjectedReason, injectedFailureMessage(value, "Certificate issuance failure injected")
+	}
 	// Derive phase
 	var newPhase platformv1alpha1.ApplicationDomainPhase
 	switch readyStatus {
@@ -123,6 +140,9 @@ func (r *CertificateWatcherReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 	meta.SetStatusCondition(&ad.Status.Conditions, cond)
 
+	eventType, eventReason, eventMessage := certificateEvent(readyStatus, reason, message)
+	recordDomainEvent(r.Recorder, &ad, now, platformv1alpha1.DomainHistorySourceCertificate, eventType, eventReason, eventMessage)
+
 	if err := r.Status().Update(ctx, &ad); err != nil {
 		logger.Error(err, "update ApplicationDomain status failed", "ad", fmt.Sprintf("%s/%s", ad.Namespace, ad.Name))
 		return ctrl.Result{}, err
@@ -154,6 +174,8 @@ func (r *CertificateWatcherReconciler) SetupWithManager(mgr ctrl.Manager) error
 	return ctrl.NewControllerManagedBy(mgr).
 		For(u).
 		WithEventFilter(pred).
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
+		WithOptions(controllerOptions("certificate", 1)).
 		Complete(r)
 }
 
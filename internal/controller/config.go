@@ -20,6 +20,9 @@ import (
 	"fmt"
 	"regexp"
 	"sync"
+	"sync/atomic"
+
+	"github.com/kibamail/kibaship/pkg/config"
 )
 
 // OperatorConfig holds the global configuration for the operator
@@ -30,43 +33,95 @@ type OperatorConfig struct {
 	DefaultPort int32
 	// GatewayClassName is the Gateway API gateway class to use for routing
 	GatewayClassName string
+	// IngressProvider selects between Gateway API HTTPRoutes and Ingress resources
+	IngressProvider config.IngressProvider
+	// IngressClassName is the IngressClass used when IngressProvider is not the Gateway API
+	IngressClassName string
 }
 
 var (
-	operatorConfig *OperatorConfig
+	// operatorConfig is swapped atomically so readers always see a consistent snapshot
+	operatorConfig atomic.Pointer[OperatorConfig]
 	configOnce     sync.Once
 )
 
-// SetOperatorConfig sets the global operator configuration
+// SetOperatorConfig sets the global operator configuration using the Gateway API ingress provider
 // This should be called once at startup after loading from ConfigMap
 func SetOperatorConfig(domain, gatewayClassName string) error {
+	return SetOperatorConfigWithIngress(domain, config.IngressProviderGateway, gatewayClassName, "")
+}
+
+// SetOperatorConfigWithIngress sets the global operator configuration with an explicit ingress provider
+// This should be called once at startup after loading from ConfigMap
+func SetOperatorConfigWithIngress(domain string, provider config.IngressProvider, gatewayClassName, ingressClassName string) error {
+	cfg, err := newOperatorConfig(domain, provider, gatewayClassName, ingressClassName)
+	if err != nil {
+		return err
+	}
+
+	configOnce.Do(func() {
+		operatorConfig.Store(cfg)
+	})
+
+	return nil
+}
+
+// UpdateOperatorConfig validates and atomically replaces the global operator configuration.
+// It is used when the operator ConfigMap changes at runtime.
+func UpdateOperatorConfig(domain string, provider config.IngressProvider, gatewayClassName, ingressClassName string) error {
+	cfg, err := newOperatorConfig(domain, provider, gatewayClassName, ingressClassName)
+	if err != nil {
+		return err
+	}
+
+	// Prevent a late SetOperatorConfig call from overwriting the update
+	configOnce.Do(func() {})
+	operatorConfig.Store(cfg)
+
+	return nil
+}
+
+// newOperatorConfig validates the settings and builds an OperatorConfig
+func newOperatorConfig(domain string, provider config.IngressProvider, gatewayClassName, ingressClassName string) (*OperatorConfig, error) {
 	// Validate domain format - must be a valid DNS name
 	domainRegex := regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
 	if !domainRegex.MatchString(domain) {
-		return fmt.Errorf("invalid domain format: %s - domain must be a valid DNS name (lowercase, alphanumeric, hyphens, dots)", domain)
+		return nil, fmt.Errorf("invalid domain format: %s - domain must be a valid DNS name (lowercase, alphanumeric, hyphens, dots)", domain)
+	}
+
+	if _, err := config.ParseIngressProvider(string(provider)); err != nil {
+		return nil, err
+	}
+	if provider == "" {
+		provider = config.DefaultIngressProvider
 	}
 
-	// Validate gateway class name - must be non-empty
-	if gatewayClassName == "" {
-		return fmt.Errorf("gateway class name cannot be empty")
+	// Validate gateway class name - must be non-empty when routing through the Gateway API
+	if provider.UsesGatewayAPI() && gatewayClassName == "" {
+		return nil, fmt.Errorf("gateway class name cannot be empty")
 	}
 
-	configOnce.Do(func() {
-		operatorConfig = &OperatorConfig{
-			Domain:           domain,
-			DefaultPort:      3000, // Hardcoded to 3000
-			GatewayClassName: gatewayClassName,
-		}
-	})
+	// Default the ingress class name when routing through Ingress resources
+	if !provider.UsesGatewayAPI() && ingressClassName == "" {
+		ingressClassName = provider.DefaultIngressClassName()
+	}
 
-	return nil
+	return &OperatorConfig{
+		Domain:           domain,
+		DefaultPort:      3000, // Hardcoded to 3000
+		GatewayClassName: gatewayClassName,
+		IngressProvider:  provider,
+		IngressClassName: ingressClassName,
+	}, nil
 }
 
-// GetOperatorConfig returns the singleton operator configuration
+// GetOperatorConfig returns the current operator configuration.
+// The returned value is a snapshot and must not be modified.
 func GetOperatorConfig() (*OperatorConfig, error) {
-	if operatorConfig == nil {
+	cfg := operatorConfig.Load()
+	if cfg == nil {
 		return nil, fmt.Errorf("operator configuration not initialized - call SetOperatorConfig first")
 	}
 
-	return operatorConfig, nil
+	return cfg, nil
 }
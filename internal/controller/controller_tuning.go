@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ControllerTuning holds the worker and requeue settings of the controllers
+type ControllerTuning struct {
+	// Concurrency overrides the number of workers of controllers, keyed by controller name
+	Concurrency map[string]int
+	// BaseDelay and MaxDelay bound the exponential backoff of failed reconciles
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// QPS and Burst bound the rate at which each controller requeues, a zero QPS lifts the limit
+	QPS   float64
+	Burst int
+}
+
+// DefaultControllerTuning returns the requeue settings controller-runtime uses by default
+func DefaultControllerTuning() ControllerTuning {
+	return ControllerTuning{
+		BaseDelay: 5 * time.Millisecond,
+		MaxDelay:  1000 * time.Second,
+		QPS:       10,
+		Burst:     100,
+	}
+}
+
+var (
+	controllerTuning atomic.Pointer[ControllerTuning]
+	// tunedControllers records the controllers set up with controllerOptions, to report
+	// concurrency overrides naming no controller
+	tunedControllers sync.Map
+)
+
+// SetControllerTuning sets the worker and requeue settings of the controllers
+// This should be called once at startup, before controllers are set up
+func SetControllerTuning(tuning ControllerTuning) error {
+	if tuning.BaseDelay <= 0 || tuning.MaxDelay < tuning.BaseDelay {
+		return fmt.Errorf("the requeue delays must be positive and the max delay at least the base delay, got %s and %s",
+			tuning.BaseDelay, tuning.MaxDelay)
+	}
+	if tuning.QPS < 0 || (tuning.QPS > 0 && tuning.Burst <= 0) {
+		return fmt.Errorf("the requeue QPS must not be negative and the burst must be positive, got %v and %d",
+			tuning.QPS, tuning.Burst)
+	}
+	controllerTuning.Store(&tuning)
+	return nil
+}
+
+// ParseControllerConcurrency parses a comma separated list of controller=workers pairs, such as
+// "deployment=20,project-cost=2"
+func ParseControllerConcurrency(value string) (map[string]int, error) {
+	concurrency := map[string]int{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, workers, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("controller concurrency must be controller=workers, got %q", pair)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(workers))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("workers of controller %s must be a positive integer, got %q", name, workers)
+		}
+		concurrency[strings.TrimSpace(name)] = n
+	}
+	return concurrency, nil
+}
+
+// UnknownConcurrencyOverrides returns the controllers of the concurrency overrides that no
+// controller set up so far is named after, to catch typos in the flag
+func UnknownConcurrencyOverrides() []string {
+	tuning := controllerTuning.Load()
+	if tuning == nil {
+		return nil
+	}
+	var unknown []string
+	for name := range tuning.Concurrency {
+		if _, ok := tunedControllers.Load(name); !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// controllerOptions returns the options of the controller named name, running workers
+// reconciles concurrently unless the tuning overrides it
+func controllerOptions(name string, workers int) controller.Options {
+	tunedControllers.Store(name, struct{}{})
+
+	tuning := controllerTuning.Load()
+	if tuning == nil {
+		return controller.Options{MaxConcurrentReconciles: workers}
+	}
+	if n, ok := tuning.Concurrency[name]; ok {
+		workers = n
+	}
+	return controller.Options{
+		MaxConcurrentReconciles: workers,
+		RateLimiter:             tuning.rateLimiter(),
+	}
+}
+
+// rateLimiter returns the requeue rate limiter of one controller, controllers must not share it
+func (t *ControllerTuning) rateLimiter() workqueue.TypedRateLimiter[reconcile.Request] {
+	backoff := workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](t.BaseDelay, t.MaxDelay)
+	if t.QPS == 0 {
+		return backoff
+	}
+	return workqueue.NewTypedMaxOfRateLimiter(
+		backoff,
+		&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(t.QPS), t.Burst)},
+	)
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestParseControllerConcurrency(t *testing.T) {
+	g := NewWithT(t)
+
+	concurrency, err := ParseControllerConcurrency(" deployment=20, project-cost=2,")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(concurrency).To(Equal(map[string]int{"deployment": 20, "project-cost": 2}))
+
+	concurrency, err = ParseControllerConcurrency("")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(concurrency).To(BeEmpty())
+
+	for _, value := range []string{"deployment", "=2", "deployment=0", "deployment=many"} {
+		_, err := ParseControllerConcurrency(value)
+		g.Expect(err).To(HaveOccurred(), value)
+	}
+}
+
+func TestControllerOptions(t *testing.T) {
+	g := NewWithT(t)
+	t.Cleanup(func() { controllerTuning.Store(nil) })
+
+	options := controllerOptions("deployment", 10)
+	g.Expect(options.MaxConcurrentReconciles).To(Equal(10))
+	g.Expect(options.RateLimiter).To(BeNil())
+
+	tuning := DefaultControllerTuning()
+	tuning.Concurrency = map[string]int{"deployment": 25, "deploymnet": 3}
+	tuning.BaseDelay = time.Second
+	tuning.QPS = 0
+	g.Expect(SetControllerTuning(tuning)).To(Succeed())
+
+	options = controllerOptions("deployment", 10)
+	g.Expect(options.MaxConcurrentReconciles).To(Equal(25))
+	g.Expect(controllerOptions("application", 1).MaxConcurrentReconciles).To(Equal(1))
+	g.Expect(UnknownConcurrencyOverrides()).To(Equal([]string{"deploymnet"}))
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "web"}}
+	g.Expect(options.RateLimiter.When(request)).To(Equal(time.Second))
+	g.Expect(options.RateLimiter.When(request)).To(Equal(2 * time.Second))
+
+	invalid := DefaultControllerTuning()
+	invalid.MaxDelay = time.Millisecond
+	g.Expect(SetControllerTuning(invalid)).NotTo(Succeed())
+	invalid = DefaultControllerTuning()
+	invalid.Burst = 0
+	g.Expect(SetControllerTuning(invalid)).NotTo(Succeed())
+}
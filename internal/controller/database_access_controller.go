@@ -0,0 +1,405 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/dbaccess"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	// databaseAccessRequeueInterval is how often access waits for the database to become ready
+	databaseAccessRequeueInterval = 30 * time.Second
+
+	// databaseAccessJobTTL keeps finished Jobs around long enough to read their logs
+	databaseAccessJobTTL int32 = 3600
+
+	databaseAccessPasswordLength  = 32
+	databaseAccessPasswordCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// DatabaseAccessReconciler applies the additional databases and users declared on MySQL and
+// Postgres applications, and the users of the service bindings to shared ones, by running a Job
+// against the database
+type DatabaseAccessReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;delete
+
+// Reconcile runs the Job applying spec.databaseAccess of a database application, together with a
+// user per service binding when it is shared, and records its outcome in status.databaseAccess
+func (r *DatabaseAccessReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var app platformv1alpha1.Application
+	if err := r.Get(ctx, req.NamespacedName, &app); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !app.DeletionTimestamp.IsZero() || app.GetUUID() == "" {
+		return ctrl.Result{}, nil
+	}
+
+	conn, ok := dbaccess.ConnectionFor(&app)
+	if !ok || !conn.SupportsAccess() {
+		return ctrl.Result{}, nil
+	}
+
+	var access platformv1alpha1.DatabaseAccessConfig
+	if app.Spec.DatabaseAccess != nil {
+		access = *app.Spec.DatabaseAccess
+	}
+	bindingUsers, err := r.bindingUsers(ctx, &app, conn)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	access.Users = append(append([]platformv1alpha1.DatabaseUser{}, access.Users...), bindingUsers...)
+	current := app.Status.DatabaseAccess
+	if current == nil && len(access.Databases) == 0 && len(access.Users) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	if app.Status.Phase != applicationPhaseReady {
+		log.Info("Database access waiting for the database", "application", app.Name)
+		return ctrl.Result{RequeueAfter: databaseAccessRequeueInterval}, nil
+	}
+
+	declared := make(map[string]bool, len(access.Users))
+	for _, user := range access.Users {
+		declared[user.Username] = true
+		if err := r.ensureUserSecret(ctx, &app, user.Username); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	var removed []string
+	if current != nil {
+		for _, username := range current.Users {
+			if !declared[username] {
+				removed = append(removed, username)
+			}
+		}
+	}
+
+	script := dbaccess.Script(conn, app.Namespace, access, removed)
+	jobName := fmt.Sprintf("%s-db-%s", app.Name, dbaccess.Hash(script))
+	if current != nil && current.JobName == jobName && current.Phase != platformv1alpha1.DatabaseAccessPhasePending {
+		return ctrl.Result{}, nil
+	}
+
+	var job batchv1.Job
+	err = r.Get(ctx, client.ObjectKey{Name: jobName, Namespace: app.Namespace}, &job)
+	switch {
+	case apierrors.IsNotFound(err):
+		if err := r.createJob(ctx, &app, conn, jobName, script, access.Users); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("Started database access job", "application", app.Name, "job", jobName)
+		return ctrl.Result{}, r.updateStatus(ctx, &app, &platformv1alpha1.DatabaseAccessStatus{
+			Phase:   platformv1alpha1.DatabaseAccessPhasePending,
+			JobName: jobName,
+		})
+	case err != nil:
+		return ctrl.Result{}, fmt.Errorf("failed to get database access job: %w", err)
+	}
+
+	next := &platformv1alpha1.DatabaseAccessStatus{Phase: platformv1alpha1.DatabaseAccessPhasePending, JobName: jobName}
+	if current != nil {
+		next.Databases = current.Databases
+		next.Users = current.Users
+		next.LastAppliedTime = current.LastAppliedTime
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			for _, username := range removed {
+				if err := r.deleteUserSecret(ctx, &app, username); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			next.Phase = platformv1alpha1.DatabaseAccessPhaseSucceeded
+			next.Databases = nil
+			for _, database := range access.Databases {
+				next.Databases = append(next.Databases, database.Name)
+			}
+			next.Users = nil
+			for _, user := range access.Users {
+				next.Users = append(next.Users, user.Username)
+			}
+			next.LastAppliedTime = &metav1.Time{Time: time.Now()}
+			log.Info("Applied database access", "application", app.Name, "job", jobName)
+		case batchv1.JobFailed:
+			next.Phase = platformv1alpha1.DatabaseAccessPhaseFailed
+			next.Message = condition.Message
+			log.Info("Database access job failed", "application", app.Name, "job", jobName, "reason", condition.Reason)
+		}
+	}
+
+	return ctrl.Result{}, r.updateStatus(ctx, &app, next)
+}
+
+// bindingUsers returns the database users of the service bindings to a shared database
+// application, one per bound application of its environment, sorted by username
+func (r *DatabaseAccessReconciler) bindingUsers(ctx context.Context, app *platformv1alpha1.Application, conn dbaccess.Connection) ([]platformv1alpha1.DatabaseUser, error) {
+	if !app.Spec.Shared {
+		return nil, nil
+	}
+
+	var applications platformv1alpha1.ApplicationList
+	if err := r.List(ctx, &applications, client.InNamespace(app.Namespace), client.MatchingLabels{
+		validation.LabelEnvironmentUUID: app.Labels[validation.LabelEnvironmentUUID],
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list applications bound to %s: %w", app.Name, err)
+	}
+
+	var users []platformv1alpha1.DatabaseUser
+	for i := range applications.Items {
+		bound := &applications.Items[i]
+		if !bound.DeletionTimestamp.IsZero() || bound.GetUUID() == "" {
+			continue
+		}
+		for _, binding := range bound.Spec.ServiceBindings {
+			if binding.Application.Name == app.Name {
+				users = append(users, dbaccess.BindingUser(conn, bound.GetUUID(), binding))
+			}
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return users, nil
+}
+
+// sharedDatabasesInNamespace maps an application to the shared databases of its namespace, so
+// adding, changing or removing its service bindings updates their users
+func (r *DatabaseAccessReconciler) sharedDatabasesInNamespace(ctx context.Context, obj client.Object) []ctrl.Request {
+	var applications platformv1alpha1.ApplicationList
+	if err := r.List(ctx, &applications, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list shared databases", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []ctrl.Request
+	for _, app := range applications.Items {
+		if app.Spec.Shared && app.Name != obj.GetName() {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKey{Name: app.Name, Namespace: app.Namespace}})
+		}
+	}
+	return requests
+}
+
+// ensureUserSecret creates the Secret holding the generated password of a database user. The
+// password of an existing Secret is kept, reapplying access does not rotate it.
+func (r *DatabaseAccessReconciler) ensureUserSecret(ctx context.Context, app *platformv1alpha1.Application, username string) error {
+	name := dbaccess.UserSecretName(app.GetUUID(), username)
+
+	var secret corev1.Secret
+	err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: app.Namespace}, &secret)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get secret of database user %s: %w", username, err)
+	}
+
+	password, err := generateDatabasePassword()
+	if err != nil {
+		return fmt.Errorf("failed to generate password of database user %s: %w", username, err)
+	}
+
+	secret = corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: app.Namespace,
+			Labels: map[string]string{
+				validation.LabelApplicationUUID: app.GetUUID(),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			dbaccess.UsernameKey: []byte(username),
+			dbaccess.PasswordKey: []byte(password),
+		},
+	}
+	if err := controllerutil.SetControllerReference(app, &secret, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner of database user secret: %w", err)
+	}
+	if err := r.Create(ctx, &secret); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create secret of database user %s: %w", username, err)
+	}
+	return nil
+}
+
+// deleteUserSecret removes the Secret of a dropped database user
+func (r *DatabaseAccessReconciler) deleteUserSecret(ctx context.Context, app *platformv1alpha1.Application, username string) error {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:      dbaccess.UserSecretName(app.GetUUID(), username),
+		Namespace: app.Namespace,
+	}}
+	if err := r.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete secret of database user %s: %w", username, err)
+	}
+	return nil
+}
+
+// createJob starts the Job running script with the database client of the engine
+func (r *DatabaseAccessReconciler) createJob(ctx context.Context, app *platformv1alpha1.Application, conn dbaccess.Connection, name, script string, users []platformv1alpha1.DatabaseUser) error {
+	image := "mysql:8.4"
+	if conn.Engine == dbaccess.EnginePostgres {
+		image = "postgres:17-alpine"
+	}
+
+	secretEnv := func(env, secret, key string, optional bool) corev1.EnvVar {
+		return corev1.EnvVar{Name: env, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+			Key:                  key,
+			Optional:             &optional,
+		}}}
+	}
+	var env []corev1.EnvVar
+	if conn.UsernameKey != "" {
+		env = append(env, secretEnv("ADMIN_USERNAME", conn.Secret, conn.UsernameKey, true))
+	}
+	env = append(env, secretEnv("ADMIN_PASSWORD", conn.Secret, conn.PasswordKey, false))
+	for _, user := range users {
+		env = append(env, secretEnv(dbaccess.PasswordEnv(user.Username), dbaccess.UserSecretName(app.GetUUID(), user.Username), dbaccess.PasswordKey, false))
+	}
+
+	backoffLimit := int32(3)
+	ttl := databaseAccessJobTTL
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: app.Namespace,
+			Labels: map[string]string{
+				validation.LabelApplicationUUID: app.GetUUID(),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:    "database-access",
+						Image:   mirrorImage(image),
+						Command: []string{"/bin/sh", "-c", script},
+						Env:     env,
+					}},
+				},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(app, job, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner of database access job: %w", err)
+	}
+	if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create database access job: %w", err)
+	}
+	return nil
+}
+
+// updateStatus records the outcome of the database access Job
+func (r *DatabaseAccessReconciler) updateStatus(ctx context.Context, app *platformv1alpha1.Application, next *platformv1alpha1.DatabaseAccessStatus) error {
+	patch := client.MergeFrom(app.DeepCopy())
+	app.Status.DatabaseAccess = next
+	if err := r.Status().Patch(ctx, app, patch); err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to update application database access status: %w", err)
+	}
+	return nil
+}
+
+// generateDatabasePassword returns a random alphanumeric password, safe to embed in SQL strings
+func generateDatabasePassword() (string, error) {
+	password := make([]byte, databaseAccessPasswordLength)
+	charsetLength := big.NewInt(int64(len(databaseAccessPasswordCharset)))
+	for i := range password {
+		index, err := rand.Int(rand.Reader, charsetLength)
+		if err != nil {
+			return "", err
+		}
+		password[i] = databaseAccessPasswordCharset[index.Int64()]
+	}
+	return string(password), nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Status updates of applications are ignored, Jobs report back through their owner. Spec changes
+// and deletions of any application reconcile the shared databases of its namespace.
+func (r *DatabaseAccessReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&platformv1alpha1.Application{}, builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			applicationPhaseChangedPredicate(),
+		))).
+		Owns(&batchv1.Job{}).
+		Watches(&platformv1alpha1.Application{}, handler.EnqueueRequestsFromMapFunc(r.sharedDatabasesInNamespace),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controllerOptions("application-database-access", 1)).
+		Named("application-database-access").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
+		Complete(r)
+}
+
+// applicationPhaseChangedPredicate passes application updates changing status.phase, so access
+// is applied as soon as the database becomes ready
+func applicationPhaseChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldApp, ok := e.ObjectOld.(*platformv1alpha1.Application)
+			if !ok {
+				return false
+			}
+			newApp, ok := e.ObjectNew.(*platformv1alpha1.Application)
+			if !ok {
+				return false
+			}
+			return oldApp.Status.Phase != newApp.Status.Phase
+		},
+	}
+}
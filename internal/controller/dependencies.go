@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	// DeploymentConditionDependenciesReady reports whether the applications the deployed
+	// application depends on were ready when the deployment started
+	DeploymentConditionDependenciesReady = "DependenciesReady"
+
+	// dependencyRequeueInterval is how often a held deployment checks its dependencies again
+	dependencyRequeueInterval = 15 * time.Second
+)
+
+// waitForDependencies holds a deployment until every application its application depends on
+// is ready. Once released, the deployment is not held again, so a dependency failing later does
+// not stall a build that already started.
+func (r *DeploymentReconciler) waitForDependencies(ctx context.Context, deployment *platformv1alpha1.Deployment, app *platformv1alpha1.Application) (bool, error) {
+	if len(app.Spec.DependsOn) == 0 {
+		return false, nil
+	}
+	if meta.IsStatusConditionTrue(deployment.Status.Conditions, DeploymentConditionDependenciesReady) {
+		return false, nil
+	}
+
+	var pending []string
+	for _, ref := range app.Spec.DependsOn {
+		var dependency platformv1alpha1.Application
+		if err := r.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: app.Namespace}, &dependency); err != nil {
+			if errors.IsNotFound(err) {
+				// A deleted dependency can never become ready
+				continue
+			}
+			return false, fmt.Errorf("failed to get dependency %s: %w", ref.Name, err)
+		}
+
+		ready, err := r.dependencyReady(ctx, &dependency)
+		if err != nil {
+			return false, err
+		}
+		if !ready {
+			name := dependency.Labels[validation.LabelResourceSlug]
+			if name == "" {
+				name = dependency.Name
+			}
+			pending = append(pending, name)
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:    DeploymentConditionDependenciesReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  "DependenciesReady",
+		Message: "All dependencies are ready",
+	}
+	if len(pending) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "WaitingForDependencies"
+		condition.Message = "Waiting for " + strings.Join(pending, ", ")
+	}
+
+	if meta.SetStatusCondition(&deployment.Status.Conditions, condition) {
+		if err := r.Status().Update(ctx, deployment); err != nil {
+			return false, fmt.Errorf("failed to update dependencies condition: %w", err)
+		}
+	}
+
+	if len(pending) > 0 {
+		logf.FromContext(ctx).Info("Deployment waiting for dependencies", "pending", pending)
+		return true, nil
+	}
+	return false, nil
+}
+
+// dependencyReady reports whether a dependency serves traffic: its current deployment
+// succeeded. Database progress is not tracked on deployments yet, databases are ready once
+// their Application is.
+func (r *DeploymentReconciler) dependencyReady(ctx context.Context, dependency *platformv1alpha1.Application) (bool, error) {
+	switch dependency.Spec.Type {
+	case platformv1alpha1.ApplicationTypeMySQL,
+		platformv1alpha1.ApplicationTypeMySQLCluster,
+		platformv1alpha1.ApplicationTypeValkey,
+		platformv1alpha1.ApplicationTypeValkeyCluster,
+		platformv1alpha1.ApplicationTypePostgres,
+		platformv1alpha1.ApplicationTypePostgresCluster:
+		return dependency.Status.Phase == applicationPhaseReady, nil
+	}
+
+	if dependency.Spec.CurrentDeploymentRef == nil {
+		return false, nil
+	}
+
+	var current platformv1alpha1.Deployment
+	if err := r.Get(ctx, client.ObjectKey{Name: dependency.Spec.CurrentDeploymentRef.Name, Namespace: dependency.Namespace}, &current); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get current deployment of %s: %w", dependency.Name, err)
+	}
+	return current.Status.Phase == platformv1alpha1.DeploymentPhaseSucceeded, nil
+}
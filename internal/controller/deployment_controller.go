@@ -20,7 +20,6 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
-	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -31,17 +30,21 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
 	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/envcrypt"
+	"github.com/kibamail/kibaship/pkg/pipelines"
 	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
 	"github.com/kibamail/kibaship/pkg/webhooks"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 )
 
@@ -50,14 +53,6 @@ const (
 	DeploymentFinalizerName = "platform.operator.kibaship.com/deployment-finalizer"
 	// GitRepositoryPipelineName is the suffix for git repository pipeline names
 	GitRepositoryPipelineSuffix = "git-repository-pipeline"
-	// GitCloneTaskName is the name of the git clone task in tekton-pipelines namespace
-	GitCloneTaskName = "tekton-task-git-clone-kibaship-com"
-	// RailpackPrepareTaskName is the name of the railpack prepare task in tekton-pipelines namespace
-	RailpackPrepareTaskName = "tekton-task-railpack-prepare-kibaship-com"
-	// RailpackBuildTaskName is the name of the railpack build task in tekton-pipelines namespace
-	RailpackBuildTaskName = "tekton-task-railpack-build-kibaship-com"
-	// DefaultGitBranch is the default git branch when none is specified
-	DefaultGitBranch = "main"
 )
 
 // DeploymentReconciler reconciles a Deployment object
@@ -67,6 +62,15 @@ type DeploymentReconciler struct {
 	NamespaceManager *NamespaceManager
 	Notifier         webhooks.Notifier
 	Recorder         record.EventRecorder
+	// Encryptor decrypts env var Secrets encrypted at rest, nil when encryption is disabled
+	Encryptor *envcrypt.Encryptor
+	// ArtifactsURL is the artifact service pipeline steps publish to, empty when artifacts are disabled
+	ArtifactsURL string
+	// Clock stamps webhook events and resolves replica schedules, nil uses the wall clock
+	Clock clock.PassiveClock
+	// RemoteBuilds dispatches PipelineRuns to the build namespace pool, nil runs them in the
+	// project namespaces
+	RemoteBuilds *RemoteBuildExecutor
 }
 
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=deployments,verbs=get;list;watch;create;update;patch;delete
@@ -143,10 +147,45 @@ func (r *DeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// Hold the deployment until the applications it depends on are ready
+	waiting, err := r.waitForDependencies(ctx, &deployment, &app)
+	if err != nil {
+		log.Error(err, "Failed to check application dependencies")
+		return ctrl.Result{}, err
+	}
+	if waiting {
+		return ctrl.Result{RequeueAfter: dependencyRequeueInterval}, nil
+	}
+
+	// Fail the build while the project uses more registry storage than its quota
+	if app.Spec.Type == platformv1alpha1.ApplicationTypeGitRepository {
+		blocked, err := r.checkRegistryQuota(ctx, &deployment)
+		if err != nil {
+			log.Error(err, "Failed to check the project registry quota")
+			return ctrl.Result{}, err
+		}
+		if blocked {
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Hold the build while the project runs as many builds as its concurrency limit allows
+	if app.Spec.Type == platformv1alpha1.ApplicationTypeGitRepository {
+		queued, err := r.waitForBuildSlot(ctx, &deployment)
+		if err != nil {
+			log.Error(err, "Failed to check the project build queue")
+			return ctrl.Result{}, err
+		}
+		if queued {
+			return ctrl.Result{RequeueAfter: buildQueueRequeueInterval}, nil
+		}
+	}
+
 	// Check if Application is of type GitRepository
 	if app.Spec.Type == platformv1alpha1.ApplicationTypeGitRepository {
 		if err := r.handleGitRepositoryDeployment(ctx, &deployment, &app); err != nil {
 			log.Error(err, "Failed to handle GitRepository deployment")
+			recordEvent(r.Recorder, &deployment, corev1.EventTypeWarning, eventReasonReconcileFailed, "%v", err)
 			return ctrl.Result{}, err
 		}
 	}
@@ -155,6 +194,7 @@ func (r *DeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if app.Spec.Type == platformv1alpha1.ApplicationTypeImageFromRegistry {
 		if err := r.handleImageFromRegistryDeployment(ctx, &deployment, &app); err != nil {
 			log.Error(err, "Failed to handle ImageFromRegistry deployment")
+			recordEvent(r.Recorder, &deployment, corev1.EventTypeWarning, eventReasonReconcileFailed, "%v", err)
 			return ctrl.Result{}, err
 		}
 	}
@@ -232,8 +272,24 @@ func (r *DeploymentReconciler) handleDeletion(ctx context.Context, deployment *p
 
 	log.Info("Handling Deployment deletion")
 
-	// TODO: Clean up any deployment-specific resources (e.g., PipelineRuns)
-	// For now, we just remove the finalizer
+	// PipelineRuns in the project namespace are owned by the deployment, the ones dispatched
+	// to the build namespace pool are not
+	if r.RemoteBuilds != nil {
+		if err := r.RemoteBuilds.Cleanup(ctx, deployment); err != nil {
+			log.Error(err, "Failed to clean up remote builds")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The finalizer is only released once no build of the deployment runs anymore
+	done, err := r.teardownDeployment(ctx, deployment)
+	if err != nil {
+		log.Error(err, "Failed to tear down Deployment")
+		return ctrl.Result{}, err
+	}
+	if !done {
+		return ctrl.Result{RequeueAfter: deploymentTeardownRequeueInterval}, nil
+	}
 
 	controllerutil.RemoveFinalizer(deployment, DeploymentFinalizerName)
 	if err := r.Update(ctx, deployment); err != nil {
@@ -314,6 +370,11 @@ func (r *DeploymentReconciler) ensureDeploymentSecret(ctx context.Context, deplo
 			Data: applicationSecret.Data, // Copy data from application secret
 		}
 
+		// Values stay encrypted in the copy, keep the provider annotation with them
+		if provider := applicationSecret.Annotations[envcrypt.AnnotationProvider]; provider != "" {
+			deploymentSecret.Annotations = map[string]string{envcrypt.AnnotationProvider: provider}
+		}
+
 		// Set owner reference to deployment for cascading deletion
 		if err := controllerutil.SetControllerReference(deployment, deploymentSecret, r.Scheme); err != nil {
 			return fmt.Errorf("failed to set controller reference on deployment secret: %w", err)
@@ -325,13 +386,12 @@ func (r *DeploymentReconciler) ensureDeploymentSecret(ctx context.Context, deplo
 
 		log.Info("Successfully created deployment secret", "secretName", deploymentSecretName)
 	} else {
-		// Update existing deployment secret if data has changed
-		// This ensures the deployment secret stays in sync with the application secret
 		log.V(1).Info("Deployment secret already exists", "secretName", deploymentSecretName)
 
 		// Note: We intentionally don't update the secret data automatically to avoid
-		// unexpected changes during deployment. If users want to update env vars,
-		// they should create a new deployment.
+		// unexpected changes during deployment. The DeploymentEnvReconciler reports env vars
+		// changed since through the EnvSynced condition, users resync them explicitly or
+		// create a new deployment.
 	}
 
 	return nil
@@ -346,6 +406,22 @@ func (r *DeploymentReconciler) handleGitRepositoryDeployment(ctx context.Context
 		return fmt.Errorf("GitRepository configuration is required for GitRepository application deployments")
 	}
 
+	// Promoted deployments reuse the image built in the source environment
+	if deployment.Spec.PromotedFrom != nil {
+		log.Info("Skipping pipeline for promoted deployment",
+			"sourceDeployment", deployment.Spec.PromotedFrom.DeploymentUUID,
+			"image", deployment.Spec.PromotedFrom.Image)
+		return nil
+	}
+
+	// External builds already pushed the image
+	if deployment.Spec.ExternalBuild != nil {
+		log.Info("Skipping pipeline for externally built deployment",
+			"provider", deployment.Spec.ExternalBuild.Provider,
+			"image", deployment.Spec.ExternalBuild.Image)
+		return nil
+	}
+
 	// Detect and log BuildType for debugging
 	buildType := app.Spec.GitRepository.BuildType
 	if buildType == "" {
@@ -360,6 +436,12 @@ func (r *DeploymentReconciler) handleGitRepositoryDeployment(ctx context.Context
 			"buildContext", app.Spec.GitRepository.DockerfileBuild.BuildContext)
 	}
 
+	// Log the builder image if BuildType is Buildpacks
+	if buildType == platformv1alpha1.BuildTypeBuildpacks && app.Spec.GitRepository.BuildpacksBuild != nil {
+		log.Info("Buildpacks build configuration",
+			"builderImage", app.Spec.GitRepository.BuildpacksBuild.BuilderImage)
+	}
+
 	// Generate the pipeline name
 	pipelineName := r.generateGitRepositoryPipelineName(ctx, deployment, app)
 
@@ -385,6 +467,11 @@ func (r *DeploymentReconciler) handleGitRepositoryDeployment(ctx context.Context
 		log.Info("GitRepository pipeline already exists", "pipelineName", pipelineName)
 	}
 
+	// Build environment variables reach the build through a secret of their own
+	if err := r.ensureBuildEnvSecret(ctx, deployment, app); err != nil {
+		return err
+	}
+
 	// Create PipelineRun for the deployment
 	if err := r.createPipelineRun(ctx, deployment, app, pipelineName); err != nil {
 		return fmt.Errorf("failed to create PipelineRun: %w", err)
@@ -397,12 +484,13 @@ func (r *DeploymentReconciler) handleGitRepositoryDeployment(ctx context.Context
 func (r *DeploymentReconciler) handleImageFromRegistryDeployment(ctx context.Context, deployment *platformv1alpha1.Deployment, app *platformv1alpha1.Application) error {
 	log := logf.FromContext(ctx).WithValues("deployment", deployment.Name, "application", app.Name)
 
-	// Validate ImageFromRegistry configuration is present in deployment spec
-	if deployment.Spec.ImageFromRegistry == nil {
-		return fmt.Errorf("ImageFromRegistry configuration is required for ImageFromRegistry application deployments")
+	// Validate ImageFromRegistry configuration is present in application spec, deployments
+	// without a tag of their own run the default tag of the application
+	if app.Spec.ImageFromRegistry == nil {
+		return fmt.Errorf("ImageFromRegistry configuration is required for ImageFromRegistry applications")
 	}
 
-	log.Info("Handling ImageFromRegistry deployment", "registry", app.Spec.ImageFromRegistry.Registry, "repository", app.Spec.ImageFromRegistry.Repository, "tag", deployment.Spec.ImageFromRegistry.Tag)
+	log.Info("Handling ImageFromRegistry deployment", "image", app.Spec.ImageFromRegistry.Image(deployment.Spec.ImageFromRegistry))
 
 	// Create Kubernetes Deployment
 	if err := r.createKubernetesDeployment(ctx, deployment, app); err != nil {
@@ -451,7 +539,7 @@ func (r *DeploymentReconciler) createKubernetesDeployment(ctx context.Context, d
 	}
 
 	// Build image name
-	imageName := r.buildImageName(app.Spec.ImageFromRegistry, deployment.Spec.ImageFromRegistry)
+	imageName := app.Spec.ImageFromRegistry.Image(deployment.Spec.ImageFromRegistry)
 
 	// Determine port
 	port := app.Spec.Port
@@ -460,10 +548,39 @@ func (r *DeploymentReconciler) createKubernetesDeployment(ctx context.Context, d
 	}
 
 	// Merge resource requirements
-	resources := r.mergeResources(app.Spec.ImageFromRegistry.Resources, deployment.Spec.ImageFromRegistry.Resources)
+	var deployResources *corev1.ResourceRequirements
+	if deployment.Spec.ImageFromRegistry != nil {
+		deployResources = deployment.Spec.ImageFromRegistry.Resources
+	}
+	resources := r.mergeResources(app.Spec.ImageFromRegistry.Resources, deployResources)
 
 	// Create Kubernetes Deployment
-	replicas := int32(1)
+	env, envFrom, decryptEnv, err := deploymentEnv(ctx, r.Client, r.Encryptor, app, deployment.Namespace, utils.GetDeploymentResourceName(deployment.GetUUID()))
+	if err != nil {
+		return err
+	}
+	envFrom = append(envFrom, externalEnvFrom(app)...)
+	envFrom = append(serviceDiscoveryEnvFrom(app), envFrom...)
+	bindingEnv, err := serviceBindingEnv(ctx, r.Client, app)
+	if err != nil {
+		return err
+	}
+	contextEnv, err := platformEnv(ctx, r.Client, deployment, app, port)
+	if err != nil {
+		return err
+	}
+	env = withPlatformEnv(env, append(bindingEnv, contextEnv...))
+
+	_, priorityClassName, err := ResolvePriorityClasses(ctx, r.Client, deployment.GetProjectUUID())
+	if err != nil {
+		return err
+	}
+	securityLevel, err := ResolveSecurityLevel(ctx, r.Client, deployment.GetProjectUUID())
+	if err != nil {
+		return err
+	}
+
+	replicas := scheduledReplicas(app, currentTime(r.Clock))
 	appUUID := app.GetUUID()
 
 	k8sDep := &appsv1.Deployment{
@@ -499,6 +616,7 @@ func (r *DeploymentReconciler) createKubernetesDeployment(ctx context.Context, d
 					},
 				},
 				Spec: corev1.PodSpec{
+					PriorityClassName: priorityClassName,
 					Containers: []corev1.Container{
 						{
 							Name:  "app",
@@ -509,15 +627,8 @@ func (r *DeploymentReconciler) createKubernetesDeployment(ctx context.Context, d
 									Protocol:      corev1.ProtocolTCP,
 								},
 							},
-							EnvFrom: []corev1.EnvFromSource{
-								{
-									SecretRef: &corev1.SecretEnvSource{
-										LocalObjectReference: corev1.LocalObjectReference{
-											Name: utils.GetDeploymentResourceName(deployment.GetUUID()),
-										},
-									},
-								},
-							},
+							Env:       env,
+							EnvFrom:   envFrom,
 							Resources: *resources,
 						},
 					},
@@ -526,6 +637,10 @@ func (r *DeploymentReconciler) createKubernetesDeployment(ctx context.Context, d
 		},
 	}
 
+	applyEnvDecryption(&k8sDep.Spec.Template.Spec, decryptEnv)
+	applyRuntimeSecurity(&k8sDep.Spec.Template.Spec, app.Spec.SecurityContext)
+	applyPodSecurity(&k8sDep.Spec.Template.Spec, securityLevel)
+
 	// Set owner reference to Deployment CR
 	if err := ctrl.SetControllerReference(deployment, k8sDep, r.Scheme); err != nil {
 		return fmt.Errorf("failed to set controller reference: %w", err)
@@ -539,30 +654,6 @@ func (r *DeploymentReconciler) createKubernetesDeployment(ctx context.Context, d
 	return nil
 }
 
-// buildImageName constructs the full container image name from registry, repository, and tag
-func (r *DeploymentReconciler) buildImageName(appConfig *platformv1alpha1.ImageFromRegistryConfig, deployConfig *platformv1alpha1.ImageFromRegistryDeploymentConfig) string {
-	var registryURL string
-	switch appConfig.Registry {
-	case platformv1alpha1.RegistryTypeDockerHub:
-		registryURL = "docker.io"
-	case platformv1alpha1.RegistryTypeGHCR:
-		registryURL = "ghcr.io"
-	default:
-		registryURL = "docker.io" // fallback
-	}
-
-	// Use deployment tag or fall back to app default tag or "latest"
-	tag := deployConfig.Tag
-	if tag == "" {
-		tag = appConfig.DefaultTag
-		if tag == "" {
-			tag = "latest"
-		}
-	}
-
-	return fmt.Sprintf("%s/%s:%s", registryURL, appConfig.Repository, tag)
-}
-
 // mergeEnvVars merges application and deployment environment variables
 // Deployment env vars override application env vars by name
 func (r *DeploymentReconciler) mergeEnvVars(appEnv []corev1.EnvVar, deployEnv []corev1.EnvVar) []corev1.EnvVar {
@@ -726,14 +817,14 @@ func (r *DeploymentReconciler) ensureApplicationDomain(ctx context.Context, depl
 		return err
 	}
 
-	// Get operator configuration for base domain
-	opConfig, err := GetOperatorConfig()
+	// Resolve the base domain from the application, its project or the operator configuration
+	baseDomain, err := ResolveBaseDomain(ctx, r.Client, app)
 	if err != nil {
-		return fmt.Errorf("failed to get operator configuration: %w", err)
+		return fmt.Errorf("failed to resolve base domain: %w", err)
 	}
 
 	// ImageFromRegistry applications use <deployment-uuid>.apps.<baseDomain>
-	domain := fmt.Sprintf("%s.apps.%s", deploymentUUID, opConfig.Domain)
+	domain := fmt.Sprintf("%s.apps.%s", deploymentUUID, baseDomain)
 
 	// Determine port
 	port := app.Spec.Port
@@ -832,6 +923,15 @@ func (r *DeploymentReconciler) createGitRepositoryPipeline(ctx context.Context,
 		return fmt.Errorf("failed to create pipeline: %w", err)
 	}
 
+	// Record the spec version, a regenerated pipeline builds the deployment the same way
+	if _, ok := deployment.Annotations[pipelines.AnnotationSpecVersion]; !ok {
+		patch := client.MergeFrom(deployment.DeepCopy())
+		metav1.SetMetaDataAnnotation(&deployment.ObjectMeta, pipelines.AnnotationSpecVersion, pipeline.Annotations[pipelines.AnnotationSpecVersion])
+		if err := r.Patch(ctx, deployment, patch); err != nil {
+			return fmt.Errorf("failed to record pipeline spec version: %w", err)
+		}
+	}
+
 	log.Info("Created GitRepository pipeline", "pipeline", pipelineName, "namespace", deployment.Namespace)
 	return nil
 }
@@ -867,18 +967,34 @@ func (r *DeploymentReconciler) getEnvSecretName(app *platformv1alpha1.Applicatio
 	return ""
 }
 
-// getEnvWorkspaceBinding returns a workspace binding for the deployment's env secret
-func (r *DeploymentReconciler) getEnvWorkspaceBinding(deployment *platformv1alpha1.Deployment) *tektonv1.WorkspaceBinding {
+// getEnvWorkspaceBinding returns a workspace binding for the deployment's env secret.
+// Encrypted secrets would only hold ciphertext in the build, an in-memory volume is bound instead
+// and the build tasks reading the env vars decrypt them into it when their pod starts. The build
+// fails when they cannot be decrypted.
+func (r *DeploymentReconciler) getEnvWorkspaceBinding(ctx context.Context, deployment *platformv1alpha1.Deployment) (*tektonv1.WorkspaceBinding, error) {
 	// Use deployment secret instead of application secret
 	deploymentUUID := deployment.GetUUID()
 	secretName := utils.GetDeploymentResourceName(deploymentUUID)
 
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: deployment.Namespace}, secret); err != nil && !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get env secret: %w", err)
+	} else if err == nil && isEncryptedEnvSecret(secret) {
+		if err := prepareEnvDecryption(ctx, r.Client, r.Encryptor, secret); err != nil {
+			return nil, err
+		}
+		return &tektonv1.WorkspaceBinding{
+			Name:     "app-env-vars",
+			EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory},
+		}, nil
+	}
+
 	return &tektonv1.WorkspaceBinding{
 		Name: "app-env-vars",
 		Secret: &corev1.SecretVolumeSource{
 			SecretName: secretName,
 		},
-	}
+	}, nil
 }
 
 // createPipelineRun creates a PipelineRun for the deployment
@@ -892,21 +1008,15 @@ func (r *DeploymentReconciler) createPipelineRun(ctx context.Context, deployment
 	pipelineRunName := fmt.Sprintf("pipeline-run-%s-%d", deploymentUUID, deployment.Generation)
 
 	// Check if PipelineRun already exists for this generation
-	existingPipelineRun := &tektonv1.PipelineRun{}
-	err := r.Get(ctx, types.NamespacedName{
-		Name:      pipelineRunName,
-		Namespace: deployment.Namespace,
-	}, existingPipelineRun)
-
-	if err == nil {
+	existingPipelineRun, err := r.getPipelineRun(ctx, deployment)
+	if err != nil {
+		return fmt.Errorf("failed to check for existing PipelineRun: %w", err)
+	}
+	if existingPipelineRun != nil {
 		log.Info("PipelineRun already exists for this deployment generation", "pipelineRunName", pipelineRunName)
 		return nil
 	}
 
-	if !errors.IsNotFound(err) {
-		return fmt.Errorf("failed to check for existing PipelineRun: %w", err)
-	}
-
 	// Get git configuration from application
 	gitConfig := app.Spec.GitRepository
 	if gitConfig == nil {
@@ -918,7 +1028,7 @@ func (r *DeploymentReconciler) createPipelineRun(ctx context.Context, deployment
 	if gitBranch == "" {
 		gitBranch = gitConfig.Branch
 		if gitBranch == "" {
-			gitBranch = DefaultGitBranch // Final fallback
+			gitBranch = pipelines.DefaultGitBranch // Final fallback
 		}
 	}
 
@@ -930,6 +1040,30 @@ func (r *DeploymentReconciler) createPipelineRun(ctx context.Context, deployment
 	// Generate service account name - must match project controller naming
 	serviceAccountName := fmt.Sprintf("project-%s-sa", projectUUID)
 
+	// Builds run at the build priority of the project so they never preempt running applications
+	buildPriorityClassName, _, err := ResolvePriorityClasses(ctx, r.Client, projectUUID)
+	if err != nil {
+		return err
+	}
+
+	// Builds are kept on the build node pool, away from latency-sensitive application pods
+	buildNodeSelector, buildTolerations := ResolveBuildScheduling(app)
+
+	// Builds go to the least busy healthy daemon of the BuildKit pool. The build cluster of
+	// remote builds runs its own BuildKit, and Buildpacks builds do not use BuildKit at all.
+	builder, buildKitHost := "", pipelines.DefaultBuildKitHost
+	if r.RemoteBuilds == nil && gitConfig.BuildType != platformv1alpha1.BuildTypeBuildpacks {
+		builder, buildKitHost, err = pickBuildKitBuilder(ctx, r.Client)
+		if err != nil {
+			return err
+		}
+	}
+
+	envWorkspace, err := r.getEnvWorkspaceBinding(ctx, deployment)
+	if err != nil {
+		return err
+	}
+
 	pipelineRun := &tektonv1.PipelineRun{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      pipelineRunName,
@@ -937,9 +1071,10 @@ func (r *DeploymentReconciler) createPipelineRun(ctx context.Context, deployment
 			Labels: map[string]string{
 				"app.kubernetes.io/name":       truncateLabel(fmt.Sprintf("pipeline-run-%s", deploymentSlug)),
 				"app.kubernetes.io/managed-by": "kibaship",
-				"app.kubernetes.io/component":  "ci-cd-pipeline-run",
+				"app.kubernetes.io/component":  pipelineRunComponent,
 				"tekton.dev/pipeline":          truncateLabel(pipelineName),
 				"deployment.kibaship.com/name": truncateLabel(deployment.Name),
+				validation.LabelProjectUUID:    projectUUID,
 			},
 			Annotations: map[string]string{
 				"description":                fmt.Sprintf("CI/CD pipeline run for deployment %s", deploymentSlug),
@@ -960,9 +1095,18 @@ func (r *DeploymentReconciler) createPipelineRun(ctx context.Context, deployment
 					Name:  "git-branch",
 					Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: gitBranch},
 				},
+				{
+					Name:  pipelines.ParamBuildKitHost,
+					Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: buildKitHost},
+				},
 			},
 			TaskRunTemplate: tektonv1.PipelineTaskRunTemplate{
 				ServiceAccountName: serviceAccountName,
+				PodTemplate: &pod.Template{
+					PriorityClassName: &buildPriorityClassName,
+					NodeSelector:      buildNodeSelector,
+					Tolerations:       buildTolerations,
+				},
 			},
 			Workspaces: func() []tektonv1.WorkspaceBinding {
 				workspaces := []tektonv1.WorkspaceBinding{
@@ -1002,14 +1146,34 @@ func (r *DeploymentReconciler) createPipelineRun(ctx context.Context, deployment
 					},
 				}
 				// Add env vars workspace (deployment secret)
-				if envWorkspace := r.getEnvWorkspaceBinding(deployment); envWorkspace != nil {
-					workspaces = append(workspaces, *envWorkspace)
+				workspaces = append(workspaces, *envWorkspace)
+				// Add build secrets workspace of Dockerfile builds
+				if gitConfig.BuildType == platformv1alpha1.BuildTypeDockerfile &&
+					gitConfig.DockerfileBuild != nil && gitConfig.DockerfileBuild.BuildSecrets != nil {
+					workspaces = append(workspaces, tektonv1.WorkspaceBinding{
+						Name: "build-secrets",
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: gitConfig.DockerfileBuild.BuildSecrets.Name,
+						},
+					})
+				}
+				// Add build env workspace, kept apart from the runtime env vars
+				if buildEnvWorkspace := getBuildEnvWorkspaceBinding(deployment, app); buildEnvWorkspace != nil {
+					workspaces = append(workspaces, *buildEnvWorkspace)
 				}
 				return workspaces
 			}(),
 		},
 	}
 
+	if builder != "" {
+		pipelineRun.Labels[LabelBuildKitBuilder] = builder
+	}
+
+	if r.RemoteBuilds != nil {
+		return r.RemoteBuilds.Dispatch(ctx, r.Client, deployment, pipelineRun)
+	}
+
 	// Set owner reference to the deployment
 	if err := controllerutil.SetControllerReference(deployment, pipelineRun, r.Scheme); err != nil {
 		return fmt.Errorf("failed to set controller reference: %w", err)
@@ -1019,10 +1183,35 @@ func (r *DeploymentReconciler) createPipelineRun(ctx context.Context, deployment
 		return fmt.Errorf("failed to create PipelineRun: %w", err)
 	}
 
-	log.Info("Created PipelineRun", "pipelineRun", pipelineRunName, "namespace", deployment.Namespace, "commit", deployment.Spec.GitRepository.CommitSHA)
+	log.Info("Created PipelineRun", "pipelineRun", pipelineRunName, "namespace", deployment.Namespace,
+		"commit", deployment.Spec.GitRepository.CommitSHA, "buildkitHost", buildKitHost)
+	recordEvent(r.Recorder, deployment, corev1.EventTypeNormal, eventReasonPipelineRunCreated, "Created PipelineRun %s building branch %s", pipelineRunName, gitBranch)
 	return nil
 }
 
+// getPipelineRun returns the PipelineRun of the current generation of a deployment, in the
+// build namespace pool when builds are dispatched there, nil when it does not exist
+func (r *DeploymentReconciler) getPipelineRun(ctx context.Context, deployment *platformv1alpha1.Deployment) (*tektonv1.PipelineRun, error) {
+	key := types.NamespacedName{
+		Name:      fmt.Sprintf("pipeline-run-%s-%d", deployment.GetUUID(), deployment.Generation),
+		Namespace: deployment.Namespace,
+	}
+	reader := client.Reader(r.Client)
+	if r.RemoteBuilds != nil {
+		key.Namespace = r.RemoteBuilds.Namespace(deployment.GetProjectUUID())
+		reader = r.RemoteBuilds.Client
+	}
+
+	var pipelineRun tektonv1.PipelineRun
+	if err := reader.Get(ctx, key, &pipelineRun); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &pipelineRun, nil
+}
+
 // truncateLabel truncates a label to 63 characters and adds a hash suffix if needed
 func truncateLabel(label string) string {
 	if len(label) <= 63 {
@@ -1074,21 +1263,14 @@ func (r *DeploymentReconciler) checkPipelineRunStatusAndEmitWebhook(ctx context.
 	}
 
 	// Get the PipelineRun for this deployment
-	deploymentUUID := deployment.GetUUID()
-	pipelineRunName := fmt.Sprintf("pipeline-run-%s-%d", deploymentUUID, deployment.Generation)
-	pipelineRun := &tektonv1.PipelineRun{}
-	err := r.Get(ctx, types.NamespacedName{
-		Namespace: deployment.Namespace,
-		Name:      pipelineRunName,
-	}, pipelineRun)
-
+	pipelineRun, err := r.getPipelineRun(ctx, deployment)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			// PipelineRun doesn't exist yet, nothing to report
-			return nil
-		}
 		return fmt.Errorf("failed to get PipelineRun: %w", err)
 	}
+	if pipelineRun == nil {
+		// PipelineRun doesn't exist yet, nothing to report
+		return nil
+	}
 
 	// Get the status condition
 	succeededCondition := pipelineRun.Status.GetCondition("Succeeded")
@@ -1131,6 +1313,7 @@ func (r *DeploymentReconciler) checkPipelineRunStatusAndEmitWebhook(ctx context.
 			Phase:     string(deployment.Status.Phase),
 			Slug:      deployment.GetSlug(),
 		},
+		Source: webhooks.NewDeploymentSource(deployment),
 		PipelineRunRef: &struct {
 			Name   string `json:"name"`
 			Status string `json:"status"`
@@ -1140,7 +1323,7 @@ func (r *DeploymentReconciler) checkPipelineRunStatusAndEmitWebhook(ctx context.
 			Status: currentStatus,
 			Reason: succeededCondition.Reason,
 		},
-		Timestamp: time.Now().UTC(),
+		Timestamp: currentTime(r.Clock).UTC(),
 	}
 	_ = r.Notifier.NotifyOptimizedDeploymentStatusChange(ctx, optimizedEvt)
 
@@ -1186,7 +1369,8 @@ func (r *DeploymentReconciler) createOptimizedWebhookEvent(deployment *platformv
 			Phase:     string(deployment.Status.Phase),
 			Slug:      deployment.GetSlug(),
 		},
-		Timestamp: time.Now().UTC(),
+		Source:    webhooks.NewDeploymentSource(deployment),
+		Timestamp: currentTime(r.Clock).UTC(),
 	}
 
 	if pipelineRun != nil {
@@ -1232,9 +1416,8 @@ func (r *DeploymentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.Service{}).
 		Owns(&platformv1alpha1.ApplicationDomain{}).
 		WithEventFilter(predicate.GenerationChangedPredicate{}). // Only watch spec changes
-		WithOptions(controller.Options{
-			MaxConcurrentReconciles: 10, // Scale: handle multiple deployments concurrently
-		}).
+		WithOptions(controllerOptions("deployment", 10)).        // Scale: handle multiple deployments concurrently
 		Named("deployment").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
 		Complete(r)
 }
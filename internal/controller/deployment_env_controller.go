@@ -0,0 +1,245 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/envcrypt"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	// DeploymentConditionEnvSynced reports whether the env var Secret of a deployment still holds
+	// the env vars of its application
+	DeploymentConditionEnvSynced = "EnvSynced"
+
+	// DeploymentReasonEnvMatchesApplication is the EnvSynced reason of deployments running the
+	// current env vars of their application
+	DeploymentReasonEnvMatchesApplication = "EnvMatchesApplication"
+
+	// DeploymentReasonApplicationEnvChanged is the EnvSynced reason of deployments whose
+	// application env vars changed after the deployment secret was copied
+	DeploymentReasonApplicationEnvChanged = "ApplicationEnvChanged"
+
+	// AnnotationDeploymentEnvHash records the deployment secret values the pods of a Kubernetes
+	// Deployment run with. It is copied to the pod template to roll pods when the secret is resynced.
+	AnnotationDeploymentEnvHash = "platform.kibaship.com/env-hash"
+
+	// applicationEnvSecretType is the type label of the env var Secret of an application
+	applicationEnvSecretType = "application-env-vars"
+)
+
+// DeploymentEnvReconciler compares the env var Secret of each deployment with the env vars of its
+// application. Deployment secrets are never updated with application env changes on their own,
+// the divergence is reported by the EnvSynced condition until the deployment secret is resynced,
+// after which the pods of the deployment are restarted with the new values.
+type DeploymentEnvReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Encryptor decrypts envelope encrypted env var Secrets when the pods are restarted
+	Encryptor *envcrypt.Encryptor
+}
+
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=deployments/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;update;patch
+
+// Reconcile reports env drift of a deployment and rolls its pods when its secret changed
+func (r *DeploymentEnvReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var deployment platformv1alpha1.Deployment
+	if err := r.Get(ctx, req.NamespacedName, &deployment); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !deployment.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	var app platformv1alpha1.Application
+	if err := r.Get(ctx, client.ObjectKey{Namespace: deployment.Namespace, Name: deployment.Spec.ApplicationRef.Name}, &app); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	var deploymentSecret corev1.Secret
+	key := client.ObjectKey{Namespace: deployment.Namespace, Name: utils.GetDeploymentResourceName(deployment.GetUUID())}
+	if err := r.Get(ctx, key, &deploymentSecret); err != nil {
+		// The deployment controller copies the secret, reconciled again once it exists
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if err := r.reportEnvDrift(ctx, &deployment, &deploymentSecret); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.rollPods(ctx, &deployment, &app, &deploymentSecret); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// reportEnvDrift sets the EnvSynced condition from the application and deployment secret values
+func (r *DeploymentEnvReconciler) reportEnvDrift(ctx context.Context, deployment *platformv1alpha1.Deployment, deploymentSecret *corev1.Secret) error {
+	var applicationSecret corev1.Secret
+	key := client.ObjectKey{Namespace: deployment.Namespace, Name: utils.GetApplicationResourceName(deployment.GetApplicationUUID())}
+	if err := r.Get(ctx, key, &applicationSecret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	condition := metav1.Condition{
+		Type:    DeploymentConditionEnvSynced,
+		Status:  metav1.ConditionTrue,
+		Reason:  DeploymentReasonEnvMatchesApplication,
+		Message: "The deployment runs the current env vars of its application",
+	}
+	if envSecretHash(applicationSecret.Data) != envSecretHash(deploymentSecret.Data) {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = DeploymentReasonApplicationEnvChanged
+		condition.Message = fmt.Sprintf("The application env vars changed after this deployment was created. "+
+			"Resync them with POST /v1/deployments/%s/sync-env, or create a new deployment", deployment.GetUUID())
+	}
+
+	// Lock on the resource version, the deployment controller replaces the conditions concurrently
+	patch := client.MergeFromWithOptions(deployment.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	if !meta.SetStatusCondition(&deployment.Status.Conditions, condition) {
+		return nil
+	}
+	if err := r.Status().Patch(ctx, deployment, patch); err != nil {
+		return fmt.Errorf("failed to update deployment env condition: %w", err)
+	}
+	if condition.Status == metav1.ConditionFalse {
+		logf.FromContext(ctx).Info("Deployment env vars diverged from the application", "deployment", deployment.Name)
+	}
+	return nil
+}
+
+// rollPods restarts the pods of the deployment's Kubernetes Deployment with the current values of
+// the deployment secret, once they changed since the pods started
+func (r *DeploymentEnvReconciler) rollPods(ctx context.Context, deployment *platformv1alpha1.Deployment, app *platformv1alpha1.Application, deploymentSecret *corev1.Secret) error {
+	var dep appsv1.Deployment
+	key := client.ObjectKey{Namespace: deployment.Namespace, Name: utils.GetKubernetesDeploymentName(deployment.GetUUID())}
+	if err := r.Get(ctx, key, &dep); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	hash := envSecretHash(deploymentSecret.Data)
+	previous := dep.Annotations[AnnotationDeploymentEnvHash]
+	if previous == hash {
+		return nil
+	}
+
+	patch := client.MergeFrom(dep.DeepCopy())
+	if dep.Annotations == nil {
+		dep.Annotations = map[string]string{}
+	}
+	dep.Annotations[AnnotationDeploymentEnvHash] = hash
+	// The first sync only records the hash, pods already started with these values
+	if previous != "" {
+		// Render the env vars again from the new values, encrypted ones are decrypted when the new pods start
+		env, envFrom, decryptEnv, err := deploymentEnv(ctx, r.Client, r.Encryptor, app, deployment.Namespace, deploymentSecret.Name)
+		if err != nil {
+			return err
+		}
+		envFrom = append(envFrom, externalEnvFrom(app)...)
+		// Service binding credentials are not in the secret, render them again
+		bindingEnv, err := serviceBindingEnv(ctx, r.Client, app)
+		if err != nil {
+			return err
+		}
+		env = withPlatformEnv(env, bindingEnv)
+		for i := range dep.Spec.Template.Spec.Containers {
+			if dep.Spec.Template.Spec.Containers[i].Name == "app" {
+				dep.Spec.Template.Spec.Containers[i].Env = env
+				dep.Spec.Template.Spec.Containers[i].EnvFrom = envFrom
+			}
+		}
+		applyEnvDecryption(&dep.Spec.Template.Spec, decryptEnv)
+		if dep.Spec.Template.Annotations == nil {
+			dep.Spec.Template.Annotations = map[string]string{}
+		}
+		dep.Spec.Template.Annotations[AnnotationDeploymentEnvHash] = hash
+	}
+	if err := r.Patch(ctx, &dep, patch); err != nil {
+		return fmt.Errorf("failed to roll deployment %s: %w", dep.Name, err)
+	}
+	if previous != "" {
+		logf.FromContext(ctx).Info("Deployment env vars resynced, restarting pods", "deployment", dep.Name)
+	}
+	return nil
+}
+
+// deploymentsForApplicationEnv maps the env var Secret of an application to its deployments
+func (r *DeploymentEnvReconciler) deploymentsForApplicationEnv(ctx context.Context, obj client.Object) []ctrl.Request {
+	appUUID := obj.GetLabels()[validation.LabelApplicationUUID]
+	if appUUID == "" {
+		return nil
+	}
+
+	var deployments platformv1alpha1.DeploymentList
+	if err := r.List(ctx, &deployments, client.InNamespace(obj.GetNamespace()), client.MatchingLabels{
+		validation.LabelApplicationUUID: appUUID,
+	}); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list deployments of application env Secret", "secret", obj.GetName())
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(deployments.Items))
+	for _, deployment := range deployments.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{
+			Namespace: deployment.Namespace,
+			Name:      deployment.Name,
+		}})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Deployments are reconciled when created, when their secret or Kubernetes Deployment is written,
+// and when the env var Secret of their application changes.
+func (r *DeploymentEnvReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	isApplicationEnv := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetLabels()["platform.operator.kibaship.com/type"] == applicationEnvSecretType
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&platformv1alpha1.Deployment{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&corev1.Secret{}, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Owns(&appsv1.Deployment{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.deploymentsForApplicationEnv),
+			builder.WithPredicates(isApplicationEnv, predicate.ResourceVersionChangedPredicate{})).
+		WithOptions(controllerOptions("deployment-env", 1)).
+		Named("deployment-env").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
+		Complete(r)
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestDeploymentEnvReconciler(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-web",
+			Namespace: "project-ns",
+			Labels:    map[string]string{validation.LabelResourceUUID: "web"},
+		},
+		Spec: platformv1alpha1.ApplicationSpec{Type: platformv1alpha1.ApplicationTypeGitRepository},
+	}
+	deployment := &platformv1alpha1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "deployment-d1",
+			Namespace: "project-ns",
+			Labels: map[string]string{
+				validation.LabelResourceUUID:    "d1",
+				validation.LabelApplicationUUID: "web",
+			},
+		},
+		Spec: platformv1alpha1.DeploymentSpec{ApplicationRef: corev1.LocalObjectReference{Name: app.Name}},
+	}
+	applicationSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-web",
+			Namespace: "project-ns",
+			Labels: map[string]string{
+				validation.LabelApplicationUUID:       "web",
+				"platform.operator.kibaship.com/type": applicationEnvSecretType,
+			},
+		},
+		Data: map[string][]byte{"LOG_LEVEL": []byte("info")},
+	}
+	deploymentSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "deployment-d1", Namespace: "project-ns"},
+		Data:       map[string][]byte{"LOG_LEVEL": []byte("info")},
+	}
+	envFrom := []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "deployment-d1"},
+	}}}
+	k8sDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "deployment-d1", Namespace: "project-ns"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app", Image: "web:d1", EnvFrom: envFrom}},
+			}},
+		},
+	}
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(app, deployment, applicationSecret, deploymentSecret, k8sDeployment).
+		WithStatusSubresource(deployment).
+		Build()
+	r := &DeploymentEnvReconciler{Client: cl, Scheme: scheme}
+
+	reconcileDeployment := func() (*metav1.Condition, *appsv1.Deployment) {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(deployment)})
+		g.Expect(err).NotTo(HaveOccurred())
+		updated := &platformv1alpha1.Deployment{}
+		g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(deployment), updated)).To(Succeed())
+		dep := &appsv1.Deployment{}
+		g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(k8sDeployment), dep)).To(Succeed())
+		return meta.FindStatusCondition(updated.Status.Conditions, DeploymentConditionEnvSynced), dep
+	}
+
+	// A fresh deployment is in sync, the hash of its pods is recorded without a restart
+	condition, dep := reconcileDeployment()
+	g.Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(condition.Reason).To(Equal(DeploymentReasonEnvMatchesApplication))
+	g.Expect(dep.Annotations[AnnotationDeploymentEnvHash]).To(Equal(envSecretHash(deploymentSecret.Data)))
+	g.Expect(dep.Spec.Template.Annotations).NotTo(HaveKey(AnnotationDeploymentEnvHash))
+
+	// Application env changes are reported, the running pods keep their values
+	applicationSecret.Data = map[string][]byte{"LOG_LEVEL": []byte("debug")}
+	g.Expect(cl.Update(ctx, applicationSecret)).To(Succeed())
+	condition, dep = reconcileDeployment()
+	g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(condition.Reason).To(Equal(DeploymentReasonApplicationEnvChanged))
+	g.Expect(condition.Message).To(ContainSubstring("POST /v1/deployments/d1/sync-env"))
+	g.Expect(dep.Spec.Template.Annotations).NotTo(HaveKey(AnnotationDeploymentEnvHash))
+
+	// Resyncing the deployment secret clears the drift and restarts the pods
+	g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(deploymentSecret), deploymentSecret)).To(Succeed())
+	deploymentSecret.Data = map[string][]byte{"LOG_LEVEL": []byte("debug")}
+	g.Expect(cl.Update(ctx, deploymentSecret)).To(Succeed())
+	condition, dep = reconcileDeployment()
+	g.Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	hash := envSecretHash(deploymentSecret.Data)
+	g.Expect(dep.Annotations[AnnotationDeploymentEnvHash]).To(Equal(hash))
+	g.Expect(dep.Spec.Template.Annotations[AnnotationDeploymentEnvHash]).To(Equal(hash))
+	g.Expect(dep.Spec.Template.Spec.Containers[0].EnvFrom).To(Equal(envFrom))
+	g.Expect(dep.Spec.Template.Spec.Containers[0].Env).To(BeEmpty())
+
+	// Application env Secrets map to the deployments of the application
+	g.Expect(r.deploymentsForApplicationEnv(ctx, applicationSecret)).To(Equal([]reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "project-ns", Name: "deployment-d1"}},
+	}))
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// These specs drive the DeploymentReconciler, the PipelineRunStatusController and the
+// DeploymentProgressController against the envtest API server the way the manager would, one
+// Reconcile call per event, with a fake clock and a recording notifier injected.
+var _ = Describe("Deployment reconcilers", func() {
+	var (
+		ctx                  context.Context
+		clk                  *clocktesting.FakePassiveClock
+		notifier             *recordingNotifier
+		deploymentReconciler *DeploymentReconciler
+		statusController     *PipelineRunStatusController
+		progressController   *DeploymentProgressController
+		testNamespace        *corev1.Namespace
+		testProject          *platformv1alpha1.Project
+		testApplication      *platformv1alpha1.Application
+		testDeployment       *platformv1alpha1.Deployment
+		appUUID              string
+		deploymentUUID       string
+	)
+
+	reconcileDeployment := func() {
+		_, err := deploymentReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(testDeployment)})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	reconcileProgress := func() {
+		_, err := progressController.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(testDeployment)})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	getDeployment := func() *platformv1alpha1.Deployment {
+		deployment := &platformv1alpha1.Deployment{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(testDeployment), deployment)).To(Succeed())
+		return deployment
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clk = clocktesting.NewFakePassiveClock(time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC))
+		notifier = &recordingNotifier{}
+
+		deploymentReconciler = &DeploymentReconciler{
+			Client:           k8sClient,
+			Scheme:           k8sClient.Scheme(),
+			NamespaceManager: NewNamespaceManager(k8sClient),
+			Notifier:         notifier,
+			Clock:            clk,
+		}
+		statusController = &PipelineRunStatusController{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+		progressController = &DeploymentProgressController{
+			Client:           k8sClient,
+			Scheme:           k8sClient.Scheme(),
+			NamespaceManager: NewNamespaceManager(k8sClient),
+			Clock:            clk,
+		}
+
+		uniqueID := time.Now().UnixNano()
+		projectUUID := fmt.Sprintf("project-uuid-%d", uniqueID)
+		envUUID := fmt.Sprintf("env-uuid-%d", uniqueID)
+		appUUID = fmt.Sprintf("app-uuid-%d", uniqueID)
+		deploymentUUID = fmt.Sprintf("deployment-uuid-%d", uniqueID)
+
+		testNamespace = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("test-deployment-integration-%d", uniqueID)},
+		}
+		Expect(k8sClient.Create(ctx, testNamespace)).To(Succeed())
+
+		var err error
+		testProject, err = CreateTestProject(ctx, k8sClient, utils.GetProjectResourceName(projectUUID), projectUUID,
+			"integration", "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+		Expect(err).NotTo(HaveOccurred())
+
+		environment, err := CreateTestEnvironment(ctx, k8sClient, utils.GetEnvironmentResourceName(envUUID), envUUID,
+			"production", testNamespace.Name, testProject.Name, projectUUID)
+		Expect(err).NotTo(HaveOccurred())
+
+		testApplication = &platformv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      utils.GetApplicationResourceName(appUUID),
+				Namespace: testNamespace.Name,
+				Labels: map[string]string{
+					validation.LabelResourceUUID:    appUUID,
+					validation.LabelResourceSlug:    "web",
+					validation.LabelEnvironmentUUID: envUUID,
+					validation.LabelProjectUUID:     projectUUID,
+				},
+			},
+			Spec: platformv1alpha1.ApplicationSpec{
+				EnvironmentRef: corev1.LocalObjectReference{Name: environment.Name},
+				Type:           platformv1alpha1.ApplicationTypeGitRepository,
+				GitRepository: &platformv1alpha1.GitRepositoryConfig{
+					Provider:     platformv1alpha1.GitProviderGitHub,
+					Repository:   "acme/web",
+					Branch:       "main",
+					PublicAccess: true,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, testApplication)).To(Succeed())
+
+		testDeployment = &platformv1alpha1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      utils.GetDeploymentResourceName(deploymentUUID),
+				Namespace: testNamespace.Name,
+				Labels: map[string]string{
+					validation.LabelResourceUUID:    deploymentUUID,
+					validation.LabelResourceSlug:    "web-1",
+					validation.LabelEnvironmentUUID: envUUID,
+					validation.LabelProjectUUID:     projectUUID,
+					validation.LabelApplicationUUID: appUUID,
+				},
+			},
+			Spec: platformv1alpha1.DeploymentSpec{
+				ApplicationRef: corev1.LocalObjectReference{Name: testApplication.Name},
+				GitRepository: &platformv1alpha1.GitRepositoryDeploymentConfig{
+					CommitSHA: "abc123def456",
+					Branch:    "main",
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, testDeployment)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = k8sClient.Delete(ctx, testProject)
+		_ = k8sClient.Delete(ctx, testNamespace)
+	})
+
+	It("waits for the application secret before creating the deployment secret", func() {
+		By("reconciling while the application secret is missing")
+		Expect(reconcileDeploymentTwice(ctx, deploymentReconciler, testDeployment)).To(Succeed())
+
+		deploymentSecretKey := types.NamespacedName{Name: utils.GetDeploymentResourceName(deploymentUUID), Namespace: testNamespace.Name}
+		err := k8sClient.Get(ctx, deploymentSecretKey, &corev1.Secret{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		By("creating the application secret and reconciling again")
+		Expect(k8sClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: utils.GetApplicationResourceName(appUUID), Namespace: testNamespace.Name},
+			Data:       map[string][]byte{"API_KEY": []byte("s3cret")},
+		})).To(Succeed())
+		reconcileDeployment()
+
+		deploymentSecret := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, deploymentSecretKey, deploymentSecret)).To(Succeed())
+		Expect(deploymentSecret.Data).To(HaveKeyWithValue("API_KEY", []byte("s3cret")))
+		Expect(metav1.IsControlledBy(deploymentSecret, getDeployment())).To(BeTrue())
+	})
+
+	It("fails the deployment and notifies once when its PipelineRun fails", func() {
+		Expect(reconcileDeploymentTwice(ctx, deploymentReconciler, testDeployment)).To(Succeed())
+
+		deployment := getDeployment()
+		pipelineRun := &tektonv1.PipelineRun{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{
+			Name:      fmt.Sprintf("pipeline-run-%s-%d", deploymentUUID, deployment.Generation),
+			Namespace: testNamespace.Name,
+		}, pipelineRun)).To(Succeed())
+
+		By("failing the PipelineRun")
+		pipelineRun.Status.MarkFailed("Failed", "Tasks Completed: 3 (Failed: 1, Cancelled 0), Skipped: 0")
+		Expect(k8sClient.Status().Update(ctx, pipelineRun)).To(Succeed())
+
+		_, err := statusController.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(pipelineRun)})
+		Expect(err).NotTo(HaveOccurred())
+
+		condition := meta.FindStatusCondition(getDeployment().Status.Conditions, "PipelineRunReady")
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("Failed"))
+
+		By("reconciling the deployment after the condition changed")
+		reconcileDeployment()
+		reconcileProgress()
+
+		Expect(getDeployment().Status.Phase).To(Equal(platformv1alpha1.DeploymentPhaseFailed))
+
+		events := notifier.DeploymentEvents()
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Type).To(Equal("deployment.pipelinerun.status.changed"))
+		Expect(events[0].NewPhase).To(Equal(string(metav1.ConditionFalse)))
+		Expect(events[0].PipelineRunRef).NotTo(BeNil())
+		Expect(events[0].PipelineRunRef.Name).To(Equal(pipelineRun.Name))
+		Expect(events[0].PipelineRunRef.Reason).To(Equal("Failed"))
+		Expect(events[0].Timestamp).To(BeTemporally("==", clk.Now()))
+
+		By("reconciling again without a new PipelineRun status")
+		reconcileDeployment()
+		Expect(notifier.DeploymentEvents()).To(HaveLen(1))
+	})
+
+	It("promotes a ready deployment at the time of the injected clock", func() {
+		deployment := getDeployment()
+		meta.SetStatusCondition(&deployment.Status.Conditions, metav1.Condition{
+			Type:   "PipelineRunReady",
+			Status: metav1.ConditionTrue,
+			Reason: "Succeeded",
+		})
+		meta.SetStatusCondition(&deployment.Status.Conditions, metav1.Condition{
+			Type:   "K8sDeploymentReady",
+			Status: metav1.ConditionTrue,
+			Reason: "DeploymentReady",
+		})
+		Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+
+		reconcileProgress()
+
+		deployment = getDeployment()
+		Expect(deployment.Status.Phase).To(Equal(platformv1alpha1.DeploymentPhaseSucceeded))
+		Expect(deployment.Annotations).To(HaveKeyWithValue(validation.AnnotationPromotedAt, clk.Now().UTC().Format(time.RFC3339)))
+		Expect(deployment.Annotations).To(HaveKeyWithValue(validation.AnnotationPromotedBy, validation.PromotedByOperator))
+
+		app := &platformv1alpha1.Application{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(testApplication), app)).To(Succeed())
+		Expect(app.Spec.CurrentDeploymentRef).NotTo(BeNil())
+		Expect(app.Spec.CurrentDeploymentRef.Name).To(Equal(testDeployment.Name))
+	})
+})
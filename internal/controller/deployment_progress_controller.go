@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -26,18 +27,20 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/envcrypt"
+	"github.com/kibamail/kibaship/pkg/pullrequest"
 	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
 )
 
 const (
@@ -50,6 +53,14 @@ type DeploymentProgressController struct {
 	client.Client
 	Scheme           *runtime.Scheme
 	NamespaceManager *NamespaceManager
+	// Encryptor decrypts env var Secrets encrypted at rest, nil when encryption is disabled
+	Encryptor *envcrypt.Encryptor
+	// PullRequests comments preview URLs on pull requests, nil disables the comments
+	PullRequests *pullrequest.Commenter
+	// Clock stamps conditions and promotions and resolves freeze windows, nil uses the wall clock
+	Clock clock.PassiveClock
+	// Recorder emits the promotion Events of deployments and applications, nil emits none
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=deployments,verbs=get;list;watch;update;patch
@@ -57,8 +68,11 @@ type DeploymentProgressController struct {
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications,verbs=get;list;watch
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applicationdomains,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=projects,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=environments,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
 
 func (r *DeploymentProgressController) SetupWithManager(mgr ctrl.Manager) error {
 	// Watch for condition changes (which come from PipelineRunWatcherReconciler and DeploymentStatusWatcherReconciler)
@@ -68,10 +82,9 @@ func (r *DeploymentProgressController) SetupWithManager(mgr ctrl.Manager) error
 			predicate.GenerationChangedPredicate{},
 			conditionChangedPredicate{}, // Custom predicate for condition changes
 		)).
-		WithOptions(controller.Options{
-			MaxConcurrentReconciles: 20,
-		}).
+		WithOptions(controllerOptions("deployment-progress", 20)).
 		Named("deployment-progress").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
 		Complete(r)
 }
 
@@ -100,8 +113,19 @@ func (r *DeploymentProgressController) Reconcile(ctx context.Context, req ctrl.R
 	targetPhase := r.computeTargetPhase(&deployment, &app)
 
 	if currentPhase == targetPhase {
-		// Already in correct phase
-		return ctrl.Result{}, nil
+		// Already in correct phase, unless a promotion hook of the deployment is still running
+		if targetPhase != platformv1alpha1.DeploymentPhaseSucceeded || !promotionHookRunning(&deployment) {
+			return ctrl.Result{}, nil
+		}
+		requeueAfter, err := r.checkAndPromoteDeployment(ctx, &deployment)
+		if err != nil {
+			log.Error(err, "Failed to check and promote deployment")
+			return ctrl.Result{}, err
+		}
+		if err := r.Status().Update(ctx, &deployment); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	log.Info("Phase transition",
@@ -109,6 +133,7 @@ func (r *DeploymentProgressController) Reconcile(ctx context.Context, req ctrl.R
 		"to", targetPhase)
 
 	// Perform phase-specific actions
+	var requeueAfter time.Duration
 	switch targetPhase {
 	case platformv1alpha1.DeploymentPhaseDeploying:
 		// PipelineRun succeeded - create K8s resources
@@ -120,10 +145,12 @@ func (r *DeploymentProgressController) Reconcile(ctx context.Context, req ctrl.R
 		// K8s resources created and ready
 		// Check if this deployment should be promoted
 		// Promote if: deployment.Spec.Promote is true OR no current deployment exists
-		if err := r.checkAndPromoteDeployment(ctx, &deployment); err != nil {
+		var err error
+		if requeueAfter, err = r.checkAndPromoteDeployment(ctx, &deployment); err != nil {
 			log.Error(err, "Failed to check and promote deployment")
 			return ctrl.Result{}, err
 		}
+		r.commentPreviewURL(ctx, &deployment, &app)
 
 	case platformv1alpha1.DeploymentPhaseFailed:
 		// PipelineRun failed
@@ -136,7 +163,7 @@ func (r *DeploymentProgressController) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // computeTargetPhase - State machine logic based on application type
@@ -169,6 +196,12 @@ func (r *DeploymentProgressController) computeTargetPhase(
 func (r *DeploymentProgressController) computeTargetPhaseForGitRepository(
 	deployment *platformv1alpha1.Deployment,
 ) platformv1alpha1.DeploymentPhase {
+	// Promoted and externally built deployments have no PipelineRun, their image was built in the
+	// source environment or by the external build
+	if deployment.PrebuiltImage() != "" {
+		return r.computeRolloutPhase(deployment)
+	}
+
 	// Check PipelineRun condition
 	prCondition := meta.FindStatusCondition(deployment.Status.Conditions, "PipelineRunReady")
 
@@ -179,22 +212,7 @@ func (r *DeploymentProgressController) computeTargetPhaseForGitRepository(
 	switch prCondition.Status {
 	case metav1.ConditionTrue:
 		// PipelineRun succeeded - check K8s Deployment readiness
-		k8sCondition := meta.FindStatusCondition(deployment.Status.Conditions, "K8sDeploymentReady")
-
-		if k8sCondition != nil {
-			// Check for crash loop - if detected, mark as Failed
-			if k8sCondition.Reason == "CrashLoopBackOff" {
-				return platformv1alpha1.DeploymentPhaseFailed
-			}
-
-			// Check if pods are ready
-			if k8sCondition.Status == metav1.ConditionTrue {
-				return platformv1alpha1.DeploymentPhaseSucceeded
-			}
-		}
-
-		// Resources created but pods not ready yet (or condition not set)
-		return platformv1alpha1.DeploymentPhaseDeploying
+		return r.computeRolloutPhase(deployment)
 
 	case metav1.ConditionFalse:
 		return platformv1alpha1.DeploymentPhaseFailed
@@ -205,6 +223,29 @@ func (r *DeploymentProgressController) computeTargetPhaseForGitRepository(
 	}
 }
 
+// computeRolloutPhase derives the phase of a GitRepository deployment whose image is available
+// from the readiness of its K8s Deployment
+func (r *DeploymentProgressController) computeRolloutPhase(
+	deployment *platformv1alpha1.Deployment,
+) platformv1alpha1.DeploymentPhase {
+	k8sCondition := meta.FindStatusCondition(deployment.Status.Conditions, "K8sDeploymentReady")
+
+	if k8sCondition != nil {
+		// Check for crash loop - if detected, mark as Failed
+		if k8sCondition.Reason == "CrashLoopBackOff" {
+			return platformv1alpha1.DeploymentPhaseFailed
+		}
+
+		// Check if pods are ready
+		if k8sCondition.Status == metav1.ConditionTrue {
+			return platformv1alpha1.DeploymentPhaseSucceeded
+		}
+	}
+
+	// Resources created but pods not ready yet (or condition not set)
+	return platformv1alpha1.DeploymentPhaseDeploying
+}
+
 // computeTargetPhaseForImageFromRegistry handles ImageFromRegistry applications
 func (r *DeploymentProgressController) computeTargetPhaseForImageFromRegistry(
 	deployment *platformv1alpha1.Deployment,
@@ -280,10 +321,12 @@ func (r *DeploymentProgressController) computeTargetPhaseForValkeyCluster(
 // Promotion happens if:
 // 1. deployment.Spec.Promote is true, OR
 // 2. application has no CurrentDeploymentRef (first successful deployment)
+// The pre-promote hook of the application runs before the promotion and the post-promote hook
+// after it, the returned duration is when to check a running hook again.
 func (r *DeploymentProgressController) checkAndPromoteDeployment(
 	ctx context.Context,
 	deployment *platformv1alpha1.Deployment,
-) error {
+) (time.Duration, error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	// Get the Application
@@ -292,13 +335,16 @@ func (r *DeploymentProgressController) checkAndPromoteDeployment(
 		Name:      deployment.Spec.ApplicationRef.Name,
 		Namespace: deployment.Namespace,
 	}, &app); err != nil {
-		return fmt.Errorf("failed to get application: %w", err)
+		return 0, fmt.Errorf("failed to get application: %w", err)
 	}
 
-	// Check if already promoted
+	// Check if already promoted, its post-promote hook may still be running
 	if app.Spec.CurrentDeploymentRef != nil && app.Spec.CurrentDeploymentRef.Name == deployment.Name {
+		if status := promotionHookStatus(deployment, platformv1alpha1.PromotionHookPostPromote); status != nil {
+			return r.runPostPromoteHook(ctx, deployment, &app, status.PreviousDeployment)
+		}
 		log.V(1).Info("Deployment already promoted")
-		return nil
+		return 0, nil
 	}
 
 	// Determine if promotion should happen
@@ -317,23 +363,120 @@ func (r *DeploymentProgressController) checkAndPromoteDeployment(
 		log.V(1).Info("Deployment not promoted",
 			"promote", deployment.Spec.Promote,
 			"hasCurrentDeployment", app.Spec.CurrentDeploymentRef != nil)
-		return nil
+		return 0, nil
+	}
+
+	// Environments requiring approval only go live through an explicit promotion
+	requiresApproval, err := environmentRequiresApproval(ctx, r.Client, deployment)
+	if err != nil {
+		return 0, err
+	}
+	if requiresApproval {
+		upsertCondition(&deployment.Status.Conditions, metav1.Condition{
+			Type:               DeploymentConditionPromoted,
+			Status:             metav1.ConditionFalse,
+			LastTransitionTime: metav1.NewTime(currentTime(r.Clock)),
+			Reason:             "ApprovalRequired",
+			Message:            "The environment requires approval, promote the deployment to release it",
+		})
+		log.Info("Deployment not promoted", "reason", "environment requires approval")
+		recordEvent(r.Recorder, deployment, corev1.EventTypeNormal, eventReasonPromotionBlocked, "The environment requires approval, promote the deployment to release it")
+		return 0, nil
+	}
+
+	// Freeze windows only protect a running deployment, the first deployment is always promoted
+	if app.Spec.CurrentDeploymentRef != nil && !deployment.Spec.OverrideFreeze {
+		window, until, err := activeFreezeWindow(ctx, r.Client, deployment, currentTime(r.Clock))
+		if err != nil {
+			return 0, err
+		}
+		if window != nil {
+			message := window.BlockedMessage(until)
+			upsertCondition(&deployment.Status.Conditions, metav1.Condition{
+				Type:               DeploymentConditionPromoted,
+				Status:             metav1.ConditionFalse,
+				LastTransitionTime: metav1.NewTime(currentTime(r.Clock)),
+				Reason:             "FreezeWindowActive",
+				Message:            message,
+			})
+			log.Info("Deployment not promoted", "reason", message)
+			recordEvent(r.Recorder, deployment, corev1.EventTypeWarning, eventReasonPromotionBlocked, "%s", message)
+			return 0, nil
+		}
+	}
+
+	// The pre-promote hook runs before the deployment receives traffic
+	if hook := promotionHook(&app, platformv1alpha1.PromotionHookPrePromote); hook != nil {
+		status, err := r.runPromotionHook(ctx, deployment, &app, platformv1alpha1.PromotionHookPrePromote, hook, "")
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case status.Phase == platformv1alpha1.PromotionHookPhaseRunning:
+			upsertCondition(&deployment.Status.Conditions, metav1.Condition{
+				Type:               DeploymentConditionPromoted,
+				Status:             metav1.ConditionFalse,
+				LastTransitionTime: metav1.NewTime(currentTime(r.Clock)),
+				Reason:             "PrePromoteHookRunning",
+				Message:            fmt.Sprintf("Waiting for the pre-promote hook Job %s", status.JobName),
+			})
+			return promotionHookPollInterval, nil
+		case status.Phase == platformv1alpha1.PromotionHookPhaseFailed && promotionHookAborts(hook):
+			upsertCondition(&deployment.Status.Conditions, metav1.Condition{
+				Type:               DeploymentConditionPromoted,
+				Status:             metav1.ConditionFalse,
+				LastTransitionTime: metav1.NewTime(currentTime(r.Clock)),
+				Reason:             "PrePromoteHookFailed",
+				Message:            fmt.Sprintf("The pre-promote hook failed: %s", status.Message),
+			})
+			log.Info("Deployment not promoted", "reason", "pre-promote hook failed")
+			return 0, nil
+		}
 	}
 
 	// Update CurrentDeploymentRef
+	var previous string
+	if app.Spec.CurrentDeploymentRef != nil {
+		previous = app.Spec.CurrentDeploymentRef.Name
+	}
 	app.Spec.CurrentDeploymentRef = &corev1.LocalObjectReference{
 		Name: deployment.Name,
 	}
 
 	if err := r.Update(ctx, &app); err != nil {
-		return fmt.Errorf("failed to update application currentDeploymentRef: %w", err)
-	}
+		return 0, fmt.Errorf("failed to update application currentDeploymentRef: %w", err)
+	}
+
+	// Record the promotion for the release history of the application. Patch refreshes the
+	// object from the server, which drops the unsaved status, so it is restored after it.
+	status := deployment.Status.DeepCopy()
+	patch := client.MergeFrom(deployment.DeepCopy())
+	if deployment.Annotations == nil {
+		deployment.Annotations = map[string]string{}
+	}
+	deployment.Annotations[validation.AnnotationPromotedAt] = currentTime(r.Clock).UTC().Format(time.RFC3339)
+	deployment.Annotations[validation.AnnotationPromotedBy] = validation.PromotedByOperator
+	if err := r.Patch(ctx, deployment, patch); err != nil {
+		return 0, fmt.Errorf("failed to record deployment promotion: %w", err)
+	}
+	deployment.Status = *status
+	upsertCondition(&deployment.Status.Conditions, metav1.Condition{
+		Type:               DeploymentConditionPromoted,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(currentTime(r.Clock)),
+		Reason:             "Promoted",
+		Message:            fmt.Sprintf("Promoted to serve application %s", app.Name),
+	})
 
 	log.Info("Successfully promoted deployment",
 		"deployment", deployment.Name,
 		"application", app.Name,
 		"reason", reason)
-	return nil
+	recordEvent(r.Recorder, deployment, corev1.EventTypeNormal, eventReasonPromoted, "Promoted to serve application %s", app.Name)
+	recordEvent(r.Recorder, &app, corev1.EventTypeNormal, eventReasonPromoted, "Deployment %s now serves the application", deployment.Name)
+
+	// The post-promote hook runs once the deployment receives traffic
+	return r.runPostPromoteHook(ctx, deployment, &app, previous)
 }
 
 func (r *DeploymentProgressController) createKubernetesResources(
@@ -436,29 +579,30 @@ func (r *DeploymentProgressController) ensureKubernetesDeployment(
 	var imageName string
 	switch app.Spec.Type {
 	case platformv1alpha1.ApplicationTypeGitRepository, platformv1alpha1.ApplicationTypeDockerImage:
-		// For GitRepository and Dockerfile apps, use built image from registry
-		imageName = fmt.Sprintf("registry.registry.svc.cluster.local/%s/%s:%s",
+		// For GitRepository and Dockerfile apps, use built image from registry. The image is
+		// referenced by digest when the pipeline reported one, so overwriting the tag never
+		// changes the running workload.
+		imageName = utils.GetBuiltImageName(
 			deployment.Namespace,
 			deployment.GetApplicationUUID(),
-			deployment.GetUUID())
+			deployment.GetUUID(),
+			deployment.Status.ImageDigest)
+		if image := deployment.PrebuiltImage(); image != "" {
+			// Promoted and externally built deployments run the image they were created with
+			imageName = image
+		}
 	case platformv1alpha1.ApplicationTypeImageFromRegistry:
 		// For ImageFromRegistry apps, use the specified image
-		if deployment.Spec.ImageFromRegistry == nil {
-			return fmt.Errorf("ImageFromRegistry deployment missing image configuration")
+		if app.Spec.ImageFromRegistry == nil {
+			return fmt.Errorf("ImageFromRegistry application missing image configuration")
 		}
-		imageName = fmt.Sprintf("%s/%s:%s",
-			app.Spec.ImageFromRegistry.Registry,
-			app.Spec.ImageFromRegistry.Repository,
-			deployment.Spec.ImageFromRegistry.Tag)
+		imageName = app.Spec.ImageFromRegistry.Image(deployment.Spec.ImageFromRegistry)
 	default:
 		return fmt.Errorf("unsupported application type for K8s Deployment creation: %s", app.Spec.Type)
 	}
 
-	// Determine container port from application spec (default 3000)
-	containerPort := r.getApplicationPort(app)
-	if containerPort == 0 {
-		containerPort = 3000
-	}
+	// Determine container port from application spec or the build (default 3000)
+	containerPort := deploymentPort(app, deployment)
 
 	// Resource profile (default to standard)
 	resourceProfile := ResourceProfileStandard
@@ -501,7 +645,28 @@ func (r *DeploymentProgressController) ensureKubernetesDeployment(
 		}
 	}
 
-	replicas := int32(1)
+	env, envFrom, decryptEnv, err := deploymentEnv(ctx, r.Client, r.Encryptor, app, deployment.Namespace, utils.GetDeploymentResourceName(deployment.GetUUID()))
+	if err != nil {
+		return err
+	}
+	envFrom = append(envFrom, externalEnvFrom(app)...)
+	envFrom = append(serviceDiscoveryEnvFrom(app), envFrom...)
+	bindingEnv, err := serviceBindingEnv(ctx, r.Client, app)
+	if err != nil {
+		return err
+	}
+	contextEnv, err := platformEnv(ctx, r.Client, deployment, app, containerPort)
+	if err != nil {
+		return err
+	}
+	env = withPlatformEnv(env, append(bindingEnv, contextEnv...))
+
+	_, priorityClassName, err := ResolvePriorityClasses(ctx, r.Client, deployment.GetProjectUUID())
+	if err != nil {
+		return err
+	}
+
+	replicas := scheduledReplicas(app, currentTime(r.Clock))
 	appUUID := app.GetUUID()
 
 	k8sDep := &appsv1.Deployment{
@@ -537,6 +702,7 @@ func (r *DeploymentProgressController) ensureKubernetesDeployment(
 					},
 				},
 				Spec: corev1.PodSpec{
+					PriorityClassName: priorityClassName,
 					ImagePullSecrets: []corev1.LocalObjectReference{
 						{Name: "registry-image-pull-secret"},
 					},
@@ -551,15 +717,8 @@ func (r *DeploymentProgressController) ensureKubernetesDeployment(
 									Protocol:      corev1.ProtocolTCP,
 								},
 							},
-							EnvFrom: []corev1.EnvFromSource{
-								{
-									SecretRef: &corev1.SecretEnvSource{
-										LocalObjectReference: corev1.LocalObjectReference{
-											Name: utils.GetDeploymentResourceName(deployment.GetUUID()),
-										},
-									},
-								},
-							},
+							Env:       env,
+							EnvFrom:   envFrom,
 							Resources: resourceRequirements,
 							VolumeMounts: []corev1.VolumeMount{
 								{
@@ -587,6 +746,10 @@ func (r *DeploymentProgressController) ensureKubernetesDeployment(
 		},
 	}
 
+	applyEnvDecryption(&k8sDep.Spec.Template.Spec, decryptEnv)
+	applyRuntimeSecurity(&k8sDep.Spec.Template.Spec, app.Spec.SecurityContext)
+	applyPodSecurity(&k8sDep.Spec.Template.Spec, project.Spec.SecurityLevel)
+
 	// Set owner reference to Deployment CR
 	if err := ctrl.SetControllerReference(deployment, k8sDep, r.Scheme); err != nil {
 		return fmt.Errorf("failed to set controller reference: %w", err)
@@ -626,8 +789,9 @@ func (r *DeploymentProgressController) ensureKubernetesService(
 		return err
 	}
 
-	// Determine container port (default 3000)
-	containerPort := int32(3000)
+	// The Service keeps the port of the application and targets the named container port, so it
+	// follows the port each deployment listens on
+	servicePort := applicationPort(app)
 
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -652,8 +816,8 @@ func (r *DeploymentProgressController) ensureKubernetesService(
 				{
 					Name:       "http",
 					Protocol:   corev1.ProtocolTCP,
-					Port:       containerPort,
-					TargetPort: intstr.FromInt32(containerPort),
+					Port:       servicePort,
+					TargetPort: intstr.FromString("http"),
 				},
 			},
 		},
@@ -668,7 +832,7 @@ func (r *DeploymentProgressController) ensureKubernetesService(
 		return fmt.Errorf("failed to create Service: %w", err)
 	}
 
-	log.Info("Created Service", "name", serviceName, "port", containerPort)
+	log.Info("Created Service", "name", serviceName, "port", servicePort)
 	return nil
 }
 
@@ -696,10 +860,10 @@ func (r *DeploymentProgressController) ensureApplicationDomain(ctx context.Conte
 		return err
 	}
 
-	// Get operator configuration for base domain
-	opConfig, err := GetOperatorConfig()
+	// Resolve the base domain from the application, its project or the operator configuration
+	baseDomain, err := ResolveBaseDomain(ctx, r.Client, app)
 	if err != nil {
-		return fmt.Errorf("failed to get operator configuration: %w", err)
+		return fmt.Errorf("failed to resolve base domain: %w", err)
 	}
 
 	// Determine domain pattern based on Application type
@@ -710,7 +874,7 @@ func (r *DeploymentProgressController) ensureApplicationDomain(ctx context.Conte
 	switch app.Spec.Type {
 	case platformv1alpha1.ApplicationTypeGitRepository, platformv1alpha1.ApplicationTypeDockerImage:
 		// Web applications use <deployment-uuid>.apps.<baseDomain>
-		domain = fmt.Sprintf("%s.apps.%s", deploymentUUID, opConfig.Domain)
+		domain = fmt.Sprintf("%s.apps.%s", deploymentUUID, baseDomain)
 	default:
 		return fmt.Errorf("unsupported application type for per-deployment domain creation: %s", app.Spec.Type)
 	}
@@ -784,8 +948,13 @@ func (r *DeploymentProgressController) ensureDeploymentHTTPRoute(
 	deploymentUUID := deployment.GetUUID()
 	appUUID := app.GetUUID()
 
+	baseDomain, err := ResolveBaseDomain(ctx, r.Client, app)
+	if err != nil {
+		return fmt.Errorf("failed to resolve base domain: %w", err)
+	}
+
 	// Generate deployment-specific domain: <deployment-uuid>.apps.<baseDomain>
-	deploymentDomain := fmt.Sprintf("%s.apps.%s", deploymentUUID, opConfig.Domain)
+	deploymentDomain := fmt.Sprintf("%s.apps.%s", deploymentUUID, baseDomain)
 
 	// Determine service name and port
 	serviceName := utils.GetServiceName(appUUID)
@@ -794,19 +963,27 @@ func (r *DeploymentProgressController) ensureDeploymentHTTPRoute(
 		servicePort = 3000 // Default port
 	}
 
-	// Create HTTPRoute for HTTPS traffic
-	httpsRouteName := fmt.Sprintf("httproute-%s", deploymentUUID)
-	if err := r.createHTTPRoute(ctx, deployment.Namespace, httpsRouteName, deploymentDomain, serviceName, servicePort, "https", deployment); err != nil {
-		return fmt.Errorf("failed to create HTTPS HTTPRoute: %w", err)
+	// Create routes for HTTPS traffic and the HTTP->HTTPS redirect
+	route := RouteSpec{
+		Namespace:       deployment.Namespace,
+		Name:            fmt.Sprintf("httproute-%s", deploymentUUID),
+		Hostname:        deploymentDomain,
+		BaseDomain:      customBaseDomain(deploymentDomain, opConfig.Domain),
+		ServiceName:     serviceName,
+		ServicePort:     servicePort,
+		SessionAffinity: app.Spec.SessionAffinity,
+		TLSPolicy:       routeTLSPolicy(false),
+		ProjectUUID:     deployment.GetProjectUUID(),
 	}
-
-	// Create HTTPRoute for HTTP->HTTPS redirect
-	httpRouteName := fmt.Sprintf("httproute-%s-redirect", deploymentUUID)
-	if err := r.createHTTPRedirectRoute(ctx, deployment.Namespace, httpRouteName, deploymentDomain, deployment); err != nil {
-		return fmt.Errorf("failed to create HTTP redirect HTTPRoute: %w", err)
+	if route.BackendProtocol, err = applicationBackendProtocol(ctx, r.Client, app, nil); err != nil {
+		return err
+	}
+	if err := NewRouteProvider(r.Client, r.Scheme, opConfig).EnsureRoute(ctx, route, deployment); err != nil {
+		return err
 	}
 
-	log.Info("Created HTTPRoutes for deployment", "domain", deploymentDomain, "service", serviceName, "port", servicePort)
+	log.Info("Created routes for deployment", "domain", deploymentDomain, "service", serviceName, "port", servicePort,
+		"ingressProvider", opConfig.IngressProvider)
 	return nil
 }
 
@@ -865,23 +1042,19 @@ func (r *DeploymentProgressController) ensureApplicationHTTPRoute(
 		return nil // Gracefully degrade
 	}
 
-	// Determine service name and port for the current deployment
-	serviceName := utils.GetServiceName(app.GetUUID())
-	servicePort := defaultDomain.Spec.Port
-
-	// Create HTTPRoute for HTTPS traffic
-	httpsRouteName := fmt.Sprintf("httproute-app-%s", app.GetUUID())
-	if err := r.createHTTPRoute(ctx, app.Namespace, httpsRouteName, defaultDomain.Spec.Domain, serviceName, servicePort, "https", deployment); err != nil {
-		return fmt.Errorf("failed to create application HTTPS HTTPRoute: %w", err)
+	// Route the default domain including its path routes and response headers
+	route, err := applicationDomainRouteSpec(ctx, r.Client, defaultDomain, app, opConfig)
+	if err != nil {
+		return fmt.Errorf("failed to resolve application routes: %w", err)
 	}
 
-	// Create HTTPRoute for HTTP->HTTPS redirect
-	httpRouteName := fmt.Sprintf("httproute-app-%s-redirect", app.GetUUID())
-	if err := r.createHTTPRedirectRoute(ctx, app.Namespace, httpRouteName, defaultDomain.Spec.Domain, deployment); err != nil {
-		return fmt.Errorf("failed to create application HTTP redirect HTTPRoute: %w", err)
+	// Create routes for HTTPS traffic and the HTTP->HTTPS redirect
+	if err := NewRouteProvider(r.Client, r.Scheme, opConfig).EnsureRoute(ctx, route, deployment); err != nil {
+		return fmt.Errorf("failed to create application routes: %w", err)
 	}
 
-	log.Info("Created/updated application HTTPRoutes", "domain", defaultDomain.Spec.Domain, "service", serviceName, "port", servicePort)
+	log.Info("Created/updated application routes", "domain", defaultDomain.Spec.Domain, "service", route.ServiceName, "port", route.ServicePort,
+		"ingressProvider", opConfig.IngressProvider)
 	return nil
 }
 
@@ -928,168 +1101,6 @@ func conditionsEqual(a, b []metav1.Condition) bool {
 	return true
 }
 
-// createHTTPRoute creates an HTTPRoute for HTTPS traffic
-func (r *DeploymentProgressController) createHTTPRoute(
-	ctx context.Context,
-	namespace, routeName, hostname, serviceName string,
-	servicePort int32,
-	listenerName string,
-	owner metav1.Object,
-) error {
-	log := ctrl.LoggerFrom(ctx)
-
-	// Check if HTTPRoute already exists
-	obj := &unstructured.Unstructured{}
-	obj.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   "gateway.networking.k8s.io",
-		Version: "v1",
-		Kind:    "HTTPRoute",
-	})
-
-	if err := r.Get(ctx, client.ObjectKey{
-		Namespace: namespace,
-		Name:      routeName,
-	}, obj); err == nil {
-		log.V(1).Info("HTTPRoute already exists", "name", routeName)
-		return nil // Already exists
-	} else if !errors.IsNotFound(err) {
-		return err
-	}
-
-	// Create new HTTPRoute
-	obj.SetNamespace(namespace)
-	obj.SetName(routeName)
-
-	// Set labels
-	labels := map[string]string{
-		"app.kubernetes.io/managed-by": "kibaship",
-		"platform.kibaship.com/type":   "httproute",
-	}
-	obj.SetLabels(labels)
-
-	// Set owner reference for cleanup
-	if err := ctrl.SetControllerReference(owner, obj, r.Scheme); err != nil {
-		return fmt.Errorf("failed to set controller reference: %w", err)
-	}
-
-	obj.Object["spec"] = map[string]any{
-		"parentRefs": []any{
-			map[string]any{
-				"name":        "kibaship-gateway",
-				"namespace":   "kibaship",
-				"sectionName": listenerName,
-			},
-		},
-		"hostnames": []any{hostname},
-		"rules": []any{
-			map[string]any{
-				"matches": []any{
-					map[string]any{
-						"path": map[string]any{
-							"type":  "PathPrefix",
-							"value": "/",
-						},
-					},
-				},
-				"backendRefs": []any{
-					map[string]any{
-						"name": serviceName,
-						"port": int64(servicePort),
-					},
-				},
-			},
-		},
-	}
-
-	if err := r.Create(ctx, obj); err != nil {
-		return fmt.Errorf("failed to create HTTPRoute: %w", err)
-	}
-
-	log.Info("Created HTTPRoute", "name", routeName, "hostname", hostname, "service", serviceName, "port", servicePort)
-	return nil
-}
-
-// createHTTPRedirectRoute creates an HTTPRoute for HTTP->HTTPS redirect
-func (r *DeploymentProgressController) createHTTPRedirectRoute(
-	ctx context.Context,
-	namespace, routeName, hostname string,
-	owner metav1.Object,
-) error {
-	log := ctrl.LoggerFrom(ctx)
-
-	// Check if HTTPRoute already exists
-	obj := &unstructured.Unstructured{}
-	obj.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   "gateway.networking.k8s.io",
-		Version: "v1",
-		Kind:    "HTTPRoute",
-	})
-
-	if err := r.Get(ctx, client.ObjectKey{
-		Namespace: namespace,
-		Name:      routeName,
-	}, obj); err == nil {
-		log.V(1).Info("HTTP redirect HTTPRoute already exists", "name", routeName)
-		return nil // Already exists
-	} else if !errors.IsNotFound(err) {
-		return err
-	}
-
-	// Create new HTTPRoute for redirect
-	obj.SetNamespace(namespace)
-	obj.SetName(routeName)
-
-	// Set labels
-	labels := map[string]string{
-		"app.kubernetes.io/managed-by": "kibaship",
-		"platform.kibaship.com/type":   "httproute-redirect",
-	}
-	obj.SetLabels(labels)
-
-	// Set owner reference for cleanup
-	if err := ctrl.SetControllerReference(owner, obj, r.Scheme); err != nil {
-		return fmt.Errorf("failed to set controller reference: %w", err)
-	}
-
-	obj.Object["spec"] = map[string]any{
-		"parentRefs": []any{
-			map[string]any{
-				"name":        "kibaship-gateway",
-				"namespace":   "kibaship",
-				"sectionName": "http",
-			},
-		},
-		"hostnames": []any{hostname},
-		"rules": []any{
-			map[string]any{
-				"matches": []any{
-					map[string]any{
-						"path": map[string]any{
-							"type":  "PathPrefix",
-							"value": "/",
-						},
-					},
-				},
-				"filters": []any{
-					map[string]any{
-						"type": "RequestRedirect",
-						"requestRedirect": map[string]any{
-							"scheme": "https",
-						},
-					},
-				},
-			},
-		},
-	}
-
-	if err := r.Create(ctx, obj); err != nil {
-		return fmt.Errorf("failed to create HTTP redirect HTTPRoute: %w", err)
-	}
-
-	log.Info("Created HTTP redirect HTTPRoute", "name", routeName, "hostname", hostname)
-	return nil
-}
-
 // getApplicationPort returns the port for the application from app.Spec.Port
 func (r *DeploymentProgressController) getApplicationPort(app *platformv1alpha1.Application) int32 {
 	return app.Spec.Port
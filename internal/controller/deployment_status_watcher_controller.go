@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -28,12 +29,12 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/idle"
 	"github.com/kibamail/kibaship/pkg/utils"
 )
 
@@ -112,16 +113,28 @@ func (r *DeploymentStatusWatcherReconciler) Reconcile(ctx context.Context, req c
 	// Derive condition status from K8s Deployment
 	var conditionStatus metav1.ConditionStatus
 	var reason, message string
+	var requeueAfter time.Duration
 
 	if crashLooping {
 		// Pods are crash looping - mark as failed
 		conditionStatus = metav1.ConditionFalse
 		reason = CrashLoopBackOffReason
 		message = crashMessage
+	} else if delay := injectedReadinessDelay(&dep, time.Now()); delay > 0 && k8sDep.Status.ReadyReplicas > 0 {
+		// Tests delay readiness through a fault annotation, the status is checked again once it elapsed
+		conditionStatus = metav1.ConditionFalse
+		reason = "PodsNotReady"
+		message = fmt.Sprintf("Pod readiness delayed by %s through fault injection", delay.Round(time.Second))
+		requeueAfter = delay
 	} else if k8sDep.Status.ReadyReplicas > 0 {
 		conditionStatus = metav1.ConditionTrue
 		reason = "PodsReady"
 		message = fmt.Sprintf("%d/%d pods ready", k8sDep.Status.ReadyReplicas, k8sDep.Status.Replicas)
+	} else if _, sleeping := k8sDep.Annotations[idle.AnnotationIdleReplicas]; sleeping {
+		// Scaled to zero by the environment idle policy, the activator wakes it on the next request
+		conditionStatus = metav1.ConditionTrue
+		reason = "ScaledToZero"
+		message = "Scaled to zero after inactivity"
 	} else if k8sDep.Status.UnavailableReplicas > 0 {
 		conditionStatus = metav1.ConditionFalse
 		reason = "PodsNotReady"
@@ -158,7 +171,7 @@ func (r *DeploymentStatusWatcherReconciler) Reconcile(ctx context.Context, req c
 	logger.V(1).Info("Updated Deployment CR with K8s Deployment status",
 		"ready", currentReady, "condition", conditionStatus)
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // isPodsCrashLooping checks if any pods are crash looping
@@ -231,10 +244,9 @@ func (r *DeploymentStatusWatcherReconciler) SetupWithManager(mgr ctrl.Manager) e
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&appsv1.Deployment{}).
 		WithEventFilter(pred).
-		WithOptions(controller.Options{
-			MaxConcurrentReconciles: 50, // Higher concurrency for status watching
-		}).
+		WithOptions(controllerOptions("deployment-status-watcher", 50)). // Higher concurrency for status watching
 		Named("deployment-status-watcher").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
 		Complete(r)
 }
 
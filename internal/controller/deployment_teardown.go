@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+const (
+	// DeploymentConditionTerminating reports the teardown of a deployment being deleted
+	DeploymentConditionTerminating = "Terminating"
+
+	// DeploymentReasonCancellingBuilds is set while the cancelled PipelineRuns of a deployment
+	// being deleted are stopping
+	DeploymentReasonCancellingBuilds = "CancellingBuilds"
+
+	// deploymentTeardownRequeueInterval is how often deletion checks whether cancelled
+	// PipelineRuns stopped
+	deploymentTeardownRequeueInterval = 5 * time.Second
+
+	// deploymentTeardownTimeout bounds how long deletion waits for cancelled PipelineRuns, so a
+	// missing or stuck Tekton cannot hold the finalizer forever
+	deploymentTeardownTimeout = 2 * time.Minute
+)
+
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;delete
+
+// teardownDeployment cancels the running PipelineRuns of a deployment being deleted, then deletes
+// its workspace PVCs, PipelineRuns, Pipelines and Secrets. It returns false while cancelled
+// PipelineRuns are still stopping, the deployment then reports them in its Terminating condition.
+func (r *DeploymentReconciler) teardownDeployment(ctx context.Context, deployment *platformv1alpha1.Deployment) (bool, error) {
+	log := logf.FromContext(ctx).WithValues("deployment", deployment.Name, "namespace", deployment.Namespace)
+
+	var runs tektonv1.PipelineRunList
+	if err := r.List(ctx, &runs, client.InNamespace(deployment.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list PipelineRuns: %w", err)
+	}
+
+	owned := map[types.UID]bool{}
+	running := 0
+	for i := range runs.Items {
+		run := &runs.Items[i]
+		if !metav1.IsControlledBy(run, deployment) {
+			continue
+		}
+		owned[run.UID] = true
+		if run.IsDone() {
+			continue
+		}
+		running++
+		if run.IsCancelled() {
+			continue
+		}
+		patch := client.MergeFrom(run.DeepCopy())
+		run.Spec.Status = tektonv1.PipelineRunSpecStatusCancelled
+		if err := r.Patch(ctx, run, patch); err != nil && !errors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to cancel PipelineRun %s: %w", run.Name, err)
+		}
+		log.Info("Cancelled PipelineRun of deleted Deployment", "pipelineRun", run.Name)
+	}
+
+	if running > 0 {
+		waited := currentTime(r.Clock).Sub(deployment.DeletionTimestamp.Time)
+		if waited < deploymentTeardownTimeout {
+			return false, r.setTerminatingCondition(ctx, deployment, DeploymentReasonCancellingBuilds,
+				fmt.Sprintf("Waiting for %d cancelled PipelineRuns to stop", running))
+		}
+		log.Info("Cancelled PipelineRuns did not stop in time, deleting them", "running", running, "waited", waited)
+	}
+
+	// Workspace PVCs are created by Tekton from the volume claim templates and owned by the runs
+	var claims corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &claims, client.InNamespace(deployment.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list PersistentVolumeClaims: %w", err)
+	}
+	for i := range claims.Items {
+		if !ownedByAny(&claims.Items[i], owned) {
+			continue
+		}
+		if err := r.Delete(ctx, &claims.Items[i]); client.IgnoreNotFound(err) != nil {
+			return false, fmt.Errorf("failed to delete workspace PVC %s: %w", claims.Items[i].Name, err)
+		}
+	}
+
+	for i := range runs.Items {
+		if !owned[runs.Items[i].UID] {
+			continue
+		}
+		if err := r.Delete(ctx, &runs.Items[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return false, fmt.Errorf("failed to delete PipelineRun %s: %w", runs.Items[i].Name, err)
+		}
+	}
+
+	var pipelines tektonv1.PipelineList
+	if err := r.List(ctx, &pipelines, client.InNamespace(deployment.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list Pipelines: %w", err)
+	}
+	for i := range pipelines.Items {
+		if !metav1.IsControlledBy(&pipelines.Items[i], deployment) {
+			continue
+		}
+		if err := r.Delete(ctx, &pipelines.Items[i]); client.IgnoreNotFound(err) != nil {
+			return false, fmt.Errorf("failed to delete Pipeline %s: %w", pipelines.Items[i].Name, err)
+		}
+	}
+
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets, client.InNamespace(deployment.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list Secrets: %w", err)
+	}
+	for i := range secrets.Items {
+		if !metav1.IsControlledBy(&secrets.Items[i], deployment) {
+			continue
+		}
+		if err := r.Delete(ctx, &secrets.Items[i]); client.IgnoreNotFound(err) != nil {
+			return false, fmt.Errorf("failed to delete Secret %s: %w", secrets.Items[i].Name, err)
+		}
+	}
+
+	log.Info("Tore down build resources of Deployment", "pipelineRuns", len(owned))
+	return true, nil
+}
+
+// setTerminatingCondition reports the progress of the teardown of a deployment being deleted
+func (r *DeploymentReconciler) setTerminatingCondition(ctx context.Context, deployment *platformv1alpha1.Deployment, reason, message string) error {
+	if !meta.SetStatusCondition(&deployment.Status.Conditions, metav1.Condition{
+		Type:               DeploymentConditionTerminating,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: deployment.Generation,
+	}) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, deployment); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to report deployment teardown: %w", err)
+	}
+	return nil
+}
+
+// ownedByAny reports whether obj has an owner among the UIDs of owners
+func ownedByAny(obj metav1.Object, owners map[types.UID]bool) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if owners[ref.UID] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+func TestDeploymentDeletionTearsDownBuilds(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := remoteBuildsScheme(g)
+	clk := clocktesting.NewFakePassiveClock(time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC))
+
+	deletedAt := metav1.NewTime(clk.Now())
+	deployment := &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:              "deployment-dep-1",
+		Namespace:         "project-ns",
+		UID:               "dep-1-uid",
+		DeletionTimestamp: &deletedAt,
+		Finalizers:        []string{DeploymentFinalizerName},
+	}}
+	other := &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name: "deployment-dep-2", Namespace: "project-ns", UID: "dep-2-uid",
+	}}
+	owned := func(owner *platformv1alpha1.Deployment, obj client.Object) client.Object {
+		g.Expect(controllerutil.SetControllerReference(owner, obj, scheme)).To(Succeed())
+		return obj
+	}
+
+	running := owned(deployment, &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name: "pipeline-run-dep-1-2", Namespace: "project-ns", UID: "run-2-uid",
+	}}).(*tektonv1.PipelineRun)
+	finished := owned(deployment, &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name: "pipeline-run-dep-1-1", Namespace: "project-ns", UID: "run-1-uid",
+	}}).(*tektonv1.PipelineRun)
+	finished.Status.MarkSucceeded("Succeeded", "All Tasks have completed executing")
+	otherRun := owned(other, &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name: "pipeline-run-dep-2-1", Namespace: "project-ns", UID: "run-3-uid",
+	}})
+	workspace := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+		Name: "pvc-dep-1", Namespace: "project-ns",
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "tekton.dev/v1", Kind: "PipelineRun", Name: running.Name, UID: running.UID}},
+	}}
+	otherWorkspace := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+		Name: "pvc-dep-2", Namespace: "project-ns",
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "tekton.dev/v1", Kind: "PipelineRun", Name: "pipeline-run-dep-2-1", UID: "run-3-uid"}},
+	}}
+	pipeline := owned(deployment, &tektonv1.Pipeline{ObjectMeta: metav1.ObjectMeta{Name: "pipeline-dep-1", Namespace: "project-ns"}})
+	secret := owned(deployment, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "deployment-dep-1", Namespace: "project-ns"}})
+	appSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "application-app-1", Namespace: "project-ns"}}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(deployment, other, running, finished, otherRun, workspace, otherWorkspace, pipeline, secret, appSecret).
+		WithStatusSubresource(deployment).
+		Build()
+	r := &DeploymentReconciler{Client: c, Scheme: scheme, Clock: clk}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}}
+
+	// The running build is cancelled and the finalizer is held until it stops
+	result, err := r.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(deploymentTeardownRequeueInterval))
+
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(running), running)).To(Succeed())
+	g.Expect(string(running.Spec.Status)).To(Equal(tektonv1.PipelineRunSpecStatusCancelled))
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(workspace), &corev1.PersistentVolumeClaim{})).To(Succeed())
+
+	current := &platformv1alpha1.Deployment{}
+	g.Expect(c.Get(ctx, request.NamespacedName, current)).To(Succeed())
+	g.Expect(current.Finalizers).To(ContainElement(DeploymentFinalizerName))
+	condition := meta.FindStatusCondition(current.Status.Conditions, DeploymentConditionTerminating)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Reason).To(Equal(DeploymentReasonCancellingBuilds))
+	g.Expect(condition.Message).To(ContainSubstring("1 cancelled PipelineRuns"))
+
+	// Once it stopped, the build resources of the deployment go away with the finalizer
+	running.Status.MarkFailed("Cancelled", "PipelineRun was cancelled")
+	g.Expect(c.Update(ctx, running)).To(Succeed())
+
+	result, err = r.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeZero())
+
+	var runs tektonv1.PipelineRunList
+	g.Expect(c.List(ctx, &runs, client.InNamespace("project-ns"))).To(Succeed())
+	g.Expect(runs.Items).To(HaveLen(1))
+	g.Expect(runs.Items[0].Name).To(Equal("pipeline-run-dep-2-1"))
+
+	var claims corev1.PersistentVolumeClaimList
+	g.Expect(c.List(ctx, &claims, client.InNamespace("project-ns"))).To(Succeed())
+	g.Expect(claims.Items).To(HaveLen(1))
+	g.Expect(claims.Items[0].Name).To(Equal("pvc-dep-2"))
+
+	var pipelines tektonv1.PipelineList
+	g.Expect(c.List(ctx, &pipelines, client.InNamespace("project-ns"))).To(Succeed())
+	g.Expect(pipelines.Items).To(BeEmpty())
+
+	var secrets corev1.SecretList
+	g.Expect(c.List(ctx, &secrets, client.InNamespace("project-ns"))).To(Succeed())
+	g.Expect(secrets.Items).To(HaveLen(1))
+	g.Expect(secrets.Items[0].Name).To(Equal("application-app-1"))
+
+	// Without finalizers the fake client removes the deleted deployment
+	g.Expect(c.Get(ctx, request.NamespacedName, current)).NotTo(Succeed())
+}
+
+func TestDeploymentDeletionStopsWaitingForStuckBuilds(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := remoteBuildsScheme(g)
+	clk := clocktesting.NewFakePassiveClock(time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC))
+
+	deletedAt := metav1.NewTime(clk.Now().Add(-deploymentTeardownTimeout))
+	deployment := &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:              "deployment-dep-1",
+		Namespace:         "project-ns",
+		UID:               "dep-1-uid",
+		DeletionTimestamp: &deletedAt,
+		Finalizers:        []string{DeploymentFinalizerName},
+	}}
+	stuck := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "pipeline-run-dep-1-1", Namespace: "project-ns", UID: "run-1-uid"},
+		Spec:       tektonv1.PipelineRunSpec{Status: tektonv1.PipelineRunSpecStatusCancelled},
+	}
+	g.Expect(controllerutil.SetControllerReference(deployment, stuck, scheme)).To(Succeed())
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment, stuck).WithStatusSubresource(deployment).Build()
+	r := &DeploymentReconciler{Client: c, Scheme: scheme, Clock: clk}
+
+	done, err := r.teardownDeployment(ctx, deployment)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(done).To(BeTrue())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(stuck), &tektonv1.PipelineRun{})).NotTo(Succeed())
+}
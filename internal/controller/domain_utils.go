@@ -124,21 +124,26 @@ func GenerateFullDomainForApplicationType(subdomain string, appType platformv1al
 		return "", 0, fmt.Errorf("failed to get operator configuration: %v", err)
 	}
 
+	return GenerateFullDomainForBaseDomain(subdomain, config.Domain, appType)
+}
+
+// GenerateFullDomainForBaseDomain creates the full domain name based on application type under the given base domain
+func GenerateFullDomainForBaseDomain(subdomain, baseDomain string, appType platformv1alpha1.ApplicationType) (string, int32, error) {
 	var fullDomain string
 	var port int32
 
 	switch appType {
 	case platformv1alpha1.ApplicationTypeGitRepository, platformv1alpha1.ApplicationTypeDockerImage, platformv1alpha1.ApplicationTypeImageFromRegistry:
-		fullDomain = fmt.Sprintf("%s.apps.%s", subdomain, config.Domain)
+		fullDomain = fmt.Sprintf("%s.apps.%s", subdomain, baseDomain)
 		port = 3000
 	case platformv1alpha1.ApplicationTypeValkey, platformv1alpha1.ApplicationTypeValkeyCluster:
-		fullDomain = fmt.Sprintf("%s.valkey.%s", subdomain, config.Domain)
+		fullDomain = fmt.Sprintf("%s.valkey.%s", subdomain, baseDomain)
 		port = 6379
 	case platformv1alpha1.ApplicationTypeMySQL, platformv1alpha1.ApplicationTypeMySQLCluster:
-		fullDomain = fmt.Sprintf("%s.mysql.%s", subdomain, config.Domain)
+		fullDomain = fmt.Sprintf("%s.mysql.%s", subdomain, baseDomain)
 		port = 3306
 	case platformv1alpha1.ApplicationTypePostgres, platformv1alpha1.ApplicationTypePostgresCluster:
-		fullDomain = fmt.Sprintf("%s.postgres.%s", subdomain, config.Domain)
+		fullDomain = fmt.Sprintf("%s.postgres.%s", subdomain, baseDomain)
 		port = 5432
 	default:
 		return "", 0, fmt.Errorf("unsupported application type for domain generation: %s", appType)
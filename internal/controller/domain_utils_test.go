@@ -21,6 +21,8 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
 )
 
 var _ = Describe("Domain Utils", func() {
@@ -96,4 +98,29 @@ var _ = Describe("Domain Utils", func() {
 			})
 		})
 	})
+
+	Describe("GenerateFullDomainForBaseDomain", func() {
+		It("uses the given base domain", func() {
+			domain, port, err := GenerateFullDomainForBaseDomain("abc123", "apps.customer.com", platformv1alpha1.ApplicationTypeGitRepository)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(domain).To(Equal("abc123.apps.apps.customer.com"))
+			Expect(port).To(Equal(int32(3000)))
+
+			domain, port, err = GenerateFullDomainForBaseDomain("abc123", "customer.com", platformv1alpha1.ApplicationTypeValkey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(domain).To(Equal("abc123.valkey.customer.com"))
+			Expect(port).To(Equal(int32(6379)))
+		})
+	})
+
+	Describe("customBaseDomain", func() {
+		DescribeTable("base domain detection",
+			func(domain, operatorDomain, expected string) {
+				Expect(customBaseDomain(domain, operatorDomain)).To(Equal(expected))
+			},
+			Entry("operator domain", "abc.apps.kibaship.com", "kibaship.com", ""),
+			Entry("custom base domain", "abc.apps.customer.com", "kibaship.com", "customer.com"),
+			Entry("not a generated domain", "www.customer.com", "kibaship.com", ""),
+		)
+	})
 })
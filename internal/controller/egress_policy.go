@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// egressPolicyResourceType labels the CiliumNetworkPolicies restricting project egress
+const egressPolicyResourceType = "egress-policy"
+
+var ciliumNetworkPolicyGVK = schema.GroupVersionKind{Group: "cilium.io", Version: "v2", Kind: "CiliumNetworkPolicy"}
+
+// egressPolicyName is the name of the egress policy of a project
+func egressPolicyName(projectUUID string) string {
+	return fmt.Sprintf("egress-%s", projectUUID)
+}
+
+// egressPolicySpec renders the CiliumNetworkPolicy spec of a restricted project. Application pods
+// keep cluster DNS, proxied through Cilium to learn the addresses of the allowed domains, and
+// in-cluster traffic. Build pods are not selected and keep pulling sources and images.
+func egressPolicySpec(projectUUID string, egress platformv1alpha1.EgressConfig) map[string]any {
+	rules := []any{
+		map[string]any{
+			"toEndpoints": []any{map[string]any{"matchLabels": map[string]any{
+				"k8s:io.kubernetes.pod.namespace": "kube-system",
+				"k8s:k8s-app":                     "kube-dns",
+			}}},
+			"toPorts": []any{map[string]any{
+				"ports": []any{map[string]any{"port": "53", "protocol": "ANY"}},
+				"rules": map[string]any{"dns": []any{map[string]any{"matchPattern": "*"}}},
+			}},
+		},
+		map[string]any{"toEntities": []any{"cluster"}},
+	}
+
+	if len(egress.AllowedCIDRs) > 0 {
+		cidrs := make([]any, 0, len(egress.AllowedCIDRs))
+		for _, cidr := range egress.AllowedCIDRs {
+			cidrs = append(cidrs, map[string]any{"cidr": cidr})
+		}
+		rules = append(rules, map[string]any{"toCIDRSet": cidrs})
+	}
+
+	if len(egress.AllowedDomains) > 0 {
+		fqdns := make([]any, 0, len(egress.AllowedDomains))
+		for _, domain := range egress.AllowedDomains {
+			if strings.HasPrefix(domain, "*.") {
+				fqdns = append(fqdns, map[string]any{"matchPattern": domain})
+			} else {
+				fqdns = append(fqdns, map[string]any{"matchName": domain})
+			}
+		}
+		rules = append(rules, map[string]any{"toFQDNs": fqdns})
+	}
+
+	return map[string]any{
+		"endpointSelector": map[string]any{"matchLabels": map[string]any{
+			"app.kubernetes.io/component": "application",
+			validation.LabelProjectUUID:   projectUUID,
+		}},
+		"egress": rules,
+	}
+}
+
+// reconcileEgressPolicy restricts the egress of the project applications with a CiliumNetworkPolicy
+// in every namespace holding them. Unrestricted projects have their policies removed.
+func (r *ProjectReconciler) reconcileEgressPolicy(ctx context.Context, project *platformv1alpha1.Project) error {
+	projectUUID := project.GetUUID()
+	egress := project.Spec.Egress
+
+	var namespaces []string
+	if egress.Restricted {
+		var apps platformv1alpha1.ApplicationList
+		if err := r.List(ctx, &apps, client.MatchingLabels{validation.LabelProjectUUID: projectUUID}); err != nil {
+			return fmt.Errorf("failed to list project applications: %w", err)
+		}
+		for _, app := range apps.Items {
+			if !slices.Contains(namespaces, app.Namespace) {
+				namespaces = append(namespaces, app.Namespace)
+			}
+		}
+		sort.Strings(namespaces)
+	}
+
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by": "kibaship",
+		"platform.kibaship.com/type":   egressPolicyResourceType,
+		validation.LabelProjectUUID:    projectUUID,
+	}
+	spec := egressPolicySpec(projectUUID, egress)
+	for _, namespace := range namespaces {
+		policy := &unstructured.Unstructured{}
+		policy.SetGroupVersionKind(ciliumNetworkPolicyGVK)
+		policy.SetNamespace(namespace)
+		policy.SetName(egressPolicyName(projectUUID))
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, policy, func() error {
+			policy.SetLabels(labels)
+			policy.Object["spec"] = spec
+			return nil
+		}); err != nil {
+			if meta.IsNoMatchError(err) {
+				return fmt.Errorf("restricted egress requires Cilium as the cluster CNI: %w", err)
+			}
+			return fmt.Errorf("failed to ensure egress CiliumNetworkPolicy: %w", err)
+		}
+	}
+
+	// Remove the policies of namespaces without project applications, or all of them once unrestricted
+	policies := &unstructured.UnstructuredList{}
+	policies.SetGroupVersionKind(ciliumNetworkPolicyGVK.GroupVersion().WithKind("CiliumNetworkPolicyList"))
+	if err := r.List(ctx, policies, client.MatchingLabels{
+		"platform.kibaship.com/type": egressPolicyResourceType,
+		validation.LabelProjectUUID:  projectUUID,
+	}); err != nil {
+		// The CiliumNetworkPolicy kind is missing without Cilium, nothing to delete then
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list egress CiliumNetworkPolicies: %w", err)
+	}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if slices.Contains(namespaces, policy.GetNamespace()) {
+			continue
+		}
+		if err := r.Delete(ctx, policy); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete egress CiliumNetworkPolicy: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestEgressPolicySpec(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := egressPolicySpec("p1", platformv1alpha1.EgressConfig{
+		Restricted:     true,
+		AllowedCIDRs:   []string{"10.20.0.0/16"},
+		AllowedDomains: []string{"api.stripe.com", "*.amazonaws.com"},
+	})
+	g.Expect(spec["endpointSelector"]).To(Equal(map[string]any{"matchLabels": map[string]any{
+		"app.kubernetes.io/component": "application",
+		validation.LabelProjectUUID:   "p1",
+	}}))
+
+	rules := spec["egress"].([]any)
+	g.Expect(rules).To(HaveLen(4))
+	g.Expect(rules[1]).To(Equal(map[string]any{"toEntities": []any{"cluster"}}))
+	g.Expect(rules[2]).To(Equal(map[string]any{"toCIDRSet": []any{map[string]any{"cidr": "10.20.0.0/16"}}}))
+	g.Expect(rules[3]).To(Equal(map[string]any{"toFQDNs": []any{
+		map[string]any{"matchName": "api.stripe.com"},
+		map[string]any{"matchPattern": "*.amazonaws.com"},
+	}}))
+
+	// Without allowlists only DNS and in-cluster traffic remain
+	g.Expect(egressPolicySpec("p1", platformv1alpha1.EgressConfig{Restricted: true})["egress"]).To(HaveLen(2))
+}
+
+func TestReconcileEgressPolicy(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	project := &platformv1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "project-p1",
+			Labels: map[string]string{validation.LabelResourceUUID: "p1"},
+		},
+		Spec: platformv1alpha1.ProjectSpec{Egress: platformv1alpha1.EgressConfig{
+			Restricted:   true,
+			AllowedCIDRs: []string{"203.0.113.0/24"},
+		}},
+	}
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-web",
+			Namespace: "project-ns",
+			Labels:    map[string]string{validation.LabelProjectUUID: "p1"},
+		},
+	}
+	// Left behind in a namespace the project no longer uses
+	stale := &unstructured.Unstructured{}
+	stale.SetGroupVersionKind(ciliumNetworkPolicyGVK)
+	stale.SetNamespace("old-ns")
+	stale.SetName(egressPolicyName("p1"))
+	stale.SetLabels(map[string]string{
+		"platform.kibaship.com/type": egressPolicyResourceType,
+		validation.LabelProjectUUID:  "p1",
+	})
+
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(project, app, stale).Build()
+	r := &ProjectReconciler{Client: cl, Scheme: scheme}
+
+	getPolicy := func(namespace string) error {
+		policy := &unstructured.Unstructured{}
+		policy.SetGroupVersionKind(ciliumNetworkPolicyGVK)
+		return cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: egressPolicyName("p1")}, policy)
+	}
+
+	g.Expect(r.reconcileEgressPolicy(ctx, project)).To(Succeed())
+	g.Expect(getPolicy("project-ns")).To(Succeed())
+	g.Expect(getPolicy("old-ns")).NotTo(Succeed())
+
+	// Lifting the restriction removes the policies
+	project.Spec.Egress = platformv1alpha1.EgressConfig{}
+	g.Expect(r.reconcileEgressPolicy(ctx, project)).To(Succeed())
+	g.Expect(getPolicy("project-ns")).NotTo(Succeed())
+}
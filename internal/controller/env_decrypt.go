@@ -0,0 +1,349 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/envcrypt"
+	"github.com/kibamail/kibaship/pkg/utils"
+)
+
+const (
+	// EnvDecryptPath is served by the webhook server, pods of deployments whose env vars are
+	// encrypted at rest fetch their decrypted values from it when they start
+	EnvDecryptPath = "/env/decrypt"
+	// EnvDecryptDir holds the decrypted env vars in the app container, one file per variable and
+	// a shell sourceable .env file
+	EnvDecryptDir = "/var/run/kibaship/env"
+	// EnvDecryptFileVar points the app container to the .env file of EnvDecryptDir
+	EnvDecryptFileVar = "KIBASHIP_ENV_FILE"
+
+	// envDecryptAudience is the audience of the service account tokens presented to EnvDecryptPath
+	envDecryptAudience = "kibaship-env-decrypt"
+	// envDecryptCAConfigMap publishes the CA of the webhook server in project namespaces
+	envDecryptCAConfigMap = "kibaship-env-decrypt-ca"
+	// webhookCertSecretName holds the serving certificate of the webhook server
+	webhookCertSecretName = "webhook-server-certs"
+
+	envDecryptContainer   = "decrypt-env"
+	envDecryptImage       = "curlimages/curl:8.11.1"
+	envDecryptVolume      = "decrypted-env"
+	envDecryptTokenVolume = "env-decrypt-token"
+	envDecryptTokenDir    = "/var/run/kibaship/env-decrypt-token"
+	envDecryptCAVolume    = "env-decrypt-ca"
+	envDecryptCADir       = "/var/run/kibaship/env-decrypt-ca"
+
+	// podNameExtra and podUIDExtra identify the pod a service account token is bound to
+	podNameExtra = "authentication.kubernetes.io/pod-name"
+	podUIDExtra  = "authentication.kubernetes.io/pod-uid"
+)
+
+// envVarNamePattern matches the names of env vars that can be exported by a shell
+var envVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// envDecryptURL is the address of EnvDecryptPath behind the webhook Service
+var envDecryptURL = fmt.Sprintf("https://kibaship-webhook-service.%s.svc%s", config.OperatorNamespace, EnvDecryptPath)
+
+// envDecryptScript fetches the decrypted env vars into the in-memory volume. The archive is
+// written next to the values so nothing lands on disk.
+const envDecryptScript = `set -eu
+ARCHIVE="` + EnvDecryptDir + `/.archive"
+curl --fail --silent --show-error --retry 5 --retry-all-errors \
+  --cacert "` + envDecryptCADir + `/ca.crt" \
+  -X POST -H "Authorization: Bearer $(cat "` + envDecryptTokenDir + `/token")" \
+  -o "$ARCHIVE" "$ENV_DECRYPT_URL"
+tar -x -f "$ARCHIVE" -C "` + EnvDecryptDir + `"
+rm -f "$ARCHIVE"
+`
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+
+// prepareEnvDecryption checks that the encrypted env var Secret can be decrypted at pod start and
+// publishes the CA of the webhook server the pods fetch the values from in its namespace
+func prepareEnvDecryption(ctx context.Context, c client.Client, encryptor *envcrypt.Encryptor, secret *corev1.Secret) error {
+	if encryptor == nil {
+		return fmt.Errorf("env secret %s is encrypted but no encryption provider is configured", secret.Name)
+	}
+
+	cert := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: config.OperatorNamespace, Name: webhookCertSecretName}, cert); err != nil {
+		return fmt.Errorf("failed to get webhook serving certificate: %w", err)
+	}
+	ca := cert.Data["ca.crt"]
+	if len(ca) == 0 {
+		return fmt.Errorf("webhook serving certificate %s has no ca.crt", webhookCertSecretName)
+	}
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: envDecryptCAConfigMap, Namespace: secret.Namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, c, configMap, func() error {
+		configMap.Labels = map[string]string{"app.kubernetes.io/managed-by": "kibaship"}
+		configMap.Data = map[string]string{"ca.crt": string(ca)}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to publish env decryption CA: %w", err)
+	}
+	return nil
+}
+
+// applyEnvDecryption adds the init container decrypting the env vars of the app container into an
+// in-memory volume at pod start, or removes it when decrypt is false. The init container
+// authenticates with a service account token bound to the pod.
+func applyEnvDecryption(spec *corev1.PodSpec, decrypt bool) {
+	spec.InitContainers = slices.DeleteFunc(spec.InitContainers, func(container corev1.Container) bool {
+		return container.Name == envDecryptContainer
+	})
+	spec.Volumes = slices.DeleteFunc(spec.Volumes, func(volume corev1.Volume) bool {
+		return volume.Name == envDecryptVolume || volume.Name == envDecryptTokenVolume || volume.Name == envDecryptCAVolume
+	})
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		if container.Name != "app" {
+			continue
+		}
+		container.VolumeMounts = slices.DeleteFunc(container.VolumeMounts, func(mount corev1.VolumeMount) bool {
+			return mount.Name == envDecryptVolume
+		})
+		container.Env = slices.DeleteFunc(container.Env, func(env corev1.EnvVar) bool {
+			return env.Name == EnvDecryptFileVar
+		})
+		if decrypt {
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: envDecryptVolume, MountPath: EnvDecryptDir, ReadOnly: true})
+			container.Env = append(container.Env, corev1.EnvVar{Name: EnvDecryptFileVar, Value: EnvDecryptDir + "/.env"})
+		}
+	}
+	if !decrypt {
+		return
+	}
+
+	spec.Volumes = append(spec.Volumes,
+		corev1.Volume{
+			Name:         envDecryptVolume,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}},
+		},
+		corev1.Volume{
+			Name: envDecryptTokenVolume,
+			VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+					Audience:          envDecryptAudience,
+					ExpirationSeconds: ptr.To[int64](600),
+					Path:              "token",
+				}}},
+			}},
+		},
+		corev1.Volume{
+			Name: envDecryptCAVolume,
+			VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: envDecryptCAConfigMap},
+			}},
+		},
+	)
+	spec.InitContainers = append(spec.InitContainers, corev1.Container{
+		Name:    envDecryptContainer,
+		Image:   mirrorImage(envDecryptImage),
+		Command: []string{"sh", "-c", envDecryptScript},
+		Env:     []corev1.EnvVar{{Name: "ENV_DECRYPT_URL", Value: envDecryptURL}},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: envDecryptVolume, MountPath: EnvDecryptDir},
+			{Name: envDecryptTokenVolume, MountPath: envDecryptTokenDir, ReadOnly: true},
+			{Name: envDecryptCAVolume, MountPath: envDecryptCADir, ReadOnly: true},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			RunAsNonRoot:             ptr.To(true),
+			RunAsUser:                ptr.To[int64](100),
+			ReadOnlyRootFilesystem:   ptr.To(true),
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+	})
+}
+
+// EnvDecryptHandler serves the decrypted env vars of a deployment to its pods as a tar archive.
+// Pods authenticate with a service account token bound to them and the deployment is read from
+// the labels of the pod, so a pod only ever receives the env vars of its own deployment.
+type EnvDecryptHandler struct {
+	Client    client.Client
+	Encryptor *envcrypt.Encryptor
+}
+
+// ServeHTTP implements http.Handler
+func (h *EnvDecryptHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := logf.Log.WithName("env-decrypt")
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.Encryptor == nil {
+		http.Error(w, "env var encryption is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		http.Error(w, "missing bearer token", http.StatusUnauthorized)
+		return
+	}
+	pod, err := h.authenticatePod(req.Context(), token)
+	if err != nil {
+		logger.Info("Rejected env decryption request", "reason", err.Error())
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	archive, err := h.decryptedEnv(req.Context(), pod)
+	if err != nil {
+		logger.Error(err, "Failed to decrypt env vars", "namespace", pod.Namespace, "pod", pod.Name)
+		http.Error(w, "failed to decrypt env vars", http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-tar")
+	_, _ = w.Write(archive)
+}
+
+// authenticatePod returns the pod the service account token is bound to
+func (h *EnvDecryptHandler) authenticatePod(ctx context.Context, token string) (*corev1.Pod, error) {
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{
+		Token:     token,
+		Audiences: []string{envDecryptAudience},
+	}}
+	if err := h.Client.Create(ctx, review); err != nil {
+		return nil, fmt.Errorf("token review failed: %w", err)
+	}
+	if !review.Status.Authenticated || !slices.Contains(review.Status.Audiences, envDecryptAudience) {
+		return nil, fmt.Errorf("token is not valid for %s", envDecryptAudience)
+	}
+
+	parts := strings.Split(review.Status.User.Username, ":")
+	if len(parts) != 4 || parts[0] != "system" || parts[1] != "serviceaccount" {
+		return nil, fmt.Errorf("%s is not a service account", review.Status.User.Username)
+	}
+	podName, podUID := review.Status.User.Extra[podNameExtra], review.Status.User.Extra[podUIDExtra]
+	if len(podName) != 1 || len(podUID) != 1 {
+		return nil, fmt.Errorf("token of %s is not bound to a pod", review.Status.User.Username)
+	}
+
+	pod := &corev1.Pod{}
+	if err := h.Client.Get(ctx, client.ObjectKey{Namespace: parts[2], Name: podName[0]}, pod); err != nil {
+		return nil, fmt.Errorf("failed to get pod %s/%s: %w", parts[2], podName[0], err)
+	}
+	if string(pod.UID) != podUID[0] {
+		return nil, fmt.Errorf("pod %s/%s was replaced", parts[2], podName[0])
+	}
+	return pod, nil
+}
+
+// decryptedEnv returns the archive of the decrypted env vars of the deployment pod belongs to.
+// App pods carry the deployment UUID, build pods the deployment name of their PipelineRun.
+func (h *EnvDecryptHandler) decryptedEnv(ctx context.Context, pod *corev1.Pod) ([]byte, error) {
+	name := pod.Labels["deployment.kibaship.com/name"]
+	if uuid := pod.Labels["platform.kibaship.com/deployment-uuid"]; uuid != "" {
+		name = utils.GetDeploymentResourceName(uuid)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("pod %s/%s does not belong to a deployment", pod.Namespace, pod.Name)
+	}
+
+	deployment := &platformv1alpha1.Deployment{}
+	if err := h.Client.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: name}, deployment); err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s: %w", name, err)
+	}
+	app := &platformv1alpha1.Application{}
+	if err := h.Client.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: deployment.Spec.ApplicationRef.Name}, app); err != nil {
+		return nil, fmt.Errorf("failed to get application of deployment %s: %w", name, err)
+	}
+	secret := &corev1.Secret{}
+	secretName := utils.GetDeploymentResourceName(deployment.GetUUID())
+	if err := h.Client.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: secretName}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get env secret %s: %w", secretName, err)
+	}
+	if !isEncryptedEnvSecret(secret) {
+		return nil, fmt.Errorf("env secret %s is not encrypted", secretName)
+	}
+
+	data, err := h.Encryptor.DecryptData(ctx, secret.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt env secret %s: %w", secretName, err)
+	}
+	templated, err := resolveEnvTemplates(ctx, h.Client, app, data)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range templated {
+		data[key] = []byte(value)
+	}
+	return envArchive(data)
+}
+
+// envArchive packs the variables as one file per variable and a shell sourceable .env file
+func envArchive(values map[string][]byte) ([]byte, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		// Only valid variable names become files, other keys could escape the directory
+		if envVarNamePattern.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var dotenv strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&dotenv, "%s='%s'\n", key, strings.ReplaceAll(string(values[key]), "'", `'\''`))
+	}
+
+	var buf bytes.Buffer
+	archive := tar.NewWriter(&buf)
+	write := func(name string, content []byte) error {
+		if err := archive.WriteHeader(&tar.Header{Name: name, Mode: 0o444, Size: int64(len(content))}); err != nil {
+			return err
+		}
+		_, err := archive.Write(content)
+		return err
+	}
+	for _, key := range keys {
+		if err := write(key, values[key]); err != nil {
+			return nil, err
+		}
+	}
+	if err := write(".env", []byte(dotenv.String())); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
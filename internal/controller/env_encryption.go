@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/envcrypt"
+)
+
+// isEncryptedEnvSecret reports whether the env var Secret holds envelope encrypted values
+func isEncryptedEnvSecret(secret *corev1.Secret) bool {
+	if secret.Annotations[envcrypt.AnnotationProvider] != "" {
+		return true
+	}
+	for _, value := range secret.Data {
+		if envcrypt.IsEncrypted(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// deploymentEnv returns how the deployment env var Secret is projected into the app container.
+// Plain Secrets are referenced through envFrom. Encrypted Secrets are neither referenced nor
+// decrypted here, the returned flag tells the caller to decrypt them at pod start with
+// applyEnvDecryption, so the plaintext never reaches etcd. Templated values of plain Secrets are
+// resolved against the other values of app and set as literal env values, which take precedence
+// over envFrom.
+func deploymentEnv(ctx context.Context, c client.Client, encryptor *envcrypt.Encryptor, app *platformv1alpha1.Application, namespace, secretName string) ([]corev1.EnvVar, []corev1.EnvFromSource, bool, error) {
+	envFrom := []corev1.EnvFromSource{
+		{
+			SecretRef: &corev1.SecretEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+			},
+		},
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: secretName}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, envFrom, false, nil
+		}
+		return nil, nil, false, fmt.Errorf("failed to get env secret: %w", err)
+	}
+	if !isEncryptedEnvSecret(secret) {
+		templated, err := resolveEnvTemplates(ctx, c, app, secret.Data)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return sortedEnv(templated), envFrom, false, nil
+	}
+
+	if err := prepareEnvDecryption(ctx, c, encryptor, secret); err != nil {
+		return nil, nil, false, err
+	}
+	return nil, nil, true, nil
+}
+
+// sortedEnv returns the variables as env vars sorted by name, nil when there are none
+func sortedEnv(values map[string]string) []corev1.EnvVar {
+	if len(values) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	env := make([]corev1.EnvVar, 0, len(keys))
+	for _, key := range keys {
+		env = append(env, corev1.EnvVar{Name: key, Value: values[key]})
+	}
+	return env
+}
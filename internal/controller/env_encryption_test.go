@@ -0,0 +1,241 @@
+package controller
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/envcrypt"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func newTestEncryptor(g *WithT) *envcrypt.Encryptor {
+	provider, err := envcrypt.NewLocalProvider(bytes.Repeat([]byte{7}, 32))
+	g.Expect(err).NotTo(HaveOccurred())
+	return envcrypt.NewEncryptor(provider)
+}
+
+func encryptedEnvObjects(g *WithT, encryptor *envcrypt.Encryptor) (*runtime.Scheme, []client.Object) {
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(authenticationv1.AddToScheme(scheme)).To(Succeed())
+
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "application-web", Namespace: "project-shop",
+			Labels: map[string]string{validation.LabelResourceSlug: "web"}},
+		Spec: platformv1alpha1.ApplicationSpec{Type: platformv1alpha1.ApplicationTypeGitRepository},
+	}
+	deployment := &platformv1alpha1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "deployment-1", Namespace: "project-shop",
+			Labels: map[string]string{validation.LabelResourceUUID: "1"}},
+		Spec: platformv1alpha1.DeploymentSpec{ApplicationRef: corev1.LocalObjectReference{Name: "application-web"}},
+	}
+	data, err := encryptor.EncryptData(context.Background(), map[string][]byte{
+		"HOST": []byte("localhost"),
+		"URL":  []byte("http://${env:HOST}:3000"),
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "deployment-1", Namespace: "project-shop"},
+		Data:       data,
+	}
+	cert := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: webhookCertSecretName, Namespace: config.OperatorNamespace},
+		Data:       map[string][]byte{"ca.crt": []byte("webhook-ca")},
+	}
+	return scheme, []client.Object{app, deployment, secret, cert}
+}
+
+func encryptedEnvFixture(g *WithT, encryptor *envcrypt.Encryptor) (client.Client, *platformv1alpha1.Application) {
+	scheme, objects := encryptedEnvObjects(g, encryptor)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		objects[0].(*platformv1alpha1.Application)
+}
+
+func TestDeploymentEnvDecryptsAtPodStart(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	encryptor := newTestEncryptor(g)
+	c, app := encryptedEnvFixture(g, encryptor)
+
+	// The pod template never references the ciphertext nor holds the values
+	env, envFrom, decrypt, err := deploymentEnv(ctx, c, encryptor, app, "project-shop", "deployment-1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(decrypt).To(BeTrue())
+	g.Expect(env).To(BeEmpty())
+	g.Expect(envFrom).To(BeEmpty())
+
+	// Nothing decrypted is stored, only the CA the init container trusts is published
+	secrets := &corev1.SecretList{}
+	g.Expect(c.List(ctx, secrets, client.InNamespace("project-shop"))).To(Succeed())
+	g.Expect(secrets.Items).To(HaveLen(1))
+	ca := &corev1.ConfigMap{}
+	g.Expect(c.Get(ctx, types.NamespacedName{Namespace: "project-shop", Name: envDecryptCAConfigMap}, ca)).To(Succeed())
+	g.Expect(ca.Data).To(HaveKeyWithValue("ca.crt", "webhook-ca"))
+
+	// Without a provider the values cannot be decrypted
+	_, _, _, err = deploymentEnv(ctx, c, nil, app, "project-shop", "deployment-1")
+	g.Expect(err).To(MatchError(ContainSubstring("no encryption provider is configured")))
+}
+
+func TestApplyEnvDecryption(t *testing.T) {
+	g := NewWithT(t)
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}
+
+	applyEnvDecryption(spec, true)
+	applyEnvDecryption(spec, true)
+	g.Expect(spec.InitContainers).To(HaveLen(1))
+	g.Expect(spec.InitContainers[0].Name).To(Equal(envDecryptContainer))
+	g.Expect(spec.Volumes).To(HaveLen(3))
+	g.Expect(spec.Volumes[0].EmptyDir.Medium).To(Equal(corev1.StorageMediumMemory))
+	g.Expect(spec.Volumes[1].Projected.Sources[0].ServiceAccountToken.Audience).To(Equal(envDecryptAudience))
+	g.Expect(spec.Containers[0].VolumeMounts).To(Equal([]corev1.VolumeMount{{Name: envDecryptVolume, MountPath: EnvDecryptDir, ReadOnly: true}}))
+	g.Expect(spec.Containers[0].Env).To(Equal([]corev1.EnvVar{{Name: EnvDecryptFileVar, Value: EnvDecryptDir + "/.env"}}))
+
+	// Storing the values in plaintext again drops the init container
+	applyEnvDecryption(spec, false)
+	g.Expect(spec.InitContainers).To(BeEmpty())
+	g.Expect(spec.Volumes).To(BeEmpty())
+	g.Expect(spec.Containers[0].VolumeMounts).To(BeEmpty())
+	g.Expect(spec.Containers[0].Env).To(BeEmpty())
+}
+
+func TestEnvWorkspaceBindingOfEncryptedSecret(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	encryptor := newTestEncryptor(g)
+	c, _ := encryptedEnvFixture(g, encryptor)
+	deployment := &platformv1alpha1.Deployment{}
+	g.Expect(c.Get(ctx, types.NamespacedName{Namespace: "project-shop", Name: "deployment-1"}, deployment)).To(Succeed())
+
+	// Builds get an in-memory volume the build tasks decrypt the values into
+	r := &DeploymentReconciler{Client: c, Scheme: c.Scheme(), Encryptor: encryptor}
+	workspace, err := r.getEnvWorkspaceBinding(ctx, deployment)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(workspace.Secret).To(BeNil())
+	g.Expect(workspace.EmptyDir.Medium).To(Equal(corev1.StorageMediumMemory))
+
+	// Builds fail rather than silently losing the env vars
+	r.Encryptor = nil
+	_, err = r.getEnvWorkspaceBinding(ctx, deployment)
+	g.Expect(err).To(MatchError(ContainSubstring("no encryption provider is configured")))
+}
+
+func TestEnvDecryptHandler(t *testing.T) {
+	g := NewWithT(t)
+	encryptor := newTestEncryptor(g)
+	scheme, objects := encryptedEnvObjects(g, encryptor)
+	pod := func(name, uid string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "project-shop", UID: types.UID(uid), Labels: labels}}
+	}
+	objects = append(objects,
+		pod("web-abc", "uid-app", map[string]string{"platform.kibaship.com/deployment-uuid": "1"}),
+		pod("build-abc", "uid-build", map[string]string{"deployment.kibaship.com/name": "deployment-1"}),
+		pod("other", "uid-other", nil),
+	)
+
+	// Each token is bound to a pod, the stale one to an earlier pod of the same name
+	bound := map[string][2]string{
+		"app-token":   {"web-abc", "uid-app"},
+		"build-token": {"build-abc", "uid-build"},
+		"other-token": {"other", "uid-other"},
+		"stale-token": {"web-abc", "uid-old"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			review, ok := obj.(*authenticationv1.TokenReview)
+			if !ok {
+				return c.Create(ctx, obj, opts...)
+			}
+			pod, ok := bound[review.Spec.Token]
+			if !ok {
+				return nil
+			}
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				Audiences:     review.Spec.Audiences,
+				User: authenticationv1.UserInfo{
+					Username: "system:serviceaccount:project-shop:default",
+					Extra: map[string]authenticationv1.ExtraValue{
+						podNameExtra: {pod[0]},
+						podUIDExtra:  {pod[1]},
+					},
+				},
+			}
+			return nil
+		},
+	}).Build()
+	handler := &EnvDecryptHandler{Client: c, Encryptor: encryptor}
+
+	request := func(token string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, EnvDecryptPath, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// App and build pods receive the decrypted and templated values of their deployment
+	for _, token := range []string{"app-token", "build-token"} {
+		response := request(token)
+		g.Expect(response.Code).To(Equal(http.StatusOK))
+		g.Expect(response.Header().Get("Content-Type")).To(Equal("application/x-tar"))
+		g.Expect(response.Body.String()).To(ContainSubstring("URL='http://localhost:3000'"))
+	}
+
+	g.Expect(request("other-token").Code).To(Equal(http.StatusForbidden))
+	g.Expect(request("stale-token").Code).To(Equal(http.StatusUnauthorized))
+	g.Expect(request("invalid").Code).To(Equal(http.StatusUnauthorized))
+	g.Expect(request("").Code).To(Equal(http.StatusUnauthorized))
+
+	handler.Encryptor = nil
+	g.Expect(request("app-token").Code).To(Equal(http.StatusServiceUnavailable))
+}
+
+func TestEnvArchive(t *testing.T) {
+	g := NewWithT(t)
+
+	archive, err := envArchive(map[string][]byte{
+		"QUOTED":  []byte("it's"),
+		"HOST":    []byte("localhost"),
+		"../EVIL": []byte("x"),
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	files := map[string]string{}
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		g.Expect(err).NotTo(HaveOccurred())
+		content, err := io.ReadAll(reader)
+		g.Expect(err).NotTo(HaveOccurred())
+		files[header.Name] = string(content)
+	}
+
+	// Keys that are not variable names never become files
+	g.Expect(files).To(Equal(map[string]string{
+		"HOST":   "localhost",
+		"QUOTED": "it's",
+		".env":   "HOST='localhost'\nQUOTED='it'\\''s'\n",
+	}))
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// resolveEnvTemplates resolves the templated values of the env vars of app into concrete values,
+// returning only the variables that held a reference. References are resolved when the
+// deployment is rolled out, later changes to the referenced values need a new deployment.
+func resolveEnvTemplates(ctx context.Context, c client.Reader, app *platformv1alpha1.Application, data map[string][]byte) (map[string]string, error) {
+	env := make(map[string]string, len(data))
+	templated := false
+	for key, value := range data {
+		env[key] = string(value)
+		templated = templated || platformv1alpha1.HasEnvTemplate(env[key])
+	}
+	if !templated {
+		return nil, nil
+	}
+
+	resolved, err := platformv1alpha1.ResolveEnvTemplates(env, func(reference platformv1alpha1.EnvReference) (string, error) {
+		return resolveEnvReference(ctx, c, app, reference)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve env templates of application %s: %w", app.Name, err)
+	}
+	return resolved, nil
+}
+
+// resolveEnvReference returns the value of an application or domain reference of the env of app
+func resolveEnvReference(ctx context.Context, c client.Reader, app *platformv1alpha1.Application, reference platformv1alpha1.EnvReference) (string, error) {
+	switch reference.Kind {
+	case platformv1alpha1.EnvReferenceDomain:
+		publicURL, err := applicationPublicURL(ctx, c, app)
+		if err != nil {
+			return "", err
+		}
+		if publicURL == "" {
+			return "", fmt.Errorf("%s: the application has no default domain", reference)
+		}
+		_, host, _ := strings.Cut(publicURL, "://")
+		return host, nil
+	case platformv1alpha1.EnvReferenceApp:
+		target, err := environmentApplicationBySlug(ctx, c, app, reference.Name)
+		if err != nil {
+			return "", err
+		}
+		if target == nil {
+			return "", fmt.Errorf("%s: no application of the environment has the slug %s", reference, reference.Name)
+		}
+		switch reference.Field {
+		case platformv1alpha1.EnvAppFieldURL:
+			publicURL, err := applicationPublicURL(ctx, c, target)
+			if err != nil {
+				return "", err
+			}
+			if publicURL == "" {
+				return "", fmt.Errorf("%s: application %s has no default domain", reference, reference.Name)
+			}
+			return publicURL, nil
+		case platformv1alpha1.EnvAppFieldHost:
+			if !servesTraffic(target) {
+				return "", fmt.Errorf("%s: application %s does not serve traffic", reference, reference.Name)
+			}
+			return utils.GetServiceAliasHost(reference.Name, target.Namespace), nil
+		case platformv1alpha1.EnvAppFieldPort:
+			return strconv.Itoa(int(applicationPort(target))), nil
+		}
+	}
+	return "", fmt.Errorf("%s cannot be resolved", reference)
+}
+
+// environmentApplicationBySlug returns the application of the environment of app with the slug,
+// nil when there is none
+func environmentApplicationBySlug(ctx context.Context, c client.Reader, app *platformv1alpha1.Application, slug string) (*platformv1alpha1.Application, error) {
+	var apps platformv1alpha1.ApplicationList
+	if err := c.List(ctx, &apps, client.InNamespace(app.Namespace),
+		client.MatchingLabels{validation.LabelEnvironmentUUID: app.Labels[validation.LabelEnvironmentUUID]}); err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+	for i := range apps.Items {
+		if apps.Items[i].GetSlug() == slug && apps.Items[i].DeletionTimestamp.IsZero() {
+			return &apps.Items[i], nil
+		}
+	}
+	return nil, nil
+}
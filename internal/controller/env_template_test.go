@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestValidateEnvTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{name: "plain values", env: map[string]string{"A": "1", "B": "$5"}},
+		{name: "shell style values", env: map[string]string{"A": "${HOME}/bin", "B": "${PORT:-3000}", "C": "${"}},
+		{name: "escaped reference", env: map[string]string{"A": "$${env:B}"}},
+		{name: "references", env: map[string]string{
+			"API_URL":  "${app:backend.url}/v1",
+			"API_HOST": "${app:backend.host}:${app:backend.port}",
+			"ORIGIN":   "https://${domain:primary}",
+			"CALLBACK": "${env:ORIGIN}/callback",
+		}},
+		{name: "unterminated", env: map[string]string{"A": "${env:B"}, wantErr: "missing its closing }"},
+		{name: "unknown kind", env: map[string]string{"A": "${secret:B}"}, wantErr: "unknown kind"},
+		{name: "unknown app field", env: map[string]string{"A": "${app:backend.ip}"}, wantErr: "must end with .url, .host or .port"},
+		{name: "unknown domain", env: map[string]string{"A": "${domain:secondary}"}, wantErr: "use ${domain:primary}"},
+		{name: "unset variable", env: map[string]string{"A": "${env:B}"}, wantErr: "references a variable that is not set"},
+		{name: "self reference", env: map[string]string{"A": "x${env:A}"}, wantErr: "A -> A"},
+		{name: "cycle", env: map[string]string{"A": "${env:B}", "B": "${env:C}", "C": "${env:A}"}, wantErr: "A -> B -> C -> A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := platformv1alpha1.ValidateEnvTemplates(tt.env)
+			if tt.wantErr == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
+
+func TestResolveEnvTemplates(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	const environmentUUID = "5a4b3c2d-1e0f-4a9b-8c7d-6e5f4a3b2c1d"
+	labels := func(slug string) map[string]string {
+		return map[string]string{validation.LabelResourceSlug: slug, validation.LabelEnvironmentUUID: environmentUUID}
+	}
+	web := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "application-web", Namespace: "project-shop", Labels: labels("web")},
+		Spec:       platformv1alpha1.ApplicationSpec{Type: platformv1alpha1.ApplicationTypeGitRepository},
+	}
+	backend := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "application-backend", Namespace: "project-shop", Labels: labels("backend")},
+		Spec:       platformv1alpha1.ApplicationSpec{Type: platformv1alpha1.ApplicationTypeDockerImage, Port: 8080},
+	}
+	database := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "application-db", Namespace: "project-shop", Labels: labels("db")},
+		Spec:       platformv1alpha1.ApplicationSpec{Type: platformv1alpha1.ApplicationTypePostgres},
+	}
+	domains := []*platformv1alpha1.ApplicationDomain{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "domain-web", Namespace: "project-shop",
+				Labels: map[string]string{ApplicationDomainLabelApplication: "application-web"}},
+			Spec: platformv1alpha1.ApplicationDomainSpec{Domain: "shop.example.com", Default: true, TLSEnabled: true},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "domain-backend", Namespace: "project-shop",
+				Labels: map[string]string{ApplicationDomainLabelApplication: "application-backend"}},
+			Spec: platformv1alpha1.ApplicationDomainSpec{Domain: "api.example.com", Default: true},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(web, backend, database, domains[0], domains[1]).Build()
+
+	resolved, err := resolveEnvTemplates(ctx, c, web, map[string][]byte{
+		"PLAIN":    []byte("unchanged"),
+		"API_URL":  []byte("${app:backend.url}/v1"),
+		"API_ADDR": []byte("${app:backend.host}:${app:backend.port}"),
+		"ORIGIN":   []byte("https://${domain:primary}"),
+		"CALLBACK": []byte("${env:ORIGIN}/callback"),
+		"LITERAL":  []byte("$${env:HOME}"),
+		"SHELL":    []byte("${HOME}"),
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resolved).To(Equal(map[string]string{
+		"API_URL":  "http://api.example.com/v1",
+		"API_ADDR": "app-backend.project-shop.svc:8080",
+		"ORIGIN":   "https://shop.example.com",
+		"CALLBACK": "https://shop.example.com/callback",
+		"LITERAL":  "${env:HOME}",
+		"SHELL":    "${HOME}",
+	}))
+
+	resolved, err = resolveEnvTemplates(ctx, c, web, map[string][]byte{"PLAIN": []byte("unchanged")})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resolved).To(BeNil())
+
+	_, err = resolveEnvTemplates(ctx, c, web, map[string][]byte{"A": []byte("${app:worker.url}")})
+	g.Expect(err).To(MatchError(ContainSubstring("no application of the environment has the slug worker")))
+
+	_, err = resolveEnvTemplates(ctx, c, web, map[string][]byte{"A": []byte("${app:db.host}")})
+	g.Expect(err).To(MatchError(ContainSubstring("application db does not serve traffic")))
+
+	_, err = resolveEnvTemplates(ctx, c, backend, map[string][]byte{"A": []byte("${env:B}"), "B": []byte("${env:A}")})
+	g.Expect(err).To(MatchError(ContainSubstring("A -> B -> A")))
+}
+
+func TestDeploymentEnvResolvesTemplates(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "application-web", Namespace: "project-shop",
+			Labels: map[string]string{validation.LabelResourceSlug: "web"}},
+		Spec: platformv1alpha1.ApplicationSpec{Type: platformv1alpha1.ApplicationTypeGitRepository},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "deployment-1", Namespace: "project-shop"},
+		Data:       map[string][]byte{"HOST": []byte("localhost"), "URL": []byte("http://${env:HOST}:3000")},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app, secret).Build()
+
+	// The plain values stay in envFrom, the templated ones override them
+	env, envFrom, decrypt, err := deploymentEnv(ctx, c, nil, app, "project-shop", "deployment-1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(decrypt).To(BeFalse())
+	g.Expect(envFrom).To(HaveLen(1))
+	g.Expect(env).To(Equal([]corev1.EnvVar{{Name: "URL", Value: "http://localhost:3000"}}))
+}
@@ -21,10 +21,12 @@ import (
 	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -45,6 +47,8 @@ type EnvironmentReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Notifier webhooks.Notifier
+	// Recorder emits the Events of environments, nil emits none
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=environments,verbs=get;list;watch;create;update;patch;delete
@@ -88,6 +92,7 @@ func (r *EnvironmentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	labelsUpdated, err := r.ensureUUIDLabels(ctx, &environment)
 	if err != nil {
 		log.Error(err, "Failed to ensure UUID labels")
+		recordEvent(r.Recorder, &environment, corev1.EventTypeWarning, eventReasonValidationFailed, "%v", err)
 		return ctrl.Result{}, err
 	}
 
@@ -115,6 +120,7 @@ func (r *EnvironmentReconciler) handleDeletion(ctx context.Context, environment
 	}
 
 	log.Info("Handling Environment deletion")
+	recordEvent(r.Recorder, environment, corev1.EventTypeNormal, eventReasonDeleting, "Deleting the applications of the environment")
 
 	// Delete all Applications associated with this Environment
 	if err := r.deleteAssociatedApplications(ctx, environment); err != nil {
@@ -169,11 +175,15 @@ func (r *EnvironmentReconciler) handleEnvironmentReconcile(ctx context.Context,
 	// Update environment status
 	if err := r.updateEnvironmentStatus(ctx, environment); err != nil {
 		log.Error(err, "Failed to update Environment status")
+		recordEvent(r.Recorder, environment, corev1.EventTypeWarning, eventReasonReconcileFailed, "%v", err)
 		return ctrl.Result{}, err
 	}
 
 	// Emit webhook on phase transition
 	r.emitEnvironmentPhaseChange(ctx, environment, prevPhase, environment.Status.Phase)
+	if prevPhase != environment.Status.Phase {
+		recordEvent(r.Recorder, environment, corev1.EventTypeNormal, eventReasonReady, "Environment is ready with %d applications", environment.Status.ApplicationCount)
+	}
 
 	log.Info("Successfully reconciled Environment")
 	return ctrl.Result{}, nil
@@ -307,6 +317,8 @@ func (r *EnvironmentReconciler) emitEnvironmentPhaseChange(ctx context.Context,
 func (r *EnvironmentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&platformv1alpha1.Environment{}).
+		WithOptions(controllerOptions("environment", 1)).
 		Named("environment").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
 		Complete(r)
 }
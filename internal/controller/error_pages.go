@@ -0,0 +1,481 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	// errorPagesPort is the port the error pages backend of a project listens on
+	errorPagesPort int32 = 8080
+	// errorPagesServerImage serves the HTML error pages uploaded for a project
+	errorPagesServerImage = "nginxinc/nginx-unprivileged:1.27-alpine"
+	// errorPagesStatusCodes are the backend responses replaced by the error pages
+	errorPagesStatusCodes = "500,502,503,504"
+	// errorPagesResourceType labels the resources of the error pages backend
+	errorPagesResourceType = "error-pages"
+)
+
+// errorPagesServerConfig answers with the status code sent by ingress-nginx in X-Code or
+// requested by Traefik as /<status>, and with 404 otherwise, rendering the uploaded pages
+const errorPagesServerConfig = `server {
+    listen 8080;
+    root /usr/share/nginx/html;
+    error_page 404 /404.html;
+    error_page 500 502 503 504 /5xx.html;
+
+    location ~ ^/(404|5xx)\.html$ {
+        internal;
+    }
+
+    location / {
+        set $code $http_x_code;
+        if ($code = "") {
+            set $code $uri;
+        }
+        if ($code ~ "500$") {
+            return 500;
+        }
+        if ($code ~ "502$") {
+            return 502;
+        }
+        if ($code ~ "503$") {
+            return 503;
+        }
+        if ($code ~ "504$") {
+            return 504;
+        }
+        return 404;
+    }
+}
+`
+
+// errorPagesName is the name of the error pages resources of a project
+func errorPagesName(projectUUID string) string {
+	return fmt.Sprintf("error-pages-%s", projectUUID)
+}
+
+// hasErrorPages reports whether the project of a route serves error pages in namespace
+func hasErrorPages(ctx context.Context, c client.Client, namespace, projectUUID string) (bool, error) {
+	if projectUUID == "" {
+		return false, nil
+	}
+	var service corev1.Service
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: errorPagesName(projectUUID)}, &service); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get error pages Service: %w", err)
+	}
+	return true, nil
+}
+
+// setErrorPagesAnnotations adds or removes the annotations sending the backend errors of an
+// Ingress to the error pages of its project
+func setErrorPagesAnnotations(annotations map[string]string, provider config.IngressProvider, namespace, projectUUID string, enabled bool) {
+	switch provider {
+	case config.IngressProviderNginx:
+		if enabled {
+			annotations["nginx.ingress.kubernetes.io/default-backend"] = errorPagesName(projectUUID)
+			annotations["nginx.ingress.kubernetes.io/custom-http-errors"] = errorPagesStatusCodes
+		} else {
+			delete(annotations, "nginx.ingress.kubernetes.io/default-backend")
+			delete(annotations, "nginx.ingress.kubernetes.io/custom-http-errors")
+		}
+	case config.IngressProviderTraefik:
+		const key = "traefik.ingress.kubernetes.io/router.middlewares"
+		var middlewares []string
+		if annotations[key] != "" {
+			middlewares = strings.Split(annotations[key], ",")
+		}
+		ref := fmt.Sprintf("%s-%s@kubernetescrd", namespace, errorPagesName(projectUUID))
+		middlewares = slices.DeleteFunc(middlewares, func(m string) bool { return m == ref })
+		if enabled && projectUUID != "" {
+			middlewares = append(middlewares, ref)
+		}
+		if len(middlewares) == 0 {
+			delete(annotations, key)
+		} else {
+			annotations[key] = strings.Join(middlewares, ",")
+		}
+	}
+}
+
+// reconcileErrorPages runs the error pages backend of a project in every namespace holding its
+// applications, wires it into the project Ingresses and answers unmatched hosts of the project
+// base domain. Disabling the error pages removes all of it.
+func (r *ProjectReconciler) reconcileErrorPages(ctx context.Context, project *platformv1alpha1.Project) error {
+	projectUUID := project.GetUUID()
+	pages := project.Spec.ErrorPages
+
+	var namespaces []string
+	if pages.Enabled() {
+		var apps platformv1alpha1.ApplicationList
+		if err := r.List(ctx, &apps, client.MatchingLabels{validation.LabelProjectUUID: projectUUID}); err != nil {
+			return fmt.Errorf("failed to list project applications: %w", err)
+		}
+		for _, app := range apps.Items {
+			if !slices.Contains(namespaces, app.Namespace) {
+				namespaces = append(namespaces, app.Namespace)
+			}
+		}
+		sort.Strings(namespaces)
+	}
+
+	opConfig, err := GetOperatorConfig()
+	if err != nil {
+		opConfig = nil
+	}
+
+	for _, namespace := range namespaces {
+		if err := r.ensureErrorPagesBackend(ctx, namespace, projectUUID, pages, opConfig); err != nil {
+			return err
+		}
+	}
+
+	// Remove the backends of namespaces without project applications, or all of them once disabled
+	var services corev1.ServiceList
+	if err := r.List(ctx, &services, client.MatchingLabels{
+		"platform.kibaship.com/type": errorPagesResourceType,
+		validation.LabelProjectUUID:  projectUUID,
+	}); err != nil {
+		return fmt.Errorf("failed to list error pages Services: %w", err)
+	}
+	for _, service := range services.Items {
+		if !slices.Contains(namespaces, service.Namespace) {
+			if err := r.deleteErrorPagesBackend(ctx, service.Namespace, projectUUID, opConfig); err != nil {
+				return err
+			}
+		}
+	}
+
+	if opConfig == nil || opConfig.Domain == "" {
+		return nil
+	}
+	return r.reconcileErrorPagesFallbackRoute(ctx, project, namespaces, opConfig)
+}
+
+// ensureErrorPagesBackend creates or updates the error pages Deployment and Service of a project
+// in namespace, with the Traefik errors Middleware, and wires them into the project Ingresses
+func (r *ProjectReconciler) ensureErrorPagesBackend(
+	ctx context.Context,
+	namespace, projectUUID string,
+	pages platformv1alpha1.ErrorPagesConfig,
+	opConfig *OperatorConfig,
+) error {
+	name := errorPagesName(projectUUID)
+	labels := map[string]string{
+		"app.kubernetes.io/name":       name,
+		"app.kubernetes.io/managed-by": "kibaship",
+		"platform.kibaship.com/type":   errorPagesResourceType,
+		validation.LabelProjectUUID:    projectUUID,
+	}
+	selector := map[string]string{"app.kubernetes.io/name": name}
+
+	image := pages.Image
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+	checksum := sha256.New()
+	if image == "" {
+		image = mirrorImage(errorPagesServerImage)
+		data := map[string]string{"default.conf": errorPagesServerConfig}
+		items := []corev1.KeyToPath{}
+		if pages.NotFound != "" {
+			data["404.html"] = pages.NotFound
+			items = append(items, corev1.KeyToPath{Key: "404.html", Path: "404.html"})
+		}
+		if pages.ServerError != "" {
+			data["5xx.html"] = pages.ServerError
+			items = append(items, corev1.KeyToPath{Key: "5xx.html", Path: "5xx.html"})
+		}
+
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+			cm.Labels = labels
+			cm.Data = data
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to ensure error pages ConfigMap: %w", err)
+		}
+		for _, key := range []string{"default.conf", "404.html", "5xx.html"} {
+			checksum.Write([]byte(data[key]))
+		}
+
+		source := corev1.LocalObjectReference{Name: name}
+		volumes = []corev1.Volume{
+			{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: source,
+				Items:                []corev1.KeyToPath{{Key: "default.conf", Path: "default.conf"}},
+			}}},
+			{Name: "pages", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: source,
+				Items:                items,
+			}}},
+		}
+		mounts = []corev1.VolumeMount{
+			{Name: "config", MountPath: "/etc/nginx/conf.d", ReadOnly: true},
+			{Name: "pages", MountPath: "/usr/share/nginx/html", ReadOnly: true},
+		}
+	} else {
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		if err := r.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete error pages ConfigMap: %w", err)
+		}
+	}
+
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
+		replicas := int32(1)
+		deployment.Labels = labels
+		deployment.Spec.Replicas = &replicas
+		deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: selector}
+		deployment.Spec.Template.Labels = labels
+		deployment.Spec.Template.Annotations = map[string]string{
+			"platform.kibaship.com/error-pages-checksum": hex.EncodeToString(checksum.Sum(nil)),
+		}
+		deployment.Spec.Template.Spec.Volumes = volumes
+		deployment.Spec.Template.Spec.Containers = []corev1.Container{{
+			Name:         "error-pages",
+			Image:        image,
+			Ports:        []corev1.ContainerPort{{Name: "http", ContainerPort: errorPagesPort, Protocol: corev1.ProtocolTCP}},
+			VolumeMounts: mounts,
+			ReadinessProbe: &corev1.Probe{
+				ProbeHandler: corev1.ProbeHandler{
+					TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(errorPagesPort)},
+				},
+				PeriodSeconds: 10,
+			},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("10m"),
+					corev1.ResourceMemory: resource.MustParse("16Mi"),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("64Mi"),
+				},
+			},
+		}}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to ensure error pages Deployment: %w", err)
+	}
+
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+		service.Labels = labels
+		service.Spec.Selector = selector
+		service.Spec.Ports = []corev1.ServicePort{{
+			Name:       "http",
+			Port:       errorPagesPort,
+			TargetPort: intstr.FromInt32(errorPagesPort),
+			Protocol:   corev1.ProtocolTCP,
+		}}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to ensure error pages Service: %w", err)
+	}
+
+	if opConfig != nil && opConfig.IngressProvider == config.IngressProviderTraefik {
+		middleware := &unstructured.Unstructured{}
+		middleware.SetGroupVersionKind(traefikMiddlewareGVK)
+		middleware.SetNamespace(namespace)
+		middleware.SetName(name)
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, middleware, func() error {
+			middleware.SetLabels(labels)
+			middleware.Object["spec"] = map[string]any{
+				"errors": map[string]any{
+					"status":  []any{"500", "502-504"},
+					"service": map[string]any{"name": name, "port": int64(errorPagesPort)},
+					"query":   "/{status}",
+				},
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to ensure error pages Middleware: %w", err)
+		}
+	}
+
+	return r.syncIngressErrorPages(ctx, namespace, projectUUID, opConfig, true)
+}
+
+// deleteErrorPagesBackend unwires the error pages of a project from its Ingresses in namespace
+// and removes the backend
+func (r *ProjectReconciler) deleteErrorPagesBackend(ctx context.Context, namespace, projectUUID string, opConfig *OperatorConfig) error {
+	if err := r.syncIngressErrorPages(ctx, namespace, projectUUID, opConfig, false); err != nil {
+		return err
+	}
+
+	objectMeta := metav1.ObjectMeta{Name: errorPagesName(projectUUID), Namespace: namespace}
+	for _, obj := range []client.Object{
+		&corev1.Service{ObjectMeta: objectMeta},
+		&appsv1.Deployment{ObjectMeta: objectMeta},
+		&corev1.ConfigMap{ObjectMeta: objectMeta},
+	} {
+		if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete error pages %T: %w", obj, err)
+		}
+	}
+
+	middleware := &unstructured.Unstructured{}
+	middleware.SetGroupVersionKind(traefikMiddlewareGVK)
+	middleware.SetNamespace(namespace)
+	middleware.SetName(errorPagesName(projectUUID))
+	// The Middleware kind is missing when Traefik CRDs were never installed, nothing to delete then
+	if err := r.Delete(ctx, middleware); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to delete error pages Middleware: %w", err)
+	}
+	return nil
+}
+
+// syncIngressErrorPages adds or removes the error pages annotations of the project Ingresses in
+// namespace. Gateway API HTTPRoutes cannot intercept backend errors and are left unchanged.
+func (r *ProjectReconciler) syncIngressErrorPages(
+	ctx context.Context,
+	namespace, projectUUID string,
+	opConfig *OperatorConfig,
+	enabled bool,
+) error {
+	if opConfig == nil || opConfig.IngressProvider.UsesGatewayAPI() || opConfig.IngressProvider == "" {
+		return nil
+	}
+
+	var ingresses networkingv1.IngressList
+	if err := r.List(ctx, &ingresses, client.InNamespace(namespace), client.MatchingLabels{
+		"app.kubernetes.io/managed-by": "kibaship",
+		validation.LabelProjectUUID:    projectUUID,
+	}); err != nil {
+		return fmt.Errorf("failed to list project Ingresses: %w", err)
+	}
+
+	for i := range ingresses.Items {
+		ingress := &ingresses.Items[i]
+		// The unmatched hosts route is served by the error pages themselves
+		if ingress.Name == errorPagesName(projectUUID) {
+			continue
+		}
+		patch := client.MergeFrom(ingress.DeepCopy())
+		if ingress.Annotations == nil {
+			ingress.Annotations = map[string]string{}
+		}
+		setErrorPagesAnnotations(ingress.Annotations, opConfig.IngressProvider, namespace, projectUUID, enabled)
+		if err := r.Patch(ctx, ingress, patch); err != nil {
+			return fmt.Errorf("failed to update error pages of Ingress %s: %w", ingress.Name, err)
+		}
+	}
+	return nil
+}
+
+// reconcileErrorPagesFallbackRoute answers the hosts of the project base domain matching no
+// application with the not found page, from the first namespace running the error pages
+func (r *ProjectReconciler) reconcileErrorPagesFallbackRoute(
+	ctx context.Context,
+	project *platformv1alpha1.Project,
+	namespaces []string,
+	opConfig *OperatorConfig,
+) error {
+	projectUUID := project.GetUUID()
+	name := errorPagesName(projectUUID)
+	provider := NewRouteProvider(r.Client, r.Scheme, opConfig)
+	baseDomain := project.Spec.BaseDomain
+
+	var routeNamespace string
+	if len(namespaces) > 0 && baseDomain != "" && baseDomain != opConfig.Domain {
+		routeNamespace = namespaces[0]
+	}
+
+	// Remove the route from namespaces it no longer belongs to
+	var services corev1.ServiceList
+	if err := r.List(ctx, &services, client.MatchingLabels{
+		"platform.kibaship.com/type": errorPagesResourceType,
+		validation.LabelProjectUUID:  projectUUID,
+	}); err != nil {
+		return fmt.Errorf("failed to list error pages Services: %w", err)
+	}
+	stale := slices.Clone(namespaces)
+	for _, service := range services.Items {
+		stale = append(stale, service.Namespace)
+	}
+	slices.Sort(stale)
+	for _, namespace := range slices.Compact(stale) {
+		if namespace == routeNamespace {
+			continue
+		}
+		if err := provider.DeleteRoute(ctx, namespace, name); err != nil {
+			return err
+		}
+	}
+
+	if routeNamespace == "" {
+		return nil
+	}
+
+	if opConfig.IngressProvider.UsesGatewayAPI() || opConfig.IngressProvider == "" {
+		if _, err := ensureBaseDomainCertificate(ctx, r.Client, baseDomain); err != nil {
+			return fmt.Errorf("failed to provision wildcard Certificate for %s: %w", baseDomain, err)
+		}
+		if err := ensureBaseDomainGatewayListener(ctx, r.Client, baseDomain); err != nil {
+			return fmt.Errorf("failed to add Gateway listener for %s: %w", baseDomain, err)
+		}
+	}
+
+	var service corev1.Service
+	if err := r.Get(ctx, client.ObjectKey{Namespace: routeNamespace, Name: name}, &service); err != nil {
+		return fmt.Errorf("failed to get error pages Service: %w", err)
+	}
+
+	route := RouteSpec{
+		Namespace:   routeNamespace,
+		Name:        name,
+		Hostname:    fmt.Sprintf("*.apps.%s", baseDomain),
+		BaseDomain:  baseDomain,
+		ServiceName: name,
+		ServicePort: errorPagesPort,
+		TLSPolicy:   routeTLSPolicy(false),
+		ProjectUUID: projectUUID,
+		Reconcile:   true,
+	}
+	if err := provider.EnsureRoute(ctx, route, &service); err != nil {
+		return fmt.Errorf("failed to route unmatched hosts to the error pages: %w", err)
+	}
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Routed unmatched hosts to the error pages", "hostname", route.Hostname, "namespace", routeNamespace)
+	return nil
+}
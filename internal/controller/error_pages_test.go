@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+var _ = Describe("Error pages", func() {
+	const projectUUID = "550e8400-e29b-41d4-a716-446655440000"
+
+	It("sends nginx backend errors to the project error pages", func() {
+		annotations := map[string]string{"nginx.ingress.kubernetes.io/ssl-redirect": TrueString}
+
+		setErrorPagesAnnotations(annotations, config.IngressProviderNginx, "default", projectUUID, true)
+		Expect(annotations).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/default-backend", "error-pages-"+projectUUID))
+		Expect(annotations).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/custom-http-errors", "500,502,503,504"))
+
+		setErrorPagesAnnotations(annotations, config.IngressProviderNginx, "default", projectUUID, false)
+		Expect(annotations).To(Equal(map[string]string{"nginx.ingress.kubernetes.io/ssl-redirect": TrueString}))
+	})
+
+	It("chains the Traefik errors middleware after the headers middleware", func() {
+		const key = "traefik.ingress.kubernetes.io/router.middlewares"
+		annotations := map[string]string{key: "default-httproute-app-headers@kubernetescrd"}
+
+		setErrorPagesAnnotations(annotations, config.IngressProviderTraefik, "default", projectUUID, true)
+		setErrorPagesAnnotations(annotations, config.IngressProviderTraefik, "default", projectUUID, true)
+		Expect(annotations).To(HaveKeyWithValue(key,
+			"default-httproute-app-headers@kubernetescrd,default-error-pages-"+projectUUID+"@kubernetescrd"))
+
+		setErrorPagesAnnotations(annotations, config.IngressProviderTraefik, "default", projectUUID, false)
+		Expect(annotations).To(HaveKeyWithValue(key, "default-httproute-app-headers@kubernetescrd"))
+
+		annotations = map[string]string{}
+		setErrorPagesAnnotations(annotations, config.IngressProviderTraefik, "default", projectUUID, false)
+		Expect(annotations).NotTo(HaveKey(key))
+	})
+})
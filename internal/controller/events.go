@@ -0,0 +1,50 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reasons of the Events emitted on the platform resources, so `kubectl describe` tells what the
+// operator did with them
+const (
+	eventReasonReady              = "Ready"
+	eventReasonValidationFailed   = "ValidationFailed"
+	eventReasonReconcileFailed    = "ReconcileFailed"
+	eventReasonDeleting           = "Deleting"
+	eventReasonEnvironmentCreated = "EnvironmentCreated"
+	eventReasonDomainCreated      = "DomainCreated"
+	eventReasonCertificateFailed  = "CertificateFailed"
+	eventReasonPromoted           = "Promoted"
+	eventReasonPromotionBlocked   = "PromotionBlocked"
+	eventReasonPipelineRunCreated = "PipelineRunCreated"
+	eventReasonHookStarted        = "PromotionHookStarted"
+	eventReasonHookSucceeded      = "PromotionHookSucceeded"
+	eventReasonHookFailed         = "PromotionHookFailed"
+	eventReasonPromotionReverted  = "PromotionReverted"
+)
+
+// recordEvent emits an Event on obj when recorder is set, reconcilers built without a recorder,
+// such as in tests, emit nothing
+func recordEvent(recorder record.EventRecorder, obj runtime.Object, eventType, reason, messageFmt string, args ...any) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+func TestRecordEvent(t *testing.T) {
+	g := NewWithT(t)
+	project := &platformv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "project-1"}}
+
+	// Reconcilers without a recorder emit nothing
+	recordEvent(nil, project, corev1.EventTypeNormal, eventReasonReady, "Project is ready in namespace %s", "project-ns")
+
+	recorder := record.NewFakeRecorder(10)
+	recordEvent(recorder, project, corev1.EventTypeNormal, eventReasonReady, "Project is ready in namespace %s", "project-ns")
+	g.Expect(<-recorder.Events).To(Equal("Normal Ready Project is ready in namespace project-ns"))
+}
+
+func TestProjectStatusErrorEmitsWarning(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	project := &platformv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "project-1"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(project).WithStatusSubresource(project).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &ProjectReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+
+	r.updateStatusWithError(context.Background(), project, eventReasonValidationFailed, "Validation failed: missing uuid label")
+	g.Expect(<-recorder.Events).To(Equal("Warning ValidationFailed Validation failed: missing uuid label"))
+	g.Expect(project.Status.Phase).To(Equal("Failed"))
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	// LabelExternalEnv marks Secrets synced by the External Secrets Operator for application env vars
+	LabelExternalEnv = "platform.kibaship.com/external-env"
+
+	// AnnotationExternalEnvHash records the synced external env values on Kubernetes Deployments.
+	// It is copied to the pod template to roll pods when the values rotate.
+	AnnotationExternalEnvHash = "platform.kibaship.com/external-env-hash"
+)
+
+var externalSecretGVK = schema.GroupVersionKind{Group: "external-secrets.io", Version: "v1", Kind: "ExternalSecret"}
+
+// externalEnvFrom returns the envFrom source for the application's external env Secret.
+// It is listed after the env secret so external values win for duplicate names.
+func externalEnvFrom(app *platformv1alpha1.Application) []corev1.EnvFromSource {
+	if app.Spec.ExternalEnv == nil {
+		return nil
+	}
+	return []corev1.EnvFromSource{
+		{
+			SecretRef: &corev1.SecretEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: utils.GetExternalEnvSecretName(app.GetUUID()),
+				},
+			},
+		},
+	}
+}
+
+// ensureExternalEnvSecret creates or updates the ExternalSecret delegating external env vars to the
+// External Secrets Operator, or removes it when the application no longer uses external env vars.
+func (r *ApplicationReconciler) ensureExternalEnvSecret(ctx context.Context, app *platformv1alpha1.Application) error {
+	log := logf.FromContext(ctx).WithValues("application", app.Name, "namespace", app.Namespace)
+	name := utils.GetExternalEnvSecretName(app.GetUUID())
+
+	externalSecret := &unstructured.Unstructured{}
+	externalSecret.SetGroupVersionKind(externalSecretGVK)
+	externalSecret.SetNamespace(app.Namespace)
+	externalSecret.SetName(name)
+
+	if app.Spec.ExternalEnv == nil {
+		if err := r.Delete(ctx, externalSecret); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return fmt.Errorf("failed to delete ExternalSecret: %w", err)
+		}
+		return nil
+	}
+
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, externalSecret, func() error {
+		externalSecret.SetLabels(map[string]string{
+			"app.kubernetes.io/managed-by":           "kibaship",
+			"platform.kibaship.com/application-uuid": app.GetUUID(),
+		})
+		externalSecret.Object["spec"] = buildExternalSecretSpec(app, name)
+		return controllerutil.SetControllerReference(app, externalSecret, r.Scheme)
+	})
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return fmt.Errorf("application uses externalEnv but the External Secrets Operator is not installed")
+		}
+		return fmt.Errorf("failed to ensure ExternalSecret: %w", err)
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Info("Reconciled ExternalSecret for external env vars", "externalSecret", name, "result", result)
+	}
+	return nil
+}
+
+// buildExternalSecretSpec renders the ExternalSecret spec for an application's external env vars
+func buildExternalSecretSpec(app *platformv1alpha1.Application, targetName string) map[string]any {
+	externalEnv := app.Spec.ExternalEnv
+
+	kind := externalEnv.SecretStoreRef.Kind
+	if kind == "" {
+		kind = platformv1alpha1.ExternalSecretStoreKindClusterSecretStore
+	}
+	refreshInterval := externalEnv.RefreshInterval
+	if refreshInterval == "" {
+		refreshInterval = "1h"
+	}
+
+	data := make([]any, 0, len(externalEnv.Variables))
+	for _, variable := range externalEnv.Variables {
+		remoteRef := map[string]any{"key": variable.Key}
+		if variable.Property != "" {
+			remoteRef["property"] = variable.Property
+		}
+		data = append(data, map[string]any{
+			"secretKey": variable.Name,
+			"remoteRef": remoteRef,
+		})
+	}
+
+	return map[string]any{
+		"refreshInterval": refreshInterval,
+		"secretStoreRef": map[string]any{
+			"name": externalEnv.SecretStoreRef.Name,
+			"kind": string(kind),
+		},
+		"target": map[string]any{
+			"name":           targetName,
+			"creationPolicy": "Owner",
+			"template": map[string]any{
+				"metadata": map[string]any{
+					"labels": map[string]any{
+						"app.kubernetes.io/managed-by":           "kibaship",
+						"platform.kibaship.com/application-uuid": app.GetUUID(),
+						LabelExternalEnv:                         "true",
+					},
+				},
+			},
+		},
+		"data": data,
+	}
+}
+
+// envSecretHash returns a stable hash of the values of an env var Secret
+func envSecretHash(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write(data[key])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// ExternalEnvReconciler watches Secrets synced by the External Secrets Operator and restarts the
+// application's pods when upstream values rotate and redeployOnChange is enabled.
+type ExternalEnvReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=external-secrets.io,resources=externalsecrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;update;patch
+
+// Reconcile rolls the application's Kubernetes Deployments when the external env values change
+func (r *ExternalEnvReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var secret corev1.Secret
+	if err := r.Get(ctx, req.NamespacedName, &secret); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	appUUID := secret.Labels[validation.LabelApplicationUUID]
+	if appUUID == "" {
+		return ctrl.Result{}, nil
+	}
+
+	var apps platformv1alpha1.ApplicationList
+	if err := r.List(ctx, &apps, client.InNamespace(secret.Namespace), client.MatchingLabels{
+		validation.LabelResourceUUID: appUUID,
+	}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list applications: %w", err)
+	}
+	if len(apps.Items) == 0 {
+		return ctrl.Result{}, nil
+	}
+	app := &apps.Items[0]
+	if app.Spec.ExternalEnv == nil || !app.Spec.ExternalEnv.RedeployOnChange {
+		return ctrl.Result{}, nil
+	}
+
+	hash := envSecretHash(secret.Data)
+
+	var deployments appsv1.DeploymentList
+	if err := r.List(ctx, &deployments, client.InNamespace(secret.Namespace), client.MatchingLabels{
+		validation.LabelApplicationUUID: appUUID,
+	}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	for i := range deployments.Items {
+		dep := &deployments.Items[i]
+		previous := dep.Annotations[AnnotationExternalEnvHash]
+		if previous == hash {
+			continue
+		}
+
+		patch := client.MergeFrom(dep.DeepCopy())
+		if dep.Annotations == nil {
+			dep.Annotations = map[string]string{}
+		}
+		dep.Annotations[AnnotationExternalEnvHash] = hash
+		// The first sync only records the hash, pods already started with these values
+		if previous != "" {
+			if dep.Spec.Template.Annotations == nil {
+				dep.Spec.Template.Annotations = map[string]string{}
+			}
+			dep.Spec.Template.Annotations[AnnotationExternalEnvHash] = hash
+		}
+		if err := r.Patch(ctx, dep, patch); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to roll deployment %s: %w", dep.Name, err)
+		}
+		if previous != "" {
+			log.Info("External env values rotated, restarting pods", "deployment", dep.Name, "application", app.Name)
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Only Secrets labelled as external env Secrets are reconciled.
+func (r *ExternalEnvReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	isExternalEnv := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetLabels()[LabelExternalEnv] == "true"
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Secret{}, builder.WithPredicates(isExternalEnv, predicate.ResourceVersionChangedPredicate{})).
+		WithOptions(controllerOptions("external-env", 1)).
+		Named("external-env").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
+		Complete(r)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+var _ = Describe("External Env", func() {
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "application-ext",
+			Labels: map[string]string{validation.LabelResourceUUID: "0b6a4c1e-7a39-4f8e-9d7a-1c2b3d4e5f60"},
+		},
+		Spec: platformv1alpha1.ApplicationSpec{
+			ExternalEnv: &platformv1alpha1.ExternalEnvConfig{
+				SecretStoreRef: platformv1alpha1.ExternalSecretStoreRef{Name: "vault"},
+				Variables: []platformv1alpha1.ExternalEnvVar{
+					{Name: "DATABASE_URL", Key: "secret/data/my-app", Property: "database_url"},
+					{Name: "API_TOKEN", Key: "secret/data/api-token"},
+				},
+			},
+		},
+	}
+
+	It("renders an ExternalSecret targeting the external env Secret", func() {
+		spec := buildExternalSecretSpec(app, "application-ext-external")
+
+		Expect(spec["refreshInterval"]).To(Equal("1h"))
+		Expect(spec["secretStoreRef"]).To(Equal(map[string]any{"name": "vault", "kind": "ClusterSecretStore"}))
+
+		target := spec["target"].(map[string]any)
+		Expect(target["name"]).To(Equal("application-ext-external"))
+
+		data := spec["data"].([]any)
+		Expect(data).To(HaveLen(2))
+		Expect(data[0]).To(Equal(map[string]any{
+			"secretKey": "DATABASE_URL",
+			"remoteRef": map[string]any{"key": "secret/data/my-app", "property": "database_url"},
+		}))
+		Expect(data[1].(map[string]any)["remoteRef"]).To(Equal(map[string]any{"key": "secret/data/api-token"}))
+	})
+
+	It("appends the external env Secret to envFrom only when configured", func() {
+		Expect(externalEnvFrom(app)).To(HaveLen(1))
+		Expect(externalEnvFrom(app)[0].SecretRef.Name).To(Equal("application-0b6a4c1e-7a39-4f8e-9d7a-1c2b3d4e5f60-external"))
+		Expect(externalEnvFrom(&platformv1alpha1.Application{})).To(BeEmpty())
+	})
+
+	It("hashes external env values independent of key order", func() {
+		a := envSecretHash(map[string][]byte{"A": []byte("1"), "B": []byte("2")})
+		b := envSecretHash(map[string][]byte{"B": []byte("2"), "A": []byte("1")})
+		c := envSecretHash(map[string][]byte{"A": []byte("1"), "B": []byte("3")})
+		Expect(a).To(Equal(b))
+		Expect(a).NotTo(Equal(c))
+	})
+})
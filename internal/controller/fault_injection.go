@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// FaultInjectedReason is the condition reason of failures forced through fault annotations
+const FaultInjectedReason = "FaultInjected"
+
+// faultInjection reports whether the controllers honor the fault annotations
+var faultInjection atomic.Bool
+
+// SetFaultInjection makes the controllers honor the fault.kibaship.com annotations, which force
+// PipelineRun failures, delay pod readiness and fail certificate issuance so e2e tests can
+// exercise promotion gates, retries and rollbacks deterministically. Never enable it in production.
+// This should be called once at startup, before controllers are set up
+func SetFaultInjection(enabled bool) {
+	faultInjection.Store(enabled)
+}
+
+// injectedFault returns the value of the fault annotation of obj, ok is false when the annotation
+// is missing or fault injection is disabled
+func injectedFault(obj metav1.Object, annotation string) (value string, ok bool) {
+	if !faultInjection.Load() {
+		return "", false
+	}
+	value, ok = obj.GetAnnotations()[annotation]
+	return value, ok
+}
+
+// injectedFailureMessage returns the message of an injected failure, the annotation value or a
+// default message when the value is empty
+func injectedFailureMessage(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// injectedReadinessDelay returns how much longer the pods of the deployment are reported unready,
+// zero once the delay elapsed, when no delay is injected or when the delay is not a valid duration
+func injectedReadinessDelay(deployment *platformv1alpha1.Deployment, now time.Time) time.Duration {
+	value, ok := injectedFault(deployment, validation.AnnotationFaultReadinessDelay)
+	if !ok {
+		return 0
+	}
+	delay, err := time.ParseDuration(value)
+	if err != nil || delay <= 0 {
+		return 0
+	}
+	remaining := deployment.CreationTimestamp.Add(delay).Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+func TestInjectedFault(t *testing.T) {
+	g := NewWithT(t)
+	t.Cleanup(func() { SetFaultInjection(false) })
+
+	deployment := &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{validation.AnnotationFaultPipelineRunFailure: "build exploded"},
+	}}
+
+	_, ok := injectedFault(deployment, validation.AnnotationFaultPipelineRunFailure)
+	g.Expect(ok).To(BeFalse())
+
+	SetFaultInjection(true)
+	value, ok := injectedFault(deployment, validation.AnnotationFaultPipelineRunFailure)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(value).To(Equal("build exploded"))
+
+	_, ok = injectedFault(deployment, validation.AnnotationFaultCertificateFailure)
+	g.Expect(ok).To(BeFalse())
+
+	g.Expect(injectedFailureMessage("", "fallback")).To(Equal("fallback"))
+	g.Expect(injectedFailureMessage("build exploded", "fallback")).To(Equal("build exploded"))
+}
+
+func TestInjectedReadinessDelay(t *testing.T) {
+	g := NewWithT(t)
+	t.Cleanup(func() { SetFaultInjection(false) })
+	SetFaultInjection(true)
+
+	created := time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC)
+	deployment := func(delay string) *platformv1alpha1.Deployment {
+		return &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.NewTime(created),
+			Annotations:       map[string]string{validation.AnnotationFaultReadinessDelay: delay},
+		}}
+	}
+
+	g.Expect(injectedReadinessDelay(deployment("90s"), created.Add(30*time.Second))).To(Equal(60 * time.Second))
+	g.Expect(injectedReadinessDelay(deployment("90s"), created.Add(2*time.Minute))).To(BeZero())
+	g.Expect(injectedReadinessDelay(deployment("soon"), created)).To(BeZero())
+	g.Expect(injectedReadinessDelay(&platformv1alpha1.Deployment{}, created)).To(BeZero())
+
+	SetFaultInjection(false)
+	g.Expect(injectedReadinessDelay(deployment("90s"), created)).To(BeZero())
+}
+
+func TestPipelineRunStatusController_InjectedFailure(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	t.Cleanup(func() { SetFaultInjection(false) })
+	SetFaultInjection(true)
+
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(tektonv1.AddToScheme(scheme)).To(Succeed())
+
+	deployment := &platformv1alpha1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "deployment-fault",
+			Namespace:   "default",
+			UID:         "deployment-fault-uid",
+			Annotations: map[string]string{validation.AnnotationFaultPipelineRunFailure: ""},
+		},
+		Spec: platformv1alpha1.DeploymentSpec{ApplicationRef: corev1.LocalObjectReference{Name: "application-fault"}},
+	}
+	pipelineRun := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pipeline-run-fault-1",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: platformv1alpha1.GroupVersion.String(),
+				Kind:       DeploymentKind,
+				Name:       deployment.Name,
+				UID:        deployment.UID,
+			}},
+		},
+	}
+	pipelineRun.Status.MarkSucceeded("Succeeded", "All Tasks have completed executing")
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(deployment, pipelineRun).
+		WithStatusSubresource(&platformv1alpha1.Deployment{}).
+		Build()
+	r := &PipelineRunStatusController{Client: cl, Scheme: scheme}
+
+	_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(pipelineRun)})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	updated := &platformv1alpha1.Deployment{}
+	g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(deployment), updated)).To(Succeed())
+	condition := meta.FindStatusCondition(updated.Status.Conditions, "PipelineRunReady")
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(condition.Reason).To(Equal(FaultInjectedReason))
+	g.Expect(condition.Message).To(Equal("PipelineRun failure injected"))
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	// DeploymentConditionPromoted reports whether a successful deployment was promoted
+	DeploymentConditionPromoted = "Promoted"
+)
+
+// activeFreezeWindow returns the freeze window of the deployment's environment covering now
+func activeFreezeWindow(ctx context.Context, c client.Reader, deployment *platformv1alpha1.Deployment, now time.Time) (*platformv1alpha1.FreezeWindow, time.Time, error) {
+	environmentUUID := deployment.Labels[validation.LabelEnvironmentUUID]
+	if environmentUUID == "" {
+		return nil, time.Time{}, nil
+	}
+
+	var environments platformv1alpha1.EnvironmentList
+	if err := c.List(ctx, &environments, client.MatchingLabels{validation.LabelResourceUUID: environmentUUID}); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to list environments: %w", err)
+	}
+	if len(environments.Items) == 0 {
+		return nil, time.Time{}, nil
+	}
+
+	window, until := environments.Items[0].ActiveFreezeWindow(now)
+	return window, until, nil
+}
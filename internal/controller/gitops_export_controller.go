@@ -0,0 +1,276 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/gitops"
+	"github.com/kibamail/kibaship/pkg/manifest"
+)
+
+const (
+	// gitopsExportName prefixes the export Jobs and their snapshot ConfigMaps, it is also the name
+	// of the single request every watched resource maps to
+	gitopsExportName = "gitops-export"
+
+	// gitopsExportComponent labels the export Jobs
+	gitopsExportComponent = "gitops-export"
+
+	// gitopsExportImage runs the export script, it ships git and ssh
+	gitopsExportImage = "alpine/git:2.47.2"
+
+	// gitopsExportRequeueInterval is how often a pending export checks whether the running
+	// export finished
+	gitopsExportRequeueInterval = 30 * time.Second
+
+	// gitopsExportJobTTL keeps finished export Jobs around long enough to read their logs
+	gitopsExportJobTTL int32 = 3600
+)
+
+// GitOpsExportReconciler commits the Projects, Environments, Applications and ApplicationDomains
+// to a git repository when they change. Every change maps to the same request, so bursts of
+// changes collapse into one export, and exports run one at a time so pushes never race.
+type GitOpsExportReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Config config.GitOpsConfig
+}
+
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=projects;environments;applications;applicationdomains,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;delete
+
+// Reconcile snapshots the platform resources and starts an export Job when the snapshot differs
+// from the last one exported
+func (r *GitOpsExportReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	objects, err := r.exportedObjects(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	snapshot, err := gitops.Snapshot(objects)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	hash := gitops.Hash(snapshot)
+	jobName := fmt.Sprintf("%s-%s", gitopsExportName, hash)
+
+	var jobs batchv1.JobList
+	if err := r.List(ctx, &jobs, client.InNamespace(config.OperatorNamespace), client.MatchingLabels{
+		"app.kubernetes.io/component": gitopsExportComponent,
+	}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list export jobs: %w", err)
+	}
+	for _, job := range jobs.Items {
+		if job.Name == jobName {
+			// This snapshot was exported, or is being exported. A failed export is retried once
+			// its Job expires.
+			return ctrl.Result{}, nil
+		}
+	}
+	for _, job := range jobs.Items {
+		if job.Status.CompletionTime == nil && !jobFailed(&job) {
+			log.V(1).Info("Export waiting for the running export", "running", job.Name)
+			return ctrl.Result{RequeueAfter: gitopsExportRequeueInterval}, nil
+		}
+	}
+
+	if err := r.createExport(ctx, jobName, hash, snapshot); err != nil {
+		return ctrl.Result{}, err
+	}
+	log.Info("Started GitOps export", "job", jobName, "resources", len(snapshot), "repository", r.Config.RepositoryURL)
+	return ctrl.Result{}, nil
+}
+
+// exportedObjects returns the sanitized platform resources, in a stable order
+func (r *GitOpsExportReconciler) exportedObjects(ctx context.Context) ([]*unstructured.Unstructured, error) {
+	var projects platformv1alpha1.ProjectList
+	var environments platformv1alpha1.EnvironmentList
+	var applications platformv1alpha1.ApplicationList
+	var domains platformv1alpha1.ApplicationDomainList
+
+	var objects []client.Object
+	for _, list := range []client.ObjectList{&projects, &environments, &applications, &domains} {
+		if err := r.List(ctx, list); err != nil {
+			return nil, fmt.Errorf("failed to list resources to export: %w", err)
+		}
+	}
+	for i := range projects.Items {
+		objects = append(objects, &projects.Items[i])
+	}
+	for i := range environments.Items {
+		objects = append(objects, &environments.Items[i])
+	}
+	for i := range applications.Items {
+		objects = append(objects, &applications.Items[i])
+	}
+	for i := range domains.Items {
+		objects = append(objects, &domains.Items[i])
+	}
+
+	exported := make([]*unstructured.Unstructured, 0, len(objects))
+	for _, obj := range objects {
+		if !obj.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		u, err := manifest.FromObject(obj, r.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		exported = append(exported, u)
+	}
+	return exported, nil
+}
+
+// createExport starts the export Job and writes the snapshot it commits. The snapshot ConfigMap
+// is owned by the Job, so both are removed when the Job expires.
+func (r *GitOpsExportReconciler) createExport(ctx context.Context, jobName, hash string, snapshot map[string]string) error {
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by": "kibaship",
+		"app.kubernetes.io/component":  gitopsExportComponent,
+	}
+
+	backoffLimit := int32(2)
+	ttl := gitopsExportJobTTL
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: config.OperatorNamespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot: ptr.To(true),
+						RunAsUser:    ptr.To(int64(65532)),
+					},
+					Containers: []corev1.Container{{
+						Name:    "export",
+						Image:   mirrorImage(gitopsExportImage),
+						Command: []string{"/bin/sh", "-c", gitops.Script(r.Config)},
+						Env:     []corev1.EnvVar{{Name: "EXPORT_ID", Value: hash}},
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "snapshot", MountPath: gitops.SnapshotMountPath, ReadOnly: true},
+							{Name: "deploy-key", MountPath: gitops.DeployKeyMountPath, ReadOnly: true},
+						},
+					}},
+					Volumes: []corev1.Volume{
+						{
+							Name: "snapshot",
+							VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: jobName},
+							}},
+						},
+						{
+							Name: "deploy-key",
+							VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+								SecretName: r.Config.DeployKeySecret,
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := r.Create(ctx, job); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to create export job: %w", err)
+	}
+
+	snapshotConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: config.OperatorNamespace,
+			Labels:    labels,
+		},
+		Data: snapshot,
+	}
+	if err := controllerutil.SetControllerReference(job, snapshotConfigMap, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner of export snapshot: %w", err)
+	}
+	if err := r.Create(ctx, snapshotConfigMap); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create export snapshot: %w", err)
+	}
+	return nil
+}
+
+// jobFailed reports whether a Job gave up
+func jobFailed(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Spec, label and annotation changes of the exported resources, and export Jobs finishing, all
+// enqueue the same request. The resources of every shard are exported by the operator of the
+// default shard.
+func (r *GitOpsExportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	export := handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []ctrl.Request {
+		return []ctrl.Request{{NamespacedName: types.NamespacedName{Namespace: config.OperatorNamespace, Name: gitopsExportName}}}
+	})
+	changed := builder.WithPredicates(predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.LabelChangedPredicate{},
+		predicate.AnnotationChangedPredicate{},
+	))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controllerOptions("gitops-export", 1)).
+		Named("gitops-export").
+		WithEventFilter(DefaultShardPredicate()).
+		Watches(&platformv1alpha1.Project{}, export, changed).
+		Watches(&platformv1alpha1.Environment{}, export, changed).
+		Watches(&platformv1alpha1.Application{}, export, changed).
+		Watches(&platformv1alpha1.ApplicationDomain{}, export, changed).
+		Watches(&batchv1.Job{}, export, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetNamespace() == config.OperatorNamespace && obj.GetLabels()["app.kubernetes.io/component"] == gitopsExportComponent
+		}))).
+		Complete(r)
+}
@@ -0,0 +1,404 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/idle"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	// DefaultIdleCheckInterval is how often applications in environments with an idle policy are checked
+	DefaultIdleCheckInterval = 30 * time.Second
+
+	// idleEndpointSliceManager marks the EndpointSlices routing public Services to the activator
+	idleEndpointSliceManager = "kibaship-operator"
+)
+
+// IdleReconciler scales applications in environments with an idle policy to zero when they receive
+// no traffic. It routes their public Service through the activator, which records requests and wakes
+// sleeping applications.
+type IdleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Interval is how often applications are checked, DefaultIdleCheckInterval when zero
+	Interval time.Duration
+}
+
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=environments,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;patch
+
+// Reconcile applies the idle policy of the application's environment
+func (r *IdleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var app platformv1alpha1.Application
+	if err := r.Get(ctx, req.NamespacedName, &app); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !app.DeletionTimestamp.IsZero() || app.GetUUID() == "" {
+		return ctrl.Result{}, nil
+	}
+
+	policy, err := r.idlePolicy(ctx, &app)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var public corev1.Service
+	err = r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: utils.GetServiceName(app.GetUUID())}, &public)
+	if apierrors.IsNotFound(err) {
+		// Nothing is deployed yet
+		if policy == nil {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: r.interval()}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get service: %w", err)
+	}
+
+	if policy == nil {
+		return ctrl.Result{}, r.disable(ctx, &app, &public)
+	}
+
+	state, lastRequest, err := r.apply(ctx, &app, &public, policy)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.updateStatus(ctx, &app, state, lastRequest); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: r.interval()}, nil
+}
+
+// idlePolicy returns the enabled idle policy of the application's environment, nil when there is none
+func (r *IdleReconciler) idlePolicy(ctx context.Context, app *platformv1alpha1.Application) (*platformv1alpha1.IdlePolicy, error) {
+	environmentUUID := app.Labels[validation.LabelEnvironmentUUID]
+	if environmentUUID == "" {
+		return nil, nil
+	}
+
+	var environments platformv1alpha1.EnvironmentList
+	if err := r.List(ctx, &environments, client.MatchingLabels{validation.LabelResourceUUID: environmentUUID}); err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+	if len(environments.Items) == 0 || !environments.Items[0].IdleEnabled() {
+		return nil, nil
+	}
+	return environments.Items[0].Spec.IdlePolicy, nil
+}
+
+// apply routes the application through the activator and scales it to zero once it has been idle
+// for the policy timeout
+func (r *IdleReconciler) apply(ctx context.Context, app *platformv1alpha1.Application, public *corev1.Service, policy *platformv1alpha1.IdlePolicy) (platformv1alpha1.IdleState, time.Time, error) {
+	log := logf.FromContext(ctx)
+
+	private, err := r.ensurePrivateService(ctx, app, public)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	lastRequest, ok := idle.LastRequest(private)
+	if !ok {
+		lastRequest = private.CreationTimestamp.Time
+	}
+
+	deployments, err := idle.Deployments(ctx, r.Client, app.Namespace, app.GetUUID())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	addresses, err := r.activatorAddresses(ctx)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if len(addresses) == 0 {
+		// Without a running activator nothing could wake the application, serve it directly
+		log.Info("No ready activator endpoints, keeping application awake", "application", app.Name)
+		if err := r.routeDirect(ctx, public, private); err != nil {
+			return "", time.Time{}, err
+		}
+		if _, err := idle.Wake(ctx, r.Client, app.Namespace, app.GetUUID()); err != nil {
+			return "", time.Time{}, err
+		}
+		return platformv1alpha1.IdleStateAwake, lastRequest, nil
+	}
+
+	if err := r.routeThroughActivator(ctx, public, addresses); err != nil {
+		return "", time.Time{}, err
+	}
+
+	if idle.Sleeping(deployments) {
+		return platformv1alpha1.IdleStateSleeping, lastRequest, nil
+	}
+
+	if len(deployments) > 0 && time.Since(lastRequest) >= policy.Timeout() {
+		if err := idle.Sleep(ctx, r.Client, app.Namespace, app.GetUUID()); err != nil {
+			return "", time.Time{}, err
+		}
+		log.Info("Scaled idle application to zero", "application", app.Name, "lastRequest", lastRequest)
+		return platformv1alpha1.IdleStateSleeping, lastRequest, nil
+	}
+
+	if !idle.Ready(deployments) && app.Status.Idle != nil && app.Status.Idle.State != platformv1alpha1.IdleStateAwake {
+		return platformv1alpha1.IdleStateWaking, lastRequest, nil
+	}
+	return platformv1alpha1.IdleStateAwake, lastRequest, nil
+}
+
+// disable serves the application directly again and wakes it
+func (r *IdleReconciler) disable(ctx context.Context, app *platformv1alpha1.Application, public *corev1.Service) error {
+	var private corev1.Service
+	err := r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: idle.PrivateServiceName(public.Name)}, &private)
+	if apierrors.IsNotFound(err) {
+		return r.updateStatus(ctx, app, "", time.Time{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get private service: %w", err)
+	}
+
+	if err := r.routeDirect(ctx, public, &private); err != nil {
+		return err
+	}
+	if _, err := idle.Wake(ctx, r.Client, app.Namespace, app.GetUUID()); err != nil {
+		return err
+	}
+	if err := r.Delete(ctx, &private); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete private service: %w", err)
+	}
+
+	logf.FromContext(ctx).Info("Disabled scale-to-zero for application", "application", app.Name)
+	return r.updateStatus(ctx, app, "", time.Time{})
+}
+
+// ensurePrivateService creates the Service selecting the application pods that the activator proxies to
+func (r *IdleReconciler) ensurePrivateService(ctx context.Context, app *platformv1alpha1.Application, public *corev1.Service) (*corev1.Service, error) {
+	var private corev1.Service
+	err := r.Get(ctx, client.ObjectKey{Namespace: public.Namespace, Name: idle.PrivateServiceName(public.Name)}, &private)
+	if err == nil {
+		return &private, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get private service: %w", err)
+	}
+	if len(public.Spec.Selector) == 0 {
+		return nil, fmt.Errorf("service %s has no selector to copy", public.Name)
+	}
+
+	labels := make(map[string]string, len(public.Labels))
+	for key, value := range public.Labels {
+		labels[key] = value
+	}
+	labels["app.kubernetes.io/component"] = "application-private-service"
+
+	private = corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      idle.PrivateServiceName(public.Name),
+			Namespace: public.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: public.Spec.Selector,
+		},
+	}
+	for _, port := range public.Spec.Ports {
+		private.Spec.Ports = append(private.Spec.Ports, corev1.ServicePort{
+			Name:       port.Name,
+			Protocol:   port.Protocol,
+			Port:       port.Port,
+			TargetPort: port.TargetPort,
+		})
+	}
+	// Start the idle timer when the application is first routed through the activator
+	idle.SetLastRequest(&private, time.Now())
+
+	if err := ctrl.SetControllerReference(app, &private, r.Scheme); err != nil {
+		return nil, fmt.Errorf("failed to set controller reference: %w", err)
+	}
+	if err := r.Create(ctx, &private); err != nil {
+		return nil, fmt.Errorf("failed to create private service: %w", err)
+	}
+	return &private, nil
+}
+
+// activatorAddresses returns the addresses of the ready activator pods
+func (r *IdleReconciler) activatorAddresses(ctx context.Context) ([]string, error) {
+	var slices discoveryv1.EndpointSliceList
+	if err := r.List(ctx, &slices, client.InNamespace(idle.ActivatorNamespace), client.MatchingLabels{
+		discoveryv1.LabelServiceName: idle.ActivatorServiceName,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list activator endpoints: %w", err)
+	}
+
+	var addresses []string
+	for _, slice := range slices.Items {
+		if slice.AddressType != discoveryv1.AddressTypeIPv4 {
+			continue
+		}
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			addresses = append(addresses, endpoint.Addresses...)
+		}
+	}
+	return addresses, nil
+}
+
+// routeThroughActivator points the public Service at the activator pods
+func (r *IdleReconciler) routeThroughActivator(ctx context.Context, public *corev1.Service, addresses []string) error {
+	portName := "http"
+	if len(public.Spec.Ports) > 0 {
+		portName = public.Spec.Ports[0].Name
+	}
+
+	endpoints := make([]discoveryv1.Endpoint, 0, len(addresses))
+	for _, address := range addresses {
+		endpoints = append(endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{address},
+			Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+		})
+	}
+
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      idle.EndpointSliceName(public.Name),
+			Namespace: public.Namespace,
+		},
+	}
+	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, slice, func() error {
+		if slice.Labels == nil {
+			slice.Labels = map[string]string{}
+		}
+		slice.Labels[discoveryv1.LabelServiceName] = public.Name
+		slice.Labels[discoveryv1.LabelManagedBy] = idleEndpointSliceManager
+		slice.AddressType = discoveryv1.AddressTypeIPv4
+		slice.Endpoints = endpoints
+		slice.Ports = []discoveryv1.EndpointPort{{
+			Name:     ptr.To(portName),
+			Protocol: ptr.To(corev1.ProtocolTCP),
+			Port:     ptr.To(int32(idle.ActivatorPort)),
+		}}
+		return ctrl.SetControllerReference(public, slice, r.Scheme)
+	}); err != nil {
+		return fmt.Errorf("failed to update activator endpoints: %w", err)
+	}
+
+	// A Service without a selector keeps the endpoints we manage
+	if len(public.Spec.Selector) > 0 {
+		patch := client.MergeFrom(public.DeepCopy())
+		public.Spec.Selector = nil
+		if err := r.Patch(ctx, public, patch); err != nil {
+			return fmt.Errorf("failed to remove service selector: %w", err)
+		}
+	}
+	return nil
+}
+
+// routeDirect restores the public Service selector and removes the activator endpoints
+func (r *IdleReconciler) routeDirect(ctx context.Context, public, private *corev1.Service) error {
+	if len(public.Spec.Selector) == 0 {
+		patch := client.MergeFrom(public.DeepCopy())
+		public.Spec.Selector = private.Spec.Selector
+		if err := r.Patch(ctx, public, patch); err != nil {
+			return fmt.Errorf("failed to restore service selector: %w", err)
+		}
+	}
+
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      idle.EndpointSliceName(public.Name),
+			Namespace: public.Namespace,
+		},
+	}
+	if err := r.Delete(ctx, slice); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete activator endpoints: %w", err)
+	}
+	return nil
+}
+
+// updateStatus records the idle state on the Application status, clearing it when state is empty
+func (r *IdleReconciler) updateStatus(ctx context.Context, app *platformv1alpha1.Application, state platformv1alpha1.IdleState, lastRequest time.Time) error {
+	var next *platformv1alpha1.IdleStatus
+	if state != "" {
+		next = &platformv1alpha1.IdleStatus{
+			State:              state,
+			LastRequestTime:    &metav1.Time{Time: lastRequest},
+			LastTransitionTime: &metav1.Time{Time: time.Now()},
+		}
+		if current := app.Status.Idle; current != nil && current.State == state {
+			next.LastTransitionTime = current.LastTransitionTime
+			if current.LastRequestTime != nil && current.LastRequestTime.Time.Equal(lastRequest.Truncate(time.Second)) {
+				return nil
+			}
+		}
+	} else if app.Status.Idle == nil {
+		return nil
+	}
+
+	patch := client.MergeFrom(app.DeepCopy())
+	app.Status.Idle = next
+	if err := r.Status().Patch(ctx, app, patch); err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to update application idle status: %w", err)
+	}
+	return nil
+}
+
+func (r *IdleReconciler) interval() time.Duration {
+	if r.Interval > 0 {
+		return r.Interval
+	}
+	return DefaultIdleCheckInterval
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Status updates are ignored, applications are checked on spec changes and every Interval.
+func (r *IdleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&platformv1alpha1.Application{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controllerOptions("application-idle", 1)).
+		Named("application-idle").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
+		Complete(r)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sort"
+	"sync/atomic"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/pipelines"
+)
+
+// imageMirror holds the registry mirror of the operator configuration
+var imageMirror atomic.Pointer[string]
+
+// SetImageMirror replaces the registry mirror platform images are pulled from. It is called at
+// startup and whenever the operator ConfigMap changes.
+func SetImageMirror(mirror string) {
+	imageMirror.Store(&mirror)
+}
+
+// currentImageMirror returns the registry mirror, empty when images are pulled from their registries
+func currentImageMirror() string {
+	if mirror := imageMirror.Load(); mirror != nil {
+		return *mirror
+	}
+	return ""
+}
+
+// mirrorImage rewrites a platform image to the registry mirror, unchanged without mirror
+func mirrorImage(image string) string {
+	return config.MirrorImage(currentImageMirror(), image)
+}
+
+// taskImageParams are the image params of the kibaship Tekton tasks with their defaults. With a
+// registry mirror the pipelines pass them rewritten, so build pods never reach public registries.
+var taskImageParams = map[string]map[string]string{
+	pipelines.GitCloneTaskName:        {"gitImage": "alpine/git:latest"},
+	pipelines.RailpackPrepareTaskName: {"railpackImage": "kibamail/kibaship-railpack-cli"},
+	pipelines.RailpackBuildTaskName: {
+		"buildImage":             "kibamail/kibaship-railpack-build:0.1.0",
+		"railpackFrontendSource": "ghcr.io/railwayapp/railpack-frontend:v0.9.0",
+	},
+	pipelines.NixpacksBuildTaskName: {
+		"buildImage":    "moby/buildkit:v0.25.1-rootless",
+		"nixpacksImage": "kibamail/kibaship-nixpacks-cli",
+	},
+	pipelines.DockerfileBuildTaskName:  {"buildImage": "moby/buildkit:v0.25.1-rootless"},
+	pipelines.BuildpacksBuildTaskName:  {"builderImage": platformv1alpha1.DefaultBuildpacksBuilderImage},
+	pipelines.PublishArtifactsTaskName: {"curlImage": "curlimages/curl:8.12.1"},
+}
+
+// mirrorPipelineImages rewrites every image a pipeline pulls to the registry mirror: the image
+// params of the cluster tasks and the step images of embedded tasks such as custom steps
+func mirrorPipelineImages(pipeline *tektonv1.Pipeline) {
+	if currentImageMirror() == "" {
+		return
+	}
+	for _, tasks := range [][]tektonv1.PipelineTask{pipeline.Spec.Tasks, pipeline.Spec.Finally} {
+		for i := range tasks {
+			mirrorPipelineTaskImages(&tasks[i])
+		}
+	}
+}
+
+// mirrorPipelineTaskImages rewrites the images of a single pipeline task
+func mirrorPipelineTaskImages(task *tektonv1.PipelineTask) {
+	if task.TaskSpec != nil {
+		for i := range task.TaskSpec.Steps {
+			task.TaskSpec.Steps[i].Image = mirrorImage(task.TaskSpec.Steps[i].Image)
+		}
+	}
+	if task.TaskRef == nil {
+		return
+	}
+
+	var taskName string
+	for _, param := range task.TaskRef.Params {
+		if param.Name == "name" {
+			taskName = param.Value.StringVal
+		}
+	}
+	defaults := taskImageParams[taskName]
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		found := false
+		for i := range task.Params {
+			if task.Params[i].Name == name {
+				task.Params[i].Value.StringVal = mirrorImage(task.Params[i].Value.StringVal)
+				found = true
+			}
+		}
+		if !found {
+			task.Params = append(task.Params, tektonv1.Param{
+				Name:  name,
+				Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: mirrorImage(defaults[name])},
+			})
+		}
+	}
+}
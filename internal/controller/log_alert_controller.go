@@ -0,0 +1,182 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/logalert"
+	"github.com/kibamail/kibaship/pkg/webhooks"
+)
+
+// LogMatchCounter counts the log lines matching a LogQL metric query, implemented by logalert.Client
+type LogMatchCounter interface {
+	CountMatches(ctx context.Context, query string, at time.Time) (int64, error)
+}
+
+// LogAlertReconciler evaluates the log alert rules of applications against the persisted logs
+// every minute, recording their state on the application status and sending webhooks when a
+// rule starts and stops firing
+type LogAlertReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Notifier webhooks.Notifier
+
+	// Logs counts matching log lines in the log store
+	Logs LogMatchCounter
+
+	// Clock evaluates rules and stamps transitions, the real clock when nil
+	Clock clock.PassiveClock
+}
+
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications/status,verbs=get;update;patch
+
+// Reconcile evaluates the log alert rules of an application and schedules the next evaluation
+func (r *LogAlertReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var app platformv1alpha1.Application
+	if err := r.Get(ctx, req.NamespacedName, &app); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !app.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	if len(app.Spec.LogAlerts) == 0 {
+		return ctrl.Result{}, r.updateStatus(ctx, &app, nil)
+	}
+
+	now := currentTime(r.Clock)
+	previous := make(map[string]platformv1alpha1.LogAlertStatus, len(app.Status.LogAlerts))
+	for _, status := range app.Status.LogAlerts {
+		previous[status.Name] = status
+	}
+
+	next := make([]platformv1alpha1.LogAlertStatus, 0, len(app.Spec.LogAlerts))
+	for i := range app.Spec.LogAlerts {
+		rule := &app.Spec.LogAlerts[i]
+		prev, seen := previous[rule.Name]
+		if !seen {
+			prev = platformv1alpha1.LogAlertStatus{Name: rule.Name, State: platformv1alpha1.LogAlertStateOK}
+		}
+
+		query := logalert.Query(app.Namespace, app.GetUUID(), rule.Pattern, rule.Match == platformv1alpha1.LogAlertMatchRegex)
+		matches, err := r.Logs.CountMatches(ctx, query, now)
+		status := nextLogAlertStatus(prev, rule, matches, err, now)
+		next = append(next, status)
+
+		if status.State != prev.State {
+			log.Info("Log alert state changed", "rule", rule.Name, "state", status.State, "matches", matches)
+			r.emitLogAlert(ctx, &app, rule, status)
+		}
+	}
+
+	if err := r.updateStatus(ctx, &app, next); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: logalert.EvaluationInterval}, nil
+}
+
+// nextLogAlertStatus folds an evaluation of rule into its status. A failed evaluation keeps the
+// state and records the error.
+func nextLogAlertStatus(previous platformv1alpha1.LogAlertStatus, rule *platformv1alpha1.LogAlertRule, matches int64, err error, now time.Time) platformv1alpha1.LogAlertStatus {
+	next := *previous.DeepCopy()
+	if err != nil {
+		next.LastError = err.Error()
+		return next
+	}
+	next.LastError = ""
+
+	state := platformv1alpha1.LogAlertStateOK
+	if matches >= rule.Threshold() {
+		state = platformv1alpha1.LogAlertStateFiring
+	}
+	if state != previous.State {
+		next.State = state
+		next.Matches = matches
+		next.LastTransitionTime = &metav1.Time{Time: now}
+	}
+	return next
+}
+
+// emitLogAlert sends a webhook when a log alert rule starts or stops firing
+func (r *LogAlertReconciler) emitLogAlert(ctx context.Context, app *platformv1alpha1.Application, rule *platformv1alpha1.LogAlertRule, status platformv1alpha1.LogAlertStatus) {
+	if r.Notifier == nil {
+		return
+	}
+
+	evt := webhooks.ApplicationLogAlertEvent{
+		Type:               "application.logalert.firing",
+		Rule:               rule.Name,
+		Pattern:            rule.Pattern,
+		Matches:            status.Matches,
+		ThresholdPerMinute: rule.Threshold(),
+		Application:        *app,
+		Timestamp:          currentTime(r.Clock).UTC(),
+	}
+	if status.State == platformv1alpha1.LogAlertStateOK {
+		evt.Type = "application.logalert.resolved"
+	}
+	_ = r.Notifier.NotifyApplicationLogAlert(ctx, evt)
+}
+
+// updateStatus records the log alert states, clearing them when next is empty
+func (r *LogAlertReconciler) updateStatus(ctx context.Context, app *platformv1alpha1.Application, next []platformv1alpha1.LogAlertStatus) error {
+	if len(next) == 0 {
+		next = nil
+	}
+	if reflect.DeepEqual(app.Status.LogAlerts, next) {
+		return nil
+	}
+
+	patch := client.MergeFrom(app.DeepCopy())
+	app.Status.LogAlerts = next
+	if err := r.Status().Patch(ctx, app, patch); err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to update application log alert status: %w", err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Status updates are ignored, rules are evaluated on spec changes and every evaluation interval.
+func (r *LogAlertReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&platformv1alpha1.Application{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controllerOptions("application-logalert", 1)).
+		Named("application-logalert").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
+		Complete(r)
+}
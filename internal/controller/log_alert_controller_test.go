@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/logalert"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// fakeLogMatchCounter returns a fixed number of matches, or err, for every query
+type fakeLogMatchCounter struct {
+	matches int64
+	err     error
+	queries []string
+}
+
+func (f *fakeLogMatchCounter) CountMatches(_ context.Context, query string, _ time.Time) (int64, error) {
+	f.queries = append(f.queries, query)
+	return f.matches, f.err
+}
+
+func TestLogAlertReconciler(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-app-1",
+			Namespace: "project-1",
+			Labels:    map[string]string{validation.LabelResourceUUID: "app-1"},
+		},
+		Spec: platformv1alpha1.ApplicationSpec{
+			LogAlerts: []platformv1alpha1.LogAlertRule{{
+				Name:               "database-errors",
+				Pattern:            "ECONNREFUSED|too many connections",
+				Match:              platformv1alpha1.LogAlertMatchRegex,
+				ThresholdPerMinute: 5,
+			}},
+		},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).WithStatusSubresource(app).Build()
+	clk := clocktesting.NewFakePassiveClock(time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC))
+	counter := &fakeLogMatchCounter{matches: 2}
+	notifier := &recordingNotifier{}
+	r := &LogAlertReconciler{Client: cl, Scheme: scheme, Notifier: notifier, Logs: counter, Clock: clk}
+
+	evaluate := func() platformv1alpha1.LogAlertStatus {
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(logalert.EvaluationInterval))
+		updated := &platformv1alpha1.Application{}
+		g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(app), updated)).To(Succeed())
+		g.Expect(updated.Status.LogAlerts).To(HaveLen(1))
+		return updated.Status.LogAlerts[0]
+	}
+
+	// Below the threshold the rule is OK and no webhook is sent
+	status := evaluate()
+	g.Expect(status.State).To(Equal(platformv1alpha1.LogAlertStateOK))
+	g.Expect(counter.queries[0]).To(Equal(logalert.Query("project-1", "app-1", "ECONNREFUSED|too many connections", true)))
+	g.Expect(notifier.LogAlertEvents()).To(BeEmpty())
+
+	// Reaching the threshold fires the rule
+	counter.matches = 5
+	clk.SetTime(clk.Now().Add(time.Minute))
+	status = evaluate()
+	g.Expect(status.State).To(Equal(platformv1alpha1.LogAlertStateFiring))
+	g.Expect(status.Matches).To(Equal(int64(5)))
+	g.Expect(status.LastTransitionTime.Time).To(BeTemporally("==", clk.Now()))
+
+	events := notifier.LogAlertEvents()
+	g.Expect(events).To(HaveLen(1))
+	g.Expect(events[0].Type).To(Equal("application.logalert.firing"))
+	g.Expect(events[0].Rule).To(Equal("database-errors"))
+	g.Expect(events[0].ThresholdPerMinute).To(Equal(int64(5)))
+
+	// A failed evaluation keeps the rule firing and records the error
+	counter.err = errors.New("loki returned 503: too many outstanding requests")
+	status = evaluate()
+	g.Expect(status.State).To(Equal(platformv1alpha1.LogAlertStateFiring))
+	g.Expect(status.LastError).To(ContainSubstring("loki returned 503"))
+	g.Expect(notifier.LogAlertEvents()).To(HaveLen(1))
+
+	// The rule resolves once the matches drop below the threshold
+	counter.err = nil
+	counter.matches = 0
+	status = evaluate()
+	g.Expect(status.State).To(Equal(platformv1alpha1.LogAlertStateOK))
+	g.Expect(status.LastError).To(BeEmpty())
+
+	events = notifier.LogAlertEvents()
+	g.Expect(events).To(HaveLen(2))
+	g.Expect(events[1].Type).To(Equal("application.logalert.resolved"))
+}
+
+func TestLogAlertReconciler_ClearsStatusWithoutRules(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "application-app-2", Namespace: "project-1"},
+		Status: platformv1alpha1.ApplicationStatus{LogAlerts: []platformv1alpha1.LogAlertStatus{{
+			Name:  "removed",
+			State: platformv1alpha1.LogAlertStateFiring,
+		}}},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).WithStatusSubresource(app).Build()
+	counter := &fakeLogMatchCounter{}
+	r := &LogAlertReconciler{Client: cl, Scheme: scheme, Logs: counter}
+
+	result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeZero())
+	g.Expect(counter.queries).To(BeEmpty())
+
+	updated := &platformv1alpha1.Application{}
+	g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(app), updated)).To(Succeed())
+	g.Expect(updated.Status.LogAlerts).To(BeEmpty())
+}
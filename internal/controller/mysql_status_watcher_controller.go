@@ -27,5 +27,6 @@ func (r *MySQLStatusWatcherReconciler) Reconcile(ctx context.Context, req ctrl.R
 // TODO: SetupWithManager - MySQL status watcher setup will be reimplemented
 func (r *MySQLStatusWatcherReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// TODO: Implement new MySQL status watcher setup logic here
+	// Filter events with WithEventFilter(ShardPredicate(mgr.GetClient())) like the other watchers
 	return nil
 }
@@ -19,6 +19,9 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
+	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -48,6 +51,12 @@ const (
 	// ManagedByValue is the value for the managed-by label
 	ManagedByValue = "kibaship"
 
+	// AnnotationProjectLabels lists the namespace labels set from the project spec, so the ones
+	// removed from the spec are removed from the namespace
+	AnnotationProjectLabels = "platform.kibaship.com/project-labels"
+	// AnnotationProjectAnnotations lists the namespace annotations set from the project spec
+	AnnotationProjectAnnotations = "platform.kibaship.com/project-annotations"
+
 	// ServiceAccountNamePrefix is the prefix for the service account name
 	ServiceAccountNamePrefix = "project-"
 	// ServiceAccountNameSuffix is the suffix for the service account name
@@ -101,6 +110,12 @@ func (nm *NamespaceManager) CreateProjectNamespace(ctx context.Context, project
 		// Namespace already exists, verify it belongs to this project
 		if existingNamespace.Labels[validation.LabelResourceUUID] == project.Labels[validation.LabelResourceUUID] {
 			log.Info("Namespace already exists for project", "namespace", namespaceName)
+			if err := nm.syncNamespaceShard(ctx, existingNamespace, project); err != nil {
+				return nil, err
+			}
+			if err := nm.syncNamespaceMetadata(ctx, existingNamespace, project); err != nil {
+				return nil, err
+			}
 			return existingNamespace, nil
 		}
 		// Namespace exists but belongs to different project
@@ -123,6 +138,8 @@ func (nm *NamespaceManager) CreateProjectNamespace(ctx context.Context, project
 		},
 	}
 
+	applyProjectNamespaceMetadata(namespace, projectNamespaceConfig(project))
+
 	// Note: Cannot set owner reference because namespace is cluster-scoped and project is namespace-scoped
 	// Instead, we use labels for tracking and finalizers for cleanup
 
@@ -230,9 +247,125 @@ func (nm *NamespaceManager) generateNamespaceLabels(project *platformv1alpha1.Pr
 		labels[validation.LabelWorkspaceUUID] = workspaceUUID
 	}
 
+	if shard, exists := project.Labels[validation.LabelShard]; exists {
+		labels[validation.LabelShard] = shard
+	}
+
 	return labels
 }
 
+// syncNamespaceShard copies the shard label of the project to its namespace, so the resources of a
+// project moved to another shard are reconciled by the operator of that shard
+func (nm *NamespaceManager) syncNamespaceShard(ctx context.Context, namespace *corev1.Namespace, project *platformv1alpha1.Project) error {
+	shard, exists := project.Labels[validation.LabelShard]
+	current, hasShard := namespace.Labels[validation.LabelShard]
+	if exists == hasShard && shard == current {
+		return nil
+	}
+
+	patch := client.MergeFrom(namespace.DeepCopy())
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
+	if exists {
+		namespace.Labels[validation.LabelShard] = shard
+	} else {
+		delete(namespace.Labels, validation.LabelShard)
+	}
+	if err := nm.Patch(ctx, namespace, patch); err != nil {
+		return fmt.Errorf("failed to update shard of namespace %s: %w", namespace.Name, err)
+	}
+
+	logf.FromContext(ctx).Info("Moved project namespace to shard", "namespace", namespace.Name, "shard", shard)
+	return nil
+}
+
+// syncNamespaceMetadata applies the labels and annotations of the project spec to its namespace,
+// restoring the ones changed by hand and removing the ones dropped from the spec
+func (nm *NamespaceManager) syncNamespaceMetadata(ctx context.Context, namespace *corev1.Namespace, project *platformv1alpha1.Project) error {
+	patch := client.MergeFrom(namespace.DeepCopy())
+	if !applyProjectNamespaceMetadata(namespace, projectNamespaceConfig(project)) {
+		return nil
+	}
+	if err := nm.Patch(ctx, namespace, patch); err != nil {
+		return fmt.Errorf("failed to update labels and annotations of namespace %s: %w", namespace.Name, err)
+	}
+
+	logf.FromContext(ctx).Info("Synced project namespace labels and annotations", "namespace", namespace.Name)
+	return nil
+}
+
+// projectNamespaceConfig returns the namespace config of the project spec with the Pod Security
+// Standards labels of its security level, tracked like the spec labels so clearing the level
+// removes them
+func projectNamespaceConfig(project *platformv1alpha1.Project) platformv1alpha1.NamespaceConfig {
+	config := project.Spec.Namespace
+	level := string(project.Spec.SecurityLevel)
+	if level == "" {
+		return config
+	}
+
+	labels := maps.Clone(config.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for _, mode := range []string{"enforce", "audit", "warn"} {
+		labels[platformv1alpha1.PodSecurityLabelPrefix+mode] = level
+		labels[platformv1alpha1.PodSecurityLabelPrefix+mode+"-version"] = "latest"
+	}
+	config.Labels = labels
+	return config
+}
+
+// applyProjectNamespaceMetadata sets the labels and annotations of config on namespace and removes
+// the ones a previous config set, it reports whether namespace changed
+func applyProjectNamespaceMetadata(namespace *corev1.Namespace, config platformv1alpha1.NamespaceConfig) bool {
+	labels := maps.Clone(namespace.Labels)
+	annotations := maps.Clone(namespace.Annotations)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	applyProjectKeys(labels, annotations, AnnotationProjectLabels, config.Labels)
+	applyProjectKeys(annotations, annotations, AnnotationProjectAnnotations, config.Annotations)
+
+	if maps.Equal(labels, namespace.Labels) && maps.Equal(annotations, namespace.Annotations) {
+		return false
+	}
+	namespace.Labels = labels
+	namespace.Annotations = annotations
+	return true
+}
+
+// applyProjectKeys sets values on target, removes the keys listed in the tracking annotation that
+// values no longer holds, and records the keys of values in the tracking annotation
+func applyProjectKeys(target, annotations map[string]string, tracking string, values map[string]string) {
+	if previous := annotations[tracking]; previous != "" {
+		for _, key := range strings.Split(previous, ",") {
+			// The tracking annotation can be edited, never remove what belongs to the operator
+			if _, keep := values[key]; !keep && !platformv1alpha1.IsProtectedNamespaceKey(key) {
+				delete(target, key)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		target[key] = value
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if len(keys) == 0 {
+		delete(annotations, tracking)
+		return
+	}
+	annotations[tracking] = strings.Join(keys, ",")
+}
+
 // IsProjectNamespaceUnique checks if the project UUID would result in a unique namespace
 func (nm *NamespaceManager) IsProjectNamespaceUnique(ctx context.Context, projectUUID string, excludeProject *platformv1alpha1.Project) (bool, error) {
 	namespaceName := nm.GenerateNamespaceName(projectUUID)
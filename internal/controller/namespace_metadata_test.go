@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+func TestApplyProjectNamespaceMetadata(t *testing.T) {
+	g := NewWithT(t)
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "project-1",
+		Labels:      map[string]string{ManagedByLabel: ManagedByValue, "team": "manual"},
+		Annotations: map[string]string{"platform.kibaship.com/project": "shop"},
+	}}
+
+	changed := applyProjectNamespaceMetadata(namespace, platformv1alpha1.NamespaceConfig{
+		Labels:      map[string]string{"pod-security.kubernetes.io/enforce": "restricted", "cost-center": "billing"},
+		Annotations: map[string]string{"example.com/owner": "payments"},
+	})
+	g.Expect(changed).To(BeTrue())
+	g.Expect(namespace.Labels).To(Equal(map[string]string{
+		ManagedByLabel:                       ManagedByValue,
+		"team":                               "manual",
+		"pod-security.kubernetes.io/enforce": "restricted",
+		"cost-center":                        "billing",
+	}))
+	g.Expect(namespace.Annotations).To(HaveKeyWithValue("example.com/owner", "payments"))
+	g.Expect(namespace.Annotations).To(HaveKeyWithValue(AnnotationProjectLabels, "cost-center,pod-security.kubernetes.io/enforce"))
+	g.Expect(namespace.Annotations).To(HaveKeyWithValue(AnnotationProjectAnnotations, "example.com/owner"))
+
+	// Applying the same config again is a no-op
+	g.Expect(applyProjectNamespaceMetadata(namespace, platformv1alpha1.NamespaceConfig{
+		Labels:      map[string]string{"pod-security.kubernetes.io/enforce": "restricted", "cost-center": "billing"},
+		Annotations: map[string]string{"example.com/owner": "payments"},
+	})).To(BeFalse())
+
+	// Hand edits are reverted and keys dropped from the spec are removed, labels set by others stay
+	namespace.Labels["pod-security.kubernetes.io/enforce"] = "privileged"
+	changed = applyProjectNamespaceMetadata(namespace, platformv1alpha1.NamespaceConfig{
+		Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+	})
+	g.Expect(changed).To(BeTrue())
+	g.Expect(namespace.Labels).To(Equal(map[string]string{
+		ManagedByLabel:                       ManagedByValue,
+		"team":                               "manual",
+		"pod-security.kubernetes.io/enforce": "restricted",
+	}))
+	g.Expect(namespace.Annotations).To(Equal(map[string]string{
+		"platform.kibaship.com/project": "shop",
+		AnnotationProjectLabels:         "pod-security.kubernetes.io/enforce",
+	}))
+
+	// A tampered tracking annotation never removes operator labels
+	namespace.Annotations[AnnotationProjectLabels] = ManagedByLabel
+	applyProjectNamespaceMetadata(namespace, platformv1alpha1.NamespaceConfig{})
+	g.Expect(namespace.Labels).To(HaveKeyWithValue(ManagedByLabel, ManagedByValue))
+	g.Expect(namespace.Annotations).NotTo(HaveKey(AnnotationProjectLabels))
+}
@@ -140,6 +140,25 @@ var _ = Describe("NamespaceManager", func() {
 			Expect(namespace1.UID).To(Equal(namespace2.UID))
 		})
 
+		It("should move the namespace to the shard of the project", func() {
+			By("Creating the namespace in the default shard")
+			namespace, err := namespaceManager.CreateProjectNamespace(ctx, testProject)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(namespace.Labels).NotTo(HaveKey(validation.LabelShard))
+
+			By("Assigning the project to a shard")
+			testProject.Labels[validation.LabelShard] = "shard-a"
+			namespace, err = namespaceManager.CreateProjectNamespace(ctx, testProject)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(namespace.Labels[validation.LabelShard]).To(Equal("shard-a"))
+
+			By("Returning the project to the default shard")
+			delete(testProject.Labels, validation.LabelShard)
+			namespace, err = namespaceManager.CreateProjectNamespace(ctx, testProject)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(namespace.Labels).NotTo(HaveKey(validation.LabelShard))
+		})
+
 		It("should fail if namespace exists for different project", func() {
 			By("Creating a namespace manually with different project UUID")
 			conflictProjectUUID := "550e8400-e29b-41d4-a716-446655440099"
@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kibamail/kibaship/pkg/webhooks"
+)
+
+// recordingNotifier records the webhook events reconcilers emit so tests can assert on them
+type recordingNotifier struct {
+	mu               sync.Mutex
+	deploymentEvents []webhooks.OptimizedDeploymentStatusEvent
+	domainEvents     []webhooks.ApplicationDomainStatusEvent
+	healthEvents     []webhooks.ApplicationHealthEvent
+	logAlertEvents   []webhooks.ApplicationLogAlertEvent
+}
+
+var _ webhooks.Notifier = &recordingNotifier{}
+
+func (n *recordingNotifier) NotifyProjectStatusChange(_ context.Context, _ webhooks.ProjectStatusEvent) error {
+	return nil
+}
+
+func (n *recordingNotifier) NotifyEnvironmentStatusChange(_ context.Context, _ webhooks.EnvironmentStatusEvent) error {
+	return nil
+}
+
+func (n *recordingNotifier) NotifyApplicationStatusChange(_ context.Context, _ webhooks.ApplicationStatusEvent) error {
+	return nil
+}
+
+func (n *recordingNotifier) NotifyApplicationDomainStatusChange(_ context.Context, evt webhooks.ApplicationDomainStatusEvent) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.domainEvents = append(n.domainEvents, evt)
+	return nil
+}
+
+func (n *recordingNotifier) NotifyApplicationDomainUptimeChange(_ context.Context, _ webhooks.ApplicationDomainUptimeEvent) error {
+	return nil
+}
+
+func (n *recordingNotifier) NotifyApplicationHealthChange(_ context.Context, evt webhooks.ApplicationHealthEvent) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.healthEvents = append(n.healthEvents, evt)
+	return nil
+}
+
+func (n *recordingNotifier) NotifyApplicationLogAlert(_ context.Context, evt webhooks.ApplicationLogAlertEvent) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.logAlertEvents = append(n.logAlertEvents, evt)
+	return nil
+}
+
+func (n *recordingNotifier) NotifyDeploymentStatusChange(_ context.Context, _ webhooks.DeploymentStatusEvent) error {
+	return nil
+}
+
+func (n *recordingNotifier) NotifyOptimizedDeploymentStatusChange(_ context.Context, evt webhooks.OptimizedDeploymentStatusEvent) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.deploymentEvents = append(n.deploymentEvents, evt)
+	return nil
+}
+
+// DeploymentEvents returns the deployment events recorded so far
+func (n *recordingNotifier) DeploymentEvents() []webhooks.OptimizedDeploymentStatusEvent {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]webhooks.OptimizedDeploymentStatusEvent(nil), n.deploymentEvents...)
+}
+
+// DomainEvents returns the application domain events recorded so far
+func (n *recordingNotifier) DomainEvents() []webhooks.ApplicationDomainStatusEvent {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]webhooks.ApplicationDomainStatusEvent(nil), n.domainEvents...)
+}
+
+// HealthEvents returns the application health events recorded so far
+func (n *recordingNotifier) HealthEvents() []webhooks.ApplicationHealthEvent {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]webhooks.ApplicationHealthEvent(nil), n.healthEvents...)
+}
+
+// LogAlertEvents returns the log alert events recorded so far
+func (n *recordingNotifier) LogAlertEvents() []webhooks.ApplicationLogAlertEvent {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]webhooks.ApplicationLogAlertEvent(nil), n.logAlertEvents...)
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+const (
+	// OperatorConfigConditionApplied reports whether the latest ConfigMap contents were applied
+	OperatorConfigConditionApplied = "Applied"
+	// OperatorConfigConditionManualActionRequired reports changes that were not fully applied automatically
+	OperatorConfigConditionManualActionRequired = "ManualActionRequired"
+
+	// operatorConfigStatusKey holds the JSON encoded conditions in the status ConfigMap
+	operatorConfigStatusKey = "conditions"
+	// operatorConfigObservedKey holds the resourceVersion of the last observed configuration
+	operatorConfigObservedKey = "observedResourceVersion"
+)
+
+// OperatorConfigReconciler watches the operator ConfigMap and hot-reloads the configuration.
+// Valid changes replace the global configuration atomically and re-run the affected bootstrap
+// steps through Apply. Conditions describing the outcome are written to the
+// kibaship-config-status ConfigMap and mirrored as Events on the operator ConfigMap.
+// Every shard reloads its own configuration, only the operator of the default shard reports
+// the conditions so shards do not race on the status ConfigMap.
+type OperatorConfigReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// Apply re-runs the bootstrap steps affected by a configuration change
+	Apply func(ctx context.Context, previous, current *config.OperatorConfiguration) error
+
+	mu      sync.Mutex
+	current *config.OperatorConfiguration
+}
+
+// NewOperatorConfigReconciler creates a reconciler starting from the configuration loaded at startup
+func NewOperatorConfigReconciler(
+	c client.Client,
+	scheme *runtime.Scheme,
+	recorder record.EventRecorder,
+	initial *config.OperatorConfiguration,
+	apply func(ctx context.Context, previous, current *config.OperatorConfiguration) error,
+) *OperatorConfigReconciler {
+	return &OperatorConfigReconciler{
+		Client:   c,
+		Scheme:   scheme,
+		Recorder: recorder,
+		Apply:    apply,
+		current:  initial,
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile validates the operator ConfigMap and applies changes
+func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var cm corev1.ConfigMap
+	if err := r.Get(ctx, req.NamespacedName, &cm); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("Operator ConfigMap deleted, keeping the last applied configuration")
+			return ctrl.Result{}, r.setConditions(ctx, "", metav1.Condition{
+				Type:    OperatorConfigConditionApplied,
+				Status:  metav1.ConditionFalse,
+				Reason:  "ConfigMapMissing",
+				Message: "operator ConfigMap was deleted, the last applied configuration stays active",
+			})
+		}
+		return ctrl.Result{}, err
+	}
+
+	next, err := config.ParseOperatorConfiguration(&cm)
+	if err != nil {
+		logger.Error(err, "Rejected invalid operator configuration, keeping the last applied configuration")
+		r.recordEvent(&cm, corev1.EventTypeWarning, "InvalidConfiguration", err.Error())
+		return ctrl.Result{}, r.setConditions(ctx, cm.ResourceVersion, metav1.Condition{
+			Type:    OperatorConfigConditionApplied,
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidConfiguration",
+			Message: err.Error(),
+		})
+	}
+
+	changes := config.DiffConfigurations(r.current, next)
+	if len(changes) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	for _, change := range changes {
+		logger.Info("Operator configuration changed", "key", change.Key, "manualAction", change.RequiresManualAction, "message", change.Message)
+	}
+
+	// Swap the controller configuration first so new reconciles use the new values
+	if err := UpdateOperatorConfig(next.Domain, next.IngressProvider, next.GatewayClassName, next.IngressClassName); err != nil {
+		logger.Error(err, "Rejected operator configuration")
+		r.recordEvent(&cm, corev1.EventTypeWarning, "InvalidConfiguration", err.Error())
+		return ctrl.Result{}, r.setConditions(ctx, cm.ResourceVersion, metav1.Condition{
+			Type:    OperatorConfigConditionApplied,
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidConfiguration",
+			Message: err.Error(),
+		})
+	}
+
+	SetBuildScheduling(next.Builds)
+	SetBuildKitPool(next.BuildKit)
+	SetTLSPolicy(next.TLS)
+	platformv1alpha1.SetImagePolicy(next.Images)
+	platformv1alpha1.SetBaseDomainPolicy(next.BaseDomainPolicy())
+	SetImageMirror(next.ImageMirror)
+	SetDrainPeriod(next.DrainPeriod)
+
+	previous := r.current
+	r.current = next
+
+	if r.Apply != nil {
+		if err := r.Apply(ctx, previous, next); err != nil {
+			logger.Error(err, "Failed to re-run bootstrap for configuration change")
+			r.recordEvent(&cm, corev1.EventTypeWarning, "BootstrapFailed", err.Error())
+			// Restore the previous snapshot so the change is retried on requeue
+			r.current = previous
+			if condErr := r.setConditions(ctx, cm.ResourceVersion, metav1.Condition{
+				Type:    OperatorConfigConditionApplied,
+				Status:  metav1.ConditionFalse,
+				Reason:  "BootstrapFailed",
+				Message: err.Error(),
+			}); condErr != nil {
+				logger.Error(condErr, "Failed to record operator configuration conditions")
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	applied := metav1.Condition{
+		Type:    OperatorConfigConditionApplied,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ConfigurationApplied",
+		Message: fmt.Sprintf("applied %d configuration change(s)", len(changes)),
+	}
+	manual := metav1.Condition{
+		Type:    OperatorConfigConditionManualActionRequired,
+		Status:  metav1.ConditionFalse,
+		Reason:  "NoActionRequired",
+		Message: "all changes were applied automatically",
+	}
+	if config.RequiresManualAction(changes) {
+		var messages []string
+		for _, change := range changes {
+			if change.RequiresManualAction {
+				messages = append(messages, change.Message)
+			}
+		}
+		manual.Status = metav1.ConditionTrue
+		manual.Reason = "ManualActionRequired"
+		manual.Message = strings.Join(messages, "; ")
+		r.recordEvent(&cm, corev1.EventTypeWarning, "ManualActionRequired", manual.Message)
+	}
+	r.recordEvent(&cm, corev1.EventTypeNormal, "ConfigurationApplied", applied.Message)
+
+	logger.Info("Operator configuration reloaded", "changes", len(changes), "manualActionRequired", manual.Status == metav1.ConditionTrue)
+	return ctrl.Result{}, r.setConditions(ctx, cm.ResourceVersion, applied, manual)
+}
+
+// setConditions merges the conditions into the status ConfigMap
+func (r *OperatorConfigReconciler) setConditions(ctx context.Context, observedVersion string, conditions ...metav1.Condition) error {
+	if !isDefaultShard() {
+		return nil
+	}
+
+	status := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: config.OperatorNamespace, Name: config.OperatorConfigStatusConfigMapName}
+	exists := true
+	if err := r.Get(ctx, key, status); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		exists = false
+		status = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "kibaship",
+				},
+			},
+		}
+	}
+
+	var existing []metav1.Condition
+	if raw := status.Data[operatorConfigStatusKey]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &existing); err != nil {
+			existing = nil
+		}
+	}
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&existing, condition)
+	}
+
+	encoded, err := json.Marshal(existing)
+	if err != nil {
+		return err
+	}
+	if status.Data == nil {
+		status.Data = map[string]string{}
+	}
+	status.Data[operatorConfigStatusKey] = string(encoded)
+	if observedVersion != "" {
+		status.Data[operatorConfigObservedKey] = observedVersion
+	}
+
+	if exists {
+		return r.Update(ctx, status)
+	}
+	return r.Create(ctx, status)
+}
+
+func (r *OperatorConfigReconciler) recordEvent(cm *corev1.ConfigMap, eventType, reason, message string) {
+	if r.Recorder == nil || !isDefaultShard() {
+		return
+	}
+	r.Recorder.Event(cm, eventType, reason, message)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Only the operator ConfigMap is watched.
+func (r *OperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	isOperatorConfig := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetNamespace() == config.OperatorNamespace && obj.GetName() == config.OperatorConfigMapName
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.ConfigMap{}, builder.WithPredicates(isOperatorConfig, predicate.ResourceVersionChangedPredicate{})).
+		WithOptions(controllerOptions("operator-config", 1)).
+		Named("operator-config").
+		Complete(r)
+}
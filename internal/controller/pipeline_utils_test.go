@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/pipelines"
+	"github.com/kibamail/kibaship/pkg/validation"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+func pipelineTaskParam(task tektonv1.PipelineTask, name string) string {
+	for _, param := range task.Params {
+		if param.Name == name {
+			return param.Value.StringVal
+		}
+	}
+	return ""
+}
+
+func TestGeneratePipelineImageMirror(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	SetImageMirror("mirror.internal:5000")
+	t.Cleanup(func() { SetImageMirror("") })
+
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+	r := &DeploymentReconciler{Scheme: scheme}
+
+	deployment := &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      "deployment-dep-1",
+		Namespace: "project-ns",
+		Labels:    map[string]string{validation.LabelResourceUUID: "dep-1"},
+	}}
+	app := &platformv1alpha1.Application{Spec: platformv1alpha1.ApplicationSpec{
+		GitRepository: &platformv1alpha1.GitRepositoryConfig{
+			Provider:     platformv1alpha1.GitProviderGitHub,
+			Repository:   "org/repo",
+			PublicAccess: true,
+			Steps:        []platformv1alpha1.PipelineStep{{Name: "test", Image: "node:20", Script: "npm test"}},
+		},
+	}}
+
+	pipeline, err := r.generatePipeline(ctx, deployment, app, "pipeline-dep-1", "project")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pipeline.Spec.Tasks).To(HaveLen(4))
+	g.Expect(pipelineTaskParam(pipeline.Spec.Tasks[0], "gitImage")).To(Equal("mirror.internal:5000/docker.io/alpine/git:latest"))
+	g.Expect(pipeline.Spec.Tasks[1].TaskSpec.Steps[0].Image).To(Equal("mirror.internal:5000/docker.io/library/node:20"))
+	g.Expect(pipelineTaskParam(pipeline.Spec.Tasks[2], "railpackImage")).To(Equal("mirror.internal:5000/docker.io/kibamail/kibaship-railpack-cli"))
+	g.Expect(pipelineTaskParam(pipeline.Spec.Tasks[3], "railpackFrontendSource")).
+		To(Equal("mirror.internal:5000/ghcr.io/railwayapp/railpack-frontend:v0.9.0"))
+
+	// Configured builder images are mirrored too
+	app.Spec.GitRepository.BuildType = platformv1alpha1.BuildTypeBuildpacks
+	app.Spec.GitRepository.BuildpacksBuild = &platformv1alpha1.BuildpacksBuildConfig{BuilderImage: "heroku/builder:24"}
+	pipeline, err = r.generatePipeline(ctx, deployment, app, "pipeline-dep-1", "project")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pipelineTaskParam(pipeline.Spec.Tasks[2], "builderImage")).To(Equal("mirror.internal:5000/docker.io/heroku/builder:24"))
+}
+
+func TestGeneratePipelineSpecVersion(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+	r := &DeploymentReconciler{Scheme: scheme}
+
+	deployment := &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      "deployment-dep-1",
+		Namespace: "project-ns",
+		Labels:    map[string]string{validation.LabelResourceUUID: "dep-1"},
+	}}
+	app := &platformv1alpha1.Application{Spec: platformv1alpha1.ApplicationSpec{
+		GitRepository: &platformv1alpha1.GitRepositoryConfig{
+			Provider:     platformv1alpha1.GitProviderGitHub,
+			Repository:   "org/repo",
+			PublicAccess: true,
+		},
+	}}
+
+	// New deployments use the current spec version
+	pipeline, err := r.generatePipeline(ctx, deployment, app, "pipeline-dep-1", "project")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pipeline.Annotations).To(HaveKeyWithValue(pipelines.AnnotationSpecVersion, pipelines.CurrentSpecVersion))
+	g.Expect(pipeline.OwnerReferences).To(HaveLen(1))
+
+	// Deployments keep the version they recorded, unknown versions are refused
+	deployment.Annotations = map[string]string{pipelines.AnnotationSpecVersion: "v0"}
+	_, err = r.generatePipeline(ctx, deployment, app, "pipeline-dep-1", "project")
+	g.Expect(err).To(MatchError(ContainSubstring(`unsupported pipeline spec version "v0"`)))
+}
@@ -165,6 +165,7 @@ func (r *PipelineRunWatcherReconciler) Reconcile(ctx context.Context, req ctrl.R
 				Phase:     string(dep.Status.Phase),
 				Slug:      dep.GetSlug(),
 			},
+			Source: webhooks.NewDeploymentSource(&dep),
 			PipelineRunRef: &struct {
 				Name   string `json:"name"`
 				Status string `json:"status"`
@@ -193,6 +194,8 @@ func (r *PipelineRunWatcherReconciler) SetupWithManager(mgr ctrl.Manager) error
 	return ctrl.NewControllerManagedBy(mgr).
 		For(u).
 		WithEventFilter(pred).
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
+		WithOptions(controllerOptions("pipelinerun", 1)).
 		Complete(r)
 }
 
@@ -18,16 +18,18 @@ package controller
 
 import (
 	"context"
+	"strconv"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/pipelines"
+	"github.com/kibamail/kibaship/pkg/validation"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 )
 
@@ -46,16 +48,13 @@ func (r *PipelineRunStatusController) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&tektonv1.PipelineRun{}).
 		WithEventFilter(predicate.ResourceVersionChangedPredicate{}).
-		WithOptions(controller.Options{
-			MaxConcurrentReconciles: 50, // High concurrency for status updates
-		}).
+		WithOptions(controllerOptions("pipelinerun-status", 50)). // High concurrency for status updates
 		Named("pipelinerun-status").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
 		Complete(r)
 }
 
 func (r *PipelineRunStatusController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := ctrl.LoggerFrom(ctx)
-
 	var pipelineRun tektonv1.PipelineRun
 	if err := r.Get(ctx, req.NamespacedName, &pipelineRun); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
@@ -83,11 +82,19 @@ func (r *PipelineRunStatusController) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	return ctrl.Result{}, recordPipelineRunStatus(ctx, r.Client, &pipelineRun, &deployment)
+}
+
+// recordPipelineRunStatus mirrors the Succeeded condition of pipelineRun into the
+// PipelineRunReady condition of deployment, along with the digest of the pushed image
+func recordPipelineRunStatus(ctx context.Context, c client.Client, pipelineRun *tektonv1.PipelineRun, deployment *platformv1alpha1.Deployment) error {
+	log := ctrl.LoggerFrom(ctx)
+
 	// Extract PipelineRun status
 	succeededCondition := pipelineRun.Status.GetCondition("Succeeded")
 	if succeededCondition == nil {
 		// No status yet
-		return ctrl.Result{}, nil
+		return nil
 	}
 
 	// CRITICAL: Idempotency check - compare ResourceVersion
@@ -97,7 +104,7 @@ func (r *PipelineRunStatusController) Reconcile(ctx context.Context, req ctrl.Re
 	if lastProcessedVersion == currentVersion {
 		log.V(1).Info("Already processed this PipelineRun version",
 			"version", currentVersion)
-		return ctrl.Result{}, nil
+		return nil
 	}
 
 	// Update Deployment condition (not phase - that's DeploymentProgressController's job)
@@ -110,26 +117,51 @@ func (r *PipelineRunStatusController) Reconcile(ctx context.Context, req ctrl.Re
 		Message:            succeededCondition.Message,
 	}
 
+	// Tests force build failures through a fault annotation, whatever the PipelineRun reports
+	if value, ok := injectedFault(deployment, validation.AnnotationFaultPipelineRunFailure); ok {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = FaultInjectedReason
+		condition.Message = injectedFailureMessage(value, "PipelineRun failure injected")
+	}
+
 	meta.SetStatusCondition(&deployment.Status.Conditions, condition)
 
+	// Record the digest of the pushed image, promotions to other environments pin it, and the
+	// port the image exposes, applications without a port listen on it
+	for _, result := range pipelineRun.Status.Results {
+		switch result.Name {
+		case pipelines.ResultImageDigest:
+			if result.Value.StringVal != "" {
+				deployment.Status.ImageDigest = result.Value.StringVal
+			}
+		case pipelines.ResultExposedPort:
+			if port, err := strconv.ParseInt(result.Value.StringVal, 10, 32); err == nil && port > 0 && port <= 65535 {
+				deployment.Status.DetectedPort = int32(port)
+			}
+		}
+	}
+
 	// Update annotation to prevent reprocessing
 	if deployment.Annotations == nil {
 		deployment.Annotations = make(map[string]string)
 	}
 	deployment.Annotations["platform.kibaship.com/last-pipelinerun-version"] = currentVersion
 
-	// Atomic update: annotation + condition
-	if err := r.Update(ctx, &deployment); err != nil {
-		return ctrl.Result{}, err
+	// Atomic update: annotation + condition. Update refreshes the object from the server,
+	// which drops the unsaved status, so it is restored before the status update.
+	status := deployment.Status.DeepCopy()
+	if err := c.Update(ctx, deployment); err != nil {
+		return err
 	}
-	if err := r.Status().Update(ctx, &deployment); err != nil {
-		return ctrl.Result{}, err
+	deployment.Status = *status
+	if err := c.Status().Update(ctx, deployment); err != nil {
+		return err
 	}
 
 	log.Info("Updated Deployment condition from PipelineRun",
-		"deployment", deploymentName,
+		"deployment", deployment.Name,
 		"condition", condition.Type,
 		"status", condition.Status)
 
-	return ctrl.Result{}, nil
+	return nil
 }
@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+)
+
+// Variables describing the deployment context of an application container
+const (
+	PlatformEnvProject        = "KIBASHIP_PROJECT"
+	PlatformEnvEnvironment    = "KIBASHIP_ENVIRONMENT"
+	PlatformEnvApplication    = "KIBASHIP_APPLICATION"
+	PlatformEnvDeploymentUUID = "KIBASHIP_DEPLOYMENT_UUID"
+	PlatformEnvCommitSHA      = "KIBASHIP_COMMIT_SHA"
+	PlatformEnvPublicURL      = "KIBASHIP_PUBLIC_URL"
+	PlatformEnvPort           = "PORT"
+)
+
+// platformEnv returns the deployment context variables of the containers of an application
+// listening on port, or nil when the application opted out. Variables without a value, such as
+// the commit of a registry image, are left out.
+func platformEnv(ctx context.Context, c client.Reader, deployment *platformv1alpha1.Deployment, app *platformv1alpha1.Application, port int32) ([]corev1.EnvVar, error) {
+	if !app.PlatformEnvEnabled() {
+		return nil, nil
+	}
+
+	var projectSlug string
+	project := &platformv1alpha1.Project{}
+	if err := c.Get(ctx, client.ObjectKey{Name: utils.GetProjectResourceName(deployment.GetProjectUUID())}, project); err == nil {
+		projectSlug = project.GetSlug()
+	} else if !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	var environmentSlug string
+	environment := &platformv1alpha1.Environment{}
+	if err := c.Get(ctx, client.ObjectKey{Name: app.Spec.EnvironmentRef.Name, Namespace: app.Namespace}, environment); err == nil {
+		environmentSlug = environment.GetSlug()
+	} else if !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get environment: %w", err)
+	}
+
+	publicURL, err := applicationPublicURL(ctx, c, app)
+	if err != nil {
+		return nil, err
+	}
+
+	var commitSHA string
+	if deployment.Spec.GitRepository != nil {
+		commitSHA = deployment.Spec.GitRepository.CommitSHA
+	}
+
+	var env []corev1.EnvVar
+	for _, variable := range []corev1.EnvVar{
+		{Name: PlatformEnvProject, Value: projectSlug},
+		{Name: PlatformEnvEnvironment, Value: environmentSlug},
+		{Name: PlatformEnvApplication, Value: app.GetSlug()},
+		{Name: PlatformEnvDeploymentUUID, Value: deployment.GetUUID()},
+		{Name: PlatformEnvCommitSHA, Value: commitSHA},
+		{Name: PlatformEnvPublicURL, Value: publicURL},
+		{Name: PlatformEnvPort, Value: strconv.Itoa(int(port))},
+	} {
+		if variable.Value != "" {
+			env = append(env, variable)
+		}
+	}
+	return env, nil
+}
+
+// applicationPublicURL returns the URL of the default domain of an application, empty when it
+// has none
+func applicationPublicURL(ctx context.Context, c client.Reader, app *platformv1alpha1.Application) (string, error) {
+	var domains platformv1alpha1.ApplicationDomainList
+	if err := c.List(ctx, &domains, client.InNamespace(app.Namespace),
+		client.MatchingLabels{ApplicationDomainLabelApplication: app.Name}); err != nil {
+		return "", fmt.Errorf("failed to list application domains: %w", err)
+	}
+	for _, domain := range domains.Items {
+		if !domain.Spec.Default || domain.Spec.Domain == "" {
+			continue
+		}
+		if domain.Spec.TLSEnabled {
+			return "https://" + domain.Spec.Domain, nil
+		}
+		return "http://" + domain.Spec.Domain, nil
+	}
+	return "", nil
+}
+
+// withPlatformEnv adds the deployment context variables to env. The platform owns these names:
+// variables of the env secret with the same names are dropped rather than shadowed, apps that
+// need their own values opt out of the platform variables.
+func withPlatformEnv(env, platform []corev1.EnvVar) []corev1.EnvVar {
+	if len(platform) == 0 {
+		return env
+	}
+	reserved := make(map[string]bool, len(platform))
+	for _, variable := range platform {
+		reserved[variable.Name] = true
+	}
+	merged := make([]corev1.EnvVar, 0, len(env)+len(platform))
+	for _, variable := range env {
+		if !reserved[variable.Name] {
+			merged = append(merged, variable)
+		}
+	}
+	return append(merged, platform...)
+}
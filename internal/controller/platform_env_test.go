@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestPlatformEnv(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	const projectUUID = "6f1c2d3e-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+	project := &platformv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{
+		Name:   utils.GetProjectResourceName(projectUUID),
+		Labels: map[string]string{validation.LabelResourceSlug: "shop"},
+	}}
+	environment := &platformv1alpha1.Environment{ObjectMeta: metav1.ObjectMeta{
+		Name:      "environment-production",
+		Namespace: "project-shop",
+		Labels:    map[string]string{validation.LabelResourceSlug: "production"},
+	}}
+	domain := &platformv1alpha1.ApplicationDomain{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "domain-web",
+			Namespace: "project-shop",
+			Labels:    map[string]string{ApplicationDomainLabelApplication: "application-web"},
+		},
+		Spec: platformv1alpha1.ApplicationDomainSpec{Domain: "web-abc123.apps.example.com", Default: true, TLSEnabled: true},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(project, environment, domain).Build()
+
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-web",
+			Namespace: "project-shop",
+			Labels:    map[string]string{validation.LabelResourceSlug: "web"},
+		},
+		Spec: platformv1alpha1.ApplicationSpec{
+			EnvironmentRef: corev1.LocalObjectReference{Name: "environment-production"},
+			Type:           platformv1alpha1.ApplicationTypeGitRepository,
+		},
+	}
+	deployment := &platformv1alpha1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "deployment-1",
+			Namespace: "project-shop",
+			Labels: map[string]string{
+				validation.LabelResourceUUID: "0d1e2f3a-4b5c-4d6e-8f7a-9b0c1d2e3f4a",
+				validation.LabelProjectUUID:  projectUUID,
+			},
+		},
+		Spec: platformv1alpha1.DeploymentSpec{
+			GitRepository: &platformv1alpha1.GitRepositoryDeploymentConfig{CommitSHA: "9f8e7d6c"},
+		},
+	}
+
+	env, err := platformEnv(ctx, c, deployment, app, 8080)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(env).To(Equal([]corev1.EnvVar{
+		{Name: PlatformEnvProject, Value: "shop"},
+		{Name: PlatformEnvEnvironment, Value: "production"},
+		{Name: PlatformEnvApplication, Value: "web"},
+		{Name: PlatformEnvDeploymentUUID, Value: "0d1e2f3a-4b5c-4d6e-8f7a-9b0c1d2e3f4a"},
+		{Name: PlatformEnvCommitSHA, Value: "9f8e7d6c"},
+		{Name: PlatformEnvPublicURL, Value: "https://web-abc123.apps.example.com"},
+		{Name: PlatformEnvPort, Value: "8080"},
+	}))
+
+	// Registry images have no commit
+	deployment.Spec.GitRepository = nil
+	env, err = platformEnv(ctx, c, deployment, app, 8080)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(env).NotTo(ContainElement(HaveField("Name", PlatformEnvCommitSHA)))
+
+	// Applications can opt out
+	disabled := false
+	app.Spec.PlatformEnv = &disabled
+	env, err = platformEnv(ctx, c, deployment, app, 8080)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(env).To(BeEmpty())
+}
+
+func TestWithPlatformEnv(t *testing.T) {
+	g := NewWithT(t)
+
+	env := []corev1.EnvVar{{Name: "DATABASE_URL", Value: "postgres://db"}, {Name: PlatformEnvPort, Value: "9000"}}
+	platform := []corev1.EnvVar{{Name: PlatformEnvPort, Value: "3000"}}
+
+	g.Expect(withPlatformEnv(env, platform)).To(Equal([]corev1.EnvVar{
+		{Name: "DATABASE_URL", Value: "postgres://db"},
+		{Name: PlatformEnvPort, Value: "3000"},
+	}))
+	g.Expect(withPlatformEnv(env, nil)).To(Equal(env))
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ResolveSecurityLevel returns the Pod Security Standards level enforced on the namespace of the
+// project identified by projectUUID, empty when the project enforces none
+func ResolveSecurityLevel(ctx context.Context, c client.Reader, projectUUID string) (platformv1alpha1.SecurityLevel, error) {
+	if projectUUID == "" {
+		return "", nil
+	}
+
+	var projects platformv1alpha1.ProjectList
+	if err := c.List(ctx, &projects, client.MatchingLabels{validation.LabelResourceUUID: projectUUID}); err != nil {
+		return "", fmt.Errorf("failed to list projects: %w", err)
+	}
+	if len(projects.Items) == 0 {
+		return "", nil
+	}
+	return projects.Items[0].Spec.SecurityLevel, nil
+}
+
+// runtimeTmpVolume is the writable /tmp of applications with a read-only root filesystem
+const runtimeTmpVolume = "tmp"
+
+// applyRuntimeSecurity sets the security context of the application spec on an application pod
+func applyRuntimeSecurity(spec *corev1.PodSpec, config *platformv1alpha1.RuntimeSecurityConfig) {
+	if config == nil {
+		return
+	}
+
+	if config.FSGroup != nil {
+		if spec.SecurityContext == nil {
+			spec.SecurityContext = &corev1.PodSecurityContext{}
+		}
+		spec.SecurityContext.FSGroup = ptr.To(*config.FSGroup)
+	}
+	if config.ReadOnlyRootFilesystem {
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name:         runtimeTmpVolume,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+	}
+
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		if container.SecurityContext == nil {
+			container.SecurityContext = &corev1.SecurityContext{}
+		}
+		if config.RunAsUser != nil {
+			container.SecurityContext.RunAsUser = ptr.To(*config.RunAsUser)
+		}
+		if config.ReadOnlyRootFilesystem {
+			container.SecurityContext.ReadOnlyRootFilesystem = ptr.To(true)
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: runtimeTmpVolume, MountPath: "/tmp"})
+		}
+		if config.Capabilities != nil {
+			capabilities := &corev1.Capabilities{}
+			for _, capability := range config.Capabilities.Add {
+				capabilities.Add = append(capabilities.Add, corev1.Capability(capability))
+			}
+			for _, capability := range config.Capabilities.Drop {
+				capabilities.Drop = append(capabilities.Drop, corev1.Capability(capability))
+			}
+			container.SecurityContext.Capabilities = capabilities
+		}
+	}
+}
+
+// applyPodSecurity sets the security contexts of an application pod so it is admitted at level.
+// Both levels get the RuntimeDefault seccomp profile and no privilege escalation, restricted pods
+// also run as non-root with every capability dropped, so their image must not run as root.
+func applyPodSecurity(spec *corev1.PodSpec, level platformv1alpha1.SecurityLevel) {
+	if level == "" {
+		return
+	}
+
+	if spec.SecurityContext == nil {
+		spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	spec.SecurityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	if level == platformv1alpha1.SecurityLevelRestricted {
+		spec.SecurityContext.RunAsNonRoot = ptr.To(true)
+	}
+
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		if container.SecurityContext == nil {
+			container.SecurityContext = &corev1.SecurityContext{}
+		}
+		container.SecurityContext.AllowPrivilegeEscalation = ptr.To(false)
+		if level == platformv1alpha1.SecurityLevelRestricted {
+			// Added capabilities are kept, admission rejects the ones restricted does not allow
+			if container.SecurityContext.Capabilities == nil {
+				container.SecurityContext.Capabilities = &corev1.Capabilities{}
+			}
+			container.SecurityContext.Capabilities.Drop = []corev1.Capability{"ALL"}
+		}
+	}
+}
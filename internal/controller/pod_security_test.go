@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+func TestApplyPodSecurity(t *testing.T) {
+	g := NewWithT(t)
+	pod := func() *corev1.PodSpec {
+		return &corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "web:1"}}}
+	}
+
+	spec := pod()
+	applyPodSecurity(spec, "")
+	g.Expect(spec).To(Equal(pod()))
+
+	spec = pod()
+	applyPodSecurity(spec, platformv1alpha1.SecurityLevelBaseline)
+	g.Expect(spec.SecurityContext.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+	g.Expect(spec.SecurityContext.RunAsNonRoot).To(BeNil())
+	g.Expect(spec.Containers[0].SecurityContext.AllowPrivilegeEscalation).To(Equal(ptr.To(false)))
+	g.Expect(spec.Containers[0].SecurityContext.Capabilities).To(BeNil())
+
+	spec = pod()
+	applyPodSecurity(spec, platformv1alpha1.SecurityLevelRestricted)
+	g.Expect(spec.SecurityContext.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+	g.Expect(spec.SecurityContext.RunAsNonRoot).To(Equal(ptr.To(true)))
+	g.Expect(spec.Containers[0].SecurityContext.AllowPrivilegeEscalation).To(Equal(ptr.To(false)))
+	g.Expect(spec.Containers[0].SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+}
+
+func TestProjectNamespaceSecurityLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	project := &platformv1alpha1.Project{Spec: platformv1alpha1.ProjectSpec{
+		Namespace:     platformv1alpha1.NamespaceConfig{Labels: map[string]string{"cost-center": "billing"}},
+		SecurityLevel: platformv1alpha1.SecurityLevelRestricted,
+	}}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "project-1",
+		Labels: map[string]string{ManagedByLabel: ManagedByValue},
+	}}
+
+	g.Expect(applyProjectNamespaceMetadata(namespace, projectNamespaceConfig(project))).To(BeTrue())
+	g.Expect(namespace.Labels).To(Equal(map[string]string{
+		ManagedByLabel:                               ManagedByValue,
+		"cost-center":                                "billing",
+		"pod-security.kubernetes.io/enforce":         "restricted",
+		"pod-security.kubernetes.io/enforce-version": "latest",
+		"pod-security.kubernetes.io/audit":           "restricted",
+		"pod-security.kubernetes.io/audit-version":   "latest",
+		"pod-security.kubernetes.io/warn":            "restricted",
+		"pod-security.kubernetes.io/warn-version":    "latest",
+	}))
+	// The spec labels of the project are left untouched
+	g.Expect(project.Spec.Namespace.Labels).To(HaveLen(1))
+
+	// Clearing the level removes the pod security labels
+	project.Spec.SecurityLevel = ""
+	g.Expect(applyProjectNamespaceMetadata(namespace, projectNamespaceConfig(project))).To(BeTrue())
+	g.Expect(namespace.Labels).To(Equal(map[string]string{
+		ManagedByLabel: ManagedByValue,
+		"cost-center":  "billing",
+	}))
+}
+
+func TestApplyRuntimeSecurity(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "web:1"}}}
+	applyRuntimeSecurity(spec, &platformv1alpha1.RuntimeSecurityConfig{
+		RunAsUser:              ptr.To(int64(1000)),
+		FSGroup:                ptr.To(int64(2000)),
+		ReadOnlyRootFilesystem: true,
+		Capabilities:           &platformv1alpha1.RuntimeCapabilities{Add: []string{"NET_BIND_SERVICE"}},
+	})
+	applyPodSecurity(spec, platformv1alpha1.SecurityLevelRestricted)
+
+	g.Expect(spec.SecurityContext.FSGroup).To(Equal(ptr.To(int64(2000))))
+	g.Expect(spec.Volumes).To(ConsistOf(corev1.Volume{
+		Name:         runtimeTmpVolume,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}))
+
+	container := spec.Containers[0]
+	g.Expect(container.SecurityContext.RunAsUser).To(Equal(ptr.To(int64(1000))))
+	g.Expect(container.SecurityContext.ReadOnlyRootFilesystem).To(Equal(ptr.To(true)))
+	g.Expect(container.VolumeMounts).To(ConsistOf(corev1.VolumeMount{Name: runtimeTmpVolume, MountPath: "/tmp"}))
+	// The project level drops every capability but the ones the application adds back
+	g.Expect(container.SecurityContext.Capabilities.Add).To(ConsistOf(corev1.Capability("NET_BIND_SERVICE")))
+	g.Expect(container.SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+}
+
+func TestValidateRuntimeSecurity(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(platformv1alpha1.ValidateRuntimeSecurity(platformv1alpha1.RuntimeSecurityConfig{})).To(Succeed())
+	g.Expect(platformv1alpha1.ValidateRuntimeSecurity(platformv1alpha1.RuntimeSecurityConfig{
+		Capabilities: &platformv1alpha1.RuntimeCapabilities{Add: []string{"NET_BIND_SERVICE"}, Drop: []string{"ALL"}},
+	})).To(Succeed())
+	g.Expect(platformv1alpha1.ValidateRuntimeSecurity(platformv1alpha1.RuntimeSecurityConfig{
+		Capabilities: &platformv1alpha1.RuntimeCapabilities{Add: []string{"SYS_ADMIN"}},
+	})).NotTo(Succeed())
+	g.Expect(platformv1alpha1.ValidateRuntimeSecurity(platformv1alpha1.RuntimeSecurityConfig{
+		Capabilities: &platformv1alpha1.RuntimeCapabilities{Add: []string{"CHOWN"}, Drop: []string{"CHOWN"}},
+	})).NotTo(Succeed())
+}
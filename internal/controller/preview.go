@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/pullrequest"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// commentPreviewURL posts the URL of a preview application on its pull request once a
+// deployment succeeds. Preview domains are created asynchronously, so the API server that
+// creates the preview cannot report them. Failures are logged and never block the deployment.
+func (r *DeploymentProgressController) commentPreviewURL(
+	ctx context.Context,
+	deployment *platformv1alpha1.Deployment,
+	app *platformv1alpha1.Application,
+) {
+	log := logf.FromContext(ctx).WithValues("application", app.Name)
+
+	if r.PullRequests == nil || app.Labels[validation.LabelPullRequest] == "" {
+		return
+	}
+	repo := app.Spec.GitRepository
+	if repo == nil || repo.SecretRef == nil || string(repo.Provider) != pullrequest.ProviderGitHub {
+		return
+	}
+
+	var env platformv1alpha1.Environment
+	if err := r.Get(ctx, client.ObjectKey{Name: app.Spec.EnvironmentRef.Name, Namespace: app.Namespace}, &env); err != nil {
+		log.Error(err, "Failed to get preview environment")
+		return
+	}
+	number, err := strconv.Atoi(env.Annotations[validation.AnnotationPullRequestNumber])
+	if err != nil {
+		log.Info("Preview environment has no pull request number", "environment", env.Name)
+		return
+	}
+
+	var domains platformv1alpha1.ApplicationDomainList
+	if err := r.List(ctx, &domains, client.InNamespace(app.Namespace), client.MatchingLabels{
+		validation.LabelApplicationUUID:  app.Labels[validation.LabelResourceUUID],
+		ApplicationDomainLabelDomainType: DefaultDomainType,
+	}); err != nil {
+		log.Error(err, "Failed to list preview domains")
+		return
+	}
+	if len(domains.Items) == 0 {
+		return
+	}
+
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Name: repo.SecretRef.Name, Namespace: deployment.Namespace}, &secret); err != nil {
+		log.Error(err, "Failed to get git access token")
+		return
+	}
+	token := string(secret.Data["token"])
+	if token == "" {
+		return
+	}
+
+	body := fmt.Sprintf("Preview of %s is live at https://%s", app.Name, domains.Items[0].Spec.Domain)
+	if deployment.Spec.GitRepository != nil && deployment.Spec.GitRepository.CommitSHA != "" {
+		sha := deployment.Spec.GitRepository.CommitSHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		body += fmt.Sprintf(" (commit %s)", sha)
+	}
+
+	if err := r.PullRequests.Comment(ctx, token, repo.Repository, number, body); err != nil {
+		log.Error(err, "Failed to comment preview URL on pull request")
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ResolvePriorityClasses returns the PriorityClasses of the build and application pods of the
+// project identified by projectUUID. The Project spec.priority wins over the classes provisioned
+// by the operator, so a burst of builds cannot evict the running applications.
+func ResolvePriorityClasses(ctx context.Context, c client.Reader, projectUUID string) (build, runtime string, err error) {
+	build, runtime = config.PriorityClassBuild, config.PriorityClassRuntime
+
+	if projectUUID == "" {
+		return build, runtime, nil
+	}
+
+	var projects platformv1alpha1.ProjectList
+	if err := c.List(ctx, &projects, client.MatchingLabels{validation.LabelResourceUUID: projectUUID}); err != nil {
+		return "", "", fmt.Errorf("failed to list projects: %w", err)
+	}
+	if len(projects.Items) == 0 {
+		return build, runtime, nil
+	}
+
+	priority := projects.Items[0].Spec.Priority
+	if priority.BuildPriorityClassName != "" {
+		build = priority.BuildPriorityClassName
+	}
+	if priority.RuntimePriorityClassName != "" {
+		runtime = priority.RuntimePriorityClassName
+	}
+	return build, runtime, nil
+}
@@ -27,10 +27,16 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
 	"github.com/kibamail/kibaship/pkg/utils"
@@ -50,6 +56,8 @@ type ProjectReconciler struct {
 	NamespaceManager *NamespaceManager
 	Validator        *ProjectValidator
 	Notifier         webhooks.Notifier
+	// Recorder emits the Events of projects, nil emits none
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=projects,verbs=get;list;watch;create;update;patch;delete
@@ -103,7 +111,7 @@ func (r *ProjectReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	// Validate project labels (always check these)
 	if err := r.Validator.ValidateRequiredLabels(&project); err != nil {
 		log.Error(err, "Project label validation failed")
-		r.updateStatusWithError(ctx, &project, err.Error())
+		r.updateStatusWithError(ctx, &project, eventReasonValidationFailed, err.Error())
 		return ctrl.Result{}, err
 	}
 
@@ -130,7 +138,7 @@ func (r *ProjectReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		// Validate uniqueness for new projects (exclude this project)
 		if err := r.Validator.CheckProjectNameUniqueness(ctx, project.Name, &project); err != nil {
 			log.Error(err, "Project name uniqueness validation failed")
-			r.updateStatusWithError(ctx, &project, err.Error())
+			r.updateStatusWithError(ctx, &project, eventReasonValidationFailed, err.Error())
 			return ctrl.Result{}, err
 		}
 	}
@@ -139,35 +147,49 @@ func (r *ProjectReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	namespace, err := r.NamespaceManager.CreateProjectNamespace(ctx, &project)
 	if err != nil {
 		log.Error(err, "Failed to create project namespace")
-		r.updateStatusWithError(ctx, &project, err.Error())
+		r.updateStatusWithError(ctx, &project, eventReasonReconcileFailed, err.Error())
 		return ctrl.Result{}, err
 	}
 
 	// Ensure registry credentials are created for this namespace
 	if err := r.ensureRegistryCredentials(ctx, namespace.Name); err != nil {
 		log.Error(err, "Failed to ensure registry credentials")
-		r.updateStatusWithError(ctx, &project, fmt.Sprintf("Failed to create registry credentials: %v", err))
+		r.updateStatusWithError(ctx, &project, eventReasonReconcileFailed, fmt.Sprintf("Failed to create registry credentials: %v", err))
 		return ctrl.Result{}, err
 	}
 
 	// Ensure registry CA certificate is copied to this namespace
 	if err := r.ensureRegistryCACertificate(ctx, namespace.Name); err != nil {
 		log.Error(err, "Failed to ensure registry CA certificate")
-		r.updateStatusWithError(ctx, &project, fmt.Sprintf("Failed to copy registry CA certificate: %v", err))
+		r.updateStatusWithError(ctx, &project, eventReasonReconcileFailed, fmt.Sprintf("Failed to copy registry CA certificate: %v", err))
 		return ctrl.Result{}, err
 	}
 
 	// Ensure Docker config secret is created for registry authentication
 	if err := r.ensureRegistryDockerConfig(ctx, namespace.Name); err != nil {
 		log.Error(err, "Failed to ensure registry Docker config")
-		r.updateStatusWithError(ctx, &project, fmt.Sprintf("Failed to create registry Docker config: %v", err))
+		r.updateStatusWithError(ctx, &project, eventReasonReconcileFailed, fmt.Sprintf("Failed to create registry Docker config: %v", err))
 		return ctrl.Result{}, err
 	}
 
 	// Ensure default production environment exists
 	if err := r.ensureDefaultEnvironment(ctx, &project, namespace.Name); err != nil {
 		log.Error(err, "Failed to create default environment")
-		r.updateStatusWithError(ctx, &project, fmt.Sprintf("Failed to create default environment: %v", err))
+		r.updateStatusWithError(ctx, &project, eventReasonReconcileFailed, fmt.Sprintf("Failed to create default environment: %v", err))
+		return ctrl.Result{}, err
+	}
+
+	// Serve the project error pages, or remove them once disabled
+	if err := r.reconcileErrorPages(ctx, &project); err != nil {
+		log.Error(err, "Failed to reconcile error pages")
+		r.updateStatusWithError(ctx, &project, eventReasonReconcileFailed, fmt.Sprintf("Failed to reconcile error pages: %v", err))
+		return ctrl.Result{}, err
+	}
+
+	// Restrict the egress of the project applications, or lift the restriction
+	if err := r.reconcileEgressPolicy(ctx, &project); err != nil {
+		log.Error(err, "Failed to reconcile egress policy")
+		r.updateStatusWithError(ctx, &project, eventReasonReconcileFailed, fmt.Sprintf("Failed to reconcile egress policy: %v", err))
 		return ctrl.Result{}, err
 	}
 
@@ -185,6 +207,7 @@ func (r *ProjectReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		}
 		// emit webhook for phase change
 		r.emitProjectPhaseChange(ctx, &project, prevPhase, project.Status.Phase)
+		recordEvent(r.Recorder, &project, corev1.EventTypeNormal, eventReasonReady, "Project is ready in namespace %s", namespace.Name)
 	}
 
 	log.Info("Successfully reconciled Project",
@@ -200,6 +223,7 @@ func (r *ProjectReconciler) handleProjectDeletion(ctx context.Context, project *
 	log := logf.FromContext(ctx)
 
 	log.Info("Handling project deletion", "project", project.Name)
+	recordEvent(r.Recorder, project, corev1.EventTypeNormal, eventReasonDeleting, "Deleting project namespace")
 
 	// Delete the project namespace (ignore NotFound errors for idempotency)
 	if err := r.NamespaceManager.DeleteProjectNamespace(ctx, project); err != nil {
@@ -221,8 +245,10 @@ func (r *ProjectReconciler) handleProjectDeletion(ctx context.Context, project *
 	return ctrl.Result{}, nil
 }
 
-// updateStatusWithError updates the project status with error information
-func (r *ProjectReconciler) updateStatusWithError(ctx context.Context, project *platformv1alpha1.Project, message string) {
+// updateStatusWithError updates the project status with error information and emits it as a
+// Warning Event with reason
+func (r *ProjectReconciler) updateStatusWithError(ctx context.Context, project *platformv1alpha1.Project, reason, message string) {
+	recordEvent(r.Recorder, project, corev1.EventTypeWarning, reason, "%s", message)
 	project.Status.Phase = "Failed"
 	project.Status.Message = message
 	now := metav1.Now()
@@ -547,6 +573,7 @@ func (r *ProjectReconciler) ensureDefaultEnvironment(ctx context.Context, projec
 	}
 
 	log.Info("Created default production environment", "environment", productionEnvName)
+	recordEvent(r.Recorder, project, corev1.EventTypeNormal, eventReasonEnvironmentCreated, "Created default production environment %s", productionEnvName)
 	return nil
 }
 
@@ -579,10 +606,29 @@ func NewProjectReconciler(k8sClient client.Client, scheme *runtime.Scheme) *Proj
 	}
 }
 
+// namespaceToProject maps a project namespace to its Project
+func namespaceToProject(_ context.Context, obj client.Object) []reconcile.Request {
+	name := obj.GetLabels()[ProjectNameLabel]
+	if name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ProjectReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	projectNamespaces := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		namespace, ok := obj.(*corev1.Namespace)
+		return ok && isProjectNamespace(namespace)
+	})
+
+	// Namespace label and annotation edits are reverted to the project spec
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&platformv1alpha1.Project{}).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(namespaceToProject),
+			builder.WithPredicates(projectNamespaces, predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		WithOptions(controllerOptions("project", 1)).
 		Named("project").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
 		Complete(r)
 }
@@ -0,0 +1,220 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/cost"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	// DefaultCostInterval is how often project cost estimates are recalculated
+	DefaultCostInterval = time.Hour
+
+	// maxCostAccrualGap caps the time accrued in one calculation so a stopped operator
+	// does not bill the current requests for the whole time it was down
+	maxCostAccrualGap = 2 * time.Hour
+)
+
+// ProjectCostReconciler aggregates the resources requested by each project's pods and
+// persistent volume claims into a cost estimate on the Project status
+type ProjectCostReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Interval is how often costs are recalculated, DefaultCostInterval when zero
+	Interval time.Duration
+}
+
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=projects,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=projects/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=pods;persistentvolumeclaims,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// Reconcile recalculates the cost estimate of a project and accrues it into the daily history
+func (r *ProjectCostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var project platformv1alpha1.Project
+	if err := r.Get(ctx, req.NamespacedName, &project); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !project.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	projectUUID := project.GetUUID()
+	if projectUUID == "" {
+		return ctrl.Result{}, nil
+	}
+
+	rates, err := r.costRates(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	total, perApplication, err := r.projectUsage(ctx, projectUUID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	now := time.Now()
+	hourly := cost.Hourly(rates, total)
+
+	next := &platformv1alpha1.ProjectCost{
+		Currency:           rates.Currency,
+		CPU:                total.CPU.String(),
+		Memory:             total.Memory.String(),
+		Storage:            total.Storage.String(),
+		HourlyCost:         cost.FormatAmount(hourly),
+		LastCalculatedTime: &metav1.Time{Time: now},
+	}
+
+	applicationUUIDs := make([]string, 0, len(perApplication))
+	for applicationUUID := range perApplication {
+		applicationUUIDs = append(applicationUUIDs, applicationUUID)
+	}
+	sort.Strings(applicationUUIDs)
+	for _, applicationUUID := range applicationUUIDs {
+		usage := perApplication[applicationUUID]
+		next.Applications = append(next.Applications, platformv1alpha1.ApplicationCost{
+			ApplicationUUID: applicationUUID,
+			CPU:             usage.CPU.String(),
+			Memory:          usage.Memory.String(),
+			Storage:         usage.Storage.String(),
+			HourlyCost:      cost.FormatAmount(cost.Hourly(rates, usage)),
+		})
+	}
+
+	// Accrue the previous estimate over the time since it was calculated
+	if previous := project.Status.Cost; previous != nil {
+		next.History = previous.History
+		if previous.LastCalculatedTime != nil && previous.Currency == next.Currency {
+			from := previous.LastCalculatedTime.Time
+			if gap := now.Sub(from); gap > maxCostAccrualGap {
+				from = now.Add(-maxCostAccrualGap)
+			}
+			next.History = cost.Accrue(previous.History, cost.ParseAmount(previous.HourlyCost), from, now)
+		}
+	}
+
+	patch := client.MergeFrom(project.DeepCopy())
+	project.Status.Cost = next
+	if err := r.Status().Patch(ctx, &project, patch); err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to update project cost: %w", err)
+	}
+
+	log.V(1).Info("Updated project cost estimate", "project", project.Name, "hourlyCost", next.HourlyCost, "currency", next.Currency)
+
+	return ctrl.Result{RequeueAfter: r.interval()}, nil
+}
+
+// projectUsage sums the requests of the project's running pods and its persistent volume claims,
+// in total and per application
+func (r *ProjectCostReconciler) projectUsage(ctx context.Context, projectUUID string) (cost.Usage, map[string]cost.Usage, error) {
+	var total cost.Usage
+	perApplication := map[string]cost.Usage{}
+
+	add := func(labels map[string]string, usage cost.Usage) {
+		total.Add(usage)
+		if applicationUUID := labels[validation.LabelApplicationUUID]; applicationUUID != "" {
+			app := perApplication[applicationUUID]
+			app.Add(usage)
+			perApplication[applicationUUID] = app
+		}
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.MatchingLabels{validation.LabelProjectUUID: projectUUID}); err != nil {
+		return total, nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		// Finished pods no longer reserve resources
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		add(pod.Labels, cost.PodUsage(pod))
+	}
+
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &pvcs, client.MatchingLabels{validation.LabelProjectUUID: projectUUID}); err != nil {
+		return total, nil, fmt.Errorf("failed to list persistent volume claims: %w", err)
+	}
+	for i := range pvcs.Items {
+		add(pvcs.Items[i].Labels, cost.PVCUsage(&pvcs.Items[i]))
+	}
+
+	return total, perApplication, nil
+}
+
+// costRates reads the cost rates from the operator ConfigMap on every calculation so rate
+// changes apply without a restart
+func (r *ProjectCostReconciler) costRates(ctx context.Context) (config.CostConfig, error) {
+	var cm corev1.ConfigMap
+	err := r.Get(ctx, client.ObjectKey{Namespace: config.OperatorNamespace, Name: config.OperatorConfigMapName}, &cm)
+	if apierrors.IsNotFound(err) {
+		return config.ParseCostConfig(nil)
+	}
+	if err != nil {
+		return config.CostConfig{}, fmt.Errorf("failed to get operator ConfigMap: %w", err)
+	}
+
+	rates, err := config.ParseCostConfig(cm.Data)
+	if err != nil {
+		return config.CostConfig{}, fmt.Errorf("ConfigMap %s/%s: %w", config.OperatorNamespace, config.OperatorConfigMapName, err)
+	}
+	return rates, nil
+}
+
+func (r *ProjectCostReconciler) interval() time.Duration {
+	if r.Interval > 0 {
+		return r.Interval
+	}
+	return DefaultCostInterval
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Status updates are ignored, costs are recalculated on spec changes and every Interval.
+func (r *ProjectCostReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&platformv1alpha1.Project{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controllerOptions("project-cost", 1)).
+		Named("project-cost").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
+		Complete(r)
+}
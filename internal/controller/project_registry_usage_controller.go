@@ -0,0 +1,219 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/registryusage"
+)
+
+const (
+	// DefaultRegistryUsageInterval is how often the registry storage of projects is measured
+	DefaultRegistryUsageInterval = 6 * time.Hour
+
+	// registryCACertSecret holds the registry CA certificate of a project namespace
+	registryCACertSecret = "registry-ca-cert"
+)
+
+// RegistryInspector measures the registry storage of repositories, implemented by registryusage.Client
+type RegistryInspector interface {
+	Inspect(ctx context.Context, creds registryusage.Credentials, repositories []string) (*registryusage.Usage, error)
+}
+
+// ProjectRegistryUsageReconciler measures the registry storage used by the images of each
+// project's applications and records it on the Project status, where the registry quota of the
+// project is enforced from
+type ProjectRegistryUsageReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Registry inspects the project repositories, the in-cluster registry when nil
+	Registry RegistryInspector
+
+	// Interval is how often usage is measured, DefaultRegistryUsageInterval when zero
+	Interval time.Duration
+
+	// Clock stamps inspections, the real clock when nil
+	Clock clock.PassiveClock
+}
+
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=projects,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=projects/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile measures the registry storage of a project and schedules the next measurement
+func (r *ProjectRegistryUsageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var project platformv1alpha1.Project
+	if err := r.Get(ctx, req.NamespacedName, &project); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !project.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	namespace := project.Status.NamespaceName
+	if namespace == "" {
+		return ctrl.Result{RequeueAfter: r.interval()}, nil
+	}
+
+	// Wait for the interval since the last inspection when reconciled early, e.g. after a quota change
+	now := currentTime(r.Clock)
+	previous := project.Status.RegistryUsage
+	if previous != nil && previous.LastInspectedTime != nil && previous.Error == "" {
+		if wait := previous.LastInspectedTime.Add(r.interval()).Sub(now); wait > time.Second {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+	}
+
+	next, err := r.inspect(ctx, namespace)
+	if err != nil {
+		log.Error(err, "Failed to measure project registry usage", "project", project.Name)
+		next = &platformv1alpha1.ProjectRegistryUsage{}
+		if previous != nil {
+			next = previous.DeepCopy()
+		}
+		next.Error = err.Error()
+	} else {
+		next.LastInspectedTime = &metav1.Time{Time: now}
+	}
+
+	patch := client.MergeFrom(project.DeepCopy())
+	project.Status.RegistryUsage = next
+	if err := r.Status().Patch(ctx, &project, patch); err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to update project registry usage: %w", err)
+	}
+
+	log.V(1).Info("Updated project registry usage", "project", project.Name, "bytes", next.Bytes,
+		"quota", project.Spec.RegistryQuota, "quotaExceeded", project.RegistryQuotaExceeded())
+
+	return ctrl.Result{RequeueAfter: r.interval()}, nil
+}
+
+// inspect measures the repositories of the applications built in namespace
+func (r *ProjectRegistryUsageReconciler) inspect(ctx context.Context, namespace string) (*platformv1alpha1.ProjectRegistryUsage, error) {
+	var apps platformv1alpha1.ApplicationList
+	if err := r.List(ctx, &apps, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	applications := map[string]string{}
+	var repositories []string
+	for i := range apps.Items {
+		app := &apps.Items[i]
+		if app.Spec.Type != platformv1alpha1.ApplicationTypeGitRepository || app.GetUUID() == "" {
+			continue
+		}
+		repository := fmt.Sprintf("%s/%s", namespace, app.GetUUID())
+		applications[repository] = app.GetUUID()
+		repositories = append(repositories, repository)
+	}
+	sort.Strings(repositories)
+
+	usage := &platformv1alpha1.ProjectRegistryUsage{}
+	if len(repositories) == 0 {
+		return usage, nil
+	}
+
+	creds, err := r.registryCredentials(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	measured, err := r.registry().Inspect(ctx, creds, repositories)
+	if err != nil {
+		return nil, err
+	}
+
+	usage.Bytes = measured.Bytes
+	for _, repository := range measured.Repositories {
+		usage.Repositories = append(usage.Repositories, platformv1alpha1.RepositoryUsage{
+			Repository:      repository.Repository,
+			ApplicationUUID: applications[repository.Repository],
+			Tags:            int32(repository.Tags),
+			Bytes:           repository.Bytes,
+		})
+	}
+	return usage, nil
+}
+
+// registryCredentials reads the registry credentials and CA certificate the project builds push with
+func (r *ProjectRegistryUsageReconciler) registryCredentials(ctx context.Context, namespace string) (registryusage.Credentials, error) {
+	var credentials corev1.Secret
+	name := fmt.Sprintf("%s-registry-credentials", namespace)
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &credentials); err != nil {
+		return registryusage.Credentials{}, fmt.Errorf("failed to get registry credentials: %w", err)
+	}
+
+	creds := registryusage.Credentials{
+		Username: string(credentials.Data["username"]),
+		Password: string(credentials.Data["password"]),
+	}
+
+	var ca corev1.Secret
+	err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: registryCACertSecret}, &ca)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return registryusage.Credentials{}, fmt.Errorf("failed to get registry CA certificate: %w", err)
+	}
+	creds.CACert = ca.Data["ca.crt"]
+	return creds, nil
+}
+
+func (r *ProjectRegistryUsageReconciler) registry() RegistryInspector {
+	if r.Registry != nil {
+		return r.Registry
+	}
+	return registryusage.NewClient("https://" + inClusterRegistry)
+}
+
+func (r *ProjectRegistryUsageReconciler) interval() time.Duration {
+	if r.Interval > 0 {
+		return r.Interval
+	}
+	return DefaultRegistryUsageInterval
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Status updates are ignored, usage is measured on spec changes and every Interval.
+func (r *ProjectRegistryUsageReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&platformv1alpha1.Project{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controllerOptions("project-registry-usage", 1)).
+		Named("project-registry-usage").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
+		Complete(r)
+}
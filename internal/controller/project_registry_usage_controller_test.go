@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/registryusage"
+	"github.com/kibamail/kibaship/pkg/validation"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// fakeRegistryInspector reports bytes per repository, or err, and records the inspections
+type fakeRegistryInspector struct {
+	bytes        map[string]int64
+	err          error
+	creds        registryusage.Credentials
+	repositories []string
+}
+
+func (f *fakeRegistryInspector) Inspect(_ context.Context, creds registryusage.Credentials, repositories []string) (*registryusage.Usage, error) {
+	f.creds = creds
+	f.repositories = repositories
+	if f.err != nil {
+		return nil, f.err
+	}
+	usage := &registryusage.Usage{}
+	for _, repository := range repositories {
+		usage.Bytes += f.bytes[repository]
+		usage.Repositories = append(usage.Repositories, registryusage.RepositoryUsage{
+			Repository: repository, Tags: 1, Bytes: f.bytes[repository],
+		})
+	}
+	return usage, nil
+}
+
+func TestProjectRegistryUsageReconciler(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC)
+
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+	project := &platformv1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "project-1", Labels: map[string]string{validation.LabelResourceUUID: "project-1"}},
+		Spec:       platformv1alpha1.ProjectSpec{RegistryQuota: "1Gi"},
+		Status:     platformv1alpha1.ProjectStatus{NamespaceName: "project-ns"},
+	}
+	application := func(uuid string, appType platformv1alpha1.ApplicationType) *platformv1alpha1.Application {
+		return &platformv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "application-" + uuid,
+				Namespace: "project-ns",
+				Labels:    map[string]string{validation.LabelResourceUUID: uuid},
+			},
+			Spec: platformv1alpha1.ApplicationSpec{Type: appType},
+		}
+	}
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "project-ns-registry-credentials", Namespace: "project-ns"},
+		Data:       map[string][]byte{"username": []byte("project-ns"), "password": []byte("secret")},
+	}
+	ca := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-ca-cert", Namespace: "project-ns"},
+		Data:       map[string][]byte{"ca.crt": []byte("ca")},
+	}
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(project, credentials, ca,
+			application("web", platformv1alpha1.ApplicationTypeGitRepository),
+			application("api", platformv1alpha1.ApplicationTypeGitRepository),
+			application("db", platformv1alpha1.ApplicationTypePostgres)).
+		WithStatusSubresource(project).
+		Build()
+	inspector := &fakeRegistryInspector{bytes: map[string]int64{"project-ns/web": 600 << 20, "project-ns/api": 300 << 20}}
+	clk := clocktesting.NewFakeClock(now)
+	r := &ProjectRegistryUsageReconciler{Client: cl, Scheme: scheme, Registry: inspector, Clock: clk}
+
+	reconcileProject := func() (reconcile.Result, *platformv1alpha1.Project) {
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(project)})
+		g.Expect(err).NotTo(HaveOccurred())
+		updated := &platformv1alpha1.Project{}
+		g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(project), updated)).To(Succeed())
+		return result, updated
+	}
+
+	// Only the repositories of built applications are inspected, with the project credentials
+	result, updated := reconcileProject()
+	g.Expect(result.RequeueAfter).To(Equal(DefaultRegistryUsageInterval))
+	g.Expect(inspector.repositories).To(Equal([]string{"project-ns/api", "project-ns/web"}))
+	g.Expect(inspector.creds).To(Equal(registryusage.Credentials{Username: "project-ns", Password: "secret", CACert: []byte("ca")}))
+	usage := updated.Status.RegistryUsage
+	g.Expect(usage.Bytes).To(Equal(int64(900 << 20)))
+	g.Expect(usage.LastInspectedTime.Time).To(BeTemporally("==", now))
+	g.Expect(usage.Repositories).To(Equal([]platformv1alpha1.RepositoryUsage{
+		{Repository: "project-ns/api", ApplicationUUID: "api", Tags: 1, Bytes: 300 << 20},
+		{Repository: "project-ns/web", ApplicationUUID: "web", Tags: 1, Bytes: 600 << 20},
+	}))
+	g.Expect(updated.RegistryQuotaExceeded()).To(BeFalse())
+
+	// Reconciles before the interval wait for the next inspection
+	clk.Step(time.Hour)
+	inspector.bytes["project-ns/web"] = 800 << 20
+	result, updated = reconcileProject()
+	g.Expect(result.RequeueAfter).To(Equal(DefaultRegistryUsageInterval - time.Hour))
+	g.Expect(updated.Status.RegistryUsage.Bytes).To(Equal(int64(900 << 20)))
+
+	// The next inspection goes over the quota
+	clk.Step(DefaultRegistryUsageInterval)
+	_, updated = reconcileProject()
+	g.Expect(updated.Status.RegistryUsage.Bytes).To(Equal(int64(1100 << 20)))
+	g.Expect(updated.RegistryQuotaExceeded()).To(BeTrue())
+	g.Expect(updated.RegistryQuotaMessage()).To(Equal("the project images use 1.1Gi of registry storage, above its 1Gi quota; " +
+		"delete unused applications or raise the project registryQuota to build again"))
+
+	// A failed inspection keeps the last usage
+	clk.Step(DefaultRegistryUsageInterval)
+	inspector.err = errors.New("registry returned 503: unavailable")
+	_, updated = reconcileProject()
+	g.Expect(updated.Status.RegistryUsage.Bytes).To(Equal(int64(1100 << 20)))
+	g.Expect(updated.Status.RegistryUsage.Error).To(Equal("registry returned 503: unavailable"))
+	g.Expect(updated.Status.RegistryUsage.LastInspectedTime.Time).To(BeTemporally("==", now.Add(time.Hour+DefaultRegistryUsageInterval)))
+}
+
+func TestCheckRegistryQuota(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(tektonv1.AddToScheme(scheme)).To(Succeed())
+
+	project := &platformv1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "project-1", Labels: map[string]string{validation.LabelResourceUUID: "project-1"}},
+		Spec:       platformv1alpha1.ProjectSpec{RegistryQuota: "1Gi"},
+		Status: platformv1alpha1.ProjectStatus{
+			RegistryUsage: &platformv1alpha1.ProjectRegistryUsage{Bytes: 512 << 20},
+		},
+	}
+	deployment := &platformv1alpha1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "deployment-1",
+			Namespace:  "project-ns",
+			Generation: 1,
+			Labels: map[string]string{
+				validation.LabelResourceUUID: "deployment-1",
+				validation.LabelProjectUUID:  "project-1",
+			},
+		},
+		Spec: platformv1alpha1.DeploymentSpec{
+			ApplicationRef: corev1.LocalObjectReference{Name: "application-web"},
+			GitRepository:  &platformv1alpha1.GitRepositoryDeploymentConfig{CommitSHA: "abc123"},
+		},
+	}
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(project, deployment).
+		WithStatusSubresource(&platformv1alpha1.Deployment{}, &platformv1alpha1.Project{}).
+		Build()
+	r := &DeploymentReconciler{Client: cl, Scheme: scheme}
+
+	check := func() (bool, *platformv1alpha1.Deployment) {
+		current := &platformv1alpha1.Deployment{}
+		g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(deployment), current)).To(Succeed())
+		blocked, err := r.checkRegistryQuota(ctx, current)
+		g.Expect(err).NotTo(HaveOccurred())
+		return blocked, current
+	}
+
+	// Below the quota the build goes ahead
+	blocked, current := check()
+	g.Expect(blocked).To(BeFalse())
+	g.Expect(current.Status.Phase).To(BeEmpty())
+
+	// Over the quota the build fails with the usage and the quota
+	project.Status.RegistryUsage.Bytes = 2 << 30
+	g.Expect(cl.Status().Update(ctx, project)).To(Succeed())
+	blocked, current = check()
+	g.Expect(blocked).To(BeTrue())
+	g.Expect(current.Status.Phase).To(Equal(platformv1alpha1.DeploymentPhaseFailed))
+	condition := meta.FindStatusCondition(current.Status.Conditions, "PipelineRunReady")
+	g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(condition.Reason).To(Equal(DeploymentReasonRegistryQuotaExceeded))
+	g.Expect(condition.Message).To(ContainSubstring("use 2.0Gi of registry storage, above its 1Gi quota"))
+
+	// The deployment stays failed once the usage drops
+	project.Status.RegistryUsage.Bytes = 0
+	g.Expect(cl.Status().Update(ctx, project)).To(Succeed())
+	blocked, _ = check()
+	g.Expect(blocked).To(BeTrue())
+}
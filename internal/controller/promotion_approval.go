@@ -0,0 +1,46 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// environmentRequiresApproval reports whether the deployment's environment holds successful
+// deployments back until they are promoted explicitly
+func environmentRequiresApproval(ctx context.Context, c client.Reader, deployment *platformv1alpha1.Deployment) (bool, error) {
+	environmentUUID := deployment.Labels[validation.LabelEnvironmentUUID]
+	if environmentUUID == "" {
+		return false, nil
+	}
+
+	var environments platformv1alpha1.EnvironmentList
+	if err := c.List(ctx, &environments, client.MatchingLabels{validation.LabelResourceUUID: environmentUUID}); err != nil {
+		return false, fmt.Errorf("failed to list environments: %w", err)
+	}
+	if len(environments.Items) == 0 {
+		return false, nil
+	}
+
+	return environments.Items[0].Spec.RequireApproval, nil
+}
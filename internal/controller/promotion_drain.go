@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/utils"
+)
+
+const (
+	// annotationPromotingDeployment records the deployment a Service alias switches to while it
+	// selects the pods of every deployment of its application
+	annotationPromotingDeployment = "platform.kibaship.com/promoting-deployment"
+
+	// annotationDrainStartedAt records when the promoted deployment became ready, the previous
+	// one is removed from the Service alias a drain period later
+	annotationDrainStartedAt = "platform.kibaship.com/drain-started-at"
+
+	// promotionReadinessInterval is how often a promotion checks the readiness of its deployment
+	promotionReadinessInterval = 5 * time.Second
+
+	deploymentUUIDSelector = "platform.kibaship.com/deployment-uuid"
+)
+
+// drainPeriod holds the drain period of the operator configuration
+var drainPeriod atomic.Int64
+
+func init() {
+	drainPeriod.Store(int64(config.DefaultDrainPeriod))
+}
+
+// SetDrainPeriod replaces how long the previous deployment of an application keeps serving once
+// a promoted deployment is ready. It is called at startup and whenever the operator ConfigMap
+// changes.
+func SetDrainPeriod(period time.Duration) {
+	drainPeriod.Store(int64(period))
+}
+
+// promotionStep is the state of the Service alias of an application during a promotion
+type promotionStep struct {
+	// Selector is the pod selector of the Service alias
+	Selector map[string]string
+	// Annotations are the promotion annotations of the Service alias, nil once it is done
+	Annotations map[string]string
+	// RequeueAfter is when the promotion moves on, zero once it is done
+	RequeueAfter time.Duration
+}
+
+// nextPromotionStep moves the Service alias of an application towards its promoted deployment.
+// A promotion first adds the pods of the promoted deployment to the alias by selecting every pod
+// of the application, waits for the promoted deployment to be ready, then keeps the pods of the
+// previous deployment for the drain period so in-flight requests complete before they are
+// removed. The first deployment, and aliases already on the promoted deployment, are not drained.
+func (r *ServiceDiscoveryReconciler) nextPromotionStep(ctx context.Context, app *platformv1alpha1.Application, current string, alias *corev1.Service) (promotionStep, error) {
+	applicationSelector := map[string]string{"app.kubernetes.io/name": fmt.Sprintf("app-%s", app.GetUUID())}
+	done := promotionStep{Selector: map[string]string{
+		"app.kubernetes.io/name": applicationSelector["app.kubernetes.io/name"],
+		deploymentUUIDSelector:   current,
+	}}
+	if alias == nil {
+		return done, nil
+	}
+
+	served := alias.Spec.Selector[deploymentUUIDSelector]
+	promoting := alias.Annotations[annotationPromotingDeployment]
+	switch {
+	case served == current:
+		return done, nil
+	case served != "":
+		// Start serving the promoted deployment alongside the previous one
+		return promotionStep{
+			Selector:     applicationSelector,
+			Annotations:  map[string]string{annotationPromotingDeployment: current},
+			RequeueAfter: promotionReadinessInterval,
+		}, nil
+	case promoting == "":
+		return done, nil
+	}
+
+	now := currentTime(r.Clock)
+	drainStartedAt, err := time.Parse(time.RFC3339, alias.Annotations[annotationDrainStartedAt])
+	if promoting != current || err != nil {
+		// Promoted again, or the promoted deployment was not ready yet
+		ready, err := r.deploymentReady(ctx, app.Namespace, current)
+		if err != nil {
+			return promotionStep{}, err
+		}
+		if !ready {
+			return promotionStep{
+				Selector:     applicationSelector,
+				Annotations:  map[string]string{annotationPromotingDeployment: current},
+				RequeueAfter: promotionReadinessInterval,
+			}, nil
+		}
+		drainStartedAt = now
+	}
+
+	remaining := drainStartedAt.Add(time.Duration(drainPeriod.Load())).Sub(now)
+	if remaining <= 0 {
+		return done, nil
+	}
+	return promotionStep{
+		Selector: applicationSelector,
+		Annotations: map[string]string{
+			annotationPromotingDeployment: current,
+			annotationDrainStartedAt:      drainStartedAt.UTC().Format(time.RFC3339),
+		},
+		RequeueAfter: remaining,
+	}, nil
+}
+
+// deploymentReady reports whether the Kubernetes Deployment of a deployment runs all its replicas
+// ready. Deployments scaled to zero have nothing to wait for.
+func (r *ServiceDiscoveryReconciler) deploymentReady(ctx context.Context, namespace, deploymentUUID string) (bool, error) {
+	var dep appsv1.Deployment
+	key := client.ObjectKey{Name: utils.GetKubernetesDeploymentName(deploymentUUID), Namespace: namespace}
+	if err := r.Get(ctx, key, &dep); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get Kubernetes Deployment of %s: %w", deploymentUUID, err)
+	}
+
+	replicas := int32(1)
+	if dep.Spec.Replicas != nil {
+		replicas = *dep.Spec.Replicas
+	}
+	return dep.Status.ObservedGeneration >= dep.Generation && dep.Status.AvailableReplicas >= replicas, nil
+}
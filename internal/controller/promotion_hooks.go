@@ -0,0 +1,284 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	// promotionHookPollInterval is how often a running promotion hook is checked
+	promotionHookPollInterval = 5 * time.Second
+
+	// defaultPromotionHookTimeout bounds hooks without a timeout
+	defaultPromotionHookTimeout int64 = 600
+
+	// LabelPromotionHook labels the Jobs of promotion hooks with the hook they run
+	LabelPromotionHook = "platform.kibaship.com/promotion-hook"
+)
+
+// promotionHook returns the hook of an application run at a point of the promotion, nil when
+// the application has none
+func promotionHook(app *platformv1alpha1.Application, hookType platformv1alpha1.PromotionHookType) *platformv1alpha1.PromotionHook {
+	if app.Spec.Hooks == nil {
+		return nil
+	}
+	switch hookType {
+	case platformv1alpha1.PromotionHookPrePromote:
+		return app.Spec.Hooks.PrePromote
+	case platformv1alpha1.PromotionHookPostPromote:
+		return app.Spec.Hooks.PostPromote
+	}
+	return nil
+}
+
+// promotionHookStatus returns the status of a hook run for a deployment, nil when it never ran
+func promotionHookStatus(deployment *platformv1alpha1.Deployment, hookType platformv1alpha1.PromotionHookType) *platformv1alpha1.PromotionHookStatus {
+	for i := range deployment.Status.Hooks {
+		if deployment.Status.Hooks[i].Type == hookType {
+			return &deployment.Status.Hooks[i]
+		}
+	}
+	return nil
+}
+
+// setPromotionHookStatus records the status of a hook run for a deployment
+func setPromotionHookStatus(deployment *platformv1alpha1.Deployment, status platformv1alpha1.PromotionHookStatus) {
+	if existing := promotionHookStatus(deployment, status.Type); existing != nil {
+		*existing = status
+		return
+	}
+	deployment.Status.Hooks = append(deployment.Status.Hooks, status)
+}
+
+// promotionHookRunning reports whether a hook of a deployment is still running, the promotion
+// then waits for it
+func promotionHookRunning(deployment *platformv1alpha1.Deployment) bool {
+	for _, hook := range deployment.Status.Hooks {
+		if hook.Phase == platformv1alpha1.PromotionHookPhaseRunning {
+			return true
+		}
+	}
+	return false
+}
+
+// promotionHookAborts reports whether a failed hook stops the promotion
+func promotionHookAborts(hook *platformv1alpha1.PromotionHook) bool {
+	return hook.FailurePolicy != platformv1alpha1.HookFailurePolicyContinue
+}
+
+// promotionHookJobName returns the name of the Job running a hook of a deployment
+func promotionHookJobName(deployment *platformv1alpha1.Deployment, hookType platformv1alpha1.PromotionHookType) string {
+	suffix := "pre-promote"
+	if hookType == platformv1alpha1.PromotionHookPostPromote {
+		suffix = "post-promote"
+	}
+	return fmt.Sprintf("%s-%s", utils.GetKubernetesDeploymentName(deployment.GetUUID()), suffix)
+}
+
+// runPromotionHook starts the Job of a hook of a deployment and records its outcome in the
+// deployment status. A hook runs once per deployment, a finished hook is never run again.
+// previous is the deployment the application served before the promotion.
+func (r *DeploymentProgressController) runPromotionHook(ctx context.Context, deployment *platformv1alpha1.Deployment, app *platformv1alpha1.Application,
+	hookType platformv1alpha1.PromotionHookType, hook *platformv1alpha1.PromotionHook, previous string) (*platformv1alpha1.PromotionHookStatus, error) {
+	status := promotionHookStatus(deployment, hookType)
+	if status != nil && status.Phase != platformv1alpha1.PromotionHookPhaseRunning {
+		return status, nil
+	}
+
+	now := metav1.NewTime(currentTime(r.Clock))
+	next := platformv1alpha1.PromotionHookStatus{
+		Type:               hookType,
+		Phase:              platformv1alpha1.PromotionHookPhaseRunning,
+		JobName:            promotionHookJobName(deployment, hookType),
+		StartTime:          &now,
+		PreviousDeployment: previous,
+	}
+	if status != nil {
+		next = *status
+	}
+
+	var job batchv1.Job
+	err := r.Get(ctx, client.ObjectKey{Name: next.JobName, Namespace: deployment.Namespace}, &job)
+	switch {
+	case apierrors.IsNotFound(err):
+		if status != nil {
+			next.Phase = platformv1alpha1.PromotionHookPhaseFailed
+			next.Message = "The Job of the hook was deleted before it finished"
+			next.CompletionTime = &now
+			break
+		}
+		if err := r.createPromotionHookJob(ctx, deployment, app, hookType, hook, next.JobName); err != nil {
+			return nil, err
+		}
+		recordEvent(r.Recorder, deployment, corev1.EventTypeNormal, eventReasonHookStarted, "Started the %s hook in Job %s", hookType, next.JobName)
+	case err != nil:
+		return nil, fmt.Errorf("failed to get %s hook job: %w", hookType, err)
+	default:
+		for _, condition := range job.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch condition.Type {
+			case batchv1.JobComplete:
+				next.Phase = platformv1alpha1.PromotionHookPhaseSucceeded
+				next.CompletionTime = &now
+				recordEvent(r.Recorder, deployment, corev1.EventTypeNormal, eventReasonHookSucceeded, "The %s hook succeeded", hookType)
+			case batchv1.JobFailed:
+				next.Phase = platformv1alpha1.PromotionHookPhaseFailed
+				next.Message = condition.Message
+				next.CompletionTime = &now
+				recordEvent(r.Recorder, deployment, corev1.EventTypeWarning, eventReasonHookFailed, "The %s hook failed: %s", hookType, condition.Message)
+			}
+		}
+	}
+
+	setPromotionHookStatus(deployment, next)
+	return promotionHookStatus(deployment, hookType), nil
+}
+
+// createPromotionHookJob starts the Job of a hook. The Job runs with the pod of the Kubernetes
+// Deployment of the deployment, its image, environment and security settings, without serving
+// traffic.
+func (r *DeploymentProgressController) createPromotionHookJob(ctx context.Context, deployment *platformv1alpha1.Deployment, app *platformv1alpha1.Application,
+	hookType platformv1alpha1.PromotionHookType, hook *platformv1alpha1.PromotionHook, name string) error {
+	var k8sDep appsv1.Deployment
+	key := client.ObjectKey{Name: utils.GetKubernetesDeploymentName(deployment.GetUUID()), Namespace: deployment.Namespace}
+	if err := r.Get(ctx, key, &k8sDep); err != nil {
+		return fmt.Errorf("failed to get Kubernetes Deployment for the %s hook: %w", hookType, err)
+	}
+	template := k8sDep.Spec.Template.Spec
+	if len(template.Containers) == 0 {
+		return fmt.Errorf("kubernetes Deployment %s has no container to run the %s hook with", key.Name, hookType)
+	}
+	appContainer := template.Containers[0]
+
+	image := appContainer.Image
+	if hook.Image != "" {
+		image = hook.Image
+	}
+	timeout := defaultPromotionHookTimeout
+	if hook.TimeoutSeconds > 0 {
+		timeout = int64(hook.TimeoutSeconds)
+	}
+	backoffLimit := int32(0)
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: deployment.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by":          "kibaship",
+				"app.kubernetes.io/component":           "promotion-hook",
+				"platform.kibaship.com/deployment-uuid": deployment.GetUUID(),
+				validation.LabelApplicationUUID:         app.GetUUID(),
+				LabelPromotionHook:                      string(hookType),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &timeout,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app.kubernetes.io/managed-by":          "kibaship",
+						"app.kubernetes.io/component":           "promotion-hook",
+						"platform.kibaship.com/deployment-uuid": deployment.GetUUID(),
+						LabelPromotionHook:                      string(hookType),
+					},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					PriorityClassName:  template.PriorityClassName,
+					ImagePullSecrets:   template.ImagePullSecrets,
+					SecurityContext:    template.SecurityContext,
+					ServiceAccountName: template.ServiceAccountName,
+					Volumes:            template.Volumes,
+					Containers: []corev1.Container{{
+						Name:            "hook",
+						Image:           image,
+						Command:         hook.Command,
+						Env:             appContainer.Env,
+						EnvFrom:         appContainer.EnvFrom,
+						Resources:       appContainer.Resources,
+						SecurityContext: appContainer.SecurityContext,
+						VolumeMounts:    appContainer.VolumeMounts,
+					}},
+				},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(deployment, job, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner of the %s hook job: %w", hookType, err)
+	}
+	if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create the %s hook job: %w", hookType, err)
+	}
+	return nil
+}
+
+// runPostPromoteHook runs the post-promote hook of a promoted deployment. A failed hook with the
+// Abort policy reverts the application to the deployment it served before the promotion.
+func (r *DeploymentProgressController) runPostPromoteHook(ctx context.Context, deployment *platformv1alpha1.Deployment, app *platformv1alpha1.Application, previous string) (time.Duration, error) {
+	hook := promotionHook(app, platformv1alpha1.PromotionHookPostPromote)
+	if hook == nil {
+		return 0, nil
+	}
+	status, err := r.runPromotionHook(ctx, deployment, app, platformv1alpha1.PromotionHookPostPromote, hook, previous)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case status.Phase == platformv1alpha1.PromotionHookPhaseRunning:
+		return promotionHookPollInterval, nil
+	case status.Phase == platformv1alpha1.PromotionHookPhaseSucceeded || !promotionHookAborts(hook):
+		return 0, nil
+	}
+
+	message := fmt.Sprintf("The post-promote hook failed: %s", status.Message)
+	if status.PreviousDeployment != "" &&
+		app.Spec.CurrentDeploymentRef != nil && app.Spec.CurrentDeploymentRef.Name == deployment.Name {
+		app.Spec.CurrentDeploymentRef = &corev1.LocalObjectReference{Name: status.PreviousDeployment}
+		if err := r.Update(ctx, app); err != nil {
+			return 0, fmt.Errorf("failed to revert the promotion of %s: %w", deployment.Name, err)
+		}
+		message = fmt.Sprintf("%s, reverted to deployment %s", message, status.PreviousDeployment)
+		recordEvent(r.Recorder, app, corev1.EventTypeWarning, eventReasonPromotionReverted, "Reverted to deployment %s, the post-promote hook of %s failed", status.PreviousDeployment, deployment.Name)
+	}
+	upsertCondition(&deployment.Status.Conditions, metav1.Condition{
+		Type:               DeploymentConditionPromoted,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.NewTime(currentTime(r.Clock)),
+		Reason:             "PostPromoteHookFailed",
+		Message:            message,
+	})
+	return 0, nil
+}
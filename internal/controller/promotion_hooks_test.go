@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	hookNamespace    = "project-shop"
+	hookPreviousUUID = "0b1c2d3e-4f5a-4b6c-8d7e-9f0a1b2c3d4e"
+	hookDeployUUID   = "7f6e5d4c-3b2a-4190-8f7e-6d5c4b3a2f1e"
+)
+
+func promotionHookFixtures(g *WithT, hooks *platformv1alpha1.PromotionHooks) (*DeploymentProgressController, *platformv1alpha1.Deployment) {
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	app := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "application-web", Namespace: hookNamespace},
+		Spec: platformv1alpha1.ApplicationSpec{
+			Type:                 platformv1alpha1.ApplicationTypeGitRepository,
+			CurrentDeploymentRef: &corev1.LocalObjectReference{Name: utils.GetDeploymentResourceName(hookPreviousUUID)},
+			Hooks:                hooks,
+		},
+	}
+	deployment := &platformv1alpha1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.GetDeploymentResourceName(hookDeployUUID),
+			Namespace: hookNamespace,
+			Labels:    map[string]string{validation.LabelResourceUUID: hookDeployUUID},
+		},
+		Spec: platformv1alpha1.DeploymentSpec{
+			ApplicationRef: corev1.LocalObjectReference{Name: app.Name},
+			Promote:        true,
+		},
+	}
+	pods := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: utils.GetKubernetesDeploymentName(hookDeployUUID), Namespace: hookNamespace},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry-image-pull-secret"}},
+			Containers: []corev1.Container{{
+				Name:  "app",
+				Image: "registry.registry.svc.cluster.local/project-shop/web:" + hookDeployUUID,
+				Env:   []corev1.EnvVar{{Name: "DATABASE_URL", Value: "postgres://db"}},
+			}},
+		}}},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app, deployment, pods).
+		WithStatusSubresource(&platformv1alpha1.Deployment{}).Build()
+	return &DeploymentProgressController{Client: c, Scheme: scheme}, deployment
+}
+
+func finishHookJob(g *WithT, c client.Client, name string, conditionType batchv1.JobConditionType) {
+	ctx := context.Background()
+	var job batchv1.Job
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: name, Namespace: hookNamespace}, &job)).To(Succeed())
+	job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+		Type:    conditionType,
+		Status:  corev1.ConditionTrue,
+		Message: "BackoffLimitExceeded",
+	})
+	g.Expect(c.Status().Update(ctx, &job)).To(Succeed())
+}
+
+func currentDeploymentName(g *WithT, c client.Client) string {
+	var app platformv1alpha1.Application
+	g.Expect(c.Get(context.Background(), client.ObjectKey{Name: "application-web", Namespace: hookNamespace}, &app)).To(Succeed())
+	return app.Spec.CurrentDeploymentRef.Name
+}
+
+func TestPrePromoteHook(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	r, deployment := promotionHookFixtures(g, &platformv1alpha1.PromotionHooks{
+		PrePromote: &platformv1alpha1.PromotionHook{Command: []string{"npm", "run", "migrate"}},
+	})
+
+	// The promotion waits for the hook
+	requeueAfter, err := r.checkAndPromoteDeployment(ctx, deployment)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(requeueAfter).To(Equal(promotionHookPollInterval))
+	g.Expect(promotionHookRunning(deployment)).To(BeTrue())
+	g.Expect(currentDeploymentName(g, r.Client)).To(Equal(utils.GetDeploymentResourceName(hookPreviousUUID)))
+
+	// The hook runs with the image and environment of the deployment
+	jobName := promotionHookJobName(deployment, platformv1alpha1.PromotionHookPrePromote)
+	var job batchv1.Job
+	g.Expect(r.Get(ctx, client.ObjectKey{Name: jobName, Namespace: hookNamespace}, &job)).To(Succeed())
+	container := job.Spec.Template.Spec.Containers[0]
+	g.Expect(container.Image).To(Equal("registry.registry.svc.cluster.local/project-shop/web:" + hookDeployUUID))
+	g.Expect(container.Command).To(Equal([]string{"npm", "run", "migrate"}))
+	g.Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "DATABASE_URL", Value: "postgres://db"}))
+	g.Expect(job.Spec.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+	g.Expect(*job.Spec.ActiveDeadlineSeconds).To(Equal(defaultPromotionHookTimeout))
+
+	requeueAfter, err = r.checkAndPromoteDeployment(ctx, deployment)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(requeueAfter).To(Equal(promotionHookPollInterval))
+
+	// Once it succeeded, the deployment is promoted
+	finishHookJob(g, r.Client, jobName, batchv1.JobComplete)
+	requeueAfter, err = r.checkAndPromoteDeployment(ctx, deployment)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(requeueAfter).To(BeZero())
+	g.Expect(promotionHookStatus(deployment, platformv1alpha1.PromotionHookPrePromote).Phase).To(Equal(platformv1alpha1.PromotionHookPhaseSucceeded))
+	g.Expect(currentDeploymentName(g, r.Client)).To(Equal(deployment.Name))
+	g.Expect(meta.IsStatusConditionTrue(deployment.Status.Conditions, DeploymentConditionPromoted)).To(BeTrue())
+}
+
+func TestPrePromoteHookFailurePolicy(t *testing.T) {
+	for _, policy := range []platformv1alpha1.HookFailurePolicy{platformv1alpha1.HookFailurePolicyAbort, platformv1alpha1.HookFailurePolicyContinue} {
+		t.Run(string(policy), func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			r, deployment := promotionHookFixtures(g, &platformv1alpha1.PromotionHooks{
+				PrePromote: &platformv1alpha1.PromotionHook{Command: []string{"false"}, FailurePolicy: policy},
+			})
+
+			_, err := r.checkAndPromoteDeployment(ctx, deployment)
+			g.Expect(err).NotTo(HaveOccurred())
+			finishHookJob(g, r.Client, promotionHookJobName(deployment, platformv1alpha1.PromotionHookPrePromote), batchv1.JobFailed)
+			requeueAfter, err := r.checkAndPromoteDeployment(ctx, deployment)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(requeueAfter).To(BeZero())
+			g.Expect(promotionHookRunning(deployment)).To(BeFalse())
+
+			promoted := meta.FindStatusCondition(deployment.Status.Conditions, DeploymentConditionPromoted)
+			if policy == platformv1alpha1.HookFailurePolicyAbort {
+				g.Expect(currentDeploymentName(g, r.Client)).To(Equal(utils.GetDeploymentResourceName(hookPreviousUUID)))
+				g.Expect(promoted.Reason).To(Equal("PrePromoteHookFailed"))
+				return
+			}
+			g.Expect(currentDeploymentName(g, r.Client)).To(Equal(deployment.Name))
+			g.Expect(promoted.Status).To(Equal(metav1.ConditionTrue))
+		})
+	}
+}
+
+func TestPostPromoteHookAbortReverts(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	r, deployment := promotionHookFixtures(g, &platformv1alpha1.PromotionHooks{
+		PostPromote: &platformv1alpha1.PromotionHook{Image: "curlimages/curl:8.10.1", Command: []string{"curl", "http://web/warm"}, TimeoutSeconds: 60},
+	})
+
+	// The deployment is promoted before the hook runs
+	requeueAfter, err := r.checkAndPromoteDeployment(ctx, deployment)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(requeueAfter).To(Equal(promotionHookPollInterval))
+	g.Expect(currentDeploymentName(g, r.Client)).To(Equal(deployment.Name))
+
+	jobName := promotionHookJobName(deployment, platformv1alpha1.PromotionHookPostPromote)
+	var job batchv1.Job
+	g.Expect(r.Get(ctx, client.ObjectKey{Name: jobName, Namespace: hookNamespace}, &job)).To(Succeed())
+	g.Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal("curlimages/curl:8.10.1"))
+	g.Expect(*job.Spec.ActiveDeadlineSeconds).To(Equal(int64(60)))
+
+	// A failed hook reverts the application to the previous deployment
+	finishHookJob(g, r.Client, jobName, batchv1.JobFailed)
+	requeueAfter, err = r.checkAndPromoteDeployment(ctx, deployment)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(requeueAfter).To(BeZero())
+	g.Expect(currentDeploymentName(g, r.Client)).To(Equal(utils.GetDeploymentResourceName(hookPreviousUUID)))
+	status := promotionHookStatus(deployment, platformv1alpha1.PromotionHookPostPromote)
+	g.Expect(status.Phase).To(Equal(platformv1alpha1.PromotionHookPhaseFailed))
+	g.Expect(status.CompletionTime).NotTo(BeNil())
+	g.Expect(meta.FindStatusCondition(deployment.Status.Conditions, DeploymentConditionPromoted).Reason).To(Equal("PostPromoteHookFailed"))
+}
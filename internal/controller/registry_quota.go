@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// DeploymentReasonRegistryQuotaExceeded fails the build of a deployment whose project uses more
+// registry storage than its quota
+const DeploymentReasonRegistryQuotaExceeded = "RegistryQuotaExceeded"
+
+// checkRegistryQuota fails the build of a deployment when its project uses more registry storage
+// than its quota. Builds that already started finish, deployments failed by the quota stay failed.
+func (r *DeploymentReconciler) checkRegistryQuota(ctx context.Context, deployment *platformv1alpha1.Deployment) (bool, error) {
+	condition := meta.FindStatusCondition(deployment.Status.Conditions, "PipelineRunReady")
+	if condition != nil && condition.Reason == DeploymentReasonRegistryQuotaExceeded {
+		return true, nil
+	}
+	if deployment.Spec.GitRepository == nil || deployment.PrebuiltImage() != "" || condition != nil {
+		return false, nil
+	}
+
+	var projects platformv1alpha1.ProjectList
+	if err := r.List(ctx, &projects, client.MatchingLabels{
+		validation.LabelResourceUUID: deployment.GetProjectUUID(),
+	}); err != nil {
+		return false, fmt.Errorf("failed to list projects: %w", err)
+	}
+	if len(projects.Items) == 0 || !projects.Items[0].RegistryQuotaExceeded() {
+		return false, nil
+	}
+
+	started, err := r.buildStarted(ctx, deployment)
+	if err != nil || started {
+		return false, err
+	}
+
+	message := "Registry quota exceeded: " + projects.Items[0].RegistryQuotaMessage()
+	meta.SetStatusCondition(&deployment.Status.Conditions, metav1.Condition{
+		Type:    "PipelineRunReady",
+		Status:  metav1.ConditionFalse,
+		Reason:  DeploymentReasonRegistryQuotaExceeded,
+		Message: message,
+	})
+	deployment.Status.Phase = platformv1alpha1.DeploymentPhaseFailed
+	if err := r.Status().Update(ctx, deployment); err != nil {
+		return false, fmt.Errorf("failed to fail deployment over the registry quota: %w", err)
+	}
+
+	logf.FromContext(ctx).Info("Build blocked by the project registry quota", "message", message)
+	return true, nil
+}
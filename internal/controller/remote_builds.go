@@ -0,0 +1,320 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+const (
+	// AnnotationBuildDeployment records on remote PipelineRuns the namespace/name of the
+	// Deployment they build, owner references cannot cross namespaces or clusters
+	AnnotationBuildDeployment = "platform.kibaship.com/build-deployment"
+
+	// inClusterRegistry is the host of the registry of the operator cluster
+	inClusterRegistry = "registry.registry.svc.cluster.local"
+
+	// registryDockerConfigSecret holds the registry credentials of a project namespace
+	registryDockerConfigSecret = "registry-docker-config"
+)
+
+// RemoteBuildExecutor dispatches the PipelineRuns of deployments to a pool of build namespaces,
+// in the operator cluster or in a dedicated build cluster. The Pipeline and the Secrets a build
+// mounts are copied next to the PipelineRun, and the image is pushed to the registry of the
+// operator cluster, which application pods pull it from.
+type RemoteBuildExecutor struct {
+	// Client reads and writes the build cluster
+	Client client.Client
+
+	// Namespaces is the pool of build namespaces
+	Namespaces []string
+
+	// Registry is the host the build cluster reaches the registry through, empty when the
+	// in-cluster registry host resolves from build pods
+	Registry string
+}
+
+// Namespace returns the build namespace of a project. A project always builds in the same
+// namespace so its builds share the build cache of the namespace.
+func (e *RemoteBuildExecutor) Namespace(projectUUID string) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(projectUUID))
+	return e.Namespaces[hash.Sum32()%uint32(len(e.Namespaces))]
+}
+
+// Dispatch copies pipelineRun, the local Pipeline it references and the Secrets it mounts into
+// the build namespace of its project, then starts it there. source reads the operator cluster.
+func (e *RemoteBuildExecutor) Dispatch(ctx context.Context, source client.Reader, deployment *platformv1alpha1.Deployment, pipelineRun *tektonv1.PipelineRun) error {
+	namespace := e.Namespace(deployment.GetProjectUUID())
+
+	var localPipeline tektonv1.Pipeline
+	if err := source.Get(ctx, types.NamespacedName{Name: pipelineRun.Spec.PipelineRef.Name, Namespace: deployment.Namespace}, &localPipeline); err != nil {
+		return fmt.Errorf("failed to get pipeline: %w", err)
+	}
+	pipeline, secretNames := e.remotePipeline(&localPipeline, namespace)
+	if err := e.Client.Get(ctx, client.ObjectKeyFromObject(pipeline), pipeline); errors.IsNotFound(err) {
+		if err := e.Client.Create(ctx, pipeline); err != nil {
+			return fmt.Errorf("failed to create remote pipeline: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to get remote pipeline: %w", err)
+	}
+
+	run := e.remotePipelineRun(deployment, pipelineRun, namespace)
+	for i := range run.Spec.Workspaces {
+		if workspace := run.Spec.Workspaces[i].Secret; workspace != nil {
+			secretNames[workspace.SecretName] = remoteSecretName(pipeline.Name, workspace.SecretName)
+			workspace.SecretName = secretNames[workspace.SecretName]
+		}
+	}
+
+	// The copies are owned by the remote Pipeline, deleting the deployment builds removes them
+	for name, remoteName := range secretNames {
+		if err := e.copySecret(ctx, source, types.NamespacedName{Name: name, Namespace: deployment.Namespace}, remoteName, pipeline); err != nil {
+			return err
+		}
+	}
+
+	if err := e.Client.Create(ctx, run); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create remote PipelineRun: %w", err)
+	}
+	logf.FromContext(ctx).Info("Dispatched PipelineRun to the build namespace pool",
+		"pipelineRun", run.Name, "buildNamespace", namespace)
+	return nil
+}
+
+// Cleanup deletes the Pipeline, the PipelineRuns and the Secret copies dispatched for a deployment
+func (e *RemoteBuildExecutor) Cleanup(ctx context.Context, deployment *platformv1alpha1.Deployment) error {
+	namespace := e.Namespace(deployment.GetProjectUUID())
+	ofDeployment := client.MatchingLabels{validation.LabelDeploymentUUID: deployment.GetUUID()}
+
+	if err := e.Client.DeleteAllOf(ctx, &tektonv1.PipelineRun{}, client.InNamespace(namespace), ofDeployment); err != nil {
+		return fmt.Errorf("failed to delete remote PipelineRuns: %w", err)
+	}
+	if err := e.Client.DeleteAllOf(ctx, &tektonv1.Pipeline{}, client.InNamespace(namespace), ofDeployment); err != nil {
+		return fmt.Errorf("failed to delete remote pipeline: %w", err)
+	}
+	// The Secret copies are owned by the remote Pipeline, delete them right away rather than
+	// leaving credentials behind until the build cluster collects them
+	if err := e.Client.DeleteAllOf(ctx, &corev1.Secret{}, client.InNamespace(namespace), ofDeployment); err != nil {
+		return fmt.Errorf("failed to delete remote secrets: %w", err)
+	}
+	return nil
+}
+
+// remotePipeline returns the copy of pipeline run in namespace, pushing to the registry host of
+// the build cluster, and the Secrets its tasks read by name
+func (e *RemoteBuildExecutor) remotePipeline(pipeline *tektonv1.Pipeline, namespace string) (*tektonv1.Pipeline, map[string]string) {
+	remote := &tektonv1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        pipeline.Name,
+			Namespace:   namespace,
+			Labels:      pipeline.Labels,
+			Annotations: pipeline.Annotations,
+		},
+		Spec: *pipeline.Spec.DeepCopy(),
+	}
+
+	secretNames := map[string]string{}
+	for i := range remote.Spec.Tasks {
+		params := remote.Spec.Tasks[i].Params
+		for j := range params {
+			switch params[j].Name {
+			case "imageTag":
+				params[j].Value.StringVal = e.remoteImage(params[j].Value.StringVal)
+			case "token-secret":
+				if name := params[j].Value.StringVal; name != "" {
+					secretNames[name] = remoteSecretName(remote.Name, name)
+					params[j].Value.StringVal = secretNames[name]
+				}
+			}
+		}
+	}
+	return remote, secretNames
+}
+
+// remotePipelineRun returns the copy of pipelineRun in namespace. It runs with the default
+// service account and storage class of the build namespace, the priority classes and build node
+// pool of the operator cluster do not apply there.
+func (e *RemoteBuildExecutor) remotePipelineRun(deployment *platformv1alpha1.Deployment, pipelineRun *tektonv1.PipelineRun, namespace string) *tektonv1.PipelineRun {
+	run := pipelineRun.DeepCopy()
+	run.Namespace = namespace
+	run.OwnerReferences = nil
+	run.Labels[validation.LabelDeploymentUUID] = deployment.GetUUID()
+	run.Annotations[AnnotationBuildDeployment] = deployment.Namespace + "/" + deployment.Name
+	run.Spec.TaskRunTemplate = tektonv1.PipelineTaskRunTemplate{}
+	for i := range run.Spec.Workspaces {
+		if claim := run.Spec.Workspaces[i].VolumeClaimTemplate; claim != nil {
+			claim.Spec.StorageClassName = nil
+		}
+	}
+	return run
+}
+
+// copySecret creates or refreshes the copy of a Secret of the operator cluster in the build
+// namespace of owner. The registry credentials are rewritten for the registry host of the
+// build cluster.
+func (e *RemoteBuildExecutor) copySecret(ctx context.Context, source client.Reader, key types.NamespacedName, name string, owner *tektonv1.Pipeline) error {
+	var secret corev1.Secret
+	if err := source.Get(ctx, key, &secret); err != nil {
+		return fmt.Errorf("failed to get secret %s: %w", key.Name, err)
+	}
+
+	data := secret.Data
+	if key.Name == registryDockerConfigSecret && e.Registry != "" {
+		config, err := rewriteDockerConfig(secret.Data["config.json"], e.Registry)
+		if err != nil {
+			return fmt.Errorf("failed to rewrite registry credentials: %w", err)
+		}
+		data = map[string][]byte{"config.json": config}
+	}
+
+	remote := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: owner.Namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, e.Client, remote, func() error {
+		remote.Labels = map[string]string{
+			"app.kubernetes.io/managed-by": "kibaship",
+			validation.LabelDeploymentUUID: owner.Labels[validation.LabelDeploymentUUID],
+		}
+		remote.Type = secret.Type
+		remote.Data = data
+		return controllerutil.SetOwnerReference(owner, remote, e.Client.Scheme())
+	}); err != nil {
+		return fmt.Errorf("failed to copy secret %s to the build namespace: %w", key.Name, err)
+	}
+	return nil
+}
+
+// remoteImage returns image with the in-cluster registry host replaced by the registry host of
+// the build cluster
+func (e *RemoteBuildExecutor) remoteImage(image string) string {
+	if e.Registry == "" {
+		return image
+	}
+	if path, ok := strings.CutPrefix(image, inClusterRegistry+"/"); ok {
+		return e.Registry + "/" + path
+	}
+	return image
+}
+
+// remoteSecretName names the copy of a Secret in the build namespace, Secrets of every project
+// share the namespace
+func remoteSecretName(pipelineName, secretName string) string {
+	return truncateLabel(pipelineName + "-" + secretName)
+}
+
+// rewriteDockerConfig adds to a Docker config.json the credentials of the in-cluster registry
+// under the registry host
+func rewriteDockerConfig(data []byte, registry string) ([]byte, error) {
+	var config struct {
+		Auths map[string]json.RawMessage `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	auth, ok := config.Auths[inClusterRegistry]
+	if !ok {
+		return nil, fmt.Errorf("no credentials for %s", inClusterRegistry)
+	}
+	config.Auths[registry] = auth
+	return json.Marshal(config)
+}
+
+// buildDeployment returns the Deployment a remote PipelineRun builds
+func buildDeployment(pipelineRun client.Object) (types.NamespacedName, bool) {
+	namespace, name, ok := strings.Cut(pipelineRun.GetAnnotations()[AnnotationBuildDeployment], "/")
+	if !ok || namespace == "" || name == "" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, true
+}
+
+// RemoteBuildStatusReconciler mirrors the status of PipelineRuns dispatched to the build
+// namespace pool into the conditions of their Deployments
+type RemoteBuildStatusReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Builds is the cluster of the build namespace pool
+	Builds cluster.Cluster
+}
+
+// Reconcile records the status of a remote PipelineRun on its Deployment
+func (r *RemoteBuildStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var pipelineRun tektonv1.PipelineRun
+	if err := r.Builds.GetClient().Get(ctx, req.NamespacedName, &pipelineRun); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	key, ok := buildDeployment(&pipelineRun)
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+	var deployment platformv1alpha1.Deployment
+	if err := r.Get(ctx, key, &deployment); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return ctrl.Result{}, recordPipelineRunStatus(ctx, r.Client, &pipelineRun, &deployment)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// PipelineRuns live on the build cluster, so they are matched on the shard of the namespace of
+// the Deployment they build, the same way ShardPredicate matches local resources.
+func (r *RemoteBuildStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	dispatched := predicate.NewTypedPredicateFuncs(func(pipelineRun *tektonv1.PipelineRun) bool {
+		key, ok := buildDeployment(pipelineRun)
+		if !ok {
+			return false
+		}
+		owned, err := ownsNamespace(context.Background(), mgr.GetClient(), key.Namespace)
+		if err != nil {
+			logf.Log.WithName("shard").Error(err, "failed to resolve shard of namespace", "namespace", key.Namespace)
+			return false
+		}
+		return owned
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WatchesRawSource(source.Kind(r.Builds.GetCache(), &tektonv1.PipelineRun{},
+			&handler.TypedEnqueueRequestForObject[*tektonv1.PipelineRun]{},
+			predicate.TypedResourceVersionChangedPredicate[*tektonv1.PipelineRun]{}, dispatched)).
+		WithOptions(controllerOptions("remote-build-status", 50)).
+		Named("remote-build-status").
+		Complete(r)
+}
@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/pipelines"
+	"github.com/kibamail/kibaship/pkg/validation"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// fakeBuildCluster serves the client of a build cluster
+type fakeBuildCluster struct {
+	cluster.Cluster
+	client client.Client
+}
+
+func (c *fakeBuildCluster) GetClient() client.Client {
+	return c.client
+}
+
+func remoteBuildsScheme(g *WithT) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(tektonv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	return scheme
+}
+
+func TestRemoteBuildExecutorNamespace(t *testing.T) {
+	g := NewWithT(t)
+
+	e := &RemoteBuildExecutor{Namespaces: []string{"builds-a", "builds-b", "builds-c"}}
+	seen := map[string]bool{}
+	for _, project := range []string{"project-1", "project-2", "project-3", "project-4", "project-5", "project-6"} {
+		namespace := e.Namespace(project)
+		g.Expect(e.Namespace(project)).To(Equal(namespace))
+		seen[namespace] = true
+	}
+	g.Expect(len(seen)).To(BeNumerically(">", 1))
+}
+
+func TestRemoteBuildExecutorDispatch(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := remoteBuildsScheme(g)
+
+	deployment := &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      "deployment-dep-1",
+		Namespace: "project-ns",
+		Labels: map[string]string{
+			validation.LabelResourceUUID: "dep-1",
+			validation.LabelProjectUUID:  "project-1",
+		},
+	}}
+	pipeline := &tektonv1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pipeline-dep-1",
+			Namespace: "project-ns",
+			Labels:    map[string]string{validation.LabelDeploymentUUID: "dep-1"},
+		},
+		Spec: tektonv1.PipelineSpec{Tasks: []tektonv1.PipelineTask{
+			{Name: "clone", Params: []tektonv1.Param{
+				{Name: "token-secret", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "git-token"}},
+			}},
+			{Name: "build", Params: []tektonv1.Param{
+				{Name: "imageTag", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "registry.registry.svc.cluster.local/project-ns/app-1:dep-1"}},
+			}},
+		}},
+	}
+	storageClass := "storage-replica-1"
+	pipelineRun := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pipeline-run-dep-1-1",
+			Namespace:   "project-ns",
+			Labels:      map[string]string{validation.LabelProjectUUID: "project-1", "app.kubernetes.io/component": pipelineRunComponent},
+			Annotations: map[string]string{},
+		},
+		Spec: tektonv1.PipelineRunSpec{
+			PipelineRef:     &tektonv1.PipelineRef{Name: "pipeline-dep-1"},
+			TaskRunTemplate: tektonv1.PipelineTaskRunTemplate{ServiceAccountName: "project-project-1-sa"},
+			Workspaces: []tektonv1.WorkspaceBinding{
+				{Name: "workspace-dep-1", VolumeClaimTemplate: &corev1.PersistentVolumeClaim{
+					Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass},
+				}},
+				{Name: "registry-docker-config", Secret: &corev1.SecretVolumeSource{SecretName: "registry-docker-config"}},
+			},
+		},
+	}
+	secrets := []client.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry-docker-config", Namespace: "project-ns"},
+			Data:       map[string][]byte{"config.json": []byte(`{"auths":{"registry.registry.svc.cluster.local":{"auth":"dTpw"}}}`)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "git-token", Namespace: "project-ns"},
+			Data:       map[string][]byte{"password": []byte("token")},
+		},
+	}
+
+	local := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment, pipeline).WithObjects(secrets...).Build()
+	remote := fake.NewClientBuilder().WithScheme(scheme).Build()
+	e := &RemoteBuildExecutor{Client: remote, Namespaces: []string{"builds"}, Registry: "registry.example.com"}
+
+	g.Expect(e.Dispatch(ctx, local, deployment, pipelineRun)).To(Succeed())
+
+	remotePipeline := &tektonv1.Pipeline{}
+	g.Expect(remote.Get(ctx, types.NamespacedName{Name: "pipeline-dep-1", Namespace: "builds"}, remotePipeline)).To(Succeed())
+	g.Expect(remotePipeline.Spec.Tasks[0].Params[0].Value.StringVal).To(Equal("pipeline-dep-1-git-token"))
+	g.Expect(remotePipeline.Spec.Tasks[1].Params[0].Value.StringVal).To(Equal("registry.example.com/project-ns/app-1:dep-1"))
+
+	remoteRun := &tektonv1.PipelineRun{}
+	g.Expect(remote.Get(ctx, types.NamespacedName{Name: "pipeline-run-dep-1-1", Namespace: "builds"}, remoteRun)).To(Succeed())
+	g.Expect(remoteRun.Annotations[AnnotationBuildDeployment]).To(Equal("project-ns/deployment-dep-1"))
+	g.Expect(remoteRun.Labels[validation.LabelDeploymentUUID]).To(Equal("dep-1"))
+	g.Expect(remoteRun.OwnerReferences).To(BeEmpty())
+	g.Expect(remoteRun.Spec.TaskRunTemplate.ServiceAccountName).To(BeEmpty())
+	g.Expect(remoteRun.Spec.Workspaces[0].VolumeClaimTemplate.Spec.StorageClassName).To(BeNil())
+	g.Expect(remoteRun.Spec.Workspaces[1].Secret.SecretName).To(Equal("pipeline-dep-1-registry-docker-config"))
+
+	dockerConfig := &corev1.Secret{}
+	g.Expect(remote.Get(ctx, types.NamespacedName{Name: "pipeline-dep-1-registry-docker-config", Namespace: "builds"}, dockerConfig)).To(Succeed())
+	g.Expect(string(dockerConfig.Data["config.json"])).To(ContainSubstring(`"registry.example.com":{"auth":"dTpw"}`))
+	g.Expect(dockerConfig.OwnerReferences).To(HaveLen(1))
+	g.Expect(dockerConfig.OwnerReferences[0].Name).To(Equal("pipeline-dep-1"))
+
+	gitToken := &corev1.Secret{}
+	g.Expect(remote.Get(ctx, types.NamespacedName{Name: "pipeline-dep-1-git-token", Namespace: "builds"}, gitToken)).To(Succeed())
+	g.Expect(gitToken.Data["password"]).To(Equal([]byte("token")))
+
+	// Dispatching again is a no-op
+	g.Expect(e.Dispatch(ctx, local, deployment, pipelineRun)).To(Succeed())
+
+	g.Expect(e.Cleanup(ctx, deployment)).To(Succeed())
+	var runs tektonv1.PipelineRunList
+	g.Expect(remote.List(ctx, &runs, client.InNamespace("builds"))).To(Succeed())
+	g.Expect(runs.Items).To(BeEmpty())
+	var pipelines tektonv1.PipelineList
+	g.Expect(remote.List(ctx, &pipelines, client.InNamespace("builds"))).To(Succeed())
+	g.Expect(pipelines.Items).To(BeEmpty())
+	var remoteSecrets corev1.SecretList
+	g.Expect(remote.List(ctx, &remoteSecrets, client.InNamespace("builds"))).To(Succeed())
+	g.Expect(remoteSecrets.Items).To(BeEmpty())
+}
+
+func TestRemoteBuildStatusReconciler(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := remoteBuildsScheme(g)
+
+	deployment := &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "deployment-dep-1", Namespace: "project-ns"}}
+	pipelineRun := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name:        "pipeline-run-dep-1-1",
+		Namespace:   "builds",
+		Annotations: map[string]string{AnnotationBuildDeployment: "project-ns/deployment-dep-1"},
+	}}
+	pipelineRun.Status.MarkSucceeded("Succeeded", "All Tasks have completed executing")
+	pipelineRun.Status.Results = []tektonv1.PipelineRunResult{{
+		Name:  pipelines.ResultImageDigest,
+		Value: tektonv1.ResultValue{Type: tektonv1.ParamTypeString, StringVal: "sha256:abc"},
+	}, {
+		Name:  pipelines.ResultExposedPort,
+		Value: tektonv1.ResultValue{Type: tektonv1.ParamTypeString, StringVal: "8080"},
+	}}
+
+	local := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment).
+		WithStatusSubresource(&platformv1alpha1.Deployment{}).Build()
+	remote := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pipelineRun).Build()
+	r := &RemoteBuildStatusReconciler{Client: local, Scheme: scheme, Builds: &fakeBuildCluster{client: remote}}
+
+	_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(pipelineRun)})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	updated := &platformv1alpha1.Deployment{}
+	g.Expect(local.Get(ctx, client.ObjectKeyFromObject(deployment), updated)).To(Succeed())
+	g.Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, "PipelineRunReady")).To(BeTrue())
+	g.Expect(updated.Status.ImageDigest).To(Equal("sha256:abc"))
+	g.Expect(updated.Status.DetectedPort).To(Equal(int32(8080)))
+}
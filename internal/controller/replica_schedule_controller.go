@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/idle"
+)
+
+// replicaScheduleCheckInterval bounds how long the scheduler waits before re-evaluating a schedule,
+// window starts are not predicted so they are picked up within this interval
+const replicaScheduleCheckInterval = time.Minute
+
+// ReplicaScheduleReconciler scales applications with a replica schedule to the replica count of
+// the window active now, or the default replicas outside any window
+type ReplicaScheduleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;patch
+
+// Reconcile applies the replica schedule of an application to its deployments
+func (r *ReplicaScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var app platformv1alpha1.Application
+	if err := r.Get(ctx, req.NamespacedName, &app); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !app.DeletionTimestamp.IsZero() || app.GetUUID() == "" {
+		return ctrl.Result{}, nil
+	}
+
+	schedule := app.Spec.ReplicaSchedule
+	if schedule == nil {
+		// Deployments keep their current replicas when the schedule is removed
+		return ctrl.Result{}, r.updateStatus(ctx, &app, nil)
+	}
+
+	now := time.Now()
+	window, replicas, until := schedule.ActiveWindow(now)
+
+	deployments, err := idle.Deployments(ctx, r.Client, app.Namespace, app.GetUUID())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	scaled := false
+	for i := range deployments {
+		dep := &deployments[i]
+		patch := client.MergeFrom(dep.DeepCopy())
+
+		// Sleeping deployments wake up to the scheduled replicas
+		if value, sleeping := dep.Annotations[idle.AnnotationIdleReplicas]; sleeping {
+			if value == strconv.Itoa(int(replicas)) {
+				continue
+			}
+			dep.Annotations[idle.AnnotationIdleReplicas] = strconv.Itoa(int(replicas))
+		} else {
+			if dep.Spec.Replicas != nil && *dep.Spec.Replicas == replicas {
+				continue
+			}
+			dep.Spec.Replicas = &replicas
+			scaled = true
+		}
+
+		if err := r.Patch(ctx, dep, patch); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to scale deployment %s: %w", dep.Name, err)
+		}
+	}
+
+	next := &platformv1alpha1.ReplicaScheduleStatus{Replicas: replicas}
+	if window != nil {
+		next.ActiveWindow = window.Name
+		next.ActiveUntil = &metav1.Time{Time: until}
+	}
+	if current := app.Status.ReplicaSchedule; current != nil {
+		next.LastScaleTime = current.LastScaleTime
+	}
+	if scaled {
+		next.LastScaleTime = &metav1.Time{Time: now}
+		log.Info("Scaled application on schedule", "application", app.Name, "window", next.ActiveWindow, "replicas", replicas)
+	}
+	if err := r.updateStatus(ctx, &app, next); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	requeueAfter := replicaScheduleCheckInterval
+	if window != nil && until.Sub(now) < requeueAfter {
+		requeueAfter = until.Sub(now)
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// updateStatus records the applied replica schedule window, clearing it when next is nil
+func (r *ReplicaScheduleReconciler) updateStatus(ctx context.Context, app *platformv1alpha1.Application, next *platformv1alpha1.ReplicaScheduleStatus) error {
+	if replicaScheduleStatusEqual(app.Status.ReplicaSchedule, next) {
+		return nil
+	}
+
+	patch := client.MergeFrom(app.DeepCopy())
+	app.Status.ReplicaSchedule = next
+	if err := r.Status().Patch(ctx, app, patch); err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to update application replica schedule status: %w", err)
+	}
+	return nil
+}
+
+func replicaScheduleStatusEqual(a, b *platformv1alpha1.ReplicaScheduleStatus) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	timeEqual := func(x, y *metav1.Time) bool {
+		if x == nil || y == nil {
+			return x == y
+		}
+		return x.Equal(y)
+	}
+	return a.ActiveWindow == b.ActiveWindow && a.Replicas == b.Replicas &&
+		timeEqual(a.ActiveUntil, b.ActiveUntil) && timeEqual(a.LastScaleTime, b.LastScaleTime)
+}
+
+// scheduledReplicas returns the replicas a new Kubernetes Deployment of the application starts with.
+// Autoscaled applications start with their minimum replicas, at least one so the deployment can
+// become ready before KEDA takes over.
+func scheduledReplicas(app *platformv1alpha1.Application, now time.Time) int32 {
+	if app.Spec.Autoscaling != nil {
+		return max(1, app.Spec.Autoscaling.Min())
+	}
+	if app.Spec.ReplicaSchedule == nil {
+		return 1
+	}
+	_, replicas, _ := app.Spec.ReplicaSchedule.ActiveWindow(now)
+	return replicas
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Status updates are ignored, schedules are evaluated on spec changes and at least every minute.
+func (r *ReplicaScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&platformv1alpha1.Application{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controllerOptions("application-replica-schedule", 1)).
+		Named("application-replica-schedule").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
+		Complete(r)
+}
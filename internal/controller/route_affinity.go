@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// traefikStickyAnnotationPrefix prefixes the Service annotations of Traefik sticky sessions
+const traefikStickyAnnotationPrefix = "traefik.ingress.kubernetes.io/service.sticky.cookie"
+
+// nginxSessionAffinityAnnotations renders the session affinity of route as ingress-nginx annotations,
+// they apply to every path of the Ingress
+func nginxSessionAffinityAnnotations(route RouteSpec) map[string]string {
+	affinity := route.SessionAffinity
+	if affinity == nil {
+		return nil
+	}
+
+	annotations := map[string]string{
+		"nginx.ingress.kubernetes.io/affinity":            "cookie",
+		"nginx.ingress.kubernetes.io/session-cookie-name": affinity.Cookie(),
+	}
+	if affinity.TTLSeconds > 0 {
+		ttl := strconv.Itoa(int(affinity.TTLSeconds))
+		annotations["nginx.ingress.kubernetes.io/session-cookie-max-age"] = ttl
+		annotations["nginx.ingress.kubernetes.io/session-cookie-expires"] = ttl
+	}
+	return annotations
+}
+
+// traefikSessionAffinityAnnotations renders the session affinity of route as the annotations Traefik
+// reads from the Service of the route
+func traefikSessionAffinityAnnotations(route RouteSpec) map[string]string {
+	affinity := route.SessionAffinity
+	if affinity == nil {
+		return nil
+	}
+
+	annotations := map[string]string{
+		traefikStickyAnnotationPrefix:           TrueString,
+		traefikStickyAnnotationPrefix + ".name": affinity.Cookie(),
+	}
+	if affinity.TTLSeconds > 0 {
+		annotations[traefikStickyAnnotationPrefix+".maxage"] = strconv.Itoa(int(affinity.TTLSeconds))
+	}
+	return annotations
+}
+
+// gatewaySessionPersistence renders the session affinity of route as the session persistence of
+// the HTTPRoute rule of its Service
+func gatewaySessionPersistence(route RouteSpec) map[string]any {
+	affinity := route.SessionAffinity
+	if affinity == nil {
+		return nil
+	}
+
+	persistence := map[string]any{
+		"sessionName":  affinity.Cookie(),
+		"type":         "Cookie",
+		"cookieConfig": map[string]any{"lifetimeType": "Session"},
+	}
+	if affinity.TTLSeconds > 0 {
+		persistence["absoluteTimeout"] = gatewayDuration(affinity.TTLSeconds)
+		persistence["cookieConfig"] = map[string]any{"lifetimeType": "Permanent"}
+	}
+	return persistence
+}
+
+// gatewayDuration formats seconds as a Gateway API duration, which allows at most 5 digits per unit
+func gatewayDuration(seconds int32) string {
+	hours, minutes, secs := seconds/3600, seconds%3600/60, seconds%60
+
+	var duration strings.Builder
+	if hours > 0 {
+		fmt.Fprintf(&duration, "%dh", hours)
+	}
+	if minutes > 0 {
+		fmt.Fprintf(&duration, "%dm", minutes)
+	}
+	if secs > 0 || duration.Len() == 0 {
+		fmt.Fprintf(&duration, "%ds", secs)
+	}
+	return duration.String()
+}
+
+// ensureServiceSessionAffinity keeps the Traefik sticky session annotations of the Service of route
+// in sync with its session affinity. The Service is created by the first deployment of the
+// application, routes reconciled before that are annotated once it is deployed.
+func (p *ingressRouteProvider) ensureServiceSessionAffinity(ctx context.Context, route RouteSpec) error {
+	var service corev1.Service
+	if err := p.Get(ctx, client.ObjectKey{Namespace: route.Namespace, Name: route.ServiceName}, &service); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get Service of route: %w", err)
+	}
+
+	desired := traefikSessionAffinityAnnotations(route)
+	patch := client.MergeFrom(service.DeepCopy())
+	changed := false
+	for key := range service.Annotations {
+		if strings.HasPrefix(key, traefikStickyAnnotationPrefix) {
+			if _, ok := desired[key]; !ok {
+				delete(service.Annotations, key)
+				changed = true
+			}
+		}
+	}
+	for key, value := range desired {
+		if service.Annotations[key] != value {
+			if service.Annotations == nil {
+				service.Annotations = map[string]string{}
+			}
+			service.Annotations[key] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := p.Patch(ctx, &service, patch); err != nil {
+		return fmt.Errorf("failed to update the session affinity of Service %s: %w", route.ServiceName, err)
+	}
+	ctrl.LoggerFrom(ctx).V(1).Info("Updated session affinity of Service", "name", route.ServiceName,
+		"sessionAffinity", route.SessionAffinity != nil)
+	return nil
+}
+
+// sessionAffinityChanged passes the updates of Applications changing their session affinity, the
+// routes of their domains render it
+func sessionAffinityChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldApp, ok := e.ObjectOld.(*platformv1alpha1.Application)
+			if !ok {
+				return false
+			}
+			newApp, ok := e.ObjectNew.(*platformv1alpha1.Application)
+			if !ok {
+				return false
+			}
+			return !reflect.DeepEqual(oldApp.Spec.SessionAffinity, newApp.Spec.SessionAffinity)
+		},
+	}
+}
+
+// applicationDomainRequests enqueues the domains of an application
+func (r *ApplicationDomainReconciler) applicationDomainRequests(ctx context.Context, obj client.Object) []ctrl.Request {
+	var domains platformv1alpha1.ApplicationDomainList
+	if err := r.List(ctx, &domains, client.InNamespace(obj.GetNamespace()),
+		client.MatchingLabels{ApplicationDomainLabelApplication: obj.GetName()}); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Failed to list ApplicationDomains of application", "application", obj.GetName())
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(domains.Items))
+	for _, domain := range domains.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&domain)})
+	}
+	return requests
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func TestSessionAffinityRoutes(t *testing.T) {
+	g := NewWithT(t)
+
+	route := RouteSpec{
+		Namespace:       "project-ns",
+		Name:            "httproute-1",
+		Hostname:        "app.example.com",
+		ServiceName:     "service-1",
+		ServicePort:     3000,
+		Paths:           []RoutePath{{Path: "/api", ServiceName: "service-2", ServicePort: 8080}},
+		SessionAffinity: &platformv1alpha1.SessionAffinity{TTLSeconds: 90061},
+	}
+
+	nginx := (&ingressRouteProvider{Provider: config.IngressProviderNginx}).annotations(route, false)
+	g.Expect(nginx).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/affinity", "cookie"))
+	g.Expect(nginx).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/session-cookie-name", platformv1alpha1.DefaultSessionAffinityCookieName))
+	g.Expect(nginx).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/session-cookie-max-age", "90061"))
+
+	// Only the rule of the application Service persists sessions
+	spec, err := httpRouteSpec(route, "https")
+	g.Expect(err).NotTo(HaveOccurred())
+	rules := spec["rules"].([]any)
+	g.Expect(rules).To(HaveLen(2))
+	g.Expect(rules[0]).NotTo(HaveKey("sessionPersistence"))
+	g.Expect(rules[1]).To(HaveKeyWithValue("sessionPersistence", map[string]any{
+		"sessionName":     platformv1alpha1.DefaultSessionAffinityCookieName,
+		"type":            "Cookie",
+		"absoluteTimeout": "25h1m1s",
+		"cookieConfig":    map[string]any{"lifetimeType": "Permanent"},
+	}))
+
+	// Without a TTL the cookie lasts as long as the browser session
+	route.SessionAffinity = &platformv1alpha1.SessionAffinity{CookieName: "SERVERID"}
+	g.Expect(nginxSessionAffinityAnnotations(route)).To(Equal(map[string]string{
+		"nginx.ingress.kubernetes.io/affinity":            "cookie",
+		"nginx.ingress.kubernetes.io/session-cookie-name": "SERVERID",
+	}))
+	g.Expect(gatewaySessionPersistence(route)).To(Equal(map[string]any{
+		"sessionName":  "SERVERID",
+		"type":         "Cookie",
+		"cookieConfig": map[string]any{"lifetimeType": "Session"},
+	}))
+
+	route.SessionAffinity = nil
+	g.Expect(nginxSessionAffinityAnnotations(route)).To(BeEmpty())
+	g.Expect(gatewaySessionPersistence(route)).To(BeNil())
+	g.Expect(gatewayDuration(0)).To(Equal("0s"))
+	g.Expect(gatewayDuration(platformv1alpha1.MaxSessionAffinityTTLSeconds)).To(Equal("8760h"))
+}
+
+func TestTraefikServiceSessionAffinity(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:        "service-1",
+		Namespace:   "project-ns",
+		Annotations: map[string]string{"example.com/owner": "team"},
+	}}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(service).Build()
+	provider := &ingressRouteProvider{Client: cl, Scheme: scheme, Provider: config.IngressProviderTraefik}
+
+	route := RouteSpec{
+		Namespace:       "project-ns",
+		Name:            "httproute-1",
+		ServiceName:     "service-1",
+		SessionAffinity: &platformv1alpha1.SessionAffinity{CookieName: "SERVERID", TTLSeconds: 3600},
+	}
+	g.Expect(provider.ensureServiceSessionAffinity(ctx, route)).To(Succeed())
+	g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(service), service)).To(Succeed())
+	g.Expect(service.Annotations).To(Equal(map[string]string{
+		"example.com/owner": "team",
+		"traefik.ingress.kubernetes.io/service.sticky.cookie":        TrueString,
+		"traefik.ingress.kubernetes.io/service.sticky.cookie.name":   "SERVERID",
+		"traefik.ingress.kubernetes.io/service.sticky.cookie.maxage": "3600",
+	}))
+
+	// Removing the affinity keeps the other annotations of the Service
+	route.SessionAffinity = nil
+	g.Expect(provider.ensureServiceSessionAffinity(ctx, route)).To(Succeed())
+	g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(service), service)).To(Succeed())
+	g.Expect(service.Annotations).To(Equal(map[string]string{"example.com/owner": "team"}))
+
+	// Routes of applications not deployed yet have no Service to annotate
+	route.ServiceName = "service-2"
+	g.Expect(provider.ensureServiceSessionAffinity(ctx, route)).To(Succeed())
+}
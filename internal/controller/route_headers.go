@@ -0,0 +1,240 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// traefikMiddlewareGVK is the Traefik Middleware kind carrying the response headers of a route
+var traefikMiddlewareGVK = schema.GroupVersionKind{Group: "traefik.io", Version: "v1alpha1", Kind: "Middleware"}
+
+// hasResponseHeaders reports whether the route adds security or CORS headers to responses
+func (r RouteSpec) hasResponseHeaders() bool {
+	return r.securityHeaders() != nil || r.CORS != nil
+}
+
+// gatewayResponseHeaderFilters renders the response headers of route as an HTTPRoute
+// ResponseHeaderModifier filter. Gateway API has no standard CORS filter, so CORS headers are set
+// statically: only a single origin (or *) can be allowed and preflight requests reach the application.
+func gatewayResponseHeaderFilters(route RouteSpec) ([]any, error) {
+	headers := map[string]string{}
+	if securityHeaders := route.securityHeaders(); securityHeaders != nil {
+		headers = securityHeaders.ResponseHeaders()
+	}
+
+	if cors := route.CORS; cors != nil {
+		if len(cors.AllowOrigins) != 1 {
+			return nil, fmt.Errorf("the %s ingress provider allows a single CORS origin or *, got %d",
+				config.IngressProviderGateway, len(cors.AllowOrigins))
+		}
+		headers["Access-Control-Allow-Origin"] = cors.AllowOrigins[0]
+		headers["Access-Control-Allow-Methods"] = strings.Join(cors.Methods(), ", ")
+		if len(cors.AllowHeaders) > 0 {
+			headers["Access-Control-Allow-Headers"] = strings.Join(cors.AllowHeaders, ", ")
+		}
+		if len(cors.ExposeHeaders) > 0 {
+			headers["Access-Control-Expose-Headers"] = strings.Join(cors.ExposeHeaders, ", ")
+		}
+		if cors.AllowCredentials {
+			headers["Access-Control-Allow-Credentials"] = TrueString
+		}
+		if cors.MaxAgeSeconds > 0 {
+			headers["Access-Control-Max-Age"] = strconv.Itoa(int(cors.MaxAgeSeconds))
+		}
+	}
+
+	if len(headers) == 0 {
+		return nil, nil
+	}
+
+	set := make([]any, 0, len(headers))
+	for _, name := range sortedHeaderNames(headers) {
+		set = append(set, map[string]any{"name": name, "value": headers[name]})
+	}
+	return []any{
+		map[string]any{
+			"type":                   "ResponseHeaderModifier",
+			"responseHeaderModifier": map[string]any{"set": set},
+		},
+	}, nil
+}
+
+// nginxResponseHeaderAnnotations renders the response headers of route as ingress-nginx annotations.
+// Security headers use a configuration snippet, which the controller must allow.
+func nginxResponseHeaderAnnotations(route RouteSpec) map[string]string {
+	annotations := map[string]string{}
+
+	if securityHeaders := route.securityHeaders(); securityHeaders != nil {
+		headers := securityHeaders.ResponseHeaders()
+		lines := make([]string, 0, len(headers))
+		for _, name := range sortedHeaderNames(headers) {
+			lines = append(lines, fmt.Sprintf("more_set_headers \"%s: %s\";", name, headers[name]))
+		}
+		if len(lines) > 0 {
+			annotations["nginx.ingress.kubernetes.io/configuration-snippet"] = strings.Join(lines, "\n")
+		}
+	}
+
+	if cors := route.CORS; cors != nil {
+		annotations["nginx.ingress.kubernetes.io/enable-cors"] = TrueString
+		annotations["nginx.ingress.kubernetes.io/cors-allow-origin"] = strings.Join(cors.AllowOrigins, ", ")
+		annotations["nginx.ingress.kubernetes.io/cors-allow-methods"] = strings.Join(cors.Methods(), ", ")
+		annotations["nginx.ingress.kubernetes.io/cors-allow-credentials"] = strconv.FormatBool(cors.AllowCredentials)
+		if len(cors.AllowHeaders) > 0 {
+			annotations["nginx.ingress.kubernetes.io/cors-allow-headers"] = strings.Join(cors.AllowHeaders, ", ")
+		}
+		if len(cors.ExposeHeaders) > 0 {
+			annotations["nginx.ingress.kubernetes.io/cors-expose-headers"] = strings.Join(cors.ExposeHeaders, ", ")
+		}
+		if cors.MaxAgeSeconds > 0 {
+			annotations["nginx.ingress.kubernetes.io/cors-max-age"] = strconv.Itoa(int(cors.MaxAgeSeconds))
+		}
+	}
+
+	return annotations
+}
+
+// ensureResponseHeaders keeps the Traefik headers Middleware of route in sync, other ingress
+// controllers are configured through annotations
+func (p *ingressRouteProvider) ensureResponseHeaders(ctx context.Context, route RouteSpec, owner metav1.Object) error {
+	if p.Provider != config.IngressProviderTraefik {
+		return nil
+	}
+	if !route.hasResponseHeaders() {
+		return deleteTraefikHeadersMiddleware(ctx, p.Client, route.Namespace, route.Name)
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(traefikMiddlewareGVK)
+	obj.SetNamespace(route.Namespace)
+	obj.SetName(traefikMiddlewareName(route.Name))
+
+	result, err := controllerutil.CreateOrUpdate(ctx, p.Client, obj, func() error {
+		obj.SetLabels(map[string]string{
+			"app.kubernetes.io/managed-by": "kibaship",
+			"platform.kibaship.com/type":   "middleware",
+		})
+		obj.Object["spec"] = map[string]any{"headers": traefikHeaders(route)}
+		return ctrl.SetControllerReference(owner, obj, p.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ensure headers Middleware: %w", err)
+	}
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Ensured headers Middleware", "name", obj.GetName(), "result", result)
+	return nil
+}
+
+// traefikHeaders renders the headers middleware configuration of route
+func traefikHeaders(route RouteSpec) map[string]any {
+	headers := map[string]any{}
+
+	if h := route.securityHeaders(); h != nil {
+		if h.HSTSMaxAgeSeconds > 0 {
+			headers["stsSeconds"] = h.HSTSMaxAgeSeconds
+			headers["stsIncludeSubdomains"] = h.HSTSIncludeSubdomains
+			headers["stsPreload"] = h.HSTSPreload
+		}
+		if h.ContentSecurityPolicy != "" {
+			headers["contentSecurityPolicy"] = h.ContentSecurityPolicy
+		}
+		if h.FrameOptions != "" {
+			headers["customFrameOptionsValue"] = h.FrameOptions
+		}
+		if h.ContentTypeNosniff {
+			headers["contentTypeNosniff"] = true
+		}
+		if h.ReferrerPolicy != "" {
+			headers["referrerPolicy"] = h.ReferrerPolicy
+		}
+	}
+
+	if cors := route.CORS; cors != nil {
+		headers["accessControlAllowOriginList"] = stringsToAny(cors.AllowOrigins)
+		headers["accessControlAllowMethods"] = stringsToAny(cors.Methods())
+		headers["accessControlAllowCredentials"] = cors.AllowCredentials
+		headers["addVaryHeader"] = true
+		if len(cors.AllowHeaders) > 0 {
+			headers["accessControlAllowHeaders"] = stringsToAny(cors.AllowHeaders)
+		}
+		if len(cors.ExposeHeaders) > 0 {
+			headers["accessControlExposeHeaders"] = stringsToAny(cors.ExposeHeaders)
+		}
+		if cors.MaxAgeSeconds > 0 {
+			headers["accessControlMaxAge"] = int64(cors.MaxAgeSeconds)
+		}
+	}
+
+	return headers
+}
+
+// deleteTraefikHeadersMiddleware removes the headers Middleware of the route named name
+func deleteTraefikHeadersMiddleware(ctx context.Context, c client.Client, namespace, name string) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(traefikMiddlewareGVK)
+	obj.SetNamespace(namespace)
+	obj.SetName(traefikMiddlewareName(name))
+
+	// The Middleware kind is missing when Traefik CRDs were never installed, nothing to delete then
+	if err := c.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to delete headers Middleware: %w", err)
+	}
+	return nil
+}
+
+// traefikMiddlewareName is the name of the headers Middleware of the route named name
+func traefikMiddlewareName(name string) string {
+	return fmt.Sprintf("%s-headers", name)
+}
+
+// traefikMiddlewareRef references the headers Middleware of a route from an Ingress annotation
+func traefikMiddlewareRef(namespace, name string) string {
+	return fmt.Sprintf("%s-%s@kubernetescrd", namespace, traefikMiddlewareName(name))
+}
+
+func sortedHeaderNames(headers map[string]string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func stringsToAny(values []string) []any {
+	out := make([]any, 0, len(values))
+	for _, v := range values {
+		out = append(out, v)
+	}
+	return out
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+const (
+	// upgradedConnectionTimeout is how long ingress-nginx keeps idle gRPC streams and WebSocket
+	// connections open, in seconds
+	upgradedConnectionTimeout = "3600"
+
+	// traefikServersSchemeAnnotation selects the protocol Traefik speaks to the pods of a Service
+	traefikServersSchemeAnnotation = "traefik.ingress.kubernetes.io/service.serversscheme"
+)
+
+// applicationBackendProtocol returns the backend protocol of the routes of an application: the
+// protocol of appDomain, or the one set on the other domains of the application, http1 when none
+func applicationBackendProtocol(
+	ctx context.Context,
+	c client.Reader,
+	app *platformv1alpha1.Application,
+	appDomain *platformv1alpha1.ApplicationDomain,
+) (platformv1alpha1.BackendProtocol, error) {
+	if appDomain != nil && appDomain.Spec.BackendProtocol != "" {
+		return appDomain.Spec.BackendProtocol, nil
+	}
+	protocol, err := platformv1alpha1.FindBackendProtocol(ctx, c, app.Namespace, app.Name, "")
+	if err != nil || protocol == "" {
+		return platformv1alpha1.BackendProtocolHTTP1, err
+	}
+	return protocol, nil
+}
+
+// nginxBackendProtocolAnnotations renders the backend protocol of route as ingress-nginx annotations
+func nginxBackendProtocolAnnotations(route RouteSpec) map[string]string {
+	switch route.BackendProtocol {
+	case platformv1alpha1.BackendProtocolGRPC:
+		return map[string]string{
+			"nginx.ingress.kubernetes.io/backend-protocol":   "GRPC",
+			"nginx.ingress.kubernetes.io/proxy-read-timeout": upgradedConnectionTimeout,
+			"nginx.ingress.kubernetes.io/proxy-send-timeout": upgradedConnectionTimeout,
+		}
+	case platformv1alpha1.BackendProtocolWebSocket:
+		return map[string]string{
+			"nginx.ingress.kubernetes.io/proxy-read-timeout": upgradedConnectionTimeout,
+			"nginx.ingress.kubernetes.io/proxy-send-timeout": upgradedConnectionTimeout,
+		}
+	}
+	return nil
+}
+
+// serviceAppProtocol returns the appProtocol of the Service port Gateway API implementations read
+// the backend protocol from, nil for http1
+func serviceAppProtocol(protocol platformv1alpha1.BackendProtocol) *string {
+	var appProtocol string
+	switch protocol {
+	case platformv1alpha1.BackendProtocolGRPC:
+		appProtocol = "kubernetes.io/h2c"
+	case platformv1alpha1.BackendProtocolWebSocket:
+		appProtocol = "kubernetes.io/ws"
+	default:
+		return nil
+	}
+	return &appProtocol
+}
+
+// ensureServiceBackendProtocol sets the backend protocol of route on its Service, where Traefik
+// reads it from an annotation and Gateway API implementations from the appProtocol of the port.
+// Routes without a backend protocol leave the Service as it is.
+func ensureServiceBackendProtocol(ctx context.Context, c client.Client, route RouteSpec, provider config.IngressProvider) error {
+	if route.BackendProtocol == "" || provider == config.IngressProviderNginx {
+		return nil
+	}
+
+	var service corev1.Service
+	if err := c.Get(ctx, client.ObjectKey{Namespace: route.Namespace, Name: route.ServiceName}, &service); err != nil {
+		if errors.IsNotFound(err) {
+			return nil // Set once the first deployment creates the Service
+		}
+		return fmt.Errorf("failed to get Service of route: %w", err)
+	}
+
+	patch := client.MergeFrom(service.DeepCopy())
+	changed := false
+	if provider == config.IngressProviderTraefik {
+		_, annotated := service.Annotations[traefikServersSchemeAnnotation]
+		switch {
+		case route.BackendProtocol == platformv1alpha1.BackendProtocolGRPC && service.Annotations[traefikServersSchemeAnnotation] != "h2c":
+			if service.Annotations == nil {
+				service.Annotations = map[string]string{}
+			}
+			service.Annotations[traefikServersSchemeAnnotation] = "h2c"
+			changed = true
+		case route.BackendProtocol != platformv1alpha1.BackendProtocolGRPC && annotated:
+			delete(service.Annotations, traefikServersSchemeAnnotation)
+			changed = true
+		}
+	} else {
+		appProtocol := serviceAppProtocol(route.BackendProtocol)
+		for i := range service.Spec.Ports {
+			port := &service.Spec.Ports[i]
+			if port.Port == route.ServicePort && !reflect.DeepEqual(port.AppProtocol, appProtocol) {
+				port.AppProtocol = appProtocol
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := c.Patch(ctx, &service, patch); err != nil {
+		return fmt.Errorf("failed to update the backend protocol of Service %s: %w", route.ServiceName, err)
+	}
+	ctrl.LoggerFrom(ctx).V(1).Info("Updated backend protocol of Service", "name", route.ServiceName,
+		"backendProtocol", route.BackendProtocol)
+	return nil
+}
+
+// backendProtocolChanged passes the updates of ApplicationDomains changing their backend protocol,
+// the other domains of the application follow it
+func backendProtocolChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			domain, ok := e.Object.(*platformv1alpha1.ApplicationDomain)
+			return ok && domain.Spec.BackendProtocol != ""
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			domain, ok := e.Object.(*platformv1alpha1.ApplicationDomain)
+			return ok && domain.Spec.BackendProtocol != ""
+		},
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldDomain, ok := e.ObjectOld.(*platformv1alpha1.ApplicationDomain)
+			if !ok {
+				return false
+			}
+			newDomain, ok := e.ObjectNew.(*platformv1alpha1.ApplicationDomain)
+			if !ok {
+				return false
+			}
+			return oldDomain.Spec.BackendProtocol != newDomain.Spec.BackendProtocol
+		},
+	}
+}
+
+// siblingDomainRequests enqueues the other domains of the application of a domain
+func (r *ApplicationDomainReconciler) siblingDomainRequests(ctx context.Context, obj client.Object) []ctrl.Request {
+	domain, ok := obj.(*platformv1alpha1.ApplicationDomain)
+	if !ok {
+		return nil
+	}
+
+	var domains platformv1alpha1.ApplicationDomainList
+	if err := r.List(ctx, &domains, client.InNamespace(domain.Namespace)); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Failed to list ApplicationDomains of application", "application", domain.Spec.ApplicationRef.Name)
+		return nil
+	}
+
+	var requests []ctrl.Request
+	for _, other := range domains.Items {
+		if other.Name != domain.Name && other.Spec.ApplicationRef.Name == domain.Spec.ApplicationRef.Name {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&other)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func TestApplicationBackendProtocol(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	app := &platformv1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "application-api", Namespace: "project-ns"}}
+	domain := func(name, application string, protocol platformv1alpha1.BackendProtocol) *platformv1alpha1.ApplicationDomain {
+		return &platformv1alpha1.ApplicationDomain{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "project-ns"},
+			Spec: platformv1alpha1.ApplicationDomainSpec{
+				ApplicationRef:  corev1.LocalObjectReference{Name: application},
+				BackendProtocol: protocol,
+			},
+		}
+	}
+	defaultDomain := domain("domain-a", "application-api", "")
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		defaultDomain,
+		domain("domain-b", "application-api", platformv1alpha1.BackendProtocolGRPC),
+		domain("domain-c", "application-web", platformv1alpha1.BackendProtocolWebSocket),
+	).Build()
+
+	// Domains without a protocol follow the other domains of their application
+	protocol, err := applicationBackendProtocol(ctx, c, app, defaultDomain)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(protocol).To(Equal(platformv1alpha1.BackendProtocolGRPC))
+
+	protocol, err = applicationBackendProtocol(ctx, c, app, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(protocol).To(Equal(platformv1alpha1.BackendProtocolGRPC))
+
+	other, err := platformv1alpha1.FindBackendProtocol(ctx, c, "project-ns", "application-api", "domain-b")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(other).To(BeEmpty())
+
+	app.Name = "application-worker"
+	protocol, err = applicationBackendProtocol(ctx, c, app, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(protocol).To(Equal(platformv1alpha1.BackendProtocolHTTP1))
+}
+
+func TestBackendProtocolRoutes(t *testing.T) {
+	g := NewWithT(t)
+
+	route := RouteSpec{Namespace: "project-ns", Name: "httproute-1", BackendProtocol: platformv1alpha1.BackendProtocolGRPC}
+	nginx := (&ingressRouteProvider{Provider: config.IngressProviderNginx}).annotations(route, false)
+	g.Expect(nginx).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/backend-protocol", "GRPC"))
+	g.Expect(nginx).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/proxy-read-timeout", upgradedConnectionTimeout))
+
+	route.BackendProtocol = platformv1alpha1.BackendProtocolWebSocket
+	g.Expect(nginxBackendProtocolAnnotations(route)).To(Equal(map[string]string{
+		"nginx.ingress.kubernetes.io/proxy-read-timeout": upgradedConnectionTimeout,
+		"nginx.ingress.kubernetes.io/proxy-send-timeout": upgradedConnectionTimeout,
+	}))
+
+	route.BackendProtocol = platformv1alpha1.BackendProtocolHTTP1
+	g.Expect(nginxBackendProtocolAnnotations(route)).To(BeEmpty())
+}
+
+func TestServiceBackendProtocol(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "service-1", Namespace: "project-ns"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 3000}}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(service).Build()
+	route := RouteSpec{Namespace: "project-ns", ServiceName: "service-1", ServicePort: 3000, BackendProtocol: platformv1alpha1.BackendProtocolGRPC}
+	get := func() *corev1.Service {
+		var current corev1.Service
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(service), &current)).To(Succeed())
+		return &current
+	}
+
+	// Gateway API implementations read the appProtocol of the port
+	g.Expect(ensureServiceBackendProtocol(ctx, c, route, config.IngressProviderGateway)).To(Succeed())
+	g.Expect(get().Spec.Ports[0].AppProtocol).To(HaveValue(Equal("kubernetes.io/h2c")))
+	route.BackendProtocol = platformv1alpha1.BackendProtocolHTTP1
+	g.Expect(ensureServiceBackendProtocol(ctx, c, route, config.IngressProviderGateway)).To(Succeed())
+	g.Expect(get().Spec.Ports[0].AppProtocol).To(BeNil())
+
+	// Traefik reads an annotation
+	route.BackendProtocol = platformv1alpha1.BackendProtocolGRPC
+	g.Expect(ensureServiceBackendProtocol(ctx, c, route, config.IngressProviderTraefik)).To(Succeed())
+	g.Expect(get().Annotations).To(HaveKeyWithValue(traefikServersSchemeAnnotation, "h2c"))
+	route.BackendProtocol = platformv1alpha1.BackendProtocolWebSocket
+	g.Expect(ensureServiceBackendProtocol(ctx, c, route, config.IngressProviderTraefik)).To(Succeed())
+	g.Expect(get().Annotations).NotTo(HaveKey(traefikServersSchemeAnnotation))
+
+	// Routes without a protocol and missing Services are left alone
+	route.BackendProtocol = ""
+	g.Expect(ensureServiceBackendProtocol(ctx, c, route, config.IngressProviderGateway)).To(Succeed())
+	route.ServiceName = "service-2"
+	route.BackendProtocol = platformv1alpha1.BackendProtocolGRPC
+	g.Expect(ensureServiceBackendProtocol(ctx, c, route, config.IngressProviderGateway)).To(Succeed())
+}
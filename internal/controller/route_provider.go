@@ -0,0 +1,593 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	// ingressGatewayName is the Gateway HTTPRoutes attach to when using the Gateway API provider
+	ingressGatewayName = "kibaship-gateway"
+	// ingressGatewayNamespace is the namespace of the kibaship Gateway
+	ingressGatewayNamespace = "kibaship"
+)
+
+// RouteSpec describes public HTTPS routing of a hostname to an application Service
+type RouteSpec struct {
+	// Namespace where the routing resources are created
+	Namespace string
+	// Name is the base name of the routing resources
+	Name string
+	// Hostname is the public hostname to route
+	Hostname string
+	// BaseDomain is the project or application base domain of Hostname, empty when
+	// Hostname lives under the operator domain
+	BaseDomain string
+	// ServiceName and ServicePort identify the backend Service
+	ServiceName string
+	ServicePort int32
+	// Paths route path prefixes to other Services, ServiceName keeps serving every other path
+	Paths []RoutePath
+	// SecurityHeaders and CORS add response headers to every path of the route
+	SecurityHeaders *platformv1alpha1.SecurityHeaders
+	CORS            *platformv1alpha1.CORSPolicy
+	// SessionAffinity pins the clients of ServiceName to one of its replicas with a cookie
+	SessionAffinity *platformv1alpha1.SessionAffinity
+	// BackendProtocol is the protocol ServiceName speaks, empty leaves the Service as it is and
+	// routes HTTP/1.1
+	BackendProtocol platformv1alpha1.BackendProtocol
+	// MaxBodySize limits the size of request bodies, nil keeps the ingress controller default
+	MaxBodySize *resource.Quantity
+	// ProxyReadTimeout and ProxySendTimeout bound how long the ingress waits on the backends of
+	// the route, nil keeps the ingress controller defaults
+	ProxyReadTimeout *metav1.Duration
+	ProxySendTimeout *metav1.Duration
+	// TLSPolicy redirects HTTP to HTTPS, adds HSTS and sets the minimum TLS version of the route,
+	// nil serves HTTP as well with neither
+	TLSPolicy *config.TLSPolicyConfig
+	// TLSSecretName is the Secret in Namespace holding a certificate uploaded for Hostname, empty
+	// when the certificate is issued through ACME
+	TLSSecretName string
+	// Annotations are added to the Ingress of the route by the ingress providers, the annotations
+	// of the platform take precedence
+	Annotations map[string]string
+	// ProjectUUID labels the routing resources and selects the error pages of the project
+	ProjectUUID string
+	// Reconcile updates existing resources to match the spec, otherwise they are only created
+	Reconcile bool
+}
+
+// RoutePath routes a path prefix of a RouteSpec hostname to a Service
+type RoutePath struct {
+	Path        string
+	ServiceName string
+	ServicePort int32
+}
+
+// backends returns the path prefixes of the route and their Services, longest prefix first
+// and ending with the catch-all / of the route Service
+func (r RouteSpec) backends() []RoutePath {
+	backends := make([]RoutePath, 0, len(r.Paths)+1)
+	backends = append(backends, r.Paths...)
+	sort.SliceStable(backends, func(i, j int) bool { return len(backends[i].Path) > len(backends[j].Path) })
+	return append(backends, RoutePath{Path: "/", ServiceName: r.ServiceName, ServicePort: r.ServicePort})
+}
+
+// RouteProvider renders routing resources for the configured ingress implementation.
+// Implementations must be idempotent.
+type RouteProvider interface {
+	// EnsureRoute creates the resources that expose route over HTTPS and apply its TLS policy
+	EnsureRoute(ctx context.Context, route RouteSpec, owner metav1.Object) error
+	// DeleteRoute removes the resources created by EnsureRoute for the route named name
+	DeleteRoute(ctx context.Context, namespace, name string) error
+}
+
+// NewRouteProvider returns the RouteProvider matching the operator ingress configuration
+func NewRouteProvider(c client.Client, scheme *runtime.Scheme, opConfig *OperatorConfig) RouteProvider {
+	if opConfig == nil || opConfig.IngressProvider.UsesGatewayAPI() || opConfig.IngressProvider == "" {
+		return &gatewayRouteProvider{Client: c, Scheme: scheme}
+	}
+
+	return &ingressRouteProvider{
+		Client:    c,
+		Scheme:    scheme,
+		Provider:  opConfig.IngressProvider,
+		ClassName: opConfig.IngressClassName,
+	}
+}
+
+// gatewayRouteProvider routes traffic with Gateway API HTTPRoutes
+type gatewayRouteProvider struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// EnsureRoute creates an HTTPS HTTPRoute and an HTTPRoute on the http listener, redirecting to
+// HTTPS unless the route opts out of the TLS policy
+func (p *gatewayRouteProvider) EnsureRoute(ctx context.Context, route RouteSpec, owner metav1.Object) error {
+	listenerName := "https"
+	switch {
+	case route.TLSSecretName != "":
+		listenerName = certificateListenerName(route.Hostname)
+	case route.BaseDomain != "":
+		listenerName = baseDomainListenerName(route.BaseDomain)
+	}
+
+	if err := ensureServiceBackendProtocol(ctx, p.Client, route, config.IngressProviderGateway); err != nil {
+		return err
+	}
+
+	if err := p.createHTTPRoute(ctx, route, listenerName, owner); err != nil {
+		return fmt.Errorf("failed to create HTTPS HTTPRoute: %w", err)
+	}
+
+	if err := p.createHTTPRedirectRoute(ctx, route, owner); err != nil {
+		return fmt.Errorf("failed to create HTTP listener HTTPRoute: %w", err)
+	}
+
+	return nil
+}
+
+// createHTTPRoute creates an HTTPRoute for HTTPS traffic
+func (p *gatewayRouteProvider) createHTTPRoute(
+	ctx context.Context,
+	route RouteSpec,
+	listenerName string,
+	owner metav1.Object,
+) error {
+	log := ctrl.LoggerFrom(ctx)
+	routeName := route.Name
+
+	// Check if HTTPRoute already exists
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1",
+		Kind:    "HTTPRoute",
+	})
+
+	if err := p.Get(ctx, client.ObjectKey{
+		Namespace: route.Namespace,
+		Name:      routeName,
+	}, obj); err == nil {
+		if !route.Reconcile {
+			log.V(1).Info("HTTPRoute already exists", "name", routeName)
+			return nil // Already exists
+		}
+		spec, err := httpRouteSpec(route, listenerName)
+		if err != nil {
+			return err
+		}
+		obj.Object["spec"] = spec
+		if err := p.Update(ctx, obj); err != nil {
+			return fmt.Errorf("failed to update HTTPRoute: %w", err)
+		}
+		log.V(1).Info("Updated HTTPRoute", "name", routeName, "paths", len(route.Paths))
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	// Create new HTTPRoute
+	obj.SetNamespace(route.Namespace)
+	obj.SetName(routeName)
+
+	// Set labels
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by": "kibaship",
+		"platform.kibaship.com/type":   "httproute",
+	}
+	if route.ProjectUUID != "" {
+		labels[validation.LabelProjectUUID] = route.ProjectUUID
+	}
+	obj.SetLabels(labels)
+
+	// Set owner reference for cleanup
+	if err := ctrl.SetControllerReference(owner, obj, p.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	spec, err := httpRouteSpec(route, listenerName)
+	if err != nil {
+		return err
+	}
+	obj.Object["spec"] = spec
+
+	if err := p.Create(ctx, obj); err != nil {
+		return fmt.Errorf("failed to create HTTPRoute: %w", err)
+	}
+
+	log.Info("Created HTTPRoute", "name", routeName, "hostname", route.Hostname, "service", route.ServiceName, "port", route.ServicePort)
+	return nil
+}
+
+// httpRouteSpec renders the HTTPRoute spec sending each path prefix of route to its Service
+func httpRouteSpec(route RouteSpec, listenerName string) (map[string]any, error) {
+	filters, err := gatewayResponseHeaderFilters(route)
+	if err != nil {
+		return nil, err
+	}
+
+	requestTimeout := gatewayRequestTimeout(route)
+	backends := route.backends()
+	rules := make([]any, 0, len(backends))
+	for _, backend := range backends {
+		rule := map[string]any{
+			"matches": []any{
+				map[string]any{
+					"path": map[string]any{
+						"type":  "PathPrefix",
+						"value": backend.Path,
+					},
+				},
+			},
+			"backendRefs": []any{
+				map[string]any{
+					"name": backend.ServiceName,
+					"port": int64(backend.ServicePort),
+				},
+			},
+		}
+		if len(filters) > 0 {
+			rule["filters"] = filters
+		}
+		if persistence := gatewaySessionPersistence(route); persistence != nil && backend.ServiceName == route.ServiceName {
+			rule["sessionPersistence"] = persistence
+		}
+		if requestTimeout != "" {
+			rule["timeouts"] = map[string]any{"request": requestTimeout}
+		}
+		rules = append(rules, rule)
+	}
+
+	return map[string]any{
+		"parentRefs": []any{
+			map[string]any{
+				"name":        ingressGatewayName,
+				"namespace":   ingressGatewayNamespace,
+				"sectionName": listenerName,
+			},
+		},
+		"hostnames": []any{route.Hostname},
+		"rules":     rules,
+	}, nil
+}
+
+// DeleteRoute deletes the HTTPS and redirect HTTPRoutes of a route
+func (p *gatewayRouteProvider) DeleteRoute(ctx context.Context, namespace, name string) error {
+	for _, routeName := range []string{name, fmt.Sprintf("%s-redirect", name)} {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{
+			Group:   "gateway.networking.k8s.io",
+			Version: "v1",
+			Kind:    "HTTPRoute",
+		})
+		obj.SetNamespace(namespace)
+		obj.SetName(routeName)
+		// The HTTPRoute kind is missing when the Gateway API was never installed, nothing to delete then
+		if err := p.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return fmt.Errorf("failed to delete HTTPRoute %s: %w", routeName, err)
+		}
+	}
+	return nil
+}
+
+// createHTTPRedirectRoute creates the HTTPRoute of the http listener, an HTTP->HTTPS redirect
+// unless the route opts out of the TLS policy
+func (p *gatewayRouteProvider) createHTTPRedirectRoute(
+	ctx context.Context,
+	route RouteSpec,
+	owner metav1.Object,
+) error {
+	log := ctrl.LoggerFrom(ctx)
+	routeName := fmt.Sprintf("%s-redirect", route.Name)
+
+	routeType := "httproute"
+	if route.redirectsHTTP() {
+		routeType = "httproute-redirect"
+	}
+	spec, err := httpListenerRouteSpec(route)
+	if err != nil {
+		return err
+	}
+
+	// Check if HTTPRoute already exists
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1",
+		Kind:    "HTTPRoute",
+	})
+
+	if err := p.Get(ctx, client.ObjectKey{
+		Namespace: route.Namespace,
+		Name:      routeName,
+	}, obj); err == nil {
+		if !route.Reconcile {
+			log.V(1).Info("HTTP listener HTTPRoute already exists", "name", routeName)
+			return nil // Already exists
+		}
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels["platform.kibaship.com/type"] = routeType
+		obj.SetLabels(labels)
+		obj.Object["spec"] = spec
+		if err := p.Update(ctx, obj); err != nil {
+			return fmt.Errorf("failed to update HTTP listener HTTPRoute: %w", err)
+		}
+		log.V(1).Info("Updated HTTP listener HTTPRoute", "name", routeName, "redirect", route.redirectsHTTP())
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	// Create new HTTPRoute for the http listener
+	obj.SetNamespace(route.Namespace)
+	obj.SetName(routeName)
+
+	// Set labels
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by": "kibaship",
+		"platform.kibaship.com/type":   routeType,
+	}
+	obj.SetLabels(labels)
+
+	// Set owner reference for cleanup
+	if err := ctrl.SetControllerReference(owner, obj, p.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	obj.Object["spec"] = spec
+
+	if err := p.Create(ctx, obj); err != nil {
+		return fmt.Errorf("failed to create HTTP listener HTTPRoute: %w", err)
+	}
+
+	log.Info("Created HTTP listener HTTPRoute", "name", routeName, "hostname", route.Hostname, "redirect", route.redirectsHTTP())
+	return nil
+}
+
+// ingressRouteProvider routes traffic with networking.k8s.io Ingress resources
+// for ingress controllers such as ingress-nginx and Traefik
+type ingressRouteProvider struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Provider  config.IngressProvider
+	ClassName string
+}
+
+// EnsureRoute creates an Ingress terminating TLS for the hostname.
+// The certificate is requested by cert-manager's ingress-shim from the platform ClusterIssuer,
+// since Ingress resources cannot reference the wildcard certificate in the kibaship namespace.
+func (p *ingressRouteProvider) EnsureRoute(ctx context.Context, route RouteSpec, owner metav1.Object) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	existing := &networkingv1.Ingress{}
+	if err := p.Get(ctx, client.ObjectKey{Namespace: route.Namespace, Name: route.Name}, existing); err == nil {
+		if !route.Reconcile {
+			log.V(1).Info("Ingress already exists", "name", route.Name)
+			return nil // Already exists
+		}
+		if err := p.ensureResponseHeaders(ctx, route, owner); err != nil {
+			return err
+		}
+		if err := p.ensureTLSOption(ctx, route, owner); err != nil {
+			return err
+		}
+		if p.Provider == config.IngressProviderTraefik {
+			if err := p.ensureServiceSessionAffinity(ctx, route); err != nil {
+				return err
+			}
+		}
+		if err := ensureServiceBackendProtocol(ctx, p.Client, route, p.Provider); err != nil {
+			return err
+		}
+		errorPages, err := hasErrorPages(ctx, p.Client, route.Namespace, route.ProjectUUID)
+		if err != nil {
+			return err
+		}
+		existing.Annotations = p.annotations(route, errorPages)
+		if route.ProjectUUID != "" {
+			if existing.Labels == nil {
+				existing.Labels = map[string]string{}
+			}
+			existing.Labels[validation.LabelProjectUUID] = route.ProjectUUID
+		}
+		existing.Spec.TLS = ingressTLS(route)
+		existing.Spec.Rules = ingressRules(route)
+		if err := p.Update(ctx, existing); err != nil {
+			return fmt.Errorf("failed to update Ingress: %w", err)
+		}
+		log.V(1).Info("Updated Ingress", "name", route.Name, "paths", len(route.Paths))
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	if err := p.ensureResponseHeaders(ctx, route, owner); err != nil {
+		return err
+	}
+	if err := p.ensureTLSOption(ctx, route, owner); err != nil {
+		return err
+	}
+	if p.Provider == config.IngressProviderTraefik {
+		if err := p.ensureServiceSessionAffinity(ctx, route); err != nil {
+			return err
+		}
+	}
+	if err := ensureServiceBackendProtocol(ctx, p.Client, route, p.Provider); err != nil {
+		return err
+	}
+	errorPages, err := hasErrorPages(ctx, p.Client, route.Namespace, route.ProjectUUID)
+	if err != nil {
+		return err
+	}
+
+	className := p.ClassName
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by": "kibaship",
+		"platform.kibaship.com/type":   "ingress",
+	}
+	if route.ProjectUUID != "" {
+		labels[validation.LabelProjectUUID] = route.ProjectUUID
+	}
+
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        route.Name,
+			Namespace:   route.Namespace,
+			Labels:      labels,
+			Annotations: p.annotations(route, errorPages),
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: &className,
+			TLS:              ingressTLS(route),
+			Rules:            ingressRules(route),
+		},
+	}
+
+	// Set owner reference for cleanup
+	if err := ctrl.SetControllerReference(owner, ingress, p.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	if err := p.Create(ctx, ingress); err != nil {
+		return fmt.Errorf("failed to create Ingress: %w", err)
+	}
+
+	log.Info("Created Ingress", "name", route.Name, "hostname", route.Hostname, "service", route.ServiceName,
+		"port", route.ServicePort, "provider", p.Provider, "ingressClass", className)
+	return nil
+}
+
+// ingressTLS terminates TLS for the hostname of route with its uploaded certificate, or the
+// certificate cert-manager issues into tls-<name>
+func ingressTLS(route RouteSpec) []networkingv1.IngressTLS {
+	secretName := route.TLSSecretName
+	if secretName == "" {
+		secretName = fmt.Sprintf("tls-%s", route.Name)
+	}
+	return []networkingv1.IngressTLS{{Hosts: []string{route.Hostname}, SecretName: secretName}}
+}
+
+// ingressRules renders the Ingress rule sending each path prefix of route to its Service
+func ingressRules(route RouteSpec) []networkingv1.IngressRule {
+	pathType := networkingv1.PathTypePrefix
+	backends := route.backends()
+	paths := make([]networkingv1.HTTPIngressPath, 0, len(backends))
+	for _, backend := range backends {
+		paths = append(paths, networkingv1.HTTPIngressPath{
+			Path:     backend.Path,
+			PathType: &pathType,
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: backend.ServiceName,
+					Port: networkingv1.ServiceBackendPort{Number: backend.ServicePort},
+				},
+			},
+		})
+	}
+
+	return []networkingv1.IngressRule{
+		{
+			Host: route.Hostname,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{Paths: paths},
+			},
+		},
+	}
+}
+
+// DeleteRoute deletes the Ingress of a route and its Traefik headers Middleware and TLSOption
+func (p *ingressRouteProvider) DeleteRoute(ctx context.Context, namespace, name string) error {
+	ingress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if err := p.Delete(ctx, ingress); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Ingress %s: %w", name, err)
+	}
+	if p.Provider == config.IngressProviderTraefik {
+		if err := deleteTraefikHeadersMiddleware(ctx, p.Client, namespace, name); err != nil {
+			return err
+		}
+		return deleteTraefikTLSOption(ctx, p.Client, namespace, name)
+	}
+	return nil
+}
+
+// annotations returns the controller specific annotations enabling TLS, the TLS policy and
+// response headers of route and the error pages of its project
+func (p *ingressRouteProvider) annotations(route RouteSpec, errorPages bool) map[string]string {
+	annotations := map[string]string{}
+	for key, value := range route.Annotations {
+		annotations[key] = value
+	}
+	if route.TLSSecretName == "" {
+		annotations["cert-manager.io/cluster-issuer"] = clusterIssuerName
+	}
+
+	switch p.Provider {
+	case config.IngressProviderNginx:
+		for key, value := range nginxTLSAnnotations(route) {
+			annotations[key] = value
+		}
+		for key, value := range nginxResponseHeaderAnnotations(route) {
+			annotations[key] = value
+		}
+		for key, value := range nginxSessionAffinityAnnotations(route) {
+			annotations[key] = value
+		}
+		for key, value := range nginxBackendProtocolAnnotations(route) {
+			annotations[key] = value
+		}
+		for key, value := range nginxProxyAnnotations(route) {
+			annotations[key] = value
+		}
+	case config.IngressProviderTraefik:
+		// Traefik redirects HTTP to HTTPS on the web entrypoint through its static configuration,
+		// the redirect of the TLS policy cannot be changed per route
+		annotations["traefik.ingress.kubernetes.io/router.entrypoints"] = "websecure"
+		annotations["traefik.ingress.kubernetes.io/router.tls"] = TrueString
+		if route.requiresTLS13() {
+			annotations["traefik.ingress.kubernetes.io/router.tls.options"] = traefikTLSOptionRef(route.Namespace, route.Name)
+		}
+		if route.hasResponseHeaders() {
+			annotations["traefik.ingress.kubernetes.io/router.middlewares"] = traefikMiddlewareRef(route.Namespace, route.Name)
+		}
+	}
+	setErrorPagesAnnotations(annotations, p.Provider, route.Namespace, route.ProjectUUID, errorPages)
+
+	return annotations
+}
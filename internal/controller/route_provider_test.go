@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func TestIngressRouteAnnotations(t *testing.T) {
+	g := NewWithT(t)
+
+	route := RouteSpec{
+		Namespace: "project-ns",
+		Name:      "httproute-1",
+		Annotations: map[string]string{
+			"nginx.ingress.kubernetes.io/proxy-body-size":  "64m",
+			"nginx.ingress.kubernetes.io/affinity":         "cookie",
+			"nginx.ingress.kubernetes.io/ssl-redirect":     TrueString,
+			"traefik.ingress.kubernetes.io/router.tls":     "false",
+			"traefik.ingress.kubernetes.io/router.service": "other",
+		},
+	}
+
+	// The annotations of the domain are kept, the ones the platform sets win
+	nginx := (&ingressRouteProvider{Provider: config.IngressProviderNginx}).annotations(route, false)
+	g.Expect(nginx).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/proxy-body-size", "64m"))
+	g.Expect(nginx).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/affinity", "cookie"))
+	g.Expect(nginx).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/ssl-redirect", "false"))
+	g.Expect(nginx).To(HaveKeyWithValue("cert-manager.io/cluster-issuer", clusterIssuerName))
+
+	traefik := (&ingressRouteProvider{Provider: config.IngressProviderTraefik}).annotations(route, false)
+	g.Expect(traefik).To(HaveKeyWithValue("traefik.ingress.kubernetes.io/router.tls", TrueString))
+	g.Expect(traefik).To(HaveKeyWithValue("traefik.ingress.kubernetes.io/router.service", "other"))
+
+	// The route keeps its own copy of the annotations
+	g.Expect(route.Annotations).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/ssl-redirect", TrueString))
+}
+
+func TestValidateIngressAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     string
+	}{
+		{name: "none"},
+		{name: "allowed prefixes", annotations: map[string]string{
+			"nginx.ingress.kubernetes.io/proxy-read-timeout":      "120",
+			"traefik.ingress.kubernetes.io/service.sticky.cookie": TrueString,
+			"nginx.ingress.kubernetes.io/session-cookie-name":     "route",
+			"traefik.ingress.kubernetes.io/router.middlewares":    "project-ns-auth@kubernetescrd",
+			"nginx.ingress.kubernetes.io/proxy-body-size":         "",
+			"nginx.ingress.kubernetes.io/upstream-hash-by":        "$remote_addr",
+			"traefik.ingress.kubernetes.io/service.serversscheme": "h2c",
+		}},
+		{name: "other prefix", annotations: map[string]string{"cert-manager.io/cluster-issuer": "other"}, wantErr: "must start with one of"},
+		{name: "bare prefix", annotations: map[string]string{"nginx.ingress.kubernetes.io/": "x"}, wantErr: "must start with one of"},
+		{name: "server snippet", annotations: map[string]string{"nginx.ingress.kubernetes.io/server-snippet": "return 200;"}, wantErr: "snippets inject raw ingress configuration"},
+		{name: "configuration snippet", annotations: map[string]string{"nginx.ingress.kubernetes.io/configuration-snippet": "more_set_headers"}, wantErr: "snippets inject raw ingress configuration"},
+		{name: "invalid name", annotations: map[string]string{"nginx.ingress.kubernetes.io/proxy body": "1"}, wantErr: "is not a valid annotation name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := platformv1alpha1.ValidateIngressAnnotations(tt.annotations)
+			if tt.wantErr == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
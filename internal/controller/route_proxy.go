@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nginxProxyAnnotations renders the request body size limit and proxy timeouts of route as
+// ingress-nginx annotations, they take precedence over the timeouts of its backend protocol
+func nginxProxyAnnotations(route RouteSpec) map[string]string {
+	annotations := map[string]string{}
+	if route.MaxBodySize != nil {
+		annotations["nginx.ingress.kubernetes.io/proxy-body-size"] = strconv.FormatInt(route.MaxBodySize.Value(), 10)
+	}
+	if route.ProxyReadTimeout != nil {
+		annotations["nginx.ingress.kubernetes.io/proxy-read-timeout"] = strconv.Itoa(int(route.ProxyReadTimeout.Seconds()))
+	}
+	if route.ProxySendTimeout != nil {
+		annotations["nginx.ingress.kubernetes.io/proxy-send-timeout"] = strconv.Itoa(int(route.ProxySendTimeout.Seconds()))
+	}
+	return annotations
+}
+
+// gatewayRequestTimeout returns the HTTPRoute request timeout of route, the longest of its proxy
+// timeouts as Gateway API only bounds whole requests, empty when neither is set
+func gatewayRequestTimeout(route RouteSpec) string {
+	var seconds int32
+	for _, timeout := range []*metav1.Duration{route.ProxyReadTimeout, route.ProxySendTimeout} {
+		if timeout != nil && int32(timeout.Seconds()) > seconds {
+			seconds = int32(timeout.Seconds())
+		}
+	}
+	if seconds == 0 {
+		return ""
+	}
+	return gatewayDuration(seconds)
+}
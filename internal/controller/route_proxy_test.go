@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func TestProxySettingsRoutes(t *testing.T) {
+	g := NewWithT(t)
+
+	maxBodySize := resource.MustParse("64Mi")
+	route := RouteSpec{
+		Namespace:        "project-ns",
+		Name:             "httproute-1",
+		Hostname:         "app.example.com",
+		ServiceName:      "service-1",
+		ServicePort:      3000,
+		Paths:            []RoutePath{{Path: "/api", ServiceName: "service-2", ServicePort: 8080}},
+		BackendProtocol:  platformv1alpha1.BackendProtocolWebSocket,
+		Annotations:      map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "1m"},
+		MaxBodySize:      &maxBodySize,
+		ProxyReadTimeout: &metav1.Duration{Duration: 5 * time.Minute},
+	}
+
+	// The proxy settings win over the backend protocol timeouts and the domain annotations
+	nginx := (&ingressRouteProvider{Provider: config.IngressProviderNginx}).annotations(route, false)
+	g.Expect(nginx).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/proxy-body-size", "67108864"))
+	g.Expect(nginx).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/proxy-read-timeout", "300"))
+	g.Expect(nginx).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/proxy-send-timeout", upgradedConnectionTimeout))
+
+	// Every rule of the HTTPRoute times out with the longest proxy timeout
+	route.ProxySendTimeout = &metav1.Duration{Duration: 90 * time.Minute}
+	spec, err := httpRouteSpec(route, "https")
+	g.Expect(err).NotTo(HaveOccurred())
+	rules := spec["rules"].([]any)
+	g.Expect(rules).To(HaveLen(2))
+	for _, rule := range rules {
+		g.Expect(rule).To(HaveKeyWithValue("timeouts", map[string]any{"request": "1h30m"}))
+	}
+
+	route.MaxBodySize, route.ProxyReadTimeout, route.ProxySendTimeout = nil, nil, nil
+	g.Expect(nginxProxyAnnotations(route)).To(BeEmpty())
+	g.Expect(gatewayRequestTimeout(route)).To(BeEmpty())
+}
+
+func TestValidateProxySettings(t *testing.T) {
+	quantity := func(value string) *resource.Quantity {
+		q := resource.MustParse(value)
+		return &q
+	}
+	duration := func(d time.Duration) *metav1.Duration {
+		return &metav1.Duration{Duration: d}
+	}
+
+	tests := []struct {
+		name        string
+		maxBodySize *resource.Quantity
+		readTimeout *metav1.Duration
+		sendTimeout *metav1.Duration
+		wantErr     string
+	}{
+		{name: "none"},
+		{name: "within bounds", maxBodySize: quantity("512Mi"), readTimeout: duration(time.Minute), sendTimeout: duration(time.Hour)},
+		{name: "body too small", maxBodySize: quantity("100"), wantErr: "maxBodySize must be between 1Ki and 5Gi"},
+		{name: "body too large", maxBodySize: quantity("6Gi"), wantErr: "maxBodySize must be between 1Ki and 5Gi"},
+		{name: "zero read timeout", readTimeout: duration(0), wantErr: "proxyReadTimeout must be between 1s and 1h"},
+		{name: "send timeout too long", sendTimeout: duration(2 * time.Hour), wantErr: "proxySendTimeout must be between 1s and 1h"},
+		{name: "fractional seconds", readTimeout: duration(1500 * time.Millisecond), wantErr: "proxyReadTimeout must be a whole number of seconds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := platformv1alpha1.ValidateProxySettings(tt.maxBodySize, tt.readTimeout, tt.sendTimeout)
+			if tt.wantErr == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// traefikTLSOptionGVK is the Traefik TLSOption kind carrying the minimum TLS version of a route
+var traefikTLSOptionGVK = schema.GroupVersionKind{Group: "traefik.io", Version: "v1alpha1", Kind: "TLSOption"}
+
+var (
+	// tlsPolicy holds the TLS policy of the operator configuration
+	tlsPolicy atomic.Pointer[config.TLSPolicyConfig]
+
+	// tlsPolicyChanged wakes the ApplicationDomain controller up to re-render every domain route
+	// when the TLS policy changes at runtime
+	tlsPolicyChanged = make(chan event.GenericEvent, 1)
+)
+
+// SetTLSPolicy replaces the TLS policy applied to routes. It is called at startup and whenever
+// the operator ConfigMap changes, a change re-renders the routes of every ApplicationDomain.
+func SetTLSPolicy(cfg config.TLSPolicyConfig) {
+	previous := tlsPolicy.Swap(&cfg)
+	if previous == nil || *previous == cfg {
+		return
+	}
+
+	select {
+	case tlsPolicyChanged <- event.GenericEvent{Object: &platformv1alpha1.ApplicationDomain{}}:
+	default: // A re-render is already pending
+	}
+}
+
+// routeTLSPolicy returns the TLS policy of a route, nil when the route opts out of it
+func routeTLSPolicy(ignore bool) *config.TLSPolicyConfig {
+	if ignore {
+		return nil
+	}
+	cfg := config.DefaultTLSPolicyConfig()
+	if current := tlsPolicy.Load(); current != nil {
+		cfg = *current
+	}
+	return &cfg
+}
+
+// redirectsHTTP reports whether plain HTTP requests to the route are redirected to HTTPS
+func (r RouteSpec) redirectsHTTP() bool {
+	return r.TLSPolicy != nil && r.TLSPolicy.RedirectHTTP
+}
+
+// requiresTLS13 reports whether the route only accepts TLS 1.3 clients
+func (r RouteSpec) requiresTLS13() bool {
+	return r.TLSPolicy != nil && r.TLSPolicy.MinVersion == config.TLSVersion13
+}
+
+// securityHeaders returns the security headers of the route, with the HSTS header of the TLS
+// policy unless the route sets its own
+func (r RouteSpec) securityHeaders() *platformv1alpha1.SecurityHeaders {
+	if r.TLSPolicy == nil || !r.TLSPolicy.HSTSEnabled() ||
+		(r.SecurityHeaders != nil && r.SecurityHeaders.HSTSMaxAgeSeconds > 0) {
+		return r.SecurityHeaders
+	}
+
+	headers := &platformv1alpha1.SecurityHeaders{}
+	if r.SecurityHeaders != nil {
+		headers = r.SecurityHeaders.DeepCopy()
+	}
+	headers.HSTSMaxAgeSeconds = r.TLSPolicy.HSTSMaxAgeSeconds
+	headers.HSTSIncludeSubdomains = r.TLSPolicy.HSTSIncludeSubdomains
+	headers.HSTSPreload = r.TLSPolicy.HSTSPreload
+	return headers
+}
+
+// nginxTLSAnnotations renders the HTTP->HTTPS redirect and minimum TLS version of route as
+// ingress-nginx annotations. The TLS version uses a server snippet, which the controller must allow.
+func nginxTLSAnnotations(route RouteSpec) map[string]string {
+	redirect := strconv.FormatBool(route.redirectsHTTP())
+	annotations := map[string]string{
+		"nginx.ingress.kubernetes.io/ssl-redirect":       redirect,
+		"nginx.ingress.kubernetes.io/force-ssl-redirect": redirect,
+	}
+	if route.requiresTLS13() {
+		annotations["nginx.ingress.kubernetes.io/server-snippet"] = "ssl_protocols TLSv1.3;"
+	}
+	return annotations
+}
+
+// ensureTLSOption keeps the Traefik TLSOption of route in sync. Only TLS 1.3 routes need one,
+// TLS 1.2 is the Traefik default.
+func (p *ingressRouteProvider) ensureTLSOption(ctx context.Context, route RouteSpec, owner metav1.Object) error {
+	if p.Provider != config.IngressProviderTraefik {
+		return nil
+	}
+	if !route.requiresTLS13() {
+		return deleteTraefikTLSOption(ctx, p.Client, route.Namespace, route.Name)
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(traefikTLSOptionGVK)
+	obj.SetNamespace(route.Namespace)
+	obj.SetName(traefikTLSOptionName(route.Name))
+
+	result, err := controllerutil.CreateOrUpdate(ctx, p.Client, obj, func() error {
+		obj.SetLabels(map[string]string{
+			"app.kubernetes.io/managed-by": "kibaship",
+			"platform.kibaship.com/type":   "tlsoption",
+		})
+		obj.Object["spec"] = map[string]any{"minVersion": "VersionTLS13"}
+		return ctrl.SetControllerReference(owner, obj, p.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ensure TLSOption: %w", err)
+	}
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Ensured TLSOption", "name", obj.GetName(), "result", result)
+	return nil
+}
+
+// deleteTraefikTLSOption removes the TLSOption of the route named name
+func deleteTraefikTLSOption(ctx context.Context, c client.Client, namespace, name string) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(traefikTLSOptionGVK)
+	obj.SetNamespace(namespace)
+	obj.SetName(traefikTLSOptionName(name))
+
+	// The TLSOption kind is missing when Traefik CRDs were never installed, nothing to delete then
+	if err := c.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to delete TLSOption: %w", err)
+	}
+	return nil
+}
+
+// traefikTLSOptionName is the name of the TLSOption of the route named name
+func traefikTLSOptionName(name string) string {
+	return fmt.Sprintf("%s-tls", name)
+}
+
+// traefikTLSOptionRef references the TLSOption of a route from an Ingress annotation
+func traefikTLSOptionRef(namespace, name string) string {
+	return fmt.Sprintf("%s-%s@kubernetescrd", namespace, traefikTLSOptionName(name))
+}
+
+// httpListenerRouteSpec renders the HTTPRoute of the http listener of the Gateway: a redirect
+// to HTTPS, or the backends of route when it does not redirect HTTP
+func httpListenerRouteSpec(route RouteSpec) (map[string]any, error) {
+	if !route.redirectsHTTP() {
+		return httpRouteSpec(route, "http")
+	}
+
+	return map[string]any{
+		"parentRefs": []any{
+			map[string]any{
+				"name":        ingressGatewayName,
+				"namespace":   ingressGatewayNamespace,
+				"sectionName": "http",
+			},
+		},
+		"hostnames": []any{route.Hostname},
+		"rules": []any{
+			map[string]any{
+				"matches": []any{
+					map[string]any{
+						"path": map[string]any{
+							"type":  "PathPrefix",
+							"value": "/",
+						},
+					},
+				},
+				"filters": []any{
+					map[string]any{
+						"type": "RequestRedirect",
+						"requestRedirect": map[string]any{
+							"scheme": "https",
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// tlsPolicyDomainRequests maps a TLS policy change to every ApplicationDomain of this shard
+func (r *ApplicationDomainReconciler) tlsPolicyDomainRequests(ctx context.Context, _ client.Object) []ctrl.Request {
+	var domains platformv1alpha1.ApplicationDomainList
+	if err := r.List(ctx, &domains); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Failed to list ApplicationDomains for TLS policy change")
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(domains.Items))
+	for _, domain := range domains.Items {
+		if owned, err := ownsNamespace(ctx, r.Client, domain.Namespace); err != nil || !owned {
+			continue
+		}
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&domain)})
+	}
+	return requests
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func TestRouteTLSPolicy(t *testing.T) {
+	g := NewWithT(t)
+	t.Cleanup(func() { tlsPolicy.Store(nil) })
+
+	g.Expect(routeTLSPolicy(true)).To(BeNil())
+	g.Expect(*routeTLSPolicy(false)).To(Equal(config.DefaultTLSPolicyConfig()))
+
+	// Only runtime changes re-render the domain routes
+	SetTLSPolicy(config.DefaultTLSPolicyConfig())
+	g.Expect(tlsPolicyChanged).To(BeEmpty())
+	policy := config.TLSPolicyConfig{MinVersion: config.TLSVersion13, RedirectHTTP: true, HSTSMaxAgeSeconds: 600}
+	SetTLSPolicy(policy)
+	SetTLSPolicy(config.DefaultTLSPolicyConfig())
+	g.Expect(tlsPolicyChanged).To(HaveLen(1))
+	<-tlsPolicyChanged
+
+	route := RouteSpec{Namespace: "project-ns", Name: "httproute-1", TLSPolicy: &policy}
+	g.Expect(route.securityHeaders().ResponseHeaders()).To(Equal(map[string]string{"Strict-Transport-Security": "max-age=600"}))
+	g.Expect(route.hasResponseHeaders()).To(BeTrue())
+	g.Expect(nginxTLSAnnotations(route)).To(Equal(map[string]string{
+		"nginx.ingress.kubernetes.io/ssl-redirect":       TrueString,
+		"nginx.ingress.kubernetes.io/force-ssl-redirect": TrueString,
+		"nginx.ingress.kubernetes.io/server-snippet":     "ssl_protocols TLSv1.3;",
+	}))
+
+	// HSTS of the domain wins over the policy
+	route.SecurityHeaders = &platformv1alpha1.SecurityHeaders{HSTSMaxAgeSeconds: 60, ContentTypeNosniff: true}
+	g.Expect(route.securityHeaders()).To(BeIdenticalTo(route.SecurityHeaders))
+
+	// Opted out routes serve HTTP without HSTS
+	route = RouteSpec{Namespace: "project-ns", Name: "httproute-1"}
+	g.Expect(route.hasResponseHeaders()).To(BeFalse())
+	g.Expect(nginxTLSAnnotations(route)).To(Equal(map[string]string{
+		"nginx.ingress.kubernetes.io/ssl-redirect":       "false",
+		"nginx.ingress.kubernetes.io/force-ssl-redirect": "false",
+	}))
+}
+
+func TestGatewayRouteTLSPolicy(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	provider := NewRouteProvider(cl, scheme, &OperatorConfig{IngressProvider: config.IngressProviderGateway})
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "project-ns", UID: "owner-uid"}}
+
+	policy := config.DefaultTLSPolicyConfig()
+	route := RouteSpec{
+		Namespace:   "project-ns",
+		Name:        "httproute-1",
+		Hostname:    "app.example.com",
+		ServiceName: "service-1",
+		ServicePort: 3000,
+		TLSPolicy:   &policy,
+		Reconcile:   true,
+	}
+	httpRoute := func() *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"})
+		g.Expect(cl.Get(ctx, client.ObjectKey{Namespace: "project-ns", Name: "httproute-1-redirect"}, obj)).To(Succeed())
+		return obj
+	}
+
+	g.Expect(provider.EnsureRoute(ctx, route, owner)).To(Succeed())
+	redirect := httpRoute()
+	g.Expect(redirect.GetLabels()["platform.kibaship.com/type"]).To(Equal("httproute-redirect"))
+	rules, _, _ := unstructured.NestedSlice(redirect.Object, "spec", "rules")
+	g.Expect(rules[0]).To(HaveKeyWithValue("filters", ContainElement(HaveKeyWithValue("type", "RequestRedirect"))))
+
+	// Opting out serves the backends on the http listener
+	route.TLSPolicy = nil
+	g.Expect(provider.EnsureRoute(ctx, route, owner)).To(Succeed())
+	served := httpRoute()
+	g.Expect(served.GetLabels()["platform.kibaship.com/type"]).To(Equal("httproute"))
+	rules, _, _ = unstructured.NestedSlice(served.Object, "spec", "rules")
+	g.Expect(rules[0]).To(HaveKey("backendRefs"))
+	g.Expect(rules[0]).NotTo(HaveKey("filters"))
+}
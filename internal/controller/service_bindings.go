@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/dbaccess"
+)
+
+// serviceBindingEnv returns the connection variables of the service bindings of an application.
+// Bindings to applications that no longer exist or are no longer shared are skipped, the
+// credentials of their users are gone.
+func serviceBindingEnv(ctx context.Context, c client.Reader, app *platformv1alpha1.Application) ([]corev1.EnvVar, error) {
+	var env []corev1.EnvVar
+	for _, binding := range app.Spec.ServiceBindings {
+		var shared platformv1alpha1.Application
+		if err := c.Get(ctx, client.ObjectKey{Name: binding.Application.Name, Namespace: app.Namespace}, &shared); err != nil {
+			if errors.IsNotFound(err) {
+				logf.FromContext(ctx).Info("Skipping service binding to a missing application", "application", binding.Application.Name)
+				continue
+			}
+			return nil, fmt.Errorf("failed to get bound application %s: %w", binding.Application.Name, err)
+		}
+
+		conn, ok := dbaccess.ConnectionFor(&shared)
+		if !shared.Spec.Shared || !ok || !conn.SupportsAccess() {
+			logf.FromContext(ctx).Info("Skipping service binding to an application that is not a shared database", "application", shared.Name)
+			continue
+		}
+		env = append(env, dbaccess.BindingEnv(conn, shared.Namespace, shared.GetUUID(), app.GetUUID(), binding)...)
+	}
+	return env, nil
+}
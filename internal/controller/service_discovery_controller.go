@@ -0,0 +1,252 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ServiceDiscoveryReconciler lets the applications of an environment call each other. Every web
+// application gets a Service alias following its promoted deployment, and the environment gets a
+// ConfigMap with the <SLUG>_HOST and <SLUG>_PORT variables of its applications, which the
+// containers of its deployments load.
+type ServiceDiscoveryReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Clock times the drain of promotions, the real clock when nil
+	Clock clock.PassiveClock
+}
+
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=environments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applications,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+
+// Reconcile updates the Service aliases and the service discovery ConfigMap of an environment
+func (r *ServiceDiscoveryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var environment platformv1alpha1.Environment
+	if err := r.Get(ctx, req.NamespacedName, &environment); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !environment.DeletionTimestamp.IsZero() || environment.GetUUID() == "" {
+		return ctrl.Result{}, nil
+	}
+
+	var apps platformv1alpha1.ApplicationList
+	if err := r.List(ctx, &apps, client.InNamespace(environment.Namespace),
+		client.MatchingLabels{validation.LabelEnvironmentUUID: environment.GetUUID()}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	data := map[string]string{}
+	var requeueAfter time.Duration
+	for i := range apps.Items {
+		app := &apps.Items[i]
+		slug := app.GetSlug()
+		if !app.DeletionTimestamp.IsZero() || slug == "" || !servesTraffic(app) {
+			continue
+		}
+
+		port := applicationPort(app)
+		prefix := utils.GetServiceDiscoveryEnvPrefix(slug)
+		data[prefix+"_HOST"] = utils.GetServiceAliasHost(slug, app.Namespace)
+		data[prefix+"_PORT"] = strconv.Itoa(int(port))
+
+		next, err := r.ensureServiceAlias(ctx, app, port)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if next > 0 && (requeueAfter == 0 || next < requeueAfter) {
+			requeueAfter = next
+		}
+	}
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      utils.GetServiceDiscoveryConfigMapName(environment.GetUUID()),
+		Namespace: environment.Namespace,
+	}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		configMap.Labels = map[string]string{
+			"app.kubernetes.io/managed-by":  "kibaship",
+			validation.LabelEnvironmentUUID: environment.GetUUID(),
+		}
+		configMap.Data = data
+		return controllerutil.SetControllerReference(&environment, configMap, r.Scheme)
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update service discovery ConfigMap: %w", err)
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Info("Updated service discovery", "environment", environment.Name, "applications", len(data)/2)
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// ensureServiceAlias points the Service alias of an application at the pods of its promoted
+// deployment, draining the previous one, and returns when a promotion in progress moves on.
+// Applications without a promoted deployment get no alias yet, they have nothing to serve.
+func (r *ServiceDiscoveryReconciler) ensureServiceAlias(ctx context.Context, app *platformv1alpha1.Application, port int32) (time.Duration, error) {
+	if app.Spec.CurrentDeploymentRef == nil {
+		return 0, nil
+	}
+	var current platformv1alpha1.Deployment
+	if err := r.Get(ctx, client.ObjectKey{Name: app.Spec.CurrentDeploymentRef.Name, Namespace: app.Namespace}, &current); err != nil {
+		if errors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get current deployment of %s: %w", app.Name, err)
+	}
+
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:      utils.GetServiceAliasName(app.GetSlug()),
+		Namespace: app.Namespace,
+	}}
+	var existing *corev1.Service
+	if err := r.Get(ctx, client.ObjectKeyFromObject(service), service); err == nil {
+		existing = service.DeepCopy()
+	} else if !errors.IsNotFound(err) {
+		return 0, fmt.Errorf("failed to get Service alias of %s: %w", app.Name, err)
+	}
+	step, err := r.nextPromotionStep(ctx, app, current.GetUUID(), existing)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+		service.Labels = map[string]string{
+			"app.kubernetes.io/managed-by":  "kibaship",
+			"app.kubernetes.io/component":   "service-alias",
+			validation.LabelApplicationUUID: app.GetUUID(),
+			validation.LabelEnvironmentUUID: app.Labels[validation.LabelEnvironmentUUID],
+		}
+		delete(service.Annotations, annotationPromotingDeployment)
+		delete(service.Annotations, annotationDrainStartedAt)
+		for key, value := range step.Annotations {
+			metav1.SetMetaDataAnnotation(&service.ObjectMeta, key, value)
+		}
+		service.Spec.Type = corev1.ServiceTypeClusterIP
+		service.Spec.Selector = step.Selector
+		service.Spec.Ports = []corev1.ServicePort{{
+			Name:       "http",
+			Protocol:   corev1.ProtocolTCP,
+			Port:       port,
+			TargetPort: intstr.FromInt32(deploymentPort(app, &current)),
+		}}
+		return controllerutil.SetControllerReference(app, service, r.Scheme)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to update Service alias of %s: %w", app.Name, err)
+	}
+	return step.RequeueAfter, nil
+}
+
+// servesTraffic reports whether an application runs Kubernetes Deployments serving its port
+func servesTraffic(app *platformv1alpha1.Application) bool {
+	switch app.Spec.Type {
+	case platformv1alpha1.ApplicationTypeGitRepository,
+		platformv1alpha1.ApplicationTypeDockerImage,
+		platformv1alpha1.ApplicationTypeImageFromRegistry:
+		return true
+	}
+	return false
+}
+
+// applicationPort returns the port an application listens on
+func applicationPort(app *platformv1alpha1.Application) int32 {
+	if app.Spec.Port == 0 {
+		return 3000
+	}
+	return app.Spec.Port
+}
+
+// deploymentPort returns the port the containers of a deployment listen on: the port of its
+// application, else the port its build detected, else 3000
+func deploymentPort(app *platformv1alpha1.Application, deployment *platformv1alpha1.Deployment) int32 {
+	if app.Spec.Port == 0 && deployment.Status.DetectedPort != 0 {
+		return deployment.Status.DetectedPort
+	}
+	return applicationPort(app)
+}
+
+// serviceDiscoveryEnvFrom loads the service discovery variables of the environment of an
+// application. It comes first, so the env secret of the application overrides them.
+func serviceDiscoveryEnvFrom(app *platformv1alpha1.Application) []corev1.EnvFromSource {
+	environmentUUID := app.Labels[validation.LabelEnvironmentUUID]
+	if environmentUUID == "" {
+		return nil
+	}
+	optional := true
+	return []corev1.EnvFromSource{
+		{
+			ConfigMapRef: &corev1.ConfigMapEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: utils.GetServiceDiscoveryConfigMapName(environmentUUID),
+				},
+				Optional: &optional,
+			},
+		},
+	}
+}
+
+// applicationToEnvironment maps an application to its environment
+func applicationToEnvironment(_ context.Context, obj client.Object) []ctrl.Request {
+	app, ok := obj.(*platformv1alpha1.Application)
+	if !ok || app.Spec.EnvironmentRef.Name == "" {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: client.ObjectKey{Name: app.Spec.EnvironmentRef.Name, Namespace: app.Namespace}}}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Applications are watched for spec changes, which include promotions, and for deletions.
+func (r *ServiceDiscoveryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&platformv1alpha1.Environment{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&platformv1alpha1.Application{}, handler.EnqueueRequestsFromMapFunc(applicationToEnvironment),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&corev1.ConfigMap{}).
+		WithOptions(controllerOptions("service-discovery", 1)).
+		Named("service-discovery").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
+		Complete(r)
+}
@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestServiceDiscoveryReconcile(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	const (
+		namespace       = "project-shop"
+		environmentUUID = "5e6f7a8b-9c0d-4e1f-8a2b-3c4d5e6f7a8b"
+		webUUID         = "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+		deploymentUUID  = "7f6e5d4c-3b2a-4190-8f7e-6d5c4b3a2f1e"
+	)
+	environment := &platformv1alpha1.Environment{ObjectMeta: metav1.ObjectMeta{
+		Name:      utils.GetEnvironmentResourceName(environmentUUID),
+		Namespace: namespace,
+		Labels:    map[string]string{validation.LabelResourceUUID: environmentUUID},
+	}}
+	application := func(name, slug string, appType platformv1alpha1.ApplicationType) *platformv1alpha1.Application {
+		return &platformv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					validation.LabelResourceSlug:    slug,
+					validation.LabelEnvironmentUUID: environmentUUID,
+				},
+			},
+			Spec: platformv1alpha1.ApplicationSpec{
+				EnvironmentRef: corev1.LocalObjectReference{Name: environment.Name},
+				Type:           appType,
+			},
+		}
+	}
+	web := application(utils.GetApplicationResourceName(webUUID), "web12345", platformv1alpha1.ApplicationTypeGitRepository)
+	web.Labels[validation.LabelResourceUUID] = webUUID
+	web.Spec.Port = 8080
+	web.Spec.CurrentDeploymentRef = &corev1.LocalObjectReference{Name: utils.GetDeploymentResourceName(deploymentUUID)}
+	worker := application("application-worker", "9worker1", platformv1alpha1.ApplicationTypeImageFromRegistry)
+	database := application("application-db", "db123456", platformv1alpha1.ApplicationTypePostgres)
+	current := &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      utils.GetDeploymentResourceName(deploymentUUID),
+		Namespace: namespace,
+		Labels:    map[string]string{validation.LabelResourceUUID: deploymentUUID},
+	}}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(environment, web, worker, database, current).Build()
+	r := &ServiceDiscoveryReconciler{Client: c, Scheme: scheme}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(environment)})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Web applications are listed, databases expose their own services
+	var configMap corev1.ConfigMap
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: utils.GetServiceDiscoveryConfigMapName(environmentUUID), Namespace: namespace}, &configMap)).To(Succeed())
+	g.Expect(configMap.Data).To(Equal(map[string]string{
+		"WEB12345_HOST":     "app-web12345.project-shop.svc",
+		"WEB12345_PORT":     "8080",
+		"APP_9WORKER1_HOST": "app-9worker1.project-shop.svc",
+		"APP_9WORKER1_PORT": "3000",
+	}))
+
+	// The alias follows the promoted deployment, applications without one get none yet
+	var alias corev1.Service
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: "app-web12345", Namespace: namespace}, &alias)).To(Succeed())
+	g.Expect(alias.Spec.Selector).To(HaveKeyWithValue("platform.kibaship.com/deployment-uuid", deploymentUUID))
+	g.Expect(alias.Spec.Ports).To(HaveLen(1))
+	g.Expect(alias.Spec.Ports[0].Port).To(Equal(int32(8080)))
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: "app-9worker1", Namespace: namespace}, &corev1.Service{})).NotTo(Succeed())
+
+	// Deleted applications drop out of the ConfigMap
+	g.Expect(c.Delete(ctx, worker)).To(Succeed())
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(environment)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(&configMap), &configMap)).To(Succeed())
+	g.Expect(configMap.Data).NotTo(HaveKey("APP_9WORKER1_HOST"))
+}
+
+func TestServiceAliasDrainsPreviousDeployment(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+	SetDrainPeriod(30 * time.Second)
+
+	const (
+		namespace       = "project-shop"
+		environmentUUID = "5e6f7a8b-9c0d-4e1f-8a2b-3c4d5e6f7a8b"
+		webUUID         = "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+		previousUUID    = "2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e"
+		currentUUID     = "7f6e5d4c-3b2a-4190-8f7e-6d5c4b3a2f1e"
+	)
+	environment := &platformv1alpha1.Environment{ObjectMeta: metav1.ObjectMeta{
+		Name:      utils.GetEnvironmentResourceName(environmentUUID),
+		Namespace: namespace,
+		Labels:    map[string]string{validation.LabelResourceUUID: environmentUUID},
+	}}
+	web := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.GetApplicationResourceName(webUUID),
+			Namespace: namespace,
+			Labels: map[string]string{
+				validation.LabelResourceUUID:    webUUID,
+				validation.LabelResourceSlug:    "web12345",
+				validation.LabelEnvironmentUUID: environmentUUID,
+			},
+		},
+		Spec: platformv1alpha1.ApplicationSpec{
+			EnvironmentRef:       corev1.LocalObjectReference{Name: environment.Name},
+			Type:                 platformv1alpha1.ApplicationTypeGitRepository,
+			CurrentDeploymentRef: &corev1.LocalObjectReference{Name: utils.GetDeploymentResourceName(currentUUID)},
+		},
+	}
+	current := &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      utils.GetDeploymentResourceName(currentUUID),
+		Namespace: namespace,
+		Labels:    map[string]string{validation.LabelResourceUUID: currentUUID},
+	}}
+	replicas := int32(2)
+	pods := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: utils.GetKubernetesDeploymentName(currentUUID), Namespace: namespace},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{AvailableReplicas: 1},
+	}
+	alias := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-web12345", Namespace: namespace},
+		Spec: corev1.ServiceSpec{Selector: map[string]string{
+			"app.kubernetes.io/name":                "app-" + webUUID,
+			"platform.kibaship.com/deployment-uuid": previousUUID,
+		}},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(environment, web, current, pods, alias).
+		WithStatusSubresource(pods).Build()
+	fakeClock := clocktesting.NewFakePassiveClock(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+	r := &ServiceDiscoveryReconciler{Client: c, Scheme: scheme, Clock: fakeClock}
+	reconcile := func() ctrl.Result {
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(environment)})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(alias), alias)).To(Succeed())
+		return result
+	}
+	applicationSelector := map[string]string{"app.kubernetes.io/name": "app-" + webUUID}
+
+	// The promoted pods join the previous ones
+	g.Expect(reconcile().RequeueAfter).To(Equal(promotionReadinessInterval))
+	g.Expect(alias.Spec.Selector).To(Equal(applicationSelector))
+	g.Expect(alias.Annotations).To(HaveKeyWithValue(annotationPromotingDeployment, currentUUID))
+
+	// Until the promoted deployment is ready, the previous one keeps serving
+	g.Expect(reconcile().RequeueAfter).To(Equal(promotionReadinessInterval))
+	g.Expect(alias.Spec.Selector).To(Equal(applicationSelector))
+	g.Expect(alias.Annotations).NotTo(HaveKey(annotationDrainStartedAt))
+
+	// Once it is ready, the previous deployment drains
+	pods.Status.AvailableReplicas = 2
+	g.Expect(c.Status().Update(ctx, pods)).To(Succeed())
+	g.Expect(reconcile().RequeueAfter).To(Equal(30 * time.Second))
+	g.Expect(alias.Spec.Selector).To(Equal(applicationSelector))
+	g.Expect(alias.Annotations).To(HaveKeyWithValue(annotationDrainStartedAt, "2025-06-01T12:00:00Z"))
+
+	fakeClock.SetTime(fakeClock.Now().Add(10 * time.Second))
+	g.Expect(reconcile().RequeueAfter).To(Equal(20 * time.Second))
+	g.Expect(alias.Spec.Selector).To(Equal(applicationSelector))
+
+	// After the drain period, only the promoted deployment is served
+	fakeClock.SetTime(fakeClock.Now().Add(20 * time.Second))
+	g.Expect(reconcile().RequeueAfter).To(BeZero())
+	g.Expect(alias.Spec.Selector).To(HaveKeyWithValue("platform.kibaship.com/deployment-uuid", currentUUID))
+	g.Expect(alias.Annotations).NotTo(HaveKey(annotationPromotingDeployment))
+	g.Expect(alias.Annotations).NotTo(HaveKey(annotationDrainStartedAt))
+}
+
+func TestDeploymentPort(t *testing.T) {
+	g := NewWithT(t)
+	app := &platformv1alpha1.Application{}
+	deployment := &platformv1alpha1.Deployment{}
+
+	g.Expect(deploymentPort(app, deployment)).To(Equal(int32(3000)))
+
+	// Applications without a port listen on the port their build detected
+	deployment.Status.DetectedPort = 8080
+	g.Expect(deploymentPort(app, deployment)).To(Equal(int32(8080)))
+
+	// The port of the application wins over the detected one
+	app.Spec.Port = 4000
+	g.Expect(deploymentPort(app, deployment)).To(Equal(int32(4000)))
+}
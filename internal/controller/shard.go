@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// operatorShard is the shard reconciled by this operator instance. The default (empty) shard
+// reconciles the projects without a shard label, so a single unsharded operator reconciles everything.
+var operatorShard atomic.Value
+
+// SetOperatorShard sets the shard reconciled by this operator instance
+// This should be called once at startup, before controllers are set up
+func SetOperatorShard(shard string) error {
+	if shard != "" && !validation.ValidateShard(shard) {
+		return fmt.Errorf("shard must be a lowercase DNS label, got %q", shard)
+	}
+	operatorShard.Store(shard)
+	return nil
+}
+
+// GetOperatorShard returns the shard reconciled by this operator instance
+func GetOperatorShard() string {
+	shard, _ := operatorShard.Load().(string)
+	return shard
+}
+
+// isDefaultShard reports whether this operator instance reconciles the default shard.
+// Cluster-wide work that is not tied to a project runs on the default shard only: the BuildKit
+// pool, the GitOps export, and the bootstrap steps and status of operator configuration changes.
+func isDefaultShard() bool {
+	return GetOperatorShard() == ""
+}
+
+// inShard reports whether resources carrying labels belong to the shard of this operator
+func inShard(labels map[string]string) bool {
+	return labels[validation.LabelShard] == GetOperatorShard()
+}
+
+// isProjectNamespace reports whether namespace was created for a project
+func isProjectNamespace(namespace *corev1.Namespace) bool {
+	return namespace.Labels[ManagedByLabel] == ManagedByValue && namespace.Labels[validation.LabelResourceUUID] != ""
+}
+
+// ownsNamespace reports whether resources in the namespace named name are reconciled by this
+// operator. Project namespaces carry the shard label of their project, every other namespace is
+// shared by all shards.
+func ownsNamespace(ctx context.Context, c client.Reader, name string) (bool, error) {
+	if name == "" {
+		return true, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+		if errors.IsNotFound(err) {
+			// The namespace is gone, let the default shard observe the remaining events
+			return GetOperatorShard() == "", nil
+		}
+		return false, err
+	}
+
+	if !isProjectNamespace(namespace) {
+		return true, nil
+	}
+	return inShard(namespace.Labels), nil
+}
+
+// ShardPredicate filters out events of resources reconciled by another operator shard.
+// Cluster-scoped resources are matched on their own shard label, namespaced resources on the
+// shard label of their project namespace.
+func ShardPredicate(c client.Reader) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		if obj.GetNamespace() == "" {
+			return inShard(obj.GetLabels())
+		}
+
+		owned, err := ownsNamespace(context.Background(), c, obj.GetNamespace())
+		if err != nil {
+			logf.Log.WithName("shard").Error(err, "failed to resolve shard of namespace", "namespace", obj.GetNamespace())
+			return false
+		}
+		return owned
+	})
+}
+
+// DefaultShardPredicate filters out every event unless this operator reconciles the default shard.
+// It guards the cluster-wide controllers, which must run once for all shards.
+func DefaultShardPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(client.Object) bool {
+		return isDefaultShard()
+	})
+}
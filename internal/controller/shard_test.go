@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestShardPredicate(t *testing.T) {
+	g := NewWithT(t)
+	t.Cleanup(func() { _ = SetOperatorShard("") })
+
+	projectNamespace := func(name, shard string) *corev1.Namespace {
+		labels := map[string]string{ManagedByLabel: ManagedByValue, validation.LabelResourceUUID: name}
+		if shard != "" {
+			labels[validation.LabelShard] = shard
+		}
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	c := fake.NewClientBuilder().WithObjects(
+		projectNamespace("project-default", ""),
+		projectNamespace("project-eu", "eu"),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+	).Build()
+
+	secret := func(namespace string) event.CreateEvent {
+		return event.CreateEvent{Object: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s", Namespace: namespace}}}
+	}
+
+	g.Expect(SetOperatorShard("")).To(Succeed())
+	g.Expect(ShardPredicate(c).Create(secret("project-default"))).To(BeTrue())
+	g.Expect(ShardPredicate(c).Create(secret("project-eu"))).To(BeFalse())
+	g.Expect(ShardPredicate(c).Create(secret("kube-system"))).To(BeTrue())
+	g.Expect(DefaultShardPredicate().Create(secret("project-eu"))).To(BeTrue())
+
+	g.Expect(SetOperatorShard("eu")).To(Succeed())
+	g.Expect(ShardPredicate(c).Create(secret("project-default"))).To(BeFalse())
+	g.Expect(ShardPredicate(c).Create(secret("project-eu"))).To(BeTrue())
+	g.Expect(ShardPredicate(c).Create(secret("kube-system"))).To(BeTrue())
+
+	// Cluster-wide controllers only run on the default shard
+	g.Expect(DefaultShardPredicate().Create(secret("project-eu"))).To(BeFalse())
+	g.Expect(DefaultShardPredicate().Create(secret("kube-system"))).To(BeFalse())
+}
+
+func TestOperatorConfigStatusOnDefaultShard(t *testing.T) {
+	g := NewWithT(t)
+	t.Cleanup(func() { _ = SetOperatorShard("") })
+
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	r := NewOperatorConfigReconciler(c, c.Scheme(), nil, nil, nil)
+	key := types.NamespacedName{Namespace: config.OperatorNamespace, Name: config.OperatorConfigStatusConfigMapName}
+	applied := metav1.Condition{Type: OperatorConfigConditionApplied, Status: metav1.ConditionTrue, Reason: "ConfigurationApplied"}
+
+	// Other shards leave the status to the operator of the default shard
+	g.Expect(SetOperatorShard("eu")).To(Succeed())
+	g.Expect(r.setConditions(ctx, "1", applied)).To(Succeed())
+	err := c.Get(ctx, key, &corev1.ConfigMap{})
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+
+	g.Expect(SetOperatorShard("")).To(Succeed())
+	g.Expect(r.setConditions(ctx, "1", applied)).To(Succeed())
+	status := &corev1.ConfigMap{}
+	g.Expect(c.Get(ctx, key, status)).To(Succeed())
+	g.Expect(status.Data[operatorConfigObservedKey]).To(Equal("1"))
+}
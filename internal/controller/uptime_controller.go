@@ -0,0 +1,237 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/uptime"
+	"github.com/kibamail/kibaship/pkg/webhooks"
+)
+
+// UptimeCheckReconciler probes application domains with an uptime check and records the
+// results on the domain status, sending webhooks when a domain goes down and recovers
+type UptimeCheckReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Notifier webhooks.Notifier
+
+	// HTTPClient sends the probes, uptime.NewHTTPClient when nil
+	HTTPClient *http.Client
+}
+
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applicationdomains,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.operator.kibaship.com,resources=applicationdomains/status,verbs=get;update;patch
+
+// Reconcile probes a domain and schedules its next probe
+func (r *UptimeCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var domain platformv1alpha1.ApplicationDomain
+	if err := r.Get(ctx, req.NamespacedName, &domain); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !domain.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	check := domain.Spec.UptimeCheck
+	if check == nil {
+		return ctrl.Result{}, r.updateStatus(ctx, &domain, nil)
+	}
+
+	now := time.Now()
+	previous := domain.Status.Uptime
+	if previous == nil {
+		previous = &platformv1alpha1.UptimeStatus{State: platformv1alpha1.UptimeStateUnknown}
+	}
+
+	// Domains are only probed once routing and certificates are in place
+	if domain.Status.Phase != platformv1alpha1.ApplicationDomainPhaseReady {
+		if domain.Status.Uptime == nil {
+			if err := r.updateStatus(ctx, &domain, previous); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: check.ProbeInterval()}, nil
+	}
+
+	// Wait for the interval since the last probe when reconciled early, e.g. after a spec change
+	if previous.LastCheckTime != nil {
+		if wait := previous.LastCheckTime.Add(check.ProbeInterval()).Sub(now); wait > time.Second {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+	}
+
+	result := uptime.Probe(ctx, r.httpClient(), probeURL(&domain), int(check.ExpectedStatus), check.ProbeTimeout())
+	next := nextUptimeStatus(previous, result, check.Threshold(), now)
+
+	// Alert when the domain goes down and when it recovers, the first successful probe is not a recovery
+	wentDown := next.State == platformv1alpha1.UptimeStateDown && previous.State != platformv1alpha1.UptimeStateDown
+	recovered := next.State == platformv1alpha1.UptimeStateUp && previous.State == platformv1alpha1.UptimeStateDown
+	if wentDown || recovered {
+		log.Info("Application domain availability changed", "domain", domain.Spec.Domain,
+			"previousState", previous.State, "state", next.State, "error", next.LastError)
+		r.emitUptimeChange(ctx, &domain, previous, next)
+	}
+
+	if err := r.updateStatus(ctx, &domain, next); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: check.ProbeInterval()}, nil
+}
+
+// nextUptimeStatus folds a probe result into the uptime status. A domain goes down after
+// threshold consecutive failures and comes back up on the first success.
+func nextUptimeStatus(previous *platformv1alpha1.UptimeStatus, result uptime.Result, threshold int32, now time.Time) *platformv1alpha1.UptimeStatus {
+	next := previous.DeepCopy()
+	next.LastCheckTime = &metav1.Time{Time: now}
+	next.LastStatusCode = int32(result.StatusCode)
+	next.LastResponseTimeMillis = result.ResponseTime.Milliseconds()
+	next.TotalChecks++
+
+	state := previous.State
+	if result.Up() {
+		next.LastError = ""
+		next.ConsecutiveFailures = 0
+		next.SuccessfulChecks++
+		state = platformv1alpha1.UptimeStateUp
+	} else {
+		next.LastError = result.Err.Error()
+		next.ConsecutiveFailures++
+		if next.ConsecutiveFailures >= threshold {
+			state = platformv1alpha1.UptimeStateDown
+		}
+	}
+
+	if state != previous.State {
+		next.State = state
+		next.LastTransitionTime = &metav1.Time{Time: now}
+	}
+	return next
+}
+
+// emitUptimeChange sends a webhook when a domain goes down or recovers
+func (r *UptimeCheckReconciler) emitUptimeChange(ctx context.Context, domain *platformv1alpha1.ApplicationDomain, previous, next *platformv1alpha1.UptimeStatus) {
+	if r.Notifier == nil {
+		return
+	}
+
+	evt := webhooks.ApplicationDomainUptimeEvent{
+		Type:              "applicationdomain.uptime.down",
+		PreviousState:     string(previous.State),
+		NewState:          string(next.State),
+		ApplicationDomain: *domain,
+		Timestamp:         time.Now().UTC(),
+	}
+	evt.ApplicationDomain.Status.Uptime = next
+	if next.State == platformv1alpha1.UptimeStateUp {
+		evt.Type = "applicationdomain.uptime.recovered"
+		if previous.LastTransitionTime != nil {
+			evt.Downtime = next.LastTransitionTime.Sub(previous.LastTransitionTime.Time).Round(time.Second).String()
+		}
+	}
+	_ = r.Notifier.NotifyApplicationDomainUptimeChange(ctx, evt)
+}
+
+// updateStatus records the uptime status, clearing it when next is nil
+func (r *UptimeCheckReconciler) updateStatus(ctx context.Context, domain *platformv1alpha1.ApplicationDomain, next *platformv1alpha1.UptimeStatus) error {
+	if reflect.DeepEqual(domain.Status.Uptime, next) {
+		return nil
+	}
+
+	patch := client.MergeFrom(domain.DeepCopy())
+	domain.Status.Uptime = next
+	if err := r.Status().Patch(ctx, domain, patch); err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to update application domain uptime status: %w", err)
+	}
+	return nil
+}
+
+func (r *UptimeCheckReconciler) httpClient() *http.Client {
+	if r.HTTPClient == nil {
+		r.HTTPClient = uptime.NewHTTPClient()
+	}
+	return r.HTTPClient
+}
+
+// probeURL returns the URL the uptime check of a domain requests
+func probeURL(domain *platformv1alpha1.ApplicationDomain) string {
+	scheme := "http"
+	if domain.Spec.TLSEnabled {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, domain.Spec.Domain, domain.Spec.UptimeCheck.ProbePath())
+}
+
+// ignoreUptimeStatusUpdates filters out updates that only change the uptime status so
+// periodic probes do not trigger reconciles of other ApplicationDomain controllers
+func ignoreUptimeStatusUpdates() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldDomain, ok := e.ObjectOld.(*platformv1alpha1.ApplicationDomain)
+			if !ok {
+				return true
+			}
+			newDomain, ok := e.ObjectNew.(*platformv1alpha1.ApplicationDomain)
+			if !ok {
+				return true
+			}
+			if oldDomain.Generation != newDomain.Generation {
+				return true
+			}
+			oldStatus := oldDomain.Status.DeepCopy()
+			newStatus := newDomain.Status.DeepCopy()
+			oldStatus.Uptime, newStatus.Uptime = nil, nil
+			return !reflect.DeepEqual(oldStatus, newStatus) ||
+				!reflect.DeepEqual(oldDomain.Labels, newDomain.Labels) ||
+				!reflect.DeepEqual(oldDomain.Annotations, newDomain.Annotations) ||
+				!reflect.DeepEqual(oldDomain.Finalizers, newDomain.Finalizers) ||
+				!oldDomain.DeletionTimestamp.Equal(newDomain.DeletionTimestamp)
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Status updates are ignored, domains are probed on spec changes and every check interval.
+func (r *UptimeCheckReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&platformv1alpha1.ApplicationDomain{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controllerOptions("applicationdomain-uptime", 1)).
+		Named("applicationdomain-uptime").
+		WithEventFilter(ShardPredicate(mgr.GetClient())).
+		Complete(r)
+}
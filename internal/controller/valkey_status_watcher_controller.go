@@ -27,5 +27,6 @@ func (r *ValkeyStatusWatcherReconciler) Reconcile(ctx context.Context, req ctrl.
 // TODO: SetupWithManager - Valkey status watcher setup will be reimplemented
 func (r *ValkeyStatusWatcherReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// TODO: Implement new Valkey status watcher setup logic here
+	// Filter events with WithEventFilter(ShardPredicate(mgr.GetClient())) like the other watchers
 	return nil
 }
@@ -0,0 +1,92 @@
+// Package migrations runs the versioned data migrations of the operator at startup, such as
+// relabeling resources, backfilling status fields or converting deprecated spec fields, so CRD
+// changes reach the resources of existing clusters without manual edits.
+//
+// Applied migrations are recorded in a ConfigMap of the operator namespace and never run again.
+// Migrations run in order and stop at the first failure, which is retried on the next start, so a
+// migration may rely on the ones before it. Replicas may start together, every migration must be
+// idempotent.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// StateConfigMapName is the ConfigMap of the operator namespace recording the applied migrations,
+// keyed by migration ID with the time they were applied
+const StateConfigMapName = "kibaship-migrations"
+
+// Migration is a data migration of the resources of a cluster
+type Migration struct {
+	// ID orders the migrations and records them once applied. It never changes once released.
+	ID string
+	// Description tells what the migration changes
+	Description string
+	// Migrate changes the resources, it must be idempotent
+	Migrate func(ctx context.Context, c client.Client) error
+}
+
+// registered are the migrations of the operator, in the order they run. New migrations are
+// appended, released ones are never removed or reordered.
+var registered = []Migration{
+	backfillPipelineSpecVersion,
+}
+
+// Apply runs the migrations of the operator that were not applied yet
+func Apply(ctx context.Context, c client.Client) error {
+	return run(ctx, c, registered, time.Now)
+}
+
+// run applies the migrations missing from the state ConfigMap in order, recording each of them
+// once it succeeded
+func run(ctx context.Context, c client.Client, migrations []Migration, now func() time.Time) error {
+	log := ctrl.Log.WithName("migrations")
+
+	state := &corev1.ConfigMap{}
+	key := client.ObjectKey{Name: StateConfigMapName, Namespace: config.OperatorNamespace}
+	if err := c.Get(ctx, key, state); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("get migrations ConfigMap: %w", err)
+		}
+		state = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      StateConfigMapName,
+			Namespace: config.OperatorNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/managed-by": "kibaship"},
+		}}
+		if err := c.Create(ctx, state); err != nil {
+			return fmt.Errorf("create migrations ConfigMap: %w", err)
+		}
+	}
+
+	for _, migration := range migrations {
+		if _, applied := state.Data[migration.ID]; applied {
+			continue
+		}
+
+		log.Info("Applying migration", "id", migration.ID, "description", migration.Description)
+		if err := migration.Migrate(ctx, c); err != nil {
+			return fmt.Errorf("migration %s: %w", migration.ID, err)
+		}
+
+		if state.Data == nil {
+			state.Data = map[string]string{}
+		}
+		state.Data[migration.ID] = now().UTC().Format(time.RFC3339)
+		// A conflict means another replica migrated concurrently, the next start catches up
+		if err := c.Update(ctx, state); err != nil {
+			return fmt.Errorf("record migration %s: %w", migration.ID, err)
+		}
+		log.Info("Migration applied", "id", migration.ID)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/pipelines"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func testScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := platformv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+func TestRunRecordsAppliedMigrations(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(testScheme(t)).Build()
+	now := func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	var ran []string
+	migration := func(id string, err error) Migration {
+		return Migration{ID: id, Migrate: func(context.Context, client.Client) error {
+			ran = append(ran, id)
+			return err
+		}}
+	}
+	failing := []Migration{migration("0001-first", nil), migration("0002-second", errors.New("boom")), migration("0003-third", nil)}
+
+	// Migrations stop at the first failure, the applied ones are recorded
+	g.Expect(run(ctx, c, failing, now)).To(MatchError(ContainSubstring("migration 0002-second: boom")))
+	g.Expect(ran).To(Equal([]string{"0001-first", "0002-second"}))
+
+	state := &corev1.ConfigMap{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: StateConfigMapName, Namespace: config.OperatorNamespace}, state)).To(Succeed())
+	g.Expect(state.Data).To(Equal(map[string]string{"0001-first": "2025-01-02T03:04:05Z"}))
+
+	// The next start resumes at the failed migration
+	ran = nil
+	fixed := []Migration{migration("0001-first", nil), migration("0002-second", nil), migration("0003-third", nil)}
+	g.Expect(run(ctx, c, fixed, now)).To(Succeed())
+	g.Expect(ran).To(Equal([]string{"0002-second", "0003-third"}))
+
+	ran = nil
+	g.Expect(run(ctx, c, fixed, now)).To(Succeed())
+	g.Expect(ran).To(BeEmpty())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(state), state)).To(Succeed())
+	g.Expect(state.Data).To(HaveLen(3))
+}
+
+func TestBackfillPipelineSpecVersion(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	app := func(uuid string, appType platformv1alpha1.ApplicationType) *platformv1alpha1.Application {
+		return &platformv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "application-" + uuid, Namespace: "default",
+				Labels: map[string]string{validation.LabelResourceUUID: uuid}},
+			Spec: platformv1alpha1.ApplicationSpec{Type: appType},
+		}
+	}
+	deployment := func(uuid, appUUID string, annotations map[string]string) *platformv1alpha1.Deployment {
+		return &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "deployment-" + uuid, Namespace: "default", Annotations: annotations,
+			Labels: map[string]string{validation.LabelResourceUUID: uuid, validation.LabelApplicationUUID: appUUID},
+		}}
+	}
+	c := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(
+		app("git", platformv1alpha1.ApplicationTypeGitRepository),
+		app("registry", platformv1alpha1.ApplicationTypeImageFromRegistry),
+		deployment("old", "git", nil),
+		deployment("versioned", "git", map[string]string{pipelines.AnnotationSpecVersion: "v2"}),
+		deployment("image", "registry", nil),
+	).Build()
+
+	g.Expect(backfillPipelineSpecVersion.Migrate(ctx, c)).To(Succeed())
+
+	var deployments platformv1alpha1.DeploymentList
+	g.Expect(c.List(ctx, &deployments)).To(Succeed())
+	versions := map[string]string{}
+	for _, d := range deployments.Items {
+		versions[d.Name] = d.Annotations[pipelines.AnnotationSpecVersion]
+	}
+	g.Expect(versions).To(Equal(map[string]string{
+		"deployment-old":       pipelines.SpecVersionV1,
+		"deployment-versioned": "v2",
+		"deployment-image":     "",
+	}))
+}
@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/pipelines"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// backfillPipelineSpecVersion records the first pipeline spec version on the git repository
+// deployments created before pipelines were versioned, their Pipelines were generated with it.
+// Without the annotation, regenerating their Pipeline would use the current spec version.
+var backfillPipelineSpecVersion = Migration{
+	ID:          "0001-backfill-pipeline-spec-version",
+	Description: "Record the pipeline spec version of existing git repository deployments",
+	Migrate: func(ctx context.Context, c client.Client) error {
+		var apps platformv1alpha1.ApplicationList
+		if err := c.List(ctx, &apps); err != nil {
+			return fmt.Errorf("list applications: %w", err)
+		}
+		gitApps := map[string]bool{}
+		for _, app := range apps.Items {
+			if app.Spec.Type == platformv1alpha1.ApplicationTypeGitRepository {
+				gitApps[app.GetUUID()] = true
+			}
+		}
+
+		var deployments platformv1alpha1.DeploymentList
+		if err := c.List(ctx, &deployments); err != nil {
+			return fmt.Errorf("list deployments: %w", err)
+		}
+		for i := range deployments.Items {
+			deployment := &deployments.Items[i]
+			if !gitApps[deployment.Labels[validation.LabelApplicationUUID]] {
+				continue
+			}
+			if _, ok := deployment.Annotations[pipelines.AnnotationSpecVersion]; ok {
+				continue
+			}
+
+			patch := client.MergeFrom(deployment.DeepCopy())
+			if deployment.Annotations == nil {
+				deployment.Annotations = map[string]string{}
+			}
+			deployment.Annotations[pipelines.AnnotationSpecVersion] = pipelines.SpecVersionV1
+			if err := c.Patch(ctx, deployment, patch); err != nil {
+				return fmt.Errorf("annotate deployment %s/%s: %w", deployment.Namespace, deployment.Name, err)
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,131 @@
+package registryauth
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// AdminHandler serves the admin API used by operators to inspect issued
+// tokens and revoke token issuance for compromised projects
+type AdminHandler struct {
+	store   *TokenStore
+	metrics *Metrics
+	token   string
+}
+
+// RevokeRequest is the body accepted by POST /admin/revocations
+type RevokeRequest struct {
+	Namespace string `json:"namespace"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// RevokeResponse is returned after a namespace has been revoked
+type RevokeResponse struct {
+	Namespace     string `json:"namespace"`
+	DroppedTokens int    `json:"droppedTokens"`
+}
+
+// NewAdminHandler creates a new admin handler guarded by the given bearer token
+func NewAdminHandler(store *TokenStore, metrics *Metrics, token string) *AdminHandler {
+	return &AdminHandler{
+		store:   store,
+		metrics: metrics,
+		token:   token,
+	}
+}
+
+// Register mounts the admin endpoints on the given mux
+func (a *AdminHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/admin/tokens", a.authorize(a.ServeTokens))
+	mux.HandleFunc("/admin/revocations", a.authorize(a.ServeRevocations))
+}
+
+// ServeTokens handles GET /admin/tokens?namespace=<ns>
+// Lists the scopes of all currently valid tokens
+func (a *AdminHandler) ServeTokens(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, a.store.ListValid(r.URL.Query().Get("namespace")))
+}
+
+// ServeRevocations handles the revocation endpoints:
+//   - GET    /admin/revocations                 lists revoked namespaces
+//   - POST   /admin/revocations                 revokes a namespace
+//   - DELETE /admin/revocations?namespace=<ns>  lifts a revocation
+func (a *AdminHandler) ServeRevocations(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, a.store.Revocations())
+
+	case http.MethodPost:
+		var req RevokeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if strings.TrimSpace(req.Namespace) == "" {
+			http.Error(w, "namespace is required", http.StatusBadRequest)
+			return
+		}
+
+		dropped, err := a.store.Revoke(r.Context(), req.Namespace, req.Reason)
+		if err != nil {
+			log.Printf("admin: failed to revoke namespace=%s: %v", req.Namespace, err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		a.metrics.TokensRevoked(dropped)
+		log.Printf("admin: revoked token issuance for namespace=%s dropped=%d reason=%q", req.Namespace, dropped, req.Reason)
+
+		writeJSON(w, http.StatusOK, RevokeResponse{Namespace: req.Namespace, DroppedTokens: dropped})
+
+	case http.MethodDelete:
+		namespace := r.URL.Query().Get("namespace")
+		if namespace == "" {
+			http.Error(w, "namespace is required", http.StatusBadRequest)
+			return
+		}
+		restored, err := a.store.Restore(r.Context(), namespace)
+		if err != nil {
+			log.Printf("admin: failed to restore namespace=%s: %v", namespace, err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		if !restored {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("admin: restored token issuance for namespace=%s", namespace)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// authorize checks the bearer token before calling next
+func (a *AdminHandler) authorize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		provided := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(a.token)) != 1 {
+			log.Printf("admin: unauthorized request to %s", r.URL.Path)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("admin: failed to encode response: %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package registryauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAdminHandler(t *testing.T) {
+	g := NewWithT(t)
+
+	store := NewTokenStore(NewConfigMapRevocations(fake.NewClientset(), "registry", "registry-auth-revocations"))
+	now := time.Now()
+	store.Record(IssuedToken{ID: "a", Namespace: "project-a", IssuedAt: now, ExpiresAt: now.Add(time.Minute)})
+	store.Record(IssuedToken{ID: "b", Namespace: "project-b", IssuedAt: now, ExpiresAt: now.Add(time.Minute)})
+
+	mux := http.NewServeMux()
+	NewAdminHandler(store, NewMetrics(), "admin-secret").Register(mux)
+	serve := func(method, target, body, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Every endpoint requires the admin token
+	g.Expect(serve(http.MethodGet, "/admin/tokens", "", "").Code).To(Equal(http.StatusUnauthorized))
+	g.Expect(serve(http.MethodGet, "/admin/revocations", "", "wrong").Code).To(Equal(http.StatusUnauthorized))
+
+	rec := serve(http.MethodGet, "/admin/tokens?namespace=project-a", "", "admin-secret")
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	var tokens []IssuedToken
+	g.Expect(json.Unmarshal(rec.Body.Bytes(), &tokens)).To(Succeed())
+	g.Expect(tokens).To(ConsistOf(HaveField("ID", "a")))
+	g.Expect(serve(http.MethodPost, "/admin/tokens", "", "admin-secret").Code).To(Equal(http.StatusMethodNotAllowed))
+
+	g.Expect(serve(http.MethodPost, "/admin/revocations", "{", "admin-secret").Code).To(Equal(http.StatusBadRequest))
+	g.Expect(serve(http.MethodPost, "/admin/revocations", `{"namespace":" "}`, "admin-secret").Code).To(Equal(http.StatusBadRequest))
+
+	rec = serve(http.MethodPost, "/admin/revocations", `{"namespace":"project-a","reason":"leaked"}`, "admin-secret")
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	var revoked RevokeResponse
+	g.Expect(json.Unmarshal(rec.Body.Bytes(), &revoked)).To(Succeed())
+	g.Expect(revoked).To(Equal(RevokeResponse{Namespace: "project-a", DroppedTokens: 1}))
+	g.Expect(store.IsRevoked("project-a")).To(BeTrue())
+
+	rec = serve(http.MethodGet, "/admin/revocations", "", "admin-secret")
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	var revocations []Revocation
+	g.Expect(json.Unmarshal(rec.Body.Bytes(), &revocations)).To(Succeed())
+	g.Expect(revocations).To(ConsistOf(And(HaveField("Namespace", "project-a"), HaveField("Reason", "leaked"))))
+
+	g.Expect(serve(http.MethodDelete, "/admin/revocations", "", "admin-secret").Code).To(Equal(http.StatusBadRequest))
+	g.Expect(serve(http.MethodDelete, "/admin/revocations?namespace=project-b", "", "admin-secret").Code).To(Equal(http.StatusNotFound))
+	g.Expect(serve(http.MethodDelete, "/admin/revocations?namespace=project-a", "", "admin-secret").Code).To(Equal(http.StatusNoContent))
+	g.Expect(store.IsRevoked("project-a")).To(BeFalse())
+	g.Expect(serve(http.MethodPut, "/admin/revocations", "", "admin-secret").Code).To(Equal(http.StatusMethodNotAllowed))
+}
@@ -1,7 +1,26 @@
 package registryauth
 
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+const (
+	// EnvTokenTTLSeconds overrides the lifetime of issued registry tokens
+	EnvTokenTTLSeconds = "REGISTRY_AUTH_TOKEN_TTL_SECONDS"
+	// EnvAdminToken is the bearer token required by the admin API.
+	// The admin API is disabled when it is not set.
+	EnvAdminToken = "REGISTRY_AUTH_ADMIN_TOKEN"
+
+	// MinTokenTTLSeconds and MaxTokenTTLSeconds bound the configurable token lifetime
+	MinTokenTTLSeconds = 60
+	MaxTokenTTLSeconds = 3600
+)
+
 // Config holds the configuration for the registry auth service
-// All values are hardcoded since the deployment environment is fully known
+// All values are hardcoded since the deployment environment is fully known,
+// except for the token TTL and admin token which can be set through the environment
 type Config struct {
 	JWT struct {
 		Issuer         string
@@ -17,6 +36,13 @@ type Config struct {
 	Cache struct {
 		TTLSeconds int
 	}
+	Admin struct {
+		Token string
+	}
+	Revocations struct {
+		Namespace string
+		ConfigMap string
+	}
 }
 
 // LoadConfig returns the configuration with hardcoded values
@@ -24,8 +50,10 @@ type Config struct {
 // - Keys are in /etc/registry-auth-keys/ (mounted from registry-auth-keys Secret)
 // - Registry service is called "docker-registry"
 // - We run on port 8080
-// - Tokens valid for 5 minutes
+// - Tokens valid for 5 minutes (override with REGISTRY_AUTH_TOKEN_TTL_SECONDS)
 // - Credentials cached for 5 minutes
+// - Admin API enabled only when REGISTRY_AUTH_ADMIN_TOKEN is set
+// - Revocations shared through the registry-auth-revocations ConfigMap in the registry namespace
 func LoadConfig() Config {
 	cfg := Config{}
 
@@ -34,6 +62,19 @@ func LoadConfig() Config {
 	cfg.JWT.ExpirationSec = 300 // 5 minutes
 	cfg.JWT.PrivateKeyPath = "/etc/registry-auth-keys/tls.key"
 
+	if raw := os.Getenv(EnvTokenTTLSeconds); raw != "" {
+		ttl, err := strconv.Atoi(raw)
+		switch {
+		case err != nil:
+			log.Printf("config: ignoring invalid %s=%q: %v", EnvTokenTTLSeconds, raw, err)
+		case ttl < MinTokenTTLSeconds || ttl > MaxTokenTTLSeconds:
+			log.Printf("config: ignoring %s=%d, must be between %d and %d",
+				EnvTokenTTLSeconds, ttl, MinTokenTTLSeconds, MaxTokenTTLSeconds)
+		default:
+			cfg.JWT.ExpirationSec = ttl
+		}
+	}
+
 	// Registry configuration
 	cfg.Registry.ServiceName = "docker-registry"
 
@@ -43,5 +84,12 @@ func LoadConfig() Config {
 	// Cache configuration
 	cfg.Cache.TTLSeconds = 300 // 5 minutes
 
+	// Admin configuration
+	cfg.Admin.Token = os.Getenv(EnvAdminToken)
+
+	// Revocations configuration
+	cfg.Revocations.Namespace = "registry"
+	cfg.Revocations.ConfigMap = "registry-auth-revocations"
+
 	return cfg
 }
@@ -6,12 +6,16 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/kibamail/kibaship/pkg/utils"
 )
 
 // Handler handles authentication requests from Docker clients
 type Handler struct {
 	validator      *Validator
 	tokenGenerator *TokenGenerator
+	store          *TokenStore
+	metrics        *Metrics
 	serviceName    string
 }
 
@@ -24,10 +28,12 @@ type TokenResponse struct {
 }
 
 // NewHandler creates a new authentication handler
-func NewHandler(validator *Validator, tokenGenerator *TokenGenerator, serviceName string) *Handler {
+func NewHandler(validator *Validator, tokenGenerator *TokenGenerator, store *TokenStore, metrics *Metrics, serviceName string) *Handler {
 	return &Handler{
 		validator:      validator,
 		tokenGenerator: tokenGenerator,
+		store:          store,
+		metrics:        metrics,
 		serviceName:    serviceName,
 	}
 }
@@ -46,6 +52,7 @@ func (h *Handler) ServeAuth(w http.ResponseWriter, r *http.Request) {
 	username, password, ok := r.BasicAuth()
 	if !ok {
 		log.Printf("auth: missing or invalid Authorization header")
+		h.metrics.RequestDenied(DenyReasonMissingAuth)
 		http.Error(w, "unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -54,6 +61,7 @@ func (h *Handler) ServeAuth(w http.ResponseWriter, r *http.Request) {
 	scopes := r.URL.Query()["scope"]
 	if len(scopes) == 0 {
 		log.Printf("auth: missing scope parameter")
+		h.metrics.RequestDenied(DenyReasonInvalidScope)
 		http.Error(w, "bad request", http.StatusBadRequest)
 		return
 	}
@@ -61,14 +69,34 @@ func (h *Handler) ServeAuth(w http.ResponseWriter, r *http.Request) {
 	var accessGrants []AccessEntry
 
 	// Determine the authenticated namespace from the username
-	// The username should match the namespace that owns the credentials
+	// The username should match the namespace that owns the credentials,
+	// robot accounts log in as robot$<namespace>+<name>
 	authenticatedNamespace := username
+	robotNamespace, robotName, isRobot := utils.ParseRegistryRobotUsername(username)
+	if isRobot {
+		authenticatedNamespace = robotNamespace
+	}
 
 	log.Printf("auth: authenticated namespace=%s", authenticatedNamespace)
 
+	// Refuse to issue tokens for namespaces revoked through the admin API
+	if h.store.IsRevoked(authenticatedNamespace) {
+		log.Printf("auth: token issuance revoked for namespace=%s", authenticatedNamespace)
+		h.metrics.RequestDenied(DenyReasonRevoked)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	// Validate credentials against the authenticated namespace
-	if !h.validator.ValidateCredentials(r.Context(), authenticatedNamespace, username, password) {
+	var valid bool
+	if isRobot {
+		valid = h.validator.ValidateRobotCredentials(r.Context(), robotNamespace, robotName, username, password)
+	} else {
+		valid = h.validator.ValidateCredentials(r.Context(), authenticatedNamespace, username, password)
+	}
+	if !valid {
 		log.Printf("auth: invalid credentials for namespace=%s", authenticatedNamespace)
+		h.metrics.RequestDenied(DenyReasonInvalidCredentials)
 		http.Error(w, "unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -78,6 +106,7 @@ func (h *Handler) ServeAuth(w http.ResponseWriter, r *http.Request) {
 		repo, actions, err := parseScope(scopeStr)
 		if err != nil {
 			log.Printf("auth: failed to parse scope %s: %v", scopeStr, err)
+			h.metrics.RequestDenied(DenyReasonInvalidScope)
 			http.Error(w, "bad request", http.StatusBadRequest)
 			return
 		}
@@ -86,6 +115,7 @@ func (h *Handler) ServeAuth(w http.ResponseWriter, r *http.Request) {
 		repoNamespace, err := extractNamespaceFromRepo(repo)
 		if err != nil {
 			log.Printf("auth: failed to extract namespace from repo=%s: %v", repo, err)
+			h.metrics.RequestDenied(DenyReasonInvalidScope)
 			http.Error(w, "bad request", http.StatusBadRequest)
 			return
 		}
@@ -95,7 +125,20 @@ func (h *Handler) ServeAuth(w http.ResponseWriter, r *http.Request) {
 		// Security policy:
 		// 1. Full access (read/write) only to authenticated namespace
 		// 2. Read-only access allowed to other namespaces (for layer mounting)
-		if repoNamespace == authenticatedNamespace {
+		// 3. Robot accounts only pull from their own namespace
+		if isRobot {
+			if repoNamespace == authenticatedNamespace && containsAction(actions, "pull") {
+				accessGrants = append(accessGrants, AccessEntry{
+					Type:    "repository",
+					Name:    repo,
+					Actions: []string{"pull"},
+				})
+				log.Printf("auth: granted robot read access repo=%s", repo)
+			} else {
+				log.Printf("auth: denied robot access repo=%s actions=%v", repo, actions)
+				h.metrics.RequestDenied(DenyReasonRobotScope)
+			}
+		} else if repoNamespace == authenticatedNamespace {
 			// Full access to own namespace
 			accessGrants = append(accessGrants, AccessEntry{
 				Type:    "repository",
@@ -121,19 +164,29 @@ func (h *Handler) ServeAuth(w http.ResponseWriter, r *http.Request) {
 				log.Printf("auth: granted cross-namespace read access repo=%s actions=%v", repo, allowedActions)
 			} else {
 				log.Printf("auth: denied cross-namespace write access repo=%s actions=%v", repo, actions)
+				h.metrics.RequestDenied(DenyReasonCrossNamespacePush)
 			}
 		}
 	}
 
 	// Generate JWT token
-	token, expiresAt, err := h.tokenGenerator.GenerateToken(username, h.serviceName, accessGrants)
+	token, claims, err := h.tokenGenerator.GenerateToken(username, h.serviceName, accessGrants)
 	if err != nil {
 		log.Printf("auth: failed to generate token: %v", err)
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	expiresIn := int(time.Until(expiresAt).Seconds())
+	h.store.Record(IssuedToken{
+		ID:        claims.ID,
+		Namespace: authenticatedNamespace,
+		Access:    accessGrants,
+		IssuedAt:  claims.IssuedAt.Time,
+		ExpiresAt: claims.ExpiresAt.Time,
+	})
+	h.metrics.TokenIssued(authenticatedNamespace)
+
+	expiresIn := int(time.Until(claims.ExpiresAt.Time).Seconds())
 
 	// Return token response
 	response := TokenResponse{
@@ -152,6 +205,16 @@ func (h *Handler) ServeAuth(w http.ResponseWriter, r *http.Request) {
 	log.Printf("auth: token issued for namespace=%s with %d access grants", authenticatedNamespace, len(accessGrants))
 }
 
+// containsAction reports whether actions includes action
+func containsAction(actions []string, action string) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
 // parseScope parses Docker registry scope format: "repository:<name>:<actions>"
 // Example: "repository:test-app/myapp:push,pull" => ("test-app/myapp", ["push", "pull"])
 func parseScope(scope string) (string, []string, error) {
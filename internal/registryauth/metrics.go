@@ -0,0 +1,76 @@
+package registryauth
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Reasons used for the denied requests counter
+const (
+	DenyReasonMissingAuth        = "missing_auth"
+	DenyReasonInvalidScope       = "invalid_scope"
+	DenyReasonInvalidCredentials = "invalid_credentials"
+	DenyReasonRevoked            = "revoked"
+	DenyReasonCrossNamespacePush = "cross_namespace_push"
+	DenyReasonRobotScope         = "robot_scope"
+)
+
+// Metrics holds the Prometheus collectors exposed on /metrics
+type Metrics struct {
+	registry       *prometheus.Registry
+	tokensIssued   *prometheus.CounterVec
+	requestsDenied *prometheus.CounterVec
+	tokensRevoked  prometheus.Counter
+}
+
+// NewMetrics creates the collectors on a dedicated registry
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		tokensIssued: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "registry_auth_tokens_issued_total",
+			Help: "Number of registry tokens issued, by namespace.",
+		}, []string{"namespace"}),
+		requestsDenied: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "registry_auth_requests_denied_total",
+			Help: "Number of denied auth requests or scopes, by reason.",
+		}, []string{"reason"}),
+		tokensRevoked: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "registry_auth_tokens_revoked_total",
+			Help: "Number of still valid tokens dropped by namespace revocations.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		m.tokensIssued,
+		m.requestsDenied,
+		m.tokensRevoked,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+// TokenIssued increments the issuance counter for a namespace
+func (m *Metrics) TokenIssued(namespace string) {
+	m.tokensIssued.WithLabelValues(namespace).Inc()
+}
+
+// RequestDenied increments the denied counter for a reason
+func (m *Metrics) RequestDenied(reason string) {
+	m.requestsDenied.WithLabelValues(reason).Inc()
+}
+
+// TokensRevoked adds the number of tokens dropped by a revocation
+func (m *Metrics) TokensRevoked(count int) {
+	m.tokensRevoked.Add(float64(count))
+}
+
+// Handler returns the HTTP handler serving the metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
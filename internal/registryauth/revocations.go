@@ -0,0 +1,123 @@
+package registryauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// RevocationBackend persists revocations so every replica of the service enforces them and they
+// survive restarts
+type RevocationBackend interface {
+	// Load returns the persisted revocations by namespace
+	Load(ctx context.Context) (map[string]Revocation, error)
+
+	// Update applies mutate to the persisted revocations and returns them. mutate reports whether
+	// it changed the revocations, nothing is written otherwise.
+	Update(ctx context.Context, mutate func(map[string]Revocation) bool) (map[string]Revocation, error)
+}
+
+// ConfigMapRevocations stores revocations in a ConfigMap, one key per revoked namespace holding
+// the JSON encoded revocation
+type ConfigMapRevocations struct {
+	clientset kubernetes.Interface
+	namespace string
+	name      string
+}
+
+// NewConfigMapRevocations creates a backend storing revocations in the ConfigMap namespace/name,
+// which is created on the first revocation
+func NewConfigMapRevocations(clientset kubernetes.Interface, namespace, name string) *ConfigMapRevocations {
+	return &ConfigMapRevocations{
+		clientset: clientset,
+		namespace: namespace,
+		name:      name,
+	}
+}
+
+// Load returns the revocations stored in the ConfigMap, none when it does not exist yet
+func (c *ConfigMapRevocations) Load(ctx context.Context) (map[string]Revocation, error) {
+	cm, err := c.clientset.CoreV1().ConfigMaps(c.namespace).Get(ctx, c.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return map[string]Revocation{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revocations ConfigMap %s/%s: %w", c.namespace, c.name, err)
+	}
+	return decodeRevocations(cm.Data), nil
+}
+
+// Update applies mutate to the revocations of the ConfigMap, retrying on conflicting writes of
+// other replicas
+func (c *ConfigMapRevocations) Update(ctx context.Context, mutate func(map[string]Revocation) bool) (map[string]Revocation, error) {
+	var revocations map[string]Revocation
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMaps := c.clientset.CoreV1().ConfigMaps(c.namespace)
+		cm, err := configMaps.Get(ctx, c.name, metav1.GetOptions{})
+		exists := err == nil
+		if apierrors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name:      c.name,
+				Namespace: c.namespace,
+				Labels:    map[string]string{"app": "registry-auth"},
+			}}
+		} else if err != nil {
+			return err
+		}
+
+		revocations = decodeRevocations(cm.Data)
+		if !mutate(revocations) {
+			return nil
+		}
+		if cm.Data, err = encodeRevocations(revocations); err != nil {
+			return err
+		}
+
+		if exists {
+			_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+		} else {
+			_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// Created by another replica in the meantime, retry against it
+				err = apierrors.NewConflict(corev1.Resource("configmaps"), c.name, err)
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update revocations ConfigMap %s/%s: %w", c.namespace, c.name, err)
+	}
+	return revocations, nil
+}
+
+func decodeRevocations(data map[string]string) map[string]Revocation {
+	revocations := make(map[string]Revocation, len(data))
+	for namespace, raw := range data {
+		var revocation Revocation
+		if err := json.Unmarshal([]byte(raw), &revocation); err != nil {
+			// A corrupted entry still revokes the namespace, the admin API lifts it
+			revocation = Revocation{}
+		}
+		revocation.Namespace = namespace
+		revocations[namespace] = revocation
+	}
+	return revocations
+}
+
+func encodeRevocations(revocations map[string]Revocation) (map[string]string, error) {
+	data := make(map[string]string, len(revocations))
+	for namespace, revocation := range revocations {
+		raw, err := json.Marshal(revocation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode revocation of namespace %s: %w", namespace, err)
+		}
+		data[namespace] = string(raw)
+	}
+	return data, nil
+}
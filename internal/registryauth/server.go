@@ -40,12 +40,29 @@ func NewServer(config Config) (*Server, error) {
 		return nil, fmt.Errorf("failed to create token generator: %w", err)
 	}
 
+	// Initialize token store and metrics, revocations are shared by all replicas
+	store := NewTokenStore(NewConfigMapRevocations(k8sClient.clientset, config.Revocations.Namespace, config.Revocations.ConfigMap))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := store.Sync(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load revocations: %w", err)
+	}
+	store.StartSyncRoutine()
+	store.StartCleanupRoutine()
+	metrics := NewMetrics()
+
 	// Initialize handler
-	handler := NewHandler(validator, tokenGenerator, config.Registry.ServiceName)
+	handler := NewHandler(validator, tokenGenerator, store, metrics, config.Registry.ServiceName)
 
 	// Create HTTP server
 	mux := http.NewServeMux()
 	mux.HandleFunc("/auth", handler.ServeAuth)
+	mux.Handle("/metrics", metrics.Handler())
+	if config.Admin.Token != "" {
+		NewAdminHandler(store, metrics, config.Admin.Token).Register(mux)
+	} else {
+		log.Printf("admin API disabled: %s is not set", EnvAdminToken)
+	}
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
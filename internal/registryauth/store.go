@@ -0,0 +1,232 @@
+package registryauth
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// IssuedToken describes a token handed out by the auth service.
+// The signed token itself is never stored, only its metadata.
+type IssuedToken struct {
+	ID        string        `json:"id"`
+	Namespace string        `json:"namespace"`
+	Access    []AccessEntry `json:"access"`
+	IssuedAt  time.Time     `json:"issuedAt"`
+	ExpiresAt time.Time     `json:"expiresAt"`
+}
+
+// Revocation records that token issuance for a namespace has been blocked
+type Revocation struct {
+	Namespace string    `json:"namespace"`
+	RevokedAt time.Time `json:"revokedAt"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// RevocationSyncInterval is how often every replica reloads the revocations written by the others
+const RevocationSyncInterval = 5 * time.Second
+
+// TokenStore keeps track of issued tokens and revoked namespaces.
+// Issued tokens are tracked in memory per replica. Revocations are persisted
+// through the backend and reloaded every RevocationSyncInterval, so a
+// revocation reaches every replica within that interval and survives restarts.
+// The registry verifies tokens offline against the JWKS, so revoking a
+// namespace stops new tokens from being issued and already issued tokens
+// expire within the configured TTL.
+type TokenStore struct {
+	tokens      map[string]IssuedToken
+	revocations map[string]Revocation
+	backend     RevocationBackend
+	mu          sync.RWMutex
+}
+
+// NewTokenStore creates an empty token store persisting revocations through backend.
+// A nil backend keeps revocations in memory.
+func NewTokenStore(backend RevocationBackend) *TokenStore {
+	return &TokenStore{
+		tokens:      make(map[string]IssuedToken),
+		revocations: make(map[string]Revocation),
+		backend:     backend,
+	}
+}
+
+// Record stores metadata about a freshly issued token
+func (ts *TokenStore) Record(token IssuedToken) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.tokens[token.ID] = token
+}
+
+// ListValid returns all tokens that have not expired yet, optionally
+// filtered by namespace, ordered by issue time
+func (ts *TokenStore) ListValid(namespace string) []IssuedToken {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	now := time.Now()
+	tokens := []IssuedToken{}
+	for _, token := range ts.tokens {
+		if now.After(token.ExpiresAt) {
+			continue
+		}
+		if namespace != "" && token.Namespace != namespace {
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].IssuedAt.Before(tokens[j].IssuedAt)
+	})
+
+	return tokens
+}
+
+// Revoke blocks token issuance for a namespace and drops the tracked tokens
+// for it. Returns the number of still valid tokens this replica dropped.
+func (ts *TokenStore) Revoke(ctx context.Context, namespace, reason string) (int, error) {
+	revocation := Revocation{
+		Namespace: namespace,
+		RevokedAt: time.Now(),
+		Reason:    reason,
+	}
+	if err := ts.update(ctx, func(revocations map[string]Revocation) bool {
+		revocations[namespace] = revocation
+		return true
+	}); err != nil {
+		return 0, err
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	now := time.Now()
+	dropped := 0
+	for id, token := range ts.tokens {
+		if token.Namespace != namespace {
+			continue
+		}
+		if !now.After(token.ExpiresAt) {
+			dropped++
+		}
+		delete(ts.tokens, id)
+	}
+
+	return dropped, nil
+}
+
+// Restore lifts a revocation. Returns false if the namespace was not revoked.
+func (ts *TokenStore) Restore(ctx context.Context, namespace string) (bool, error) {
+	restored := false
+	err := ts.update(ctx, func(revocations map[string]Revocation) bool {
+		if _, restored = revocations[namespace]; restored {
+			delete(revocations, namespace)
+		}
+		return restored
+	})
+	return restored, err
+}
+
+// update applies mutate to the persisted revocations and replaces the ones
+// held in memory with the result
+func (ts *TokenStore) update(ctx context.Context, mutate func(map[string]Revocation) bool) error {
+	if ts.backend == nil {
+		ts.mu.Lock()
+		defer ts.mu.Unlock()
+		mutate(ts.revocations)
+		return nil
+	}
+
+	revocations, err := ts.backend.Update(ctx, mutate)
+	if err != nil {
+		return err
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.revocations = revocations
+	return nil
+}
+
+// Sync reloads the revocations from the backend, picking up the ones
+// written by other replicas
+func (ts *TokenStore) Sync(ctx context.Context) error {
+	if ts.backend == nil {
+		return nil
+	}
+
+	revocations, err := ts.backend.Load(ctx)
+	if err != nil {
+		return err
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.revocations = revocations
+	return nil
+}
+
+// IsRevoked reports whether token issuance is blocked for a namespace
+func (ts *TokenStore) IsRevoked(namespace string) bool {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	_, ok := ts.revocations[namespace]
+	return ok
+}
+
+// Revocations returns all active revocations ordered by namespace
+func (ts *TokenStore) Revocations() []Revocation {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	revocations := make([]Revocation, 0, len(ts.revocations))
+	for _, revocation := range ts.revocations {
+		revocations = append(revocations, revocation)
+	}
+
+	sort.Slice(revocations, func(i, j int) bool {
+		return revocations[i].Namespace < revocations[j].Namespace
+	})
+
+	return revocations
+}
+
+// Cleanup removes expired tokens from the store
+func (ts *TokenStore) Cleanup() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	now := time.Now()
+	for id, token := range ts.tokens {
+		if now.After(token.ExpiresAt) {
+			delete(ts.tokens, id)
+		}
+	}
+}
+
+// StartSyncRoutine starts a background goroutine reloading the revocations
+// every RevocationSyncInterval. Failed reloads keep the last known revocations.
+func (ts *TokenStore) StartSyncRoutine() {
+	ticker := time.NewTicker(RevocationSyncInterval)
+	go func() {
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), RevocationSyncInterval)
+			if err := ts.Sync(ctx); err != nil {
+				log.Printf("store: failed to reload revocations: %v", err)
+			}
+			cancel()
+		}
+	}()
+}
+
+// StartCleanupRoutine starts a background goroutine to periodically clean up expired tokens
+func (ts *TokenStore) StartCleanupRoutine() {
+	ticker := time.NewTicker(time.Minute)
+	go func() {
+		for range ticker.C {
+			ts.Cleanup()
+		}
+	}()
+}
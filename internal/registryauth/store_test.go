@@ -0,0 +1,83 @@
+package registryauth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTokenStoreListsValidTokens(t *testing.T) {
+	g := NewWithT(t)
+	store := NewTokenStore(nil)
+	now := time.Now()
+
+	store.Record(IssuedToken{ID: "b", Namespace: "project-a", IssuedAt: now, ExpiresAt: now.Add(time.Minute)})
+	store.Record(IssuedToken{ID: "a", Namespace: "project-a", IssuedAt: now.Add(-time.Second), ExpiresAt: now.Add(time.Minute)})
+	store.Record(IssuedToken{ID: "c", Namespace: "project-b", IssuedAt: now, ExpiresAt: now.Add(time.Minute)})
+	store.Record(IssuedToken{ID: "expired", Namespace: "project-a", IssuedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)})
+
+	ids := func(tokens []IssuedToken) []string {
+		result := []string{}
+		for _, token := range tokens {
+			result = append(result, token.ID)
+		}
+		return result
+	}
+	g.Expect(ids(store.ListValid("project-a"))).To(Equal([]string{"a", "b"}))
+	g.Expect(ids(store.ListValid(""))).To(HaveLen(3))
+
+	store.Cleanup()
+	g.Expect(store.tokens).NotTo(HaveKey("expired"))
+}
+
+func TestTokenStoreSharesRevocationsBetweenReplicas(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	clientset := fake.NewClientset()
+
+	// Two replicas of the service share the revocations ConfigMap
+	first := NewTokenStore(NewConfigMapRevocations(clientset, "registry", "registry-auth-revocations"))
+	second := NewTokenStore(NewConfigMapRevocations(clientset, "registry", "registry-auth-revocations"))
+	g.Expect(first.Sync(ctx)).To(Succeed())
+
+	now := time.Now()
+	first.Record(IssuedToken{ID: "a", Namespace: "project-a", IssuedAt: now, ExpiresAt: now.Add(time.Minute)})
+	dropped, err := first.Revoke(ctx, "project-a", "leaked credentials")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(dropped).To(Equal(1))
+	g.Expect(first.IsRevoked("project-a")).To(BeTrue())
+	g.Expect(first.ListValid("project-a")).To(BeEmpty())
+
+	cm, err := clientset.CoreV1().ConfigMaps("registry").Get(ctx, "registry-auth-revocations", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm.Data).To(HaveKey("project-a"))
+
+	// The other replica enforces the revocation once it reloads them
+	g.Expect(second.IsRevoked("project-a")).To(BeFalse())
+	g.Expect(second.Sync(ctx)).To(Succeed())
+	g.Expect(second.IsRevoked("project-a")).To(BeTrue())
+	g.Expect(second.Revocations()).To(ConsistOf(HaveField("Reason", "leaked credentials")))
+
+	// Revocations survive restarts
+	restarted := NewTokenStore(NewConfigMapRevocations(clientset, "registry", "registry-auth-revocations"))
+	g.Expect(restarted.Sync(ctx)).To(Succeed())
+	g.Expect(restarted.IsRevoked("project-a")).To(BeTrue())
+
+	// Lifting the revocation on one replica reaches the others
+	_, err = second.Revoke(ctx, "project-b", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	restored, err := second.Restore(ctx, "project-a")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(restored).To(BeTrue())
+	restored, err = second.Restore(ctx, "project-a")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(restored).To(BeFalse())
+
+	g.Expect(first.Sync(ctx)).To(Succeed())
+	g.Expect(first.IsRevoked("project-a")).To(BeFalse())
+	g.Expect(first.IsRevoked("project-b")).To(BeTrue())
+}
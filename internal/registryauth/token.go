@@ -69,7 +69,8 @@ func NewTokenGenerator(privateKeyPath, issuer string, expirationSec int) (*Token
 }
 
 // GenerateToken creates a JWT token with the specified access grants
-func (tg *TokenGenerator) GenerateToken(subject, audience string, access []AccessEntry) (string, time.Time, error) {
+// and returns it together with the claims that were signed
+func (tg *TokenGenerator) GenerateToken(subject, audience string, access []AccessEntry) (string, *TokenClaims, error) {
 	now := time.Now()
 	expiresAt := now.Add(tg.expiration)
 
@@ -91,8 +92,8 @@ func (tg *TokenGenerator) GenerateToken(subject, audience string, access []Acces
 
 	tokenString, err := token.SignedString(tg.privateKey)
 	if err != nil {
-		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
+		return "", nil, fmt.Errorf("failed to sign token: %w", err)
 	}
 
-	return tokenString, expiresAt, nil
+	return tokenString, &claims, nil
 }
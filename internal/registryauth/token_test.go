@@ -0,0 +1,79 @@
+package registryauth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/onsi/gomega"
+)
+
+// newTestTokenGenerator writes a fresh RSA key and loads a token generator from it
+func newTestTokenGenerator(t *testing.T) (*TokenGenerator, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to encode key: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "tls.key")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	generator, err := NewTokenGenerator(path, "registry-token-issuer", 300)
+	if err != nil {
+		t.Fatalf("failed to create token generator: %v", err)
+	}
+	return generator, key
+}
+
+func TestGenerateTokenIsVerifiedWithThePublicKey(t *testing.T) {
+	g := NewWithT(t)
+	generator, key := newTestTokenGenerator(t)
+
+	access := []AccessEntry{{Type: "repository", Name: "project-a/app", Actions: []string{"pull", "push"}}}
+	signed, claims, err := generator.GenerateToken("project-a", "docker-registry", access)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(claims.ID).NotTo(BeEmpty())
+	g.Expect(claims.ExpiresAt.Sub(claims.IssuedAt.Time)).To(Equal(300 * time.Second))
+
+	verified := &TokenClaims{}
+	token, err := jwt.ParseWithClaims(signed, verified, func(token *jwt.Token) (interface{}, error) {
+		return &key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"RS256"}), jwt.WithIssuer("registry-token-issuer"), jwt.WithAudience("docker-registry"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(token.Header).To(HaveKeyWithValue("kid", "registry-auth-jwt-signer"))
+	g.Expect(verified.Subject).To(Equal("project-a"))
+	g.Expect(verified.Access).To(Equal(access))
+
+	// Tokens signed by another key are rejected
+	other, _ := newTestTokenGenerator(t)
+	forged, _, err := other.GenerateToken("project-a", "docker-registry", access)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = jwt.ParseWithClaims(forged, &TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return &key.PublicKey, nil
+	})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestNewTokenGeneratorRejectsInvalidKeys(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewTokenGenerator(filepath.Join(t.TempDir(), "missing.key"), "issuer", 300)
+	g.Expect(err).To(MatchError(ContainSubstring("failed to read private key")))
+
+	path := filepath.Join(t.TempDir(), "tls.key")
+	g.Expect(os.WriteFile(path, []byte("not a key"), 0o600)).To(Succeed())
+	_, err = NewTokenGenerator(path, "issuer", 300)
+	g.Expect(err).To(MatchError(ContainSubstring("failed to decode PEM block")))
+}
@@ -5,6 +5,8 @@ import (
 	"crypto/subtle"
 	"fmt"
 	"log"
+
+	"github.com/kibamail/kibaship/pkg/utils"
 )
 
 // Validator handles credential validation and namespace access control
@@ -57,3 +59,36 @@ func (v *Validator) ValidateCredentials(ctx context.Context, namespace, username
 	log.Printf("auth: credentials validated for namespace=%s", namespace)
 	return true
 }
+
+// ValidateRobotCredentials validates the credentials of a robot account against its Secret in
+// the project namespace. Robot accounts are cached by username since a namespace has several.
+// Returns true if credentials are valid
+func (v *Validator) ValidateRobotCredentials(ctx context.Context, namespace, robotName, username, password string) bool {
+	if cached, ok := v.cache.Get(username); ok {
+		if subtle.ConstantTimeCompare([]byte(cached.Password), []byte(password)) == 1 {
+			log.Printf("auth: cache hit for robot=%s", username)
+			return true
+		}
+	}
+
+	secretName := utils.GetRegistryRobotSecretName(robotName)
+	creds, err := v.k8sClient.GetCredentials(ctx, namespace, secretName)
+	if err != nil {
+		log.Printf("auth: failed to get robot credentials secret %s/%s: %v", namespace, secretName, err)
+		return false
+	}
+
+	if creds.Username != username {
+		log.Printf("auth: username mismatch for robot secret %s/%s", namespace, secretName)
+		return false
+	}
+
+	if subtle.ConstantTimeCompare([]byte(creds.Password), []byte(password)) != 1 {
+		log.Printf("auth: password mismatch for robot=%s", username)
+		return false
+	}
+
+	v.cache.Set(username, creds)
+	log.Printf("auth: credentials validated for robot=%s", username)
+	return true
+}
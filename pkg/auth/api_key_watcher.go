@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// APIKeySource provides the API key requests are authenticated against
+type APIKeySource interface {
+	APIKey() string
+}
+
+// staticAPIKey is an API key that never changes
+type staticAPIKey string
+
+// APIKey returns the static API key
+func (k staticAPIKey) APIKey() string {
+	return string(k)
+}
+
+// APIKeyWatcher keeps the API key of the shared secret in memory. The key is updated from a
+// watch on the secret, so every API server replica accepts the same key, including after a rotation.
+type APIKeyWatcher struct {
+	apiKey atomic.Value
+}
+
+// APIKey returns the current API key
+func (w *APIKeyWatcher) APIKey() string {
+	apiKey, _ := w.apiKey.Load().(string)
+	return apiKey
+}
+
+// update stores the API key of secret, secrets without a key are ignored
+func (w *APIKeyWatcher) update(obj interface{}) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+
+	apiKey := string(secret.Data[SecretKey])
+	if apiKey == "" || apiKey == w.APIKey() {
+		return
+	}
+
+	w.apiKey.Store(apiKey)
+	log.Printf("API key updated from secret %s", SecretName)
+}
+
+// WatchAPIKey starts watching the API key secret until ctx is done. The returned watcher holds
+// apiKey until the watch cache has synced, a deleted secret keeps the last known key.
+func (s *SecretManager) WatchAPIKey(ctx context.Context, apiKey string) (*APIKeyWatcher, error) {
+	watcher := &APIKeyWatcher{}
+	watcher.apiKey.Store(apiKey)
+
+	factory := informers.NewSharedInformerFactoryWithOptions(s.client, 0,
+		informers.WithNamespace(s.namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", SecretName).String()
+		}),
+	)
+
+	informer := factory.Core().V1().Secrets().Informer()
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.update,
+		UpdateFunc: func(_, obj interface{}) { watcher.update(obj) },
+	}); err != nil {
+		return nil, fmt.Errorf("failed to watch secret %s: %w", SecretName, err)
+	}
+
+	factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return nil, fmt.Errorf("failed to sync secret %s", SecretName)
+	}
+
+	return watcher, nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package auth
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
 
@@ -25,13 +26,19 @@ import (
 
 // APIKeyAuthenticator handles API key authentication
 type APIKeyAuthenticator struct {
-	apiKey string
+	source APIKeySource
 }
 
 // NewAPIKeyAuthenticator creates a new API key authenticator
 func NewAPIKeyAuthenticator(apiKey string) *APIKeyAuthenticator {
+	return NewAPIKeyAuthenticatorWithSource(staticAPIKey(apiKey))
+}
+
+// NewAPIKeyAuthenticatorWithSource creates an API key authenticator reading the key from source
+// on every request
+func NewAPIKeyAuthenticatorWithSource(source APIKeySource) *APIKeyAuthenticator {
 	return &APIKeyAuthenticator{
-		apiKey: apiKey,
+		source: source,
 	}
 }
 
@@ -61,7 +68,8 @@ func (a *APIKeyAuthenticator) Middleware() gin.HandlerFunc {
 		}
 
 		token := strings.TrimPrefix(authHeader, bearerPrefix)
-		if token != a.apiKey {
+		apiKey := a.source.APIKey()
+		if apiKey == "" || subtle.ConstantTimeCompare([]byte(token), []byte(apiKey)) != 1 {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error":   "Unauthorized",
 				"message": "Invalid API key",
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ACMEDNSProvider selects the cert-manager DNS01 solver answering challenges for the platform zones
+type ACMEDNSProvider string
+
+const (
+	// ACMEDNSProviderAcmeDNS answers every challenge through the acme-dns server of the platform
+	ACMEDNSProviderAcmeDNS ACMEDNSProvider = ""
+
+	// ACMEDNSProviderCloudflare uses the cert-manager Cloudflare solver
+	ACMEDNSProviderCloudflare ACMEDNSProvider = "cloudflare"
+
+	// ACMEDNSProviderRoute53 uses the cert-manager Route53 solver
+	ACMEDNSProviderRoute53 ACMEDNSProvider = "route53"
+
+	// ACMEDNSProviderHetzner uses the cert-manager-webhook-hetzner webhook solver, which must be installed
+	ACMEDNSProviderHetzner ACMEDNSProvider = "hetzner"
+)
+
+const (
+	// ACMEDNSCredentialsSecretName holds the DNS provider credentials. cert-manager reads the Secrets
+	// of ClusterIssuers from its cluster resource namespace, cert-manager by default.
+	// Keys: api-token (cloudflare), access-key-id and secret-access-key (route53), api-key (hetzner)
+	ACMEDNSCredentialsSecretName = "kibaship-dns-credentials"
+
+	// DefaultHetznerDNSAPIURL is the Hetzner DNS API the webhook solver calls
+	DefaultHetznerDNSAPIURL = "https://dns.hetzner.com/api/v1"
+)
+
+// ACMEDNSConfig holds the DNS01 solver of the DNS zones the platform operator controls. Challenges
+// for other domains, such as the custom domains of customers delegating _acme-challenge, are
+// always answered by acme-dns.
+type ACMEDNSConfig struct {
+	// Provider is the DNS provider of Zones, empty answers every challenge through acme-dns
+	Provider ACMEDNSProvider
+
+	// Zones are the DNS zones solved by Provider, the operator domain when none is configured
+	Zones []string
+
+	// Route53Region is the AWS region of the Route53 API
+	Route53Region string
+	// Route53HostedZoneID pins the hosted zone, Route53 looks it up from the zone name otherwise
+	Route53HostedZoneID string
+
+	// HetznerGroupName is the API group the Hetzner webhook solver was installed with
+	HetznerGroupName string
+}
+
+// Enabled reports whether challenges of the platform zones are answered by a DNS provider
+func (a ACMEDNSConfig) Enabled() bool {
+	return a.Provider != ACMEDNSProviderAcmeDNS
+}
+
+// ParseACMEDNSConfig reads and validates the certs.dns_* keys of the operator ConfigMap
+func ParseACMEDNSConfig(data map[string]string) (ACMEDNSConfig, error) {
+	cfg := ACMEDNSConfig{
+		Provider:            ACMEDNSProvider(strings.TrimSpace(data[ConfigKeyACMEDNSProvider])),
+		Zones:               splitList(data[ConfigKeyACMEDNSZones]),
+		Route53Region:       strings.TrimSpace(data[ConfigKeyACMEDNSRoute53Region]),
+		Route53HostedZoneID: strings.TrimSpace(data[ConfigKeyACMEDNSRoute53HostedZoneID]),
+		HetznerGroupName:    strings.TrimSpace(data[ConfigKeyACMEDNSHetznerGroupName]),
+	}
+
+	switch cfg.Provider {
+	case ACMEDNSProviderAcmeDNS:
+		if len(cfg.Zones) > 0 {
+			return cfg, fmt.Errorf("%s requires %s", ConfigKeyACMEDNSZones, ConfigKeyACMEDNSProvider)
+		}
+		return cfg, nil
+	case ACMEDNSProviderCloudflare:
+	case ACMEDNSProviderRoute53:
+		if cfg.Route53Region == "" {
+			return cfg, fmt.Errorf("%s is required when %s is route53", ConfigKeyACMEDNSRoute53Region, ConfigKeyACMEDNSProvider)
+		}
+	case ACMEDNSProviderHetzner:
+		if cfg.HetznerGroupName == "" {
+			return cfg, fmt.Errorf("%s is required when %s is hetzner", ConfigKeyACMEDNSHetznerGroupName, ConfigKeyACMEDNSProvider)
+		}
+	default:
+		return cfg, fmt.Errorf("invalid value for %s: %s (must be '%s', '%s' or '%s')",
+			ConfigKeyACMEDNSProvider, cfg.Provider, ACMEDNSProviderCloudflare, ACMEDNSProviderRoute53, ACMEDNSProviderHetzner)
+	}
+
+	if len(cfg.Zones) == 0 {
+		if domain := strings.TrimSpace(data[ConfigKeyDomain]); domain != "" {
+			cfg.Zones = []string{domain}
+		}
+	}
+	if len(cfg.Zones) == 0 {
+		return cfg, fmt.Errorf("%s is required when %s is set", ConfigKeyACMEDNSZones, ConfigKeyACMEDNSProvider)
+	}
+	for i, zone := range cfg.Zones {
+		zone = strings.TrimSuffix(strings.ToLower(zone), ".")
+		if zone == "" || strings.ContainsAny(zone, "*/ ") {
+			return cfg, fmt.Errorf("invalid value for %s: %q is not a DNS zone", ConfigKeyACMEDNSZones, cfg.Zones[i])
+		}
+		cfg.Zones[i] = zone
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseACMEDNSConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg, err := ParseACMEDNSConfig(map[string]string{ConfigKeyDomain: "example.com"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Enabled()).To(BeFalse())
+	g.Expect(cfg.Zones).To(BeEmpty())
+
+	// The operator domain is solved by the provider unless zones are listed
+	cfg, err = ParseACMEDNSConfig(map[string]string{
+		ConfigKeyDomain:          "example.com",
+		ConfigKeyACMEDNSProvider: "cloudflare",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Enabled()).To(BeTrue())
+	g.Expect(cfg.Zones).To(Equal([]string{"example.com"}))
+
+	cfg, err = ParseACMEDNSConfig(map[string]string{
+		ConfigKeyDomain:                     "example.com",
+		ConfigKeyACMEDNSProvider:            "route53",
+		ConfigKeyACMEDNSZones:               "Example.com., example.org",
+		ConfigKeyACMEDNSRoute53Region:       "eu-west-1",
+		ConfigKeyACMEDNSRoute53HostedZoneID: "Z123",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg).To(Equal(ACMEDNSConfig{
+		Provider:            ACMEDNSProviderRoute53,
+		Zones:               []string{"example.com", "example.org"},
+		Route53Region:       "eu-west-1",
+		Route53HostedZoneID: "Z123",
+	}))
+}
+
+func TestParseACMEDNSConfigValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ParseACMEDNSConfig(map[string]string{ConfigKeyACMEDNSProvider: "digitalocean"})
+	g.Expect(err).To(MatchError(ContainSubstring("invalid value for certs.dns_provider")))
+
+	_, err = ParseACMEDNSConfig(map[string]string{ConfigKeyACMEDNSZones: "example.com"})
+	g.Expect(err).To(MatchError(ContainSubstring("certs.dns_zones requires certs.dns_provider")))
+
+	_, err = ParseACMEDNSConfig(map[string]string{ConfigKeyDomain: "example.com", ConfigKeyACMEDNSProvider: "route53"})
+	g.Expect(err).To(MatchError(ContainSubstring("certs.route53_region is required")))
+
+	_, err = ParseACMEDNSConfig(map[string]string{ConfigKeyDomain: "example.com", ConfigKeyACMEDNSProvider: "hetzner"})
+	g.Expect(err).To(MatchError(ContainSubstring("certs.hetzner_group_name is required")))
+
+	_, err = ParseACMEDNSConfig(map[string]string{ConfigKeyACMEDNSProvider: "cloudflare", ConfigKeyACMEDNSZones: "*.example.com"})
+	g.Expect(err).To(MatchError(ContainSubstring("is not a DNS zone")))
+}
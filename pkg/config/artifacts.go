@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ArtifactsProvider selects where pipeline artifacts are stored
+type ArtifactsProvider string
+
+const (
+	// ArtifactsProviderNone disables artifact collection
+	ArtifactsProviderNone ArtifactsProvider = ""
+
+	// ArtifactsProviderPVC stores artifacts on a volume of the artifact service
+	ArtifactsProviderPVC ArtifactsProvider = "pvc"
+
+	// ArtifactsProviderS3 stores artifacts in an S3 compatible bucket behind the artifact service
+	ArtifactsProviderS3 ArtifactsProvider = "s3"
+)
+
+const (
+	// DefaultArtifactsStorageSize is the artifact volume size when artifacts.storage_size is not set
+	DefaultArtifactsStorageSize = "20Gi"
+
+	// ArtifactsNamespace is the namespace the operator installs the artifact service into
+	ArtifactsNamespace = "kibaship-artifacts"
+
+	// ArtifactsServiceURL is the in-cluster address of the artifact service. Pipelines upload
+	// artifacts to it and the API server lists and downloads them from it over WebDAV.
+	ArtifactsServiceURL = "http://artifacts." + ArtifactsNamespace + ".svc:8080"
+
+	// ArtifactsS3AccessKeyIDKey is the key of the S3 access key ID in the credentials Secret
+	ArtifactsS3AccessKeyIDKey = "access_key_id"
+
+	// ArtifactsS3SecretAccessKeyKey is the key of the S3 secret access key in the credentials Secret
+	ArtifactsS3SecretAccessKeyKey = "secret_access_key"
+)
+
+// ArtifactsConfig holds the pipeline artifact storage configuration
+type ArtifactsConfig struct {
+	// Provider is the artifact storage backend, empty when artifacts are not collected
+	Provider ArtifactsProvider
+
+	// StorageSize is the size of the artifact volume of the pvc provider
+	StorageSize string
+
+	// StorageClass is the storage class of the artifact volume, the cluster default when empty
+	StorageClass string
+
+	// S3Endpoint is the URL of the S3 compatible object store
+	S3Endpoint string
+
+	// S3Bucket is the bucket artifacts are stored in
+	S3Bucket string
+
+	// S3Region is the region of the bucket
+	S3Region string
+
+	// S3CredentialsSecret names a Secret in the kibaship-artifacts namespace holding the
+	// access_key_id and secret_access_key of the bucket
+	S3CredentialsSecret string
+}
+
+// Enabled reports whether pipeline artifacts are collected
+func (a ArtifactsConfig) Enabled() bool {
+	return a.Provider != ArtifactsProviderNone
+}
+
+// Endpoint returns the base URL of the artifact service
+func (a ArtifactsConfig) Endpoint() string {
+	return ArtifactsServiceURL
+}
+
+// DeploymentArtifactsPath is the artifact service collection holding the artifacts of a deployment
+func DeploymentArtifactsPath(deploymentUUID string) string {
+	return "deployments/" + deploymentUUID
+}
+
+// ParseArtifactsConfig reads and validates the artifacts.* keys of the operator ConfigMap
+func ParseArtifactsConfig(data map[string]string) (ArtifactsConfig, error) {
+	cfg := ArtifactsConfig{
+		Provider:            ArtifactsProvider(strings.TrimSpace(data[ConfigKeyArtifactsProvider])),
+		StorageSize:         strings.TrimSpace(data[ConfigKeyArtifactsStorageSize]),
+		StorageClass:        strings.TrimSpace(data[ConfigKeyArtifactsStorageClass]),
+		S3Endpoint:          strings.TrimRight(strings.TrimSpace(data[ConfigKeyArtifactsS3Endpoint]), "/"),
+		S3Bucket:            strings.TrimSpace(data[ConfigKeyArtifactsS3Bucket]),
+		S3Region:            strings.TrimSpace(data[ConfigKeyArtifactsS3Region]),
+		S3CredentialsSecret: strings.TrimSpace(data[ConfigKeyArtifactsS3CredentialsSecret]),
+	}
+
+	switch cfg.Provider {
+	case ArtifactsProviderNone:
+		return ArtifactsConfig{}, nil
+	case ArtifactsProviderPVC:
+		if cfg.StorageSize == "" {
+			cfg.StorageSize = DefaultArtifactsStorageSize
+		}
+		if _, err := resource.ParseQuantity(cfg.StorageSize); err != nil {
+			return cfg, fmt.Errorf("invalid value for %s: %s (must be a quantity such as 20Gi)", ConfigKeyArtifactsStorageSize, cfg.StorageSize)
+		}
+	case ArtifactsProviderS3:
+		u, err := url.Parse(cfg.S3Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return cfg, fmt.Errorf("invalid value for %s: %q (must be an http or https URL)", ConfigKeyArtifactsS3Endpoint, cfg.S3Endpoint)
+		}
+		if cfg.S3Bucket == "" {
+			return cfg, fmt.Errorf("%s is required when %s is %s", ConfigKeyArtifactsS3Bucket, ConfigKeyArtifactsProvider, ArtifactsProviderS3)
+		}
+		if cfg.S3CredentialsSecret == "" {
+			return cfg, fmt.Errorf("%s is required when %s is %s", ConfigKeyArtifactsS3CredentialsSecret, ConfigKeyArtifactsProvider, ArtifactsProviderS3)
+		}
+	default:
+		return cfg, fmt.Errorf("invalid value for %s: %s (must be '%s' or '%s')",
+			ConfigKeyArtifactsProvider, cfg.Provider, ArtifactsProviderPVC, ArtifactsProviderS3)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseArtifactsConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	artifacts, err := ParseArtifactsConfig(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(artifacts.Enabled()).To(BeFalse())
+
+	artifacts, err = ParseArtifactsConfig(map[string]string{ConfigKeyArtifactsProvider: "pvc"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(artifacts.Enabled()).To(BeTrue())
+	g.Expect(artifacts.StorageSize).To(Equal(DefaultArtifactsStorageSize))
+	g.Expect(artifacts.Endpoint()).To(Equal(ArtifactsServiceURL))
+
+	artifacts, err = ParseArtifactsConfig(map[string]string{
+		ConfigKeyArtifactsProvider:            "s3",
+		ConfigKeyArtifactsS3Endpoint:          "https://s3.eu-central-1.amazonaws.com/",
+		ConfigKeyArtifactsS3Bucket:            "kibaship-artifacts",
+		ConfigKeyArtifactsS3Region:            "eu-central-1",
+		ConfigKeyArtifactsS3CredentialsSecret: "artifacts-s3-credentials",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(artifacts.Provider).To(Equal(ArtifactsProviderS3))
+	g.Expect(artifacts.S3Endpoint).To(Equal("https://s3.eu-central-1.amazonaws.com"))
+	g.Expect(artifacts.StorageSize).To(BeEmpty())
+}
+
+func TestParseArtifactsConfigValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ParseArtifactsConfig(map[string]string{ConfigKeyArtifactsProvider: "gcs"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid value for artifacts.provider"))
+
+	_, err = ParseArtifactsConfig(map[string]string{ConfigKeyArtifactsProvider: "pvc", ConfigKeyArtifactsStorageSize: "lots"})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ParseArtifactsConfig(map[string]string{ConfigKeyArtifactsProvider: "s3", ConfigKeyArtifactsS3Endpoint: "s3.amazonaws.com"})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ParseArtifactsConfig(map[string]string{
+		ConfigKeyArtifactsProvider:   "s3",
+		ConfigKeyArtifactsS3Endpoint: "https://s3.amazonaws.com",
+		ConfigKeyArtifactsS3Bucket:   "kibaship-artifacts",
+	})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("artifacts.s3_credentials_secret is required"))
+}
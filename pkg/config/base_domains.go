@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// baseDomainPattern matches the base domains Projects and Applications may set
+var baseDomainPattern = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// BaseDomainPolicy lists the base domains Projects and Applications may set in spec.baseDomain.
+// The operator provisions a wildcard certificate and a listener on the shared Gateway for every
+// custom base domain, so only domains the platform operator verified may be used.
+type BaseDomainPolicy struct {
+	// Domain is the operator domain, always allowed
+	Domain string
+
+	// Allowed are the base domains that may be used besides Domain, their subdomains included.
+	// Without any, no custom base domain may be used.
+	Allowed []string
+}
+
+// Check returns an error explaining why a base domain may not be used, nil when it may
+func (p BaseDomainPolicy) Check(baseDomain string) error {
+	if baseDomain == "" || baseDomain == p.Domain {
+		return nil
+	}
+	for _, allowed := range p.Allowed {
+		if baseDomain == allowed || strings.HasSuffix(baseDomain, "."+allowed) {
+			return nil
+		}
+	}
+	if len(p.Allowed) == 0 {
+		return fmt.Errorf("base domain %s is not allowed, the platform does not allow custom base domains", baseDomain)
+	}
+	return fmt.Errorf("base domain %s is not allowed, use one of %s or a subdomain of them",
+		baseDomain, strings.Join(p.Allowed, ", "))
+}
+
+// BaseDomainPolicy returns the base domain policy of the configuration
+func (c *OperatorConfiguration) BaseDomainPolicy() BaseDomainPolicy {
+	return BaseDomainPolicy{Domain: c.Domain, Allowed: c.AllowedBaseDomains}
+}
+
+// ParseAllowedBaseDomains reads and validates base_domains.allowed, the list of base domains
+// Projects and Applications may set besides the operator domain
+func ParseAllowedBaseDomains(data map[string]string) ([]string, error) {
+	var domains []string
+	for _, domain := range splitList(data[ConfigKeyBaseDomainsAllowed]) {
+		domain = strings.TrimSuffix(domain, ".")
+		if len(domain) > 200 || !baseDomainPattern.MatchString(domain) {
+			return nil, fmt.Errorf("invalid value for %s: %q is not a lowercase domain name", ConfigKeyBaseDomainsAllowed, domain)
+		}
+		domains = append(domains, domain)
+	}
+	return domains, nil
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseAllowedBaseDomains(t *testing.T) {
+	g := NewWithT(t)
+
+	domains, err := ParseAllowedBaseDomains(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(domains).To(BeEmpty())
+
+	domains, err = ParseAllowedBaseDomains(map[string]string{ConfigKeyBaseDomainsAllowed: "customer.com, apps.other.io."})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(domains).To(Equal([]string{"customer.com", "apps.other.io"}))
+
+	for _, domain := range []string{"Customer.com", "*.customer.com", "localhost", "https://customer.com"} {
+		_, err := ParseAllowedBaseDomains(map[string]string{ConfigKeyBaseDomainsAllowed: domain})
+		g.Expect(err).To(MatchError(ContainSubstring("invalid value for base_domains.allowed")), domain)
+	}
+}
+
+func TestBaseDomainPolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	// Only the operator domain may be used without an allowlist
+	policy := BaseDomainPolicy{Domain: "kibaship.example.com"}
+	g.Expect(policy.Check("")).To(Succeed())
+	g.Expect(policy.Check("kibaship.example.com")).To(Succeed())
+	g.Expect(policy.Check("customer.com")).To(MatchError(ContainSubstring("does not allow custom base domains")))
+
+	// Allowed domains cover their subdomains, not domains sharing their suffix
+	policy.Allowed = []string{"customer.com"}
+	g.Expect(policy.Check("customer.com")).To(Succeed())
+	g.Expect(policy.Check("eu.customer.com")).To(Succeed())
+	g.Expect(policy.Check("evilcustomer.com")).To(MatchError(ContainSubstring("use one of customer.com")))
+	g.Expect(policy.Check("bank.com")).NotTo(Succeed())
+}
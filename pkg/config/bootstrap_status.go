@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// BootstrapStatusConfigMapName is the ConfigMap in the operator namespace the operator
+	// records the outcome of its bootstrap steps in
+	BootstrapStatusConfigMapName = "kibaship-bootstrap-status"
+
+	// BootstrapStatusKey holds the JSON encoded bootstrap steps in the status ConfigMap
+	BootstrapStatusKey = "steps"
+)
+
+// BootstrapStepState is the outcome of the latest attempt of a bootstrap step
+type BootstrapStepState string
+
+const (
+	// BootstrapStepRunning is a step being attempted
+	BootstrapStepRunning BootstrapStepState = "Running"
+	// BootstrapStepSucceeded is a step whose latest attempt succeeded
+	BootstrapStepSucceeded BootstrapStepState = "Succeeded"
+	// BootstrapStepFailed is a step whose latest attempt failed, it is retried at NextRetryTime
+	BootstrapStepFailed BootstrapStepState = "Failed"
+)
+
+// BootstrapStepStatus records the attempts of a bootstrap step
+type BootstrapStepStatus struct {
+	// Step is the position of the step in the bootstrap sequence, starting at 1
+	Step int `json:"step"`
+	// Name identifies the step, such as ingress-certificates
+	Name string `json:"name"`
+	// State is the outcome of the latest attempt
+	State BootstrapStepState `json:"state"`
+	// Error is the error of the latest attempt when it failed
+	Error string `json:"error,omitempty"`
+	// Attempts counts the attempts since the operator started
+	Attempts int `json:"attempts"`
+	// LastAttemptTime is when the latest attempt started
+	LastAttemptTime *time.Time `json:"lastAttemptTime,omitempty"`
+	// LastSuccessTime is when the step last succeeded
+	LastSuccessTime *time.Time `json:"lastSuccessTime,omitempty"`
+	// NextRetryTime is when a failed step is attempted again
+	NextRetryTime *time.Time `json:"nextRetryTime,omitempty"`
+}
+
+// ParseBootstrapStatus decodes the bootstrap steps recorded in the status ConfigMap, ordered
+// as they run. A ConfigMap without steps yields none.
+func ParseBootstrapStatus(data map[string]string) ([]BootstrapStepStatus, error) {
+	raw := data[BootstrapStatusKey]
+	if raw == "" {
+		return nil, nil
+	}
+	var steps []BootstrapStepStatus
+	if err := json.Unmarshal([]byte(raw), &steps); err != nil {
+		return nil, fmt.Errorf("invalid %s in ConfigMap %s/%s: %w", BootstrapStatusKey, OperatorNamespace, BootstrapStatusConfigMapName, err)
+	}
+	return steps, nil
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseBootstrapStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	steps, err := ParseBootstrapStatus(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(steps).To(BeEmpty())
+
+	steps, err = ParseBootstrapStatus(map[string]string{
+		BootstrapStatusKey: `[{"step":4,"name":"ingress-certificates","state":"Failed","error":"no matches for kind Certificate","attempts":3,"nextRetryTime":"2025-01-01T00:04:00Z"}]`,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(steps).To(HaveLen(1))
+	g.Expect(steps[0].Step).To(Equal(4))
+	g.Expect(steps[0].State).To(Equal(BootstrapStepFailed))
+	g.Expect(steps[0].Error).To(Equal("no matches for kind Certificate"))
+	g.Expect(steps[0].Attempts).To(Equal(3))
+	g.Expect(steps[0].NextRetryTime).NotTo(BeNil())
+	g.Expect(steps[0].LastSuccessTime).To(BeNil())
+
+	_, err = ParseBootstrapStatus(map[string]string{BootstrapStatusKey: "not json"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid steps in ConfigMap kibaship/kibaship-bootstrap-status"))
+}
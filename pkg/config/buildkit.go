@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultBuildKitBuildsPerBuilder is how many builds a BuildKit daemon of the pool runs at
+	// once before the pool grows, when buildkit.builds_per_builder is not set
+	DefaultBuildKitBuildsPerBuilder = 4
+)
+
+// BuildKitConfig holds the pool of BuildKit daemons the operator manages. The pool grows and
+// shrinks with the running builds between its minimum and maximum size, and each build is
+// routed to a healthy daemon. Without a pool, builds share the buildkitd Deployment of the
+// buildkit namespace.
+type BuildKitConfig struct {
+	// MinBuilders is the number of daemons kept running without builds
+	MinBuilders int
+
+	// MaxBuilders caps the pool, zero when the operator does not manage a pool
+	MaxBuilders int
+
+	// BuildsPerBuilder is how many builds a daemon runs at once before the pool grows
+	BuildsPerBuilder int
+}
+
+// Enabled reports whether the operator manages a pool of BuildKit daemons
+func (b BuildKitConfig) Enabled() bool {
+	return b.MaxBuilders > 0
+}
+
+// ParseBuildKitConfig reads and validates the buildkit.* keys of the operator ConfigMap.
+// buildkit.pool_max_builders enables the pool, buildkit.pool_min_builders defaults to one and
+// buildkit.builds_per_builder to DefaultBuildKitBuildsPerBuilder.
+func ParseBuildKitConfig(data map[string]string) (BuildKitConfig, error) {
+	cfg := BuildKitConfig{BuildsPerBuilder: DefaultBuildKitBuildsPerBuilder}
+
+	parse := func(key string, minimum int) (int, bool, error) {
+		value := strings.TrimSpace(data[key])
+		if value == "" {
+			return 0, false, nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < minimum {
+			return 0, false, fmt.Errorf("invalid value for %s: %q (must be an integer of at least %d)", key, value, minimum)
+		}
+		return n, true, nil
+	}
+
+	maxBuilders, ok, err := parse(ConfigKeyBuildKitPoolMaxBuilders, 1)
+	if err != nil {
+		return cfg, err
+	}
+	if !ok {
+		for _, key := range []string{ConfigKeyBuildKitPoolMinBuilders, ConfigKeyBuildKitBuildsPerBuilder} {
+			if strings.TrimSpace(data[key]) != "" {
+				return cfg, fmt.Errorf("%s is required when %s is set", ConfigKeyBuildKitPoolMaxBuilders, key)
+			}
+		}
+		return cfg, nil
+	}
+	cfg.MaxBuilders = maxBuilders
+
+	cfg.MinBuilders = 1
+	if minBuilders, ok, err := parse(ConfigKeyBuildKitPoolMinBuilders, 0); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.MinBuilders = minBuilders
+	}
+	if cfg.MinBuilders > cfg.MaxBuilders {
+		return cfg, fmt.Errorf("invalid value for %s: %d (must not exceed %s, %d)",
+			ConfigKeyBuildKitPoolMinBuilders, cfg.MinBuilders, ConfigKeyBuildKitPoolMaxBuilders, cfg.MaxBuilders)
+	}
+
+	if perBuilder, ok, err := parse(ConfigKeyBuildKitBuildsPerBuilder, 1); err != nil {
+		return cfg, err
+	} else if ok {
+		cfg.BuildsPerBuilder = perBuilder
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseBuildKitConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg, err := ParseBuildKitConfig(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Enabled()).To(BeFalse())
+
+	cfg, err = ParseBuildKitConfig(map[string]string{ConfigKeyBuildKitPoolMaxBuilders: "4"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg).To(Equal(BuildKitConfig{MinBuilders: 1, MaxBuilders: 4, BuildsPerBuilder: DefaultBuildKitBuildsPerBuilder}))
+	g.Expect(cfg.Enabled()).To(BeTrue())
+
+	cfg, err = ParseBuildKitConfig(map[string]string{
+		ConfigKeyBuildKitPoolMaxBuilders:  "6",
+		ConfigKeyBuildKitPoolMinBuilders:  "0",
+		ConfigKeyBuildKitBuildsPerBuilder: " 2 ",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg).To(Equal(BuildKitConfig{MinBuilders: 0, MaxBuilders: 6, BuildsPerBuilder: 2}))
+}
+
+func TestParseBuildKitConfigValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ParseBuildKitConfig(map[string]string{ConfigKeyBuildKitPoolMaxBuilders: "0"})
+	g.Expect(err).To(MatchError(ContainSubstring("invalid value for buildkit.pool_max_builders")))
+
+	_, err = ParseBuildKitConfig(map[string]string{ConfigKeyBuildKitPoolMinBuilders: "2"})
+	g.Expect(err).To(MatchError(ContainSubstring("buildkit.pool_max_builders is required")))
+
+	_, err = ParseBuildKitConfig(map[string]string{
+		ConfigKeyBuildKitPoolMaxBuilders: "2",
+		ConfigKeyBuildKitPoolMinBuilders: "3",
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("must not exceed")))
+
+	_, err = ParseBuildKitConfig(map[string]string{
+		ConfigKeyBuildKitPoolMaxBuilders:  "2",
+		ConfigKeyBuildKitBuildsPerBuilder: "many",
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("invalid value for buildkit.builds_per_builder")))
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// BuildsConfig holds the scheduling of Tekton build pods. Builds are kept on a dedicated node
+// pool so heavy image builds do not compete with latency-sensitive application pods.
+type BuildsConfig struct {
+	// NodeSelector restricts build pods to nodes carrying these labels
+	NodeSelector map[string]string
+
+	// Tolerations let build pods run on nodes tainted for builds
+	Tolerations []corev1.Toleration
+
+	// MaxConcurrentPerProject caps the builds running at once in a project, further builds are
+	// queued until a build finishes. Zero runs every build immediately.
+	MaxConcurrentPerProject int
+
+	// RemoteKubeconfigSecret names the Secret of the operator namespace holding, under the
+	// kubeconfig key, the credentials of a dedicated build cluster. Empty runs remote builds
+	// in the operator cluster.
+	RemoteKubeconfigSecret string
+
+	// RemoteNamespaces is the pool of namespaces builds are dispatched to. Each project always
+	// builds in the same namespace of the pool. Empty runs builds in the project namespaces.
+	RemoteNamespaces []string
+
+	// RemoteRegistry is the host the build cluster pushes images to. It must reach the registry
+	// of the operator cluster, which application pods pull the built images from.
+	RemoteRegistry string
+}
+
+// Enabled reports whether build pods are scheduled onto a dedicated node pool
+func (b BuildsConfig) Enabled() bool {
+	return len(b.NodeSelector) > 0 || len(b.Tolerations) > 0
+}
+
+// Remote reports whether builds are dispatched to a namespace pool instead of the project namespaces
+func (b BuildsConfig) Remote() bool {
+	return len(b.RemoteNamespaces) > 0
+}
+
+// ParseBuildsConfig reads and validates the builds.* keys of the operator ConfigMap.
+// builds.node_selector is a list of label=value pairs, builds.tolerations a list of taints in
+// kubectl taint syntax (key=value:Effect, key:Effect or key to tolerate all effects) and
+// builds.max_concurrent_per_project a non-negative number of builds. builds.remote_namespaces
+// is a list of namespaces, builds.remote_registry a registry host, required along with
+// builds.remote_kubeconfig_secret.
+func ParseBuildsConfig(data map[string]string) (BuildsConfig, error) {
+	cfg := BuildsConfig{}
+
+	for _, pair := range splitList(data[ConfigKeyBuildsNodeSelector]) {
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok {
+			return cfg, fmt.Errorf("invalid value for %s: %q (must be label=value)", ConfigKeyBuildsNodeSelector, pair)
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return cfg, fmt.Errorf("invalid value for %s: label %q: %s", ConfigKeyBuildsNodeSelector, key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return cfg, fmt.Errorf("invalid value for %s: value %q: %s", ConfigKeyBuildsNodeSelector, value, strings.Join(errs, ", "))
+		}
+		if cfg.NodeSelector == nil {
+			cfg.NodeSelector = map[string]string{}
+		}
+		cfg.NodeSelector[key] = value
+	}
+
+	for _, taint := range splitList(data[ConfigKeyBuildsTolerations]) {
+		toleration, err := parseToleration(taint)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid value for %s: %w", ConfigKeyBuildsTolerations, err)
+		}
+		cfg.Tolerations = append(cfg.Tolerations, toleration)
+	}
+
+	if value := strings.TrimSpace(data[ConfigKeyBuildsMaxConcurrentPerProject]); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return cfg, fmt.Errorf("invalid value for %s: %q (must be a non-negative integer)", ConfigKeyBuildsMaxConcurrentPerProject, value)
+		}
+		cfg.MaxConcurrentPerProject = limit
+	}
+
+	for _, namespace := range splitList(data[ConfigKeyBuildsRemoteNamespaces]) {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return cfg, fmt.Errorf("invalid value for %s: namespace %q: %s", ConfigKeyBuildsRemoteNamespaces, namespace, strings.Join(errs, ", "))
+		}
+		if !slices.Contains(cfg.RemoteNamespaces, namespace) {
+			cfg.RemoteNamespaces = append(cfg.RemoteNamespaces, namespace)
+		}
+	}
+
+	cfg.RemoteKubeconfigSecret = strings.TrimSpace(data[ConfigKeyBuildsRemoteKubeconfigSecret])
+	if cfg.RemoteKubeconfigSecret != "" {
+		if errs := validation.IsDNS1123Subdomain(cfg.RemoteKubeconfigSecret); len(errs) > 0 {
+			return cfg, fmt.Errorf("invalid value for %s: %q: %s", ConfigKeyBuildsRemoteKubeconfigSecret, cfg.RemoteKubeconfigSecret, strings.Join(errs, ", "))
+		}
+		if !cfg.Remote() {
+			return cfg, fmt.Errorf("%s is required when %s is set", ConfigKeyBuildsRemoteNamespaces, ConfigKeyBuildsRemoteKubeconfigSecret)
+		}
+	}
+
+	cfg.RemoteRegistry = strings.TrimSpace(data[ConfigKeyBuildsRemoteRegistry])
+	if cfg.RemoteRegistry != "" {
+		if !cfg.Remote() {
+			return cfg, fmt.Errorf("%s is only valid along with %s", ConfigKeyBuildsRemoteRegistry, ConfigKeyBuildsRemoteNamespaces)
+		}
+		if strings.Contains(cfg.RemoteRegistry, "://") || strings.Contains(cfg.RemoteRegistry, "/") {
+			return cfg, fmt.Errorf("invalid value for %s: %q (must be a registry host, optionally with a port)", ConfigKeyBuildsRemoteRegistry, cfg.RemoteRegistry)
+		}
+	} else if cfg.RemoteKubeconfigSecret != "" {
+		return cfg, fmt.Errorf("%s is required when %s is set", ConfigKeyBuildsRemoteRegistry, ConfigKeyBuildsRemoteKubeconfigSecret)
+	}
+
+	return cfg, nil
+}
+
+// parseToleration builds the toleration of a taint written as key=value:Effect
+func parseToleration(taint string) (corev1.Toleration, error) {
+	spec, effect, _ := strings.Cut(taint, ":")
+	key, value, hasValue := strings.Cut(spec, "=")
+
+	toleration := corev1.Toleration{
+		Key:      strings.TrimSpace(key),
+		Operator: corev1.TolerationOpExists,
+		Effect:   corev1.TaintEffect(strings.TrimSpace(effect)),
+	}
+	if hasValue {
+		toleration.Operator = corev1.TolerationOpEqual
+		toleration.Value = strings.TrimSpace(value)
+	}
+
+	if errs := validation.IsQualifiedName(toleration.Key); len(errs) > 0 {
+		return toleration, fmt.Errorf("taint key %q: %s", toleration.Key, strings.Join(errs, ", "))
+	}
+	if errs := validation.IsValidLabelValue(toleration.Value); len(errs) > 0 {
+		return toleration, fmt.Errorf("taint value %q: %s", toleration.Value, strings.Join(errs, ", "))
+	}
+
+	switch toleration.Effect {
+	case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		return toleration, fmt.Errorf("taint effect %q (must be '%s', '%s' or '%s')", toleration.Effect,
+			corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute)
+	}
+
+	return toleration, nil
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseBuildsConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	builds, err := ParseBuildsConfig(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(builds.Enabled()).To(BeFalse())
+
+	builds, err = ParseBuildsConfig(map[string]string{
+		ConfigKeyBuildsNodeSelector: "kibaship.com/pool=builds, kubernetes.io/arch=amd64",
+		ConfigKeyBuildsTolerations:  "kibaship.com/pool=builds:NoSchedule, dedicated",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(builds.Enabled()).To(BeTrue())
+	g.Expect(builds.NodeSelector).To(Equal(map[string]string{
+		"kibaship.com/pool":  "builds",
+		"kubernetes.io/arch": "amd64",
+	}))
+	g.Expect(builds.Tolerations).To(Equal([]corev1.Toleration{
+		{Key: "kibaship.com/pool", Operator: corev1.TolerationOpEqual, Value: "builds", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "dedicated", Operator: corev1.TolerationOpExists},
+	}))
+	g.Expect(builds.MaxConcurrentPerProject).To(BeZero())
+
+	builds, err = ParseBuildsConfig(map[string]string{ConfigKeyBuildsMaxConcurrentPerProject: " 2 "})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(builds.MaxConcurrentPerProject).To(Equal(2))
+	g.Expect(builds.Enabled()).To(BeFalse())
+}
+
+func TestParseBuildsConfigValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ParseBuildsConfig(map[string]string{ConfigKeyBuildsNodeSelector: "builds"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid value for builds.node_selector"))
+
+	_, err = ParseBuildsConfig(map[string]string{ConfigKeyBuildsNodeSelector: "pool=not a label"})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ParseBuildsConfig(map[string]string{ConfigKeyBuildsTolerations: "pool=builds:NoRun"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid value for builds.tolerations"))
+
+	_, err = ParseBuildsConfig(map[string]string{ConfigKeyBuildsTolerations: "=builds:NoSchedule"})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ParseBuildsConfig(map[string]string{ConfigKeyBuildsMaxConcurrentPerProject: "-1"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid value for builds.max_concurrent_per_project"))
+
+	_, err = ParseBuildsConfig(map[string]string{ConfigKeyBuildsMaxConcurrentPerProject: "two"})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestParseBuildsConfigRemote(t *testing.T) {
+	g := NewWithT(t)
+
+	builds, err := ParseBuildsConfig(map[string]string{ConfigKeyBuildsRemoteNamespaces: "builds-a, builds-b, builds-a"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(builds.Remote()).To(BeTrue())
+	g.Expect(builds.RemoteNamespaces).To(Equal([]string{"builds-a", "builds-b"}))
+	g.Expect(builds.RemoteKubeconfigSecret).To(BeEmpty())
+
+	builds, err = ParseBuildsConfig(map[string]string{
+		ConfigKeyBuildsRemoteKubeconfigSecret: "build-cluster",
+		ConfigKeyBuildsRemoteNamespaces:       "builds",
+		ConfigKeyBuildsRemoteRegistry:         "registry.example.com:5000",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(builds.RemoteKubeconfigSecret).To(Equal("build-cluster"))
+	g.Expect(builds.RemoteRegistry).To(Equal("registry.example.com:5000"))
+
+	_, err = ParseBuildsConfig(map[string]string{ConfigKeyBuildsRemoteNamespaces: "Builds"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid value for builds.remote_namespaces"))
+
+	_, err = ParseBuildsConfig(map[string]string{ConfigKeyBuildsRemoteKubeconfigSecret: "build-cluster"})
+	g.Expect(err).To(MatchError(ContainSubstring("builds.remote_namespaces is required")))
+
+	_, err = ParseBuildsConfig(map[string]string{
+		ConfigKeyBuildsRemoteKubeconfigSecret: "build-cluster",
+		ConfigKeyBuildsRemoteNamespaces:       "builds",
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("builds.remote_registry is required")))
+
+	_, err = ParseBuildsConfig(map[string]string{
+		ConfigKeyBuildsRemoteNamespaces: "builds",
+		ConfigKeyBuildsRemoteRegistry:   "https://registry.example.com",
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("invalid value for builds.remote_registry")))
+
+	_, err = ParseBuildsConfig(map[string]string{ConfigKeyBuildsRemoteRegistry: "registry.example.com"})
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,259 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ConfigChange describes a single changed setting between two operator configurations
+type ConfigChange struct {
+	// Key is the ConfigMap key that changed
+	Key string
+
+	// RequiresManualAction is true when resources created with the previous value
+	// are not migrated automatically and need operator intervention
+	RequiresManualAction bool
+
+	// Message explains the effect of the change
+	Message string
+}
+
+// DiffConfigurations returns the settings that differ between previous and current
+func DiffConfigurations(previous, current *OperatorConfiguration) []ConfigChange {
+	if previous == nil || current == nil {
+		return nil
+	}
+
+	var changes []ConfigChange
+
+	if previous.Domain != current.Domain {
+		changes = append(changes, ConfigChange{
+			Key:                  ConfigKeyDomain,
+			RequiresManualAction: true,
+			Message: fmt.Sprintf("domain changed from %s to %s: new deployments use the new domain, "+
+				"existing ApplicationDomains and the wildcard certificate for %s must be migrated manually",
+				previous.Domain, current.Domain, previous.Domain),
+		})
+	}
+
+	if previous.ACMEEmail != current.ACMEEmail {
+		changes = append(changes, ConfigChange{
+			Key:     ConfigKeyACMEEmail,
+			Message: fmt.Sprintf("ACME email changed to %s, ClusterIssuer updated", current.ACMEEmail),
+		})
+	}
+
+	if previous.ACMEEnv != current.ACMEEnv {
+		changes = append(changes, ConfigChange{
+			Key:                  ConfigKeyACMEEnv,
+			RequiresManualAction: true,
+			Message: fmt.Sprintf("ACME environment changed to %s: ClusterIssuer updated, "+
+				"existing certificates keep their %s issued certificates until renewed", current.ACMEEnv, previous.ACMEEnv),
+		})
+	}
+
+	if !reflect.DeepEqual(previous.ACMEDNS, current.ACMEDNS) {
+		changes = append(changes, ConfigChange{
+			Key: ConfigKeyACMEDNSProvider,
+			Message: "ACME DNS solver changed, ClusterIssuer updated; " +
+				"certificates of the platform zones are solved through it from their next renewal",
+		})
+	}
+
+	if previous.WebhookURL != current.WebhookURL {
+		changes = append(changes, ConfigChange{
+			Key:     ConfigKeyWebhookURL,
+			Message: "webhook URL changed, notifications are delivered to the new URL",
+		})
+	}
+
+	if previous.WebhookEnrichDeployments != current.WebhookEnrichDeployments {
+		changes = append(changes, ConfigChange{
+			Key:     ConfigKeyWebhookEnrichDeployments,
+			Message: "deployment webhook enrichment changed, it applies to the next deployment notifications",
+		})
+	}
+
+	if previous.Events != current.Events {
+		changes = append(changes, ConfigChange{
+			Key:                  ConfigKeyEventsBackend,
+			RequiresManualAction: true,
+			Message: fmt.Sprintf("event backend settings changed: restart the operator to deliver events through %s",
+				current.Events.Backend),
+		})
+	}
+
+	if previous.DrainPeriod != current.DrainPeriod {
+		changes = append(changes, ConfigChange{
+			Key:     ConfigKeyDeploymentsDrainPeriod,
+			Message: fmt.Sprintf("drain period changed to %s, it applies to the next promotions", current.DrainPeriod),
+		})
+	}
+
+	if previous.GatewayClassName != current.GatewayClassName {
+		changes = append(changes, ConfigChange{
+			Key:                  ConfigKeyGatewayClassName,
+			RequiresManualAction: true,
+			Message: fmt.Sprintf("gateway class changed from %s to %s: gatewayClassName is immutable, "+
+				"delete the existing Gateway so it can be recreated", previous.GatewayClassName, current.GatewayClassName),
+		})
+	}
+
+	if previous.IngressProvider != current.IngressProvider || previous.IngressClassName != current.IngressClassName {
+		changes = append(changes, ConfigChange{
+			Key:                  ConfigKeyIngressProvider,
+			RequiresManualAction: true,
+			Message: fmt.Sprintf("ingress provider changed from %s to %s: routes of running applications "+
+				"are not migrated, redeploy applications to recreate them", previous.IngressProvider, current.IngressProvider),
+		})
+	}
+
+	if !reflect.DeepEqual(previous.StorageClasses, current.StorageClasses) {
+		changes = append(changes, ConfigChange{
+			Key:                  ConfigKeyStorageClasses,
+			RequiresManualAction: true,
+			Message: "storage classes changed: missing classes are created, StorageClass parameters are immutable " +
+				"so existing classes must be recreated manually to pick up new settings",
+		})
+	}
+
+	if !reflect.DeepEqual(previous.LoadBalancer, current.LoadBalancer) {
+		changes = append(changes, ConfigChange{
+			Key:     ConfigKeyLoadBalancerProvider,
+			Message: "load balancer IP pool configuration changed, pools updated",
+		})
+	}
+
+	if previous.Encryption != current.Encryption {
+		changes = append(changes, ConfigChange{
+			Key:                  ConfigKeyEncryptionProvider,
+			RequiresManualAction: true,
+			Message: "env var encryption settings changed: restart the operator and API server to load the new key " +
+				"and re-save existing env vars, values encrypted with the previous key cannot be decrypted once it is gone",
+		})
+	}
+
+	if previous.Cost != current.Cost {
+		changes = append(changes, ConfigChange{
+			Key:     ConfigKeyCostCPUCoreHour,
+			Message: "cost rates changed, project cost estimates use the new rates from their next calculation",
+		})
+	}
+
+	if previous.Logging != current.Logging {
+		changes = append(changes, ConfigChange{
+			Key:                  ConfigKeyLoggingProvider,
+			RequiresManualAction: previous.Logging.Enabled() && !current.Logging.Enabled(),
+			Message: "logging pipeline configuration changed, Loki and the log collector updated; " +
+				"disabling logging leaves the kibaship-logging namespace and its stored logs in place for manual removal",
+		})
+	}
+
+	if previous.Artifacts != current.Artifacts {
+		changes = append(changes, ConfigChange{
+			Key:                  ConfigKeyArtifactsProvider,
+			RequiresManualAction: previous.Artifacts.Enabled() != current.Artifacts.Enabled(),
+			Message: "artifact storage configuration changed, the artifact service updated; " +
+				"restart the operator and API server after enabling or disabling artifacts, " +
+				"disabling them leaves the kibaship-artifacts namespace and its stored artifacts in place for manual removal",
+		})
+	}
+
+	if previous.Monitoring != current.Monitoring {
+		changes = append(changes, ConfigChange{
+			Key: ConfigKeyMonitoringProvider,
+			RequiresManualAction: (previous.Monitoring.Enabled() && !current.Monitoring.Enabled()) ||
+				previous.Monitoring.StorageSize != current.Monitoring.StorageSize ||
+				previous.Monitoring.StorageClass != current.Monitoring.StorageClass,
+			Message: "monitoring stack configuration changed, the kube-prometheus-stack release is upgraded with the new values; " +
+				"the Prometheus volume of a new storage size or class only applies once its PersistentVolumeClaim is recreated, " +
+				"disabling monitoring leaves the kibaship-monitoring namespace and its stored metrics in place for manual removal",
+		})
+	}
+
+	if !reflect.DeepEqual(previous.Builds.NodeSelector, current.Builds.NodeSelector) ||
+		!reflect.DeepEqual(previous.Builds.Tolerations, current.Builds.Tolerations) {
+		changes = append(changes, ConfigChange{
+			Key:     ConfigKeyBuildsNodeSelector,
+			Message: "build node pool changed, new builds are scheduled with the new node selector and tolerations",
+		})
+	}
+
+	if previous.Builds.MaxConcurrentPerProject != current.Builds.MaxConcurrentPerProject {
+		changes = append(changes, ConfigChange{
+			Key:     ConfigKeyBuildsMaxConcurrentPerProject,
+			Message: "build concurrency limit changed, queued builds start as soon as the new limit allows",
+		})
+	}
+
+	if previous.BuildKit != current.BuildKit {
+		changes = append(changes, ConfigChange{
+			Key:     ConfigKeyBuildKitPoolMaxBuilders,
+			Message: "BuildKit pool settings changed, the pool is resized within a minute; idle daemons leave it first",
+		})
+	}
+
+	if previous.Builds.RemoteKubeconfigSecret != current.Builds.RemoteKubeconfigSecret ||
+		!reflect.DeepEqual(previous.Builds.RemoteNamespaces, current.Builds.RemoteNamespaces) ||
+		previous.Builds.RemoteRegistry != current.Builds.RemoteRegistry {
+		changes = append(changes, ConfigChange{
+			Key:                  ConfigKeyBuildsRemoteNamespaces,
+			RequiresManualAction: true,
+			Message: "remote build executor changed: restart the operator to dispatch new builds with the new settings, " +
+				"running builds finish where they started",
+		})
+	}
+
+	if previous.GitOps != current.GitOps {
+		changes = append(changes, ConfigChange{
+			Key:                  ConfigKeyGitOpsRepositoryURL,
+			RequiresManualAction: true,
+			Message: "GitOps export changed: restart the operator to export resources with the new repository, branch, path or deploy key, " +
+				"files already pushed with the previous settings are left in place",
+		})
+	}
+
+	if previous.TLS != current.TLS {
+		changes = append(changes, ConfigChange{
+			Key: ConfigKeyTLSMinVersion,
+			Message: "TLS policy changed, routes of application domains are updated; " +
+				"routes of individual deployments pick it up on their next deployment",
+		})
+	}
+
+	if !reflect.DeepEqual(previous.Images, current.Images) {
+		changes = append(changes, ConfigChange{
+			Key: ConfigKeyImagesAllowed,
+			Message: "image policy changed, applications and Dockerfile builds are checked against it from their next change or build; " +
+				"running applications keep their images",
+		})
+	}
+
+	if !reflect.DeepEqual(previous.AllowedBaseDomains, current.AllowedBaseDomains) {
+		changes = append(changes, ConfigChange{
+			Key: ConfigKeyBaseDomainsAllowed,
+			Message: "allowed base domains changed, projects and applications are checked against them when their base domain changes; " +
+				"certificates and Gateway listeners of base domains already in use are kept",
+		})
+	}
+
+	if previous.ImageMirror != current.ImageMirror {
+		changes = append(changes, ConfigChange{
+			Key: ConfigKeyImagesMirror,
+			Message: "image mirror changed, bootstrap components, BuildKit daemons and new builds pull from it; " +
+				"the shared buildkitd Deployment is restarted with the new registry mirrors",
+		})
+	}
+
+	return changes
+}
+
+// RequiresManualAction reports whether any of the changes needs operator intervention
+func RequiresManualAction(changes []ConfigChange) bool {
+	for _, change := range changes {
+		if change.RequiresManualAction {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDiffConfigurations(t *testing.T) {
+	g := NewWithT(t)
+
+	previous := &OperatorConfiguration{
+		Domain:           "apps.example.com",
+		GatewayClassName: "cilium",
+		ACMEEmail:        "ops@example.com",
+		ACMEEnv:          "staging",
+		IngressProvider:  IngressProviderGateway,
+		StorageClasses:   DefaultStorageClasses(),
+	}
+
+	g.Expect(DiffConfigurations(previous, previous)).To(BeEmpty())
+
+	current := *previous
+	current.ACMEEmail = "certs@example.com"
+	current.WebhookURL = "https://hooks.example.com"
+	changes := DiffConfigurations(previous, &current)
+	g.Expect(changes).To(HaveLen(2))
+	g.Expect(RequiresManualAction(changes)).To(BeFalse())
+
+	current.Domain = "apps.example.org"
+	changes = DiffConfigurations(previous, &current)
+	g.Expect(changes).To(HaveLen(3))
+	g.Expect(RequiresManualAction(changes)).To(BeTrue())
+	g.Expect(changes[0].Key).To(Equal(ConfigKeyDomain))
+	g.Expect(changes[0].Message).To(ContainSubstring("apps.example.org"))
+}
+
+func TestDiffConfigurationsAllowedBaseDomains(t *testing.T) {
+	g := NewWithT(t)
+
+	previous := &OperatorConfiguration{Domain: "apps.example.com"}
+	current := *previous
+	current.AllowedBaseDomains = []string{"customer.com"}
+	changes := DiffConfigurations(previous, &current)
+	g.Expect(changes).To(HaveLen(1))
+	g.Expect(changes[0].Key).To(Equal(ConfigKeyBaseDomainsAllowed))
+	g.Expect(RequiresManualAction(changes)).To(BeFalse())
+}
+
+func TestDiffConfigurationsMonitoring(t *testing.T) {
+	g := NewWithT(t)
+
+	previous := &OperatorConfiguration{Monitoring: MonitoringConfig{
+		Provider:     MonitoringProviderKubePrometheusStack,
+		ChartVersion: DefaultMonitoringChartVersion,
+		Retention:    DefaultMonitoringRetention,
+		StorageSize:  DefaultMonitoringStorageSize,
+	}}
+	current := *previous
+	current.Monitoring.ChartVersion = "76.0.0"
+	changes := DiffConfigurations(previous, &current)
+	g.Expect(changes).To(HaveLen(1))
+	g.Expect(changes[0].Key).To(Equal(ConfigKeyMonitoringProvider))
+	g.Expect(RequiresManualAction(changes)).To(BeFalse())
+
+	// The Prometheus volume is not resized in place
+	current.Monitoring.StorageSize = "50Gi"
+	g.Expect(RequiresManualAction(DiffConfigurations(previous, &current))).To(BeTrue())
+
+	current = *previous
+	current.Monitoring = MonitoringConfig{}
+	g.Expect(RequiresManualAction(DiffConfigurations(previous, &current))).To(BeTrue())
+}
+
+func TestDiffConfigurationsGitOps(t *testing.T) {
+	g := NewWithT(t)
+
+	previous := &OperatorConfiguration{GitOps: GitOpsConfig{
+		RepositoryURL: "git@github.com:acme/platform.git",
+		Branch:        "main",
+		Path:          "clusters/production",
+	}}
+	current := *previous
+	current.GitOps.Branch = "exports"
+	changes := DiffConfigurations(previous, &current)
+	g.Expect(changes).To(HaveLen(1))
+	g.Expect(changes[0].Key).To(Equal(ConfigKeyGitOpsRepositoryURL))
+	g.Expect(changes[0].Message).To(ContainSubstring("restart the operator"))
+	g.Expect(RequiresManualAction(changes)).To(BeTrue())
+}
+
+func TestDiffConfigurationsNil(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(DiffConfigurations(nil, &OperatorConfiguration{})).To(BeNil())
+}
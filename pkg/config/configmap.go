@@ -27,15 +27,104 @@ const (
 	// OperatorConfigMapName is the name of the ConfigMap in the operator namespace
 	OperatorConfigMapName = "kibaship-config"
 
+	// OperatorConfigStatusConfigMapName is the ConfigMap the operator records configuration
+	// reload conditions in
+	OperatorConfigStatusConfigMapName = "kibaship-config-status"
+
 	// OperatorNamespace is the namespace where the operator runs
 	OperatorNamespace = "kibaship"
 
 	// ConfigMap keys
 	ConfigKeyDomain           = "ingress.domain"
 	ConfigKeyGatewayClassName = "ingress.gateway_classname"
+	ConfigKeyIngressProvider  = "ingress.provider"
+	ConfigKeyIngressClassName = "ingress.class_name"
 	ConfigKeyACMEEmail        = "certs.email"
 	ConfigKeyACMEEnv          = "certs.env"
 	ConfigKeyWebhookURL       = "webhooks.url"
+	ConfigKeyStorageClasses   = "storage.classes"
+
+	ConfigKeyWebhookEnrichDeployments = "webhooks.enrich_deployments"
+
+	ConfigKeyEventsBackend       = "events.backend"
+	ConfigKeyEventsNATSURL       = "events.nats_url"
+	ConfigKeyEventsNATSStream    = "events.nats_stream"
+	ConfigKeyEventsSubjectPrefix = "events.subject_prefix"
+
+	ConfigKeyDeploymentsDrainPeriod = "deployments.drain_period"
+
+	ConfigKeyACMEDNSProvider            = "certs.dns_provider"
+	ConfigKeyACMEDNSZones               = "certs.dns_zones"
+	ConfigKeyACMEDNSRoute53Region       = "certs.route53_region"
+	ConfigKeyACMEDNSRoute53HostedZoneID = "certs.route53_hosted_zone_id"
+	ConfigKeyACMEDNSHetznerGroupName    = "certs.hetzner_group_name"
+
+	ConfigKeyLoadBalancerProvider   = "loadbalancer.provider"
+	ConfigKeyLoadBalancerAddresses  = "loadbalancer.addresses"
+	ConfigKeyLoadBalancerInterfaces = "loadbalancer.interfaces"
+
+	ConfigKeyEncryptionProvider     = "encryption.provider"
+	ConfigKeyEncryptionVaultAddress = "encryption.vault.address"
+	ConfigKeyEncryptionVaultMount   = "encryption.vault.mount"
+	ConfigKeyEncryptionVaultKey     = "encryption.vault.key"
+	ConfigKeyEncryptionAWSKMSKeyID  = "encryption.awskms.key_id"
+	ConfigKeyEncryptionAWSRegion    = "encryption.awskms.region"
+
+	ConfigKeyCostCPUCoreHour   = "cost.cpu_core_hour"
+	ConfigKeyCostMemoryGBHour  = "cost.memory_gb_hour"
+	ConfigKeyCostStorageGBHour = "cost.storage_gb_hour"
+	ConfigKeyCostCurrency      = "cost.currency"
+
+	ConfigKeyLoggingProvider     = "logging.provider"
+	ConfigKeyLoggingCollector    = "logging.collector"
+	ConfigKeyLoggingRetention    = "logging.retention"
+	ConfigKeyLoggingStorageSize  = "logging.storage_size"
+	ConfigKeyLoggingStorageClass = "logging.storage_class"
+	ConfigKeyLoggingLokiURL      = "logging.loki_url"
+
+	ConfigKeyArtifactsProvider            = "artifacts.provider"
+	ConfigKeyArtifactsStorageSize         = "artifacts.storage_size"
+	ConfigKeyArtifactsStorageClass        = "artifacts.storage_class"
+	ConfigKeyArtifactsS3Endpoint          = "artifacts.s3_endpoint"
+	ConfigKeyArtifactsS3Bucket            = "artifacts.s3_bucket"
+	ConfigKeyArtifactsS3Region            = "artifacts.s3_region"
+	ConfigKeyArtifactsS3CredentialsSecret = "artifacts.s3_credentials_secret"
+
+	ConfigKeyBuildsNodeSelector            = "builds.node_selector"
+	ConfigKeyBuildsTolerations             = "builds.tolerations"
+	ConfigKeyBuildsMaxConcurrentPerProject = "builds.max_concurrent_per_project"
+	ConfigKeyBuildsRemoteKubeconfigSecret  = "builds.remote_kubeconfig_secret"
+	ConfigKeyBuildsRemoteNamespaces        = "builds.remote_namespaces"
+	ConfigKeyBuildsRemoteRegistry          = "builds.remote_registry"
+
+	ConfigKeyBuildKitPoolMinBuilders  = "buildkit.pool_min_builders"
+	ConfigKeyBuildKitPoolMaxBuilders  = "buildkit.pool_max_builders"
+	ConfigKeyBuildKitBuildsPerBuilder = "buildkit.builds_per_builder"
+
+	ConfigKeyGitOpsRepositoryURL   = "gitops.repository_url"
+	ConfigKeyGitOpsBranch          = "gitops.branch"
+	ConfigKeyGitOpsPath            = "gitops.path"
+	ConfigKeyGitOpsDeployKeySecret = "gitops.deploy_key_secret"
+
+	ConfigKeyMetricsPrometheusURL = "metrics.prometheus_url"
+
+	ConfigKeyMonitoringProvider     = "monitoring.provider"
+	ConfigKeyMonitoringChartVersion = "monitoring.chart_version"
+	ConfigKeyMonitoringRetention    = "monitoring.retention"
+	ConfigKeyMonitoringStorageSize  = "monitoring.storage_size"
+	ConfigKeyMonitoringStorageClass = "monitoring.storage_class"
+
+	ConfigKeyTLSMinVersion            = "tls.min_version"
+	ConfigKeyTLSRedirectHTTP          = "tls.redirect_http"
+	ConfigKeyTLSHSTSMaxAge            = "tls.hsts_max_age"
+	ConfigKeyTLSHSTSIncludeSubdomains = "tls.hsts_include_subdomains"
+	ConfigKeyTLSHSTSPreload           = "tls.hsts_preload"
+
+	ConfigKeyImagesAllowed = "images.allowed"
+	ConfigKeyImagesDenied  = "images.denied"
+	ConfigKeyImagesMirror  = "images.mirror"
+
+	ConfigKeyBaseDomainsAllowed = "base_domains.allowed"
 
 	// WebhookSecretName is the name of the Secret created in the operator namespace
 	// that holds the HMAC signing key for webhook payloads.
@@ -54,8 +143,36 @@ type OperatorConfiguration struct {
 	Domain           string
 	ACMEEmail        string
 	ACMEEnv          string
+	ACMEDNS          ACMEDNSConfig
 	WebhookURL       string
 	GatewayClassName string
+	IngressProvider  IngressProvider
+	IngressClassName string
+	StorageClasses   []StorageClassConfig
+	LoadBalancer     LoadBalancerConfig
+	Encryption       EncryptionConfig
+	Cost             CostConfig
+	Logging          LoggingConfig
+	Artifacts        ArtifactsConfig
+	Monitoring       MonitoringConfig
+	Builds           BuildsConfig
+	BuildKit         BuildKitConfig
+	GitOps           GitOpsConfig
+	TLS              TLSPolicyConfig
+	Images           ImagePolicyConfig
+	ImageMirror      string
+
+	// AllowedBaseDomains are the base domains Projects and Applications may set besides Domain
+	AllowedBaseDomains []string
+
+	// WebhookEnrichDeployments adds the deployment context to deployment webhooks
+	WebhookEnrichDeployments bool
+
+	// Events selects the backend resource lifecycle events are delivered through
+	Events EventsConfig
+
+	// DrainPeriod is how long the previous deployment keeps serving once a promoted one is ready
+	DrainPeriod time.Duration
 }
 
 // LoadConfigFromConfigMap loads the operator configuration from a ConfigMap
@@ -98,6 +215,12 @@ func LoadConfigFromConfigMap(ctx context.Context, kubeConfig *rest.Config) (*Ope
 		return nil, lastErr
 	}
 
+	return ParseOperatorConfiguration(configMap)
+}
+
+// ParseOperatorConfiguration extracts and validates the operator configuration from the ConfigMap.
+// It is used both at startup and when the ConfigMap changes at runtime.
+func ParseOperatorConfiguration(configMap *corev1.ConfigMap) (*OperatorConfiguration, error) {
 	// Extract and validate required fields
 	domain, ok := configMap.Data[ConfigKeyDomain]
 	if !ok || domain == "" {
@@ -105,18 +228,51 @@ func LoadConfigFromConfigMap(ctx context.Context, kubeConfig *rest.Config) (*Ope
 			OperatorNamespace, OperatorConfigMapName, ConfigKeyDomain)
 	}
 
+	// Events are delivered as webhooks unless an event bus is configured
+	events, err := ParseEventsConfig(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// The webhook URL is only required when events are delivered as webhooks
 	webhookURL, ok := configMap.Data[ConfigKeyWebhookURL]
-	if !ok || webhookURL == "" {
+	if events.UsesWebhooks() && (!ok || webhookURL == "") {
 		return nil, fmt.Errorf("ConfigMap %s/%s is missing required key %s",
 			OperatorNamespace, OperatorConfigMapName, ConfigKeyWebhookURL)
 	}
 
-	gatewayClassName, ok := configMap.Data[ConfigKeyGatewayClassName]
-	if !ok || gatewayClassName == "" {
+	// Deployment webhooks only carry the deployment unless enrichment is enabled
+	webhookEnrichDeployments, err := ParseWebhookEnrichDeployments(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// Promoted deployments take over from the previous one after the drain period
+	drainPeriod, err := ParseDeploymentDrainPeriod(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// Ingress provider is optional, defaults to the Gateway API
+	ingressProvider, err := ParseIngressProvider(configMap.Data[ConfigKeyIngressProvider])
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s has invalid value for %s: %w",
+			OperatorNamespace, OperatorConfigMapName, ConfigKeyIngressProvider, err)
+	}
+
+	// Gateway class name is only required when routing through the Gateway API
+	gatewayClassName := configMap.Data[ConfigKeyGatewayClassName]
+	if ingressProvider.UsesGatewayAPI() && gatewayClassName == "" {
 		return nil, fmt.Errorf("ConfigMap %s/%s is missing required key %s",
 			OperatorNamespace, OperatorConfigMapName, ConfigKeyGatewayClassName)
 	}
 
+	// Ingress class name defaults to the class conventionally installed by the controller
+	ingressClassName := configMap.Data[ConfigKeyIngressClassName]
+	if ingressClassName == "" {
+		ingressClassName = ingressProvider.DefaultIngressClassName()
+	}
+
 	// ACMEEmail is now required
 	acmeEmail, ok := configMap.Data[ConfigKeyACMEEmail]
 	if !ok || acmeEmail == "" {
@@ -136,11 +292,122 @@ func LoadConfigFromConfigMap(ctx context.Context, kubeConfig *rest.Config) (*Ope
 			OperatorNamespace, OperatorConfigMapName, ConfigKeyACMEEnv, acmeEnv)
 	}
 
+	// DNS providers are optional, acme-dns answers every challenge without one
+	acmeDNS, err := ParseACMEDNSConfig(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// Storage classes are optional, defaults to the Longhorn classes
+	storageClasses, err := ParseStorageClasses(configMap.Data[ConfigKeyStorageClasses])
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// LoadBalancer IP pools are optional, only needed on bare-metal clusters
+	loadBalancer, err := ParseLoadBalancerConfig(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// Env var encryption at rest is optional
+	encryption, err := ParseEncryptionConfig(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// Cost rates are optional, costs are reported as zero without them
+	cost, err := ParseCostConfig(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// Persistent logging is optional, logs live only as long as the pods without it
+	logging, err := ParseLoggingConfig(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// Pipeline artifact collection is optional
+	artifacts, err := ParseArtifactsConfig(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// The monitoring stack is optional
+	monitoring, err := ParseMonitoringConfig(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// Builds run on any node unless a build node pool is configured
+	builds, err := ParseBuildsConfig(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// Builds share the buildkitd Deployment unless the operator manages a BuildKit pool
+	buildKit, err := ParseBuildKitConfig(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// Exporting the platform resources to git is optional
+	gitops, err := ParseGitOpsConfig(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// Routes redirect HTTP to HTTPS and accept TLS 1.2 unless a TLS policy is configured
+	tls, err := ParseTLSPolicyConfig(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// Applications may use any image unless an image policy is configured
+	images, err := ParseImagePolicyConfig(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// Platform images are pulled from their public registries unless a mirror is configured
+	imageMirror, err := ParseImageMirror(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
+	// Only the operator domain may be used as base domain unless others are allowed
+	allowedBaseDomains, err := ParseAllowedBaseDomains(configMap.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s: %w", OperatorNamespace, OperatorConfigMapName, err)
+	}
+
 	return &OperatorConfiguration{
 		Domain:           domain,
 		ACMEEmail:        acmeEmail,
 		ACMEEnv:          acmeEnv,
+		ACMEDNS:          acmeDNS,
 		WebhookURL:       webhookURL,
 		GatewayClassName: gatewayClassName,
+		IngressProvider:  ingressProvider,
+		IngressClassName: ingressClassName,
+		StorageClasses:   storageClasses,
+		LoadBalancer:     loadBalancer,
+		Encryption:       encryption,
+		Cost:             cost,
+		Logging:          logging,
+		Artifacts:        artifacts,
+		Monitoring:       monitoring,
+		Builds:           builds,
+		BuildKit:         buildKit,
+		GitOps:           gitops,
+		TLS:              tls,
+		Images:           images,
+		ImageMirror:      imageMirror,
+
+		AllowedBaseDomains:       allowedBaseDomains,
+		WebhookEnrichDeployments: webhookEnrichDeployments,
+		Events:                   events,
+		DrainPeriod:              drainPeriod,
 	}, nil
 }
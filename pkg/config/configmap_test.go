@@ -289,3 +289,64 @@ func TestLoadConfigFromConfigMapInvalidACMEEnv(t *testing.T) {
 	g.Expect(err.Error()).To(ContainSubstring("invalid value for certs.env: invalid"))
 	g.Expect(err.Error()).To(ContainSubstring("must be 'production' or 'staging'"))
 }
+
+func TestLoadConfigFromConfigMapIngressProvider(t *testing.T) {
+	g := NewWithT(t)
+
+	// Ingress based providers do not need a gateway class name
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      OperatorConfigMapName,
+			Namespace: OperatorNamespace,
+		},
+		Data: map[string]string{
+			ConfigKeyDomain:          "example.com",
+			ConfigKeyIngressProvider: "traefik",
+			ConfigKeyWebhookURL:      "https://webhook.example.com/kibaship",
+			ConfigKeyACMEEmail:       "admin@example.com",
+		},
+	}
+
+	fakeClientset := fake.NewSimpleClientset(configMap)
+
+	originalNewForConfig := newForConfigFunc
+	defer func() { newForConfigFunc = originalNewForConfig }()
+	newForConfigFunc = func(*rest.Config) (kubernetesInterface, error) {
+		return fakeClientset, nil
+	}
+
+	config, err := LoadConfigFromConfigMap(context.Background(), &rest.Config{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(config.IngressProvider).To(Equal(IngressProviderTraefik))
+	g.Expect(config.IngressClassName).To(Equal("traefik"))
+	g.Expect(config.GatewayClassName).To(BeEmpty())
+}
+
+func TestLoadConfigFromConfigMapInvalidIngressProvider(t *testing.T) {
+	g := NewWithT(t)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      OperatorConfigMapName,
+			Namespace: OperatorNamespace,
+		},
+		Data: map[string]string{
+			ConfigKeyDomain:          "example.com",
+			ConfigKeyIngressProvider: "haproxy",
+			ConfigKeyWebhookURL:      "https://webhook.example.com/kibaship",
+			ConfigKeyACMEEmail:       "admin@example.com",
+		},
+	}
+
+	fakeClientset := fake.NewSimpleClientset(configMap)
+
+	originalNewForConfig := newForConfigFunc
+	defer func() { newForConfigFunc = originalNewForConfig }()
+	newForConfigFunc = func(*rest.Config) (kubernetesInterface, error) {
+		return fakeClientset, nil
+	}
+
+	_, err := LoadConfigFromConfigMap(context.Background(), &rest.Config{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid value for ingress.provider"))
+}
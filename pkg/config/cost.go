@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultCostCurrency is the currency reported when cost.currency is not set
+const DefaultCostCurrency = "USD"
+
+// CostConfig holds the rates used to estimate what the resources requested by projects cost
+type CostConfig struct {
+	// CPUCoreHour is the price of one requested CPU core for one hour
+	CPUCoreHour float64
+	// MemoryGBHour is the price of one requested GiB of memory for one hour
+	MemoryGBHour float64
+	// StorageGBHour is the price of one requested GiB of persistent storage for one hour
+	StorageGBHour float64
+	// Currency is the ISO 4217 code the rates are expressed in
+	Currency string
+}
+
+// Enabled reports whether any rate is configured
+func (c CostConfig) Enabled() bool {
+	return c.CPUCoreHour > 0 || c.MemoryGBHour > 0 || c.StorageGBHour > 0
+}
+
+// ParseCostConfig reads and validates the cost.* keys of the operator ConfigMap
+func ParseCostConfig(data map[string]string) (CostConfig, error) {
+	cfg := CostConfig{
+		Currency: strings.ToUpper(strings.TrimSpace(data[ConfigKeyCostCurrency])),
+	}
+	if cfg.Currency == "" {
+		cfg.Currency = DefaultCostCurrency
+	}
+	if len(cfg.Currency) != 3 {
+		return cfg, fmt.Errorf("invalid value for %s: %s (must be a 3 letter currency code)", ConfigKeyCostCurrency, cfg.Currency)
+	}
+
+	rates := []struct {
+		key  string
+		rate *float64
+	}{
+		{ConfigKeyCostCPUCoreHour, &cfg.CPUCoreHour},
+		{ConfigKeyCostMemoryGBHour, &cfg.MemoryGBHour},
+		{ConfigKeyCostStorageGBHour, &cfg.StorageGBHour},
+	}
+	for _, r := range rates {
+		value := strings.TrimSpace(data[r.key])
+		if value == "" {
+			continue
+		}
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 {
+			return cfg, fmt.Errorf("invalid value for %s: %s (must be a non-negative number)", r.key, value)
+		}
+		*r.rate = rate
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseCostConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	cost, err := ParseCostConfig(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cost.Enabled()).To(BeFalse())
+	g.Expect(cost.Currency).To(Equal(DefaultCostCurrency))
+
+	cost, err = ParseCostConfig(map[string]string{
+		ConfigKeyCostCPUCoreHour:   "0.02",
+		ConfigKeyCostMemoryGBHour:  " 0.005 ",
+		ConfigKeyCostStorageGBHour: "0.0001",
+		ConfigKeyCostCurrency:      "eur",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cost.Enabled()).To(BeTrue())
+	g.Expect(cost.CPUCoreHour).To(Equal(0.02))
+	g.Expect(cost.MemoryGBHour).To(Equal(0.005))
+	g.Expect(cost.StorageGBHour).To(Equal(0.0001))
+	g.Expect(cost.Currency).To(Equal("EUR"))
+}
+
+func TestParseCostConfigValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ParseCostConfig(map[string]string{ConfigKeyCostCPUCoreHour: "-1"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid value for cost.cpu_core_hour"))
+
+	_, err = ParseCostConfig(map[string]string{ConfigKeyCostMemoryGBHour: "cheap"})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ParseCostConfig(map[string]string{ConfigKeyCostCurrency: "euro"})
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultDrainPeriod is how long the previous deployment keeps serving after a promotion
+	DefaultDrainPeriod = 30 * time.Second
+
+	// MaxDrainPeriod bounds the drain period, old deployments keep running meanwhile
+	MaxDrainPeriod = time.Hour
+)
+
+// ParseDeploymentDrainPeriod reads deployments.drain_period, how long the pods of the previous
+// deployment of an application keep receiving requests once a promoted deployment is ready
+func ParseDeploymentDrainPeriod(data map[string]string) (time.Duration, error) {
+	value := strings.TrimSpace(data[ConfigKeyDeploymentsDrainPeriod])
+	if value == "" {
+		return DefaultDrainPeriod, nil
+	}
+	period, err := time.ParseDuration(value)
+	if err != nil || period < 0 || period > MaxDrainPeriod {
+		return 0, fmt.Errorf("invalid value for %s: %s (must be a duration between 0s and %s)",
+			ConfigKeyDeploymentsDrainPeriod, value, MaxDrainPeriod)
+	}
+	return period, nil
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseDeploymentDrainPeriod(t *testing.T) {
+	g := NewWithT(t)
+
+	period, err := ParseDeploymentDrainPeriod(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(period).To(Equal(DefaultDrainPeriod))
+
+	period, err = ParseDeploymentDrainPeriod(map[string]string{ConfigKeyDeploymentsDrainPeriod: " 2m "})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(period).To(Equal(2 * time.Minute))
+
+	period, err = ParseDeploymentDrainPeriod(map[string]string{ConfigKeyDeploymentsDrainPeriod: "0s"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(period).To(BeZero())
+
+	for _, value := range []string{"soon", "-5s", "2h"} {
+		_, err = ParseDeploymentDrainPeriod(map[string]string{ConfigKeyDeploymentsDrainPeriod: value})
+		g.Expect(err).To(MatchError(ContainSubstring(ConfigKeyDeploymentsDrainPeriod)), value)
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// EncryptionProvider selects the key management service used to wrap env var data keys
+type EncryptionProvider string
+
+const (
+	// EncryptionProviderNone stores env vars in plain Secrets
+	EncryptionProviderNone EncryptionProvider = ""
+
+	// EncryptionProviderVault wraps data keys with a HashiCorp Vault transit key
+	EncryptionProviderVault EncryptionProvider = "vault"
+
+	// EncryptionProviderAWSKMS wraps data keys with an AWS KMS key
+	EncryptionProviderAWSKMS EncryptionProvider = "awskms"
+
+	// EncryptionProviderLocal wraps data keys with a static AES-256 key stored in the credentials Secret
+	EncryptionProviderLocal EncryptionProvider = "local"
+)
+
+const (
+	// EncryptionCredentialsSecretName holds the KMS credentials in the operator namespace.
+	// Keys: vault-token, aws-access-key-id, aws-secret-access-key, aws-session-token, local-key
+	EncryptionCredentialsSecretName = "kibaship-encryption"
+
+	// DefaultVaultTransitMount is the Vault transit secrets engine mount path used when none is configured
+	DefaultVaultTransitMount = "transit"
+)
+
+// EncryptionConfig holds the env var encryption at rest configuration
+type EncryptionConfig struct {
+	// Provider is the KMS implementation, empty disables encryption
+	Provider EncryptionProvider
+
+	// VaultAddress is the Vault server URL (https://vault.example.com:8200)
+	VaultAddress string
+	// VaultMount is the transit secrets engine mount path
+	VaultMount string
+	// VaultKey is the transit key name
+	VaultKey string
+
+	// AWSKMSKeyID is the KMS key ID, ARN or alias
+	AWSKMSKeyID string
+	// AWSRegion is the region of the KMS key
+	AWSRegion string
+}
+
+// Enabled reports whether env var values are encrypted before they are stored
+func (e EncryptionConfig) Enabled() bool {
+	return e.Provider != EncryptionProviderNone
+}
+
+// ParseEncryptionConfig reads and validates the encryption.* keys of the operator ConfigMap
+func ParseEncryptionConfig(data map[string]string) (EncryptionConfig, error) {
+	cfg := EncryptionConfig{
+		Provider:     EncryptionProvider(strings.TrimSpace(data[ConfigKeyEncryptionProvider])),
+		VaultAddress: strings.TrimSpace(data[ConfigKeyEncryptionVaultAddress]),
+		VaultMount:   strings.Trim(strings.TrimSpace(data[ConfigKeyEncryptionVaultMount]), "/"),
+		VaultKey:     strings.TrimSpace(data[ConfigKeyEncryptionVaultKey]),
+		AWSKMSKeyID:  strings.TrimSpace(data[ConfigKeyEncryptionAWSKMSKeyID]),
+		AWSRegion:    strings.TrimSpace(data[ConfigKeyEncryptionAWSRegion]),
+	}
+
+	switch cfg.Provider {
+	case EncryptionProviderNone, EncryptionProviderLocal:
+		return cfg, nil
+	case EncryptionProviderVault:
+		if cfg.VaultAddress == "" {
+			return cfg, fmt.Errorf("%s is required when %s is vault", ConfigKeyEncryptionVaultAddress, ConfigKeyEncryptionProvider)
+		}
+		if u, err := url.Parse(cfg.VaultAddress); err != nil || u.Scheme == "" || u.Host == "" {
+			return cfg, fmt.Errorf("invalid value for %s: %s is not a valid URL", ConfigKeyEncryptionVaultAddress, cfg.VaultAddress)
+		}
+		if cfg.VaultKey == "" {
+			return cfg, fmt.Errorf("%s is required when %s is vault", ConfigKeyEncryptionVaultKey, ConfigKeyEncryptionProvider)
+		}
+		if cfg.VaultMount == "" {
+			cfg.VaultMount = DefaultVaultTransitMount
+		}
+		return cfg, nil
+	case EncryptionProviderAWSKMS:
+		if cfg.AWSKMSKeyID == "" {
+			return cfg, fmt.Errorf("%s is required when %s is awskms", ConfigKeyEncryptionAWSKMSKeyID, ConfigKeyEncryptionProvider)
+		}
+		if cfg.AWSRegion == "" {
+			return cfg, fmt.Errorf("%s is required when %s is awskms", ConfigKeyEncryptionAWSRegion, ConfigKeyEncryptionProvider)
+		}
+		return cfg, nil
+	default:
+		return cfg, fmt.Errorf("invalid value for %s: %s (must be '%s', '%s' or '%s')",
+			ConfigKeyEncryptionProvider, cfg.Provider, EncryptionProviderVault, EncryptionProviderAWSKMS, EncryptionProviderLocal)
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseEncryptionConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	enc, err := ParseEncryptionConfig(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(enc.Enabled()).To(BeFalse())
+
+	enc, err = ParseEncryptionConfig(map[string]string{
+		ConfigKeyEncryptionProvider:     "vault",
+		ConfigKeyEncryptionVaultAddress: "https://vault.example.com:8200",
+		ConfigKeyEncryptionVaultKey:     "kibaship-env",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(enc.Provider).To(Equal(EncryptionProviderVault))
+	g.Expect(enc.VaultMount).To(Equal(DefaultVaultTransitMount))
+
+	enc, err = ParseEncryptionConfig(map[string]string{
+		ConfigKeyEncryptionProvider:    "awskms",
+		ConfigKeyEncryptionAWSKMSKeyID: "alias/kibaship",
+		ConfigKeyEncryptionAWSRegion:   "eu-west-1",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(enc.AWSKMSKeyID).To(Equal("alias/kibaship"))
+}
+
+func TestParseEncryptionConfigValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ParseEncryptionConfig(map[string]string{ConfigKeyEncryptionProvider: "gcpkms"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid value for encryption.provider"))
+
+	_, err = ParseEncryptionConfig(map[string]string{
+		ConfigKeyEncryptionProvider:     "vault",
+		ConfigKeyEncryptionVaultAddress: "vault.example.com",
+		ConfigKeyEncryptionVaultKey:     "kibaship-env",
+	})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("is not a valid URL"))
+
+	_, err = ParseEncryptionConfig(map[string]string{
+		ConfigKeyEncryptionProvider:    "awskms",
+		ConfigKeyEncryptionAWSKMSKeyID: "alias/kibaship",
+	})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("encryption.awskms.region is required"))
+}
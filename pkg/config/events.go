@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// EventsBackendWebhook delivers resource lifecycle events as signed HTTP webhooks
+	EventsBackendWebhook = "webhook"
+	// EventsBackendNATS publishes resource lifecycle events to a NATS JetStream stream
+	EventsBackendNATS = "nats"
+
+	// DefaultEventsNATSStream is the JetStream stream events are published to
+	DefaultEventsNATSStream = "KIBASHIP_EVENTS"
+	// DefaultEventsSubjectPrefix prefixes the subjects of the events published to an event bus
+	DefaultEventsSubjectPrefix = "kibaship"
+)
+
+var (
+	eventsBackendPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+	natsSubjectPattern   = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+	natsStreamPattern    = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// EventsConfig selects the backend resource lifecycle events are delivered through. Webhooks
+// are the default, an event bus lets platforms consume every event as a stream instead.
+type EventsConfig struct {
+	// Backend names the notifier backend events are delivered through
+	Backend string
+
+	// NATSURL is the URL of the NATS server of the nats backend
+	NATSURL string
+
+	// NATSStream is the JetStream stream the nats backend publishes to, created when missing
+	NATSStream string
+
+	// SubjectPrefix prefixes the subjects events are published on, followed by their type such
+	// as kibaship.deployment.status.changed
+	SubjectPrefix string
+}
+
+// UsesWebhooks reports whether events are delivered as HTTP webhooks
+func (c EventsConfig) UsesWebhooks() bool {
+	return c.Backend == EventsBackendWebhook
+}
+
+// ParseEventsConfig reads and validates the events.* keys of the operator ConfigMap. Backends
+// other than webhook and nats are accepted, they must be registered with the notifier registry.
+func ParseEventsConfig(data map[string]string) (EventsConfig, error) {
+	cfg := EventsConfig{
+		Backend:       strings.TrimSpace(data[ConfigKeyEventsBackend]),
+		NATSURL:       strings.TrimSpace(data[ConfigKeyEventsNATSURL]),
+		NATSStream:    strings.TrimSpace(data[ConfigKeyEventsNATSStream]),
+		SubjectPrefix: strings.TrimSpace(data[ConfigKeyEventsSubjectPrefix]),
+	}
+	if cfg.Backend == "" {
+		cfg.Backend = EventsBackendWebhook
+	}
+	if cfg.NATSStream == "" {
+		cfg.NATSStream = DefaultEventsNATSStream
+	}
+	if cfg.SubjectPrefix == "" {
+		cfg.SubjectPrefix = DefaultEventsSubjectPrefix
+	}
+
+	if !eventsBackendPattern.MatchString(cfg.Backend) {
+		return cfg, fmt.Errorf("invalid value for %s: %q (must be a lowercase backend name)", ConfigKeyEventsBackend, cfg.Backend)
+	}
+	if !natsSubjectPattern.MatchString(cfg.SubjectPrefix) {
+		return cfg, fmt.Errorf("invalid value for %s: %q (must be dot separated subject tokens)", ConfigKeyEventsSubjectPrefix, cfg.SubjectPrefix)
+	}
+
+	if cfg.Backend == EventsBackendNATS {
+		if cfg.NATSURL == "" {
+			return cfg, fmt.Errorf("%s is required when %s is %s", ConfigKeyEventsNATSURL, ConfigKeyEventsBackend, EventsBackendNATS)
+		}
+		for _, server := range strings.Split(cfg.NATSURL, ",") {
+			u, err := url.Parse(strings.TrimSpace(server))
+			if err != nil || (u.Scheme != "nats" && u.Scheme != "tls") || u.Host == "" {
+				return cfg, fmt.Errorf("invalid value for %s: %s (must be nats:// or tls:// URLs)", ConfigKeyEventsNATSURL, server)
+			}
+		}
+		if !natsStreamPattern.MatchString(cfg.NATSStream) {
+			return cfg, fmt.Errorf("invalid value for %s: %q (must be letters, digits, - and _)", ConfigKeyEventsNATSStream, cfg.NATSStream)
+		}
+	}
+
+	return cfg, nil
+}
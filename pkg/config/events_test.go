@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseEventsConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg, err := ParseEventsConfig(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.UsesWebhooks()).To(BeTrue())
+	g.Expect(cfg.SubjectPrefix).To(Equal(DefaultEventsSubjectPrefix))
+
+	cfg, err = ParseEventsConfig(map[string]string{
+		ConfigKeyEventsBackend:       "nats",
+		ConfigKeyEventsNATSURL:       "nats://nats-0.nats:4222, nats://nats-1.nats:4222",
+		ConfigKeyEventsSubjectPrefix: "acme.platform",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.UsesWebhooks()).To(BeFalse())
+	g.Expect(cfg.NATSStream).To(Equal(DefaultEventsNATSStream))
+	g.Expect(cfg.SubjectPrefix).To(Equal("acme.platform"))
+
+	for _, data := range []map[string]string{
+		{ConfigKeyEventsBackend: "NATS"},
+		{ConfigKeyEventsBackend: "nats"},
+		{ConfigKeyEventsBackend: "nats", ConfigKeyEventsNATSURL: "http://nats:4222"},
+		{ConfigKeyEventsBackend: "nats", ConfigKeyEventsNATSURL: "nats://nats:4222", ConfigKeyEventsNATSStream: "kibaship.events"},
+		{ConfigKeyEventsSubjectPrefix: "kibaship.>"},
+	} {
+		_, err := ParseEventsConfig(data)
+		g.Expect(err).To(HaveOccurred(), "%v", data)
+	}
+}
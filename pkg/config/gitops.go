@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// DefaultGitOpsBranch is the branch resources are exported to when gitops.branch is not set
+	DefaultGitOpsBranch = "main"
+
+	// DefaultGitOpsPath is the directory of the repository resources are exported to when
+	// gitops.path is not set
+	DefaultGitOpsPath = "kibaship"
+
+	// GitOpsDeployKeyKey is the key of the SSH private key in the deploy key Secret, the key
+	// kubernetes.io/ssh-auth Secrets use
+	GitOpsDeployKeyKey = "ssh-privatekey"
+
+	// GitOpsKnownHostsKey is the optional key of the known_hosts of the git host in the deploy
+	// key Secret. Without it the host key is trusted on first use.
+	GitOpsKnownHostsKey = "known_hosts"
+)
+
+// scpLikeGitURL matches the scp-like syntax of SSH git URLs, such as git@github.com:org/repo.git
+var scpLikeGitURL = regexp.MustCompile(`^[A-Za-z0-9._-]+@[A-Za-z0-9.-]+:[^/].*$`)
+
+// GitOpsConfig holds the export of the platform resources to a git repository. The operator
+// commits the Projects, Environments, Applications and ApplicationDomains to the repository on
+// change, so the history is audited and a cluster can be rebuilt with kubectl apply.
+type GitOpsConfig struct {
+	// RepositoryURL is the SSH URL of the repository, empty when resources are not exported
+	RepositoryURL string
+
+	// Branch is the branch exports are pushed to
+	Branch string
+
+	// Path is the directory of the repository the resources are written to
+	Path string
+
+	// DeployKeySecret names a Secret in the operator namespace holding the SSH deploy key
+	// under ssh-privatekey and optionally the known_hosts of the git host
+	DeployKeySecret string
+}
+
+// Enabled reports whether resources are exported to a git repository
+func (g GitOpsConfig) Enabled() bool {
+	return g.RepositoryURL != ""
+}
+
+// ParseGitOpsConfig reads and validates the gitops.* keys of the operator ConfigMap
+func ParseGitOpsConfig(data map[string]string) (GitOpsConfig, error) {
+	cfg := GitOpsConfig{
+		RepositoryURL:   strings.TrimSpace(data[ConfigKeyGitOpsRepositoryURL]),
+		Branch:          strings.TrimSpace(data[ConfigKeyGitOpsBranch]),
+		Path:            strings.Trim(strings.TrimSpace(data[ConfigKeyGitOpsPath]), "/"),
+		DeployKeySecret: strings.TrimSpace(data[ConfigKeyGitOpsDeployKeySecret]),
+	}
+	if cfg.RepositoryURL == "" {
+		return GitOpsConfig{}, nil
+	}
+
+	if !scpLikeGitURL.MatchString(cfg.RepositoryURL) {
+		u, err := url.Parse(cfg.RepositoryURL)
+		if err != nil || u.Scheme != "ssh" || u.Host == "" {
+			return cfg, fmt.Errorf("invalid value for %s: %q (must be an SSH URL such as git@github.com:org/repo.git)",
+				ConfigKeyGitOpsRepositoryURL, cfg.RepositoryURL)
+		}
+	}
+
+	if cfg.DeployKeySecret == "" {
+		return cfg, fmt.Errorf("%s is required when %s is set", ConfigKeyGitOpsDeployKeySecret, ConfigKeyGitOpsRepositoryURL)
+	}
+	if errs := validation.IsDNS1123Subdomain(cfg.DeployKeySecret); len(errs) > 0 {
+		return cfg, fmt.Errorf("invalid value for %s: %q: %s", ConfigKeyGitOpsDeployKeySecret, cfg.DeployKeySecret, strings.Join(errs, ", "))
+	}
+
+	if cfg.Branch == "" {
+		cfg.Branch = DefaultGitOpsBranch
+	}
+	if strings.ContainsAny(cfg.Branch, " ~^:?*[\\") || strings.HasPrefix(cfg.Branch, "-") || strings.Contains(cfg.Branch, "..") {
+		return cfg, fmt.Errorf("invalid value for %s: %q (must be a git branch name)", ConfigKeyGitOpsBranch, cfg.Branch)
+	}
+
+	if cfg.Path == "" {
+		cfg.Path = DefaultGitOpsPath
+	}
+	if cleaned := path.Clean(cfg.Path); cleaned != cfg.Path || cleaned == "." || strings.HasPrefix(cleaned, "..") ||
+		strings.ContainsAny(cfg.Path, " '\"$`\\") {
+		return cfg, fmt.Errorf("invalid value for %s: %q (must be a relative directory of the repository)", ConfigKeyGitOpsPath, cfg.Path)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseGitOpsConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	gitops, err := ParseGitOpsConfig(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gitops.Enabled()).To(BeFalse())
+
+	gitops, err = ParseGitOpsConfig(map[string]string{
+		ConfigKeyGitOpsRepositoryURL:   "git@github.com:acme/platform.git",
+		ConfigKeyGitOpsDeployKeySecret: "gitops-deploy-key",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gitops.Enabled()).To(BeTrue())
+	g.Expect(gitops.Branch).To(Equal(DefaultGitOpsBranch))
+	g.Expect(gitops.Path).To(Equal(DefaultGitOpsPath))
+
+	gitops, err = ParseGitOpsConfig(map[string]string{
+		ConfigKeyGitOpsRepositoryURL:   "ssh://git@gitlab.example.com:2222/acme/platform.git",
+		ConfigKeyGitOpsDeployKeySecret: "gitops-deploy-key",
+		ConfigKeyGitOpsBranch:          "clusters/production",
+		ConfigKeyGitOpsPath:            "/clusters/production/",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gitops.Branch).To(Equal("clusters/production"))
+	g.Expect(gitops.Path).To(Equal("clusters/production"))
+}
+
+func TestParseGitOpsConfigValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ParseGitOpsConfig(map[string]string{
+		ConfigKeyGitOpsRepositoryURL:   "https://github.com/acme/platform.git",
+		ConfigKeyGitOpsDeployKeySecret: "gitops-deploy-key",
+	})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("must be an SSH URL"))
+
+	_, err = ParseGitOpsConfig(map[string]string{ConfigKeyGitOpsRepositoryURL: "git@github.com:acme/platform.git"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("gitops.deploy_key_secret is required"))
+
+	_, err = ParseGitOpsConfig(map[string]string{
+		ConfigKeyGitOpsRepositoryURL:   "git@github.com:acme/platform.git",
+		ConfigKeyGitOpsDeployKeySecret: "gitops-deploy-key",
+		ConfigKeyGitOpsBranch:          "main..dev",
+	})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ParseGitOpsConfig(map[string]string{
+		ConfigKeyGitOpsRepositoryURL:   "git@github.com:acme/platform.git",
+		ConfigKeyGitOpsDeployKeySecret: "gitops-deploy-key",
+		ConfigKeyGitOpsPath:            "../outside",
+	})
+	g.Expect(err).To(HaveOccurred())
+}
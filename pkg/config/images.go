@@ -0,0 +1,154 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ImagePolicyConfig restricts the images applications run and the base images of their
+// Dockerfile builds. Patterns are fully qualified repositories such as
+// docker.io/library/node, a trailing /* matches every repository below a path and a bare
+// registry host matches every image of the registry. Denied patterns win over allowed ones,
+// without allowed patterns every image that is not denied may be used.
+type ImagePolicyConfig struct {
+	// Allowed are the image patterns applications may use, empty allows every image
+	Allowed []string
+
+	// Denied are the image patterns applications must not use
+	Denied []string
+}
+
+// Enabled reports whether the policy restricts any image
+func (c ImagePolicyConfig) Enabled() bool {
+	return len(c.Allowed) > 0 || len(c.Denied) > 0
+}
+
+// Check returns an error explaining why an image may not be used, nil when it may
+func (c ImagePolicyConfig) Check(image string) error {
+	ref := NormalizeImageReference(image)
+	for _, pattern := range c.Denied {
+		if matchImagePattern(pattern, ref) {
+			return fmt.Errorf("image %s is denied by the platform image policy (matches %s)", ref, pattern)
+		}
+	}
+	if len(c.Allowed) == 0 {
+		return nil
+	}
+	for _, pattern := range c.Allowed {
+		if matchImagePattern(pattern, ref) {
+			return nil
+		}
+	}
+	return fmt.Errorf("image %s is not allowed by the platform image policy, use an image matching one of: %s",
+		ref, strings.Join(c.Allowed, ", "))
+}
+
+// NormalizeImageReference returns the fully qualified repository of an image reference,
+// without tag or digest. Docker Hub images are qualified as docker.io, official images as
+// docker.io/library.
+func NormalizeImageReference(image string) string {
+	ref := strings.TrimSpace(image)
+	ref, _, _ = strings.Cut(ref, "@")
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+
+	registry, repository, qualified := strings.Cut(ref, "/")
+	if !qualified || (!strings.ContainsAny(registry, ".:") && registry != "localhost") {
+		registry, repository = "docker.io", ref
+	}
+	if registry == "index.docker.io" || registry == "registry-1.docker.io" {
+		registry = "docker.io"
+	}
+	if registry == "docker.io" && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	return registry + "/" + repository
+}
+
+// matchImagePattern reports whether a normalized image reference matches a policy pattern
+func matchImagePattern(pattern, ref string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(ref, prefix)
+	}
+	return ref == pattern
+}
+
+// MirrorImage rewrites an image reference to a mirror registry. Images are looked up on the mirror
+// under their fully qualified repository, docker.io/library/node:20 becomes
+// <mirror>/docker.io/library/node:20. Without mirror the image is returned unchanged.
+func MirrorImage(mirror, image string) string {
+	if mirror == "" || image == "" || strings.HasPrefix(image, mirror+"/") {
+		return image
+	}
+	name, suffix := image, ""
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, suffix = name[:i], name[i:]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, suffix = name[:i], name[i:]+suffix
+	}
+	return mirror + "/" + NormalizeImageReference(name) + suffix
+}
+
+// ParseImageMirror reads and validates images.mirror, the registry host, optionally followed by
+// a path, every platform image is pulled from in air-gapped installations
+func ParseImageMirror(data map[string]string) (string, error) {
+	mirror := strings.TrimSuffix(strings.TrimSpace(data[ConfigKeyImagesMirror]), "/")
+	if mirror == "" {
+		return "", nil
+	}
+	host, _, _ := strings.Cut(mirror, "/")
+	if strings.Contains(mirror, "://") || mirror != strings.ToLower(mirror) || strings.ContainsAny(mirror, " @") ||
+		(!strings.ContainsAny(host, ".:") && host != "localhost") {
+		return "", fmt.Errorf("invalid value for %s: %q (must be a registry host, optionally followed by a path)", ConfigKeyImagesMirror, mirror)
+	}
+	return mirror, nil
+}
+
+// ParseImagePolicyConfig reads and validates the images.* keys of the operator ConfigMap.
+// images.allowed and images.denied are lists of image patterns.
+func ParseImagePolicyConfig(data map[string]string) (ImagePolicyConfig, error) {
+	cfg := ImagePolicyConfig{}
+
+	for _, key := range []string{ConfigKeyImagesAllowed, ConfigKeyImagesDenied} {
+		var patterns []string
+		for _, pattern := range splitList(data[key]) {
+			normalized, err := parseImagePattern(pattern)
+			if err != nil {
+				return cfg, fmt.Errorf("invalid value for %s: %w", key, err)
+			}
+			patterns = append(patterns, normalized)
+		}
+		if key == ConfigKeyImagesAllowed {
+			cfg.Allowed = patterns
+		} else {
+			cfg.Denied = patterns
+		}
+	}
+
+	return cfg, nil
+}
+
+// parseImagePattern validates an image pattern, a bare registry host becomes host/*
+func parseImagePattern(pattern string) (string, error) {
+	if pattern != strings.ToLower(pattern) || strings.ContainsAny(pattern, " @?[") {
+		return "", fmt.Errorf("image pattern %q must be a lowercase repository without digest", pattern)
+	}
+
+	registry, repository, hasRepository := strings.Cut(pattern, "/")
+	if !strings.ContainsAny(registry, ".:") && registry != "localhost" {
+		return "", fmt.Errorf("image pattern %q must start with a registry host, e.g. docker.io/%s", pattern, pattern)
+	}
+	if !hasRepository {
+		return registry + "/*", nil
+	}
+	if repository == "" || strings.Contains(strings.TrimSuffix(repository, "/*"), "*") ||
+		(strings.HasSuffix(repository, "*") && repository != "*" && !strings.HasSuffix(repository, "/*")) {
+		return "", fmt.Errorf("image pattern %q may only end with /* as a wildcard", pattern)
+	}
+	if strings.Contains(repository, ":") {
+		return "", fmt.Errorf("image pattern %q must not carry a tag", pattern)
+	}
+	return pattern, nil
+}
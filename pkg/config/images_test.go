@@ -0,0 +1,85 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNormalizeImageReference(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(NormalizeImageReference("node:20-alpine")).To(Equal("docker.io/library/node"))
+	g.Expect(NormalizeImageReference("acme/api")).To(Equal("docker.io/acme/api"))
+	g.Expect(NormalizeImageReference("index.docker.io/nginx")).To(Equal("docker.io/library/nginx"))
+	g.Expect(NormalizeImageReference("ghcr.io/acme/api:1.2@sha256:abc")).To(Equal("ghcr.io/acme/api"))
+	g.Expect(NormalizeImageReference("localhost:5000/api:dev")).To(Equal("localhost:5000/api"))
+}
+
+func TestParseImagePolicyConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	images, err := ParseImagePolicyConfig(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(images.Enabled()).To(BeFalse())
+	g.Expect(images.Check("anything:latest")).To(Succeed())
+
+	images, err = ParseImagePolicyConfig(map[string]string{
+		ConfigKeyImagesAllowed: "ghcr.io/acme/*, docker.io/library/*, quay.io",
+		ConfigKeyImagesDenied:  "docker.io/library/ubuntu",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(images.Allowed).To(Equal([]string{"ghcr.io/acme/*", "docker.io/library/*", "quay.io/*"}))
+
+	g.Expect(images.Check("node:20")).To(Succeed())
+	g.Expect(images.Check("ghcr.io/acme/tools/api:1.0")).To(Succeed())
+	g.Expect(images.Check("quay.io/prometheus/node-exporter")).To(Succeed())
+
+	err = images.Check("ubuntu:24.04")
+	g.Expect(err).To(MatchError(ContainSubstring("docker.io/library/ubuntu is denied")))
+	err = images.Check("ghcr.io/other/api")
+	g.Expect(err).To(MatchError(ContainSubstring("use an image matching one of: ghcr.io/acme/*, docker.io/library/*, quay.io/*")))
+	// The path wildcard does not match a sibling organization sharing the prefix
+	g.Expect(images.Check("ghcr.io/acme-evil/api")).NotTo(Succeed())
+
+	// Only denied images are rejected without an allowlist
+	images, err = ParseImagePolicyConfig(map[string]string{ConfigKeyImagesDenied: "docker.io"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(images.Check("nginx")).NotTo(Succeed())
+	g.Expect(images.Check("ghcr.io/acme/api")).To(Succeed())
+}
+
+func TestParseImagePolicyConfigValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	for _, pattern := range []string{"node", "library/node", "docker.io/library/node:20", "ghcr.io/acme*", "ghcr.io/*/api", "GHCR.io/acme/*"} {
+		_, err := ParseImagePolicyConfig(map[string]string{ConfigKeyImagesAllowed: pattern})
+		g.Expect(err).To(HaveOccurred(), pattern)
+		g.Expect(err.Error()).To(ContainSubstring("invalid value for images.allowed"))
+	}
+}
+
+func TestMirrorImage(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(MirrorImage("", "grafana/loki:3.4.2")).To(Equal("grafana/loki:3.4.2"))
+	g.Expect(MirrorImage("mirror.corp:5000", "grafana/loki:3.4.2")).To(Equal("mirror.corp:5000/docker.io/grafana/loki:3.4.2"))
+	g.Expect(MirrorImage("mirror.corp/kibaship", "alpine/git")).To(Equal("mirror.corp/kibaship/docker.io/alpine/git"))
+	g.Expect(MirrorImage("mirror.corp", "ghcr.io/railwayapp/railpack-frontend:v0.9.0@sha256:abc")).
+		To(Equal("mirror.corp/ghcr.io/railwayapp/railpack-frontend:v0.9.0@sha256:abc"))
+	// Images already on the mirror are left alone
+	g.Expect(MirrorImage("mirror.corp", "mirror.corp/docker.io/library/node:20")).To(Equal("mirror.corp/docker.io/library/node:20"))
+}
+
+func TestParseImageMirror(t *testing.T) {
+	g := NewWithT(t)
+
+	mirror, err := ParseImageMirror(map[string]string{ConfigKeyImagesMirror: " mirror.corp:5000/kibaship/ "})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mirror).To(Equal("mirror.corp:5000/kibaship"))
+
+	for _, value := range []string{"https://mirror.corp", "mirror", "Mirror.corp"} {
+		_, err := ParseImageMirror(map[string]string{ConfigKeyImagesMirror: value})
+		g.Expect(err).To(HaveOccurred(), value)
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// IngressProvider selects how application traffic is routed into the cluster
+type IngressProvider string
+
+const (
+	// IngressProviderGateway routes traffic with Gateway API HTTPRoutes attached to the kibaship Gateway
+	IngressProviderGateway IngressProvider = "gateway"
+
+	// IngressProviderNginx routes traffic with networking.k8s.io Ingress resources for ingress-nginx
+	IngressProviderNginx IngressProvider = "nginx"
+
+	// IngressProviderTraefik routes traffic with networking.k8s.io Ingress resources for Traefik
+	IngressProviderTraefik IngressProvider = "traefik"
+)
+
+// DefaultIngressProvider is used when ingress.provider is not set
+const DefaultIngressProvider = IngressProviderGateway
+
+// UsesGatewayAPI reports whether the provider routes through the Gateway API
+func (p IngressProvider) UsesGatewayAPI() bool {
+	return p == IngressProviderGateway
+}
+
+// DefaultIngressClassName returns the IngressClass name conventionally installed by the controller
+func (p IngressProvider) DefaultIngressClassName() string {
+	switch p {
+	case IngressProviderNginx:
+		return "nginx"
+	case IngressProviderTraefik:
+		return "traefik"
+	default:
+		return ""
+	}
+}
+
+// RequestsMetric returns the request counter the ingress controller exports to Prometheus and the
+// label selector of the requests it routes to a Service. ok is false for providers without request
+// metrics.
+func (p IngressProvider) RequestsMetric(namespace, serviceName string) (metric, selector string, ok bool) {
+	switch p {
+	case IngressProviderNginx:
+		return "nginx_ingress_controller_requests",
+			fmt.Sprintf(`namespace=%s, service=%s`, strconv.Quote(namespace), strconv.Quote(serviceName)), true
+	case IngressProviderTraefik:
+		// Traefik names the backends of Ingress resources <namespace>-<service>-<port>@kubernetes
+		return "traefik_service_requests_total",
+			fmt.Sprintf(`service=~%s`, strconv.Quote(namespace+"-"+serviceName+"-[0-9]+@kubernetes")), true
+	default:
+		return "", "", false
+	}
+}
+
+// ParseIngressProvider validates an ingress.provider value. An empty value yields DefaultIngressProvider.
+func ParseIngressProvider(value string) (IngressProvider, error) {
+	if value == "" {
+		return DefaultIngressProvider, nil
+	}
+
+	switch provider := IngressProvider(value); provider {
+	case IngressProviderGateway, IngressProviderNginx, IngressProviderTraefik:
+		return provider, nil
+	default:
+		return "", fmt.Errorf("invalid ingress provider %q (must be one of %s, %s, %s)",
+			value, IngressProviderGateway, IngressProviderNginx, IngressProviderTraefik)
+	}
+}
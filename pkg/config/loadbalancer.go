@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// LoadBalancerProvider selects the implementation that assigns IPs to LoadBalancer Services
+type LoadBalancerProvider string
+
+const (
+	// LoadBalancerProviderNone leaves LoadBalancer IP assignment to the cloud provider
+	LoadBalancerProviderNone LoadBalancerProvider = ""
+
+	// LoadBalancerProviderCilium uses Cilium LB-IPAM with L2 announcements
+	LoadBalancerProviderCilium LoadBalancerProvider = "cilium"
+
+	// LoadBalancerProviderMetalLB uses MetalLB in L2 mode
+	LoadBalancerProviderMetalLB LoadBalancerProvider = "metallb"
+)
+
+// LoadBalancerConfig holds the LoadBalancer IP pool configuration used on bare-metal clusters
+type LoadBalancerConfig struct {
+	// Provider is the LB IPAM implementation, empty when IPs come from the cloud provider
+	Provider LoadBalancerProvider
+
+	// Addresses are the pool blocks, each a CIDR (10.0.0.0/28) or an inclusive range (10.0.0.10-10.0.0.20)
+	Addresses []string
+
+	// Interfaces restricts L2 announcements to the given network interfaces (regular expressions for Cilium).
+	// Empty announces on all interfaces.
+	Interfaces []string
+}
+
+// Enabled reports whether the operator should manage LoadBalancer IP pools
+func (l LoadBalancerConfig) Enabled() bool {
+	return l.Provider != LoadBalancerProviderNone
+}
+
+// ParseLoadBalancerConfig reads and validates the loadbalancer.* keys of the operator ConfigMap
+func ParseLoadBalancerConfig(data map[string]string) (LoadBalancerConfig, error) {
+	cfg := LoadBalancerConfig{
+		Provider:   LoadBalancerProvider(strings.TrimSpace(data[ConfigKeyLoadBalancerProvider])),
+		Addresses:  splitList(data[ConfigKeyLoadBalancerAddresses]),
+		Interfaces: splitList(data[ConfigKeyLoadBalancerInterfaces]),
+	}
+
+	switch cfg.Provider {
+	case LoadBalancerProviderNone:
+		return cfg, nil
+	case LoadBalancerProviderCilium, LoadBalancerProviderMetalLB:
+	default:
+		return cfg, fmt.Errorf("invalid value for %s: %s (must be '%s' or '%s')",
+			ConfigKeyLoadBalancerProvider, cfg.Provider, LoadBalancerProviderCilium, LoadBalancerProviderMetalLB)
+	}
+
+	if len(cfg.Addresses) == 0 {
+		return cfg, fmt.Errorf("%s is required when %s is set", ConfigKeyLoadBalancerAddresses, ConfigKeyLoadBalancerProvider)
+	}
+
+	for _, address := range cfg.Addresses {
+		if err := validateAddressBlock(address); err != nil {
+			return cfg, fmt.Errorf("invalid value for %s: %w", ConfigKeyLoadBalancerAddresses, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// validateAddressBlock accepts a CIDR or an inclusive IP range of the same family
+func validateAddressBlock(block string) error {
+	if start, stop, ok := strings.Cut(block, "-"); ok {
+		startIP := net.ParseIP(strings.TrimSpace(start))
+		stopIP := net.ParseIP(strings.TrimSpace(stop))
+		if startIP == nil || stopIP == nil {
+			return fmt.Errorf("%s is not a valid IP range", block)
+		}
+		if (startIP.To4() == nil) != (stopIP.To4() == nil) {
+			return fmt.Errorf("%s mixes IPv4 and IPv6 addresses", block)
+		}
+		return nil
+	}
+
+	if _, _, err := net.ParseCIDR(block); err != nil {
+		return fmt.Errorf("%s is not a valid CIDR or IP range", block)
+	}
+	return nil
+}
+
+// splitList splits a comma separated ConfigMap value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
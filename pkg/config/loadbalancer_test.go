@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseLoadBalancerConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	lb, err := ParseLoadBalancerConfig(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(lb.Enabled()).To(BeFalse())
+
+	lb, err = ParseLoadBalancerConfig(map[string]string{
+		ConfigKeyLoadBalancerProvider:   "cilium",
+		ConfigKeyLoadBalancerAddresses:  "10.0.0.0/28, 10.0.1.10-10.0.1.20",
+		ConfigKeyLoadBalancerInterfaces: "eth0",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(lb.Provider).To(Equal(LoadBalancerProviderCilium))
+	g.Expect(lb.Addresses).To(Equal([]string{"10.0.0.0/28", "10.0.1.10-10.0.1.20"}))
+	g.Expect(lb.Interfaces).To(Equal([]string{"eth0"}))
+}
+
+func TestParseLoadBalancerConfigValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ParseLoadBalancerConfig(map[string]string{ConfigKeyLoadBalancerProvider: "kube-vip"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid value for loadbalancer.provider"))
+
+	_, err = ParseLoadBalancerConfig(map[string]string{ConfigKeyLoadBalancerProvider: "metallb"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("loadbalancer.addresses is required"))
+
+	_, err = ParseLoadBalancerConfig(map[string]string{
+		ConfigKeyLoadBalancerProvider:  "metallb",
+		ConfigKeyLoadBalancerAddresses: "10.0.0.1-fd00::1",
+	})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("mixes IPv4 and IPv6"))
+
+	_, err = ParseLoadBalancerConfig(map[string]string{
+		ConfigKeyLoadBalancerProvider:  "metallb",
+		ConfigKeyLoadBalancerAddresses: "not-an-ip",
+	})
+	g.Expect(err).To(HaveOccurred())
+}
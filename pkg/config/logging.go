@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// LoggingProvider selects the backend application logs are persisted to
+type LoggingProvider string
+
+const (
+	// LoggingProviderNone keeps logs only for the lifetime of the pods
+	LoggingProviderNone LoggingProvider = ""
+
+	// LoggingProviderLoki ships logs to Grafana Loki
+	LoggingProviderLoki LoggingProvider = "loki"
+)
+
+// LoggingCollector selects the node agent that tails container logs
+type LoggingCollector string
+
+const (
+	// LoggingCollectorPromtail uses Grafana promtail
+	LoggingCollectorPromtail LoggingCollector = "promtail"
+
+	// LoggingCollectorVector uses Vector
+	LoggingCollectorVector LoggingCollector = "vector"
+)
+
+const (
+	// DefaultLoggingRetention is how long logs are kept when logging.retention is not set
+	DefaultLoggingRetention = 7 * 24 * time.Hour
+
+	// DefaultLoggingStorageSize is the Loki volume size when logging.storage_size is not set
+	DefaultLoggingStorageSize = "10Gi"
+
+	// LoggingNamespace is the namespace the operator installs Loki and the log collector into
+	LoggingNamespace = "kibaship-logging"
+
+	// BundledLokiURL is the address of the Loki installed by the operator
+	BundledLokiURL = "http://loki." + LoggingNamespace + ".svc:3100"
+)
+
+// Loki stream labels attached to application logs by the collector
+const (
+	LogLabelNamespace       = "namespace"
+	LogLabelPod             = "pod"
+	LogLabelContainer       = "container"
+	LogLabelApplicationUUID = "application_uuid"
+	LogLabelDeploymentUUID  = "deployment_uuid"
+	LogLabelProjectUUID     = "project_uuid"
+)
+
+// LoggingConfig holds the persistent logging pipeline configuration
+type LoggingConfig struct {
+	// Provider is the log backend, empty when logs are not persisted
+	Provider LoggingProvider
+
+	// Collector is the node agent shipping container logs to the backend
+	Collector LoggingCollector
+
+	// Retention is how long Loki keeps logs before deleting them
+	Retention time.Duration
+
+	// StorageSize is the size of the Loki data volume
+	StorageSize string
+
+	// StorageClass is the storage class of the Loki data volume, the cluster default when empty
+	StorageClass string
+
+	// LokiURL points at an existing Loki installation. When set the operator does not
+	// bootstrap Loki itself and only deploys the collector.
+	LokiURL string
+}
+
+// Enabled reports whether application logs are persisted
+func (l LoggingConfig) Enabled() bool {
+	return l.Provider != LoggingProviderNone
+}
+
+// Endpoint returns the base URL of the Loki logs are shipped to and queried from
+func (l LoggingConfig) Endpoint() string {
+	if l.LokiURL != "" {
+		return l.LokiURL
+	}
+	return BundledLokiURL
+}
+
+// ParseLoggingConfig reads and validates the logging.* keys of the operator ConfigMap
+func ParseLoggingConfig(data map[string]string) (LoggingConfig, error) {
+	cfg := LoggingConfig{
+		Provider:     LoggingProvider(strings.TrimSpace(data[ConfigKeyLoggingProvider])),
+		Collector:    LoggingCollector(strings.TrimSpace(data[ConfigKeyLoggingCollector])),
+		StorageSize:  strings.TrimSpace(data[ConfigKeyLoggingStorageSize]),
+		StorageClass: strings.TrimSpace(data[ConfigKeyLoggingStorageClass]),
+		LokiURL:      strings.TrimRight(strings.TrimSpace(data[ConfigKeyLoggingLokiURL]), "/"),
+	}
+
+	switch cfg.Provider {
+	case LoggingProviderNone:
+		return LoggingConfig{}, nil
+	case LoggingProviderLoki:
+	default:
+		return cfg, fmt.Errorf("invalid value for %s: %s (must be '%s')",
+			ConfigKeyLoggingProvider, cfg.Provider, LoggingProviderLoki)
+	}
+
+	switch cfg.Collector {
+	case "":
+		cfg.Collector = LoggingCollectorPromtail
+	case LoggingCollectorPromtail, LoggingCollectorVector:
+	default:
+		return cfg, fmt.Errorf("invalid value for %s: %s (must be '%s' or '%s')",
+			ConfigKeyLoggingCollector, cfg.Collector, LoggingCollectorPromtail, LoggingCollectorVector)
+	}
+
+	cfg.Retention = DefaultLoggingRetention
+	if value := strings.TrimSpace(data[ConfigKeyLoggingRetention]); value != "" {
+		retention, err := time.ParseDuration(value)
+		if err != nil || retention < 24*time.Hour {
+			return cfg, fmt.Errorf("invalid value for %s: %s (must be a duration of at least 24h)", ConfigKeyLoggingRetention, value)
+		}
+		cfg.Retention = retention
+	}
+
+	if cfg.StorageSize == "" {
+		cfg.StorageSize = DefaultLoggingStorageSize
+	}
+	if _, err := resource.ParseQuantity(cfg.StorageSize); err != nil {
+		return cfg, fmt.Errorf("invalid value for %s: %s (must be a quantity such as 10Gi)", ConfigKeyLoggingStorageSize, cfg.StorageSize)
+	}
+
+	if cfg.LokiURL != "" {
+		u, err := url.Parse(cfg.LokiURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return cfg, fmt.Errorf("invalid value for %s: %s (must be an http or https URL)", ConfigKeyLoggingLokiURL, cfg.LokiURL)
+		}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseLoggingConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	logging, err := ParseLoggingConfig(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(logging.Enabled()).To(BeFalse())
+
+	logging, err = ParseLoggingConfig(map[string]string{ConfigKeyLoggingProvider: "loki"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(logging.Enabled()).To(BeTrue())
+	g.Expect(logging.Collector).To(Equal(LoggingCollectorPromtail))
+	g.Expect(logging.Retention).To(Equal(DefaultLoggingRetention))
+	g.Expect(logging.StorageSize).To(Equal(DefaultLoggingStorageSize))
+
+	logging, err = ParseLoggingConfig(map[string]string{
+		ConfigKeyLoggingProvider:     "loki",
+		ConfigKeyLoggingCollector:    "vector",
+		ConfigKeyLoggingRetention:    "720h",
+		ConfigKeyLoggingStorageSize:  "50Gi",
+		ConfigKeyLoggingStorageClass: "storage-replica-2",
+		ConfigKeyLoggingLokiURL:      "http://loki.monitoring.svc:3100/",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(logging.Collector).To(Equal(LoggingCollectorVector))
+	g.Expect(logging.Retention).To(Equal(720 * time.Hour))
+	g.Expect(logging.StorageSize).To(Equal("50Gi"))
+	g.Expect(logging.StorageClass).To(Equal("storage-replica-2"))
+	g.Expect(logging.LokiURL).To(Equal("http://loki.monitoring.svc:3100"))
+}
+
+func TestParseLoggingConfigValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ParseLoggingConfig(map[string]string{ConfigKeyLoggingProvider: "elasticsearch"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid value for logging.provider"))
+
+	_, err = ParseLoggingConfig(map[string]string{ConfigKeyLoggingProvider: "loki", ConfigKeyLoggingCollector: "fluentd"})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ParseLoggingConfig(map[string]string{ConfigKeyLoggingProvider: "loki", ConfigKeyLoggingRetention: "1h"})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ParseLoggingConfig(map[string]string{ConfigKeyLoggingProvider: "loki", ConfigKeyLoggingStorageSize: "lots"})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ParseLoggingConfig(map[string]string{ConfigKeyLoggingProvider: "loki", ConfigKeyLoggingLokiURL: "loki:3100"})
+	g.Expect(err).To(HaveOccurred())
+
+	// Settings are ignored while logging is disabled
+	_, err = ParseLoggingConfig(map[string]string{ConfigKeyLoggingCollector: "fluentd"})
+	g.Expect(err).NotTo(HaveOccurred())
+}
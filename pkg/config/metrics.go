@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MetricsConfig holds the settings of the Prometheus application traffic metrics are read from
+type MetricsConfig struct {
+	// PrometheusURL is the base URL of a Prometheus scraping the ingress controller, the
+	// Prometheus of the monitoring stack when one is installed. Traffic metrics are disabled
+	// when empty.
+	PrometheusURL string
+
+	// IngressProvider selects the ingress controller metrics queried for application traffic
+	IngressProvider IngressProvider
+}
+
+// Enabled reports whether application traffic metrics can be queried
+func (m MetricsConfig) Enabled() bool {
+	return m.PrometheusURL != ""
+}
+
+// Endpoint returns the base URL of the Prometheus traffic metrics are queried from
+func (m MetricsConfig) Endpoint() string {
+	return m.PrometheusURL
+}
+
+// ParseMetricsConfig reads and validates the metrics.* keys of the operator ConfigMap
+func ParseMetricsConfig(data map[string]string) (MetricsConfig, error) {
+	cfg := MetricsConfig{
+		PrometheusURL: strings.TrimRight(strings.TrimSpace(data[ConfigKeyMetricsPrometheusURL]), "/"),
+	}
+	if cfg.PrometheusURL == "" {
+		// The Prometheus of the monitoring stack is queried unless another one is configured
+		monitoring, err := ParseMonitoringConfig(data)
+		if err != nil || !monitoring.Enabled() {
+			return MetricsConfig{}, nil
+		}
+		cfg.PrometheusURL = BundledPrometheusURL
+	}
+
+	u, err := url.Parse(cfg.PrometheusURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return cfg, fmt.Errorf("invalid value for %s: %s (must be an http or https URL)", ConfigKeyMetricsPrometheusURL, cfg.PrometheusURL)
+	}
+
+	provider, err := ParseIngressProvider(strings.TrimSpace(data[ConfigKeyIngressProvider]))
+	if err != nil {
+		return cfg, fmt.Errorf("invalid value for %s: %w", ConfigKeyIngressProvider, err)
+	}
+	cfg.IngressProvider = provider
+
+	return cfg, nil
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseMetricsConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	metrics, err := ParseMetricsConfig(map[string]string{ConfigKeyIngressProvider: "nginx"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(metrics.Enabled()).To(BeFalse())
+
+	metrics, err = ParseMetricsConfig(map[string]string{ConfigKeyMetricsPrometheusURL: "http://prometheus.monitoring.svc:9090/"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(metrics.Enabled()).To(BeTrue())
+	g.Expect(metrics.Endpoint()).To(Equal("http://prometheus.monitoring.svc:9090"))
+	g.Expect(metrics.IngressProvider).To(Equal(DefaultIngressProvider))
+
+	metrics, err = ParseMetricsConfig(map[string]string{
+		ConfigKeyMetricsPrometheusURL: "https://prometheus.example.com",
+		ConfigKeyIngressProvider:      "traefik",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(metrics.IngressProvider).To(Equal(IngressProviderTraefik))
+
+	// The Prometheus of the monitoring stack is used unless another one is configured
+	metrics, err = ParseMetricsConfig(map[string]string{ConfigKeyMonitoringProvider: "kube-prometheus-stack"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(metrics.Endpoint()).To(Equal(BundledPrometheusURL))
+}
+
+func TestParseMetricsConfigValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ParseMetricsConfig(map[string]string{ConfigKeyMetricsPrometheusURL: "prometheus:9090"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid value for metrics.prometheus_url"))
+
+	_, err = ParseMetricsConfig(map[string]string{
+		ConfigKeyMetricsPrometheusURL: "http://prometheus.monitoring.svc:9090",
+		ConfigKeyIngressProvider:      "haproxy",
+	})
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// MonitoringProvider selects the monitoring stack the operator installs
+type MonitoringProvider string
+
+const (
+	// MonitoringProviderNone installs no monitoring stack
+	MonitoringProviderNone MonitoringProvider = ""
+
+	// MonitoringProviderKubePrometheusStack installs the kube-prometheus-stack Helm chart
+	MonitoringProviderKubePrometheusStack MonitoringProvider = "kube-prometheus-stack"
+)
+
+const (
+	// DefaultMonitoringChartVersion is the kube-prometheus-stack chart installed when
+	// monitoring.chart_version is not set
+	DefaultMonitoringChartVersion = "75.15.1"
+
+	// DefaultMonitoringRetention is how long Prometheus keeps metrics when monitoring.retention is not set
+	DefaultMonitoringRetention = 15 * 24 * time.Hour
+
+	// DefaultMonitoringStorageSize is the Prometheus volume size when monitoring.storage_size is not set
+	DefaultMonitoringStorageSize = "20Gi"
+
+	// MonitoringNamespace is the namespace the operator installs the monitoring stack into
+	MonitoringNamespace = "kibaship-monitoring"
+
+	// MonitoringReleaseName is the Helm release of the monitoring stack
+	MonitoringReleaseName = "kube-prometheus-stack"
+
+	// BundledPrometheusURL is the address of the Prometheus installed by the operator
+	BundledPrometheusURL = "http://" + MonitoringReleaseName + "-prometheus." + MonitoringNamespace + ".svc:9090"
+)
+
+// chartVersionPattern matches the semantic version of a Helm chart
+var chartVersionPattern = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+$`)
+
+// MonitoringConfig holds the settings of the monitoring stack installed at bootstrap
+type MonitoringConfig struct {
+	// Provider is the monitoring stack, empty when none is installed
+	Provider MonitoringProvider
+
+	// ChartVersion is the version of the kube-prometheus-stack chart
+	ChartVersion string
+
+	// Retention is how long Prometheus keeps metrics
+	Retention time.Duration
+
+	// StorageSize is the size of the Prometheus data volume
+	StorageSize string
+
+	// StorageClass is the storage class of the Prometheus data volume, the cluster default when empty
+	StorageClass string
+}
+
+// Enabled reports whether the operator installs a monitoring stack
+func (m MonitoringConfig) Enabled() bool {
+	return m.Provider != MonitoringProviderNone
+}
+
+// ParseMonitoringConfig reads and validates the monitoring.* keys of the operator ConfigMap
+func ParseMonitoringConfig(data map[string]string) (MonitoringConfig, error) {
+	cfg := MonitoringConfig{
+		Provider:     MonitoringProvider(strings.TrimSpace(data[ConfigKeyMonitoringProvider])),
+		ChartVersion: strings.TrimSpace(data[ConfigKeyMonitoringChartVersion]),
+		StorageSize:  strings.TrimSpace(data[ConfigKeyMonitoringStorageSize]),
+		StorageClass: strings.TrimSpace(data[ConfigKeyMonitoringStorageClass]),
+	}
+
+	switch cfg.Provider {
+	case MonitoringProviderNone:
+		return MonitoringConfig{}, nil
+	case MonitoringProviderKubePrometheusStack:
+	default:
+		return cfg, fmt.Errorf("invalid value for %s: %s (must be '%s')",
+			ConfigKeyMonitoringProvider, cfg.Provider, MonitoringProviderKubePrometheusStack)
+	}
+
+	if cfg.ChartVersion == "" {
+		cfg.ChartVersion = DefaultMonitoringChartVersion
+	}
+	if !chartVersionPattern.MatchString(cfg.ChartVersion) {
+		return cfg, fmt.Errorf("invalid value for %s: %s (must be a chart version such as %s)",
+			ConfigKeyMonitoringChartVersion, cfg.ChartVersion, DefaultMonitoringChartVersion)
+	}
+
+	cfg.Retention = DefaultMonitoringRetention
+	if value := strings.TrimSpace(data[ConfigKeyMonitoringRetention]); value != "" {
+		retention, err := time.ParseDuration(value)
+		if err != nil || retention < 24*time.Hour {
+			return cfg, fmt.Errorf("invalid value for %s: %s (must be a duration of at least 24h)", ConfigKeyMonitoringRetention, value)
+		}
+		cfg.Retention = retention
+	}
+
+	if cfg.StorageSize == "" {
+		cfg.StorageSize = DefaultMonitoringStorageSize
+	}
+	if _, err := resource.ParseQuantity(cfg.StorageSize); err != nil {
+		return cfg, fmt.Errorf("invalid value for %s: %s (must be a quantity such as 20Gi)", ConfigKeyMonitoringStorageSize, cfg.StorageSize)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseMonitoringConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	monitoring, err := ParseMonitoringConfig(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(monitoring.Enabled()).To(BeFalse())
+
+	monitoring, err = ParseMonitoringConfig(map[string]string{ConfigKeyMonitoringProvider: "kube-prometheus-stack"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(monitoring.Enabled()).To(BeTrue())
+	g.Expect(monitoring.ChartVersion).To(Equal(DefaultMonitoringChartVersion))
+	g.Expect(monitoring.Retention).To(Equal(DefaultMonitoringRetention))
+	g.Expect(monitoring.StorageSize).To(Equal(DefaultMonitoringStorageSize))
+
+	monitoring, err = ParseMonitoringConfig(map[string]string{
+		ConfigKeyMonitoringProvider:     "kube-prometheus-stack",
+		ConfigKeyMonitoringChartVersion: "70.4.2",
+		ConfigKeyMonitoringRetention:    "720h",
+		ConfigKeyMonitoringStorageSize:  "100Gi",
+		ConfigKeyMonitoringStorageClass: "storage-replica-2",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(monitoring.ChartVersion).To(Equal("70.4.2"))
+	g.Expect(monitoring.Retention).To(Equal(720 * time.Hour))
+	g.Expect(monitoring.StorageSize).To(Equal("100Gi"))
+	g.Expect(monitoring.StorageClass).To(Equal("storage-replica-2"))
+}
+
+func TestParseMonitoringConfigValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ParseMonitoringConfig(map[string]string{ConfigKeyMonitoringProvider: "victoria-metrics"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid value for monitoring.provider"))
+
+	_, err = ParseMonitoringConfig(map[string]string{ConfigKeyMonitoringProvider: "kube-prometheus-stack", ConfigKeyMonitoringChartVersion: "latest"})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ParseMonitoringConfig(map[string]string{ConfigKeyMonitoringProvider: "kube-prometheus-stack", ConfigKeyMonitoringRetention: "1h"})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ParseMonitoringConfig(map[string]string{ConfigKeyMonitoringProvider: "kube-prometheus-stack", ConfigKeyMonitoringStorageSize: "lots"})
+	g.Expect(err).To(HaveOccurred())
+
+	// Settings are ignored while monitoring is disabled
+	_, err = ParseMonitoringConfig(map[string]string{ConfigKeyMonitoringChartVersion: "latest"})
+	g.Expect(err).NotTo(HaveOccurred())
+}
@@ -0,0 +1,14 @@
+package config
+
+// PriorityClasses the operator provisions on startup. Runtime pods outrank builds so a burst of
+// builds cannot evict running applications, and platform components outrank both.
+const (
+	// PriorityClassBuild schedules pipeline and image build pods. Builds never preempt other pods.
+	PriorityClassBuild = "kibaship-build"
+
+	// PriorityClassRuntime schedules application pods, which may preempt builds
+	PriorityClassRuntime = "kibaship-runtime"
+
+	// PriorityClassSystem schedules the platform components the operator provisions
+	PriorityClassSystem = "kibaship-system"
+)
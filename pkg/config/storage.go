@@ -1,8 +1,139 @@
 package config
 
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
 // Global storage class names for the platform.
 // Use these constants everywhere instead of hard-coded strings.
 const (
 	StorageClassReplica1 = "storage-replica-1"
 	StorageClassReplica2 = "storage-replica-2"
 )
+
+const (
+	// LonghornProvisioner is the CSI driver used when no storage classes are configured
+	LonghornProvisioner = "driver.longhorn.io"
+
+	// LonghornReplicaParameter is the StorageClass parameter Longhorn reads the replica count from
+	LonghornReplicaParameter = "numberOfReplicas"
+)
+
+// StorageClassConfig describes a StorageClass the operator provisions on startup.
+// Classes are read as a YAML list from the storage.classes ConfigMap key,
+// see config/samples/kibaship-config-cilium.yaml for an example.
+type StorageClassConfig struct {
+	// Name of the StorageClass
+	Name string `yaml:"name"`
+
+	// Provisioner is the CSI driver backing the class
+	Provisioner string `yaml:"provisioner"`
+
+	// Replicas is the number of volume replicas. It is written into the parameter
+	// named by ReplicaParameter, which defaults to numberOfReplicas for Longhorn.
+	// Drivers without a replica parameter should express replication in Parameters.
+	Replicas int `yaml:"replicas,omitempty"`
+
+	// ReplicaParameter is the provisioner parameter that receives Replicas
+	ReplicaParameter string `yaml:"replicaParameter,omitempty"`
+
+	// Default marks the class as the cluster default StorageClass
+	Default bool `yaml:"default,omitempty"`
+
+	// Parameters are passed through to the StorageClass as-is
+	Parameters map[string]string `yaml:"parameters,omitempty"`
+
+	// VolumeBindingMode defaults to WaitForFirstConsumer
+	VolumeBindingMode string `yaml:"volumeBindingMode,omitempty"`
+
+	// ReclaimPolicy defaults to the Kubernetes default (Delete)
+	ReclaimPolicy string `yaml:"reclaimPolicy,omitempty"`
+}
+
+// StorageParameters returns the StorageClass parameters including the replica count
+func (s StorageClassConfig) StorageParameters() map[string]string {
+	params := make(map[string]string, len(s.Parameters)+1)
+	for k, v := range s.Parameters {
+		params[k] = v
+	}
+
+	replicaParameter := s.ReplicaParameter
+	if replicaParameter == "" && s.Provisioner == LonghornProvisioner {
+		replicaParameter = LonghornReplicaParameter
+	}
+	if replicaParameter != "" && s.Replicas > 0 {
+		params[replicaParameter] = strconv.Itoa(s.Replicas)
+	}
+
+	return params
+}
+
+// DefaultStorageClasses returns the Longhorn-backed classes used when the
+// ConfigMap does not define any storage classes
+func DefaultStorageClasses() []StorageClassConfig {
+	return []StorageClassConfig{
+		{Name: StorageClassReplica1, Provisioner: LonghornProvisioner, Replicas: 1},
+		{Name: StorageClassReplica2, Provisioner: LonghornProvisioner, Replicas: 2},
+	}
+}
+
+// ParseStorageClasses parses and validates the storage.classes ConfigMap value.
+// An empty value yields DefaultStorageClasses.
+func ParseStorageClasses(raw string) ([]StorageClassConfig, error) {
+	if raw == "" {
+		return DefaultStorageClasses(), nil
+	}
+
+	var classes []StorageClassConfig
+	if err := yaml.Unmarshal([]byte(raw), &classes); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ConfigKeyStorageClasses, err)
+	}
+
+	if err := ValidateStorageClasses(classes); err != nil {
+		return nil, err
+	}
+
+	return classes, nil
+}
+
+// ValidateStorageClasses checks that the classes are well formed and that the
+// class names the platform relies on are present
+func ValidateStorageClasses(classes []StorageClassConfig) error {
+	seen := make(map[string]bool, len(classes))
+	defaults := 0
+
+	for i, class := range classes {
+		if class.Name == "" {
+			return fmt.Errorf("%s[%d]: name is required", ConfigKeyStorageClasses, i)
+		}
+		if class.Provisioner == "" {
+			return fmt.Errorf("%s[%d] (%s): provisioner is required", ConfigKeyStorageClasses, i, class.Name)
+		}
+		if class.Replicas < 0 {
+			return fmt.Errorf("%s[%d] (%s): replicas must not be negative", ConfigKeyStorageClasses, i, class.Name)
+		}
+		if seen[class.Name] {
+			return fmt.Errorf("%s: duplicate storage class %s", ConfigKeyStorageClasses, class.Name)
+		}
+		seen[class.Name] = true
+
+		if class.Default {
+			defaults++
+		}
+	}
+
+	if defaults > 1 {
+		return fmt.Errorf("%s: at most one storage class can be marked default, found %d", ConfigKeyStorageClasses, defaults)
+	}
+
+	for _, required := range []string{StorageClassReplica1, StorageClassReplica2} {
+		if !seen[required] {
+			return fmt.Errorf("%s: storage class %s is required by the platform", ConfigKeyStorageClasses, required)
+		}
+	}
+
+	return nil
+}
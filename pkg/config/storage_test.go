@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseStorageClassesDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	classes, err := ParseStorageClasses("")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(classes).To(Equal(DefaultStorageClasses()))
+	g.Expect(classes[0].StorageParameters()).To(HaveKeyWithValue(LonghornReplicaParameter, "1"))
+	g.Expect(classes[1].StorageParameters()).To(HaveKeyWithValue(LonghornReplicaParameter, "2"))
+}
+
+func TestParseStorageClassesCustomProvisioner(t *testing.T) {
+	g := NewWithT(t)
+
+	raw := `
+- name: storage-replica-1
+  provisioner: openebs.io/local
+  default: true
+- name: storage-replica-2
+  provisioner: rook-ceph.rbd.csi.ceph.com
+  replicas: 2
+  replicaParameter: replicas
+  parameters:
+    pool: replicapool
+`
+	classes, err := ParseStorageClasses(raw)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(classes).To(HaveLen(2))
+	g.Expect(classes[0].Default).To(BeTrue())
+	g.Expect(classes[0].StorageParameters()).To(BeEmpty())
+	g.Expect(classes[1].StorageParameters()).To(Equal(map[string]string{
+		"pool":     "replicapool",
+		"replicas": "2",
+	}))
+}
+
+func TestParseStorageClassesValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ParseStorageClasses(`
+- name: storage-replica-1
+  provisioner: openebs.io/local
+`)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("storage class storage-replica-2 is required"))
+
+	_, err = ParseStorageClasses(`
+- name: storage-replica-1
+  provisioner: openebs.io/local
+  default: true
+- name: storage-replica-2
+  provisioner: openebs.io/local
+  default: true
+`)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("at most one storage class can be marked default"))
+
+	_, err = ParseStorageClasses(`
+- name: storage-replica-1
+`)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("provisioner is required"))
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// TLSVersion12 accepts TLS 1.2 and 1.3 clients
+	TLSVersion12 = "1.2"
+
+	// TLSVersion13 only accepts TLS 1.3 clients
+	TLSVersion13 = "1.3"
+
+	// HSTSPreloadMinMaxAgeSeconds is the shortest HSTS max-age accepted by the browser preload lists
+	HSTSPreloadMinMaxAgeSeconds = 31536000
+)
+
+// TLSPolicyConfig holds the TLS policy applied to every route of the platform. Domains may opt
+// out, they are then served over HTTP as well, without HSTS or minimum TLS version. Without any
+// tls.* key, HTTP is redirected to HTTPS and TLS 1.2 is the minimum version.
+type TLSPolicyConfig struct {
+	// MinVersion is the lowest TLS version accepted, TLSVersion12 or TLSVersion13. Ingress
+	// controllers enforce it per route, the Gateway API has no portable setting for it so
+	// Gateways keep the versions of their implementation.
+	MinVersion string
+
+	// RedirectHTTP redirects plain HTTP requests to HTTPS. Traefik redirects through its static
+	// configuration, this setting does not apply to it.
+	RedirectHTTP bool
+
+	// HSTSMaxAgeSeconds adds Strict-Transport-Security with this max-age, 0 leaves it unset.
+	// Domains with their own HSTS security headers keep them.
+	HSTSMaxAgeSeconds int64
+
+	// HSTSIncludeSubdomains adds includeSubDomains to Strict-Transport-Security
+	HSTSIncludeSubdomains bool
+
+	// HSTSPreload adds preload to Strict-Transport-Security
+	HSTSPreload bool
+}
+
+// DefaultTLSPolicyConfig returns the policy applied when the ConfigMap has no tls.* key
+func DefaultTLSPolicyConfig() TLSPolicyConfig {
+	return TLSPolicyConfig{MinVersion: TLSVersion12, RedirectHTTP: true}
+}
+
+// HSTSEnabled reports whether the policy adds Strict-Transport-Security to responses
+func (t TLSPolicyConfig) HSTSEnabled() bool {
+	return t.HSTSMaxAgeSeconds > 0
+}
+
+// ParseTLSPolicyConfig reads and validates the tls.* keys of the operator ConfigMap. A preloaded
+// HSTS policy must include subdomains and last at least HSTSPreloadMinMaxAgeSeconds.
+func ParseTLSPolicyConfig(data map[string]string) (TLSPolicyConfig, error) {
+	cfg := DefaultTLSPolicyConfig()
+
+	parseBool := func(key string, value *bool) error {
+		raw := strings.TrimSpace(data[key])
+		if raw == "" {
+			return nil
+		}
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %q (must be true or false)", key, raw)
+		}
+		*value = parsed
+		return nil
+	}
+
+	if version := strings.TrimSpace(data[ConfigKeyTLSMinVersion]); version != "" {
+		if version != TLSVersion12 && version != TLSVersion13 {
+			return cfg, fmt.Errorf("invalid value for %s: %s (must be '%s' or '%s')",
+				ConfigKeyTLSMinVersion, version, TLSVersion12, TLSVersion13)
+		}
+		cfg.MinVersion = version
+	}
+
+	if err := parseBool(ConfigKeyTLSRedirectHTTP, &cfg.RedirectHTTP); err != nil {
+		return cfg, err
+	}
+
+	if value := strings.TrimSpace(data[ConfigKeyTLSHSTSMaxAge]); value != "" {
+		maxAge, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || maxAge < 0 {
+			return cfg, fmt.Errorf("invalid value for %s: %q (must be a number of seconds)", ConfigKeyTLSHSTSMaxAge, value)
+		}
+		cfg.HSTSMaxAgeSeconds = maxAge
+	}
+
+	if err := parseBool(ConfigKeyTLSHSTSIncludeSubdomains, &cfg.HSTSIncludeSubdomains); err != nil {
+		return cfg, err
+	}
+	if err := parseBool(ConfigKeyTLSHSTSPreload, &cfg.HSTSPreload); err != nil {
+		return cfg, err
+	}
+
+	if (cfg.HSTSIncludeSubdomains || cfg.HSTSPreload) && !cfg.HSTSEnabled() {
+		return cfg, fmt.Errorf("%s is required when %s or %s is set",
+			ConfigKeyTLSHSTSMaxAge, ConfigKeyTLSHSTSIncludeSubdomains, ConfigKeyTLSHSTSPreload)
+	}
+	if cfg.HSTSPreload && (!cfg.HSTSIncludeSubdomains || cfg.HSTSMaxAgeSeconds < HSTSPreloadMinMaxAgeSeconds) {
+		return cfg, fmt.Errorf("%s requires %s and a %s of at least %d",
+			ConfigKeyTLSHSTSPreload, ConfigKeyTLSHSTSIncludeSubdomains, ConfigKeyTLSHSTSMaxAge, HSTSPreloadMinMaxAgeSeconds)
+	}
+
+	return cfg, nil
+}
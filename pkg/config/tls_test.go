@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseTLSPolicyConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg, err := ParseTLSPolicyConfig(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg).To(Equal(DefaultTLSPolicyConfig()))
+	g.Expect(cfg.HSTSEnabled()).To(BeFalse())
+
+	cfg, err = ParseTLSPolicyConfig(map[string]string{
+		ConfigKeyTLSMinVersion:            "1.3",
+		ConfigKeyTLSRedirectHTTP:          "false",
+		ConfigKeyTLSHSTSMaxAge:            " 63072000 ",
+		ConfigKeyTLSHSTSIncludeSubdomains: "true",
+		ConfigKeyTLSHSTSPreload:           "true",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg).To(Equal(TLSPolicyConfig{
+		MinVersion:            TLSVersion13,
+		HSTSMaxAgeSeconds:     63072000,
+		HSTSIncludeSubdomains: true,
+		HSTSPreload:           true,
+	}))
+	g.Expect(cfg.HSTSEnabled()).To(BeTrue())
+}
+
+func TestParseTLSPolicyConfigValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ParseTLSPolicyConfig(map[string]string{ConfigKeyTLSMinVersion: "1.1"})
+	g.Expect(err).To(MatchError(ContainSubstring("invalid value for tls.min_version")))
+
+	_, err = ParseTLSPolicyConfig(map[string]string{ConfigKeyTLSRedirectHTTP: "sometimes"})
+	g.Expect(err).To(MatchError(ContainSubstring("invalid value for tls.redirect_http")))
+
+	_, err = ParseTLSPolicyConfig(map[string]string{ConfigKeyTLSHSTSMaxAge: "-1"})
+	g.Expect(err).To(MatchError(ContainSubstring("invalid value for tls.hsts_max_age")))
+
+	_, err = ParseTLSPolicyConfig(map[string]string{ConfigKeyTLSHSTSIncludeSubdomains: "true"})
+	g.Expect(err).To(MatchError(ContainSubstring("tls.hsts_max_age is required")))
+
+	_, err = ParseTLSPolicyConfig(map[string]string{
+		ConfigKeyTLSHSTSMaxAge:  "86400",
+		ConfigKeyTLSHSTSPreload: "true",
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("tls.hsts_preload requires")))
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseWebhookEnrichDeployments reads webhooks.enrich_deployments, whether deployment webhooks
+// carry the project, environment and application of the deployment. It is off by default to
+// keep payloads small.
+func ParseWebhookEnrichDeployments(data map[string]string) (bool, error) {
+	raw := strings.TrimSpace(data[ConfigKeyWebhookEnrichDeployments])
+	if raw == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %q (must be true or false)", ConfigKeyWebhookEnrichDeployments, raw)
+	}
+	return enabled, nil
+}
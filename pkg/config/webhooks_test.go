@@ -0,0 +1,22 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseWebhookEnrichDeployments(t *testing.T) {
+	g := NewWithT(t)
+
+	enabled, err := ParseWebhookEnrichDeployments(map[string]string{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(enabled).To(BeFalse())
+
+	enabled, err = ParseWebhookEnrichDeployments(map[string]string{ConfigKeyWebhookEnrichDeployments: " true "})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(enabled).To(BeTrue())
+
+	_, err = ParseWebhookEnrichDeployments(map[string]string{ConfigKeyWebhookEnrichDeployments: "sometimes"})
+	g.Expect(err).To(MatchError(ContainSubstring(ConfigKeyWebhookEnrichDeployments)))
+}
@@ -0,0 +1,130 @@
+// Package cost estimates what the resources requested by kibaship workloads cost.
+//
+// Estimates are based on resource requests rather than actual usage, which is what a
+// workload reserves on a shared cluster, priced with the per hour rates from the
+// operator ConfigMap.
+package cost
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// HistoryDays is the number of days of cost history kept per project
+const HistoryDays = 30
+
+// DateFormat is the layout of DailyCost dates
+const DateFormat = "2006-01-02"
+
+const bytesPerGiB = 1 << 30
+
+// Usage is an amount of requested CPU, memory and storage
+type Usage struct {
+	CPU     resource.Quantity
+	Memory  resource.Quantity
+	Storage resource.Quantity
+}
+
+// Add adds other to the usage
+func (u *Usage) Add(other Usage) {
+	u.CPU.Add(other.CPU)
+	u.Memory.Add(other.Memory)
+	u.Storage.Add(other.Storage)
+}
+
+// PodUsage returns the CPU and memory requested by a pod. Init containers run before the
+// regular containers, so a pod reserves the larger of the two.
+func PodUsage(pod *corev1.Pod) Usage {
+	var usage Usage
+	for _, container := range pod.Spec.Containers {
+		usage.CPU.Add(container.Resources.Requests[corev1.ResourceCPU])
+		usage.Memory.Add(container.Resources.Requests[corev1.ResourceMemory])
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if cpu := container.Resources.Requests[corev1.ResourceCPU]; cpu.Cmp(usage.CPU) > 0 {
+			usage.CPU = cpu.DeepCopy()
+		}
+		if memory := container.Resources.Requests[corev1.ResourceMemory]; memory.Cmp(usage.Memory) > 0 {
+			usage.Memory = memory.DeepCopy()
+		}
+	}
+	for name, quantity := range pod.Spec.Overhead {
+		switch name {
+		case corev1.ResourceCPU:
+			usage.CPU.Add(quantity)
+		case corev1.ResourceMemory:
+			usage.Memory.Add(quantity)
+		}
+	}
+	return usage
+}
+
+// PVCUsage returns the storage requested by a persistent volume claim
+func PVCUsage(pvc *corev1.PersistentVolumeClaim) Usage {
+	var usage Usage
+	usage.Storage.Add(pvc.Spec.Resources.Requests[corev1.ResourceStorage])
+	return usage
+}
+
+// Hourly returns the cost of the usage for one hour
+func Hourly(rates config.CostConfig, usage Usage) float64 {
+	cores := float64(usage.CPU.MilliValue()) / 1000
+	memoryGiB := float64(usage.Memory.Value()) / bytesPerGiB
+	storageGiB := float64(usage.Storage.Value()) / bytesPerGiB
+	return cores*rates.CPUCoreHour + memoryGiB*rates.MemoryGBHour + storageGiB*rates.StorageGBHour
+}
+
+// FormatAmount formats a cost for the Project status
+func FormatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 4, 64)
+}
+
+// ParseAmount parses a cost from the Project status, treating malformed values as zero
+func ParseAmount(amount string) float64 {
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// Accrue adds the cost of running at hourly between from and to to the daily history,
+// splitting it across UTC days. Only the last HistoryDays days are kept.
+func Accrue(history []v1alpha1.DailyCost, hourly float64, from, to time.Time) []v1alpha1.DailyCost {
+	totals := make(map[string]float64, len(history)+1)
+	for _, day := range history {
+		totals[day.Date] = ParseAmount(day.Cost)
+	}
+
+	from, to = from.UTC(), to.UTC()
+	for from.Before(to) {
+		end := from.Truncate(24 * time.Hour).Add(24 * time.Hour)
+		if end.After(to) {
+			end = to
+		}
+		totals[from.Format(DateFormat)] += hourly * end.Sub(from).Hours()
+		from = end
+	}
+
+	dates := make([]string, 0, len(totals))
+	for date := range totals {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	if len(dates) > HistoryDays {
+		dates = dates[len(dates)-HistoryDays:]
+	}
+
+	result := make([]v1alpha1.DailyCost, 0, len(dates))
+	for _, date := range dates {
+		result = append(result, v1alpha1.DailyCost{Date: date, Cost: FormatAmount(totals[date])})
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package cost
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func requests(cpu, memory string) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}}
+}
+
+func TestPodUsage(t *testing.T) {
+	g := NewWithT(t)
+
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: "app", Resources: requests("500m", "512Mi")},
+			{Name: "sidecar", Resources: requests("100m", "128Mi")},
+		},
+		InitContainers: []corev1.Container{
+			{Name: "migrate", Resources: requests("1", "256Mi")},
+		},
+	}}
+
+	usage := PodUsage(pod)
+	g.Expect(usage.CPU.MilliValue()).To(Equal(int64(1000)))
+	g.Expect(usage.Memory.Value()).To(Equal(int64(640 << 20)))
+}
+
+func TestHourly(t *testing.T) {
+	g := NewWithT(t)
+
+	rates := config.CostConfig{CPUCoreHour: 0.02, MemoryGBHour: 0.01, StorageGBHour: 0.001}
+	usage := Usage{
+		CPU:     resource.MustParse("1500m"),
+		Memory:  resource.MustParse("2Gi"),
+		Storage: resource.MustParse("10Gi"),
+	}
+	g.Expect(Hourly(rates, usage)).To(BeNumerically("~", 0.03+0.02+0.01, 1e-9))
+	g.Expect(Hourly(config.CostConfig{}, usage)).To(BeZero())
+}
+
+func TestAccrue(t *testing.T) {
+	g := NewWithT(t)
+
+	// Two hours across midnight are split between both days
+	from := time.Date(2025, 10, 14, 23, 0, 0, 0, time.UTC)
+	history := Accrue([]v1alpha1.DailyCost{{Date: "2025-10-14", Cost: "1.0000"}}, 0.5, from, from.Add(2*time.Hour))
+	g.Expect(history).To(Equal([]v1alpha1.DailyCost{
+		{Date: "2025-10-14", Cost: "1.5000"},
+		{Date: "2025-10-15", Cost: "0.5000"},
+	}))
+
+	// Nothing accrues when no time passed
+	g.Expect(Accrue(history, 0.5, from, from)).To(Equal(history))
+
+	// Old days are dropped
+	var long []v1alpha1.DailyCost
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	long = Accrue(long, 1, start, start.Add(40*24*time.Hour))
+	g.Expect(long).To(HaveLen(HistoryDays))
+	g.Expect(long[0].Date).To(Equal("2025-09-11"))
+	g.Expect(long[HistoryDays-1].Cost).To(Equal("24.0000"))
+}
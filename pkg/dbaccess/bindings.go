@@ -0,0 +1,68 @@
+package dbaccess
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// postgresBindingSchema is the schema the users of Postgres service bindings are granted
+const postgresBindingSchema = "public"
+
+// BindingUsername returns the database user of the service binding of an application
+func BindingUsername(applicationUUID string) string {
+	id := strings.ReplaceAll(applicationUUID, "-", "")
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return "app_" + strings.ToLower(id)
+}
+
+// BindingUser returns the database user created on the shared database behind conn for the
+// service binding of an application: the application database on MySQL, the public schema of the
+// application database on Postgres
+func BindingUser(conn Connection, applicationUUID string, binding v1alpha1.ServiceBinding) v1alpha1.DatabaseUser {
+	user := v1alpha1.DatabaseUser{Username: BindingUsername(applicationUUID), ReadOnly: binding.ReadOnly}
+	switch {
+	case conn.Engine == EnginePostgres:
+		user.Databases = []string{postgresBindingSchema}
+	case conn.Database != "":
+		user.Databases = []string{conn.Database}
+	}
+	return user
+}
+
+// BindingEnv returns the variables injected into the containers of an application bound to the
+// shared database behind conn. The credentials are read from the Secret of the binding user, the
+// URL is expanded from them by the kubelet.
+func BindingEnv(conn Connection, namespace, sharedUUID, applicationUUID string, binding v1alpha1.ServiceBinding) []corev1.EnvVar {
+	prefix := binding.Prefix()
+	database := conn.Database
+	if conn.Engine == EnginePostgres && database == "" {
+		database = "postgres"
+	}
+	secret := UserSecretName(sharedUUID, BindingUsername(applicationUUID))
+	secretEnv := func(name, key string) corev1.EnvVar {
+		return corev1.EnvVar{Name: name, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+			Key:                  key,
+		}}}
+	}
+
+	url := fmt.Sprintf("%s://$(%s_USERNAME):$(%s_PASSWORD)@%s:%d", conn.Scheme, prefix, prefix, conn.Host(namespace), conn.Port)
+	if database != "" {
+		url += "/" + database
+	}
+	return []corev1.EnvVar{
+		{Name: prefix + "_HOST", Value: conn.Host(namespace)},
+		{Name: prefix + "_PORT", Value: fmt.Sprintf("%d", conn.Port)},
+		{Name: prefix + "_DATABASE", Value: database},
+		secretEnv(prefix+"_USERNAME", UsernameKey),
+		secretEnv(prefix+"_PASSWORD", PasswordKey),
+		// Variables referenced with $(NAME) must be listed before
+		{Name: prefix + "_URL", Value: url},
+	}
+}
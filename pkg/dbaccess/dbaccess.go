@@ -0,0 +1,260 @@
+// Package dbaccess describes how to reach the database applications and renders the scripts
+// creating their additional databases and users.
+//
+// The operator applies the spec.databaseAccess of a MySQL or Postgres application with a Job
+// running the database client against the application, authenticated as its administrative
+// user. Every user gets a generated password stored in a Secret, the scripts read the passwords
+// from the environment so they never appear in the Job spec. Scripts are idempotent: the Job is
+// identified by the hash of its script and only rerun when the declared access changes.
+//
+// Applications bound to a shared database get a user of their own, named after the bound
+// application, applied by the same Job as the declared users.
+package dbaccess
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// Engine is the database engine of an application
+type Engine string
+
+const (
+	EngineMySQL    Engine = "mysql"
+	EnginePostgres Engine = "postgres"
+	EngineValkey   Engine = "valkey"
+)
+
+// PasswordKey is the key of the password in the Secret of a database user
+const PasswordKey = "password"
+
+// UsernameKey is the key of the username in the Secret of a database user
+const UsernameKey = "username"
+
+// Connection describes where a database application listens and keeps its credentials
+type Connection struct {
+	Engine Engine
+	// Scheme of the connection strings clients use
+	Scheme   string
+	Service  string
+	Port     int32
+	Database string
+	// Secret holding the administrative credentials
+	Secret string
+	// Username is the administrative user, unless the Secret holds it under UsernameKey
+	Username    string
+	UsernameKey string
+	PasswordKey string
+}
+
+// ConnectionFor returns the connection details of a database application, following the resource
+// names the database operators are given. A secretRef on the application wins over the Secret the
+// operator generates.
+func ConnectionFor(app *v1alpha1.Application) (Connection, bool) {
+	uuid := app.Labels[validation.LabelResourceUUID]
+
+	var conn Connection
+	var secretName string
+	switch app.Spec.Type {
+	case v1alpha1.ApplicationTypeMySQL, v1alpha1.ApplicationTypeMySQLCluster:
+		conn = Connection{Engine: EngineMySQL, Scheme: "mysql", Port: 3306, Username: "root", UsernameKey: "rootUser", PasswordKey: "rootPassword"}
+		if app.Spec.Type == v1alpha1.ApplicationTypeMySQL && app.Spec.MySQL != nil {
+			conn.Service = utils.GetMySQLResourceName(app.Spec.MySQL.Slug)
+			conn.Database = app.Spec.MySQL.Database
+			secretName = refName(app.Spec.MySQL.SecretRef)
+		}
+		if app.Spec.Type == v1alpha1.ApplicationTypeMySQLCluster && app.Spec.MySQLCluster != nil {
+			conn.Service = utils.GetMySQLClusterResourceName(app.Spec.MySQLCluster.Slug)
+			conn.Database = app.Spec.MySQLCluster.Database
+			secretName = refName(app.Spec.MySQLCluster.SecretRef)
+		}
+	case v1alpha1.ApplicationTypePostgres, v1alpha1.ApplicationTypePostgresCluster:
+		conn = Connection{Engine: EnginePostgres, Scheme: "postgres", Port: 5432, Username: "postgres", UsernameKey: "username", PasswordKey: "password"}
+		if app.Spec.Type == v1alpha1.ApplicationTypePostgres {
+			conn.Service = utils.GetPostgresResourceName(uuid)
+			if app.Spec.Postgres != nil {
+				conn.Database = app.Spec.Postgres.Database
+				secretName = refName(app.Spec.Postgres.SecretRef)
+			}
+		} else {
+			conn.Service = utils.GetPostgresClusterResourceName(uuid)
+			if app.Spec.PostgresCluster != nil {
+				conn.Database = app.Spec.PostgresCluster.Database
+				secretName = refName(app.Spec.PostgresCluster.SecretRef)
+			}
+		}
+	case v1alpha1.ApplicationTypeValkey, v1alpha1.ApplicationTypeValkeyCluster:
+		// Valkey speaks the Redis protocol, clients connect with redis:// URLs
+		conn = Connection{Engine: EngineValkey, Scheme: "redis", Port: 6379, Username: "default", PasswordKey: "password"}
+		var database int32
+		if app.Spec.Type == v1alpha1.ApplicationTypeValkey {
+			conn.Service = utils.GetValkeyResourceName(uuid)
+			if app.Spec.Valkey != nil {
+				database = app.Spec.Valkey.Database
+				secretName = refName(app.Spec.Valkey.SecretRef)
+			}
+		} else {
+			conn.Service = utils.GetValkeyClusterResourceName(uuid)
+			if app.Spec.ValkeyCluster != nil {
+				database = app.Spec.ValkeyCluster.Database
+				secretName = refName(app.Spec.ValkeyCluster.SecretRef)
+			}
+		}
+		conn.Database = strconv.Itoa(int(database))
+	default:
+		return Connection{}, false
+	}
+
+	conn.Secret = conn.Service
+	if secretName != "" {
+		conn.Secret = secretName
+	}
+	return conn, true
+}
+
+// Host returns the in-cluster DNS name of the database in namespace
+func (c Connection) Host(namespace string) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", c.Service, namespace)
+}
+
+// SupportsAccess reports whether additional databases and users can be managed on the engine
+func (c Connection) SupportsAccess() bool {
+	return c.Engine == EngineMySQL || c.Engine == EnginePostgres
+}
+
+// UserSecretName returns the name of the Secret holding the password of a database user
+func UserSecretName(applicationUUID, username string) string {
+	return fmt.Sprintf("%s-user-%s", utils.GetApplicationResourceName(applicationUUID), strings.ReplaceAll(username, "_", "-"))
+}
+
+// PasswordEnv returns the environment variable the script reads the password of a user from
+func PasswordEnv(username string) string {
+	return "USER_" + strings.ToUpper(username) + "_PASSWORD"
+}
+
+// Script renders the shell script applying access to the database behind conn and dropping the
+// users in removed. The script authenticates with the ADMIN_USERNAME and ADMIN_PASSWORD
+// environment variables and reads user passwords from PasswordEnv.
+func Script(conn Connection, namespace string, access v1alpha1.DatabaseAccessConfig, removed []string) string {
+	users := append([]v1alpha1.DatabaseUser{}, access.Users...)
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	removed = append([]string{}, removed...)
+	sort.Strings(removed)
+
+	if conn.Engine == EnginePostgres {
+		return postgresScript(conn, namespace, access.Databases, users, removed)
+	}
+	return mysqlScript(conn, namespace, access.Databases, users, removed)
+}
+
+// Hash identifies a script, Jobs are named after it
+func Hash(script string) string {
+	sum := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(sum[:])[:10]
+}
+
+// mysqlScript renders the script for MySQL. The heredoc is quoted, passwords are hex encoded by
+// the shell into session variables ahead of it and set with prepared statements, so they are
+// never interpreted by the shell nor parsed as SQL.
+func mysqlScript(conn Connection, namespace string, databases []v1alpha1.DatabaseSchema, users []v1alpha1.DatabaseUser, removed []string) string {
+	var b strings.Builder
+	b.WriteString("set -eu\n")
+	b.WriteString("{\n")
+	for _, user := range users {
+		fmt.Fprintf(&b, "printf \"SET @%s = UNHEX('%%s');\\n\" \"$(printf '%%s' \"$%s\" | od -An -v -tx1 | tr -d ' \\n')\"\n",
+			strings.ToLower(PasswordEnv(user.Username)), PasswordEnv(user.Username))
+	}
+	b.WriteString("cat <<'SQL'\n")
+	for _, database := range databases {
+		fmt.Fprintf(&b, "CREATE DATABASE IF NOT EXISTS `%s`;\n", database.Name)
+	}
+	for _, user := range users {
+		account := fmt.Sprintf("'%s'@'%%'", user.Username)
+		fmt.Fprintf(&b, "CREATE USER IF NOT EXISTS %s;\n", account)
+		fmt.Fprintf(&b, "SET @statement = CONCAT('ALTER USER ''%s''@''%%'' IDENTIFIED BY ', QUOTE(CONVERT(@%s USING utf8mb4)));\n",
+			user.Username, strings.ToLower(PasswordEnv(user.Username)))
+		b.WriteString("PREPARE statement FROM @statement;\nEXECUTE statement;\nDEALLOCATE PREPARE statement;\n")
+		fmt.Fprintf(&b, "REVOKE ALL PRIVILEGES, GRANT OPTION FROM %s;\n", account)
+		privileges := "ALL PRIVILEGES"
+		if user.ReadOnly {
+			privileges = "SELECT, SHOW VIEW"
+		}
+		for _, database := range user.Databases {
+			fmt.Fprintf(&b, "GRANT %s ON `%s`.* TO %s;\n", privileges, database, account)
+		}
+	}
+	for _, username := range removed {
+		fmt.Fprintf(&b, "DROP USER IF EXISTS '%s'@'%%';\n", username)
+	}
+	b.WriteString("SQL\n")
+	fmt.Fprintf(&b, "} | mysql --host=%s --port=%d --user=\"${ADMIN_USERNAME:-%s}\" --password=\"$ADMIN_PASSWORD\"\n",
+		conn.Host(namespace), conn.Port, conn.Username)
+	return b.String()
+}
+
+// postgresScript renders the script for Postgres. Databases are schemas of the application
+// database. The heredoc is quoted, passwords are passed as psql variables.
+func postgresScript(conn Connection, namespace string, databases []v1alpha1.DatabaseSchema, users []v1alpha1.DatabaseUser, removed []string) string {
+	database := conn.Database
+	if database == "" {
+		database = "postgres"
+	}
+
+	var b strings.Builder
+	b.WriteString("set -eu\n")
+	b.WriteString("export PGPASSWORD=\"$ADMIN_PASSWORD\"\n")
+	fmt.Fprintf(&b, "psql --host=%s --port=%d --username=\"${ADMIN_USERNAME:-%s}\" --dbname=%s -v ON_ERROR_STOP=1",
+		conn.Host(namespace), conn.Port, conn.Username, database)
+	for _, user := range users {
+		fmt.Fprintf(&b, " -v %s=\"$%s\"", strings.ToLower(PasswordEnv(user.Username)), PasswordEnv(user.Username))
+	}
+	b.WriteString(" <<'SQL'\n")
+	for _, schema := range databases {
+		fmt.Fprintf(&b, "CREATE SCHEMA IF NOT EXISTS \"%s\";\n", schema.Name)
+	}
+	for _, user := range users {
+		role := fmt.Sprintf("\"%s\"", user.Username)
+		fmt.Fprintf(&b, "SELECT format('CREATE ROLE %%I LOGIN', '%s') WHERE NOT EXISTS (SELECT FROM pg_roles WHERE rolname = '%s')\\gexec\n",
+			user.Username, user.Username)
+		fmt.Fprintf(&b, "ALTER ROLE %s WITH LOGIN PASSWORD :'%s';\n", role, strings.ToLower(PasswordEnv(user.Username)))
+		fmt.Fprintf(&b, "GRANT CONNECT ON DATABASE \"%s\" TO %s;\n", database, role)
+		for _, schema := range user.Databases {
+			if user.ReadOnly {
+				fmt.Fprintf(&b, "REVOKE CREATE ON SCHEMA \"%s\" FROM %s;\n", schema, role)
+				fmt.Fprintf(&b, "GRANT USAGE ON SCHEMA \"%s\" TO %s;\n", schema, role)
+				fmt.Fprintf(&b, "GRANT SELECT ON ALL TABLES IN SCHEMA \"%s\" TO %s;\n", schema, role)
+				fmt.Fprintf(&b, "ALTER DEFAULT PRIVILEGES IN SCHEMA \"%s\" GRANT SELECT ON TABLES TO %s;\n", schema, role)
+				continue
+			}
+			fmt.Fprintf(&b, "GRANT USAGE, CREATE ON SCHEMA \"%s\" TO %s;\n", schema, role)
+			fmt.Fprintf(&b, "GRANT ALL ON ALL TABLES IN SCHEMA \"%s\" TO %s;\n", schema, role)
+			fmt.Fprintf(&b, "GRANT ALL ON ALL SEQUENCES IN SCHEMA \"%s\" TO %s;\n", schema, role)
+			fmt.Fprintf(&b, "ALTER DEFAULT PRIVILEGES IN SCHEMA \"%s\" GRANT ALL ON TABLES TO %s;\n", schema, role)
+		}
+	}
+	for _, username := range removed {
+		// Objects of the dropped role are kept, they are handed to the administrative user
+		fmt.Fprintf(&b, "SELECT format('REASSIGN OWNED BY %%1$I TO CURRENT_USER; DROP OWNED BY %%1$I; DROP ROLE %%1$I', '%s') WHERE EXISTS (SELECT FROM pg_roles WHERE rolname = '%s')\\gexec\n",
+			username, username)
+	}
+	b.WriteString("SQL\n")
+	return b.String()
+}
+
+// refName returns the name of an optional reference
+func refName(ref *corev1.LocalObjectReference) string {
+	if ref == nil {
+		return ""
+	}
+	return ref.Name
+}
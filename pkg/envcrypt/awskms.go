@@ -0,0 +1,191 @@
+package envcrypt
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWSCredentials are the static credentials used to sign KMS requests
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// AWSKMSProvider wraps data keys with an AWS KMS symmetric key.
+// Requests are sent to the KMS JSON API and signed with Signature Version 4.
+type AWSKMSProvider struct {
+	keyID       string
+	region      string
+	endpoint    string
+	credentials AWSCredentials
+	client      *http.Client
+	now         func() time.Time
+}
+
+// NewAWSKMSProvider creates a provider for the given key. An empty endpoint uses the regional
+// KMS endpoint (https://kms.<region>.amazonaws.com).
+func NewAWSKMSProvider(keyID, region, endpoint string, credentials AWSCredentials) (*AWSKMSProvider, error) {
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, fmt.Errorf("aws access key id and secret access key are required")
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com", region)
+	}
+	return &AWSKMSProvider{
+		keyID:       keyID,
+		region:      region,
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		credentials: credentials,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		now:         time.Now,
+	}, nil
+}
+
+// Name implements KeyProvider
+func (p *AWSKMSProvider) Name() string {
+	return "awskms"
+}
+
+// WrapKey implements KeyProvider
+func (p *AWSKMSProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	var resp struct {
+		CiphertextBlob []byte `json:"CiphertextBlob"`
+	}
+	body := map[string]any{"KeyId": p.keyID, "Plaintext": dataKey}
+	if err := p.call(ctx, "Encrypt", body, &resp); err != nil {
+		return nil, err
+	}
+	return resp.CiphertextBlob, nil
+}
+
+// UnwrapKey implements KeyProvider
+func (p *AWSKMSProvider) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var resp struct {
+		Plaintext []byte `json:"Plaintext"`
+	}
+	body := map[string]any{"KeyId": p.keyID, "CiphertextBlob": wrapped}
+	if err := p.call(ctx, "Decrypt", body, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
+
+func (p *AWSKMSProvider) call(ctx context.Context, operation string, body any, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+operation)
+	p.sign(req, payload)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("kms %s: %w", operation, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("kms %s: read response: %w", operation, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kms %s: unexpected status %d: %s", operation, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("kms %s: decode response: %w", operation, err)
+	}
+	return nil
+}
+
+// sign adds AWS Signature Version 4 headers to a KMS request
+func (p *AWSKMSProvider) sign(req *http.Request, payload []byte) {
+	now := p.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if p.credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.credentials.SessionToken)
+	}
+
+	signedHeaders := []string{"content-type", "host", "x-amz-date", "x-amz-target"}
+	if p.credentials.SessionToken != "" {
+		signedHeaders = append(signedHeaders, "x-amz-security-token")
+	}
+	// Signed header names must be sorted
+	sort.Strings(signedHeaders)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/kms/aws4_request", date, p.region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+p.credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, p.region)
+	key = hmacSHA256(key, "kms")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.credentials.AccessKeyID, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+func canonicalQuery(values url.Values) string {
+	// url.Values.Encode sorts by key
+	return strings.ReplaceAll(values.Encode(), "+", "%20")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,205 @@
+// Package envcrypt implements envelope encryption for application environment variables.
+//
+// Every value is encrypted with a fresh AES-256-GCM data key. The data key is wrapped by an
+// external key management service (Vault transit, AWS KMS or a static local key) and stored
+// next to the ciphertext, so Secrets only ever hold encrypted values. The operator unwraps the
+// data key when it projects the values into application pods.
+package envcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	// ValuePrefix marks an encrypted env var value
+	ValuePrefix = "kibaship:enc:v1:"
+
+	// AnnotationProvider is set on env var Secrets whose values are encrypted
+	AnnotationProvider = "platform.kibaship.com/env-encryption"
+
+	dataKeySize = 32
+)
+
+// KeyProvider wraps and unwraps data keys with a key encryption key held by a KMS
+type KeyProvider interface {
+	// Name identifies the provider in encrypted values
+	Name() string
+	// WrapKey encrypts a data key
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key returned by WrapKey
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// envelope is the serialized form of an encrypted value
+type envelope struct {
+	Provider   string `json:"p"`
+	WrappedKey []byte `json:"k"`
+	Nonce      []byte `json:"n"`
+	Ciphertext []byte `json:"c"`
+}
+
+// Encryptor encrypts and decrypts env var values with a KeyProvider.
+// Unwrapped data keys are cached in memory to avoid a KMS round trip per value.
+type Encryptor struct {
+	provider KeyProvider
+
+	mu    sync.RWMutex
+	cache map[string][]byte
+}
+
+// NewEncryptor creates an Encryptor backed by the given KeyProvider
+func NewEncryptor(provider KeyProvider) *Encryptor {
+	return &Encryptor{
+		provider: provider,
+		cache:    make(map[string][]byte),
+	}
+}
+
+// ProviderName returns the name of the underlying KeyProvider
+func (e *Encryptor) ProviderName() string {
+	return e.provider.Name()
+}
+
+// IsEncrypted reports whether a value was produced by Encrypt
+func IsEncrypted(value []byte) bool {
+	return strings.HasPrefix(string(value), ValuePrefix)
+}
+
+// Encrypt encrypts a single value with a fresh data key
+func (e *Encryptor) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("generate data key: %w", err)
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	wrapped, err := e.provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrap data key with %s: %w", e.provider.Name(), err)
+	}
+
+	raw, err := json.Marshal(envelope{
+		Provider:   e.provider.Name(),
+		WrappedKey: wrapped,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(ValuePrefix + base64.StdEncoding.EncodeToString(raw)), nil
+}
+
+// Decrypt decrypts a value produced by Encrypt. Values without the encryption prefix
+// are returned unchanged so Secrets written before encryption was enabled keep working.
+func (e *Encryptor) Decrypt(ctx context.Context, value []byte) ([]byte, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(value), ValuePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("decode encrypted value: %w", err)
+	}
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return nil, fmt.Errorf("decode encrypted value: %w", err)
+	}
+	if env.Provider != e.provider.Name() {
+		return nil, fmt.Errorf("value was encrypted with %s, configured provider is %s", env.Provider, e.provider.Name())
+	}
+
+	dataKey, err := e.unwrap(ctx, env.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+// EncryptData encrypts every value of a Secret data map. Already encrypted values are kept.
+func (e *Encryptor) EncryptData(ctx context.Context, data map[string][]byte) (map[string][]byte, error) {
+	out := make(map[string][]byte, len(data))
+	for key, value := range data {
+		if IsEncrypted(value) {
+			out[key] = value
+			continue
+		}
+		encrypted, err := e.Encrypt(ctx, value)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt %s: %w", key, err)
+		}
+		out[key] = encrypted
+	}
+	return out, nil
+}
+
+// DecryptData decrypts every value of a Secret data map
+func (e *Encryptor) DecryptData(ctx context.Context, data map[string][]byte) (map[string][]byte, error) {
+	out := make(map[string][]byte, len(data))
+	for key, value := range data {
+		plaintext, err := e.Decrypt(ctx, value)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt %s: %w", key, err)
+		}
+		out[key] = plaintext
+	}
+	return out, nil
+}
+
+// unwrap returns the data key for a wrapped key, asking the KMS only on cache misses
+func (e *Encryptor) unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	cacheKey := string(wrapped)
+
+	e.mu.RLock()
+	dataKey, ok := e.cache[cacheKey]
+	e.mu.RUnlock()
+	if ok {
+		return dataKey, nil
+	}
+
+	dataKey, err := e.provider.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key with %s: %w", e.provider.Name(), err)
+	}
+	if len(dataKey) != dataKeySize {
+		return nil, fmt.Errorf("unwrap data key with %s: unexpected key length %d", e.provider.Name(), len(dataKey))
+	}
+
+	e.mu.Lock()
+	e.cache[cacheKey] = dataKey
+	e.mu.Unlock()
+	return dataKey, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
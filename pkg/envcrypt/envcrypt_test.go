@@ -0,0 +1,161 @@
+package envcrypt
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func testLocalProvider(t *testing.T) *LocalProvider {
+	provider, err := NewLocalProvider([]byte(strings.Repeat("k", 32)))
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+	return provider
+}
+
+func TestEncryptorRoundTrip(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	e := NewEncryptor(testLocalProvider(t))
+
+	encrypted, err := e.Encrypt(ctx, []byte("postgres://user:pass@db/app"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(IsEncrypted(encrypted)).To(BeTrue())
+	g.Expect(string(encrypted)).NotTo(ContainSubstring("postgres"))
+
+	plaintext, err := e.Decrypt(ctx, encrypted)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(plaintext)).To(Equal("postgres://user:pass@db/app"))
+
+	// Values stored before encryption was enabled pass through unchanged
+	plaintext, err = e.Decrypt(ctx, []byte("plain"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(plaintext)).To(Equal("plain"))
+}
+
+func TestEncryptorData(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	e := NewEncryptor(testLocalProvider(t))
+
+	encrypted, err := e.EncryptData(ctx, map[string][]byte{"A": []byte("1"), "B": []byte("2")})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(IsEncrypted(encrypted["A"])).To(BeTrue())
+
+	again, err := e.EncryptData(ctx, encrypted)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(again["A"]).To(Equal(encrypted["A"]))
+
+	decrypted, err := e.DecryptData(ctx, again)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(decrypted).To(Equal(map[string][]byte{"A": []byte("1"), "B": []byte("2")}))
+}
+
+func TestEncryptorWrongKey(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	encrypted, err := NewEncryptor(testLocalProvider(t)).Encrypt(ctx, []byte("secret"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	other, err := NewLocalProvider([]byte(strings.Repeat("x", 32)))
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = NewEncryptor(other).Decrypt(ctx, encrypted)
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = NewLocalProvider([]byte("short"))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestVaultTransitProvider(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	var decrypts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/kibaship":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{"ciphertext": "vault:v1:" + body["plaintext"]}})
+		case "/v1/transit/decrypt/kibaship":
+			decrypts.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{"plaintext": strings.TrimPrefix(body["ciphertext"], "vault:v1:")}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewVaultTransitProvider(server.URL, "transit", "kibaship", "s.token")
+	g.Expect(err).NotTo(HaveOccurred())
+	e := NewEncryptor(provider)
+
+	encrypted, err := e.Encrypt(ctx, []byte("value"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for range 2 {
+		plaintext, err := e.Decrypt(ctx, encrypted)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(string(plaintext)).To(Equal("value"))
+	}
+	// The unwrapped data key is cached
+	g.Expect(decrypts.Load()).To(Equal(int32(1)))
+
+	denied, err := NewVaultTransitProvider(server.URL, "transit", "kibaship", "wrong")
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = NewEncryptor(denied).Encrypt(ctx, []byte("value"))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("unexpected status 403"))
+}
+
+func TestAWSKMSProvider(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			!strings.Contains(r.Header.Get("Authorization"), "/eu-west-1/kms/aws4_request") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.Encrypt":
+			_ = json.NewEncoder(w).Encode(map[string]string{"CiphertextBlob": body["Plaintext"]})
+		case "TrentService.Decrypt":
+			_ = json.NewEncoder(w).Encode(map[string]string{"Plaintext": body["CiphertextBlob"]})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewAWSKMSProvider("alias/kibaship", "eu-west-1", server.URL, AWSCredentials{
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	wrapped, err := provider.WrapKey(ctx, []byte("data-key"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(base64.StdEncoding.EncodeToString(wrapped)).To(Equal(base64.StdEncoding.EncodeToString([]byte("data-key"))))
+
+	e := NewEncryptor(provider)
+	encrypted, err := e.Encrypt(ctx, []byte("value"))
+	g.Expect(err).NotTo(HaveOccurred())
+	plaintext, err := e.Decrypt(ctx, encrypted)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(plaintext)).To(Equal("value"))
+}
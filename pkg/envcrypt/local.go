@@ -0,0 +1,53 @@
+package envcrypt
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// LocalProvider wraps data keys with a static AES-256 key encryption key.
+// It needs no external service but the key lives in a cluster Secret, so it only
+// protects against reads of the env var Secrets themselves.
+type LocalProvider struct {
+	key []byte
+}
+
+// NewLocalProvider creates a LocalProvider from a 32 byte key
+func NewLocalProvider(key []byte) (*LocalProvider, error) {
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("local encryption key must be %d bytes, got %d", dataKeySize, len(key))
+	}
+	return &LocalProvider{key: key}, nil
+}
+
+// Name implements KeyProvider
+func (p *LocalProvider) Name() string {
+	return "local"
+}
+
+// WrapKey implements KeyProvider
+func (p *LocalProvider) WrapKey(_ context.Context, dataKey []byte) ([]byte, error) {
+	gcm, err := newGCM(p.key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, dataKey, nil), nil
+}
+
+// UnwrapKey implements KeyProvider
+func (p *LocalProvider) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	gcm, err := newGCM(p.key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, fmt.Errorf("wrapped key is too short")
+	}
+	nonce, ciphertext := wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,63 @@
+package envcrypt
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// Keys of the encryption credentials Secret
+const (
+	CredentialVaultToken         = "vault-token"
+	CredentialAWSAccessKeyID     = "aws-access-key-id"
+	CredentialAWSSecretAccessKey = "aws-secret-access-key"
+	CredentialAWSSessionToken    = "aws-session-token"
+	CredentialLocalKey           = "local-key"
+)
+
+// NewKeyProvider creates the KeyProvider selected by the operator configuration.
+// credentials is the data of the kibaship-encryption Secret.
+func NewKeyProvider(cfg config.EncryptionConfig, credentials map[string][]byte) (KeyProvider, error) {
+	switch cfg.Provider {
+	case config.EncryptionProviderVault:
+		return NewVaultTransitProvider(cfg.VaultAddress, cfg.VaultMount, cfg.VaultKey, string(credentials[CredentialVaultToken]))
+	case config.EncryptionProviderAWSKMS:
+		return NewAWSKMSProvider(cfg.AWSKMSKeyID, cfg.AWSRegion, "", AWSCredentials{
+			AccessKeyID:     string(credentials[CredentialAWSAccessKeyID]),
+			SecretAccessKey: string(credentials[CredentialAWSSecretAccessKey]),
+			SessionToken:    string(credentials[CredentialAWSSessionToken]),
+		})
+	case config.EncryptionProviderLocal:
+		return NewLocalProvider(credentials[CredentialLocalKey])
+	default:
+		return nil, fmt.Errorf("unsupported encryption provider %q", cfg.Provider)
+	}
+}
+
+// Load creates an Encryptor from the encryption configuration and the kibaship-encryption Secret.
+// It returns nil when encryption is disabled.
+func Load(ctx context.Context, c client.Reader, cfg config.EncryptionConfig) (*Encryptor, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: config.OperatorNamespace, Name: config.EncryptionCredentialsSecretName}
+	if err := c.Get(ctx, key, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("encryption credentials Secret %s/%s not found", key.Namespace, key.Name)
+		}
+		return nil, fmt.Errorf("failed to get encryption credentials Secret: %w", err)
+	}
+
+	provider, err := NewKeyProvider(cfg, secret.Data)
+	if err != nil {
+		return nil, err
+	}
+	return NewEncryptor(provider), nil
+}
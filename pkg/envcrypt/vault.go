@@ -0,0 +1,105 @@
+package envcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VaultTransitProvider wraps data keys with a HashiCorp Vault transit key
+type VaultTransitProvider struct {
+	address string
+	mount   string
+	key     string
+	token   string
+	client  *http.Client
+}
+
+// NewVaultTransitProvider creates a provider for the transit key at <address>/v1/<mount>/keys/<key>
+func NewVaultTransitProvider(address, mount, key, token string) (*VaultTransitProvider, error) {
+	if token == "" {
+		return nil, fmt.Errorf("vault token is required")
+	}
+	return &VaultTransitProvider{
+		address: strings.TrimSuffix(address, "/"),
+		mount:   strings.Trim(mount, "/"),
+		key:     key,
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name implements KeyProvider
+func (p *VaultTransitProvider) Name() string {
+	return "vault"
+}
+
+// WrapKey implements KeyProvider
+func (p *VaultTransitProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	body := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dataKey)}
+	if err := p.post(ctx, "encrypt", body, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data.Ciphertext == "" {
+		return nil, fmt.Errorf("vault returned an empty ciphertext")
+	}
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+// UnwrapKey implements KeyProvider
+func (p *VaultTransitProvider) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	body := map[string]string{"ciphertext": string(wrapped)}
+	if err := p.post(ctx, "decrypt", body, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+}
+
+func (p *VaultTransitProvider) post(ctx context.Context, operation string, body any, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/%s/%s", p.address, p.mount, operation, p.key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault %s: %w", operation, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("vault %s: read response: %w", operation, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vault %s: unexpected status %d: %s", operation, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("vault %s: decode response: %w", operation, err)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+// Package freeze evaluates deployment freeze windows.
+//
+// A freeze window starts whenever its cron schedule fires and lasts for a fixed duration.
+// Schedules use the standard five field cron syntax (minute hour day-of-month month day-of-week)
+// with lists, ranges, steps and three letter month and weekday names.
+package freeze
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxDuration is the longest supported freeze window
+const MaxDuration = 31 * 24 * time.Hour
+
+// Schedule is a parsed five field cron expression
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny record unrestricted fields, cron matches either day field
+	// when both are restricted
+	domAny, dowAny bool
+}
+
+type field struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{min: 0, max: 59}
+	hourField   = field{min: 0, max: 23}
+	domField    = field{min: 1, max: 31}
+	monthField  = field{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = field{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// ParseSchedule parses a five field cron expression such as "0 18 * * FRI"
+func ParseSchedule(expr string) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule %q must have 5 fields (minute hour day-of-month month day-of-week)", expr)
+	}
+
+	var s Schedule
+	var err error
+	if s.minute, err = parseField(fields[0], minuteField); err != nil {
+		return nil, fmt.Errorf("schedule %q: minute: %w", expr, err)
+	}
+	if s.hour, err = parseField(fields[1], hourField); err != nil {
+		return nil, fmt.Errorf("schedule %q: hour: %w", expr, err)
+	}
+	if s.dom, err = parseField(fields[2], domField); err != nil {
+		return nil, fmt.Errorf("schedule %q: day-of-month: %w", expr, err)
+	}
+	if s.month, err = parseField(fields[3], monthField); err != nil {
+		return nil, fmt.Errorf("schedule %q: month: %w", expr, err)
+	}
+	if s.dow, err = parseField(fields[4], dowField); err != nil {
+		return nil, fmt.Errorf("schedule %q: day-of-week: %w", expr, err)
+	}
+	// 7 is an alias for Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domAny = fields[2] == "*"
+	s.dowAny = fields[4] == "*"
+	return &s, nil
+}
+
+// Matches reports whether the schedule fires at the minute containing t
+func (s *Schedule) Matches(t time.Time) bool {
+	if s.minute&(1<<uint(t.Minute())) == 0 ||
+		s.hour&(1<<uint(t.Hour())) == 0 ||
+		s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// LastStart returns the latest time at or before now at which the schedule fired, looking back
+// at most lookback. ok is false when the schedule did not fire in that period.
+func (s *Schedule) LastStart(now time.Time, lookback time.Duration) (start time.Time, ok bool) {
+	t := now.Truncate(time.Minute)
+	earliest := now.Add(-lookback)
+	for !t.Before(earliest) {
+		if s.Matches(t) {
+			return t, true
+		}
+		t = t.Add(-time.Minute)
+	}
+	return time.Time{}, false
+}
+
+// Active reports whether a window starting on schedule and lasting duration covers now.
+// The schedule is evaluated in the given IANA timezone, UTC when empty.
+// It returns the end of the active window.
+func Active(schedule string, duration time.Duration, timezone string, now time.Time) (bool, time.Time, error) {
+	s, err := ParseSchedule(schedule)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	loc, err := LoadLocation(timezone)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	start, ok := s.LastStart(now.In(loc), duration)
+	if !ok {
+		return false, time.Time{}, nil
+	}
+	end := start.Add(duration)
+	if !now.Before(end) {
+		return false, time.Time{}, nil
+	}
+	return true, end, nil
+}
+
+// LoadLocation resolves an IANA timezone name, UTC when empty
+func LoadLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", timezone)
+	}
+	return loc, nil
+}
+
+// ValidateDuration checks that a freeze window duration is usable
+func ValidateDuration(duration time.Duration) error {
+	if duration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+	if duration > MaxDuration {
+		return fmt.Errorf("duration must not exceed %s", MaxDuration)
+	}
+	return nil
+}
+
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		b, err := parsePart(part, f)
+		if err != nil {
+			return 0, err
+		}
+		bits |= b
+	}
+	return bits, nil
+}
+
+func parsePart(part string, f field) (uint64, error) {
+	rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+
+	step := 1
+	if hasStep {
+		n, err := strconv.Atoi(stepExpr)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid step %q", stepExpr)
+		}
+		step = n
+	}
+
+	var low, high int
+	switch {
+	case rangeExpr == "*":
+		low, high = f.min, f.max
+	case strings.Contains(rangeExpr, "-"):
+		lowExpr, highExpr, _ := strings.Cut(rangeExpr, "-")
+		var err error
+		if low, err = parseValue(lowExpr, f); err != nil {
+			return 0, err
+		}
+		if high, err = parseValue(highExpr, f); err != nil {
+			return 0, err
+		}
+		if low > high {
+			return 0, fmt.Errorf("invalid range %q", rangeExpr)
+		}
+	default:
+		v, err := parseValue(rangeExpr, f)
+		if err != nil {
+			return 0, err
+		}
+		low, high = v, v
+		if hasStep {
+			high = f.max
+		}
+	}
+
+	var bits uint64
+	for v := low; v <= high; v += step {
+		bits |= 1 << uint(v)
+	}
+	return bits, nil
+}
+
+func parseValue(expr string, f field) (int, error) {
+	if v, ok := f.names[strings.ToLower(expr)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", expr)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, f.min, f.max)
+	}
+	return v, nil
+}
@@ -0,0 +1,80 @@
+package freeze
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseSchedule(t *testing.T) {
+	g := NewWithT(t)
+
+	for _, expr := range []string{"* * * * *", "0 18 * * FRI", "*/15 9-17 * * mon-fri", "0 0 1,15 * *", "30 22 * dec 7"} {
+		_, err := ParseSchedule(expr)
+		g.Expect(err).NotTo(HaveOccurred(), expr)
+	}
+
+	for _, expr := range []string{"", "0 18 * *", "60 * * * *", "0 24 * * *", "0 0 0 * *", "0 0 * 13 *", "0 0 * * funday", "*/0 * * * *", "5-1 * * * *"} {
+		_, err := ParseSchedule(expr)
+		g.Expect(err).To(HaveOccurred(), expr)
+	}
+}
+
+func TestScheduleMatches(t *testing.T) {
+	g := NewWithT(t)
+
+	s, err := ParseSchedule("0 18 * * FRI")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s.Matches(time.Date(2025, 10, 17, 18, 0, 0, 0, time.UTC))).To(BeTrue())
+	g.Expect(s.Matches(time.Date(2025, 10, 17, 18, 1, 0, 0, time.UTC))).To(BeFalse())
+	g.Expect(s.Matches(time.Date(2025, 10, 16, 18, 0, 0, 0, time.UTC))).To(BeFalse())
+
+	// Sunday can be written as 0 or 7
+	s, err = ParseSchedule("0 0 * * 7")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s.Matches(time.Date(2025, 10, 19, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+
+	// Restricted day-of-month and day-of-week match either
+	s, err = ParseSchedule("0 0 1 * MON")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s.Matches(time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+	g.Expect(s.Matches(time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+	g.Expect(s.Matches(time.Date(2025, 10, 21, 0, 0, 0, 0, time.UTC))).To(BeFalse())
+}
+
+func TestActive(t *testing.T) {
+	g := NewWithT(t)
+
+	// Weekend freeze: Friday 18:00 for 62 hours
+	weekend := 62 * time.Hour
+
+	active, end, err := Active("0 18 * * FRI", weekend, "", time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(active).To(BeTrue())
+	g.Expect(end).To(BeTemporally("==", time.Date(2025, 10, 20, 8, 0, 0, 0, time.UTC)))
+
+	active, _, err = Active("0 18 * * FRI", weekend, "", time.Date(2025, 10, 20, 8, 0, 0, 0, time.UTC))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(active).To(BeFalse())
+
+	active, _, err = Active("0 18 * * FRI", weekend, "", time.Date(2025, 10, 16, 12, 0, 0, 0, time.UTC))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(active).To(BeFalse())
+
+	// 18:00 in New York is 22:00 UTC during daylight saving time
+	active, _, err = Active("0 18 * * FRI", time.Hour, "America/New_York", time.Date(2025, 10, 17, 22, 30, 0, 0, time.UTC))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(active).To(BeTrue())
+
+	_, _, err = Active("0 18 * * FRI", time.Hour, "Mars/Olympus", time.Now())
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestValidateDuration(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(ValidateDuration(48 * time.Hour)).To(Succeed())
+	g.Expect(ValidateDuration(0)).NotTo(Succeed())
+	g.Expect(ValidateDuration(MaxDuration + time.Minute)).NotTo(Succeed())
+}
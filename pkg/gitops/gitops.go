@@ -0,0 +1,109 @@
+// Package gitops renders the platform resources as the files of a git repository and the script
+// committing them.
+//
+// The operator image carries no git client, so exports run as Jobs: the operator writes a
+// snapshot of the resources to a ConfigMap, and a Job mounting it with the deploy key clones the
+// repository, replaces the export directory with the snapshot and pushes when something changed.
+// Every resource is a file at <path>/<kind>/<name>.yaml, ready to be replayed with kubectl apply.
+package gitops
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/manifest"
+)
+
+const (
+	// SnapshotMountPath is where the export Job mounts the snapshot ConfigMap
+	SnapshotMountPath = "/snapshot"
+
+	// DeployKeyMountPath is where the export Job mounts the deploy key Secret
+	DeployKeyMountPath = "/deploy-key"
+
+	// keySeparator joins the kind directory and the file name in snapshot keys. ConfigMap keys
+	// cannot hold slashes, resource names cannot hold underscores.
+	keySeparator = "_"
+)
+
+// Directory returns the directory of the export holding resources of kind
+func Directory(kind string) string {
+	return strings.ToLower(kind) + "s"
+}
+
+// Snapshot renders objects as the data of the snapshot ConfigMap, keyed by <kind>_<name>.yaml.
+// Status is left out, it is rebuilt by the operator and would make every reconcile a commit.
+func Snapshot(objects []*unstructured.Unstructured) (map[string]string, error) {
+	data := make(map[string]string, len(objects))
+	for _, obj := range objects {
+		obj = obj.DeepCopy()
+		unstructured.RemoveNestedField(obj.Object, "status")
+
+		rendered, err := manifest.Marshal([]*unstructured.Unstructured{obj})
+		if err != nil {
+			return nil, err
+		}
+		key := Directory(obj.GetKind()) + keySeparator + obj.GetName() + ".yaml"
+		if _, exists := data[key]; exists {
+			return nil, fmt.Errorf("duplicate %s %s in snapshot", obj.GetKind(), obj.GetName())
+		}
+		data[key] = string(rendered)
+	}
+	return data, nil
+}
+
+// Hash identifies a snapshot, export Jobs are named after it
+func Hash(snapshot map[string]string) string {
+	keys := make([]string, 0, len(snapshot))
+	for key := range snapshot {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s\x00%s\x00", key, snapshot[key])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:10]
+}
+
+// Script renders the shell script committing the snapshot mounted at SnapshotMountPath to the
+// repository of cfg. An empty repository gets the branch created by the first export.
+func Script(cfg config.GitOpsConfig) string {
+	var b strings.Builder
+	b.WriteString("set -eu\n")
+	b.WriteString("export HOME=/tmp\n")
+	fmt.Fprintf(&b, "install -m 600 %s/%s /tmp/deploy-key\n", DeployKeyMountPath, config.GitOpsDeployKeyKey)
+	fmt.Fprintf(&b, "if [ -s %s/%s ]; then\n", DeployKeyMountPath, config.GitOpsKnownHostsKey)
+	fmt.Fprintf(&b, "  cp %s/%s /tmp/known_hosts\n", DeployKeyMountPath, config.GitOpsKnownHostsKey)
+	b.WriteString("  export GIT_SSH_COMMAND='ssh -i /tmp/deploy-key -o IdentitiesOnly=yes -o UserKnownHostsFile=/tmp/known_hosts -o StrictHostKeyChecking=yes'\n")
+	b.WriteString("else\n")
+	b.WriteString("  export GIT_SSH_COMMAND='ssh -i /tmp/deploy-key -o IdentitiesOnly=yes -o UserKnownHostsFile=/tmp/known_hosts -o StrictHostKeyChecking=accept-new'\n")
+	b.WriteString("fi\n")
+	fmt.Fprintf(&b, "if ! git clone --depth 1 --branch '%s' '%s' /tmp/repo; then\n", cfg.Branch, cfg.RepositoryURL)
+	fmt.Fprintf(&b, "  git init --initial-branch '%s' /tmp/repo\n", cfg.Branch)
+	fmt.Fprintf(&b, "  git -C /tmp/repo remote add origin '%s'\n", cfg.RepositoryURL)
+	b.WriteString("fi\n")
+	b.WriteString("cd /tmp/repo\n")
+	fmt.Fprintf(&b, "rm -rf '%s'\n", cfg.Path)
+	fmt.Fprintf(&b, "for file in %s/*.yaml; do\n", SnapshotMountPath)
+	b.WriteString("  [ -e \"$file\" ] || continue\n")
+	b.WriteString("  name=$(basename \"$file\")\n")
+	fmt.Fprintf(&b, "  mkdir -p '%s'/\"${name%%%%%s*}\"\n", cfg.Path, keySeparator)
+	fmt.Fprintf(&b, "  cp \"$file\" '%s'/\"${name%%%%%s*}\"/\"${name#*%s}\"\n", cfg.Path, keySeparator, keySeparator)
+	b.WriteString("done\n")
+	fmt.Fprintf(&b, "git add -A -- '%s'\n", cfg.Path)
+	b.WriteString("if git diff --cached --quiet; then\n")
+	b.WriteString("  echo 'No changes to export'\n")
+	b.WriteString("  exit 0\n")
+	b.WriteString("fi\n")
+	b.WriteString("git -c user.name='kibaship' -c user.email='operator@kibaship.com' commit -m \"Export platform resources ($EXPORT_ID)\"\n")
+	fmt.Fprintf(&b, "git push origin 'HEAD:%s'\n", cfg.Branch)
+	return b.String()
+}
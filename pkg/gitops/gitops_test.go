@@ -0,0 +1,67 @@
+package gitops
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func object(kind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "platform.operator.kibaship.com/v1alpha1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"spec":       spec,
+		"status":     map[string]interface{}{"phase": "Ready"},
+	}}
+}
+
+func TestSnapshot(t *testing.T) {
+	g := NewWithT(t)
+
+	snapshot, err := Snapshot([]*unstructured.Unstructured{
+		object("Project", "project-1", map[string]interface{}{"description": "Billing"}),
+		object("ApplicationDomain", "domain-1", map[string]interface{}{"domain": "app.example.com"}),
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(snapshot).To(HaveKey("projects_project-1.yaml"))
+	g.Expect(snapshot).To(HaveKey("applicationdomains_domain-1.yaml"))
+	g.Expect(snapshot["projects_project-1.yaml"]).To(ContainSubstring("description: Billing"))
+	g.Expect(snapshot["projects_project-1.yaml"]).NotTo(ContainSubstring("status"))
+
+	_, err = Snapshot([]*unstructured.Unstructured{
+		object("Project", "project-1", nil),
+		object("Project", "project-1", nil),
+	})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestHash(t *testing.T) {
+	g := NewWithT(t)
+
+	a := map[string]string{"projects_a.yaml": "a", "projects_b.yaml": "b"}
+	b := map[string]string{"projects_b.yaml": "b", "projects_a.yaml": "a"}
+	g.Expect(Hash(a)).To(Equal(Hash(b)))
+	g.Expect(Hash(a)).To(HaveLen(10))
+
+	b["projects_b.yaml"] = "changed"
+	g.Expect(Hash(a)).NotTo(Equal(Hash(b)))
+}
+
+func TestScript(t *testing.T) {
+	g := NewWithT(t)
+
+	script := Script(config.GitOpsConfig{
+		RepositoryURL: "git@github.com:acme/platform.git",
+		Branch:        "main",
+		Path:          "clusters/production",
+	})
+	g.Expect(script).To(ContainSubstring("git clone --depth 1 --branch 'main' 'git@github.com:acme/platform.git' /tmp/repo"))
+	g.Expect(script).To(ContainSubstring("rm -rf 'clusters/production'"))
+	g.Expect(script).To(ContainSubstring(`mkdir -p 'clusters/production'/"${name%%_*}"`))
+	g.Expect(script).To(ContainSubstring(`cp "$file" 'clusters/production'/"${name%%_*}"/"${name#*_}"`))
+	g.Expect(script).To(ContainSubstring("git push origin 'HEAD:main'"))
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/services"
+)
+
+// AdminHandler serves the cluster-wide listings of platform operators
+type AdminHandler struct {
+	adminService *services.AdminService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(adminService *services.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// ListApplications handles GET /v1/admin/applications
+// @Summary Search applications of all projects
+// @Description List the applications of every project, newest first, to find problem workloads without iterating projects. Served from the API server cache, so it may lag the cluster by a moment.
+// @Tags admin
+// @Produce json
+// @Param query query string false "Only return applications whose UUID, slug, name or namespace contains this text, ignoring case"
+// @Param phase query string false "Only return applications in this phase, such as Failed"
+// @Param limit query int false "Maximum number of applications to return (default 50, max 500)"
+// @Param offset query int false "Number of matching applications to skip"
+// @Success 200 {object} models.AdminApplicationListResponse "Matching applications"
+// @Failure 400 {object} models.ValidationErrors "Invalid query parameters"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/admin/applications [get]
+func (h *AdminHandler) ListApplications(c *gin.Context) {
+	req, ok := bindAdminListRequest(c)
+	if !ok {
+		return
+	}
+
+	applications, err := h.adminService.ListApplications(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to list applications: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, applications)
+}
+
+// ListDeployments handles GET /v1/admin/deployments
+// @Summary Search deployments of all projects
+// @Description List the deployments of every project, newest first, such as the failed ones with phase=Failed. Served from the API server cache, so it may lag the cluster by a moment.
+// @Tags admin
+// @Produce json
+// @Param query query string false "Only return deployments whose UUID, slug, application, namespace, commit or branch contains this text, ignoring case"
+// @Param phase query string false "Only return deployments in this phase, such as Failed"
+// @Param limit query int false "Maximum number of deployments to return (default 50, max 500)"
+// @Param offset query int false "Number of matching deployments to skip"
+// @Success 200 {object} models.AdminDeploymentListResponse "Matching deployments"
+// @Failure 400 {object} models.ValidationErrors "Invalid query parameters"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/admin/deployments [get]
+func (h *AdminHandler) ListDeployments(c *gin.Context) {
+	req, ok := bindAdminListRequest(c)
+	if !ok {
+		return
+	}
+
+	deployments, err := h.adminService.ListDeployments(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to list deployments: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, deployments)
+}
+
+// bindAdminListRequest parses and validates the query parameters of an admin listing, writing
+// the error response when they are invalid
+func bindAdminListRequest(c *gin.Context) (*models.AdminListRequest, bool) {
+	var req models.AdminListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid query parameters: " + err.Error(),
+		})
+		return nil, false
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return nil, false
+	}
+
+	return &req, true
+}
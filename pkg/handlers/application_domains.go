@@ -17,12 +17,16 @@ limitations under the License.
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/kibamail/kibaship/api/v1alpha1"
 	"github.com/kibamail/kibaship/pkg/models"
 	"github.com/kibamail/kibaship/pkg/services"
+	"github.com/kibamail/kibaship/pkg/uptime"
 )
 
 // ApplicationDomainHandler handles application domain-related HTTP requests
@@ -101,6 +105,67 @@ func (h *ApplicationDomainHandler) CreateApplicationDomain(c *gin.Context) {
 	c.JSON(http.StatusCreated, applicationDomain.ToResponse())
 }
 
+// BulkCreateApplicationDomains handles POST /v1/applications/:uuid/domains/bulk
+// @Summary Import several application domains
+// @Description Create several domains of an application sharing their port and TLS settings. Every domain is checked for syntax, repeats and use by another application domain before any is created. Atomic imports create every domain or none, otherwise the valid domains are created. The response reports the outcome of each domain.
+// @Tags application-domains
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application UUID or slug (8-character identifier)"
+// @Param domains body models.ApplicationDomainBulkCreateRequest true "Domains to import and their shared settings"
+// @Success 201 {object} models.ApplicationDomainBulkCreateResponse "Every domain was created"
+// @Success 207 {object} models.ApplicationDomainBulkCreateResponse "Some domains were created, see the results of the others"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 409 {object} models.ApplicationDomainBulkCreateResponse "No domain was created, see the results"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/domains/bulk [post]
+func (h *ApplicationDomainHandler) BulkCreateApplicationDomains(c *gin.Context) {
+	applicationSlug := c.Param("uuid")
+
+	var req models.ApplicationDomainBulkCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	response, err := h.applicationDomainService.BulkCreateApplicationDomains(c.Request.Context(), applicationSlug, &req)
+	if err != nil {
+		if err.Error() == "failed to get application: application with slug "+applicationSlug+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with slug '" + applicationSlug + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to import application domains: " + err.Error(),
+		})
+		return
+	}
+
+	switch {
+	case response.Failed == 0:
+		c.JSON(http.StatusCreated, response)
+	case response.Created > 0:
+		c.JSON(http.StatusMultiStatus, response)
+	default:
+		c.JSON(http.StatusConflict, response)
+	}
+}
+
 // GetApplicationDomain handles GET /v1/domains/:uuid
 // @Summary Get application domain by UUID
 // @Description Retrieve an application domain by its unique UUID identifier
@@ -185,3 +250,670 @@ func (h *ApplicationDomainHandler) DeleteApplicationDomain(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// UpdateUptimeCheck handles PUT /v1/domains/:uuid/uptime-check
+// @Summary Configure the uptime check of an application domain
+// @Description Enable or replace the periodic HTTP availability check of an application domain. Alerts are sent through webhooks when the domain goes down and recovers.
+// @Tags application-domains
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application domain UUID"
+// @Param uptimeCheck body models.UptimeCheck true "Uptime check configuration"
+// @Success 200 {object} models.ApplicationDomainResponse "Uptime check configured successfully"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/uptime-check [put]
+func (h *ApplicationDomainHandler) UpdateUptimeCheck(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.UptimeCheck
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	applicationDomain, err := h.applicationDomainService.UpdateUptimeCheck(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application domain with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to configure uptime check: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, applicationDomain.ToResponse())
+}
+
+// DeleteUptimeCheck handles DELETE /v1/domains/:uuid/uptime-check
+// @Summary Disable the uptime check of an application domain
+// @Description Stop probing an application domain and clear its uptime status
+// @Tags application-domains
+// @Param uuid path string true "Application domain UUID"
+// @Success 204 "Uptime check disabled successfully"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/uptime-check [delete]
+func (h *ApplicationDomainHandler) DeleteUptimeCheck(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	err := h.applicationDomainService.DeleteUptimeCheck(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application domain with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to disable uptime check: " + err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UpdateDomainRoutes handles PUT /v1/domains/:uuid/routes
+// @Summary Route path prefixes of an application domain to other applications
+// @Description Replace the path routes of an application domain, e.g. /api to a backend application while the application of the domain keeps serving every other path. Routes may only target web applications of the same project. The longest matching prefix wins and requests keep their full path.
+// @Tags application-domains
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application domain UUID"
+// @Param routes body models.DomainRoutesRequest true "Path routes of the domain"
+// @Success 200 {object} models.ApplicationDomainResponse "Path routes updated successfully"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data or routes to applications that cannot be routed to"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/routes [put]
+func (h *ApplicationDomainHandler) UpdateDomainRoutes(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.DomainRoutesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	applicationDomain, err := h.applicationDomainService.UpdateDomainRoutes(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application domain with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrInvalidDomainRoute) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to update domain routes: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, applicationDomain.ToResponse())
+}
+
+// DeleteDomainRoutes handles DELETE /v1/domains/:uuid/routes
+// @Summary Remove the path routes of an application domain
+// @Description Remove every path route of an application domain so its application serves all paths again
+// @Tags application-domains
+// @Param uuid path string true "Application domain UUID"
+// @Success 204 "Path routes removed successfully"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/routes [delete]
+func (h *ApplicationDomainHandler) DeleteDomainRoutes(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	err := h.applicationDomainService.DeleteDomainRoutes(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application domain with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to remove domain routes: " + err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UpdateDomainHeaders handles PUT /v1/domains/:uuid/headers
+// @Summary Configure the response headers of an application domain
+// @Description Replace the security headers (HSTS, CSP, X-Frame-Options) and CORS policy added to every response of an application domain by the ingress. Omitted sections are removed. The gateway ingress provider allows a single CORS origin and ingress-nginx must allow configuration snippets for security headers.
+// @Tags application-domains
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application domain UUID"
+// @Param headers body models.DomainHeadersRequest true "Security headers and CORS policy"
+// @Success 200 {object} models.ApplicationDomainResponse "Response headers configured successfully"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/headers [put]
+func (h *ApplicationDomainHandler) UpdateDomainHeaders(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.DomainHeadersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	applicationDomain, err := h.applicationDomainService.UpdateDomainHeaders(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application domain with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to configure response headers: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, applicationDomain.ToResponse())
+}
+
+// DeleteDomainHeaders handles DELETE /v1/domains/:uuid/headers
+// @Summary Remove the response headers of an application domain
+// @Description Remove the security headers and CORS policy of an application domain
+// @Tags application-domains
+// @Param uuid path string true "Application domain UUID"
+// @Success 204 "Response headers removed successfully"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/headers [delete]
+func (h *ApplicationDomainHandler) DeleteDomainHeaders(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	err := h.applicationDomainService.DeleteDomainHeaders(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application domain with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to remove response headers: " + err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UpdateDomainIngressAnnotations handles PUT /v1/domains/:uuid/ingress-annotations
+// @Summary Configure the ingress annotations of an application domain
+// @Description Replace the annotations added to the Ingress of an application domain, such as nginx.ingress.kubernetes.io/proxy-body-size or traefik.ingress.kubernetes.io/service.sticky.cookie. Only nginx.ingress.kubernetes.io/ and traefik.ingress.kubernetes.io/ annotations are allowed and snippets are rejected. Annotations the platform sets take precedence, and the gateway ingress provider ignores them.
+// @Tags application-domains
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application domain UUID"
+// @Param annotations body models.DomainIngressAnnotationsRequest true "Ingress annotations"
+// @Success 200 {object} models.ApplicationDomainResponse "Ingress annotations configured successfully"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/ingress-annotations [put]
+func (h *ApplicationDomainHandler) UpdateDomainIngressAnnotations(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.DomainIngressAnnotationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	applicationDomain, err := h.applicationDomainService.UpdateDomainIngressAnnotations(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application domain with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to configure ingress annotations: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, applicationDomain.ToResponse())
+}
+
+// DeleteDomainIngressAnnotations handles DELETE /v1/domains/:uuid/ingress-annotations
+// @Summary Remove the ingress annotations of an application domain
+// @Description Remove the annotations added to the Ingress of an application domain
+// @Tags application-domains
+// @Param uuid path string true "Application domain UUID"
+// @Success 204 "Ingress annotations removed successfully"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/ingress-annotations [delete]
+func (h *ApplicationDomainHandler) DeleteDomainIngressAnnotations(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	err := h.applicationDomainService.DeleteDomainIngressAnnotations(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application domain with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to remove ingress annotations: " + err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UpdateDomainBackendProtocol handles PUT /v1/domains/:uuid/backend-protocol
+// @Summary Configure the backend protocol of an application domain
+// @Description Set the protocol the application speaks behind an application domain: http1, grpc for gRPC over cleartext HTTP/2 (h2c), or websocket for long lived connections. Ingress-nginx switches the backend protocol and raises its proxy timeouts, Traefik and Gateway API read the protocol from the application Service, so the domains of an application must agree on it. Domains without a backend protocol follow the other domains of their application.
+// @Tags application-domains
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application domain UUID"
+// @Param backendProtocol body models.DomainBackendProtocolRequest true "Backend protocol"
+// @Success 200 {object} models.ApplicationDomainResponse "Backend protocol configured successfully"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 409 {object} auth.ErrorResponse "The other domains of the application use another backend protocol"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/backend-protocol [put]
+func (h *ApplicationDomainHandler) UpdateDomainBackendProtocol(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.DomainBackendProtocolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	applicationDomain, err := h.applicationDomainService.UpdateDomainBackendProtocol(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application domain with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrBackendProtocolConflict) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to configure backend protocol: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, applicationDomain.ToResponse())
+}
+
+// DeleteDomainBackendProtocol handles DELETE /v1/domains/:uuid/backend-protocol
+// @Summary Remove the backend protocol of an application domain
+// @Description Remove the backend protocol of an application domain, it follows the other domains of its application again
+// @Tags application-domains
+// @Param uuid path string true "Application domain UUID"
+// @Success 204 "Backend protocol removed successfully"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/backend-protocol [delete]
+func (h *ApplicationDomainHandler) DeleteDomainBackendProtocol(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	err := h.applicationDomainService.DeleteDomainBackendProtocol(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application domain with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to remove backend protocol: " + err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UpdateDomainProxySettings handles PUT /v1/domains/:uuid/proxy-settings
+// @Summary Tune the request body size limit and proxy timeouts of an application domain
+// @Description Replace the request body size limit (1Ki to 5Gi) and the proxy read and send timeouts (whole seconds, 1s to 1h) of an application domain, settings left out fall back to the ingress defaults. Ingress-nginx applies all three, Gateway API routes use the longest timeout as their request timeout, Traefik ignores them.
+// @Tags application-domains
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application domain UUID"
+// @Param proxySettings body models.DomainProxySettings true "Proxy settings"
+// @Success 200 {object} models.ApplicationDomainResponse "Proxy settings updated successfully"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/proxy-settings [put]
+func (h *ApplicationDomainHandler) UpdateDomainProxySettings(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.DomainProxySettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	applicationDomain, err := h.applicationDomainService.UpdateDomainProxySettings(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application domain with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to update proxy settings: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, applicationDomain.ToResponse())
+}
+
+// DeleteDomainProxySettings handles DELETE /v1/domains/:uuid/proxy-settings
+// @Summary Remove the proxy settings of an application domain
+// @Description Restore the ingress defaults for the request body size limit and proxy timeouts of an application domain
+// @Tags application-domains
+// @Param uuid path string true "Application domain UUID"
+// @Success 204 "Proxy settings removed successfully"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/proxy-settings [delete]
+func (h *ApplicationDomainHandler) DeleteDomainProxySettings(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	err := h.applicationDomainService.DeleteDomainProxySettings(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application domain with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to remove proxy settings: " + err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UploadDomainCertificate handles POST /v1/domains/:uuid/certificate
+// @Summary Upload the TLS certificate of a custom domain
+// @Description Serve an existing certificate (e.g. an EV certificate) for a custom domain instead of one issued through ACME. The certificate chain and private key must form a pair valid for the domain right now. Uploading again replaces the certificate after validating the new one, the previous one keeps being served when it is rejected. The domain fails once the certificate expires, certificateExpiresAt tells when to replace it.
+// @Tags application-domains
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application domain UUID"
+// @Param certificate body models.DomainCertificateRequest true "PEM certificate chain and private key"
+// @Success 200 {object} models.ApplicationDomainResponse "Certificate uploaded successfully"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data or a certificate that is not valid for the domain"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/certificate [post]
+func (h *ApplicationDomainHandler) UploadDomainCertificate(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.DomainCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	applicationDomain, err := h.applicationDomainService.UploadDomainCertificate(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application domain with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrInvalidDomainCertificate) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to upload domain certificate: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, applicationDomain.ToResponse())
+}
+
+// DeleteDomainCertificate handles DELETE /v1/domains/:uuid/certificate
+// @Summary Remove the uploaded TLS certificate of a custom domain
+// @Description Remove the uploaded certificate of a custom domain, a certificate is issued through ACME for it again
+// @Tags application-domains
+// @Param uuid path string true "Application domain UUID"
+// @Success 204 "Certificate removed successfully"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/certificate [delete]
+func (h *ApplicationDomainHandler) DeleteDomainCertificate(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	err := h.applicationDomainService.DeleteDomainCertificate(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application domain with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to remove domain certificate: " + err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetApplicationDomainBadge handles GET /v1/domains/:uuid/badge.svg
+// @Summary Get the uptime status badge of an application domain
+// @Description Render an SVG badge with the current uptime status of an application domain, for embedding in READMEs. This endpoint is public.
+// @Tags application-domains
+// @Produce image/svg+xml
+// @Param uuid path string true "Application domain UUID"
+// @Success 200 {string} string "SVG status badge"
+// @Failure 404 {string} string "SVG badge for an unknown domain"
+// @Router /v1/domains/{uuid}/badge.svg [get]
+func (h *ApplicationDomainHandler) GetApplicationDomainBadge(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	// Badges are embedded as images so failures are rendered rather than returned as JSON
+	c.Header("Cache-Control", "no-cache, max-age=0")
+
+	status, err := h.applicationDomainService.GetUptimeStatus(c.Request.Context(), uuid)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if err.Error() == "application domain with UUID "+uuid+" not found" {
+			code = http.StatusNotFound
+		}
+		c.Data(code, "image/svg+xml", uptime.Badge("uptime", "unknown", uptime.ColorUnknown))
+		return
+	}
+
+	message, color := "not configured", uptime.ColorUnknown
+	if status != nil {
+		switch status.State {
+		case string(v1alpha1.UptimeStateUp):
+			message, color = fmt.Sprintf("up %.1f%%", status.UptimePercentage), uptime.ColorUp
+		case string(v1alpha1.UptimeStateDown):
+			message, color = "down", uptime.ColorDown
+		default:
+			message = "unknown"
+		}
+	}
+
+	c.Data(http.StatusOK, "image/svg+xml", uptime.Badge("uptime", message, color))
+}
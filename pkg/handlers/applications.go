@@ -17,7 +17,11 @@ limitations under the License.
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -91,6 +95,14 @@ func (h *ApplicationHandler) CreateApplication(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, services.ErrInvalidApplicationDependency) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Internal Server Error",
 			"message": "Failed to create application: " + err.Error(),
@@ -195,6 +207,14 @@ func (h *ApplicationHandler) UpdateApplication(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, services.ErrInvalidApplicationDependency) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Internal Server Error",
 			"message": "Failed to update application: " + err.Error(),
@@ -347,12 +367,13 @@ func (h *ApplicationHandler) GetApplicationsByEnvironment(c *gin.Context) {
 
 // UpdateApplicationEnv handles PATCH /v1/applications/:uuid/env
 // @Summary Update environment variables for an application
-// @Description Update environment variables for a GitRepository application by merging new variables with existing ones
+// @Description Update environment variables for a GitRepository application by merging new variables with existing ones. Every change is recorded as a new env version the application can be rolled back to. Values may reference other platform values with ${env:NAME}, ${app:<slug>.url}, ${app:<slug>.host}, ${app:<slug>.port} and ${domain:primary}, resolved when the application is deployed; $${ is a literal ${. References to unknown values or cycles are rejected.
 // @Tags applications
 // @Accept json
 // @Produce json
 // @Param uuid path string true "Application UUID or slug"
 // @Param variables body models.ApplicationEnvUpdateRequest true "Environment variables to set/update"
+// @Param changedBy query string false "Who changes the env vars, recorded in the env version history" default(api)
 // @Success 200 {string} string "Environment variables updated successfully"
 // @Failure 400 {object} auth.ErrorResponse "Invalid request data"
 // @Failure 401 {object} auth.ErrorResponse "Authentication required"
@@ -388,8 +409,17 @@ func (h *ApplicationHandler) UpdateApplicationEnv(c *gin.Context) {
 		return
 	}
 
-	err := h.applicationService.UpdateApplicationEnv(c.Request.Context(), uuid, &req)
+	changedBy := c.DefaultQuery("changedBy", "api")
+
+	err := h.applicationService.UpdateApplicationEnv(c.Request.Context(), uuid, &req, changedBy)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidEnvTemplate) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
 		if err.Error() == "application with UUID "+uuid+" not found" {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error":   "Not Found",
@@ -409,3 +439,1227 @@ func (h *ApplicationHandler) UpdateApplicationEnv(c *gin.Context) {
 		"message": "Environment variables updated successfully",
 	})
 }
+
+// GetApplicationEnvVersions handles GET /v1/applications/:uuid/env/versions
+// @Summary List application env versions
+// @Description List the recorded versions of the env vars of an application, newest first. Versions list the variable names and a hash of the values, never the values themselves. The latest 50 versions are kept.
+// @Tags applications
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Success 200 {object} models.ApplicationEnvVersionsResponse "Application env versions"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/env/versions [get]
+func (h *ApplicationHandler) GetApplicationEnvVersions(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	versions, err := h.applicationService.GetApplicationEnvVersions(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to retrieve env versions: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+// RollbackApplicationEnv handles POST /v1/applications/:uuid/env/versions/:version/rollback
+// @Summary Roll back application env vars
+// @Description Restore the env vars of an application to a recorded version, recorded as a new version. A deployment running the image of the current deployment is created to apply them, no build runs. Freeze windows apply to the deployment unless overrideFreeze is set.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Param version path int true "Env version to restore"
+// @Param changedBy query string false "Who rolls back the env vars, recorded in the env version history" default(api)
+// @Param rollback body models.ApplicationEnvRollbackRequest false "Rollback options"
+// @Success 200 {object} models.ApplicationEnvRollbackResponse "Env vars restored"
+// @Failure 400 {object} auth.ErrorResponse "Invalid version"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application or env version not found"
+// @Failure 409 {object} auth.ErrorResponse "The application already uses the env vars of the version"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/env/versions/{version}/rollback [post]
+func (h *ApplicationHandler) RollbackApplicationEnv(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Version must be a positive number",
+		})
+		return
+	}
+
+	// The body is optional, an empty one rolls back with the default options
+	var req models.ApplicationEnvRollbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	changedBy := c.DefaultQuery("changedBy", "api")
+
+	response, err := h.applicationService.RollbackApplicationEnv(c.Request.Context(), uuid, version, changedBy, &req)
+	if err != nil {
+		errMsg := err.Error()
+		switch {
+		case errors.Is(err, services.ErrEnvRollbackNotAllowed):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": errMsg,
+			})
+		case errMsg == "application with UUID "+uuid+" not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+		case errMsg == fmt.Sprintf("env version %d of application %s not found", version, uuid):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": errMsg,
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to roll back env vars: " + errMsg,
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetReplicaSchedule handles GET /v1/applications/:uuid/replica-schedule
+// @Summary Get application replica schedule
+// @Description Get the replica schedule of an application and the number of replicas it runs now
+// @Tags applications
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Success 200 {object} models.ReplicaScheduleResponse "Application replica schedule"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/replica-schedule [get]
+func (h *ApplicationHandler) GetReplicaSchedule(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	schedule, err := h.applicationService.GetReplicaSchedule(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get replica schedule: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// UpdateReplicaSchedule handles PUT /v1/applications/:uuid/replica-schedule
+// @Summary Replace application replica schedule
+// @Description Replace the replica schedule of an application. Its deployments run the replicas of the active window, or the default replicas outside any window.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Param schedule body models.ReplicaScheduleUpdateRequest true "Replica schedule"
+// @Success 200 {object} models.ReplicaScheduleResponse "Updated replica schedule"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/replica-schedule [put]
+func (h *ApplicationHandler) UpdateReplicaSchedule(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.ReplicaScheduleUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	schedule, err := h.applicationService.UpdateReplicaSchedule(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to update replica schedule: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteReplicaSchedule handles DELETE /v1/applications/:uuid/replica-schedule
+// @Summary Remove application replica schedule
+// @Description Remove the replica schedule of an application. Its deployments keep their current replica count.
+// @Tags applications
+// @Param uuid path string true "Application UUID"
+// @Success 204 "Replica schedule removed successfully"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/replica-schedule [delete]
+func (h *ApplicationHandler) DeleteReplicaSchedule(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	if err := h.applicationService.DeleteReplicaSchedule(c.Request.Context(), uuid); err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to remove replica schedule: " + err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetAutoscaling handles GET /v1/applications/:uuid/autoscaling
+// @Summary Get application autoscaling
+// @Description Get the autoscaling configuration of an application and the replicas it runs and is scaled to
+// @Tags applications
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Success 200 {object} models.AutoscalingResponse "Application autoscaling"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/autoscaling [get]
+func (h *ApplicationHandler) GetAutoscaling(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	autoscaling, err := h.applicationService.GetAutoscaling(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get autoscaling: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, autoscaling)
+}
+
+// UpdateAutoscaling handles PUT /v1/applications/:uuid/autoscaling
+// @Summary Configure application autoscaling
+// @Description Scale a GitRepository, DockerImage or ImageFromRegistry application between minReplicas and maxReplicas through KEDA, which must be installed in the cluster. Triggers scale on the CPU utilization percent of the replicas (cpu), the requests per second routed by ingress-nginx or Traefik, read from the Prometheus of the traffic metrics (requestsPerSecond), the length of a list of a Valkey application (valkeyListLength) or the number a query against a MySQL application returns (mysqlQuery). Each replica handles target of the metric, the trigger asking for the most replicas wins. minReplicas 0 scales the application to zero while no trigger is active. Autoscaling cannot be combined with a replica schedule.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Param autoscaling body models.AutoscalingUpdateRequest true "Autoscaling"
+// @Success 200 {object} models.AutoscalingResponse "Updated autoscaling"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 409 {object} auth.ErrorResponse "The application is not scaled by the operator or has a replica schedule"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/autoscaling [put]
+func (h *ApplicationHandler) UpdateAutoscaling(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.AutoscalingUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	autoscaling, err := h.applicationService.UpdateAutoscaling(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrAutoscalingNotSupported) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to update autoscaling: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, autoscaling)
+}
+
+// DeleteAutoscaling handles DELETE /v1/applications/:uuid/autoscaling
+// @Summary Remove application autoscaling
+// @Description Remove the autoscaling configuration of an application. Its deployments keep their current replica count.
+// @Tags applications
+// @Param uuid path string true "Application UUID"
+// @Success 204 "Autoscaling removed successfully"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/autoscaling [delete]
+func (h *ApplicationHandler) DeleteAutoscaling(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	if err := h.applicationService.DeleteAutoscaling(c.Request.Context(), uuid); err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to remove autoscaling: " + err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetLogAlertRules handles GET /v1/applications/:uuid/log-alerts
+// @Summary Get application log alerts
+// @Description Get the log alert rules of an application and whether each is firing
+// @Tags applications
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Success 200 {object} models.LogAlertRulesResponse "Application log alerts"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/log-alerts [get]
+func (h *ApplicationHandler) GetLogAlertRules(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	rules, err := h.applicationService.GetLogAlertRules(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get log alerts: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// UpdateLogAlertRules handles PUT /v1/applications/:uuid/log-alerts
+// @Summary Replace application log alerts
+// @Description Replace the log alert rules of an application, an empty list removes them. A rule fires a webhook when the runtime logs match its pattern at least thresholdPerMinute times in a minute, and another when it resolves. Requires the operator logging pipeline (logging.provider) to be enabled.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Param rules body models.LogAlertRulesUpdateRequest true "Log alert rules"
+// @Success 200 {object} models.LogAlertRulesResponse "Updated log alerts"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/log-alerts [put]
+func (h *ApplicationHandler) UpdateLogAlertRules(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.LogAlertRulesUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	rules, err := h.applicationService.UpdateLogAlertRules(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to update log alerts: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// GetPromotionHooks handles GET /v1/applications/:uuid/hooks
+// @Summary Get application promotion hooks
+// @Description Get the commands run before and after the deployments of an application are promoted
+// @Tags applications
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Success 200 {object} models.PromotionHooksResponse "Application promotion hooks"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/hooks [get]
+func (h *ApplicationHandler) GetPromotionHooks(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	hooks, err := h.applicationService.GetPromotionHooks(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get promotion hooks: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, hooks)
+}
+
+// UpdatePromotionHooks handles PUT /v1/applications/:uuid/hooks
+// @Summary Replace application promotion hooks
+// @Description Replace the promotion hooks of an application, a missing hook is removed. The prePromote hook runs as a Job with the image and environment of a deployment before it receives traffic, the postPromote hook once it does. A failed hook with the Abort failure policy keeps the deployment from being promoted, or reverts the application to the previous deployment. Promotions requested through the API do not run hooks.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Param hooks body models.PromotionHooksUpdateRequest true "Promotion hooks"
+// @Success 200 {object} models.PromotionHooksResponse "Updated promotion hooks"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/hooks [put]
+func (h *ApplicationHandler) UpdatePromotionHooks(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.PromotionHooksUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	hooks, err := h.applicationService.UpdatePromotionHooks(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to update promotion hooks: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, hooks)
+}
+
+// GetBuildEnv handles GET /v1/applications/:uuid/build-env
+// @Summary Get application build environment variables
+// @Description List the names of the environment variables only the builds of an application see. Values are never returned.
+// @Tags applications
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Success 200 {object} models.BuildEnvResponse "Build environment variable names"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/build-env [get]
+func (h *ApplicationHandler) GetBuildEnv(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	buildEnv, err := h.applicationService.GetBuildEnv(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get build env: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, buildEnv)
+}
+
+// UpdateBuildEnv handles PUT /v1/applications/:uuid/build-env
+// @Summary Replace application build environment variables
+// @Description Replace the environment variables only the builds of a GitRepository application see, such as NPM_TOKEN or NODE_OPTIONS. They are passed to the builder as secrets and never set in the running container. Deployments created afterwards build with them. Nixpacks builds do not support them.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Param buildEnv body models.BuildEnvUpdateRequest true "Build environment variables"
+// @Success 200 {object} models.BuildEnvResponse "Updated build environment variable names"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 409 {object} auth.ErrorResponse "The application builds do not support build environment variables"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/build-env [put]
+func (h *ApplicationHandler) UpdateBuildEnv(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.BuildEnvUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	buildEnv, err := h.applicationService.UpdateBuildEnv(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrBuildEnvNotSupported) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to update build env: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, buildEnv)
+}
+
+// GetSessionAffinity handles GET /v1/applications/:uuid/session-affinity
+// @Summary Get application session affinity
+// @Description Get the cookie pinning each client of an application to one of its replicas
+// @Tags applications
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Success 200 {object} models.SessionAffinityResponse "Application session affinity"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/session-affinity [get]
+func (h *ApplicationHandler) GetSessionAffinity(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	affinity, err := h.applicationService.GetSessionAffinity(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get session affinity: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, affinity)
+}
+
+// UpdateSessionAffinity handles PUT /v1/applications/:uuid/session-affinity
+// @Summary Configure application session affinity
+// @Description Pin each client of a GitRepository, DockerImage or ImageFromRegistry application to one of its replicas with a cookie, for applications keeping sessions in memory. The cookie is named kibaship-affinity unless cookieName is set and lasts ttlSeconds, or until the browser is closed when it is 0. Ingress-nginx pins the clients on every path of the domains of the application, Traefik on the application Service and Gateway API implementations through HTTPRoute session persistence, which they may not support.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Param sessionAffinity body models.SessionAffinityUpdateRequest true "Session affinity"
+// @Success 200 {object} models.SessionAffinityResponse "Updated session affinity"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 409 {object} auth.ErrorResponse "The application is not served through the ingress"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/session-affinity [put]
+func (h *ApplicationHandler) UpdateSessionAffinity(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.SessionAffinityUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	affinity, err := h.applicationService.UpdateSessionAffinity(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrSessionAffinityNotSupported) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to update session affinity: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, affinity)
+}
+
+// DeleteSessionAffinity handles DELETE /v1/applications/:uuid/session-affinity
+// @Summary Remove application session affinity
+// @Description Remove the session affinity of an application, its clients are spread over its replicas again
+// @Tags applications
+// @Param uuid path string true "Application UUID"
+// @Success 204 "Session affinity removed successfully"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/session-affinity [delete]
+func (h *ApplicationHandler) DeleteSessionAffinity(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	if err := h.applicationService.DeleteSessionAffinity(c.Request.Context(), uuid); err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to remove session affinity: " + err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetApplicationLogHistory handles GET /v1/applications/:uuid/logs/history
+// @Summary Get application log history
+// @Description Search the persisted logs of an application, including logs of pods that no longer exist. Requires the operator logging pipeline (logging.provider) to be enabled.
+// @Tags applications
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Param start query string false "Start of the searched period (RFC3339)"
+// @Param end query string false "End of the searched period (RFC3339), defaults to now"
+// @Param since query string false "Search this far back from end, such as 24h (default 1h, cannot be combined with start)"
+// @Param filter query string false "Only return lines containing this text"
+// @Param limit query int false "Maximum number of lines to return (default 100, max 5000)"
+// @Param direction query string false "backward returns the newest lines first (default), forward the oldest"
+// @Success 200 {object} models.ApplicationLogHistoryResponse "Application log lines"
+// @Failure 400 {object} models.ValidationErrors "Invalid query parameters"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Failure 503 {object} auth.ErrorResponse "Persistent logging is not configured"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/logs/history [get]
+func (h *ApplicationHandler) GetApplicationLogHistory(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.ApplicationLogHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	logs, err := h.applicationService.GetApplicationLogHistory(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrLogStoreNotConfigured) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Service Unavailable",
+				"message": "Historical logs are not available: " + err.Error(),
+			})
+			return
+		}
+
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get application logs: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
+// GetApplicationTraffic handles GET /v1/applications/:uuid/traffic
+// @Summary Get application traffic
+// @Description Return the request rate, 4xx and 5xx rates and p95 latency of an application over the last hour and the last day, read from the ingress controller metrics in Prometheus. Requires metrics.prometheus_url and the nginx or traefik ingress provider.
+// @Tags applications
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Success 200 {object} models.ApplicationTrafficResponse "Application traffic"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Failure 503 {object} auth.ErrorResponse "Traffic metrics are not available"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/traffic [get]
+func (h *ApplicationHandler) GetApplicationTraffic(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	traffic, err := h.applicationService.GetApplicationTraffic(c.Request.Context(), uuid)
+	if err != nil {
+		if errors.Is(err, services.ErrMetricsNotConfigured) || errors.Is(err, services.ErrTrafficMetricsUnsupported) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Service Unavailable",
+				"message": "Traffic metrics are not available: " + err.Error(),
+			})
+			return
+		}
+
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get application traffic: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, traffic)
+}
+
+// GetApplicationConnection handles GET /v1/applications/:uuid/connection
+// @Summary Get database connection info
+// @Description Return the host, port, database and connection strings of a MySQL, Postgres or Valkey application, inside the cluster and through its default domain, with a reference to the Secret holding its credentials. With reveal=true the credential pair is included; credentials can be revealed once, afterwards read them from the Secret.
+// @Tags applications
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Param reveal query bool false "Include the credential pair, possible once per application"
+// @Success 200 {object} models.ApplicationConnectionResponse "Connection info"
+// @Failure 400 {object} auth.ErrorResponse "Application is not a database"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 409 {object} auth.ErrorResponse "Credentials already revealed or not provisioned yet"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/connection [get]
+func (h *ApplicationHandler) GetApplicationConnection(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	reveal := c.Query("reveal") == "true"
+
+	connection, err := h.applicationService.GetApplicationConnection(c.Request.Context(), uuid, reveal)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotDatabaseApplication):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+		case errors.Is(err, services.ErrCredentialsAlreadyRevealed), errors.Is(err, services.ErrCredentialsUnavailable):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": err.Error(),
+			})
+		case err.Error() == "application with UUID "+uuid+" not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to get application connection: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, connection)
+}
+
+// GetDatabaseAccess handles GET /v1/applications/:uuid/databases
+// @Summary Get database users and schemas
+// @Description Return the additional databases and users of a MySQL or Postgres application and the state of the Job applying them.
+// @Tags applications
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Success 200 {object} models.DatabaseAccessResponse "Databases and users"
+// @Failure 400 {object} auth.ErrorResponse "Application is not a MySQL or Postgres database"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/databases [get]
+func (h *ApplicationHandler) GetDatabaseAccess(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	access, err := h.applicationService.GetDatabaseAccess(c.Request.Context(), uuid)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDatabaseAccessUnsupported):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+		case err.Error() == "application with UUID "+uuid+" not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to get database access: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, access)
+}
+
+// CreateDatabase handles POST /v1/applications/:uuid/databases
+// @Summary Create a database
+// @Description Add a database to a MySQL application, or a schema to the database of a Postgres application. The operator creates it with a Job; the phase of the response reports its progress.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Param database body models.DatabaseCreateRequest true "Database to create"
+// @Success 202 {object} models.DatabaseAccessResponse "Database accepted"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 409 {object} auth.ErrorResponse "Database already exists"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/databases [post]
+func (h *ApplicationHandler) CreateDatabase(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.DatabaseCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	access, err := h.applicationService.CreateDatabase(c.Request.Context(), uuid, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDatabaseAccessUnsupported), errors.Is(err, services.ErrInvalidDatabaseAccess):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+		case errors.Is(err, services.ErrDatabaseAccessExists):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": err.Error(),
+			})
+		case err.Error() == "application with UUID "+uuid+" not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to create database: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, access)
+}
+
+// CreateDatabaseUser handles POST /v1/applications/:uuid/users
+// @Summary Create a database user
+// @Description Add a user to a MySQL or Postgres application with access to some of its databases, for example one user per service of a microservice stack. The password is generated and stored in the Secret named in the response. The operator creates the user with a Job; the phase of the response reports its progress.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Param user body models.DatabaseUserCreateRequest true "User to create"
+// @Success 202 {object} models.DatabaseAccessResponse "User accepted"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 409 {object} auth.ErrorResponse "User already exists"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/users [post]
+func (h *ApplicationHandler) CreateDatabaseUser(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.DatabaseUserCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	access, err := h.applicationService.CreateDatabaseUser(c.Request.Context(), uuid, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDatabaseAccessUnsupported), errors.Is(err, services.ErrInvalidDatabaseAccess):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+		case errors.Is(err, services.ErrDatabaseAccessExists):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": err.Error(),
+			})
+		case err.Error() == "application with UUID "+uuid+" not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to create database user: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, access)
+}
+
+// DeleteDatabaseUser handles DELETE /v1/applications/:uuid/users/:username
+// @Summary Delete a database user
+// @Description Remove a user from a MySQL or Postgres application. The operator drops the user and deletes its Secret; on Postgres the objects it owns are handed to the administrative user.
+// @Tags applications
+// @Param uuid path string true "Application UUID"
+// @Param username path string true "Username"
+// @Success 204 "Database user removed successfully"
+// @Failure 400 {object} auth.ErrorResponse "Application is not a MySQL or Postgres database"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application or user not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/users/{username} [delete]
+func (h *ApplicationHandler) DeleteDatabaseUser(c *gin.Context) {
+	uuid := c.Param("uuid")
+	username := c.Param("username")
+
+	if err := h.applicationService.DeleteDatabaseUser(c.Request.Context(), uuid, username); err != nil {
+		switch {
+		case errors.Is(err, services.ErrDatabaseAccessUnsupported):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+		case errors.Is(err, services.ErrDatabaseUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Database user '" + username + "' was not found",
+			})
+		case err.Error() == "application with UUID "+uuid+" not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to delete database user: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetServiceBindings handles GET /v1/applications/:uuid/service-bindings
+// @Summary Get application service bindings
+// @Description Get whether an application is a shared database and the shared databases it binds to
+// @Tags applications
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Success 200 {object} models.ServiceBindingsResponse "Service bindings"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/service-bindings [get]
+func (h *ApplicationHandler) GetServiceBindings(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	bindings, err := h.applicationService.GetServiceBindings(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get service bindings: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, bindings)
+}
+
+// UpdateServiceBindings handles PUT /v1/applications/:uuid/service-bindings
+// @Summary Replace application service bindings
+// @Description Share a MySQL or Postgres application with its environment, and bind an application to shared databases of its environment. Each binding gets a database user of its own and injects <PREFIX>_URL, <PREFIX>_HOST, <PREFIX>_PORT, <PREFIX>_DATABASE, <PREFIX>_USERNAME and <PREFIX>_PASSWORD into the application containers. Deployments created afterwards receive them.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application UUID"
+// @Param bindings body models.ServiceBindingsUpdateRequest true "Service bindings"
+// @Success 200 {object} models.ServiceBindingsResponse "Updated service bindings"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 409 {object} auth.ErrorResponse "A bound application is not a shared database of the environment, or the database is still bound"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/service-bindings [put]
+func (h *ApplicationHandler) UpdateServiceBindings(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.ServiceBindingsUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	bindings, err := h.applicationService.UpdateServiceBindings(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "application with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrInvalidServiceBinding) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to update service bindings: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, bindings)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/services"
+)
+
+// ApplyHandler converges project trees to declarative manifests
+type ApplyHandler struct {
+	applyService *services.ApplyService
+}
+
+// NewApplyHandler creates a new apply handler
+func NewApplyHandler(applyService *services.ApplyService) *ApplyHandler {
+	return &ApplyHandler{
+		applyService: applyService,
+	}
+}
+
+// Apply handles POST /v1/apply
+// @Summary Apply a declarative manifest
+// @Description Diff a kibaship.yaml manifest describing a project, its environments, applications, domains and environment variables against the cluster, then create, update and delete resources until they match. Resources are matched by name, environments, applications and custom domains the manifest leaves out are deleted. The body is JSON, or YAML when sent as application/yaml. With dryRun=true the change plan is returned without changing anything.
+// @Tags apply
+// @Accept json
+// @Accept application/yaml
+// @Produce json
+// @Param manifest body models.ApplyRequest true "Declarative manifest"
+// @Param dryRun query bool false "Return the change plan without applying it"
+// @Success 200 {object} models.ApplyResponse "Change plan"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in the manifest"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 409 {object} auth.ErrorResponse "Manifest conflicts with existing resources"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/apply [post]
+func (h *ApplyHandler) Apply(c *gin.Context) {
+	var req models.ApplyRequest
+	switch c.ContentType() {
+	case "application/yaml", "application/x-yaml", "text/yaml":
+		body, err := io.ReadAll(c.Request.Body)
+		if err == nil {
+			err = yaml.UnmarshalStrict(body, &req)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "Invalid YAML format: " + err.Error(),
+			})
+			return
+		}
+	default:
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "Invalid JSON format: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	response, err := h.applyService.Apply(c.Request.Context(), &req, c.Query("dryRun") == "true")
+	if err != nil {
+		status, title := http.StatusInternalServerError, "Internal Server Error"
+		if errors.Is(err, services.ErrApplyConflict) {
+			status, title = http.StatusConflict, "Conflict"
+		}
+		c.JSON(status, gin.H{
+			"error":   title,
+			"message": "Failed to apply manifest: " + err.Error(),
+			"changes": response.Changes,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/services"
+)
+
+const (
+	// buildLogWriteWait bounds the write of a message to a client
+	buildLogWriteWait = 10 * time.Second
+	// buildLogPongWait is how long a client may stay silent, it answers the pings in between
+	buildLogPongWait   = 60 * time.Second
+	buildLogPingPeriod = buildLogPongWait * 9 / 10
+)
+
+// buildLogUpgrader upgrades build log requests to WebSockets. Clients authenticate with the API
+// key header rather than cookies, so cross-origin upgrades are allowed.
+var buildLogUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 32 * 1024,
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+// StreamBuildLogs handles GET /v1/deployments/:uuid/build-logs/ws
+// @Summary Tail deployment build logs over a WebSocket
+// @Description Upgrade to a WebSocket streaming the step logs of the pipeline building a deployment, task after task, until the build is done. Lines are sent in batches (models.BuildLogMessage) of up to 500 lines or 64KiB, at least every 250ms. Every batch has a sequence number the client acknowledges by sending {"ack": seq}; the server sends at most window batches ahead of the acknowledgments and stops reading the build logs while the window is full. With gzip, batches are gzipped JSON in binary messages. The last message has done set, with error when following the logs failed.
+// @Tags deployments
+// @Produce json
+// @Param uuid path string true "Deployment UUID"
+// @Param window query int false "Batches sent ahead of the acknowledgments (default 8, max 64)"
+// @Param gzip query bool false "Send batches gzipped in binary messages"
+// @Success 101 {object} models.BuildLogMessage "Switching to the WebSocket protocol"
+// @Failure 400 {object} models.ValidationErrors "Invalid query parameters"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Deployment or pipeline run not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Failure 503 {object} auth.ErrorResponse "Build logs are not configured"
+// @Security BearerAuth
+// @Router /v1/deployments/{uuid}/build-logs/ws [get]
+func (h *DeploymentHandler) StreamBuildLogs(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var options models.BuildLogStreamOptions
+	if err := c.ShouldBindQuery(&options); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+	if validationErr := options.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	stream, err := h.deploymentService.OpenBuildLogStream(c.Request.Context(), uuid)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrBuildLogsNotConfigured):
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Service Unavailable",
+				"message": "Build logs are not available: " + err.Error(),
+			})
+		case errors.Is(err, services.ErrPipelineRunNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Deployment with UUID '" + uuid + "' has no pipeline run",
+			})
+		case err.Error() == "deployment with UUID "+uuid+" not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Deployment with UUID '" + uuid + "' was not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to open build logs: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	// The upgrader answers failed upgrades itself
+	conn, err := buildLogUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := serveBuildLogs(c.Request.Context(), conn, stream, options); err != nil && !errors.Is(err, context.Canceled) {
+		log.Printf("Build log stream of deployment %s ended: %v", uuid, err)
+	}
+}
+
+// serveBuildLogs sends the build logs of stream in acknowledged batches until the build is done,
+// the client goes away or stops answering pings
+func serveBuildLogs(ctx context.Context, conn *websocket.Conn, stream *services.BuildLogStream, options models.BuildLogStreamOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Read acknowledgments until the client closes the connection
+	acks := make(chan int64, options.WindowSize())
+	_ = conn.SetReadDeadline(time.Now().Add(buildLogPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(buildLogPongWait))
+	})
+	go func() {
+		defer cancel()
+		for {
+			var ack models.BuildLogAck
+			if err := conn.ReadJSON(&ack); err != nil {
+				return
+			}
+			_ = conn.SetReadDeadline(time.Now().Add(buildLogPongWait))
+			select {
+			case acks <- ack.Seq:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Follow the logs, blocking while the batch and the window are full
+	lines := make(chan models.BuildLogLine, models.BuildLogBatchLines)
+	followErr := make(chan error, 1)
+	go func() {
+		followErr <- stream.Follow(ctx, func(line models.BuildLogLine) error {
+			select {
+			case lines <- line:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		close(lines)
+	}()
+
+	flush := time.NewTicker(models.BuildLogFlushInterval)
+	defer flush.Stop()
+	ping := time.NewTicker(buildLogPingPeriod)
+	defer ping.Stop()
+
+	window := models.NewBuildLogWindow(options.WindowSize())
+	var batcher models.BuildLogBatcher
+	following, flushDue := true, false
+	var streamErr string
+	for {
+		if !following && batcher.Empty() {
+			done := models.BuildLogMessage{Seq: window.Next(), Done: true, Error: streamErr}
+			if err := writeBuildLogMessage(conn, options, done); err != nil {
+				return err
+			}
+			return conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(buildLogWriteWait))
+		}
+
+		var in <-chan models.BuildLogLine
+		if following && !batcher.Full() {
+			in = lines
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case seq := <-acks:
+			window.Ack(seq)
+		case line, ok := <-in:
+			if ok {
+				batcher.Add(line)
+				break
+			}
+			following = false
+			if err := <-followErr; err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				streamErr = err.Error()
+			}
+		case <-flush.C:
+			flushDue = true
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(buildLogWriteWait)); err != nil {
+				return err
+			}
+		}
+
+		if !batcher.Empty() && !window.Full() && (flushDue || batcher.Full() || !following) {
+			message := models.BuildLogMessage{Seq: window.Next(), Lines: batcher.Take()}
+			if err := writeBuildLogMessage(conn, options, message); err != nil {
+				return err
+			}
+			flushDue = false
+		}
+	}
+}
+
+// writeBuildLogMessage sends message as JSON text, or gzipped JSON in a binary message
+func writeBuildLogMessage(conn *websocket.Conn, options models.BuildLogStreamOptions, message models.BuildLogMessage) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	messageType := websocket.TextMessage
+	if options.Gzip {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(payload); err != nil {
+			return err
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+		payload, messageType = buf.Bytes(), websocket.BinaryMessage
+	}
+
+	_ = conn.SetWriteDeadline(time.Now().Add(buildLogWriteWait))
+	return conn.WriteMessage(messageType, payload)
+}
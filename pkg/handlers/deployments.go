@@ -17,7 +17,12 @@ limitations under the License.
 package handlers
 
 import (
+	"errors"
+	"io"
+	"mime"
 	"net/http"
+	"path"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
@@ -39,16 +44,19 @@ func NewDeploymentHandler(deploymentService *services.DeploymentService) *Deploy
 
 // CreateDeployment handles POST /v1/applications/:uuid/deployments
 // @Summary Create a new deployment
-// @Description Create a new deployment for an application
+// @Description Create a new deployment for an application. With plan=true the Kubernetes resources the deployment would generate are returned without creating anything, so builds can be reviewed before they are triggered.
 // @Tags deployments
 // @Accept json
 // @Produce json
 // @Param uuid path string true "Application UUID or slug"
 // @Param deployment body models.DeploymentCreateRequest true "Deployment creation data"
+// @Param plan query bool false "Return the resources the deployment would generate without creating it"
 // @Success 201 {object} models.DeploymentResponse "Deployment created successfully"
+// @Success 200 {object} models.DeploymentPlan "Resources the deployment would generate"
 // @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
 // @Failure 401 {object} auth.ErrorResponse "Authentication required"
 // @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 409 {object} auth.ErrorResponse "Project registry quota exceeded"
 // @Failure 500 {object} auth.ErrorResponse "Internal server error"
 // @Security BearerAuth
 // @Router /v1/applications/{uuid}/deployments [post]
@@ -81,6 +89,28 @@ func (h *DeploymentHandler) CreateDeployment(c *gin.Context) {
 		return
 	}
 
+	if c.Query("plan") == "true" {
+		plan, err := h.deploymentService.PlanDeployment(c.Request.Context(), &req)
+		if err != nil {
+			if err.Error() == "failed to get application: application with UUID "+applicationUUID+" not found" {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error":   "Not Found",
+					"message": "Application with UUID '" + applicationUUID + "' was not found",
+				})
+				return
+			}
+
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to plan deployment: " + err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, plan)
+		return
+	}
+
 	deployment, err := h.deploymentService.CreateDeployment(c.Request.Context(), &req)
 	if err != nil {
 		if err.Error() == "failed to get application: application with UUID "+applicationUUID+" not found" {
@@ -91,6 +121,75 @@ func (h *DeploymentHandler) CreateDeployment(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, services.ErrRegistryQuotaExceeded) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to create deployment: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, deployment.ToResponse())
+}
+
+// CreateExternalDeployment handles POST /v1/applications/:uuid/deployments/external
+// @Summary Deploy an externally built image
+// @Description Notify the platform that an external CI system, such as a Tekton EventListener or a GitHub Actions workflow, already built and pushed the image of a commit. The deployment skips the build pipeline and rolls out the image pinned to its digest. Only GitRepository applications deploy external builds, and the cluster must be able to pull the image.
+// @Tags deployments
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Application UUID or slug"
+// @Param deployment body models.ExternalDeploymentCreateRequest true "Externally built image and commit"
+// @Success 201 {object} models.DeploymentResponse "Deployment created successfully"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 409 {object} auth.ErrorResponse "Application does not deploy external builds"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/deployments/external [post]
+func (h *DeploymentHandler) CreateExternalDeployment(c *gin.Context) {
+	applicationUUID := c.Param("uuid")
+
+	var req models.ExternalDeploymentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	deployment, err := h.deploymentService.CreateExternalDeployment(c.Request.Context(), applicationUUID, &req)
+	if err != nil {
+		if err.Error() == "failed to get application: application with UUID "+applicationUUID+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + applicationUUID + "' was not found",
+			})
+			return
+		}
+
+		if errors.Is(err, services.ErrExternalDeploymentNotAllowed) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": err.Error(),
+			})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Internal Server Error",
 			"message": "Failed to create deployment: " + err.Error(),
@@ -193,15 +292,61 @@ func (h *DeploymentHandler) GetDeploymentsByApplication(c *gin.Context) {
 	c.JSON(http.StatusOK, responses)
 }
 
+// GetReleasesByApplication handles GET /v1/applications/:uuid/releases
+// @Summary Get the releases of an application
+// @Description Retrieve the release history of an application: its promoted deployments, newest first, with their commit metadata, image digests and who promoted them and when. The current deployment is always included.
+// @Tags deployments
+// @Produce json
+// @Param uuid path string true "Application UUID or slug (8-character identifier)"
+// @Success 200 {array} models.ReleaseResponse "Release history"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/releases [get]
+func (h *DeploymentHandler) GetReleasesByApplication(c *gin.Context) {
+	applicationSlug := c.Param("uuid")
+
+	if applicationSlug == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Application slug is required",
+		})
+		return
+	}
+
+	releases, err := h.deploymentService.GetReleasesByApplication(c.Request.Context(), applicationSlug)
+	if err != nil {
+		if err.Error() == "failed to get application: application with UUID "+applicationSlug+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + applicationSlug + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to retrieve releases: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, releases)
+}
+
 // PromoteDeployment handles POST /v1/deployments/:uuid/promote
 // @Summary Promote a deployment
 // @Description Promote a deployment by updating the application's currentDeploymentRef to point to this deployment
 // @Tags deployments
 // @Produce json
 // @Param uuid path string true "Deployment UUID or slug"
+// @Param overrideFreeze query bool false "Promote even while an environment freeze window is active"
+// @Param promotedBy query string false "Who promotes the deployment, recorded in the release history" default(api)
 // @Success 200 {object} map[string]string "Deployment promoted successfully"
 // @Failure 401 {object} auth.ErrorResponse "Authentication required"
 // @Failure 404 {object} auth.ErrorResponse "Deployment not found"
+// @Failure 409 {object} auth.ErrorResponse "Promotion blocked by a freeze window"
 // @Failure 500 {object} auth.ErrorResponse "Internal server error"
 // @Security BearerAuth
 // @Router /v1/deployments/{uuid}/promote [post]
@@ -216,8 +361,19 @@ func (h *DeploymentHandler) PromoteDeployment(c *gin.Context) {
 		return
 	}
 
-	err := h.deploymentService.PromoteDeployment(c.Request.Context(), deploymentUUID)
+	overrideFreeze := c.Query("overrideFreeze") == "true"
+	promotedBy := c.DefaultQuery("promotedBy", "api")
+
+	err := h.deploymentService.PromoteDeployment(c.Request.Context(), deploymentUUID, overrideFreeze, promotedBy)
 	if err != nil {
+		if errors.Is(err, services.ErrFreezeWindowActive) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": err.Error(),
+			})
+			return
+		}
+
 		// Check if deployment not found (checking for substring to handle wrapped errors)
 		errMsg := err.Error()
 		if errMsg == "failed to get deployment: deployment with UUID "+deploymentUUID+" not found" ||
@@ -240,3 +396,277 @@ func (h *DeploymentHandler) PromoteDeployment(c *gin.Context) {
 		"message": "Deployment promoted successfully",
 	})
 }
+
+// PromoteDeploymentToEnvironment handles POST /v1/deployments/:uuid/promote-to/:environmentUuid
+// @Summary Promote a deployment to another environment
+// @Description Create a deployment in another environment of the project that runs the image already built for a succeeded deployment, skipping the build. The commit is carried over. The new deployment goes live once it is running unless the target environment requires approval, in which case it is released with the promote endpoint. Freeze windows of the target environment apply unless overrideFreeze is set.
+// @Tags deployments
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Deployment UUID"
+// @Param environmentUuid path string true "Target environment UUID"
+// @Param promotion body models.DeploymentPromoteToRequest false "Promotion options"
+// @Success 201 {object} models.DeploymentResponse "Deployment created in the target environment"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Deployment, environment or application not found"
+// @Failure 409 {object} auth.ErrorResponse "Deployment cannot be promoted to the environment"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/deployments/{uuid}/promote-to/{environmentUuid} [post]
+func (h *DeploymentHandler) PromoteDeploymentToEnvironment(c *gin.Context) {
+	deploymentUUID := c.Param("uuid")
+	environmentUUID := c.Param("environmentUuid")
+
+	// The body is optional, an empty one promotes with the default options
+	var req models.DeploymentPromoteToRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	deployment, err := h.deploymentService.PromoteDeploymentToEnvironment(c.Request.Context(), deploymentUUID, environmentUUID, &req)
+	if err != nil {
+		errMsg := err.Error()
+		switch {
+		case errors.Is(err, services.ErrPromotionNotAllowed):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": errMsg,
+			})
+		case errMsg == "deployment with UUID "+deploymentUUID+" not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Deployment with UUID '" + deploymentUUID + "' was not found",
+			})
+		case errMsg == "environment with UUID "+environmentUUID+" not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Environment with UUID '" + environmentUUID + "' was not found",
+			})
+		case req.ApplicationUUID != "" && errMsg == "application with UUID "+req.ApplicationUUID+" not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Application with UUID '" + req.ApplicationUUID + "' was not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to promote deployment: " + errMsg,
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, deployment.ToResponse())
+}
+
+// SyncDeploymentEnv handles POST /v1/deployments/:uuid/sync-env
+// @Summary Resync deployment env vars
+// @Description Copy the current env vars of the application into a deployment and restart its pods with them. Deployments otherwise keep the env vars their application had when they were created, envOutOfSync reports when these changed since.
+// @Tags deployments
+// @Produce json
+// @Param uuid path string true "Deployment UUID"
+// @Success 200 {object} models.DeploymentResponse "Env vars synced, the pods restart with them"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Deployment not found"
+// @Failure 409 {object} auth.ErrorResponse "Deployment has no env vars to sync"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/deployments/{uuid}/sync-env [post]
+func (h *DeploymentHandler) SyncDeploymentEnv(c *gin.Context) {
+	deploymentUUID := c.Param("uuid")
+
+	deployment, err := h.deploymentService.SyncDeploymentEnv(c.Request.Context(), deploymentUUID)
+	if err != nil {
+		errMsg := err.Error()
+		switch {
+		case errors.Is(err, services.ErrDeploymentEnvNotSyncable):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": errMsg,
+			})
+		case errMsg == "deployment with UUID "+deploymentUUID+" not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Deployment with UUID '" + deploymentUUID + "' was not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to sync deployment env vars: " + errMsg,
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, deployment.ToResponse())
+}
+
+// ListDeploymentArtifacts handles GET /v1/deployments/:uuid/artifacts
+// @Summary List deployment artifacts
+// @Description List the files, such as JUnit reports and coverage, published by the custom pipeline steps of a deployment. Requires the operator artifact service (artifacts.provider) to be enabled.
+// @Tags deployments
+// @Produce json
+// @Param uuid path string true "Deployment UUID"
+// @Success 200 {object} models.DeploymentArtifactsResponse "Deployment artifacts"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Deployment not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Failure 503 {object} auth.ErrorResponse "Artifact collection is not configured"
+// @Security BearerAuth
+// @Router /v1/deployments/{uuid}/artifacts [get]
+func (h *DeploymentHandler) ListDeploymentArtifacts(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	artifacts, err := h.deploymentService.ListDeploymentArtifacts(c.Request.Context(), uuid)
+	if err != nil {
+		h.handleArtifactError(c, uuid, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, artifacts)
+}
+
+// GetDeploymentPipeline handles GET /v1/deployments/:uuid/pipeline
+// @Summary Get deployment pipeline progress
+// @Description Get the task graph of the pipeline building a deployment with the status, start and end times and duration of every task, ready to render as a pipeline progress view
+// @Tags deployments
+// @Produce json
+// @Param uuid path string true "Deployment UUID"
+// @Success 200 {object} models.DeploymentPipelineResponse "Deployment pipeline"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Deployment or pipeline run not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/deployments/{uuid}/pipeline [get]
+func (h *DeploymentHandler) GetDeploymentPipeline(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	pipeline, err := h.deploymentService.GetDeploymentPipeline(c.Request.Context(), uuid)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPipelineRunNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Deployment with UUID '" + uuid + "' has no pipeline run",
+			})
+		case err.Error() == "deployment with UUID "+uuid+" not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Deployment with UUID '" + uuid + "' was not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to retrieve deployment pipeline: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, pipeline)
+}
+
+// GetDeploymentHooks handles GET /v1/deployments/:uuid/hooks
+// @Summary Get deployment promotion hooks
+// @Description Get the promotion hooks run for a deployment with their phase and logs. Logs are only included while the hook pods exist.
+// @Tags deployments
+// @Produce json
+// @Param uuid path string true "Deployment UUID"
+// @Success 200 {object} models.DeploymentHooksResponse "Deployment promotion hooks"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Deployment not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/deployments/{uuid}/hooks [get]
+func (h *DeploymentHandler) GetDeploymentHooks(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	hooks, err := h.deploymentService.GetDeploymentHooks(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "deployment with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Deployment with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to retrieve deployment hooks: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, hooks)
+}
+
+// DownloadDeploymentArtifact handles GET /v1/deployments/:uuid/artifacts/*path
+// @Summary Download a deployment artifact
+// @Description Download a file published by the custom pipeline steps of a deployment
+// @Tags deployments
+// @Produce octet-stream
+// @Param uuid path string true "Deployment UUID"
+// @Param path path string true "Artifact path as listed by the artifacts endpoint"
+// @Success 200 {file} file "Artifact content"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Deployment or artifact not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Failure 503 {object} auth.ErrorResponse "Artifact collection is not configured"
+// @Security BearerAuth
+// @Router /v1/deployments/{uuid}/artifacts/{path} [get]
+func (h *DeploymentHandler) DownloadDeploymentArtifact(c *gin.Context) {
+	uuid := c.Param("uuid")
+	artifactPath := strings.TrimPrefix(c.Param("path"), "/")
+
+	artifact, err := h.deploymentService.GetDeploymentArtifact(c.Request.Context(), uuid, artifactPath)
+	if err != nil {
+		h.handleArtifactError(c, uuid, err)
+		return
+	}
+	defer func() { _ = artifact.Body.Close() }()
+
+	contentType := artifact.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.DataFromReader(http.StatusOK, artifact.Size, contentType, artifact.Body, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(artifactPath)}),
+	})
+}
+
+// handleArtifactError maps artifact errors to HTTP responses
+func (h *DeploymentHandler) handleArtifactError(c *gin.Context, uuid string, err error) {
+	switch {
+	case errors.Is(err, services.ErrArtifactStoreNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Deployment artifacts are not available: " + err.Error(),
+		})
+	case errors.Is(err, services.ErrArtifactNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": "Artifact '" + strings.TrimPrefix(c.Param("path"), "/") + "' was not found",
+		})
+	case err.Error() == "deployment with UUID "+uuid+" not found":
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": "Deployment with UUID '" + uuid + "' was not found",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to retrieve deployment artifacts: " + err.Error(),
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -247,6 +248,14 @@ func (h *EnvironmentHandler) UpdateEnvironment(c *gin.Context) {
 
 	environment, err := h.environmentService.UpdateEnvironment(c.Request.Context(), slug, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrIdlePolicyProduction) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+
 		if err.Error() == "environment with UUID "+slug+" not found" {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error":   "Not Found",
@@ -306,3 +315,90 @@ func (h *EnvironmentHandler) DeleteEnvironment(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// GetFreezeWindows handles GET /v1/environments/:uuid/freeze-windows
+// @Summary Get environment freeze windows
+// @Description Get the freeze windows of an environment and the window blocking promotions now, if any
+// @Tags environments
+// @Produce json
+// @Param uuid path string true "Environment UUID"
+// @Success 200 {object} models.FreezeWindowsResponse "Environment freeze windows"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Environment not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/environments/{uuid}/freeze-windows [get]
+func (h *EnvironmentHandler) GetFreezeWindows(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	windows, err := h.environmentService.GetFreezeWindows(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "environment with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Environment with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get freeze windows: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, windows)
+}
+
+// UpdateFreezeWindows handles PUT /v1/environments/:uuid/freeze-windows
+// @Summary Replace environment freeze windows
+// @Description Replace the freeze windows of an environment. Promotions into the environment are blocked while a window is active.
+// @Tags environments
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Environment UUID"
+// @Param windows body models.FreezeWindowsUpdateRequest true "Freeze windows"
+// @Success 200 {object} models.FreezeWindowsResponse "Updated freeze windows"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Environment not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/environments/{uuid}/freeze-windows [put]
+func (h *EnvironmentHandler) UpdateFreezeWindows(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.FreezeWindowsUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	windows, err := h.environmentService.UpdateFreezeWindows(c.Request.Context(), uuid, &req)
+	if err != nil {
+		if err.Error() == "environment with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Environment with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to update freeze windows: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, windows)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/pullrequest"
+	"github.com/kibamail/kibaship/pkg/services"
+)
+
+// maxWebhookBodySize bounds the git webhook payloads read into memory
+const maxWebhookBodySize = 10 << 20
+
+// GitWebhookHandler handles webhook deliveries of git providers
+type GitWebhookHandler struct {
+	previewService *services.PreviewEnvironmentService
+	secret         []byte
+}
+
+// NewGitWebhookHandler creates a new git webhook handler verifying deliveries with secret
+func NewGitWebhookHandler(previewService *services.PreviewEnvironmentService, secret string) *GitWebhookHandler {
+	return &GitWebhookHandler{
+		previewService: previewService,
+		secret:         []byte(secret),
+	}
+}
+
+// ReceiveGitHubWebhook handles POST /v1/webhooks/github
+// @Summary Receive a GitHub webhook
+// @Description Receive pull_request events of GitHub repositories. Opening a pull request creates a preview environment in every project with applications tracking its base branch, with clones of those applications deployed from the head branch. New commits are redeployed and closing the pull request deletes the preview environments. Pull requests from forks are ignored. Deliveries are authenticated with the X-Hub-Signature-256 HMAC of the webhook secret instead of the API key.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-GitHub-Event header string true "GitHub event type"
+// @Param X-Hub-Signature-256 header string true "HMAC SHA-256 signature of the payload"
+// @Success 200 {object} models.PullRequestEventResponse "Preview environments updated"
+// @Success 204 "Event ignored"
+// @Failure 400 {object} auth.ErrorResponse "Invalid payload"
+// @Failure 401 {object} auth.ErrorResponse "Invalid signature"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Router /v1/webhooks/github [post]
+func (h *GitWebhookHandler) ReceiveGitHubWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Failed to read webhook payload: " + err.Error(),
+		})
+		return
+	}
+
+	if !pullrequest.VerifyGitHubSignature(h.secret, body, c.GetHeader(pullrequest.GitHubSignatureHeader)) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": "Invalid webhook signature",
+		})
+		return
+	}
+
+	evt, err := pullrequest.ParseGitHubEvent(c.GetHeader(pullrequest.GitHubEventHeader), body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+	if evt == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	environmentUUIDs, err := h.previewService.HandlePullRequest(c.Request.Context(), evt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to update preview environments: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PullRequestEventResponse{
+		Action:           string(evt.Action),
+		EnvironmentUUIDs: environmentUUIDs,
+	})
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kibamail/kibaship/pkg/services"
+)
+
+// ManifestHandler serves the Kubernetes manifests behind API resources
+type ManifestHandler struct {
+	manifestService *services.ManifestService
+}
+
+// NewManifestHandler creates a new manifest handler
+func NewManifestHandler(manifestService *services.ManifestService) *ManifestHandler {
+	return &ManifestHandler{
+		manifestService: manifestService,
+	}
+}
+
+// GetProjectManifest handles GET /v1/projects/:uuid/manifest
+// @Summary Get project manifest
+// @Description Return the Project custom resource as YAML, without server-assigned metadata.
+// @Tags manifests
+// @Produce application/yaml
+// @Param uuid path string true "Project UUID"
+// @Success 200 {string} string "Project manifest"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Project not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/projects/{uuid}/manifest [get]
+func (h *ManifestHandler) GetProjectManifest(c *gin.Context) {
+	data, err := h.manifestService.GetProjectManifest(c.Request.Context(), c.Param("uuid"))
+	writeManifest(c, data, err)
+}
+
+// GetEnvironmentManifest handles GET /v1/environments/:uuid/manifest
+// @Summary Get environment manifest
+// @Description Return the Environment custom resource as YAML, without server-assigned metadata.
+// @Tags manifests
+// @Produce application/yaml
+// @Param uuid path string true "Environment UUID"
+// @Success 200 {string} string "Environment manifest"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Environment not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/environments/{uuid}/manifest [get]
+func (h *ManifestHandler) GetEnvironmentManifest(c *gin.Context) {
+	data, err := h.manifestService.GetEnvironmentManifest(c.Request.Context(), c.Param("uuid"))
+	writeManifest(c, data, err)
+}
+
+// GetApplicationManifest handles GET /v1/applications/:uuid/manifest
+// @Summary Get application manifest
+// @Description Return the Application custom resource as YAML, without server-assigned metadata. With children=true the Kubernetes Deployments and Services running the application and the Ingresses or HTTPRoutes of its domains follow it as further documents. Literal environment variable values are redacted.
+// @Tags manifests
+// @Produce application/yaml
+// @Param uuid path string true "Application UUID"
+// @Param children query bool false "Include the Kubernetes objects generated for the application"
+// @Success 200 {string} string "Application manifest"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/applications/{uuid}/manifest [get]
+func (h *ManifestHandler) GetApplicationManifest(c *gin.Context) {
+	children := c.Query("children") == "true"
+
+	data, err := h.manifestService.GetApplicationManifest(c.Request.Context(), c.Param("uuid"), children)
+	writeManifest(c, data, err)
+}
+
+// GetDeploymentManifest handles GET /v1/deployments/:uuid/manifest
+// @Summary Get deployment manifest
+// @Description Return the Deployment custom resource as YAML, without server-assigned metadata. With children=true the Kubernetes Deployment rolling out its image follows it as a further document. Literal environment variable values are redacted.
+// @Tags manifests
+// @Produce application/yaml
+// @Param uuid path string true "Deployment UUID"
+// @Param children query bool false "Include the Kubernetes objects generated for the deployment"
+// @Success 200 {string} string "Deployment manifest"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Deployment not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/deployments/{uuid}/manifest [get]
+func (h *ManifestHandler) GetDeploymentManifest(c *gin.Context) {
+	children := c.Query("children") == "true"
+
+	data, err := h.manifestService.GetDeploymentManifest(c.Request.Context(), c.Param("uuid"), children)
+	writeManifest(c, data, err)
+}
+
+// GetApplicationDomainManifest handles GET /v1/domains/:uuid/manifest
+// @Summary Get application domain manifest
+// @Description Return the ApplicationDomain custom resource as YAML, without server-assigned metadata. With children=true the Ingress or HTTPRoutes routing the domain follow it as further documents.
+// @Tags manifests
+// @Produce application/yaml
+// @Param uuid path string true "Application domain UUID"
+// @Param children query bool false "Include the Kubernetes objects generated for the domain"
+// @Success 200 {string} string "Application domain manifest"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Application domain not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/domains/{uuid}/manifest [get]
+func (h *ManifestHandler) GetApplicationDomainManifest(c *gin.Context) {
+	children := c.Query("children") == "true"
+
+	data, err := h.manifestService.GetApplicationDomainManifest(c.Request.Context(), c.Param("uuid"), children)
+	writeManifest(c, data, err)
+}
+
+// writeManifest responds with a rendered manifest, or the error rendering it
+func writeManifest(c *gin.Context, data []byte, err error) {
+	if err != nil {
+		if errors.Is(err, services.ErrManifestNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get manifest: " + err.Error(),
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/yaml", data)
+}
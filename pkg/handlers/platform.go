@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kibamail/kibaship/pkg/services"
+)
+
+// PlatformHandler serves the state of the platform installation
+type PlatformHandler struct {
+	platformService *services.PlatformService
+}
+
+// NewPlatformHandler creates a new platform handler
+func NewPlatformHandler(platformService *services.PlatformService) *PlatformHandler {
+	return &PlatformHandler{
+		platformService: platformService,
+	}
+}
+
+// GetBootstrapStatus handles GET /v1/platform/bootstrap
+// @Summary Get the platform bootstrap status
+// @Description Report the steps the operator runs to install the platform, such as provisioning ingress and certificates, with the state of each, the error of a failed step and when it is retried. Failed steps are retried by the operator with an increasing delay, and right away when the operator configuration changes.
+// @Tags platform
+// @Produce json
+// @Success 200 {object} models.PlatformBootstrapResponse "Bootstrap status"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "The operator has not recorded a bootstrap yet"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/platform/bootstrap [get]
+func (h *PlatformHandler) GetBootstrapStatus(c *gin.Context) {
+	status, err := h.platformService.GetBootstrapStatus(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, services.ErrBootstrapStatusNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "The operator has not recorded a bootstrap yet",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get bootstrap status: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
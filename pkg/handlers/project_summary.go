@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kibamail/kibaship/pkg/services"
+)
+
+// ProjectSummaryHandler serves the dashboard summary of projects
+type ProjectSummaryHandler struct {
+	summaryService *services.ProjectSummaryService
+}
+
+// NewProjectSummaryHandler creates a new project summary handler
+func NewProjectSummaryHandler(summaryService *services.ProjectSummaryService) *ProjectSummaryHandler {
+	return &ProjectSummaryHandler{
+		summaryService: summaryService,
+	}
+}
+
+// GetProjectSummary handles GET /v1/projects/:uuid/summary
+// @Summary Get project summary
+// @Description Return the state of a project in one call: its applications per phase, the incidents needing attention (failed latest deployments, expired or failed certificates, crash looping pods) and its 10 most recent deployments, newest first. Served from the API server cache, so it may lag the cluster by a moment.
+// @Tags projects
+// @Produce json
+// @Param uuid path string true "Project UUID"
+// @Success 200 {object} models.ProjectSummaryResponse "Project summary"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Project not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/projects/{uuid}/summary [get]
+func (h *ProjectSummaryHandler) GetProjectSummary(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	summary, err := h.summaryService.GetProjectSummary(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "project with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Project with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to retrieve project summary: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -127,6 +128,76 @@ func (h *ProjectHandler) GetProject(c *gin.Context) {
 	c.JSON(http.StatusOK, project.ToResponse())
 }
 
+// GetProjectCost handles GET /v1/projects/:uuid/cost
+// @Summary Get project cost estimate
+// @Description Retrieve the estimated cost of the CPU, memory and storage requested by a project's workloads, per application and per day for the last 30 days. Estimates are recalculated hourly from the rates in the operator ConfigMap.
+// @Tags projects
+// @Produce json
+// @Param uuid path string true "Project UUID"
+// @Success 200 {object} models.ProjectCostResponse "Project cost estimate"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Project not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/projects/{uuid}/cost [get]
+func (h *ProjectHandler) GetProjectCost(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	estimate, err := h.projectService.GetProjectCost(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "project with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Project with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to retrieve project cost: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, estimate)
+}
+
+// GetProjectUsage handles GET /v1/projects/:uuid/usage
+// @Summary Get project registry usage
+// @Description Retrieve the registry storage used by the images of a project's applications and its registry quota. Usage is measured by inspecting the registry every few hours, blobs shared by several images are counted once. New builds are refused while the usage is above the quota.
+// @Tags projects
+// @Produce json
+// @Param uuid path string true "Project UUID"
+// @Success 200 {object} models.ProjectUsageResponse "Project registry usage"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Project not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/projects/{uuid}/usage [get]
+func (h *ProjectHandler) GetProjectUsage(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	usage, err := h.projectService.GetProjectUsage(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "project with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Project with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to retrieve project usage: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
 // DeleteProject handles DELETE /v1/projects/:uuid
 // @Summary Delete project by UUID
 // @Description Delete a project by its unique UUID or slug identifier
@@ -229,3 +300,136 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 
 	c.JSON(http.StatusOK, project.ToResponse())
 }
+
+// ListRegistryRobots handles GET /v1/projects/:uuid/registry-robots
+// @Summary List project registry robot accounts
+// @Description List the robot accounts pulling the images of a project from the registry. Passwords are only returned when a robot account is created.
+// @Tags projects
+// @Produce json
+// @Param uuid path string true "Project UUID"
+// @Success 200 {array} models.RegistryRobotResponse "Registry robot accounts"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Project not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/projects/{uuid}/registry-robots [get]
+func (h *ProjectHandler) ListRegistryRobots(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	robots, err := h.projectService.ListRegistryRobots(c.Request.Context(), uuid)
+	if err != nil {
+		if err.Error() == "project with UUID "+uuid+" not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Project with UUID '" + uuid + "' was not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to list registry robot accounts: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, robots)
+}
+
+// CreateRegistryRobot handles POST /v1/projects/:uuid/registry-robots
+// @Summary Create a project registry robot account
+// @Description Create a long-lived robot account pulling the images of a project from the registry, for external clusters and developer machines. Robot accounts log in with the returned username and password and can only pull the repositories of the project. The password is only returned in this response.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Project UUID"
+// @Param robot body models.RegistryRobotCreateRequest true "Robot account to create"
+// @Success 201 {object} models.RegistryRobotCreateResponse "Robot account created"
+// @Failure 400 {object} models.ValidationErrors "Validation errors in request data"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Project not found"
+// @Failure 409 {object} auth.ErrorResponse "Robot account already exists"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/projects/{uuid}/registry-robots [post]
+func (h *ProjectHandler) CreateRegistryRobot(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	var req models.RegistryRobotCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid JSON format: " + err.Error(),
+		})
+		return
+	}
+
+	if validationErr := req.Validate(); validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	robot, err := h.projectService.CreateRegistryRobot(c.Request.Context(), uuid, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRegistryRobotExists):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Conflict",
+				"message": err.Error(),
+			})
+		case err.Error() == "project with UUID "+uuid+" not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Project with UUID '" + uuid + "' was not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to create registry robot account: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, robot)
+}
+
+// DeleteRegistryRobot handles DELETE /v1/projects/:uuid/registry-robots/:name
+// @Summary Delete a project registry robot account
+// @Description Delete a robot account of a project. New logins are refused within the credential cache TTL of the registry auth service, about five minutes, and tokens already issued expire within their lifetime.
+// @Tags projects
+// @Param uuid path string true "Project UUID"
+// @Param name path string true "Robot account name"
+// @Success 204 "Robot account deleted successfully"
+// @Failure 401 {object} auth.ErrorResponse "Authentication required"
+// @Failure 404 {object} auth.ErrorResponse "Project or robot account not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /v1/projects/{uuid}/registry-robots/{name} [delete]
+func (h *ProjectHandler) DeleteRegistryRobot(c *gin.Context) {
+	uuid := c.Param("uuid")
+	name := c.Param("name")
+
+	if err := h.projectService.DeleteRegistryRobot(c.Request.Context(), uuid, name); err != nil {
+		switch {
+		case errors.Is(err, services.ErrRegistryRobotNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Registry robot account '" + name + "' was not found",
+			})
+		case err.Error() == "project with UUID "+uuid+" not found":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not Found",
+				"message": "Project with UUID '" + uuid + "' was not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to delete registry robot account: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,173 @@
+// Package idle scales applications to zero when they receive no traffic and back up on demand.
+//
+// Applications in environments with an idle policy are served through the activator: their
+// public Service has no selector and its endpoints point at the activator pods, which proxy
+// requests to a private Service selecting the application pods. The activator records the time
+// of the last request on the private Service. Once it is older than the policy timeout the
+// operator scales the application's Deployments to zero, and the next request makes the
+// activator scale them back up and hold the request until a pod is ready.
+package idle
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	// AnnotationLastRequest records on the private Service when the activator last proxied a request
+	AnnotationLastRequest = "platform.kibaship.com/last-request-at"
+
+	// AnnotationIdleReplicas records on a Deployment scaled to zero how many replicas to restore on wake
+	AnnotationIdleReplicas = "platform.kibaship.com/idle-replicas"
+
+	// ActivatorNamespace is the namespace the activator runs in
+	ActivatorNamespace = "kibaship"
+
+	// ActivatorServiceName is the Service selecting the activator pods
+	ActivatorServiceName = "kibaship-activator"
+
+	// ActivatorPort is the port the activator pods accept proxied traffic on
+	ActivatorPort = 8080
+)
+
+// PrivateServiceName returns the name of the Service selecting an application's pods
+// while its public Service points at the activator
+func PrivateServiceName(serviceName string) string {
+	return serviceName + "-private"
+}
+
+// EndpointSliceName returns the name of the EndpointSlice routing a public Service to the activator
+func EndpointSliceName(serviceName string) string {
+	return serviceName + "-activator"
+}
+
+// LastRequest returns the last request time recorded on the private Service
+func LastRequest(obj metav1.Object) (time.Time, bool) {
+	value, ok := obj.GetAnnotations()[AnnotationLastRequest]
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// SetLastRequest records t as the last request time on the private Service
+func SetLastRequest(obj metav1.Object, t time.Time) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationLastRequest] = t.UTC().Format(time.RFC3339)
+	obj.SetAnnotations(annotations)
+}
+
+// Deployments lists the Kubernetes Deployments running an application
+func Deployments(ctx context.Context, c client.Client, namespace, applicationUUID string) ([]appsv1.Deployment, error) {
+	var deployments appsv1.DeploymentList
+	if err := c.List(ctx, &deployments, client.InNamespace(namespace), client.MatchingLabels{
+		validation.LabelApplicationUUID: applicationUUID,
+		"app.kubernetes.io/component":   "application",
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	return deployments.Items, nil
+}
+
+// Sleeping reports whether the Deployments were scaled to zero by the idle policy
+func Sleeping(deployments []appsv1.Deployment) bool {
+	sleeping := false
+	for _, dep := range deployments {
+		if _, ok := dep.Annotations[AnnotationIdleReplicas]; ok {
+			sleeping = true
+			continue
+		}
+		if dep.Spec.Replicas == nil || *dep.Spec.Replicas > 0 {
+			return false
+		}
+	}
+	return sleeping
+}
+
+// Ready reports whether any of the Deployments has a ready pod
+func Ready(deployments []appsv1.Deployment) bool {
+	for _, dep := range deployments {
+		if dep.Status.ReadyReplicas > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Sleep scales an application's Deployments to zero, remembering their replicas
+func Sleep(ctx context.Context, c client.Client, namespace, applicationUUID string) error {
+	deployments, err := Deployments(ctx, c, namespace, applicationUUID)
+	if err != nil {
+		return err
+	}
+
+	for i := range deployments {
+		dep := &deployments[i]
+		if dep.Spec.Replicas != nil && *dep.Spec.Replicas == 0 {
+			continue
+		}
+		replicas := int32(1)
+		if dep.Spec.Replicas != nil {
+			replicas = *dep.Spec.Replicas
+		}
+
+		patch := client.MergeFrom(dep.DeepCopy())
+		if dep.Annotations == nil {
+			dep.Annotations = map[string]string{}
+		}
+		dep.Annotations[AnnotationIdleReplicas] = strconv.Itoa(int(replicas))
+		zero := int32(0)
+		dep.Spec.Replicas = &zero
+		if err := c.Patch(ctx, dep, patch); err != nil {
+			return fmt.Errorf("failed to scale deployment %s to zero: %w", dep.Name, err)
+		}
+	}
+	return nil
+}
+
+// Wake restores the replicas of an application's Deployments scaled to zero by Sleep.
+// It reports whether any Deployment was scaled up.
+func Wake(ctx context.Context, c client.Client, namespace, applicationUUID string) (bool, error) {
+	deployments, err := Deployments(ctx, c, namespace, applicationUUID)
+	if err != nil {
+		return false, err
+	}
+
+	woken := false
+	for i := range deployments {
+		dep := &deployments[i]
+		value, ok := dep.Annotations[AnnotationIdleReplicas]
+		if !ok {
+			continue
+		}
+		replicas, err := strconv.Atoi(value)
+		if err != nil || replicas < 1 {
+			replicas = 1
+		}
+
+		patch := client.MergeFrom(dep.DeepCopy())
+		delete(dep.Annotations, AnnotationIdleReplicas)
+		restored := int32(replicas)
+		dep.Spec.Replicas = &restored
+		if err := c.Patch(ctx, dep, patch); err != nil {
+			return woken, fmt.Errorf("failed to wake deployment %s: %w", dep.Name, err)
+		}
+		woken = true
+	}
+	return woken, nil
+}
@@ -0,0 +1,106 @@
+package idle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func appDeployment(name, appUUID string, replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "project-ns",
+			Labels: map[string]string{
+				validation.LabelApplicationUUID: appUUID,
+				"app.kubernetes.io/component":   "application",
+			},
+		},
+		Spec: appsv1.DeploymentSpec{Replicas: ptr.To(replicas)},
+	}
+}
+
+func TestSleepAndWake(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		appDeployment("app-a", "app-1", 3),
+		appDeployment("app-other", "app-2", 1),
+	).Build()
+
+	g.Expect(Sleep(ctx, c, "project-ns", "app-1")).To(Succeed())
+
+	deployments, err := Deployments(ctx, c, "project-ns", "app-1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deployments).To(HaveLen(1))
+	g.Expect(*deployments[0].Spec.Replicas).To(BeZero())
+	g.Expect(deployments[0].Annotations).To(HaveKeyWithValue(AnnotationIdleReplicas, "3"))
+	g.Expect(Sleeping(deployments)).To(BeTrue())
+
+	// Other applications are left alone
+	var other appsv1.Deployment
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "project-ns", Name: "app-other"}, &other)).To(Succeed())
+	g.Expect(*other.Spec.Replicas).To(BeEquivalentTo(1))
+
+	woken, err := Wake(ctx, c, "project-ns", "app-1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(woken).To(BeTrue())
+
+	deployments, err = Deployments(ctx, c, "project-ns", "app-1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*deployments[0].Spec.Replicas).To(BeEquivalentTo(3))
+	g.Expect(deployments[0].Annotations).NotTo(HaveKey(AnnotationIdleReplicas))
+	g.Expect(Sleeping(deployments)).To(BeFalse())
+
+	// Waking an awake application does nothing
+	woken, err = Wake(ctx, c, "project-ns", "app-1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(woken).To(BeFalse())
+}
+
+func TestSleeping(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Sleeping(nil)).To(BeFalse())
+
+	// Scaled to zero by hand rather than by the idle policy
+	manual := appDeployment("app-a", "app-1", 0)
+	g.Expect(Sleeping([]appsv1.Deployment{*manual})).To(BeFalse())
+
+	asleep := appDeployment("app-b", "app-1", 0)
+	asleep.Annotations = map[string]string{AnnotationIdleReplicas: "1"}
+	g.Expect(Sleeping([]appsv1.Deployment{*manual, *asleep})).To(BeTrue())
+	g.Expect(Sleeping([]appsv1.Deployment{*asleep, *appDeployment("app-c", "app-1", 1)})).To(BeFalse())
+}
+
+func TestLastRequest(t *testing.T) {
+	g := NewWithT(t)
+
+	var service corev1.Service
+	_, ok := LastRequest(&service)
+	g.Expect(ok).To(BeFalse())
+
+	at := time.Date(2025, 10, 17, 18, 0, 0, 0, time.UTC)
+	SetLastRequest(&service, at)
+	got, ok := LastRequest(&service)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(got).To(BeTemporally("==", at))
+
+	service.Annotations[AnnotationLastRequest] = "yesterday"
+	_, ok = LastRequest(&service)
+	g.Expect(ok).To(BeFalse())
+}
@@ -0,0 +1,116 @@
+// Package logalert counts the application log lines matching alert rules in Loki.
+package logalert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+const (
+	// EvaluationInterval is how often alert rules are evaluated, matches are counted over the
+	// same period so thresholds are per minute
+	EvaluationInterval = time.Minute
+
+	// MaxPatternLength is the longest supported rule pattern
+	MaxPatternLength = 1024
+
+	// queryTimeout bounds a single Loki query
+	queryTimeout = 30 * time.Second
+)
+
+// Query builds the LogQL metric query counting the log lines of an application matching
+// pattern during the last EvaluationInterval. The pattern is an RE2 regular expression when
+// regex is set and a plain substring otherwise.
+func Query(namespace, applicationUUID, pattern string, regex bool) string {
+	operator := "|="
+	if regex {
+		operator = "|~"
+	}
+	return fmt.Sprintf(`sum(count_over_time({%s=%s, %s=%s} %s %s [1m]))`,
+		config.LogLabelNamespace, strconv.Quote(namespace),
+		config.LogLabelApplicationUUID, strconv.Quote(applicationUUID),
+		operator, strconv.Quote(pattern))
+}
+
+// Client runs log metric queries against Loki
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a Client for the Loki at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: queryTimeout},
+	}
+}
+
+// queryResponse is the subset of the Loki instant query response used for metric queries
+type queryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Value [2]any `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// CountMatches runs a metric query built by Query evaluated at time at and returns the number
+// of matching lines, 0 when no line matched
+func (c *Client) CountMatches(ctx context.Context, query string, at time.Time) (int64, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("time", strconv.FormatInt(at.UnixNano(), 10))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/loki/api/v1/query?"+params.Encode(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build Loki request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query Loki: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, fmt.Errorf("loki returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result queryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode Loki response: %w", err)
+	}
+	if result.Status != "success" {
+		return 0, fmt.Errorf("loki query failed with status %s", result.Status)
+	}
+	if result.Data.ResultType != "vector" {
+		return 0, fmt.Errorf("unexpected Loki result type %s", result.Data.ResultType)
+	}
+
+	var count int64
+	for _, sample := range result.Data.Result {
+		raw, ok := sample.Value[1].(string)
+		if !ok {
+			return 0, fmt.Errorf("invalid Loki sample value %v", sample.Value[1])
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid Loki sample value %q: %w", raw, err)
+		}
+		count += int64(value)
+	}
+	return count, nil
+}
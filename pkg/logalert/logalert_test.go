@@ -0,0 +1,54 @@
+package logalert
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestQuery(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Query("project-1", "app-1", "connection refused", false)).To(Equal(
+		`sum(count_over_time({namespace="project-1", application_uuid="app-1"} |= "connection refused" [1m]))`))
+	g.Expect(Query("project-1", "app-1", `level=(error|fatal)`, true)).To(Equal(
+		`sum(count_over_time({namespace="project-1", application_uuid="app-1"} |~ "level=(error|fatal)" [1m]))`))
+}
+
+func TestCountMatches(t *testing.T) {
+	g := NewWithT(t)
+
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		switch query {
+		case "matching":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1741944600,"12"]}]}}`))
+		case "quiet":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		default:
+			http.Error(w, "parse error", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	c := NewClient(server.URL + "/")
+	at := time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC)
+
+	count, err := c.CountMatches(ctx, "matching", at)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(12)))
+
+	count, err = c.CountMatches(ctx, "quiet", at)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(BeZero())
+
+	_, err = c.CountMatches(ctx, "invalid", at)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("loki returned 400: parse error"))
+}
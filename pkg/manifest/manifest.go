@@ -0,0 +1,110 @@
+// Package manifest renders Kubernetes objects as YAML manifests safe to hand out through the API.
+//
+// Objects are sanitized the way an export for GitOps needs them: the metadata the API server
+// assigns is removed, and literal environment variable values of pod templates are redacted,
+// since the operator inlines decrypted env vars into the Deployments it generates.
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+// RedactedValue replaces the literal values of environment variables
+const RedactedValue = "<redacted>"
+
+// serverMetadata are the metadata fields assigned by the API server, meaningless outside the cluster
+var serverMetadata = []string{
+	"managedFields",
+	"resourceVersion",
+	"uid",
+	"generation",
+	"creationTimestamp",
+	"selfLink",
+	"ownerReferences",
+}
+
+// lastAppliedAnnotation holds a full copy of the object written by kubectl apply
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// FromObject converts a typed object to a sanitized unstructured object. The apiVersion and kind,
+// which typed objects read from the API server lack, are resolved from the scheme.
+func FromObject(obj runtime.Object, scheme *runtime.Scheme) (*unstructured.Unstructured, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve kind: %w", err)
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s: %w", gvk.Kind, err)
+	}
+
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	Sanitize(u)
+	return u, nil
+}
+
+// Sanitize removes the server assigned metadata of an object and redacts the literal environment
+// variable values found anywhere in it
+func Sanitize(u *unstructured.Unstructured) {
+	for _, field := range serverMetadata {
+		unstructured.RemoveNestedField(u.Object, "metadata", field)
+	}
+
+	if annotations := u.GetAnnotations(); annotations != nil {
+		delete(annotations, lastAppliedAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		u.SetAnnotations(annotations)
+	}
+
+	redactEnv(u.Object)
+}
+
+// redactEnv walks value and replaces the value of every env var, keeping valueFrom references
+func redactEnv(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if env, ok := field.([]interface{}); ok && key == "env" {
+				for _, item := range env {
+					if envVar, ok := item.(map[string]interface{}); ok {
+						if _, literal := envVar["value"]; literal {
+							envVar["value"] = RedactedValue
+						}
+					}
+				}
+				continue
+			}
+			redactEnv(field)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactEnv(item)
+		}
+	}
+}
+
+// Marshal renders objects as a multi-document YAML stream, in the order given
+func Marshal(objects []*unstructured.Unstructured) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, obj := range objects {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
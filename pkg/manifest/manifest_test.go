@@ -0,0 +1,99 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+func testScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+func TestFromObjectSanitizes(t *testing.T) {
+	g := NewWithT(t)
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "app-1",
+			Namespace:         "default",
+			UID:               types.UID("5e1d"),
+			ResourceVersion:   "42",
+			Generation:        3,
+			CreationTimestamp: metav1.Now(),
+			ManagedFields:     []metav1.ManagedFieldsEntry{{Manager: "kibaship"}},
+			OwnerReferences:   []metav1.OwnerReference{{Name: "deployment-1", UID: types.UID("0a1b")}},
+			Labels:            map[string]string{"app.kubernetes.io/managed-by": "kibaship"},
+			Annotations:       map[string]string{lastAppliedAnnotation: "{}"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "app",
+						Image: "registry.example.com/app:v1",
+						Env: []corev1.EnvVar{
+							{Name: "DATABASE_URL", Value: "postgres://user:secret@db/app"},
+							{Name: "API_KEY", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "keys"},
+								Key:                  "api",
+							}}},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	u, err := FromObject(deployment, testScheme(t))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(u.GetAPIVersion()).To(Equal("apps/v1"))
+	g.Expect(u.GetKind()).To(Equal("Deployment"))
+	g.Expect(u.GetUID()).To(BeEmpty())
+	g.Expect(u.GetResourceVersion()).To(BeEmpty())
+	g.Expect(u.GetManagedFields()).To(BeEmpty())
+	g.Expect(u.GetOwnerReferences()).To(BeEmpty())
+	g.Expect(u.GetAnnotations()).To(BeEmpty())
+	g.Expect(u.GetLabels()).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "kibaship"))
+
+	containers, _, _ := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "containers")
+	env := containers[0].(map[string]interface{})["env"].([]interface{})
+	g.Expect(env[0]).To(HaveKeyWithValue("value", RedactedValue))
+	g.Expect(env[1]).NotTo(HaveKey("value"))
+	g.Expect(env[1]).To(HaveKey("valueFrom"))
+
+	// The original object is left untouched
+	g.Expect(deployment.Spec.Template.Spec.Containers[0].Env[0].Value).To(Equal("postgres://user:secret@db/app"))
+}
+
+func TestMarshal(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := testScheme(t)
+	service, err := FromObject(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "app-svc", Namespace: "default"}}, scheme)
+	g.Expect(err).NotTo(HaveOccurred())
+	configMap, err := FromObject(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"}}, scheme)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	data, err := Marshal([]*unstructured.Unstructured{service, configMap})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	documents := strings.Split(string(data), "---\n")
+	g.Expect(documents).To(HaveLen(2))
+	g.Expect(documents[0]).To(ContainSubstring("kind: Service"))
+	g.Expect(documents[0]).To(ContainSubstring("name: app-svc"))
+	g.Expect(documents[1]).To(ContainSubstring("kind: ConfigMap"))
+	g.Expect(string(data)).NotTo(ContainSubstring("creationTimestamp"))
+}
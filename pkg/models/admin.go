@@ -0,0 +1,219 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	// DefaultAdminListLimit is how many resources an admin listing returns without a limit
+	DefaultAdminListLimit = 50
+
+	// MaxAdminListLimit is the most resources an admin listing returns at once
+	MaxAdminListLimit = 500
+
+	// maxAdminQueryLength bounds the search text of admin listings
+	maxAdminQueryLength = 253
+)
+
+// AdminListRequest holds the query parameters of a cluster-wide listing. Query matches the
+// UUID, slug, name or namespace of a resource, ignoring case; phase matches its phase exactly.
+type AdminListRequest struct {
+	Query  string `form:"query" example:"checkout"`
+	Phase  string `form:"phase" example:"Failed"`
+	Limit  int    `form:"limit" example:"50"`
+	Offset int    `form:"offset" example:"0"`
+}
+
+// Validate validates the admin list request
+func (r *AdminListRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	if len(r.Query) > maxAdminQueryLength {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "query",
+			Message: fmt.Sprintf("query must be at most %d characters", maxAdminQueryLength),
+		})
+	}
+	if r.Limit < 0 || r.Limit > MaxAdminListLimit {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "limit",
+			Message: fmt.Sprintf("limit must be between 1 and %d", MaxAdminListLimit),
+		})
+	}
+	if r.Offset < 0 {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "offset",
+			Message: "offset must not be negative",
+		})
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// matches reports whether a resource in phase, identified by values, passes the request filters
+func (r *AdminListRequest) matches(phase string, values ...string) bool {
+	if r.Phase != "" && phase != r.Phase {
+		return false
+	}
+	if r.Query == "" {
+		return true
+	}
+	query := strings.ToLower(r.Query)
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// limit returns the page size of the request
+func (r *AdminListRequest) limit() int {
+	if r.Limit == 0 {
+		return DefaultAdminListLimit
+	}
+	return r.Limit
+}
+
+// page returns the bounds of the requested page of total results
+func (r *AdminListRequest) page(total int) (int, int) {
+	start := min(r.Offset, total)
+	return start, min(start+r.limit(), total)
+}
+
+// AdminApplicationResponse locates an application in the cluster
+type AdminApplicationResponse struct {
+	UUID            string          `json:"uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name            string          `json:"name" example:"checkout-api"`
+	Slug            string          `json:"slug" example:"abc123de"`
+	Type            ApplicationType `json:"type" example:"GitRepository"`
+	Phase           string          `json:"phase" example:"Running"`
+	ProjectUUID     string          `json:"projectUuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	EnvironmentUUID string          `json:"environmentUuid" example:"123e4567-e89b-12d3-a456-426614174002"`
+	Namespace       string          `json:"namespace" example:"project-123e4567-e89b-12d3-a456-426614174001"`
+	CreatedAt       time.Time       `json:"createdAt" example:"2023-01-01T12:00:00Z"`
+}
+
+// AdminApplicationListResponse is a page of the applications of all projects. Total counts every
+// matching application, not only the ones of the page.
+type AdminApplicationListResponse struct {
+	Applications []AdminApplicationResponse `json:"applications"`
+	Total        int                        `json:"total" example:"120"`
+	Limit        int                        `json:"limit" example:"50"`
+	Offset       int                        `json:"offset" example:"0"`
+}
+
+// NewAdminApplicationListResponse filters applications by the request and returns the requested
+// page, newest first. Applications without a phase yet are reported as Pending.
+func NewAdminApplicationListResponse(applications []v1alpha1.Application, req *AdminListRequest) *AdminApplicationListResponse {
+	matching := make([]AdminApplicationResponse, 0, len(applications))
+	for i := range applications {
+		app := &applications[i]
+		phase := app.Status.Phase
+		if phase == "" {
+			phase = "Pending"
+		}
+		response := AdminApplicationResponse{
+			UUID:            app.GetUUID(),
+			Name:            app.GetAnnotations()[validation.AnnotationResourceName],
+			Slug:            app.GetSlug(),
+			Type:            ApplicationType(app.Spec.Type),
+			Phase:           phase,
+			ProjectUUID:     app.GetLabels()[validation.LabelProjectUUID],
+			EnvironmentUUID: app.GetLabels()[validation.LabelEnvironmentUUID],
+			Namespace:       app.Namespace,
+			CreatedAt:       app.CreationTimestamp.Time,
+		}
+		if req.matches(phase, response.UUID, response.Slug, response.Name, app.Name, app.Namespace) {
+			matching = append(matching, response)
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		if !matching[i].CreatedAt.Equal(matching[j].CreatedAt) {
+			return matching[i].CreatedAt.After(matching[j].CreatedAt)
+		}
+		return matching[i].UUID < matching[j].UUID
+	})
+
+	start, end := req.page(len(matching))
+	return &AdminApplicationListResponse{
+		Applications: matching[start:end],
+		Total:        len(matching),
+		Limit:        req.limit(),
+		Offset:       req.Offset,
+	}
+}
+
+// AdminDeploymentListResponse is a page of the deployments of all projects. Total counts every
+// matching deployment, not only the ones of the page.
+type AdminDeploymentListResponse struct {
+	Deployments []DeploymentResponse `json:"deployments"`
+	Total       int                  `json:"total" example:"12"`
+	Limit       int                  `json:"limit" example:"50"`
+	Offset      int                  `json:"offset" example:"0"`
+}
+
+// NewAdminDeploymentListResponse filters deployments by the request and returns the requested
+// page, newest first. The query also matches the application slug and the commit deployed.
+func NewAdminDeploymentListResponse(deployments []v1alpha1.Deployment, applications []v1alpha1.Application, req *AdminListRequest) *AdminDeploymentListResponse {
+	applicationSlugs := make(map[string]string, len(applications))
+	for i := range applications {
+		applicationSlugs[applications[i].GetUUID()] = applications[i].GetSlug()
+	}
+
+	matching := make([]DeploymentResponse, 0, len(deployments))
+	for i := range deployments {
+		crd := &deployments[i]
+		d := &Deployment{}
+		d.ConvertFromCRD(crd, applicationSlugs[crd.GetLabels()[validation.LabelApplicationUUID]])
+		values := []string{d.UUID, d.Slug, d.ApplicationUUID, d.ApplicationSlug, crd.Name, crd.Namespace}
+		if d.GitRepository != nil {
+			values = append(values, d.GitRepository.CommitSHA, d.GitRepository.Branch)
+		}
+		if req.matches(string(d.Phase), values...) {
+			matching = append(matching, d.ToResponse())
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		if !matching[i].CreatedAt.Equal(matching[j].CreatedAt) {
+			return matching[i].CreatedAt.After(matching[j].CreatedAt)
+		}
+		return matching[i].UUID < matching[j].UUID
+	})
+
+	start, end := req.page(len(matching))
+	return &AdminDeploymentListResponse{
+		Deployments: matching[start:end],
+		Total:       len(matching),
+		Limit:       req.limit(),
+		Offset:      req.Offset,
+	}
+}
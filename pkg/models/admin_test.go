@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestAdminListRequestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         AdminListRequest
+		expectField string
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name: "query with page",
+			req:  AdminListRequest{Query: "checkout", Phase: "Failed", Limit: 100, Offset: 200},
+		},
+		{
+			name:        "limit too large",
+			req:         AdminListRequest{Limit: MaxAdminListLimit + 1},
+			expectField: "limit",
+		},
+		{
+			name:        "negative offset",
+			req:         AdminListRequest{Offset: -1},
+			expectField: "offset",
+		},
+		{
+			name:        "query too long",
+			req:         AdminListRequest{Query: strings.Repeat("a", 254)},
+			expectField: "query",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
+
+func adminTestApplication(uuid, name, namespace string, created time.Time, phase string) v1alpha1.Application {
+	return v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "application-" + uuid,
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(created),
+			Labels: map[string]string{
+				validation.LabelResourceUUID: uuid,
+				validation.LabelResourceSlug: uuid + "-slug",
+				validation.LabelProjectUUID:  namespace,
+			},
+			Annotations: map[string]string{validation.AnnotationResourceName: name},
+		},
+		Spec:   v1alpha1.ApplicationSpec{Type: v1alpha1.ApplicationTypeGitRepository},
+		Status: v1alpha1.ApplicationStatus{Phase: phase},
+	}
+}
+
+func TestNewAdminApplicationListResponse(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC)
+	applications := []v1alpha1.Application{
+		adminTestApplication("app-1", "Checkout API", "project-a", now.Add(-3*time.Hour), "Running"),
+		adminTestApplication("app-2", "checkout-worker", "project-b", now.Add(-2*time.Hour), "Failed"),
+		adminTestApplication("app-3", "billing", "project-b", now.Add(-time.Hour), ""),
+	}
+
+	all := NewAdminApplicationListResponse(applications, &AdminListRequest{})
+	if all.Total != 3 || all.Limit != DefaultAdminListLimit || len(all.Applications) != 3 {
+		t.Fatalf("unexpected response %+v", all)
+	}
+	if all.Applications[0].UUID != "app-3" || all.Applications[0].Phase != "Pending" {
+		t.Errorf("expected the newest application first as Pending, got %+v", all.Applications[0])
+	}
+
+	search := NewAdminApplicationListResponse(applications, &AdminListRequest{Query: "CHECKOUT"})
+	if search.Total != 2 || search.Applications[0].UUID != "app-2" || search.Applications[1].UUID != "app-1" {
+		t.Errorf("expected both checkout applications, got %+v", search.Applications)
+	}
+
+	failed := NewAdminApplicationListResponse(applications, &AdminListRequest{Phase: "Failed"})
+	if failed.Total != 1 || failed.Applications[0].ProjectUUID != "project-b" {
+		t.Errorf("expected the failed application, got %+v", failed.Applications)
+	}
+
+	page := NewAdminApplicationListResponse(applications, &AdminListRequest{Limit: 2, Offset: 2})
+	if page.Total != 3 || len(page.Applications) != 1 || page.Applications[0].UUID != "app-1" {
+		t.Errorf("expected the last application on the second page, got %+v", page)
+	}
+
+	past := NewAdminApplicationListResponse(applications, &AdminListRequest{Offset: 10})
+	if past.Total != 3 || len(past.Applications) != 0 {
+		t.Errorf("expected an empty page past the end, got %+v", past)
+	}
+}
+
+func TestNewAdminDeploymentListResponse(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC)
+	applications := []v1alpha1.Application{
+		adminTestApplication("app-1", "checkout", "project-a", now, "Running"),
+	}
+	deployments := []v1alpha1.Deployment{
+		summaryTestDeployment("dep-1", "app-1", now.Add(-2*time.Hour), v1alpha1.DeploymentPhaseFailed),
+		summaryTestDeployment("dep-2", "app-1", now.Add(-time.Hour), v1alpha1.DeploymentPhaseSucceeded),
+		summaryTestDeployment("dep-3", "app-2", now, v1alpha1.DeploymentPhaseFailed),
+	}
+	deployments[0].Spec.GitRepository = &v1alpha1.GitRepositoryDeploymentConfig{CommitSHA: "abc123def456"}
+
+	failed := NewAdminDeploymentListResponse(deployments, applications, &AdminListRequest{Phase: "Failed"})
+	if failed.Total != 2 || failed.Deployments[0].UUID != "dep-3" || failed.Deployments[1].UUID != "dep-1" {
+		t.Fatalf("expected the failed deployments newest first, got %+v", failed.Deployments)
+	}
+	if slug := failed.Deployments[1].ApplicationSlug; slug != "app-1-slug" {
+		t.Errorf("expected the application slug, got %q", slug)
+	}
+
+	byCommit := NewAdminDeploymentListResponse(deployments, applications, &AdminListRequest{Query: "abc123"})
+	if byCommit.Total != 1 || byCommit.Deployments[0].UUID != "dep-1" {
+		t.Errorf("expected the deployment of the commit, got %+v", byCommit.Deployments)
+	}
+}
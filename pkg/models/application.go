@@ -17,6 +17,8 @@ limitations under the License.
 package models
 
 import (
+	"fmt"
+	"path"
 	"regexp"
 	"strings"
 	"time"
@@ -26,6 +28,7 @@ import (
 	"github.com/kibamail/kibaship/pkg/validation"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 )
 
 // EnvironmentVariable represents a Kubernetes environment variable
@@ -76,6 +79,8 @@ type BuildType string
 const (
 	BuildTypeRailpack   BuildType = "Railpack"
 	BuildTypeDockerfile BuildType = "Dockerfile"
+	BuildTypeNixpacks   BuildType = "Nixpacks"
+	BuildTypeBuildpacks BuildType = "Buildpacks"
 )
 
 // HealthCheckConfig defines the health check configuration for an application
@@ -91,25 +96,47 @@ type HealthCheckConfig struct {
 
 // DockerfileBuildConfig defines configuration for Dockerfile builds
 type DockerfileBuildConfig struct {
-	DockerfilePath string `json:"dockerfilePath" example:"Dockerfile"`
-	BuildContext   string `json:"buildContext,omitempty" example:"."`
+	DockerfilePath string            `json:"dockerfilePath" example:"Dockerfile"`
+	BuildContext   string            `json:"buildContext,omitempty" example:"."`
+	BuildArgs      map[string]string `json:"buildArgs,omitempty"`
+	BuildSecrets   *string           `json:"buildSecrets,omitempty" example:"npm-registry-token"`
+}
+
+// BuildpacksBuildConfig defines configuration for Cloud Native Buildpacks builds
+type BuildpacksBuildConfig struct {
+	BuilderImage string `json:"builderImage,omitempty" example:"paketobuildpacks/builder-jammy-base:latest"`
+}
+
+// PipelineStep defines a custom command run before the image is built. Files written to
+// $KIBASHIP_ARTIFACTS_DIR are published as deployment artifacts.
+type PipelineStep struct {
+	Name   string `json:"name" example:"test"`
+	Image  string `json:"image" example:"node:22"`
+	Script string `json:"script" example:"npm ci && npm test -- --reporter=junit --reporter-option output=$KIBASHIP_ARTIFACTS_DIR/junit.xml"`
 }
 
 // GitRepositoryConfig defines configuration for GitRepository applications
 type GitRepositoryConfig struct {
-	Provider           GitProvider            `json:"provider" example:"github.com"`
-	Repository         string                 `json:"repository" example:"myorg/myapp"`
-	PublicAccess       bool                   `json:"publicAccess,omitempty" example:"false"`
-	SecretRef          *string                `json:"secretRef,omitempty" example:"git-credentials"`
-	Branch             string                 `json:"branch,omitempty" example:"main"`
-	Path               string                 `json:"path,omitempty" example:""`
-	RootDirectory      string                 `json:"rootDirectory,omitempty" example:"./"`
-	BuildType          BuildType              `json:"buildType,omitempty" example:"Railpack"`
-	DockerfileBuild    *DockerfileBuildConfig `json:"dockerfileBuild,omitempty"`
-	BuildCommand       string                 `json:"buildCommand,omitempty" example:"npm run build"`
-	StartCommand       string                 `json:"startCommand,omitempty" example:"npm start"`
-	SpaOutputDirectory string                 `json:"spaOutputDirectory,omitempty" example:"dist"`
-	HealthCheck        *HealthCheckConfig     `json:"healthCheck,omitempty"`
+	Provider            GitProvider            `json:"provider" example:"github.com"`
+	Repository          string                 `json:"repository" example:"myorg/myapp"`
+	PublicAccess        bool                   `json:"publicAccess,omitempty" example:"false"`
+	SecretRef           *string                `json:"secretRef,omitempty" example:"git-credentials"`
+	Branch              string                 `json:"branch,omitempty" example:"main"`
+	Path                string                 `json:"path,omitempty" example:""`
+	RootDirectory       string                 `json:"rootDirectory,omitempty" example:"./"`
+	GitSubmodules       bool                   `json:"gitSubmodules,omitempty" example:"false"`
+	GitLFS              bool                   `json:"gitLFS,omitempty" example:"false"`
+	CloneDepth          int32                  `json:"cloneDepth,omitempty" example:"1"`
+	SparseCheckoutPaths []string               `json:"sparseCheckoutPaths,omitempty" example:"services/api"`
+	BuildType           BuildType              `json:"buildType,omitempty" example:"Railpack"`
+	DockerfileBuild     *DockerfileBuildConfig `json:"dockerfileBuild,omitempty"`
+	BuildpacksBuild     *BuildpacksBuildConfig `json:"buildpacksBuild,omitempty"`
+	BuildCommand        string                 `json:"buildCommand,omitempty" example:"npm run build"`
+	StartCommand        string                 `json:"startCommand,omitempty" example:"npm start"`
+	SpaOutputDirectory  string                 `json:"spaOutputDirectory,omitempty" example:"dist"`
+	HealthCheck         *HealthCheckConfig     `json:"healthCheck,omitempty"`
+	Steps               []PipelineStep         `json:"steps,omitempty"`
+	BuildScheduling     *BuildScheduling       `json:"buildScheduling,omitempty"`
 }
 
 // DockerImageConfig defines configuration for DockerImage applications
@@ -181,6 +208,9 @@ type ApplicationCreateRequest struct {
 	EnvironmentUUID   string                   `json:"environmentUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
 	Type              ApplicationType          `json:"type" example:"DockerImage"`
 	Port              int32                    `json:"port,omitempty" example:"3000"`
+	BaseDomain        string                   `json:"baseDomain,omitempty" example:"apps.customer.com"`
+	DependsOn         []string                 `json:"dependsOn,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	PlatformEnv       *bool                    `json:"platformEnv,omitempty" example:"true"`
 	GitRepository     *GitRepositoryConfig     `json:"gitRepository,omitempty"`
 	DockerImage       *DockerImageConfig       `json:"dockerImage,omitempty"`
 	ImageFromRegistry *ImageFromRegistryConfig `json:"imageFromRegistry,omitempty"`
@@ -195,6 +225,9 @@ type ApplicationCreateRequest struct {
 // ApplicationUpdateRequest represents a request to update an application
 type ApplicationUpdateRequest struct {
 	Name              *string                  `json:"name,omitempty" example:"updated-web-app"`
+	BaseDomain        *string                  `json:"baseDomain,omitempty" example:"apps.customer.com"`
+	DependsOn         *[]string                `json:"dependsOn,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	PlatformEnv       *bool                    `json:"platformEnv,omitempty" example:"false"`
 	GitRepository     *GitRepositoryConfig     `json:"gitRepository,omitempty"`
 	DockerImage       *DockerImageConfig       `json:"dockerImage,omitempty"`
 	ImageFromRegistry *ImageFromRegistryConfig `json:"imageFromRegistry,omitempty"`
@@ -221,6 +254,9 @@ type Application struct {
 	EnvironmentUUID   string                   `json:"environmentUuid"`
 	Type              ApplicationType          `json:"type"`
 	Port              int32                    `json:"port,omitempty" example:"3000"`
+	BaseDomain        string                   `json:"baseDomain,omitempty" example:"apps.customer.com"`
+	DependsOn         []string                 `json:"dependsOn,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	PlatformEnv       bool                     `json:"platformEnv" example:"true"`
 	GitRepository     *GitRepositoryConfig     `json:"gitRepository,omitempty"`
 	DockerImage       *DockerImageConfig       `json:"dockerImage,omitempty"`
 	ImageFromRegistry *ImageFromRegistryConfig `json:"imageFromRegistry,omitempty"`
@@ -231,7 +267,9 @@ type Application struct {
 	Valkey            *ValkeyConfig            `json:"valkey,omitempty"`
 	ValkeyCluster     *ValkeyClusterConfig     `json:"valkeyCluster,omitempty"`
 	Status            string                   `json:"status"`
+	Degraded          *ApplicationDegradation  `json:"degraded,omitempty"`
 	Domains           []*ApplicationDomain     `json:"domains,omitempty"`
+	InternalEndpoints []InternalEndpoint       `json:"internalEndpoints,omitempty"`
 	LatestDeployment  *Deployment              `json:"latestDeployment,omitempty"`
 	CreatedAt         time.Time                `json:"createdAt"`
 	UpdatedAt         time.Time                `json:"updatedAt"`
@@ -246,6 +284,9 @@ type ApplicationResponse struct {
 	ProjectSlug       string                      `json:"projectSlug" example:"xyz789ab"`
 	Type              ApplicationType             `json:"type" example:"DockerImage"`
 	Port              int32                       `json:"port,omitempty" example:"3000"`
+	BaseDomain        string                      `json:"baseDomain,omitempty" example:"apps.customer.com"`
+	DependsOn         []string                    `json:"dependsOn,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	PlatformEnv       bool                        `json:"platformEnv" example:"true"`
 	GitRepository     *GitRepositoryConfig        `json:"gitRepository,omitempty"`
 	DockerImage       *DockerImageConfig          `json:"dockerImage,omitempty"`
 	ImageFromRegistry *ImageFromRegistryConfig    `json:"imageFromRegistry,omitempty"`
@@ -256,7 +297,9 @@ type ApplicationResponse struct {
 	Valkey            *ValkeyConfig               `json:"valkey,omitempty"`
 	ValkeyCluster     *ValkeyClusterConfig        `json:"valkeyCluster,omitempty"`
 	Status            string                      `json:"status" example:"Running"`
+	Degraded          *ApplicationDegradation     `json:"degraded,omitempty"`
 	Domains           []ApplicationDomainResponse `json:"domains,omitempty"`
+	InternalEndpoints []InternalEndpoint          `json:"internalEndpoints,omitempty"`
 	LatestDeployment  *DeploymentResponse         `json:"latestDeployment,omitempty"`
 	CreatedAt         time.Time                   `json:"createdAt" example:"2023-01-01T12:00:00Z"`
 	UpdatedAt         time.Time                   `json:"updatedAt" example:"2023-01-01T12:00:00Z"`
@@ -318,6 +361,17 @@ func (req *ApplicationCreateRequest) Validate() *ValidationErrors {
 		})
 	}
 
+	// Validate base domain
+	if req.BaseDomain != "" && !isValidBaseDomain(req.BaseDomain) {
+		errors = append(errors, ValidationError{
+			Field:   "baseDomain",
+			Message: "Base domain must be a valid domain name (e.g., 'apps.customer.com')",
+		})
+	}
+
+	// Validate dependencies
+	errors = append(errors, validateDependsOn(req.DependsOn)...)
+
 	// Validate type-specific configuration
 	switch req.Type {
 	case ApplicationTypeGitRepository:
@@ -429,6 +483,19 @@ func (req *ApplicationUpdateRequest) ValidateUpdate() *ValidationErrors {
 		errors = append(errors, validateValkeyCluster(req.ValkeyCluster)...)
 	}
 
+	// Validate base domain if provided; an empty value clears the override
+	if req.BaseDomain != nil && *req.BaseDomain != "" && !isValidBaseDomain(*req.BaseDomain) {
+		errors = append(errors, ValidationError{
+			Field:   "baseDomain",
+			Message: "Base domain must be a valid domain name (e.g., 'apps.customer.com')",
+		})
+	}
+
+	// Validate dependencies if provided; an empty list removes them
+	if req.DependsOn != nil {
+		errors = append(errors, validateDependsOn(*req.DependsOn)...)
+	}
+
 	if len(errors) > 0 {
 		return &ValidationErrors{Errors: errors}
 	}
@@ -453,23 +520,28 @@ func (a *Application) ToResponse() ApplicationResponse {
 	}
 
 	return ApplicationResponse{
-		UUID:             a.UUID,
-		Name:             a.Name,
-		Slug:             a.Slug,
-		ProjectUUID:      a.ProjectUUID,
-		ProjectSlug:      a.ProjectSlug,
-		Type:             a.Type,
-		GitRepository:    a.GitRepository,
-		DockerImage:      a.DockerImage,
-		MySQL:            a.MySQL,
-		MySQLCluster:     a.MySQLCluster,
-		Postgres:         a.Postgres,
-		PostgresCluster:  a.PostgresCluster,
-		Status:           a.Status,
-		Domains:          domains,
-		LatestDeployment: latestDeployment,
-		CreatedAt:        a.CreatedAt,
-		UpdatedAt:        a.UpdatedAt,
+		UUID:              a.UUID,
+		Name:              a.Name,
+		Slug:              a.Slug,
+		ProjectUUID:       a.ProjectUUID,
+		ProjectSlug:       a.ProjectSlug,
+		Type:              a.Type,
+		BaseDomain:        a.BaseDomain,
+		DependsOn:         a.DependsOn,
+		PlatformEnv:       a.PlatformEnv,
+		GitRepository:     a.GitRepository,
+		DockerImage:       a.DockerImage,
+		MySQL:             a.MySQL,
+		MySQLCluster:      a.MySQLCluster,
+		Postgres:          a.Postgres,
+		PostgresCluster:   a.PostgresCluster,
+		Status:            a.Status,
+		Degraded:          a.Degraded,
+		Domains:           domains,
+		InternalEndpoints: a.InternalEndpoints,
+		LatestDeployment:  latestDeployment,
+		CreatedAt:         a.CreatedAt,
+		UpdatedAt:         a.UpdatedAt,
 	}
 }
 
@@ -495,7 +567,9 @@ func isValidGitProvider(provider GitProvider) bool {
 
 func isValidBuildType(buildType BuildType) bool {
 	return buildType == BuildTypeRailpack ||
-		buildType == BuildTypeDockerfile
+		buildType == BuildTypeDockerfile ||
+		buildType == BuildTypeNixpacks ||
+		buildType == BuildTypeBuildpacks
 }
 
 func validateGitRepository(config *GitRepositoryConfig) []ValidationError {
@@ -530,7 +604,7 @@ func validateGitRepository(config *GitRepositoryConfig) []ValidationError {
 	if config.BuildType != "" && !isValidBuildType(config.BuildType) {
 		errors = append(errors, ValidationError{
 			Field:   "gitRepository.buildType",
-			Message: "BuildType must be one of: Railpack, Dockerfile",
+			Message: "BuildType must be one of: Railpack, Dockerfile, Nixpacks, Buildpacks",
 		})
 	}
 
@@ -546,6 +620,89 @@ func validateGitRepository(config *GitRepositoryConfig) []ValidationError {
 		}
 	}
 
+	// Validate the builder image of Buildpacks builds
+	if config.BuildType == BuildTypeBuildpacks && config.BuildpacksBuild != nil &&
+		strings.ContainsAny(config.BuildpacksBuild.BuilderImage, " \t\n") {
+		errors = append(errors, ValidationError{
+			Field:   "gitRepository.buildpacksBuild.builderImage",
+			Message: "BuilderImage must be an image reference without whitespace",
+		})
+	}
+
+	errors = append(errors, validatePipelineSteps(config.Steps)...)
+	errors = append(errors, validateCloneOptions(config)...)
+	errors = append(errors, validateBuildScheduling(config.BuildScheduling)...)
+
+	return errors
+}
+
+// validateCloneOptions validates the clone depth and checks that the sparse checkout paths
+// contain the directories the build reads
+func validateCloneOptions(config *GitRepositoryConfig) []ValidationError {
+	var errors []ValidationError
+
+	if config.CloneDepth < 0 || config.CloneDepth > v1alpha1.MaxCloneDepth {
+		errors = append(errors, ValidationError{
+			Field:   "gitRepository.cloneDepth",
+			Message: fmt.Sprintf("Clone depth must be between 0 and %d, 0 clones the full history", v1alpha1.MaxCloneDepth),
+		})
+	}
+
+	buildPaths := []string{config.RootDirectory}
+	if config.BuildType == BuildTypeDockerfile && config.DockerfileBuild != nil {
+		buildPaths = append(buildPaths, path.Dir(config.DockerfileBuild.DockerfilePath), config.DockerfileBuild.BuildContext)
+	}
+	if err := v1alpha1.ValidateSparseCheckoutPaths(config.SparseCheckoutPaths, buildPaths...); err != nil {
+		errors = append(errors, ValidationError{
+			Field:   "gitRepository.sparseCheckoutPaths",
+			Message: err.Error(),
+		})
+	}
+
+	return errors
+}
+
+func validatePipelineSteps(steps []PipelineStep) []ValidationError {
+	var errors []ValidationError
+
+	if len(steps) > 10 {
+		errors = append(errors, ValidationError{
+			Field:   "gitRepository.steps",
+			Message: "At most 10 pipeline steps are allowed",
+		})
+	}
+
+	nameRegex := regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	seen := make(map[string]bool, len(steps))
+	for i, step := range steps {
+		field := fmt.Sprintf("gitRepository.steps[%d]", i)
+		if !nameRegex.MatchString(step.Name) || len(step.Name) > 40 {
+			errors = append(errors, ValidationError{
+				Field:   field + ".name",
+				Message: "Step name must be lowercase alphanumeric characters or '-', at most 40 characters",
+			})
+		} else if seen[step.Name] {
+			errors = append(errors, ValidationError{
+				Field:   field + ".name",
+				Message: "Step names must be unique",
+			})
+		}
+		seen[step.Name] = true
+
+		if strings.TrimSpace(step.Image) == "" {
+			errors = append(errors, ValidationError{
+				Field:   field + ".image",
+				Message: "Step image is required",
+			})
+		}
+		if strings.TrimSpace(step.Script) == "" {
+			errors = append(errors, ValidationError{
+				Field:   field + ".script",
+				Message: "Step script is required",
+			})
+		}
+	}
+
 	return errors
 }
 
@@ -732,6 +889,27 @@ func validateDockerfileBuild(config *DockerfileBuildConfig) []ValidationError {
 		}
 	}
 
+	// Validate build arg names
+	argRegex := regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	for name := range config.BuildArgs {
+		if !argRegex.MatchString(name) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("gitRepository.dockerfileBuild.buildArgs.%s", name),
+				Message: "Build arg names must start with a letter or underscore and contain only letters, digits and underscores",
+			})
+		}
+	}
+
+	// Validate the build secrets secret name
+	if config.BuildSecrets != nil {
+		if errs := k8svalidation.IsDNS1123Subdomain(*config.BuildSecrets); len(errs) > 0 {
+			errors = append(errors, ValidationError{
+				Field:   "gitRepository.dockerfileBuild.buildSecrets",
+				Message: "BuildSecrets must be a valid secret name",
+			})
+		}
+	}
+
 	return errors
 }
 
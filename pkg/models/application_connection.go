@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ConnectionEndpoint is an address a database application is reachable on
+type ConnectionEndpoint struct {
+	Host             string `json:"host" example:"m-a1b2c3d4e5f6g7h8i9.default.svc.cluster.local"`
+	Port             int32  `json:"port" example:"3306"`
+	ConnectionString string `json:"connectionString" example:"mysql://root@m-a1b2c3d4e5f6g7h8i9.default.svc.cluster.local:3306/app"`
+}
+
+// ConnectionSecretRef locates the Secret holding the credentials of a database application
+type ConnectionSecretRef struct {
+	Name        string `json:"name" example:"m-a1b2c3d4e5f6g7h8i9"`
+	Namespace   string `json:"namespace" example:"default"`
+	UsernameKey string `json:"usernameKey,omitempty" example:"rootUser"`
+	PasswordKey string `json:"passwordKey" example:"rootPassword"`
+}
+
+// ConnectionCredentials is the credential pair of a database application
+type ConnectionCredentials struct {
+	Username string `json:"username" example:"root"`
+	Password string `json:"password" example:"s3cr3t-p4ssw0rd"`
+}
+
+// ApplicationConnectionResponse tells how to connect to a database application. Credentials are
+// only returned by the request revealing them, which can be made once; afterwards the secret
+// reference is the way to the password.
+type ApplicationConnectionResponse struct {
+	ApplicationUUID       string                 `json:"applicationUuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Type                  ApplicationType        `json:"type" example:"MySQL"`
+	Database              string                 `json:"database,omitempty" example:"app"`
+	Internal              ConnectionEndpoint     `json:"internal"`
+	External              *ConnectionEndpoint    `json:"external,omitempty"`
+	SecretRef             ConnectionSecretRef    `json:"secretRef"`
+	Credentials           *ConnectionCredentials `json:"credentials,omitempty"`
+	CredentialsRevealedAt *time.Time             `json:"credentialsRevealedAt,omitempty" example:"2023-01-01T12:00:00Z"`
+}
+
+// BuildConnectionString returns the URL clients of the scheme connect with. The password is
+// left out when it is empty, so the string can be shown without revealing it.
+func BuildConnectionString(scheme, username, password, host string, port int32, database string) string {
+	u := url.URL{
+		Scheme: scheme,
+		Host:   net.JoinHostPort(host, strconv.Itoa(int(port))),
+	}
+	switch {
+	case username != "" && password != "":
+		u.User = url.UserPassword(username, password)
+	case username != "":
+		u.User = url.User(username)
+	case password != "":
+		u.User = url.UserPassword("", password)
+	}
+	if database != "" {
+		u.Path = "/" + database
+	}
+	return u.String()
+}
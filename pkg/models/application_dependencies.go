@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// maxApplicationDependencies matches the dependsOn limit of the Application CRD
+const maxApplicationDependencies = 20
+
+// validateDependsOn validates the application UUIDs an application depends on
+func validateDependsOn(dependsOn []string) []ValidationError {
+	var errors []ValidationError
+
+	if len(dependsOn) > maxApplicationDependencies {
+		errors = append(errors, ValidationError{
+			Field:   "dependsOn",
+			Message: fmt.Sprintf("An application can depend on at most %d applications", maxApplicationDependencies),
+		})
+	}
+
+	seen := make(map[string]bool, len(dependsOn))
+	for i, dependency := range dependsOn {
+		field := fmt.Sprintf("dependsOn[%d]", i)
+		if !isValidUUID(dependency) {
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Message: "Dependency must be a valid application UUID",
+			})
+			continue
+		}
+		if seen[dependency] {
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Message: "Dependency is listed more than once",
+			})
+		}
+		seen[dependency] = true
+	}
+
+	return errors
+}
+
+// DependencyOrder sorts the applications of a dependency graph, keyed by application UUID, so
+// every application comes after the applications it depends on. Dependencies outside the graph
+// are ignored. When the graph has a cycle the order is nil and the cycle is returned, starting
+// and ending with the same application.
+func DependencyOrder(graph map[string][]string) ([]string, []string) {
+	uuids := make([]string, 0, len(graph))
+	for uuid := range graph {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(graph))
+	order := make([]string, 0, len(graph))
+	var path []string
+
+	var visit func(uuid string) []string
+	visit = func(uuid string) []string {
+		switch state[uuid] {
+		case visited:
+			return nil
+		case visiting:
+			for i := range path {
+				if path[i] == uuid {
+					return append(append([]string{}, path[i:]...), uuid)
+				}
+			}
+		}
+
+		state[uuid] = visiting
+		path = append(path, uuid)
+
+		dependencies := append([]string{}, graph[uuid]...)
+		sort.Strings(dependencies)
+		for _, dependency := range dependencies {
+			if _, ok := graph[dependency]; !ok {
+				continue
+			}
+			if cycle := visit(dependency); cycle != nil {
+				return cycle
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[uuid] = visited
+		order = append(order, uuid)
+		return nil
+	}
+
+	for _, uuid := range uuids {
+		if cycle := visit(uuid); cycle != nil {
+			return nil, cycle
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDependencyOrder(t *testing.T) {
+	graph := map[string][]string{
+		"web":    {"mysql", "valkey"},
+		"worker": {"mysql"},
+		"mysql":  nil,
+		"valkey": {"deleted"},
+	}
+
+	order, cycle := DependencyOrder(graph)
+	if cycle != nil {
+		t.Fatalf("expected no cycle, got %v", cycle)
+	}
+	expected := []string{"mysql", "valkey", "web", "worker"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected order %v, got %v", expected, order)
+	}
+
+	graph["mysql"] = []string{"worker"}
+	order, cycle = DependencyOrder(graph)
+	if order != nil {
+		t.Errorf("expected no order for a cyclic graph, got %v", order)
+	}
+	expected = []string{"mysql", "worker", "mysql"}
+	if !reflect.DeepEqual(cycle, expected) {
+		t.Errorf("expected cycle %v, got %v", expected, cycle)
+	}
+
+	_, cycle = DependencyOrder(map[string][]string{"web": {"web"}})
+	if !reflect.DeepEqual(cycle, []string{"web", "web"}) {
+		t.Errorf("expected self dependency cycle, got %v", cycle)
+	}
+}
+
+func TestApplicationDependsOnValidate(t *testing.T) {
+	const mysql = "550e8400-e29b-41d4-a716-446655440000"
+
+	tests := []struct {
+		name        string
+		dependsOn   []string
+		expectField string
+	}{
+		{name: "single dependency", dependsOn: []string{mysql}},
+		{name: "remove dependencies", dependsOn: []string{}},
+		{name: "slug instead of uuid", dependsOn: []string{"abc123de"}, expectField: "dependsOn[0]"},
+		{name: "duplicate dependency", dependsOn: []string{mysql, mysql}, expectField: "dependsOn[1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := ApplicationUpdateRequest{DependsOn: &tt.dependsOn}
+			errs := req.ValidateUpdate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
@@ -47,46 +47,71 @@ type ApplicationDomainCreateRequest struct {
 	Type            ApplicationDomainType `json:"type" example:"custom"`
 	Default         bool                  `json:"default" example:"false"`
 	TLSEnabled      bool                  `json:"tlsEnabled" example:"true"`
+	IgnoreTLSPolicy bool                  `json:"ignoreTLSPolicy,omitempty" example:"false"`
 }
 
 // ApplicationDomainResponse represents the application domain data returned to clients
 type ApplicationDomainResponse struct {
-	UUID             string                 `json:"uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Slug             string                 `json:"slug" example:"def456gh"`
-	ApplicationUUID  string                 `json:"applicationUuid" example:"550e8400-e29b-41d4-a716-446655440001"`
-	ApplicationSlug  string                 `json:"applicationSlug" example:"abc123de"`
-	ProjectUUID      string                 `json:"projectUuid" example:"550e8400-e29b-41d4-a716-446655440002"`
-	Domain           string                 `json:"domain" example:"my-app.example.com"`
-	Port             int32                  `json:"port" example:"3000"`
-	Type             ApplicationDomainType  `json:"type" example:"custom"`
-	Default          bool                   `json:"default" example:"false"`
-	TLSEnabled       bool                   `json:"tlsEnabled" example:"true"`
-	Phase            ApplicationDomainPhase `json:"phase" example:"Pending"`
-	CertificateReady bool                   `json:"certificateReady" example:"false"`
-	IngressReady     bool                   `json:"ingressReady" example:"false"`
-	DNSConfigured    bool                   `json:"dnsConfigured" example:"false"`
-	CreatedAt        time.Time              `json:"createdAt" example:"2023-01-01T12:00:00Z"`
-	UpdatedAt        time.Time              `json:"updatedAt" example:"2023-01-01T12:00:00Z"`
+	UUID                 string                 `json:"uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Slug                 string                 `json:"slug" example:"def456gh"`
+	ApplicationUUID      string                 `json:"applicationUuid" example:"550e8400-e29b-41d4-a716-446655440001"`
+	ApplicationSlug      string                 `json:"applicationSlug" example:"abc123de"`
+	ProjectUUID          string                 `json:"projectUuid" example:"550e8400-e29b-41d4-a716-446655440002"`
+	Domain               string                 `json:"domain" example:"my-app.example.com"`
+	Port                 int32                  `json:"port" example:"3000"`
+	Type                 ApplicationDomainType  `json:"type" example:"custom"`
+	Default              bool                   `json:"default" example:"false"`
+	TLSEnabled           bool                   `json:"tlsEnabled" example:"true"`
+	IgnoreTLSPolicy      bool                   `json:"ignoreTLSPolicy" example:"false"`
+	CustomCertificate    bool                   `json:"customCertificate" example:"false"`
+	CertificateExpiresAt *time.Time             `json:"certificateExpiresAt,omitempty" example:"2024-01-01T12:00:00Z"`
+	Phase                ApplicationDomainPhase `json:"phase" example:"Pending"`
+	CertificateReady     bool                   `json:"certificateReady" example:"false"`
+	IngressReady         bool                   `json:"ingressReady" example:"false"`
+	DNSConfigured        bool                   `json:"dnsConfigured" example:"false"`
+	UptimeCheck          *UptimeCheck           `json:"uptimeCheck,omitempty"`
+	Uptime               *UptimeStatus          `json:"uptime,omitempty"`
+	Routes               []DomainRoute          `json:"routes,omitempty"`
+	SecurityHeaders      *SecurityHeaders       `json:"securityHeaders,omitempty"`
+	CORS                 *CORSPolicy            `json:"cors,omitempty"`
+	IngressAnnotations   map[string]string      `json:"ingressAnnotations,omitempty"`
+	BackendProtocol      string                 `json:"backendProtocol,omitempty" example:"grpc"`
+	ProxySettings        *DomainProxySettings   `json:"proxySettings,omitempty"`
+	History              []DomainHistoryEntry   `json:"history,omitempty"`
+	CreatedAt            time.Time              `json:"createdAt" example:"2023-01-01T12:00:00Z"`
+	UpdatedAt            time.Time              `json:"updatedAt" example:"2023-01-01T12:00:00Z"`
 }
 
 // ApplicationDomain represents the internal application domain model
 type ApplicationDomain struct {
-	UUID             string
-	Slug             string
-	ApplicationUUID  string
-	ApplicationSlug  string
-	ProjectUUID      string
-	Domain           string
-	Port             int32
-	Type             ApplicationDomainType
-	Default          bool
-	TLSEnabled       bool
-	Phase            ApplicationDomainPhase
-	CertificateReady bool
-	IngressReady     bool
-	DNSConfigured    bool
-	CreatedAt        time.Time
-	UpdatedAt        time.Time
+	UUID                 string
+	Slug                 string
+	ApplicationUUID      string
+	ApplicationSlug      string
+	ProjectUUID          string
+	Domain               string
+	Port                 int32
+	Type                 ApplicationDomainType
+	Default              bool
+	TLSEnabled           bool
+	IgnoreTLSPolicy      bool
+	CustomCertificate    bool
+	CertificateExpiresAt *time.Time
+	Phase                ApplicationDomainPhase
+	CertificateReady     bool
+	IngressReady         bool
+	DNSConfigured        bool
+	UptimeCheck          *UptimeCheck
+	Uptime               *UptimeStatus
+	Routes               []DomainRoute
+	SecurityHeaders      *SecurityHeaders
+	CORS                 *CORSPolicy
+	IngressAnnotations   map[string]string
+	BackendProtocol      string
+	ProxySettings        *DomainProxySettings
+	History              []DomainHistoryEntry
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
 }
 
 // NewApplicationDomain creates a new application domain with the given parameters
@@ -115,22 +140,34 @@ func NewApplicationDomain(applicationUUID, applicationSlug, projectUUID, slug, d
 // ToResponse converts the internal application domain to a response model
 func (ad *ApplicationDomain) ToResponse() ApplicationDomainResponse {
 	return ApplicationDomainResponse{
-		UUID:             ad.UUID,
-		Slug:             ad.Slug,
-		ApplicationUUID:  ad.ApplicationUUID,
-		ApplicationSlug:  ad.ApplicationSlug,
-		ProjectUUID:      ad.ProjectUUID,
-		Domain:           ad.Domain,
-		Port:             ad.Port,
-		Type:             ad.Type,
-		Default:          ad.Default,
-		TLSEnabled:       ad.TLSEnabled,
-		Phase:            ad.Phase,
-		CertificateReady: ad.CertificateReady,
-		IngressReady:     ad.IngressReady,
-		DNSConfigured:    ad.DNSConfigured,
-		CreatedAt:        ad.CreatedAt,
-		UpdatedAt:        ad.UpdatedAt,
+		UUID:                 ad.UUID,
+		Slug:                 ad.Slug,
+		ApplicationUUID:      ad.ApplicationUUID,
+		ApplicationSlug:      ad.ApplicationSlug,
+		ProjectUUID:          ad.ProjectUUID,
+		Domain:               ad.Domain,
+		Port:                 ad.Port,
+		Type:                 ad.Type,
+		Default:              ad.Default,
+		TLSEnabled:           ad.TLSEnabled,
+		IgnoreTLSPolicy:      ad.IgnoreTLSPolicy,
+		CustomCertificate:    ad.CustomCertificate,
+		CertificateExpiresAt: ad.CertificateExpiresAt,
+		Phase:                ad.Phase,
+		CertificateReady:     ad.CertificateReady,
+		IngressReady:         ad.IngressReady,
+		DNSConfigured:        ad.DNSConfigured,
+		UptimeCheck:          ad.UptimeCheck,
+		Uptime:               ad.Uptime,
+		Routes:               ad.Routes,
+		SecurityHeaders:      ad.SecurityHeaders,
+		CORS:                 ad.CORS,
+		IngressAnnotations:   ad.IngressAnnotations,
+		BackendProtocol:      ad.BackendProtocol,
+		ProxySettings:        ad.ProxySettings,
+		History:              ad.History,
+		CreatedAt:            ad.CreatedAt,
+		UpdatedAt:            ad.UpdatedAt,
 	}
 }
 
@@ -204,10 +241,52 @@ func (ad *ApplicationDomain) ConvertFromCRD(crd *v1alpha1.ApplicationDomain, app
 	ad.Type = ApplicationDomainType(crd.Spec.Type)
 	ad.Default = crd.Spec.Default
 	ad.TLSEnabled = crd.Spec.TLSEnabled
+	ad.IgnoreTLSPolicy = crd.Spec.IgnoreTLSPolicy
+	ad.CustomCertificate = crd.Spec.CertificateSecretRef != nil
+	if crd.Status.CertificateNotAfter != nil {
+		expiresAt := crd.Status.CertificateNotAfter.Time
+		ad.CertificateExpiresAt = &expiresAt
+	}
 	ad.Phase = ApplicationDomainPhase(crd.Status.Phase)
 	ad.CertificateReady = crd.Status.CertificateReady
 	ad.IngressReady = crd.Status.IngressReady
 	ad.DNSConfigured = crd.Status.DNSConfigured
+	ad.UptimeCheck = uptimeCheckFromCRD(crd.Spec.UptimeCheck)
+	ad.Uptime = UptimeStatusFromCRD(crd.Status.Uptime)
+	ad.Routes = domainRoutesFromCRD(crd.Spec.Routes)
+	ad.SecurityHeaders = securityHeadersFromCRD(crd.Spec.SecurityHeaders)
+	ad.CORS = corsPolicyFromCRD(crd.Spec.CORS)
+	ad.IngressAnnotations = crd.Spec.IngressAnnotations
+	ad.BackendProtocol = string(crd.Spec.BackendProtocol)
+	ad.ProxySettings = domainProxySettingsFromCRD(&crd.Spec)
+	ad.History = domainHistoryFromCRD(crd.Status.History)
 	ad.CreatedAt = crd.CreationTimestamp.Time
 	ad.UpdatedAt = crd.CreationTimestamp.Time
 }
+
+// DomainHistoryEntry is a certificate issuance or ingress sync event of an application domain
+type DomainHistoryEntry struct {
+	Time    time.Time `json:"time" example:"2023-01-01T12:00:00Z"`
+	Source  string    `json:"source" example:"Certificate"`
+	Type    string    `json:"type" example:"Warning"`
+	Reason  string    `json:"reason" example:"CertificateFailed"`
+	Message string    `json:"message,omitempty" example:"Failed: the ACME order was rejected"`
+}
+
+// domainHistoryFromCRD converts the history of an ApplicationDomain CRD, oldest first
+func domainHistoryFromCRD(history []v1alpha1.DomainHistoryEntry) []DomainHistoryEntry {
+	if len(history) == 0 {
+		return nil
+	}
+	out := make([]DomainHistoryEntry, 0, len(history))
+	for _, entry := range history {
+		out = append(out, DomainHistoryEntry{
+			Time:    entry.Time.Time,
+			Source:  string(entry.Source),
+			Type:    entry.Type,
+			Reason:  entry.Reason,
+			Message: entry.Message,
+		})
+	}
+	return out
+}
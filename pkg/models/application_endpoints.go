@@ -0,0 +1,27 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+// InternalEndpoint is how the other applications of the environment reach an application. The
+// host is a Service following its promoted deployment, and the containers of every application
+// of the environment get the host and port in the HostEnv and PortEnv variables.
+type InternalEndpoint struct {
+	Host    string `json:"host" example:"app-abc123de.project-123e4567.svc"`
+	Port    int32  `json:"port" example:"3000"`
+	HostEnv string `json:"hostEnv" example:"ABC123DE_HOST"`
+	PortEnv string `json:"portEnv" example:"ABC123DE_PORT"`
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ApplicationEnvVersionResponse is a recorded version of the env vars of an application. Values
+// are never returned, versions with the same values share the same hash.
+type ApplicationEnvVersionResponse struct {
+	Version      int       `json:"version" example:"3"`
+	Hash         string    `json:"hash" example:"9f86d081884c7d65"`
+	Keys         []string  `json:"keys" example:"API_KEY,DB_HOST"`
+	ChangedBy    string    `json:"changedBy,omitempty" example:"jane@example.com"`
+	RestoredFrom int       `json:"restoredFrom,omitempty" example:"1"`
+	CreatedAt    time.Time `json:"createdAt" example:"2023-01-01T12:00:00Z"`
+}
+
+// ApplicationEnvVersionsResponse lists the env versions of an application, newest first
+type ApplicationEnvVersionsResponse struct {
+	ApplicationUUID string                          `json:"applicationUuid" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Versions        []ApplicationEnvVersionResponse `json:"versions"`
+}
+
+// ApplicationEnvRollbackRequest holds the options of an env rollback
+type ApplicationEnvRollbackRequest struct {
+	// OverrideFreeze releases the deployment created for the rollback even while a freeze window is active
+	OverrideFreeze bool `json:"overrideFreeze,omitempty" example:"false"`
+}
+
+// ApplicationEnvRollbackResponse is the env version a rollback recorded and the deployment
+// applying it, absent when the application was never deployed
+type ApplicationEnvRollbackResponse struct {
+	Version    ApplicationEnvVersionResponse `json:"version"`
+	Deployment *DeploymentResponse           `json:"deployment,omitempty"`
+}
+
+// NewApplicationEnvVersionResponse reads an env version from its Secret
+func NewApplicationEnvVersionResponse(secret *corev1.Secret) ApplicationEnvVersionResponse {
+	annotations := secret.GetAnnotations()
+	version, _ := strconv.Atoi(annotations[validation.AnnotationEnvVersion])
+	restoredFrom, _ := strconv.Atoi(annotations[validation.AnnotationEnvRestoredFrom])
+
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return ApplicationEnvVersionResponse{
+		Version:      version,
+		Hash:         annotations[validation.AnnotationEnvVersionHash],
+		Keys:         keys,
+		ChangedBy:    annotations[validation.AnnotationEnvChangedBy],
+		RestoredFrom: restoredFrom,
+		CreatedAt:    secret.CreationTimestamp.Time,
+	}
+}
+
+// BuildApplicationEnvVersions lists the env versions of an application from their Secrets, newest
+// first. Secrets without a version number are skipped.
+func BuildApplicationEnvVersions(secrets []corev1.Secret) []ApplicationEnvVersionResponse {
+	versions := make([]ApplicationEnvVersionResponse, 0, len(secrets))
+	for i := range secrets {
+		version := NewApplicationEnvVersionResponse(&secrets[i])
+		if version.Version <= 0 {
+			continue
+		}
+		versions = append(versions, version)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+	return versions
+}
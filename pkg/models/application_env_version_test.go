@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func envVersionSecret(annotations map[string]string, created time.Time, data map[string][]byte) corev1.Secret {
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Annotations: annotations, CreationTimestamp: metav1.NewTime(created)},
+		Data:       data,
+	}
+}
+
+func TestBuildApplicationEnvVersions(t *testing.T) {
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	secrets := []corev1.Secret{
+		envVersionSecret(map[string]string{
+			validation.AnnotationEnvVersion:     "1",
+			validation.AnnotationEnvVersionHash: "aaaa",
+		}, base, map[string][]byte{"DB_HOST": []byte("db"), "API_KEY": []byte("k1")}),
+		envVersionSecret(map[string]string{
+			validation.AnnotationEnvVersion:      "3",
+			validation.AnnotationEnvVersionHash:  "aaaa",
+			validation.AnnotationEnvChangedBy:    "jane@example.com",
+			validation.AnnotationEnvRestoredFrom: "1",
+		}, base.Add(2*time.Hour), map[string][]byte{"DB_HOST": []byte("db"), "API_KEY": []byte("k1")}),
+		// Not a recorded version
+		envVersionSecret(nil, base, nil),
+		envVersionSecret(map[string]string{
+			validation.AnnotationEnvVersion:     "2",
+			validation.AnnotationEnvVersionHash: "bbbb",
+			validation.AnnotationEnvChangedBy:   "api",
+		}, base.Add(time.Hour), map[string][]byte{"DB_HOST": []byte("db"), "API_KEY": []byte("k2")}),
+	}
+
+	versions := BuildApplicationEnvVersions(secrets)
+	if len(versions) != 3 {
+		t.Fatalf("Expected 3 versions, got %d", len(versions))
+	}
+
+	var order []int
+	for _, version := range versions {
+		order = append(order, version.Version)
+	}
+	if !reflect.DeepEqual(order, []int{3, 2, 1}) {
+		t.Errorf("Expected versions newest first, got %v", order)
+	}
+
+	rollback := versions[0]
+	if rollback.ChangedBy != "jane@example.com" || rollback.RestoredFrom != 1 || rollback.Hash != "aaaa" {
+		t.Errorf("Unexpected rollback version %+v", rollback)
+	}
+	if !reflect.DeepEqual(rollback.Keys, []string{"API_KEY", "DB_HOST"}) {
+		t.Errorf("Expected sorted keys, got %v", rollback.Keys)
+	}
+	if !rollback.CreatedAt.Equal(base.Add(2 * time.Hour)) {
+		t.Errorf("Expected creation time %v, got %v", base.Add(2*time.Hour), rollback.CreatedAt)
+	}
+	if versions[2].ChangedBy != "" || versions[2].RestoredFrom != 0 {
+		t.Errorf("Expected the baseline version without author, got %+v", versions[2])
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplicationDegradation describes why the pods of the promoted deployment of an application fail
+type ApplicationDegradation struct {
+	Reason  string    `json:"reason" example:"CrashLoopBackOff"`
+	Message string    `json:"message" example:"Container app of pod web-7d9f8-x2x4z is crash looping, last exit code 1 (Error)"`
+	Since   time.Time `json:"since" example:"2023-01-01T12:00:00Z"`
+}
+
+// ApplicationDegradationFromConditions returns the degradation the operator reports through the
+// Degraded condition of an Application, nil when the application is healthy
+func ApplicationDegradationFromConditions(conditions []metav1.Condition) *ApplicationDegradation {
+	condition := meta.FindStatusCondition(conditions, "Degraded")
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		return nil
+	}
+	return &ApplicationDegradation{
+		Reason:  condition.Reason,
+		Message: condition.Message,
+		Since:   condition.LastTransitionTime.Time,
+	}
+}
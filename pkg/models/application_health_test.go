@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplicationDegradationFromConditions(t *testing.T) {
+	since := time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC)
+	ready := metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "ApplicationReady"}
+	degraded := metav1.Condition{
+		Type:               "Degraded",
+		Status:             metav1.ConditionTrue,
+		Reason:             "ImagePullBackOff",
+		Message:            "Container app of pod web-1 cannot pull image registry.example.com/web:v2",
+		LastTransitionTime: metav1.NewTime(since),
+	}
+
+	if got := ApplicationDegradationFromConditions(nil); got != nil {
+		t.Errorf("expected no degradation without conditions, got %+v", got)
+	}
+	if got := ApplicationDegradationFromConditions([]metav1.Condition{ready}); got != nil {
+		t.Errorf("expected no degradation without a Degraded condition, got %+v", got)
+	}
+
+	recovered := degraded
+	recovered.Status = metav1.ConditionFalse
+	if got := ApplicationDegradationFromConditions([]metav1.Condition{ready, recovered}); got != nil {
+		t.Errorf("expected no degradation once recovered, got %+v", got)
+	}
+
+	got := ApplicationDegradationFromConditions([]metav1.Condition{ready, degraded})
+	if got == nil {
+		t.Fatal("expected a degradation")
+	}
+	if got.Reason != degraded.Reason || got.Message != degraded.Message || !got.Since.Equal(since) {
+		t.Errorf("unexpected degradation %+v", got)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultLogHistoryLimit is the number of log lines returned when limit is not set
+	DefaultLogHistoryLimit = 100
+
+	// MaxLogHistoryLimit is the largest number of log lines returned by one request
+	MaxLogHistoryLimit = 5000
+
+	// DefaultLogHistoryRange is how far back logs are searched when neither start nor since is set
+	DefaultLogHistoryRange = time.Hour
+
+	// LogDirectionBackward returns the newest lines first
+	LogDirectionBackward = "backward"
+
+	// LogDirectionForward returns the oldest lines first
+	LogDirectionForward = "forward"
+)
+
+// ApplicationLogHistoryRequest holds the query parameters of a historical log search
+type ApplicationLogHistoryRequest struct {
+	Start     string `form:"start" example:"2025-01-01T00:00:00Z"`
+	End       string `form:"end" example:"2025-01-01T01:00:00Z"`
+	Since     string `form:"since" example:"24h"`
+	Filter    string `form:"filter" example:"error"`
+	Limit     int    `form:"limit" example:"100"`
+	Direction string `form:"direction" example:"backward"`
+}
+
+// Validate validates the log history request
+func (r *ApplicationLogHistoryRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	var start, end time.Time
+	var err error
+	if r.Start != "" {
+		if start, err = time.Parse(time.RFC3339, r.Start); err != nil {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   "start",
+				Message: "start must be an RFC3339 timestamp",
+			})
+		}
+	}
+	if r.End != "" {
+		if end, err = time.Parse(time.RFC3339, r.End); err != nil {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   "end",
+				Message: "end must be an RFC3339 timestamp",
+			})
+		}
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "start",
+			Message: "start must be before end",
+		})
+	}
+
+	if r.Since != "" {
+		if r.Start != "" {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   "since",
+				Message: "since cannot be combined with start",
+			})
+		} else if since, err := time.ParseDuration(r.Since); err != nil || since <= 0 {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   "since",
+				Message: "since must be a positive Go duration such as 24h",
+			})
+		}
+	}
+
+	if r.Limit < 0 || r.Limit > MaxLogHistoryLimit {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "limit",
+			Message: fmt.Sprintf("limit must be between 1 and %d", MaxLogHistoryLimit),
+		})
+	}
+
+	if r.Direction != "" && r.Direction != LogDirectionBackward && r.Direction != LogDirectionForward {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "direction",
+			Message: fmt.Sprintf("direction must be '%s' or '%s'", LogDirectionBackward, LogDirectionForward),
+		})
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// TimeRange returns the searched period of a validated request. The end defaults to now and the
+// start to since before the end, or DefaultLogHistoryRange when since is not set.
+func (r *ApplicationLogHistoryRequest) TimeRange(now time.Time) (time.Time, time.Time) {
+	end := now
+	if r.End != "" {
+		end, _ = time.Parse(time.RFC3339, r.End)
+	}
+
+	if r.Start != "" {
+		start, _ := time.Parse(time.RFC3339, r.Start)
+		return start, end
+	}
+
+	since := DefaultLogHistoryRange
+	if r.Since != "" {
+		since, _ = time.ParseDuration(r.Since)
+	}
+	return end.Add(-since), end
+}
+
+// ApplicationLogEntry is a single log line of an application pod
+type ApplicationLogEntry struct {
+	Timestamp      time.Time `json:"timestamp" example:"2023-01-01T00:00:00Z"`
+	Line           string    `json:"line" example:"GET /healthz 200"`
+	Pod            string    `json:"pod,omitempty" example:"app-123e4567-5d8f7c9b6-x2x9z"`
+	Container      string    `json:"container,omitempty" example:"app"`
+	DeploymentUUID string    `json:"deploymentUuid,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ApplicationLogHistoryResponse holds the historical log lines of an application
+type ApplicationLogHistoryResponse struct {
+	ApplicationUUID string                `json:"applicationUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Start           time.Time             `json:"start" example:"2023-01-01T00:00:00Z"`
+	End             time.Time             `json:"end" example:"2023-01-01T01:00:00Z"`
+	Direction       string                `json:"direction" example:"backward"`
+	Entries         []ApplicationLogEntry `json:"entries"`
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplicationLogHistoryRequestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         ApplicationLogHistoryRequest
+		expectField string
+	}{
+		{
+			name: "defaults",
+			req:  ApplicationLogHistoryRequest{},
+		},
+		{
+			name: "time range with filter",
+			req:  ApplicationLogHistoryRequest{Start: "2025-01-01T00:00:00Z", End: "2025-01-02T00:00:00Z", Filter: "error", Limit: 500, Direction: "forward"},
+		},
+		{
+			name: "since",
+			req:  ApplicationLogHistoryRequest{Since: "24h"},
+		},
+		{
+			name:        "invalid start",
+			req:         ApplicationLogHistoryRequest{Start: "yesterday"},
+			expectField: "start",
+		},
+		{
+			name:        "start after end",
+			req:         ApplicationLogHistoryRequest{Start: "2025-01-02T00:00:00Z", End: "2025-01-01T00:00:00Z"},
+			expectField: "start",
+		},
+		{
+			name:        "since with start",
+			req:         ApplicationLogHistoryRequest{Start: "2025-01-01T00:00:00Z", Since: "1h"},
+			expectField: "since",
+		},
+		{
+			name:        "invalid since",
+			req:         ApplicationLogHistoryRequest{Since: "-1h"},
+			expectField: "since",
+		},
+		{
+			name:        "limit too large",
+			req:         ApplicationLogHistoryRequest{Limit: MaxLogHistoryLimit + 1},
+			expectField: "limit",
+		},
+		{
+			name:        "invalid direction",
+			req:         ApplicationLogHistoryRequest{Direction: "sideways"},
+			expectField: "direction",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
+
+func TestApplicationLogHistoryRequestTimeRange(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	start, end := (&ApplicationLogHistoryRequest{}).TimeRange(now)
+	if !end.Equal(now) || !start.Equal(now.Add(-DefaultLogHistoryRange)) {
+		t.Errorf("expected the last hour, got %s - %s", start, end)
+	}
+
+	start, end = (&ApplicationLogHistoryRequest{Since: "24h", End: "2025-01-05T00:00:00Z"}).TimeRange(now)
+	if !end.Equal(time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)) || !start.Equal(time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the day before end, got %s - %s", start, end)
+	}
+
+	start, end = (&ApplicationLogHistoryRequest{Start: "2025-01-01T00:00:00Z"}).TimeRange(now)
+	if !end.Equal(now) || !start.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected start until now, got %s - %s", start, end)
+	}
+}
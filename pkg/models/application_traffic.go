@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+const (
+	// TrafficWindowHour reports traffic over the last hour
+	TrafficWindowHour = "1h"
+
+	// TrafficWindowDay reports traffic over the last day
+	TrafficWindowDay = "24h"
+)
+
+// TrafficWindows are the periods application traffic is reported over
+var TrafficWindows = []string{TrafficWindowHour, TrafficWindowDay}
+
+// ApplicationTrafficWindow holds the traffic an application served over one period, as seen by
+// the ingress controller
+type ApplicationTrafficWindow struct {
+	Window                string   `json:"window" example:"1h"`
+	RequestsPerSecond     float64  `json:"requestsPerSecond" example:"12.5"`
+	ClientErrorsPerSecond float64  `json:"clientErrorsPerSecond" example:"0.25"`
+	ServerErrorsPerSecond float64  `json:"serverErrorsPerSecond" example:"0.05"`
+	ClientErrorRate       float64  `json:"clientErrorRate" example:"0.02"`
+	ServerErrorRate       float64  `json:"serverErrorRate" example:"0.004"`
+	P95LatencyMs          *float64 `json:"p95LatencyMs,omitempty" example:"84.5"`
+}
+
+// NewApplicationTrafficWindow builds the traffic of a period from the per second request and
+// error rates and the p95 latency in seconds, nil when no request was served. Error rates are the
+// share of requests answered with a 4xx or 5xx status.
+func NewApplicationTrafficWindow(window string, requests, clientErrors, serverErrors float64, p95Seconds *float64) ApplicationTrafficWindow {
+	traffic := ApplicationTrafficWindow{
+		Window:                window,
+		RequestsPerSecond:     requests,
+		ClientErrorsPerSecond: clientErrors,
+		ServerErrorsPerSecond: serverErrors,
+	}
+	if requests > 0 {
+		traffic.ClientErrorRate = clientErrors / requests
+		traffic.ServerErrorRate = serverErrors / requests
+	}
+	if p95Seconds != nil {
+		latency := *p95Seconds * 1000
+		traffic.P95LatencyMs = &latency
+	}
+	return traffic
+}
+
+// ApplicationTrafficResponse holds the traffic of an application over the last hour and day
+type ApplicationTrafficResponse struct {
+	ApplicationUUID string                     `json:"applicationUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	IngressProvider string                     `json:"ingressProvider" example:"nginx"`
+	Windows         []ApplicationTrafficWindow `json:"windows"`
+}
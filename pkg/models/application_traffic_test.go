@@ -0,0 +1,45 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "testing"
+
+func TestNewApplicationTrafficWindow(t *testing.T) {
+	p95 := 0.0845
+	traffic := NewApplicationTrafficWindow(TrafficWindowHour, 10, 0.5, 0.1, &p95)
+
+	if traffic.Window != TrafficWindowHour || traffic.RequestsPerSecond != 10 {
+		t.Errorf("unexpected traffic %+v", traffic)
+	}
+	if traffic.ClientErrorRate != 0.05 || traffic.ServerErrorRate != 0.01 {
+		t.Errorf("expected error rates 0.05 and 0.01, got %v and %v", traffic.ClientErrorRate, traffic.ServerErrorRate)
+	}
+	if traffic.P95LatencyMs == nil || *traffic.P95LatencyMs != 84.5 {
+		t.Errorf("expected p95 latency of 84.5ms, got %v", traffic.P95LatencyMs)
+	}
+}
+
+func TestNewApplicationTrafficWindowWithoutRequests(t *testing.T) {
+	traffic := NewApplicationTrafficWindow(TrafficWindowDay, 0, 0, 0, nil)
+
+	if traffic.ClientErrorRate != 0 || traffic.ServerErrorRate != 0 {
+		t.Errorf("expected no error rates without requests, got %+v", traffic)
+	}
+	if traffic.P95LatencyMs != nil {
+		t.Errorf("expected no latency without requests, got %v", *traffic.P95LatencyMs)
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package models
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -88,6 +89,33 @@ func TestValidateDockerfileBuild(t *testing.T) {
 			expectErrors:  true,
 			errorContains: "must be a relative path",
 		},
+		{
+			name: "valid build args and build secrets",
+			config: &DockerfileBuildConfig{
+				DockerfilePath: "Dockerfile",
+				BuildArgs:      map[string]string{"NODE_VERSION": "22", "_private": "1"},
+				BuildSecrets:   func() *string { s := "npm-registry-token"; return &s }(),
+			},
+			expectErrors: false,
+		},
+		{
+			name: "invalid build arg name",
+			config: &DockerfileBuildConfig{
+				DockerfilePath: "Dockerfile",
+				BuildArgs:      map[string]string{"NODE-VERSION": "22"},
+			},
+			expectErrors:  true,
+			errorContains: "Build arg names must start with a letter",
+		},
+		{
+			name: "invalid build secrets name",
+			config: &DockerfileBuildConfig{
+				DockerfilePath: "Dockerfile",
+				BuildSecrets:   func() *string { s := "NPM_TOKEN"; return &s }(),
+			},
+			expectErrors:  true,
+			errorContains: "BuildSecrets must be a valid secret name",
+		},
 	}
 
 	for _, tt := range tests {
@@ -160,6 +188,39 @@ func TestValidateGitRepositoryWithBuildType(t *testing.T) {
 			expectErrors:  true,
 			errorContains: "must be one of: Railpack, Dockerfile",
 		},
+		{
+			name: "valid Nixpacks build type",
+			config: &GitRepositoryConfig{
+				Provider:     GitProviderGitHub,
+				Repository:   "org/repo",
+				PublicAccess: true,
+				BuildType:    BuildTypeNixpacks,
+			},
+			expectErrors: false,
+		},
+		{
+			name: "valid Buildpacks build type with builder image",
+			config: &GitRepositoryConfig{
+				Provider:        GitProviderGitHub,
+				Repository:      "org/repo",
+				PublicAccess:    true,
+				BuildType:       BuildTypeBuildpacks,
+				BuildpacksBuild: &BuildpacksBuildConfig{BuilderImage: "heroku/builder:24"},
+			},
+			expectErrors: false,
+		},
+		{
+			name: "Buildpacks builder image with whitespace",
+			config: &GitRepositoryConfig{
+				Provider:        GitProviderGitHub,
+				Repository:      "org/repo",
+				PublicAccess:    true,
+				BuildType:       BuildTypeBuildpacks,
+				BuildpacksBuild: &BuildpacksBuildConfig{BuilderImage: "heroku/builder 24"},
+			},
+			expectErrors:  true,
+			errorContains: "without whitespace",
+		},
 		{
 			name: "Dockerfile build type without config",
 			config: &GitRepositoryConfig{
@@ -201,6 +262,182 @@ func TestValidateGitRepositoryWithBuildType(t *testing.T) {
 	}
 }
 
+func TestValidatePipelineSteps(t *testing.T) {
+	tests := []struct {
+		name          string
+		steps         []PipelineStep
+		expectErrors  bool
+		errorContains string
+	}{
+		{
+			name:         "no steps",
+			steps:        nil,
+			expectErrors: false,
+		},
+		{
+			name: "valid steps",
+			steps: []PipelineStep{
+				{Name: "lint", Image: "node:22", Script: "npm ci && npm run lint"},
+				{Name: "unit-tests", Image: "node:22", Script: "npm ci && npm test"},
+			},
+			expectErrors: false,
+		},
+		{
+			name: "invalid step name",
+			steps: []PipelineStep{
+				{Name: "Unit Tests", Image: "node:22", Script: "npm test"},
+			},
+			expectErrors:  true,
+			errorContains: "Step name must be lowercase",
+		},
+		{
+			name: "duplicate step names",
+			steps: []PipelineStep{
+				{Name: "test", Image: "node:22", Script: "npm test"},
+				{Name: "test", Image: "golang:1.24", Script: "go test ./..."},
+			},
+			expectErrors:  true,
+			errorContains: "Step names must be unique",
+		},
+		{
+			name: "missing image",
+			steps: []PipelineStep{
+				{Name: "test", Script: "npm test"},
+			},
+			expectErrors:  true,
+			errorContains: "Step image is required",
+		},
+		{
+			name: "missing script",
+			steps: []PipelineStep{
+				{Name: "test", Image: "node:22", Script: "  "},
+			},
+			expectErrors:  true,
+			errorContains: "Step script is required",
+		},
+		{
+			name: "too many steps",
+			steps: func() []PipelineStep {
+				steps := make([]PipelineStep, 11)
+				for i := range steps {
+					steps[i] = PipelineStep{Name: fmt.Sprintf("step-%d", i), Image: "alpine", Script: "true"}
+				}
+				return steps
+			}(),
+			expectErrors:  true,
+			errorContains: "At most 10 pipeline steps",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validatePipelineSteps(tt.steps)
+
+			if tt.expectErrors && len(errors) == 0 {
+				t.Errorf("expected errors but got none")
+			}
+
+			if !tt.expectErrors && len(errors) > 0 {
+				t.Errorf("expected no errors but got: %v", errors)
+			}
+
+			if tt.expectErrors && tt.errorContains != "" {
+				found := false
+				for _, err := range errors {
+					if contains(err.Message, tt.errorContains) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error containing '%s', got: %v", tt.errorContains, errors)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateCloneOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        GitRepositoryConfig
+		expectErrors  bool
+		errorContains string
+	}{
+		{
+			name:         "full clone",
+			config:       GitRepositoryConfig{},
+			expectErrors: false,
+		},
+		{
+			name:         "shallow sparse clone of a monorepo service",
+			config:       GitRepositoryConfig{CloneDepth: 1, RootDirectory: "./services/api/", SparseCheckoutPaths: []string{"services/api", "packages/shared"}},
+			expectErrors: false,
+		},
+		{
+			name:          "negative depth",
+			config:        GitRepositoryConfig{CloneDepth: -1},
+			expectErrors:  true,
+			errorContains: "Clone depth must be between 0 and 10000",
+		},
+		{
+			name:          "root directory outside the sparse checkout",
+			config:        GitRepositoryConfig{RootDirectory: "services/web", SparseCheckoutPaths: []string{"services/api"}},
+			expectErrors:  true,
+			errorContains: "services/web is used by the build",
+		},
+		{
+			name: "Dockerfile outside the sparse checkout",
+			config: GitRepositoryConfig{
+				BuildType:           BuildTypeDockerfile,
+				DockerfileBuild:     &DockerfileBuildConfig{DockerfilePath: "deploy/Dockerfile", BuildContext: "services/api"},
+				SparseCheckoutPaths: []string{"services/api"},
+			},
+			expectErrors:  true,
+			errorContains: "deploy is used by the build",
+		},
+		{
+			name:          "wildcard path",
+			config:        GitRepositoryConfig{SparseCheckoutPaths: []string{"services/*"}},
+			expectErrors:  true,
+			errorContains: "without wildcards",
+		},
+		{
+			name:          "path outside the repository",
+			config:        GitRepositoryConfig{SparseCheckoutPaths: []string{"../other"}},
+			expectErrors:  true,
+			errorContains: "relative path inside the repository",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validateCloneOptions(&tt.config)
+
+			if tt.expectErrors && len(errors) == 0 {
+				t.Errorf("expected errors but got none")
+			}
+
+			if !tt.expectErrors && len(errors) > 0 {
+				t.Errorf("expected no errors but got: %v", errors)
+			}
+
+			if tt.expectErrors && tt.errorContains != "" {
+				found := false
+				for _, err := range errors {
+					if contains(err.Message, tt.errorContains) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error containing '%s', got: %v", tt.errorContains, errors)
+				}
+			}
+		})
+	}
+}
+
 func TestIsValidBuildType(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -209,6 +446,8 @@ func TestIsValidBuildType(t *testing.T) {
 	}{
 		{"valid Railpack", BuildTypeRailpack, true},
 		{"valid Dockerfile", BuildTypeDockerfile, true},
+		{"valid Nixpacks", BuildTypeNixpacks, true},
+		{"valid Buildpacks", BuildTypeBuildpacks, true},
 		{"invalid empty", "", false},
 		{"invalid type", "InvalidType", false},
 	}
@@ -0,0 +1,273 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// ApplyAction is what applying a manifest does to a resource
+type ApplyAction string
+
+const (
+	ApplyActionCreate ApplyAction = "create"
+	ApplyActionUpdate ApplyAction = "update"
+	ApplyActionDelete ApplyAction = "delete"
+)
+
+// Kinds of the resources a change plan refers to
+const (
+	ApplyKindProject             = "Project"
+	ApplyKindEnvironment         = "Environment"
+	ApplyKindApplication         = "Application"
+	ApplyKindApplicationDomain   = "ApplicationDomain"
+	ApplyKindEnvironmentVariable = "EnvironmentVariables"
+)
+
+// applyPlaceholderUUID stands in for the environment of applications that are validated before
+// their environment exists
+const applyPlaceholderUUID = "00000000-0000-0000-0000-000000000000"
+
+// ApplyRequest describes a project tree declaratively, as written in kibaship.yaml. Applying it
+// creates, updates and deletes the environments, applications and domains of the project until
+// they match the manifest. Resources are matched by name: the project within its workspace,
+// environments within the project, applications within their environment and domains by host.
+type ApplyRequest struct {
+	Project      ApplyProject       `json:"project"`
+	Environments []ApplyEnvironment `json:"environments,omitempty"`
+}
+
+// ApplyProject describes the project of a manifest
+type ApplyProject struct {
+	Name          string `json:"name" example:"billing"`
+	WorkspaceUUID string `json:"workspaceUuid" example:"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`
+	Description   string `json:"description,omitempty" example:"Billing services"`
+	BaseDomain    string `json:"baseDomain,omitempty" example:"apps.customer.com"`
+}
+
+// ApplyEnvironment describes an environment of a manifest. Variables are left alone when omitted.
+type ApplyEnvironment struct {
+	Name         string             `json:"name" example:"production"`
+	Description  string             `json:"description,omitempty" example:"Production environment"`
+	Variables    map[string]string  `json:"variables,omitempty"`
+	Applications []ApplyApplication `json:"applications,omitempty"`
+}
+
+// ApplyApplication describes an application of a manifest. DependsOn names applications of the
+// same environment. Env replaces the environment variables of the application and Domains its
+// custom domains, both are left alone when omitted.
+type ApplyApplication struct {
+	Name              string                   `json:"name" example:"api"`
+	Type              ApplicationType          `json:"type" example:"DockerImage"`
+	Port              int32                    `json:"port,omitempty" example:"3000"`
+	BaseDomain        string                   `json:"baseDomain,omitempty" example:"apps.customer.com"`
+	DependsOn         []string                 `json:"dependsOn,omitempty" example:"postgres"`
+	GitRepository     *GitRepositoryConfig     `json:"gitRepository,omitempty"`
+	DockerImage       *DockerImageConfig       `json:"dockerImage,omitempty"`
+	ImageFromRegistry *ImageFromRegistryConfig `json:"imageFromRegistry,omitempty"`
+	MySQL             *MySQLConfig             `json:"mysql,omitempty"`
+	MySQLCluster      *MySQLClusterConfig      `json:"mysqlCluster,omitempty"`
+	Postgres          *PostgresConfig          `json:"postgres,omitempty"`
+	PostgresCluster   *PostgresClusterConfig   `json:"postgresCluster,omitempty"`
+	Valkey            *ValkeyConfig            `json:"valkey,omitempty"`
+	ValkeyCluster     *ValkeyClusterConfig     `json:"valkeyCluster,omitempty"`
+	Env               map[string]string        `json:"env,omitempty"`
+	Domains           []ApplyDomain            `json:"domains,omitempty"`
+}
+
+// ApplyDomain describes a custom domain of an application. Default domains are managed by the
+// platform and never touched by an apply.
+type ApplyDomain struct {
+	Domain     string `json:"domain" example:"api.example.com"`
+	Port       int32  `json:"port" example:"3000"`
+	TLSEnabled bool   `json:"tlsEnabled" example:"true"`
+}
+
+// ApplyChange is one step of the change plan of a manifest
+type ApplyChange struct {
+	Action ApplyAction `json:"action" example:"update"`
+	Kind   string      `json:"kind" example:"Application"`
+	// Path names the resource within the project, such as production/api
+	Path string `json:"path" example:"production/api"`
+	UUID string `json:"uuid,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	// Fields lists the fields an update changes, or the names of the variables it sets and removes
+	Fields []string `json:"fields,omitempty" example:"dockerImage"`
+}
+
+// ApplyResponse is the change plan of a manifest, applied unless DryRun is set
+type ApplyResponse struct {
+	ProjectUUID string        `json:"projectUuid,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DryRun      bool          `json:"dryRun" example:"false"`
+	Changes     []ApplyChange `json:"changes"`
+}
+
+// CreateRequest returns the request creating the project
+func (p *ApplyProject) CreateRequest() *ProjectCreateRequest {
+	return &ProjectCreateRequest{
+		Name:          p.Name,
+		Description:   p.Description,
+		WorkspaceUUID: p.WorkspaceUUID,
+		BaseDomain:    p.BaseDomain,
+	}
+}
+
+// CreateRequest returns the request creating the application in an environment, dependsOn holds
+// the UUIDs of its dependencies
+func (a *ApplyApplication) CreateRequest(environmentUUID string, dependsOn []string) *ApplicationCreateRequest {
+	return &ApplicationCreateRequest{
+		Name:              a.Name,
+		EnvironmentUUID:   environmentUUID,
+		Type:              a.Type,
+		Port:              a.Port,
+		BaseDomain:        a.BaseDomain,
+		DependsOn:         dependsOn,
+		GitRepository:     a.GitRepository,
+		DockerImage:       a.DockerImage,
+		ImageFromRegistry: a.ImageFromRegistry,
+		MySQL:             a.MySQL,
+		MySQLCluster:      a.MySQLCluster,
+		Postgres:          a.Postgres,
+		PostgresCluster:   a.PostgresCluster,
+		Valkey:            a.Valkey,
+		ValkeyCluster:     a.ValkeyCluster,
+	}
+}
+
+// Validate validates the manifest before anything is diffed
+func (r *ApplyRequest) Validate() *ValidationErrors {
+	var errors []ValidationError
+
+	if projectErrors := r.Project.CreateRequest().Validate(); projectErrors != nil {
+		for _, e := range projectErrors.Errors {
+			errors = append(errors, ValidationError{Field: "project." + e.Field, Message: e.Message})
+		}
+	}
+
+	environments := make(map[string]bool, len(r.Environments))
+	domains := make(map[string]bool)
+	for i := range r.Environments {
+		environment := &r.Environments[i]
+		prefix := fmt.Sprintf("environments[%d]", i)
+
+		if strings.TrimSpace(environment.Name) == "" {
+			errors = append(errors, ValidationError{Field: prefix + ".name", Message: "Environment name is required"})
+		} else if environments[environment.Name] {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".name",
+				Message: fmt.Sprintf("Duplicate environment %s", environment.Name),
+			})
+		}
+		environments[environment.Name] = true
+
+		applications := make(map[string]bool, len(environment.Applications))
+		graph := make(map[string][]string, len(environment.Applications))
+		for j := range environment.Applications {
+			applications[environment.Applications[j].Name] = true
+			graph[environment.Applications[j].Name] = environment.Applications[j].DependsOn
+		}
+		seen := make(map[string]bool, len(environment.Applications))
+		for j := range environment.Applications {
+			application := &environment.Applications[j]
+			errors = append(errors, validateApplyApplication(fmt.Sprintf("%s.applications[%d]", prefix, j), application, applications, seen, domains)...)
+		}
+		if _, cycle := DependencyOrder(graph); cycle != nil {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".applications",
+				Message: fmt.Sprintf("Dependency cycle %s", strings.Join(cycle, " -> ")),
+			})
+		}
+	}
+
+	if len(errors) > 0 {
+		return &ValidationErrors{Errors: errors}
+	}
+
+	return nil
+}
+
+// validateApplyApplication validates an application of a manifest, applications holds the names
+// of the applications of its environment, seen and domains the names and hosts validated so far
+func validateApplyApplication(prefix string, application *ApplyApplication, applications, seen, domains map[string]bool) []ValidationError {
+	var errors []ValidationError
+
+	if seen[application.Name] {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".name",
+			Message: fmt.Sprintf("Duplicate application %s", application.Name),
+		})
+	}
+	seen[application.Name] = true
+
+	if createErrors := application.CreateRequest(applyPlaceholderUUID, nil).Validate(); createErrors != nil {
+		for _, e := range createErrors.Errors {
+			errors = append(errors, ValidationError{Field: prefix + "." + e.Field, Message: e.Message})
+		}
+	}
+
+	if len(application.DependsOn) > maxApplicationDependencies {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".dependsOn",
+			Message: fmt.Sprintf("An application can depend on at most %d applications", maxApplicationDependencies),
+		})
+	}
+	for i, dependency := range application.DependsOn {
+		field := fmt.Sprintf("%s.dependsOn[%d]", prefix, i)
+		switch {
+		case dependency == application.Name:
+			errors = append(errors, ValidationError{Field: field, Message: "An application cannot depend on itself"})
+		case !applications[dependency]:
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("Dependency %s is not an application of the environment", dependency),
+			})
+		}
+	}
+
+	if application.Env != nil {
+		switch application.Type {
+		case ApplicationTypeValkey, ApplicationTypeValkeyCluster, ApplicationTypeImageFromRegistry:
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".env",
+				Message: fmt.Sprintf("%s applications do not have environment variables", application.Type),
+			})
+		}
+		if err := v1alpha1.ValidateEnvTemplates(application.Env); err != nil {
+			errors = append(errors, ValidationError{Field: prefix + ".env", Message: err.Error()})
+		}
+	}
+
+	for i, domain := range application.Domains {
+		field := fmt.Sprintf("%s.domains[%d]", prefix, i)
+		if !isValidDomain(domain.Domain) {
+			errors = append(errors, ValidationError{Field: field + ".domain", Message: "Domain must be a valid domain name"})
+		} else if domains[domain.Domain] {
+			errors = append(errors, ValidationError{
+				Field:   field + ".domain",
+				Message: fmt.Sprintf("Duplicate domain %s", domain.Domain),
+			})
+		}
+		domains[domain.Domain] = true
+		if domain.Port < 1 || domain.Port > 65535 {
+			errors = append(errors, ValidationError{Field: field + ".port", Message: "Port must be between 1 and 65535"})
+		}
+	}
+
+	return errors
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+)
+
+func validApplyRequest() *ApplyRequest {
+	return &ApplyRequest{
+		Project: ApplyProject{Name: "billing", WorkspaceUUID: "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		Environments: []ApplyEnvironment{{
+			Name: "production",
+			Applications: []ApplyApplication{
+				{
+					Name:        "api",
+					Type:        ApplicationTypeDockerImage,
+					DependsOn:   []string{"db"},
+					DockerImage: &DockerImageConfig{Image: "nginx"},
+					Env:         map[string]string{"LOG_LEVEL": "info"},
+					Domains:     []ApplyDomain{{Domain: "api.example.com", Port: 3000, TLSEnabled: true}},
+				},
+				{Name: "db", Type: ApplicationTypePostgres},
+			},
+		}},
+	}
+}
+
+func TestApplyRequestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		mutate      func(req *ApplyRequest)
+		expectField string
+	}{
+		{name: "valid manifest", mutate: func(req *ApplyRequest) {}},
+		{name: "missing project name", mutate: func(req *ApplyRequest) { req.Project.Name = "" }, expectField: "project.name"},
+		{name: "invalid workspace", mutate: func(req *ApplyRequest) { req.Project.WorkspaceUUID = "workspace" }, expectField: "project.workspaceUuid"},
+		{
+			name: "duplicate environment",
+			mutate: func(req *ApplyRequest) {
+				req.Environments = append(req.Environments, ApplyEnvironment{Name: "production"})
+			},
+			expectField: "environments[1].name",
+		},
+		{
+			name: "duplicate application",
+			mutate: func(req *ApplyRequest) {
+				req.Environments[0].Applications = append(req.Environments[0].Applications, ApplyApplication{Name: "db", Type: ApplicationTypePostgres})
+			},
+			expectField: "environments[0].applications[2].name",
+		},
+		{
+			name:        "invalid application configuration",
+			mutate:      func(req *ApplyRequest) { req.Environments[0].Applications[0].DockerImage = nil },
+			expectField: "environments[0].applications[0].dockerImage",
+		},
+		{
+			name:        "unknown dependency",
+			mutate:      func(req *ApplyRequest) { req.Environments[0].Applications[0].DependsOn = []string{"cache"} },
+			expectField: "environments[0].applications[0].dependsOn[0]",
+		},
+		{
+			name: "dependency cycle",
+			mutate: func(req *ApplyRequest) {
+				req.Environments[0].Applications[1].DependsOn = []string{"api"}
+			},
+			expectField: "environments[0].applications",
+		},
+		{
+			name: "env on an application without variables",
+			mutate: func(req *ApplyRequest) {
+				req.Environments[0].Applications[1].Type = ApplicationTypeValkey
+				req.Environments[0].Applications[1].Env = map[string]string{"A": "1"}
+			},
+			expectField: "environments[0].applications[1].env",
+		},
+		{
+			name: "env template cycle",
+			mutate: func(req *ApplyRequest) {
+				req.Environments[0].Applications[0].Env = map[string]string{"A": "${env:B}", "B": "${env:A}"}
+			},
+			expectField: "environments[0].applications[0].env",
+		},
+		{
+			name:        "invalid domain",
+			mutate:      func(req *ApplyRequest) { req.Environments[0].Applications[0].Domains[0].Domain = "Not A Domain" },
+			expectField: "environments[0].applications[0].domains[0].domain",
+		},
+		{
+			name:        "invalid domain port",
+			mutate:      func(req *ApplyRequest) { req.Environments[0].Applications[0].Domains[0].Port = 0 },
+			expectField: "environments[0].applications[0].domains[0].port",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validApplyRequest()
+			tt.mutate(req)
+			errs := req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+)
+
+// AutoscalingTrigger is a metric an application scales on
+type AutoscalingTrigger struct {
+	Type            string `json:"type" example:"valkeyListLength" enums:"cpu,requestsPerSecond,valkeyListLength,mysqlQuery"`
+	Target          int64  `json:"target" example:"10"`
+	ApplicationUUID string `json:"applicationUuid,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ListName        string `json:"listName,omitempty" example:"jobs"`
+	Query           string `json:"query,omitempty" example:"SELECT COUNT(*) FROM jobs WHERE state = 'pending'"`
+}
+
+// AutoscalingUpdateRequest replaces the autoscaling configuration of an application
+type AutoscalingUpdateRequest struct {
+	MinReplicas *int32               `json:"minReplicas,omitempty" example:"1"`
+	MaxReplicas int32                `json:"maxReplicas" example:"10"`
+	Triggers    []AutoscalingTrigger `json:"triggers"`
+}
+
+// Validate validates the autoscaling update request
+func (r *AutoscalingUpdateRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	for i, trigger := range r.Triggers {
+		if trigger.ApplicationUUID != "" && !isValidUUID(trigger.ApplicationUUID) {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   fmt.Sprintf("triggers[%d].applicationUuid", i),
+				Message: "applicationUuid must be a valid UUID",
+			})
+		}
+	}
+	if len(errors.Errors) == 0 {
+		if err := v1alpha1.ValidateAutoscaling(*r.ToCRD()); err != nil {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   "triggers",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// ToCRD converts the request to the autoscaling configuration of an Application CRD
+func (r *AutoscalingUpdateRequest) ToCRD() *v1alpha1.Autoscaling {
+	autoscaling := &v1alpha1.Autoscaling{
+		MinReplicas: r.MinReplicas,
+		MaxReplicas: r.MaxReplicas,
+		Triggers:    make([]v1alpha1.AutoscalingTrigger, len(r.Triggers)),
+	}
+	for i, trigger := range r.Triggers {
+		autoscaling.Triggers[i] = v1alpha1.AutoscalingTrigger{
+			Type:     v1alpha1.AutoscalingTriggerType(trigger.Type),
+			Target:   trigger.Target,
+			ListName: trigger.ListName,
+			Query:    trigger.Query,
+		}
+		if trigger.ApplicationUUID != "" {
+			autoscaling.Triggers[i].ApplicationRef = &corev1.LocalObjectReference{
+				Name: utils.GetApplicationResourceName(trigger.ApplicationUUID),
+			}
+		}
+	}
+	return autoscaling
+}
+
+// AutoscalingResponse describes the autoscaling configuration of an application and its current scale
+type AutoscalingResponse struct {
+	ApplicationUUID string               `json:"applicationUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Enabled         bool                 `json:"enabled" example:"true"`
+	MinReplicas     int32                `json:"minReplicas,omitempty" example:"1"`
+	MaxReplicas     int32                `json:"maxReplicas,omitempty" example:"10"`
+	Triggers        []AutoscalingTrigger `json:"triggers"`
+	Ready           bool                 `json:"ready" example:"true"`
+	Active          bool                 `json:"active" example:"true"`
+	CurrentReplicas int32                `json:"currentReplicas" example:"3"`
+	DesiredReplicas int32                `json:"desiredReplicas" example:"4"`
+	LastScaleTime   *time.Time           `json:"lastScaleTime,omitempty" example:"2023-01-02T09:00:00Z"`
+	Message         string               `json:"message,omitempty" example:"KEDA is not installed in the cluster"`
+}
+
+// NewAutoscalingResponse converts the autoscaling configuration and status of an Application CRD
+// to the API response
+func NewAutoscalingResponse(app *v1alpha1.Application) *AutoscalingResponse {
+	response := &AutoscalingResponse{
+		ApplicationUUID: app.GetUUID(),
+		Triggers:        []AutoscalingTrigger{},
+	}
+	if autoscaling := app.Spec.Autoscaling; autoscaling != nil {
+		response.Enabled = true
+		response.MinReplicas = autoscaling.Min()
+		response.MaxReplicas = autoscaling.MaxReplicas
+		for _, trigger := range autoscaling.Triggers {
+			t := AutoscalingTrigger{
+				Type:     string(trigger.Type),
+				Target:   trigger.Target,
+				ListName: trigger.ListName,
+				Query:    trigger.Query,
+			}
+			if trigger.ApplicationRef != nil {
+				t.ApplicationUUID = utils.GetApplicationUUID(trigger.ApplicationRef.Name)
+			}
+			response.Triggers = append(response.Triggers, t)
+		}
+	}
+	if status := app.Status.Autoscaling; status != nil {
+		response.Ready = status.Ready
+		response.Active = status.Active
+		response.CurrentReplicas = status.CurrentReplicas
+		response.DesiredReplicas = status.DesiredReplicas
+		response.Message = status.Message
+		if status.LastScaleTime != nil {
+			response.LastScaleTime = &status.LastScaleTime.Time
+		}
+	}
+	return response
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+func TestAutoscalingUpdateRequestValidate(t *testing.T) {
+	const queue = "550e8400-e29b-41d4-a716-446655440000"
+	zero := int32(0)
+	five := int32(5)
+
+	tests := []struct {
+		name        string
+		req         AutoscalingUpdateRequest
+		expectField string
+	}{
+		{
+			name: "cpu",
+			req:  AutoscalingUpdateRequest{MaxReplicas: 5, Triggers: []AutoscalingTrigger{{Type: "cpu", Target: 70}}},
+		},
+		{
+			name: "queue scaled to zero",
+			req: AutoscalingUpdateRequest{MinReplicas: &zero, MaxReplicas: 10, Triggers: []AutoscalingTrigger{
+				{Type: "valkeyListLength", Target: 10, ApplicationUUID: queue, ListName: "jobs"},
+				{Type: "requestsPerSecond", Target: 50},
+			}},
+		},
+		{
+			name:        "no triggers",
+			req:         AutoscalingUpdateRequest{MaxReplicas: 5},
+			expectField: "triggers",
+		},
+		{
+			name:        "min above max",
+			req:         AutoscalingUpdateRequest{MinReplicas: &five, MaxReplicas: 2, Triggers: []AutoscalingTrigger{{Type: "cpu", Target: 70}}},
+			expectField: "triggers",
+		},
+		{
+			name:        "cpu scaled to zero",
+			req:         AutoscalingUpdateRequest{MinReplicas: &zero, MaxReplicas: 2, Triggers: []AutoscalingTrigger{{Type: "cpu", Target: 70}}},
+			expectField: "triggers",
+		},
+		{
+			name:        "cpu over 100 percent",
+			req:         AutoscalingUpdateRequest{MaxReplicas: 2, Triggers: []AutoscalingTrigger{{Type: "cpu", Target: 120}}},
+			expectField: "triggers",
+		},
+		{
+			name:        "unknown type",
+			req:         AutoscalingUpdateRequest{MaxReplicas: 2, Triggers: []AutoscalingTrigger{{Type: "memory", Target: 70}}},
+			expectField: "triggers",
+		},
+		{
+			name:        "list without application",
+			req:         AutoscalingUpdateRequest{MaxReplicas: 2, Triggers: []AutoscalingTrigger{{Type: "valkeyListLength", Target: 1, ListName: "jobs"}}},
+			expectField: "triggers",
+		},
+		{
+			name:        "query on a request rate trigger",
+			req:         AutoscalingUpdateRequest{MaxReplicas: 2, Triggers: []AutoscalingTrigger{{Type: "requestsPerSecond", Target: 1, Query: "SELECT 1"}}},
+			expectField: "triggers",
+		},
+		{
+			name: "invalid application uuid",
+			req: AutoscalingUpdateRequest{MaxReplicas: 2, Triggers: []AutoscalingTrigger{
+				{Type: "mysqlQuery", Target: 1, ApplicationUUID: "orders", Query: "SELECT 1"},
+			}},
+			expectField: "triggers[0].applicationUuid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
+
+func TestNewAutoscalingResponse(t *testing.T) {
+	app := &v1alpha1.Application{}
+	if response := NewAutoscalingResponse(app); response.Enabled || len(response.Triggers) != 0 {
+		t.Errorf("expected autoscaling to be disabled, got %+v", response)
+	}
+
+	req := AutoscalingUpdateRequest{MaxReplicas: 4, Triggers: []AutoscalingTrigger{
+		{Type: "mysqlQuery", Target: 20, ApplicationUUID: "550e8400-e29b-41d4-a716-446655440000", Query: "SELECT 1"},
+	}}
+	app.Spec.Autoscaling = req.ToCRD()
+	if ref := app.Spec.Autoscaling.Triggers[0].ApplicationRef; ref == nil || ref.Name != "application-550e8400-e29b-41d4-a716-446655440000" {
+		t.Fatalf("expected the trigger to reference the application resource, got %+v", ref)
+	}
+	app.Status.Autoscaling = &v1alpha1.AutoscalingStatus{
+		Ready:           true,
+		CurrentReplicas: 2,
+		DesiredReplicas: 3,
+		LastScaleTime:   &metav1.Time{},
+	}
+
+	response := NewAutoscalingResponse(app)
+	if !response.Enabled || response.MinReplicas != 1 || response.MaxReplicas != 4 || !response.Ready ||
+		response.CurrentReplicas != 2 || response.DesiredReplicas != 3 || response.LastScaleTime == nil {
+		t.Errorf("unexpected autoscaling response %+v", response)
+	}
+	if len(response.Triggers) != 1 || response.Triggers[0] != req.Triggers[0] {
+		t.Errorf("expected the triggers of the request, got %+v", response.Triggers)
+	}
+}
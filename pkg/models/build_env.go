@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// BuildEnvUpdateRequest replaces the build environment variables of an application, an empty
+// request removes them
+type BuildEnvUpdateRequest struct {
+	Variables map[string]string `json:"variables" example:"NPM_TOKEN:npm_abc123,NODE_OPTIONS:--max-old-space-size=4096"`
+}
+
+// Validate validates the build env update request
+func (r *BuildEnvUpdateRequest) Validate() *ValidationErrors {
+	if err := v1alpha1.ValidateBuildEnv(r.Variables); err != nil {
+		return &ValidationErrors{
+			Errors: []ValidationError{{
+				Field:   "variables",
+				Message: err.Error(),
+			}},
+		}
+	}
+	return nil
+}
+
+// BuildEnvResponse lists the build environment variables of an application. Values are never
+// returned, they often hold registry tokens.
+type BuildEnvResponse struct {
+	ApplicationUUID string   `json:"applicationUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Names           []string `json:"names" example:"NODE_OPTIONS,NPM_TOKEN"`
+}
+
+// NewBuildEnvResponse lists the build environment variables of an Application CRD held in its
+// build env secret, sorted by name. A nil secret lists none.
+func NewBuildEnvResponse(app *v1alpha1.Application, secret *corev1.Secret) *BuildEnvResponse {
+	response := &BuildEnvResponse{ApplicationUUID: app.GetUUID(), Names: []string{}}
+	if secret != nil {
+		for name := range secret.Data {
+			response.Names = append(response.Names, name)
+		}
+	}
+	sort.Strings(response.Names)
+	return response
+}
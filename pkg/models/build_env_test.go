@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestBuildEnvUpdateRequestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         BuildEnvUpdateRequest
+		expectField string
+	}{
+		{
+			name: "valid variables",
+			req:  BuildEnvUpdateRequest{Variables: map[string]string{"NPM_TOKEN": "npm_abc123", "NODE_OPTIONS": "--max-old-space-size=4096"}},
+		},
+		{
+			name: "no variables removes them",
+		},
+		{
+			name:        "invalid name",
+			req:         BuildEnvUpdateRequest{Variables: map[string]string{"NPM-TOKEN": "npm_abc123"}},
+			expectField: "variables",
+		},
+		{
+			name:        "too many variables",
+			req:         BuildEnvUpdateRequest{Variables: manyBuildEnvVariables(v1alpha1.MaxBuildEnvVariables + 1)},
+			expectField: "variables",
+		},
+		{
+			name:        "values too large",
+			req:         BuildEnvUpdateRequest{Variables: map[string]string{"CERT": strings.Repeat("a", v1alpha1.MaxBuildEnvBytes)}},
+			expectField: "variables",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
+
+func TestNewBuildEnvResponse(t *testing.T) {
+	app := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{validation.LabelResourceUUID: "app-1"}},
+		Spec: v1alpha1.ApplicationSpec{GitRepository: &v1alpha1.GitRepositoryConfig{
+			BuildEnvSecretRef: &corev1.LocalObjectReference{Name: "application-app-1-build-env"},
+		}},
+	}
+	secret := &corev1.Secret{Data: map[string][]byte{
+		"NPM_TOKEN":    []byte("npm_abc123"),
+		"NODE_OPTIONS": []byte("--max-old-space-size=4096"),
+	}}
+
+	response := NewBuildEnvResponse(app, secret)
+	if response.ApplicationUUID != "app-1" || strings.Join(response.Names, ",") != "NODE_OPTIONS,NPM_TOKEN" {
+		t.Errorf("unexpected response %+v", response)
+	}
+
+	response = NewBuildEnvResponse(app, nil)
+	if len(response.Names) != 0 {
+		t.Errorf("expected no names without a secret, got %v", response.Names)
+	}
+}
+
+func manyBuildEnvVariables(n int) map[string]string {
+	variables := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		variables[fmt.Sprintf("VAR_%d", i)] = "value"
+	}
+	return variables
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultBuildLogWindow is the number of batches sent ahead of the client acknowledgments
+	// when window is not set
+	DefaultBuildLogWindow = 8
+
+	// MaxBuildLogWindow is the largest window a client can ask for
+	MaxBuildLogWindow = 64
+
+	// BuildLogBatchLines and BuildLogBatchBytes bound the lines of a batch
+	BuildLogBatchLines = 500
+	BuildLogBatchBytes = 64 * 1024
+
+	// BuildLogFlushInterval is how long lines wait for their batch to fill up
+	BuildLogFlushInterval = 250 * time.Millisecond
+
+	// MaxBuildLogLineBytes truncates longer lines, such as progress bars without line breaks
+	MaxBuildLogLineBytes = 16 * 1024
+)
+
+// BuildLogStreamOptions holds the query parameters of the build log WebSocket
+type BuildLogStreamOptions struct {
+	// Window is the number of batches sent ahead of the client acknowledgments
+	Window int `form:"window" example:"8"`
+	// Gzip sends every batch gzipped in a binary message instead of a text message
+	Gzip bool `form:"gzip" example:"true"`
+}
+
+// Validate validates the build log stream options
+func (o *BuildLogStreamOptions) Validate() *ValidationErrors {
+	if o.Window < 0 || o.Window > MaxBuildLogWindow {
+		return &ValidationErrors{Errors: []ValidationError{{
+			Field:   "window",
+			Message: fmt.Sprintf("window must be between 1 and %d", MaxBuildLogWindow),
+		}}}
+	}
+	return nil
+}
+
+// WindowSize returns the window of validated options
+func (o *BuildLogStreamOptions) WindowSize() int {
+	if o.Window == 0 {
+		return DefaultBuildLogWindow
+	}
+	return o.Window
+}
+
+// BuildLogLine is a line printed by a step of the build of a deployment
+type BuildLogLine struct {
+	Task string `json:"task" example:"build"`
+	Step string `json:"step" example:"build-and-push"`
+	Line string `json:"line" example:"#5 [2/4] RUN npm ci"`
+}
+
+// BuildLogMessage is a message of the build log WebSocket. Batches carry lines and a sequence
+// number, the last message has Done set, with Error when following the logs failed.
+type BuildLogMessage struct {
+	Seq   int64          `json:"seq" example:"1"`
+	Lines []BuildLogLine `json:"lines,omitempty"`
+	Done  bool           `json:"done,omitempty" example:"false"`
+	Error string         `json:"error,omitempty"`
+}
+
+// BuildLogAck is sent by clients of the build log WebSocket once they processed the batches up to
+// Seq, the server only sends a window of batches ahead of the acknowledgments
+type BuildLogAck struct {
+	Seq int64 `json:"ack" example:"1"`
+}
+
+// BuildLogBatcher groups log lines into batches of at most BuildLogBatchLines lines and
+// BuildLogBatchBytes bytes
+type BuildLogBatcher struct {
+	lines []BuildLogLine
+	size  int
+}
+
+// Add appends line to the batch and reports whether the batch is full
+func (b *BuildLogBatcher) Add(line BuildLogLine) bool {
+	b.lines = append(b.lines, line)
+	b.size += len(line.Task) + len(line.Step) + len(line.Line)
+	return b.Full()
+}
+
+// Full reports whether the batch must be sent before more lines are added
+func (b *BuildLogBatcher) Full() bool {
+	return len(b.lines) >= BuildLogBatchLines || b.size >= BuildLogBatchBytes
+}
+
+// Empty reports whether the batch has no lines
+func (b *BuildLogBatcher) Empty() bool {
+	return len(b.lines) == 0
+}
+
+// Take returns the lines of the batch and starts a new one
+func (b *BuildLogBatcher) Take() []BuildLogLine {
+	lines := b.lines
+	b.lines = nil
+	b.size = 0
+	return lines
+}
+
+// BuildLogWindow tracks the batches sent to a client and not acknowledged yet
+type BuildLogWindow struct {
+	size  int
+	sent  int64
+	acked int64
+}
+
+// NewBuildLogWindow returns a window letting size batches go unacknowledged
+func NewBuildLogWindow(size int) *BuildLogWindow {
+	return &BuildLogWindow{size: size}
+}
+
+// Next returns the sequence number of the next batch sent
+func (w *BuildLogWindow) Next() int64 {
+	w.sent++
+	return w.sent
+}
+
+// Ack records that the client processed the batches up to seq. Acknowledgments of batches not
+// sent yet or already acknowledged are ignored.
+func (w *BuildLogWindow) Ack(seq int64) {
+	if seq > w.acked && seq <= w.sent {
+		w.acked = seq
+	}
+}
+
+// Full reports whether no more batches can be sent until the client acknowledges some
+func (w *BuildLogWindow) Full() bool {
+	return w.sent-w.acked >= int64(w.size)
+}
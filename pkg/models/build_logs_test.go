@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildLogStreamOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options BuildLogStreamOptions
+		window  int
+		wantErr bool
+	}{
+		{name: "defaults", options: BuildLogStreamOptions{}, window: DefaultBuildLogWindow},
+		{name: "custom window", options: BuildLogStreamOptions{Window: 2, Gzip: true}, window: 2},
+		{name: "negative window", options: BuildLogStreamOptions{Window: -1}, wantErr: true},
+		{name: "window too large", options: BuildLogStreamOptions{Window: MaxBuildLogWindow + 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && tt.options.WindowSize() != tt.window {
+				t.Errorf("WindowSize() = %d, want %d", tt.options.WindowSize(), tt.window)
+			}
+		})
+	}
+}
+
+func TestBuildLogBatcher(t *testing.T) {
+	var batcher BuildLogBatcher
+	if !batcher.Empty() {
+		t.Fatal("new batcher is not empty")
+	}
+
+	for i := 1; i < BuildLogBatchLines; i++ {
+		if batcher.Add(BuildLogLine{Task: "build", Step: "build", Line: "line"}) {
+			t.Fatalf("batch full after %d lines", i)
+		}
+	}
+	if !batcher.Add(BuildLogLine{Task: "build", Step: "build", Line: "line"}) {
+		t.Fatalf("batch not full after %d lines", BuildLogBatchLines)
+	}
+	if lines := batcher.Take(); len(lines) != BuildLogBatchLines {
+		t.Errorf("Take() returned %d lines, want %d", len(lines), BuildLogBatchLines)
+	}
+	if !batcher.Empty() {
+		t.Error("batcher not empty after Take()")
+	}
+
+	if !batcher.Add(BuildLogLine{Line: strings.Repeat("x", BuildLogBatchBytes)}) {
+		t.Error("batch not full once it holds BuildLogBatchBytes")
+	}
+}
+
+func TestBuildLogWindow(t *testing.T) {
+	window := NewBuildLogWindow(2)
+
+	if seq := window.Next(); seq != 1 {
+		t.Fatalf("first batch seq = %d, want 1", seq)
+	}
+	if window.Full() {
+		t.Fatal("window full after one of two batches")
+	}
+	window.Next()
+	if !window.Full() {
+		t.Fatal("window not full after two unacknowledged batches")
+	}
+
+	// Acknowledgments of batches not sent yet are ignored
+	window.Ack(5)
+	if !window.Full() {
+		t.Fatal("window opened by an acknowledgment of an unsent batch")
+	}
+
+	window.Ack(1)
+	if window.Full() {
+		t.Fatal("window still full after an acknowledgment")
+	}
+
+	// Stale acknowledgments do not reopen the window
+	window.Next()
+	window.Ack(1)
+	if !window.Full() {
+		t.Error("window opened by a stale acknowledgment")
+	}
+}
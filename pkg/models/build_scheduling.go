@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// BuildToleration lets build pods run on nodes carrying a matching taint
+type BuildToleration struct {
+	Key      string `json:"key,omitempty" example:"kibaship.com/pool"`
+	Operator string `json:"operator,omitempty" example:"Equal"`
+	Value    string `json:"value,omitempty" example:"builds"`
+	Effect   string `json:"effect,omitempty" example:"NoSchedule"`
+}
+
+// BuildScheduling places the build pods of an application on specific nodes. It replaces the
+// build node pool of the operator configuration when set.
+type BuildScheduling struct {
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	Tolerations  []BuildToleration `json:"tolerations,omitempty"`
+}
+
+// validateBuildScheduling validates the build node selector and tolerations
+func validateBuildScheduling(scheduling *BuildScheduling) []ValidationError {
+	if err := v1alpha1.ValidateBuildScheduling(scheduling.ToCRD()); err != nil {
+		return []ValidationError{{
+			Field:   "gitRepository.buildScheduling",
+			Message: err.Error(),
+		}}
+	}
+	return nil
+}
+
+// ToCRD converts the build scheduling to its Application CRD form
+func (b *BuildScheduling) ToCRD() *v1alpha1.BuildSchedulingConfig {
+	if b == nil {
+		return nil
+	}
+
+	scheduling := &v1alpha1.BuildSchedulingConfig{NodeSelector: b.NodeSelector}
+	for _, t := range b.Tolerations {
+		scheduling.Tolerations = append(scheduling.Tolerations, corev1.Toleration{
+			Key:      t.Key,
+			Operator: corev1.TolerationOperator(t.Operator),
+			Value:    t.Value,
+			Effect:   corev1.TaintEffect(t.Effect),
+		})
+	}
+	return scheduling
+}
+
+// BuildSchedulingFromCRD converts the build scheduling of an Application CRD
+func BuildSchedulingFromCRD(b *v1alpha1.BuildSchedulingConfig) *BuildScheduling {
+	if b == nil {
+		return nil
+	}
+
+	scheduling := &BuildScheduling{NodeSelector: b.NodeSelector}
+	for _, t := range b.Tolerations {
+		scheduling.Tolerations = append(scheduling.Tolerations, BuildToleration{
+			Key:      t.Key,
+			Operator: string(t.Operator),
+			Value:    t.Value,
+			Effect:   string(t.Effect),
+		})
+	}
+	return scheduling
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "testing"
+
+func TestValidateBuildScheduling(t *testing.T) {
+	tests := []struct {
+		name        string
+		scheduling  *BuildScheduling
+		expectField string
+	}{
+		{
+			name:       "no build scheduling",
+			scheduling: nil,
+		},
+		{
+			name: "build node pool",
+			scheduling: &BuildScheduling{
+				NodeSelector: map[string]string{"kibaship.com/pool": "builds"},
+				Tolerations: []BuildToleration{
+					{Key: "kibaship.com/pool", Operator: "Equal", Value: "builds", Effect: "NoSchedule"},
+					{Key: "dedicated", Operator: "Exists"},
+				},
+			},
+		},
+		{
+			name:        "invalid node selector label",
+			scheduling:  &BuildScheduling{NodeSelector: map[string]string{"pool name": "builds"}},
+			expectField: "gitRepository.buildScheduling",
+		},
+		{
+			name:        "invalid toleration operator",
+			scheduling:  &BuildScheduling{Tolerations: []BuildToleration{{Key: "pool", Operator: "In", Value: "builds"}}},
+			expectField: "gitRepository.buildScheduling",
+		},
+		{
+			name:        "invalid taint effect",
+			scheduling:  &BuildScheduling{Tolerations: []BuildToleration{{Key: "pool", Value: "builds", Effect: "NoRun"}}},
+			expectField: "gitRepository.buildScheduling",
+		},
+		{
+			name:        "value with exists operator",
+			scheduling:  &BuildScheduling{Tolerations: []BuildToleration{{Key: "pool", Operator: "Exists", Value: "builds"}}},
+			expectField: "gitRepository.buildScheduling",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateBuildScheduling(tt.scheduling)
+
+			if tt.expectField == "" {
+				if len(errs) > 0 {
+					t.Errorf("expected no errors, got %v", errs)
+				}
+				return
+			}
+
+			if len(errs) == 0 {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs)
+			}
+		})
+	}
+}
+
+func TestBuildSchedulingRoundTrip(t *testing.T) {
+	scheduling := &BuildScheduling{
+		NodeSelector: map[string]string{"kibaship.com/pool": "builds"},
+		Tolerations:  []BuildToleration{{Key: "kibaship.com/pool", Operator: "Equal", Value: "builds", Effect: "NoSchedule"}},
+	}
+
+	got := BuildSchedulingFromCRD(scheduling.ToCRD())
+	if got.NodeSelector["kibaship.com/pool"] != "builds" || len(got.Tolerations) != 1 || got.Tolerations[0] != scheduling.Tolerations[0] {
+		t.Errorf("expected %+v after round trip, got %+v", scheduling, got)
+	}
+}
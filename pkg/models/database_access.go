@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxDatabaseNameLength is the longest name of an additional database or schema
+	MaxDatabaseNameLength = 63
+
+	// MaxDatabaseUsernameLength is the longest username MySQL accepts
+	MaxDatabaseUsernameLength = 32
+)
+
+var databaseIdentifierPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// reservedDatabaseUsernames are the users the database engines and the platform rely on
+var reservedDatabaseUsernames = map[string]bool{
+	"root":     true,
+	"postgres": true,
+	"default":  true,
+	"admin":    true,
+}
+
+// DatabaseCreateRequest adds a database to a MySQL application, or a schema to a Postgres application
+type DatabaseCreateRequest struct {
+	Name string `json:"name" example:"orders"`
+}
+
+// Validate validates the database create request
+func (r *DatabaseCreateRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	if message := validateDatabaseIdentifier(r.Name, MaxDatabaseNameLength); message != "" {
+		errors.Errors = append(errors.Errors, ValidationError{Field: "name", Message: "name " + message})
+	} else if strings.HasPrefix(r.Name, "pg_") || r.Name == "information_schema" || r.Name == "mysql" ||
+		r.Name == "performance_schema" || r.Name == "sys" || r.Name == "public" {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "name",
+			Message: fmt.Sprintf("%s is reserved by the database engine", r.Name),
+		})
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// DatabaseUserCreateRequest adds a user to a MySQL or Postgres application with access to some of
+// its databases. The password is generated and stored in a Secret.
+type DatabaseUserCreateRequest struct {
+	Username  string   `json:"username" example:"orders_service"`
+	Databases []string `json:"databases" example:"orders"`
+	ReadOnly  bool     `json:"readOnly,omitempty" example:"false"`
+}
+
+// Validate validates the database user create request
+func (r *DatabaseUserCreateRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	if message := validateDatabaseIdentifier(r.Username, MaxDatabaseUsernameLength); message != "" {
+		errors.Errors = append(errors.Errors, ValidationError{Field: "username", Message: "username " + message})
+	} else if reservedDatabaseUsernames[r.Username] || strings.HasPrefix(r.Username, "mysql") || strings.HasPrefix(r.Username, "pg_") {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "username",
+			Message: fmt.Sprintf("%s is reserved by the database engine", r.Username),
+		})
+	}
+
+	if len(r.Databases) == 0 {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "databases",
+			Message: "at least one database is required",
+		})
+	}
+	seen := make(map[string]bool, len(r.Databases))
+	for i, database := range r.Databases {
+		field := fmt.Sprintf("databases[%d]", i)
+		if message := validateDatabaseIdentifier(database, MaxDatabaseNameLength); message != "" {
+			errors.Errors = append(errors.Errors, ValidationError{Field: field, Message: "database " + message})
+		} else if seen[database] {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("duplicate database %s", database),
+			})
+		}
+		seen[database] = true
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// validateDatabaseIdentifier returns why name is not a valid database identifier, or an empty string
+func validateDatabaseIdentifier(name string, maxLength int) string {
+	if !databaseIdentifierPattern.MatchString(name) || len(name) > maxLength {
+		return fmt.Sprintf("must start with a lowercase letter, contain only lowercase letters, digits and underscores, and be at most %d characters", maxLength)
+	}
+	return ""
+}
+
+// DatabaseUserResponse describes an additional user of a database application
+type DatabaseUserResponse struct {
+	Username   string   `json:"username" example:"orders_service"`
+	Databases  []string `json:"databases" example:"orders"`
+	ReadOnly   bool     `json:"readOnly" example:"false"`
+	SecretName string   `json:"secretName" example:"application-550e8400-e29b-41d4-a716-446655440000-user-orders-service"`
+}
+
+// DatabaseAccessResponse describes the additional databases and users of a database application
+// and the state of the Job applying them
+type DatabaseAccessResponse struct {
+	ApplicationUUID string                 `json:"applicationUuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Databases       []string               `json:"databases" example:"orders"`
+	Users           []DatabaseUserResponse `json:"users"`
+	Phase           string                 `json:"phase,omitempty" example:"Succeeded"`
+	Message         string                 `json:"message,omitempty" example:""`
+	LastAppliedAt   *time.Time             `json:"lastAppliedAt,omitempty" example:"2023-01-01T12:00:00Z"`
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDatabaseCreateRequestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		database    string
+		expectField string
+	}{
+		{name: "valid name", database: "orders"},
+		{name: "underscores and digits", database: "orders_v2"},
+		{name: "empty name", database: "", expectField: "name"},
+		{name: "uppercase", database: "Orders", expectField: "name"},
+		{name: "hyphen", database: "order-items", expectField: "name"},
+		{name: "too long", database: "a" + strings.Repeat("b", 63), expectField: "name"},
+		{name: "engine schema", database: "information_schema", expectField: "name"},
+		{name: "postgres catalog prefix", database: "pg_catalog", expectField: "name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &DatabaseCreateRequest{Name: tt.database}
+			errs := req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
+
+func TestDatabaseUserCreateRequestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		username    string
+		databases   []string
+		expectField string
+	}{
+		{name: "valid user", username: "orders_service", databases: []string{"orders"}},
+		{name: "several databases", username: "reporting", databases: []string{"orders", "billing"}},
+		{name: "invalid username", username: "orders-service", databases: []string{"orders"}, expectField: "username"},
+		{name: "username too long", username: strings.Repeat("a", 33), databases: []string{"orders"}, expectField: "username"},
+		{name: "reserved username", username: "root", databases: []string{"orders"}, expectField: "username"},
+		{name: "mysql system user", username: "mysql_session", databases: []string{"orders"}, expectField: "username"},
+		{name: "no databases", username: "orders_service", expectField: "databases"},
+		{name: "invalid database", username: "orders_service", databases: []string{"Orders"}, expectField: "databases[0]"},
+		{name: "duplicate database", username: "orders_service", databases: []string{"orders", "orders"}, expectField: "databases[1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &DatabaseUserCreateRequest{Username: tt.username, Databases: tt.databases}
+			errs := req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/kibamail/kibaship/api/v1alpha1"
 	"github.com/kibamail/kibaship/pkg/validation"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type DeploymentPhase string
@@ -48,12 +50,42 @@ type ImageFromRegistryDeploymentConfig struct {
 	Resources *ResourceRequirements `json:"resources,omitempty"`
 }
 
+// DeploymentPromotionSource identifies the deployment a promoted deployment reuses the image of
+type DeploymentPromotionSource struct {
+	DeploymentUUID  string `json:"deploymentUuid" example:"550e8400-e29b-41d4-a716-446655440003"`
+	EnvironmentUUID string `json:"environmentUuid" example:"550e8400-e29b-41d4-a716-446655440004"`
+	Image           string `json:"image" example:"registry.registry.svc.cluster.local/default/550e8400-e29b-41d4-a716-446655440001:550e8400-e29b-41d4-a716-446655440003@sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"`
+}
+
+// DeploymentPromoteToRequest represents the request to promote a deployment to another environment
+type DeploymentPromoteToRequest struct {
+	// ApplicationUUID selects the target application, by default the application with the same name in the target environment
+	ApplicationUUID string `json:"applicationUuid,omitempty" example:"550e8400-e29b-41d4-a716-446655440005"`
+	OverrideFreeze  bool   `json:"overrideFreeze,omitempty" example:"false"`
+}
+
+// Validate validates the deployment promote request
+func (req *DeploymentPromoteToRequest) Validate() *ValidationErrors {
+	if req.ApplicationUUID != "" && !validation.ValidateUUID(req.ApplicationUUID) {
+		return &ValidationErrors{
+			Errors: []ValidationError{{
+				Field:   "applicationUuid",
+				Message: "Application UUID must be a valid UUID",
+			}},
+		}
+	}
+
+	return nil
+}
+
 // DeploymentCreateRequest represents the request to create a new deployment
 type DeploymentCreateRequest struct {
 	ApplicationUUID   string                             `json:"applicationUuid" example:"550e8400-e29b-41d4-a716-446655440001" validate:"required"`
 	Promote           bool                               `json:"promote,omitempty" example:"false"`
+	OverrideFreeze    bool                               `json:"overrideFreeze,omitempty" example:"false"`
 	GitRepository     *GitRepositoryDeploymentConfig     `json:"gitRepository,omitempty"`
 	ImageFromRegistry *ImageFromRegistryDeploymentConfig `json:"imageFromRegistry,omitempty"`
+	Source            *DeploymentSource                  `json:"source,omitempty"`
 }
 
 // DeploymentResponse represents the deployment data returned to clients
@@ -66,6 +98,14 @@ type DeploymentResponse struct {
 	Phase             DeploymentPhase                    `json:"phase" example:"Initializing"`
 	GitRepository     *GitRepositoryDeploymentConfig     `json:"gitRepository,omitempty"`
 	ImageFromRegistry *ImageFromRegistryDeploymentConfig `json:"imageFromRegistry,omitempty"`
+	Source            *DeploymentSource                  `json:"source,omitempty"`
+	PromotedFrom      *DeploymentPromotionSource         `json:"promotedFrom,omitempty"`
+	ExternalBuild     *DeploymentExternalBuild           `json:"externalBuild,omitempty"`
+	ImageDigest       string                             `json:"imageDigest,omitempty" example:"sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"`
+	DetectedPort      int32                              `json:"detectedPort,omitempty" example:"8080"`
+	QueuePosition     int32                              `json:"queuePosition,omitempty" example:"2"`
+	EstimatedStart    *time.Time                         `json:"estimatedStart,omitempty" example:"2023-01-01T12:05:00Z"`
+	EnvOutOfSync      bool                               `json:"envOutOfSync,omitempty" example:"false"`
 	CreatedAt         time.Time                          `json:"createdAt" example:"2023-01-01T12:00:00Z"`
 	UpdatedAt         time.Time                          `json:"updatedAt" example:"2023-01-01T12:00:00Z"`
 }
@@ -80,6 +120,14 @@ type Deployment struct {
 	Phase             DeploymentPhase
 	GitRepository     *GitRepositoryDeploymentConfig
 	ImageFromRegistry *ImageFromRegistryDeploymentConfig
+	Source            *DeploymentSource
+	PromotedFrom      *DeploymentPromotionSource
+	ExternalBuild     *DeploymentExternalBuild
+	ImageDigest       string
+	DetectedPort      int32
+	QueuePosition     int32
+	EstimatedStart    *time.Time
+	EnvOutOfSync      bool
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
@@ -111,6 +159,14 @@ func (d *Deployment) ToResponse() DeploymentResponse {
 		Phase:             d.Phase,
 		GitRepository:     d.GitRepository,
 		ImageFromRegistry: d.ImageFromRegistry,
+		Source:            d.Source,
+		PromotedFrom:      d.PromotedFrom,
+		ExternalBuild:     d.ExternalBuild,
+		ImageDigest:       d.ImageDigest,
+		DetectedPort:      d.DetectedPort,
+		QueuePosition:     d.QueuePosition,
+		EstimatedStart:    d.EstimatedStart,
+		EnvOutOfSync:      d.EnvOutOfSync,
 		CreatedAt:         d.CreatedAt,
 		UpdatedAt:         d.UpdatedAt,
 	}
@@ -142,6 +198,10 @@ func (req *DeploymentCreateRequest) Validate() *ValidationErrors {
 		}
 	}
 
+	if req.Source != nil {
+		validationErrors = append(validationErrors, validateDeploymentSource(req.Source)...)
+	}
+
 	if len(validationErrors) > 0 {
 		return &ValidationErrors{
 			Errors: validationErrors,
@@ -159,6 +219,17 @@ func (d *Deployment) ConvertFromCRD(crd *v1alpha1.Deployment, applicationSlug st
 	d.ApplicationSlug = applicationSlug
 	d.ProjectUUID = crd.GetLabels()[validation.LabelProjectUUID]
 	d.Phase = DeploymentPhase(crd.Status.Phase)
+	d.ImageDigest = crd.Status.ImageDigest
+	d.DetectedPort = crd.Status.DetectedPort
+	d.QueuePosition = crd.Status.QueuePosition
+	if crd.Status.EstimatedStart != nil {
+		estimatedStart := crd.Status.EstimatedStart.Time
+		d.EstimatedStart = &estimatedStart
+	}
+	// The application env vars changed since the deployment secret was copied
+	envSynced := meta.FindStatusCondition(crd.Status.Conditions, "EnvSynced")
+	d.EnvOutOfSync = envSynced != nil && envSynced.Status == metav1.ConditionFalse
+	d.Source = DeploymentSourceFromAnnotations(crd.GetAnnotations())
 	d.CreatedAt = crd.CreationTimestamp.Time
 	d.UpdatedAt = crd.CreationTimestamp.Time
 
@@ -184,6 +255,22 @@ func (d *Deployment) ConvertFromCRD(crd *v1alpha1.Deployment, applicationSlug st
 			d.ImageFromRegistry.Resources = fromKubernetesResourceRequirements(*crd.Spec.ImageFromRegistry.Resources)
 		}
 	}
+
+	if crd.Spec.PromotedFrom != nil {
+		d.PromotedFrom = &DeploymentPromotionSource{
+			DeploymentUUID:  crd.Spec.PromotedFrom.DeploymentUUID,
+			EnvironmentUUID: crd.Spec.PromotedFrom.EnvironmentUUID,
+			Image:           crd.Spec.PromotedFrom.Image,
+		}
+	}
+
+	if crd.Spec.ExternalBuild != nil {
+		d.ExternalBuild = &DeploymentExternalBuild{
+			Image:    crd.Spec.ExternalBuild.Image,
+			Provider: crd.Spec.ExternalBuild.Provider,
+			RunURL:   crd.Spec.ExternalBuild.RunURL,
+		}
+	}
 }
 
 // fromKubernetesResourceRequirements converts Kubernetes ResourceRequirements to our ResourceRequirements
@@ -0,0 +1,34 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "time"
+
+// DeploymentArtifact is a file published by a custom pipeline step of a deployment
+type DeploymentArtifact struct {
+	Path         string    `json:"path" example:"reports/junit.xml"`
+	Size         int64     `json:"size" example:"18432"`
+	ContentType  string    `json:"contentType,omitempty" example:"text/xml; charset=utf-8"`
+	LastModified time.Time `json:"lastModified" example:"2023-01-01T12:00:00Z"`
+	DownloadURL  string    `json:"downloadUrl" example:"/v1/deployments/123e4567-e89b-12d3-a456-426614174000/artifacts/reports/junit.xml"`
+}
+
+// DeploymentArtifactsResponse lists the artifacts published by the pipeline of a deployment
+type DeploymentArtifactsResponse struct {
+	DeploymentUUID string               `json:"deploymentUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Artifacts      []DeploymentArtifact `json:"artifacts"`
+}
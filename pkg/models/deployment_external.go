@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"net/url"
+	"regexp"
+)
+
+const (
+	maxExternalImageLength    = 512
+	maxExternalProviderLength = 63
+)
+
+var (
+	// externalImagePattern matches image references without a digest, optionally with a registry
+	// port and a tag
+	externalImagePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._/-]*(:[0-9]+/[a-z0-9._/-]+)?(:[A-Za-z0-9_][A-Za-z0-9._-]{0,127})?$`)
+
+	// imageDigestPattern matches the sha256 digest of an image
+	imageDigestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// DeploymentExternalBuild describes an image built outside the platform that a deployment rolls out
+type DeploymentExternalBuild struct {
+	Image    string `json:"image" example:"ghcr.io/myorg/myapp@sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"`
+	Provider string `json:"provider,omitempty" example:"github-actions"`
+	RunURL   string `json:"runUrl,omitempty" example:"https://github.com/myorg/myapp/actions/runs/42"`
+}
+
+// ExternalDeploymentCreateRequest notifies the platform of an image already built by an external
+// CI system, such as a Tekton EventListener or a GitHub Actions workflow
+type ExternalDeploymentCreateRequest struct {
+	Image          string            `json:"image" example:"ghcr.io/myorg/myapp"`
+	Digest         string            `json:"digest" example:"sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"`
+	CommitSHA      string            `json:"commitSHA" example:"abc123def456"`
+	Branch         string            `json:"branch,omitempty" example:"main"`
+	Provider       string            `json:"provider,omitempty" example:"github-actions"`
+	RunURL         string            `json:"runUrl,omitempty" example:"https://github.com/myorg/myapp/actions/runs/42"`
+	Promote        bool              `json:"promote,omitempty" example:"true"`
+	OverrideFreeze bool              `json:"overrideFreeze,omitempty" example:"false"`
+	Source         *DeploymentSource `json:"source,omitempty"`
+}
+
+// Validate validates the external deployment create request
+func (req *ExternalDeploymentCreateRequest) Validate() *ValidationErrors {
+	var validationErrors []ValidationError
+
+	if req.Image == "" || len(req.Image) > maxExternalImageLength || !externalImagePattern.MatchString(req.Image) {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "image",
+			Message: "Image must be an image reference without a digest, such as ghcr.io/myorg/myapp",
+		})
+	}
+	if !imageDigestPattern.MatchString(req.Digest) {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "digest",
+			Message: "Digest must be a sha256 image digest",
+		})
+	}
+	if req.CommitSHA == "" {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "commitSHA",
+			Message: "Commit SHA is required for external deployments",
+		})
+	}
+	if len(req.Provider) > maxExternalProviderLength {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "provider",
+			Message: "Provider must be at most 63 characters",
+		})
+	}
+	if req.RunURL != "" {
+		u, err := url.Parse(req.RunURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "runUrl",
+				Message: "Run URL must be an absolute http or https URL",
+			})
+		}
+	}
+	if req.Source != nil {
+		validationErrors = append(validationErrors, validateDeploymentSource(req.Source)...)
+	}
+
+	if len(validationErrors) > 0 {
+		return &ValidationErrors{
+			Errors: validationErrors,
+		}
+	}
+
+	return nil
+}
+
+// PinnedImage returns the image of the request pinned to its digest
+func (req *ExternalDeploymentCreateRequest) PinnedImage() string {
+	return req.Image + "@" + req.Digest
+}
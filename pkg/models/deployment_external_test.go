@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+const testImageDigest = "sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"
+
+func TestExternalDeploymentCreateRequestValidate(t *testing.T) {
+	valid := ExternalDeploymentCreateRequest{
+		Image:     "ghcr.io/myorg/myapp",
+		Digest:    testImageDigest,
+		CommitSHA: "abc123def456",
+		Provider:  "github-actions",
+		RunURL:    "https://github.com/myorg/myapp/actions/runs/42",
+	}
+
+	tests := []struct {
+		name        string
+		modify      func(req *ExternalDeploymentCreateRequest)
+		expectField string
+	}{
+		{
+			name:   "valid request",
+			modify: func(req *ExternalDeploymentCreateRequest) {},
+		},
+		{
+			name:   "tagged image on a registry with a port",
+			modify: func(req *ExternalDeploymentCreateRequest) { req.Image = "registry.example.com:5000/myapp:v1.2.3" },
+		},
+		{
+			name:        "image with a digest",
+			modify:      func(req *ExternalDeploymentCreateRequest) { req.Image = "ghcr.io/myorg/myapp@" + testImageDigest },
+			expectField: "image",
+		},
+		{
+			name:        "missing digest",
+			modify:      func(req *ExternalDeploymentCreateRequest) { req.Digest = "" },
+			expectField: "digest",
+		},
+		{
+			name:        "missing commit",
+			modify:      func(req *ExternalDeploymentCreateRequest) { req.CommitSHA = "" },
+			expectField: "commitSHA",
+		},
+		{
+			name:        "relative run URL",
+			modify:      func(req *ExternalDeploymentCreateRequest) { req.RunURL = "/actions/runs/42" },
+			expectField: "runUrl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+			errs := req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
+
+func TestExternalBuildFromCRD(t *testing.T) {
+	req := &ExternalDeploymentCreateRequest{Image: "ghcr.io/myorg/myapp", Digest: testImageDigest}
+	crd := &v1alpha1.Deployment{Spec: v1alpha1.DeploymentSpec{ExternalBuild: &v1alpha1.ExternalBuildSource{
+		Image:    req.PinnedImage(),
+		Provider: "tekton",
+	}}}
+
+	deployment := &Deployment{}
+	deployment.ConvertFromCRD(crd, "web")
+
+	if deployment.ExternalBuild == nil || deployment.ExternalBuild.Image != "ghcr.io/myorg/myapp@"+testImageDigest ||
+		deployment.ExternalBuild.Provider != "tekton" {
+		t.Errorf("unexpected external build %+v", deployment.ExternalBuild)
+	}
+	if image := crd.PrebuiltImage(); image != req.PinnedImage() {
+		t.Errorf("expected the external image to be rolled out, got %q", image)
+	}
+}
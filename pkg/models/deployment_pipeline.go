@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// taskRunReasonPending is the reason of TaskRuns whose pod is not scheduled yet
+const taskRunReasonPending = "Pending"
+
+// PipelineStatus is the progress of a deployment pipeline or of one of its tasks
+type PipelineStatus string
+
+const (
+	PipelineStatusPending   PipelineStatus = "Pending"
+	PipelineStatusRunning   PipelineStatus = "Running"
+	PipelineStatusSucceeded PipelineStatus = "Succeeded"
+	PipelineStatusFailed    PipelineStatus = "Failed"
+	PipelineStatusCancelled PipelineStatus = "Cancelled"
+	PipelineStatusSkipped   PipelineStatus = "Skipped"
+)
+
+// DeploymentPipelineTask is a node of the pipeline graph. RunAfter lists the tasks it waits for,
+// finally tasks run once all other tasks are done. DurationSeconds of a running task is the time
+// elapsed so far.
+type DeploymentPipelineTask struct {
+	Name            string         `json:"name" example:"build"`
+	RunAfter        []string       `json:"runAfter" example:"clone-repository"`
+	Finally         bool           `json:"finally" example:"false"`
+	Status          PipelineStatus `json:"status" example:"Running"`
+	Message         string         `json:"message,omitempty" example:"All Steps have completed executing"`
+	StartedAt       *time.Time     `json:"startedAt,omitempty" example:"2023-01-01T12:00:00Z"`
+	CompletedAt     *time.Time     `json:"completedAt,omitempty" example:"2023-01-01T12:03:00Z"`
+	DurationSeconds int64          `json:"durationSeconds" example:"180"`
+}
+
+// DeploymentPipelineResponse is the task graph of the pipeline building a deployment, with the
+// progress of every task, in the order the pipeline declares them
+type DeploymentPipelineResponse struct {
+	DeploymentUUID  string                   `json:"deploymentUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	PipelineRun     string                   `json:"pipelineRun" example:"pipeline-run-123e4567-e89b-12d3-a456-426614174000-1"`
+	Status          PipelineStatus           `json:"status" example:"Running"`
+	Message         string                   `json:"message,omitempty" example:"Tasks Completed: 1 (Failed: 0, Cancelled 0), Incomplete: 1, Skipped: 0"`
+	StartedAt       *time.Time               `json:"startedAt,omitempty" example:"2023-01-01T12:00:00Z"`
+	CompletedAt     *time.Time               `json:"completedAt,omitempty" example:"2023-01-01T12:05:00Z"`
+	DurationSeconds int64                    `json:"durationSeconds" example:"300"`
+	Tasks           []DeploymentPipelineTask `json:"tasks"`
+}
+
+// NewDeploymentPipelineResponse derives the pipeline graph of a deployment from its PipelineRun and
+// the TaskRuns it created. Tasks without a TaskRun are Pending while the run is in progress and
+// Skipped once it is done.
+func NewDeploymentPipelineResponse(deploymentUUID string, pipelineRun *tektonv1.PipelineRun,
+	taskRuns []tektonv1.TaskRun, now time.Time) *DeploymentPipelineResponse {
+	response := &DeploymentPipelineResponse{
+		DeploymentUUID: deploymentUUID,
+		PipelineRun:    pipelineRun.Name,
+		Tasks:          []DeploymentPipelineTask{},
+	}
+	response.Status, response.Message = pipelineConditionStatus(pipelineRun.Status.GetCondition(apis.ConditionSucceeded), tektonv1.PipelineRunReasonCancelled.String())
+	response.StartedAt, response.CompletedAt, response.DurationSeconds = pipelineTiming(pipelineRun.Status.StartTime, pipelineRun.Status.CompletionTime, now)
+
+	runsByTask := make(map[string]*tektonv1.TaskRun, len(taskRuns))
+	for i := range taskRuns {
+		runsByTask[taskRuns[i].Labels["tekton.dev/pipelineTask"]] = &taskRuns[i]
+	}
+	skipped := make(map[string]string, len(pipelineRun.Status.SkippedTasks))
+	for _, task := range pipelineRun.Status.SkippedTasks {
+		skipped[task.Name] = string(task.Reason)
+	}
+	done := response.Status != PipelineStatusPending && response.Status != PipelineStatusRunning
+
+	spec := pipelineRun.Status.PipelineSpec
+	if spec == nil {
+		return response
+	}
+
+	addTask := func(pipelineTask tektonv1.PipelineTask, finally bool) {
+		task := DeploymentPipelineTask{
+			Name:     pipelineTask.Name,
+			RunAfter: append([]string{}, pipelineTask.RunAfter...),
+			Finally:  finally,
+			Status:   PipelineStatusPending,
+		}
+		switch run, reason := runsByTask[pipelineTask.Name], skipped[pipelineTask.Name]; {
+		case run != nil:
+			task.Status, task.Message = pipelineConditionStatus(run.Status.GetCondition(apis.ConditionSucceeded), tektonv1.TaskRunReasonCancelled.String())
+			task.StartedAt, task.CompletedAt, task.DurationSeconds = pipelineTiming(run.Status.StartTime, run.Status.CompletionTime, now)
+		case reason != "":
+			task.Status, task.Message = PipelineStatusSkipped, reason
+		case done:
+			task.Status = PipelineStatusSkipped
+		}
+		response.Tasks = append(response.Tasks, task)
+	}
+	for _, task := range spec.Tasks {
+		addTask(task, false)
+	}
+	for _, task := range spec.Finally {
+		addTask(task, true)
+	}
+
+	return response
+}
+
+// pipelineConditionStatus maps the Succeeded condition of a PipelineRun or TaskRun to a status
+func pipelineConditionStatus(condition *apis.Condition, cancelledReason string) (PipelineStatus, string) {
+	if condition == nil {
+		return PipelineStatusPending, ""
+	}
+
+	switch {
+	case condition.IsTrue():
+		return PipelineStatusSucceeded, condition.Message
+	case condition.IsFalse() && condition.Reason == cancelledReason:
+		return PipelineStatusCancelled, condition.Message
+	case condition.IsFalse():
+		return PipelineStatusFailed, condition.Message
+	case condition.Reason == tektonv1.PipelineRunReasonPending.String() || condition.Reason == taskRunReasonPending:
+		return PipelineStatusPending, condition.Message
+	default:
+		return PipelineStatusRunning, condition.Message
+	}
+}
+
+// pipelineTiming returns the start and completion times and the duration, up to now when the
+// run has not completed yet
+func pipelineTiming(start, completion *metav1.Time, now time.Time) (*time.Time, *time.Time, int64) {
+	if start == nil {
+		return nil, nil, 0
+	}
+
+	startedAt := start.Time
+	if completion == nil {
+		return &startedAt, nil, int64(now.Sub(startedAt).Seconds())
+	}
+	completedAt := completion.Time
+	return &startedAt, &completedAt, int64(completedAt.Sub(startedAt).Seconds())
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+func pipelineTestTaskRun(task string, start time.Time, completion *time.Time, status corev1.ConditionStatus, reason string) tektonv1.TaskRun {
+	run := tektonv1.TaskRun{ObjectMeta: metav1.ObjectMeta{
+		Name:   "pipeline-run-dep-1-1-" + task,
+		Labels: map[string]string{"tekton.dev/pipelineTask": task},
+	}}
+	run.Status.StartTime = &metav1.Time{Time: start}
+	if completion != nil {
+		run.Status.CompletionTime = &metav1.Time{Time: *completion}
+	}
+	run.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: status, Reason: reason})
+	return run
+}
+
+func TestNewDeploymentPipelineResponse(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC)
+	started := now.Add(-5 * time.Minute)
+	cloned := started.Add(30 * time.Second)
+
+	pipelineRun := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pipeline-run-dep-1-1"}}
+	pipelineRun.Status.StartTime = &metav1.Time{Time: started}
+	pipelineRun.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionUnknown, Reason: "Running"})
+	pipelineRun.Status.PipelineSpec = &tektonv1.PipelineSpec{
+		Tasks: []tektonv1.PipelineTask{
+			{Name: "clone-repository"},
+			{Name: "step-test", RunAfter: []string{"clone-repository"}},
+			{Name: "build", RunAfter: []string{"step-test"}},
+		},
+		Finally: []tektonv1.PipelineTask{{Name: "publish-artifacts"}},
+	}
+	taskRuns := []tektonv1.TaskRun{
+		pipelineTestTaskRun("clone-repository", started, &cloned, corev1.ConditionTrue, "Succeeded"),
+		pipelineTestTaskRun("step-test", cloned, nil, corev1.ConditionUnknown, "Running"),
+	}
+
+	response := NewDeploymentPipelineResponse("dep-1", pipelineRun, taskRuns, now)
+	if response.Status != PipelineStatusRunning || response.DurationSeconds != 300 || response.CompletedAt != nil {
+		t.Fatalf("unexpected pipeline progress: %+v", response)
+	}
+	if len(response.Tasks) != 4 {
+		t.Fatalf("expected 4 tasks, got %d", len(response.Tasks))
+	}
+
+	expected := []struct {
+		name     string
+		status   PipelineStatus
+		duration int64
+		finally  bool
+	}{
+		{"clone-repository", PipelineStatusSucceeded, 30, false},
+		{"step-test", PipelineStatusRunning, 270, false},
+		{"build", PipelineStatusPending, 0, false},
+		{"publish-artifacts", PipelineStatusPending, 0, true},
+	}
+	for i, want := range expected {
+		task := response.Tasks[i]
+		if task.Name != want.name || task.Status != want.status || task.DurationSeconds != want.duration || task.Finally != want.finally {
+			t.Errorf("task %d: expected %+v, got %+v", i, want, task)
+		}
+	}
+	if got := response.Tasks[2].RunAfter; len(got) != 1 || got[0] != "step-test" {
+		t.Errorf("expected build to run after step-test, got %v", got)
+	}
+
+	// The step fails, the build never runs
+	failed := now.Add(time.Minute)
+	taskRuns[1] = pipelineTestTaskRun("step-test", cloned, &failed, corev1.ConditionFalse, "Failed")
+	pipelineRun.Status.CompletionTime = &metav1.Time{Time: failed}
+	pipelineRun.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse, Reason: "Failed", Message: "Tasks Completed: 2 (Failed: 1, Cancelled 0), Skipped: 1"})
+
+	response = NewDeploymentPipelineResponse("dep-1", pipelineRun, taskRuns, now.Add(time.Hour))
+	if response.Status != PipelineStatusFailed || response.DurationSeconds != 360 {
+		t.Fatalf("unexpected pipeline progress: %+v", response)
+	}
+	if response.Tasks[1].Status != PipelineStatusFailed || response.Tasks[1].DurationSeconds != 330 {
+		t.Errorf("expected step-test to fail after 330s, got %+v", response.Tasks[1])
+	}
+	if response.Tasks[2].Status != PipelineStatusSkipped {
+		t.Errorf("expected build to be skipped, got %s", response.Tasks[2].Status)
+	}
+}
+
+func TestNewDeploymentPipelineResponse_Cancelled(t *testing.T) {
+	pipelineRun := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pipeline-run-dep-1-1"}}
+	pipelineRun.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse, Reason: tektonv1.PipelineRunReasonCancelled.String()})
+
+	response := NewDeploymentPipelineResponse("dep-1", pipelineRun, nil, time.Now())
+	if response.Status != PipelineStatusCancelled {
+		t.Errorf("expected Cancelled, got %s", response.Status)
+	}
+	if response.Tasks == nil || len(response.Tasks) != 0 {
+		t.Errorf("expected an empty task list before the pipeline spec is resolved, got %v", response.Tasks)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+// DeploymentPlanAction is what creating a deployment does to a resource
+type DeploymentPlanAction string
+
+const (
+	DeploymentPlanActionCreate    DeploymentPlanAction = "create"
+	DeploymentPlanActionUnchanged DeploymentPlanAction = "unchanged"
+)
+
+// DeploymentPlanResource is a Kubernetes resource the controllers generate for a deployment, with
+// the fields worth reviewing before a build is triggered
+type DeploymentPlanResource struct {
+	Kind       string               `json:"kind" example:"Pipeline"`
+	APIVersion string               `json:"apiVersion" example:"tekton.dev/v1"`
+	Name       string               `json:"name" example:"pipeline-123e4567-e89b-12d3-a456-426614174000"`
+	Namespace  string               `json:"namespace" example:"default"`
+	Action     DeploymentPlanAction `json:"action" example:"create"`
+	Fields     map[string]string    `json:"fields,omitempty"`
+}
+
+// DeploymentPlan lists the resources creating a deployment generates, in the order the
+// controllers create them. DeploymentUUID is an example: the deployment gets a new UUID when it
+// is created, which changes the names derived from it.
+type DeploymentPlan struct {
+	ApplicationUUID string                   `json:"applicationUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	DeploymentUUID  string                   `json:"deploymentUuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Resources       []DeploymentPlanResource `json:"resources"`
+}
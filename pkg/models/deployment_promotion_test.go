@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestDeploymentPromoteToRequestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         DeploymentPromoteToRequest
+		expectField string
+	}{
+		{
+			name: "defaults",
+			req:  DeploymentPromoteToRequest{},
+		},
+		{
+			name: "explicit target application",
+			req:  DeploymentPromoteToRequest{ApplicationUUID: "550e8400-e29b-41d4-a716-446655440005", OverrideFreeze: true},
+		},
+		{
+			name:        "invalid target application",
+			req:         DeploymentPromoteToRequest{ApplicationUUID: "api"},
+			expectField: "applicationUuid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
+
+func TestDeploymentConvertFromCRDPromotedFrom(t *testing.T) {
+	crd := &v1alpha1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				validation.LabelResourceUUID:    "550e8400-e29b-41d4-a716-446655440006",
+				validation.LabelApplicationUUID: "550e8400-e29b-41d4-a716-446655440005",
+			},
+		},
+		Spec: v1alpha1.DeploymentSpec{
+			GitRepository: &v1alpha1.GitRepositoryDeploymentConfig{CommitSHA: "abc123def456", Branch: "main"},
+			PromotedFrom: &v1alpha1.PromotionSource{
+				DeploymentUUID:  "550e8400-e29b-41d4-a716-446655440003",
+				EnvironmentUUID: "550e8400-e29b-41d4-a716-446655440004",
+				Image:           "registry.registry.svc.cluster.local/default/app:build@sha256:abc",
+			},
+		},
+		Status: v1alpha1.DeploymentStatus{ImageDigest: "sha256:def"},
+	}
+
+	deployment := &Deployment{}
+	deployment.ConvertFromCRD(crd, "abc123de")
+
+	if deployment.PromotedFrom == nil {
+		t.Fatal("expected promotedFrom to be set")
+	}
+	if deployment.PromotedFrom.DeploymentUUID != "550e8400-e29b-41d4-a716-446655440003" ||
+		deployment.PromotedFrom.EnvironmentUUID != "550e8400-e29b-41d4-a716-446655440004" ||
+		deployment.PromotedFrom.Image != "registry.registry.svc.cluster.local/default/app:build@sha256:abc" {
+		t.Errorf("unexpected promotedFrom %+v", deployment.PromotedFrom)
+	}
+	if deployment.ImageDigest != "sha256:def" {
+		t.Errorf("expected image digest sha256:def, got %q", deployment.ImageDigest)
+	}
+	if deployment.GitRepository == nil || deployment.GitRepository.CommitSHA != "abc123def456" {
+		t.Errorf("expected the commit to be carried over, got %+v", deployment.GitRepository)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"net/url"
+	"unicode/utf8"
+
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	maxCommitMessageLength = 4096
+	maxCommitAuthorLength  = 256
+)
+
+// DeploymentSource describes the change a deployment ships, for human-readable release notes
+type DeploymentSource struct {
+	CommitMessage  string `json:"commitMessage,omitempty" example:"Fix login redirect loop"`
+	CommitAuthor   string `json:"commitAuthor,omitempty" example:"Jane Doe <jane@example.com>"`
+	PullRequestURL string `json:"pullRequestUrl,omitempty" example:"https://github.com/myorg/myapp/pull/42"`
+}
+
+// validateDeploymentSource validates the source metadata of a deployment
+func validateDeploymentSource(source *DeploymentSource) []ValidationError {
+	var validationErrors []ValidationError
+
+	if utf8.RuneCountInString(source.CommitMessage) > maxCommitMessageLength {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "source.commitMessage",
+			Message: "Commit message must be at most 4096 characters",
+		})
+	}
+	if utf8.RuneCountInString(source.CommitAuthor) > maxCommitAuthorLength {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "source.commitAuthor",
+			Message: "Commit author must be at most 256 characters",
+		})
+	}
+	if source.PullRequestURL != "" {
+		u, err := url.Parse(source.PullRequestURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "source.pullRequestUrl",
+				Message: "Pull request URL must be an absolute http or https URL",
+			})
+		}
+	}
+
+	return validationErrors
+}
+
+// SetAnnotations stores the source metadata in the annotations of a Deployment CRD
+func (s *DeploymentSource) SetAnnotations(annotations map[string]string) {
+	if s == nil {
+		return
+	}
+	if s.CommitMessage != "" {
+		annotations[validation.AnnotationCommitMessage] = s.CommitMessage
+	}
+	if s.CommitAuthor != "" {
+		annotations[validation.AnnotationCommitAuthor] = s.CommitAuthor
+	}
+	if s.PullRequestURL != "" {
+		annotations[validation.AnnotationPullRequestURL] = s.PullRequestURL
+	}
+}
+
+// DeploymentSourceFromAnnotations reads the source metadata of a Deployment CRD, nil when it has none
+func DeploymentSourceFromAnnotations(annotations map[string]string) *DeploymentSource {
+	source := &DeploymentSource{
+		CommitMessage:  annotations[validation.AnnotationCommitMessage],
+		CommitAuthor:   annotations[validation.AnnotationCommitAuthor],
+		PullRequestURL: annotations[validation.AnnotationPullRequestURL],
+	}
+	if *source == (DeploymentSource{}) {
+		return nil
+	}
+	return source
+}
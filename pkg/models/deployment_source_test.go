@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestDeploymentCreateRequestValidateSource(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      *DeploymentSource
+		expectField string
+	}{
+		{
+			name: "no source",
+		},
+		{
+			name: "full source",
+			source: &DeploymentSource{
+				CommitMessage:  "Fix login redirect loop\n\nThe session cookie was dropped on redirect.",
+				CommitAuthor:   "Jane Doe <jane@example.com>",
+				PullRequestURL: "https://github.com/myorg/myapp/pull/42",
+			},
+		},
+		{
+			name:        "commit message too long",
+			source:      &DeploymentSource{CommitMessage: strings.Repeat("a", 4097)},
+			expectField: "source.commitMessage",
+		},
+		{
+			name:        "commit author too long",
+			source:      &DeploymentSource{CommitAuthor: strings.Repeat("a", 257)},
+			expectField: "source.commitAuthor",
+		},
+		{
+			name:        "relative pull request URL",
+			source:      &DeploymentSource{PullRequestURL: "myorg/myapp/pull/42"},
+			expectField: "source.pullRequestUrl",
+		},
+		{
+			name:        "non http pull request URL",
+			source:      &DeploymentSource{PullRequestURL: "javascript:alert(1)"},
+			expectField: "source.pullRequestUrl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := DeploymentCreateRequest{
+				ApplicationUUID: "550e8400-e29b-41d4-a716-446655440001",
+				Source:          tt.source,
+			}
+			errs := req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
+
+func TestDeploymentSourceAnnotations(t *testing.T) {
+	source := &DeploymentSource{
+		CommitMessage:  "Fix login redirect loop",
+		CommitAuthor:   "Jane Doe <jane@example.com>",
+		PullRequestURL: "https://github.com/myorg/myapp/pull/42",
+	}
+
+	crd := &v1alpha1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{validation.AnnotationResourceName: "Deployment for api"},
+		},
+	}
+	source.SetAnnotations(crd.Annotations)
+
+	if crd.Annotations[validation.AnnotationCommitMessage] != "Fix login redirect loop" {
+		t.Errorf("expected commit message annotation, got %v", crd.Annotations)
+	}
+
+	deployment := &Deployment{}
+	deployment.ConvertFromCRD(crd, "abc123de")
+	if deployment.Source == nil || *deployment.Source != *source {
+		t.Errorf("expected source %+v, got %+v", source, deployment.Source)
+	}
+
+	if got := DeploymentSourceFromAnnotations(map[string]string{validation.AnnotationResourceName: "Deployment for api"}); got != nil {
+		t.Errorf("expected no source, got %+v", got)
+	}
+
+	// A nil source leaves the annotations untouched
+	var empty *DeploymentSource
+	annotations := map[string]string{}
+	empty.SetAnnotations(annotations)
+	if len(annotations) != 0 {
+		t.Errorf("expected no annotations, got %v", annotations)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// DomainBackendProtocolRequest sets the protocol the application speaks behind an application domain
+type DomainBackendProtocolRequest struct {
+	BackendProtocol string `json:"backendProtocol" example:"grpc" validate:"required"`
+}
+
+// Validate validates the backend protocol
+func (req *DomainBackendProtocolRequest) Validate() *ValidationErrors {
+	switch v1alpha1.BackendProtocol(req.BackendProtocol) {
+	case v1alpha1.BackendProtocolHTTP1, v1alpha1.BackendProtocolGRPC, v1alpha1.BackendProtocolWebSocket:
+		return nil
+	}
+
+	return &ValidationErrors{
+		Errors: []ValidationError{{
+			Field: "backendProtocol",
+			Message: fmt.Sprintf("backendProtocol must be one of %s, %s or %s",
+				v1alpha1.BackendProtocolHTTP1, v1alpha1.BackendProtocolGRPC, v1alpha1.BackendProtocolWebSocket),
+		}},
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "testing"
+
+func TestDomainBackendProtocolRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     DomainBackendProtocolRequest
+		wantErr bool
+	}{
+		{name: "http1", req: DomainBackendProtocolRequest{BackendProtocol: "http1"}},
+		{name: "grpc", req: DomainBackendProtocolRequest{BackendProtocol: "grpc"}},
+		{name: "websocket", req: DomainBackendProtocolRequest{BackendProtocol: "websocket"}},
+		{name: "empty", req: DomainBackendProtocolRequest{}, wantErr: true},
+		{name: "unknown protocol", req: DomainBackendProtocolRequest{BackendProtocol: "http2"}, wantErr: true},
+		{name: "case sensitive", req: DomainBackendProtocolRequest{BackendProtocol: "GRPC"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			if !tt.wantErr {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil || errs.Errors[0].Field != "backendProtocol" {
+				t.Errorf("expected error on backendProtocol, got %v", errs)
+			}
+		})
+	}
+}
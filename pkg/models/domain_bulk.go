@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxBulkDomains bounds the domains imported by one bulk request
+const MaxBulkDomains = 100
+
+// Statuses of the domains of a bulk import
+const (
+	BulkDomainCreated   = "created"
+	BulkDomainInvalid   = "invalid"
+	BulkDomainDuplicate = "duplicate"
+	BulkDomainConflict  = "conflict"
+	BulkDomainFailed    = "failed"
+	// BulkDomainSkipped marks valid domains an atomic import did not create, or rolled back,
+	// because another domain of the request failed
+	BulkDomainSkipped = "skipped"
+)
+
+// ApplicationDomainBulkCreateRequest imports several domains of an application sharing their
+// port and TLS settings. Type defaults to custom, imported domains are usually owned by the
+// customer. Atomic imports create every domain or none, otherwise the valid domains are created
+// and the others reported.
+type ApplicationDomainBulkCreateRequest struct {
+	Domains         []string              `json:"domains" example:"shop.example.com,www.shop.example.com"`
+	Port            int32                 `json:"port" example:"3000"`
+	Type            ApplicationDomainType `json:"type,omitempty" example:"custom"`
+	TLSEnabled      bool                  `json:"tlsEnabled" example:"true"`
+	IgnoreTLSPolicy bool                  `json:"ignoreTLSPolicy,omitempty" example:"false"`
+	Atomic          bool                  `json:"atomic" example:"true"`
+}
+
+// ApplicationDomainBulkResult is the outcome of one domain of a bulk import
+type ApplicationDomainBulkResult struct {
+	Domain            string                     `json:"domain" example:"shop.example.com"`
+	Status            string                     `json:"status" example:"created"`
+	Message           string                     `json:"message,omitempty" example:"domain is already used by another application domain"`
+	ApplicationDomain *ApplicationDomainResponse `json:"applicationDomain,omitempty"`
+}
+
+// ApplicationDomainBulkCreateResponse reports the outcome of every domain of a bulk import, in
+// the order of the request
+type ApplicationDomainBulkCreateResponse struct {
+	Created int                           `json:"created" example:"2"`
+	Failed  int                           `json:"failed" example:"0"`
+	Results []ApplicationDomainBulkResult `json:"results"`
+}
+
+// Validate validates the settings shared by the domains, the domains themselves are checked one
+// by one by CheckDomains
+func (req *ApplicationDomainBulkCreateRequest) Validate() *ValidationErrors {
+	var validationErrors []ValidationError
+
+	if len(req.Domains) == 0 {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "domains",
+			Message: "At least one domain is required",
+		})
+	} else if len(req.Domains) > MaxBulkDomains {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "domains",
+			Message: fmt.Sprintf("At most %d domains can be imported at once", MaxBulkDomains),
+		})
+	}
+
+	if req.Port < 1 || req.Port > 65535 {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "port",
+			Message: "Port must be between 1 and 65535",
+		})
+	}
+
+	if req.Type != "" && req.Type != ApplicationDomainTypeDefault && req.Type != ApplicationDomainTypeCustom {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "type",
+			Message: "Type must be either 'default' or 'custom'",
+		})
+	}
+
+	if len(validationErrors) > 0 {
+		return &ValidationErrors{
+			Errors: validationErrors,
+		}
+	}
+
+	return nil
+}
+
+// DomainType returns the type of the imported domains
+func (req *ApplicationDomainBulkCreateRequest) DomainType() ApplicationDomainType {
+	if req.Type == "" {
+		return ApplicationDomainTypeCustom
+	}
+	return req.Type
+}
+
+// CheckDomains returns a result per domain of the request, in order, with the domains lowercased.
+// Malformed and repeated domains are marked invalid and duplicate, the status of the others is
+// left empty.
+func (req *ApplicationDomainBulkCreateRequest) CheckDomains() []ApplicationDomainBulkResult {
+	results := make([]ApplicationDomainBulkResult, len(req.Domains))
+	seen := make(map[string]bool, len(req.Domains))
+	for i, domain := range req.Domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		results[i].Domain = domain
+
+		switch {
+		case domain == "" || len(domain) > 253 || !isValidDomain(domain):
+			results[i].Status = BulkDomainInvalid
+			results[i].Message = "domain must be a valid domain name"
+		case seen[domain]:
+			results[i].Status = BulkDomainDuplicate
+			results[i].Message = "domain is listed more than once"
+		}
+		seen[domain] = true
+	}
+	return results
+}
+
+// NewApplicationDomainBulkCreateResponse counts the created and failed domains of results
+func NewApplicationDomainBulkCreateResponse(results []ApplicationDomainBulkResult) *ApplicationDomainBulkCreateResponse {
+	response := &ApplicationDomainBulkCreateResponse{Results: results}
+	for _, result := range results {
+		if result.Status == BulkDomainCreated {
+			response.Created++
+		} else {
+			response.Failed++
+		}
+	}
+	return response
+}
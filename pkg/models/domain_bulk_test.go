@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestApplicationDomainBulkCreateRequestValidate(t *testing.T) {
+	tooMany := make([]string, MaxBulkDomains+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("shop-%d.example.com", i)
+	}
+
+	tests := []struct {
+		name        string
+		req         ApplicationDomainBulkCreateRequest
+		expectField string
+	}{
+		{
+			name: "custom domains",
+			req:  ApplicationDomainBulkCreateRequest{Domains: []string{"shop.example.com", "www.shop.example.com"}, Port: 3000, TLSEnabled: true},
+		},
+		{
+			name:        "no domains",
+			req:         ApplicationDomainBulkCreateRequest{Port: 3000},
+			expectField: "domains",
+		},
+		{
+			name:        "too many domains",
+			req:         ApplicationDomainBulkCreateRequest{Domains: tooMany, Port: 3000},
+			expectField: "domains",
+		},
+		{
+			name:        "missing port",
+			req:         ApplicationDomainBulkCreateRequest{Domains: []string{"shop.example.com"}},
+			expectField: "port",
+		},
+		{
+			name:        "unknown type",
+			req:         ApplicationDomainBulkCreateRequest{Domains: []string{"shop.example.com"}, Port: 3000, Type: "wildcard"},
+			expectField: "type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
+
+func TestApplicationDomainBulkCreateRequestCheckDomains(t *testing.T) {
+	req := ApplicationDomainBulkCreateRequest{Domains: []string{
+		" Shop.Example.com ",
+		"shop.example.com",
+		"shop_example.com",
+		"",
+		"api.example.com",
+	}}
+
+	expected := []ApplicationDomainBulkResult{
+		{Domain: "shop.example.com"},
+		{Domain: "shop.example.com", Status: BulkDomainDuplicate, Message: "domain is listed more than once"},
+		{Domain: "shop_example.com", Status: BulkDomainInvalid, Message: "domain must be a valid domain name"},
+		{Domain: "", Status: BulkDomainInvalid, Message: "domain must be a valid domain name"},
+		{Domain: "api.example.com"},
+	}
+
+	results := req.CheckDomains()
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+	for i := range expected {
+		if results[i] != expected[i] {
+			t.Errorf("result %d: expected %+v, got %+v", i, expected[i], results[i])
+		}
+	}
+
+	if req.DomainType() != ApplicationDomainTypeCustom {
+		t.Errorf("expected imported domains to default to custom, got %s", req.DomainType())
+	}
+
+	response := NewApplicationDomainBulkCreateResponse([]ApplicationDomainBulkResult{
+		{Domain: "shop.example.com", Status: BulkDomainCreated},
+		{Domain: "api.example.com", Status: BulkDomainConflict},
+	})
+	if response.Created != 1 || response.Failed != 1 {
+		t.Errorf("expected 1 created and 1 failed domain, got %d and %d", response.Created, response.Failed)
+	}
+}
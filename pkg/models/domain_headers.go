@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// SecurityHeaders configures security headers added to every response of an application domain
+type SecurityHeaders struct {
+	HSTSMaxAgeSeconds     int64  `json:"hstsMaxAgeSeconds,omitempty" example:"31536000"`
+	HSTSIncludeSubdomains bool   `json:"hstsIncludeSubdomains,omitempty" example:"true"`
+	HSTSPreload           bool   `json:"hstsPreload,omitempty" example:"false"`
+	ContentSecurityPolicy string `json:"contentSecurityPolicy,omitempty" example:"default-src 'self'"`
+	FrameOptions          string `json:"frameOptions,omitempty" example:"DENY"`
+	ContentTypeNosniff    bool   `json:"contentTypeNosniff,omitempty" example:"true"`
+	ReferrerPolicy        string `json:"referrerPolicy,omitempty" example:"strict-origin-when-cross-origin"`
+}
+
+// CORSPolicy configures the cross-origin requests browsers allow to an application domain
+type CORSPolicy struct {
+	AllowOrigins     []string `json:"allowOrigins" example:"https://app.example.com"`
+	AllowMethods     []string `json:"allowMethods,omitempty" example:"GET,POST"`
+	AllowHeaders     []string `json:"allowHeaders,omitempty" example:"Authorization,Content-Type"`
+	ExposeHeaders    []string `json:"exposeHeaders,omitempty" example:"X-Request-Id"`
+	AllowCredentials bool     `json:"allowCredentials,omitempty" example:"true"`
+	MaxAgeSeconds    int32    `json:"maxAgeSeconds,omitempty" example:"600"`
+}
+
+// DomainHeadersRequest replaces the security headers and CORS policy of an application domain,
+// omitted sections are removed
+type DomainHeadersRequest struct {
+	SecurityHeaders *SecurityHeaders `json:"securityHeaders,omitempty"`
+	CORS            *CORSPolicy      `json:"cors,omitempty"`
+}
+
+// Validate validates the security headers and CORS policy
+func (req *DomainHeadersRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	if req.SecurityHeaders == nil && req.CORS == nil {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "securityHeaders",
+			Message: "at least one of securityHeaders or cors must be provided",
+		})
+	}
+
+	if req.SecurityHeaders != nil {
+		if err := v1alpha1.ValidateSecurityHeaders(req.SecurityHeaders.ToCRD()); err != nil {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   "securityHeaders",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if req.CORS != nil {
+		if err := v1alpha1.ValidateCORSPolicy(req.CORS.ToCRD()); err != nil {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   "cors",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// ToCRD converts the security headers to their ApplicationDomain CRD form
+func (h *SecurityHeaders) ToCRD() *v1alpha1.SecurityHeaders {
+	if h == nil {
+		return nil
+	}
+	return &v1alpha1.SecurityHeaders{
+		HSTSMaxAgeSeconds:     h.HSTSMaxAgeSeconds,
+		HSTSIncludeSubdomains: h.HSTSIncludeSubdomains,
+		HSTSPreload:           h.HSTSPreload,
+		ContentSecurityPolicy: h.ContentSecurityPolicy,
+		FrameOptions:          h.FrameOptions,
+		ContentTypeNosniff:    h.ContentTypeNosniff,
+		ReferrerPolicy:        h.ReferrerPolicy,
+	}
+}
+
+// ToCRD converts the CORS policy to its ApplicationDomain CRD form
+func (c *CORSPolicy) ToCRD() *v1alpha1.CORSPolicy {
+	if c == nil {
+		return nil
+	}
+	return &v1alpha1.CORSPolicy{
+		AllowOrigins:     c.AllowOrigins,
+		AllowMethods:     c.AllowMethods,
+		AllowHeaders:     c.AllowHeaders,
+		ExposeHeaders:    c.ExposeHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAgeSeconds:    c.MaxAgeSeconds,
+	}
+}
+
+// securityHeadersFromCRD converts the security headers of an ApplicationDomain CRD
+func securityHeadersFromCRD(h *v1alpha1.SecurityHeaders) *SecurityHeaders {
+	if h == nil {
+		return nil
+	}
+	return &SecurityHeaders{
+		HSTSMaxAgeSeconds:     h.HSTSMaxAgeSeconds,
+		HSTSIncludeSubdomains: h.HSTSIncludeSubdomains,
+		HSTSPreload:           h.HSTSPreload,
+		ContentSecurityPolicy: h.ContentSecurityPolicy,
+		FrameOptions:          h.FrameOptions,
+		ContentTypeNosniff:    h.ContentTypeNosniff,
+		ReferrerPolicy:        h.ReferrerPolicy,
+	}
+}
+
+// corsPolicyFromCRD converts the CORS policy of an ApplicationDomain CRD
+func corsPolicyFromCRD(c *v1alpha1.CORSPolicy) *CORSPolicy {
+	if c == nil {
+		return nil
+	}
+	return &CORSPolicy{
+		AllowOrigins:     c.AllowOrigins,
+		AllowMethods:     c.AllowMethods,
+		AllowHeaders:     c.AllowHeaders,
+		ExposeHeaders:    c.ExposeHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAgeSeconds:    c.MaxAgeSeconds,
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "testing"
+
+func TestDomainHeadersRequestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         DomainHeadersRequest
+		expectField string
+	}{
+		{
+			name: "security headers",
+			req: DomainHeadersRequest{SecurityHeaders: &SecurityHeaders{
+				HSTSMaxAgeSeconds:     31536000,
+				HSTSIncludeSubdomains: true,
+				ContentSecurityPolicy: "default-src 'self'; img-src *",
+				FrameOptions:          "SAMEORIGIN",
+				ContentTypeNosniff:    true,
+				ReferrerPolicy:        "no-referrer",
+			}},
+		},
+		{
+			name: "cors policy",
+			req: DomainHeadersRequest{CORS: &CORSPolicy{
+				AllowOrigins:     []string{"https://app.example.com", "http://localhost:3000"},
+				AllowMethods:     []string{"GET", "POST", "DELETE"},
+				AllowHeaders:     []string{"Authorization", "Content-Type"},
+				AllowCredentials: true,
+				MaxAgeSeconds:    600,
+			}},
+		},
+		{
+			name:        "empty request",
+			req:         DomainHeadersRequest{},
+			expectField: "securityHeaders",
+		},
+		{
+			name:        "invalid frame options",
+			req:         DomainHeadersRequest{SecurityHeaders: &SecurityHeaders{FrameOptions: "ALLOW-FROM https://example.com"}},
+			expectField: "securityHeaders",
+		},
+		{
+			name:        "quoted content security policy",
+			req:         DomainHeadersRequest{SecurityHeaders: &SecurityHeaders{ContentSecurityPolicy: `default-src "self"`}},
+			expectField: "securityHeaders",
+		},
+		{
+			name:        "invalid referrer policy",
+			req:         DomainHeadersRequest{SecurityHeaders: &SecurityHeaders{ReferrerPolicy: "everywhere"}},
+			expectField: "securityHeaders",
+		},
+		{
+			name:        "no origins",
+			req:         DomainHeadersRequest{CORS: &CORSPolicy{}},
+			expectField: "cors",
+		},
+		{
+			name:        "origin with path",
+			req:         DomainHeadersRequest{CORS: &CORSPolicy{AllowOrigins: []string{"https://app.example.com/login"}}},
+			expectField: "cors",
+		},
+		{
+			name:        "credentials for any origin",
+			req:         DomainHeadersRequest{CORS: &CORSPolicy{AllowOrigins: []string{"*"}, AllowCredentials: true}},
+			expectField: "cors",
+		},
+		{
+			name:        "unsupported method",
+			req:         DomainHeadersRequest{CORS: &CORSPolicy{AllowOrigins: []string{"*"}, AllowMethods: []string{"TRACE"}}},
+			expectField: "cors",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
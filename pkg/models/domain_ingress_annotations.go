@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// DomainIngressAnnotationsRequest replaces the annotations an application domain adds to its Ingress
+type DomainIngressAnnotationsRequest struct {
+	Annotations map[string]string `json:"annotations" validate:"required"`
+}
+
+// Validate validates the ingress annotations
+func (req *DomainIngressAnnotationsRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	if len(req.Annotations) == 0 {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "annotations",
+			Message: "at least one annotation must be provided",
+		})
+	} else if err := v1alpha1.ValidateIngressAnnotations(req.Annotations); err != nil {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "annotations",
+			Message: err.Error(),
+		})
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDomainIngressAnnotationsRequestValidate(t *testing.T) {
+	tooMany := map[string]string{}
+	for i := 0; i <= 50; i++ {
+		tooMany[fmt.Sprintf("nginx.ingress.kubernetes.io/annotation-%d", i)] = "1"
+	}
+
+	tests := []struct {
+		name        string
+		req         DomainIngressAnnotationsRequest
+		expectField string
+	}{
+		{
+			name: "nginx and traefik annotations",
+			req: DomainIngressAnnotationsRequest{Annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/proxy-body-size":         "64m",
+				"nginx.ingress.kubernetes.io/proxy-read-timeout":      "300",
+				"traefik.ingress.kubernetes.io/service.sticky.cookie": "true",
+			}},
+		},
+		{
+			name:        "empty request",
+			req:         DomainIngressAnnotationsRequest{},
+			expectField: "annotations",
+		},
+		{
+			name:        "other prefix",
+			req:         DomainIngressAnnotationsRequest{Annotations: map[string]string{"kubernetes.io/ingress.class": "nginx"}},
+			expectField: "annotations",
+		},
+		{
+			name:        "snippet",
+			req:         DomainIngressAnnotationsRequest{Annotations: map[string]string{"nginx.ingress.kubernetes.io/auth-snippet": "return 200;"}},
+			expectField: "annotations",
+		},
+		{
+			name:        "too many annotations",
+			req:         DomainIngressAnnotationsRequest{Annotations: tooMany},
+			expectField: "annotations",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// DomainProxySettings tunes the request body size limit and proxy timeouts of an application domain
+type DomainProxySettings struct {
+	MaxBodySize      string `json:"maxBodySize,omitempty" example:"64Mi"`
+	ProxyReadTimeout string `json:"proxyReadTimeout,omitempty" example:"5m0s"`
+	ProxySendTimeout string `json:"proxySendTimeout,omitempty" example:"5m0s"`
+}
+
+// Validate validates the proxy settings
+func (s *DomainProxySettings) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	if s.MaxBodySize == "" && s.ProxyReadTimeout == "" && s.ProxySendTimeout == "" {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "maxBodySize",
+			Message: "at least one of maxBodySize, proxyReadTimeout or proxySendTimeout must be provided",
+		})
+		return errors
+	}
+
+	maxBodySize, readTimeout, sendTimeout, err := s.ToCRD()
+	if err != nil {
+		errors.Errors = append(errors.Errors, *err)
+		return errors
+	}
+
+	if err := v1alpha1.ValidateProxySettings(maxBodySize, nil, nil); err != nil {
+		errors.Errors = append(errors.Errors, ValidationError{Field: "maxBodySize", Message: err.Error()})
+	}
+	if err := v1alpha1.ValidateProxySettings(nil, readTimeout, nil); err != nil {
+		errors.Errors = append(errors.Errors, ValidationError{Field: "proxyReadTimeout", Message: err.Error()})
+	}
+	if err := v1alpha1.ValidateProxySettings(nil, nil, sendTimeout); err != nil {
+		errors.Errors = append(errors.Errors, ValidationError{Field: "proxySendTimeout", Message: err.Error()})
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// ToCRD parses the proxy settings into the ApplicationDomain spec fields, unset settings are nil
+func (s *DomainProxySettings) ToCRD() (*resource.Quantity, *metav1.Duration, *metav1.Duration, *ValidationError) {
+	var maxBodySize *resource.Quantity
+	if s.MaxBodySize != "" {
+		quantity, err := resource.ParseQuantity(s.MaxBodySize)
+		if err != nil {
+			return nil, nil, nil, &ValidationError{Field: "maxBodySize", Message: "maxBodySize must be a quantity such as 64Mi"}
+		}
+		maxBodySize = &quantity
+	}
+
+	readTimeout, err := parseProxyTimeout("proxyReadTimeout", s.ProxyReadTimeout)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sendTimeout, err := parseProxyTimeout("proxySendTimeout", s.ProxySendTimeout)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return maxBodySize, readTimeout, sendTimeout, nil
+}
+
+func parseProxyTimeout(field, value string) (*metav1.Duration, *ValidationError) {
+	if value == "" {
+		return nil, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, &ValidationError{Field: field, Message: fmt.Sprintf("%s must be a Go duration such as 5m", field)}
+	}
+	return &metav1.Duration{Duration: timeout}, nil
+}
+
+// domainProxySettingsFromCRD converts the proxy settings of an ApplicationDomain CRD
+func domainProxySettingsFromCRD(spec *v1alpha1.ApplicationDomainSpec) *DomainProxySettings {
+	if !spec.HasProxySettings() {
+		return nil
+	}
+
+	settings := &DomainProxySettings{}
+	if spec.MaxBodySize != nil {
+		settings.MaxBodySize = spec.MaxBodySize.String()
+	}
+	if spec.ProxyReadTimeout != nil {
+		settings.ProxyReadTimeout = spec.ProxyReadTimeout.Duration.String()
+	}
+	if spec.ProxySendTimeout != nil {
+		settings.ProxySendTimeout = spec.ProxySendTimeout.Duration.String()
+	}
+	return settings
+}
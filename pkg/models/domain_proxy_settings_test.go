@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "testing"
+
+func TestDomainProxySettingsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    DomainProxySettings
+		expectField string
+	}{
+		{
+			name:     "all settings",
+			settings: DomainProxySettings{MaxBodySize: "64Mi", ProxyReadTimeout: "5m", ProxySendTimeout: "300s"},
+		},
+		{
+			name:     "body size only",
+			settings: DomainProxySettings{MaxBodySize: "100M"},
+		},
+		{
+			name:        "empty request",
+			settings:    DomainProxySettings{},
+			expectField: "maxBodySize",
+		},
+		{
+			name:        "invalid quantity",
+			settings:    DomainProxySettings{MaxBodySize: "64 megabytes"},
+			expectField: "maxBodySize",
+		},
+		{
+			name:        "body size too large",
+			settings:    DomainProxySettings{MaxBodySize: "10Gi"},
+			expectField: "maxBodySize",
+		},
+		{
+			name:        "invalid duration",
+			settings:    DomainProxySettings{ProxyReadTimeout: "300"},
+			expectField: "proxyReadTimeout",
+		},
+		{
+			name:        "send timeout too long",
+			settings:    DomainProxySettings{ProxySendTimeout: "2h"},
+			expectField: "proxySendTimeout",
+		},
+		{
+			name:        "fractional seconds",
+			settings:    DomainProxySettings{ProxySendTimeout: "1.5s"},
+			expectField: "proxySendTimeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.settings.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
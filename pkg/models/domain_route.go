@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// routePathPattern matches the path prefixes accepted by the ApplicationDomain CRD
+var routePathPattern = regexp.MustCompile(`^/[A-Za-z0-9._~/-]*$`)
+
+// DomainRoute routes a path prefix of an application domain to another application of the project
+type DomainRoute struct {
+	Path            string `json:"path" example:"/api"`
+	ApplicationUUID string `json:"applicationUuid" example:"550e8400-e29b-41d4-a716-446655440003"`
+	Port            int32  `json:"port,omitempty" example:"8080"`
+}
+
+// DomainRoutesRequest replaces the path routes of an application domain
+type DomainRoutesRequest struct {
+	Routes []DomainRoute `json:"routes"`
+}
+
+// Validate validates the path routes
+func (req *DomainRoutesRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	seen := make(map[string]bool, len(req.Routes))
+	for i, route := range req.Routes {
+		field := fmt.Sprintf("routes[%d]", i)
+
+		path := v1alpha1.NormalizeRoutePath(route.Path)
+		switch {
+		case !routePathPattern.MatchString(route.Path) || strings.Contains(route.Path, "//"):
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".path",
+				Message: "path must start with / and contain only letters, digits and . _ ~ - / characters",
+			})
+		case path == "/":
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".path",
+				Message: "path / is served by the application of the domain",
+			})
+		case seen[path]:
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".path",
+				Message: fmt.Sprintf("path %s is routed more than once", path),
+			})
+		}
+		seen[path] = true
+
+		if !validation.ValidateUUID(route.ApplicationUUID) {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".applicationUuid",
+				Message: "applicationUuid must be a valid application UUID",
+			})
+		}
+
+		if route.Port < 0 || route.Port > 65535 {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".port",
+				Message: "port must be between 1 and 65535",
+			})
+		}
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// domainRoutesFromCRD converts the path routes of an ApplicationDomain CRD
+func domainRoutesFromCRD(routes []v1alpha1.DomainRoute) []DomainRoute {
+	if len(routes) == 0 {
+		return nil
+	}
+	out := make([]DomainRoute, 0, len(routes))
+	for _, route := range routes {
+		out = append(out, DomainRoute{
+			Path:            route.Path,
+			ApplicationUUID: strings.TrimPrefix(route.ApplicationRef.Name, utils.GetApplicationResourceName("")),
+			Port:            route.Port,
+		})
+	}
+	return out
+}
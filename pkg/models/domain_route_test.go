@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "testing"
+
+func TestDomainRoutesRequestValidate(t *testing.T) {
+	const backend = "550e8400-e29b-41d4-a716-446655440003"
+	const docs = "550e8400-e29b-41d4-a716-446655440004"
+
+	tests := []struct {
+		name        string
+		req         DomainRoutesRequest
+		expectField string
+	}{
+		{
+			name: "no routes",
+			req:  DomainRoutesRequest{},
+		},
+		{
+			name: "routes to several applications",
+			req: DomainRoutesRequest{Routes: []DomainRoute{
+				{Path: "/api", ApplicationUUID: backend, Port: 8080},
+				{Path: "/docs/", ApplicationUUID: docs},
+			}},
+		},
+		{
+			name:        "relative path",
+			req:         DomainRoutesRequest{Routes: []DomainRoute{{Path: "api", ApplicationUUID: backend}}},
+			expectField: "routes[0].path",
+		},
+		{
+			name:        "empty segment",
+			req:         DomainRoutesRequest{Routes: []DomainRoute{{Path: "/api//v1", ApplicationUUID: backend}}},
+			expectField: "routes[0].path",
+		},
+		{
+			name:        "root path",
+			req:         DomainRoutesRequest{Routes: []DomainRoute{{Path: "/", ApplicationUUID: backend}}},
+			expectField: "routes[0].path",
+		},
+		{
+			name: "same path to different applications",
+			req: DomainRoutesRequest{Routes: []DomainRoute{
+				{Path: "/api", ApplicationUUID: backend},
+				{Path: "/api/", ApplicationUUID: docs},
+			}},
+			expectField: "routes[1].path",
+		},
+		{
+			name:        "invalid application UUID",
+			req:         DomainRoutesRequest{Routes: []DomainRoute{{Path: "/api", ApplicationUUID: "backend"}}},
+			expectField: "routes[0].applicationUuid",
+		},
+		{
+			name:        "invalid port",
+			req:         DomainRoutesRequest{Routes: []DomainRoute{{Path: "/api", ApplicationUUID: backend, Port: 70000}}},
+			expectField: "routes[0].port",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
@@ -66,34 +66,57 @@ func (r *EnvironmentCreateRequest) Validate() *ValidationErrors {
 	return nil
 }
 
+// EnvironmentIdlePolicy scales the applications of a non-production environment to zero after a
+// period without requests
+type EnvironmentIdlePolicy struct {
+	Enabled   bool   `json:"enabled" example:"true"`
+	IdleAfter string `json:"idleAfter,omitempty" example:"30m"`
+}
+
 // EnvironmentUpdateRequest represents the request to update an environment
 type EnvironmentUpdateRequest struct {
-	Description *string            `json:"description,omitempty" example:"Updated production environment"`
-	Variables   *map[string]string `json:"variables,omitempty"`
+	Description *string                `json:"description,omitempty" example:"Updated production environment"`
+	Variables   *map[string]string     `json:"variables,omitempty"`
+	IdlePolicy  *EnvironmentIdlePolicy `json:"idlePolicy,omitempty"`
+	// RequireApproval holds successful deployments back until they are promoted explicitly
+	RequireApproval *bool `json:"requireApproval,omitempty" example:"true"`
 }
 
 // Validate validates the environment update request
 func (r *EnvironmentUpdateRequest) Validate() error {
 	// At least one field must be provided
-	if r.Description == nil && r.Variables == nil {
+	if r.Description == nil && r.Variables == nil && r.IdlePolicy == nil && r.RequireApproval == nil {
 		return fmt.Errorf("at least one field must be provided for update")
 	}
 
+	if r.IdlePolicy != nil && r.IdlePolicy.IdleAfter != "" {
+		idleAfter, err := time.ParseDuration(r.IdlePolicy.IdleAfter)
+		if err != nil {
+			return fmt.Errorf("idlePolicy.idleAfter must be a Go duration such as 30m: %v", err)
+		}
+		if idleAfter < time.Minute {
+			return fmt.Errorf("idlePolicy.idleAfter must be at least 1m")
+		}
+	}
+
 	return nil
 }
 
 // Environment represents an environment in the system
 type Environment struct {
-	UUID             string            `json:"uuid"`
-	Name             string            `json:"name"`
-	Slug             string            `json:"slug"`
-	Description      string            `json:"description,omitempty"`
-	Variables        map[string]string `json:"variables,omitempty"`
-	ProjectUUID      string            `json:"projectUuid"`
-	ProjectSlug      string            `json:"projectSlug"`
-	ApplicationCount int32             `json:"applicationCount"`
-	CreatedAt        time.Time         `json:"createdAt"`
-	UpdatedAt        time.Time         `json:"updatedAt"`
+	UUID             string                 `json:"uuid"`
+	Name             string                 `json:"name"`
+	Slug             string                 `json:"slug"`
+	Description      string                 `json:"description,omitempty"`
+	Variables        map[string]string      `json:"variables,omitempty"`
+	ProjectUUID      string                 `json:"projectUuid"`
+	ProjectSlug      string                 `json:"projectSlug"`
+	ApplicationCount int32                  `json:"applicationCount"`
+	IdlePolicy       *EnvironmentIdlePolicy `json:"idlePolicy,omitempty"`
+	RequireApproval  bool                   `json:"requireApproval"`
+	PullRequestURL   string                 `json:"pullRequestUrl,omitempty"`
+	CreatedAt        time.Time              `json:"createdAt"`
+	UpdatedAt        time.Time              `json:"updatedAt"`
 }
 
 // NewEnvironment creates a new Environment
@@ -112,16 +135,19 @@ func NewEnvironment(name, projectUUID, projectSlug, slug string) *Environment {
 
 // EnvironmentResponse represents an environment response
 type EnvironmentResponse struct {
-	UUID             string            `json:"uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Name             string            `json:"name" example:"production"`
-	Slug             string            `json:"slug" example:"abc123de"`
-	Description      string            `json:"description,omitempty" example:"Production environment"`
-	Variables        map[string]string `json:"variables,omitempty"`
-	ProjectUUID      string            `json:"projectUuid" example:"123e4567-e89b-12d3-a456-426614174001"`
-	ProjectSlug      string            `json:"projectSlug" example:"xyz789ab"`
-	ApplicationCount int32             `json:"applicationCount" example:"5"`
-	CreatedAt        time.Time         `json:"createdAt" example:"2023-01-01T00:00:00Z"`
-	UpdatedAt        time.Time         `json:"updatedAt" example:"2023-01-01T00:00:00Z"`
+	UUID             string                 `json:"uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name             string                 `json:"name" example:"production"`
+	Slug             string                 `json:"slug" example:"abc123de"`
+	Description      string                 `json:"description,omitempty" example:"Production environment"`
+	Variables        map[string]string      `json:"variables,omitempty"`
+	ProjectUUID      string                 `json:"projectUuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	ProjectSlug      string                 `json:"projectSlug" example:"xyz789ab"`
+	ApplicationCount int32                  `json:"applicationCount" example:"5"`
+	IdlePolicy       *EnvironmentIdlePolicy `json:"idlePolicy,omitempty"`
+	RequireApproval  bool                   `json:"requireApproval" example:"false"`
+	PullRequestURL   string                 `json:"pullRequestUrl,omitempty" example:"https://github.com/myorg/myapp/pull/42"`
+	CreatedAt        time.Time              `json:"createdAt" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt        time.Time              `json:"updatedAt" example:"2023-01-01T00:00:00Z"`
 }
 
 // ToResponse converts an Environment to EnvironmentResponse
@@ -135,6 +161,9 @@ func (e *Environment) ToResponse() *EnvironmentResponse {
 		ProjectUUID:      e.ProjectUUID,
 		ProjectSlug:      e.ProjectSlug,
 		ApplicationCount: e.ApplicationCount,
+		IdlePolicy:       e.IdlePolicy,
+		RequireApproval:  e.RequireApproval,
+		PullRequestURL:   e.PullRequestURL,
 		CreatedAt:        e.CreatedAt,
 		UpdatedAt:        e.UpdatedAt,
 	}
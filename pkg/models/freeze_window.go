@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/kibamail/kibaship/pkg/freeze"
+)
+
+var freezeWindowNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// FreezeWindow is a recurring period during which deployment promotions are blocked
+type FreezeWindow struct {
+	Name     string `json:"name" example:"weekends"`
+	Schedule string `json:"schedule" example:"0 18 * * FRI"`
+	Duration string `json:"duration" example:"62h"`
+	Timezone string `json:"timezone,omitempty" example:"Europe/Berlin"`
+	Reason   string `json:"reason,omitempty" example:"No deploys over the weekend"`
+}
+
+// FreezeWindowsUpdateRequest replaces the freeze windows of an environment
+type FreezeWindowsUpdateRequest struct {
+	Windows []FreezeWindow `json:"windows"`
+}
+
+// Validate validates the freeze windows update request
+func (r *FreezeWindowsUpdateRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	seen := make(map[string]bool, len(r.Windows))
+	for i, window := range r.Windows {
+		field := fmt.Sprintf("windows[%d]", i)
+
+		if !freezeWindowNamePattern.MatchString(window.Name) || len(window.Name) > 63 {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".name",
+				Message: "name must be a lowercase DNS label of at most 63 characters",
+			})
+		} else if seen[window.Name] {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".name",
+				Message: fmt.Sprintf("duplicate freeze window name %s", window.Name),
+			})
+		}
+		seen[window.Name] = true
+
+		if _, err := freeze.ParseSchedule(window.Schedule); err != nil {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".schedule",
+				Message: err.Error(),
+			})
+		}
+
+		duration, err := time.ParseDuration(window.Duration)
+		if err == nil {
+			err = freeze.ValidateDuration(duration)
+		}
+		if err != nil {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".duration",
+				Message: fmt.Sprintf("duration must be a positive Go duration such as 62h: %v", err),
+			})
+		}
+
+		if _, err := freeze.LoadLocation(window.Timezone); err != nil {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".timezone",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// FreezeWindowsResponse lists the freeze windows of an environment and the window active now
+type FreezeWindowsResponse struct {
+	EnvironmentUUID string         `json:"environmentUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Windows         []FreezeWindow `json:"windows"`
+	ActiveWindow    string         `json:"activeWindow,omitempty" example:"weekends"`
+	ActiveUntil     *time.Time     `json:"activeUntil,omitempty" example:"2023-01-02T08:00:00Z"`
+}
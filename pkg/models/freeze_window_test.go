@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+)
+
+func TestFreezeWindowsUpdateRequestValidate(t *testing.T) {
+	weekends := FreezeWindow{Name: "weekends", Schedule: "0 18 * * FRI", Duration: "62h", Timezone: "Europe/Berlin"}
+
+	tests := []struct {
+		name        string
+		windows     []FreezeWindow
+		expectField string
+	}{
+		{
+			name:    "valid windows",
+			windows: []FreezeWindow{weekends, {Name: "year-end", Schedule: "0 0 20 12 *", Duration: "336h"}},
+		},
+		{
+			name:    "no windows clears the freeze",
+			windows: nil,
+		},
+		{
+			name:        "invalid name",
+			windows:     []FreezeWindow{{Name: "Weekends", Schedule: "0 18 * * FRI", Duration: "62h"}},
+			expectField: "windows[0].name",
+		},
+		{
+			name:        "duplicate name",
+			windows:     []FreezeWindow{weekends, weekends},
+			expectField: "windows[1].name",
+		},
+		{
+			name:        "invalid schedule",
+			windows:     []FreezeWindow{{Name: "weekends", Schedule: "0 18 * *", Duration: "62h"}},
+			expectField: "windows[0].schedule",
+		},
+		{
+			name:        "duration too long",
+			windows:     []FreezeWindow{{Name: "weekends", Schedule: "0 18 * * FRI", Duration: "1000h"}},
+			expectField: "windows[0].duration",
+		},
+		{
+			name:        "unknown timezone",
+			windows:     []FreezeWindow{{Name: "weekends", Schedule: "0 18 * * FRI", Duration: "62h", Timezone: "Mars/Olympus"}},
+			expectField: "windows[0].timezone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &FreezeWindowsUpdateRequest{Windows: tt.windows}
+			errs := req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
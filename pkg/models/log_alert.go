@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+const (
+	// MaxLogAlertRules is the largest number of log alert rules of an application
+	MaxLogAlertRules = 20
+
+	// MaxLogAlertPatternLength is the longest supported log alert pattern
+	MaxLogAlertPatternLength = 1024
+)
+
+// LogAlertRule fires when the logs of an application match a pattern at least
+// thresholdPerMinute times in a minute
+type LogAlertRule struct {
+	Name               string `json:"name" example:"database-errors"`
+	Pattern            string `json:"pattern" example:"ECONNREFUSED|too many connections"`
+	Match              string `json:"match,omitempty" example:"Regex"`
+	ThresholdPerMinute int32  `json:"thresholdPerMinute,omitempty" example:"5"`
+}
+
+// LogAlertRulesUpdateRequest replaces the log alert rules of an application
+type LogAlertRulesUpdateRequest struct {
+	Rules []LogAlertRule `json:"rules"`
+}
+
+// Validate validates the log alert rules update request
+func (r *LogAlertRulesUpdateRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	if len(r.Rules) > MaxLogAlertRules {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "rules",
+			Message: fmt.Sprintf("at most %d log alert rules are supported", MaxLogAlertRules),
+		})
+	}
+
+	seen := make(map[string]bool, len(r.Rules))
+	for i, rule := range r.Rules {
+		field := fmt.Sprintf("rules[%d]", i)
+
+		if !freezeWindowNamePattern.MatchString(rule.Name) || len(rule.Name) > 63 {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".name",
+				Message: "name must be a lowercase DNS label of at most 63 characters",
+			})
+		} else if seen[rule.Name] {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".name",
+				Message: fmt.Sprintf("duplicate log alert name %s", rule.Name),
+			})
+		}
+		seen[rule.Name] = true
+
+		if rule.Pattern == "" || len(rule.Pattern) > MaxLogAlertPatternLength {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".pattern",
+				Message: fmt.Sprintf("pattern must be between 1 and %d characters", MaxLogAlertPatternLength),
+			})
+		}
+
+		switch v1alpha1.LogAlertMatch(rule.Match) {
+		case "", v1alpha1.LogAlertMatchSubstring:
+		case v1alpha1.LogAlertMatchRegex:
+			if _, err := regexp.Compile(rule.Pattern); err != nil {
+				errors.Errors = append(errors.Errors, ValidationError{
+					Field:   field + ".pattern",
+					Message: fmt.Sprintf("pattern must be a valid regular expression: %v", err),
+				})
+			}
+		default:
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".match",
+				Message: fmt.Sprintf("match must be '%s' or '%s'", v1alpha1.LogAlertMatchSubstring, v1alpha1.LogAlertMatchRegex),
+			})
+		}
+
+		if rule.ThresholdPerMinute < 0 {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".thresholdPerMinute",
+				Message: "thresholdPerMinute must be at least 1",
+			})
+		}
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// LogAlertRuleResponse describes a log alert rule and its current state
+type LogAlertRuleResponse struct {
+	LogAlertRule
+	State              string     `json:"state" example:"Firing"`
+	Matches            int64      `json:"matches,omitempty" example:"12"`
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty" example:"2023-01-01T12:00:00Z"`
+	LastError          string     `json:"lastError,omitempty" example:""`
+}
+
+// LogAlertRulesResponse describes the log alert rules of an application
+type LogAlertRulesResponse struct {
+	ApplicationUUID string                 `json:"applicationUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Rules           []LogAlertRuleResponse `json:"rules"`
+}
+
+// NewLogAlertRulesResponse converts the log alert rules of an Application CRD and their states
+// to the API response. Rules not evaluated yet are reported as OK.
+func NewLogAlertRulesResponse(app *v1alpha1.Application) *LogAlertRulesResponse {
+	states := make(map[string]v1alpha1.LogAlertStatus, len(app.Status.LogAlerts))
+	for _, status := range app.Status.LogAlerts {
+		states[status.Name] = status
+	}
+
+	response := &LogAlertRulesResponse{
+		ApplicationUUID: app.GetUUID(),
+		Rules:           make([]LogAlertRuleResponse, 0, len(app.Spec.LogAlerts)),
+	}
+	for _, rule := range app.Spec.LogAlerts {
+		match := rule.Match
+		if match == "" {
+			match = v1alpha1.LogAlertMatchSubstring
+		}
+		item := LogAlertRuleResponse{
+			LogAlertRule: LogAlertRule{
+				Name:               rule.Name,
+				Pattern:            rule.Pattern,
+				Match:              string(match),
+				ThresholdPerMinute: int32(rule.Threshold()),
+			},
+			State: string(v1alpha1.LogAlertStateOK),
+		}
+		if status, ok := states[rule.Name]; ok {
+			item.State = string(status.State)
+			item.Matches = status.Matches
+			item.LastError = status.LastError
+			if status.LastTransitionTime != nil {
+				item.LastTransitionTime = &status.LastTransitionTime.Time
+			}
+		}
+		response.Rules = append(response.Rules, item)
+	}
+	return response
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestLogAlertRulesUpdateRequestValidate(t *testing.T) {
+	databaseErrors := LogAlertRule{Name: "database-errors", Pattern: "ECONNREFUSED|too many connections", Match: "Regex", ThresholdPerMinute: 5}
+
+	tests := []struct {
+		name        string
+		rules       []LogAlertRule
+		expectField string
+	}{
+		{
+			name:  "valid rules",
+			rules: []LogAlertRule{databaseErrors, {Name: "panics", Pattern: "panic:"}},
+		},
+		{
+			name:  "no rules removes every alert",
+			rules: nil,
+		},
+		{
+			name:        "duplicate name",
+			rules:       []LogAlertRule{databaseErrors, databaseErrors},
+			expectField: "rules[1].name",
+		},
+		{
+			name:        "empty pattern",
+			rules:       []LogAlertRule{{Name: "panics"}},
+			expectField: "rules[0].pattern",
+		},
+		{
+			name:        "invalid regular expression",
+			rules:       []LogAlertRule{{Name: "panics", Pattern: "panic(", Match: "Regex"}},
+			expectField: "rules[0].pattern",
+		},
+		{
+			name:        "unknown match",
+			rules:       []LogAlertRule{{Name: "panics", Pattern: "panic:", Match: "glob"}},
+			expectField: "rules[0].match",
+		},
+		{
+			name:        "negative threshold",
+			rules:       []LogAlertRule{{Name: "panics", Pattern: "panic:", ThresholdPerMinute: -1}},
+			expectField: "rules[0].thresholdPerMinute",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &LogAlertRulesUpdateRequest{Rules: tt.rules}
+			errs := req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
+
+func TestNewLogAlertRulesResponse(t *testing.T) {
+	firedAt := metav1.NewTime(time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC))
+	app := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{validation.LabelResourceUUID: "app-1"}},
+		Spec: v1alpha1.ApplicationSpec{LogAlerts: []v1alpha1.LogAlertRule{
+			{Name: "database-errors", Pattern: "ECONNREFUSED", ThresholdPerMinute: 5},
+			{Name: "panics", Pattern: "panic:"},
+		}},
+		Status: v1alpha1.ApplicationStatus{LogAlerts: []v1alpha1.LogAlertStatus{{
+			Name:               "database-errors",
+			State:              v1alpha1.LogAlertStateFiring,
+			Matches:            12,
+			LastTransitionTime: &firedAt,
+		}}},
+	}
+
+	response := NewLogAlertRulesResponse(app)
+
+	if response.ApplicationUUID != "app-1" || len(response.Rules) != 2 {
+		t.Fatalf("unexpected response %+v", response)
+	}
+	if rule := response.Rules[0]; rule.State != "Firing" || rule.Matches != 12 || rule.Match != "Substring" ||
+		rule.LastTransitionTime == nil || !rule.LastTransitionTime.Equal(firedAt.Time) {
+		t.Errorf("unexpected firing rule %+v", rule)
+	}
+	if rule := response.Rules[1]; rule.State != "OK" || rule.ThresholdPerMinute != 1 {
+		t.Errorf("expected the rule not evaluated yet to be OK with the default threshold, got %+v", rule)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+// PlatformBootstrapStep is the outcome of a step of the operator bootstrap
+type PlatformBootstrapStep struct {
+	Step            int        `json:"step" example:"4"`
+	Name            string     `json:"name" example:"ingress-certificates"`
+	State           string     `json:"state" example:"Failed" enums:"Running,Succeeded,Failed"`
+	Error           string     `json:"error,omitempty" example:"no matches for kind \"ClusterIssuer\" in version \"cert-manager.io/v1\""`
+	Attempts        int        `json:"attempts" example:"3"`
+	LastAttemptTime *time.Time `json:"lastAttemptTime,omitempty" example:"2025-01-01T00:03:00Z"`
+	LastSuccessTime *time.Time `json:"lastSuccessTime,omitempty"`
+	NextRetryTime   *time.Time `json:"nextRetryTime,omitempty" example:"2025-01-01T00:07:00Z"`
+}
+
+// PlatformBootstrapResponse reports the steps the operator runs to install the platform, so a
+// failed installation can be diagnosed without the operator logs
+type PlatformBootstrapResponse struct {
+	// Ready is true once every bootstrap step succeeded
+	Ready bool                    `json:"ready" example:"false"`
+	Steps []PlatformBootstrapStep `json:"steps"`
+}
+
+// NewPlatformBootstrapResponse builds the bootstrap report from the recorded steps
+func NewPlatformBootstrapResponse(steps []config.BootstrapStepStatus) *PlatformBootstrapResponse {
+	response := &PlatformBootstrapResponse{
+		Ready: len(steps) > 0,
+		Steps: make([]PlatformBootstrapStep, 0, len(steps)),
+	}
+	for _, step := range steps {
+		if step.State != config.BootstrapStepSucceeded {
+			response.Ready = false
+		}
+		response.Steps = append(response.Steps, PlatformBootstrapStep{
+			Step:            step.Step,
+			Name:            step.Name,
+			State:           string(step.State),
+			Error:           step.Error,
+			Attempts:        step.Attempts,
+			LastAttemptTime: step.LastAttemptTime,
+			LastSuccessTime: step.LastSuccessTime,
+			NextRetryTime:   step.NextRetryTime,
+		})
+	}
+	return response
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+
+	"github.com/kibamail/kibaship/pkg/config"
+)
+
+func TestNewPlatformBootstrapResponse(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []config.BootstrapStepStatus
+		ready bool
+	}{
+		{
+			name:  "nothing recorded",
+			ready: false,
+		},
+		{
+			name: "all steps succeeded",
+			steps: []config.BootstrapStepStatus{
+				{Step: 1, Name: "storage-classes", State: config.BootstrapStepSucceeded},
+				{Step: 2, Name: "priority-classes", State: config.BootstrapStepSucceeded},
+			},
+			ready: true,
+		},
+		{
+			name: "failed step",
+			steps: []config.BootstrapStepStatus{
+				{Step: 1, Name: "storage-classes", State: config.BootstrapStepSucceeded},
+				{Step: 4, Name: "ingress-certificates", State: config.BootstrapStepFailed, Error: "cert-manager is not installed"},
+			},
+			ready: false,
+		},
+		{
+			name: "step still running",
+			steps: []config.BootstrapStepStatus{
+				{Step: 1, Name: "storage-classes", State: config.BootstrapStepRunning},
+			},
+			ready: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := NewPlatformBootstrapResponse(tt.steps)
+			if response.Ready != tt.ready {
+				t.Errorf("expected ready %v, got %v", tt.ready, response.Ready)
+			}
+			if len(response.Steps) != len(tt.steps) {
+				t.Fatalf("expected %d steps, got %d", len(tt.steps), len(response.Steps))
+			}
+			for i, step := range tt.steps {
+				if response.Steps[i].Name != step.Name || response.Steps[i].State != string(step.State) || response.Steps[i].Error != step.Error {
+					t.Errorf("step %d not converted: %+v", i, response.Steps[i])
+				}
+			}
+		})
+	}
+}
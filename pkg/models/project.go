@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/kibamail/kibaship/api/v1alpha1"
 	"github.com/kibamail/kibaship/pkg/utils"
 )
 
@@ -79,6 +80,37 @@ type VolumeSettings struct {
 	MaxStorageSize string `json:"maxStorageSize,omitempty" example:"100Gi"`
 }
 
+// PrioritySettings selects the PriorityClasses of the project build and application pods,
+// empty names use the classes provisioned by the operator
+type PrioritySettings struct {
+	BuildPriorityClassName   string `json:"buildPriorityClassName,omitempty" example:"kibaship-build"`
+	RuntimePriorityClassName string `json:"runtimePriorityClassName,omitempty" example:"kibaship-runtime"`
+}
+
+// ErrorPageSettings replaces the ingress 404 and 5xx pages of the project applications,
+// either with an image serving them or with uploaded HTML documents
+type ErrorPageSettings struct {
+	Image       string `json:"image,omitempty" example:"ghcr.io/acme/error-pages:1.0"`
+	NotFound    string `json:"notFound,omitempty" example:"<html><body><h1>Page not found</h1></body></html>"`
+	ServerError string `json:"serverError,omitempty" example:"<html><body><h1>Something went wrong</h1></body></html>"`
+}
+
+// EgressSettings restricts what the project applications may call outside the cluster. Restricted
+// applications reach the cluster, cluster DNS and the allowed CIDRs and domains only.
+type EgressSettings struct {
+	Restricted     bool     `json:"restricted" example:"true"`
+	AllowedCIDRs   []string `json:"allowedCIDRs,omitempty" example:"203.0.113.0/24"`
+	AllowedDomains []string `json:"allowedDomains,omitempty" example:"api.stripe.com,*.amazonaws.com"`
+}
+
+// NamespaceSettings adds labels and annotations to the project namespace, such as the
+// pod-security.kubernetes.io enforcement levels. Keys under kibaship.com, kubernetes.io and
+// k8s.io are reserved.
+type NamespaceSettings struct {
+	Labels      map[string]string `json:"labels,omitempty" example:"pod-security.kubernetes.io/enforce:baseline,cost-center:billing"`
+	Annotations map[string]string `json:"annotations,omitempty" example:"team:payments"`
+}
+
 // ProjectCreateRequest represents the request payload for creating a project
 type ProjectCreateRequest struct {
 	Name                    string                   `json:"name" example:"my-awesome-project"`
@@ -88,6 +120,13 @@ type ProjectCreateRequest struct {
 	ResourceProfile         *ResourceProfile         `json:"resourceProfile,omitempty" example:"development"`
 	CustomResourceLimits    *CustomResourceLimits    `json:"customResourceLimits,omitempty"`
 	VolumeSettings          *VolumeSettings          `json:"volumeSettings,omitempty"`
+	BaseDomain              string                   `json:"baseDomain,omitempty" example:"apps.customer.com"`
+	PrioritySettings        *PrioritySettings        `json:"prioritySettings,omitempty"`
+	ErrorPages              *ErrorPageSettings       `json:"errorPages,omitempty"`
+	Egress                  *EgressSettings          `json:"egress,omitempty"`
+	Namespace               *NamespaceSettings       `json:"namespace,omitempty"`
+	SecurityLevel           string                   `json:"securityLevel,omitempty" example:"baseline"`
+	RegistryQuota           string                   `json:"registryQuota,omitempty" example:"20Gi"`
 }
 
 // ProjectResponse represents the response when returning project information
@@ -100,6 +139,13 @@ type ProjectResponse struct {
 	EnabledApplicationTypes ApplicationTypeSettings `json:"enabledApplicationTypes"`
 	ResourceProfile         ResourceProfile         `json:"resourceProfile" example:"development"`
 	VolumeSettings          VolumeSettings          `json:"volumeSettings"`
+	BaseDomain              string                  `json:"baseDomain,omitempty" example:"apps.customer.com"`
+	PrioritySettings        PrioritySettings        `json:"prioritySettings"`
+	ErrorPages              ErrorPageSettings       `json:"errorPages"`
+	Egress                  EgressSettings          `json:"egress"`
+	Namespace               NamespaceSettings       `json:"namespace"`
+	SecurityLevel           string                  `json:"securityLevel,omitempty" example:"baseline"`
+	RegistryQuota           string                  `json:"registryQuota,omitempty" example:"20Gi"`
 	Status                  string                  `json:"status" example:"Ready"`
 	NamespaceName           string                  `json:"namespaceName,omitempty" example:"project-550e8400-e29b-41d4-a716-446655440000"`
 	CreatedAt               time.Time               `json:"createdAt" example:"2023-01-01T12:00:00Z"`
@@ -116,6 +162,13 @@ type Project struct {
 	EnabledApplicationTypes ApplicationTypeSettings
 	ResourceProfile         ResourceProfile
 	VolumeSettings          VolumeSettings
+	BaseDomain              string
+	PrioritySettings        PrioritySettings
+	ErrorPages              ErrorPageSettings
+	Egress                  EgressSettings
+	Namespace               NamespaceSettings
+	SecurityLevel           string
+	RegistryQuota           string
 	Status                  string
 	NamespaceName           string
 	CreatedAt               time.Time
@@ -241,6 +294,47 @@ func (req *ProjectCreateRequest) Validate() *ValidationErrors {
 		}
 	}
 
+	// Validate base domain
+	if req.BaseDomain != "" && !isValidBaseDomain(req.BaseDomain) {
+		errors = append(errors, ValidationError{
+			Field:   "baseDomain",
+			Message: "Base domain must be a valid domain name (e.g., 'apps.customer.com')",
+		})
+	}
+
+	// Validate priority settings
+	if req.PrioritySettings != nil {
+		errors = append(errors, validatePrioritySettings(req.PrioritySettings)...)
+	}
+
+	// Validate error pages
+	if req.ErrorPages != nil {
+		errors = append(errors, validateErrorPageSettings(req.ErrorPages)...)
+	}
+
+	// Validate egress settings
+	if req.Egress != nil {
+		errors = append(errors, validateEgressSettings(req.Egress)...)
+	}
+
+	// Validate namespace labels and annotations
+	if req.Namespace != nil {
+		errors = append(errors, validateNamespaceSettings(req.Namespace)...)
+	}
+
+	// Validate the pod security level
+	if req.SecurityLevel != "" {
+		errors = append(errors, validateSecurityLevel(req.SecurityLevel, req.Namespace)...)
+	}
+
+	// Validate registry quota
+	if req.RegistryQuota != "" && !isValidStorageSize(req.RegistryQuota) {
+		errors = append(errors, ValidationError{
+			Field:   "registryQuota",
+			Message: "Registry quota must be in valid format (e.g., '20Gi', '500Mi', '1Ti')",
+		})
+	}
+
 	if len(errors) > 0 {
 		return &ValidationErrors{Errors: errors}
 	}
@@ -259,6 +353,13 @@ func (p *Project) ToResponse() ProjectResponse {
 		EnabledApplicationTypes: p.EnabledApplicationTypes,
 		ResourceProfile:         p.ResourceProfile,
 		VolumeSettings:          p.VolumeSettings,
+		BaseDomain:              p.BaseDomain,
+		PrioritySettings:        p.PrioritySettings,
+		ErrorPages:              p.ErrorPages,
+		Egress:                  p.Egress,
+		Namespace:               p.Namespace,
+		SecurityLevel:           p.SecurityLevel,
+		RegistryQuota:           p.RegistryQuota,
 		Status:                  p.Status,
 		NamespaceName:           p.NamespaceName,
 		CreatedAt:               p.CreatedAt,
@@ -302,6 +403,182 @@ func isValidResourceProfile(profile ResourceProfile) bool {
 		profile == ResourceProfileCustom
 }
 
+// priorityClassNamePattern matches the PriorityClass names accepted by the Project CRD
+var priorityClassNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+
+// validatePrioritySettings validates the PriorityClass names of a project
+func validatePrioritySettings(settings *PrioritySettings) []ValidationError {
+	var errors []ValidationError
+
+	fields := []struct {
+		field string
+		name  string
+	}{
+		{"prioritySettings.buildPriorityClassName", settings.BuildPriorityClassName},
+		{"prioritySettings.runtimePriorityClassName", settings.RuntimePriorityClassName},
+	}
+	for _, f := range fields {
+		if f.name == "" {
+			continue
+		}
+		if len(f.name) > 253 || !priorityClassNamePattern.MatchString(f.name) {
+			errors = append(errors, ValidationError{
+				Field:   f.field,
+				Message: "Priority class name must be a lowercase DNS subdomain",
+			})
+			continue
+		}
+		if err := v1alpha1.ValidatePriorityClassName(f.name); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   f.field,
+				Message: err.Error(),
+			})
+		}
+	}
+
+	return errors
+}
+
+// maxErrorPageSize bounds each uploaded error page, matching the Project CRD
+const maxErrorPageSize = 262144
+
+// validateErrorPageSettings validates the custom error pages of a project
+func validateErrorPageSettings(settings *ErrorPageSettings) []ValidationError {
+	var errors []ValidationError
+
+	if len(settings.Image) > 512 {
+		errors = append(errors, ValidationError{
+			Field:   "errorPages.image",
+			Message: "Error pages image must be at most 512 characters",
+		})
+	}
+	if len(settings.NotFound) > maxErrorPageSize {
+		errors = append(errors, ValidationError{
+			Field:   "errorPages.notFound",
+			Message: "Not found page must be at most 256KiB",
+		})
+	}
+	if len(settings.ServerError) > maxErrorPageSize {
+		errors = append(errors, ValidationError{
+			Field:   "errorPages.serverError",
+			Message: "Server error page must be at most 256KiB",
+		})
+	}
+
+	if err := v1alpha1.ValidateErrorPages(v1alpha1.ErrorPagesConfig{
+		Image:       settings.Image,
+		NotFound:    settings.NotFound,
+		ServerError: settings.ServerError,
+	}); err != nil {
+		errors = append(errors, ValidationError{
+			Field:   "errorPages",
+			Message: err.Error(),
+		})
+	}
+
+	return errors
+}
+
+// maxEgressAllowlistSize bounds the allowed CIDRs and domains, matching the Project CRD
+const maxEgressAllowlistSize = 100
+
+// egressDomainRegex matches an allowed egress domain, optionally a *. wildcard over its subdomains
+var egressDomainRegex = regexp.MustCompile(`^(\*\.)?([a-z0-9]([-a-z0-9]*[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// validateEgressSettings validates the egress restriction of a project
+func validateEgressSettings(settings *EgressSettings) []ValidationError {
+	var errors []ValidationError
+
+	if len(settings.AllowedCIDRs) > maxEgressAllowlistSize {
+		errors = append(errors, ValidationError{
+			Field:   "egress.allowedCIDRs",
+			Message: "At most 100 CIDRs can be allowed",
+		})
+	}
+	if len(settings.AllowedDomains) > maxEgressAllowlistSize {
+		errors = append(errors, ValidationError{
+			Field:   "egress.allowedDomains",
+			Message: "At most 100 domains can be allowed",
+		})
+	}
+	for _, domain := range settings.AllowedDomains {
+		if len(domain) > 253 || !egressDomainRegex.MatchString(domain) {
+			errors = append(errors, ValidationError{
+				Field:   "egress.allowedDomains",
+				Message: fmt.Sprintf("Allowed domain %q must be a domain name, optionally prefixed with '*.'", domain),
+			})
+		}
+	}
+
+	if err := v1alpha1.ValidateEgress(v1alpha1.EgressConfig{
+		Restricted:     settings.Restricted,
+		AllowedCIDRs:   settings.AllowedCIDRs,
+		AllowedDomains: settings.AllowedDomains,
+	}); err != nil {
+		errors = append(errors, ValidationError{
+			Field:   "egress",
+			Message: err.Error(),
+		})
+	}
+
+	return errors
+}
+
+// maxNamespaceMetadataSize bounds the namespace labels and annotations, matching the Project CRD
+const maxNamespaceMetadataSize = 50
+
+// validateNamespaceSettings validates the labels and annotations of a project namespace
+func validateNamespaceSettings(settings *NamespaceSettings) []ValidationError {
+	var errors []ValidationError
+
+	if len(settings.Labels) > maxNamespaceMetadataSize {
+		errors = append(errors, ValidationError{
+			Field:   "namespace.labels",
+			Message: "At most 50 namespace labels can be set",
+		})
+	}
+	if len(settings.Annotations) > maxNamespaceMetadataSize {
+		errors = append(errors, ValidationError{
+			Field:   "namespace.annotations",
+			Message: "At most 50 namespace annotations can be set",
+		})
+	}
+
+	if err := v1alpha1.ValidateNamespace(v1alpha1.NamespaceConfig{
+		Labels:      settings.Labels,
+		Annotations: settings.Annotations,
+	}); err != nil {
+		errors = append(errors, ValidationError{
+			Field:   "namespace",
+			Message: err.Error(),
+		})
+	}
+
+	return errors
+}
+
+// validateSecurityLevel validates a project pod security level and that the namespace labels set
+// along with it leave the pod security labels to the operator
+func validateSecurityLevel(level string, namespace *NamespaceSettings) []ValidationError {
+	var labels map[string]string
+	if namespace != nil {
+		labels = namespace.Labels
+	}
+	if err := v1alpha1.ValidateSecurityLevel(v1alpha1.SecurityLevel(level), v1alpha1.NamespaceConfig{Labels: labels}); err != nil {
+		return []ValidationError{{
+			Field:   "securityLevel",
+			Message: err.Error(),
+		}}
+	}
+	return nil
+}
+
+// isValidBaseDomain validates a project or application base domain: a lowercase
+// domain name with at least two labels, matching the CRD validation
+func isValidBaseDomain(domain string) bool {
+	return len(domain) <= 200 && strings.Contains(domain, ".") && isValidDomain(domain)
+}
+
 func isValidStorageSize(size string) bool {
 	storageRegex := regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(Mi|Gi|Ti)$`)
 	return storageRegex.MatchString(size)
@@ -374,6 +651,13 @@ type ProjectUpdateRequest struct {
 	ResourceProfile         *ResourceProfile         `json:"resourceProfile,omitempty" example:"production"`
 	CustomResourceLimits    *CustomResourceLimits    `json:"customResourceLimits,omitempty"`
 	VolumeSettings          *VolumeSettings          `json:"volumeSettings,omitempty"`
+	BaseDomain              *string                  `json:"baseDomain,omitempty" example:"apps.customer.com"`
+	PrioritySettings        *PrioritySettings        `json:"prioritySettings,omitempty"`
+	ErrorPages              *ErrorPageSettings       `json:"errorPages,omitempty"`
+	Egress                  *EgressSettings          `json:"egress,omitempty"`
+	Namespace               *NamespaceSettings       `json:"namespace,omitempty"`
+	SecurityLevel           *string                  `json:"securityLevel,omitempty" example:"restricted"`
+	RegistryQuota           *string                  `json:"registryQuota,omitempty" example:"20Gi"`
 }
 
 // ValidateUpdate validates a project update request
@@ -437,6 +721,47 @@ func (req *ProjectUpdateRequest) ValidateUpdate() *ValidationErrors {
 		}
 	}
 
+	// Validate base domain if provided; an empty value clears the override
+	if req.BaseDomain != nil && *req.BaseDomain != "" && !isValidBaseDomain(*req.BaseDomain) {
+		errors = append(errors, ValidationError{
+			Field:   "baseDomain",
+			Message: "Base domain must be a valid domain name (e.g., 'apps.customer.com')",
+		})
+	}
+
+	// Validate priority settings if provided; empty names restore the operator classes
+	if req.PrioritySettings != nil {
+		errors = append(errors, validatePrioritySettings(req.PrioritySettings)...)
+	}
+
+	// Validate error pages if provided; empty settings restore the ingress defaults
+	if req.ErrorPages != nil {
+		errors = append(errors, validateErrorPageSettings(req.ErrorPages)...)
+	}
+
+	// Validate egress settings if provided; empty settings lift the restriction
+	if req.Egress != nil {
+		errors = append(errors, validateEgressSettings(req.Egress)...)
+	}
+
+	// Validate namespace settings if provided; empty settings remove the custom metadata
+	if req.Namespace != nil {
+		errors = append(errors, validateNamespaceSettings(req.Namespace)...)
+	}
+
+	// Validate the pod security level if provided; an empty value stops the enforcement
+	if req.SecurityLevel != nil && *req.SecurityLevel != "" {
+		errors = append(errors, validateSecurityLevel(*req.SecurityLevel, req.Namespace)...)
+	}
+
+	// Validate registry quota if provided; an empty value removes the quota
+	if req.RegistryQuota != nil && *req.RegistryQuota != "" && !isValidStorageSize(*req.RegistryQuota) {
+		errors = append(errors, ValidationError{
+			Field:   "registryQuota",
+			Message: "Registry quota must be in valid format (e.g., '20Gi', '500Mi', '1Ti')",
+		})
+	}
+
 	if len(errors) > 0 {
 		return &ValidationErrors{Errors: errors}
 	}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// ApplicationCostResponse is the cost estimate of a single application
+type ApplicationCostResponse struct {
+	ApplicationUUID string `json:"applicationUuid" example:"550e8400-e29b-41d4-a716-446655440001"`
+	CPU             string `json:"cpu,omitempty" example:"1500m"`
+	Memory          string `json:"memory,omitempty" example:"2Gi"`
+	Storage         string `json:"storage,omitempty" example:"10Gi"`
+	HourlyCost      string `json:"hourlyCost" example:"0.0410"`
+}
+
+// DailyCostResponse is the estimated cost accrued on one UTC day
+type DailyCostResponse struct {
+	Date string `json:"date" example:"2023-01-01"`
+	Cost string `json:"cost" example:"0.9840"`
+}
+
+// ProjectCostResponse is the cost estimate of a project based on the resources its workloads request.
+// CalculatedAt is omitted until the operator has calculated the first estimate.
+type ProjectCostResponse struct {
+	ProjectUUID  string                    `json:"projectUuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Currency     string                    `json:"currency,omitempty" example:"USD"`
+	CPU          string                    `json:"cpu,omitempty" example:"1500m"`
+	Memory       string                    `json:"memory,omitempty" example:"2Gi"`
+	Storage      string                    `json:"storage,omitempty" example:"10Gi"`
+	HourlyCost   string                    `json:"hourlyCost" example:"0.0410"`
+	Applications []ApplicationCostResponse `json:"applications"`
+	History      []DailyCostResponse       `json:"history"`
+	CalculatedAt *time.Time                `json:"calculatedAt,omitempty" example:"2023-01-01T12:00:00Z"`
+}
+
+// NewProjectCostResponse converts the cost estimate of a Project CRD status to the API response
+func NewProjectCostResponse(projectUUID string, estimate *v1alpha1.ProjectCost) *ProjectCostResponse {
+	response := &ProjectCostResponse{
+		ProjectUUID:  projectUUID,
+		HourlyCost:   "0.0000",
+		Applications: []ApplicationCostResponse{},
+		History:      []DailyCostResponse{},
+	}
+	if estimate == nil {
+		return response
+	}
+
+	response.Currency = estimate.Currency
+	response.CPU = estimate.CPU
+	response.Memory = estimate.Memory
+	response.Storage = estimate.Storage
+	response.HourlyCost = estimate.HourlyCost
+	for _, app := range estimate.Applications {
+		response.Applications = append(response.Applications, ApplicationCostResponse{
+			ApplicationUUID: app.ApplicationUUID,
+			CPU:             app.CPU,
+			Memory:          app.Memory,
+			Storage:         app.Storage,
+			HourlyCost:      app.HourlyCost,
+		})
+	}
+	for _, day := range estimate.History {
+		response.History = append(response.History, DailyCostResponse{Date: day.Date, Cost: day.Cost})
+	}
+	if estimate.LastCalculatedTime != nil {
+		calculatedAt := estimate.LastCalculatedTime.Time
+		response.CalculatedAt = &calculatedAt
+	}
+	return response
+}
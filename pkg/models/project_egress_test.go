@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "testing"
+
+func TestProjectEgressSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    *EgressSettings
+		expectField string
+	}{
+		{
+			name: "allowlists",
+			settings: &EgressSettings{
+				Restricted:     true,
+				AllowedCIDRs:   []string{"203.0.113.0/24", "2001:db8::/32"},
+				AllowedDomains: []string{"api.stripe.com", "*.amazonaws.com"},
+			},
+		},
+		{
+			name:     "deny all external traffic",
+			settings: &EgressSettings{Restricted: true},
+		},
+		{
+			name:     "lift restriction",
+			settings: &EgressSettings{},
+		},
+		{
+			name:        "invalid CIDR",
+			settings:    &EgressSettings{Restricted: true, AllowedCIDRs: []string{"203.0.113.0"}},
+			expectField: "egress",
+		},
+		{
+			name:        "invalid domain",
+			settings:    &EgressSettings{Restricted: true, AllowedDomains: []string{"api.*.example.com"}},
+			expectField: "egress.allowedDomains",
+		},
+		{
+			name:        "allowlist without restriction",
+			settings:    &EgressSettings{AllowedDomains: []string{"api.stripe.com"}},
+			expectField: "egress",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := ProjectUpdateRequest{Egress: tt.settings}
+			errs := req.ValidateUpdate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
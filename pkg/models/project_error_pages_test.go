@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProjectErrorPageSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    *ErrorPageSettings
+		expectField string
+	}{
+		{
+			name:     "uploaded pages",
+			settings: &ErrorPageSettings{NotFound: "<h1>Not found</h1>", ServerError: "<h1>Oops</h1>"},
+		},
+		{
+			name:     "image",
+			settings: &ErrorPageSettings{Image: "ghcr.io/acme/error-pages:1.0"},
+		},
+		{
+			name:     "restore ingress defaults",
+			settings: &ErrorPageSettings{},
+		},
+		{
+			name:        "image and pages",
+			settings:    &ErrorPageSettings{Image: "ghcr.io/acme/error-pages:1.0", NotFound: "<h1>Not found</h1>"},
+			expectField: "errorPages",
+		},
+		{
+			name:        "image with whitespace",
+			settings:    &ErrorPageSettings{Image: "ghcr.io/acme/error pages"},
+			expectField: "errorPages",
+		},
+		{
+			name:        "oversized server error page",
+			settings:    &ErrorPageSettings{ServerError: strings.Repeat("a", maxErrorPageSize+1)},
+			expectField: "errorPages.serverError",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := ProjectUpdateRequest{ErrorPages: tt.settings}
+			errs := req.ValidateUpdate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "testing"
+
+func TestProjectNamespaceSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    *NamespaceSettings
+		expectField string
+	}{
+		{
+			name: "pod security and cost center",
+			settings: &NamespaceSettings{
+				Labels: map[string]string{
+					"pod-security.kubernetes.io/enforce": "baseline",
+					"istio-injection":                    "enabled",
+					"cost-center":                        "billing",
+				},
+				Annotations: map[string]string{"example.com/owner": "Payments team"},
+			},
+		},
+		{
+			name:     "remove custom metadata",
+			settings: &NamespaceSettings{},
+		},
+		{
+			name:        "operator label",
+			settings:    &NamespaceSettings{Labels: map[string]string{"platform.kibaship.com/uuid": "other"}},
+			expectField: "namespace",
+		},
+		{
+			name:        "managed-by label",
+			settings:    &NamespaceSettings{Labels: map[string]string{"app.kubernetes.io/managed-by": "helm"}},
+			expectField: "namespace",
+		},
+		{
+			name:        "kubernetes annotation",
+			settings:    &NamespaceSettings{Annotations: map[string]string{"kubernetes.io/metadata.name": "other"}},
+			expectField: "namespace",
+		},
+		{
+			name:        "invalid label value",
+			settings:    &NamespaceSettings{Labels: map[string]string{"cost-center": "billing team"}},
+			expectField: "namespace",
+		},
+		{
+			name:        "invalid key",
+			settings:    &NamespaceSettings{Annotations: map[string]string{"-owner": "payments"}},
+			expectField: "namespace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := ProjectUpdateRequest{Namespace: tt.settings}
+			errs := req.ValidateUpdate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
+
+func TestProjectSecurityLevelValidate(t *testing.T) {
+	level := func(s string) *string { return &s }
+	tests := []struct {
+		name        string
+		level       *string
+		namespace   *NamespaceSettings
+		expectField string
+	}{
+		{name: "baseline", level: level("baseline")},
+		{name: "restricted", level: level("restricted")},
+		{name: "stop enforcing", level: level("")},
+		{
+			name:        "unknown level",
+			level:       level("privileged"),
+			expectField: "securityLevel",
+		},
+		{
+			name:        "pod security label with a level",
+			level:       level("restricted"),
+			namespace:   &NamespaceSettings{Labels: map[string]string{"pod-security.kubernetes.io/enforce": "baseline"}},
+			expectField: "securityLevel",
+		},
+		{
+			name:      "other labels with a level",
+			level:     level("baseline"),
+			namespace: &NamespaceSettings{Labels: map[string]string{"cost-center": "billing"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := ProjectUpdateRequest{SecurityLevel: tt.level, Namespace: tt.namespace}
+			errs := req.ValidateUpdate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
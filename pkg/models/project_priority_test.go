@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "testing"
+
+func TestProjectPrioritySettingsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    *PrioritySettings
+		expectField string
+	}{
+		{
+			name: "operator classes",
+			settings: &PrioritySettings{
+				BuildPriorityClassName:   "kibaship-build",
+				RuntimePriorityClassName: "kibaship-runtime",
+			},
+		},
+		{
+			name:     "custom runtime class",
+			settings: &PrioritySettings{RuntimePriorityClassName: "customer.production"},
+		},
+		{
+			name:     "restore operator classes",
+			settings: &PrioritySettings{},
+		},
+		{
+			name:        "invalid class name",
+			settings:    &PrioritySettings{BuildPriorityClassName: "Low_Priority"},
+			expectField: "prioritySettings.buildPriorityClassName",
+		},
+		{
+			name:        "kubernetes system class",
+			settings:    &PrioritySettings{RuntimePriorityClassName: "system-cluster-critical"},
+			expectField: "prioritySettings.runtimePriorityClassName",
+		},
+		{
+			name:        "platform system class",
+			settings:    &PrioritySettings{RuntimePriorityClassName: "kibaship-system"},
+			expectField: "prioritySettings.runtimePriorityClassName",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := ProjectUpdateRequest{PrioritySettings: tt.settings}
+			errs := req.ValidateUpdate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
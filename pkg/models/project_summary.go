@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// IncidentType identifies what needs attention in a project
+type IncidentType string
+
+const (
+	// IncidentTypeDeploymentFailed is reported when the latest deployment of an application failed
+	IncidentTypeDeploymentFailed IncidentType = "DeploymentFailed"
+	// IncidentTypeCertificateFailed is reported when the certificate of a domain expired or failed to issue
+	IncidentTypeCertificateFailed IncidentType = "CertificateFailed"
+	// IncidentTypeCrashLooping is reported for every pod with a container in CrashLoopBackOff
+	IncidentTypeCrashLooping IncidentType = "CrashLooping"
+)
+
+// ProjectSummaryLatestDeployments is how many deployments the project summary lists
+const ProjectSummaryLatestDeployments = 10
+
+// crashLoopBackOffReason is the waiting reason of containers restarting after repeated crashes
+const crashLoopBackOffReason = "CrashLoopBackOff"
+
+// ProjectIncidentResponse is a failure in a project that needs attention. Since is omitted when the
+// start of the failure is unknown.
+type ProjectIncidentResponse struct {
+	Type            IncidentType `json:"type" example:"DeploymentFailed"`
+	ApplicationUUID string       `json:"applicationUuid,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	ResourceUUID    string       `json:"resourceUuid,omitempty" example:"550e8400-e29b-41d4-a716-446655440003"`
+	ResourceName    string       `json:"resourceName" example:"deployment-550e8400-e29b-41d4-a716-446655440003"`
+	Message         string       `json:"message,omitempty" example:"PipelineRun failed"`
+	Since           *time.Time   `json:"since,omitempty" example:"2023-01-01T12:00:00Z"`
+}
+
+// ProjectSummaryResponse is the state of a project at a glance: its applications per phase, the
+// incidents needing attention and its most recent deployments, newest first
+type ProjectSummaryResponse struct {
+	ProjectUUID         string                    `json:"projectUuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Applications        int                       `json:"applications" example:"4"`
+	ApplicationsByPhase map[string]int            `json:"applicationsByPhase"`
+	Incidents           []ProjectIncidentResponse `json:"incidents"`
+	LatestDeployments   []DeploymentResponse      `json:"latestDeployments"`
+}
+
+// NewProjectSummaryResponse aggregates the applications, deployments, domains and pods of a project.
+// Applications without a phase yet are counted as Pending.
+func NewProjectSummaryResponse(projectUUID string, applications []v1alpha1.Application,
+	deployments []v1alpha1.Deployment, domains []v1alpha1.ApplicationDomain, pods []corev1.Pod) *ProjectSummaryResponse {
+	summary := &ProjectSummaryResponse{
+		ProjectUUID:         projectUUID,
+		Applications:        len(applications),
+		ApplicationsByPhase: map[string]int{},
+		Incidents:           []ProjectIncidentResponse{},
+		LatestDeployments:   []DeploymentResponse{},
+	}
+
+	applicationSlugs := make(map[string]string, len(applications))
+	for _, app := range applications {
+		phase := app.Status.Phase
+		if phase == "" {
+			phase = "Pending"
+		}
+		summary.ApplicationsByPhase[phase]++
+		applicationSlugs[app.GetUUID()] = app.GetSlug()
+	}
+
+	sorted := make([]*v1alpha1.Deployment, 0, len(deployments))
+	for i := range deployments {
+		sorted = append(sorted, &deployments[i])
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[j].CreationTimestamp.Before(&sorted[i].CreationTimestamp)
+	})
+
+	// Only the latest deployment of an application counts, a later one supersedes a failure
+	seen := map[string]bool{}
+	for _, deployment := range sorted {
+		appUUID := deployment.GetLabels()[validation.LabelApplicationUUID]
+		if seen[appUUID] {
+			continue
+		}
+		seen[appUUID] = true
+		if deployment.Status.Phase != v1alpha1.DeploymentPhaseFailed {
+			continue
+		}
+		message, since := deploymentFailure(deployment.Status.Conditions)
+		summary.Incidents = append(summary.Incidents, ProjectIncidentResponse{
+			Type:            IncidentTypeDeploymentFailed,
+			ApplicationUUID: appUUID,
+			ResourceUUID:    deployment.GetUUID(),
+			ResourceName:    deployment.Name,
+			Message:         message,
+			Since:           since,
+		})
+	}
+
+	for _, domain := range domains {
+		if domain.Status.CertificateRef == nil || domain.Status.CertificateReady ||
+			domain.Status.Phase != v1alpha1.ApplicationDomainPhaseFailed {
+			continue
+		}
+		var since *time.Time
+		if ready := meta.FindStatusCondition(domain.Status.Conditions, "Ready"); ready != nil {
+			since = &ready.LastTransitionTime.Time
+		}
+		summary.Incidents = append(summary.Incidents, ProjectIncidentResponse{
+			Type:            IncidentTypeCertificateFailed,
+			ApplicationUUID: domain.GetLabels()[validation.LabelApplicationUUID],
+			ResourceUUID:    domain.GetLabels()[validation.LabelResourceUUID],
+			ResourceName:    domain.Spec.Domain,
+			Message:         domain.Status.Message,
+			Since:           since,
+		})
+	}
+
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Waiting == nil || status.State.Waiting.Reason != crashLoopBackOffReason {
+				continue
+			}
+			var since *time.Time
+			if terminated := status.LastTerminationState.Terminated; terminated != nil {
+				since = &terminated.FinishedAt.Time
+			}
+			summary.Incidents = append(summary.Incidents, ProjectIncidentResponse{
+				Type:            IncidentTypeCrashLooping,
+				ApplicationUUID: pod.Labels[validation.LabelApplicationUUID],
+				ResourceName:    pod.Name,
+				Message:         fmt.Sprintf("Container %s restarted %d times", status.Name, status.RestartCount),
+				Since:           since,
+			})
+			break
+		}
+	}
+
+	for _, deployment := range sorted[:min(len(sorted), ProjectSummaryLatestDeployments)] {
+		var d Deployment
+		d.ConvertFromCRD(deployment, applicationSlugs[deployment.GetLabels()[validation.LabelApplicationUUID]])
+		summary.LatestDeployments = append(summary.LatestDeployments, d.ToResponse())
+	}
+
+	return summary
+}
+
+// deploymentFailure returns the message and transition time of the condition that failed a
+// deployment, the build or the rollout
+func deploymentFailure(conditions []metav1.Condition) (string, *time.Time) {
+	for _, conditionType := range []string{"PipelineRunReady", "K8sDeploymentReady"} {
+		condition := meta.FindStatusCondition(conditions, conditionType)
+		if condition != nil && condition.Status == metav1.ConditionFalse {
+			return condition.Message, &condition.LastTransitionTime.Time
+		}
+	}
+	return "", nil
+}
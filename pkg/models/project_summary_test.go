@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func summaryTestDeployment(uuid, appUUID string, created time.Time, phase v1alpha1.DeploymentPhase, conditions ...metav1.Condition) v1alpha1.Deployment {
+	return v1alpha1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "deployment-" + uuid,
+			CreationTimestamp: metav1.NewTime(created),
+			Labels: map[string]string{
+				validation.LabelResourceUUID:    uuid,
+				validation.LabelApplicationUUID: appUUID,
+			},
+		},
+		Status: v1alpha1.DeploymentStatus{Phase: phase, Conditions: conditions},
+	}
+}
+
+func TestNewProjectSummaryResponse(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC)
+	failedAt := metav1.NewTime(now.Add(-time.Minute))
+
+	applications := []v1alpha1.Application{
+		{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{validation.LabelResourceUUID: "app-1", validation.LabelResourceSlug: "web"}},
+			Status: v1alpha1.ApplicationStatus{Phase: "Ready"}},
+		{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{validation.LabelResourceUUID: "app-2", validation.LabelResourceSlug: "api"}},
+			Status: v1alpha1.ApplicationStatus{Phase: "Ready"}},
+		{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{validation.LabelResourceUUID: "app-3", validation.LabelResourceSlug: "db"}}},
+	}
+	deployments := []v1alpha1.Deployment{
+		// The failure of app-1 is superseded by a later deployment
+		summaryTestDeployment("dep-1", "app-1", now.Add(-3*time.Hour), v1alpha1.DeploymentPhaseFailed),
+		summaryTestDeployment("dep-2", "app-1", now.Add(-2*time.Hour), v1alpha1.DeploymentPhaseSucceeded),
+		summaryTestDeployment("dep-3", "app-2", now.Add(-time.Hour), v1alpha1.DeploymentPhaseFailed,
+			metav1.Condition{Type: "PipelineRunReady", Status: metav1.ConditionFalse, Message: "build failed", LastTransitionTime: failedAt}),
+	}
+	domains := []v1alpha1.ApplicationDomain{
+		{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{validation.LabelResourceUUID: "dom-1", validation.LabelApplicationUUID: "app-1"}},
+			Spec:       v1alpha1.ApplicationDomainSpec{Domain: "web.example.com"},
+			Status: v1alpha1.ApplicationDomainStatus{
+				Phase:          v1alpha1.ApplicationDomainPhaseFailed,
+				CertificateRef: &v1alpha1.NamespacedRef{Name: "web-example-com", Namespace: "default"},
+				Message:        "Expired: Certificate expired",
+			},
+		},
+		{
+			// Failed without a certificate, the application is missing
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{validation.LabelResourceUUID: "dom-2"}},
+			Status:     v1alpha1.ApplicationDomainStatus{Phase: v1alpha1.ApplicationDomainPhaseFailed},
+		},
+	}
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Labels: map[string]string{validation.LabelApplicationUUID: "app-1"}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "app",
+				RestartCount: 7,
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-def"},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "app",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}}},
+		},
+	}
+
+	summary := NewProjectSummaryResponse("project-1", applications, deployments, domains, pods)
+
+	if summary.Applications != 3 {
+		t.Errorf("expected 3 applications, got %d", summary.Applications)
+	}
+	if summary.ApplicationsByPhase["Ready"] != 2 || summary.ApplicationsByPhase["Pending"] != 1 {
+		t.Errorf("unexpected applications by phase %v", summary.ApplicationsByPhase)
+	}
+
+	var types []IncidentType
+	for _, incident := range summary.Incidents {
+		types = append(types, incident.Type)
+	}
+	expected := []IncidentType{IncidentTypeDeploymentFailed, IncidentTypeCertificateFailed, IncidentTypeCrashLooping}
+	if fmt.Sprint(types) != fmt.Sprint(expected) {
+		t.Fatalf("expected incidents %v, got %v", expected, types)
+	}
+	if incident := summary.Incidents[0]; incident.ResourceUUID != "dep-3" || incident.Message != "build failed" ||
+		incident.Since == nil || !incident.Since.Equal(failedAt.Time) {
+		t.Errorf("unexpected deployment incident %+v", incident)
+	}
+	if incident := summary.Incidents[1]; incident.ResourceName != "web.example.com" || incident.ApplicationUUID != "app-1" {
+		t.Errorf("unexpected certificate incident %+v", incident)
+	}
+	if incident := summary.Incidents[2]; incident.ResourceName != "web-abc" || incident.Message != "Container app restarted 7 times" {
+		t.Errorf("unexpected crash loop incident %+v", incident)
+	}
+
+	if len(summary.LatestDeployments) != 3 {
+		t.Fatalf("expected 3 latest deployments, got %d", len(summary.LatestDeployments))
+	}
+	if summary.LatestDeployments[0].UUID != "dep-3" || summary.LatestDeployments[0].ApplicationSlug != "api" {
+		t.Errorf("expected the newest deployment first, got %+v", summary.LatestDeployments[0])
+	}
+}
+
+func TestNewProjectSummaryResponseLimitsDeployments(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC)
+	var deployments []v1alpha1.Deployment
+	for i := range ProjectSummaryLatestDeployments + 5 {
+		deployments = append(deployments, summaryTestDeployment(fmt.Sprintf("dep-%d", i), "app-1",
+			now.Add(time.Duration(i)*time.Minute), v1alpha1.DeploymentPhaseSucceeded))
+	}
+
+	summary := NewProjectSummaryResponse("project-1", nil, deployments, nil, nil)
+
+	if len(summary.LatestDeployments) != ProjectSummaryLatestDeployments {
+		t.Fatalf("expected %d deployments, got %d", ProjectSummaryLatestDeployments, len(summary.LatestDeployments))
+	}
+	if summary.LatestDeployments[0].UUID != fmt.Sprintf("dep-%d", ProjectSummaryLatestDeployments+4) {
+		t.Errorf("expected the newest deployment first, got %s", summary.LatestDeployments[0].UUID)
+	}
+	if len(summary.Incidents) != 0 || len(summary.ApplicationsByPhase) != 0 {
+		t.Errorf("expected an empty summary, got %+v", summary)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// RepositoryUsageResponse is the registry storage used by the images of a single application
+type RepositoryUsageResponse struct {
+	ApplicationUUID string `json:"applicationUuid" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Repository      string `json:"repository" example:"project-550e8400/550e8400-e29b-41d4-a716-446655440001"`
+	Tags            int32  `json:"tags" example:"12"`
+	Bytes           int64  `json:"bytes" example:"734003200"`
+}
+
+// RegistryUsageResponse is the registry storage used by the images of a project and its quota.
+// InspectedAt is omitted until the operator has inspected the registry once.
+type RegistryUsageResponse struct {
+	Bytes         int64                     `json:"bytes" example:"1073741824"`
+	Quota         string                    `json:"quota,omitempty" example:"20Gi"`
+	QuotaBytes    int64                     `json:"quotaBytes,omitempty" example:"21474836480"`
+	QuotaExceeded bool                      `json:"quotaExceeded" example:"false"`
+	Repositories  []RepositoryUsageResponse `json:"repositories"`
+	InspectedAt   *time.Time                `json:"inspectedAt,omitempty" example:"2023-01-01T12:00:00Z"`
+	Error         string                    `json:"error,omitempty" example:""`
+}
+
+// ProjectUsageResponse is the storage used by a project
+type ProjectUsageResponse struct {
+	ProjectUUID string                `json:"projectUuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Registry    RegistryUsageResponse `json:"registry"`
+}
+
+// NewProjectUsageResponse converts the registry usage and quota of a Project CRD to the API response
+func NewProjectUsageResponse(projectUUID string, project *v1alpha1.Project) *ProjectUsageResponse {
+	response := &ProjectUsageResponse{
+		ProjectUUID: projectUUID,
+		Registry: RegistryUsageResponse{
+			Quota:         project.Spec.RegistryQuota,
+			QuotaBytes:    project.RegistryQuotaBytes(),
+			QuotaExceeded: project.RegistryQuotaExceeded(),
+			Repositories:  []RepositoryUsageResponse{},
+		},
+	}
+
+	usage := project.Status.RegistryUsage
+	if usage == nil {
+		return response
+	}
+	response.Registry.Bytes = usage.Bytes
+	response.Registry.Error = usage.Error
+	for _, repository := range usage.Repositories {
+		response.Registry.Repositories = append(response.Registry.Repositories, RepositoryUsageResponse{
+			ApplicationUUID: repository.ApplicationUUID,
+			Repository:      repository.Repository,
+			Tags:            repository.Tags,
+			Bytes:           repository.Bytes,
+		})
+	}
+	if usage.LastInspectedTime != nil {
+		inspectedAt := usage.LastInspectedTime.Time
+		response.Registry.InspectedAt = &inspectedAt
+	}
+	return response
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+const (
+	// DefaultPromotionHookTimeoutSeconds bounds hooks without a timeout
+	DefaultPromotionHookTimeoutSeconds = 600
+
+	// MaxPromotionHookTimeoutSeconds is the longest a hook may run
+	MaxPromotionHookTimeoutSeconds = 86400
+
+	// MaxPromotionHookLogBytes is the largest hook log returned by the API, longer logs are cut
+	MaxPromotionHookLogBytes = 256 * 1024
+)
+
+// PromotionHook is a command run as a Job around the promotion of a deployment
+type PromotionHook struct {
+	Image          string   `json:"image,omitempty" example:"curlimages/curl:8.10.1"`
+	Command        []string `json:"command" example:"npm,run,migrate"`
+	FailurePolicy  string   `json:"failurePolicy,omitempty" example:"Abort"`
+	TimeoutSeconds int32    `json:"timeoutSeconds,omitempty" example:"600"`
+}
+
+// PromotionHooksUpdateRequest replaces the promotion hooks of an application, a missing hook
+// removes it
+type PromotionHooksUpdateRequest struct {
+	PrePromote  *PromotionHook `json:"prePromote,omitempty"`
+	PostPromote *PromotionHook `json:"postPromote,omitempty"`
+}
+
+// Validate validates the promotion hooks update request
+func (r *PromotionHooksUpdateRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	validateHook := func(field string, hook *PromotionHook) {
+		if hook == nil {
+			return
+		}
+		if len(hook.Command) == 0 {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".command",
+				Message: "command is required",
+			})
+		}
+		switch v1alpha1.HookFailurePolicy(hook.FailurePolicy) {
+		case "", v1alpha1.HookFailurePolicyAbort, v1alpha1.HookFailurePolicyContinue:
+		default:
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".failurePolicy",
+				Message: fmt.Sprintf("failurePolicy must be '%s' or '%s'", v1alpha1.HookFailurePolicyAbort, v1alpha1.HookFailurePolicyContinue),
+			})
+		}
+		if hook.TimeoutSeconds < 0 || hook.TimeoutSeconds > MaxPromotionHookTimeoutSeconds {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".timeoutSeconds",
+				Message: fmt.Sprintf("timeoutSeconds must be between 1 and %d", MaxPromotionHookTimeoutSeconds),
+			})
+		}
+	}
+	validateHook("prePromote", r.PrePromote)
+	validateHook("postPromote", r.PostPromote)
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// ToCRD converts the request to the promotion hooks of an Application CRD, nil when it has none
+func (r *PromotionHooksUpdateRequest) ToCRD() *v1alpha1.PromotionHooks {
+	if r.PrePromote == nil && r.PostPromote == nil {
+		return nil
+	}
+	return &v1alpha1.PromotionHooks{
+		PrePromote:  r.PrePromote.toCRD(),
+		PostPromote: r.PostPromote.toCRD(),
+	}
+}
+
+func (h *PromotionHook) toCRD() *v1alpha1.PromotionHook {
+	if h == nil {
+		return nil
+	}
+	policy := v1alpha1.HookFailurePolicy(h.FailurePolicy)
+	if policy == "" {
+		policy = v1alpha1.HookFailurePolicyAbort
+	}
+	return &v1alpha1.PromotionHook{
+		Image:          h.Image,
+		Command:        h.Command,
+		FailurePolicy:  policy,
+		TimeoutSeconds: h.TimeoutSeconds,
+	}
+}
+
+// PromotionHooksResponse describes the promotion hooks of an application
+type PromotionHooksResponse struct {
+	ApplicationUUID string         `json:"applicationUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	PrePromote      *PromotionHook `json:"prePromote,omitempty"`
+	PostPromote     *PromotionHook `json:"postPromote,omitempty"`
+}
+
+// NewPromotionHooksResponse converts the promotion hooks of an Application CRD to the API
+// response, filling in the defaults
+func NewPromotionHooksResponse(app *v1alpha1.Application) *PromotionHooksResponse {
+	response := &PromotionHooksResponse{ApplicationUUID: app.GetUUID()}
+	if app.Spec.Hooks != nil {
+		response.PrePromote = newPromotionHook(app.Spec.Hooks.PrePromote)
+		response.PostPromote = newPromotionHook(app.Spec.Hooks.PostPromote)
+	}
+	return response
+}
+
+func newPromotionHook(hook *v1alpha1.PromotionHook) *PromotionHook {
+	if hook == nil {
+		return nil
+	}
+	policy := hook.FailurePolicy
+	if policy == "" {
+		policy = v1alpha1.HookFailurePolicyAbort
+	}
+	timeout := hook.TimeoutSeconds
+	if timeout == 0 {
+		timeout = DefaultPromotionHookTimeoutSeconds
+	}
+	return &PromotionHook{
+		Image:          hook.Image,
+		Command:        hook.Command,
+		FailurePolicy:  string(policy),
+		TimeoutSeconds: timeout,
+	}
+}
+
+// PromotionHookRun describes a hook run for a deployment and its logs
+type PromotionHookRun struct {
+	Type               string     `json:"type" example:"PrePromote"`
+	Phase              string     `json:"phase" example:"Succeeded"`
+	JobName            string     `json:"jobName" example:"deployment-123e4567-e89b-12d3-a456-426614174000-pre-promote"`
+	Message            string     `json:"message,omitempty" example:""`
+	StartedAt          *time.Time `json:"startedAt,omitempty" example:"2023-01-01T12:00:00Z"`
+	CompletedAt        *time.Time `json:"completedAt,omitempty" example:"2023-01-01T12:01:00Z"`
+	PreviousDeployment string     `json:"previousDeployment,omitempty" example:"deployment-0b1c2d3e-4f5a-4b6c-8d7e-9f0a1b2c3d4e"`
+	Logs               string     `json:"logs,omitempty" example:"Migrations applied"`
+}
+
+// DeploymentHooksResponse describes the promotion hooks run for a deployment
+type DeploymentHooksResponse struct {
+	DeploymentUUID string             `json:"deploymentUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Hooks          []PromotionHookRun `json:"hooks"`
+}
+
+// NewPromotionHookRun converts the status of a hook run to the API response, without its logs
+func NewPromotionHookRun(status v1alpha1.PromotionHookStatus) PromotionHookRun {
+	run := PromotionHookRun{
+		Type:               string(status.Type),
+		Phase:              string(status.Phase),
+		JobName:            status.JobName,
+		Message:            status.Message,
+		PreviousDeployment: status.PreviousDeployment,
+	}
+	if status.StartTime != nil {
+		run.StartedAt = &status.StartTime.Time
+	}
+	if status.CompletionTime != nil {
+		run.CompletedAt = &status.CompletionTime.Time
+	}
+	return run
+}
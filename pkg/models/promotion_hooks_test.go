@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestPromotionHooksUpdateRequestValidate(t *testing.T) {
+	migrate := &PromotionHook{Command: []string{"npm", "run", "migrate"}}
+
+	tests := []struct {
+		name        string
+		req         PromotionHooksUpdateRequest
+		expectField string
+	}{
+		{
+			name: "valid hooks",
+			req: PromotionHooksUpdateRequest{
+				PrePromote:  migrate,
+				PostPromote: &PromotionHook{Image: "curlimages/curl:8.10.1", Command: []string{"curl", "http://web"}, FailurePolicy: "Continue", TimeoutSeconds: 60},
+			},
+		},
+		{
+			name: "no hooks removes them",
+		},
+		{
+			name:        "missing command",
+			req:         PromotionHooksUpdateRequest{PostPromote: &PromotionHook{Image: "curlimages/curl:8.10.1"}},
+			expectField: "postPromote.command",
+		},
+		{
+			name:        "unknown failure policy",
+			req:         PromotionHooksUpdateRequest{PrePromote: &PromotionHook{Command: migrate.Command, FailurePolicy: "Retry"}},
+			expectField: "prePromote.failurePolicy",
+		},
+		{
+			name:        "timeout too long",
+			req:         PromotionHooksUpdateRequest{PrePromote: &PromotionHook{Command: migrate.Command, TimeoutSeconds: 90000}},
+			expectField: "prePromote.timeoutSeconds",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
+
+func TestPromotionHooksRoundTrip(t *testing.T) {
+	req := &PromotionHooksUpdateRequest{PrePromote: &PromotionHook{Command: []string{"npm", "run", "migrate"}}}
+	if hooks := (&PromotionHooksUpdateRequest{}).ToCRD(); hooks != nil {
+		t.Errorf("expected an empty request to remove the hooks, got %+v", hooks)
+	}
+
+	app := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{validation.LabelResourceUUID: "app-1"}},
+		Spec:       v1alpha1.ApplicationSpec{Hooks: req.ToCRD()},
+	}
+	if policy := app.Spec.Hooks.PrePromote.FailurePolicy; policy != v1alpha1.HookFailurePolicyAbort {
+		t.Errorf("expected hooks to abort by default, got %s", policy)
+	}
+
+	response := NewPromotionHooksResponse(app)
+	if response.ApplicationUUID != "app-1" || response.PostPromote != nil || response.PrePromote == nil {
+		t.Fatalf("unexpected response %+v", response)
+	}
+	if hook := response.PrePromote; hook.TimeoutSeconds != DefaultPromotionHookTimeoutSeconds || hook.FailurePolicy != "Abort" {
+		t.Errorf("expected the defaults to be filled in, got %+v", hook)
+	}
+}
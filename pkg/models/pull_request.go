@@ -0,0 +1,23 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+// PullRequestEventResponse reports the preview environments changed by a pull request event
+type PullRequestEventResponse struct {
+	Action           string   `json:"action" example:"opened"`
+	EnvironmentUUIDs []string `json:"environmentUuids" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
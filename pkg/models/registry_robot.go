@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"regexp"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// MaxRegistryRobotNameLength is the longest name of a registry robot account
+	MaxRegistryRobotNameLength = 40
+
+	// maxRegistryRobotDescriptionLength is the longest description of a registry robot account
+	maxRegistryRobotDescriptionLength = 255
+
+	// RegistryRobotDescriptionAnnotation holds the description of a registry robot account Secret
+	RegistryRobotDescriptionAnnotation = "platform.kibaship.com/description"
+)
+
+var registryRobotNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// RegistryRobotCreateRequest creates a robot account pulling the images of a project from the
+// registry, for external clusters and developer machines
+type RegistryRobotCreateRequest struct {
+	Name        string `json:"name" example:"staging-cluster"`
+	Description string `json:"description,omitempty" example:"Pulls images into the staging cluster"`
+}
+
+// Validate validates the registry robot create request
+func (r *RegistryRobotCreateRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	if !registryRobotNamePattern.MatchString(r.Name) || len(r.Name) > MaxRegistryRobotNameLength {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "name",
+			Message: "name must be a lowercase DNS label of at most 40 characters",
+		})
+	}
+	if len(r.Description) > maxRegistryRobotDescriptionLength {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "description",
+			Message: "description must be at most 255 characters",
+		})
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// RegistryRobotResponse describes a registry robot account of a project. Robot accounts pull the
+// images under <namespace>/ in the registry and cannot push.
+type RegistryRobotResponse struct {
+	Name        string    `json:"name" example:"staging-cluster"`
+	Username    string    `json:"username" example:"robot$project-550e8400-e29b-41d4-a716-446655440000+staging-cluster"`
+	Namespace   string    `json:"namespace" example:"project-550e8400-e29b-41d4-a716-446655440000"`
+	Description string    `json:"description,omitempty" example:"Pulls images into the staging cluster"`
+	CreatedAt   time.Time `json:"createdAt" example:"2023-01-01T12:00:00Z"`
+}
+
+// RegistryRobotCreateResponse is returned once when a robot account is created, it is the only
+// response holding the password
+type RegistryRobotCreateResponse struct {
+	RegistryRobotResponse
+	Password string `json:"password" example:"bXktc2VjcmV0LXBhc3N3b3JkLWZvci10aGUtcm9ib3Q="`
+}
+
+// NewRegistryRobotResponse describes the robot account stored in a Secret, without its password
+func NewRegistryRobotResponse(name string, secret *corev1.Secret) RegistryRobotResponse {
+	return RegistryRobotResponse{
+		Name:        name,
+		Username:    string(secret.Data["username"]),
+		Namespace:   secret.Namespace,
+		Description: secret.Annotations[RegistryRobotDescriptionAnnotation],
+		CreatedAt:   secret.CreationTimestamp.Time,
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistryRobotCreateRequestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         RegistryRobotCreateRequest
+		expectField string
+	}{
+		{
+			name: "valid robot",
+			req:  RegistryRobotCreateRequest{Name: "staging-cluster", Description: "Pulls images into the staging cluster"},
+		},
+		{
+			name:        "missing name",
+			req:         RegistryRobotCreateRequest{},
+			expectField: "name",
+		},
+		{
+			name:        "name with a plus sign",
+			req:         RegistryRobotCreateRequest{Name: "ci+deploy"},
+			expectField: "name",
+		},
+		{
+			name:        "name too long",
+			req:         RegistryRobotCreateRequest{Name: strings.Repeat("a", MaxRegistryRobotNameLength+1)},
+			expectField: "name",
+		},
+		{
+			name:        "description too long",
+			req:         RegistryRobotCreateRequest{Name: "ci", Description: strings.Repeat("a", 256)},
+			expectField: "description",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
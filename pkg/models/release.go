@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ReleaseResponse is a promoted deployment in the release history of an application
+type ReleaseResponse struct {
+	DeploymentUUID string                         `json:"deploymentUuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeploymentSlug string                         `json:"deploymentSlug" example:"def456gh"`
+	Current        bool                           `json:"current" example:"true"`
+	GitRepository  *GitRepositoryDeploymentConfig `json:"gitRepository,omitempty"`
+	Source         *DeploymentSource              `json:"source,omitempty"`
+	ImageDigest    string                         `json:"imageDigest,omitempty" example:"sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"`
+	PromotedFrom   *DeploymentPromotionSource     `json:"promotedFrom,omitempty"`
+	PromotedBy     string                         `json:"promotedBy,omitempty" example:"jane@example.com"`
+	PromotedAt     *time.Time                     `json:"promotedAt,omitempty" example:"2023-01-01T12:05:00Z"`
+	CreatedAt      time.Time                      `json:"createdAt" example:"2023-01-01T12:00:00Z"`
+}
+
+// BuildReleases assembles the release history of an application from its Deployment CRDs,
+// newest first. A deployment is a release once its promotion was recorded. The current
+// deployment is always included, it may predate promotion records.
+func BuildReleases(deployments []v1alpha1.Deployment, currentDeploymentName, applicationSlug string) []ReleaseResponse {
+	releases := make([]ReleaseResponse, 0)
+	for i := range deployments {
+		crd := &deployments[i]
+		current := crd.Name == currentDeploymentName
+		promotedAt, err := time.Parse(time.RFC3339, crd.GetAnnotations()[validation.AnnotationPromotedAt])
+		if err != nil && !current {
+			continue
+		}
+
+		deployment := &Deployment{}
+		deployment.ConvertFromCRD(crd, applicationSlug)
+		release := ReleaseResponse{
+			DeploymentUUID: deployment.UUID,
+			DeploymentSlug: deployment.Slug,
+			Current:        current,
+			GitRepository:  deployment.GitRepository,
+			Source:         deployment.Source,
+			ImageDigest:    deployment.ImageDigest,
+			PromotedFrom:   deployment.PromotedFrom,
+			PromotedBy:     crd.GetAnnotations()[validation.AnnotationPromotedBy],
+			CreatedAt:      deployment.CreatedAt,
+		}
+		if err == nil {
+			release.PromotedAt = &promotedAt
+		}
+		releases = append(releases, release)
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releaseTime(releases[i]).After(releaseTime(releases[j]))
+	})
+	return releases
+}
+
+// releaseTime orders releases by promotion, falling back to creation for unrecorded promotions
+func releaseTime(r ReleaseResponse) time.Time {
+	if r.PromotedAt != nil {
+		return *r.PromotedAt
+	}
+	return r.CreatedAt
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func releaseDeployment(name, uuid string, created time.Time, annotations map[string]string) v1alpha1.Deployment {
+	return v1alpha1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Labels:            map[string]string{validation.LabelResourceUUID: uuid},
+			Annotations:       annotations,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: v1alpha1.DeploymentSpec{
+			GitRepository: &v1alpha1.GitRepositoryDeploymentConfig{CommitSHA: "sha-" + uuid},
+		},
+	}
+}
+
+func TestBuildReleases(t *testing.T) {
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	deployments := []v1alpha1.Deployment{
+		releaseDeployment("deployment-a", "a", base, map[string]string{
+			validation.AnnotationPromotedAt: base.Add(time.Minute).Format(time.RFC3339),
+			validation.AnnotationPromotedBy: validation.PromotedByOperator,
+		}),
+		// Never promoted, not a release
+		releaseDeployment("deployment-b", "b", base.Add(time.Hour), nil),
+		releaseDeployment("deployment-c", "c", base.Add(2*time.Hour), map[string]string{
+			validation.AnnotationPromotedAt:    base.Add(3 * time.Hour).Format(time.RFC3339),
+			validation.AnnotationPromotedBy:    "jane@example.com",
+			validation.AnnotationCommitMessage: "Fix login redirect loop",
+		}),
+		// Current deployment promoted before promotions were recorded
+		releaseDeployment("deployment-d", "d", base.Add(4*time.Hour), nil),
+	}
+
+	releases := BuildReleases(deployments, "deployment-d", "abc123de")
+
+	if len(releases) != 3 {
+		t.Fatalf("expected 3 releases, got %+v", releases)
+	}
+	order := []string{releases[0].DeploymentUUID, releases[1].DeploymentUUID, releases[2].DeploymentUUID}
+	if order[0] != "d" || order[1] != "c" || order[2] != "a" {
+		t.Errorf("expected releases d, c, a, got %v", order)
+	}
+
+	if !releases[0].Current || releases[0].PromotedAt != nil || releases[0].PromotedBy != "" {
+		t.Errorf("expected the current deployment without promotion record, got %+v", releases[0])
+	}
+
+	c := releases[1]
+	if c.Current || c.PromotedBy != "jane@example.com" || c.PromotedAt == nil || !c.PromotedAt.Equal(base.Add(3*time.Hour)) {
+		t.Errorf("unexpected release %+v", c)
+	}
+	if c.Source == nil || c.Source.CommitMessage != "Fix login redirect loop" {
+		t.Errorf("expected commit message, got %+v", c.Source)
+	}
+	if c.GitRepository == nil || c.GitRepository.CommitSHA != "sha-c" {
+		t.Errorf("expected commit sha-c, got %+v", c.GitRepository)
+	}
+
+	if releases := BuildReleases(nil, "", "abc123de"); releases == nil || len(releases) != 0 {
+		t.Errorf("expected an empty release history, got %v", releases)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kibamail/kibaship/pkg/freeze"
+)
+
+// MaxScheduledReplicas is the largest replica count a replica schedule may set
+const MaxScheduledReplicas = 100
+
+// ReplicaWindow runs a different number of replicas during a recurring period
+type ReplicaWindow struct {
+	Name     string `json:"name" example:"business-hours"`
+	Schedule string `json:"schedule" example:"0 9 * * MON-FRI"`
+	Duration string `json:"duration" example:"9h"`
+	Timezone string `json:"timezone,omitempty" example:"Europe/Berlin"`
+	Replicas int32  `json:"replicas" example:"4"`
+}
+
+// ReplicaScheduleUpdateRequest replaces the replica schedule of an application
+type ReplicaScheduleUpdateRequest struct {
+	DefaultReplicas *int32          `json:"defaultReplicas,omitempty" example:"1"`
+	Windows         []ReplicaWindow `json:"windows"`
+}
+
+// Validate validates the replica schedule update request
+func (r *ReplicaScheduleUpdateRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	if r.DefaultReplicas != nil && (*r.DefaultReplicas < 0 || *r.DefaultReplicas > MaxScheduledReplicas) {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "defaultReplicas",
+			Message: fmt.Sprintf("defaultReplicas must be between 0 and %d", MaxScheduledReplicas),
+		})
+	}
+
+	seen := make(map[string]bool, len(r.Windows))
+	for i, window := range r.Windows {
+		field := fmt.Sprintf("windows[%d]", i)
+
+		if !freezeWindowNamePattern.MatchString(window.Name) || len(window.Name) > 63 {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".name",
+				Message: "name must be a lowercase DNS label of at most 63 characters",
+			})
+		} else if seen[window.Name] {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".name",
+				Message: fmt.Sprintf("duplicate replica window name %s", window.Name),
+			})
+		}
+		seen[window.Name] = true
+
+		if _, err := freeze.ParseSchedule(window.Schedule); err != nil {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".schedule",
+				Message: err.Error(),
+			})
+		}
+
+		duration, err := time.ParseDuration(window.Duration)
+		if err == nil {
+			err = freeze.ValidateDuration(duration)
+		}
+		if err != nil {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".duration",
+				Message: fmt.Sprintf("duration must be a positive Go duration such as 9h: %v", err),
+			})
+		}
+
+		if _, err := freeze.LoadLocation(window.Timezone); err != nil {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".timezone",
+				Message: err.Error(),
+			})
+		}
+
+		if window.Replicas < 0 || window.Replicas > MaxScheduledReplicas {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   field + ".replicas",
+				Message: fmt.Sprintf("replicas must be between 0 and %d", MaxScheduledReplicas),
+			})
+		}
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// ReplicaScheduleResponse describes the replica schedule of an application and the replicas it runs now
+type ReplicaScheduleResponse struct {
+	ApplicationUUID string          `json:"applicationUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	DefaultReplicas int32           `json:"defaultReplicas" example:"1"`
+	Windows         []ReplicaWindow `json:"windows"`
+	Replicas        int32           `json:"replicas" example:"4"`
+	ActiveWindow    string          `json:"activeWindow,omitempty" example:"business-hours"`
+	ActiveUntil     *time.Time      `json:"activeUntil,omitempty" example:"2023-01-02T18:00:00Z"`
+	LastScaleTime   *time.Time      `json:"lastScaleTime,omitempty" example:"2023-01-02T09:00:00Z"`
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+)
+
+func TestReplicaScheduleUpdateRequestValidate(t *testing.T) {
+	businessHours := ReplicaWindow{Name: "business-hours", Schedule: "0 9 * * MON-FRI", Duration: "9h", Timezone: "Europe/Berlin", Replicas: 4}
+	negative := int32(-1)
+
+	tests := []struct {
+		name            string
+		defaultReplicas *int32
+		windows         []ReplicaWindow
+		expectField     string
+	}{
+		{
+			name:    "valid windows",
+			windows: []ReplicaWindow{businessHours, {Name: "nightly-batch", Schedule: "0 2 * * *", Duration: "2h", Replicas: 2}},
+		},
+		{
+			name:    "no windows runs the default replicas",
+			windows: nil,
+		},
+		{
+			name:            "negative default replicas",
+			defaultReplicas: &negative,
+			expectField:     "defaultReplicas",
+		},
+		{
+			name:        "duplicate name",
+			windows:     []ReplicaWindow{businessHours, businessHours},
+			expectField: "windows[1].name",
+		},
+		{
+			name:        "invalid schedule",
+			windows:     []ReplicaWindow{{Name: "business-hours", Schedule: "0 9 * *", Duration: "9h", Replicas: 4}},
+			expectField: "windows[0].schedule",
+		},
+		{
+			name:        "invalid duration",
+			windows:     []ReplicaWindow{{Name: "business-hours", Schedule: "0 9 * * MON-FRI", Duration: "nine hours", Replicas: 4}},
+			expectField: "windows[0].duration",
+		},
+		{
+			name:        "too many replicas",
+			windows:     []ReplicaWindow{{Name: "business-hours", Schedule: "0 9 * * MON-FRI", Duration: "9h", Replicas: 500}},
+			expectField: "windows[0].replicas",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &ReplicaScheduleUpdateRequest{DefaultReplicas: tt.defaultReplicas, Windows: tt.windows}
+			errs := req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+)
+
+// serviceBindingPrefixPattern matches the env prefix of a service binding
+var serviceBindingPrefixPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,39}$`)
+
+// ServiceBinding connects an application to a shared database application of its environment
+type ServiceBinding struct {
+	ApplicationUUID string `json:"applicationUuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	EnvPrefix       string `json:"envPrefix,omitempty" example:"DATABASE"`
+	ReadOnly        bool   `json:"readOnly,omitempty" example:"false"`
+}
+
+// ServiceBindingsUpdateRequest replaces the service bindings of an application and decides
+// whether other applications may bind to it
+type ServiceBindingsUpdateRequest struct {
+	Shared   bool             `json:"shared" example:"false"`
+	Bindings []ServiceBinding `json:"bindings"`
+}
+
+// Validate validates the service bindings update request
+func (r *ServiceBindingsUpdateRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	for i, binding := range r.Bindings {
+		if !isValidUUID(binding.ApplicationUUID) {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   fmt.Sprintf("bindings[%d].applicationUuid", i),
+				Message: "applicationUuid must be a valid UUID",
+			})
+		}
+		if binding.EnvPrefix != "" && !serviceBindingPrefixPattern.MatchString(binding.EnvPrefix) {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   fmt.Sprintf("bindings[%d].envPrefix", i),
+				Message: "envPrefix must start with an uppercase letter and contain only uppercase letters, digits and underscores, at most 40 characters",
+			})
+		}
+	}
+	if len(errors.Errors) == 0 {
+		if err := v1alpha1.ValidateServiceBindings("", r.ToCRD()); err != nil {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   "bindings",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// ToCRD converts the bindings of the request to the service bindings of an Application CRD
+func (r *ServiceBindingsUpdateRequest) ToCRD() []v1alpha1.ServiceBinding {
+	if len(r.Bindings) == 0 {
+		return nil
+	}
+	bindings := make([]v1alpha1.ServiceBinding, len(r.Bindings))
+	for i, binding := range r.Bindings {
+		bindings[i] = v1alpha1.ServiceBinding{
+			Application: corev1.LocalObjectReference{Name: utils.GetApplicationResourceName(binding.ApplicationUUID)},
+			EnvPrefix:   binding.EnvPrefix,
+			ReadOnly:    binding.ReadOnly,
+		}
+	}
+	return bindings
+}
+
+// ServiceBindingsResponse describes the service bindings of an application
+type ServiceBindingsResponse struct {
+	ApplicationUUID string           `json:"applicationUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Shared          bool             `json:"shared" example:"false"`
+	Bindings        []ServiceBinding `json:"bindings"`
+}
+
+// NewServiceBindingsResponse converts the service bindings of an Application CRD to the API
+// response, filling in the default env prefix
+func NewServiceBindingsResponse(app *v1alpha1.Application) *ServiceBindingsResponse {
+	response := &ServiceBindingsResponse{
+		ApplicationUUID: app.GetUUID(),
+		Shared:          app.Spec.Shared,
+		Bindings:        make([]ServiceBinding, 0, len(app.Spec.ServiceBindings)),
+	}
+	for _, binding := range app.Spec.ServiceBindings {
+		response.Bindings = append(response.Bindings, ServiceBinding{
+			ApplicationUUID: utils.GetApplicationUUID(binding.Application.Name),
+			EnvPrefix:       binding.Prefix(),
+			ReadOnly:        binding.ReadOnly,
+		})
+	}
+	return response
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestServiceBindingsUpdateRequestValidate(t *testing.T) {
+	const (
+		orders  = "550e8400-e29b-41d4-a716-446655440000"
+		billing = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	)
+
+	tests := []struct {
+		name        string
+		req         ServiceBindingsUpdateRequest
+		expectField string
+	}{
+		{
+			name: "valid bindings",
+			req: ServiceBindingsUpdateRequest{Bindings: []ServiceBinding{
+				{ApplicationUUID: orders},
+				{ApplicationUUID: billing, EnvPrefix: "BILLING_DB", ReadOnly: true},
+			}},
+		},
+		{
+			name: "shared without bindings",
+			req:  ServiceBindingsUpdateRequest{Shared: true},
+		},
+		{
+			name:        "invalid application uuid",
+			req:         ServiceBindingsUpdateRequest{Bindings: []ServiceBinding{{ApplicationUUID: "orders"}}},
+			expectField: "bindings[0].applicationUuid",
+		},
+		{
+			name:        "lowercase prefix",
+			req:         ServiceBindingsUpdateRequest{Bindings: []ServiceBinding{{ApplicationUUID: orders, EnvPrefix: "db"}}},
+			expectField: "bindings[0].envPrefix",
+		},
+		{
+			name: "duplicate default prefix",
+			req: ServiceBindingsUpdateRequest{Bindings: []ServiceBinding{
+				{ApplicationUUID: orders},
+				{ApplicationUUID: billing},
+			}},
+			expectField: "bindings",
+		},
+		{
+			name: "application bound twice",
+			req: ServiceBindingsUpdateRequest{Bindings: []ServiceBinding{
+				{ApplicationUUID: orders},
+				{ApplicationUUID: orders, EnvPrefix: "REPLICA"},
+			}},
+			expectField: "bindings",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
+
+func TestServiceBindingsRoundTrip(t *testing.T) {
+	req := &ServiceBindingsUpdateRequest{Bindings: []ServiceBinding{
+		{ApplicationUUID: "550e8400-e29b-41d4-a716-446655440000", ReadOnly: true},
+	}}
+	if bindings := (&ServiceBindingsUpdateRequest{}).ToCRD(); bindings != nil {
+		t.Errorf("expected an empty request to remove the bindings, got %+v", bindings)
+	}
+
+	app := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{validation.LabelResourceUUID: "app-1"}},
+		Spec:       v1alpha1.ApplicationSpec{ServiceBindings: req.ToCRD()},
+	}
+	if name := app.Spec.ServiceBindings[0].Application.Name; name != "application-550e8400-e29b-41d4-a716-446655440000" {
+		t.Errorf("expected the binding to reference the Application CRD, got %s", name)
+	}
+
+	response := NewServiceBindingsResponse(app)
+	if response.ApplicationUUID != "app-1" || response.Shared || len(response.Bindings) != 1 {
+		t.Fatalf("unexpected response %+v", response)
+	}
+	if binding := response.Bindings[0]; binding.ApplicationUUID != req.Bindings[0].ApplicationUUID ||
+		binding.EnvPrefix != v1alpha1.DefaultServiceBindingEnvPrefix || !binding.ReadOnly {
+		t.Errorf("unexpected binding %+v", binding)
+	}
+}
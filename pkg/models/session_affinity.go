@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+// SessionAffinityUpdateRequest pins each client of an application to one replica with a cookie
+type SessionAffinityUpdateRequest struct {
+	CookieName string `json:"cookieName,omitempty" example:"SERVERID"`
+	TTLSeconds int32  `json:"ttlSeconds,omitempty" example:"3600"`
+}
+
+// Validate validates the session affinity update request
+func (r *SessionAffinityUpdateRequest) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	if err := v1alpha1.ValidateSessionAffinity(*r.ToCRD()); err != nil {
+		field := "cookieName"
+		if r.TTLSeconds < 0 || r.TTLSeconds > v1alpha1.MaxSessionAffinityTTLSeconds {
+			field = "ttlSeconds"
+		}
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   field,
+			Message: err.Error(),
+		})
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// ToCRD converts the request to the session affinity of an Application CRD
+func (r *SessionAffinityUpdateRequest) ToCRD() *v1alpha1.SessionAffinity {
+	return &v1alpha1.SessionAffinity{
+		CookieName: r.CookieName,
+		TTLSeconds: r.TTLSeconds,
+	}
+}
+
+// SessionAffinityResponse describes the session affinity of an application
+type SessionAffinityResponse struct {
+	ApplicationUUID string `json:"applicationUuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Enabled         bool   `json:"enabled" example:"true"`
+	CookieName      string `json:"cookieName,omitempty" example:"SERVERID"`
+	TTLSeconds      int32  `json:"ttlSeconds,omitempty" example:"3600"`
+}
+
+// NewSessionAffinityResponse converts the session affinity of an Application CRD to the API
+// response, filling in the default cookie name
+func NewSessionAffinityResponse(app *v1alpha1.Application) *SessionAffinityResponse {
+	response := &SessionAffinityResponse{ApplicationUUID: app.GetUUID()}
+	if affinity := app.Spec.SessionAffinity; affinity != nil {
+		response.Enabled = true
+		response.CookieName = affinity.Cookie()
+		response.TTLSeconds = affinity.TTLSeconds
+	}
+	return response
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+)
+
+func TestSessionAffinityUpdateRequestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         SessionAffinityUpdateRequest
+		expectField string
+	}{
+		{
+			name: "default cookie",
+			req:  SessionAffinityUpdateRequest{},
+		},
+		{
+			name: "named cookie with ttl",
+			req:  SessionAffinityUpdateRequest{CookieName: "SERVERID", TTLSeconds: 3600},
+		},
+		{
+			name:        "cookie name with spaces",
+			req:         SessionAffinityUpdateRequest{CookieName: "server id"},
+			expectField: "cookieName",
+		},
+		{
+			name:        "negative ttl",
+			req:         SessionAffinityUpdateRequest{TTLSeconds: -1},
+			expectField: "ttlSeconds",
+		},
+		{
+			name:        "ttl over a year",
+			req:         SessionAffinityUpdateRequest{TTLSeconds: v1alpha1.MaxSessionAffinityTTLSeconds + 1},
+			expectField: "ttlSeconds",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
+
+func TestNewSessionAffinityResponse(t *testing.T) {
+	app := &v1alpha1.Application{}
+	if response := NewSessionAffinityResponse(app); response.Enabled {
+		t.Errorf("expected session affinity to be disabled, got %+v", response)
+	}
+
+	app.Spec.SessionAffinity = &v1alpha1.SessionAffinity{TTLSeconds: 60}
+	response := NewSessionAffinityResponse(app)
+	if !response.Enabled || response.CookieName != v1alpha1.DefaultSessionAffinityCookieName || response.TTLSeconds != 60 {
+		t.Errorf("unexpected session affinity response %+v", response)
+	}
+}
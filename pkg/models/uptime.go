@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/uptime"
+)
+
+// UptimeCheck configures periodic availability probes of an application domain
+type UptimeCheck struct {
+	Interval         string `json:"interval,omitempty" example:"1m"`
+	Path             string `json:"path,omitempty" example:"/healthz"`
+	ExpectedStatus   int32  `json:"expectedStatus,omitempty" example:"200"`
+	Timeout          string `json:"timeout,omitempty" example:"10s"`
+	FailureThreshold int32  `json:"failureThreshold,omitempty" example:"3"`
+}
+
+// Validate validates the uptime check
+func (c *UptimeCheck) Validate() *ValidationErrors {
+	errors := &ValidationErrors{
+		Errors: []ValidationError{},
+	}
+
+	interval := uptime.DefaultInterval
+	if c.Interval != "" {
+		d, err := time.ParseDuration(c.Interval)
+		if err != nil || d < uptime.MinInterval {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   "interval",
+				Message: fmt.Sprintf("interval must be a Go duration of at least %s", uptime.MinInterval),
+			})
+		} else {
+			interval = d
+		}
+	}
+
+	if c.Timeout != "" {
+		d, err := time.ParseDuration(c.Timeout)
+		if err != nil || d <= 0 || d >= interval {
+			errors.Errors = append(errors.Errors, ValidationError{
+				Field:   "timeout",
+				Message: "timeout must be a positive Go duration shorter than the interval",
+			})
+		}
+	}
+
+	if c.Path != "" && !strings.HasPrefix(c.Path, "/") {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "path",
+			Message: "path must start with /",
+		})
+	}
+
+	if c.ExpectedStatus != 0 && (c.ExpectedStatus < 100 || c.ExpectedStatus > 599) {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "expectedStatus",
+			Message: "expectedStatus must be an HTTP status between 100 and 599",
+		})
+	}
+
+	if c.FailureThreshold < 0 || c.FailureThreshold > 10 {
+		errors.Errors = append(errors.Errors, ValidationError{
+			Field:   "failureThreshold",
+			Message: "failureThreshold must be between 1 and 10",
+		})
+	}
+
+	if len(errors.Errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// UptimeStatus reports the results of the uptime check of an application domain
+type UptimeStatus struct {
+	State                  string     `json:"state" example:"Up"`
+	LastCheckTime          *time.Time `json:"lastCheckTime,omitempty" example:"2023-01-01T12:00:00Z"`
+	LastTransitionTime     *time.Time `json:"lastTransitionTime,omitempty" example:"2023-01-01T11:00:00Z"`
+	LastStatusCode         int32      `json:"lastStatusCode,omitempty" example:"200"`
+	LastResponseTimeMillis int64      `json:"lastResponseTimeMillis,omitempty" example:"84"`
+	LastError              string     `json:"lastError,omitempty" example:"unexpected status 503"`
+	ConsecutiveFailures    int32      `json:"consecutiveFailures" example:"0"`
+	UptimePercentage       float64    `json:"uptimePercentage" example:"99.95"`
+}
+
+// uptimeCheckFromCRD converts the uptime check of an ApplicationDomain CRD
+func uptimeCheckFromCRD(check *v1alpha1.UptimeCheck) *UptimeCheck {
+	if check == nil {
+		return nil
+	}
+	return &UptimeCheck{
+		Interval:         check.ProbeInterval().String(),
+		Path:             check.ProbePath(),
+		ExpectedStatus:   check.ExpectedStatus,
+		Timeout:          check.ProbeTimeout().String(),
+		FailureThreshold: check.Threshold(),
+	}
+}
+
+// UptimeStatusFromCRD converts the uptime status of an ApplicationDomain CRD
+func UptimeStatusFromCRD(status *v1alpha1.UptimeStatus) *UptimeStatus {
+	if status == nil {
+		return nil
+	}
+	out := &UptimeStatus{
+		State:                  string(status.State),
+		LastStatusCode:         status.LastStatusCode,
+		LastResponseTimeMillis: status.LastResponseTimeMillis,
+		LastError:              status.LastError,
+		ConsecutiveFailures:    status.ConsecutiveFailures,
+		UptimePercentage:       status.UptimePercentage(),
+	}
+	if status.LastCheckTime != nil {
+		out.LastCheckTime = &status.LastCheckTime.Time
+	}
+	if status.LastTransitionTime != nil {
+		out.LastTransitionTime = &status.LastTransitionTime.Time
+	}
+	return out
+}
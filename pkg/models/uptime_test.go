@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "testing"
+
+func TestUptimeCheckValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		check       UptimeCheck
+		expectField string
+	}{
+		{
+			name:  "defaults",
+			check: UptimeCheck{},
+		},
+		{
+			name:  "full check",
+			check: UptimeCheck{Interval: "5m", Path: "/healthz", ExpectedStatus: 204, Timeout: "30s", FailureThreshold: 5},
+		},
+		{
+			name:        "interval too short",
+			check:       UptimeCheck{Interval: "10s"},
+			expectField: "interval",
+		},
+		{
+			name:        "invalid interval",
+			check:       UptimeCheck{Interval: "every minute"},
+			expectField: "interval",
+		},
+		{
+			name:        "timeout not shorter than interval",
+			check:       UptimeCheck{Interval: "1m", Timeout: "1m"},
+			expectField: "timeout",
+		},
+		{
+			name:        "relative path",
+			check:       UptimeCheck{Path: "healthz"},
+			expectField: "path",
+		},
+		{
+			name:        "invalid expected status",
+			check:       UptimeCheck{ExpectedStatus: 42},
+			expectField: "expectedStatus",
+		},
+		{
+			name:        "failure threshold too large",
+			check:       UptimeCheck{FailureThreshold: 11},
+			expectField: "failureThreshold",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.check.Validate()
+
+			if tt.expectField == "" {
+				if errs != nil {
+					t.Errorf("expected no errors, got %v", errs.Errors)
+				}
+				return
+			}
+
+			if errs == nil {
+				t.Fatalf("expected error on %s, got none", tt.expectField)
+			}
+			if errs.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %v", tt.expectField, errs.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Package pipelines generates the Tekton Pipelines building the git repository deployments.
+//
+// Pipelines are generated from a versioned spec. A deployment records the spec version its first
+// Pipeline was generated with, so regenerating the Pipeline of an old deployment produces the
+// same build even after the generator evolved. Changes that alter generated Pipelines go into a
+// new version, the previous versions stay untouched.
+package pipelines
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+const (
+	// GitCloneTaskName is the name of the git clone task in tekton-pipelines namespace
+	GitCloneTaskName = "tekton-task-git-clone-kibaship-com"
+	// RailpackPrepareTaskName is the name of the railpack prepare task in tekton-pipelines namespace
+	RailpackPrepareTaskName = "tekton-task-railpack-prepare-kibaship-com"
+	// RailpackBuildTaskName is the name of the railpack build task in tekton-pipelines namespace
+	RailpackBuildTaskName = "tekton-task-railpack-build-kibaship-com"
+	// DockerfileBuildTaskName is the name of the Dockerfile build task in tekton-pipelines namespace
+	DockerfileBuildTaskName = "tekton-task-dockerfile-build-kibaship-com"
+	// NixpacksBuildTaskName is the name of the Nixpacks build task in tekton-pipelines namespace
+	NixpacksBuildTaskName = "tekton-task-nixpacks-build-kibaship-com"
+	// BuildpacksBuildTaskName is the name of the Cloud Native Buildpacks build task in tekton-pipelines namespace
+	BuildpacksBuildTaskName = "tekton-task-buildpacks-build-kibaship-com"
+	// PublishArtifactsTaskName is the name of the artifact publishing task in tekton-pipelines namespace
+	PublishArtifactsTaskName = "tekton-task-publish-artifacts-kibaship-com"
+
+	// DefaultGitBranch is the default git branch when none is specified
+	DefaultGitBranch = "main"
+	// DefaultBuildKitHost is the shared buildkitd Deployment builds use without a BuildKit pool,
+	// or when no daemon of the pool is healthy
+	DefaultBuildKitHost = "tcp://buildkitd.buildkit.svc:1234"
+	// ParamBuildKitHost is the Pipeline parameter holding the BuildKit daemon of a build
+	ParamBuildKitHost = "buildkit-host"
+	// ResultImageDigest is the pipeline result holding the digest of the pushed image
+	ResultImageDigest = "image-digest"
+	// ResultExposedPort is the pipeline result holding the port the pushed image exposes
+	ResultExposedPort = "exposed-port"
+	// ArtifactsDirEnvVar tells custom pipeline steps where to write the artifacts they publish
+	ArtifactsDirEnvVar = "KIBASHIP_ARTIFACTS_DIR"
+
+	// artifactsDir is the artifacts directory relative to the pipeline workspace
+	artifactsDir = ".kibaship/artifacts"
+)
+
+const (
+	// AnnotationSpecVersion records the spec version a Pipeline, and the deployment it builds,
+	// were generated with
+	AnnotationSpecVersion = "platform.kibaship.com/pipeline-spec-version"
+
+	// SpecVersionV1 clones the repository, runs the custom steps and builds the image with the
+	// builder of the build type
+	SpecVersionV1 = "v1"
+
+	// SpecVersionV2 builds like SpecVersionV1 and reports the port the image exposes
+	SpecVersionV2 = "v2"
+
+	// SpecVersionV3 builds like SpecVersionV2 and passes the build environment variables of the
+	// application to the builder
+	SpecVersionV3 = "v3"
+
+	// SpecVersionV4 builds like SpecVersionV3 and clones the submodules and Git LFS objects of
+	// the repository when the application enables them
+	SpecVersionV4 = "v4"
+
+	// SpecVersionV5 builds like SpecVersionV4 and clones large repositories shallowly or sparsely
+	// when the application sets a clone depth or sparse checkout paths
+	SpecVersionV5 = "v5"
+
+	// CurrentSpecVersion is the spec version of the Pipelines of new deployments
+	CurrentSpecVersion = SpecVersionV5
+)
+
+// Input is what a Pipeline is generated from
+type Input struct {
+	// Name is the name of the Pipeline
+	Name string
+	// Deployment is the deployment the Pipeline builds, the Pipeline lives in its namespace
+	Deployment *platformv1alpha1.Deployment
+	// GitRepository is the git repository configuration of the application
+	GitRepository *platformv1alpha1.GitRepositoryConfig
+	// ProjectSlug labels the Pipeline with the slug of its project
+	ProjectSlug string
+	// ArtifactsURL is where custom steps publish their artifacts, empty to not publish them
+	ArtifactsURL string
+	// ImagePolicy restricts the base images of Dockerfile builds
+	ImagePolicy config.ImagePolicyConfig
+}
+
+// specBuilder generates the Pipeline of a spec version
+type specBuilder func(in Input) (*tektonv1.Pipeline, error)
+
+// specBuilders are the spec versions Pipelines can be generated with
+var specBuilders = map[string]specBuilder{
+	SpecVersionV1: buildV1,
+	SpecVersionV2: buildV2,
+	SpecVersionV3: buildV3,
+	SpecVersionV4: buildV4,
+	SpecVersionV5: buildV5,
+}
+
+// Build generates the Pipeline of in with a spec version, annotated with that version
+func Build(version string, in Input) (*tektonv1.Pipeline, error) {
+	builder, ok := specBuilders[version]
+	if !ok {
+		return nil, fmt.Errorf("unsupported pipeline spec version %q, supported: %s",
+			version, strings.Join(SpecVersions(), ", "))
+	}
+	if in.GitRepository == nil {
+		return nil, fmt.Errorf("GitRepository configuration is nil")
+	}
+
+	pipeline, err := builder(in)
+	if err != nil {
+		return nil, err
+	}
+	if pipeline.Annotations == nil {
+		pipeline.Annotations = map[string]string{}
+	}
+	pipeline.Annotations[AnnotationSpecVersion] = version
+	return pipeline, nil
+}
+
+// SpecVersion returns the spec version the Pipelines of a deployment are generated with, the
+// current one for deployments that did not record any yet
+func SpecVersion(deployment *platformv1alpha1.Deployment) string {
+	if version := deployment.GetAnnotations()[AnnotationSpecVersion]; version != "" {
+		return version
+	}
+	return CurrentSpecVersion
+}
+
+// SpecVersions returns the supported spec versions, sorted
+func SpecVersions() []string {
+	versions := make([]string, 0, len(specBuilders))
+	for version := range specBuilders {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
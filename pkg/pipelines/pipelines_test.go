@@ -0,0 +1,325 @@
+package pipelines
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/validation"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the generated pipelines")
+
+func taskParam(task tektonv1.PipelineTask, name string) string {
+	for _, param := range task.Params {
+		if param.Name == name {
+			return param.Value.StringVal
+		}
+	}
+	return ""
+}
+
+func testInput(gitConfig *platformv1alpha1.GitRepositoryConfig) Input {
+	return Input{
+		Name: "pipeline-dep-1",
+		Deployment: &platformv1alpha1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:      "deployment-dep-1",
+			Namespace: "project-ns",
+			Labels: map[string]string{
+				validation.LabelResourceUUID:    "dep-1",
+				validation.LabelResourceSlug:    "dep1slug",
+				validation.LabelApplicationUUID: "app-1",
+				validation.LabelProjectUUID:     "project-1",
+			},
+		}},
+		GitRepository: gitConfig,
+		ProjectSlug:   "project",
+	}
+}
+
+// TestBuildGolden compares the generated pipelines of every spec version and build type with
+// their golden files. Run with -update to rewrite them after an intended change to the newest
+// version, the golden files of older versions must not change.
+func TestBuildGolden(t *testing.T) {
+	cases := map[string]Input{
+		"railpack": testInput(&platformv1alpha1.GitRepositoryConfig{
+			Provider:   platformv1alpha1.GitProviderGitHub,
+			Repository: "org/repo",
+			SecretRef:  &corev1.LocalObjectReference{Name: "git-token"},
+		}),
+		"dockerfile": testInput(&platformv1alpha1.GitRepositoryConfig{
+			Provider:     platformv1alpha1.GitProviderGitHub,
+			Repository:   "org/repo",
+			Branch:       "release",
+			PublicAccess: true,
+			BuildType:    platformv1alpha1.BuildTypeDockerfile,
+			DockerfileBuild: &platformv1alpha1.DockerfileBuildConfig{
+				DockerfilePath: "deploy/Dockerfile",
+				BuildContext:   "services/api",
+				BuildArgs:      map[string]string{"NODE_VERSION": "22", "APP_ENV": "production"},
+			},
+		}),
+		"nixpacks": testInput(&platformv1alpha1.GitRepositoryConfig{
+			Provider:      platformv1alpha1.GitProviderGitHub,
+			Repository:    "org/repo",
+			PublicAccess:  true,
+			RootDirectory: "services/api",
+			BuildType:     platformv1alpha1.BuildTypeNixpacks,
+		}),
+		"buildpacks": testInput(&platformv1alpha1.GitRepositoryConfig{
+			Provider:        platformv1alpha1.GitProviderGitHub,
+			Repository:      "org/repo",
+			PublicAccess:    true,
+			BuildType:       platformv1alpha1.BuildTypeBuildpacks,
+			BuildpacksBuild: &platformv1alpha1.BuildpacksBuildConfig{BuilderImage: "heroku/builder:24"},
+		}),
+		"steps": func() Input {
+			in := testInput(&platformv1alpha1.GitRepositoryConfig{
+				Provider:      platformv1alpha1.GitProviderGitHub,
+				Repository:    "org/repo",
+				PublicAccess:  true,
+				RootDirectory: "./web/",
+				Steps: []platformv1alpha1.PipelineStep{
+					{Name: "lint", Image: "node:20", Script: "npm run lint"},
+					{Name: "test", Image: "node:20", Script: "#!/bin/bash\nnpm test"},
+				},
+			})
+			in.ArtifactsURL = "http://artifacts.kibaship.svc"
+			return in
+		}(),
+	}
+
+	for _, version := range SpecVersions() {
+		for name, in := range cases {
+			t.Run(version+"/"+name, func(t *testing.T) {
+				g := NewWithT(t)
+
+				pipeline, err := Build(version, in)
+				g.Expect(err).NotTo(HaveOccurred())
+				generated, err := yaml.Marshal(pipeline)
+				g.Expect(err).NotTo(HaveOccurred())
+
+				golden := filepath.Join("testdata", version, name+".yaml")
+				if *update {
+					g.Expect(os.MkdirAll(filepath.Dir(golden), 0o755)).To(Succeed())
+					g.Expect(os.WriteFile(golden, generated, 0o644)).To(Succeed())
+				}
+				expected, err := os.ReadFile(golden)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(string(generated)).To(Equal(string(expected)))
+			})
+		}
+	}
+}
+
+func TestBuildSpecVersion(t *testing.T) {
+	g := NewWithT(t)
+	in := testInput(&platformv1alpha1.GitRepositoryConfig{Provider: platformv1alpha1.GitProviderGitHub, Repository: "org/repo"})
+
+	pipeline, err := Build(SpecVersionV1, in)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pipeline.Annotations).To(HaveKeyWithValue(AnnotationSpecVersion, SpecVersionV1))
+
+	_, err = Build("v0", in)
+	g.Expect(err).To(MatchError(ContainSubstring(`unsupported pipeline spec version "v0"`)))
+
+	in.GitRepository = nil
+	_, err = Build(CurrentSpecVersion, in)
+	g.Expect(err).To(HaveOccurred())
+
+	// Deployments without a recorded version use the current one
+	g.Expect(SpecVersion(in.Deployment)).To(Equal(CurrentSpecVersion))
+	in.Deployment.Annotations = map[string]string{AnnotationSpecVersion: SpecVersionV1}
+	g.Expect(SpecVersion(in.Deployment)).To(Equal(SpecVersionV1))
+}
+
+func TestBuildBuilderPipelines(t *testing.T) {
+	g := NewWithT(t)
+	in := testInput(&platformv1alpha1.GitRepositoryConfig{
+		Provider:      platformv1alpha1.GitProviderGitHub,
+		Repository:    "org/repo",
+		PublicAccess:  true,
+		RootDirectory: "services/api",
+		BuildType:     platformv1alpha1.BuildTypeNixpacks,
+	})
+
+	pipeline, err := Build(CurrentSpecVersion, in)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pipeline.Labels["platform.kibaship.com/build-type"]).To(Equal("Nixpacks"))
+	g.Expect(pipeline.Spec.Tasks).To(HaveLen(2))
+	build := pipeline.Spec.Tasks[1]
+	g.Expect(build.Name).To(Equal("build-nixpacks"))
+	g.Expect(build.TaskRef.Params[1].Value.StringVal).To(Equal(NixpacksBuildTaskName))
+	g.Expect(taskParam(build, "contextPath")).To(Equal("services/api"))
+	g.Expect(taskParam(build, "buildkitHost")).To(Equal("$(params." + ParamBuildKitHost + ")"))
+
+	// Buildpacks builds use the default builder unless one is configured
+	in.GitRepository.BuildType = platformv1alpha1.BuildTypeBuildpacks
+	pipeline, err = Build(CurrentSpecVersion, in)
+	g.Expect(err).NotTo(HaveOccurred())
+	build = pipeline.Spec.Tasks[1]
+	g.Expect(build.Name).To(Equal("build-buildpacks"))
+	g.Expect(build.TaskRef.Params[1].Value.StringVal).To(Equal(BuildpacksBuildTaskName))
+	g.Expect(taskParam(build, "builderImage")).To(Equal(platformv1alpha1.DefaultBuildpacksBuilderImage))
+	g.Expect(pipeline.Spec.Results[3].Value.StringVal).To(Equal("$(tasks.build-buildpacks.results.imageDigest)"))
+
+	in.GitRepository.BuildpacksBuild = &platformv1alpha1.BuildpacksBuildConfig{BuilderImage: "heroku/builder:24"}
+	pipeline, err = Build(CurrentSpecVersion, in)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(taskParam(pipeline.Spec.Tasks[1], "builderImage")).To(Equal("heroku/builder:24"))
+}
+
+func TestBuildDockerfileBuildArgs(t *testing.T) {
+	g := NewWithT(t)
+	in := testInput(&platformv1alpha1.GitRepositoryConfig{
+		Provider:     platformv1alpha1.GitProviderGitHub,
+		Repository:   "org/repo",
+		PublicAccess: true,
+		BuildType:    platformv1alpha1.BuildTypeDockerfile,
+		DockerfileBuild: &platformv1alpha1.DockerfileBuildConfig{
+			DockerfilePath: "Dockerfile",
+			BuildArgs:      map[string]string{"NODE_VERSION": "22", "APP_ENV": "production"},
+			BuildSecrets:   &corev1.LocalObjectReference{Name: "npm-registry-token"},
+		},
+	})
+
+	pipeline, err := Build(CurrentSpecVersion, in)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	build := pipeline.Spec.Tasks[1]
+	var buildArgs tektonv1.ParamValue
+	for _, param := range build.Params {
+		if param.Name == "buildArgs" {
+			buildArgs = param.Value
+		}
+	}
+	g.Expect(buildArgs.Type).To(Equal(tektonv1.ParamTypeArray))
+	g.Expect(buildArgs.ArrayVal).To(Equal([]string{"APP_ENV=production", "NODE_VERSION=22"}))
+	g.Expect(build.Workspaces).To(ContainElement(tektonv1.WorkspacePipelineTaskBinding{Name: "build-secrets", Workspace: "build-secrets"}))
+	g.Expect(pipeline.Spec.Workspaces).To(ContainElement(HaveField("Name", "build-secrets")))
+
+	// Dockerfile builds need their configuration
+	in.GitRepository.DockerfileBuild = nil
+	_, err = Build(CurrentSpecVersion, in)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestBuildDockerfileImagePolicy(t *testing.T) {
+	g := NewWithT(t)
+	in := testInput(&platformv1alpha1.GitRepositoryConfig{
+		Provider:        platformv1alpha1.GitProviderGitHub,
+		Repository:      "org/repo",
+		PublicAccess:    true,
+		BuildType:       platformv1alpha1.BuildTypeDockerfile,
+		DockerfileBuild: &platformv1alpha1.DockerfileBuildConfig{DockerfilePath: "Dockerfile"},
+	})
+	in.ImagePolicy = config.ImagePolicyConfig{
+		Allowed: []string{"ghcr.io/acme/*", "docker.io/library/*"},
+		Denied:  []string{"docker.io/library/ubuntu"},
+	}
+
+	pipeline, err := Build(CurrentSpecVersion, in)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	build := pipeline.Spec.Tasks[1]
+	g.Expect(taskParam(build, "allowedImages")).To(Equal("ghcr.io/acme/* docker.io/library/*"))
+	g.Expect(taskParam(build, "deniedImages")).To(Equal("docker.io/library/ubuntu"))
+}
+
+func TestBuildExposedPortResult(t *testing.T) {
+	g := NewWithT(t)
+	in := testInput(&platformv1alpha1.GitRepositoryConfig{Provider: platformv1alpha1.GitProviderGitHub, Repository: "org/repo"})
+
+	pipeline, err := Build(SpecVersionV2, in)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pipeline.Spec.Results).To(ContainElement(HaveField("Value.StringVal", "$(tasks.build.results.exposedPort)")))
+
+	// Version 1 Pipelines do not report the port
+	pipeline, err = Build(SpecVersionV1, in)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pipeline.Spec.Results).NotTo(ContainElement(HaveField("Name", ResultExposedPort)))
+
+	// Builders that cannot detect the port do not report it either
+	in.GitRepository.BuildType = platformv1alpha1.BuildTypeNixpacks
+	pipeline, err = Build(SpecVersionV2, in)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pipeline.Spec.Results).NotTo(ContainElement(HaveField("Name", ResultExposedPort)))
+}
+
+func TestBuildEnvWorkspace(t *testing.T) {
+	g := NewWithT(t)
+	in := testInput(&platformv1alpha1.GitRepositoryConfig{Provider: platformv1alpha1.GitProviderGitHub, Repository: "org/repo"})
+
+	pipeline, err := Build(SpecVersionV3, in)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pipeline.Spec.Workspaces).To(ContainElement(HaveField("Name", WorkspaceBuildEnv)))
+	binding := tektonv1.WorkspacePipelineTaskBinding{Name: WorkspaceBuildEnv, Workspace: WorkspaceBuildEnv}
+	g.Expect(pipeline.Spec.Tasks[1].Workspaces).To(ContainElement(binding))
+	g.Expect(pipeline.Spec.Tasks[2].Workspaces).To(ContainElement(binding))
+	g.Expect(SupportsBuildEnv(SpecVersionV3, "")).To(BeTrue())
+
+	// Version 2 Pipelines do not declare the workspace
+	pipeline, err = Build(SpecVersionV2, in)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pipeline.Spec.Workspaces).NotTo(ContainElement(HaveField("Name", WorkspaceBuildEnv)))
+	g.Expect(SupportsBuildEnv(SpecVersionV2, "")).To(BeFalse())
+
+	// Nixpacks would store the variables in the image
+	in.GitRepository.BuildType = platformv1alpha1.BuildTypeNixpacks
+	pipeline, err = Build(SpecVersionV3, in)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pipeline.Spec.Workspaces).NotTo(ContainElement(HaveField("Name", WorkspaceBuildEnv)))
+	g.Expect(SupportsBuildEnv(SpecVersionV3, platformv1alpha1.BuildTypeNixpacks)).To(BeFalse())
+}
+
+func TestBuildGitCloneOptions(t *testing.T) {
+	g := NewWithT(t)
+	in := testInput(&platformv1alpha1.GitRepositoryConfig{
+		Provider:      platformv1alpha1.GitProviderGitHub,
+		Repository:    "org/repo",
+		GitSubmodules: true,
+		GitLFS:        true,
+	})
+
+	pipeline, err := Build(SpecVersionV4, in)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(taskParam(pipeline.Spec.Tasks[0], ParamGitSubmodules)).To(Equal("true"))
+	g.Expect(taskParam(pipeline.Spec.Tasks[0], ParamGitLFS)).To(Equal("true"))
+
+	// Version 3 Pipelines leave the clone task defaults
+	pipeline, err = Build(SpecVersionV3, in)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(taskParam(pipeline.Spec.Tasks[0], ParamGitSubmodules)).To(BeEmpty())
+	g.Expect(taskParam(pipeline.Spec.Tasks[0], ParamGitLFS)).To(BeEmpty())
+}
+
+func TestBuildShallowSparseClone(t *testing.T) {
+	g := NewWithT(t)
+	in := testInput(&platformv1alpha1.GitRepositoryConfig{
+		Provider:            platformv1alpha1.GitProviderGitHub,
+		Repository:          "org/monorepo",
+		RootDirectory:       "services/api",
+		CloneDepth:          1,
+		SparseCheckoutPaths: []string{"services/api", "packages/shared"},
+	})
+
+	pipeline, err := Build(SpecVersionV5, in)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(taskParam(pipeline.Spec.Tasks[0], ParamCloneDepth)).To(Equal("1"))
+	g.Expect(taskParam(pipeline.Spec.Tasks[0], ParamSparseCheckoutPaths)).To(Equal("services/api\npackages/shared"))
+
+	// Version 4 Pipelines clone the full repository
+	pipeline, err = Build(SpecVersionV4, in)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(taskParam(pipeline.Spec.Tasks[0], ParamCloneDepth)).To(BeEmpty())
+	g.Expect(taskParam(pipeline.Spec.Tasks[0], ParamSparseCheckoutPaths)).To(BeEmpty())
+}
@@ -0,0 +1,580 @@
+package pipelines
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/utils"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// buildV1 generates a version 1 Pipeline based on the BuildType of the application
+func buildV1(in Input) (*tektonv1.Pipeline, error) {
+	// Determine BuildType (default to Railpack for backward compatibility)
+	buildType := in.GitRepository.BuildType
+	if buildType == "" {
+		buildType = platformv1alpha1.BuildTypeRailpack
+	}
+
+	switch buildType {
+	case platformv1alpha1.BuildTypeRailpack:
+		return railpackV1(in)
+	case platformv1alpha1.BuildTypeDockerfile:
+		return dockerfileV1(in)
+	case platformv1alpha1.BuildTypeNixpacks:
+		return nixpacksV1(in)
+	case platformv1alpha1.BuildTypeBuildpacks:
+		return buildpacksV1(in)
+	default:
+		return nil, fmt.Errorf("unsupported BuildType: %s", buildType)
+	}
+}
+
+// railpackV1 generates a Tekton Pipeline for Railpack builds
+func railpackV1(in Input) (*tektonv1.Pipeline, error) {
+	deployment, gitConfig := in.Deployment, in.GitRepository
+	deploymentSlug := deployment.GetSlug()
+	deploymentUUID := deployment.GetUUID()
+	projectUUID := deployment.GetProjectUUID()
+
+	// Construct git URL from provider and repository
+	gitURL := fmt.Sprintf("https://%s/%s", gitConfig.Provider, gitConfig.Repository)
+
+	// Get branch (use default if empty)
+	gitBranch := gitConfig.Branch
+	if gitBranch == "" {
+		gitBranch = DefaultGitBranch
+	}
+
+	// Get secret name (only if not public access)
+	var tokenSecret string
+	if !gitConfig.PublicAccess && gitConfig.SecretRef != nil {
+		tokenSecret = gitConfig.SecretRef.Name
+	}
+
+	// Generate workspace name based on deployment UUID
+	workspaceName := fmt.Sprintf("workspace-%s", deploymentUUID)
+
+	pipeline := &tektonv1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      in.Name,
+			Namespace: deployment.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":                 fmt.Sprintf("project-%s", projectUUID),
+				"app.kubernetes.io/managed-by":           "kibaship",
+				"app.kubernetes.io/component":            "ci-cd-pipeline",
+				"tekton.dev/pipeline":                    "git-repository-railpack",
+				"project.kibaship.com/slug":              in.ProjectSlug,
+				"platform.kibaship.com/deployment-uuid":  deployment.Labels["platform.kibaship.com/uuid"],
+				"platform.kibaship.com/application-uuid": deployment.Labels["platform.kibaship.com/application-uuid"],
+				"platform.kibaship.com/project-uuid":     deployment.Labels["platform.kibaship.com/project-uuid"],
+				"platform.kibaship.com/build-type":       string(platformv1alpha1.BuildTypeRailpack),
+			},
+			Annotations: map[string]string{
+				"description":                fmt.Sprintf("CI/CD pipeline for deployment %s using Railpack build", deploymentSlug),
+				"project.kibaship.com/usage": "Clones repository, prepares with Railpack, builds and pushes image",
+				"tekton.dev/displayName":     fmt.Sprintf("Deployment %s Railpack Pipeline", deploymentSlug),
+			},
+		},
+		Spec: tektonv1.PipelineSpec{
+			Description: "Pipeline that builds applications using Railpack. Clones source code from Git, runs railpack prepare, builds the image with BuildKit, and pushes to registry.",
+			Params: []tektonv1.ParamSpec{
+				{
+					Name:        "git-commit",
+					Description: "Specific commit hash to checkout",
+					Type:        tektonv1.ParamTypeString,
+				},
+				{
+					Name:        "git-branch",
+					Description: "Git branch to checkout (optional, defaults to configured branch)",
+					Type:        tektonv1.ParamTypeString,
+					Default:     &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: gitBranch},
+				},
+				{
+					Name:        ParamBuildKitHost,
+					Description: "Address of the BuildKit daemon building the image",
+					Type:        tektonv1.ParamTypeString,
+					Default:     &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: DefaultBuildKitHost},
+				},
+			},
+			Workspaces: []tektonv1.PipelineWorkspaceDeclaration{
+				{
+					Name:        workspaceName,
+					Description: "Workspace where the cloned source code will be stored",
+				},
+				{
+					Name:        "registry-docker-config",
+					Description: "Docker config for registry authentication",
+					Optional:    true,
+				},
+				{
+					Name:        "registry-ca-cert",
+					Description: "Registry CA certificate for TLS trust",
+					Optional:    true,
+				},
+				{
+					Name:        "app-env-vars",
+					Description: "Application environment variables from secret",
+					Optional:    true,
+				},
+			},
+			Tasks: []tektonv1.PipelineTask{
+				{
+					Name: "clone-repository",
+					TaskRef: &tektonv1.TaskRef{
+						ResolverRef: tektonv1.ResolverRef{
+							Resolver: "cluster",
+							Params: []tektonv1.Param{
+								{
+									Name:  "kind",
+									Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "task"},
+								},
+								{
+									Name:  "name",
+									Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: GitCloneTaskName},
+								},
+								{
+									Name:  "namespace",
+									Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "tekton-pipelines"},
+								},
+							},
+						},
+					},
+					Params: []tektonv1.Param{
+						{
+							Name:  "url",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: gitURL},
+						},
+						{
+							Name:  "branch",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(params.git-branch)"},
+						},
+						{
+							Name:  "commit",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(params.git-commit)"},
+						},
+						{
+							Name:  "token-secret",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: tokenSecret},
+						},
+						{
+							Name:  "public-access",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("%t", gitConfig.PublicAccess)},
+						},
+					},
+					Workspaces: []tektonv1.WorkspacePipelineTaskBinding{
+						{
+							Name:      "output",
+							Workspace: workspaceName,
+						},
+					},
+				},
+				{
+					Name:     "prepare",
+					RunAfter: []string{"clone-repository"},
+					TaskRef: &tektonv1.TaskRef{
+						ResolverRef: tektonv1.ResolverRef{
+							Resolver: "cluster",
+							Params: []tektonv1.Param{
+								{Name: "kind", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "task"}},
+								{Name: "name", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: RailpackPrepareTaskName}},
+								{Name: "namespace", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "tekton-pipelines"}},
+							},
+						},
+					},
+					Params: []tektonv1.Param{
+						{Name: "contextPath", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: func() string {
+							if gitConfig.RootDirectory == "" {
+								return "."
+							}
+							return gitConfig.RootDirectory
+						}()}},
+						{Name: "railpackVersion", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "0.1.2"}},
+					},
+					Workspaces: []tektonv1.WorkspacePipelineTaskBinding{
+						{Name: "output", Workspace: workspaceName},
+					},
+				},
+				{
+					Name:     "build",
+					RunAfter: []string{"prepare"},
+					TaskRef: &tektonv1.TaskRef{
+						ResolverRef: tektonv1.ResolverRef{
+							Resolver: "cluster",
+							Params: []tektonv1.Param{
+								{Name: "kind", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "task"}},
+								{Name: "name", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: RailpackBuildTaskName}},
+								{Name: "namespace", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "tekton-pipelines"}},
+							},
+						},
+					},
+					Params: []tektonv1.Param{
+						{Name: "contextPath", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: func() string {
+							if gitConfig.RootDirectory == "" {
+								return "."
+							}
+							return gitConfig.RootDirectory
+						}()}},
+						{Name: "railpackFrontendSource", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "ghcr.io/railwayapp/railpack-frontend:v0.9.0"}},
+						{Name: "imageTag", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: utils.GetBuiltImageName(deployment.Namespace, deployment.GetApplicationUUID(), deployment.GetUUID(), "")}},
+						{Name: "buildkitHost", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(params." + ParamBuildKitHost + ")"}},
+					},
+					Workspaces: []tektonv1.WorkspacePipelineTaskBinding{
+						{Name: "output", Workspace: workspaceName},
+						{Name: "docker-config", Workspace: "registry-docker-config"},
+						{Name: "registry-ca", Workspace: "registry-ca-cert"},
+						{Name: "app-env-vars", Workspace: "app-env-vars"},
+					},
+				},
+			},
+			Results: []tektonv1.PipelineResult{
+				{
+					Name:        "commit-sha",
+					Description: "The actual commit SHA that was checked out",
+					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.clone-repository.results.commit)"},
+				},
+				{
+					Name:        "repository-url",
+					Description: "The repository URL that was cloned",
+					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.clone-repository.results.url)"},
+				},
+				{
+					Name:        ResultImageDigest,
+					Description: "The digest of the image that was built and pushed",
+					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.build.results.imageDigest)"},
+				},
+			},
+		},
+	}
+
+	addStepsV1(pipeline, in, workspaceName)
+
+	return pipeline, nil
+}
+
+// dockerfileV1 generates a Tekton Pipeline for Dockerfile builds
+func dockerfileV1(in Input) (*tektonv1.Pipeline, error) {
+	deployment, gitConfig := in.Deployment, in.GitRepository
+	deploymentSlug := deployment.GetSlug()
+	deploymentUUID := deployment.GetUUID()
+	projectUUID := deployment.GetProjectUUID()
+
+	// Validate DockerfileBuild configuration
+	if gitConfig.DockerfileBuild == nil {
+		return nil, fmt.Errorf("DockerfileBuild configuration is required for Dockerfile BuildType")
+	}
+
+	// Get Dockerfile configuration
+	dockerfilePath := gitConfig.DockerfileBuild.DockerfilePath
+	if dockerfilePath == "" {
+		dockerfilePath = "Dockerfile" // Default
+	}
+
+	buildContext := gitConfig.DockerfileBuild.BuildContext
+	if buildContext == "" {
+		buildContext = "." // Default to root
+	}
+
+	// Build args are passed in a stable order so the pipeline does not change between reconciles
+	buildArgs := make([]string, 0, len(gitConfig.DockerfileBuild.BuildArgs))
+	for name, value := range gitConfig.DockerfileBuild.BuildArgs {
+		buildArgs = append(buildArgs, name+"="+value)
+	}
+	sort.Strings(buildArgs)
+
+	// FROM lines are checked against the image policy before the build starts
+	imagePolicy := in.ImagePolicy
+
+	// Construct git URL from provider and repository
+	gitURL := fmt.Sprintf("https://%s/%s", gitConfig.Provider, gitConfig.Repository)
+
+	// Get branch (use default if empty)
+	gitBranch := gitConfig.Branch
+	if gitBranch == "" {
+		gitBranch = DefaultGitBranch
+	}
+
+	// Get secret name (only if not public access)
+	var tokenSecret string
+	if !gitConfig.PublicAccess && gitConfig.SecretRef != nil {
+		tokenSecret = gitConfig.SecretRef.Name
+	}
+
+	// Generate workspace name based on deployment UUID
+	workspaceName := fmt.Sprintf("workspace-%s", deploymentUUID)
+
+	pipeline := &tektonv1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      in.Name,
+			Namespace: deployment.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":                 fmt.Sprintf("project-%s", projectUUID),
+				"app.kubernetes.io/managed-by":           "kibaship",
+				"app.kubernetes.io/component":            "ci-cd-pipeline",
+				"tekton.dev/pipeline":                    "git-repository-dockerfile",
+				"project.kibaship.com/slug":              in.ProjectSlug,
+				"platform.kibaship.com/deployment-uuid":  deployment.Labels["platform.kibaship.com/uuid"],
+				"platform.kibaship.com/application-uuid": deployment.Labels["platform.kibaship.com/application-uuid"],
+				"platform.kibaship.com/project-uuid":     deployment.Labels["platform.kibaship.com/project-uuid"],
+				"platform.kibaship.com/build-type":       string(platformv1alpha1.BuildTypeDockerfile),
+			},
+			Annotations: map[string]string{
+				"description":                fmt.Sprintf("CI/CD pipeline for deployment %s using Dockerfile build", deploymentSlug),
+				"project.kibaship.com/usage": fmt.Sprintf("Clones repository, builds image from %s, and pushes to registry", dockerfilePath),
+				"tekton.dev/displayName":     fmt.Sprintf("Deployment %s Dockerfile Pipeline", deploymentSlug),
+			},
+		},
+		Spec: tektonv1.PipelineSpec{
+			Description: fmt.Sprintf("Pipeline that builds applications using Dockerfile. Clones source code from Git, builds the image from %s using BuildKit, and pushes to registry.", dockerfilePath),
+			Params: []tektonv1.ParamSpec{
+				{
+					Name:        "git-commit",
+					Description: "Specific commit hash to checkout",
+					Type:        tektonv1.ParamTypeString,
+				},
+				{
+					Name:        "git-branch",
+					Description: "Git branch to checkout (optional, defaults to configured branch)",
+					Type:        tektonv1.ParamTypeString,
+					Default:     &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: gitBranch},
+				},
+				{
+					Name:        ParamBuildKitHost,
+					Description: "Address of the BuildKit daemon building the image",
+					Type:        tektonv1.ParamTypeString,
+					Default:     &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: DefaultBuildKitHost},
+				},
+			},
+			Workspaces: []tektonv1.PipelineWorkspaceDeclaration{
+				{
+					Name:        workspaceName,
+					Description: "Workspace where the cloned source code will be stored",
+				},
+				{
+					Name:        "registry-docker-config",
+					Description: "Docker config for registry authentication",
+					Optional:    false, // Required for Dockerfile builds
+				},
+				{
+					Name:        "registry-ca-cert",
+					Description: "Registry CA certificate for TLS trust",
+					Optional:    false, // Required for Dockerfile builds
+				},
+				{
+					Name:        "app-env-vars",
+					Description: "Application environment variables from secret",
+					Optional:    true,
+				},
+				{
+					Name:        "build-secrets",
+					Description: "Secrets exposed to the build as BuildKit secret mounts",
+					Optional:    true,
+				},
+			},
+			Tasks: []tektonv1.PipelineTask{
+				{
+					Name: "clone-repository",
+					TaskRef: &tektonv1.TaskRef{
+						ResolverRef: tektonv1.ResolverRef{
+							Resolver: "cluster",
+							Params: []tektonv1.Param{
+								{
+									Name:  "kind",
+									Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "task"},
+								},
+								{
+									Name:  "name",
+									Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: GitCloneTaskName},
+								},
+								{
+									Name:  "namespace",
+									Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "tekton-pipelines"},
+								},
+							},
+						},
+					},
+					Params: []tektonv1.Param{
+						{
+							Name:  "url",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: gitURL},
+						},
+						{
+							Name:  "branch",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(params.git-branch)"},
+						},
+						{
+							Name:  "commit",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(params.git-commit)"},
+						},
+						{
+							Name:  "token-secret",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: tokenSecret},
+						},
+						{
+							Name:  "public-access",
+							Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("%t", gitConfig.PublicAccess)},
+						},
+					},
+					Workspaces: []tektonv1.WorkspacePipelineTaskBinding{
+						{
+							Name:      "output",
+							Workspace: workspaceName,
+						},
+					},
+				},
+				{
+					Name:     "build-dockerfile",
+					RunAfter: []string{"clone-repository"},
+					TaskRef: &tektonv1.TaskRef{
+						ResolverRef: tektonv1.ResolverRef{
+							Resolver: "cluster",
+							Params: []tektonv1.Param{
+								{Name: "kind", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "task"}},
+								{Name: "name", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: DockerfileBuildTaskName}},
+								{Name: "namespace", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "tekton-pipelines"}},
+							},
+						},
+					},
+					Params: []tektonv1.Param{
+						{Name: "dockerfilePath", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: dockerfilePath}},
+						{Name: "contextPath", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: buildContext}},
+						{Name: "buildArgs", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeArray, ArrayVal: buildArgs}},
+						{Name: "imageTag", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: utils.GetBuiltImageName(deployment.Namespace, deployment.GetApplicationUUID(), deployment.GetUUID(), "")}},
+						{Name: "buildkitHost", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(params." + ParamBuildKitHost + ")"}},
+						{Name: "allowedImages", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: strings.Join(imagePolicy.Allowed, " ")}},
+						{Name: "deniedImages", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: strings.Join(imagePolicy.Denied, " ")}},
+					},
+					Workspaces: []tektonv1.WorkspacePipelineTaskBinding{
+						{Name: "output", Workspace: workspaceName},
+						{Name: "docker-config", Workspace: "registry-docker-config"},
+						{Name: "registry-ca", Workspace: "registry-ca-cert"},
+						{Name: "app-env-vars", Workspace: "app-env-vars"},
+						{Name: "build-secrets", Workspace: "build-secrets"},
+					},
+				},
+			},
+			Results: []tektonv1.PipelineResult{
+				{
+					Name:        "commit-sha",
+					Description: "The actual commit SHA that was checked out",
+					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.clone-repository.results.commit)"},
+				},
+				{
+					Name:        "repository-url",
+					Description: "The repository URL that was cloned",
+					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.clone-repository.results.url)"},
+				},
+				{
+					Name:        "build-output",
+					Description: "The image tag that was built and pushed",
+					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.build-dockerfile.results.buildOutput)"},
+				},
+				{
+					Name:        ResultImageDigest,
+					Description: "The digest of the image that was built and pushed",
+					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.build-dockerfile.results.imageDigest)"},
+				},
+			},
+		},
+	}
+
+	addStepsV1(pipeline, in, workspaceName)
+
+	return pipeline, nil
+}
+
+// addStepsV1 runs the custom steps of the application in order between the clone and the
+// build tasks. When artifact collection is enabled a finally task uploads whatever the steps wrote
+// to the artifacts directory, so test reports of failed steps are published too.
+func addStepsV1(pipeline *tektonv1.Pipeline, in Input, workspaceName string) {
+	gitConfig := in.GitRepository
+	if len(gitConfig.Steps) == 0 {
+		return
+	}
+
+	workingDir := "$(workspaces.source.path)"
+	if rootDir := strings.Trim(strings.TrimPrefix(gitConfig.RootDirectory, "./"), "/"); rootDir != "" && rootDir != "." {
+		workingDir += "/" + rootDir
+	}
+
+	previous := "clone-repository"
+	steps := make([]tektonv1.PipelineTask, 0, len(gitConfig.Steps))
+	for _, step := range gitConfig.Steps {
+		name := "step-" + step.Name
+		steps = append(steps, tektonv1.PipelineTask{
+			Name:     name,
+			RunAfter: []string{previous},
+			TaskSpec: &tektonv1.EmbeddedTask{
+				TaskSpec: tektonv1.TaskSpec{
+					Description: fmt.Sprintf("Custom pipeline step %s", step.Name),
+					Workspaces:  []tektonv1.WorkspaceDeclaration{{Name: "source"}},
+					Steps: []tektonv1.Step{{
+						Name:       "run",
+						Image:      step.Image,
+						WorkingDir: workingDir,
+						Env: []corev1.EnvVar{{
+							Name:  ArtifactsDirEnvVar,
+							Value: "$(workspaces.source.path)/" + artifactsDir,
+						}},
+						Script: stepScript(step.Script),
+					}},
+				},
+			},
+			Workspaces: []tektonv1.WorkspacePipelineTaskBinding{
+				{Name: "source", Workspace: workspaceName},
+			},
+		})
+		previous = name
+	}
+
+	// The build waits for the last step instead of the clone
+	for i := range pipeline.Spec.Tasks {
+		for j, after := range pipeline.Spec.Tasks[i].RunAfter {
+			if after == "clone-repository" {
+				pipeline.Spec.Tasks[i].RunAfter[j] = previous
+			}
+		}
+	}
+	pipeline.Spec.Tasks = append(pipeline.Spec.Tasks[:1], append(steps, pipeline.Spec.Tasks[1:]...)...)
+
+	if in.ArtifactsURL == "" {
+		return
+	}
+
+	pipeline.Spec.Finally = append(pipeline.Spec.Finally, tektonv1.PipelineTask{
+		Name: "publish-artifacts",
+		TaskRef: &tektonv1.TaskRef{
+			ResolverRef: tektonv1.ResolverRef{
+				Resolver: "cluster",
+				Params: []tektonv1.Param{
+					{Name: "kind", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "task"}},
+					{Name: "name", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: PublishArtifactsTaskName}},
+					{Name: "namespace", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "tekton-pipelines"}},
+				},
+			},
+		},
+		Params: []tektonv1.Param{
+			{Name: "artifacts-dir", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: artifactsDir}},
+			{Name: "artifacts-url", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: in.ArtifactsURL}},
+			{Name: "artifacts-path", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: config.DeploymentArtifactsPath(in.Deployment.GetUUID())}},
+		},
+		Workspaces: []tektonv1.WorkspacePipelineTaskBinding{
+			{Name: "source", Workspace: workspaceName},
+		},
+	})
+}
+
+// stepScript creates the artifacts directory before the step script runs. Scripts with a
+// shebang are left untouched as the shell they run in is unknown.
+func stepScript(script string) string {
+	if strings.HasPrefix(script, "#!") {
+		return script
+	}
+	return fmt.Sprintf("mkdir -p \"$%s\"\n%s", ArtifactsDirEnvVar, script)
+}
@@ -0,0 +1,224 @@
+package pipelines
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/utils"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// nixpacksV1 generates a Tekton Pipeline for Nixpacks builds. Nixpacks detects the
+// stack of the root directory and writes a Dockerfile that BuildKit then builds.
+func nixpacksV1(in Input) (*tektonv1.Pipeline, error) {
+	deployment, gitConfig := in.Deployment, in.GitRepository
+	buildTask := tektonv1.PipelineTask{
+		Name:     "build-nixpacks",
+		RunAfter: []string{"clone-repository"},
+		TaskRef:  clusterTaskRef(NixpacksBuildTaskName),
+		Params: []tektonv1.Param{
+			{Name: "contextPath", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: builderContextPath(gitConfig)}},
+			{Name: "imageTag", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: utils.GetBuiltImageName(deployment.Namespace, deployment.GetApplicationUUID(), deployment.GetUUID(), "")}},
+			{Name: "buildkitHost", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(params." + ParamBuildKitHost + ")"}},
+		},
+	}
+
+	return builderV1(in,
+		platformv1alpha1.BuildTypeNixpacks, "Nixpacks", buildTask)
+}
+
+// buildpacksV1 generates a Tekton Pipeline for Cloud Native Buildpacks builds. The
+// lifecycle of the builder image builds and pushes the image itself, without BuildKit.
+func buildpacksV1(in Input) (*tektonv1.Pipeline, error) {
+	deployment, gitConfig := in.Deployment, in.GitRepository
+	builderImage := platformv1alpha1.DefaultBuildpacksBuilderImage
+	if gitConfig.BuildpacksBuild != nil && gitConfig.BuildpacksBuild.BuilderImage != "" {
+		builderImage = gitConfig.BuildpacksBuild.BuilderImage
+	}
+
+	buildTask := tektonv1.PipelineTask{
+		Name:     "build-buildpacks",
+		RunAfter: []string{"clone-repository"},
+		TaskRef:  clusterTaskRef(BuildpacksBuildTaskName),
+		Params: []tektonv1.Param{
+			{Name: "contextPath", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: builderContextPath(gitConfig)}},
+			{Name: "imageTag", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: utils.GetBuiltImageName(deployment.Namespace, deployment.GetApplicationUUID(), deployment.GetUUID(), "")}},
+			{Name: "builderImage", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: builderImage}},
+		},
+	}
+
+	return builderV1(in,
+		platformv1alpha1.BuildTypeBuildpacks, "Cloud Native Buildpacks", buildTask)
+}
+
+// builderV1 generates a pipeline that clones the repository and runs a single
+// build task detecting the stack of the application
+func builderV1(
+	in Input,
+	buildType platformv1alpha1.BuildType,
+	builderName string,
+	buildTask tektonv1.PipelineTask,
+) (*tektonv1.Pipeline, error) {
+	deployment, gitConfig := in.Deployment, in.GitRepository
+	deploymentSlug := deployment.GetSlug()
+	deploymentUUID := deployment.GetUUID()
+	projectUUID := deployment.GetProjectUUID()
+
+	// Construct git URL from provider and repository
+	gitURL := fmt.Sprintf("https://%s/%s", gitConfig.Provider, gitConfig.Repository)
+
+	// Get branch (use default if empty)
+	gitBranch := gitConfig.Branch
+	if gitBranch == "" {
+		gitBranch = DefaultGitBranch
+	}
+
+	// Get secret name (only if not public access)
+	var tokenSecret string
+	if !gitConfig.PublicAccess && gitConfig.SecretRef != nil {
+		tokenSecret = gitConfig.SecretRef.Name
+	}
+
+	// Generate workspace name based on deployment UUID
+	workspaceName := fmt.Sprintf("workspace-%s", deploymentUUID)
+
+	buildTask.Workspaces = []tektonv1.WorkspacePipelineTaskBinding{
+		{Name: "output", Workspace: workspaceName},
+		{Name: "docker-config", Workspace: "registry-docker-config"},
+		{Name: "registry-ca", Workspace: "registry-ca-cert"},
+		{Name: "app-env-vars", Workspace: "app-env-vars"},
+	}
+
+	pipeline := &tektonv1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      in.Name,
+			Namespace: deployment.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":                 fmt.Sprintf("project-%s", projectUUID),
+				"app.kubernetes.io/managed-by":           "kibaship",
+				"app.kubernetes.io/component":            "ci-cd-pipeline",
+				"tekton.dev/pipeline":                    "git-repository-" + strings.ToLower(string(buildType)),
+				"project.kibaship.com/slug":              in.ProjectSlug,
+				"platform.kibaship.com/deployment-uuid":  deployment.Labels["platform.kibaship.com/uuid"],
+				"platform.kibaship.com/application-uuid": deployment.Labels["platform.kibaship.com/application-uuid"],
+				"platform.kibaship.com/project-uuid":     deployment.Labels["platform.kibaship.com/project-uuid"],
+				"platform.kibaship.com/build-type":       string(buildType),
+			},
+			Annotations: map[string]string{
+				"description":                fmt.Sprintf("CI/CD pipeline for deployment %s using %s build", deploymentSlug, builderName),
+				"project.kibaship.com/usage": fmt.Sprintf("Clones repository, builds image with %s, and pushes to registry", builderName),
+				"tekton.dev/displayName":     fmt.Sprintf("Deployment %s %s Pipeline", deploymentSlug, buildType),
+			},
+		},
+		Spec: tektonv1.PipelineSpec{
+			Description: fmt.Sprintf("Pipeline that builds applications using %s. Clones source code from Git, detects the stack, builds the image, and pushes to registry.", builderName),
+			Params: []tektonv1.ParamSpec{
+				{
+					Name:        "git-commit",
+					Description: "Specific commit hash to checkout",
+					Type:        tektonv1.ParamTypeString,
+				},
+				{
+					Name:        "git-branch",
+					Description: "Git branch to checkout (optional, defaults to configured branch)",
+					Type:        tektonv1.ParamTypeString,
+					Default:     &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: gitBranch},
+				},
+				{
+					Name:        ParamBuildKitHost,
+					Description: "Address of the BuildKit daemon building the image",
+					Type:        tektonv1.ParamTypeString,
+					Default:     &tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: DefaultBuildKitHost},
+				},
+			},
+			Workspaces: []tektonv1.PipelineWorkspaceDeclaration{
+				{
+					Name:        workspaceName,
+					Description: "Workspace where the cloned source code will be stored",
+				},
+				{
+					Name:        "registry-docker-config",
+					Description: "Docker config for registry authentication",
+				},
+				{
+					Name:        "registry-ca-cert",
+					Description: "Registry CA certificate for TLS trust",
+				},
+				{
+					Name:        "app-env-vars",
+					Description: "Application environment variables from secret",
+					Optional:    true,
+				},
+			},
+			Tasks: []tektonv1.PipelineTask{
+				{
+					Name:    "clone-repository",
+					TaskRef: clusterTaskRef(GitCloneTaskName),
+					Params: []tektonv1.Param{
+						{Name: "url", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: gitURL}},
+						{Name: "branch", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(params.git-branch)"}},
+						{Name: "commit", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(params.git-commit)"}},
+						{Name: "token-secret", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: tokenSecret}},
+						{Name: "public-access", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("%t", gitConfig.PublicAccess)}},
+					},
+					Workspaces: []tektonv1.WorkspacePipelineTaskBinding{
+						{Name: "output", Workspace: workspaceName},
+					},
+				},
+				buildTask,
+			},
+			Results: []tektonv1.PipelineResult{
+				{
+					Name:        "commit-sha",
+					Description: "The actual commit SHA that was checked out",
+					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.clone-repository.results.commit)"},
+				},
+				{
+					Name:        "repository-url",
+					Description: "The repository URL that was cloned",
+					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks.clone-repository.results.url)"},
+				},
+				{
+					Name:        "build-output",
+					Description: "The image tag that was built and pushed",
+					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("$(tasks.%s.results.buildOutput)", buildTask.Name)},
+				},
+				{
+					Name:        ResultImageDigest,
+					Description: "The digest of the image that was built and pushed",
+					Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("$(tasks.%s.results.imageDigest)", buildTask.Name)},
+				},
+			},
+		},
+	}
+
+	addStepsV1(pipeline, in, workspaceName)
+
+	return pipeline, nil
+}
+
+// clusterTaskRef references a task installed in the tekton-pipelines namespace
+func clusterTaskRef(name string) *tektonv1.TaskRef {
+	return &tektonv1.TaskRef{
+		ResolverRef: tektonv1.ResolverRef{
+			Resolver: "cluster",
+			Params: []tektonv1.Param{
+				{Name: "kind", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "task"}},
+				{Name: "name", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: name}},
+				{Name: "namespace", Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "tekton-pipelines"}},
+			},
+		},
+	}
+}
+
+// builderContextPath is the directory the builders detect the stack of, the root directory of
+// the application like Railpack builds
+func builderContextPath(gitConfig *platformv1alpha1.GitRepositoryConfig) string {
+	if gitConfig.RootDirectory == "" {
+		return "."
+	}
+	return gitConfig.RootDirectory
+}
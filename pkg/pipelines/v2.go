@@ -0,0 +1,34 @@
+package pipelines
+
+import (
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// portDetectingTasksV2 are the build tasks reporting the port their image exposes, by build type
+var portDetectingTasksV2 = map[platformv1alpha1.BuildType]string{
+	platformv1alpha1.BuildTypeRailpack:   "build",
+	platformv1alpha1.BuildTypeDockerfile: "build-dockerfile",
+}
+
+// buildV2 generates a version 2 Pipeline, the version 1 Pipeline reporting the port detected by
+// the Railpack and Dockerfile builds as a result
+func buildV2(in Input) (*tektonv1.Pipeline, error) {
+	pipeline, err := buildV1(in)
+	if err != nil {
+		return nil, err
+	}
+
+	buildType := in.GitRepository.BuildType
+	if buildType == "" {
+		buildType = platformv1alpha1.BuildTypeRailpack
+	}
+	if task, ok := portDetectingTasksV2[buildType]; ok {
+		pipeline.Spec.Results = append(pipeline.Spec.Results, tektonv1.PipelineResult{
+			Name:        ResultExposedPort,
+			Description: "The port the built image exposes, empty when the build detected none",
+			Value:       tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: "$(tasks." + task + ".results.exposedPort)"},
+		})
+	}
+	return pipeline, nil
+}
@@ -0,0 +1,63 @@
+package pipelines
+
+import (
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// WorkspaceBuildEnv is the workspace holding the build environment variables of an application
+const WorkspaceBuildEnv = "build-env"
+
+// buildEnvTasksV3 are the tasks reading the build environment variables, by build type. Nixpacks
+// writes the variables of its build into the image, its builds do not get them.
+var buildEnvTasksV3 = map[platformv1alpha1.BuildType][]string{
+	platformv1alpha1.BuildTypeRailpack:   {"prepare", "build"},
+	platformv1alpha1.BuildTypeDockerfile: {"build-dockerfile"},
+	platformv1alpha1.BuildTypeBuildpacks: {"build-buildpacks"},
+}
+
+// buildV3 generates a version 3 Pipeline, the version 2 Pipeline with an optional workspace
+// passing the build environment variables to the builder
+func buildV3(in Input) (*tektonv1.Pipeline, error) {
+	pipeline, err := buildV2(in)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, ok := buildEnvTasksV3[defaultBuildType(in.GitRepository.BuildType)]
+	if !ok {
+		return pipeline, nil
+	}
+	pipeline.Spec.Workspaces = append(pipeline.Spec.Workspaces, tektonv1.PipelineWorkspaceDeclaration{
+		Name:        WorkspaceBuildEnv,
+		Description: "Build environment variables of the application, never passed to the runtime container",
+		Optional:    true,
+	})
+	for i := range pipeline.Spec.Tasks {
+		for _, name := range tasks {
+			if pipeline.Spec.Tasks[i].Name == name {
+				pipeline.Spec.Tasks[i].Workspaces = append(pipeline.Spec.Tasks[i].Workspaces,
+					tektonv1.WorkspacePipelineTaskBinding{Name: WorkspaceBuildEnv, Workspace: WorkspaceBuildEnv})
+			}
+		}
+	}
+	return pipeline, nil
+}
+
+// SupportsBuildEnv reports whether the Pipelines of a spec version and build type declare the
+// build environment workspace
+func SupportsBuildEnv(version string, buildType platformv1alpha1.BuildType) bool {
+	if version == SpecVersionV1 || version == SpecVersionV2 {
+		return false
+	}
+	_, ok := buildEnvTasksV3[defaultBuildType(buildType)]
+	return ok
+}
+
+// defaultBuildType returns the build type, Railpack when it is not set
+func defaultBuildType(buildType platformv1alpha1.BuildType) platformv1alpha1.BuildType {
+	if buildType == "" {
+		return platformv1alpha1.BuildTypeRailpack
+	}
+	return buildType
+}
@@ -0,0 +1,37 @@
+package pipelines
+
+import (
+	"strconv"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+const (
+	// cloneTaskName is the Pipeline task cloning the repository
+	cloneTaskName = "clone-repository"
+
+	// ParamGitSubmodules tells the git clone task to clone the submodules recursively
+	ParamGitSubmodules = "submodules"
+	// ParamGitLFS tells the git clone task to fetch the Git LFS objects of the checked out commit
+	ParamGitLFS = "lfs"
+)
+
+// buildV4 generates a version 4 Pipeline, the version 3 Pipeline passing the submodules and Git
+// LFS options of the application to the git clone task
+func buildV4(in Input) (*tektonv1.Pipeline, error) {
+	pipeline, err := buildV3(in)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range pipeline.Spec.Tasks {
+		if pipeline.Spec.Tasks[i].Name != cloneTaskName {
+			continue
+		}
+		pipeline.Spec.Tasks[i].Params = append(pipeline.Spec.Tasks[i].Params,
+			tektonv1.Param{Name: ParamGitSubmodules, Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: strconv.FormatBool(in.GitRepository.GitSubmodules)}},
+			tektonv1.Param{Name: ParamGitLFS, Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: strconv.FormatBool(in.GitRepository.GitLFS)}},
+		)
+	}
+	return pipeline, nil
+}
@@ -0,0 +1,36 @@
+package pipelines
+
+import (
+	"strconv"
+	"strings"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+const (
+	// ParamCloneDepth tells the git clone task how many commits to clone, 0 for the full history
+	ParamCloneDepth = "depth"
+	// ParamSparseCheckoutPaths lists the directories the git clone task checks out, one per line,
+	// empty to check out the whole repository
+	ParamSparseCheckoutPaths = "sparse-paths"
+)
+
+// buildV5 generates a version 5 Pipeline, the version 4 Pipeline passing the clone depth and
+// sparse checkout paths of the application to the git clone task
+func buildV5(in Input) (*tektonv1.Pipeline, error) {
+	pipeline, err := buildV4(in)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range pipeline.Spec.Tasks {
+		if pipeline.Spec.Tasks[i].Name != cloneTaskName {
+			continue
+		}
+		pipeline.Spec.Tasks[i].Params = append(pipeline.Spec.Tasks[i].Params,
+			tektonv1.Param{Name: ParamCloneDepth, Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: strconv.Itoa(int(in.GitRepository.CloneDepth))}},
+			tektonv1.Param{Name: ParamSparseCheckoutPaths, Value: tektonv1.ParamValue{Type: tektonv1.ParamTypeString, StringVal: strings.Join(in.GitRepository.SparseCheckoutPaths, "\n")}},
+		)
+	}
+	return pipeline, nil
+}
@@ -0,0 +1,214 @@
+// Package pullrequest parses pull request webhooks of git providers and comments on pull requests.
+package pullrequest
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// ProviderGitHub is the git provider of GitHub repositories, as used in Application specs
+	ProviderGitHub = "github.com"
+
+	// GitHubAPIURL is the base URL of the GitHub REST API
+	GitHubAPIURL = "https://api.github.com"
+
+	// GitHubEventHeader carries the event type of a GitHub webhook delivery
+	GitHubEventHeader = "X-GitHub-Event"
+
+	// GitHubSignatureHeader carries the HMAC SHA-256 signature of a GitHub webhook delivery
+	GitHubSignatureHeader = "X-Hub-Signature-256"
+)
+
+// Action is what happened to a pull request
+type Action string
+
+const (
+	// ActionOpened is sent when a pull request is opened or reopened
+	ActionOpened Action = "opened"
+
+	// ActionSynchronized is sent when commits are pushed to the head branch of a pull request
+	ActionSynchronized Action = "synchronized"
+
+	// ActionClosed is sent when a pull request is merged or closed without merging
+	ActionClosed Action = "closed"
+)
+
+// Event is a pull request event of a git provider
+type Event struct {
+	// Provider is the git provider of the repository, such as github.com
+	Provider string
+
+	// Action is what happened to the pull request
+	Action Action
+
+	// ID identifies the pull request at the provider, unique across repositories
+	ID int64
+
+	// Number is the number of the pull request within its repository
+	Number int
+
+	// Repository is the base repository in the format <org-name>/<repo-name>
+	Repository string
+
+	// DefaultBranch is the default branch of the base repository
+	DefaultBranch string
+
+	// BaseBranch is the branch the pull request merges into
+	BaseBranch string
+
+	// HeadBranch is the branch with the changes of the pull request
+	HeadBranch string
+
+	// HeadSHA is the latest commit of the head branch
+	HeadSHA string
+
+	// URL is the web page of the pull request
+	URL string
+
+	// Fork reports whether the head branch lives in another repository than the base branch
+	Fork bool
+}
+
+// Key identifies the pull request in the labels of its preview resources
+func (e *Event) Key() string {
+	return fmt.Sprintf("%s-%d", strings.TrimSuffix(e.Provider, ".com"), e.ID)
+}
+
+// VerifyGitHubSignature reports whether signature, the X-Hub-Signature-256 header of a GitHub
+// webhook delivery, is the HMAC SHA-256 of body with secret
+func VerifyGitHubSignature(secret, body []byte, signature string) bool {
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok || len(secret) == 0 {
+		return false
+	}
+
+	expected, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+type gitHubRepository struct {
+	FullName      string `json:"full_name"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+type gitHubBranch struct {
+	Ref  string            `json:"ref"`
+	SHA  string            `json:"sha"`
+	Repo *gitHubRepository `json:"repo"`
+}
+
+type gitHubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		ID      int64        `json:"id"`
+		HTMLURL string       `json:"html_url"`
+		Head    gitHubBranch `json:"head"`
+		Base    gitHubBranch `json:"base"`
+	} `json:"pull_request"`
+	Repository gitHubRepository `json:"repository"`
+}
+
+// ParseGitHubEvent parses a GitHub webhook delivery of eventType. It returns nil for events
+// that do not change a preview, such as pushes or pull request label changes.
+func ParseGitHubEvent(eventType string, body []byte) (*Event, error) {
+	if eventType != "pull_request" {
+		return nil, nil
+	}
+
+	var payload gitHubPullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid pull_request payload: %w", err)
+	}
+
+	var action Action
+	switch payload.Action {
+	case "opened", "reopened":
+		action = ActionOpened
+	case "synchronize":
+		action = ActionSynchronized
+	case "closed":
+		action = ActionClosed
+	default:
+		return nil, nil
+	}
+
+	pr := payload.PullRequest
+	if pr.ID == 0 || payload.Repository.FullName == "" {
+		return nil, fmt.Errorf("pull_request payload is missing the pull request or repository")
+	}
+
+	return &Event{
+		Provider:      ProviderGitHub,
+		Action:        action,
+		ID:            pr.ID,
+		Number:        payload.Number,
+		Repository:    payload.Repository.FullName,
+		DefaultBranch: payload.Repository.DefaultBranch,
+		BaseBranch:    pr.Base.Ref,
+		HeadBranch:    pr.Head.Ref,
+		HeadSHA:       pr.Head.SHA,
+		URL:           pr.HTMLURL,
+		Fork:          pr.Head.Repo == nil || !strings.EqualFold(pr.Head.Repo.FullName, payload.Repository.FullName),
+	}, nil
+}
+
+// Commenter posts comments on pull requests
+type Commenter struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewCommenter creates a Commenter for the GitHub REST API at baseURL
+func NewCommenter(baseURL string) *Commenter {
+	return &Commenter{
+		client:  &http.Client{Timeout: 15 * time.Second},
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Comment posts body as a comment on pull request number of repository, authenticated with token
+func (c *Commenter) Comment(ctx context.Context, token, repository string, number int, body string) error {
+	payload, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/issues/%d/comments", c.baseURL, repository, number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "kibaship-previews/1.0")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to comment on pull request %s#%d: %w", repository, number, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failed to comment on pull request %s#%d: unexpected status %d", repository, number, resp.StatusCode)
+	}
+	return nil
+}
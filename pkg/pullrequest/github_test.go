@@ -0,0 +1,116 @@
+package pullrequest
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const pullRequestPayload = `{
+	"action": "%s",
+	"number": 42,
+	"pull_request": {
+		"id": 1893042113,
+		"html_url": "https://github.com/myorg/myapp/pull/42",
+		"head": {"ref": "feature/login", "sha": "4f1c2d3", "repo": {"full_name": "%s"}},
+		"base": {"ref": "main", "sha": "9a8b7c6", "repo": {"full_name": "myorg/myapp"}}
+	},
+	"repository": {"full_name": "myorg/myapp", "default_branch": "main"}
+}`
+
+func payload(action, headRepository string) []byte {
+	return []byte(fmt.Sprintf(pullRequestPayload, action, headRepository))
+}
+
+func TestVerifyGitHubSignature(t *testing.T) {
+	g := NewWithT(t)
+
+	secret := []byte("webhook-secret")
+	body := []byte(`{"zen":"Keep it logically awesome."}`)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	g.Expect(VerifyGitHubSignature(secret, body, signature)).To(BeTrue())
+	g.Expect(VerifyGitHubSignature([]byte("other-secret"), body, signature)).To(BeFalse())
+	g.Expect(VerifyGitHubSignature(secret, []byte(`{}`), signature)).To(BeFalse())
+	g.Expect(VerifyGitHubSignature(secret, body, "sha1=abc")).To(BeFalse())
+	g.Expect(VerifyGitHubSignature(nil, body, signature)).To(BeFalse())
+}
+
+func TestParseGitHubEvent(t *testing.T) {
+	g := NewWithT(t)
+
+	evt, err := ParseGitHubEvent("pull_request", payload("opened", "myorg/myapp"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(evt).To(Equal(&Event{
+		Provider:      ProviderGitHub,
+		Action:        ActionOpened,
+		ID:            1893042113,
+		Number:        42,
+		Repository:    "myorg/myapp",
+		DefaultBranch: "main",
+		BaseBranch:    "main",
+		HeadBranch:    "feature/login",
+		HeadSHA:       "4f1c2d3",
+		URL:           "https://github.com/myorg/myapp/pull/42",
+	}))
+	g.Expect(evt.Key()).To(Equal("github-1893042113"))
+
+	evt, err = ParseGitHubEvent("pull_request", payload("reopened", "myorg/myapp"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(evt.Action).To(Equal(ActionOpened))
+
+	evt, err = ParseGitHubEvent("pull_request", payload("synchronize", "myorg/myapp"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(evt.Action).To(Equal(ActionSynchronized))
+
+	evt, err = ParseGitHubEvent("pull_request", payload("closed", "contributor/myapp"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(evt.Action).To(Equal(ActionClosed))
+	g.Expect(evt.Fork).To(BeTrue())
+
+	// Events that do not change a preview are ignored
+	evt, err = ParseGitHubEvent("pull_request", payload("labeled", "myorg/myapp"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(evt).To(BeNil())
+
+	evt, err = ParseGitHubEvent("push", []byte(`{}`))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(evt).To(BeNil())
+
+	_, err = ParseGitHubEvent("pull_request", []byte(`{"action":"opened"}`))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestComment(t *testing.T) {
+	g := NewWithT(t)
+
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/myorg/myapp/issues/42/comments" || r.Header.Get("Authorization") != "Bearer ghp_token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	commenter := NewCommenter(server.URL + "/")
+	err := commenter.Comment(context.Background(), "ghp_token", "myorg/myapp", 42, "Preview is live")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(received).To(HaveKeyWithValue("body", "Preview is live"))
+
+	err = commenter.Comment(context.Background(), "wrong-token", "myorg/myapp", 42, "Preview is live")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("unexpected status 404"))
+}
@@ -0,0 +1,324 @@
+// Package registryusage measures the storage the images of a project use in the registry.
+package registryusage
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// requestTimeout bounds a single registry request
+	requestTimeout = 30 * time.Second
+
+	// maxErrorBodyRead is how much of an error response is quoted in errors
+	maxErrorBodyRead = 1024
+)
+
+// manifestMediaTypes are the manifest and index formats accepted from the registry
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// challengeParam matches a key="value" parameter of a WWW-Authenticate challenge
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// errRepositoryNotFound is returned by the registry for repositories nothing was pushed to yet
+var errRepositoryNotFound = errors.New("repository not found")
+
+// Credentials authenticate against the registry and its token service
+type Credentials struct {
+	Username string
+	Password string
+
+	// CACert is the PEM certificate authority of the registry, the system roots when empty
+	CACert []byte
+}
+
+// RepositoryUsage is the storage used by a single repository
+type RepositoryUsage struct {
+	Repository string
+	Tags       int
+	Bytes      int64
+}
+
+// Usage is the storage used by a set of repositories. Bytes counts every blob once, even when
+// several tags or repositories share it, as the registry stores it once.
+type Usage struct {
+	Bytes        int64
+	Repositories []RepositoryUsage
+}
+
+// Client inspects repositories through the registry HTTP API
+type Client struct {
+	baseURL string
+}
+
+// NewClient creates a Client for the registry at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// descriptor references a blob or manifest by digest
+type descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// manifest is the subset of image manifests and indexes used to size images
+type manifest struct {
+	MediaType string       `json:"mediaType"`
+	Config    descriptor   `json:"config"`
+	Layers    []descriptor `json:"layers"`
+	Manifests []descriptor `json:"manifests"`
+}
+
+// Inspect measures the storage used by repositories from the manifests of all their tags.
+// Repositories nothing was pushed to yet use no storage.
+func (c *Client) Inspect(ctx context.Context, creds Credentials, repositories []string) (*Usage, error) {
+	session, err := c.newSession(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &Usage{}
+	seen := map[string]bool{}
+	for _, repository := range repositories {
+		tags, err := session.listTags(ctx, repository)
+		if errors.Is(err, errRepositoryNotFound) {
+			usage.Repositories = append(usage.Repositories, RepositoryUsage{Repository: repository})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags of %s: %w", repository, err)
+		}
+
+		blobs := map[string]int64{}
+		for _, tag := range tags {
+			if err := session.collectBlobs(ctx, repository, tag, blobs); err != nil {
+				return nil, fmt.Errorf("failed to inspect %s:%s: %w", repository, tag, err)
+			}
+		}
+
+		repoUsage := RepositoryUsage{Repository: repository, Tags: len(tags)}
+		for digest, size := range blobs {
+			repoUsage.Bytes += size
+			if !seen[digest] {
+				seen[digest] = true
+				usage.Bytes += size
+			}
+		}
+		usage.Repositories = append(usage.Repositories, repoUsage)
+	}
+	return usage, nil
+}
+
+// session holds the HTTP client and bearer tokens of one inspection
+type session struct {
+	baseURL    string
+	creds      Credentials
+	httpClient *http.Client
+	tokens     map[string]string
+}
+
+func (c *Client) newSession(creds Credentials) (*session, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(creds.CACert) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(creds.CACert) {
+			return nil, fmt.Errorf("invalid registry CA certificate")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &session{
+		baseURL:    c.baseURL,
+		creds:      creds,
+		httpClient: &http.Client{Timeout: requestTimeout, Transport: transport},
+		tokens:     map[string]string{},
+	}, nil
+}
+
+// listTags returns all tags of repository, following the pagination links of the registry
+func (s *session) listTags(ctx context.Context, repository string) ([]string, error) {
+	var tags []string
+	next := fmt.Sprintf("/v2/%s/tags/list", repository)
+	for next != "" {
+		resp, err := s.get(ctx, repository, next, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		link := resp.Header.Get("Link")
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode tag list: %w", err)
+		}
+		tags = append(tags, page.Tags...)
+		next = nextLink(link)
+	}
+	return tags, nil
+}
+
+// collectBlobs adds the config and layer blobs of the manifest reference points to, and of every
+// platform manifest of an index, to blobs
+func (s *session) collectBlobs(ctx context.Context, repository, reference string, blobs map[string]int64) error {
+	resp, err := s.get(ctx, repository, fmt.Sprintf("/v2/%s/manifests/%s", repository, reference),
+		map[string]string{"Accept": strings.Join(manifestMediaTypes, ", ")})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var m manifest
+	if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return fmt.Errorf("failed to decode manifest: %w", err)
+	}
+
+	for _, child := range m.Manifests {
+		if err := s.collectBlobs(ctx, repository, child.Digest, blobs); err != nil {
+			return err
+		}
+	}
+	if m.Config.Digest != "" {
+		blobs[m.Config.Digest] = m.Config.Size
+	}
+	for _, layer := range m.Layers {
+		blobs[layer.Digest] = layer.Size
+	}
+	return nil
+}
+
+// get sends an authenticated GET request for path of repository. The registry answers the first
+// request of a repository with a bearer challenge, the token obtained for it is reused afterwards.
+func (s *session) get(ctx context.Context, repository, path string, headers map[string]string) (*http.Response, error) {
+	send := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build registry request: %w", err)
+		}
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		if token := s.tokens[repository]; token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		} else if s.creds.Username != "" {
+			req.SetBasicAuth(s.creds.Username, s.creds.Password)
+		}
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query registry: %w", err)
+		}
+		return resp, nil
+	}
+
+	resp, err := send()
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && s.tokens[repository] == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+		if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+			return nil, fmt.Errorf("registry rejected the credentials")
+		}
+		token, err := s.fetchToken(ctx, challenge)
+		if err != nil {
+			return nil, err
+		}
+		s.tokens[repository] = token
+		if resp, err = send(); err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode == http.StatusNotFound && strings.HasSuffix(path, "/tags/list") {
+		_ = resp.Body.Close()
+		return nil, errRepositoryNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyRead))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("registry returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// fetchToken obtains a bearer token from the token service named in a WWW-Authenticate challenge
+func (s *session) fetchToken(ctx context.Context, challenge string) (string, error) {
+	params := map[string]string{}
+	for _, match := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("registry challenge has no realm")
+	}
+
+	query := url.Values{}
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	if scope := params["scope"]; scope != "" {
+		query.Set("scope", scope)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build token request: %w", err)
+	}
+	req.SetBasicAuth(s.creds.Username, s.creds.Password)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request registry token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyRead))
+		return "", fmt.Errorf("token service returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode registry token: %w", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+	return "", fmt.Errorf("token service returned no token")
+}
+
+// nextLink returns the path of the next page in a Link header, empty on the last page
+func nextLink(link string) string {
+	if !strings.Contains(link, `rel="next"`) {
+		return ""
+	}
+	start := strings.Index(link, "<")
+	end := strings.Index(link, ">")
+	if start < 0 || end <= start {
+		return ""
+	}
+	return link[start+1 : end]
+}
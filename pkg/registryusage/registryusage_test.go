@@ -0,0 +1,120 @@
+package registryusage
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestInspect(t *testing.T) {
+	g := NewWithT(t)
+
+	manifests := map[string]any{
+		// Multi-platform image sharing its base layer with the single platform one
+		"project-1/app-1/manifests/v1": map[string]any{
+			"mediaType": "application/vnd.oci.image.index.v1+json",
+			"manifests": []any{
+				map[string]any{"digest": "sha256:amd64", "size": 500},
+				map[string]any{"digest": "sha256:arm64", "size": 500},
+			},
+		},
+		"project-1/app-1/manifests/sha256:amd64": map[string]any{
+			"config": map[string]any{"digest": "sha256:config-amd64", "size": 10},
+			"layers": []any{
+				map[string]any{"digest": "sha256:base", "size": 1000},
+				map[string]any{"digest": "sha256:app-amd64", "size": 200},
+			},
+		},
+		"project-1/app-1/manifests/sha256:arm64": map[string]any{
+			"config": map[string]any{"digest": "sha256:config-arm64", "size": 10},
+			"layers": []any{
+				map[string]any{"digest": "sha256:base-arm64", "size": 900},
+				map[string]any{"digest": "sha256:app-arm64", "size": 200},
+			},
+		},
+		"project-1/app-1/manifests/v2": map[string]any{
+			"mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+			"config":    map[string]any{"digest": "sha256:config-v2", "size": 10},
+			"layers": []any{
+				map[string]any{"digest": "sha256:base", "size": 1000},
+				map[string]any{"digest": "sha256:app-v2", "size": 300},
+			},
+		},
+		"project-1/app-2/manifests/latest": map[string]any{
+			"config": map[string]any{"digest": "sha256:config-app-2", "size": 10},
+			"layers": []any{
+				map[string]any{"digest": "sha256:base", "size": 1000},
+			},
+		},
+	}
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth" {
+			username, password, ok := r.BasicAuth()
+			if !ok || username != "project-1" || password != "secret" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "token-" + r.URL.Query().Get("scope")})
+			return
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/v2/"), "/", 3)
+		scope := "repository:" + parts[0] + "/" + parts[1] + ":pull"
+		if r.Header.Get("Authorization") != "Bearer token-"+scope {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/auth",service="registry",scope="`+scope+`"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/project-1/app-1/tags/list":
+			if r.URL.Query().Get("last") == "" {
+				w.Header().Set("Link", `</v2/project-1/app-1/tags/list?last=v1&n=1>; rel="next"`)
+				_, _ = w.Write([]byte(`{"name":"project-1/app-1","tags":["v1"]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"name":"project-1/app-1","tags":["v2"]}`))
+		case "/v2/project-1/app-2/tags/list":
+			_, _ = w.Write([]byte(`{"name":"project-1/app-2","tags":["latest"]}`))
+		default:
+			m, ok := manifests[strings.TrimPrefix(r.URL.Path, "/v2/")]
+			if !ok {
+				http.Error(w, `{"errors":[{"code":"NAME_UNKNOWN"}]}`, http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(m)
+		}
+	}))
+	defer server.Close()
+
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	creds := Credentials{Username: "project-1", Password: "secret", CACert: caCert}
+	c := NewClient(server.URL + "/")
+	ctx := context.Background()
+
+	usage, err := c.Inspect(ctx, creds, []string{"project-1/app-1", "project-1/app-2", "project-1/app-3"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(usage.Repositories).To(Equal([]RepositoryUsage{
+		{Repository: "project-1/app-1", Tags: 2, Bytes: 1000 + 200 + 10 + 900 + 200 + 10 + 300 + 10},
+		{Repository: "project-1/app-2", Tags: 1, Bytes: 1000 + 10},
+		{Repository: "project-1/app-3"},
+	}))
+	// The base layer shared by both repositories is stored once
+	g.Expect(usage.Bytes).To(Equal(int64(2630 + 10)))
+
+	creds.Password = "wrong"
+	_, err = c.Inspect(ctx, creds, []string{"project-1/app-1"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("token service returned 401"))
+
+	_, err = c.Inspect(ctx, Credentials{CACert: []byte("not a certificate")}, nil)
+	g.Expect(err).To(MatchError("invalid registry CA certificate"))
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// AdminService searches the resources of all projects for platform operators. It reads from the
+// informer cache of the API server, so a search costs no API server round trips.
+type AdminService struct {
+	cache client.Reader
+}
+
+// NewAdminService creates a new admin service reading from cache
+func NewAdminService(cache client.Reader) *AdminService {
+	return &AdminService{
+		cache: cache,
+	}
+}
+
+// ListApplications returns a page of the applications of all projects matching the request
+func (s *AdminService) ListApplications(ctx context.Context, req *models.AdminListRequest) (*models.AdminApplicationListResponse, error) {
+	applications, err := s.listApplications(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewAdminApplicationListResponse(applications, req), nil
+}
+
+// ListDeployments returns a page of the deployments of all projects matching the request
+func (s *AdminService) ListDeployments(ctx context.Context, req *models.AdminListRequest) (*models.AdminDeploymentListResponse, error) {
+	var deployments v1alpha1.DeploymentList
+	if err := s.cache.List(ctx, &deployments, client.HasLabels{validation.LabelProjectUUID}); err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	applications, err := s.listApplications(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewAdminDeploymentListResponse(deployments.Items, applications, req), nil
+}
+
+// listApplications lists the applications of all projects
+func (s *AdminService) listApplications(ctx context.Context) ([]v1alpha1.Application, error) {
+	var applications v1alpha1.ApplicationList
+	if err := s.cache.List(ctx, &applications, client.HasLabels{validation.LabelProjectUUID}); err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+	return applications.Items, nil
+}
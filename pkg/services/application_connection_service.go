@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/dbaccess"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+var (
+	// ErrNotDatabaseApplication is returned when connection info is requested for an application
+	// that is not a database
+	ErrNotDatabaseApplication = errors.New("application is not a database")
+
+	// ErrCredentialsUnavailable is returned when the credentials of a database are revealed
+	// before its Secret was provisioned
+	ErrCredentialsUnavailable = errors.New("database credentials are not provisioned yet")
+
+	// ErrCredentialsAlreadyRevealed is returned when the credentials of a database are revealed
+	// a second time
+	ErrCredentialsAlreadyRevealed = errors.New("database credentials were already revealed")
+)
+
+// GetApplicationConnection returns how to connect to a database application. With reveal the
+// credential pair is returned as well, once: the Secret is marked when it is revealed and later
+// reveals fail with ErrCredentialsAlreadyRevealed.
+func (s *ApplicationService) GetApplicationConnection(ctx context.Context, uuid string, reveal bool) (*models.ApplicationConnectionResponse, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, ok := dbaccess.ConnectionFor(crd)
+	if !ok {
+		return nil, fmt.Errorf("%w: application %s is of type %s", ErrNotDatabaseApplication, uuid, crd.Spec.Type)
+	}
+
+	response := &models.ApplicationConnectionResponse{
+		ApplicationUUID: uuid,
+		Type:            s.convertApplicationTypeFromCRD(crd.Spec.Type),
+		Database:        conn.Database,
+		Internal: models.ConnectionEndpoint{
+			Host: conn.Host(crd.Namespace),
+			Port: conn.Port,
+		},
+		SecretRef: models.ConnectionSecretRef{
+			Name:        conn.Secret,
+			Namespace:   crd.Namespace,
+			UsernameKey: conn.UsernameKey,
+			PasswordKey: conn.PasswordKey,
+		},
+	}
+
+	if s.domainService != nil {
+		domains, err := s.domainService.GetApplicationDomainsByApplicationUUIDNoValidate(ctx, uuid, crd.Labels[validation.LabelResourceSlug])
+		if err != nil {
+			return nil, fmt.Errorf("failed to load application domains: %w", err)
+		}
+		for _, domain := range domains {
+			if domain.Default {
+				response.External = &models.ConnectionEndpoint{Host: domain.Domain, Port: domain.Port}
+				break
+			}
+		}
+	}
+
+	var secret corev1.Secret
+	err = s.client.Get(ctx, client.ObjectKey{Name: conn.Secret, Namespace: crd.Namespace}, &secret)
+	switch {
+	case apierrors.IsNotFound(err):
+		if reveal {
+			return nil, ErrCredentialsUnavailable
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get database credentials: %w", err)
+	default:
+		if revealedAt, err := time.Parse(time.RFC3339, secret.Annotations[validation.AnnotationCredentialsRevealedAt]); err == nil {
+			response.CredentialsRevealedAt = &revealedAt
+		}
+		if conn.UsernameKey != "" && len(secret.Data[conn.UsernameKey]) > 0 {
+			conn.Username = string(secret.Data[conn.UsernameKey])
+		}
+	}
+
+	if reveal {
+		if response.CredentialsRevealedAt != nil {
+			return nil, ErrCredentialsAlreadyRevealed
+		}
+		if err := s.markCredentialsRevealed(ctx, &secret); err != nil {
+			return nil, err
+		}
+		now := time.Now().UTC().Truncate(time.Second)
+		response.CredentialsRevealedAt = &now
+		response.Credentials = &models.ConnectionCredentials{
+			Username: conn.Username,
+			Password: string(secret.Data[conn.PasswordKey]),
+		}
+	}
+
+	var password string
+	if response.Credentials != nil {
+		password = response.Credentials.Password
+	}
+	response.Internal.ConnectionString = models.BuildConnectionString(
+		conn.Scheme, conn.Username, password, response.Internal.Host, response.Internal.Port, conn.Database)
+	if response.External != nil {
+		response.External.ConnectionString = models.BuildConnectionString(
+			conn.Scheme, conn.Username, password, response.External.Host, response.External.Port, conn.Database)
+	}
+
+	return response, nil
+}
+
+// markCredentialsRevealed annotates a database Secret with the time its credentials were revealed.
+// The patch is optimistically locked, so of two concurrent reveals only one succeeds.
+func (s *ApplicationService) markCredentialsRevealed(ctx context.Context, secret *corev1.Secret) error {
+	patch := client.MergeFromWithOptions(secret.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[validation.AnnotationCredentialsRevealedAt] = time.Now().UTC().Format(time.RFC3339)
+
+	if err := s.client.Patch(ctx, secret, patch); err != nil {
+		if apierrors.IsConflict(err) {
+			return ErrCredentialsAlreadyRevealed
+		}
+		return fmt.Errorf("failed to record credentials reveal: %w", err)
+	}
+	return nil
+}
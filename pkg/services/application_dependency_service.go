@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ErrInvalidApplicationDependency is returned when an application depends on an application
+// outside its environment, on itself, or through a dependency cycle
+var ErrInvalidApplicationDependency = errors.New("invalid application dependency")
+
+// validateDependencies checks that the dependencies of an application are applications of the
+// same environment and that depending on them creates no cycle
+func (s *ApplicationService) validateDependencies(ctx context.Context, environmentUUID, applicationUUID string, dependsOn []string) error {
+	if len(dependsOn) == 0 {
+		return nil
+	}
+
+	var applicationList v1alpha1.ApplicationList
+	if err := s.client.List(ctx, &applicationList, client.MatchingLabels{
+		validation.LabelEnvironmentUUID: environmentUUID,
+	}); err != nil {
+		return fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	graph := make(map[string][]string, len(applicationList.Items)+1)
+	slugs := make(map[string]string, len(applicationList.Items))
+	for i := range applicationList.Items {
+		app := &applicationList.Items[i]
+		uuid := app.Labels[validation.LabelResourceUUID]
+		graph[uuid] = dependencyUUIDs(app.Spec.DependsOn)
+		slugs[uuid] = app.Labels[validation.LabelResourceSlug]
+	}
+
+	for _, dependency := range dependsOn {
+		if dependency == applicationUUID {
+			return fmt.Errorf("%w: an application cannot depend on itself", ErrInvalidApplicationDependency)
+		}
+		if _, ok := graph[dependency]; !ok {
+			return fmt.Errorf("%w: application %s is not in environment %s",
+				ErrInvalidApplicationDependency, dependency, environmentUUID)
+		}
+	}
+
+	graph[applicationUUID] = dependsOn
+	if _, cycle := models.DependencyOrder(graph); cycle != nil {
+		names := make([]string, len(cycle))
+		for i, uuid := range cycle {
+			names[i] = uuid
+			if slugs[uuid] != "" {
+				names[i] = slugs[uuid]
+			}
+		}
+		return fmt.Errorf("%w: dependency cycle %s", ErrInvalidApplicationDependency, strings.Join(names, " -> "))
+	}
+
+	return nil
+}
+
+// dependencyRefs converts application UUIDs to references to their Application CRDs
+func dependencyRefs(dependsOn []string) []corev1.LocalObjectReference {
+	if len(dependsOn) == 0 {
+		return nil
+	}
+	refs := make([]corev1.LocalObjectReference, len(dependsOn))
+	for i, uuid := range dependsOn {
+		refs[i] = corev1.LocalObjectReference{Name: utils.GetApplicationResourceName(uuid)}
+	}
+	return refs
+}
+
+// dependencyUUIDs converts references to Application CRDs to application UUIDs
+func dependencyUUIDs(refs []corev1.LocalObjectReference) []string {
+	if len(refs) == 0 {
+		return nil
+	}
+	uuids := make([]string, len(refs))
+	for i, ref := range refs {
+		uuids[i] = utils.GetApplicationUUID(ref.Name)
+	}
+	return uuids
+}
@@ -55,34 +55,9 @@ func (s *ApplicationDomainService) CreateApplicationDomain(ctx context.Context,
 		return nil, fmt.Errorf("failed to get application: %w", err)
 	}
 
-	// Generate random slug for application domain
-	slug, err := utils.GenerateRandomSlug()
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate application domain slug: %w", err)
-	}
-
-	// Check if slug already exists (very unlikely but possible)
-	exists, err := s.slugExists(ctx, slug)
+	slug, err := s.generateSlug(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check slug uniqueness: %w", err)
-	}
-
-	// If slug exists, try generating a new one (up to 3 attempts)
-	attempts := 0
-	for exists && attempts < 3 {
-		slug, err = utils.GenerateRandomSlug()
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate application domain slug: %w", err)
-		}
-		exists, err = s.slugExists(ctx, slug)
-		if err != nil {
-			return nil, fmt.Errorf("failed to check slug uniqueness: %w", err)
-		}
-		attempts++
-	}
-
-	if exists {
-		return nil, fmt.Errorf("failed to generate unique slug after 3 attempts")
+		return nil, err
 	}
 
 	// Set default values
@@ -103,6 +78,7 @@ func (s *ApplicationDomainService) CreateApplicationDomain(ctx context.Context,
 		req.Default,
 		req.TLSEnabled,
 	)
+	applicationDomain.IgnoreTLSPolicy = req.IgnoreTLSPolicy
 
 	// Create Kubernetes ApplicationDomain CRD
 	crd := s.convertToApplicationDomainCRD(applicationDomain, application)
@@ -118,6 +94,120 @@ func (s *ApplicationDomainService) CreateApplicationDomain(ctx context.Context,
 	return applicationDomain, nil
 }
 
+// BulkCreateApplicationDomains imports several domains of an application. Every domain is checked
+// before any is created: malformed, repeated and already used domains are reported. Atomic imports
+// create nothing unless every domain is valid, and roll back the created domains when one fails.
+func (s *ApplicationDomainService) BulkCreateApplicationDomains(ctx context.Context, applicationSlug string, req *models.ApplicationDomainBulkCreateRequest) (*models.ApplicationDomainBulkCreateResponse, error) {
+	application, err := s.applicationService.GetApplication(ctx, applicationSlug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	results := req.CheckDomains()
+
+	// Domains are unique across the cluster, whatever application serves them
+	var domainList v1alpha1.ApplicationDomainList
+	if err := s.client.List(ctx, &domainList); err != nil {
+		return nil, fmt.Errorf("failed to list application domains: %w", err)
+	}
+	used := make(map[string]string, len(domainList.Items))
+	for _, existing := range domainList.Items {
+		used[existing.Spec.Domain] = existing.Name
+	}
+
+	valid := true
+	for i := range results {
+		if results[i].Status == "" {
+			if name, ok := used[results[i].Domain]; ok {
+				results[i].Status = models.BulkDomainConflict
+				results[i].Message = fmt.Sprintf("domain is already used by application domain %s", name)
+			}
+		}
+		valid = valid && results[i].Status == ""
+	}
+
+	if req.Atomic && !valid {
+		skipPending(results, "not created, other domains of the request are not valid")
+		return models.NewApplicationDomainBulkCreateResponse(results), nil
+	}
+
+	var created []*v1alpha1.ApplicationDomain
+	for i := range results {
+		if results[i].Status != "" {
+			continue
+		}
+
+		applicationDomain, crd, err := s.createBulkDomain(ctx, application, results[i].Domain, req)
+		if err != nil {
+			results[i].Status = models.BulkDomainFailed
+			results[i].Message = err.Error()
+			if !req.Atomic {
+				continue
+			}
+
+			// Roll back the domains of the atomic import created so far
+			for _, domain := range created {
+				if err := s.client.Delete(ctx, domain); client.IgnoreNotFound(err) != nil {
+					return nil, fmt.Errorf("failed to roll back application domain %s: %w", domain.Spec.Domain, err)
+				}
+			}
+			for j := range results {
+				if results[j].Status == models.BulkDomainCreated {
+					results[j].Status = models.BulkDomainSkipped
+					results[j].Message = "rolled back, another domain of the request failed"
+					results[j].ApplicationDomain = nil
+				}
+			}
+			skipPending(results, "not created, another domain of the request failed")
+			return models.NewApplicationDomainBulkCreateResponse(results), nil
+		}
+
+		created = append(created, crd)
+		response := applicationDomain.ToResponse()
+		results[i].Status = models.BulkDomainCreated
+		results[i].ApplicationDomain = &response
+	}
+
+	return models.NewApplicationDomainBulkCreateResponse(results), nil
+}
+
+// createBulkDomain creates one domain of a bulk import
+func (s *ApplicationDomainService) createBulkDomain(ctx context.Context, application *models.Application, domain string, req *models.ApplicationDomainBulkCreateRequest) (*models.ApplicationDomain, *v1alpha1.ApplicationDomain, error) {
+	slug, err := s.generateSlug(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	applicationDomain := models.NewApplicationDomain(
+		application.UUID,
+		application.Slug,
+		application.ProjectUUID,
+		slug,
+		domain,
+		req.Port,
+		req.DomainType(),
+		false,
+		req.TLSEnabled,
+	)
+	applicationDomain.IgnoreTLSPolicy = req.IgnoreTLSPolicy
+
+	crd := s.convertToApplicationDomainCRD(applicationDomain, application)
+	if err := s.client.Create(ctx, crd); err != nil {
+		return nil, nil, fmt.Errorf("failed to create ApplicationDomain CRD: %w", err)
+	}
+	return applicationDomain, crd, nil
+}
+
+// skipPending marks the domains of a bulk import still without a status as skipped
+func skipPending(results []models.ApplicationDomainBulkResult, message string) {
+	for i := range results {
+		if results[i].Status == "" {
+			results[i].Status = models.BulkDomainSkipped
+			results[i].Message = message
+		}
+	}
+}
+
 // GetApplicationDomain retrieves an application domain by UUID
 func (s *ApplicationDomainService) GetApplicationDomain(ctx context.Context, uuid string) (*models.ApplicationDomain, error) {
 	// List all application domains and find by UUID label only
@@ -235,6 +325,40 @@ func (s *ApplicationDomainService) DeleteApplicationDomain(ctx context.Context,
 	return nil
 }
 
+// generateSlug returns a random application domain slug no domain uses yet
+func (s *ApplicationDomainService) generateSlug(ctx context.Context) (string, error) {
+	// Generate random slug for application domain
+	slug, err := utils.GenerateRandomSlug()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate application domain slug: %w", err)
+	}
+
+	// Check if slug already exists (very unlikely but possible)
+	exists, err := s.slugExists(ctx, slug)
+	if err != nil {
+		return "", fmt.Errorf("failed to check slug uniqueness: %w", err)
+	}
+
+	// If slug exists, try generating a new one (up to 3 attempts)
+	attempts := 0
+	for exists && attempts < 3 {
+		slug, err = utils.GenerateRandomSlug()
+		if err != nil {
+			return "", fmt.Errorf("failed to generate application domain slug: %w", err)
+		}
+		exists, err = s.slugExists(ctx, slug)
+		if err != nil {
+			return "", fmt.Errorf("failed to check slug uniqueness: %w", err)
+		}
+		attempts++
+	}
+
+	if exists {
+		return "", fmt.Errorf("failed to generate unique slug after 3 attempts")
+	}
+	return slug, nil
+}
+
 // slugExists checks if an application domain with the given slug already exists
 func (s *ApplicationDomainService) slugExists(ctx context.Context, slug string) (bool, error) {
 	var domainList v1alpha1.ApplicationDomainList
@@ -298,11 +422,12 @@ func (s *ApplicationDomainService) convertToApplicationDomainCRD(applicationDoma
 			ApplicationRef: corev1.LocalObjectReference{
 				Name: utils.GetApplicationResourceName(applicationDomain.ApplicationUUID),
 			},
-			Domain:     applicationDomain.Domain,
-			Port:       applicationDomain.Port,
-			Type:       v1alpha1.ApplicationDomainType(applicationDomain.Type),
-			Default:    applicationDomain.Default,
-			TLSEnabled: applicationDomain.TLSEnabled,
+			Domain:          applicationDomain.Domain,
+			Port:            applicationDomain.Port,
+			Type:            v1alpha1.ApplicationDomainType(applicationDomain.Type),
+			Default:         applicationDomain.Default,
+			TLSEnabled:      applicationDomain.TLSEnabled,
+			IgnoreTLSPolicy: applicationDomain.IgnoreTLSPolicy,
 		},
 	}
 }
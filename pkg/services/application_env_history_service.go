@@ -0,0 +1,359 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/envcrypt"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	// maxApplicationEnvVersions is how many env versions are kept per application, older ones are pruned
+	maxApplicationEnvVersions = 50
+
+	// applicationEnvVersionType is the type label of application env version Secrets
+	applicationEnvVersionType = "application-env-version"
+)
+
+// ErrEnvRollbackNotAllowed is returned when an application cannot be rolled back to an env version
+var ErrEnvRollbackNotAllowed = errors.New("env rollback not allowed")
+
+// applicationEnvSecretName returns the name of the env var Secret of an application
+func applicationEnvSecretName(app *v1alpha1.Application) (string, error) {
+	var env *corev1.LocalObjectReference
+	switch app.Spec.Type {
+	case v1alpha1.ApplicationTypeGitRepository:
+		if app.Spec.GitRepository != nil {
+			env = app.Spec.GitRepository.Env
+		}
+	case v1alpha1.ApplicationTypeDockerImage:
+		if app.Spec.DockerImage != nil {
+			env = app.Spec.DockerImage.Env
+		}
+	case v1alpha1.ApplicationTypeMySQL:
+		if app.Spec.MySQL != nil {
+			env = app.Spec.MySQL.Env
+		}
+	case v1alpha1.ApplicationTypeMySQLCluster:
+		if app.Spec.MySQLCluster != nil {
+			env = app.Spec.MySQLCluster.Env
+		}
+	case v1alpha1.ApplicationTypePostgres:
+		if app.Spec.Postgres != nil {
+			env = app.Spec.Postgres.Env
+		}
+	case v1alpha1.ApplicationTypePostgresCluster:
+		if app.Spec.PostgresCluster != nil {
+			env = app.Spec.PostgresCluster.Env
+		}
+	default:
+		return "", fmt.Errorf("unsupported application type: %s", app.Spec.Type)
+	}
+	if env == nil {
+		return "", fmt.Errorf("application does not have an environment variables secret configured")
+	}
+	return env.Name, nil
+}
+
+// envDataHash returns a stable hash of env var values, stored encrypted values are hashed as is
+func envDataHash(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write(data[key])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// GetApplicationEnvVersions lists the recorded env versions of an application, newest first
+func (s *ApplicationService) GetApplicationEnvVersions(ctx context.Context, uuid string) (*models.ApplicationEnvVersionsResponse, error) {
+	app, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := s.listEnvVersions(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ApplicationEnvVersionsResponse{
+		ApplicationUUID: uuid,
+		Versions:        models.BuildApplicationEnvVersions(secrets),
+	}, nil
+}
+
+// RollbackApplicationEnv restores the env vars of an application to a recorded version, which is
+// recorded as a new version attributed to changedBy. A deployment running the image of the
+// current deployment is created to apply the restored env vars, released like any deployment
+// of the application unless a freeze window is active and overrideFreeze is not set.
+func (s *ApplicationService) RollbackApplicationEnv(ctx context.Context, uuid string, version int, changedBy string, req *models.ApplicationEnvRollbackRequest) (*models.ApplicationEnvRollbackResponse, error) {
+	app, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot corev1.Secret
+	if err := s.client.Get(ctx, client.ObjectKey{
+		Namespace: app.Namespace,
+		Name:      utils.GetApplicationEnvVersionName(uuid, version),
+	}, &snapshot); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("env version %d of application %s not found", version, uuid)
+		}
+		return nil, fmt.Errorf("failed to get env version: %w", err)
+	}
+
+	secretName, err := applicationEnvSecretName(app)
+	if err != nil {
+		return nil, err
+	}
+	var secret corev1.Secret
+	if err := s.client.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: secretName}, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("environment variables secret %s not found", secretName)
+		}
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+	if envDataHash(secret.Data) == envDataHash(snapshot.Data) {
+		return nil, fmt.Errorf("%w: the application already uses the env vars of version %d", ErrEnvRollbackNotAllowed, version)
+	}
+
+	secret.Data = maps.Clone(snapshot.Data)
+	// Values stay encrypted in the snapshot, restore the provider annotation with them
+	if provider := snapshot.Annotations[envcrypt.AnnotationProvider]; provider != "" {
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[envcrypt.AnnotationProvider] = provider
+	} else {
+		delete(secret.Annotations, envcrypt.AnnotationProvider)
+	}
+	if err := s.client.Update(ctx, &secret); err != nil {
+		return nil, fmt.Errorf("failed to update secret: %w", err)
+	}
+
+	recorded, err := s.recordEnvVersion(ctx, app, &secret, changedBy, version)
+	if err != nil {
+		return nil, err
+	}
+	response := &models.ApplicationEnvRollbackResponse{Version: models.NewApplicationEnvVersionResponse(recorded)}
+
+	deployment, err := s.deploymentService.redeployCurrentImage(ctx, app, req.OverrideFreeze)
+	if err != nil {
+		return nil, fmt.Errorf("env vars restored to version %d but the redeployment failed: %w", version, err)
+	}
+	if deployment != nil {
+		deploymentResponse := deployment.ToResponse()
+		response.Deployment = &deploymentResponse
+	}
+	return response, nil
+}
+
+// recordBaselineEnvVersion records the env vars an application had before history was kept as its
+// first version
+func (s *ApplicationService) recordBaselineEnvVersion(ctx context.Context, app *v1alpha1.Application, secret *corev1.Secret) error {
+	if len(secret.Data) == 0 {
+		return nil
+	}
+	versions, err := s.listEnvVersions(ctx, app)
+	if err != nil || len(versions) > 0 {
+		return err
+	}
+	_, err = s.recordEnvVersion(ctx, app, secret, "", 0)
+	return err
+}
+
+// recordEnvVersion snapshots the env var Secret of an application as its next version, unless the
+// values did not change since the latest version. restoredFrom is the version a rollback restored.
+func (s *ApplicationService) recordEnvVersion(ctx context.Context, app *v1alpha1.Application, secret *corev1.Secret, changedBy string, restoredFrom int) (*corev1.Secret, error) {
+	versions, err := s.listEnvVersions(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := envDataHash(secret.Data)
+	latest := 0
+	var latestSecret *corev1.Secret
+	for i := range versions {
+		if version := envVersionNumber(&versions[i]); version > latest {
+			latest = version
+			latestSecret = &versions[i]
+		}
+	}
+	if latestSecret != nil && latestSecret.Annotations[validation.AnnotationEnvVersionHash] == hash && restoredFrom == 0 {
+		return latestSecret, nil
+	}
+
+	appUUID := app.GetUUID()
+	snapshot := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.GetApplicationEnvVersionName(appUUID, latest+1),
+			Namespace: app.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by":        "kibaship",
+				validation.LabelApplicationUUID:       appUUID,
+				"platform.operator.kibaship.com/type": applicationEnvVersionType,
+				validation.LabelProjectUUID:           app.Labels[validation.LabelProjectUUID],
+			},
+			Annotations: map[string]string{
+				validation.AnnotationEnvVersion:     strconv.Itoa(latest + 1),
+				validation.AnnotationEnvVersionHash: hash,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: maps.Clone(secret.Data),
+	}
+	if changedBy != "" {
+		snapshot.Annotations[validation.AnnotationEnvChangedBy] = changedBy
+	}
+	if restoredFrom > 0 {
+		snapshot.Annotations[validation.AnnotationEnvRestoredFrom] = strconv.Itoa(restoredFrom)
+	}
+	if provider := secret.Annotations[envcrypt.AnnotationProvider]; provider != "" {
+		snapshot.Annotations[envcrypt.AnnotationProvider] = provider
+	}
+	if err := controllerutil.SetControllerReference(app, snapshot, s.scheme); err != nil {
+		return nil, fmt.Errorf("failed to set owner reference on env version: %w", err)
+	}
+	if err := s.client.Create(ctx, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to record env version: %w", err)
+	}
+
+	// Prune the oldest versions beyond the retention
+	sort.Slice(versions, func(i, j int) bool {
+		return envVersionNumber(&versions[i]) < envVersionNumber(&versions[j])
+	})
+	for i := 0; i < len(versions)+1-maxApplicationEnvVersions; i++ {
+		if err := s.client.Delete(ctx, &versions[i]); client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to prune env version %s: %w", versions[i].Name, err)
+		}
+	}
+	return snapshot, nil
+}
+
+// listEnvVersions returns the env version Secrets of an application
+func (s *ApplicationService) listEnvVersions(ctx context.Context, app *v1alpha1.Application) ([]corev1.Secret, error) {
+	var secrets corev1.SecretList
+	if err := s.client.List(ctx, &secrets, client.InNamespace(app.Namespace), client.MatchingLabels{
+		validation.LabelApplicationUUID:       app.GetUUID(),
+		"platform.operator.kibaship.com/type": applicationEnvVersionType,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list env versions: %w", err)
+	}
+	return secrets.Items, nil
+}
+
+// envVersionNumber returns the version number of an env version Secret
+func envVersionNumber(secret *corev1.Secret) int {
+	version, _ := strconv.Atoi(secret.Annotations[validation.AnnotationEnvVersion])
+	return version
+}
+
+// redeployCurrentImage creates a deployment of an application running the image of its current
+// deployment, so changed env vars are applied without building again. Nothing is deployed for
+// applications that were never deployed or do not run an image.
+func (s *DeploymentService) redeployCurrentImage(ctx context.Context, app *v1alpha1.Application, overrideFreeze bool) (*models.Deployment, error) {
+	if app.Spec.CurrentDeploymentRef == nil {
+		return nil, nil
+	}
+	if app.Spec.Type != v1alpha1.ApplicationTypeGitRepository && app.Spec.Type != v1alpha1.ApplicationTypeImageFromRegistry {
+		return nil, nil
+	}
+
+	var current v1alpha1.Deployment
+	if err := s.client.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: app.Spec.CurrentDeploymentRef.Name}, &current); err != nil {
+		return nil, fmt.Errorf("failed to get current deployment: %w", err)
+	}
+
+	application, err := s.getApplicationByUUID(ctx, app.GetUUID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	slug, err := s.generateUniqueSlug(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var gitRepository *models.GitRepositoryDeploymentConfig
+	if current.Spec.GitRepository != nil {
+		gitRepository = &models.GitRepositoryDeploymentConfig{
+			CommitSHA: current.Spec.GitRepository.CommitSHA,
+			Branch:    current.Spec.GitRepository.Branch,
+		}
+	}
+	deployment := models.NewDeployment(application.UUID, application.Slug, application.ProjectUUID, slug, gitRepository)
+	deployment.Source = models.DeploymentSourceFromAnnotations(current.GetAnnotations())
+
+	crd := s.convertToDeploymentCRD(deployment, application, true)
+	crd.Spec.OverrideFreeze = overrideFreeze
+	if current.Spec.ImageFromRegistry != nil {
+		crd.Spec.ImageFromRegistry = current.Spec.ImageFromRegistry.DeepCopy()
+	}
+	if current.Spec.ExternalBuild != nil {
+		// Run the externally built image again
+		crd.Spec.ExternalBuild = current.Spec.ExternalBuild.DeepCopy()
+	} else if app.Spec.Type == v1alpha1.ApplicationTypeGitRepository {
+		// Reuse the image built for the current deployment, within the same environment
+		promotedFrom := current.Spec.PromotedFrom.DeepCopy()
+		if promotedFrom == nil {
+			promotedFrom = &v1alpha1.PromotionSource{
+				DeploymentUUID:  current.GetUUID(),
+				EnvironmentUUID: current.GetEnvironmentUUID(),
+				Image:           utils.GetBuiltImageName(current.Namespace, app.GetUUID(), current.GetUUID(), current.Status.ImageDigest),
+			}
+		}
+		crd.Spec.PromotedFrom = promotedFrom
+	}
+
+	if err := s.client.Create(ctx, crd); err != nil {
+		return nil, fmt.Errorf("failed to create Deployment CRD: %w", err)
+	}
+
+	redeployed := &models.Deployment{}
+	redeployed.ConvertFromCRD(crd, application.Slug)
+	if redeployed.Phase == "" {
+		redeployed.Phase = models.DeploymentPhaseInitializing
+	}
+	return redeployed, nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+// ErrLogStoreNotConfigured is returned when historical logs are requested but no log store is configured
+var ErrLogStoreNotConfigured = errors.New("persistent logging is not configured")
+
+// lokiQueryTimeout bounds a single Loki query
+const lokiQueryTimeout = 30 * time.Second
+
+// LokiClient queries application logs from Loki
+type LokiClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewLokiClient creates a LokiClient for the Loki at baseURL
+func NewLokiClient(baseURL string) *LokiClient {
+	return &LokiClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: lokiQueryTimeout},
+	}
+}
+
+// lokiQueryRangeResponse is the subset of the Loki query_range response used for log streams
+type lokiQueryRangeResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// QueryRange runs a LogQL log query over the given period and returns the lines merged across
+// streams, ordered by direction and truncated to limit
+func (l *LokiClient) QueryRange(ctx context.Context, query string, start, end time.Time, limit int, direction string) ([]models.ApplicationLogEntry, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("start", strconv.FormatInt(start.UnixNano(), 10))
+	params.Set("end", strconv.FormatInt(end.UnixNano(), 10))
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("direction", direction)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.baseURL+"/loki/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Loki request: %w", err)
+	}
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Loki: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("loki returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result lokiQueryRangeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode Loki response: %w", err)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("loki query failed with status %s", result.Status)
+	}
+
+	entries := []models.ApplicationLogEntry{}
+	for _, stream := range result.Data.Result {
+		for _, value := range stream.Values {
+			ns, err := strconv.ParseInt(value[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid Loki timestamp %q: %w", value[0], err)
+			}
+			entries = append(entries, models.ApplicationLogEntry{
+				Timestamp:      time.Unix(0, ns).UTC(),
+				Line:           value[1],
+				Pod:            stream.Stream[config.LogLabelPod],
+				Container:      stream.Stream[config.LogLabelContainer],
+				DeploymentUUID: stream.Stream[config.LogLabelDeploymentUUID],
+			})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if direction == models.LogDirectionForward {
+			return entries[i].Timestamp.Before(entries[j].Timestamp)
+		}
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// GetApplicationLogHistory returns the persisted logs of an application, including logs of pods
+// that no longer exist
+func (s *ApplicationService) GetApplicationLogHistory(ctx context.Context, uuid string, req *models.ApplicationLogHistoryRequest) (*models.ApplicationLogHistoryResponse, error) {
+	if s.logs == nil {
+		return nil, ErrLogStoreNotConfigured
+	}
+
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := req.TimeRange(time.Now())
+	limit := req.Limit
+	if limit == 0 {
+		limit = models.DefaultLogHistoryLimit
+	}
+	direction := req.Direction
+	if direction == "" {
+		direction = models.LogDirectionBackward
+	}
+
+	entries, err := s.logs.QueryRange(ctx, applicationLogQuery(crd.Namespace, crd.GetUUID(), req.Filter), start, end, limit, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ApplicationLogHistoryResponse{
+		ApplicationUUID: crd.GetUUID(),
+		Start:           start,
+		End:             end,
+		Direction:       direction,
+		Entries:         entries,
+	}, nil
+}
+
+// applicationLogQuery builds the LogQL query selecting the logs of an application, keeping the
+// lines that contain filter when it is set
+func applicationLogQuery(namespace, applicationUUID, filter string) string {
+	query := fmt.Sprintf(`{%s=%s, %s=%s}`,
+		config.LogLabelNamespace, strconv.Quote(namespace),
+		config.LogLabelApplicationUUID, strconv.Quote(applicationUUID))
+	if filter != "" {
+		query += " |= " + strconv.Quote(filter)
+	}
+	return query
+}
@@ -18,6 +18,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
@@ -25,13 +26,20 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/envcrypt"
 	"github.com/kibamail/kibaship/pkg/models"
 	"github.com/kibamail/kibaship/pkg/utils"
 	"github.com/kibamail/kibaship/pkg/validation"
 )
 
+// ErrInvalidEnvTemplate is returned when the templated env values of an application reference
+// unknown values or each other in a cycle
+var ErrInvalidEnvTemplate = errors.New("invalid env template")
+
 // ApplicationService handles CRUD operations for applications
 type ApplicationService struct {
 	client             client.Client
@@ -40,6 +48,10 @@ type ApplicationService struct {
 	environmentService *EnvironmentService
 	domainService      *ApplicationDomainService
 	deploymentService  *DeploymentService
+	encryptor          *envcrypt.Encryptor
+	logs               *LokiClient
+	metrics            *PrometheusClient
+	ingressProvider    config.IngressProvider
 }
 
 // NewApplicationService creates a new ApplicationService
@@ -64,35 +76,58 @@ func (s *ApplicationService) SetDeploymentService(deploymentService *DeploymentS
 	s.deploymentService = deploymentService
 }
 
+// SetEncryptor enables encryption at rest for environment variable values
+func (s *ApplicationService) SetEncryptor(encryptor *envcrypt.Encryptor) {
+	s.encryptor = encryptor
+}
+
+// SetLogStore enables historical log queries against the Loki the operator ships logs to
+func (s *ApplicationService) SetLogStore(logs *LokiClient) {
+	s.logs = logs
+}
+
+// SetMetricsStore enables traffic metrics read from a Prometheus scraping the ingress controller
+// of provider
+func (s *ApplicationService) SetMetricsStore(metrics *PrometheusClient, provider config.IngressProvider) {
+	s.metrics = metrics
+	s.ingressProvider = provider
+}
+
 // CreateApplication creates a new application
 func (s *ApplicationService) CreateApplication(ctx context.Context, req *models.ApplicationCreateRequest) (*models.Application, error) {
+	application, _, err := s.createApplication(ctx, req, nil)
+	return application, err
+}
+
+// createApplication creates a new application, customize adjusts the Application CRD before it is created
+func (s *ApplicationService) createApplication(ctx context.Context, req *models.ApplicationCreateRequest, customize func(*v1alpha1.Application)) (*models.Application, *v1alpha1.Application, error) {
 	// First, verify the environment exists and get its details
 	environment, err := s.environmentService.GetEnvironment(ctx, req.EnvironmentUUID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get environment: %w", err)
+		return nil, nil, fmt.Errorf("failed to get environment: %w", err)
 	}
 
 	// Get the project to check application type enablement
 	project, err := s.projectService.GetProject(ctx, environment.ProjectUUID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get project: %w", err)
+		return nil, nil, fmt.Errorf("failed to get project: %w", err)
 	}
 
 	// Check if the application type is enabled for this project
 	if !s.isApplicationTypeEnabled(project, req.Type) {
-		return nil, fmt.Errorf("application type '%s' is not enabled for project '%s'", req.Type, environment.ProjectSlug)
+		return nil, nil, fmt.Errorf("application type '%s' is not enabled for project '%s'", req.Type, environment.ProjectSlug)
 	}
 
 	// Generate random slug
 	slug, err := utils.GenerateRandomSlug()
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate application slug: %w", err)
+		return nil, nil, fmt.Errorf("failed to generate application slug: %w", err)
 	}
 
 	// Check if slug already exists (very unlikely but possible)
 	exists, err := s.slugExists(ctx, slug)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check slug uniqueness: %w", err)
+		return nil, nil, fmt.Errorf("failed to check slug uniqueness: %w", err)
 	}
 
 	// If slug exists, try generating a new one (up to 3 attempts)
@@ -100,17 +135,17 @@ func (s *ApplicationService) CreateApplication(ctx context.Context, req *models.
 	for exists && attempts < 3 {
 		slug, err = utils.GenerateRandomSlug()
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate application slug: %w", err)
+			return nil, nil, fmt.Errorf("failed to generate application slug: %w", err)
 		}
 		exists, err = s.slugExists(ctx, slug)
 		if err != nil {
-			return nil, fmt.Errorf("failed to check slug uniqueness: %w", err)
+			return nil, nil, fmt.Errorf("failed to check slug uniqueness: %w", err)
 		}
 		attempts++
 	}
 
 	if exists {
-		return nil, fmt.Errorf("failed to generate unique slug after 3 attempts")
+		return nil, nil, fmt.Errorf("failed to generate unique slug after 3 attempts")
 	}
 
 	// Create internal application model
@@ -124,19 +159,29 @@ func (s *ApplicationService) CreateApplication(ctx context.Context, req *models.
 
 	// Set type-specific configuration
 	s.setApplicationConfiguration(application, req)
+	application.BaseDomain = req.BaseDomain
+	application.PlatformEnv = req.PlatformEnv == nil || *req.PlatformEnv
+
+	if err := s.validateDependencies(ctx, environment.UUID, application.UUID, req.DependsOn); err != nil {
+		return nil, nil, err
+	}
+	application.DependsOn = req.DependsOn
 
 	// Create Kubernetes Application CRD
 	crd := s.convertToApplicationCRD(application, environment)
+	if customize != nil {
+		customize(crd)
+	}
 
 	err = s.client.Create(ctx, crd)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Application CRD: %w", err)
+		return nil, nil, fmt.Errorf("failed to create Application CRD: %w", err)
 	}
 
 	// Update application with CRD information
 	application.Status = "Pending" // Will be updated by the operator
 
-	return application, nil
+	return application, crd, nil
 }
 
 // GetApplication retrieves an application by UUID with domains auto-loaded
@@ -194,6 +239,12 @@ func (s *ApplicationService) UpdateApplication(ctx context.Context, uuid string,
 	// Get the existing CRD
 	existingCRD := &applicationList.Items[0]
 
+	if req.DependsOn != nil {
+		if err := s.validateDependencies(ctx, existingCRD.Labels[validation.LabelEnvironmentUUID], uuid, *req.DependsOn); err != nil {
+			return nil, err
+		}
+	}
+
 	// Apply updates to annotations and spec
 	s.applyApplicationUpdates(existingCRD, req)
 
@@ -227,8 +278,9 @@ func (s *ApplicationService) UpdateApplication(ctx context.Context, uuid string,
 	return updatedApplication, nil
 }
 
-// UpdateApplicationEnv updates environment variables for an application
-func (s *ApplicationService) UpdateApplicationEnv(ctx context.Context, uuid string, req *models.ApplicationEnvUpdateRequest) error {
+// UpdateApplicationEnv updates environment variables for an application. Every change is recorded
+// as a new env version attributed to changedBy, the application can be rolled back to it later.
+func (s *ApplicationService) UpdateApplicationEnv(ctx context.Context, uuid string, req *models.ApplicationEnvUpdateRequest, changedBy string) error {
 	// First get the existing application
 	var applicationList v1alpha1.ApplicationList
 	err := s.client.List(ctx, &applicationList, client.MatchingLabels{
@@ -248,41 +300,9 @@ func (s *ApplicationService) UpdateApplicationEnv(ctx context.Context, uuid stri
 
 	app := &applicationList.Items[0]
 
-	// Get the env secret ref based on application type
-	var secretName string
-	switch app.Spec.Type {
-	case v1alpha1.ApplicationTypeGitRepository:
-		if app.Spec.GitRepository == nil || app.Spec.GitRepository.Env == nil {
-			return fmt.Errorf("application does not have an environment variables secret configured")
-		}
-		secretName = app.Spec.GitRepository.Env.Name
-	case v1alpha1.ApplicationTypeDockerImage:
-		if app.Spec.DockerImage == nil || app.Spec.DockerImage.Env == nil {
-			return fmt.Errorf("application does not have an environment variables secret configured")
-		}
-		secretName = app.Spec.DockerImage.Env.Name
-	case v1alpha1.ApplicationTypeMySQL:
-		if app.Spec.MySQL == nil || app.Spec.MySQL.Env == nil {
-			return fmt.Errorf("application does not have an environment variables secret configured")
-		}
-		secretName = app.Spec.MySQL.Env.Name
-	case v1alpha1.ApplicationTypeMySQLCluster:
-		if app.Spec.MySQLCluster == nil || app.Spec.MySQLCluster.Env == nil {
-			return fmt.Errorf("application does not have an environment variables secret configured")
-		}
-		secretName = app.Spec.MySQLCluster.Env.Name
-	case v1alpha1.ApplicationTypePostgres:
-		if app.Spec.Postgres == nil || app.Spec.Postgres.Env == nil {
-			return fmt.Errorf("application does not have an environment variables secret configured")
-		}
-		secretName = app.Spec.Postgres.Env.Name
-	case v1alpha1.ApplicationTypePostgresCluster:
-		if app.Spec.PostgresCluster == nil || app.Spec.PostgresCluster.Env == nil {
-			return fmt.Errorf("application does not have an environment variables secret configured")
-		}
-		secretName = app.Spec.PostgresCluster.Env.Name
-	default:
-		return fmt.Errorf("unsupported application type: %s", app.Spec.Type)
+	secretName, err := applicationEnvSecretName(app)
+	if err != nil {
+		return err
 	}
 
 	// Fetch the secret
@@ -298,6 +318,15 @@ func (s *ApplicationService) UpdateApplicationEnv(ctx context.Context, uuid stri
 		return fmt.Errorf("failed to get secret: %w", err)
 	}
 
+	if err := s.validateEnvTemplates(ctx, secret.Data, req.Variables); err != nil {
+		return err
+	}
+
+	// Env vars set before history was kept become the first version, so they can be restored
+	if err := s.recordBaselineEnvVersion(ctx, app, &secret); err != nil {
+		return err
+	}
+
 	// Merge incoming variables with existing ones
 	if secret.Data == nil {
 		secret.Data = make(map[string][]byte)
@@ -307,15 +336,84 @@ func (s *ApplicationService) UpdateApplicationEnv(ctx context.Context, uuid stri
 		secret.Data[key] = []byte(value)
 	}
 
+	// Encrypt values before they reach etcd when a KMS is configured
+	if s.encryptor != nil {
+		encrypted, err := s.encryptor.EncryptData(ctx, secret.Data)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt environment variables: %w", err)
+		}
+		secret.Data = encrypted
+		if secret.Annotations == nil {
+			secret.Annotations = make(map[string]string)
+		}
+		secret.Annotations[envcrypt.AnnotationProvider] = s.encryptor.ProviderName()
+	}
+
 	// Update the secret
 	err = s.client.Update(ctx, &secret)
 	if err != nil {
 		return fmt.Errorf("failed to update secret: %w", err)
 	}
 
+	if _, err := s.recordEnvVersion(ctx, app, &secret, changedBy, 0); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateEnvTemplates checks the templated values of the env vars of an application once the
+// updates are merged into its current values
+func (s *ApplicationService) validateEnvTemplates(ctx context.Context, current map[string][]byte, updates map[string]string) error {
+	env := make(map[string]string, len(current)+len(updates))
+	for key, value := range current {
+		if envcrypt.IsEncrypted(value) {
+			if s.encryptor == nil {
+				// Checked again when the operator decrypts the values on deploy
+				env[key] = ""
+				continue
+			}
+			decrypted, err := s.encryptor.Decrypt(ctx, value)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt environment variable %s: %w", key, err)
+			}
+			value = decrypted
+		}
+		env[key] = string(value)
+	}
+	for key, value := range updates {
+		env[key] = value
+	}
+
+	if err := v1alpha1.ValidateEnvTemplates(env); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEnvTemplate, err)
+	}
 	return nil
 }
 
+// newApplicationEnvSecret returns the environment variables Secret of an application under the
+// name the operator expects, so the operator adopts it instead of creating an empty one
+func (s *ApplicationService) newApplicationEnvSecret(app *v1alpha1.Application) (*corev1.Secret, error) {
+	appUUID := app.Labels[validation.LabelResourceUUID]
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.GetApplicationResourceName(appUUID),
+			Namespace: app.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by":        "kibaship",
+				validation.LabelApplicationUUID:       appUUID,
+				"platform.operator.kibaship.com/type": "application-env-vars",
+				validation.LabelProjectUUID:           app.Labels[validation.LabelProjectUUID],
+				validation.LabelEnvironmentUUID:       app.Labels[validation.LabelEnvironmentUUID],
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+	if err := controllerutil.SetControllerReference(app, secret, s.scheme); err != nil {
+		return nil, fmt.Errorf("failed to set owner reference on secret: %w", err)
+	}
+	return secret, nil
+}
+
 // DeleteApplication deletes an application by UUID
 func (s *ApplicationService) DeleteApplication(ctx context.Context, uuid string) error {
 	// First check if application exists
@@ -613,6 +711,9 @@ func (s *ApplicationService) convertToApplicationCRD(app *models.Application, en
 				Name: utils.GetEnvironmentResourceName(environment.UUID),
 			},
 			Type:            s.convertApplicationType(app.Type),
+			BaseDomain:      app.BaseDomain,
+			DependsOn:       dependencyRefs(app.DependsOn),
+			PlatformEnv:     platformEnvSpec(app.PlatformEnv),
 			GitRepository:   s.convertGitRepositoryConfig(app.GitRepository),
 			DockerImage:     s.convertDockerImageConfig(app.DockerImage),
 			MySQL:           s.convertMySQLConfig(app.MySQL),
@@ -641,23 +742,61 @@ func (s *ApplicationService) convertFromApplicationCRD(crd *v1alpha1.Application
 	// The actual project relationship is maintained through the project-uuid label
 
 	return &models.Application{
-		UUID:            labels[validation.LabelResourceUUID],
-		Name:            annotations[validation.AnnotationResourceName],
-		Slug:            labels[validation.LabelResourceSlug],
-		ProjectUUID:     labels[validation.LabelProjectUUID],
-		ProjectSlug:     projectSlug,
-		EnvironmentUUID: labels[validation.LabelEnvironmentUUID],
-		Type:            s.convertApplicationTypeFromCRD(crd.Spec.Type),
-		GitRepository:   s.convertGitRepositoryConfigFromCRD(crd.Spec.GitRepository),
-		DockerImage:     s.convertDockerImageConfigFromCRD(crd.Spec.DockerImage),
-		MySQL:           s.convertMySQLConfigFromCRD(crd.Spec.MySQL),
-		MySQLCluster:    s.convertMySQLClusterConfigFromCRD(crd.Spec.MySQLCluster),
-		Postgres:        s.convertPostgresConfigFromCRD(crd.Spec.Postgres),
-		PostgresCluster: s.convertPostgresClusterConfigFromCRD(crd.Spec.PostgresCluster),
-		Status:          crd.Status.Phase,
-		CreatedAt:       crd.CreationTimestamp.Time,
-		UpdatedAt:       crd.CreationTimestamp.Time, // Would need to track updates
+		UUID:              labels[validation.LabelResourceUUID],
+		Name:              annotations[validation.AnnotationResourceName],
+		Slug:              labels[validation.LabelResourceSlug],
+		ProjectUUID:       labels[validation.LabelProjectUUID],
+		ProjectSlug:       projectSlug,
+		EnvironmentUUID:   labels[validation.LabelEnvironmentUUID],
+		Type:              s.convertApplicationTypeFromCRD(crd.Spec.Type),
+		BaseDomain:        crd.Spec.BaseDomain,
+		DependsOn:         dependencyUUIDs(crd.Spec.DependsOn),
+		PlatformEnv:       crd.PlatformEnvEnabled(),
+		GitRepository:     s.convertGitRepositoryConfigFromCRD(crd.Spec.GitRepository),
+		DockerImage:       s.convertDockerImageConfigFromCRD(crd.Spec.DockerImage),
+		MySQL:             s.convertMySQLConfigFromCRD(crd.Spec.MySQL),
+		MySQLCluster:      s.convertMySQLClusterConfigFromCRD(crd.Spec.MySQLCluster),
+		Postgres:          s.convertPostgresConfigFromCRD(crd.Spec.Postgres),
+		PostgresCluster:   s.convertPostgresClusterConfigFromCRD(crd.Spec.PostgresCluster),
+		Status:            crd.Status.Phase,
+		Degraded:          models.ApplicationDegradationFromConditions(crd.Status.Conditions),
+		InternalEndpoints: internalEndpoints(crd),
+		CreatedAt:         crd.CreationTimestamp.Time,
+		UpdatedAt:         crd.CreationTimestamp.Time, // Would need to track updates
+	}
+}
+
+// internalEndpoints returns the endpoint the other applications of the environment reach an
+// application through, the service discovery controller maintains it for web applications
+func internalEndpoints(crd *v1alpha1.Application) []models.InternalEndpoint {
+	web := crd.Spec.Type == v1alpha1.ApplicationTypeGitRepository ||
+		crd.Spec.Type == v1alpha1.ApplicationTypeDockerImage ||
+		crd.Spec.Type == v1alpha1.ApplicationTypeImageFromRegistry
+	slug := crd.GetSlug()
+	if !web || slug == "" {
+		return nil
+	}
+
+	port := crd.Spec.Port
+	if port == 0 {
+		port = 3000
+	}
+	prefix := utils.GetServiceDiscoveryEnvPrefix(slug)
+	return []models.InternalEndpoint{{
+		Host:    utils.GetServiceAliasHost(slug, crd.Namespace),
+		Port:    port,
+		HostEnv: prefix + "_HOST",
+		PortEnv: prefix + "_PORT",
+	}}
+}
+
+// platformEnvSpec converts the platform env setting of an application to its CRD field, which is
+// only set to opt out
+func platformEnvSpec(enabled bool) *bool {
+	if enabled {
+		return nil
 	}
+	return &enabled
 }
 
 // applyApplicationUpdates applies patch updates to the existing CRD
@@ -673,9 +812,29 @@ func (s *ApplicationService) applyApplicationUpdates(crd *v1alpha1.Application,
 		crd.SetAnnotations(annotations)
 	}
 
+	// Update base domain; only domains generated afterwards use it
+	if req.BaseDomain != nil {
+		crd.Spec.BaseDomain = *req.BaseDomain
+	}
+
+	// Update dependencies; builds started afterwards wait for them
+	if req.DependsOn != nil {
+		crd.Spec.DependsOn = dependencyRefs(*req.DependsOn)
+	}
+
+	// Update the deployment context variables; deployments created afterwards get them
+	if req.PlatformEnv != nil {
+		crd.Spec.PlatformEnv = platformEnvSpec(*req.PlatformEnv)
+	}
+
 	// Update type-specific configurations
 	if req.GitRepository != nil {
-		crd.Spec.GitRepository = s.convertGitRepositoryConfig(req.GitRepository)
+		gitConfig := s.convertGitRepositoryConfig(req.GitRepository)
+		// Build env vars are managed through their own endpoint
+		if crd.Spec.GitRepository != nil {
+			gitConfig.BuildEnvSecretRef = crd.Spec.GitRepository.BuildEnvSecretRef
+		}
+		crd.Spec.GitRepository = gitConfig
 	}
 	if req.DockerImage != nil {
 		crd.Spec.DockerImage = s.convertDockerImageConfig(req.DockerImage)
@@ -775,9 +934,16 @@ func (s *ApplicationService) convertDockerfileBuildConfig(config *models.Dockerf
 		return nil
 	}
 
+	var buildSecrets *corev1.LocalObjectReference
+	if config.BuildSecrets != nil {
+		buildSecrets = &corev1.LocalObjectReference{Name: *config.BuildSecrets}
+	}
+
 	return &v1alpha1.DockerfileBuildConfig{
 		DockerfilePath: config.DockerfilePath,
 		BuildContext:   config.BuildContext,
+		BuildArgs:      config.BuildArgs,
+		BuildSecrets:   buildSecrets,
 	}
 }
 
@@ -786,9 +952,36 @@ func (s *ApplicationService) convertDockerfileBuildConfigFromCRD(config *v1alpha
 		return nil
 	}
 
+	var buildSecrets *string
+	if config.BuildSecrets != nil {
+		buildSecrets = &config.BuildSecrets.Name
+	}
+
 	return &models.DockerfileBuildConfig{
 		DockerfilePath: config.DockerfilePath,
 		BuildContext:   config.BuildContext,
+		BuildArgs:      config.BuildArgs,
+		BuildSecrets:   buildSecrets,
+	}
+}
+
+func (s *ApplicationService) convertBuildpacksBuildConfig(config *models.BuildpacksBuildConfig) *v1alpha1.BuildpacksBuildConfig {
+	if config == nil {
+		return nil
+	}
+
+	return &v1alpha1.BuildpacksBuildConfig{
+		BuilderImage: config.BuilderImage,
+	}
+}
+
+func (s *ApplicationService) convertBuildpacksBuildConfigFromCRD(config *v1alpha1.BuildpacksBuildConfig) *models.BuildpacksBuildConfig {
+	if config == nil {
+		return nil
+	}
+
+	return &models.BuildpacksBuildConfig{
+		BuilderImage: config.BuilderImage,
 	}
 }
 
@@ -803,23 +996,42 @@ func (s *ApplicationService) convertGitRepositoryConfig(config *models.GitReposi
 	}
 
 	return &v1alpha1.GitRepositoryConfig{
-		Provider:           v1alpha1.GitProvider(config.Provider),
-		Repository:         config.Repository,
-		PublicAccess:       config.PublicAccess,
-		SecretRef:          secretRef,
-		Branch:             config.Branch,
-		Path:               config.Path,
-		RootDirectory:      config.RootDirectory,
-		BuildCommand:       config.BuildCommand,
-		StartCommand:       config.StartCommand,
-		SpaOutputDirectory: config.SpaOutputDirectory,
-		BuildType:          v1alpha1.BuildType(config.BuildType),
-		DockerfileBuild:    s.convertDockerfileBuildConfig(config.DockerfileBuild),
-		HealthCheck:        s.convertHealthCheckConfig(config.HealthCheck),
+		Provider:            v1alpha1.GitProvider(config.Provider),
+		Repository:          config.Repository,
+		PublicAccess:        config.PublicAccess,
+		SecretRef:           secretRef,
+		Branch:              config.Branch,
+		Path:                config.Path,
+		RootDirectory:       config.RootDirectory,
+		GitSubmodules:       config.GitSubmodules,
+		GitLFS:              config.GitLFS,
+		CloneDepth:          config.CloneDepth,
+		SparseCheckoutPaths: config.SparseCheckoutPaths,
+		BuildCommand:        config.BuildCommand,
+		StartCommand:        config.StartCommand,
+		SpaOutputDirectory:  config.SpaOutputDirectory,
+		BuildType:           v1alpha1.BuildType(config.BuildType),
+		DockerfileBuild:     s.convertDockerfileBuildConfig(config.DockerfileBuild),
+		BuildpacksBuild:     s.convertBuildpacksBuildConfig(config.BuildpacksBuild),
+		HealthCheck:         s.convertHealthCheckConfig(config.HealthCheck),
+		Steps:               s.convertPipelineSteps(config.Steps),
+		BuildScheduling:     config.BuildScheduling.ToCRD(),
 		// Env is automatically set by the application controller
 	}
 }
 
+func (s *ApplicationService) convertPipelineSteps(steps []models.PipelineStep) []v1alpha1.PipelineStep {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	out := make([]v1alpha1.PipelineStep, 0, len(steps))
+	for _, step := range steps {
+		out = append(out, v1alpha1.PipelineStep{Name: step.Name, Image: step.Image, Script: step.Script})
+	}
+	return out
+}
+
 func (s *ApplicationService) convertGitRepositoryConfigFromCRD(config *v1alpha1.GitRepositoryConfig) *models.GitRepositoryConfig {
 	if config == nil {
 		return nil
@@ -831,23 +1043,42 @@ func (s *ApplicationService) convertGitRepositoryConfigFromCRD(config *v1alpha1.
 	}
 
 	return &models.GitRepositoryConfig{
-		Provider:           models.GitProvider(config.Provider),
-		Repository:         config.Repository,
-		PublicAccess:       config.PublicAccess,
-		SecretRef:          secretRef,
-		Branch:             config.Branch,
-		Path:               config.Path,
-		RootDirectory:      config.RootDirectory,
-		BuildCommand:       config.BuildCommand,
-		StartCommand:       config.StartCommand,
-		SpaOutputDirectory: config.SpaOutputDirectory,
-		BuildType:          models.BuildType(config.BuildType),
-		DockerfileBuild:    s.convertDockerfileBuildConfigFromCRD(config.DockerfileBuild),
-		HealthCheck:        s.convertHealthCheckConfigFromCRD(config.HealthCheck),
+		Provider:            models.GitProvider(config.Provider),
+		Repository:          config.Repository,
+		PublicAccess:        config.PublicAccess,
+		SecretRef:           secretRef,
+		Branch:              config.Branch,
+		Path:                config.Path,
+		RootDirectory:       config.RootDirectory,
+		GitSubmodules:       config.GitSubmodules,
+		GitLFS:              config.GitLFS,
+		CloneDepth:          config.CloneDepth,
+		SparseCheckoutPaths: config.SparseCheckoutPaths,
+		BuildCommand:        config.BuildCommand,
+		StartCommand:        config.StartCommand,
+		SpaOutputDirectory:  config.SpaOutputDirectory,
+		BuildType:           models.BuildType(config.BuildType),
+		DockerfileBuild:     s.convertDockerfileBuildConfigFromCRD(config.DockerfileBuild),
+		BuildpacksBuild:     s.convertBuildpacksBuildConfigFromCRD(config.BuildpacksBuild),
+		HealthCheck:         s.convertHealthCheckConfigFromCRD(config.HealthCheck),
+		Steps:               s.convertPipelineStepsFromCRD(config.Steps),
+		BuildScheduling:     models.BuildSchedulingFromCRD(config.BuildScheduling),
 		// Env is automatically managed by the application controller
 	}
 }
 
+func (s *ApplicationService) convertPipelineStepsFromCRD(steps []v1alpha1.PipelineStep) []models.PipelineStep {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	out := make([]models.PipelineStep, 0, len(steps))
+	for _, step := range steps {
+		out = append(out, models.PipelineStep{Name: step.Name, Image: step.Image, Script: step.Script})
+	}
+	return out
+}
+
 func (s *ApplicationService) convertDockerImageConfig(config *models.DockerImageConfig) *v1alpha1.DockerImageConfig {
 	if config == nil {
 		return nil
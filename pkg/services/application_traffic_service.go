@@ -0,0 +1,208 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/utils"
+)
+
+var (
+	// ErrMetricsNotConfigured is returned when traffic metrics are requested but no Prometheus is configured
+	ErrMetricsNotConfigured = errors.New("traffic metrics are not configured")
+
+	// ErrTrafficMetricsUnsupported is returned when the ingress provider exports no per-route metrics
+	ErrTrafficMetricsUnsupported = errors.New("the ingress provider does not export per-route traffic metrics")
+)
+
+// prometheusQueryTimeout bounds a single Prometheus query
+const prometheusQueryTimeout = 15 * time.Second
+
+// PrometheusClient queries ingress controller metrics from Prometheus
+type PrometheusClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewPrometheusClient creates a PrometheusClient for the Prometheus at baseURL
+func NewPrometheusClient(baseURL string) *PrometheusClient {
+	return &PrometheusClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: prometheusQueryTimeout},
+	}
+}
+
+// prometheusQueryResponse is the subset of the Prometheus instant query response used for vectors
+type prometheusQueryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Value  [2]any            `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// Query runs a PromQL instant query evaluated at time at and returns the sum of the resulting
+// vector. found is false when the query returned no sample, or only NaN samples.
+func (p *PrometheusClient) Query(ctx context.Context, query string, at time.Time) (value float64, found bool, err error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("time", strconv.FormatInt(at.Unix(), 10))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/api/v1/query?"+params.Encode(), nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to build Prometheus request: %w", err)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to query Prometheus: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, false, fmt.Errorf("prometheus returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result prometheusQueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, false, fmt.Errorf("failed to decode Prometheus response: %w", err)
+	}
+	if result.Status != "success" {
+		return 0, false, fmt.Errorf("prometheus query failed with status %s: %s", result.Status, result.Error)
+	}
+	if result.Data.ResultType != "vector" {
+		return 0, false, fmt.Errorf("unexpected Prometheus result type %s", result.Data.ResultType)
+	}
+
+	for _, sample := range result.Data.Result {
+		raw, ok := sample.Value[1].(string)
+		if !ok {
+			return 0, false, fmt.Errorf("invalid Prometheus sample value %v", sample.Value[1])
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid Prometheus sample value %q: %w", raw, err)
+		}
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		value += v
+		found = true
+	}
+	return value, found, nil
+}
+
+// trafficQueries holds the PromQL queries reporting the traffic of an application over a window
+type trafficQueries struct {
+	requests     string
+	clientErrors string
+	serverErrors string
+	p95Latency   string
+}
+
+// applicationTrafficQueries builds the queries reading the traffic of the Service of an
+// application from the metrics of the ingress controller routing to it
+func applicationTrafficQueries(provider config.IngressProvider, namespace, serviceName, window string) (trafficQueries, error) {
+	requests, selector, ok := provider.RequestsMetric(namespace, serviceName)
+	if !ok {
+		return trafficQueries{}, ErrTrafficMetricsUnsupported
+	}
+	duration, status := "nginx_ingress_controller_request_duration_seconds_bucket", "status"
+	if provider == config.IngressProviderTraefik {
+		duration, status = "traefik_service_request_duration_seconds_bucket", "code"
+	}
+
+	rate := func(metric, extra string) string {
+		return fmt.Sprintf(`sum(rate(%s{%s%s}[%s]))`, metric, selector, extra, window)
+	}
+	return trafficQueries{
+		requests:     rate(requests, ""),
+		clientErrors: rate(requests, fmt.Sprintf(`, %s=~"4.."`, status)),
+		serverErrors: rate(requests, fmt.Sprintf(`, %s=~"5.."`, status)),
+		p95Latency: fmt.Sprintf(`histogram_quantile(0.95, sum by (le) (rate(%s{%s}[%s])))`,
+			duration, selector, window),
+	}, nil
+}
+
+// GetApplicationTraffic returns the request rate, error rates and p95 latency of an application
+// over the last hour and the last day, read from the ingress controller metrics
+func (s *ApplicationService) GetApplicationTraffic(ctx context.Context, uuid string) (*models.ApplicationTrafficResponse, error) {
+	if s.metrics == nil {
+		return nil, ErrMetricsNotConfigured
+	}
+
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	response := &models.ApplicationTrafficResponse{
+		ApplicationUUID: crd.GetUUID(),
+		IngressProvider: string(s.ingressProvider),
+		Windows:         []models.ApplicationTrafficWindow{},
+	}
+	for _, window := range models.TrafficWindows {
+		queries, err := applicationTrafficQueries(s.ingressProvider, crd.Namespace, utils.GetServiceName(crd.GetUUID()), window)
+		if err != nil {
+			return nil, err
+		}
+
+		requests, _, err := s.metrics.Query(ctx, queries.requests, now)
+		if err != nil {
+			return nil, err
+		}
+		clientErrors, _, err := s.metrics.Query(ctx, queries.clientErrors, now)
+		if err != nil {
+			return nil, err
+		}
+		serverErrors, _, err := s.metrics.Query(ctx, queries.serverErrors, now)
+		if err != nil {
+			return nil, err
+		}
+		latency, found, err := s.metrics.Query(ctx, queries.p95Latency, now)
+		if err != nil {
+			return nil, err
+		}
+		var p95 *float64
+		if found {
+			p95 = &latency
+		}
+
+		response.Windows = append(response.Windows,
+			models.NewApplicationTrafficWindow(window, requests, clientErrors, serverErrors, p95))
+	}
+	return response, nil
+}
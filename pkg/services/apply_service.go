@@ -0,0 +1,666 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/envcrypt"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ErrApplyConflict is returned when the resources of a manifest cannot be converged, such as an
+// application changing type or a name matching several resources
+var ErrApplyConflict = errors.New("manifest conflicts with existing resources")
+
+// ApplyService converges a project tree to a declarative manifest. Environments, applications and
+// custom domains of the project that the manifest leaves out are deleted, preview environments
+// are never touched.
+type ApplyService struct {
+	client             client.Client
+	projectService     *ProjectService
+	environmentService *EnvironmentService
+	applicationService *ApplicationService
+	domainService      *ApplicationDomainService
+}
+
+// NewApplyService creates a new ApplyService
+func NewApplyService(k8sClient client.Client, projectService *ProjectService, environmentService *EnvironmentService, applicationService *ApplicationService, domainService *ApplicationDomainService) *ApplyService {
+	return &ApplyService{
+		client:             k8sClient,
+		projectService:     projectService,
+		environmentService: environmentService,
+		applicationService: applicationService,
+		domainService:      domainService,
+	}
+}
+
+// applyRun collects the change plan of a manifest, making every change as it is planned unless
+// dryRun is set. A dry run plans the children of resources it would create as created.
+type applyRun struct {
+	*ApplyService
+	dryRun   bool
+	response *models.ApplyResponse
+}
+
+// record adds a change to the plan and reports whether it should be made
+func (r *applyRun) record(change models.ApplyChange) bool {
+	r.response.Changes = append(r.response.Changes, change)
+	return !r.dryRun
+}
+
+// Apply diffs the manifest against the project it names and creates, updates and deletes
+// resources until they match. The changes made before an error are returned with it.
+func (s *ApplyService) Apply(ctx context.Context, req *models.ApplyRequest, dryRun bool) (*models.ApplyResponse, error) {
+	run := &applyRun{
+		ApplyService: s,
+		dryRun:       dryRun,
+		response:     &models.ApplyResponse{DryRun: dryRun, Changes: []models.ApplyChange{}},
+	}
+
+	project, err := run.applyProject(ctx, &req.Project)
+	if err != nil {
+		return run.response, err
+	}
+	if project != nil {
+		run.response.ProjectUUID = project.UUID
+	}
+
+	if err := run.applyEnvironments(ctx, project, req.Environments); err != nil {
+		return run.response, err
+	}
+	return run.response, nil
+}
+
+// applyProject finds the project of the manifest by name within its workspace, creating or
+// updating it. The project is nil when a dry run would create it.
+func (r *applyRun) applyProject(ctx context.Context, desired *models.ApplyProject) (*models.Project, error) {
+	var projectList v1alpha1.ProjectList
+	if err := r.client.List(ctx, &projectList, client.MatchingLabels{
+		validation.LabelWorkspaceUUID: desired.WorkspaceUUID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	var current *models.Project
+	for i := range projectList.Items {
+		project := r.projectService.convertFromProjectCRD(&projectList.Items[i])
+		if project.Name != desired.Name || projectList.Items[i].DeletionTimestamp != nil {
+			continue
+		}
+		if current != nil {
+			return nil, fmt.Errorf("%w: several projects are named %s in workspace %s", ErrApplyConflict, desired.Name, desired.WorkspaceUUID)
+		}
+		current = project
+	}
+
+	if current == nil {
+		if !r.record(models.ApplyChange{Action: models.ApplyActionCreate, Kind: models.ApplyKindProject, Path: desired.Name}) {
+			return nil, nil
+		}
+		project, err := r.projectService.CreateProject(ctx, desired.CreateRequest())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create project %s: %w", desired.Name, err)
+		}
+		r.response.Changes[len(r.response.Changes)-1].UUID = project.UUID
+		return project, nil
+	}
+
+	update := &models.ProjectUpdateRequest{}
+	var fields []string
+	if current.Description != desired.Description {
+		update.Description = &desired.Description
+		fields = append(fields, "description")
+	}
+	if current.BaseDomain != desired.BaseDomain {
+		update.BaseDomain = &desired.BaseDomain
+		fields = append(fields, "baseDomain")
+	}
+	if len(fields) == 0 {
+		return current, nil
+	}
+	if r.record(models.ApplyChange{Action: models.ApplyActionUpdate, Kind: models.ApplyKindProject, Path: desired.Name, UUID: current.UUID, Fields: fields}) {
+		if _, err := r.projectService.UpdateProject(ctx, current.UUID, update); err != nil {
+			return nil, fmt.Errorf("failed to update project %s: %w", desired.Name, err)
+		}
+	}
+	return current, nil
+}
+
+// applyEnvironments converges the environments of a project, matched by name
+func (r *applyRun) applyEnvironments(ctx context.Context, project *models.Project, desired []models.ApplyEnvironment) error {
+	existing := make(map[string]*models.Environment)
+	if project != nil {
+		var environmentList v1alpha1.EnvironmentList
+		if err := r.client.List(ctx, &environmentList, client.MatchingLabels{
+			validation.LabelProjectUUID: project.UUID,
+		}); err != nil {
+			return fmt.Errorf("failed to list environments: %w", err)
+		}
+		for i := range environmentList.Items {
+			crd := &environmentList.Items[i]
+			if crd.Labels[validation.LabelPullRequest] != "" || crd.DeletionTimestamp != nil {
+				continue
+			}
+			environment := r.environmentService.convertFromEnvironmentCRD(crd)
+			if _, ok := existing[environment.Name]; ok {
+				return fmt.Errorf("%w: several environments are named %s", ErrApplyConflict, environment.Name)
+			}
+			existing[environment.Name] = environment
+		}
+	}
+
+	for i := range desired {
+		environment, err := r.applyEnvironment(ctx, project, existing[desired[i].Name], &desired[i])
+		if err != nil {
+			return err
+		}
+		if err := r.applyApplications(ctx, environment, &desired[i]); err != nil {
+			return err
+		}
+		delete(existing, desired[i].Name)
+	}
+
+	for _, name := range sortedKeys(existing) {
+		environment := existing[name]
+		if r.record(models.ApplyChange{Action: models.ApplyActionDelete, Kind: models.ApplyKindEnvironment, Path: name, UUID: environment.UUID}) {
+			if err := r.environmentService.DeleteEnvironment(ctx, environment.UUID); err != nil {
+				return fmt.Errorf("failed to delete environment %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// applyEnvironment creates or updates an environment. The environment is nil when a dry run
+// would create it.
+func (r *applyRun) applyEnvironment(ctx context.Context, project *models.Project, current *models.Environment, desired *models.ApplyEnvironment) (*models.Environment, error) {
+	if current == nil {
+		if !r.record(models.ApplyChange{Action: models.ApplyActionCreate, Kind: models.ApplyKindEnvironment, Path: desired.Name}) {
+			return nil, nil
+		}
+		environment, err := r.environmentService.CreateEnvironment(ctx, &models.EnvironmentCreateRequest{
+			Name:        desired.Name,
+			Description: desired.Description,
+			Variables:   desired.Variables,
+			ProjectUUID: project.UUID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create environment %s: %w", desired.Name, err)
+		}
+		r.response.Changes[len(r.response.Changes)-1].UUID = environment.UUID
+		return environment, nil
+	}
+
+	update := &models.EnvironmentUpdateRequest{}
+	var fields []string
+	if current.Description != desired.Description {
+		update.Description = &desired.Description
+		fields = append(fields, "description")
+	}
+	if desired.Variables != nil && !reflect.DeepEqual(nonNilMap(current.Variables), desired.Variables) {
+		update.Variables = &desired.Variables
+		fields = append(fields, "variables")
+	}
+	if len(fields) == 0 {
+		return current, nil
+	}
+	if r.record(models.ApplyChange{Action: models.ApplyActionUpdate, Kind: models.ApplyKindEnvironment, Path: desired.Name, UUID: current.UUID, Fields: fields}) {
+		if _, err := r.environmentService.UpdateEnvironment(ctx, current.UUID, update); err != nil {
+			return nil, fmt.Errorf("failed to update environment %s: %w", desired.Name, err)
+		}
+	}
+	return current, nil
+}
+
+// applyApplications converges the applications of an environment, matched by name. Dependencies
+// are converged before their dependents, so every dependency exists when it is referenced.
+func (r *applyRun) applyApplications(ctx context.Context, environment *models.Environment, desired *models.ApplyEnvironment) error {
+	existing := make(map[string]*v1alpha1.Application)
+	if environment != nil {
+		var applicationList v1alpha1.ApplicationList
+		if err := r.client.List(ctx, &applicationList, client.MatchingLabels{
+			validation.LabelEnvironmentUUID: environment.UUID,
+		}); err != nil {
+			return fmt.Errorf("failed to list applications: %w", err)
+		}
+		for i := range applicationList.Items {
+			crd := &applicationList.Items[i]
+			if crd.DeletionTimestamp != nil {
+				continue
+			}
+			name := crd.Annotations[validation.AnnotationResourceName]
+			if _, ok := existing[name]; ok {
+				return fmt.Errorf("%w: several applications of environment %s are named %s", ErrApplyConflict, desired.Name, name)
+			}
+			existing[name] = crd
+		}
+	}
+
+	byName := make(map[string]*models.ApplyApplication, len(desired.Applications))
+	graph := make(map[string][]string, len(desired.Applications))
+	uuids := make(map[string]string, len(existing))
+	for i := range desired.Applications {
+		application := &desired.Applications[i]
+		byName[application.Name] = application
+		graph[application.Name] = application.DependsOn
+		if crd, ok := existing[application.Name]; ok {
+			if current := models.ApplicationType(crd.Spec.Type); current != application.Type {
+				return fmt.Errorf("%w: application %s/%s is a %s application, it cannot become %s",
+					ErrApplyConflict, desired.Name, application.Name, current, application.Type)
+			}
+			uuids[application.Name] = crd.Labels[validation.LabelResourceUUID]
+		}
+	}
+
+	order, cycle := models.DependencyOrder(graph)
+	if cycle != nil {
+		return fmt.Errorf("%w: dependency cycle in environment %s", ErrApplyConflict, desired.Name)
+	}
+	for _, name := range order {
+		application := byName[name]
+		dependsOn := make([]string, 0, len(application.DependsOn))
+		for _, dependency := range application.DependsOn {
+			dependsOn = append(dependsOn, uuids[dependency])
+		}
+
+		uuid, err := r.applyApplication(ctx, environment, existing[name], desired.Name, application, dependsOn)
+		if err != nil {
+			return err
+		}
+		uuids[name] = uuid
+		delete(existing, name)
+	}
+
+	for _, name := range sortedKeys(existing) {
+		uuid := existing[name].Labels[validation.LabelResourceUUID]
+		if r.record(models.ApplyChange{Action: models.ApplyActionDelete, Kind: models.ApplyKindApplication, Path: desired.Name + "/" + name, UUID: uuid}) {
+			if err := r.applicationService.DeleteApplication(ctx, uuid); err != nil {
+				return fmt.Errorf("failed to delete application %s/%s: %w", desired.Name, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// applyApplication creates or updates an application with its environment variables and custom
+// domains, and returns its UUID. The UUID is empty when a dry run would create the application.
+func (r *applyRun) applyApplication(ctx context.Context, environment *models.Environment, current *v1alpha1.Application, environmentName string, desired *models.ApplyApplication, dependsOn []string) (string, error) {
+	path := environmentName + "/" + desired.Name
+
+	if current == nil {
+		if !r.record(models.ApplyChange{Action: models.ApplyActionCreate, Kind: models.ApplyKindApplication, Path: path}) {
+			if len(desired.Env) > 0 {
+				r.record(models.ApplyChange{Action: models.ApplyActionCreate, Kind: models.ApplyKindEnvironmentVariable, Path: path, Fields: sortedKeys(desired.Env)})
+			}
+			for _, domain := range desired.Domains {
+				r.record(models.ApplyChange{Action: models.ApplyActionCreate, Kind: models.ApplyKindApplicationDomain, Path: path + "/" + domain.Domain})
+			}
+			return "", nil
+		}
+		application, crd, err := r.applicationService.createApplication(ctx, desired.CreateRequest(environment.UUID, dependsOn), nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to create application %s: %w", path, err)
+		}
+		r.response.Changes[len(r.response.Changes)-1].UUID = application.UUID
+		if err := r.applyEnv(ctx, path, crd, desired.Env, models.ApplyActionCreate); err != nil {
+			return application.UUID, err
+		}
+		return application.UUID, r.applyDomains(ctx, path, application, desired.Domains)
+	}
+
+	application := r.applicationService.convertFromApplicationCRD(current)
+	update, fields, err := applicationUpdate(application, desired, dependsOn)
+	if err != nil {
+		return application.UUID, err
+	}
+	if len(fields) > 0 {
+		if r.record(models.ApplyChange{Action: models.ApplyActionUpdate, Kind: models.ApplyKindApplication, Path: path, UUID: application.UUID, Fields: fields}) {
+			if _, err := r.applicationService.UpdateApplication(ctx, application.UUID, update); err != nil {
+				return application.UUID, fmt.Errorf("failed to update application %s: %w", path, err)
+			}
+		}
+	}
+	if err := r.applyEnv(ctx, path, current, desired.Env, models.ApplyActionUpdate); err != nil {
+		return application.UUID, err
+	}
+	return application.UUID, r.applyDomains(ctx, path, application, desired.Domains)
+}
+
+// applicationUpdate returns the update converging an application and the fields it changes.
+// Configuration fields the manifest leaves out keep the values the platform defaulted.
+func applicationUpdate(current *models.Application, desired *models.ApplyApplication, dependsOn []string) (*models.ApplicationUpdateRequest, []string, error) {
+	update := &models.ApplicationUpdateRequest{}
+	var fields []string
+
+	if current.BaseDomain != desired.BaseDomain {
+		update.BaseDomain = &desired.BaseDomain
+		fields = append(fields, "baseDomain")
+	}
+
+	currentDependsOn := append([]string(nil), current.DependsOn...)
+	desiredDependsOn := append([]string(nil), dependsOn...)
+	sort.Strings(currentDependsOn)
+	sort.Strings(desiredDependsOn)
+	if !reflect.DeepEqual(nonNilSlice(currentDependsOn), nonNilSlice(desiredDependsOn)) {
+		update.DependsOn = &dependsOn
+		fields = append(fields, "dependsOn")
+	}
+
+	configs := []struct {
+		field            string
+		desired, current interface{}
+		set              func()
+	}{
+		{"gitRepository", desired.GitRepository, current.GitRepository, func() { update.GitRepository = desired.GitRepository }},
+		{"dockerImage", desired.DockerImage, current.DockerImage, func() { update.DockerImage = desired.DockerImage }},
+		{"imageFromRegistry", desired.ImageFromRegistry, current.ImageFromRegistry, func() { update.ImageFromRegistry = desired.ImageFromRegistry }},
+		{"mysql", desired.MySQL, current.MySQL, func() { update.MySQL = desired.MySQL }},
+		{"mysqlCluster", desired.MySQLCluster, current.MySQLCluster, func() { update.MySQLCluster = desired.MySQLCluster }},
+		{"postgres", desired.Postgres, current.Postgres, func() { update.Postgres = desired.Postgres }},
+		{"postgresCluster", desired.PostgresCluster, current.PostgresCluster, func() { update.PostgresCluster = desired.PostgresCluster }},
+		{"valkey", desired.Valkey, current.Valkey, func() { update.Valkey = desired.Valkey }},
+		{"valkeyCluster", desired.ValkeyCluster, current.ValkeyCluster, func() { update.ValkeyCluster = desired.ValkeyCluster }},
+	}
+	for _, config := range configs {
+		if reflect.ValueOf(config.desired).IsNil() {
+			continue
+		}
+		differs, err := configDiffers(config.desired, config.current)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to compare %s of application %s: %w", config.field, desired.Name, err)
+		}
+		if differs {
+			config.set()
+			fields = append(fields, config.field)
+		}
+	}
+
+	return update, fields, nil
+}
+
+// configDiffers reports whether desired sets a field of an application configuration to another
+// value than current
+func configDiffers(desired, current interface{}) (bool, error) {
+	desiredFields, err := jsonValue(desired)
+	if err != nil {
+		return false, err
+	}
+	currentFields, err := jsonValue(current)
+	if err != nil {
+		return false, err
+	}
+	return !jsonSubset(desiredFields, currentFields), nil
+}
+
+// jsonValue returns the JSON representation of v as maps, slices and scalars
+func jsonValue(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// jsonSubset reports whether every field of desired has the same value in current. Lists are
+// compared as a whole.
+func jsonSubset(desired, current interface{}) bool {
+	desiredFields, ok := desired.(map[string]interface{})
+	if !ok {
+		return reflect.DeepEqual(desired, current)
+	}
+	currentFields, ok := current.(map[string]interface{})
+	if !ok {
+		return len(desiredFields) == 0
+	}
+	for key, value := range desiredFields {
+		if !jsonSubset(value, currentFields[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyEnv replaces the environment variables of an application when they differ from env.
+// Variables are left alone when env is nil. The Secret is created under the name the operator
+// expects when the operator has not created it yet. app is nil when a dry run would create it.
+func (r *applyRun) applyEnv(ctx context.Context, path string, app *v1alpha1.Application, env map[string]string, action models.ApplyAction) error {
+	if env == nil {
+		return nil
+	}
+
+	var secret corev1.Secret
+	current := map[string]string{}
+	exists := false
+	if app != nil {
+		err := r.client.Get(ctx, client.ObjectKey{
+			Name:      utils.GetApplicationResourceName(app.Labels[validation.LabelResourceUUID]),
+			Namespace: app.Namespace,
+		}, &secret)
+		switch {
+		case err == nil:
+			exists = true
+			if current, err = r.decryptEnv(ctx, secret.Data); err != nil {
+				return fmt.Errorf("failed to read environment variables of application %s: %w", path, err)
+			}
+		case !apierrors.IsNotFound(err):
+			return fmt.Errorf("failed to get environment variables of application %s: %w", path, err)
+		}
+	}
+
+	var changed []string
+	for key, value := range env {
+		if currentValue, ok := current[key]; !ok || currentValue != value {
+			changed = append(changed, key)
+		}
+	}
+	for key := range current {
+		if _, ok := env[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	sort.Strings(changed)
+
+	change := models.ApplyChange{Action: action, Kind: models.ApplyKindEnvironmentVariable, Path: path, Fields: changed}
+	if app != nil {
+		change.UUID = app.Labels[validation.LabelResourceUUID]
+	}
+	if !r.record(change) {
+		return nil
+	}
+
+	data := make(map[string][]byte, len(env))
+	for key, value := range env {
+		data[key] = []byte(value)
+	}
+	if r.applicationService.encryptor != nil {
+		encrypted, err := r.applicationService.encryptor.EncryptData(ctx, data)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt environment variables: %w", err)
+		}
+		data = encrypted
+	}
+
+	if !exists {
+		created, err := r.applicationService.newApplicationEnvSecret(app)
+		if err != nil {
+			return err
+		}
+		secret = *created
+	}
+	secret.Data = data
+	if r.applicationService.encryptor != nil {
+		if secret.Annotations == nil {
+			secret.Annotations = make(map[string]string)
+		}
+		secret.Annotations[envcrypt.AnnotationProvider] = r.applicationService.encryptor.ProviderName()
+	}
+
+	if exists {
+		if err := r.client.Update(ctx, &secret); err != nil {
+			return fmt.Errorf("failed to update environment variables of application %s: %w", path, err)
+		}
+		return nil
+	}
+	if err := r.client.Create(ctx, &secret); err != nil {
+		return fmt.Errorf("failed to create environment variables of application %s: %w", path, err)
+	}
+	return nil
+}
+
+// decryptEnv returns the plaintext values of an environment variables Secret
+func (r *applyRun) decryptEnv(ctx context.Context, data map[string][]byte) (map[string]string, error) {
+	if r.applicationService.encryptor != nil {
+		decrypted, err := r.applicationService.encryptor.DecryptData(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+		data = decrypted
+	}
+
+	env := make(map[string]string, len(data))
+	for key, value := range data {
+		if envcrypt.IsEncrypted(value) {
+			return nil, fmt.Errorf("%s is encrypted and no encryption provider is configured", key)
+		}
+		env[key] = string(value)
+	}
+	return env, nil
+}
+
+// applyDomains converges the custom domains of an application, matched by host. Domains are
+// left alone when desired is nil.
+func (r *applyRun) applyDomains(ctx context.Context, path string, application *models.Application, desired []models.ApplyDomain) error {
+	if desired == nil {
+		return nil
+	}
+
+	domains, err := r.domainService.GetApplicationDomainsByApplicationUUIDNoValidate(ctx, application.UUID, application.Slug)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*models.ApplicationDomain, len(domains))
+	for _, domain := range domains {
+		if domain.Type == models.ApplicationDomainTypeCustom {
+			existing[domain.Domain] = domain
+		}
+	}
+
+	for _, domain := range desired {
+		domainPath := path + "/" + domain.Domain
+		current, ok := existing[domain.Domain]
+		delete(existing, domain.Domain)
+
+		change := models.ApplyChange{Action: models.ApplyActionCreate, Kind: models.ApplyKindApplicationDomain, Path: domainPath}
+		if ok {
+			if current.Port != domain.Port {
+				change.Fields = append(change.Fields, "port")
+			}
+			if current.TLSEnabled != domain.TLSEnabled {
+				change.Fields = append(change.Fields, "tlsEnabled")
+			}
+			if len(change.Fields) == 0 {
+				continue
+			}
+			change.Action = models.ApplyActionUpdate
+			change.UUID = current.UUID
+		}
+		if !r.record(change) {
+			continue
+		}
+
+		if ok {
+			crd, err := r.domainService.getApplicationDomainCRD(ctx, current.UUID)
+			if err != nil {
+				return err
+			}
+			if _, err := r.domainService.updateApplicationDomainSpec(ctx, crd, func(spec *v1alpha1.ApplicationDomainSpec) {
+				spec.Port = domain.Port
+				spec.TLSEnabled = domain.TLSEnabled
+			}); err != nil {
+				return fmt.Errorf("failed to update domain %s: %w", domainPath, err)
+			}
+			continue
+		}
+		created, err := r.domainService.CreateApplicationDomain(ctx, &models.ApplicationDomainCreateRequest{
+			ApplicationSlug: application.UUID,
+			Domain:          domain.Domain,
+			Port:            domain.Port,
+			Type:            models.ApplicationDomainTypeCustom,
+			TLSEnabled:      domain.TLSEnabled,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create domain %s: %w", domainPath, err)
+		}
+		r.response.Changes[len(r.response.Changes)-1].UUID = created.UUID
+	}
+
+	for _, host := range sortedKeys(existing) {
+		domain := existing[host]
+		if r.record(models.ApplyChange{Action: models.ApplyActionDelete, Kind: models.ApplyKindApplicationDomain, Path: path + "/" + host, UUID: domain.UUID}) {
+			if err := r.domainService.DeleteApplicationDomain(ctx, domain.UUID); err != nil {
+				return fmt.Errorf("failed to delete domain %s/%s: %w", path, host, err)
+			}
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// nonNilMap returns m, or an empty map when m is nil
+func nonNilMap(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
+
+// nonNilSlice returns s, or an empty slice when s is nil
+func nonNilSlice(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+// ErrAutoscalingNotSupported is returned when an application does not run deployments the
+// operator scales, or is scaled by a replica schedule
+var ErrAutoscalingNotSupported = errors.New("autoscaling is not supported")
+
+// GetAutoscaling returns the autoscaling configuration of an application and its current scale
+func (s *ApplicationService) GetAutoscaling(ctx context.Context, uuid string) (*models.AutoscalingResponse, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewAutoscalingResponse(crd), nil
+}
+
+// UpdateAutoscaling replaces the autoscaling configuration of an application. The operator scales
+// its current deployment through a KEDA ScaledObject.
+func (s *ApplicationService) UpdateAutoscaling(ctx context.Context, uuid string, req *models.AutoscalingUpdateRequest) (*models.AutoscalingResponse, error) {
+	crd, err := s.setAutoscaling(ctx, uuid, req.ToCRD())
+	if err != nil {
+		return nil, err
+	}
+	return models.NewAutoscalingResponse(crd), nil
+}
+
+// DeleteAutoscaling removes the autoscaling configuration of an application.
+// Its deployments keep their current replica count.
+func (s *ApplicationService) DeleteAutoscaling(ctx context.Context, uuid string) error {
+	_, err := s.setAutoscaling(ctx, uuid, nil)
+	return err
+}
+
+// setAutoscaling sets the autoscaling configuration of an Application CRD with a simple conflict retry loop
+func (s *ApplicationService) setAutoscaling(ctx context.Context, uuid string, autoscaling *v1alpha1.Autoscaling) (*v1alpha1.Application, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < 3; i++ {
+		if autoscaling != nil {
+			switch crd.Spec.Type {
+			case v1alpha1.ApplicationTypeGitRepository, v1alpha1.ApplicationTypeDockerImage, v1alpha1.ApplicationTypeImageFromRegistry:
+			default:
+				return nil, fmt.Errorf("%w: %s applications are not scaled by the operator", ErrAutoscalingNotSupported, crd.Spec.Type)
+			}
+			if crd.Spec.ReplicaSchedule != nil {
+				return nil, fmt.Errorf("%w: remove the replica schedule of the application first", ErrAutoscalingNotSupported)
+			}
+		}
+
+		crd.Spec.Autoscaling = autoscaling
+		if err = s.client.Update(ctx, crd); err == nil || !apierrors.IsConflict(err) {
+			break
+		}
+		var latest v1alpha1.Application
+		if getErr := s.client.Get(ctx, client.ObjectKey{Namespace: crd.Namespace, Name: crd.Name}, &latest); getErr != nil {
+			return nil, fmt.Errorf("failed to refetch Application for conflict resolution: %w", getErr)
+		}
+		crd = &latest
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update Application CRD: %w", err)
+	}
+	return crd, nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ErrBuildEnvNotSupported is returned when the builds of an application cannot receive build
+// environment variables
+var ErrBuildEnvNotSupported = errors.New("build environment variables are not supported")
+
+// GetBuildEnv returns the names of the build environment variables of an application
+func (s *ApplicationService) GetBuildEnv(ctx context.Context, uuid string) (*models.BuildEnvResponse, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := s.getBuildEnvSecret(ctx, crd)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewBuildEnvResponse(crd, secret), nil
+}
+
+// UpdateBuildEnv replaces the build environment variables of an application, an empty request
+// removes them. The values are written to an operator managed secret owned by the application,
+// the Application only references it. Deployments created afterwards build with the new variables.
+func (s *ApplicationService) UpdateBuildEnv(ctx context.Context, uuid string, req *models.BuildEnvUpdateRequest) (*models.BuildEnvResponse, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	gitConfig := crd.Spec.GitRepository
+	if crd.Spec.Type != v1alpha1.ApplicationTypeGitRepository || gitConfig == nil {
+		return nil, fmt.Errorf("%w: %s applications are not built", ErrBuildEnvNotSupported, crd.Spec.Type)
+	}
+	if gitConfig.BuildType == v1alpha1.BuildTypeNixpacks && len(req.Variables) > 0 {
+		return nil, fmt.Errorf("%w: Nixpacks builds store their environment in the image", ErrBuildEnvNotSupported)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.GetApplicationBuildEnvSecretName(crd.GetUUID()),
+			Namespace: crd.Namespace,
+		},
+	}
+	var ref *corev1.LocalObjectReference
+	if len(req.Variables) > 0 {
+		_, err = controllerutil.CreateOrUpdate(ctx, s.client, secret, func() error {
+			secret.Labels = map[string]string{
+				"app.kubernetes.io/managed-by":        "kibaship",
+				"platform.operator.kibaship.com/type": "application-build-env",
+				validation.LabelApplicationUUID:       crd.GetUUID(),
+				validation.LabelProjectUUID:           crd.GetLabels()[validation.LabelProjectUUID],
+			}
+			secret.Type = corev1.SecretTypeOpaque
+			secret.Data = make(map[string][]byte, len(req.Variables))
+			for name, value := range req.Variables {
+				secret.Data[name] = []byte(value)
+			}
+			return controllerutil.SetControllerReference(crd, secret, s.scheme)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to store build env secret: %w", err)
+		}
+		ref = &corev1.LocalObjectReference{Name: secret.Name}
+	}
+
+	for i := 0; i < 3; i++ {
+		gitConfig := crd.Spec.GitRepository
+		if gitConfig == nil {
+			return nil, fmt.Errorf("%w: %s applications are not built", ErrBuildEnvNotSupported, crd.Spec.Type)
+		}
+		if equality.Semantic.DeepEqual(gitConfig.BuildEnvSecretRef, ref) {
+			break
+		}
+		gitConfig.BuildEnvSecretRef = ref
+		if err = s.client.Update(ctx, crd); err == nil || !apierrors.IsConflict(err) {
+			break
+		}
+		var latest v1alpha1.Application
+		if getErr := s.client.Get(ctx, client.ObjectKey{Namespace: crd.Namespace, Name: crd.Name}, &latest); getErr != nil {
+			return nil, fmt.Errorf("failed to refetch Application for conflict resolution: %w", getErr)
+		}
+		crd = &latest
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update Application CRD: %w", err)
+	}
+
+	if ref == nil {
+		if err := s.client.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to delete build env secret: %w", err)
+		}
+		return models.NewBuildEnvResponse(crd, nil), nil
+	}
+	return models.NewBuildEnvResponse(crd, secret), nil
+}
+
+// getBuildEnvSecret returns the build env secret an application references, nil when it has none
+func (s *ApplicationService) getBuildEnvSecret(ctx context.Context, crd *v1alpha1.Application) (*corev1.Secret, error) {
+	if crd.Spec.GitRepository == nil || crd.Spec.GitRepository.BuildEnvSecretRef == nil {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: crd.Namespace, Name: crd.Spec.GitRepository.BuildEnvSecretRef.Name}
+	if err := s.client.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get build env secret: %w", err)
+	}
+	return secret, nil
+}
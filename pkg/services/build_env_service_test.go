@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const buildEnvAppUUID = "33333333-3333-3333-3333-333333333333"
+
+func TestUpdateBuildEnvStoresValuesInSecret(t *testing.T) {
+	ctx := context.Background()
+	scheme := newManifestTestScheme(t, false)
+	app := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-web",
+			Namespace: "project-ns",
+			Labels:    map[string]string{validation.LabelResourceUUID: buildEnvAppUUID},
+		},
+		Spec: v1alpha1.ApplicationSpec{
+			Type:          v1alpha1.ApplicationTypeGitRepository,
+			GitRepository: &v1alpha1.GitRepositoryConfig{Repository: "org/repo", BuildType: v1alpha1.BuildTypeRailpack},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).Build()
+	service := NewApplicationService(c, scheme, nil, nil)
+
+	response, err := service.UpdateBuildEnv(ctx, buildEnvAppUUID, &models.BuildEnvUpdateRequest{
+		Variables: map[string]string{"NPM_TOKEN": "npm_secret", "NODE_OPTIONS": "--max-old-space-size=4096"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateBuildEnv: %v", err)
+	}
+	if strings.Join(response.Names, ",") != "NODE_OPTIONS,NPM_TOKEN" {
+		t.Errorf("unexpected names %v", response.Names)
+	}
+
+	// The Application only references the secret holding the values
+	var updated v1alpha1.Application
+	if err := c.Get(ctx, client.ObjectKeyFromObject(app), &updated); err != nil {
+		t.Fatalf("get application: %v", err)
+	}
+	ref := updated.Spec.GitRepository.BuildEnvSecretRef
+	if ref == nil || ref.Name != "application-"+buildEnvAppUUID+"-build-env" {
+		t.Fatalf("unexpected build env secret reference %v", ref)
+	}
+	var secret corev1.Secret
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "project-ns", Name: ref.Name}, &secret); err != nil {
+		t.Fatalf("get build env secret: %v", err)
+	}
+	if string(secret.Data["NPM_TOKEN"]) != "npm_secret" || len(secret.OwnerReferences) != 1 {
+		t.Errorf("unexpected build env secret %+v", secret)
+	}
+
+	response, err = service.GetBuildEnv(ctx, buildEnvAppUUID)
+	if err != nil || len(response.Names) != 2 {
+		t.Errorf("GetBuildEnv returned %v, %v", response, err)
+	}
+
+	// An empty request removes the reference and the secret
+	if _, err := service.UpdateBuildEnv(ctx, buildEnvAppUUID, &models.BuildEnvUpdateRequest{}); err != nil {
+		t.Fatalf("UpdateBuildEnv: %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(app), &updated); err != nil {
+		t.Fatalf("get application: %v", err)
+	}
+	if updated.Spec.GitRepository.BuildEnvSecretRef != nil {
+		t.Errorf("expected the reference to be removed, got %v", updated.Spec.GitRepository.BuildEnvSecretRef)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "project-ns", Name: ref.Name}, &secret); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the build env secret to be deleted, got %v", err)
+	}
+}
+
+func TestUpdateBuildEnvRejectsNixpacks(t *testing.T) {
+	scheme := newManifestTestScheme(t, false)
+	app := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-web",
+			Namespace: "project-ns",
+			Labels:    map[string]string{validation.LabelResourceUUID: buildEnvAppUUID},
+		},
+		Spec: v1alpha1.ApplicationSpec{
+			Type:          v1alpha1.ApplicationTypeGitRepository,
+			GitRepository: &v1alpha1.GitRepositoryConfig{Repository: "org/repo", BuildType: v1alpha1.BuildTypeNixpacks},
+		},
+	}
+	service := NewApplicationService(fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).Build(), scheme, nil, nil)
+
+	_, err := service.UpdateBuildEnv(context.Background(), buildEnvAppUUID, &models.BuildEnvUpdateRequest{
+		Variables: map[string]string{"NPM_TOKEN": "npm_secret"},
+	})
+	if !errors.Is(err, ErrBuildEnvNotSupported) {
+		t.Errorf("expected ErrBuildEnvNotSupported, got %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/dbaccess"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+// maxDatabaseAccessItems mirrors the MaxItems of the databases and users of spec.databaseAccess
+const maxDatabaseAccessItems = 50
+
+var (
+	// ErrDatabaseAccessUnsupported is returned when databases or users are managed on an
+	// application that is not a MySQL or Postgres database
+	ErrDatabaseAccessUnsupported = errors.New("application does not support additional databases and users")
+
+	// ErrInvalidDatabaseAccess is returned when a user is granted an unknown database, or an
+	// application would exceed its databases or users
+	ErrInvalidDatabaseAccess = errors.New("invalid database access")
+
+	// ErrDatabaseAccessExists is returned when a database or user is created twice
+	ErrDatabaseAccessExists = errors.New("database or user already exists")
+
+	// ErrDatabaseUserNotFound is returned when an unknown database user is deleted
+	ErrDatabaseUserNotFound = errors.New("database user not found")
+)
+
+// GetDatabaseAccess returns the additional databases and users of a database application
+func (s *ApplicationService) GetDatabaseAccess(ctx context.Context, uuid string) (*models.DatabaseAccessResponse, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if conn, ok := dbaccess.ConnectionFor(crd); !ok || !conn.SupportsAccess() {
+		return nil, fmt.Errorf("%w: application %s is of type %s", ErrDatabaseAccessUnsupported, uuid, crd.Spec.Type)
+	}
+	return databaseAccessResponse(crd), nil
+}
+
+// CreateDatabase adds a database to a MySQL application, or a schema to a Postgres application.
+// The operator creates it with a Job, the response reports the Job as pending.
+func (s *ApplicationService) CreateDatabase(ctx context.Context, uuid string, req *models.DatabaseCreateRequest) (*models.DatabaseAccessResponse, error) {
+	crd, err := s.updateDatabaseAccess(ctx, uuid, func(_ dbaccess.Connection, access *v1alpha1.DatabaseAccessConfig) error {
+		for _, database := range access.Databases {
+			if database.Name == req.Name {
+				return fmt.Errorf("%w: database %s", ErrDatabaseAccessExists, req.Name)
+			}
+		}
+		if len(access.Databases) >= maxDatabaseAccessItems {
+			return fmt.Errorf("%w: an application has at most %d databases", ErrInvalidDatabaseAccess, maxDatabaseAccessItems)
+		}
+		access.Databases = append(access.Databases, v1alpha1.DatabaseSchema{Name: req.Name})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return databaseAccessResponse(crd), nil
+}
+
+// CreateDatabaseUser adds a user to a MySQL or Postgres application. Users are granted additional
+// databases, or the database of the application itself (the public schema on Postgres).
+func (s *ApplicationService) CreateDatabaseUser(ctx context.Context, uuid string, req *models.DatabaseUserCreateRequest) (*models.DatabaseAccessResponse, error) {
+	crd, err := s.updateDatabaseAccess(ctx, uuid, func(conn dbaccess.Connection, access *v1alpha1.DatabaseAccessConfig) error {
+		for _, user := range access.Users {
+			if user.Username == req.Username {
+				return fmt.Errorf("%w: user %s", ErrDatabaseAccessExists, req.Username)
+			}
+		}
+		if len(access.Users) >= maxDatabaseAccessItems {
+			return fmt.Errorf("%w: an application has at most %d users", ErrInvalidDatabaseAccess, maxDatabaseAccessItems)
+		}
+
+		known := map[string]bool{}
+		if conn.Engine == dbaccess.EnginePostgres {
+			known["public"] = true
+		} else if conn.Database != "" {
+			known[conn.Database] = true
+		}
+		for _, database := range access.Databases {
+			known[database.Name] = true
+		}
+		for _, database := range req.Databases {
+			if !known[database] {
+				return fmt.Errorf("%w: database %s does not exist", ErrInvalidDatabaseAccess, database)
+			}
+		}
+
+		access.Users = append(access.Users, v1alpha1.DatabaseUser{
+			Username:  req.Username,
+			Databases: req.Databases,
+			ReadOnly:  req.ReadOnly,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return databaseAccessResponse(crd), nil
+}
+
+// DeleteDatabaseUser removes a user from a database application. The operator drops the user and
+// its Secret; objects it owns on Postgres are handed to the administrative user.
+func (s *ApplicationService) DeleteDatabaseUser(ctx context.Context, uuid, username string) error {
+	_, err := s.updateDatabaseAccess(ctx, uuid, func(_ dbaccess.Connection, access *v1alpha1.DatabaseAccessConfig) error {
+		for i, user := range access.Users {
+			if user.Username == username {
+				access.Users = append(access.Users[:i], access.Users[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %s", ErrDatabaseUserNotFound, username)
+	})
+	return err
+}
+
+// updateDatabaseAccess applies mutate to spec.databaseAccess of a database application with a
+// simple conflict retry loop. mutate runs again on the refetched Application after a conflict.
+func (s *ApplicationService) updateDatabaseAccess(ctx context.Context, uuid string, mutate func(dbaccess.Connection, *v1alpha1.DatabaseAccessConfig) error) (*v1alpha1.Application, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	conn, ok := dbaccess.ConnectionFor(crd)
+	if !ok || !conn.SupportsAccess() {
+		return nil, fmt.Errorf("%w: application %s is of type %s", ErrDatabaseAccessUnsupported, uuid, crd.Spec.Type)
+	}
+
+	for i := 0; i < 3; i++ {
+		access := &v1alpha1.DatabaseAccessConfig{}
+		if crd.Spec.DatabaseAccess != nil {
+			access = crd.Spec.DatabaseAccess.DeepCopy()
+		}
+		if err := mutate(conn, access); err != nil {
+			return nil, err
+		}
+		crd.Spec.DatabaseAccess = access
+		if len(access.Databases) == 0 && len(access.Users) == 0 {
+			crd.Spec.DatabaseAccess = nil
+		}
+
+		if err = s.client.Update(ctx, crd); err == nil || !apierrors.IsConflict(err) {
+			break
+		}
+		var latest v1alpha1.Application
+		if getErr := s.client.Get(ctx, client.ObjectKey{Namespace: crd.Namespace, Name: crd.Name}, &latest); getErr != nil {
+			return nil, fmt.Errorf("failed to refetch Application for conflict resolution: %w", getErr)
+		}
+		crd = &latest
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update Application CRD: %w", err)
+	}
+	return crd, nil
+}
+
+// databaseAccessResponse converts the database access of an Application CRD to the API response
+func databaseAccessResponse(crd *v1alpha1.Application) *models.DatabaseAccessResponse {
+	response := &models.DatabaseAccessResponse{
+		ApplicationUUID: crd.GetUUID(),
+		Databases:       []string{},
+		Users:           []models.DatabaseUserResponse{},
+	}
+	if access := crd.Spec.DatabaseAccess; access != nil {
+		for _, database := range access.Databases {
+			response.Databases = append(response.Databases, database.Name)
+		}
+		for _, user := range access.Users {
+			response.Users = append(response.Users, models.DatabaseUserResponse{
+				Username:   user.Username,
+				Databases:  user.Databases,
+				ReadOnly:   user.ReadOnly,
+				SecretName: dbaccess.UserSecretName(crd.GetUUID(), user.Username),
+			})
+		}
+	}
+
+	status := crd.Status.DatabaseAccess
+	switch {
+	case status == nil && len(response.Databases)+len(response.Users) > 0:
+		response.Phase = string(v1alpha1.DatabaseAccessPhasePending)
+	case status != nil:
+		response.Phase = string(status.Phase)
+		response.Message = status.Message
+		if status.LastAppliedTime != nil {
+			response.LastAppliedAt = &status.LastAppliedTime.Time
+		}
+	}
+	return response
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+var (
+	// ErrArtifactStoreNotConfigured is returned when artifacts are requested but artifact collection is disabled
+	ErrArtifactStoreNotConfigured = errors.New("artifact collection is not configured")
+
+	// ErrArtifactNotFound is returned when a requested artifact does not exist
+	ErrArtifactNotFound = errors.New("artifact not found")
+)
+
+// artifactListTimeout bounds listing the artifacts of a deployment, downloads are bound by the request context
+const artifactListTimeout = 30 * time.Second
+
+// ArtifactStore reads pipeline artifacts from the artifact service over WebDAV
+type ArtifactStore struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewArtifactStore creates an ArtifactStore for the artifact service at baseURL
+func NewArtifactStore(baseURL string) *ArtifactStore {
+	return &ArtifactStore{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{},
+	}
+}
+
+// ArtifactContent is an open artifact download, the caller must close Body
+type ArtifactContent struct {
+	Body        io.ReadCloser
+	Size        int64
+	ContentType string
+}
+
+// webdavMultistatus is the subset of a WebDAV PROPFIND response used to list files
+type webdavMultistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Prop struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+				ContentLength int64  `xml:"getcontentlength"`
+				ContentType   string `xml:"getcontenttype"`
+				LastModified  string `xml:"getlastmodified"`
+			} `xml:"prop"`
+			Status string `xml:"status"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// List returns the files below the collection at prefix with paths relative to it. A missing
+// collection has no files.
+func (a *ArtifactStore) List(ctx context.Context, prefix string) ([]models.DeploymentArtifact, error) {
+	ctx, cancel := context.WithTimeout(ctx, artifactListTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "PROPFIND", a.url(prefix)+"/", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build artifact service request: %w", err)
+	}
+	req.Header.Set("Depth", "infinity")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return []models.DeploymentArtifact{}, nil
+	}
+	if resp.StatusCode != http.StatusMultiStatus {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("artifact service returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result webdavMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode artifact listing: %w", err)
+	}
+
+	root := "/" + strings.Trim(prefix, "/") + "/"
+	artifacts := []models.DeploymentArtifact{}
+	for _, response := range result.Responses {
+		// Hrefs may be absolute URLs or escaped paths
+		href, err := url.Parse(response.Href)
+		if err != nil {
+			continue
+		}
+		relative, ok := strings.CutPrefix(href.Path, root)
+		if !ok || relative == "" {
+			continue
+		}
+
+		for _, propstat := range response.Propstat {
+			if !strings.Contains(propstat.Status, " 200 ") || propstat.Prop.ResourceType.Collection != nil {
+				continue
+			}
+			artifact := models.DeploymentArtifact{
+				Path:        relative,
+				Size:        propstat.Prop.ContentLength,
+				ContentType: propstat.Prop.ContentType,
+			}
+			if modified, err := http.ParseTime(propstat.Prop.LastModified); err == nil {
+				artifact.LastModified = modified.UTC()
+			}
+			artifacts = append(artifacts, artifact)
+		}
+	}
+
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Path < artifacts[j].Path })
+	return artifacts, nil
+}
+
+// Open starts downloading the file at name
+func (a *ArtifactStore) Open(ctx context.Context, name string) (*ArtifactContent, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url(name), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build artifact service request: %w", err)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download artifact: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return &ArtifactContent{
+			Body:        resp.Body,
+			Size:        resp.ContentLength,
+			ContentType: resp.Header.Get("Content-Type"),
+		}, nil
+	case http.StatusNotFound:
+		_ = resp.Body.Close()
+		return nil, ErrArtifactNotFound
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("artifact service returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+}
+
+// url escapes name and joins it to the base URL
+func (a *ArtifactStore) url(name string) string {
+	return a.baseURL + (&url.URL{Path: "/" + strings.Trim(name, "/")}).EscapedPath()
+}
+
+// ListDeploymentArtifacts returns the artifacts published by the custom pipeline steps of a deployment
+func (s *DeploymentService) ListDeploymentArtifacts(ctx context.Context, uuid string) (*models.DeploymentArtifactsResponse, error) {
+	if s.artifacts == nil {
+		return nil, ErrArtifactStoreNotConfigured
+	}
+
+	deployment, err := s.GetDeployment(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts, err := s.artifacts.List(ctx, config.DeploymentArtifactsPath(deployment.UUID))
+	if err != nil {
+		return nil, err
+	}
+	for i := range artifacts {
+		artifacts[i].DownloadURL = fmt.Sprintf("/v1/deployments/%s/artifacts/%s", deployment.UUID, artifacts[i].Path)
+	}
+
+	return &models.DeploymentArtifactsResponse{
+		DeploymentUUID: deployment.UUID,
+		Artifacts:      artifacts,
+	}, nil
+}
+
+// GetDeploymentArtifact opens an artifact of a deployment for download
+func (s *DeploymentService) GetDeploymentArtifact(ctx context.Context, uuid, name string) (*ArtifactContent, error) {
+	if s.artifacts == nil {
+		return nil, ErrArtifactStoreNotConfigured
+	}
+
+	// Keep the path inside the deployment collection
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return nil, ErrArtifactNotFound
+	}
+
+	deployment, err := s.GetDeployment(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.artifacts.Open(ctx, config.DeploymentArtifactsPath(deployment.UUID)+"/"+name)
+}
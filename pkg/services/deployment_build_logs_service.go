@@ -0,0 +1,258 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/models"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// ErrBuildLogsNotConfigured is returned when the API server was started without access to pod logs
+var ErrBuildLogsNotConfigured = errors.New("build logs are not configured")
+
+// buildLogPollInterval is how often a followed build is checked for new tasks and started steps
+const buildLogPollInterval = 2 * time.Second
+
+// BuildLogStream follows the step logs of the pipeline run building a deployment
+type BuildLogStream struct {
+	client      client.Client
+	pods        corev1client.PodsGetter
+	namespace   string
+	pipelineRun string
+}
+
+// SetPodLogs enables following the build logs of deployments
+func (s *DeploymentService) SetPodLogs(pods corev1client.PodsGetter) {
+	s.pods = pods
+}
+
+// OpenBuildLogStream returns the build log stream of a deployment, ErrPipelineRunNotFound when
+// the deployment has no pipeline run
+func (s *DeploymentService) OpenBuildLogStream(ctx context.Context, uuid string) (*BuildLogStream, error) {
+	if s.pods == nil {
+		return nil, ErrBuildLogsNotConfigured
+	}
+
+	deployment, err := s.getDeploymentCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Named by the deployment controller after the generation it builds
+	var pipelineRun tektonv1.PipelineRun
+	key := types.NamespacedName{
+		Name:      fmt.Sprintf("pipeline-run-%s-%d", uuid, deployment.Generation),
+		Namespace: deployment.Namespace,
+	}
+	if err := s.client.Get(ctx, key, &pipelineRun); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ErrPipelineRunNotFound
+		}
+		return nil, fmt.Errorf("failed to get pipeline run: %w", err)
+	}
+
+	return &BuildLogStream{
+		client:      s.client,
+		pods:        s.pods,
+		namespace:   pipelineRun.Namespace,
+		pipelineRun: pipelineRun.Name,
+	}, nil
+}
+
+// Follow emits the lines of every step, task after task in the order they started, following the
+// running ones, until the pipeline run is done or ctx is cancelled. The log streams are read as
+// fast as emit returns, a blocking emit holds them, which is how slow clients push back.
+func (b *BuildLogStream) Follow(ctx context.Context, emit func(models.BuildLogLine) error) error {
+	followed := map[string]bool{}
+	for {
+		var pipelineRun tektonv1.PipelineRun
+		if err := b.client.Get(ctx, types.NamespacedName{Name: b.pipelineRun, Namespace: b.namespace}, &pipelineRun); err != nil {
+			return fmt.Errorf("failed to get pipeline run: %w", err)
+		}
+		// Read before the task runs, so the tasks of a done pipeline run are all listed
+		done := pipelineRun.IsDone()
+
+		var taskRuns tektonv1.TaskRunList
+		if err := b.client.List(ctx, &taskRuns, client.InNamespace(b.namespace), client.MatchingLabels{
+			"tekton.dev/pipelineRun": b.pipelineRun,
+		}); err != nil {
+			return fmt.Errorf("failed to list task runs: %w", err)
+		}
+		sortTaskRunsByStart(taskRuns.Items)
+
+		var next *tektonv1.TaskRun
+		for i := range taskRuns.Items {
+			taskRun := &taskRuns.Items[i]
+			if followed[taskRun.Name] {
+				continue
+			}
+			if taskRun.Status.PodName == "" {
+				// Tasks skipped or cancelled before starting never get a pod
+				if taskRun.IsDone() {
+					followed[taskRun.Name] = true
+				}
+				continue
+			}
+			next = taskRun
+			break
+		}
+
+		if next == nil {
+			if done {
+				return nil
+			}
+			if err := waitBuildLogPoll(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := b.followTaskRun(ctx, next, emit); err != nil {
+			return err
+		}
+		followed[next.Name] = true
+	}
+}
+
+// followTaskRun emits the lines of the steps of a task run in order
+func (b *BuildLogStream) followTaskRun(ctx context.Context, taskRun *tektonv1.TaskRun, emit func(models.BuildLogLine) error) error {
+	pod, err := b.pods.Pods(b.namespace).Get(ctx, taskRun.Status.PodName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Pruned along with its logs
+			return nil
+		}
+		return fmt.Errorf("failed to get pod of task run %s: %w", taskRun.Name, err)
+	}
+
+	task := taskRun.Labels["tekton.dev/pipelineTask"]
+	if task == "" {
+		task = taskRun.Name
+	}
+	for _, container := range pod.Spec.Containers {
+		if !strings.HasPrefix(container.Name, "step-") {
+			continue
+		}
+		step := strings.TrimPrefix(container.Name, "step-")
+		err := b.followContainer(ctx, pod.Name, container.Name, func(line string) error {
+			return emit(models.BuildLogLine{Task: task, Step: step, Line: line})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// followContainer emits the lines of a step container until it terminates, waiting for it to start
+func (b *BuildLogStream) followContainer(ctx context.Context, podName, container string, emit func(string) error) error {
+	for {
+		stream, err := b.pods.Pods(b.namespace).GetLogs(podName, &corev1.PodLogOptions{
+			Container: container,
+			Follow:    true,
+		}).Stream(ctx)
+		if err == nil {
+			defer func() { _ = stream.Close() }()
+			return readLogLines(stream, emit)
+		}
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if !apierrors.IsBadRequest(err) {
+			return fmt.Errorf("failed to follow logs of %s/%s: %w", podName, container, err)
+		}
+
+		// The container has not started yet, unless its pod finished without running it
+		pod, getErr := b.pods.Pods(b.namespace).Get(ctx, podName, metav1.GetOptions{})
+		if getErr != nil {
+			if apierrors.IsNotFound(getErr) {
+				return nil
+			}
+			return fmt.Errorf("failed to get pod %s: %w", podName, getErr)
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			return nil
+		}
+		if err := waitBuildLogPoll(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// readLogLines emits the lines of r, truncating the ones longer than MaxBuildLogLineBytes
+func readLogLines(r io.Reader, emit func(string) error) error {
+	reader := bufio.NewReaderSize(r, models.MaxBuildLogLineBytes)
+	for {
+		line, isPrefix, err := reader.ReadLine()
+		if len(line) > 0 || (err == nil && !isPrefix) {
+			text := string(line)
+			// Drop the rest of a line longer than the buffer
+			for isPrefix && err == nil {
+				_, isPrefix, err = reader.ReadLine()
+			}
+			if emitErr := emit(text); emitErr != nil {
+				return emitErr
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// sortTaskRunsByStart orders task runs by start time, the ones not started yet last
+func sortTaskRunsByStart(taskRuns []tektonv1.TaskRun) {
+	sort.SliceStable(taskRuns, func(i, j int) bool {
+		a, b := taskRuns[i].Status.StartTime, taskRuns[j].Status.StartTime
+		switch {
+		case a == nil || b == nil:
+			return a != nil && b == nil
+		case !a.Equal(b):
+			return a.Before(b)
+		default:
+			return taskRuns[i].Name < taskRuns[j].Name
+		}
+	})
+}
+
+func waitBuildLogPoll(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(buildLogPollInterval):
+		return nil
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/envcrypt"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ErrDeploymentEnvNotSyncable is returned when a deployment has no env vars to resync
+var ErrDeploymentEnvNotSyncable = errors.New("deployment env vars cannot be synced")
+
+// SyncDeploymentEnv copies the current env vars of the application into the env var Secret of a
+// deployment, which otherwise keeps the env vars the application had when it was created. The
+// operator restarts the pods of the deployment with the new values.
+func (s *DeploymentService) SyncDeploymentEnv(ctx context.Context, uuid string) (*models.Deployment, error) {
+	crd, err := s.getDeploymentCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	applicationUUID := crd.GetLabels()[validation.LabelApplicationUUID]
+	var applicationSecret corev1.Secret
+	if err := s.client.Get(ctx, client.ObjectKey{
+		Namespace: crd.Namespace,
+		Name:      utils.GetApplicationResourceName(applicationUUID),
+	}, &applicationSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: application %s has no environment variables secret", ErrDeploymentEnvNotSyncable, applicationUUID)
+		}
+		return nil, fmt.Errorf("failed to get application secret: %w", err)
+	}
+
+	var deploymentSecret corev1.Secret
+	if err := s.client.Get(ctx, client.ObjectKey{
+		Namespace: crd.Namespace,
+		Name:      utils.GetDeploymentResourceName(uuid),
+	}, &deploymentSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: deployment %s has no environment variables secret yet", ErrDeploymentEnvNotSyncable, uuid)
+		}
+		return nil, fmt.Errorf("failed to get deployment secret: %w", err)
+	}
+
+	patch := client.MergeFromWithOptions(deploymentSecret.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	deploymentSecret.Data = maps.Clone(applicationSecret.Data)
+	// Values stay encrypted in the copy, keep the provider annotation with them
+	if provider := applicationSecret.Annotations[envcrypt.AnnotationProvider]; provider != "" {
+		if deploymentSecret.Annotations == nil {
+			deploymentSecret.Annotations = map[string]string{}
+		}
+		deploymentSecret.Annotations[envcrypt.AnnotationProvider] = provider
+	} else {
+		delete(deploymentSecret.Annotations, envcrypt.AnnotationProvider)
+	}
+	if err := s.client.Patch(ctx, &deploymentSecret, patch); err != nil {
+		return nil, fmt.Errorf("failed to sync deployment secret: %w", err)
+	}
+
+	application, err := s.getApplicationByUUID(ctx, applicationUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	deployment := &models.Deployment{}
+	deployment.ConvertFromCRD(crd, application.Slug)
+	// The operator reports the deployment in sync once it sees the copied values
+	deployment.EnvOutOfSync = false
+	return deployment, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+// ErrExternalDeploymentNotAllowed is returned when an application cannot deploy externally built images
+var ErrExternalDeploymentNotAllowed = errors.New("external deployments are not allowed")
+
+// CreateExternalDeployment creates a deployment of an image already built by an external CI
+// system. The build pipeline is skipped and the image, pinned to its digest, is rolled out.
+// Only GitRepository applications deploy external builds, the image must be pullable by the
+// cluster.
+func (s *DeploymentService) CreateExternalDeployment(ctx context.Context, applicationUUID string, req *models.ExternalDeploymentCreateRequest) (*models.Deployment, error) {
+	application, err := s.getApplicationByUUID(ctx, applicationUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	if application.Type != models.ApplicationTypeGitRepository {
+		return nil, fmt.Errorf("%w: %s applications do not build images", ErrExternalDeploymentNotAllowed, application.Type)
+	}
+
+	slug, err := s.generateUniqueSlug(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment := models.NewDeployment(application.UUID, application.Slug, application.ProjectUUID, slug, &models.GitRepositoryDeploymentConfig{
+		CommitSHA: req.CommitSHA,
+		Branch:    req.Branch,
+	})
+	deployment.Source = req.Source
+
+	crd := s.convertToDeploymentCRD(deployment, application, req.Promote)
+	crd.Spec.OverrideFreeze = req.OverrideFreeze
+	crd.Spec.ExternalBuild = &v1alpha1.ExternalBuildSource{
+		Image:    req.PinnedImage(),
+		Provider: req.Provider,
+		RunURL:   req.RunURL,
+	}
+
+	if err := s.client.Create(ctx, crd); err != nil {
+		return nil, fmt.Errorf("failed to create Deployment CRD: %w", err)
+	}
+
+	created := &models.Deployment{}
+	created.ConvertFromCRD(crd, application.Slug)
+	if created.Phase == "" {
+		created.Phase = models.DeploymentPhaseInitializing
+	}
+	return created, nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/models"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// ErrPipelineRunNotFound is returned when a deployment has no pipeline run, such as promoted
+// deployments or builds that have not started yet
+var ErrPipelineRunNotFound = errors.New("deployment has no pipeline run")
+
+// GetDeploymentPipeline returns the task graph of the pipeline building a deployment with the
+// progress of every task
+func (s *DeploymentService) GetDeploymentPipeline(ctx context.Context, uuid string) (*models.DeploymentPipelineResponse, error) {
+	deployment, err := s.getDeploymentCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Named by the deployment controller after the generation it builds
+	var pipelineRun tektonv1.PipelineRun
+	key := types.NamespacedName{
+		Name:      fmt.Sprintf("pipeline-run-%s-%d", uuid, deployment.Generation),
+		Namespace: deployment.Namespace,
+	}
+	if err := s.client.Get(ctx, key, &pipelineRun); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ErrPipelineRunNotFound
+		}
+		return nil, fmt.Errorf("failed to get pipeline run: %w", err)
+	}
+
+	var taskRuns tektonv1.TaskRunList
+	if err := s.client.List(ctx, &taskRuns, client.InNamespace(deployment.Namespace), client.MatchingLabels{
+		"tekton.dev/pipelineRun": pipelineRun.Name,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list task runs: %w", err)
+	}
+
+	return models.NewDeploymentPipelineResponse(uuid, &pipelineRun, taskRuns.Items, time.Now()), nil
+}
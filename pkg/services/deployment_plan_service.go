@@ -0,0 +1,231 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/pipelines"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// PlanDeployment returns the Kubernetes resources the controllers would generate for a
+// deployment request, without creating anything. Builds are expensive, the plan lets them be
+// reviewed before they are triggered.
+func (s *DeploymentService) PlanDeployment(ctx context.Context, req *models.DeploymentCreateRequest) (*models.DeploymentPlan, error) {
+	app, err := s.getApplicationCRD(ctx, req.ApplicationUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	application := &models.Application{}
+	application.ConvertFromCRD(app)
+
+	slug, err := utils.GenerateRandomSlug()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate deployment slug: %w", err)
+	}
+	deployment := models.NewDeployment(application.UUID, application.Slug, application.ProjectUUID, slug, req.GitRepository)
+	deployment.ImageFromRegistry = req.ImageFromRegistry
+	deployment.Source = req.Source
+	crd := s.convertToDeploymentCRD(deployment, application, req.Promote)
+	crd.Spec.OverrideFreeze = req.OverrideFreeze
+	namespace := crd.Namespace
+
+	plan := &models.DeploymentPlan{
+		ApplicationUUID: application.UUID,
+		DeploymentUUID:  deployment.UUID,
+		Resources: []models.DeploymentPlanResource{
+			planResource(crd.Kind, crd.APIVersion, crd.Name, namespace, map[string]string{
+				"application": crd.Spec.ApplicationRef.Name,
+				"promote":     strconv.FormatBool(req.Promote),
+			}),
+			planResource("Secret", "v1", utils.GetDeploymentResourceName(deployment.UUID), namespace, map[string]string{
+				"copiedFrom": utils.GetApplicationResourceName(application.UUID),
+			}),
+		},
+	}
+
+	port := app.Spec.Port
+	if port == 0 {
+		port = 3000
+	}
+	var image, serviceName string
+	switch app.Spec.Type {
+	case v1alpha1.ApplicationTypeGitRepository:
+		resources, err := planPipeline(app, crd, application.ProjectSlug)
+		if err != nil {
+			return nil, err
+		}
+		plan.Resources = append(plan.Resources, resources...)
+		image = utils.GetBuiltImageName(namespace, application.UUID, deployment.UUID, "")
+		serviceName = utils.GetServiceName(application.UUID)
+	case v1alpha1.ApplicationTypeDockerImage:
+		image = utils.GetBuiltImageName(namespace, application.UUID, deployment.UUID, "")
+		serviceName = utils.GetServiceName(application.UUID)
+	case v1alpha1.ApplicationTypeImageFromRegistry:
+		if app.Spec.ImageFromRegistry == nil {
+			return nil, fmt.Errorf("ImageFromRegistry application missing image configuration")
+		}
+		image = app.Spec.ImageFromRegistry.Image(crd.Spec.ImageFromRegistry)
+		// ImageFromRegistry deployments get a Service of their own
+		serviceName = utils.GetServiceName(deployment.UUID)
+	default:
+		// Database applications run no workload per deployment
+		return plan, nil
+	}
+
+	plan.Resources = append(plan.Resources, planResource("Deployment", "apps/v1",
+		utils.GetKubernetesDeploymentName(deployment.UUID), namespace, map[string]string{
+			"image": image,
+			"port":  strconv.Itoa(int(port)),
+		}))
+
+	service := planResource("Service", "v1", serviceName, namespace, map[string]string{"port": strconv.Itoa(int(port))})
+	var existing corev1.Service
+	err = s.client.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: namespace}, &existing)
+	switch {
+	case err == nil:
+		// The Service of an application is shared by its deployments
+		service.Action = models.DeploymentPlanActionUnchanged
+	case !apierrors.IsNotFound(err):
+		return nil, fmt.Errorf("failed to get Service: %w", err)
+	}
+	plan.Resources = append(plan.Resources, service)
+
+	domain := planResource("ApplicationDomain", crd.APIVersion, utils.GetApplicationDomainResourceName(deployment.UUID), namespace, map[string]string{
+		"port": strconv.Itoa(int(port)),
+	})
+	baseDomain, err := s.planBaseDomain(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	if baseDomain != "" {
+		domain.Fields["domain"] = fmt.Sprintf("%s.apps.%s", deployment.UUID, baseDomain)
+	}
+	plan.Resources = append(plan.Resources, domain)
+
+	return plan, nil
+}
+
+// planPipeline returns the Pipeline and the PipelineRun building a git repository deployment,
+// generated with the spec version of new deployments
+func planPipeline(app *v1alpha1.Application, crd *v1alpha1.Deployment, projectSlug string) ([]models.DeploymentPlanResource, error) {
+	deploymentUUID := crd.Labels[validation.LabelResourceUUID]
+	pipeline, err := pipelines.Build(pipelines.CurrentSpecVersion, pipelines.Input{
+		Name:          fmt.Sprintf("pipeline-%s", deploymentUUID),
+		Deployment:    crd,
+		GitRepository: app.Spec.GitRepository,
+		ProjectSlug:   projectSlug,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate pipeline: %w", err)
+	}
+
+	tasks := make([]string, 0, len(pipeline.Spec.Tasks))
+	for _, task := range pipeline.Spec.Tasks {
+		tasks = append(tasks, task.Name)
+	}
+	buildType := app.Spec.GitRepository.BuildType
+	if buildType == "" {
+		buildType = v1alpha1.BuildTypeRailpack
+	}
+
+	// The branch of the deployment wins over the branch of the application
+	branch, commit := "", "HEAD"
+	if crd.Spec.GitRepository != nil {
+		branch = crd.Spec.GitRepository.Branch
+		if crd.Spec.GitRepository.CommitSHA != "" {
+			commit = crd.Spec.GitRepository.CommitSHA
+		}
+	}
+	if branch == "" {
+		branch = app.Spec.GitRepository.Branch
+	}
+	if branch == "" {
+		branch = pipelines.DefaultGitBranch
+	}
+
+	return []models.DeploymentPlanResource{
+		planResource("Pipeline", "tekton.dev/v1", pipeline.Name, crd.Namespace, map[string]string{
+			"buildType":   string(buildType),
+			"specVersion": pipelines.CurrentSpecVersion,
+			"tasks":       strings.Join(tasks, ","),
+		}),
+		// The first generation of the deployment runs the first build
+		planResource("PipelineRun", "tekton.dev/v1", fmt.Sprintf("pipeline-run-%s-1", deploymentUUID), crd.Namespace, map[string]string{
+			"pipeline":   pipeline.Name,
+			"repository": app.Spec.GitRepository.Repository,
+			"branch":     branch,
+			"commit":     commit,
+		}),
+	}, nil
+}
+
+// planBaseDomain resolves the base domain of the generated deployment domains like the
+// controllers: the application wins over its project, which wins over the operator domain. The
+// operator domain is read from the default domain of the application, generated on it.
+func (s *DeploymentService) planBaseDomain(ctx context.Context, app *v1alpha1.Application) (string, error) {
+	if app.Spec.BaseDomain != "" {
+		return app.Spec.BaseDomain, nil
+	}
+
+	var projects v1alpha1.ProjectList
+	if err := s.client.List(ctx, &projects, client.MatchingLabels{
+		validation.LabelResourceUUID: app.Labels[validation.LabelProjectUUID],
+	}); err != nil {
+		return "", fmt.Errorf("failed to list projects: %w", err)
+	}
+	if len(projects.Items) > 0 && projects.Items[0].Spec.BaseDomain != "" {
+		return projects.Items[0].Spec.BaseDomain, nil
+	}
+
+	var domains v1alpha1.ApplicationDomainList
+	if err := s.client.List(ctx, &domains, client.InNamespace(app.Namespace), client.MatchingLabels{
+		validation.LabelApplicationUUID: app.GetUUID(),
+	}); err != nil {
+		return "", fmt.Errorf("failed to list application domains: %w", err)
+	}
+	for _, domain := range domains.Items {
+		if _, base, found := strings.Cut(domain.Spec.Domain, ".apps."); found && domain.Spec.Default {
+			return base, nil
+		}
+	}
+	return "", nil
+}
+
+// planResource returns a resource of a deployment plan created by the deployment
+func planResource(kind, apiVersion, name, namespace string, fields map[string]string) models.DeploymentPlanResource {
+	return models.DeploymentPlanResource{
+		Kind:       kind,
+		APIVersion: apiVersion,
+		Name:       name,
+		Namespace:  namespace,
+		Action:     models.DeploymentPlanActionCreate,
+		Fields:     fields,
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const planAppUUID = "33333333-3333-3333-3333-333333333333"
+
+// planDeployment plans a deployment of an application of the given spec
+func planDeployment(t *testing.T, spec v1alpha1.ApplicationSpec, req *models.DeploymentCreateRequest) *models.DeploymentPlan {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	app := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-web",
+			Namespace: "project-ns",
+			Labels: map[string]string{
+				validation.LabelResourceUUID: planAppUUID,
+				validation.LabelResourceSlug: "web",
+				validation.LabelProjectUUID:  "44444444-4444-4444-4444-444444444444",
+			},
+		},
+		Spec: spec,
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).Build()
+
+	req.ApplicationUUID = planAppUUID
+	plan, err := NewDeploymentService(c, scheme, nil).PlanDeployment(context.Background(), req)
+	if err != nil {
+		t.Fatalf("PlanDeployment() error = %v", err)
+	}
+	return plan
+}
+
+// planFields returns the fields of the planned resource of the API version and kind, nil when it
+// is not planned
+func planFields(plan *models.DeploymentPlan, apiVersion, kind string) map[string]string {
+	for _, resource := range plan.Resources {
+		if resource.APIVersion == apiVersion && resource.Kind == kind {
+			return resource.Fields
+		}
+	}
+	return nil
+}
+
+func TestPlanDeploymentImageFromRegistry(t *testing.T) {
+	tests := []struct {
+		name   string
+		config v1alpha1.ImageFromRegistryConfig
+		req    *models.ImageFromRegistryDeploymentConfig
+		want   string
+	}{
+		{
+			name:   "tag of the deployment",
+			config: v1alpha1.ImageFromRegistryConfig{Registry: v1alpha1.RegistryTypeDockerHub, Repository: "library/nginx", DefaultTag: "1.26"},
+			req:    &models.ImageFromRegistryDeploymentConfig{Tag: "1.27"},
+			want:   "docker.io/library/nginx:1.27",
+		},
+		{
+			name:   "default tag of the application",
+			config: v1alpha1.ImageFromRegistryConfig{Registry: v1alpha1.RegistryTypeGHCR, Repository: "acme/api", DefaultTag: "v2"},
+			want:   "ghcr.io/acme/api:v2",
+		},
+		{
+			name:   "latest without any tag",
+			config: v1alpha1.ImageFromRegistryConfig{Registry: v1alpha1.RegistryTypeGHCR, Repository: "acme/api"},
+			req:    &models.ImageFromRegistryDeploymentConfig{},
+			want:   "ghcr.io/acme/api:latest",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planDeployment(t, v1alpha1.ApplicationSpec{
+				Type:              v1alpha1.ApplicationTypeImageFromRegistry,
+				ImageFromRegistry: &tt.config,
+			}, &models.DeploymentCreateRequest{ImageFromRegistry: tt.req})
+
+			if got := planFields(plan, "apps/v1", "Deployment")["image"]; got != tt.want {
+				t.Errorf("planned image = %q, want %q", got, tt.want)
+			}
+			if planFields(plan, "v1", "Service") == nil {
+				t.Error("expected a Service of the deployment to be planned")
+			}
+		})
+	}
+}
+
+func TestPlanDeploymentApplicationTypes(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      v1alpha1.ApplicationSpec
+		wantKinds []string
+	}{
+		{
+			name: "git repository",
+			spec: v1alpha1.ApplicationSpec{
+				Type:          v1alpha1.ApplicationTypeGitRepository,
+				GitRepository: &v1alpha1.GitRepositoryConfig{Provider: v1alpha1.GitProviderGitHub, Repository: "acme/web"},
+			},
+			wantKinds: []string{"Deployment", "Secret", "Pipeline", "PipelineRun", "Deployment", "Service", "ApplicationDomain"},
+		},
+		{
+			name: "docker image",
+			spec: v1alpha1.ApplicationSpec{
+				Type:        v1alpha1.ApplicationTypeDockerImage,
+				DockerImage: &v1alpha1.DockerImageConfig{Image: "nginx:1.27"},
+			},
+			wantKinds: []string{"Deployment", "Secret", "Deployment", "Service", "ApplicationDomain"},
+		},
+		{
+			name: "database",
+			spec: v1alpha1.ApplicationSpec{
+				Type:     v1alpha1.ApplicationTypePostgres,
+				Postgres: &v1alpha1.PostgresConfig{},
+			},
+			wantKinds: []string{"Deployment", "Secret"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planDeployment(t, tt.spec, &models.DeploymentCreateRequest{})
+
+			kinds := make([]string, 0, len(plan.Resources))
+			for _, resource := range plan.Resources {
+				kinds = append(kinds, resource.Kind)
+			}
+			if len(kinds) != len(tt.wantKinds) {
+				t.Fatalf("planned kinds = %v, want %v", kinds, tt.wantKinds)
+			}
+			for i := range kinds {
+				if kinds[i] != tt.wantKinds[i] {
+					t.Fatalf("planned kinds = %v, want %v", kinds, tt.wantKinds)
+				}
+			}
+		})
+	}
+}
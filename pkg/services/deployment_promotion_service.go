@@ -0,0 +1,239 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ErrPromotionNotAllowed is returned when a deployment cannot be promoted to the requested environment
+var ErrPromotionNotAllowed = errors.New("deployment cannot be promoted")
+
+// PromoteDeploymentToEnvironment creates a deployment in another environment of the project that
+// reuses the image of a succeeded deployment instead of building the commit again. The new
+// deployment goes live once it is running unless the target environment requires approval, and
+// the freeze windows of the target environment apply unless overrideFreeze is set.
+func (s *DeploymentService) PromoteDeploymentToEnvironment(ctx context.Context, uuid, environmentUUID string, req *models.DeploymentPromoteToRequest) (*models.Deployment, error) {
+	source, err := s.getDeploymentCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.Status.Phase != v1alpha1.DeploymentPhaseSucceeded {
+		return nil, fmt.Errorf("%w: only succeeded deployments can be promoted, deployment %s is %s",
+			ErrPromotionNotAllowed, uuid, source.Status.Phase)
+	}
+
+	sourceApplication, err := s.getApplicationCRD(ctx, source.GetLabels()[validation.LabelApplicationUUID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	environment, err := s.getEnvironmentCRD(ctx, environmentUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceEnvironmentUUID := source.GetLabels()[validation.LabelEnvironmentUUID]
+	if environmentUUID == sourceEnvironmentUUID {
+		return nil, fmt.Errorf("%w: deployment %s already belongs to environment %s", ErrPromotionNotAllowed, uuid, environmentUUID)
+	}
+	if environment.GetLabels()[validation.LabelProjectUUID] != source.GetLabels()[validation.LabelProjectUUID] {
+		return nil, fmt.Errorf("%w: environment %s belongs to another project", ErrPromotionNotAllowed, environmentUUID)
+	}
+
+	targetApplication, err := s.findPromotionTarget(ctx, sourceApplication, environmentUUID, req.ApplicationUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	promotedFrom := &v1alpha1.PromotionSource{
+		DeploymentUUID:  uuid,
+		EnvironmentUUID: sourceEnvironmentUUID,
+	}
+	switch sourceApplication.Spec.Type {
+	case v1alpha1.ApplicationTypeGitRepository:
+		promotedFrom.Image = utils.GetBuiltImageName(source.Namespace, sourceApplication.GetUUID(), uuid, source.Status.ImageDigest)
+		if image := source.PrebuiltImage(); image != "" {
+			// The source deployment was not built by its own pipeline either
+			promotedFrom.Image = image
+		}
+	case v1alpha1.ApplicationTypeImageFromRegistry:
+		if source.Spec.ImageFromRegistry == nil {
+			return nil, fmt.Errorf("%w: deployment %s has no image configuration", ErrPromotionNotAllowed, uuid)
+		}
+		sourceImage, targetImage := sourceApplication.Spec.ImageFromRegistry, targetApplication.Spec.ImageFromRegistry
+		if sourceImage == nil || targetImage == nil ||
+			sourceImage.Registry != targetImage.Registry || sourceImage.Repository != targetImage.Repository {
+			return nil, fmt.Errorf("%w: target application does not deploy the same image repository", ErrPromotionNotAllowed)
+		}
+		promotedFrom.Image = sourceImage.Image(source.Spec.ImageFromRegistry)
+	default:
+		return nil, fmt.Errorf("%w: %s applications cannot be promoted", ErrPromotionNotAllowed, sourceApplication.Spec.Type)
+	}
+
+	application, err := s.getApplicationByUUID(ctx, targetApplication.GetUUID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	slug, err := s.generateUniqueSlug(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Carry the commit over so the promoted deployment reports what it runs
+	var gitRepository *models.GitRepositoryDeploymentConfig
+	if source.Spec.GitRepository != nil {
+		gitRepository = &models.GitRepositoryDeploymentConfig{
+			CommitSHA: source.Spec.GitRepository.CommitSHA,
+			Branch:    source.Spec.GitRepository.Branch,
+		}
+	}
+	deployment := models.NewDeployment(application.UUID, application.Slug, application.ProjectUUID, slug, gitRepository)
+	deployment.Source = models.DeploymentSourceFromAnnotations(source.GetAnnotations())
+
+	crd := s.convertToDeploymentCRD(deployment, application, !environment.Spec.RequireApproval)
+	crd.Spec.OverrideFreeze = req.OverrideFreeze
+	crd.Spec.PromotedFrom = promotedFrom
+	if source.Spec.ImageFromRegistry != nil {
+		crd.Spec.ImageFromRegistry = source.Spec.ImageFromRegistry.DeepCopy()
+	}
+
+	if err := s.client.Create(ctx, crd); err != nil {
+		return nil, fmt.Errorf("failed to create Deployment CRD: %w", err)
+	}
+
+	promoted := &models.Deployment{}
+	promoted.ConvertFromCRD(crd, application.Slug)
+	if promoted.Phase == "" {
+		promoted.Phase = models.DeploymentPhaseInitializing
+	}
+	return promoted, nil
+}
+
+// findPromotionTarget returns the application of the target environment a deployment is promoted
+// to, the one with the same name as the source application unless applicationUUID selects one
+func (s *DeploymentService) findPromotionTarget(ctx context.Context, source *v1alpha1.Application, environmentUUID, applicationUUID string) (*v1alpha1.Application, error) {
+	var target *v1alpha1.Application
+	if applicationUUID != "" {
+		application, err := s.getApplicationCRD(ctx, applicationUUID)
+		if err != nil {
+			return nil, err
+		}
+		if application.GetLabels()[validation.LabelEnvironmentUUID] != environmentUUID {
+			return nil, fmt.Errorf("%w: application %s does not belong to environment %s", ErrPromotionNotAllowed, applicationUUID, environmentUUID)
+		}
+		target = application
+	} else {
+		var applicationList v1alpha1.ApplicationList
+		if err := s.client.List(ctx, &applicationList, client.MatchingLabels{
+			validation.LabelEnvironmentUUID: environmentUUID,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list applications: %w", err)
+		}
+
+		name := source.GetAnnotations()[validation.AnnotationResourceName]
+		for i := range applicationList.Items {
+			if applicationList.Items[i].GetAnnotations()[validation.AnnotationResourceName] == name {
+				target = &applicationList.Items[i]
+				break
+			}
+		}
+		if target == nil {
+			return nil, fmt.Errorf("%w: environment %s has no application named %q", ErrPromotionNotAllowed, environmentUUID, name)
+		}
+	}
+
+	if target.Spec.Type != source.Spec.Type {
+		return nil, fmt.Errorf("%w: target application is a %s application, the deployment is for a %s application",
+			ErrPromotionNotAllowed, target.Spec.Type, source.Spec.Type)
+	}
+	return target, nil
+}
+
+// getDeploymentCRD fetches a Deployment CRD by UUID
+func (s *DeploymentService) getDeploymentCRD(ctx context.Context, uuid string) (*v1alpha1.Deployment, error) {
+	var deploymentList v1alpha1.DeploymentList
+	err := s.client.List(ctx, &deploymentList, client.MatchingLabels{
+		validation.LabelResourceUUID: uuid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	if len(deploymentList.Items) == 0 {
+		return nil, fmt.Errorf("deployment with UUID %s not found", uuid)
+	}
+
+	if len(deploymentList.Items) > 1 {
+		return nil, fmt.Errorf("multiple deployments found with UUID %s", uuid)
+	}
+
+	return &deploymentList.Items[0], nil
+}
+
+// getApplicationCRD fetches an Application CRD by UUID
+func (s *DeploymentService) getApplicationCRD(ctx context.Context, uuid string) (*v1alpha1.Application, error) {
+	var applicationList v1alpha1.ApplicationList
+	err := s.client.List(ctx, &applicationList, client.MatchingLabels{
+		validation.LabelResourceUUID: uuid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	if len(applicationList.Items) == 0 {
+		return nil, fmt.Errorf("application with UUID %s not found", uuid)
+	}
+
+	if len(applicationList.Items) > 1 {
+		return nil, fmt.Errorf("multiple applications found with UUID %s", uuid)
+	}
+
+	return &applicationList.Items[0], nil
+}
+
+// getEnvironmentCRD fetches an Environment CRD by UUID
+func (s *DeploymentService) getEnvironmentCRD(ctx context.Context, uuid string) (*v1alpha1.Environment, error) {
+	var environmentList v1alpha1.EnvironmentList
+	err := s.client.List(ctx, &environmentList, client.MatchingLabels{
+		validation.LabelResourceUUID: uuid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	if len(environmentList.Items) == 0 {
+		return nil, fmt.Errorf("environment with UUID %s not found", uuid)
+	}
+
+	if len(environmentList.Items) > 1 {
+		return nil, fmt.Errorf("multiple environments found with UUID %s", uuid)
+	}
+
+	return &environmentList.Items[0], nil
+}
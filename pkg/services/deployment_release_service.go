@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// GetReleasesByApplication returns the promoted deployments of an application, newest first
+func (s *DeploymentService) GetReleasesByApplication(ctx context.Context, applicationUUID string) ([]models.ReleaseResponse, error) {
+	application, err := s.getApplicationByUUID(ctx, applicationUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	applicationCRD, err := s.getApplicationCRD(ctx, application.UUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	var current string
+	if applicationCRD.Spec.CurrentDeploymentRef != nil {
+		current = applicationCRD.Spec.CurrentDeploymentRef.Name
+	}
+
+	var deploymentList v1alpha1.DeploymentList
+	if err := s.client.List(ctx, &deploymentList, client.MatchingLabels{
+		validation.LabelApplicationUUID: application.UUID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	return models.BuildReleases(deploymentList.Items, current, application.Slug), nil
+}
+
+// recordPromotion annotates a deployment with when and by whom it was promoted
+func (s *DeploymentService) recordPromotion(ctx context.Context, deploymentUUID, promotedBy string) error {
+	var deploymentList v1alpha1.DeploymentList
+	if err := s.client.List(ctx, &deploymentList, client.MatchingLabels{
+		validation.LabelResourceUUID: deploymentUUID,
+	}); err != nil {
+		return fmt.Errorf("failed to list deployments: %w", err)
+	}
+	if len(deploymentList.Items) == 0 {
+		return fmt.Errorf("deployment with UUID %s not found", deploymentUUID)
+	}
+
+	deployment := &deploymentList.Items[0]
+	patch := client.MergeFrom(deployment.DeepCopy())
+	if deployment.Annotations == nil {
+		deployment.Annotations = map[string]string{}
+	}
+	deployment.Annotations[validation.AnnotationPromotedAt] = time.Now().UTC().Format(time.RFC3339)
+	deployment.Annotations[validation.AnnotationPromotedBy] = promotedBy
+
+	if err := s.client.Patch(ctx, deployment, patch); err != nil {
+		return fmt.Errorf("failed to record deployment promotion: %w", err)
+	}
+	return nil
+}
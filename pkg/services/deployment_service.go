@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kibamail/kibaship/api/v1alpha1"
@@ -36,6 +37,12 @@ type DeploymentService struct {
 	client             client.Client
 	scheme             *runtime.Scheme
 	applicationService *ApplicationService
+
+	// artifacts serves pipeline artifacts, nil when artifact collection is disabled
+	artifacts *ArtifactStore
+
+	// pods reads the logs of build pods, nil when build logs are not configured
+	pods corev1client.PodsGetter
 }
 
 // NewDeploymentService creates a new deployment service
@@ -47,6 +54,11 @@ func NewDeploymentService(k8sClient client.Client, scheme *runtime.Scheme, appli
 	}
 }
 
+// SetArtifactStore enables listing and downloading pipeline artifacts
+func (s *DeploymentService) SetArtifactStore(store *ArtifactStore) {
+	s.artifacts = store
+}
+
 // CreateDeployment creates a new deployment
 func (s *DeploymentService) CreateDeployment(ctx context.Context, req *models.DeploymentCreateRequest) (*models.Deployment, error) {
 	// First, verify the application exists and get its details
@@ -55,34 +67,17 @@ func (s *DeploymentService) CreateDeployment(ctx context.Context, req *models.De
 		return nil, fmt.Errorf("failed to get application: %w", err)
 	}
 
-	// Generate random slug for deployment
-	slug, err := utils.GenerateRandomSlug()
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate deployment slug: %w", err)
-	}
-
-	// Check if slug already exists (very unlikely but possible)
-	exists, err := s.slugExists(ctx, slug)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check slug uniqueness: %w", err)
-	}
-
-	// If slug exists, try generating a new one (up to 3 attempts)
-	attempts := 0
-	for exists && attempts < 3 {
-		slug, err = utils.GenerateRandomSlug()
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate deployment slug: %w", err)
-		}
-		exists, err = s.slugExists(ctx, slug)
-		if err != nil {
-			return nil, fmt.Errorf("failed to check slug uniqueness: %w", err)
+	// Builds are refused while the project uses more registry storage than its quota
+	if application.Type == models.ApplicationTypeGitRepository {
+		if err := checkRegistryQuota(ctx, s.client, application.ProjectUUID); err != nil {
+			return nil, err
 		}
-		attempts++
 	}
 
-	if exists {
-		return nil, fmt.Errorf("failed to generate unique slug after 3 attempts")
+	// Generate random slug for deployment
+	slug, err := s.generateUniqueSlug(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create internal deployment model
@@ -98,9 +93,11 @@ func (s *DeploymentService) CreateDeployment(ctx context.Context, req *models.De
 	if req.ImageFromRegistry != nil {
 		deployment.ImageFromRegistry = req.ImageFromRegistry
 	}
+	deployment.Source = req.Source
 
 	// Create Kubernetes Deployment CRD
 	crd := s.convertToDeploymentCRD(deployment, application, req.Promote)
+	crd.Spec.OverrideFreeze = req.OverrideFreeze
 
 	err = s.client.Create(ctx, crd)
 	if err != nil {
@@ -179,8 +176,10 @@ func (s *DeploymentService) GetDeploymentsByApplication(ctx context.Context, app
 }
 
 // PromoteDeployment promotes a deployment by updating the application's currentDeploymentRef
-// This function is for explicit promotion via API calls
-func (s *DeploymentService) PromoteDeployment(ctx context.Context, deploymentUUID string) error {
+// This function is for explicit promotion via API calls. Promotions are rejected while a freeze
+// window of the application's environment is active unless overrideFreeze is set. promotedBy is
+// recorded on the deployment for the release history of the application.
+func (s *DeploymentService) PromoteDeployment(ctx context.Context, deploymentUUID string, overrideFreeze bool, promotedBy string) error {
 	// Get the deployment
 	deployment, err := s.GetDeployment(ctx, deploymentUUID)
 	if err != nil {
@@ -212,6 +211,13 @@ func (s *DeploymentService) PromoteDeployment(ctx context.Context, deploymentUUI
 		return nil // Already promoted
 	}
 
+	// The first deployment of an application is always promoted, matching the controller
+	if application.Spec.CurrentDeploymentRef != nil && !overrideFreeze {
+		if err := checkFreezeWindows(ctx, s.client, application.GetLabels()[validation.LabelEnvironmentUUID]); err != nil {
+			return err
+		}
+	}
+
 	// Update the currentDeploymentRef
 	application.Spec.CurrentDeploymentRef = &corev1.LocalObjectReference{
 		Name: utils.GetDeploymentResourceName(deploymentUUID),
@@ -221,7 +227,7 @@ func (s *DeploymentService) PromoteDeployment(ctx context.Context, deploymentUUI
 		return fmt.Errorf("failed to update application currentDeploymentRef: %w", err)
 	}
 
-	return nil
+	return s.recordPromotion(ctx, deployment.UUID, promotedBy)
 }
 
 // GetLatestDeploymentByApplicationUUID retrieves the most recent deployment for an application by UUID
@@ -262,6 +268,40 @@ func (s *DeploymentService) GetLatestDeploymentByApplicationUUID(ctx context.Con
 	return deployment, nil
 }
 
+// generateUniqueSlug generates a random deployment slug that is not in use
+func (s *DeploymentService) generateUniqueSlug(ctx context.Context) (string, error) {
+	slug, err := utils.GenerateRandomSlug()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate deployment slug: %w", err)
+	}
+
+	// Check if slug already exists (very unlikely but possible)
+	exists, err := s.slugExists(ctx, slug)
+	if err != nil {
+		return "", fmt.Errorf("failed to check slug uniqueness: %w", err)
+	}
+
+	// If slug exists, try generating a new one (up to 3 attempts)
+	attempts := 0
+	for exists && attempts < 3 {
+		slug, err = utils.GenerateRandomSlug()
+		if err != nil {
+			return "", fmt.Errorf("failed to generate deployment slug: %w", err)
+		}
+		exists, err = s.slugExists(ctx, slug)
+		if err != nil {
+			return "", fmt.Errorf("failed to check slug uniqueness: %w", err)
+		}
+		attempts++
+	}
+
+	if exists {
+		return "", fmt.Errorf("failed to generate unique slug after 3 attempts")
+	}
+
+	return slug, nil
+}
+
 // slugExists checks if a deployment with the given slug already exists
 func (s *DeploymentService) slugExists(ctx context.Context, slug string) (bool, error) {
 	var deploymentList v1alpha1.DeploymentList
@@ -329,6 +369,7 @@ func (s *DeploymentService) convertToDeploymentCRD(deployment *models.Deployment
 			Promote: promote,
 		},
 	}
+	deployment.Source.SetAnnotations(crd.Annotations)
 
 	// Add GitRepository config if present
 	if deployment.GitRepository != nil {
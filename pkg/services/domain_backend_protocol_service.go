@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+// ErrBackendProtocolConflict is returned when the other domains of an application set another
+// backend protocol
+var ErrBackendProtocolConflict = errors.New("backend protocol conflicts with the other domains of the application")
+
+// UpdateDomainBackendProtocol sets the protocol the application speaks behind an application domain.
+// The domains of an application must agree on it.
+func (s *ApplicationDomainService) UpdateDomainBackendProtocol(ctx context.Context, uuid string, req *models.DomainBackendProtocolRequest) (*models.ApplicationDomain, error) {
+	crd, err := s.getApplicationDomainCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	protocol := v1alpha1.BackendProtocol(req.BackendProtocol)
+	other, err := v1alpha1.FindBackendProtocol(ctx, s.client, crd.Namespace, crd.Spec.ApplicationRef.Name, crd.Name)
+	if err != nil {
+		return nil, err
+	}
+	if other != "" && other != protocol {
+		return nil, fmt.Errorf("%w: the other domains use %s", ErrBackendProtocolConflict, other)
+	}
+
+	crd, err = s.updateApplicationDomainSpec(ctx, crd, func(spec *v1alpha1.ApplicationDomainSpec) {
+		spec.BackendProtocol = protocol
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toApplicationDomain(ctx, crd)
+}
+
+// DeleteDomainBackendProtocol removes the backend protocol of an application domain, it follows
+// the other domains of the application again
+func (s *ApplicationDomainService) DeleteDomainBackendProtocol(ctx context.Context, uuid string) error {
+	crd, err := s.getApplicationDomainCRD(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.updateApplicationDomainSpec(ctx, crd, func(spec *v1alpha1.ApplicationDomainSpec) {
+		spec.BackendProtocol = ""
+	})
+	return err
+}
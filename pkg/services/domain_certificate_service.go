@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ErrInvalidDomainCertificate is returned when an uploaded certificate cannot be served for its domain
+var ErrInvalidDomainCertificate = errors.New("invalid domain certificate")
+
+// UploadDomainCertificate stores the certificate and private key of a custom domain in a TLS Secret
+// the domain serves instead of an ACME certificate. Replacing the certificate validates the new one
+// the same way, the domain keeps serving the previous certificate when it is rejected.
+func (s *ApplicationDomainService) UploadDomainCertificate(ctx context.Context, uuid string, req *models.DomainCertificateRequest) (*models.ApplicationDomain, error) {
+	crd, err := s.getApplicationDomainCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if crd.Spec.Type != v1alpha1.ApplicationDomainTypeCustom {
+		return nil, fmt.Errorf("%w: only custom domains can bring their own certificate", ErrInvalidDomainCertificate)
+	}
+
+	certPEM := []byte(strings.TrimSpace(req.Certificate) + "\n")
+	keyPEM := []byte(strings.TrimSpace(req.PrivateKey) + "\n")
+	if _, err := v1alpha1.ValidateDomainCertificate(certPEM, keyPEM, crd.Spec.Domain, time.Now()); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDomainCertificate, err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("domain-certificate-%s", crd.Name),
+			Namespace: crd.Namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, s.client, secret, func() error {
+		secret.Labels = map[string]string{
+			"app.kubernetes.io/managed-by":        "kibaship",
+			"platform.operator.kibaship.com/type": "domain-certificate",
+			validation.LabelProjectUUID:           crd.GetLabels()[validation.LabelProjectUUID],
+		}
+		secret.Type = corev1.SecretTypeTLS
+		secret.Data = map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		}
+		return controllerutil.SetControllerReference(crd, secret, s.scheme)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store domain certificate: %w", err)
+	}
+
+	crd, err = s.updateApplicationDomainSpec(ctx, crd, func(spec *v1alpha1.ApplicationDomainSpec) {
+		spec.CertificateSecretRef = &corev1.LocalObjectReference{Name: secret.Name}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toApplicationDomain(ctx, crd)
+}
+
+// DeleteDomainCertificate removes the uploaded certificate of a domain, which goes back to a
+// certificate issued through ACME
+func (s *ApplicationDomainService) DeleteDomainCertificate(ctx context.Context, uuid string) error {
+	crd, err := s.getApplicationDomainCRD(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	ref := crd.Spec.CertificateSecretRef
+	if ref == nil {
+		return nil
+	}
+
+	if _, err := s.updateApplicationDomainSpec(ctx, crd, func(spec *v1alpha1.ApplicationDomainSpec) {
+		spec.CertificateSecretRef = nil
+	}); err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: crd.Namespace}}
+	if err := s.client.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete domain certificate: %w", err)
+	}
+	return nil
+}
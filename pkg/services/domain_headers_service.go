@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+// UpdateDomainHeaders replaces the security headers and CORS policy of an application domain
+func (s *ApplicationDomainService) UpdateDomainHeaders(ctx context.Context, uuid string, req *models.DomainHeadersRequest) (*models.ApplicationDomain, error) {
+	crd, err := s.getApplicationDomainCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	crd, err = s.updateApplicationDomainSpec(ctx, crd, func(spec *v1alpha1.ApplicationDomainSpec) {
+		spec.SecurityHeaders = req.SecurityHeaders.ToCRD()
+		spec.CORS = req.CORS.ToCRD()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toApplicationDomain(ctx, crd)
+}
+
+// DeleteDomainHeaders removes the security headers and CORS policy of an application domain
+func (s *ApplicationDomainService) DeleteDomainHeaders(ctx context.Context, uuid string) error {
+	crd, err := s.getApplicationDomainCRD(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.updateApplicationDomainSpec(ctx, crd, func(spec *v1alpha1.ApplicationDomainSpec) {
+		spec.SecurityHeaders = nil
+		spec.CORS = nil
+	})
+	return err
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+// UpdateDomainIngressAnnotations replaces the annotations an application domain adds to its Ingress
+func (s *ApplicationDomainService) UpdateDomainIngressAnnotations(ctx context.Context, uuid string, req *models.DomainIngressAnnotationsRequest) (*models.ApplicationDomain, error) {
+	crd, err := s.getApplicationDomainCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	crd, err = s.updateApplicationDomainSpec(ctx, crd, func(spec *v1alpha1.ApplicationDomainSpec) {
+		spec.IngressAnnotations = req.Annotations
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toApplicationDomain(ctx, crd)
+}
+
+// DeleteDomainIngressAnnotations removes the annotations an application domain adds to its Ingress
+func (s *ApplicationDomainService) DeleteDomainIngressAnnotations(ctx context.Context, uuid string) error {
+	crd, err := s.getApplicationDomainCRD(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.updateApplicationDomainSpec(ctx, crd, func(spec *v1alpha1.ApplicationDomainSpec) {
+		spec.IngressAnnotations = nil
+	})
+	return err
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+// UpdateDomainProxySettings replaces the request body size limit and proxy timeouts of an
+// application domain, settings left out of the request fall back to the ingress defaults
+func (s *ApplicationDomainService) UpdateDomainProxySettings(ctx context.Context, uuid string, req *models.DomainProxySettings) (*models.ApplicationDomain, error) {
+	maxBodySize, readTimeout, sendTimeout, validationErr := req.ToCRD()
+	if validationErr != nil {
+		return nil, fmt.Errorf("invalid proxy settings: %s", validationErr.Message)
+	}
+
+	crd, err := s.getApplicationDomainCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	crd, err = s.updateApplicationDomainSpec(ctx, crd, func(spec *v1alpha1.ApplicationDomainSpec) {
+		spec.MaxBodySize = maxBodySize
+		spec.ProxyReadTimeout = readTimeout
+		spec.ProxySendTimeout = sendTimeout
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toApplicationDomain(ctx, crd)
+}
+
+// DeleteDomainProxySettings restores the ingress defaults for the request body size limit and
+// proxy timeouts of an application domain
+func (s *ApplicationDomainService) DeleteDomainProxySettings(ctx context.Context, uuid string) error {
+	crd, err := s.getApplicationDomainCRD(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.updateApplicationDomainSpec(ctx, crd, func(spec *v1alpha1.ApplicationDomainSpec) {
+		spec.MaxBodySize = nil
+		spec.ProxyReadTimeout = nil
+		spec.ProxySendTimeout = nil
+	})
+	return err
+}
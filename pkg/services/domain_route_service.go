@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ErrInvalidDomainRoute is returned when a path route targets an application it cannot route to
+var ErrInvalidDomainRoute = errors.New("invalid domain route")
+
+// UpdateDomainRoutes replaces the path routes of an application domain. Routes may only target
+// web applications of the project the domain belongs to.
+func (s *ApplicationDomainService) UpdateDomainRoutes(ctx context.Context, uuid string, req *models.DomainRoutesRequest) (*models.ApplicationDomain, error) {
+	crd, err := s.getApplicationDomainCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := make([]v1alpha1.DomainRoute, 0, len(req.Routes))
+	for _, route := range req.Routes {
+		application, err := s.getApplicationByUUID(ctx, route.ApplicationUUID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: route %s: %v", ErrInvalidDomainRoute, route.Path, err)
+		}
+
+		switch application.Type {
+		case models.ApplicationTypeGitRepository, models.ApplicationTypeDockerImage, models.ApplicationTypeImageFromRegistry:
+		default:
+			return nil, fmt.Errorf("%w: route %s: application %s of type %s does not serve HTTP",
+				ErrInvalidDomainRoute, route.Path, application.UUID, application.Type)
+		}
+
+		if application.ProjectUUID != crd.GetLabels()[validation.LabelProjectUUID] {
+			return nil, fmt.Errorf("%w: route %s: application %s belongs to another project",
+				ErrInvalidDomainRoute, route.Path, application.UUID)
+		}
+
+		port := route.Port
+		if port == 0 {
+			port = 3000 // Default port
+		}
+		routes = append(routes, v1alpha1.DomainRoute{
+			Path:           v1alpha1.NormalizeRoutePath(route.Path),
+			ApplicationRef: corev1.LocalObjectReference{Name: utils.GetApplicationResourceName(application.UUID)},
+			Port:           port,
+		})
+	}
+	if len(routes) == 0 {
+		routes = nil
+	}
+
+	crd, err = s.updateApplicationDomainSpec(ctx, crd, func(spec *v1alpha1.ApplicationDomainSpec) {
+		spec.Routes = routes
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toApplicationDomain(ctx, crd)
+}
+
+// DeleteDomainRoutes removes the path routes of an application domain, its application serves every path again
+func (s *ApplicationDomainService) DeleteDomainRoutes(ctx context.Context, uuid string) error {
+	crd, err := s.getApplicationDomainCRD(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.updateApplicationDomainSpec(ctx, crd, func(spec *v1alpha1.ApplicationDomainSpec) {
+		spec.Routes = nil
+	})
+	return err
+}
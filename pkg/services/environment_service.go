@@ -18,7 +18,9 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -32,6 +34,9 @@ import (
 	"github.com/kibamail/kibaship/pkg/validation"
 )
 
+// ErrIdlePolicyProduction is returned when an idle policy is enabled on a production environment
+var ErrIdlePolicyProduction = errors.New("idle policy cannot be enabled on production environments")
+
 // EnvironmentService handles CRUD operations for environments
 type EnvironmentService struct {
 	client         client.Client
@@ -50,6 +55,11 @@ func NewEnvironmentService(k8sClient client.Client, scheme *runtime.Scheme, proj
 
 // CreateEnvironment creates a new environment
 func (s *EnvironmentService) CreateEnvironment(ctx context.Context, req *models.EnvironmentCreateRequest) (*models.Environment, error) {
+	return s.createEnvironment(ctx, req, nil)
+}
+
+// createEnvironment creates a new environment, customize adjusts the Environment CRD before it is created
+func (s *EnvironmentService) createEnvironment(ctx context.Context, req *models.EnvironmentCreateRequest, customize func(*v1alpha1.Environment)) (*models.Environment, error) {
 	// First, verify the project exists and get its details
 	project, err := s.projectService.GetProject(ctx, req.ProjectUUID)
 	if err != nil {
@@ -104,6 +114,10 @@ func (s *EnvironmentService) CreateEnvironment(ctx context.Context, req *models.
 
 	// Create Kubernetes Environment CRD
 	crd := s.convertToEnvironmentCRD(environment)
+	if customize != nil {
+		customize(crd)
+		environment.PullRequestURL = crd.Annotations[validation.AnnotationPullRequestURL]
+	}
 
 	err = s.client.Create(ctx, crd)
 	if err != nil {
@@ -198,6 +212,10 @@ func (s *EnvironmentService) UpdateEnvironment(ctx context.Context, uuid string,
 	// Get the existing CRD
 	existingCRD := &environmentList.Items[0]
 
+	if req.IdlePolicy != nil && req.IdlePolicy.Enabled && existingCRD.IsProduction() {
+		return nil, ErrIdlePolicyProduction
+	}
+
 	// Apply updates to annotations and spec
 	s.applyEnvironmentUpdates(existingCRD, req)
 
@@ -334,14 +352,17 @@ func (s *EnvironmentService) convertFromEnvironmentCRD(crd *v1alpha1.Environment
 	}
 
 	return &models.Environment{
-		UUID:        labels[validation.LabelResourceUUID],
-		Name:        annotations[validation.AnnotationResourceName],
-		Slug:        labels[validation.LabelResourceSlug],
-		Description: annotations[validation.AnnotationResourceDescription],
-		ProjectUUID: labels[validation.LabelProjectUUID],
-		ProjectSlug: s.extractProjectSlugFromRef(crd.Spec.ProjectRef.Name),
-		CreatedAt:   crd.CreationTimestamp.Time,
-		UpdatedAt:   crd.CreationTimestamp.Time, // Would need to track updates
+		UUID:            labels[validation.LabelResourceUUID],
+		Name:            annotations[validation.AnnotationResourceName],
+		Slug:            labels[validation.LabelResourceSlug],
+		Description:     annotations[validation.AnnotationResourceDescription],
+		ProjectUUID:     labels[validation.LabelProjectUUID],
+		ProjectSlug:     s.extractProjectSlugFromRef(crd.Spec.ProjectRef.Name),
+		IdlePolicy:      idlePolicyResponse(crd.Spec.IdlePolicy),
+		RequireApproval: crd.Spec.RequireApproval,
+		PullRequestURL:  annotations[validation.AnnotationPullRequestURL],
+		CreatedAt:       crd.CreationTimestamp.Time,
+		UpdatedAt:       crd.CreationTimestamp.Time, // Would need to track updates
 	}
 }
 
@@ -358,10 +379,34 @@ func (s *EnvironmentService) applyEnvironmentUpdates(crd *v1alpha1.Environment,
 		crd.SetAnnotations(annotations)
 	}
 
+	// Update the idle policy, an idleAfter of zero falls back to the default timeout
+	if req.IdlePolicy != nil {
+		policy := &v1alpha1.IdlePolicy{Enabled: req.IdlePolicy.Enabled}
+		if idleAfter, err := time.ParseDuration(req.IdlePolicy.IdleAfter); err == nil {
+			policy.IdleAfter = &metav1.Duration{Duration: idleAfter}
+		}
+		crd.Spec.IdlePolicy = policy
+	}
+
+	if req.RequireApproval != nil {
+		crd.Spec.RequireApproval = *req.RequireApproval
+	}
+
 	// Note: Variables are no longer stored on Environment CRD
 	// They should be managed at the Application level via secrets
 }
 
+// idlePolicyResponse converts the idle policy of an Environment CRD to the API model
+func idlePolicyResponse(policy *v1alpha1.IdlePolicy) *models.EnvironmentIdlePolicy {
+	if policy == nil {
+		return nil
+	}
+	return &models.EnvironmentIdlePolicy{
+		Enabled:   policy.Enabled,
+		IdleAfter: policy.Timeout().String(),
+	}
+}
+
 // extractProjectSlugFromRef extracts the project slug from the ProjectRef name
 // Name format: "project-{slug}-kibaship-com"
 func (s *EnvironmentService) extractProjectSlugFromRef(refName string) string {
@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ErrFreezeWindowActive is returned when a promotion is blocked by an environment freeze window
+var ErrFreezeWindowActive = errors.New("freeze window active")
+
+// GetFreezeWindows returns the freeze windows of an environment and the window active now
+func (s *EnvironmentService) GetFreezeWindows(ctx context.Context, uuid string) (*models.FreezeWindowsResponse, error) {
+	crd, err := s.getEnvironmentCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	return freezeWindowsResponse(crd, time.Now()), nil
+}
+
+// UpdateFreezeWindows replaces the freeze windows of an environment
+func (s *EnvironmentService) UpdateFreezeWindows(ctx context.Context, uuid string, req *models.FreezeWindowsUpdateRequest) (*models.FreezeWindowsResponse, error) {
+	windows := make([]v1alpha1.FreezeWindow, 0, len(req.Windows))
+	for _, window := range req.Windows {
+		duration, err := time.ParseDuration(window.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration for freeze window %s: %w", window.Name, err)
+		}
+		windows = append(windows, v1alpha1.FreezeWindow{
+			Name:     window.Name,
+			Schedule: window.Schedule,
+			Duration: metav1.Duration{Duration: duration},
+			Timezone: window.Timezone,
+			Reason:   window.Reason,
+		})
+	}
+
+	crd, err := s.getEnvironmentCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the CRD in Kubernetes with a simple conflict retry loop
+	for i := 0; i < 3; i++ {
+		crd.Spec.FreezeWindows = windows
+		if err = s.client.Update(ctx, crd); err == nil || !apierrors.IsConflict(err) {
+			break
+		}
+		var latest v1alpha1.Environment
+		if getErr := s.client.Get(ctx, client.ObjectKey{Namespace: crd.Namespace, Name: crd.Name}, &latest); getErr != nil {
+			return nil, fmt.Errorf("failed to refetch Environment for conflict resolution: %w", getErr)
+		}
+		crd = &latest
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update Environment CRD: %w", err)
+	}
+
+	return freezeWindowsResponse(crd, time.Now()), nil
+}
+
+// checkFreezeWindows returns an error wrapping ErrFreezeWindowActive when a freeze window of the
+// environment blocks promotions now
+func checkFreezeWindows(ctx context.Context, c client.Reader, environmentUUID string) error {
+	var environmentList v1alpha1.EnvironmentList
+	err := c.List(ctx, &environmentList, client.MatchingLabels{
+		validation.LabelResourceUUID: environmentUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list environments: %w", err)
+	}
+	if len(environmentList.Items) == 0 {
+		return nil
+	}
+
+	if window, until := environmentList.Items[0].ActiveFreezeWindow(time.Now()); window != nil {
+		return fmt.Errorf("%w: %s", ErrFreezeWindowActive, window.BlockedMessage(until))
+	}
+	return nil
+}
+
+// getEnvironmentCRD fetches an Environment CRD by UUID
+func (s *EnvironmentService) getEnvironmentCRD(ctx context.Context, uuid string) (*v1alpha1.Environment, error) {
+	var environmentList v1alpha1.EnvironmentList
+	err := s.client.List(ctx, &environmentList, client.MatchingLabels{
+		validation.LabelResourceUUID: uuid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	if len(environmentList.Items) == 0 {
+		return nil, fmt.Errorf("environment with UUID %s not found", uuid)
+	}
+
+	if len(environmentList.Items) > 1 {
+		return nil, fmt.Errorf("multiple environments found with UUID %s", uuid)
+	}
+
+	return &environmentList.Items[0], nil
+}
+
+// freezeWindowsResponse converts the freeze windows of an Environment CRD to the API response
+func freezeWindowsResponse(crd *v1alpha1.Environment, now time.Time) *models.FreezeWindowsResponse {
+	response := &models.FreezeWindowsResponse{
+		EnvironmentUUID: crd.GetUUID(),
+		Windows:         make([]models.FreezeWindow, 0, len(crd.Spec.FreezeWindows)),
+	}
+	for _, window := range crd.Spec.FreezeWindows {
+		response.Windows = append(response.Windows, models.FreezeWindow{
+			Name:     window.Name,
+			Schedule: window.Schedule,
+			Duration: window.Duration.Duration.String(),
+			Timezone: window.Timezone,
+			Reason:   window.Reason,
+		})
+	}
+	if window, until := crd.ActiveFreezeWindow(now); window != nil {
+		response.ActiveWindow = window.Name
+		response.ActiveUntil = &until
+	}
+	return response
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+// GetLogAlertRules returns the log alert rules of an application and their current state
+func (s *ApplicationService) GetLogAlertRules(ctx context.Context, uuid string) (*models.LogAlertRulesResponse, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewLogAlertRulesResponse(crd), nil
+}
+
+// UpdateLogAlertRules replaces the log alert rules of an application, an empty list removes them
+func (s *ApplicationService) UpdateLogAlertRules(ctx context.Context, uuid string, req *models.LogAlertRulesUpdateRequest) (*models.LogAlertRulesResponse, error) {
+	var rules []v1alpha1.LogAlertRule
+	for _, rule := range req.Rules {
+		match := v1alpha1.LogAlertMatch(rule.Match)
+		if match == "" {
+			match = v1alpha1.LogAlertMatchSubstring
+		}
+		threshold := rule.ThresholdPerMinute
+		if threshold == 0 {
+			threshold = 1
+		}
+		rules = append(rules, v1alpha1.LogAlertRule{
+			Name:               rule.Name,
+			Pattern:            rule.Pattern,
+			Match:              match,
+			ThresholdPerMinute: threshold,
+		})
+	}
+
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < 3; i++ {
+		crd.Spec.LogAlerts = rules
+		if err = s.client.Update(ctx, crd); err == nil || !apierrors.IsConflict(err) {
+			break
+		}
+		var latest v1alpha1.Application
+		if getErr := s.client.Get(ctx, client.ObjectKey{Namespace: crd.Namespace, Name: crd.Name}, &latest); getErr != nil {
+			return nil, fmt.Errorf("failed to refetch Application for conflict resolution: %w", getErr)
+		}
+		crd = &latest
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update Application CRD: %w", err)
+	}
+	return models.NewLogAlertRulesResponse(crd), nil
+}
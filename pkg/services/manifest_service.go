@@ -0,0 +1,247 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/manifest"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ErrManifestNotFound is returned when the resource of a manifest does not exist
+var ErrManifestNotFound = errors.New("resource not found")
+
+// httpRouteListGVK is the list kind of the Gateway API routes the operator generates for domains
+var httpRouteListGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRouteList"}
+
+// ManifestService exports the custom resources behind the API, and the Kubernetes objects the
+// operator generates from them, as sanitized YAML
+type ManifestService struct {
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+// NewManifestService creates a new ManifestService
+func NewManifestService(k8sClient client.Client, scheme *runtime.Scheme) *ManifestService {
+	return &ManifestService{
+		client: k8sClient,
+		scheme: scheme,
+	}
+}
+
+// GetProjectManifest returns the Project CR with the given UUID. Projects have no generated
+// children worth exporting, the environments and applications have their own manifests.
+func (s *ManifestService) GetProjectManifest(ctx context.Context, uuid string) ([]byte, error) {
+	var list v1alpha1.ProjectList
+	if err := s.listByUUID(ctx, &list, "project", uuid); err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("%w: project with UUID %s", ErrManifestNotFound, uuid)
+	}
+	return s.render(&list.Items[0])
+}
+
+// GetEnvironmentManifest returns the Environment CR with the given UUID
+func (s *ManifestService) GetEnvironmentManifest(ctx context.Context, uuid string) ([]byte, error) {
+	var list v1alpha1.EnvironmentList
+	if err := s.listByUUID(ctx, &list, "environment", uuid); err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("%w: environment with UUID %s", ErrManifestNotFound, uuid)
+	}
+	return s.render(&list.Items[0])
+}
+
+// GetApplicationManifest returns the Application CR with the given UUID. With children, the
+// Kubernetes Deployments and Services running it and the routes of its domains follow it.
+func (s *ManifestService) GetApplicationManifest(ctx context.Context, uuid string, children bool) ([]byte, error) {
+	var list v1alpha1.ApplicationList
+	if err := s.listByUUID(ctx, &list, "application", uuid); err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("%w: application with UUID %s", ErrManifestNotFound, uuid)
+	}
+	app := &list.Items[0]
+
+	objects := []client.Object{app}
+	if children {
+		selector := client.MatchingLabels{validation.LabelApplicationUUID: uuid}
+
+		var deployments appsv1.DeploymentList
+		if err := s.client.List(ctx, &deployments, client.InNamespace(app.Namespace), selector); err != nil {
+			return nil, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		for i := range deployments.Items {
+			objects = append(objects, &deployments.Items[i])
+		}
+
+		var services corev1.ServiceList
+		if err := s.client.List(ctx, &services, client.InNamespace(app.Namespace), selector); err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+		for i := range services.Items {
+			objects = append(objects, &services.Items[i])
+		}
+
+		var domains v1alpha1.ApplicationDomainList
+		if err := s.client.List(ctx, &domains, selector); err != nil {
+			return nil, fmt.Errorf("failed to list application domains: %w", err)
+		}
+		for i := range domains.Items {
+			routes, err := s.domainRoutes(ctx, &domains.Items[i])
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, routes...)
+		}
+	}
+	return s.render(objects...)
+}
+
+// GetDeploymentManifest returns the Deployment CR with the given UUID. With children, the
+// Kubernetes Deployment rolling out its image follows it.
+func (s *ManifestService) GetDeploymentManifest(ctx context.Context, uuid string, children bool) ([]byte, error) {
+	var list v1alpha1.DeploymentList
+	if err := s.listByUUID(ctx, &list, "deployment", uuid); err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("%w: deployment with UUID %s", ErrManifestNotFound, uuid)
+	}
+	deployment := &list.Items[0]
+
+	objects := []client.Object{deployment}
+	if children {
+		var deployments appsv1.DeploymentList
+		if err := s.client.List(ctx, &deployments, client.InNamespace(deployment.Namespace), client.MatchingLabels{
+			"platform.kibaship.com/deployment-uuid": uuid,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		for i := range deployments.Items {
+			objects = append(objects, &deployments.Items[i])
+		}
+	}
+	return s.render(objects...)
+}
+
+// GetApplicationDomainManifest returns the ApplicationDomain CR with the given UUID. With children,
+// the Ingress or HTTPRoutes routing it follow it.
+func (s *ManifestService) GetApplicationDomainManifest(ctx context.Context, uuid string, children bool) ([]byte, error) {
+	var list v1alpha1.ApplicationDomainList
+	if err := s.listByUUID(ctx, &list, "application domain", uuid); err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("%w: application domain with UUID %s", ErrManifestNotFound, uuid)
+	}
+	domain := &list.Items[0]
+
+	objects := []client.Object{domain}
+	if children {
+		routes, err := s.domainRoutes(ctx, domain)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, routes...)
+	}
+	return s.render(objects...)
+}
+
+// domainRoutes returns the Ingresses and HTTPRoutes the operator created for a domain, found by
+// their owner reference. HTTPRoutes are skipped on clusters without the Gateway API.
+func (s *ManifestService) domainRoutes(ctx context.Context, domain *v1alpha1.ApplicationDomain) ([]client.Object, error) {
+	var routes []client.Object
+
+	var ingresses networkingv1.IngressList
+	if err := s.client.List(ctx, &ingresses, client.InNamespace(domain.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %w", err)
+	}
+	for i := range ingresses.Items {
+		if ownedBy(&ingresses.Items[i], domain) {
+			routes = append(routes, &ingresses.Items[i])
+		}
+	}
+
+	httpRoutes := &unstructured.UnstructuredList{}
+	httpRoutes.SetGroupVersionKind(httpRouteListGVK)
+	if err := s.client.List(ctx, httpRoutes, client.InNamespace(domain.Namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return routes, nil
+		}
+		return nil, fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+	for i := range httpRoutes.Items {
+		if ownedBy(&httpRoutes.Items[i], domain) {
+			routes = append(routes, &httpRoutes.Items[i])
+		}
+	}
+	return routes, nil
+}
+
+// listByUUID lists the resources of a kind carrying the UUID label, failing on ambiguous UUIDs
+func (s *ManifestService) listByUUID(ctx context.Context, list client.ObjectList, kind, uuid string) error {
+	if err := s.client.List(ctx, list, client.MatchingLabels{validation.LabelResourceUUID: uuid}); err != nil {
+		return fmt.Errorf("failed to list %ss: %w", kind, err)
+	}
+	if meta.LenList(list) > 1 {
+		return fmt.Errorf("multiple %ss found with UUID %s", kind, uuid)
+	}
+	return nil
+}
+
+// render sanitizes objects and renders them as one YAML stream
+func (s *ManifestService) render(objects ...client.Object) ([]byte, error) {
+	rendered := make([]*unstructured.Unstructured, 0, len(objects))
+	for _, obj := range objects {
+		if u, ok := obj.(*unstructured.Unstructured); ok {
+			u = u.DeepCopy()
+			manifest.Sanitize(u)
+			rendered = append(rendered, u)
+			continue
+		}
+		u, err := manifest.FromObject(obj, s.scheme)
+		if err != nil {
+			return nil, err
+		}
+		rendered = append(rendered, u)
+	}
+	return manifest.Marshal(rendered)
+}
+
+// ownedBy reports whether owner is the controller of obj
+func ownedBy(obj, owner metav1.Object) bool {
+	ref := metav1.GetControllerOf(obj)
+	return ref != nil && ref.UID == owner.GetUID()
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+const (
+	manifestAppUUID    = "11111111-1111-1111-1111-111111111111"
+	manifestDomainUUID = "22222222-2222-2222-2222-222222222222"
+)
+
+// manifestFixtures returns an application with a domain and the objects the operator generates
+// for them, plus an unrelated Service
+func manifestFixtures() []client.Object {
+	domain := &v1alpha1.ApplicationDomain{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "domain-web",
+			Namespace: "project-ns",
+			UID:       "domain-uid",
+			Labels: map[string]string{
+				validation.LabelResourceUUID:    manifestDomainUUID,
+				validation.LabelApplicationUUID: manifestAppUUID,
+			},
+		},
+		Spec: v1alpha1.ApplicationDomainSpec{Domain: "web.example.com", Port: 3000},
+	}
+	controller := true
+	ownerRef := []metav1.OwnerReference{{
+		APIVersion: "platform.operator.kibaship.com/v1alpha1",
+		Kind:       "ApplicationDomain",
+		Name:       domain.Name,
+		UID:        domain.UID,
+		Controller: &controller,
+	}}
+	appLabels := map[string]string{validation.LabelApplicationUUID: manifestAppUUID}
+
+	httpRoute := &unstructured.Unstructured{}
+	httpRoute.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"})
+	httpRoute.SetNamespace("project-ns")
+	httpRoute.SetName("httproute-web")
+	httpRoute.SetOwnerReferences(ownerRef)
+
+	return []client.Object{
+		&v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-web",
+			Namespace: "project-ns",
+			Labels:    map[string]string{validation.LabelResourceUUID: manifestAppUUID},
+		}},
+		domain,
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "deployment-web", Namespace: "project-ns", Labels: appLabels}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "service-web", Namespace: "project-ns", Labels: appLabels}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "service-other", Namespace: "project-ns"}},
+		&networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "ingress-web", Namespace: "project-ns", OwnerReferences: ownerRef}},
+		&networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "ingress-other", Namespace: "project-ns"}},
+		httpRoute,
+	}
+}
+
+func newManifestTestScheme(t *testing.T, gatewayAPI bool) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add client-go types: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add kibaship types: %v", err)
+	}
+	if gatewayAPI {
+		scheme.AddKnownTypeWithName(httpRouteListGVK.GroupVersion().WithKind("HTTPRoute"), &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(httpRouteListGVK, &unstructured.UnstructuredList{})
+	}
+	return scheme
+}
+
+// manifestNames returns the kind/name of each document of a manifest
+func manifestNames(t *testing.T, manifest []byte) []string {
+	t.Helper()
+	var names []string
+	var kind string
+	for _, line := range strings.Split(string(manifest), "\n") {
+		if value, ok := strings.CutPrefix(line, "kind: "); ok {
+			kind = value
+		}
+		if value, ok := strings.CutPrefix(line, "  name: "); ok {
+			names = append(names, kind+"/"+value)
+		}
+	}
+	return names
+}
+
+func TestGetApplicationManifestWithChildren(t *testing.T) {
+	ctx := context.Background()
+	scheme := newManifestTestScheme(t, true)
+	objects := manifestFixtures()
+	service := NewManifestService(fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(), scheme)
+
+	manifest, err := service.GetApplicationManifest(ctx, manifestAppUUID, false)
+	if err != nil {
+		t.Fatalf("failed to get manifest: %v", err)
+	}
+	if names := manifestNames(t, manifest); len(names) != 1 || names[0] != "Application/application-web" {
+		t.Errorf("expected only the application without children, got %v", names)
+	}
+
+	manifest, err = service.GetApplicationManifest(ctx, manifestAppUUID, true)
+	if err != nil {
+		t.Fatalf("failed to get manifest with children: %v", err)
+	}
+	want := []string{
+		"Application/application-web",
+		"Deployment/deployment-web",
+		"Service/service-web",
+		"Ingress/ingress-web",
+		"HTTPRoute/httproute-web",
+	}
+	if names := manifestNames(t, manifest); strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("expected children %v, got %v", want, names)
+	}
+}
+
+func TestGetApplicationDomainManifestWithoutGatewayAPI(t *testing.T) {
+	ctx := context.Background()
+	scheme := newManifestTestScheme(t, false)
+	var objects []client.Object
+	for _, obj := range manifestFixtures() {
+		if _, ok := obj.(*unstructured.Unstructured); !ok {
+			objects = append(objects, obj)
+		}
+	}
+	service := NewManifestService(fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(), scheme)
+
+	// HTTPRoutes are skipped on clusters without the Gateway API
+	manifest, err := service.GetApplicationDomainManifest(ctx, manifestDomainUUID, true)
+	if err != nil {
+		t.Fatalf("failed to get manifest with children: %v", err)
+	}
+	want := []string{"ApplicationDomain/domain-web", "Ingress/ingress-web"}
+	if names := manifestNames(t, manifest); strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("expected children %v, got %v", want, names)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/pkg/config"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+// ErrBootstrapStatusNotFound is returned when the operator has not recorded a bootstrap yet
+var ErrBootstrapStatusNotFound = errors.New("bootstrap status not found")
+
+// PlatformService reports the state of the platform installation
+type PlatformService struct {
+	client client.Client
+}
+
+// NewPlatformService creates a new platform service
+func NewPlatformService(k8sClient client.Client) *PlatformService {
+	return &PlatformService{
+		client: k8sClient,
+	}
+}
+
+// GetBootstrapStatus returns the outcome of the bootstrap steps recorded by the operator
+func (s *PlatformService) GetBootstrapStatus(ctx context.Context) (*models.PlatformBootstrapResponse, error) {
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: config.OperatorNamespace, Name: config.BootstrapStatusConfigMapName}
+	if err := s.client.Get(ctx, key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ErrBootstrapStatusNotFound
+		}
+		return nil, fmt.Errorf("failed to get bootstrap status: %w", err)
+	}
+
+	steps, err := config.ParseBootstrapStatus(cm.Data)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewPlatformBootstrapResponse(steps), nil
+}
@@ -0,0 +1,343 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/pullrequest"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// PreviewEnvironmentService keeps an ephemeral environment per pull request. Every GitRepository
+// application tracking the base branch of the pull request is cloned into the preview environment
+// of its project and deployed from the head branch. The environment, and everything in it, is
+// deleted when the pull request closes.
+type PreviewEnvironmentService struct {
+	client             client.Client
+	environmentService *EnvironmentService
+	applicationService *ApplicationService
+	deploymentService  *DeploymentService
+}
+
+// NewPreviewEnvironmentService creates a new PreviewEnvironmentService
+func NewPreviewEnvironmentService(k8sClient client.Client, environmentService *EnvironmentService, applicationService *ApplicationService, deploymentService *DeploymentService) *PreviewEnvironmentService {
+	return &PreviewEnvironmentService{
+		client:             k8sClient,
+		environmentService: environmentService,
+		applicationService: applicationService,
+		deploymentService:  deploymentService,
+	}
+}
+
+// HandlePullRequest creates, redeploys or deletes the preview environments of a pull request
+// and returns the UUIDs of the environments it changed
+func (s *PreviewEnvironmentService) HandlePullRequest(ctx context.Context, evt *pullrequest.Event) ([]string, error) {
+	if evt.Action == pullrequest.ActionClosed {
+		return s.deletePreviewEnvironments(ctx, evt)
+	}
+
+	// Pull requests from forks would build untrusted code with the credentials of the repository
+	if evt.Fork {
+		return []string{}, nil
+	}
+
+	sources, err := s.sourceApplications(ctx, evt)
+	if err != nil {
+		return nil, err
+	}
+
+	byProject := make(map[string][]*v1alpha1.Application)
+	for _, source := range sources {
+		projectUUID := source.Labels[validation.LabelProjectUUID]
+		byProject[projectUUID] = append(byProject[projectUUID], source)
+	}
+
+	projectUUIDs := make([]string, 0, len(byProject))
+	for projectUUID := range byProject {
+		projectUUIDs = append(projectUUIDs, projectUUID)
+	}
+	sort.Strings(projectUUIDs)
+
+	environmentUUIDs := make([]string, 0, len(projectUUIDs))
+	for _, projectUUID := range projectUUIDs {
+		environment, err := s.ensurePreviewEnvironment(ctx, projectUUID, evt)
+		if err != nil {
+			return environmentUUIDs, err
+		}
+		environmentUUIDs = append(environmentUUIDs, environment.UUID)
+
+		// Clone dependencies first, so the clones of their dependents can reference them
+		previews := make(map[string]string)
+		for _, source := range dependencyOrdered(byProject[projectUUID]) {
+			preview, err := s.ensurePreviewApplication(ctx, source, environment, evt, previews)
+			if err != nil {
+				return environmentUUIDs, err
+			}
+			previews[source.Labels[validation.LabelResourceUUID]] = preview.UUID
+			if err := s.deployHead(ctx, preview, evt); err != nil {
+				return environmentUUIDs, err
+			}
+		}
+	}
+
+	return environmentUUIDs, nil
+}
+
+// sourceApplications returns the GitRepository applications built from the branch the pull
+// request merges into, preview applications excluded
+func (s *PreviewEnvironmentService) sourceApplications(ctx context.Context, evt *pullrequest.Event) ([]*v1alpha1.Application, error) {
+	var applicationList v1alpha1.ApplicationList
+	if err := s.client.List(ctx, &applicationList); err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	var sources []*v1alpha1.Application
+	for i := range applicationList.Items {
+		app := &applicationList.Items[i]
+		gitRepo := app.Spec.GitRepository
+		if app.Spec.Type != v1alpha1.ApplicationTypeGitRepository || gitRepo == nil {
+			continue
+		}
+		if app.Labels[validation.LabelPullRequest] != "" || app.DeletionTimestamp != nil {
+			continue
+		}
+		if string(gitRepo.Provider) != evt.Provider || !strings.EqualFold(gitRepo.Repository, evt.Repository) {
+			continue
+		}
+
+		branch := gitRepo.Branch
+		if branch == "" {
+			branch = evt.DefaultBranch
+		}
+		if branch == evt.BaseBranch {
+			sources = append(sources, app)
+		}
+	}
+
+	return sources, nil
+}
+
+// ensurePreviewEnvironment returns the preview environment of the pull request in the project,
+// creating it on the first event
+func (s *PreviewEnvironmentService) ensurePreviewEnvironment(ctx context.Context, projectUUID string, evt *pullrequest.Event) (*models.Environment, error) {
+	var environmentList v1alpha1.EnvironmentList
+	if err := s.client.List(ctx, &environmentList, client.MatchingLabels{
+		validation.LabelProjectUUID: projectUUID,
+		validation.LabelPullRequest: evt.Key(),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+	if len(environmentList.Items) > 0 {
+		return s.environmentService.convertFromEnvironmentCRD(&environmentList.Items[0]), nil
+	}
+
+	req := &models.EnvironmentCreateRequest{
+		ProjectUUID: projectUUID,
+		Name:        fmt.Sprintf("pr-%d", evt.Number),
+		Description: fmt.Sprintf("Preview of %s#%d", evt.Repository, evt.Number),
+	}
+	environment, err := s.environmentService.createEnvironment(ctx, req, func(crd *v1alpha1.Environment) {
+		crd.Labels[validation.LabelPullRequest] = evt.Key()
+		crd.Annotations[validation.AnnotationPullRequestNumber] = strconv.Itoa(evt.Number)
+		crd.Annotations[validation.AnnotationPullRequestURL] = evt.URL
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create preview environment: %w", err)
+	}
+	return environment, nil
+}
+
+// dependencyOrdered sorts source applications so every application comes after the applications
+// it depends on
+func dependencyOrdered(sources []*v1alpha1.Application) []*v1alpha1.Application {
+	graph := make(map[string][]string, len(sources))
+	byUUID := make(map[string]*v1alpha1.Application, len(sources))
+	for _, source := range sources {
+		uuid := source.Labels[validation.LabelResourceUUID]
+		graph[uuid] = dependencyUUIDs(source.Spec.DependsOn)
+		byUUID[uuid] = source
+	}
+
+	order, cycle := models.DependencyOrder(graph)
+	if cycle != nil {
+		return sources
+	}
+	ordered := make([]*v1alpha1.Application, len(order))
+	for i, uuid := range order {
+		ordered[i] = byUUID[uuid]
+	}
+	return ordered
+}
+
+// ensurePreviewApplication returns the clone of source in the preview environment, creating it
+// from the head branch of the pull request on the first event. Dependencies of source that were
+// cloned, keyed by source UUID in previews, become dependencies of the clone.
+func (s *PreviewEnvironmentService) ensurePreviewApplication(ctx context.Context, source *v1alpha1.Application, environment *models.Environment, evt *pullrequest.Event, previews map[string]string) (*models.Application, error) {
+	sourceUUID := source.Labels[validation.LabelResourceUUID]
+
+	var applicationList v1alpha1.ApplicationList
+	if err := s.client.List(ctx, &applicationList, client.MatchingLabels{
+		validation.LabelEnvironmentUUID:   environment.UUID,
+		validation.LabelPreviewSourceUUID: sourceUUID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+	if len(applicationList.Items) > 0 {
+		return s.applicationService.convertFromApplicationCRD(&applicationList.Items[0]), nil
+	}
+
+	clone := s.applicationService.convertFromApplicationCRD(source)
+	clone.GitRepository.Branch = evt.HeadBranch
+
+	var dependsOn []string
+	for _, dependency := range clone.DependsOn {
+		if preview, ok := previews[dependency]; ok {
+			dependsOn = append(dependsOn, preview)
+		}
+	}
+
+	req := &models.ApplicationCreateRequest{
+		Name:            clone.Name,
+		EnvironmentUUID: environment.UUID,
+		Type:            clone.Type,
+		BaseDomain:      clone.BaseDomain,
+		DependsOn:       dependsOn,
+		PlatformEnv:     &clone.PlatformEnv,
+		GitRepository:   clone.GitRepository,
+	}
+	preview, crd, err := s.applicationService.createApplication(ctx, req, func(crd *v1alpha1.Application) {
+		crd.Labels[validation.LabelPullRequest] = evt.Key()
+		crd.Labels[validation.LabelPreviewSourceUUID] = sourceUUID
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create preview of application %s: %w", sourceUUID, err)
+	}
+
+	if err := s.copyEnvSecret(ctx, source, crd); err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+// copyEnvSecret gives the preview application the environment variables of its source. The
+// Secret is created under the name the operator expects, so the operator adopts it instead of
+// creating an empty one. Values encrypted at rest are copied as they are.
+func (s *PreviewEnvironmentService) copyEnvSecret(ctx context.Context, source, preview *v1alpha1.Application) error {
+	if source.Spec.GitRepository.Env == nil {
+		return nil
+	}
+
+	var sourceSecret corev1.Secret
+	if err := s.client.Get(ctx, client.ObjectKey{Name: source.Spec.GitRepository.Env.Name, Namespace: source.Namespace}, &sourceSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get environment variables of application %s: %w", source.Name, err)
+	}
+
+	secret, err := s.applicationService.newApplicationEnvSecret(preview)
+	if err != nil {
+		return err
+	}
+	secret.Annotations = sourceSecret.Annotations
+	secret.Data = sourceSecret.Data
+
+	err = s.client.Create(ctx, secret)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create environment variables secret: %w", err)
+	}
+
+	// The operator created the Secret first, fill it unless variables were set in the meantime
+	var existing corev1.Secret
+	if err := s.client.Get(ctx, client.ObjectKeyFromObject(secret), &existing); err != nil {
+		return fmt.Errorf("failed to get environment variables secret: %w", err)
+	}
+	if len(existing.Data) > 0 {
+		return nil
+	}
+	existing.Data = sourceSecret.Data
+	if existing.Annotations == nil {
+		existing.Annotations = make(map[string]string)
+	}
+	for key, value := range sourceSecret.Annotations {
+		existing.Annotations[key] = value
+	}
+	return s.client.Update(ctx, &existing)
+}
+
+// deployHead deploys the head commit of the pull request, redelivered events of a commit
+// that is already deployed are ignored
+func (s *PreviewEnvironmentService) deployHead(ctx context.Context, preview *models.Application, evt *pullrequest.Event) error {
+	latest, err := s.deploymentService.GetLatestDeploymentByApplicationUUID(ctx, preview.UUID)
+	if err != nil {
+		return err
+	}
+	if latest != nil && latest.GitRepository != nil && latest.GitRepository.CommitSHA == evt.HeadSHA {
+		return nil
+	}
+
+	_, err = s.deploymentService.CreateDeployment(ctx, &models.DeploymentCreateRequest{
+		ApplicationUUID: preview.UUID,
+		Promote:         true,
+		GitRepository: &models.GitRepositoryDeploymentConfig{
+			CommitSHA: evt.HeadSHA,
+			Branch:    evt.HeadBranch,
+		},
+		Source: &models.DeploymentSource{PullRequestURL: evt.URL},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to deploy preview of application %s: %w", preview.UUID, err)
+	}
+	return nil
+}
+
+// deletePreviewEnvironments deletes the preview environments of the pull request. The operator
+// deletes their applications along with them.
+func (s *PreviewEnvironmentService) deletePreviewEnvironments(ctx context.Context, evt *pullrequest.Event) ([]string, error) {
+	var environmentList v1alpha1.EnvironmentList
+	if err := s.client.List(ctx, &environmentList, client.MatchingLabels{
+		validation.LabelPullRequest: evt.Key(),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	environmentUUIDs := make([]string, 0, len(environmentList.Items))
+	for i := range environmentList.Items {
+		environment := &environmentList.Items[i]
+		if err := s.client.Delete(ctx, environment); err != nil && !apierrors.IsNotFound(err) {
+			return environmentUUIDs, fmt.Errorf("failed to delete preview environment: %w", err)
+		}
+		environmentUUIDs = append(environmentUUIDs, environment.Labels[validation.LabelResourceUUID])
+	}
+
+	return environmentUUIDs, nil
+}
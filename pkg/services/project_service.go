@@ -88,6 +88,21 @@ func (s *ProjectService) CreateProject(ctx context.Context, req *models.ProjectC
 		req.ResourceProfile,
 		req.VolumeSettings,
 	)
+	project.BaseDomain = req.BaseDomain
+	if req.PrioritySettings != nil {
+		project.PrioritySettings = *req.PrioritySettings
+	}
+	if req.ErrorPages != nil {
+		project.ErrorPages = *req.ErrorPages
+	}
+	if req.Egress != nil {
+		project.Egress = *req.Egress
+	}
+	if req.Namespace != nil {
+		project.Namespace = *req.Namespace
+	}
+	project.SecurityLevel = req.SecurityLevel
+	project.RegistryQuota = req.RegistryQuota
 
 	// Create Kubernetes Project CRD
 	crd := s.convertToProjectCRD(project, req)
@@ -126,6 +141,48 @@ func (s *ProjectService) GetProject(ctx context.Context, uuid string) (*models.P
 	return project, nil
 }
 
+// GetProjectCost returns the cost estimate the operator calculated for a project
+func (s *ProjectService) GetProjectCost(ctx context.Context, uuid string) (*models.ProjectCostResponse, error) {
+	var projectList v1alpha1.ProjectList
+	err := s.client.List(ctx, &projectList, client.MatchingLabels{
+		validation.LabelResourceUUID: uuid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	if len(projectList.Items) == 0 {
+		return nil, fmt.Errorf("project with UUID %s not found", uuid)
+	}
+
+	if len(projectList.Items) > 1 {
+		return nil, fmt.Errorf("multiple projects found with UUID %s", uuid)
+	}
+
+	return models.NewProjectCostResponse(uuid, projectList.Items[0].Status.Cost), nil
+}
+
+// GetProjectUsage returns the registry storage the operator measured for a project and its quota
+func (s *ProjectService) GetProjectUsage(ctx context.Context, uuid string) (*models.ProjectUsageResponse, error) {
+	var projectList v1alpha1.ProjectList
+	err := s.client.List(ctx, &projectList, client.MatchingLabels{
+		validation.LabelResourceUUID: uuid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	if len(projectList.Items) == 0 {
+		return nil, fmt.Errorf("project with UUID %s not found", uuid)
+	}
+
+	if len(projectList.Items) > 1 {
+		return nil, fmt.Errorf("multiple projects found with UUID %s", uuid)
+	}
+
+	return models.NewProjectUsageResponse(uuid, &projectList.Items[0]), nil
+}
+
 // DeleteProject deletes a project by UUID
 func (s *ProjectService) DeleteProject(ctx context.Context, uuid string) error {
 	// First check if project exists
@@ -261,6 +318,55 @@ func (s *ProjectService) applyProjectUpdates(crd *v1alpha1.Project, req *models.
 	if req.VolumeSettings != nil && req.VolumeSettings.MaxStorageSize != "" {
 		crd.Spec.Volumes.MaxStorageSize = req.VolumeSettings.MaxStorageSize
 	}
+
+	// Update base domain; new application domains pick it up, existing ones are kept
+	if req.BaseDomain != nil {
+		crd.Spec.BaseDomain = *req.BaseDomain
+	}
+
+	// Update priority classes; pods created by later builds and deployments pick them up
+	if req.PrioritySettings != nil {
+		crd.Spec.Priority = v1alpha1.PriorityConfig{
+			BuildPriorityClassName:   req.PrioritySettings.BuildPriorityClassName,
+			RuntimePriorityClassName: req.PrioritySettings.RuntimePriorityClassName,
+		}
+	}
+
+	// Update error pages; the operator rewires the project ingresses on its next reconcile
+	if req.ErrorPages != nil {
+		crd.Spec.ErrorPages = v1alpha1.ErrorPagesConfig{
+			Image:       req.ErrorPages.Image,
+			NotFound:    req.ErrorPages.NotFound,
+			ServerError: req.ErrorPages.ServerError,
+		}
+	}
+
+	// Update egress; the operator renders or removes the project network policies on its next reconcile
+	if req.Egress != nil {
+		crd.Spec.Egress = v1alpha1.EgressConfig{
+			Restricted:     req.Egress.Restricted,
+			AllowedCIDRs:   req.Egress.AllowedCIDRs,
+			AllowedDomains: req.Egress.AllowedDomains,
+		}
+	}
+
+	// Update namespace metadata; the operator syncs the project namespace on its next reconcile
+	if req.Namespace != nil {
+		crd.Spec.Namespace = v1alpha1.NamespaceConfig{
+			Labels:      req.Namespace.Labels,
+			Annotations: req.Namespace.Annotations,
+		}
+	}
+
+	// Update the pod security level; application pods comply with it from their next deployment
+	if req.SecurityLevel != nil {
+		crd.Spec.SecurityLevel = v1alpha1.SecurityLevel(*req.SecurityLevel)
+	}
+
+	// Update registry quota; builds are checked against it from the next one
+	if req.RegistryQuota != nil {
+		crd.Spec.RegistryQuota = *req.RegistryQuota
+	}
 }
 
 // determineCurrentResourceProfile determines the resource profile from the current spec
@@ -346,6 +452,27 @@ func (s *ProjectService) convertToProjectCRD(project *models.Project, req *model
 		Spec: v1alpha1.ProjectSpec{
 			ApplicationTypes: applicationTypesConfig,
 			Volumes:          volumeConfig,
+			BaseDomain:       req.BaseDomain,
+			Priority: v1alpha1.PriorityConfig{
+				BuildPriorityClassName:   project.PrioritySettings.BuildPriorityClassName,
+				RuntimePriorityClassName: project.PrioritySettings.RuntimePriorityClassName,
+			},
+			ErrorPages: v1alpha1.ErrorPagesConfig{
+				Image:       project.ErrorPages.Image,
+				NotFound:    project.ErrorPages.NotFound,
+				ServerError: project.ErrorPages.ServerError,
+			},
+			Egress: v1alpha1.EgressConfig{
+				Restricted:     project.Egress.Restricted,
+				AllowedCIDRs:   project.Egress.AllowedCIDRs,
+				AllowedDomains: project.Egress.AllowedDomains,
+			},
+			Namespace: v1alpha1.NamespaceConfig{
+				Labels:      project.Namespace.Labels,
+				Annotations: project.Namespace.Annotations,
+			},
+			SecurityLevel: v1alpha1.SecurityLevel(project.SecurityLevel),
+			RegistryQuota: project.RegistryQuota,
 		},
 	}
 }
@@ -379,6 +506,27 @@ func (s *ProjectService) convertFromProjectCRD(crd *v1alpha1.Project) *models.Pr
 		VolumeSettings: models.VolumeSettings{
 			MaxStorageSize: crd.Spec.Volumes.MaxStorageSize,
 		},
+		BaseDomain: crd.Spec.BaseDomain,
+		PrioritySettings: models.PrioritySettings{
+			BuildPriorityClassName:   crd.Spec.Priority.BuildPriorityClassName,
+			RuntimePriorityClassName: crd.Spec.Priority.RuntimePriorityClassName,
+		},
+		ErrorPages: models.ErrorPageSettings{
+			Image:       crd.Spec.ErrorPages.Image,
+			NotFound:    crd.Spec.ErrorPages.NotFound,
+			ServerError: crd.Spec.ErrorPages.ServerError,
+		},
+		Egress: models.EgressSettings{
+			Restricted:     crd.Spec.Egress.Restricted,
+			AllowedCIDRs:   crd.Spec.Egress.AllowedCIDRs,
+			AllowedDomains: crd.Spec.Egress.AllowedDomains,
+		},
+		Namespace: models.NamespaceSettings{
+			Labels:      crd.Spec.Namespace.Labels,
+			Annotations: crd.Spec.Namespace.Annotations,
+		},
+		SecurityLevel: string(crd.Spec.SecurityLevel),
+		RegistryQuota: crd.Spec.RegistryQuota,
 		Status:        crd.Status.Phase,
 		NamespaceName: crd.Status.NamespaceName,
 		CreatedAt:     crd.CreationTimestamp.Time,
@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ProjectSummaryService aggregates the state of a project for dashboards. It reads from the
+// informer cache of the API server, so a summary costs no API server round trips.
+type ProjectSummaryService struct {
+	cache          client.Reader
+	projectService *ProjectService
+}
+
+// NewProjectSummaryService creates a new project summary service reading from cache
+func NewProjectSummaryService(cache client.Reader, projectService *ProjectService) *ProjectSummaryService {
+	return &ProjectSummaryService{
+		cache:          cache,
+		projectService: projectService,
+	}
+}
+
+// GetProjectSummary returns the application phases, incidents and latest deployments of a project
+func (s *ProjectSummaryService) GetProjectSummary(ctx context.Context, uuid string) (*models.ProjectSummaryResponse, error) {
+	if _, err := s.projectService.GetProject(ctx, uuid); err != nil {
+		return nil, err
+	}
+
+	inProject := client.MatchingLabels{validation.LabelProjectUUID: uuid}
+
+	var applications v1alpha1.ApplicationList
+	if err := s.cache.List(ctx, &applications, inProject); err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	var deployments v1alpha1.DeploymentList
+	if err := s.cache.List(ctx, &deployments, inProject); err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	var domains v1alpha1.ApplicationDomainList
+	if err := s.cache.List(ctx, &domains, inProject); err != nil {
+		return nil, fmt.Errorf("failed to list application domains: %w", err)
+	}
+
+	var pods corev1.PodList
+	if err := s.cache.List(ctx, &pods, inProject); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	return models.NewProjectSummaryResponse(uuid, applications.Items, deployments.Items, domains.Items, pods.Items), nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+// promotionHookContainer is the container of the Jobs running promotion hooks
+const promotionHookContainer = "hook"
+
+// GetPromotionHooks returns the promotion hooks of an application
+func (s *ApplicationService) GetPromotionHooks(ctx context.Context, uuid string) (*models.PromotionHooksResponse, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewPromotionHooksResponse(crd), nil
+}
+
+// UpdatePromotionHooks replaces the promotion hooks of an application, an empty request removes them
+func (s *ApplicationService) UpdatePromotionHooks(ctx context.Context, uuid string, req *models.PromotionHooksUpdateRequest) (*models.PromotionHooksResponse, error) {
+	hooks := req.ToCRD()
+
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < 3; i++ {
+		crd.Spec.Hooks = hooks
+		if err = s.client.Update(ctx, crd); err == nil || !apierrors.IsConflict(err) {
+			break
+		}
+		var latest v1alpha1.Application
+		if getErr := s.client.Get(ctx, client.ObjectKey{Namespace: crd.Namespace, Name: crd.Name}, &latest); getErr != nil {
+			return nil, fmt.Errorf("failed to refetch Application for conflict resolution: %w", getErr)
+		}
+		crd = &latest
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update Application CRD: %w", err)
+	}
+	return models.NewPromotionHooksResponse(crd), nil
+}
+
+// GetDeploymentHooks returns the promotion hooks run for a deployment with their logs.
+// Logs are left out when the API server has no access to pod logs or the hook pods were removed.
+func (s *DeploymentService) GetDeploymentHooks(ctx context.Context, uuid string) (*models.DeploymentHooksResponse, error) {
+	deployment, err := s.getDeploymentCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.DeploymentHooksResponse{
+		DeploymentUUID: uuid,
+		Hooks:          make([]models.PromotionHookRun, 0, len(deployment.Status.Hooks)),
+	}
+	for _, status := range deployment.Status.Hooks {
+		run := models.NewPromotionHookRun(status)
+		if s.pods != nil && status.JobName != "" {
+			logs, err := s.promotionHookLogs(ctx, deployment.Namespace, status.JobName)
+			if err != nil {
+				return nil, err
+			}
+			run.Logs = logs
+		}
+		response.Hooks = append(response.Hooks, run)
+	}
+	return response, nil
+}
+
+// promotionHookLogs reads the logs of the latest pod of a hook Job, empty when it has none
+func (s *DeploymentService) promotionHookLogs(ctx context.Context, namespace, jobName string) (string, error) {
+	pods, err := s.pods.Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + jobName})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods of hook %s: %w", jobName, err)
+	}
+	var latest *corev1.Pod
+	for i := range pods.Items {
+		if latest == nil || latest.CreationTimestamp.Before(&pods.Items[i].CreationTimestamp) {
+			latest = &pods.Items[i]
+		}
+	}
+	if latest == nil {
+		return "", nil
+	}
+
+	limitBytes := int64(models.MaxPromotionHookLogBytes)
+	logs, err := s.pods.Pods(namespace).GetLogs(latest.Name, &corev1.PodLogOptions{
+		Container:  promotionHookContainer,
+		LimitBytes: &limitBytes,
+	}).DoRaw(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsBadRequest(err) {
+			// Pruned, or the container has not started yet
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get logs of hook %s: %w", jobName, err)
+	}
+	return string(logs), nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ErrRegistryQuotaExceeded is returned when a build is refused because its project uses more
+// registry storage than its quota
+var ErrRegistryQuotaExceeded = errors.New("registry quota exceeded")
+
+// checkRegistryQuota returns an error wrapping ErrRegistryQuotaExceeded when the last measured
+// registry usage of the project is above its quota
+func checkRegistryQuota(ctx context.Context, c client.Reader, projectUUID string) error {
+	var projectList v1alpha1.ProjectList
+	err := c.List(ctx, &projectList, client.MatchingLabels{
+		validation.LabelResourceUUID: projectUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+	if len(projectList.Items) == 0 {
+		return nil
+	}
+
+	if project := &projectList.Items[0]; project.RegistryQuotaExceeded() {
+		return fmt.Errorf("%w: %s", ErrRegistryQuotaExceeded, project.RegistryQuotaMessage())
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// labelRegistryRobot holds the name of the robot account a Secret stores the credentials of
+const labelRegistryRobot = "platform.kibaship.com/registry-robot"
+
+var (
+	// ErrRegistryRobotExists is returned when a robot account is created twice in a project
+	ErrRegistryRobotExists = errors.New("registry robot account already exists")
+
+	// ErrRegistryRobotNotFound is returned when an unknown robot account is deleted
+	ErrRegistryRobotNotFound = errors.New("registry robot account not found")
+)
+
+// ListRegistryRobots returns the registry robot accounts of a project ordered by name
+func (s *ProjectService) ListRegistryRobots(ctx context.Context, uuid string) ([]models.RegistryRobotResponse, error) {
+	namespace, err := s.getProjectNamespace(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets corev1.SecretList
+	if err := s.client.List(ctx, &secrets, client.InNamespace(namespace), client.HasLabels{labelRegistryRobot}); err != nil {
+		return nil, fmt.Errorf("failed to list registry robot accounts: %w", err)
+	}
+
+	robots := make([]models.RegistryRobotResponse, 0, len(secrets.Items))
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		robots = append(robots, models.NewRegistryRobotResponse(secret.Labels[labelRegistryRobot], secret))
+	}
+	sort.Slice(robots, func(i, j int) bool {
+		return robots[i].Name < robots[j].Name
+	})
+	return robots, nil
+}
+
+// CreateRegistryRobot creates a robot account pulling the images of a project. The password is
+// generated and stored in a Secret of the project namespace the registry auth service validates
+// logins against; it is returned only once.
+func (s *ProjectService) CreateRegistryRobot(ctx context.Context, uuid string, req *models.RegistryRobotCreateRequest) (*models.RegistryRobotCreateResponse, error) {
+	namespace, err := s.getProjectNamespace(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := generateRegistryRobotPassword()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate registry robot password: %w", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.GetRegistryRobotSecretName(req.Name),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "kibaship",
+				"app.kubernetes.io/component":  "registry-robot",
+				labelRegistryRobot:             req.Name,
+				validation.LabelProjectUUID:    uuid,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"username": []byte(utils.GetRegistryRobotUsername(namespace, req.Name)),
+			"password": []byte(password),
+		},
+	}
+	if req.Description != "" {
+		secret.Annotations = map[string]string{models.RegistryRobotDescriptionAnnotation: req.Description}
+	}
+
+	if err := s.client.Create(ctx, secret); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("%w: %s", ErrRegistryRobotExists, req.Name)
+		}
+		return nil, fmt.Errorf("failed to create registry robot account: %w", err)
+	}
+
+	return &models.RegistryRobotCreateResponse{
+		RegistryRobotResponse: models.NewRegistryRobotResponse(req.Name, secret),
+		Password:              password,
+	}, nil
+}
+
+// DeleteRegistryRobot deletes a robot account of a project. Logins are refused once the credential
+// cache of the registry auth service expires, issued tokens expire within their TTL.
+func (s *ProjectService) DeleteRegistryRobot(ctx context.Context, uuid, name string) error {
+	namespace, err := s.getProjectNamespace(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	var secret corev1.Secret
+	key := client.ObjectKey{Namespace: namespace, Name: utils.GetRegistryRobotSecretName(name)}
+	if err := s.client.Get(ctx, key, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%w: %s", ErrRegistryRobotNotFound, name)
+		}
+		return fmt.Errorf("failed to get registry robot account: %w", err)
+	}
+	if secret.Labels[labelRegistryRobot] != name {
+		return fmt.Errorf("%w: %s", ErrRegistryRobotNotFound, name)
+	}
+
+	if err := s.client.Delete(ctx, &secret); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete registry robot account: %w", err)
+	}
+	return nil
+}
+
+// getProjectNamespace returns the namespace of a project, where its registry repositories live
+func (s *ProjectService) getProjectNamespace(ctx context.Context, uuid string) (string, error) {
+	var projectList v1alpha1.ProjectList
+	err := s.client.List(ctx, &projectList, client.MatchingLabels{
+		validation.LabelResourceUUID: uuid,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	if len(projectList.Items) == 0 {
+		return "", fmt.Errorf("project with UUID %s not found", uuid)
+	}
+
+	if namespace := projectList.Items[0].Status.NamespaceName; namespace != "" {
+		return namespace, nil
+	}
+	return utils.GetProjectResourceName(uuid), nil
+}
+
+// generateRegistryRobotPassword generates a random password for a registry robot account
+func generateRegistryRobotPassword() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
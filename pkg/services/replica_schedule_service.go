@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// GetReplicaSchedule returns the replica schedule of an application and the replicas it runs now
+func (s *ApplicationService) GetReplicaSchedule(ctx context.Context, uuid string) (*models.ReplicaScheduleResponse, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	return replicaScheduleResponse(crd, time.Now()), nil
+}
+
+// UpdateReplicaSchedule replaces the replica schedule of an application
+func (s *ApplicationService) UpdateReplicaSchedule(ctx context.Context, uuid string, req *models.ReplicaScheduleUpdateRequest) (*models.ReplicaScheduleResponse, error) {
+	schedule := &v1alpha1.ReplicaSchedule{
+		DefaultReplicas: req.DefaultReplicas,
+		Windows:         make([]v1alpha1.ReplicaWindow, 0, len(req.Windows)),
+	}
+	for _, window := range req.Windows {
+		duration, err := time.ParseDuration(window.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration for replica window %s: %w", window.Name, err)
+		}
+		schedule.Windows = append(schedule.Windows, v1alpha1.ReplicaWindow{
+			Name:     window.Name,
+			Schedule: window.Schedule,
+			Duration: metav1.Duration{Duration: duration},
+			Timezone: window.Timezone,
+			Replicas: window.Replicas,
+		})
+	}
+
+	crd, err := s.setReplicaSchedule(ctx, uuid, schedule)
+	if err != nil {
+		return nil, err
+	}
+	return replicaScheduleResponse(crd, time.Now()), nil
+}
+
+// DeleteReplicaSchedule removes the replica schedule of an application.
+// Its deployments keep their current replica count.
+func (s *ApplicationService) DeleteReplicaSchedule(ctx context.Context, uuid string) error {
+	_, err := s.setReplicaSchedule(ctx, uuid, nil)
+	return err
+}
+
+// setReplicaSchedule sets the replica schedule of an Application CRD with a simple conflict retry loop
+func (s *ApplicationService) setReplicaSchedule(ctx context.Context, uuid string, schedule *v1alpha1.ReplicaSchedule) (*v1alpha1.Application, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < 3; i++ {
+		crd.Spec.ReplicaSchedule = schedule
+		if err = s.client.Update(ctx, crd); err == nil || !apierrors.IsConflict(err) {
+			break
+		}
+		var latest v1alpha1.Application
+		if getErr := s.client.Get(ctx, client.ObjectKey{Namespace: crd.Namespace, Name: crd.Name}, &latest); getErr != nil {
+			return nil, fmt.Errorf("failed to refetch Application for conflict resolution: %w", getErr)
+		}
+		crd = &latest
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update Application CRD: %w", err)
+	}
+	return crd, nil
+}
+
+// getApplicationCRD fetches an Application CRD by UUID
+func (s *ApplicationService) getApplicationCRD(ctx context.Context, uuid string) (*v1alpha1.Application, error) {
+	var applicationList v1alpha1.ApplicationList
+	err := s.client.List(ctx, &applicationList, client.MatchingLabels{
+		validation.LabelResourceUUID: uuid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	if len(applicationList.Items) == 0 {
+		return nil, fmt.Errorf("application with UUID %s not found", uuid)
+	}
+
+	if len(applicationList.Items) > 1 {
+		return nil, fmt.Errorf("multiple applications found with UUID %s", uuid)
+	}
+
+	return &applicationList.Items[0], nil
+}
+
+// replicaScheduleResponse converts the replica schedule of an Application CRD to the API response
+func replicaScheduleResponse(crd *v1alpha1.Application, now time.Time) *models.ReplicaScheduleResponse {
+	schedule := crd.Spec.ReplicaSchedule
+	if schedule == nil {
+		schedule = &v1alpha1.ReplicaSchedule{}
+	}
+
+	window, replicas, until := schedule.ActiveWindow(now)
+	response := &models.ReplicaScheduleResponse{
+		ApplicationUUID: crd.GetUUID(),
+		DefaultReplicas: 1,
+		Windows:         make([]models.ReplicaWindow, 0, len(schedule.Windows)),
+		Replicas:        replicas,
+	}
+	if schedule.DefaultReplicas != nil {
+		response.DefaultReplicas = *schedule.DefaultReplicas
+	}
+	for _, w := range schedule.Windows {
+		response.Windows = append(response.Windows, models.ReplicaWindow{
+			Name:     w.Name,
+			Schedule: w.Schedule,
+			Duration: w.Duration.Duration.String(),
+			Timezone: w.Timezone,
+			Replicas: w.Replicas,
+		})
+	}
+	if window != nil {
+		response.ActiveWindow = window.Name
+		response.ActiveUntil = &until
+	}
+	if status := crd.Status.ReplicaSchedule; status != nil && status.LastScaleTime != nil {
+		response.LastScaleTime = &status.LastScaleTime.Time
+	}
+	return response
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/utils"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// ErrInvalidServiceBinding is returned when an application binds to an application that is not a
+// shared database of its environment, or stops sharing a database other applications bind to
+var ErrInvalidServiceBinding = errors.New("invalid service binding")
+
+// GetServiceBindings returns the service bindings of an application
+func (s *ApplicationService) GetServiceBindings(ctx context.Context, uuid string) (*models.ServiceBindingsResponse, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewServiceBindingsResponse(crd), nil
+}
+
+// UpdateServiceBindings replaces the service bindings of an application and whether it is shared.
+// Deployments created afterwards receive the connection variables of the new bindings.
+func (s *ApplicationService) UpdateServiceBindings(ctx context.Context, uuid string, req *models.ServiceBindingsUpdateRequest) (*models.ServiceBindingsResponse, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if req.Shared && !sharableApplicationType(crd.Spec.Type) {
+		return nil, fmt.Errorf("%w: %s applications cannot be shared", ErrInvalidServiceBinding, crd.Spec.Type)
+	}
+
+	var applicationList v1alpha1.ApplicationList
+	if err := s.client.List(ctx, &applicationList, client.MatchingLabels{
+		validation.LabelEnvironmentUUID: crd.Labels[validation.LabelEnvironmentUUID],
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+	if err := validateServiceBindings(crd, req, applicationList.Items); err != nil {
+		return nil, err
+	}
+
+	bindings := req.ToCRD()
+	for i := 0; i < 3; i++ {
+		crd.Spec.Shared = req.Shared
+		crd.Spec.ServiceBindings = bindings
+		if err = s.client.Update(ctx, crd); err == nil || !apierrors.IsConflict(err) {
+			break
+		}
+		var latest v1alpha1.Application
+		if getErr := s.client.Get(ctx, client.ObjectKey{Namespace: crd.Namespace, Name: crd.Name}, &latest); getErr != nil {
+			return nil, fmt.Errorf("failed to refetch Application for conflict resolution: %w", getErr)
+		}
+		crd = &latest
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update Application CRD: %w", err)
+	}
+	return models.NewServiceBindingsResponse(crd), nil
+}
+
+// validateServiceBindings checks that an application binds only to shared databases of its
+// environment, and that no application is left bound to it when it stops being shared
+func validateServiceBindings(app *v1alpha1.Application, req *models.ServiceBindingsUpdateRequest, environmentApps []v1alpha1.Application) error {
+	apps := make(map[string]*v1alpha1.Application, len(environmentApps))
+	for i := range environmentApps {
+		apps[environmentApps[i].Name] = &environmentApps[i]
+	}
+
+	for _, binding := range req.Bindings {
+		name := utils.GetApplicationResourceName(binding.ApplicationUUID)
+		target, ok := apps[name]
+		switch {
+		case name == app.Name:
+			return fmt.Errorf("%w: an application cannot bind to itself", ErrInvalidServiceBinding)
+		case !ok:
+			return fmt.Errorf("%w: application %s is not in environment %s",
+				ErrInvalidServiceBinding, binding.ApplicationUUID, app.Labels[validation.LabelEnvironmentUUID])
+		case !target.Spec.Shared:
+			return fmt.Errorf("%w: application %s is not shared", ErrInvalidServiceBinding, binding.ApplicationUUID)
+		}
+	}
+
+	if req.Shared || !app.Spec.Shared {
+		return nil
+	}
+	for i := range environmentApps {
+		for _, binding := range environmentApps[i].Spec.ServiceBindings {
+			if binding.Application.Name == app.Name {
+				return fmt.Errorf("%w: application %s is still bound to it",
+					ErrInvalidServiceBinding, environmentApps[i].GetUUID())
+			}
+		}
+	}
+	return nil
+}
+
+// sharableApplicationType reports whether applications of a type can be shared, only databases
+// with per-user credentials can
+func sharableApplicationType(t v1alpha1.ApplicationType) bool {
+	switch t {
+	case v1alpha1.ApplicationTypeMySQL, v1alpha1.ApplicationTypeMySQLCluster,
+		v1alpha1.ApplicationTypePostgres, v1alpha1.ApplicationTypePostgresCluster:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+)
+
+// ErrSessionAffinityNotSupported is returned when an application does not serve traffic through
+// the ingress
+var ErrSessionAffinityNotSupported = errors.New("session affinity is not supported")
+
+// GetSessionAffinity returns the session affinity of an application
+func (s *ApplicationService) GetSessionAffinity(ctx context.Context, uuid string) (*models.SessionAffinityResponse, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewSessionAffinityResponse(crd), nil
+}
+
+// UpdateSessionAffinity pins each client of an application to one replica with a cookie. The
+// routes of its domains are updated by the operator.
+func (s *ApplicationService) UpdateSessionAffinity(ctx context.Context, uuid string, req *models.SessionAffinityUpdateRequest) (*models.SessionAffinityResponse, error) {
+	crd, err := s.setSessionAffinity(ctx, uuid, req.ToCRD())
+	if err != nil {
+		return nil, err
+	}
+	return models.NewSessionAffinityResponse(crd), nil
+}
+
+// DeleteSessionAffinity removes the session affinity of an application, clients are spread over
+// its replicas again
+func (s *ApplicationService) DeleteSessionAffinity(ctx context.Context, uuid string) error {
+	_, err := s.setSessionAffinity(ctx, uuid, nil)
+	return err
+}
+
+// setSessionAffinity sets the session affinity of an Application CRD with a simple conflict retry loop
+func (s *ApplicationService) setSessionAffinity(ctx context.Context, uuid string, affinity *v1alpha1.SessionAffinity) (*v1alpha1.Application, error) {
+	crd, err := s.getApplicationCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < 3; i++ {
+		if affinity != nil {
+			switch crd.Spec.Type {
+			case v1alpha1.ApplicationTypeGitRepository, v1alpha1.ApplicationTypeDockerImage, v1alpha1.ApplicationTypeImageFromRegistry:
+			default:
+				return nil, fmt.Errorf("%w: %s applications are not served through the ingress", ErrSessionAffinityNotSupported, crd.Spec.Type)
+			}
+		}
+
+		crd.Spec.SessionAffinity = affinity
+		if err = s.client.Update(ctx, crd); err == nil || !apierrors.IsConflict(err) {
+			break
+		}
+		var latest v1alpha1.Application
+		if getErr := s.client.Get(ctx, client.ObjectKey{Namespace: crd.Namespace, Name: crd.Name}, &latest); getErr != nil {
+			return nil, fmt.Errorf("failed to refetch Application for conflict resolution: %w", getErr)
+		}
+		crd = &latest
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update Application CRD: %w", err)
+	}
+	return crd, nil
+}
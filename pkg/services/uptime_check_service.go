@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/models"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+// UpdateUptimeCheck enables or replaces the uptime check of an application domain
+func (s *ApplicationDomainService) UpdateUptimeCheck(ctx context.Context, uuid string, req *models.UptimeCheck) (*models.ApplicationDomain, error) {
+	check := &v1alpha1.UptimeCheck{
+		Path:             req.Path,
+		ExpectedStatus:   req.ExpectedStatus,
+		FailureThreshold: req.FailureThreshold,
+	}
+	if req.Interval != "" {
+		interval, err := time.ParseDuration(req.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid uptime check interval: %w", err)
+		}
+		check.Interval = metav1.Duration{Duration: interval}
+	}
+	if req.Timeout != "" {
+		timeout, err := time.ParseDuration(req.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid uptime check timeout: %w", err)
+		}
+		check.Timeout = metav1.Duration{Duration: timeout}
+	}
+
+	crd, err := s.setUptimeCheck(ctx, uuid, check)
+	if err != nil {
+		return nil, err
+	}
+	return s.toApplicationDomain(ctx, crd)
+}
+
+// DeleteUptimeCheck disables the uptime check of an application domain
+func (s *ApplicationDomainService) DeleteUptimeCheck(ctx context.Context, uuid string) error {
+	_, err := s.setUptimeCheck(ctx, uuid, nil)
+	return err
+}
+
+// GetUptimeStatus returns the uptime status of an application domain, nil when it has no uptime check
+func (s *ApplicationDomainService) GetUptimeStatus(ctx context.Context, uuid string) (*models.UptimeStatus, error) {
+	crd, err := s.getApplicationDomainCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if crd.Spec.UptimeCheck == nil {
+		return nil, nil
+	}
+	if crd.Status.Uptime == nil {
+		return &models.UptimeStatus{State: string(v1alpha1.UptimeStateUnknown)}, nil
+	}
+	return models.UptimeStatusFromCRD(crd.Status.Uptime), nil
+}
+
+// setUptimeCheck sets the uptime check of an ApplicationDomain CRD
+func (s *ApplicationDomainService) setUptimeCheck(ctx context.Context, uuid string, check *v1alpha1.UptimeCheck) (*v1alpha1.ApplicationDomain, error) {
+	crd, err := s.getApplicationDomainCRD(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateApplicationDomainSpec(ctx, crd, func(spec *v1alpha1.ApplicationDomainSpec) {
+		spec.UptimeCheck = check
+	})
+}
+
+// updateApplicationDomainSpec applies mutate to the spec of an ApplicationDomain CRD with a simple conflict retry loop
+func (s *ApplicationDomainService) updateApplicationDomainSpec(
+	ctx context.Context,
+	crd *v1alpha1.ApplicationDomain,
+	mutate func(spec *v1alpha1.ApplicationDomainSpec),
+) (*v1alpha1.ApplicationDomain, error) {
+	var err error
+	for i := 0; i < 3; i++ {
+		mutate(&crd.Spec)
+		if err = s.client.Update(ctx, crd); err == nil || !apierrors.IsConflict(err) {
+			break
+		}
+		var latest v1alpha1.ApplicationDomain
+		if getErr := s.client.Get(ctx, client.ObjectKey{Namespace: crd.Namespace, Name: crd.Name}, &latest); getErr != nil {
+			return nil, fmt.Errorf("failed to refetch ApplicationDomain for conflict resolution: %w", getErr)
+		}
+		crd = &latest
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update ApplicationDomain CRD: %w", err)
+	}
+	return crd, nil
+}
+
+// getApplicationDomainCRD fetches an ApplicationDomain CRD by UUID
+func (s *ApplicationDomainService) getApplicationDomainCRD(ctx context.Context, uuid string) (*v1alpha1.ApplicationDomain, error) {
+	var domainList v1alpha1.ApplicationDomainList
+	err := s.client.List(ctx, &domainList, client.MatchingLabels{
+		validation.LabelResourceUUID: uuid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list application domains: %w", err)
+	}
+
+	if len(domainList.Items) == 0 {
+		return nil, fmt.Errorf("application domain with UUID %s not found", uuid)
+	}
+
+	if len(domainList.Items) > 1 {
+		return nil, fmt.Errorf("multiple application domains found with UUID %s", uuid)
+	}
+
+	return &domainList.Items[0], nil
+}
+
+// toApplicationDomain converts an ApplicationDomain CRD to the internal model
+func (s *ApplicationDomainService) toApplicationDomain(ctx context.Context, crd *v1alpha1.ApplicationDomain) (*models.ApplicationDomain, error) {
+	application, err := s.getApplicationByUUID(ctx, crd.GetLabels()[validation.LabelApplicationUUID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	applicationDomain := &models.ApplicationDomain{}
+	applicationDomain.ConvertFromCRD(crd, application.Slug)
+	return applicationDomain, nil
+}
@@ -0,0 +1,43 @@
+package uptime
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+)
+
+// Badge colors
+const (
+	ColorUp      = "#4c1"
+	ColorDown    = "#e05d44"
+	ColorUnknown = "#9f9f9f"
+)
+
+// charWidth approximates the width of a character of 11px Verdana, the badge font
+const charWidth = 7
+
+// Badge renders a flat two part status badge in the style of shields.io
+func Badge(label, message, color string) []byte {
+	labelWidth := len(label)*charWidth + 10
+	messageWidth := len(message)*charWidth + 10
+	width := labelWidth + messageWidth
+
+	label = html.EscapeString(label)
+	message = html.EscapeString(message)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`, width, label, message)
+	fmt.Fprintf(&b, `<title>%s: %s</title>`, label, message)
+	b.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&b, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	b.WriteString(`<g clip-path="url(#r)">`)
+	fmt.Fprintf(&b, `<rect width="%d" height="20" fill="#555"/>`, labelWidth)
+	fmt.Fprintf(&b, `<rect x="%d" width="%d" height="20" fill="%s"/>`, labelWidth, messageWidth, color)
+	fmt.Fprintf(&b, `<rect width="%d" height="20" fill="url(#s)"/>`, width)
+	b.WriteString(`</g>`)
+	b.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, labelWidth/2, label)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, labelWidth+messageWidth/2, message)
+	b.WriteString(`</g></svg>`)
+	return b.Bytes()
+}
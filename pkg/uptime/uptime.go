@@ -0,0 +1,92 @@
+// Package uptime probes application domains over HTTP and renders status badges.
+package uptime
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultInterval is how often a domain is probed when the check sets no interval
+	DefaultInterval = time.Minute
+
+	// MinInterval is the shortest supported probe interval
+	MinInterval = 30 * time.Second
+
+	// DefaultTimeout bounds a probe when the check sets no timeout
+	DefaultTimeout = 10 * time.Second
+
+	// DefaultFailureThreshold is the number of consecutive failed probes after which a domain is down
+	DefaultFailureThreshold = 3
+
+	// maxBodyRead is how much of a response body is drained so connections can be reused
+	maxBodyRead = 64 * 1024
+)
+
+// Result is the outcome of a single probe
+type Result struct {
+	// StatusCode is the HTTP status returned, 0 when the request failed
+	StatusCode int
+
+	// ResponseTime is how long the request took
+	ResponseTime time.Duration
+
+	// Err is the error of a failed request or an unexpected status
+	Err error
+}
+
+// Up reports whether the probe succeeded
+func (r Result) Up() bool {
+	return r.Err == nil
+}
+
+// NewHTTPClient returns the client probes are sent with. It does not follow redirects so the
+// expected status can match a 3xx, and it skips certificate verification so domains whose
+// certificate is still being issued are reported on their availability alone.
+func NewHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Probe sends a GET request to url and checks the response status. With expectedStatus 0 any
+// status below 400 is accepted.
+func Probe(ctx context.Context, c *http.Client, url string, expectedStatus int, timeout time.Duration) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Result{Err: err}
+	}
+	req.Header.Set("User-Agent", "kibaship-uptime/1.0")
+
+	start := time.Now()
+	resp, err := c.Do(req)
+	if err != nil {
+		return Result{ResponseTime: time.Since(start), Err: err}
+	}
+	_, _ = io.CopyN(io.Discard, resp.Body, maxBodyRead)
+	_ = resp.Body.Close()
+
+	result := Result{StatusCode: resp.StatusCode, ResponseTime: time.Since(start)}
+	switch {
+	case expectedStatus != 0 && resp.StatusCode != expectedStatus:
+		result.Err = fmt.Errorf("unexpected status %d, expected %d", resp.StatusCode, expectedStatus)
+	case expectedStatus == 0 && resp.StatusCode >= 400:
+		result.Err = fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return result
+}
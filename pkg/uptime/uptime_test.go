@@ -0,0 +1,63 @@
+package uptime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestProbe(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			w.WriteHeader(http.StatusOK)
+		case "/moved":
+			http.Redirect(w, r, "/healthz", http.StatusFound)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	c := NewHTTPClient()
+
+	result := Probe(ctx, c, server.URL+"/healthz", 0, time.Second)
+	g.Expect(result.Up()).To(BeTrue())
+	g.Expect(result.StatusCode).To(Equal(http.StatusOK))
+
+	// Redirects are not followed so a 3xx can be expected
+	result = Probe(ctx, c, server.URL+"/moved", http.StatusFound, time.Second)
+	g.Expect(result.Up()).To(BeTrue())
+
+	result = Probe(ctx, c, server.URL+"/moved", http.StatusOK, time.Second)
+	g.Expect(result.Up()).To(BeFalse())
+	g.Expect(result.StatusCode).To(Equal(http.StatusFound))
+
+	result = Probe(ctx, c, server.URL+"/down", 0, time.Second)
+	g.Expect(result.Up()).To(BeFalse())
+	g.Expect(result.Err.Error()).To(ContainSubstring("unexpected status 503"))
+
+	result = Probe(ctx, c, server.URL+"/slow", 0, 50*time.Millisecond)
+	g.Expect(result.Up()).To(BeFalse())
+	g.Expect(result.StatusCode).To(BeZero())
+}
+
+func TestBadge(t *testing.T) {
+	g := NewWithT(t)
+
+	svg := string(Badge("uptime", "up", ColorUp))
+	g.Expect(svg).To(HavePrefix("<svg"))
+	g.Expect(svg).To(ContainSubstring(`aria-label="uptime: up"`))
+	g.Expect(svg).To(ContainSubstring(`fill="#4c1"`))
+
+	g.Expect(string(Badge("uptime", "<down>", ColorDown))).To(ContainSubstring("&lt;down&gt;"))
+}
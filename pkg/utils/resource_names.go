@@ -16,7 +16,10 @@ limitations under the License.
 
 package utils
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Resource naming conventions for CRDs and associated Kubernetes resources.
 // These functions ensure consistent naming across all resources.
@@ -37,12 +40,41 @@ func GetApplicationResourceName(uuid string) string {
 	return fmt.Sprintf("application-%s", uuid)
 }
 
+// GetApplicationUUID returns the UUID of an Application from its resource name
+func GetApplicationUUID(resourceName string) string {
+	return strings.TrimPrefix(resourceName, "application-")
+}
+
+// GetApplicationEnvVersionName returns the name of the Secret holding a recorded version of an
+// application's env vars
+func GetApplicationEnvVersionName(applicationUUID string, version int) string {
+	return fmt.Sprintf("application-%s-env-v%d", applicationUUID, version)
+}
+
+// GetExternalEnvSecretName returns the name of the Secret the External Secrets Operator
+// syncs an application's external env vars into
+func GetExternalEnvSecretName(applicationUUID string) string {
+	return fmt.Sprintf("application-%s-external", applicationUUID)
+}
+
 // GetDeploymentResourceName returns the standard name for a Deployment resource
 // This name is used for the Deployment CR, its associated secret, and the Kubernetes Deployment
 func GetDeploymentResourceName(uuid string) string {
 	return fmt.Sprintf("deployment-%s", uuid)
 }
 
+// GetBuildEnvSecretName returns the name of the secret passing the build environment variables of
+// an application to the builds of a deployment
+func GetBuildEnvSecretName(deploymentUUID string) string {
+	return fmt.Sprintf("deployment-%s-build-env", deploymentUUID)
+}
+
+// GetApplicationBuildEnvSecretName returns the name of the secret holding the build environment
+// variables of an application
+func GetApplicationBuildEnvSecretName(appUUID string) string {
+	return fmt.Sprintf("application-%s-build-env", appUUID)
+}
+
 // GetApplicationDomainResourceName returns the standard name for an ApplicationDomain resource
 func GetApplicationDomainResourceName(uuid string) string {
 	return fmt.Sprintf("domain-%s", uuid)
@@ -60,6 +92,18 @@ func GetValkeyClusterResourceName(uuid string) string {
 	return fmt.Sprintf("valkey-cluster-%s", uuid)
 }
 
+// GetPostgresResourceName returns the standard name for a Postgres resource
+// This name is used for both the Postgres CR and its associated secret
+func GetPostgresResourceName(uuid string) string {
+	return fmt.Sprintf("postgres-%s", uuid)
+}
+
+// GetPostgresClusterResourceName returns the standard name for a PostgresCluster resource
+// This name is used for both the PostgresCluster CR and its associated secret
+func GetPostgresClusterResourceName(uuid string) string {
+	return fmt.Sprintf("postgres-cluster-%s", uuid)
+}
+
 // MySQL uses a unique slug instead of UUID due to name length limits
 
 // GetMySQLResourceName returns the standard name for a MySQL resource
@@ -85,3 +129,70 @@ func GetServiceName(applicationUUID string) string {
 func GetKubernetesDeploymentName(deploymentUUID string) string {
 	return GetDeploymentResourceName(deploymentUUID)
 }
+
+// GetBuiltImageName returns the in-cluster registry image a deployment's pipeline pushes, pinned
+// to digest when it is known
+func GetBuiltImageName(namespace, applicationUUID, deploymentUUID, digest string) string {
+	image := fmt.Sprintf("registry.registry.svc.cluster.local/%s/%s:%s", namespace, applicationUUID, deploymentUUID)
+	if digest != "" {
+		image += "@" + digest
+	}
+	return image
+}
+
+// GetServiceAliasName returns the name of the Service other applications of the environment reach
+// an application through, following its promoted deployment
+func GetServiceAliasName(applicationSlug string) string {
+	return fmt.Sprintf("app-%s", applicationSlug)
+}
+
+// GetServiceAliasHost returns the in-cluster host name of the Service alias of an application
+func GetServiceAliasHost(applicationSlug, namespace string) string {
+	return fmt.Sprintf("%s.%s.svc", GetServiceAliasName(applicationSlug), namespace)
+}
+
+// GetServiceDiscoveryConfigMapName returns the name of the ConfigMap holding the host and port
+// variables of the applications of an environment
+func GetServiceDiscoveryConfigMapName(environmentUUID string) string {
+	return fmt.Sprintf("environment-%s-services", environmentUUID)
+}
+
+// GetServiceDiscoveryEnvPrefix returns the prefix of the <PREFIX>_HOST and <PREFIX>_PORT variables
+// of an application: its slug uppercased with dashes replaced by underscores, prefixed with APP_
+// when the slug starts with a digit
+func GetServiceDiscoveryEnvPrefix(applicationSlug string) string {
+	prefix := strings.ToUpper(strings.ReplaceAll(applicationSlug, "-", "_"))
+	if prefix != "" && prefix[0] >= '0' && prefix[0] <= '9' {
+		prefix = "APP_" + prefix
+	}
+	return prefix
+}
+
+// registryRobotUsernamePrefix marks the usernames of registry robot accounts
+const registryRobotUsernamePrefix = "robot$"
+
+// GetRegistryRobotSecretName returns the name of the Secret in a project namespace holding the
+// credentials of a registry robot account
+func GetRegistryRobotSecretName(robotName string) string {
+	return fmt.Sprintf("registry-robot-%s", robotName)
+}
+
+// GetRegistryRobotUsername returns the username a registry robot account logs in with:
+// robot$<namespace>+<name>
+func GetRegistryRobotUsername(namespace, robotName string) string {
+	return registryRobotUsernamePrefix + namespace + "+" + robotName
+}
+
+// ParseRegistryRobotUsername returns the namespace and name of a registry robot account username,
+// ok is false when the username does not belong to a robot account
+func ParseRegistryRobotUsername(username string) (namespace, robotName string, ok bool) {
+	rest, found := strings.CutPrefix(username, registryRobotUsernamePrefix)
+	if !found {
+		return "", "", false
+	}
+	namespace, robotName, found = strings.Cut(rest, "+")
+	if !found || namespace == "" || robotName == "" {
+		return "", "", false
+	}
+	return namespace, robotName, true
+}
@@ -47,6 +47,32 @@ func TestGetApplicationResourceName(t *testing.T) {
 	}
 }
 
+func TestGetApplicationUUID(t *testing.T) {
+	expected := "550e8400-e29b-41d4-a716-446655440002"
+	result := GetApplicationUUID(GetApplicationResourceName(expected))
+	if result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
+
+func TestGetApplicationEnvVersionName(t *testing.T) {
+	uuid := "550e8400-e29b-41d4-a716-446655440002"
+	expected := "application-550e8400-e29b-41d4-a716-446655440002-env-v3"
+	result := GetApplicationEnvVersionName(uuid, 3)
+	if result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
+
+func TestGetExternalEnvSecretName(t *testing.T) {
+	uuid := "550e8400-e29b-41d4-a716-446655440002"
+	expected := "application-550e8400-e29b-41d4-a716-446655440002-external"
+	result := GetExternalEnvSecretName(uuid)
+	if result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
+
 func TestGetDeploymentResourceName(t *testing.T) {
 	uuid := "550e8400-e29b-41d4-a716-446655440003"
 	expected := "deployment-550e8400-e29b-41d4-a716-446655440003"
@@ -79,6 +105,24 @@ func TestGetValkeyClusterResourceName(t *testing.T) {
 	t.Skip("Valkey cluster resource name test removed - TODO: implement new test")
 }
 
+func TestGetPostgresResourceName(t *testing.T) {
+	uuid := "550e8400-e29b-41d4-a716-446655440006"
+	expected := "postgres-550e8400-e29b-41d4-a716-446655440006"
+	result := GetPostgresResourceName(uuid)
+	if result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
+
+func TestGetPostgresClusterResourceName(t *testing.T) {
+	uuid := "550e8400-e29b-41d4-a716-446655440007"
+	expected := "postgres-cluster-550e8400-e29b-41d4-a716-446655440007"
+	result := GetPostgresClusterResourceName(uuid)
+	if result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
+
 func TestGetMySQLResourceName(t *testing.T) {
 	t.Skip("MySQL resource name test removed - TODO: implement new test")
 }
@@ -109,3 +153,56 @@ func TestGetKubernetesDeploymentName(t *testing.T) {
 		t.Errorf("Expected %s, got %s", expected, result)
 	}
 }
+
+func TestGetBuiltImageName(t *testing.T) {
+	expected := "registry.registry.svc.cluster.local/default/550e8400-e29b-41d4-a716-446655440002:550e8400-e29b-41d4-a716-446655440003"
+	result := GetBuiltImageName("default", "550e8400-e29b-41d4-a716-446655440002", "550e8400-e29b-41d4-a716-446655440003", "")
+	if result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+
+	expected += "@sha256:abc"
+	result = GetBuiltImageName("default", "550e8400-e29b-41d4-a716-446655440002", "550e8400-e29b-41d4-a716-446655440003", "sha256:abc")
+	if result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
+
+func TestGetServiceAliasHost(t *testing.T) {
+	expected := "app-web12345.project-550e8400.svc"
+	result := GetServiceAliasHost("web12345", "project-550e8400")
+	if result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
+
+func TestGetServiceDiscoveryEnvPrefix(t *testing.T) {
+	tests := map[string]string{
+		"web12345": "WEB12345",
+		"my-api":   "MY_API",
+		"9x7kq2ab": "APP_9X7KQ2AB",
+	}
+	for slug, expected := range tests {
+		if result := GetServiceDiscoveryEnvPrefix(slug); result != expected {
+			t.Errorf("GetServiceDiscoveryEnvPrefix(%q): expected %s, got %s", slug, expected, result)
+		}
+	}
+}
+
+func TestRegistryRobotUsername(t *testing.T) {
+	username := GetRegistryRobotUsername("project-550e8400-e29b-41d4-a716-446655440000", "ci")
+	if username != "robot$project-550e8400-e29b-41d4-a716-446655440000+ci" {
+		t.Errorf("unexpected username %s", username)
+	}
+
+	namespace, name, ok := ParseRegistryRobotUsername(username)
+	if !ok || namespace != "project-550e8400-e29b-41d4-a716-446655440000" || name != "ci" {
+		t.Errorf("expected to parse the username back, got %s %s %v", namespace, name, ok)
+	}
+
+	for _, invalid := range []string{"project-550e8400-e29b-41d4-a716-446655440000", "robot$ci", "robot$+ci", "robot$project-a+"} {
+		if _, _, ok := ParseRegistryRobotUsername(invalid); ok {
+			t.Errorf("expected %s not to be a robot username", invalid)
+		}
+	}
+}
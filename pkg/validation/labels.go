@@ -37,11 +37,56 @@ const (
 	LabelApplicationUUID = "platform.kibaship.com/application-uuid"
 	// LabelDeploymentUUID is the label key for deployment UUID (for ApplicationDomains)
 	LabelDeploymentUUID = "platform.kibaship.com/deployment-uuid"
+	// LabelShard is the label key assigning a Project, and its namespace, to an operator shard
+	LabelShard = "platform.kibaship.com/shard"
+	// LabelPullRequest is the label key for the provider pull request a preview Environment and its Applications belong to
+	LabelPullRequest = "platform.kibaship.com/pull-request"
+	// LabelPreviewSourceUUID is the label key for the UUID of the Application a preview Application is cloned from
+	LabelPreviewSourceUUID = "platform.kibaship.com/preview-source-uuid"
 
 	// AnnotationResourceName is the annotation key for resource display name
 	AnnotationResourceName = "platform.kibaship.com/name"
+	// AnnotationAllowDomainConflict is the admin annotation letting an ApplicationDomain claim a hostname
+	// another ApplicationDomain already claims, when set to "true"
+	AnnotationAllowDomainConflict = "platform.kibaship.com/allow-domain-conflict"
+	// GroupDomainAdmins is the group whose members may set AnnotationAllowDomainConflict, besides
+	// the cluster admins of system:masters
+	GroupDomainAdmins = "kibaship:domain-admins"
 	// AnnotationResourceDescription is the annotation key for resource description
 	AnnotationResourceDescription = "platform.kibaship.com/description"
+	// AnnotationPullRequestNumber is the annotation key for the pull request number of a preview Environment
+	AnnotationPullRequestNumber = "platform.kibaship.com/pull-request-number"
+	// AnnotationPullRequestURL is the annotation key for the web page of the pull request of a preview Environment or a Deployment
+	AnnotationPullRequestURL = "platform.kibaship.com/pull-request-url"
+	// AnnotationCommitMessage is the annotation key for the commit message of the source a Deployment is built from
+	AnnotationCommitMessage = "platform.kibaship.com/commit-message"
+	// AnnotationCommitAuthor is the annotation key for the commit author of the source a Deployment is built from
+	AnnotationCommitAuthor = "platform.kibaship.com/commit-author"
+	// AnnotationPromotedAt is the annotation key for the RFC 3339 time a Deployment was promoted
+	AnnotationPromotedAt = "platform.kibaship.com/promoted-at"
+	// AnnotationPromotedBy is the annotation key for who promoted a Deployment, a user or the operator
+	AnnotationPromotedBy = "platform.kibaship.com/promoted-by"
+	// AnnotationEnvVersion is the annotation key for the version number of an application env version Secret
+	AnnotationEnvVersion = "platform.kibaship.com/env-version"
+	// AnnotationEnvVersionHash is the annotation key for the hash of the env var values of an application env version Secret
+	AnnotationEnvVersionHash = "platform.kibaship.com/env-version-hash"
+	// AnnotationEnvChangedBy is the annotation key for who changed the env vars of an application env version Secret
+	AnnotationEnvChangedBy = "platform.kibaship.com/env-changed-by"
+	// AnnotationEnvRestoredFrom is the annotation key for the version an application env version was rolled back to
+	AnnotationEnvRestoredFrom = "platform.kibaship.com/env-restored-from"
+	// AnnotationCredentialsRevealedAt is the annotation key for the RFC 3339 time the credentials of a database Secret were revealed through the API
+	AnnotationCredentialsRevealedAt = "platform.kibaship.com/credentials-revealed-at"
+
+	// AnnotationFaultPipelineRunFailure is the annotation key failing the PipelineRun of a Deployment, its value is the failure message.
+	// Like every fault annotation it is only honored by operators running with fault injection enabled.
+	AnnotationFaultPipelineRunFailure = "fault.kibaship.com/pipelinerun-failure"
+	// AnnotationFaultReadinessDelay is the annotation key for the duration, such as 90s, the pods of a Deployment are reported unready after it is created
+	AnnotationFaultReadinessDelay = "fault.kibaship.com/readiness-delay"
+	// AnnotationFaultCertificateFailure is the annotation key failing the certificate issuance of an ApplicationDomain, its value is the failure message
+	AnnotationFaultCertificateFailure = "fault.kibaship.com/certificate-failure"
+
+	// PromotedByOperator is the AnnotationPromotedBy value of Deployments promoted by the operator
+	PromotedByOperator = "operator"
 )
 
 // ValidateUUID validates that a string is a valid UUID format
@@ -57,6 +102,11 @@ func ValidateSlug(slug string) bool {
 	return slugRegex.MatchString(slug)
 }
 
+// ValidateShard validates that a string is a valid operator shard name
+func ValidateShard(shard string) bool {
+	return len(shard) <= 63 && ValidateSlug(shard)
+}
+
 // GenerateUUID generates a new UUID string
 func GenerateUUID() string {
 	return uuid.New().String()
@@ -0,0 +1,113 @@
+package webhooks
+
+import (
+	"context"
+	"sync/atomic"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// enricher adds the resources related to an event, read through a cache-backed reader, before a
+// notifier delivers it. Resources missing from the cache are left out, enrichment never fails a
+// notification.
+type enricher struct {
+	reader client.Reader // cache-backed reader for enrichment
+	// enrichDeployments adds the deployment context to deployment events, it can change on
+	// configuration reload
+	enrichDeployments atomic.Bool
+}
+
+// SetEnrichDeployments sets whether deployment events carry the project, environment and
+// application of the deployment, read through the cache-backed reader.
+// It is safe to call while notifications are being sent.
+func (e *enricher) SetEnrichDeployments(enabled bool) {
+	e.enrichDeployments.Store(enabled)
+}
+
+// enrichApplicationDomainEvent adds the Certificate of the domain when available
+func (e *enricher) enrichApplicationDomainEvent(ctx context.Context, evt *ApplicationDomainStatusEvent) {
+	ref := evt.ApplicationDomain.Status.CertificateRef
+	if e.reader != nil && ref != nil && ref.Name != "" && ref.Namespace != "" {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"})
+		_ = e.reader.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: ref.Namespace}, u)
+		if len(u.Object) > 0 {
+			evt.Certificate = u.Object
+		}
+	}
+}
+
+// enrichDeploymentEvent adds the latest PipelineRun of the deployment when available and not
+// already provided
+func (e *enricher) enrichDeploymentEvent(ctx context.Context, evt *DeploymentStatusEvent) {
+	if e.reader != nil && evt.PipelineRun == nil {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(schema.GroupVersionKind{Group: "tekton.dev", Version: "v1", Kind: "PipelineRunList"})
+		_ = e.reader.List(ctx, list,
+			client.InNamespace(evt.Deployment.Namespace),
+			client.MatchingLabels(map[string]string{"deployment.kibaship.com/name": evt.Deployment.Name}),
+		)
+		if len(list.Items) > 0 {
+			// pick newest by creationTimestamp
+			latest := list.Items[0]
+			for _, it := range list.Items[1:] {
+				if it.GetCreationTimestamp().After(latest.GetCreationTimestamp().Time) {
+					latest = it
+				}
+			}
+			evt.PipelineRun = latest.Object
+		}
+	}
+}
+
+// enrichOptimizedDeploymentEvent adds the deployment context when enabled and not already provided
+func (e *enricher) enrichOptimizedDeploymentEvent(ctx context.Context, evt *OptimizedDeploymentStatusEvent) {
+	if e.reader != nil && e.enrichDeployments.Load() && evt.Context == nil {
+		deployment := &platformv1alpha1.Deployment{}
+		key := client.ObjectKey{Name: evt.DeploymentRef.Name, Namespace: evt.DeploymentRef.Namespace}
+		if err := e.reader.Get(ctx, key, deployment); err == nil {
+			evt.Context = e.deploymentContext(ctx, deployment)
+			if evt.Source == nil {
+				evt.Source = NewDeploymentSource(deployment)
+			}
+		}
+	}
+}
+
+// deploymentContext looks up the project, environment and application of deployment
+func (e *enricher) deploymentContext(ctx context.Context, deployment *platformv1alpha1.Deployment) *DeploymentContext {
+	deploymentContext := &DeploymentContext{
+		ProjectUUID:     deployment.GetProjectUUID(),
+		EnvironmentUUID: deployment.GetEnvironmentUUID(),
+		ApplicationUUID: deployment.GetApplicationUUID(),
+		ImageDigest:     deployment.Status.ImageDigest,
+	}
+
+	application := &platformv1alpha1.Application{}
+	key := client.ObjectKey{Name: deployment.Spec.ApplicationRef.Name, Namespace: deployment.Namespace}
+	if err := e.reader.Get(ctx, key, application); err == nil {
+		deploymentContext.ApplicationSlug = application.GetSlug()
+		deploymentContext.ApplicationType = string(application.Spec.Type)
+
+		environment := &platformv1alpha1.Environment{}
+		key := client.ObjectKey{Name: application.Spec.EnvironmentRef.Name, Namespace: deployment.Namespace}
+		if err := e.reader.Get(ctx, key, environment); err == nil {
+			deploymentContext.EnvironmentSlug = environment.GetSlug()
+		}
+	}
+
+	if deploymentContext.ProjectUUID != "" {
+		var projects platformv1alpha1.ProjectList
+		if err := e.reader.List(ctx, &projects,
+			client.MatchingLabels{validation.LabelResourceUUID: deploymentContext.ProjectUUID},
+		); err == nil && len(projects.Items) > 0 {
+			deploymentContext.ProjectSlug = projects.Items[0].GetSlug()
+		}
+	}
+
+	return deploymentContext
+}
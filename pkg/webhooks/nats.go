@@ -0,0 +1,153 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// EventTypeHeader is the NATS header carrying the type of a published event
+	EventTypeHeader = "Kibaship-Event-Type"
+
+	natsPublishAttempts  = 5
+	natsPublishRetryWait = 500 * time.Millisecond
+	// natsDuplicateWindow is how long the stream remembers the IDs of published events to drop
+	// the duplicates of retried publishes
+	natsDuplicateWindow = 2 * time.Minute
+)
+
+// NATSNotifier implements Notifier by publishing events to a NATS JetStream stream. Events are
+// published on the subject prefix followed by their type, such as kibaship.deployment.status.changed,
+// so consumers follow a resource kind with kibaship.deployment.>. Publishes wait for the stream
+// to acknowledge the event and are retried under a message ID derived from the payload, the stream
+// drops the duplicates: events are delivered at least once.
+type NATSNotifier struct {
+	enricher
+	conn   *nats.Conn
+	js     jetstream.JetStream
+	stream string
+	prefix string
+}
+
+// NewNATSNotifier connects to the NATS servers of settings and creates the stream events are
+// published to when it does not exist. An existing stream is left as configured.
+func NewNATSNotifier(ctx context.Context, settings NotifierSettings) (*NATSNotifier, error) {
+	conn, err := nats.Connect(settings.NATSURL, nats.Name("kibaship-operator"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	_, err = js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:        settings.NATSStream,
+		Description: "Kibaship resource lifecycle events",
+		Subjects:    []string{settings.SubjectPrefix + ".>"},
+		Storage:     jetstream.FileStorage,
+		Duplicates:  natsDuplicateWindow,
+	})
+	if err != nil && !errors.Is(err, jetstream.ErrStreamNameAlreadyInUse) {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create stream %s: %w", settings.NATSStream, err)
+	}
+
+	n := &NATSNotifier{
+		enricher: enricher{reader: settings.Reader},
+		conn:     conn,
+		js:       js,
+		stream:   settings.NATSStream,
+		prefix:   settings.SubjectPrefix,
+	}
+	n.SetEnrichDeployments(settings.EnrichDeployments)
+	return n, nil
+}
+
+// Close publishes the pending events and closes the connection
+func (n *NATSNotifier) Close() error {
+	return n.conn.Drain()
+}
+
+// natsSubject returns the subject an event of eventType is published on
+func natsSubject(prefix, eventType string) string {
+	return prefix + "." + eventType
+}
+
+func (n *NATSNotifier) publish(ctx context.Context, eventType string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(body)
+	msgID := hex.EncodeToString(sum[:])
+
+	msg := nats.NewMsg(natsSubject(n.prefix, eventType))
+	msg.Header.Set(EventTypeHeader, eventType)
+	msg.Data = body
+
+	var lastErr error
+	for attempt := 0; attempt < natsPublishAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * natsPublishRetryWait):
+			}
+		}
+		if _, lastErr = n.js.PublishMsg(ctx, msg, jetstream.WithMsgID(msgID), jetstream.WithExpectStream(n.stream)); lastErr == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to publish %s: %w", msg.Subject, lastErr)
+}
+
+func (n *NATSNotifier) NotifyProjectStatusChange(ctx context.Context, evt ProjectStatusEvent) error {
+	return n.publish(ctx, evt.Type, evt)
+}
+
+func (n *NATSNotifier) NotifyEnvironmentStatusChange(ctx context.Context, evt EnvironmentStatusEvent) error {
+	return n.publish(ctx, evt.Type, evt)
+}
+
+func (n *NATSNotifier) NotifyApplicationStatusChange(ctx context.Context, evt ApplicationStatusEvent) error {
+	return n.publish(ctx, evt.Type, evt)
+}
+
+func (n *NATSNotifier) NotifyApplicationDomainStatusChange(ctx context.Context, evt ApplicationDomainStatusEvent) error {
+	n.enrichApplicationDomainEvent(ctx, &evt)
+	return n.publish(ctx, evt.Type, evt)
+}
+
+func (n *NATSNotifier) NotifyApplicationDomainUptimeChange(ctx context.Context, evt ApplicationDomainUptimeEvent) error {
+	return n.publish(ctx, evt.Type, evt)
+}
+
+func (n *NATSNotifier) NotifyApplicationHealthChange(ctx context.Context, evt ApplicationHealthEvent) error {
+	return n.publish(ctx, evt.Type, evt)
+}
+
+func (n *NATSNotifier) NotifyApplicationLogAlert(ctx context.Context, evt ApplicationLogAlertEvent) error {
+	return n.publish(ctx, evt.Type, evt)
+}
+
+func (n *NATSNotifier) NotifyDeploymentStatusChange(ctx context.Context, evt DeploymentStatusEvent) error {
+	n.enrichDeploymentEvent(ctx, &evt)
+	return n.publish(ctx, evt.Type, evt)
+}
+
+func (n *NATSNotifier) NotifyOptimizedDeploymentStatusChange(
+	ctx context.Context, evt OptimizedDeploymentStatusEvent,
+) error {
+	n.enrichOptimizedDeploymentEvent(ctx, &evt)
+	return n.publish(ctx, evt.Type, evt)
+}
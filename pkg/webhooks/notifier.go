@@ -7,12 +7,12 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
 	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	"github.com/kibamail/kibaship/pkg/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -22,6 +22,9 @@ type Notifier interface {
 	NotifyEnvironmentStatusChange(ctx context.Context, evt EnvironmentStatusEvent) error
 	NotifyApplicationStatusChange(ctx context.Context, evt ApplicationStatusEvent) error
 	NotifyApplicationDomainStatusChange(ctx context.Context, evt ApplicationDomainStatusEvent) error
+	NotifyApplicationDomainUptimeChange(ctx context.Context, evt ApplicationDomainUptimeEvent) error
+	NotifyApplicationHealthChange(ctx context.Context, evt ApplicationHealthEvent) error
+	NotifyApplicationLogAlert(ctx context.Context, evt ApplicationLogAlertEvent) error
 	NotifyDeploymentStatusChange(ctx context.Context, evt DeploymentStatusEvent) error
 	// NotifyOptimizedDeploymentStatusChange sends memory-optimized deployment status notifications
 	NotifyOptimizedDeploymentStatusChange(ctx context.Context, evt OptimizedDeploymentStatusEvent) error
@@ -65,6 +68,40 @@ type ApplicationDomainStatusEvent struct {
 	Timestamp   time.Time `json:"timestamp"`
 }
 
+// ApplicationDomainUptimeEvent is the payload for application domain uptime notifications,
+// sent when a domain goes down and when it recovers.
+type ApplicationDomainUptimeEvent struct {
+	Type              string                             `json:"type"`
+	PreviousState     string                             `json:"previousState"`
+	NewState          string                             `json:"newState"`
+	ApplicationDomain platformv1alpha1.ApplicationDomain `json:"applicationDomain"`
+	// Downtime is how long the domain was down, set when it recovers
+	Downtime  string    `json:"downtime,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ApplicationHealthEvent is the payload for application health notifications, sent when the pods
+// of the promoted deployment of an application start failing and when they recover.
+type ApplicationHealthEvent struct {
+	Type        string                       `json:"type"`
+	Reason      string                       `json:"reason"`
+	Message     string                       `json:"message"`
+	Application platformv1alpha1.Application `json:"application"`
+	Timestamp   time.Time                    `json:"timestamp"`
+}
+
+// ApplicationLogAlertEvent is the payload for log alert notifications, sent when a log alert rule
+// of an application starts firing and when it resolves.
+type ApplicationLogAlertEvent struct {
+	Type               string                       `json:"type"`
+	Rule               string                       `json:"rule"`
+	Pattern            string                       `json:"pattern"`
+	Matches            int64                        `json:"matches"`
+	ThresholdPerMinute int64                        `json:"thresholdPerMinute"`
+	Application        platformv1alpha1.Application `json:"application"`
+	Timestamp          time.Time                    `json:"timestamp"`
+}
+
 // DeploymentStatusEvent is the payload for deployment status change notifications.
 type DeploymentStatusEvent struct {
 	Type          string                      `json:"type"`
@@ -90,6 +127,11 @@ type OptimizedDeploymentStatusEvent struct {
 		Phase     string `json:"phase"`
 		Slug      string `json:"slug"`
 	} `json:"deploymentRef"`
+	// Source describes the change the deployment ships, when the deployment was created with it
+	Source *DeploymentSource `json:"source,omitempty"`
+	// Context is the project, environment and application of the deployment, set when deployment
+	// webhook enrichment is enabled
+	Context *DeploymentContext `json:"context,omitempty"`
 	// Only essential PipelineRun fields
 	PipelineRunRef *struct {
 		Name   string `json:"name"`
@@ -99,6 +141,43 @@ type OptimizedDeploymentStatusEvent struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// DeploymentSource is the commit metadata of a deployment, read from its annotations
+type DeploymentSource struct {
+	CommitSHA      string `json:"commitSha,omitempty"`
+	CommitMessage  string `json:"commitMessage,omitempty"`
+	CommitAuthor   string `json:"commitAuthor,omitempty"`
+	PullRequestURL string `json:"pullRequestUrl,omitempty"`
+}
+
+// DeploymentContext is the project, environment and application a deployment belongs to, and the
+// image it ships
+type DeploymentContext struct {
+	ProjectUUID     string `json:"projectUuid,omitempty"`
+	ProjectSlug     string `json:"projectSlug,omitempty"`
+	EnvironmentUUID string `json:"environmentUuid,omitempty"`
+	EnvironmentSlug string `json:"environmentSlug,omitempty"`
+	ApplicationUUID string `json:"applicationUuid,omitempty"`
+	ApplicationSlug string `json:"applicationSlug,omitempty"`
+	ApplicationType string `json:"applicationType,omitempty"`
+	ImageDigest     string `json:"imageDigest,omitempty"`
+}
+
+// NewDeploymentSource returns the source metadata of deployment, nil when it has none
+func NewDeploymentSource(deployment *platformv1alpha1.Deployment) *DeploymentSource {
+	source := &DeploymentSource{
+		CommitMessage:  deployment.Annotations[validation.AnnotationCommitMessage],
+		CommitAuthor:   deployment.Annotations[validation.AnnotationCommitAuthor],
+		PullRequestURL: deployment.Annotations[validation.AnnotationPullRequestURL],
+	}
+	if deployment.Spec.GitRepository != nil {
+		source.CommitSHA = deployment.Spec.GitRepository.CommitSHA
+	}
+	if *source == (DeploymentSource{}) {
+		return nil
+	}
+	return source
+}
+
 // NoopNotifier is a drop-in that does nothing.
 type NoopNotifier struct{}
 
@@ -114,6 +193,15 @@ func (n NoopNotifier) NotifyApplicationStatusChange(ctx context.Context, evt App
 func (n NoopNotifier) NotifyApplicationDomainStatusChange(ctx context.Context, evt ApplicationDomainStatusEvent) error {
 	return nil
 }
+func (n NoopNotifier) NotifyApplicationDomainUptimeChange(ctx context.Context, evt ApplicationDomainUptimeEvent) error {
+	return nil
+}
+func (n NoopNotifier) NotifyApplicationHealthChange(ctx context.Context, evt ApplicationHealthEvent) error {
+	return nil
+}
+func (n NoopNotifier) NotifyApplicationLogAlert(ctx context.Context, evt ApplicationLogAlertEvent) error {
+	return nil
+}
 func (n NoopNotifier) NotifyDeploymentStatusChange(ctx context.Context, evt DeploymentStatusEvent) error {
 	return nil
 }
@@ -128,7 +216,8 @@ type HTTPNotifier struct {
 	client     *retryablehttp.Client
 	targetURL  string
 	signingKey []byte
-	reader     client.Reader // cache-backed reader for enrichment
+	enricher
+	mu sync.RWMutex // guards targetURL, which can change on configuration reload
 }
 
 // NewHTTPNotifier constructs an HTTPNotifier with sane defaults.
@@ -152,7 +241,22 @@ func NewHTTPNotifier(targetURL string, signingKey []byte, reader client.Reader)
 		return code >= 500, nil
 	}
 	c.Logger = nil // keep quiet in tests; rely on our logs
-	return &HTTPNotifier{client: c, targetURL: targetURL, signingKey: signingKey, reader: reader}
+	return &HTTPNotifier{client: c, targetURL: targetURL, signingKey: signingKey, enricher: enricher{reader: reader}}
+}
+
+// SetTargetURL changes the URL webhooks are delivered to.
+// It is safe to call while notifications are being sent.
+func (n *HTTPNotifier) SetTargetURL(targetURL string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.targetURL = targetURL
+}
+
+// TargetURL returns the URL webhooks are delivered to
+func (n *HTTPNotifier) TargetURL() string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.targetURL
 }
 
 func (n *HTTPNotifier) postSigned(ctx context.Context, payload any) error {
@@ -164,7 +268,7 @@ func (n *HTTPNotifier) postSigned(ctx context.Context, payload any) error {
 	_, _ = h.Write(body)
 	sig := hex.EncodeToString(h.Sum(nil))
 
-	req, err := retryablehttp.NewRequest(http.MethodPost, n.targetURL, body)
+	req, err := retryablehttp.NewRequest(http.MethodPost, n.TargetURL(), body)
 	if err != nil {
 		return err
 	}
@@ -191,44 +295,30 @@ func (n *HTTPNotifier) NotifyApplicationDomainStatusChange(
 	ctx context.Context,
 	evt ApplicationDomainStatusEvent,
 ) error {
-	// enrich with Certificate when available
-	ref := evt.ApplicationDomain.Status.CertificateRef
-	if n.reader != nil && ref != nil && ref.Name != "" && ref.Namespace != "" {
-		u := &unstructured.Unstructured{}
-		u.SetGroupVersionKind(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"})
-		_ = n.reader.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: ref.Namespace}, u)
-		if len(u.Object) > 0 {
-			evt.Certificate = u.Object
-		}
-	}
+	n.enrichApplicationDomainEvent(ctx, &evt)
+	return n.postSigned(ctx, evt)
+}
+
+func (n *HTTPNotifier) NotifyApplicationDomainUptimeChange(ctx context.Context, evt ApplicationDomainUptimeEvent) error {
+	return n.postSigned(ctx, evt)
+}
+
+func (n *HTTPNotifier) NotifyApplicationHealthChange(ctx context.Context, evt ApplicationHealthEvent) error {
+	return n.postSigned(ctx, evt)
+}
+
+func (n *HTTPNotifier) NotifyApplicationLogAlert(ctx context.Context, evt ApplicationLogAlertEvent) error {
 	return n.postSigned(ctx, evt)
 }
 
 func (n *HTTPNotifier) NotifyDeploymentStatusChange(ctx context.Context, evt DeploymentStatusEvent) error {
-	// enrich with latest PipelineRun when available and not already provided
-	if n.reader != nil && evt.PipelineRun == nil {
-		list := &unstructured.UnstructuredList{}
-		list.SetGroupVersionKind(schema.GroupVersionKind{Group: "tekton.dev", Version: "v1", Kind: "PipelineRunList"})
-		_ = n.reader.List(ctx, list,
-			client.InNamespace(evt.Deployment.Namespace),
-			client.MatchingLabels(map[string]string{"deployment.kibaship.com/name": evt.Deployment.Name}),
-		)
-		if len(list.Items) > 0 {
-			// pick newest by creationTimestamp
-			latest := list.Items[0]
-			for _, it := range list.Items[1:] {
-				if it.GetCreationTimestamp().After(latest.GetCreationTimestamp().Time) {
-					latest = it
-				}
-			}
-			evt.PipelineRun = latest.Object
-		}
-	}
+	n.enrichDeploymentEvent(ctx, &evt)
 	return n.postSigned(ctx, evt)
 }
 
 func (n *HTTPNotifier) NotifyOptimizedDeploymentStatusChange(
 	ctx context.Context, evt OptimizedDeploymentStatusEvent,
 ) error {
+	n.enrichOptimizedDeploymentEvent(ctx, &evt)
 	return n.postSigned(ctx, evt)
 }
@@ -0,0 +1,122 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/kibamail/kibaship/api/v1alpha1"
+	"github.com/kibamail/kibaship/pkg/validation"
+)
+
+func TestNotifyOptimizedDeploymentStatusChangeEnrichesContext(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(platformv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	project := &platformv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{
+		Name:   "project-shop",
+		Labels: map[string]string{validation.LabelResourceUUID: "project-uuid", validation.LabelResourceSlug: "shop"},
+	}}
+	environment := &platformv1alpha1.Environment{ObjectMeta: metav1.ObjectMeta{
+		Name: "environment-production", Namespace: "project-shop",
+		Labels: map[string]string{validation.LabelResourceSlug: "production"},
+	}}
+	application := &platformv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "application-api", Namespace: "project-shop",
+			Labels: map[string]string{validation.LabelResourceSlug: "api"},
+		},
+		Spec: platformv1alpha1.ApplicationSpec{
+			Type:           platformv1alpha1.ApplicationTypeGitRepository,
+			EnvironmentRef: corev1.LocalObjectReference{Name: environment.Name},
+		},
+	}
+	deployment := &platformv1alpha1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "deployment-api-1", Namespace: "project-shop",
+			Labels: map[string]string{
+				validation.LabelProjectUUID:     "project-uuid",
+				validation.LabelEnvironmentUUID: "environment-uuid",
+				validation.LabelApplicationUUID: "application-uuid",
+			},
+			Annotations: map[string]string{validation.AnnotationCommitAuthor: "Ada"},
+		},
+		Spec:   platformv1alpha1.DeploymentSpec{ApplicationRef: corev1.LocalObjectReference{Name: application.Name}},
+		Status: platformv1alpha1.DeploymentStatus{ImageDigest: "sha256:abc"},
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(project, environment, application, deployment).Build()
+
+	var received []OptimizedDeploymentStatusEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var evt OptimizedDeploymentStatusEvent
+		g.Expect(json.NewDecoder(r.Body).Decode(&evt)).To(Succeed())
+		received = append(received, evt)
+	}))
+	defer server.Close()
+
+	n := NewHTTPNotifier(server.URL, []byte("secret"), reader)
+	evt := OptimizedDeploymentStatusEvent{Type: "deployment.status.changed"}
+	evt.DeploymentRef.Name = deployment.Name
+	evt.DeploymentRef.Namespace = deployment.Namespace
+
+	// Enrichment is off by default
+	g.Expect(n.NotifyOptimizedDeploymentStatusChange(ctx, evt)).To(Succeed())
+	g.Expect(received).To(HaveLen(1))
+	g.Expect(received[0].Context).To(BeNil())
+
+	n.SetEnrichDeployments(true)
+	g.Expect(n.NotifyOptimizedDeploymentStatusChange(ctx, evt)).To(Succeed())
+	g.Expect(received).To(HaveLen(2))
+	g.Expect(received[1].Context).To(Equal(&DeploymentContext{
+		ProjectUUID:     "project-uuid",
+		ProjectSlug:     "shop",
+		EnvironmentUUID: "environment-uuid",
+		EnvironmentSlug: "production",
+		ApplicationUUID: "application-uuid",
+		ApplicationSlug: "api",
+		ApplicationType: string(platformv1alpha1.ApplicationTypeGitRepository),
+		ImageDigest:     "sha256:abc",
+	}))
+	g.Expect(received[1].Source).To(Equal(&DeploymentSource{CommitAuthor: "Ada"}))
+}
+
+func TestNewNotifierRegistry(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	n, err := NewNotifier(ctx, BackendWebhook, NotifierSettings{WebhookURL: "https://hooks.example.com"})
+	g.Expect(err).NotTo(HaveOccurred())
+	httpNotifier, ok := n.(*HTTPNotifier)
+	g.Expect(ok).To(BeTrue())
+
+	Reconfigure(n, NotifierSettings{WebhookURL: "https://hooks.example.org", EnrichDeployments: true})
+	g.Expect(httpNotifier.TargetURL()).To(Equal("https://hooks.example.org"))
+	g.Expect(httpNotifier.enrichDeployments.Load()).To(BeTrue())
+
+	RegisterNotifier("test-bus", func(context.Context, NotifierSettings) (Notifier, error) {
+		return NoopNotifier{}, nil
+	})
+	n, err = NewNotifier(ctx, "test-bus", NotifierSettings{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(n).To(Equal(NoopNotifier{}))
+
+	_, err = NewNotifier(ctx, "kafka", NotifierSettings{})
+	g.Expect(err).To(MatchError(ContainSubstring(`unknown notifier backend "kafka"`)))
+}
+
+func TestNATSSubject(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(natsSubject("kibaship", "deployment.status.changed")).To(Equal("kibaship.deployment.status.changed"))
+	g.Expect(natsSubject("acme.platform", "applicationdomain.uptime.down")).To(Equal("acme.platform.applicationdomain.uptime.down"))
+}
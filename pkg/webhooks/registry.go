@@ -0,0 +1,92 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Backend names of the built-in notifiers
+const (
+	BackendWebhook = "webhook"
+	BackendNATS    = "nats"
+)
+
+// NotifierSettings are the settings notifier backends are built from
+type NotifierSettings struct {
+	// WebhookURL and SigningKey configure the webhook backend
+	WebhookURL string
+	SigningKey []byte
+
+	// NATSURL, NATSStream and SubjectPrefix configure the nats backend
+	NATSURL       string
+	NATSStream    string
+	SubjectPrefix string
+
+	// EnrichDeployments adds the deployment context to deployment events
+	EnrichDeployments bool
+
+	// Reader is the cache-backed reader events are enriched through
+	Reader client.Reader
+}
+
+// NotifierFactory builds the Notifier of a backend
+type NotifierFactory func(ctx context.Context, settings NotifierSettings) (Notifier, error)
+
+var (
+	notifierFactoriesMu sync.RWMutex
+	notifierFactories   = map[string]NotifierFactory{
+		BackendWebhook: func(_ context.Context, settings NotifierSettings) (Notifier, error) {
+			n := NewHTTPNotifier(settings.WebhookURL, settings.SigningKey, settings.Reader)
+			n.SetEnrichDeployments(settings.EnrichDeployments)
+			return n, nil
+		},
+		BackendNATS: func(ctx context.Context, settings NotifierSettings) (Notifier, error) {
+			return NewNATSNotifier(ctx, settings)
+		},
+	}
+)
+
+// RegisterNotifier makes a notifier backend available to NewNotifier under name, replacing the
+// backend registered under the same name
+func RegisterNotifier(name string, factory NotifierFactory) {
+	notifierFactoriesMu.Lock()
+	defer notifierFactoriesMu.Unlock()
+	notifierFactories[name] = factory
+}
+
+// NewNotifier builds the Notifier of the backend registered under name
+func NewNotifier(ctx context.Context, name string, settings NotifierSettings) (Notifier, error) {
+	notifierFactoriesMu.RLock()
+	factory, ok := notifierFactories[name]
+	notifierFactoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown notifier backend %q (registered: %v)", name, registeredNotifiers())
+	}
+	return factory(ctx, settings)
+}
+
+// Reconfigure applies the settings that can change on configuration reload to a notifier built
+// by NewNotifier. Other settings require a new notifier.
+func Reconfigure(n Notifier, settings NotifierSettings) {
+	if httpNotifier, ok := n.(*HTTPNotifier); ok && settings.WebhookURL != "" {
+		httpNotifier.SetTargetURL(settings.WebhookURL)
+	}
+	if enriching, ok := n.(interface{ SetEnrichDeployments(bool) }); ok {
+		enriching.SetEnrichDeployments(settings.EnrichDeployments)
+	}
+}
+
+func registeredNotifiers() []string {
+	notifierFactoriesMu.RLock()
+	defer notifierFactoriesMu.RUnlock()
+	names := make([]string, 0, len(notifierFactories))
+	for name := range notifierFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -18,6 +18,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
@@ -27,6 +28,9 @@ import (
 	"github.com/gin-gonic/gin"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/kibamail/kibaship/pkg/auth"
 	"github.com/kibamail/kibaship/pkg/models"
@@ -191,4 +195,45 @@ var _ = Describe("API Authentication", func() {
 		})
 	})
 
+	Describe("API Key Rotation", func() {
+		It("accepts the rotated key from the shared secret", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: auth.SecretName, Namespace: "kibaship"},
+				Data:       map[string][]byte{auth.SecretKey: []byte(apiKey)},
+			}
+			clientset := fake.NewSimpleClientset(secret)
+			secretManager := auth.NewSecretManagerWithClient(clientset, "kibaship")
+
+			watcher, err := secretManager.WatchAPIKey(ctx, apiKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(watcher.APIKey()).To(Equal(apiKey))
+
+			authenticator := auth.NewAPIKeyAuthenticatorWithSource(watcher)
+			rotatedRouter := gin.New()
+			rotatedRouter.GET("/projects", authenticator.Middleware(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			status := func(key string) int {
+				req := httptest.NewRequest("GET", "/projects", nil)
+				req.Header.Set("Authorization", "Bearer "+key)
+				w := httptest.NewRecorder()
+				rotatedRouter.ServeHTTP(w, req)
+				return w.Code
+			}
+			Expect(status(apiKey)).To(Equal(http.StatusOK))
+
+			rotatedKey := generateAPIKey()
+			secret.Data[auth.SecretKey] = []byte(rotatedKey)
+			_, err = clientset.CoreV1().Secrets("kibaship").Update(ctx, secret, metav1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int { return status(rotatedKey) }).Should(Equal(http.StatusOK))
+			Expect(status(apiKey)).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
 })